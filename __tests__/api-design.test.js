@@ -0,0 +1,105 @@
+/**
+ * Tests for API design checks
+ */
+
+const {
+  extractRoutes,
+  checkRoutes,
+  checkResolvers,
+  checkApiDesign,
+  findBreakingChanges
+} = require('../lib/patterns/api-design');
+
+describe('api-design', () => {
+  describe('extractRoutes', () => {
+    it('should find Express, FastAPI, Flask and Spring routes', () => {
+      const routes = extractRoutes([
+        "router.get('/users', listUsers);",
+        '@app.post("/orders/{order_id}")',
+        "@bp.route('/items/<int:id>', methods=['PUT'])",
+        '@GetMapping(value = "/accounts/{id}")'
+      ].join('\n'));
+      expect(routes.map(({ method, path, line }) => ({ method, path, line }))).toEqual([
+        { method: 'GET', path: '/users', line: 1 },
+        { method: 'POST', path: '/orders/{order_id}', line: 2 },
+        { method: 'PUT', path: '/items/<int:id>', line: 3 },
+        { method: 'GET', path: '/accounts/{id}', line: 4 }
+      ]);
+    });
+  });
+
+  describe('checkRoutes', () => {
+    it('should flag verbs, singular collections and unpaginated lists', () => {
+      const findings = checkRoutes(extractRoutes([
+        "app.post('/createUser', create);",
+        "app.get('/user/:id', show);",
+        "app.get('/users', async (req, res) => {",
+        '  res.json(await db.users.findMany());',
+        '});',
+        "app.get('/orders', async (req, res) => {",
+        '  const { limit, cursor } = req.query;',
+        '});',
+        "app.get('/api/v1/me', me);"
+      ].join('\n')));
+      expect(findings.map(({ line, rule }) => ({ line, rule }))).toEqual([
+        { line: 1, rule: 'verb_in_path' },
+        { line: 2, rule: 'singular_resource' },
+        { line: 3, rule: 'missing_pagination' }
+      ]);
+      expect(findings[1].suggestion).toBe('Use plural collection names (/users/{id})');
+    });
+  });
+
+  describe('checkResolvers', () => {
+    it('should flag per-parent queries in resolvers unless batched', () => {
+      const content = [
+        'export const resolvers = {',
+        '  Post: {',
+        '    author: (parent) => prisma.user.findUnique({ where: { id: parent.authorId } }),',
+        '    tags: (parent, _, { loaders }) => loaders.tags.load(parent.id)',
+        '  }',
+        '};'
+      ].join('\n');
+      expect(checkResolvers(content, 'src/graphql/schema.ts').map(finding => finding.line)).toEqual([3]);
+      expect(checkResolvers('const a = parent.user.find(x);', 'src/tree.js')).toEqual([]);
+    });
+  });
+
+  describe('checkApiDesign', () => {
+    it('should check source files and skip others', () => {
+      const contents = { 'src/routes.js': "router.get('/getUsers', h);\n", 'README.md': "app.get('/getUsers')\n" };
+      const findings = checkApiDesign('/repo', ['src/routes.js', 'README.md', 'missing.js'], { readFile: file => {
+        if (!(file in contents)) throw new Error('ENOENT');
+        return contents[file];
+      } });
+      expect(findings.map(({ file, rule }) => ({ file, rule }))).toEqual([
+        { file: 'src/routes.js', rule: 'verb_in_path' }
+      ]);
+    });
+  });
+
+  describe('findBreakingChanges', () => {
+    it('should report removed, renamed, unexported and re-signed exports', () => {
+      const diff = {
+        symbols: {
+          added: [{ file: 'src/api.ts', name: 'createOrder', kind: 'function', line: 3, exported: true }],
+          removed: [
+            { file: 'src/api.ts', name: 'legacy', kind: 'function', line: 9, exported: true },
+            { file: 'src/api.ts', name: 'helper', kind: 'function', line: 12, exported: false }
+          ],
+          renamed: [{ file: 'src/api.ts', name: 'getUser', kind: 'function', line: 5, exported: true, from: { name: 'fetchUser' } }],
+          changed: [
+            { file: 'src/api.ts', name: 'listUsers', kind: 'function', line: 7, exported: true, signature: '(limit: number)', before: { signature: '()', exported: true } },
+            { file: 'src/api.ts', name: 'Config', kind: 'interface', line: 1, exported: false, before: { exported: true } },
+            { file: 'src/api.ts', name: 'internal', kind: 'function', line: 2, exported: true, signature: '(a)', before: { signature: '()', exported: false } }
+          ]
+        }
+      };
+      expect(findBreakingChanges(diff).map(({ name, change }) => `${change}:${name}`)).toEqual([
+        'removed:legacy', 'renamed:getUser', 'signature:listUsers', 'unexported:Config'
+      ]);
+      expect(findBreakingChanges(diff, { includeFile: file => file.startsWith('lib/') })).toEqual([]);
+      expect(findBreakingChanges(null)).toEqual([]);
+    });
+  });
+});
@@ -0,0 +1,102 @@
+/**
+ * Tests for the slop baseline
+ */
+
+const fs = require('fs');
+const os = require('os');
+const path = require('path');
+
+const {
+  BASELINE_FILE,
+  createBaseline,
+  writeBaseline,
+  addToBaseline,
+  loadBaseline,
+  filterBaseline
+} = require('../lib/patterns/baseline');
+const { runPipeline } = require('../lib/patterns/pipeline');
+
+describe('baseline', () => {
+  let root;
+
+  function write(files) {
+    for (const [file, content] of Object.entries(files)) {
+      const fullPath = path.join(root, file);
+      fs.mkdirSync(path.dirname(fullPath), { recursive: true });
+      fs.writeFileSync(fullPath, content);
+    }
+  }
+
+  function finding(file, line, patternName = 'console_debugging') {
+    return { file, line, patternName, content: '' };
+  }
+
+  beforeEach(() => {
+    root = fs.mkdtempSync(path.join(os.tmpdir(), 'baseline-'));
+  });
+
+  afterEach(() => {
+    fs.rmSync(root, { recursive: true, force: true });
+  });
+
+  it('should group findings by file, pattern, and line fingerprint', () => {
+    write({ 'src/a.js': 'console.log(x);\n  console.log(x);\nconsole.log(y);\n' });
+    const baseline = createBaseline(root, [finding('src/a.js', 1), finding('src/a.js', 2), finding('src/a.js', 3)]);
+
+    expect(baseline.version).toBe(1);
+    expect(baseline.entries.map(e => e.count).sort()).toEqual([1, 2]);
+    expect(baseline.entries.every(e => e.file === 'src/a.js' && /^[0-9a-f]{16}$/.test(e.fingerprint))).toBe(true);
+  });
+
+  it('should keep findings suppressed when lines move and report edited or extra lines', () => {
+    write({ 'src/a.js': 'console.log(x);\nconsole.debug(y);\n' });
+    const baseline = createBaseline(root, [finding('src/a.js', 1), finding('src/a.js', 2)]);
+
+    write({ 'src/a.js': '// header\n\nconsole.log(x);\nconsole.debug(y, z);\nconsole.log(x);\n' });
+    const result = filterBaseline(root, [finding('src/a.js', 3), finding('src/a.js', 4), finding('src/a.js', 5)], baseline);
+
+    expect(result.suppressed).toBe(1);
+    expect(result.findings.map(f => f.line)).toEqual([4, 5]);
+  });
+
+  it('should round-trip through the baseline file and reject unknown versions', () => {
+    write({ 'a.js': 'console.log(1);\n' });
+    const written = writeBaseline(root, [finding('a.js', 1)]);
+    expect(written).toEqual({ file: path.join(root, BASELINE_FILE), entries: 1, findings: 1 });
+    expect(loadBaseline(root).baseline.entries).toHaveLength(1);
+
+    expect(loadBaseline(root, 'missing.json')).toEqual({ baseline: null, error: null });
+    write({ 'old.json': JSON.stringify({ version: 99, entries: [] }) });
+    expect(loadBaseline(root, 'old.json').error).toContain('unsupported version 99');
+  });
+
+  it('should add findings to an existing baseline and refuse to overwrite a broken one', () => {
+    write({ 'src/a.js': 'console.log(x);\nconsole.log(y);\n' });
+    writeBaseline(root, [finding('src/a.js', 1)]);
+
+    const added = addToBaseline(root, [finding('src/a.js', 2), finding('src/a.js', 1)]);
+    expect(added).toMatchObject({ entries: 2, added: 1, findings: 2, error: null });
+    const { baseline } = loadBaseline(root);
+    expect(baseline.entries.map(e => e.count).sort()).toEqual([1, 2]);
+    expect(filterBaseline(root, [finding('src/a.js', 2)], baseline).findings).toEqual([]);
+
+    write({ [BASELINE_FILE]: '{' });
+    expect(addToBaseline(root, [finding('src/a.js', 1)]).error).toMatch(/invalid JSON/);
+    expect(fs.readFileSync(path.join(root, BASELINE_FILE), 'utf8')).toBe('{');
+  });
+
+  it('should report only new findings from the pipeline once a baseline exists', () => {
+    write({ 'src/app.js': 'function run() {\n  console.log("start");\n}\n' });
+    const options = { thoroughness: 'quick', targetFiles: ['src/app.js'], linters: [], ast: false };
+
+    const initial = runPipeline(root, { ...options, baseline: false });
+    writeBaseline(root, initial.findings);
+
+    write({ 'src/app.js': 'function run() {\n  console.log("start");\n  console.log("debug");\n}\n' });
+    const result = runPipeline(root, options);
+    expect(result.findings.map(f => f.line)).toEqual([3]);
+    expect(result.baseline).toEqual({ file: BASELINE_FILE, suppressed: initial.findings.length, error: null });
+
+    expect(runPipeline(root, { ...options, baseline: false }).findings.length).toBe(initial.findings.length + 1);
+  });
+});
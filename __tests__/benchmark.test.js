@@ -0,0 +1,147 @@
+/**
+ * Tests for benchmark runs and regression comparison
+ */
+
+const fs = require('fs');
+const os = require('os');
+const path = require('path');
+
+const {
+  parseGoBench,
+  readCriterionResults,
+  parsePytestBenchmark,
+  parseVitestBench,
+  detectHarnesses,
+  welchTTest,
+  compareResults,
+  runBenchmarks,
+  renderReport
+} = require('../lib/benchmark');
+
+const goOutput = (samples) => [
+  'goos: linux',
+  'goarch: amd64',
+  'pkg: example.com/shop/cart',
+  ...samples.map(ns => `BenchmarkTotal-8   \t 1000000\t      ${ns} ns/op\t     240 B/op\t       3 allocs/op`),
+  'BenchmarkEmpty-8   \t 50000000\t        20.5 ns/op',
+  'PASS',
+  'ok  \texample.com/shop/cart\t2.1s'
+].join('\n');
+
+describe('benchmark', () => {
+  let dir;
+
+  beforeEach(() => {
+    dir = fs.mkdtempSync(path.join(os.tmpdir(), 'benchmark-'));
+  });
+
+  afterEach(() => {
+    fs.rmSync(dir, { recursive: true, force: true });
+  });
+
+  const write = (file, content) => {
+    fs.mkdirSync(path.dirname(path.join(dir, file)), { recursive: true });
+    fs.writeFileSync(path.join(dir, file), typeof content === 'string' ? content : JSON.stringify(content));
+  };
+
+  describe('parsers', () => {
+    it('should summarize go test -bench runs per benchmark', () => {
+      const results = parseGoBench(goOutput([1000, 1100, 1200]));
+      expect(results.map(result => result.id)).toEqual(['example.com/shop/cart.BenchmarkTotal', 'example.com/shop/cart.BenchmarkEmpty']);
+      expect(results[0]).toMatchObject({ harness: 'go', mean: 1100, sd: 100, n: 3, median: 1100, bytesPerOp: 240, allocsPerOp: 3 });
+      expect(results[1]).toMatchObject({ mean: 20.5, sd: 0, n: 1 });
+    });
+
+    it('should read criterion samples and convert pytest and vitest reports to nanoseconds', () => {
+      write('target/criterion/fib/20/new/sample.json', { iters: [10, 20], times: [1000, 2400] });
+      write('target/criterion/fib/20/new/benchmark.json', { full_id: 'fib/20' });
+      write('target/criterion/fib/20/base/sample.json', { iters: [1], times: [1] });
+      expect(readCriterionResults(path.join(dir, 'target/criterion'))).toEqual([
+        { id: 'fib/20', harness: 'criterion', mean: 110, sd: expect.any(Number), n: 2, median: 110, min: 100 }
+      ]);
+
+      const pytest = JSON.stringify({ benchmarks: [{ name: 'test_sort', fullname: 'tests/test_sort.py::test_sort', stats: { mean: 0.002, stddev: 0.0001, rounds: 40, median: 0.002, min: 0.0019 } }] });
+      expect(parsePytestBenchmark(pytest)[0]).toMatchObject({ id: 'tests/test_sort.py::test_sort', harness: 'pytest-benchmark', mean: 2e6, n: 40 });
+
+      const vitest = JSON.stringify({ files: [{ filepath: '/repo/src/sort.bench.ts', groups: [{ fullName: 'src/sort.bench.ts > sort', benchmarks: [{ name: 'quick', mean: 0.5, sd: 0.05, sampleCount: 1000, samples: [] }] }] }] });
+      expect(parseVitestBench(vitest)[0]).toMatchObject({ id: 'src/sort.bench.ts > sort > quick', harness: 'vitest', mean: 500000, sd: 50000, n: 1000 });
+      expect(parseVitestBench('not json')).toEqual([]);
+    });
+  });
+
+  describe('detectHarnesses', () => {
+    it('should find harnesses from dependencies and benchmark files', async () => {
+      write('go.mod', 'module example.com/shop\n\ngo 1.22\n');
+      write('cart/cart_test.go', 'package cart\n\nfunc BenchmarkTotal(b *testing.B) {}\n');
+      write('cart/other_test.go', 'package cart\n\nfunc TestTotal(t *testing.T) {}\n');
+      write('package.json', { devDependencies: { vitest: '^1.0.0' } });
+      write('src/sort.bench.ts', "bench('quick', () => {})");
+      write('node_modules/vitest/bad.bench.ts', '');
+
+      expect(await detectHarnesses(dir)).toEqual([
+        { harness: 'go', files: ['cart/cart_test.go'], label: 'go test -bench' },
+        { harness: 'vitest', files: ['src/sort.bench.ts'], label: 'vitest bench' }
+      ]);
+    });
+  });
+
+  describe('compareResults', () => {
+    it('should apply Welch\'s t-test and the change threshold', () => {
+      expect(welchTTest({ mean: 10, sd: 1, n: 10 }, { mean: 11, sd: 1, n: 10 }).pValue).toBeCloseTo(0.0382, 3);
+      expect(welchTTest({ mean: 10, sd: 1, n: 1 }, { mean: 11, sd: 1, n: 10 })).toBeNull();
+
+      const bench = (id, mean, sd, n) => ({ id, harness: 'go', mean, sd, n });
+      const rows = compareResults(
+        [bench('slow', 100, 5, 6), bench('noisy', 100, 40, 6), bench('faster', 100, 2, 6), bench('single', 100, 0, 1), bench('gone', 1, 0, 6)],
+        [bench('slow', 130, 8, 6), bench('noisy', 120, 40, 6), bench('faster', 80, 2, 6), bench('single', 150, 0, 1), bench('new', 1, 0, 6)]
+      );
+      expect(rows.map(row => [row.id, row.status])).toEqual([
+        ['slow', 'regression'],
+        ['single', 'inconclusive'],
+        ['faster', 'improvement'],
+        ['noisy', 'unchanged'],
+        ['new', 'added'],
+        ['gone', 'removed']
+      ]);
+      expect(rows[0].change).toBeCloseTo(30);
+    });
+  });
+
+  describe('runBenchmarks', () => {
+    it('should store results and compare against the baseline run in a worktree', async () => {
+      write('go.mod', 'module example.com/shop\n');
+      write('cart/cart_test.go', 'func BenchmarkTotal(b *testing.B) {}\n');
+      const calls = [];
+      const run = (cwd, argv) => {
+        calls.push(argv.slice(0, 3).join(' '));
+        if (argv[1] === 'rev-parse') return 'c0ffee1234567890\n';
+        if (argv[1] === 'status') return '';
+        if (argv[1] === 'merge-base') return 'ba5e000000000000\n';
+        if (argv[0] === 'git') return '';
+        return cwd === dir ? goOutput([1500, 1550, 1600, 1520, 1580, 1560]) : goOutput([1000, 1050, 990, 1010, 1020, 1000]);
+      };
+
+      const report = await runBenchmarks(dir, { base: 'origin/main', run });
+      expect(calls).toEqual(['git rev-parse HEAD', 'git status --porcelain', 'go test -run', 'git merge-base origin/main', 'git worktree add', 'go test -run', 'git worktree remove']);
+      expect(report).toMatchObject({ success: true, file: '.awesome-slash/bench/c0ffee1234567890.json', baseline: { ref: 'origin/main', commit: 'ba5e000000000000', source: 'ran' } });
+      expect(report.regressions.map(row => row.id)).toEqual(['example.com/shop/cart.BenchmarkTotal']);
+      expect(JSON.parse(fs.readFileSync(path.join(dir, '.awesome-slash/bench/ba5e000000000000.json'), 'utf8')).benchmarks[0].mean).toBeCloseTo(1011.67, 1);
+
+      const content = renderReport(report);
+      expect(content).toContain('**Baseline**: origin/main (ba5e000, ran in a worktree)');
+      expect(content).toMatch(/\| `example.com\/shop\/cart.BenchmarkTotal` \| 1.01 µs ± 21.4 ns \| 1.55 µs ± 37.1 ns \| \+53.4% \| <0.001 \| regression \|/);
+
+      // A second run reuses the stored baseline
+      calls.length = 0;
+      const again = await runBenchmarks(dir, { base: 'origin/main', run });
+      expect(again.baseline.source).toBe('stored');
+      expect(calls).not.toContain('git worktree add');
+    });
+
+    it('should fail without benchmarks or with invalid settings', async () => {
+      expect((await runBenchmarks(dir, { run: () => '' })).error).toMatch(/^No benchmarks found/);
+      write('.awesome-slash.json', { benchmark: { alpha: 2 } });
+      expect((await runBenchmarks(dir, { run: () => '' })).error).toBe('.awesome-slash.json: benchmark.alpha must be between 0 and 1');
+    });
+  });
+});
@@ -0,0 +1,122 @@
+/**
+ * Tests for lib/cache (shared disk cache)
+ */
+
+const fs = require('fs');
+const os = require('os');
+const path = require('path');
+
+const cache = require('../lib/cache');
+const { queryVulnerabilities } = require('../lib/deps');
+const { loadRunResults } = require('../lib/flaky');
+
+describe('cache', () => {
+  let root;
+
+  const writeConfig = config => fs.writeFileSync(path.join(root, '.awesome-slash.json'), JSON.stringify(config));
+  const age = (file, ms) => {
+    const time = new Date(Date.now() - ms);
+    fs.utimesSync(file, time, time);
+  };
+
+  beforeEach(() => {
+    root = fs.mkdtempSync(path.join(os.tmpdir(), 'cache-'));
+  });
+
+  afterEach(() => {
+    fs.rmSync(root, { recursive: true, force: true });
+  });
+
+  it('should store values per namespace and expire them after the TTL', async () => {
+    const osv = cache.createCache(root, 'osv');
+    expect(osv.get('query:npm:lodash@4.17.20')).toBeUndefined();
+    expect(osv.set('query:npm:lodash@4.17.20', ['GHSA-1'])).toBe(true);
+    expect(osv.set('empty', null)).toBe(true);
+    expect(osv.get('query:npm:lodash@4.17.20')).toEqual(['GHSA-1']);
+    expect(osv.get('empty')).toBeNull();
+    expect(path.relative(root, osv.file('empty'))).toBe(path.join('.awesome-slash', 'cache', 'osv', 'empty.json'));
+    expect(path.basename(osv.file('query:npm:lodash@4.17.20'))).toMatch(/^[0-9a-f]{40}\.json$/);
+
+    age(osv.file('empty'), 7 * 60 * 60 * 1000);
+    expect(osv.get('empty')).toBeUndefined();
+    expect(fs.existsSync(osv.file('empty'))).toBe(false);
+
+    let calls = 0;
+    const compute = async () => ++calls;
+    expect(await osv.wrap('counter', compute)).toBe(1);
+    expect(await osv.wrap('counter', compute)).toBe(1);
+    expect(() => cache.createCache(root, '../up')).toThrow('Invalid cache namespace');
+  });
+
+  it('should evict the oldest entries past the size ceiling', () => {
+    const settings = { ...cache.readSettings(root), maxSizeMb: 2500 / (1024 * 1024) };
+    const ci = cache.createCache(root, 'ci', { settings });
+    ci.set('first', 'x'.repeat(1000));
+    age(ci.file('first'), 3000);
+    ci.set('second', 'x'.repeat(1000));
+    age(ci.file('second'), 2000);
+    ci.set('third', 'x'.repeat(1000));
+
+    expect(ci.get('first')).toBeUndefined();
+    expect(ci.get('second')).toHaveLength(1000);
+    expect(ci.get('third')).toHaveLength(1000);
+    expect(cache.stats(root)).toMatchObject({ entries: 2, namespaces: [{ namespace: 'ci', entries: 2, expired: 0 }] });
+  });
+
+  it('should honor config settings and clear by namespace or age', () => {
+    writeConfig({ cache: { ttl: { detection: 60 } } });
+    const detection = cache.createCache(root, 'detection');
+    const repoMap = cache.createCache(root, 'repo-map');
+    expect(detection.ttlMs).toBe(60000);
+    detection.set('platform', { projectType: 'go' });
+    repoMap.set('files', { version: 1, files: {} });
+    age(detection.file('platform'), 120000);
+
+    expect(cache.stats(root).namespaces.map(({ namespace, expired }) => [namespace, expired])).toEqual([['detection', 1], ['repo-map', 0]]);
+    expect(cache.clear(root, { expired: true }).removed).toBe(1);
+    expect(cache.clear(root, { namespace: 'repo-map' }).removed).toBe(1);
+    expect(cache.clear(root)).toEqual({ removed: 0, bytes: 0 });
+    expect(fs.existsSync(cache.cacheDir(root))).toBe(false);
+
+    writeConfig({ cache: { enabled: false } });
+    const off = cache.createCache(root, 'osv');
+    expect(off.set('key', 1)).toBe(false);
+    expect(off.get('key')).toBeUndefined();
+
+    writeConfig({ cache: { ttl: { osv: '1h' } } });
+    expect(cache.readSettings(root).error).toBe('.awesome-slash.json: cache.ttl.osv must be a whole number of seconds');
+    writeConfig({ cache: { maxSizeMb: 0 } });
+    expect(cache.readSettings(root).error).toBe('.awesome-slash.json: cache.maxSizeMb must be a positive number');
+  });
+
+  it('should spare OSV and CI requests on a second run', async () => {
+    const osv = cache.createCache(root, 'osv');
+    const requests = [];
+    const request = async (method, pathname, body) => {
+      requests.push(`${method} ${pathname}${body ? ` ${body.queries.length}` : ''}`);
+      if (pathname === '/v1/querybatch') return { results: body.queries.map(query => (query.package.name === 'lodash' ? { vulns: [{ id: 'GHSA-1' }] } : {})) };
+      return { id: 'GHSA-1', summary: 'Prototype pollution', affected: [] };
+    };
+    const deps = [
+      { ecosystem: 'npm', name: 'lodash', version: '4.17.20' },
+      { ecosystem: 'npm', name: 'chalk', version: '5.3.0' }
+    ];
+    const first = await queryVulnerabilities(deps, { request, cache: osv });
+    const second = await queryVulnerabilities([...deps, { ecosystem: 'npm', name: 'ms', version: '2.1.3' }], { request, cache: osv });
+    expect(second).toEqual(first);
+    expect(requests).toEqual(['POST /v1/querybatch 2', 'GET /v1/vulns/GHSA-1', 'POST /v1/querybatch 1']);
+
+    const ci = cache.createCache(root, 'ci');
+    let downloads = 0;
+    const run = () => {
+      downloads++;
+      return JSON.stringify({ test_suites: [{ name: 'unit', test_cases: [{ name: 'adds', classname: 'math', status: 'success' }] }] });
+    };
+    const finished = { id: '41', attempt: 1, status: 'success' };
+    const running = { id: '42', attempt: 1, status: 'running' };
+    for (const runInfo of [finished, finished, running, running]) {
+      expect(loadRunResults(root, 'gitlab-ci', runInfo, { run, cache: ci })).toHaveLength(1);
+    }
+    expect(downloads).toBe(3);
+  });
+});
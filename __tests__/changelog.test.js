@@ -0,0 +1,179 @@
+/**
+ * Tests for changelog generation from conventional commits
+ */
+
+const {
+  parseCommit,
+  resolveRepository,
+  groupCommits,
+  formatRefs,
+  compareUrl,
+  renderRelease,
+  updateChangelog
+} = require('../lib/changelog');
+
+const github = { host: 'github', url: 'https://github.com/acme/shop' };
+
+describe('changelog', () => {
+  describe('parseCommit', () => {
+    it('should parse type, scope, breaking marker and references', () => {
+      const commit = parseCommit({
+        hash: 'abc1234def',
+        subject: 'feat(cart)!: drop legacy totals (#42)',
+        body: 'Closes #7, refs #9\n\nBREAKING CHANGE: totals are now in cents'
+      });
+      expect(commit).toEqual({
+        hash: 'abc1234def',
+        type: 'feat',
+        scope: 'cart',
+        breaking: true,
+        breakingNote: 'totals are now in cents',
+        description: 'drop legacy totals',
+        prs: [42],
+        issues: [7, 9]
+      });
+    });
+
+    it('should keep non-conventional subjects and read merge requests', () => {
+      expect(parseCommit({ hash: 'a', subject: 'Update README' })).toMatchObject({ type: null, description: 'Update README' });
+      expect(parseCommit({ hash: 'a', subject: 'Merge pull request #5 from acme/fix' }).prs).toEqual([5]);
+      expect(parseCommit({ hash: 'a', subject: 'fix: retry (!12)' }, 'gitlab').prs).toEqual([12]);
+      expect(parseCommit({ hash: 'a', subject: 'fix: retry', body: 'See merge request acme/shop!31' }, 'gitlab').prs).toEqual([31]);
+    });
+  });
+
+  describe('resolveRepository', () => {
+    it('should map remotes to web URLs', () => {
+      expect(resolveRepository('git@github.com:acme/shop.git')).toEqual(github);
+      expect(resolveRepository('https://gitlab.com/group/sub/shop.git')).toEqual({ host: 'gitlab', url: 'https://gitlab.com/group/sub/shop' });
+      expect(resolveRepository('ssh://git@code.acme.io:2222/team/shop.git', 'gitlab-ci'))
+        .toEqual({ host: 'gitlab', url: 'https://code.acme.io/team/shop' });
+      expect(resolveRepository('https://code.acme.io/team/shop.git', 'jenkins')).toBeNull();
+      expect(resolveRepository('')).toBeNull();
+    });
+  });
+
+  describe('groupCommits', () => {
+    it('should group by section and scope, skipping chores in Keep a Changelog', () => {
+      const commits = [
+        'feat(ui): dark mode', 'fix: null total', 'feat: export CSV', 'chore: bump deps', 'feat(api): pagination', 'Tweak copy'
+      ].map((subject, i) => parseCommit({ hash: String(i), subject }));
+      const { sections } = groupCommits(commits);
+      expect(sections.map(section => section.title)).toEqual(['Added', 'Fixed']);
+      expect(sections[0].scopes.map(group => group.scope)).toEqual([null, 'api', 'ui']);
+      expect(groupCommits(commits, 'conventional').sections.map(section => section.title)).toEqual(['Features', 'Bug Fixes', 'Other']);
+    });
+  });
+
+  describe('formatRefs and compareUrl', () => {
+    it('should link per host', () => {
+      const commit = parseCommit({ hash: 'abc1234def', subject: 'fix: x (#3)', body: 'Fixes #4' });
+      expect(formatRefs(commit, github)).toBe(
+        ' ([#3](https://github.com/acme/shop/pull/3)) ([#4](https://github.com/acme/shop/issues/4)) ([abc1234](https://github.com/acme/shop/commit/abc1234def))'
+      );
+      expect(formatRefs(commit, { host: 'gitlab', url: 'https://gitlab.com/a/b' })).toContain('[!3](https://gitlab.com/a/b/-/merge_requests/3)');
+      expect(formatRefs(commit, null)).toBe(' (#3) (#4) (abc1234)');
+      expect(compareUrl(github, 'v1.1.0', '1.2.0')).toBe('https://github.com/acme/shop/compare/v1.1.0...v1.2.0');
+      expect(compareUrl(github, 'v1.1.0', 'Unreleased')).toBe('https://github.com/acme/shop/compare/v1.1.0...HEAD');
+      expect(compareUrl(github, null, '1.2.0')).toBeNull();
+    });
+  });
+
+  describe('renderRelease', () => {
+    const commits = [
+      parseCommit({ hash: 'aaaaaaa1', subject: 'feat(cart)!: totals in cents', body: 'BREAKING CHANGE: amounts are integers' }),
+      parseCommit({ hash: 'bbbbbbb2', subject: 'fix: rounding (#8)' })
+    ];
+
+    it('should render Keep a Changelog sections', () => {
+      expect(renderRelease(commits, { version: '2.0.0', date: '2026-10-01' })).toBe([
+        '## [2.0.0] - 2026-10-01',
+        '',
+        '### Added',
+        '- **BREAKING** **cart:** totals in cents. amounts are integers (aaaaaaa)',
+        '',
+        '### Fixed',
+        '- rounding (#8) (bbbbbbb)',
+        ''
+      ].join('\n'));
+    });
+
+    it('should render conventional-changelog sections with a compare heading', () => {
+      const text = renderRelease(commits, { style: 'conventional', version: '2.0.0', date: '2026-10-01', repository: github, compare: 'https://c' });
+      expect(text.split('\n').slice(0, 8)).toEqual([
+        '## [2.0.0](https://c) (2026-10-01)',
+        '',
+        '### ⚠ BREAKING CHANGES',
+        '',
+        '* **cart:** amounts are integers',
+        '',
+        '### Features',
+        ''
+      ]);
+      expect(text).toContain('* rounding ([#8](https://github.com/acme/shop/pull/8))');
+    });
+
+    it('should render nothing when no commit is notable', () => {
+      expect(renderRelease([parseCommit({ hash: 'a', subject: 'chore: lint' })])).toBe('');
+    });
+  });
+
+  describe('updateChangelog', () => {
+    const existing = [
+      '# Changelog',
+      '',
+      '## [Unreleased]',
+      '',
+      '### Added',
+      '- pending entry',
+      '',
+      '## [1.0.0] - 2026-01-01',
+      '',
+      '### Added',
+      '- first',
+      '',
+      '[1.0.0]: https://github.com/acme/shop/compare/v0.9.0...v1.0.0',
+      ''
+    ].join('\n');
+
+    it('should replace Unreleased with the release and keep an empty Unreleased heading', () => {
+      const updated = updateChangelog(existing, '## [1.1.0] - 2026-02-01\n\n### Fixed\n- bug\n', {
+        version: '1.1.0',
+        compare: 'https://github.com/acme/shop/compare/v1.0.0...v1.1.0'
+      });
+      expect(updated).toBe([
+        '# Changelog',
+        '',
+        '## [Unreleased]',
+        '',
+        '## [1.1.0] - 2026-02-01',
+        '',
+        '### Fixed',
+        '- bug',
+        '',
+        '## [1.0.0] - 2026-01-01',
+        '',
+        '### Added',
+        '- first',
+        '',
+        '[1.1.0]: https://github.com/acme/shop/compare/v1.0.0...v1.1.0',
+        '[1.0.0]: https://github.com/acme/shop/compare/v0.9.0...v1.0.0',
+        ''
+      ].join('\n'));
+    });
+
+    it('should replace an existing section for the same version', () => {
+      const updated = updateChangelog(existing, '## [1.0.0] - 2026-01-02\n\n### Added\n- redone\n', { version: '1.0.0' });
+      expect(updated).toContain('## [1.0.0] - 2026-01-02\n\n### Added\n- redone\n\n[1.0.0]:');
+      expect(updated).not.toContain('- first');
+      expect(updated).toContain('- pending entry');
+    });
+
+    it('should create a file with a header', () => {
+      const created = updateChangelog(null, '## [Unreleased]\n\n### Added\n- x\n');
+      expect(created.startsWith('# Changelog\n')).toBe(true);
+      expect(created).toContain('Keep a Changelog');
+      expect(created.endsWith('## [Unreleased]\n\n### Added\n- x\n')).toBe(true);
+    });
+  });
+});
@@ -0,0 +1,79 @@
+/**
+ * Tests for lib/utils/codeowners (owners of findings)
+ */
+
+const fs = require('fs');
+const os = require('os');
+const path = require('path');
+
+const codeowners = require('../lib/utils/codeowners');
+const { runPipeline } = require('../lib/patterns/pipeline');
+
+describe('codeowners', () => {
+  let root;
+  const write = (file, content) => {
+    fs.mkdirSync(path.dirname(path.join(root, file)), { recursive: true });
+    fs.writeFileSync(path.join(root, file), content);
+  };
+
+  beforeEach(() => {
+    root = fs.realpathSync(fs.mkdtempSync(path.join(os.tmpdir(), 'codeowners-')));
+  });
+
+  afterEach(() => {
+    fs.rmSync(root, { recursive: true, force: true });
+  });
+
+  it('should route paths to the last matching rule, keeping teams and emails', () => {
+    const rules = codeowners.parseCodeowners([
+      '* @lead',
+      'src/ @ana @acme/web dev@acme.io',
+      '/src/generated/',
+      'docs/**/*.md @acme/docs'
+    ].join('\n'));
+    expect(codeowners.ownersFor(rules, './src/cart.js')).toEqual(['@ana', '@acme/web', 'dev@acme.io']);
+    expect(codeowners.ownersFor(rules, 'src\\generated\\api.js')).toEqual([]);
+    expect(codeowners.ownersFor(rules, 'docs/guide/setup.md')).toEqual(['@acme/docs']);
+    expect(codeowners.ownersFor(rules, null)).toEqual(['@lead']);
+
+    const findings = codeowners.attachOwners([
+      { file: 'src/cart.js' },
+      { file: 'src/generated/api.js' },
+      { file: 'README', owners: ['@kept'] }
+    ], { rules });
+    expect(codeowners.groupByOwner(findings).map(group => [group.owner, group.findings.length])).toEqual([
+      ['@acme/web', 1], ['@ana', 1], ['@kept', 1], ['dev@acme.io', 1], [null, 1]
+    ]);
+    expect(codeowners.reviewersFor(findings, { exclude: ['ANA'] })).toEqual({ users: ['kept'], teams: ['acme/web'] });
+    expect(codeowners.attachOwners(findings, null)).toBe(findings);
+  });
+
+  it('should limit a trailing /* to direct children', () => {
+    const rules = codeowners.parseCodeowners([
+      '* @lead',
+      'docs/* @acme/docs',
+      '/apps/*/config/* @acme/ops',
+      'lib/**/* @acme/core'
+    ].join('\n'));
+    expect(codeowners.ownersFor(rules, 'docs/setup.md')).toEqual(['@acme/docs']);
+    expect(codeowners.ownersFor(rules, 'docs/a/b.md')).toEqual(['@lead']);
+    expect(codeowners.ownersFor(rules, 'apps/web/config/env.json')).toEqual(['@acme/ops']);
+    expect(codeowners.ownersFor(rules, 'apps/web/config/prod/env.json')).toEqual(['@lead']);
+    expect(codeowners.ownersFor(rules, 'lib/utils/deep/x.js')).toEqual(['@acme/core']);
+  });
+
+  it('should attach owners to pipeline findings only when the repository has a CODEOWNERS', () => {
+    write('src/app.js', 'function app() {\n  console.log("debug");\n}\n');
+    const scan = options => runPipeline(root, { thoroughness: 'quick', targetFiles: ['src/app.js'], baseline: false, linters: [], runtimes: [], repoMap: null, ...options });
+
+    const unowned = scan();
+    expect(unowned.codeowners).toBeNull();
+    expect(unowned.findings[0]).not.toHaveProperty('owners');
+
+    write('.github/CODEOWNERS', 'src/ @acme/web\n');
+    const owned = scan();
+    expect(owned.codeowners).toBe('.github/CODEOWNERS');
+    expect(owned.findings.every(finding => finding.owners.includes('@acme/web'))).toBe(true);
+    expect(scan({ codeowners: false }).findings[0]).not.toHaveProperty('owners');
+  });
+});
@@ -0,0 +1,180 @@
+/**
+ * Tests for lib/commit (conventional commit messages)
+ */
+
+const { execFileSync } = require('child_process');
+const fs = require('fs');
+const os = require('os');
+const path = require('path');
+
+const commit = require('../lib/commit');
+
+describe('commit', () => {
+  const symbol = (name, file, extra = {}) => ({ name, file, kind: 'function', line: 1, exported: true, ...extra });
+  const symbols = (changes = {}) => ({ symbols: { added: [], removed: [], renamed: [], changed: [], ...changes } });
+
+  it('should infer the type from file kinds and the symbol diff', () => {
+    const source = { file: 'lib/cart/index.js', status: 'modified' };
+    expect(commit.inferType([{ file: '__tests__/cart.test.js', status: 'added' }])).toBe('test');
+    expect(commit.inferType([{ file: 'docs/USAGE.md', status: 'modified' }, { file: 'README.md', status: 'modified' }])).toBe('docs');
+    expect(commit.inferType([{ file: 'package.json', status: 'modified' }, { file: 'package-lock.json', status: 'modified' }])).toBe('build');
+    expect(commit.inferType([{ file: '.github/workflows/ci.yml', status: 'modified' }, { file: 'README.md', status: 'modified' }])).toBe('chore');
+    expect(commit.inferType([source], symbols({ added: [symbol('total', source.file)] }))).toBe('feat');
+    expect(commit.inferType([source], symbols({ removed: [symbol('legacy', source.file)] }))).toBe('refactor');
+    expect(commit.inferType([source], symbols({ changed: [symbol('total', source.file, { before: { exported: true } })] }))).toBe('fix');
+    expect(commit.inferType([source], null)).toBe('fix');
+    // type-enum without fix falls back to the next allowed type
+    expect(commit.inferType([source], null, { 'type-enum': [2, 'always', ['feat', 'chore']] })).toBe('chore');
+  });
+
+  it('should infer the scope from the workspace package or source directory', () => {
+    const packages = [{ name: '@shop/api', path: 'packages/api' }, { name: '@shop/web', path: 'packages/web' }];
+    expect(commit.inferScope([
+      { file: 'packages/api/src/cart.ts', status: 'modified' },
+      { file: 'packages/api/test/cart.test.ts', status: 'modified' }
+    ], { packages })).toBe('api');
+    expect(commit.inferScope([
+      { file: 'packages/api/src/cart.ts', status: 'modified' },
+      { file: 'packages/web/src/cart.ts', status: 'modified' }
+    ], { packages })).toBeNull();
+    expect(commit.inferScope([
+      { file: 'lib/Cart/index.js', status: 'modified' },
+      { file: 'lib/Cart/total.js', status: 'added' },
+      { file: '__tests__/cart.test.js', status: 'added' }
+    ])).toBe('cart');
+    expect(commit.inferScope([{ file: 'src/index.js', status: 'modified' }])).toBeNull();
+    expect(commit.inferScope([{ file: 'lib/cart/index.js', status: 'modified' }], { rules: { 'scope-enum': [2, 'always', ['api']] } })).toBeNull();
+  });
+
+  it('should build a message with symbol bullets and a breaking footer', () => {
+    const files = [
+      { file: 'lib/cart/index.js', status: 'modified' },
+      { file: 'lib/cart/total.js', status: 'added' },
+      { file: 'docs/cart.md', status: 'modified' }
+    ];
+    const diff = symbols({
+      added: [symbol('computeTotal', 'lib/cart/total.js'), symbol('roundPrice', 'lib/cart/total.js', { exported: false })],
+      removed: [symbol('legacyTotal', 'lib/cart/index.js')],
+      changed: [symbol('addItem', 'lib/cart/index.js', { signature: '(item, qty)', before: { exported: true, signature: '(item)' } })]
+    });
+    const result = commit.buildMessage({ files, diff });
+
+    expect(result.header).toBe('feat(cart)!: add computeTotal and roundPrice');
+    expect(result.message).toBe([
+      'feat(cart)!: add computeTotal and roundPrice',
+      '',
+      '- Add `computeTotal` in lib/cart/total.js',
+      '- Add `roundPrice` in lib/cart/total.js',
+      '- Remove `legacyTotal` from lib/cart/index.js',
+      '- Change the signature of `addItem` in lib/cart/index.js',
+      '- Update docs/cart.md',
+      '',
+      'BREAKING CHANGE: `legacyTotal` removed',
+      ''
+    ].join('\n'));
+    expect(result.breaking.map(change => change.name)).toEqual(['legacyTotal']);
+    expect(result.problems).toEqual([]);
+  });
+
+  it('should fit the header and body to the commitlint limits', () => {
+    const files = [{ file: 'lib/cart/index.js', status: 'modified' }];
+    const diff = symbols({ added: ['computeTotal', 'applyDiscount', 'removeItem'].map(name => symbol(name, files[0].file)) });
+    const config = {
+      file: '.commitlintrc.json',
+      extends: ['@commitlint/config-conventional'],
+      rules: { 'header-max-length': [2, 'always', 40], 'body-max-line-length': [2, 'always', 30], 'subject-case': [2, 'always', 'sentence-case'] }
+    };
+    const result = commit.buildMessage({ files, diff, config });
+
+    expect(result.header).toBe('feat(cart): Add computeTotal and 2 more');
+    expect(result.body.split('\n')).toEqual([
+      '- Add `computeTotal` in',
+      '  lib/cart/index.js',
+      '- Add `applyDiscount` in',
+      '  lib/cart/index.js',
+      '- Add `removeItem` in',
+      '  lib/cart/index.js'
+    ]);
+    expect(result.problems).toEqual([]);
+  });
+
+  it('should report commitlint problems in a message', () => {
+    const rules = commit.resolveRules({ extends: ['@commitlint/config-conventional'], rules: { 'scope-empty': [1, 'never'] } });
+    expect(commit.lintMessage('Feature: Added the cart page.\nmore text\n', rules).map(problem => [problem.rule, problem.level])).toEqual([
+      ['type-enum', 'error'],
+      ['scope-empty', 'warning'],
+      ['subject-full-stop', 'error'],
+      ['subject-case', 'error'],
+      ['body-leading-blank', 'warning']
+    ]);
+    expect(commit.lintMessage('wip')[0]).toEqual({ rule: 'type-empty', level: 'error', message: 'Type is missing' });
+    expect(commit.lintMessage('fix(cart): round totals\n')).toEqual([]);
+  });
+
+  describe('repository', () => {
+    let root;
+    const write = (file, content) => {
+      fs.mkdirSync(path.dirname(path.join(root, file)), { recursive: true });
+      fs.writeFileSync(path.join(root, file), content);
+    };
+    const git = (...args) => execFileSync('git', args, { cwd: root, stdio: 'pipe' });
+
+    beforeEach(() => {
+      root = fs.mkdtempSync(path.join(os.tmpdir(), 'commit-'));
+    });
+
+    afterEach(() => {
+      fs.rmSync(root, { recursive: true, force: true });
+    });
+
+    it('should list staged files only', () => {
+      git('init', '-q');
+      git('config', 'user.email', 'test@example.com');
+      git('config', 'user.name', 'Test');
+      write('old.js', 'module.exports = 1;\n');
+      git('add', '.');
+      git('commit', '-q', '-m', 'init');
+      git('mv', 'old.js', 'new.js');
+      write('staged.js', 'module.exports = 2;\n');
+      write('unstaged.js', 'module.exports = 3;\n');
+      git('add', 'staged.js');
+
+      expect(commit.getStagedFiles(root)).toEqual([
+        { file: 'new.js', status: 'renamed', from: 'old.js' },
+        { file: 'staged.js', status: 'added' }
+      ]);
+    });
+
+    it('should read commitlint configs without running them', () => {
+      expect(commit.findCommitlintConfig(root)).toBeNull();
+
+      write('commitlint.config.ts', [
+        "import { RuleConfigSeverity, type UserConfig } from '@commitlint/types';",
+        'const Configuration: UserConfig = {',
+        "  extends: ['@commitlint/config-conventional'], // preset",
+        '  rules: {',
+        "    'scope-enum': [RuleConfigSeverity.Error, 'always', ['api', 'web']],",
+        "    'header-max-length': [RuleConfigSeverity.Warning, 'always', 72],",
+        '  },',
+        '  ignores: [message => message.startsWith("WIP")],',
+        '};',
+        'export default Configuration;'
+      ].join('\n'));
+      expect(commit.findCommitlintConfig(root)).toEqual({
+        file: 'commitlint.config.ts',
+        extends: ['@commitlint/config-conventional'],
+        rules: { 'scope-enum': [2, 'always', ['api', 'web']], 'header-max-length': [1, 'always', 72] },
+        error: null
+      });
+
+      write('.commitlintrc.yml', 'extends:\n  - "@commitlint/config-conventional"\nrules:\n  type-enum: [2, always, [feat, fix]]  # strict\n');
+      const yaml = commit.findCommitlintConfig(root);
+      expect(yaml.file).toBe('.commitlintrc.yml');
+      expect(commit.resolveRules(yaml)['type-enum']).toEqual([2, 'always', ['feat', 'fix']]);
+      expect(commit.resolveRules(yaml)['header-max-length']).toEqual([2, 'always', 100]);
+
+      write('package.json', JSON.stringify({ name: 'shop', commitlint: { rules: { 'type-enum': [2, 'always', ['feat']] } } }));
+      expect(commit.findCommitlintConfig(root)).toMatchObject({ file: 'package.json', extends: [] });
+    });
+  });
+});
@@ -0,0 +1,81 @@
+/**
+ * Tests for lib/config
+ */
+
+const fs = require('fs');
+const os = require('os');
+const path = require('path');
+
+const { loadConfig, parseConfig, validateConfig, commandArgs } = require('../lib/config');
+
+describe('config', () => {
+  let root;
+
+  beforeEach(() => {
+    root = fs.mkdtempSync(path.join(os.tmpdir(), 'config-'));
+  });
+
+  afterEach(() => {
+    fs.rmSync(root, { recursive: true, force: true });
+  });
+
+  it('should return an empty config when no file exists', () => {
+    expect(loadConfig(root)).toEqual({ config: {}, file: null, error: null });
+  });
+
+  it('should read .awesome-slash.json before .awsome-slash.json', () => {
+    fs.writeFileSync(path.join(root, '.awsome-slash.json'), '{"branchStrategy":"gitflow"}');
+    expect(loadConfig(root)).toEqual({ config: { branchStrategy: 'gitflow' }, file: '.awsome-slash.json', error: null });
+
+    fs.writeFileSync(path.join(root, '.awesome-slash.json'), '{"branchStrategy":"tag-based"}');
+    expect(loadConfig(root).config.branchStrategy).toBe('tag-based');
+  });
+
+  it('should load awesome-slash.config.js when there is no JSON config', () => {
+    fs.writeFileSync(path.join(root, 'awesome-slash.config.js'), "module.exports = { ignore: ['dist', 'build'].map(dir => `${dir}/**`) };\n");
+    expect(loadConfig(root)).toEqual({ config: { ignore: ['dist/**', 'build/**'] }, file: 'awesome-slash.config.js', error: null });
+
+    fs.writeFileSync(path.join(root, 'awesome-slash.config.js'), 'module.exports = [];\n');
+    expect(loadConfig(root).error).toBe('awesome-slash.config.js: module.exports must be an object');
+    fs.writeFileSync(path.join(root, 'awesome-slash.config.js'), 'throw new Error("boom");\n');
+    expect(loadConfig(root).error).toBe('awesome-slash.config.js: failed to load (boom)');
+  });
+
+  it('should validate the whole config against the schema', () => {
+    expect(validateConfig({
+      $schema: 'https://example.com/schema.json',
+      ignore: ['vendor/**'],
+      branchStrategy: 'gitflow',
+      commands: { deslop: { args: 'report src' } },
+      benchmark: { threshold: 10 }
+    })).toEqual({ valid: true, errors: [] });
+
+    expect(validateConfig({
+      branchStratgy: 'gitflow',
+      severity: { failOn: 'blocker' },
+      benchmark: { alpha: 1 },
+      notify: { channels: { team: { type: 'slack', url: 'http://hooks.example.com', token: 'x' } } }
+    }, '.awesome-slash.json').errors).toEqual([
+      '.awesome-slash.json: severity.failOn: must be one of critical, high, medium, low, explain, none',
+      '.awesome-slash.json: benchmark.alpha: must be less than 1',
+      '.awesome-slash.json: notify.channels.team.url: does not match pattern ^https://',
+      '.awesome-slash.json: notify.channels.team: Unexpected property: token',
+      '.awesome-slash.json: unknown key branchStratgy (did you mean branchStrategy?)'
+    ]);
+  });
+
+  it('should fall back to configured command arguments', () => {
+    fs.writeFileSync(path.join(root, '.awesome-slash.json'), '{"commands":{"flaky":{"args":"--runs 50  --create-issues"}}}');
+    expect(commandArgs('flaky', '', root)).toEqual(['--runs', '50', '--create-issues']);
+    expect(commandArgs('flaky', '--runs 10', root)).toEqual(['--runs', '10']);
+    expect(commandArgs('release', ' ', root)).toEqual([]);
+  });
+
+  it('should report invalid config files', () => {
+    expect(parseConfig('{ nope', '.awesome-slash.json').error).toContain('.awesome-slash.json: invalid JSON');
+    expect(parseConfig('[1]', '.awesome-slash.json')).toEqual({
+      config: {},
+      error: '.awesome-slash.json: top-level value must be an object'
+    });
+  });
+});
@@ -0,0 +1,204 @@
+/**
+ * Tests for project-defined slop patterns
+ */
+
+const fs = require('fs');
+const os = require('os');
+const path = require('path');
+
+const { compileCustomPattern, loadCustomPatterns, loadScanSettings } = require('../lib/patterns/custom-patterns');
+const { runPhase1, runPipeline } = require('../lib/patterns/pipeline');
+const slopAst = require('../lib/patterns/slop-ast');
+
+describe('custom-patterns', () => {
+  let root;
+
+  function write(files) {
+    for (const [file, content] of Object.entries(files)) {
+      const fullPath = path.join(root, file);
+      fs.mkdirSync(path.dirname(fullPath), { recursive: true });
+      fs.writeFileSync(fullPath, content);
+    }
+  }
+
+  beforeEach(() => {
+    root = fs.mkdtempSync(path.join(os.tmpdir(), 'custom-patterns-'));
+  });
+
+  afterEach(() => {
+    fs.rmSync(root, { recursive: true, force: true });
+  });
+
+  describe('compileCustomPattern', () => {
+    it('should compile a definition to the built-in pattern shape', () => {
+      const { pattern, errors } = compileCustomPattern('no_legacy_http', {
+        pattern: 'legacyHttp\\.(get|post)\\(',
+        flags: 'i',
+        severity: 'high',
+        exclude: ['vendor/*']
+      });
+
+      expect(errors).toEqual([]);
+      expect(pattern).toMatchObject({
+        severity: 'high',
+        autoFix: 'flag',
+        language: null,
+        exclude: ['vendor/*'],
+        description: 'Custom pattern no_legacy_http',
+        custom: true
+      });
+      expect(pattern.pattern.test('LegacyHTTP.get(url)')).toBe(true);
+    });
+
+    it('should report schema, name, and regex errors', () => {
+      expect(compileCustomPattern('console_debugging', { pattern: 'x' }).errors)
+        .toEqual(['slopPatterns.console_debugging: name collides with a built-in pattern']);
+      expect(compileCustomPattern('banned', { pattern: 'x', severity: 'urgent', extra: true }).errors).toEqual([
+        'slopPatterns.banned: severity: must be one of critical, high, medium, low, explain',
+        'slopPatterns.banned: Unexpected property: extra'
+      ]);
+      expect(compileCustomPattern('banned', { pattern: '(' }).errors[0])
+        .toContain('slopPatterns.banned.pattern: invalid regular expression');
+      expect(compileCustomPattern('banned', { severity: 'low' }).errors)
+        .toEqual(['slopPatterns.banned: requires pattern or ast']);
+      expect(compileCustomPattern('banned', { ast: { matcher: 'member_call', object: 'moment' } }).errors)
+        .toEqual(['slopPatterns.banned.ast: member_call requires properties']);
+    });
+  });
+
+  it('should load valid patterns from the project config and report invalid ones', () => {
+    write({
+      '.awesome-slash.json': JSON.stringify({
+        slopPatterns: {
+          no_eval: { pattern: '\\beval\\(', severity: 'critical' },
+          broken: { pattern: '[' }
+        }
+      })
+    });
+
+    const result = loadCustomPatterns(root);
+    expect(Object.keys(result.patterns)).toEqual(['no_eval']);
+    expect(result.file).toBe('.awesome-slash.json');
+    expect(result.errors).toHaveLength(1);
+    expect(result.errors[0]).toMatch(/^\.awesome-slash\.json: slopPatterns\.broken\.pattern: invalid regular expression/);
+  });
+
+  it('should flag banned APIs alongside built-in patterns in the pipeline', () => {
+    write({
+      '.awesome-slash.json': JSON.stringify({
+        slopPatterns: {
+          no_legacy_http: { pattern: 'legacyHttp\\.', severity: 'high', exclude: ['*.spec.js'] },
+          no_pickle: { pattern: '^import pickle', language: 'python' }
+        }
+      }),
+      'src/api.js': 'const res = legacyHttp.get(url);\nconsole.log(res);\n',
+      'src/api.spec.js': 'legacyHttp.get(url);\n',
+      'tools/dump.py': 'import pickle\n'
+    });
+
+    const result = runPipeline(root, {
+      thoroughness: 'quick',
+      targetFiles: ['src/api.js', 'src/api.spec.js', 'tools/dump.py'],
+      linters: [],
+      ast: false
+    });
+
+    const names = result.findings.map(f => `${f.file}:${f.patternName}`);
+    expect(names).toEqual(expect.arrayContaining([
+      'src/api.js:no_legacy_http',
+      'src/api.js:console_debugging',
+      'tools/dump.py:no_pickle'
+    ]));
+    expect(names).not.toContain('src/api.spec.js:no_legacy_http');
+    expect(result.findings.find(f => f.patternName === 'no_legacy_http').severity).toBe('high');
+    expect(result.customPatternErrors).toEqual([]);
+    expect(result.metadata.customPatterns).toBe(2);
+
+    const jsOnly = runPipeline(root, { thoroughness: 'quick', targetFiles: ['tools/dump.py'], language: 'javascript', linters: [], ast: false });
+    expect(jsOnly.findings).toEqual([]);
+  });
+
+  describe('scan settings', () => {
+    it('should ignore files, turn patterns off, and override severity', () => {
+      write({
+        '.awesome-slash.json': JSON.stringify({
+          ignore: ['generated/**'],
+          patterns: {
+            console_debugging: { severity: 'high', exclude: ['scripts/*'] },
+            hardcoded_secrets: { enabled: false }
+          },
+          severity: { failOn: 'high' }
+        }),
+        'src/api.js': "console.log(res);\nconst password = 'hunter2hunter2';\n",
+        'scripts/dev.js': 'console.log(1);\n',
+        'generated/client.js': 'console.log(2);\n'
+      });
+
+      const settings = loadScanSettings(root);
+      expect(settings.severity).toEqual({ failOn: 'high' });
+      const result = runPipeline(root, {
+        thoroughness: 'quick',
+        targetFiles: ['src/api.js', 'scripts/dev.js', 'generated/client.js'],
+        linters: [],
+        ast: false
+      });
+      expect(result.findings.map(f => `${f.file}:${f.patternName}:${f.severity}`)).toEqual(['src/api.js:console_debugging:high']);
+      expect(result.configErrors).toEqual([]);
+    });
+
+    it('should report invalid settings and apply none of them', () => {
+      write({
+        '.awesome-slash.json': JSON.stringify({
+          ignore: 'dist',
+          patterns: { console_debuging: { enabled: false }, old_todos: { severity: 'urgent' } }
+        })
+      });
+      expect(loadScanSettings(root)).toEqual({
+        ignore: [],
+        overrides: {},
+        severity: {},
+        file: '.awesome-slash.json',
+        errors: [
+          '.awesome-slash.json: ignore: expected type array, got string',
+          '.awesome-slash.json: patterns.old_todos.severity: must be one of critical, high, medium, low, explain',
+          '.awesome-slash.json: patterns.console_debuging: no built-in pattern named console_debuging'
+        ]
+      });
+    });
+  });
+
+  it('should run AST-only patterns when the parser is available', () => {
+    write({ 'src/date.js': 'import moment from "moment";\nconst s = "moment";\n' });
+    const { pattern } = compileCustomPattern('no_moment', { ast: { matcher: 'import', sources: ['moment'] } });
+    const program = {
+      type: 'Program',
+      loc: { start: { line: 1 } },
+      body: [{
+        type: 'ImportDeclaration',
+        loc: { start: { line: 1 } },
+        source: { type: 'StringLiteral', loc: { start: { line: 1 } }, value: 'moment' }
+      }]
+    };
+    const loader = () => ({ parse: () => ({ type: 'File', program }) });
+
+    const findings = runPhase1(root, ['src/date.js'], null, { require: loader }, { no_moment: pattern })
+      .filter(f => f.patternName === 'no_moment');
+    expect(findings).toEqual([expect.objectContaining({ line: 1, details: { engine: 'ast' } })]);
+
+    expect(runPhase1(root, ['src/date.js'], null, false, { no_moment: pattern })
+      .filter(f => f.patternName === 'no_moment')).toEqual([]);
+  });
+
+  it('should match call and import subpaths in slop-ast', () => {
+    const call = { type: 'CallExpression', callee: { type: 'Identifier', name: 'eval' }, arguments: [] };
+    const requireCall = {
+      type: 'CallExpression',
+      callee: { type: 'Identifier', name: 'require' },
+      arguments: [{ type: 'StringLiteral', value: 'lodash/get' }]
+    };
+
+    expect(slopAst.MATCHERS.call(call, { names: ['eval'] })).toBe(true);
+    expect(slopAst.MATCHERS.import(requireCall, { sources: ['lodash/*'] })).toBe(true);
+    expect(slopAst.MATCHERS.import(requireCall, { sources: ['lodash'] })).toBe(false);
+  });
+});
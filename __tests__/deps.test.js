@@ -0,0 +1,192 @@
+/**
+ * Tests for the dependency audit
+ */
+
+const fs = require('fs');
+const os = require('os');
+const path = require('path');
+
+const {
+  detectManagers,
+  parseGoRequires,
+  readDependencies,
+  parseOutdated,
+  getOutdated,
+  queryVulnerabilities,
+  findUnused,
+  auditDependencies,
+  renderReport
+} = require('../lib/deps');
+
+describe('deps', () => {
+  let dir;
+  const write = (file, content) => {
+    fs.mkdirSync(path.dirname(path.join(dir, file)), { recursive: true });
+    fs.writeFileSync(path.join(dir, file), content);
+  };
+
+  beforeEach(() => {
+    dir = fs.mkdtempSync(path.join(os.tmpdir(), 'deps-audit-'));
+  });
+
+  afterEach(() => {
+    fs.rmSync(dir, { recursive: true, force: true });
+  });
+
+  describe('detectManagers', () => {
+    it('should pick one manager per ecosystem from lockfiles', () => {
+      write('package.json', '{}');
+      write('yarn.lock', '');
+      write('requirements.txt', 'flask\n');
+      write('go.mod', 'module example.com/app\n');
+      expect(detectManagers(dir).map(entry => entry.manager)).toEqual(['yarn', 'pip', 'go']);
+    });
+  });
+
+  describe('readDependencies', () => {
+    it('should resolve locked versions per ecosystem', async () => {
+      write('package.json', JSON.stringify({ dependencies: { lodash: '^4.17.0' }, devDependencies: { jest: '^29.0.0' } }));
+      write('package-lock.json', JSON.stringify({ packages: { 'node_modules/lodash': { version: '4.17.20' }, 'node_modules/jest': { version: '29.7.0' } } }));
+      write('requirements.txt', 'Django==4.2.1\nrequests>=2\n');
+      write('Cargo.toml', '[dependencies]\nserde = "1"\n');
+      write('Cargo.lock', '[[package]]\nname = "serde"\nversion = "1.0.190"\n');
+      write('go.mod', 'module example.com/app\n\nrequire (\n\tgithub.com/gin-gonic/gin v1.9.0\n\tgolang.org/x/sys v0.10.0 // indirect\n)\n');
+
+      const deps = await readDependencies(dir);
+      const byName = Object.fromEntries(deps.map(dep => [dep.name, dep]));
+      expect(byName.lodash).toMatchObject({ ecosystem: 'npm', version: '4.17.20', dev: false });
+      expect(byName.jest).toMatchObject({ version: '29.7.0', dev: true });
+      expect(byName.django.version).toBe('4.2.1');
+      expect(byName.requests.version).toBeNull();
+      expect(byName.serde.version).toBe('1.0.190');
+      expect(byName['github.com/gin-gonic/gin']).toMatchObject({ version: 'v1.9.0', indirect: false });
+      expect(byName['golang.org/x/sys'].indirect).toBe(true);
+    });
+  });
+
+  describe('parseGoRequires', () => {
+    it('should read single-line requires', () => {
+      expect(parseGoRequires('require github.com/pkg/errors v0.9.1\n').get('github.com/pkg/errors')).toEqual({ version: 'v0.9.1', indirect: false });
+    });
+  });
+
+  describe('parseOutdated', () => {
+    it('should parse each manager output', () => {
+      expect(parseOutdated('npm', '{"lodash":{"current":"4.17.20","wanted":"4.17.21","latest":"4.17.21"}}')).toEqual([
+        { name: 'lodash', current: '4.17.20', wanted: '4.17.21', latest: '4.17.21' }
+      ]);
+      expect(parseOutdated('yarn', '{"type":"info","data":"x"}\n{"type":"table","data":{"body":[["react","17.0.2","17.0.2","18.2.0","dependencies",""]]}}')[0])
+        .toEqual({ name: 'react', current: '17.0.2', wanted: '17.0.2', latest: '18.2.0' });
+      expect(parseOutdated('pip', '[{"name":"Django","version":"4.2.1","latest_version":"5.0"}]')[0].latest).toBe('5.0');
+      expect(parseOutdated('poetry', 'requests (!) 2.28.0 2.31.0 Python HTTP for Humans.\n')[0]).toEqual({ name: 'requests', current: '2.28.0', wanted: null, latest: '2.31.0' });
+      expect(parseOutdated('cargo', '{"dependencies":[{"name":"serde","project":"1.0.1","compat":"1.0.190","latest":"1.0.190"},{"name":"log","project":"0.4.20","compat":"---","latest":"0.4.20"}]}'))
+        .toEqual([{ name: 'serde', current: '1.0.1', wanted: '1.0.190', latest: '1.0.190' }]);
+      expect(parseOutdated('go', '{\n\t"Path": "example.com/app",\n\t"Main": true\n}\n{\n\t"Path": "github.com/gin-gonic/gin",\n\t"Version": "v1.9.0",\n\t"Update": {"Version": "v1.9.1"}\n}\n'))
+        .toEqual([{ name: 'github.com/gin-gonic/gin', current: 'v1.9.0', wanted: null, latest: 'v1.9.1' }]);
+      expect(parseOutdated('npm', '')).toEqual([]);
+    });
+  });
+
+  describe('getOutdated', () => {
+    it('should flag major upgrades and skip failed commands', () => {
+      const run = (cwd, argv) => (argv[0] === 'npm' ? '{"react":{"current":"17.0.2","latest":"18.2.0"}}' : null);
+      const { outdated, skipped } = getOutdated(dir, [{ manager: 'npm', ecosystem: 'npm' }, { manager: 'go', ecosystem: 'go' }], run);
+      expect(outdated).toEqual([{ ecosystem: 'npm', manager: 'npm', name: 'react', current: '17.0.2', wanted: null, latest: '18.2.0', major: true }]);
+      expect(skipped).toEqual(['go']);
+    });
+  });
+
+  describe('queryVulnerabilities', () => {
+    it('should batch locked versions and read advisory details', async () => {
+      const calls = [];
+      const request = async (method, pathname, body) => {
+        calls.push([method, pathname, body]);
+        if (pathname === '/v1/querybatch') return { results: [{ vulns: [{ id: 'GHSA-1' }] }, {}] };
+        return {
+          id: 'GHSA-1',
+          aliases: ['CVE-2021-23337'],
+          summary: 'Command injection in lodash',
+          database_specific: { severity: 'HIGH' },
+          affected: [{ package: { name: 'lodash' }, ranges: [{ events: [{ introduced: '0' }, { fixed: '4.17.21' }] }] }]
+        };
+      };
+      const vulns = await queryVulnerabilities([
+        { ecosystem: 'npm', name: 'lodash', version: '4.17.20' },
+        { ecosystem: 'go', name: 'github.com/gin-gonic/gin', version: 'v1.9.0' },
+        { ecosystem: 'python', name: 'requests', version: null }
+      ], { request });
+      expect(calls[0][2].queries).toEqual([
+        { package: { name: 'lodash', ecosystem: 'npm' }, version: '4.17.20' },
+        { package: { name: 'github.com/gin-gonic/gin', ecosystem: 'Go' }, version: '1.9.0' }
+      ]);
+      expect(vulns).toEqual([{
+        ecosystem: 'npm', name: 'lodash', version: '4.17.20', id: 'GHSA-1', aliases: ['CVE-2021-23337'],
+        summary: 'Command injection in lodash', severity: 'high', fixed: '4.17.21'
+      }]);
+    });
+  });
+
+  describe('findUnused', () => {
+    const map = {
+      files: {
+        'src/app.ts': { language: 'typescript', imports: [{ source: '@scope/ui/button' }, { source: './util' }, { source: 'node:fs' }] },
+        'tools/job.py': { language: 'python', imports: [{ source: 'yaml' }, { source: 'requests.adapters' }] },
+        'main.go': { language: 'go', imports: [{ source: 'github.com/gin-gonic/gin/binding' }] }
+      }
+    };
+
+    it('should report dependencies no file imports', () => {
+      const deps = [
+        { ecosystem: 'npm', name: '@scope/ui', file: 'package.json', dev: false },
+        { ecosystem: 'npm', name: 'left-pad', file: 'package.json', dev: false },
+        { ecosystem: 'npm', name: 'rimraf', file: 'package.json', dev: true },
+        { ecosystem: 'npm', name: 'tsup', file: 'package.json', dev: true },
+        { ecosystem: 'npm', name: 'chalk', file: 'package.json', dev: true },
+        { ecosystem: 'python', name: 'pyyaml', file: 'requirements.txt', dev: false },
+        { ecosystem: 'python', name: 'requests', file: 'requirements.txt', dev: false },
+        { ecosystem: 'python', name: 'flask', file: 'requirements.txt', dev: false },
+        { ecosystem: 'go', name: 'github.com/gin-gonic/gin', file: 'go.mod', dev: false },
+        { ecosystem: 'go', name: 'golang.org/x/sys', file: 'go.mod', dev: false, indirect: true },
+        { ecosystem: 'rust', name: 'serde', file: 'Cargo.toml', dev: false }
+      ];
+      expect(findUnused(deps, map, { scripts: '{"build":"tsup src/index.ts"}' })).toEqual([
+        { ecosystem: 'npm', name: 'left-pad', file: 'package.json', dev: false, confidence: 'high' },
+        { ecosystem: 'npm', name: 'chalk', file: 'package.json', dev: true, confidence: 'low' },
+        { ecosystem: 'python', name: 'flask', file: 'requirements.txt', dev: false, confidence: 'high' }
+      ]);
+      expect(findUnused(deps, null)).toEqual([]);
+    });
+  });
+
+  describe('auditDependencies', () => {
+    it('should combine the checks into one report', async () => {
+      write('package.json', JSON.stringify({ dependencies: { lodash: '^4.17.0', 'left-pad': '^1.3.0' } }));
+      write('package-lock.json', JSON.stringify({ packages: { 'node_modules/lodash': { version: '4.17.20' } } }));
+      const report = await auditDependencies(dir, {
+        map: { files: { 'index.js': { language: 'javascript', imports: [{ source: 'lodash' }] } } },
+        run: () => '{"lodash":{"current":"4.17.20","wanted":"4.17.21","latest":"4.17.21"}}',
+        request: async () => { throw new Error('offline'); }
+      });
+      expect(report).toMatchObject({
+        success: true,
+        managers: ['npm'],
+        dependencies: 2,
+        unlocked: ['left-pad'],
+        vulnerabilities: [],
+        errors: ['OSV lookup failed: offline']
+      });
+      expect(report.outdated).toHaveLength(1);
+      expect(report.unused.map(dep => dep.name)).toEqual(['left-pad']);
+
+      const text = renderReport(report);
+      expect(text).toContain('**Vulnerable**: 0 | **Outdated**: 1 | **Unused**: 1');
+      expect(text).toContain('| lodash | 4.17.20 | 4.17.21 | npm |');
+      expect(text).toContain('| left-pad | package.json | high |');
+      expect(text).toContain('- OSV lookup failed: offline');
+    });
+
+    it('should fail without a manifest', async () => {
+      expect((await auditDependencies(dir)).success).toBe(false);
+    });
+  });
+});
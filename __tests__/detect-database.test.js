@@ -0,0 +1,169 @@
+/**
+ * Tests for detect-database.js
+ */
+
+const fs = require('fs');
+const os = require('os');
+const path = require('path');
+
+const {
+  detectDatabases,
+  parsePythonDependencies,
+  parseGoModules,
+  parseCargoDependencies,
+  parseComposeServices,
+  normalizeImage
+} = require('../lib/platform/detect-database');
+
+describe('detect-database', () => {
+  let root;
+
+  function write(files) {
+    for (const [file, content] of Object.entries(files)) {
+      const fullPath = path.join(root, file);
+      fs.mkdirSync(path.dirname(fullPath), { recursive: true });
+      fs.writeFileSync(fullPath, content);
+    }
+  }
+
+  beforeEach(() => {
+    root = fs.mkdtempSync(path.join(os.tmpdir(), 'detect-database-'));
+  });
+
+  afterEach(() => {
+    fs.rmSync(root, { recursive: true, force: true });
+  });
+
+  describe('parsers', () => {
+    it('should read PEP 621, Poetry, and requirements dependencies', () => {
+      const pyproject = [
+        '[project]',
+        'name = "redis-tools"',
+        'dependencies = [',
+        '  "SQLAlchemy[asyncio]>=2.0",',
+        '  "psycopg2-binary",',
+        ']',
+        '',
+        '[project.optional-dependencies]',
+        'cache = ["redis>=5"]',
+        '',
+        '[tool.poetry.dependencies]',
+        'python = "^3.11"',
+        'asyncpg = { version = "^0.29" }'
+      ].join('\n');
+
+      expect(parsePythonDependencies(pyproject, 'pyproject.toml'))
+        .toEqual(['sqlalchemy', 'psycopg2-binary', 'redis', 'asyncpg']);
+      expect(parsePythonDependencies('-r base.txt\nPyMySQL==1.1  # driver\n', 'requirements.txt'))
+        .toEqual(['pymysql']);
+    });
+
+    it('should read go.mod requires and Cargo dependency tables', () => {
+      const goMod = 'module example.com/app\n\nrequire github.com/lib/pq v1.10.9\n\nrequire (\n\tgorm.io/gorm v1.25.0\n\tgithub.com/jackc/pgx/v5 v5.5.0 // indirect\n)\n';
+      expect(parseGoModules(goMod)).toEqual(['github.com/lib/pq', 'gorm.io/gorm', 'github.com/jackc/pgx/v5']);
+
+      const cargo = '[package]\nname = "app"\n\n[dependencies]\nrusqlite = "0.31"\n\n[dependencies.redis]\nversion = "0.25"\n\n[dev-dependencies]\ntokio-postgres = "0.7"\n';
+      expect(parseCargoDependencies(cargo)).toEqual(['rusqlite', 'redis', 'tokio-postgres']);
+    });
+
+    it('should map compose services to images', () => {
+      const compose = 'version: "3"\nservices:\n  db:\n    image: docker.io/library/postgres:16\n    environment:\n      image: not-a-service\n  cache:\n    image: "redis:7-alpine"\nvolumes:\n  data:\n';
+      expect(parseComposeServices(compose)).toEqual([
+        { service: 'db', image: 'docker.io/library/postgres:16' },
+        { service: 'cache', image: 'redis:7-alpine' }
+      ]);
+      expect(normalizeImage('docker.io/library/postgres:16')).toBe('library/postgres');
+      expect(normalizeImage('localhost:5000/bitnami/mysql@sha256:abc')).toBe('bitnami/mysql');
+    });
+  });
+
+  describe('detectDatabases', () => {
+    it('should return null for projects without databases', async () => {
+      write({ 'package.json': '{"dependencies":{"express":"^4"}}' });
+      expect(await detectDatabases(root)).toBeNull();
+    });
+
+    it('should detect Prisma with its datasource provider and migrations', async () => {
+      write({
+        'package.json': '{"dependencies":{"@prisma/client":"^5","ioredis":"^5"},"devDependencies":{"prisma":"^5"}}',
+        'prisma/schema.prisma': 'datasource db {\n  provider = "postgresql"\n  url = env("DATABASE_URL")\n}\n',
+        'prisma/migrations/20240101000000_init/migration.sql': 'CREATE TABLE users ();'
+      });
+
+      expect(await detectDatabases(root)).toEqual({
+        databases: [
+          { name: 'postgres', sources: ['prisma/schema.prisma'] },
+          { name: 'redis', sources: ['package.json:ioredis'] }
+        ],
+        orms: [{
+          name: 'prisma',
+          database: 'postgres',
+          sources: ['package.json:@prisma/client', 'package.json:prisma', 'prisma/schema.prisma']
+        }],
+        migrations: [{ path: 'prisma/migrations', tool: 'prisma' }]
+      });
+    });
+
+    it('should detect Drizzle dialect and compose services', async () => {
+      write({
+        'package.json': '{"dependencies":{"drizzle-orm":"^0.30","mysql2":"^3"}}',
+        'drizzle.config.ts': "export default { dialect: 'mysql', schema: './src/schema.ts' };",
+        'docker-compose.yml': 'services:\n  mysql:\n    image: mariadb:11\n  mongo:\n    image: mongo:7\n',
+        'drizzle/0000_init.sql': 'CREATE TABLE t ();'
+      });
+
+      const result = await detectDatabases(root);
+      expect(result.databases).toEqual([
+        { name: 'mysql', sources: ['package.json:mysql2', 'docker-compose.yml:mysql', 'drizzle.config.ts'] },
+        { name: 'mongodb', sources: ['docker-compose.yml:mongo'] }
+      ]);
+      expect(result.orms).toEqual([{ name: 'drizzle', database: 'mysql', sources: ['package.json:drizzle-orm', 'drizzle.config.ts'] }]);
+      expect(result.migrations).toEqual([{ path: 'drizzle', tool: 'drizzle' }]);
+    });
+
+    it('should detect SQLAlchemy with Alembic and Django app migrations', async () => {
+      write({
+        'requirements.txt': 'sqlalchemy==2.0\nalembic\n',
+        'alembic.ini': '[alembic]\nsqlalchemy.url = sqlite:///app.db\n',
+        'migrations/env.py': '',
+        'migrations/versions/001_init.py': '',
+        'shop/migrations/__init__.py': '',
+        'shop/migrations/0001_initial.py': ''
+      });
+
+      const result = await detectDatabases(root);
+      expect(result.databases).toEqual([{ name: 'sqlite', sources: ['alembic.ini'] }]);
+      expect(result.orms).toEqual([{ name: 'sqlalchemy', database: 'sqlite', sources: ['requirements.txt:sqlalchemy', 'alembic.ini'] }]);
+      expect(result.migrations).toEqual([
+        { path: 'migrations/versions', tool: 'alembic' },
+        { path: 'shop/migrations', tool: 'django' }
+      ]);
+    });
+
+    it('should detect GORM drivers and golang-migrate directories', async () => {
+      write({
+        'go.mod': 'module app\n\nrequire (\n\tgorm.io/gorm v1.25.0\n\tgorm.io/driver/postgres v1.5.0\n\tgithub.com/redis/go-redis/v9 v9.5.0\n)\n',
+        'db/migrations/0001_users.up.sql': '',
+        'db/migrations/0001_users.down.sql': ''
+      });
+
+      const result = await detectDatabases(root);
+      expect(result.databases.map(db => db.name)).toEqual(['postgres', 'redis']);
+      expect(result.orms).toEqual([{ name: 'gorm', database: 'postgres', sources: ['go.mod:gorm.io/gorm'] }]);
+      expect(result.migrations).toEqual([{ path: 'db/migrations', tool: 'golang-migrate' }]);
+    });
+
+    it('should detect ActiveRecord adapters in Rails apps', async () => {
+      write({
+        'Gemfile': "source 'https://rubygems.org'\ngem 'rails', '~> 7.1'\ngem \"pg\"\n",
+        'config/database.yml': 'default: &default\n  adapter: postgresql\n  pool: 5\n',
+        'db/migrate/20240101000000_create_users.rb': ''
+      });
+
+      const result = await detectDatabases(root);
+      expect(result.databases).toEqual([{ name: 'postgres', sources: ['Gemfile:pg', 'config/database.yml'] }]);
+      expect(result.orms).toEqual([{ name: 'activerecord', database: 'postgres', sources: ['Gemfile:rails', 'config/database.yml'] }]);
+      expect(result.migrations).toEqual([{ path: 'db/migrate', tool: 'activerecord' }]);
+    });
+  });
+});
@@ -0,0 +1,75 @@
+/**
+ * Tests for detect-framework-versions.js
+ */
+
+const fs = require('fs');
+const os = require('os');
+const path = require('path');
+
+const {
+  detectFrameworkVersions,
+  npmLockVersion,
+  pythonLockVersion,
+  gemLockVersion
+} = require('../lib/platform/detect-framework-versions');
+
+describe('detect-framework-versions', () => {
+  let root;
+
+  function write(files) {
+    for (const [file, content] of Object.entries(files)) {
+      const fullPath = path.join(root, file);
+      fs.mkdirSync(path.dirname(fullPath), { recursive: true });
+      fs.writeFileSync(fullPath, content);
+    }
+  }
+
+  function framework(name) {
+    return detectFrameworkVersions(root).frameworks.find(entry => entry.name === name);
+  }
+
+  beforeEach(() => {
+    root = fs.mkdtempSync(path.join(os.tmpdir(), 'detect-framework-versions-'));
+  });
+
+  afterEach(() => {
+    fs.rmSync(root, { recursive: true, force: true });
+  });
+
+  it('should return null when no framework is found', () => {
+    write({ 'package.json': '{"dependencies": {"lodash": "^4.17.21"}}' });
+    expect(detectFrameworkVersions(root)).toBeNull();
+  });
+
+  describe('npmLockVersion', () => {
+    it('should read npm, yarn and pnpm lockfiles', () => {
+      expect(npmLockVersion('package-lock.json', JSON.stringify({ packages: { 'node_modules/react': { version: '18.3.1' } } }), 'react')).toBe('18.3.1');
+      expect(npmLockVersion('package-lock.json', JSON.stringify({ dependencies: { react: { version: '17.0.2' } } }), 'react')).toBe('17.0.2');
+      expect(npmLockVersion('yarn.lock', 'react-dom@^18.2.0:\n  version "18.2.0"\n\n"react@^18.2.0", react@^18.0.0:\n  version "18.3.1"\n', 'react')).toBe('18.3.1');
+      expect(npmLockVersion('yarn.lock', '"@angular/core@npm:^17.0.0":\n  version: 17.3.2\n  resolution: "@angular/core@npm:17.3.2"\n', '@angular/core')).toBe('17.3.2');
+      expect(npmLockVersion('pnpm-lock.yaml', 'packages:\n\n  next@14.2.3(react@18.3.1):\n    resolution: {}\n', 'next')).toBe('14.2.3');
+      expect(npmLockVersion('pnpm-lock.yaml', 'packages:\n  /vue/3.4.21:\n    resolution: {}\n', 'vue')).toBe('3.4.21');
+      expect(npmLockVersion('package-lock.json', 'not json', 'react')).toBeNull();
+    });
+  });
+
+  describe('pythonLockVersion and gemLockVersion', () => {
+    it('should read Poetry, uv, Pipenv and Bundler lockfiles', () => {
+      expect(pythonLockVersion('poetry.lock', '[[package]]\nname = "Django"\nversion = "5.0.4"\n\n[[package]]\nname = "pydantic"\nversion = "2.7.0"\n', 'pydantic')).toBe('2.7.0');
+      expect(pythonLockVersion('Pipfile.lock', JSON.stringify({ default: { Django: { version: '==4.2.11' } } }), 'django')).toBe('4.2.11');
+      expect(gemLockVersion('GEM\n  specs:\n    rails (7.1.3)\n      actioncable (= 7.1.3)\n', 'rails')).toBe('7.1.3');
+    });
+  });
+
+  it('should prefer locked versions and fall back to manifest ranges', () => {
+    write({
+      'package.json': '{"dependencies": {"react": "^18.2.0", "vue": "~3.4.0"}}',
+      'package-lock.json': JSON.stringify({ packages: { 'node_modules/react': { version: '18.3.1' } } }),
+      'requirements.txt': 'Django>=4.2,<5\npydantic[email]==1.10.13\n'
+    });
+    expect(framework('react')).toEqual({ name: 'react', package: 'react', version: '18.3.1', major: 18, source: 'package-lock.json' });
+    expect(framework('vue')).toMatchObject({ version: '3.4.0', major: 3, source: 'package.json#dependencies' });
+    expect(framework('django')).toMatchObject({ version: '4.2', major: 4, source: 'requirements.txt' });
+    expect(framework('pydantic')).toMatchObject({ version: '1.10.13', major: 1 });
+  });
+});
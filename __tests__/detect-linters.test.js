@@ -0,0 +1,155 @@
+/**
+ * Tests for detect-linters.js
+ */
+
+const fs = require('fs');
+const os = require('os');
+const path = require('path');
+
+const {
+  detectLinters,
+  isRuleEnabled,
+  findEnforcingRule,
+  parseEslintConfig,
+  parseGolangciConfig,
+  parseJsonc
+} = require('../lib/platform/detect-linters');
+
+describe('detect-linters', () => {
+  let root;
+
+  function write(files) {
+    for (const [file, content] of Object.entries(files)) {
+      const fullPath = path.join(root, file);
+      fs.mkdirSync(path.dirname(fullPath), { recursive: true });
+      fs.writeFileSync(fullPath, content);
+    }
+  }
+
+  function linter(name) {
+    return detectLinters(root).linters.find(entry => entry.name === name);
+  }
+
+  beforeEach(() => {
+    root = fs.mkdtempSync(path.join(os.tmpdir(), 'detect-linters-'));
+  });
+
+  afterEach(() => {
+    fs.rmSync(root, { recursive: true, force: true });
+  });
+
+  it('should return null without linters', () => {
+    write({ 'package.json': '{"name":"app"}' });
+    expect(detectLinters(root)).toBeNull();
+  });
+
+  it('should parse JSONC with comments and trailing commas', () => {
+    expect(parseJsonc('{\n  // comment\n  "a": "http://x", /* block */\n  "b": [1, 2,],\n}')).toEqual({ a: 'http://x', b: [1, 2] });
+  });
+
+  describe('ESLint', () => {
+    it('should read legacy JSON rules and the recommended preset', () => {
+      write({ '.eslintrc.json': '{"extends": ["eslint:recommended"], "rules": {"no-console": ["error", {"allow": ["warn"]}], "no-empty": "off"}}' });
+      const eslint = linter('eslint');
+
+      expect(eslint).toMatchObject({ language: 'js', lints: true, formats: false, sources: ['.eslintrc.json'], presets: ['recommended'] });
+      expect(isRuleEnabled(eslint, 'no-console')).toBe(true);
+      expect(isRuleEnabled(eslint, 'no-empty')).toBe(false);
+      expect(isRuleEnabled(eslint, 'no-unreachable')).toBe(true);
+      expect(isRuleEnabled(eslint, 'no-magic-numbers')).toBe(false);
+    });
+
+    it('should scan flat configs for rule levels', () => {
+      const config = "import js from '@eslint/js';\nexport default [js.configs.recommended, { rules: { 'no-console': 'warn', 'n/no-process-exit': 2, quotes: ['error', 'single'] } }];";
+      expect(parseEslintConfig('eslint.config.js', config)).toEqual({
+        presets: ['recommended'],
+        rules: { 'no-console': 'warn', 'n/no-process-exit': 'error', quotes: 'error' }
+      });
+    });
+
+    it('should read eslintConfig and prettier from package.json', () => {
+      write({ 'package.json': '{"eslintConfig":{"rules":{"no-warning-comments":1}},"prettier":{"semi":false}}' });
+      expect(isRuleEnabled(linter('eslint'), 'no-warning-comments')).toBe(true);
+      expect(linter('prettier')).toMatchObject({ formats: true, lints: false, sources: ['package.json#prettier'] });
+    });
+  });
+
+  it('should read Biome rule groups and formatter state', () => {
+    write({ 'biome.jsonc': '{\n  "formatter": { "enabled": false },\n  "linter": { "rules": { "suspicious": { "noConsole": { "level": "error" } }, "correctness": { "noUnreachable": "off" } } }\n}' });
+    const biome = linter('biome');
+
+    expect(biome.formats).toBe(false);
+    expect(isRuleEnabled(biome, 'suspicious/noConsole')).toBe(true);
+    expect(isRuleEnabled(biome, 'correctness/noUnreachable')).toBe(false);
+    expect(isRuleEnabled(biome, 'suspicious/noDebugger')).toBe(true);
+  });
+
+  it('should apply ruff select, extend-select, and ignore prefixes', () => {
+    write({
+      'pyproject.toml': '[tool.ruff]\nline-length = 100\n\n[tool.ruff.lint]\nselect = [\n  "E",\n  "F",\n  "T20",\n]\nignore = ["E303"]\n\n[tool.ruff.format]\nquote-style = "single"\n'
+    });
+    const ruff = linter('ruff');
+
+    expect(ruff).toMatchObject({ formats: true, select: ['E', 'F', 'T20'], ignore: ['E303'], sources: ['pyproject.toml'] });
+    expect(isRuleEnabled(ruff, 'T201')).toBe(true);
+    expect(isRuleEnabled(ruff, 'E303')).toBe(false);
+    expect(isRuleEnabled(ruff, 'E101')).toBe(true);
+    expect(isRuleEnabled(ruff, 'ERA001')).toBe(false);
+  });
+
+  it('should use ruff defaults and detect black from pre-commit', () => {
+    write({ '.pre-commit-config.yaml': 'repos:\n  - repo: https://github.com/astral-sh/ruff-pre-commit\n    hooks:\n      - id: ruff\n  - repo: https://github.com/psf/black\n    hooks:\n      - id: black\n' });
+
+    expect(linter('ruff')).toMatchObject({ formats: false, select: ['E4', 'E7', 'E9', 'F'], sources: ['.pre-commit-config.yaml'] });
+    expect(linter('black')).toMatchObject({ formats: true, sources: ['.pre-commit-config.yaml'] });
+  });
+
+  it('should read flake8 sections from setup.cfg', () => {
+    write({ 'setup.cfg': '[metadata]\nname = app\n\n[flake8]\nmax-line-length = 100\nextend-select =\n    T20,\n    B\nextend-ignore = E303\n' });
+    const flake8 = linter('flake8');
+
+    expect(flake8.select).toEqual(['E', 'F', 'W', 'C90', 'T20', 'B']);
+    expect(isRuleEnabled(flake8, 'W291')).toBe(true);
+    expect(isRuleEnabled(flake8, 'E303')).toBe(false);
+  });
+
+  it('should read golangci-lint v1 and v2 linter lists', () => {
+    const v1 = parseGolangciConfig('.golangci.yml', 'run:\n  timeout: 5m\nlinters:\n  disable-all: true\n  enable:\n    - govet\n    - godox\n');
+    expect(v1).toEqual({ presets: [], rules: { govet: 'error', godox: 'error' } });
+
+    write({ '.golangci.yaml': 'version: "2"\nlinters:\n  default: standard\n  enable: [mnd]\n  disable:\n  - errcheck\n  settings:\n    mnd:\n      checks: [argument]\n' });
+    const golangci = linter('golangci-lint');
+    expect(isRuleEnabled(golangci, 'mnd')).toBe(true);
+    expect(isRuleEnabled(golangci, 'errcheck')).toBe(false);
+    expect(isRuleEnabled(golangci, 'staticcheck')).toBe(true);
+    expect(isRuleEnabled(golangci, 'godox')).toBe(false);
+  });
+
+  it('should read clippy lints from Cargo.toml and crate attributes', () => {
+    write({
+      'Cargo.toml': '[package]\nname = "app"\n\n[lints.clippy]\nprint_stdout = "deny"\npedantic = { level = "warn", priority = -1 }\n',
+      'src/main.rs': '#![warn(clippy::dbg_macro, clippy::print_stderr)]\nfn main() {}\n',
+      'rustfmt.toml': 'edition = "2021"\n'
+    });
+    const clippy = linter('clippy');
+
+    expect(clippy.rules).toEqual({ print_stdout: 'error', pedantic: 'warn', dbg_macro: 'warn', print_stderr: 'warn' });
+    expect(linter('rustfmt').formats).toBe(true);
+  });
+
+  it('should find the enforcing rule for a file language', () => {
+    write({
+      '.eslintrc.json': '{"rules":{"no-console":"error"}}',
+      '.prettierrc': '{}',
+      'ruff.toml': 'select = ["T20"]\n'
+    });
+    const { linters } = detectLinters(root);
+
+    expect(findEnforcingRule(linters, ['eslint:no-console'], 'js')).toBe('eslint:no-console');
+    expect(findEnforcingRule(linters, ['prettier', 'black'], 'js')).toBe('prettier');
+    expect(findEnforcingRule(linters, ['prettier', 'black'], 'python')).toBeNull();
+    // Combination entries need every rule (T10 is not selected)
+    expect(findEnforcingRule(linters, [['ruff:T20', 'ruff:T10']], 'python')).toBeNull();
+    expect(findEnforcingRule(linters, [['ruff:T20']], 'python')).toBe('ruff:T20');
+  });
+});
@@ -0,0 +1,138 @@
+/**
+ * Tests for the persisted detection cache in detect-platform.js
+ */
+
+const fs = require('fs');
+const os = require('os');
+const path = require('path');
+
+describe('detect-platform persisted cache', () => {
+  const originalCwd = process.cwd();
+  const originalStateDir = process.env.AI_STATE_DIR;
+  let root;
+
+  function write(files) {
+    for (const [file, content] of Object.entries(files)) {
+      const fullPath = path.join(root, file);
+      fs.mkdirSync(path.dirname(fullPath), { recursive: true });
+      fs.writeFileSync(fullPath, content);
+    }
+  }
+
+  // Bump mtimes explicitly; filesystem timestamps can be coarse
+  function touch(file, offsetMs = 5000) {
+    const time = new Date(Date.now() + offsetMs);
+    fs.utimesSync(path.join(root, file), time, time);
+  }
+
+  // A fresh module instance has an empty in-memory cache, like a new process
+  function load() {
+    let platform;
+    jest.isolateModules(() => {
+      platform = require('../lib/platform/detect-platform');
+    });
+    return platform;
+  }
+
+  beforeEach(() => {
+    root = fs.realpathSync(fs.mkdtempSync(path.join(os.tmpdir(), 'detect-platform-cache-')));
+    process.chdir(root);
+    process.env.AI_STATE_DIR = '.state';
+  });
+
+  afterEach(() => {
+    process.chdir(originalCwd);
+    if (originalStateDir === undefined) delete process.env.AI_STATE_DIR;
+    else process.env.AI_STATE_DIR = originalStateDir;
+    fs.rmSync(root, { recursive: true, force: true });
+  });
+
+  it('should persist results and reuse them in a new process', async () => {
+    write({ 'package.json': '{"name":"app"}', 'package-lock.json': '{}' });
+    const first = await load().detect();
+
+    const record = JSON.parse(fs.readFileSync(path.join(root, '.awesome-slash', 'cache', 'detection', 'platform.json'), 'utf8'));
+    expect(record).toMatchObject({ version: 5, createdAt: first.timestamp, detection: { projectType: 'nodejs' } });
+    expect(record.markers).toEqual(expect.arrayContaining(['.', 'package.json', 'Cargo.toml']));
+
+    const second = await load().detect();
+    expect(second.timestamp).toBe(first.timestamp);
+    expect(second.packageManager).toBe('npm');
+  });
+
+  it('should invalidate when a marker file is added or edited', async () => {
+    write({ 'package.json': '{"name":"app"}' });
+    await load().detect();
+
+    write({ 'Cargo.toml': '[package]\nname = "app"\n' });
+    fs.rmSync(path.join(root, 'package.json'));
+    touch('.');
+    const second = await load().detect();
+    expect(second.projectType).toBe('rust');
+    expect((await load().detect()).timestamp).toBe(second.timestamp);
+
+    write({ 'Cargo.toml': '[workspace]\n' });
+    touch('Cargo.toml', 10000);
+    expect((await load().detect()).timestamp).not.toBe(second.timestamp);
+  });
+
+  it('should invalidate when a nested directory changes', async () => {
+    write({ 'deploy/k8s/app.yaml': 'apiVersion: v1\nkind: Service\n' });
+    expect((await load().detect()).containerization.manifests).toEqual(['deploy/k8s/app.yaml']);
+
+    write({ 'deploy/Dockerfile': 'FROM node:20' });
+    touch('deploy');
+    expect((await load().detect()).containerization.dockerfiles).toEqual(['deploy/Dockerfile']);
+  });
+
+  it('should ignore stale or foreign records and recompute on forceRefresh', async () => {
+    write({ 'go.mod': 'module app\n', '.awesome-slash/cache/detection/platform.json': '{"version":0,"detection":{"projectType":"rust"}}' });
+    const platform = load();
+    expect((await platform.detect()).projectType).toBe('go');
+
+    const record = JSON.parse(fs.readFileSync(platform.getPersistedCachePath(), 'utf8'));
+    record.createdAt = new Date(Date.now() - 2 * 24 * 60 * 60 * 1000).toISOString();
+    record.detection.projectType = 'rust';
+    fs.writeFileSync(platform.getPersistedCachePath(), JSON.stringify(record));
+    expect((await load().detect()).projectType).toBe('go');
+
+    const refreshed = await platform.detect(true);
+    expect(refreshed.projectType).toBe('go');
+  });
+
+  it('should trace why the persisted result was not reused', async () => {
+    write({ 'package.json': '{"name":"app"}' });
+    await load().detect();
+    write({ 'package.json': '{"name":"renamed"}' });
+    touch('package.json');
+
+    const lines = [];
+    let platform;
+    let logger;
+    jest.isolateModules(() => {
+      platform = require('../lib/platform/detect-platform');
+      logger = require('../lib/utils/logger');
+    });
+    const original = process.env.AWESOME_SLASH_LOG_LEVEL;
+    process.env.AWESOME_SLASH_LOG_LEVEL = 'debug';
+    logger.setSink(line => lines.push(line));
+    try {
+      await platform.detect();
+    } finally {
+      if (original === undefined) delete process.env.AWESOME_SLASH_LOG_LEVEL;
+      else process.env.AWESOME_SLASH_LOG_LEVEL = original;
+    }
+    expect(lines[0]).toMatch(/^platform:detect: persisted cache skipped \(reason=changed since .*: package\.json\)$/);
+    expect(lines[1]).toMatch(/^platform:detect: detected \(.*projectType=nodejs/);
+  });
+
+  it('should remove the persisted result on invalidateCache', async () => {
+    write({ 'go.mod': 'module app\n' });
+    const platform = load();
+    await platform.detect();
+    expect(fs.existsSync(platform.getPersistedCachePath())).toBe(true);
+
+    platform.invalidateCache();
+    expect(fs.existsSync(platform.getPersistedCachePath())).toBe(false);
+  });
+});
@@ -9,26 +9,37 @@ const fs = require('fs');
 jest.mock('fs', () => ({
   promises: {
     access: jest.fn(),
-    readFile: jest.fn()
+    readFile: jest.fn(),
+    readdir: jest.fn()
   }
 }));
 
 // Mock child_process
 jest.mock('child_process', () => ({
-  exec: jest.fn()
+  execFile: jest.fn()
 }));
 
-const { exec } = require('child_process');
+const { execFile } = require('child_process');
 
 // Import after mocking
 const {
   detect,
   invalidateCache,
   detectCI,
+  detectCIPipelines,
+  parseCIPipelines,
   detectDeployment,
+  detectDeployments,
+  detectCandidates,
   detectProjectType,
   detectPackageManager,
+  detectWorkspaces,
+  detectContainerization,
+  detectInfrastructure,
+  detectServerless,
+  parseTerraformProviders,
   detectBranchStrategy,
+  detectBranching,
   detectMainBranch
 } = require('../lib/platform/detect-platform');
 
@@ -39,6 +50,35 @@ describe('detect-platform', () => {
     fs.promises.access.mockRejectedValue(new Error('ENOENT'));
   });
 
+  /**
+   * Mock a file tree: keys are files (string content) or directories (null)
+   */
+  function mockTree(tree) {
+    const entries = Object.keys(tree);
+    fs.promises.access.mockImplementation((p) =>
+      entries.includes(p) ? Promise.resolve() : Promise.reject(new Error('ENOENT'))
+    );
+    fs.promises.readFile.mockImplementation((p) => {
+      const rel = path.relative(process.cwd(), p).split(path.sep).join('/');
+      return typeof tree[rel] === 'string' ? Promise.resolve(tree[rel]) : Promise.reject(new Error('ENOENT'));
+    });
+    fs.promises.readdir.mockImplementation((dir) => {
+      const prefix = dir === '.' ? '' : `${dir}/`;
+      const children = new Map();
+      for (const entry of entries) {
+        if (!entry.startsWith(prefix)) continue;
+        const rest = entry.slice(prefix.length);
+        const name = rest.split('/')[0];
+        children.set(name, children.get(name) || rest.includes('/'));
+      }
+      return Promise.resolve(Array.from(children).map(([name, isDir]) => ({
+        name,
+        isDirectory: () => isDir,
+        isFile: () => !isDir
+      })));
+    });
+  }
+
   describe('detectCI', () => {
     it('should detect github-actions when .github/workflows exists', async () => {
       fs.promises.access.mockImplementation((path) =>
@@ -138,6 +178,80 @@ describe('detect-platform', () => {
     });
   });
 
+  describe('additional CI platforms', () => {
+    it.each([
+      ['.buildkite', 'buildkite'],
+      ['azure-pipelines.yml', 'azure-pipelines'],
+      ['.drone.yml', 'drone'],
+      ['.woodpecker.yml', 'woodpecker'],
+      ['.woodpecker', 'woodpecker']
+    ])('should detect %s as %s', async (file, platform) => {
+      fs.promises.access.mockImplementation((p) =>
+        p === file ? Promise.resolve() : Promise.reject(new Error('ENOENT'))
+      );
+      expect(await detectCI()).toBe(platform);
+    });
+  });
+
+  describe('parseCIPipelines', () => {
+    it('should read Buildkite step labels', () => {
+      const content = 'steps:\n  - label: ":jest: Test"\n    command: npm test\n  - wait\n  - label: Deploy # prod\n';
+      expect(parseCIPipelines('buildkite', '.buildkite/pipeline.yml', content)).toEqual([
+        { platform: 'buildkite', file: '.buildkite/pipeline.yml', name: 'pipeline', jobs: [':jest: Test', 'Deploy'] }
+      ]);
+    });
+
+    it('should read Azure stages and jobs, ignoring run-number names', () => {
+      const content = 'name: $(Date:yyyyMMdd)$(Rev:.r)\nstages:\n- stage: Build\n  jobs:\n  - job: Compile\n- stage: Release\n';
+      const [pipeline] = parseCIPipelines('azure-pipelines', 'azure-pipelines.yml', content);
+      expect(pipeline.name).toBe('azure-pipelines');
+      expect(pipeline.jobs).toEqual(['Build', 'Release', 'Compile']);
+    });
+
+    it('should return one Drone pipeline per document', () => {
+      const content = [
+        'kind: pipeline', 'name: default', 'steps:', '- name: build', '- name: test',
+        '---', 'kind: secret', 'name: token',
+        '---', 'kind: pipeline', 'name: deploy', 'steps:', '  - name: ship', ''
+      ].join('\n');
+      expect(parseCIPipelines('drone', '.drone.yml', content).map(p => [p.name, p.jobs])).toEqual([
+        ['default', ['build', 'test']],
+        ['deploy', ['ship']]
+      ]);
+    });
+
+    it('should read Woodpecker steps in map and list form', () => {
+      expect(parseCIPipelines('woodpecker', '.woodpecker/test.yml', 'steps:\n  lint:\n    image: node\n  test:\n    image: node\n')[0])
+        .toEqual({ platform: 'woodpecker', file: '.woodpecker/test.yml', name: 'test', jobs: ['lint', 'test'] });
+      expect(parseCIPipelines('woodpecker', '.woodpecker.yml', 'steps:\n  - name: build\n    image: go\n')[0].jobs)
+        .toEqual(['build']);
+    });
+
+    it('should read GitHub Actions workflow names and jobs', () => {
+      const content = "name: 'CI'\non: push\njobs:\n  test:\n    runs-on: ubuntu-latest\n    steps: []\n  lint:\n    runs-on: ubuntu-latest\n";
+      expect(parseCIPipelines('github-actions', '.github/workflows/ci.yml', content)[0])
+        .toMatchObject({ name: 'CI', jobs: ['test', 'lint'] });
+    });
+  });
+
+  describe('detectCIPipelines', () => {
+    it('should collect pipelines from every CI platform present', async () => {
+      mockTree({
+        '.buildkite': null,
+        '.buildkite/pipeline.yml': 'steps:\n  - label: Build\n',
+        '.buildkite/release.yaml': 'steps:\n  - label: Publish\n',
+        '.buildkite/README.md': '',
+        '.drone.yml': 'kind: pipeline\nname: ci\nsteps:\n- name: test\n'
+      });
+
+      expect((await detectCIPipelines()).map(p => `${p.platform}:${p.name}:${p.jobs.join(',')}`)).toEqual([
+        'buildkite:pipeline:Build',
+        'buildkite:release:Publish',
+        'drone:ci:test'
+      ]);
+    });
+  });
+
   describe('detectDeployment', () => {
     it('should detect railway when railway.json exists', async () => {
       fs.promises.access.mockImplementation((path) =>
@@ -181,6 +295,124 @@ describe('detect-platform', () => {
     });
   });
 
+  describe('detectDeployments', () => {
+    it('should tell Cloudflare Pages from Workers by wrangler config', async () => {
+      mockTree({ 'wrangler.toml': 'name = "site"\npages_build_output_dir = "./dist"\n' });
+      expect(await detectDeployments()).toEqual(['cloudflare-pages']);
+
+      invalidateCache();
+      mockTree({ 'wrangler.toml': 'name = "api"\nmain = "src/index.ts"\n' });
+      expect(await detectDeployments()).toEqual(['cloudflare-workers']);
+    });
+
+    it('should detect Heroku from Procfile or a Heroku app.json', async () => {
+      mockTree({ 'Procfile': 'web: npm start' });
+      expect(await detectDeployments()).toEqual(['heroku']);
+
+      invalidateCache();
+      mockTree({ 'app.json': '{"name":"x","buildpacks":[{"url":"heroku/nodejs"}]}' });
+      expect(await detectDeployments()).toEqual(['heroku']);
+
+      invalidateCache();
+      mockTree({ 'app.json': '{"expo":{"name":"mobile"}}' });
+      expect(await detectDeployments()).toEqual([]);
+    });
+
+    it('should detect AWS Amplify and Deno Deploy', async () => {
+      mockTree({
+        'amplify.yml': 'version: 1',
+        'deno.json': '{"deploy":{"project":"site","entrypoint":"main.ts"}}'
+      });
+      expect(await detectDeployments()).toEqual(['aws-amplify', 'deno-deploy']);
+    });
+
+    it('should ignore deno.json without deploy config', async () => {
+      mockTree({ 'deno.json': '{"tasks":{"dev":"deno run main.ts"}}' });
+      expect(await detectDeployments()).toEqual([]);
+      expect(await detectDeployment()).toBeNull();
+    });
+
+    it('should list every platform and keep the first as deployment', async () => {
+      execFile.mockImplementation((file, args, opts, cb) => {
+        cb(null, 'refs/remotes/origin/main\n');
+      });
+      mockTree({ 'vercel.json': '{}', 'wrangler.toml': 'name = "edge"', 'Procfile': 'web: node server.js' });
+
+      const result = await detect();
+      expect(result.deployments).toEqual(['vercel', 'cloudflare-workers', 'heroku']);
+      expect(result.deployment).toBe('vercel');
+    });
+  });
+
+  describe('detectCandidates', () => {
+    it('should rank conflicting deployment configs with evidence', async () => {
+      mockTree({
+        'vercel.json': '{}',
+        'netlify.toml': '[build]',
+        '.github/workflows': null,
+        '.github/workflows/deploy.yml': 'name: Deploy\njobs:\n  ship:\n    steps:\n      - run: npx netlify deploy --prod\n'
+      });
+
+      const { candidates, ambiguous } = await detectCandidates();
+      expect(candidates.deployment).toEqual([
+        {
+          value: 'netlify',
+          confidence: 0.95,
+          evidence: [
+            { source: 'netlify.toml', weight: 0.9 },
+            { source: '.github/workflows/deploy.yml#deploy-command', weight: 0.5 }
+          ]
+        },
+        { value: 'vercel', confidence: 0.9, evidence: [{ source: 'vercel.json', weight: 0.9 }] }
+      ]);
+      expect(candidates.ci).toEqual([
+        { value: 'github-actions', confidence: 0.9, evidence: [{ source: '.github/workflows/deploy.yml', weight: 0.9 }] }
+      ]);
+      expect(ambiguous).toEqual(['deployment']);
+    });
+
+    it('should weigh shared and legacy markers lower', async () => {
+      mockTree({ 'fly.toml': 'app = "api"', 'Procfile': 'web: ./server' });
+      const { candidates, ambiguous } = await detectCandidates();
+      expect(candidates.deployment.map(c => [c.value, c.confidence])).toEqual([['fly', 0.9], ['heroku', 0.5]]);
+      expect(ambiguous).toEqual(['deployment']);
+
+      invalidateCache();
+      mockTree({ 'railway.toml': '', 'go.mod': 'module app' });
+      const result = await detectCandidates();
+      expect(result.candidates.deployment.map(c => [c.value, c.confidence])).toEqual([['railway', 0.6]]);
+      expect(result.candidates.projectType.map(c => c.value)).toEqual(['go']);
+      expect(result.ambiguous).toEqual([]);
+    });
+
+    it('should combine lockfiles with the packageManager field', async () => {
+      mockTree({
+        'package.json': '{"packageManager":"pnpm@9.1.0"}',
+        'pnpm-lock.yaml': '',
+        'package-lock.json': '{}',
+        'pyproject.toml': '[project]'
+      });
+
+      const { candidates, ambiguous } = await detectCandidates();
+      expect(candidates.packageManager.map(c => [c.value, c.confidence])).toEqual([['pnpm', 0.99], ['npm', 0.9]]);
+      expect(candidates.packageManager[0].evidence.map(e => e.source)).toEqual(['pnpm-lock.yaml', 'package.json#packageManager']);
+      expect(candidates.projectType.map(c => [c.value, c.confidence])).toEqual([['python', 0.9], ['nodejs', 0.8]]);
+      expect(ambiguous).toEqual(['projectType', 'packageManager']);
+    });
+
+    it('should be included in detect() without changing first-match fields', async () => {
+      execFile.mockImplementation((file, args, opts, cb) => {
+        cb(null, 'refs/remotes/origin/main\n');
+      });
+      mockTree({ 'package.json': '{}', 'pyproject.toml': '[project]' });
+
+      const result = await detect();
+      expect(result.projectType).toBe('nodejs');
+      expect(result.candidates.projectType[0].value).toBe('python');
+      expect(result.ambiguous).toEqual(['projectType']);
+    });
+  });
+
   describe('detectProjectType', () => {
     it('should detect nodejs when package.json exists', async () => {
       fs.promises.access.mockImplementation((path) =>
@@ -285,11 +517,186 @@ describe('detect-platform', () => {
     });
   });
 
+  describe('detectWorkspaces', () => {
+    it('should return null for single-package repos', async () => {
+      mockTree({ 'package.json': '{"name":"app"}', 'package-lock.json': '{}' });
+      expect(await detectWorkspaces()).toBeNull();
+    });
+
+    it('should expand pnpm workspaces with per-package project types', async () => {
+      mockTree({
+        'package.json': '{"name":"root"}',
+        'pnpm-lock.yaml': '',
+        'pnpm-workspace.yaml': "packages:\n  - 'apps/*'\n  - \"services/**\" # nested\n  - '!apps/legacy'\n",
+        'apps/web/package.json': '{"name":"@acme/web"}',
+        'apps/legacy/package.json': '{"name":"legacy"}',
+        'services/api/pyproject.toml': '',
+        'services/api/poetry.lock': ''
+      });
+
+      expect(await detectWorkspaces()).toEqual({
+        tools: ['pnpm'],
+        packageManager: 'pnpm',
+        packages: [
+          { name: '@acme/web', path: 'apps/web', projectType: 'nodejs', packageManager: 'pnpm' },
+          { name: 'api', path: 'services/api', projectType: 'python', packageManager: 'poetry' }
+        ]
+      });
+    });
+
+    it('should read yarn workspaces and note Turborepo', async () => {
+      mockTree({
+        'package.json': '{"name":"root","workspaces":{"packages":["packages/*"]}}',
+        'yarn.lock': '',
+        'turbo.json': '{}',
+        'packages/ui/package.json': '{"name":"ui"}',
+        'packages/docs/README.md': ''
+      });
+
+      const result = await detectWorkspaces();
+      expect(result.tools).toEqual(['yarn', 'turborepo']);
+      expect(result.packages).toEqual([{ name: 'ui', path: 'packages/ui', projectType: 'nodejs', packageManager: 'yarn' }]);
+    });
+
+    it('should use Lerna and Nx defaults', async () => {
+      mockTree({
+        'lerna.json': '{"npmClient":"npm"}',
+        'nx.json': '{}',
+        'packages/core/package.json': '{"name":"core"}',
+        'libs/shared/project.json': '{}'
+      });
+
+      const result = await detectWorkspaces();
+      expect(result.tools).toEqual(['lerna', 'nx']);
+      expect(result.packageManager).toBe('npm');
+      expect(result.packages.map(p => [p.path, p.projectType])).toEqual([
+        ['libs/shared', 'unknown'],
+        ['packages/core', 'nodejs']
+      ]);
+    });
+
+    it('should be included in detect()', async () => {
+      execFile.mockImplementation((file, args, opts, cb) => {
+        cb(null, 'refs/remotes/origin/main\n');
+      });
+      mockTree({ 'package.json': '{"workspaces":["pkgs/*"]}', 'pkgs/a/package.json': '{"name":"a"}' });
+
+      const result = await detect();
+      expect(result.monorepo.tools).toEqual(['npm']);
+      expect(result.monorepo.packages[0].name).toBe('a');
+    });
+  });
+
+  describe('detectContainerization', () => {
+    it('should return null without container or Kubernetes files', async () => {
+      mockTree({ 'package.json': '{}', 'src/index.js': '' });
+      expect(await detectContainerization()).toBeNull();
+    });
+
+    it('should detect Dockerfiles and Compose files', async () => {
+      mockTree({
+        'Dockerfile': 'FROM node:20',
+        'services/api/Dockerfile.dev': 'FROM node:20',
+        'docker/worker.dockerfile': 'FROM python:3',
+        'docker-compose.yml': 'services: {}',
+        'compose.override.yaml': 'services: {}',
+        'node_modules/pkg/Dockerfile': 'FROM scratch'
+      });
+
+      expect(await detectContainerization()).toEqual({
+        dockerfiles: ['Dockerfile', 'docker/worker.dockerfile', 'services/api/Dockerfile.dev'],
+        compose: ['compose.override.yaml', 'docker-compose.yml'],
+        helmCharts: [],
+        kustomizations: [],
+        manifests: [],
+        orchestrator: 'compose'
+      });
+    });
+
+    it('should detect Helm charts, Kustomize overlays, and manifests', async () => {
+      mockTree({
+        'charts/app/Chart.yaml': 'name: app',
+        'charts/app/templates/deployment.yaml': 'apiVersion: apps/v1\nkind: Deployment\n',
+        'k8s/base/kustomization.yaml': 'resources: []',
+        'k8s/base/service.yaml': 'apiVersion: v1\nkind: Service\n',
+        'k8s/overlays/prod/kustomization.yaml': 'resources: []',
+        'deploy/values.yaml': 'replicas: 2'
+      });
+
+      const result = await detectContainerization();
+      expect(result.helmCharts).toEqual(['charts/app']);
+      expect(result.kustomizations).toEqual(['k8s/base', 'k8s/overlays/prod']);
+      expect(result.manifests).toEqual(['k8s/base/service.yaml']);
+      expect(result.orchestrator).toBe('kubernetes');
+    });
+  });
+
+  describe('detectInfrastructure', () => {
+    it('should return null without IaC files', async () => {
+      mockTree({ 'package.json': '{}', 'config/app.yaml': 'a: 1' });
+      expect(await detectInfrastructure()).toBeNull();
+    });
+
+    it('should read Terraform providers from blocks, sources, and resources', () => {
+      const tf = [
+        'terraform {',
+        '  required_providers {',
+        '    google = { source = "hashicorp/google" }',
+        '  }',
+        '}',
+        'provider "aws" {}',
+        'resource "azurerm_resource_group" "rg" {}',
+        'resource "random_id" "x" {}'
+      ].join('\n');
+      expect(parseTerraformProviders(tf)).toEqual(['aws', 'google', 'azurerm', 'random']);
+    });
+
+    it('should group Terraform files by module directory', async () => {
+      mockTree({
+        'infra': null,
+        'infra/main.tf': 'provider "aws" { region = "us-east-1" }\nmodule "vpc" { source = "./modules/vpc" }',
+        'infra/variables.tf': 'variable "region" {}',
+        'infra/modules': null,
+        'infra/modules/vpc': null,
+        'infra/modules/vpc/main.tf': 'resource "aws_vpc" "main" {}\nresource "random_id" "suffix" {}'
+      });
+
+      expect(await detectInfrastructure()).toEqual({
+        tools: ['terraform'],
+        providers: ['aws'],
+        projects: [
+          { tool: 'terraform', path: 'infra', providers: ['aws'], files: ['infra/main.tf', 'infra/variables.tf'] },
+          { tool: 'terraform', path: 'infra/modules/vpc', providers: ['aws'], files: ['infra/modules/vpc/main.tf'] }
+        ]
+      });
+    });
+
+    it('should detect Pulumi, CDK, and CloudFormation projects', async () => {
+      mockTree({
+        'Pulumi.yaml': 'name: site\nruntime: nodejs\n',
+        'package.json': '{"dependencies":{"@pulumi/pulumi":"^3","@pulumi/gcp":"^7"}}',
+        'cdk': null,
+        'cdk/cdk.json': '{"app":"npx ts-node bin/app.ts"}',
+        'template.yaml': 'AWSTemplateFormatVersion: "2010-09-09"\nTransform: AWS::Serverless-2016-10-31\n',
+        'templates': null,
+        'templates/email.yaml': 'subject: Hello'
+      });
+
+      const result = await detectInfrastructure();
+      expect(result.tools).toEqual(['pulumi', 'cdk', 'cloudformation']);
+      expect(result.providers).toEqual(['aws', 'gcp']);
+      expect(result.projects).toEqual([
+        { tool: 'pulumi', path: '.', runtime: 'nodejs', providers: ['gcp'], files: ['Pulumi.yaml'] },
+        { tool: 'cdk', path: 'cdk', providers: ['aws'], files: ['cdk/cdk.json'] },
+        { tool: 'cloudformation', path: '.', providers: ['aws'], files: ['template.yaml'] }
+      ]);
+    });
+  });
+
   describe('detectMainBranch', () => {
     it('should return main branch from git symbolic-ref', async () => {
-      exec.mockImplementation((cmd, opts, cb) => {
-        if (typeof opts === 'function') cb = opts;
-        cb(null, { stdout: 'refs/remotes/origin/main\n', stderr: '' });
+      execFile.mockImplementation((file, args, opts, cb) => {
+        cb(null, 'refs/remotes/origin/main\n');
       });
 
       expect(await detectMainBranch()).toBe('main');
@@ -297,13 +704,12 @@ describe('detect-platform', () => {
 
     it('should fallback to main if symbolic-ref fails but main exists', async () => {
       let callCount = 0;
-      exec.mockImplementation((cmd, opts, cb) => {
-        if (typeof opts === 'function') cb = opts;
+      execFile.mockImplementation((file, args, opts, cb) => {
         callCount++;
         if (callCount === 1) {
-          cb(new Error('not found'), { stdout: '', stderr: '' });
+          cb(new Error('not found'), '');
         } else {
-          cb(null, { stdout: 'abc123', stderr: '' });
+          cb(null, 'abc123');
         }
       });
 
@@ -311,9 +717,8 @@ describe('detect-platform', () => {
     });
 
     it('should fallback to master if main does not exist', async () => {
-      exec.mockImplementation((cmd, opts, cb) => {
-        if (typeof opts === 'function') cb = opts;
-        cb(new Error('not found'), { stdout: '', stderr: '' });
+      execFile.mockImplementation((file, args, opts, cb) => {
+        cb(new Error('not found'), '');
       });
 
       expect(await detectMainBranch()).toBe('master');
@@ -327,9 +732,8 @@ describe('detect-platform', () => {
           ? Promise.resolve()
           : Promise.reject(new Error('ENOENT'))
       );
-      exec.mockImplementation((cmd, opts, cb) => {
-        if (typeof opts === 'function') cb = opts;
-        cb(null, { stdout: 'refs/remotes/origin/main\n', stderr: '' });
+      execFile.mockImplementation((file, args, opts, cb) => {
+        cb(null, 'refs/remotes/origin/main\n');
       });
 
       const result1 = await detect();
@@ -341,9 +745,8 @@ describe('detect-platform', () => {
 
     it('should refresh cache when forceRefresh is true', async () => {
       fs.promises.access.mockRejectedValue(new Error('ENOENT'));
-      exec.mockImplementation((cmd, opts, cb) => {
-        if (typeof opts === 'function') cb = opts;
-        cb(null, { stdout: 'refs/remotes/origin/main\n', stderr: '' });
+      execFile.mockImplementation((file, args, opts, cb) => {
+        cb(null, 'refs/remotes/origin/main\n');
       });
 
       const result1 = await detect();
@@ -361,9 +764,8 @@ describe('detect-platform', () => {
     });
 
     it('should include timestamp in result', async () => {
-      exec.mockImplementation((cmd, opts, cb) => {
-        if (typeof opts === 'function') cb = opts;
-        cb(null, { stdout: 'refs/remotes/origin/main\n', stderr: '' });
+      execFile.mockImplementation((file, args, opts, cb) => {
+        cb(null, 'refs/remotes/origin/main\n');
       });
       const result = await detect();
       expect(result.timestamp).toBeDefined();
@@ -372,9 +774,8 @@ describe('detect-platform', () => {
 
     it('should handle all detection functions failing gracefully', async () => {
       fs.promises.access.mockRejectedValue(new Error('ENOENT'));
-      exec.mockImplementation((cmd, opts, cb) => {
-        if (typeof opts === 'function') cb = opts;
-        cb(new Error('git error'), { stdout: '', stderr: '' });
+      execFile.mockImplementation((file, args, opts, cb) => {
+        cb(new Error('git error'), '');
       });
 
       const result = await detect();
@@ -391,9 +792,8 @@ describe('detect-platform', () => {
   describe('invalidateCache', () => {
     it('should force new detection on next call', async () => {
       fs.promises.access.mockRejectedValue(new Error('ENOENT'));
-      exec.mockImplementation((cmd, opts, cb) => {
-        if (typeof opts === 'function') cb = opts;
-        cb(null, { stdout: 'refs/remotes/origin/main\n', stderr: '' });
+      execFile.mockImplementation((file, args, opts, cb) => {
+        cb(null, 'refs/remotes/origin/main\n');
       });
 
       await detect();
@@ -418,9 +818,8 @@ describe('detect-platform', () => {
             ? Promise.resolve()
             : Promise.reject(new Error('ENOENT'))
         );
-        exec.mockImplementation((cmd, opts, cb) => {
-          if (typeof opts === 'function') cb = opts;
-          cb(null, { stdout: 'refs/remotes/origin/main\n', stderr: '' });
+        execFile.mockImplementation((file, args, opts, cb) => {
+          cb(null, 'refs/remotes/origin/main\n');
         });
 
         const result1 = await detect();
@@ -435,9 +834,8 @@ describe('detect-platform', () => {
             ? Promise.resolve()
             : Promise.reject(new Error('ENOENT'))
         );
-        exec.mockImplementation((cmd, opts, cb) => {
-          if (typeof opts === 'function') cb = opts;
-          cb(null, { stdout: 'refs/remotes/origin/main\n', stderr: '' });
+        execFile.mockImplementation((file, args, opts, cb) => {
+          cb(null, 'refs/remotes/origin/main\n');
         });
 
         const result1 = await detect();
@@ -461,9 +859,8 @@ describe('detect-platform', () => {
             ? Promise.resolve()
             : Promise.reject(new Error('ENOENT'))
         );
-        exec.mockImplementation((cmd, opts, cb) => {
-          if (typeof opts === 'function') cb = opts;
-          cb(null, { stdout: 'refs/remotes/origin/main\n', stderr: '' });
+        execFile.mockImplementation((file, args, opts, cb) => {
+          cb(null, 'refs/remotes/origin/main\n');
         });
 
         const result1 = await detect();
@@ -549,20 +946,111 @@ describe('detect-platform', () => {
     });
   });
 
+  describe('detectServerless', () => {
+    it('should return null without serverless configs', async () => {
+      mockTree({ 'package.json': '{"dependencies":{"express":"^4"}}', 'index.js': 'module.exports = {}' });
+      expect(await detectServerless()).toBeNull();
+    });
+
+    it('should list Serverless Framework functions with provider and per-function runtimes', async () => {
+      mockTree({
+        'serverless.yml': [
+          'service: api',
+          'provider:',
+          '  name: aws',
+          '  runtime: nodejs20.x # default',
+          'functions:',
+          '  hello:',
+          '    handler: src/handler.hello',
+          '    events:',
+          '      - httpApi:',
+          '          path: /hello',
+          '  report:',
+          '    handler: report.main',
+          '    runtime: python3.12'
+        ].join('\n')
+      });
+
+      const result = await detectServerless();
+      expect(result.frameworks).toEqual(['serverless']);
+      expect(result.functions).toEqual([
+        {
+          name: 'hello', framework: 'serverless', provider: 'aws', runtime: 'nodejs20.x', handler: 'src/handler.hello',
+          path: '.', file: 'serverless.yml', deployCommand: 'serverless deploy function --function hello'
+        },
+        {
+          name: 'report', framework: 'serverless', provider: 'aws', runtime: 'python3.12', handler: 'report.main',
+          path: '.', file: 'serverless.yml', deployCommand: 'serverless deploy function --function report'
+        }
+      ]);
+    });
+
+    it('should read SAM functions and Globals runtimes', async () => {
+      mockTree({
+        'template.yaml': [
+          'AWSTemplateFormatVersion: "2010-09-09"',
+          'Transform: AWS::Serverless-2016-10-31',
+          'Globals:',
+          '  Function:',
+          '    Runtime: python3.11',
+          'Resources:',
+          '  ApiFunction:',
+          '    Type: AWS::Serverless::Function',
+          '    Properties:',
+          '      Handler: app.lambda_handler',
+          '      CodeUri: api/',
+          '  WorkerFunction:',
+          '    Type: AWS::Serverless::Function',
+          '    Properties:',
+          '      Runtime: nodejs20.x',
+          '      Handler: worker.handler',
+          '  Queue:',
+          '    Type: AWS::SQS::Queue'
+        ].join('\n')
+      });
+
+      const result = await detectServerless();
+      expect(result.frameworks).toEqual(['sam']);
+      expect(result.functions.map(fn => [fn.name, fn.runtime, fn.handler, fn.deployCommand])).toEqual([
+        ['ApiFunction', 'python3.11', 'app.lambda_handler', 'sam sync --code --resource-id ApiFunction'],
+        ['WorkerFunction', 'nodejs20.x', 'worker.handler', 'sam sync --code --resource-id WorkerFunction']
+      ]);
+    });
+
+    it('should detect Google Cloud Functions and Firebase Functions entry points', async () => {
+      mockTree({
+        'fn': null,
+        'fn/package.json': JSON.stringify({ engines: { node: '>=20' }, dependencies: { '@google-cloud/functions-framework': '^3' } }),
+        'fn/index.js': "const functions = require('@google-cloud/functions-framework');\nfunctions.http('helloHttp', (req, res) => res.send('ok'));\nfunctions.cloudEvent('onUpload', () => {});\n",
+        'firebase.json': JSON.stringify({ functions: { source: 'functions', runtime: 'nodejs18' } }),
+        'functions': null,
+        'functions/index.js': "exports.api = functions.https.onRequest(app);\nexport const nightly = onSchedule('every day', run);\n"
+      });
+
+      const result = await detectServerless();
+      expect(result.frameworks).toEqual(['gcp-functions', 'firebase-functions']);
+      expect(result.files).toEqual(['fn/index.js', 'firebase.json']);
+      expect(result.functions.map(fn => [fn.framework, fn.name, fn.runtime, fn.deployCommand])).toEqual([
+        ['gcp-functions', 'helloHttp', 'nodejs20', 'gcloud functions deploy helloHttp --gen2 --runtime nodejs20 --entry-point helloHttp --source fn --trigger-http'],
+        ['gcp-functions', 'onUpload', 'nodejs20', 'gcloud functions deploy onUpload --gen2 --runtime nodejs20 --entry-point onUpload --source fn'],
+        ['firebase-functions', 'api', 'nodejs18', 'firebase deploy --only functions:api'],
+        ['firebase-functions', 'nightly', 'nodejs18', 'firebase deploy --only functions:nightly']
+      ]);
+    });
+  });
+
   describe('detectBranchStrategy', () => {
     it('should return single-branch when git commands fail', async () => {
-      exec.mockImplementation((cmd, opts, cb) => {
-        if (typeof opts === 'function') cb = opts;
-        cb(new Error('git error'), { stdout: '', stderr: '' });
+      execFile.mockImplementation((file, args, opts, cb) => {
+        cb(new Error('git error'), '');
       });
 
       expect(await detectBranchStrategy()).toBe('single-branch');
     });
 
     it('should return multi-branch when stable branch exists', async () => {
-      exec.mockImplementation((cmd, opts, cb) => {
-        if (typeof opts === 'function') cb = opts;
-        cb(null, { stdout: '* main\n  stable\n', stderr: '' });
+      execFile.mockImplementation((file, args, opts, cb) => {
+        cb(null, '* main\n  stable\n');
       });
       fs.promises.access.mockRejectedValue(new Error('ENOENT'));
 
@@ -570,9 +1058,8 @@ describe('detect-platform', () => {
     });
 
     it('should return single-branch when no stable/production branches', async () => {
-      exec.mockImplementation((cmd, opts, cb) => {
-        if (typeof opts === 'function') cb = opts;
-        cb(null, { stdout: '* main\n  feature/test\n', stderr: '' });
+      execFile.mockImplementation((file, args, opts, cb) => {
+        cb(null, '* main\n  feature/test\n');
       });
       fs.promises.access.mockRejectedValue(new Error('ENOENT'));
 
@@ -580,6 +1067,93 @@ describe('detect-platform', () => {
     });
   });
 
+  describe('detectBranching', () => {
+    function mockGit({ branches = '', remotes = '', tags = '' }) {
+      execFile.mockImplementation((file, args, opts, cb) => {
+        const cmd = [file, ...args].join(' ');
+        const stdout = cmd === 'git branch' ? branches : cmd === 'git branch -r' ? remotes : cmd.startsWith('git tag') ? tags : '';
+        cb(null, stdout);
+      });
+    }
+
+    it('should detect GitFlow from develop and release branches', async () => {
+      mockGit({ branches: '* develop\n  main\n', remotes: '  origin/HEAD -> origin/develop\n  origin/release/2.1\n  origin/hotfix/login\n' });
+      mockTree({});
+
+      const result = await detectBranching();
+      expect(result).toMatchObject({
+        model: 'gitflow',
+        strategy: 'multi-branch',
+        developBranch: 'develop',
+        productionBranch: 'main',
+        releaseBranches: ['release/2.1']
+      });
+    });
+
+    it('should detect release-branch production with version tags', async () => {
+      mockGit({ branches: '* main\n', remotes: '  origin/main\n  origin/release/1.0\n  origin/release/1.1\n  origin/product-page\n', tags: 'v1.1.0\nv1.0.0\nnightly\n' });
+      mockTree({});
+
+      const result = await detectBranching();
+      expect(result).toMatchObject({
+        model: 'release-branch',
+        strategy: 'single-branch',
+        productionBranch: null,
+        releaseBranches: ['release/1.0', 'release/1.1'],
+        latestTag: 'v1.1.0'
+      });
+    });
+
+    it('should detect tag-based deploys and trunk-based environments from CI', async () => {
+      mockGit({ branches: '* main\n' });
+      mockTree({
+        '.github/workflows': null,
+        '.github/workflows/release.yml': 'on:\n  push:\n    tags:\n      - "v*"\njobs:\n  deploy:\n    environment: production\n    steps: []\n'
+      });
+      expect(await detectBranching()).toMatchObject({ model: 'tag-based', tagTriggers: ['.github/workflows/release.yml'] });
+
+      invalidateCache();
+      mockTree({
+        '.github/workflows': null,
+        '.github/workflows/deploy.yml': 'on:\n  push:\n    branches: [main]\njobs:\n  staging:\n    environment:\n      name: staging\n  prod:\n    environment: production\n'
+      });
+      expect(await detectBranching()).toMatchObject({ model: 'trunk-based', strategy: 'single-branch', environments: ['staging', 'production'] });
+    });
+
+    it('should apply overrides from the project config', async () => {
+      mockGit({ branches: '* main\n  stable\n' });
+      mockTree({
+        '.awesome-slash.json': JSON.stringify({ branchStrategy: { model: 'release-branch', releaseBranches: 'release/*', releaseTags: 'v*', mainBranch: 'main' } })
+      });
+
+      const result = await detectBranching();
+      expect(result).toMatchObject({
+        model: 'release-branch',
+        strategy: 'single-branch',
+        source: '.awesome-slash.json',
+        mainBranch: 'main',
+        productionBranch: null,
+        releaseBranches: ['release/*'],
+        releaseTags: 'v*',
+        configError: null
+      });
+    });
+
+    it('should report invalid overrides and fall back to detection', async () => {
+      mockGit({ branches: '* main\n  stable\n' });
+      mockTree({ '.awesome-slash.json': JSON.stringify({ branchStrategy: 'git-flow' }) });
+
+      const result = await detectBranching();
+      expect(result.model).toBe('production-branch');
+      expect(result.source).toBe('detected');
+      expect(result.configError).toContain('unknown branchStrategy.model "git-flow"');
+
+      invalidateCache();
+      mockTree({ '.awesome-slash.json': JSON.stringify({ branchStrategy: 'single-branch' }) });
+      expect(await detectBranchStrategy()).toBe('single-branch');
+    });
+  });
+
   describe('error handling edge cases', () => {
     it('should handle mixed success/failure in parallel async operations', async () => {
       let callCount = 0;
@@ -589,9 +1163,8 @@ describe('detect-platform', () => {
         if (callCount % 3 === 0) return Promise.reject(new Error('EPERM'));
         return Promise.reject(new Error('ENOENT'));
       });
-      exec.mockImplementation((cmd, opts, cb) => {
-        if (typeof opts === 'function') cb = opts;
-        cb(null, { stdout: 'refs/remotes/origin/main\n', stderr: '' });
+      execFile.mockImplementation((file, args, opts, cb) => {
+        cb(null, 'refs/remotes/origin/main\n');
       });
 
       const result = await detect();
@@ -604,9 +1177,8 @@ describe('detect-platform', () => {
           setTimeout(() => reject(new Error('ENOENT')), 10);
         })
       );
-      exec.mockImplementation((cmd, opts, cb) => {
-        if (typeof opts === 'function') cb = opts;
-        setTimeout(() => cb(null, { stdout: 'main\n', stderr: '' }), 10);
+      execFile.mockImplementation((file, args, opts, cb) => {
+        setTimeout(() => cb(null, 'main\n'), 10);
       });
 
       const result = await detect();
@@ -614,9 +1186,8 @@ describe('detect-platform', () => {
     }, 10000);
 
     it('should recover from file read errors in branch strategy detection', async () => {
-      exec.mockImplementation((cmd, opts, cb) => {
-        if (typeof opts === 'function') cb = opts;
-        cb(null, { stdout: '* main\n', stderr: '' });
+      execFile.mockImplementation((file, args, opts, cb) => {
+        cb(null, '* main\n');
       });
       fs.promises.access.mockImplementation((path) =>
         path === 'railway.json'
@@ -630,9 +1201,8 @@ describe('detect-platform', () => {
     });
 
     it('should handle JSON parse errors in railway.json gracefully', async () => {
-      exec.mockImplementation((cmd, opts, cb) => {
-        if (typeof opts === 'function') cb = opts;
-        cb(null, { stdout: '* main\n', stderr: '' });
+      execFile.mockImplementation((file, args, opts, cb) => {
+        cb(null, '* main\n');
       });
       fs.promises.access.mockImplementation((path) =>
         path === 'railway.json'
@@ -0,0 +1,113 @@
+/**
+ * Tests for detect-runtimes.js
+ */
+
+const fs = require('fs');
+const os = require('os');
+const path = require('path');
+
+const { detectRuntimes, lowerBound, compareVersions } = require('../lib/platform/detect-runtimes');
+
+describe('detect-runtimes', () => {
+  let root;
+
+  function write(files) {
+    for (const [file, content] of Object.entries(files)) {
+      const fullPath = path.join(root, file);
+      fs.mkdirSync(path.dirname(fullPath), { recursive: true });
+      fs.writeFileSync(fullPath, content);
+    }
+  }
+
+  function runtime(name) {
+    return detectRuntimes(root).runtimes.find(r => r.name === name);
+  }
+
+  beforeEach(() => {
+    root = fs.mkdtempSync(path.join(os.tmpdir(), 'detect-runtimes-'));
+  });
+
+  afterEach(() => {
+    fs.rmSync(root, { recursive: true, force: true });
+  });
+
+  it('should return null when no runtime is declared', () => {
+    write({ 'README.md': '# app' });
+    expect(detectRuntimes(root)).toBeNull();
+  });
+
+  describe('lowerBound', () => {
+    it('should handle npm ranges and PEP 440 specifiers', () => {
+      expect(lowerBound('>=18.0.0')).toBe('18.0.0');
+      expect(lowerBound('^18.17 || >=20')).toBe('18.17');
+      expect(lowerBound('18.x')).toBe('18');
+      expect(lowerBound('>=3.9,<4')).toBe('3.9');
+      expect(lowerBound('~=3.10')).toBe('3.10');
+      expect(lowerBound('>=3.8, !=3.9.0')).toBe('3.8');
+      expect(lowerBound('<20')).toBeNull();
+    });
+
+    it('should compare versions numerically', () => {
+      expect(compareVersions('20.11', '20.9')).toBeGreaterThan(0);
+      expect(compareVersions('v18', '18.0.0')).toBe(0);
+      expect(compareVersions('go1.21', '1.22')).toBeLessThan(0);
+    });
+  });
+
+  it('should read Node versions from .nvmrc and package.json engines', () => {
+    write({
+      '.nvmrc': 'v20.11.1\n',
+      'package.json': JSON.stringify({ engines: { node: '>=18.17' } })
+    });
+
+    expect(runtime('node')).toEqual({
+      name: 'node',
+      version: '20.11.1',
+      minimum: '18.17',
+      minimumSource: 'package.json#engines.node',
+      sources: [
+        { file: '.nvmrc', value: 'v20.11.1' },
+        { file: 'package.json#engines.node', value: '>=18.17' }
+      ]
+    });
+  });
+
+  it('should resolve lts codenames and ignore unresolvable aliases', () => {
+    write({ '.nvmrc': 'lts/hydrogen\n' });
+    expect(runtime('node')).toMatchObject({ version: '18', minimum: '18' });
+
+    write({ '.nvmrc': 'node\n' });
+    expect(runtime('node')).toMatchObject({ version: null, minimum: null, sources: [{ file: '.nvmrc', value: 'node' }] });
+  });
+
+  it('should read the go directive and toolchain from go.mod', () => {
+    write({ 'go.mod': 'module example.com/app\n\ngo 1.21\n\ntoolchain go1.22.3\n' });
+    expect(runtime('go')).toMatchObject({ version: '1.22.3', minimum: '1.21', minimumSource: 'go.mod#go' });
+  });
+
+  it('should read requires-python and .python-version', () => {
+    write({
+      'pyproject.toml': '[project]\nname = "app"\nrequires-python = ">=3.9,<4"\n\n[tool.ruff]\ntarget-version = "py311"\n',
+      '.python-version': '3.12.1\n'
+    });
+    expect(runtime('python')).toMatchObject({ version: '3.12.1', minimum: '3.9' });
+  });
+
+  it('should read rust-toolchain.toml and Cargo.toml rust-version', () => {
+    write({
+      'rust-toolchain.toml': '[toolchain]\nchannel = "1.79.0"\ncomponents = ["clippy"]\n',
+      'Cargo.toml': '[package]\nname = "app"\nrust-version = "1.70"\n'
+    });
+    expect(runtime('rust')).toMatchObject({ version: '1.79.0', minimum: '1.70', minimumSource: 'Cargo.toml#package.rust-version' });
+  });
+
+  it('should read .tool-versions entries', () => {
+    write({ '.tool-versions': 'nodejs 22.2.0\npython 3.11.4 # pinned\nterraform 1.8.0\n' });
+
+    const result = detectRuntimes(root);
+    expect(result.runtimes.map(r => [r.name, r.version])).toEqual([
+      ['node', '22.2.0'],
+      ['python', '3.11.4']
+    ]);
+  });
+});
@@ -0,0 +1,109 @@
+/**
+ * Tests for detect-secrets.js
+ */
+
+const fs = require('fs');
+const os = require('os');
+const path = require('path');
+const { execFileSync } = require('child_process');
+
+const { detectSecrets } = require('../lib/platform/detect-secrets');
+
+describe('detect-secrets', () => {
+  let root;
+
+  function write(files) {
+    for (const [file, content] of Object.entries(files)) {
+      const fullPath = path.join(root, file);
+      fs.mkdirSync(path.dirname(fullPath), { recursive: true });
+      fs.writeFileSync(fullPath, content);
+    }
+  }
+
+  function git(...args) {
+    execFileSync('git', args, { cwd: root, stdio: 'ignore' });
+  }
+
+  beforeEach(() => {
+    root = fs.mkdtempSync(path.join(os.tmpdir(), 'detect-secrets-'));
+  });
+
+  afterEach(() => {
+    fs.rmSync(root, { recursive: true, force: true });
+  });
+
+  it('should return null without env files or secrets tooling', async () => {
+    write({ 'package.json': '{"name":"app"}' });
+    expect(await detectSecrets(root)).toBeNull();
+  });
+
+  it('should list env files and flag secret files git would commit', async () => {
+    write({
+      '.env': 'API_KEY=abc',
+      '.env.example': 'API_KEY=',
+      'apps/web/.env.local': 'TOKEN=1',
+      '.gitignore': '.env\n'
+    });
+    git('init', '-q');
+    git('add', '.gitignore', '.env.example', 'apps/web/.env.local');
+
+    const result = await detectSecrets(root);
+    expect(result.envFiles).toEqual([
+      { file: '.env', template: false, tracked: false, ignored: true },
+      { file: '.env.example', template: true, tracked: true, ignored: false },
+      { file: 'apps/web/.env.local', template: false, tracked: true, ignored: false }
+    ]);
+    expect(result.neverCommit).toEqual(['.env', 'apps/web/.env.local']);
+    expect(result.exposed).toEqual(['apps/web/.env.local']);
+    expect(result.tools).toEqual([]);
+  });
+
+  it('should report null git status outside a repository', async () => {
+    write({ '.env': 'A=1' });
+    const result = await detectSecrets(root);
+    expect(result.envFiles).toEqual([{ file: '.env', template: false, tracked: null, ignored: null }]);
+    expect(result.exposed).toBeNull();
+  });
+
+  it('should detect direnv, Doppler, and Vault', async () => {
+    write({
+      '.envrc': 'dotenv',
+      'package.json': JSON.stringify({ scripts: { dev: 'doppler run -- next dev' }, dependencies: { 'node-vault': '^0.10' } }),
+      '.github/workflows/deploy.yml': 'steps:\n  - uses: hashicorp/vault-action@v3\n',
+      '.vault-token': 's.xxx'
+    });
+
+    const result = await detectSecrets(root);
+    expect(result.tools).toEqual([
+      { name: 'direnv', sources: ['.envrc'] },
+      { name: 'doppler', sources: ['package.json'] },
+      { name: 'vault', sources: ['package.json:node-vault', '.github/workflows/deploy.yml'] }
+    ]);
+    expect(result.neverCommit).toEqual(['.vault-token']);
+  });
+
+  it('should detect SOPS configs and encrypted files', async () => {
+    write({
+      '.sops.yaml': 'creation_rules:\n  - path_regex: secrets/.*\n',
+      'secrets/prod.enc.yaml': 'db_password: ENC[AES256_GCM,data:abc]\nsops:\n    mac: ENC[...]\n',
+      'config/secrets.yaml': 'plain: true\n'
+    });
+
+    const result = await detectSecrets(root);
+    expect(result.tools).toEqual([{ name: 'sops', sources: ['.sops.yaml', 'secrets/prod.enc.yaml'] }]);
+  });
+
+  it('should detect AWS Secrets Manager from SDK dependencies and CLI calls', async () => {
+    write({
+      'go.mod': 'module app\n\nrequire github.com/aws/aws-sdk-go-v2/service/secretsmanager v1.28.0\n',
+      'Makefile': 'secrets:\n\taws secretsmanager get-secret-value --secret-id app\n'
+    });
+
+    const result = await detectSecrets(root);
+    expect(result.tools).toEqual([{
+      name: 'aws-secrets-manager',
+      sources: ['go.mod:github.com/aws/aws-sdk-go-v2/service/secretsmanager', 'Makefile']
+    }]);
+    expect(result.envFiles).toEqual([]);
+  });
+});
@@ -0,0 +1,137 @@
+/**
+ * Tests for detect-tests.js
+ */
+
+const fs = require('fs');
+const os = require('os');
+const path = require('path');
+
+const { detectTestFrameworks, parseMakeTargets } = require('../lib/platform/detect-tests');
+
+describe('detect-tests', () => {
+  let root;
+
+  function write(files) {
+    for (const [file, content] of Object.entries(files)) {
+      const fullPath = path.join(root, file);
+      fs.mkdirSync(path.dirname(fullPath), { recursive: true });
+      fs.writeFileSync(fullPath, content);
+    }
+  }
+
+  beforeEach(() => {
+    root = fs.mkdtempSync(path.join(os.tmpdir(), 'detect-tests-'));
+  });
+
+  afterEach(() => {
+    fs.rmSync(root, { recursive: true, force: true });
+  });
+
+  it('should parse Makefile targets', () => {
+    expect(parseMakeTargets('.PHONY: test\nbuild lint:\n\tgo build\ntest: build\n\tgo test\nVAR := 1\n'))
+      .toEqual(['build', 'lint', 'test']);
+  });
+
+  it('should return null without test tooling', async () => {
+    write({ 'package.json': '{"scripts":{"test":"echo \\"Error: no test specified\\" && exit 1"}}' });
+    expect(await detectTestFrameworks(root)).toBeNull();
+  });
+
+  it('should detect Jest and Vitest and prefer the package script', async () => {
+    write({
+      'package.json': JSON.stringify({
+        scripts: { test: 'jest --coverage', 'test:unit': 'vitest run' },
+        devDependencies: { jest: '^29', vitest: '^1' }
+      }),
+      'pnpm-lock.yaml': '',
+      'vitest.config.ts': 'export default {}'
+    });
+
+    expect(await detectTestFrameworks(root)).toEqual({
+      frameworks: [
+        { name: 'jest', language: 'javascript', sources: ['package.json:jest', 'package.json#scripts.test'] },
+        { name: 'vitest', language: 'javascript', sources: ['package.json:vitest', 'vitest.config.ts', 'package.json#scripts.test:unit'] }
+      ],
+      command: 'pnpm test',
+      commands: [
+        { command: 'pnpm test', source: 'package.json#scripts.test' },
+        { command: 'npx jest', source: 'framework:jest' },
+        { command: 'npx vitest run', source: 'framework:vitest' }
+      ]
+    });
+  });
+
+  it('should detect Mocha from its config file', async () => {
+    write({ '.mocharc.yml': 'spec: test/**/*.spec.js' });
+    const result = await detectTestFrameworks(root);
+    expect(result.frameworks).toEqual([{ name: 'mocha', language: 'javascript', sources: ['.mocharc.yml'] }]);
+    expect(result.command).toBe('npx mocha');
+  });
+
+  it('should detect pytest with tox and Poetry', async () => {
+    write({
+      'pyproject.toml': '[tool.poetry.group.dev.dependencies]\npytest = "^8"\n\n[tool.pytest.ini_options]\naddopts = "-q"\n',
+      'poetry.lock': '',
+      'tox.ini': '[tox]\nenvlist = py311\n'
+    });
+
+    const result = await detectTestFrameworks(root);
+    expect(result.frameworks).toEqual([
+      { name: 'pytest', language: 'python', sources: ['pyproject.toml:pytest', 'pyproject.toml'] }
+    ]);
+    expect(result.commands).toEqual([
+      { command: 'tox', source: 'tox.ini' },
+      { command: 'poetry run pytest', source: 'framework:pytest' }
+    ]);
+  });
+
+  it('should detect unittest from test module imports', async () => {
+    write({
+      'setup.py': 'from setuptools import setup',
+      'tests/test_core.py': 'import unittest\n\nclass CoreTest(unittest.TestCase):\n    pass\n'
+    });
+
+    const result = await detectTestFrameworks(root);
+    expect(result.frameworks).toEqual([{ name: 'unittest', language: 'python', sources: ['tests/test_core.py'] }]);
+    expect(result.command).toBe('python -m unittest discover');
+  });
+
+  it('should detect go test with testify and a Makefile target', async () => {
+    write({
+      'go.mod': 'module app\n\nrequire github.com/stretchr/testify v1.9.0\n',
+      'Makefile': 'build:\n\tgo build ./...\n\ntest:\n\tgo test -race ./...\n'
+    });
+
+    const result = await detectTestFrameworks(root);
+    expect(result.frameworks.map(framework => framework.name)).toEqual(['go-test', 'testify']);
+    expect(result.commands).toEqual([
+      { command: 'make test', source: 'Makefile' },
+      { command: 'go test ./...', source: 'framework:go-test' }
+    ]);
+  });
+
+  it('should detect cargo test and nextest', async () => {
+    write({ 'Cargo.toml': '[package]\nname = "app"\n', '.config/nextest.toml': '' });
+    const result = await detectTestFrameworks(root);
+    expect(result.frameworks.map(framework => framework.name)).toEqual(['cargo-test', 'cargo-nextest']);
+    expect(result.command).toBe('cargo test');
+  });
+
+  it('should pick Maven or the Gradle wrapper for JUnit', async () => {
+    write({ 'pom.xml': '<dependency><groupId>org.junit.jupiter</groupId><artifactId>junit-jupiter</artifactId></dependency>' });
+    expect((await detectTestFrameworks(root)).command).toBe('mvn test');
+
+    fs.rmSync(path.join(root, 'pom.xml'));
+    write({ 'build.gradle.kts': 'testImplementation("org.junit.jupiter:junit-jupiter:5.10.0")', 'gradlew': '#!/bin/sh' });
+    const result = await detectTestFrameworks(root);
+    expect(result.frameworks).toEqual([{ name: 'junit', language: 'java', sources: ['build.gradle.kts'] }]);
+    expect(result.command).toBe('./gradlew test');
+  });
+
+  it('should detect RSpec from the Gemfile and .rspec', async () => {
+    write({ 'Gemfile': "group :test do\n  gem 'rspec-rails'\nend\n", '.rspec': '--require spec_helper' });
+    const result = await detectTestFrameworks(root);
+    expect(result.frameworks).toEqual([{ name: 'rspec', language: 'ruby', sources: ['Gemfile:rspec-rails', '.rspec'] }]);
+    expect(result.command).toBe('bundle exec rspec');
+  });
+});
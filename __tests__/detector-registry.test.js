@@ -0,0 +1,124 @@
+/**
+ * Tests for detector-registry.js and custom detectors in detect()
+ */
+
+const fs = require('fs');
+const os = require('os');
+const path = require('path');
+
+describe('detector-registry', () => {
+  const originalCwd = process.cwd();
+  const originalStateDir = process.env.AI_STATE_DIR;
+  let root;
+
+  function write(files) {
+    for (const [file, content] of Object.entries(files)) {
+      const fullPath = path.join(root, file);
+      fs.mkdirSync(path.dirname(fullPath), { recursive: true });
+      fs.writeFileSync(fullPath, content);
+    }
+  }
+
+  // Fresh registry and caches, like a new process
+  function load() {
+    let platform;
+    jest.isolateModules(() => {
+      platform = require('../lib/platform/detect-platform');
+    });
+    return platform;
+  }
+
+  const paasPlugin = `module.exports = {
+  name: 'acme-paas',
+  markers: ['acme.yaml'],
+  async detect({ exists, readFile }) {
+    if (!(await exists('acme.yaml'))) return null;
+    const match = (await readFile('acme.yaml')).match(/^app:\\s*(\\S+)/m);
+    return { manifest: 'acme.yaml', app: match ? match[1] : null };
+  }
+};
+`;
+
+  beforeEach(() => {
+    root = fs.realpathSync(fs.mkdtempSync(path.join(os.tmpdir(), 'detector-registry-')));
+    process.chdir(root);
+    process.env.AI_STATE_DIR = '.state';
+  });
+
+  afterEach(() => {
+    process.chdir(originalCwd);
+    if (originalStateDir === undefined) delete process.env.AI_STATE_DIR;
+    else process.env.AI_STATE_DIR = originalStateDir;
+    fs.rmSync(root, { recursive: true, force: true });
+  });
+
+  it('should validate detector definitions', () => {
+    const { registerDetector } = load();
+    expect(() => registerDetector({ name: 'Bad Name', detect() {} })).toThrow('Invalid detector name');
+    expect(() => registerDetector({ name: 'no-detect' })).toThrow('must provide a detect() function');
+    expect(() => registerDetector({ name: 'bad-markers', detect() {}, markers: 'acme.yaml' })).toThrow('markers');
+
+    registerDetector({ name: 'acme', detect() {} });
+    expect(() => registerDetector({ name: 'acme', detect() {} })).toThrow('already registered');
+  });
+
+  it('should run project-local detectors alongside the built-ins', async () => {
+    write({
+      'package.json': '{"name":"app"}',
+      'acme.yaml': 'app: storefront\n',
+      '.state/detectors/paas.js': paasPlugin
+    });
+
+    const result = await load().detect();
+    expect(result.projectType).toBe('nodejs');
+    expect(result.custom).toEqual({ 'acme-paas': { manifest: 'acme.yaml', app: 'storefront' } });
+    expect(result.customErrors).toEqual([]);
+  });
+
+  it('should return null custom results when no detector matches', async () => {
+    write({ '.state/detectors/paas.js': paasPlugin });
+    const result = await load().detect();
+    expect(result.custom).toBeNull();
+  });
+
+  it('should invalidate the persisted result when a detector marker changes', async () => {
+    write({ 'acme.yaml': 'app: storefront\n', '.state/detectors/paas.js': paasPlugin });
+    await load().detect();
+
+    write({ 'acme.yaml': 'app: checkout\n' });
+    const time = new Date(Date.now() + 5000);
+    fs.utimesSync(path.join(root, 'acme.yaml'), time, time);
+
+    expect((await load().detect()).custom['acme-paas'].app).toBe('checkout');
+  });
+
+  it('should report failing detectors and broken plugins without failing detection', async () => {
+    write({
+      '.state/detectors/a-broken.js': 'module.exports = {',
+      '.state/detectors/b-register.js': `module.exports = ({ registerDetector }) => {
+  registerDetector({ name: 'throws', detect() { throw new Error('boom'); } });
+  registerDetector({ name: 'works', detect: () => ({ ok: true }) });
+};
+`
+    });
+
+    const result = await load().detect();
+    expect(result.custom).toEqual({ works: { ok: true } });
+    expect(result.customErrors).toEqual([
+      { file: '.state/detectors/a-broken.js', error: expect.any(String) },
+      { name: 'throws', error: 'boom' }
+    ]);
+  });
+
+  it('should skip cached results after registering a detector in-process', async () => {
+    const platform = load();
+    const first = await platform.detect();
+    expect(first.custom).toBeNull();
+
+    platform.registerDetector({ name: 'always', detect: () => 'yes' });
+    expect((await platform.detect()).custom).toEqual({ always: 'yes' });
+
+    platform.unregisterDetector('always');
+    expect((await platform.detect()).custom).toBeNull();
+  });
+});
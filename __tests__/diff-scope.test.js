@@ -0,0 +1,152 @@
+/**
+ * Tests for diff-scoped slop scanning
+ */
+
+const fs = require('fs');
+const os = require('os');
+const path = require('path');
+
+const { parseUnifiedDiff, getChangedLines, getStagedLines, filterToChangedLines } = require('../lib/patterns/diff-scope');
+const { runPipeline } = require('../lib/patterns/pipeline');
+
+describe('diff-scope', () => {
+  describe('parseUnifiedDiff', () => {
+    it('should map added lines to new-side line numbers', () => {
+      const diff = [
+        'diff --git a/src/app.js b/src/app.js',
+        'index 1111111..2222222 100644',
+        '--- a/src/app.js',
+        '+++ b/src/app.js',
+        '@@ -3,0 +4,2 @@ function run() {',
+        '+  console.log(1);',
+        '++++ not a header',
+        '@@ -10 +12 @@',
+        '-  old();',
+        '+  updated();',
+        'diff --git a/gone.js b/gone.js',
+        'deleted file mode 100644',
+        '--- a/gone.js',
+        '+++ /dev/null',
+        '@@ -1 +0,0 @@',
+        '-x();',
+        'diff --git a/old name.js b/new name.js',
+        'similarity index 90%',
+        'rename from old name.js',
+        'rename to new name.js',
+        '--- a/old name.js',
+        '+++ b/new name.js',
+        '@@ -1 +1 @@',
+        '-a',
+        '+b',
+        'diff --git "a/t\\303\\244b.js" "b/t\\303\\244b.js"',
+        '--- "a/t\\303\\244b.js"',
+        '+++ "b/t\\303\\244b.js"',
+        '@@ -0,0 +1 @@',
+        '+c'
+      ].join('\n');
+
+      expect(Object.fromEntries(parseUnifiedDiff(diff))).toEqual({
+        'src/app.js': [4, 5, 12],
+        'new name.js': [1],
+        'täb.js': [1]
+      });
+    });
+  });
+
+  describe('getChangedLines', () => {
+    it('should diff the merge base against the working tree', () => {
+      const calls = [];
+      const execFileSync = (cmd, args) => {
+        calls.push(args);
+        if (args[0] === 'merge-base') return 'abc123\n';
+        return 'diff --git a/a.js b/a.js\n--- a/a.js\n+++ b/a.js\n@@ -1 +1 @@\n-x\n+y\n';
+      };
+
+      const result = getChangedLines('/repo', 'origin/main', { execFileSync });
+      expect(result.error).toBeNull();
+      expect(result.mergeBase).toBe('abc123');
+      expect(Object.fromEntries(result.files)).toEqual({ 'a.js': [1] });
+      expect(calls[0]).toEqual(['merge-base', 'origin/main', 'HEAD']);
+      expect(calls[1]).toEqual(expect.arrayContaining(['diff', '--unified=0', '--relative', 'abc123']));
+    });
+
+    it('should reject unsafe refs and report git failures', () => {
+      const execFileSync = () => {
+        const error = new Error('Command failed');
+        error.stderr = 'fatal: Not a valid object name nope\n';
+        throw error;
+      };
+      expect(getChangedLines('/repo', '--output=/tmp/x', { execFileSync }).error).toBe('Invalid diff base: --output=/tmp/x');
+      expect(getChangedLines('/repo', 'nope', { execFileSync }).error)
+        .toBe('Cannot find merge base of nope and HEAD: fatal: Not a valid object name nope');
+    });
+  });
+
+  describe('getStagedLines', () => {
+    it('should diff the index against HEAD without deletions', () => {
+      const calls = [];
+      const execFileSync = (cmd, args) => {
+        calls.push(args);
+        return 'diff --git a/a.js b/a.js\n--- a/a.js\n+++ b/a.js\n@@ -0,0 +1,2 @@\n+x\n+y\n';
+      };
+
+      const result = getStagedLines('/repo', { execFileSync });
+      expect(result).toEqual({ base: 'staged', mergeBase: null, files: new Map([['a.js', [1, 2]]]), error: null });
+      expect(calls[0]).toEqual(expect.arrayContaining(['diff', '--cached', '--unified=0', '--diff-filter=ACMR']));
+    });
+
+    it('should report git failures', () => {
+      const execFileSync = () => {
+        const error = new Error('Command failed');
+        error.stderr = 'fatal: not a git repository\n';
+        throw error;
+      };
+      expect(getStagedLines('/repo', { execFileSync }).error).toBe('git diff --cached failed: fatal: not a git repository');
+    });
+  });
+
+  it('should keep findings on changed lines, including overlapping blocks', () => {
+    const files = new Map([['a.js', [3, 10]]]);
+    const findings = [
+      { file: 'a.js', line: 3 },
+      { file: 'a.js', line: 4 },
+      { file: 'a.js', line: 8, details: { startLine: 8, endLine: 11 } },
+      { file: 'b.js', line: 3 },
+      { file: 'a.js' }
+    ];
+
+    const result = filterToChangedLines(findings, files);
+    expect(result.findings).toEqual([findings[0], findings[2]]);
+    expect(result.outOfScope).toBe(3);
+  });
+
+  describe('runPipeline integration', () => {
+    let root;
+
+    beforeEach(() => {
+      root = fs.mkdtempSync(path.join(os.tmpdir(), 'diff-scope-'));
+      fs.writeFileSync(path.join(root, 'app.js'), 'console.log(1);\nconsole.log(2);\n');
+      fs.writeFileSync(path.join(root, 'app.test.js'), 'console.log(3);\n');
+      fs.writeFileSync(path.join(root, 'README.md'), '# app\n');
+    });
+
+    afterEach(() => {
+      fs.rmSync(root, { recursive: true, force: true });
+    });
+
+    it('should scan changed source files and report only changed lines', () => {
+      const changedLines = new Map([['app.js', [2]], ['app.test.js', [1]], ['README.md', [1]]]);
+      const result = runPipeline(root, {
+        thoroughness: 'quick',
+        diffBase: 'main',
+        changedLines,
+        linters: [],
+        baseline: false
+      });
+
+      expect(result.metadata.filesAnalyzed).toBe(1);
+      expect(result.findings.map(f => `${f.file}:${f.line}`)).toEqual(['app.js:2']);
+      expect(result.diffScope).toMatchObject({ base: 'main', changedFiles: 3, changedLines: 3, outOfScope: 1 });
+    });
+  });
+});
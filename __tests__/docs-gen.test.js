@@ -0,0 +1,209 @@
+/**
+ * Tests for API reference generation from the repo map
+ */
+
+const fs = require('fs');
+const os = require('os');
+const path = require('path');
+
+const {
+  GENERATED_MARKER,
+  readDocComment,
+  readModuleDoc,
+  declarationAt,
+  generateDocs,
+  writeDocs
+} = require('../lib/docs-gen');
+
+describe('docs-gen', () => {
+  let dir;
+
+  const write = (file, content) => {
+    fs.mkdirSync(path.dirname(path.join(dir, file)), { recursive: true });
+    fs.writeFileSync(path.join(dir, file), content);
+  };
+
+  beforeEach(() => {
+    dir = fs.mkdtempSync(path.join(os.tmpdir(), 'docs-gen-'));
+    write('src/config/index.js', [
+      '/**',
+      ' * Project configuration loading',
+      ' *',
+      ' * @module src/config',
+      ' */',
+      '',
+      '/**',
+      ' * Parsed settings file',
+      ' */',
+      'class Settings {',
+      '  /**',
+      '   * Value of a key',
+      '   * @param {string} key',
+      '   */',
+      '  get(key) {',
+      '    return this[key];',
+      '  }',
+      '}',
+      '',
+      '/**',
+      ' * Read the settings file',
+      ' * @param {string} basePath - Project root',
+      ' * @returns {Settings}',
+      ' */',
+      'function loadSettings(basePath,',
+      '  options = {}) {',
+      '  return new Settings();',
+      '}',
+      '',
+      'module.exports = { Settings, loadSettings };'
+    ].join('\n'));
+    write('src/server/index.js', [
+      "const { loadSettings } = require('../config');",
+      '',
+      '// Start the HTTP server with the project settings.',
+      '// Resolves once the port is bound.',
+      'async function start(settings) {',
+      '  return loadSettings(settings);',
+      '}',
+      '',
+      'module.exports = { start };'
+    ].join('\n'));
+    write('src/server/index.test.js', "test('start', () => {});\n");
+  });
+
+  afterEach(() => {
+    fs.rmSync(dir, { recursive: true, force: true });
+  });
+
+  const buildMap = () => ({
+    files: {
+      'src/config/index.js': {
+        language: 'javascript',
+        symbols: {
+          exports: [{ name: 'Settings', line: 30 }, { name: 'loadSettings', line: 30 }],
+          functions: [{ name: 'loadSettings', kind: 'function', line: 25, exported: true }],
+          classes: [{ name: 'Settings', kind: 'class', line: 10, exported: true, methods: [{ name: 'get', line: 15 }] }],
+          types: [],
+          constants: []
+        },
+        imports: []
+      },
+      'src/server/index.js': {
+        language: 'javascript',
+        symbols: {
+          exports: [{ name: 'start', line: 9 }],
+          functions: [{ name: 'start', kind: 'function', line: 5, exported: true }],
+          classes: [],
+          types: [],
+          constants: []
+        },
+        imports: [{ source: '../config', kind: 'require', line: 1 }]
+      },
+      'src/server/index.test.js': {
+        language: 'javascript',
+        symbols: { exports: [], functions: [{ name: 'helper', kind: 'function', line: 1, exported: true }], classes: [], types: [], constants: [] },
+        imports: []
+      }
+    }
+  });
+
+  describe('readDocComment', () => {
+    it('should read JSDoc text up to the first tag', () => {
+      const lines = fs.readFileSync(path.join(dir, 'src/config/index.js'), 'utf8').split('\n');
+      expect(readDocComment(lines, 25, 'javascript')).toEqual({ text: 'Read the settings file', deprecated: false });
+    });
+
+    it('should read line comments, Go deprecation notes, and skip attributes', () => {
+      const go = ['// Open opens the store.', '//', '// Deprecated: use OpenContext.', 'func Open() error {'];
+      expect(readDocComment(go, 4, 'go')).toEqual({ text: 'Open opens the store.\n\nDeprecated: use OpenContext.', deprecated: true });
+
+      const rust = ['/// Parsed manifest', '#[derive(Debug)]', 'pub struct Manifest {'];
+      expect(readDocComment(rust, 3, 'rust').text).toBe('Parsed manifest');
+
+      expect(readDocComment(['let x = 1;', 'function f() {}'], 2, 'javascript')).toBeNull();
+    });
+
+    it('should prefer Python docstrings', () => {
+      const python = ['# helper', 'def fetch(url,', '          timeout=5):', '    """Fetch a URL.', '', '    Retries once.', '    """'];
+      expect(readDocComment(python, 2, 'python').text).toBe('Fetch a URL.\n\nRetries once.');
+      expect(readModuleDoc(['"""Cart pricing."""', 'import os'], 'python').text).toBe('Cart pricing.');
+    });
+  });
+
+  describe('readModuleDoc', () => {
+    it('should skip license headers and read the package comment', () => {
+      const js = ['#!/usr/bin/env node', '// Copyright 2024 Example', '', '/**', ' * Queue workers', ' */', 'const x = 1;'];
+      expect(readModuleDoc(js, 'javascript').text).toBe('Queue workers');
+      expect(readModuleDoc(['// Package cart prices orders.', 'package cart'], 'go').text).toBe('Package cart prices orders.');
+      expect(readModuleDoc(['const x = 1;'], 'javascript')).toBeNull();
+    });
+  });
+
+  describe('declarationAt', () => {
+    it('should join multi-line signatures and drop the body', () => {
+      const lines = ['func (s *Store) Get(', '\tkey string,', ') (Value, error) {'];
+      expect(declarationAt(lines, 1, 'Get')).toBe('func (s *Store) Get(key string,) (Value, error)');
+      expect(declarationAt(['type Config struct {'], 1, 'Config')).toBe('type Config struct');
+      expect(declarationAt(['def run(a, b):'], 1, 'run')).toBe('def run(a, b)');
+      expect(declarationAt(['something else'], 1, 'run')).toBeNull();
+    });
+  });
+
+  describe('generateDocs', () => {
+    it('should require a repo map', () => {
+      expect(generateDocs(dir, {})).toEqual({ success: false, error: 'No repo-map found. Run /repo-map init first.' });
+    });
+
+    it('should render one page per package with docs and import links', () => {
+      const result = generateDocs(dir, { map: buildMap() });
+      expect(result.success).toBe(true);
+      expect(result.pages.map(page => page.file)).toEqual(['docs/api/README.md', 'docs/api/src-config.md', 'docs/api/src-server.md']);
+
+      const config = result.pages[1].content;
+      expect(config.startsWith(GENERATED_MARKER)).toBe(true);
+      expect(config).toContain('# src/config\n\nProject configuration loading\n');
+      expect(config).toContain('**Imported by**: [src/server](src-server.md)');
+      expect(config).toContain('- [class Settings](#class-settings)');
+      expect(config).toContain('### function loadSettings\n\n```js\nfunction loadSettings(basePath, options = {})\n```\n\nRead the settings file\n');
+      expect(config).toContain('Source: [index.js:25](../../src/config/index.js#L25)');
+      expect(config).toContain('#### Settings.get\n\n```js\nget(key)\n```\n\nValue of a key\n');
+
+      const server = result.pages[2].content;
+      expect(server).toContain('**Imports**: [src/config](src-config.md)');
+      expect(server).toContain('Start the HTTP server with the project settings.\nResolves once the port is bound.');
+      expect(server).not.toContain('helper');
+
+      expect(result.pages[0].content).toContain('| [src/config](src-config.md) | javascript | 2 | Project configuration loading |');
+      expect(result.pages[0].content).toContain('2 packages, 3 exported symbols.');
+    });
+
+    it('should link types used in signatures to their page', () => {
+      write('src/server/index.js', [
+        "const { Settings } = require('../config');",
+        'function start(settings = new Settings()) {}',
+        'module.exports = { start };'
+      ].join('\n'));
+      const map = buildMap();
+      map.files['src/server/index.js'].symbols.functions[0].line = 2;
+      const server = generateDocs(dir, { map }).pages[2].content;
+      expect(server).toContain('**Uses**: [Settings](src-config.md#class-settings) | Source: [index.js:2]');
+    });
+  });
+
+  describe('writeDocs', () => {
+    it('should replace generated pages and leave hand-written files alone', () => {
+      write('docs/api/src-old.md', `${GENERATED_MARKER}\n\n# src/old\n`);
+      write('docs/api/README.md', '# Hand-written\n');
+      write('docs/api/notes.md', '# Notes\n');
+
+      const summary = writeDocs(dir, generateDocs(dir, { map: buildMap() }));
+      expect(summary).toEqual({
+        written: ['docs/api/src-config.md', 'docs/api/src-server.md'],
+        removed: ['docs/api/src-old.md'],
+        skipped: ['docs/api/README.md']
+      });
+      expect(fs.readFileSync(path.join(dir, 'docs/api/README.md'), 'utf8')).toBe('# Hand-written\n');
+      expect(fs.existsSync(path.join(dir, 'docs/api/notes.md'))).toBe(true);
+    });
+  });
+});
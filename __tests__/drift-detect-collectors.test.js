@@ -172,6 +172,19 @@ Just some description without installation or usage.
       expect(result.hasTypeScript).toBe(false);
     });
 
+    test('skips ignored directories', () => {
+      fs.mkdirSync(path.join(testDir, 'src'));
+      fs.mkdirSync(path.join(testDir, 'generated'));
+      fs.mkdirSync(path.join(testDir, 'vendor'));
+      fs.writeFileSync(path.join(testDir, '.gitignore'), 'generated/\n');
+
+      const result = scanCodebase({ cwd: testDir });
+
+      expect(result.topLevelDirs).toContain('src');
+      expect(result.topLevelDirs).not.toContain('generated');
+      expect(result.topLevelDirs).not.toContain('vendor');
+    });
+
     test('detects TypeScript from tsconfig.json', () => {
       fs.writeFileSync(path.join(testDir, 'tsconfig.json'), '{}');
 
@@ -0,0 +1,101 @@
+/**
+ * Tests for duplicate code detection
+ */
+
+const fs = require('fs');
+const os = require('os');
+const path = require('path');
+
+const { tokenize, winnow, findDuplicates } = require('../lib/patterns/duplicates');
+const { runPipeline } = require('../lib/patterns/pipeline');
+
+const block = [
+  'function total(items) {',
+  '  let sum = 0;',
+  '  for (const item of items) {',
+  '    if (item.price > 10) {',
+  '      sum += item.price * item.qty;',
+  '    } else {',
+  '      sum += item.price;',
+  '    }',
+  '  }',
+  '  return sum;',
+  '}'
+];
+
+describe('duplicates', () => {
+  it('should tokenize without comments and keep line numbers', () => {
+    const tokens = tokenize('a = "x // y"; // note\n/* block\n */ b(1);\n', 'js');
+    expect(tokens.map(t => `${t.line}:${t.value}`)).toEqual(['1:a', '1:=', '1:"x // y"', '1:;', '3:b', '3:(', '3:1', '3:)', '3:;']);
+
+    const python = tokenize('x = 1  # comment\ns = """doc # not a comment"""\n', 'python');
+    expect(python.map(t => t.value)).toEqual(['x', '=', '1', 's', '=', '"""doc # not a comment"""']);
+  });
+
+  it('should winnow at least one fingerprint per window', () => {
+    const tokens = tokenize(block.join('\n'), 'js');
+    const prints = winnow(tokens, 10, 4);
+    const positions = prints.map(p => p.pos);
+
+    expect(prints.length).toBeGreaterThan(0);
+    expect(positions).toEqual([...positions].sort((a, b) => a - b));
+    for (let i = 1; i < positions.length; i++) {
+      expect(positions[i] - positions[i - 1]).toBeLessThanOrEqual(4);
+    }
+    expect(winnow(tokens.slice(0, 5), 10, 4)).toEqual([]);
+  });
+
+  it('should find blocks copied across files regardless of formatting and comments', () => {
+    const files = {
+      'src/a.js': ['// header', ...block, ''].join('\n'),
+      'src/b.js': ['const x = 1;', '', ...block.map(line => line.replace(/^ +/, s => s + s)), ''].join('\n').replace('let sum = 0;', 'let sum = 0; // running total'),
+      'src/c.js': block.slice(0, 4).join('\n')
+    };
+
+    const clones = findDuplicates('/repo', Object.keys(files), { readFile: file => files[file] });
+    expect(clones).toEqual([{
+      a: { file: 'src/a.js', startLine: 2, endLine: 12 },
+      b: { file: 'src/b.js', startLine: 3, endLine: 13 },
+      lines: 11,
+      tokens: expect.any(Number)
+    }]);
+    expect(findDuplicates('/repo', Object.keys(files), { readFile: file => files[file], minLines: 12 })).toEqual([]);
+  });
+
+  it('should report non-overlapping copies within one file', () => {
+    const content = [...block, '', ...block].join('\n').replace(/total/, 'first');
+    const clones = findDuplicates('/repo', ['a.js'], { readFile: () => content, minTokens: 40 });
+
+    expect(clones).toHaveLength(1);
+    expect(clones[0].a.endLine).toBeLessThan(clones[0].b.startLine);
+    expect(clones[0].b).toEqual({ file: 'a.js', startLine: 13, endLine: 23 });
+  });
+
+  describe('runPipeline integration', () => {
+    let root;
+
+    beforeEach(() => {
+      root = fs.mkdtempSync(path.join(os.tmpdir(), 'duplicates-'));
+      fs.writeFileSync(path.join(root, 'a.js'), block.join('\n') + '\nmodule.exports = total;\n');
+      fs.writeFileSync(path.join(root, 'b.js'), block.join('\n') + '\nmodule.exports = total;\n');
+    });
+
+    afterEach(() => {
+      fs.rmSync(root, { recursive: true, force: true });
+    });
+
+    it('should report duplicate_code with the other location and honor thresholds', () => {
+      const options = { thoroughness: 'normal', linters: [], runtimes: [], baseline: false };
+      const findings = runPipeline(root, options).findings.filter(f => f.patternName === 'duplicate_code');
+
+      expect(findings).toHaveLength(1);
+      expect(findings[0]).toMatchObject({ file: 'b.js', line: 1, certainty: 'MEDIUM' });
+      expect(findings[0].details.duplicateOf).toEqual({ file: 'a.js', startLine: 1, endLine: 12 });
+
+      const strict = runPipeline(root, { ...options, duplicates: { minLines: 20 } });
+      expect(strict.findings.some(f => f.patternName === 'duplicate_code')).toBe(false);
+      const disabled = runPipeline(root, { ...options, duplicates: false });
+      expect(disabled.findings.some(f => f.patternName === 'duplicate_code')).toBe(false);
+    });
+  });
+});
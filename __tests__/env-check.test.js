@@ -0,0 +1,115 @@
+/**
+ * Tests for environment variable checks
+ */
+
+const fs = require('fs');
+const os = require('os');
+const path = require('path');
+
+const {
+  parseDotenv,
+  yamlBlockKeys,
+  collectSources,
+  compareEnv,
+  checkEnv,
+  renderReport
+} = require('../lib/env-check');
+
+const map = {
+  files: {
+    'src/server.js': {
+      language: 'javascript',
+      env: [
+        { name: 'PORT', line: 1, fallback: true },
+        { name: 'DATABASE_URL', line: 2, fallback: false },
+        { name: 'SENTRY_DSN', line: 3, fallback: false },
+        { name: 'NODE_ENV', line: 4, fallback: false }
+      ]
+    },
+    'src/flags.js': { language: 'javascript', env: [{ name: 'FEATURE_X', line: 5, fallback: true }] },
+    'worker/main.py': { language: 'python' },
+    'tests/server.test.js': { language: 'javascript', env: [{ name: 'TEST_ONLY', line: 1, fallback: false }] }
+  }
+};
+
+function writeFiles(dir, files) {
+  for (const [file, content] of Object.entries(files)) {
+    fs.mkdirSync(path.dirname(path.join(dir, file)), { recursive: true });
+    fs.writeFileSync(path.join(dir, file), content);
+  }
+}
+
+describe('env-check', () => {
+  describe('parsers', () => {
+    it('should read dotenv names, including commented-out ones', () => {
+      expect(parseDotenv('# Database\nDATABASE_URL=postgres://\nexport PORT=3000\n# SENTRY_DSN=\nnot a var\n'))
+        .toEqual(['DATABASE_URL', 'PORT', 'SENTRY_DSN']);
+    });
+
+    it('should read YAML env blocks as mappings and lists', () => {
+      const compose = 'services:\n  web:\n    environment:\n      - DATABASE_URL=postgres://db\n      - PORT\n    ports:\n      - "80:80"\n  db:\n    environment:\n      POSTGRES_DB: app\n';
+      expect(yamlBlockKeys(compose, 'environment')).toEqual(['DATABASE_URL', 'PORT', 'POSTGRES_DB']);
+    });
+  });
+
+  describe('collectSources', () => {
+    let dir;
+
+    beforeEach(() => {
+      dir = fs.mkdtempSync(path.join(os.tmpdir(), 'env-check-'));
+    });
+
+    afterEach(() => {
+      fs.rmSync(dir, { recursive: true, force: true });
+    });
+
+    it('should read example, CI, and deploy files', () => {
+      writeFiles(dir, {
+        '.env.example': 'DATABASE_URL=\nLEGACY_TOKEN=\n',
+        '.github/workflows/ci.yml': 'env:\n  CI_LEVEL: 1\njobs:\n  deploy:\n    steps:\n      - run: npm publish\n        env:\n          NPM_TOKEN: ${{ secrets.NPM_TOKEN }}\n          TOKEN: ${{ secrets.GITHUB_TOKEN }}\n',
+        'fly.toml': 'app = "shop"\n[env]\n  SENTRY_DSN = "https://x"\n[http_service]\n  internal_port = 8080\n',
+        'Dockerfile': 'FROM node:20\nARG VERSION\nENV PORT=8080 LOG_FORMAT=json\n'
+      });
+
+      expect(collectSources(dir)).toEqual([
+        { file: '.env.example', kind: 'example', names: ['DATABASE_URL', 'LEGACY_TOKEN'] },
+        { file: '.github/workflows/ci.yml', kind: 'ci', names: ['CI_LEVEL', 'NPM_TOKEN', 'TOKEN'] },
+        { file: 'fly.toml', kind: 'deploy', names: ['SENTRY_DSN'] },
+        { file: 'Dockerfile', kind: 'deploy', names: ['PORT', 'LOG_FORMAT'] }
+      ]);
+    });
+
+    it('should report undocumented, unused, and example gaps', () => {
+      writeFiles(dir, {
+        '.env.example': 'DATABASE_URL=\nLEGACY_TOKEN=\n',
+        'fly.toml': '[env]\n  SENTRY_DSN = "https://x"\n',
+        'worker/main.py': "import os\nqueue = os.environ['QUEUE_URL']\n"
+      });
+
+      const report = checkEnv(dir, { map });
+      expect(report.success).toBe(true);
+      expect(report.undocumented.map(variable => [variable.name, variable.severity])).toEqual([
+        ['QUEUE_URL', 'high'],
+        ['FEATURE_X', 'low'],
+        ['PORT', 'low']
+      ]);
+      expect(report.undocumented[0].reads).toEqual([{ file: 'worker/main.py', line: 2, fallback: false }]);
+      expect(report.unused).toEqual([{ name: 'LEGACY_TOKEN', documented: [{ file: '.env.example', kind: 'example' }] }]);
+      expect(report.exampleGaps).toEqual([{ name: 'SENTRY_DSN', documented: [{ file: 'fly.toml', kind: 'deploy' }] }]);
+      expect(report.variables.map(variable => variable.name)).not.toContain('TEST_ONLY');
+
+      const content = renderReport(report);
+      expect(content).toContain('| `QUEUE_URL` | no | `worker/main.py:2` |');
+      expect(content).toContain('| `LEGACY_TOKEN` | .env.example |');
+      expect(content).toContain('- `SENTRY_DSN` (fly.toml)');
+    });
+
+    it('should skip the unused check when a read uses a computed name', () => {
+      const dynamicMap = { files: { 'src/config.js': { language: 'javascript', env: [{ name: null, line: 3, fallback: false, dynamic: true }] } } };
+      const report = compareEnv(dynamicMap, [{ file: '.env.example', kind: 'example', names: ['ANYTHING'] }]);
+      expect(report.unused).toEqual([]);
+      expect(report.dynamic).toEqual([{ file: 'src/config.js', line: 3 }]);
+      expect(checkEnv(dir, { map: null }).success).toBe(false);
+    });
+  });
+});
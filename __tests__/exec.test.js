@@ -0,0 +1,130 @@
+/**
+ * Tests for the process and path helpers
+ * The real-process cases run on Linux, macOS, and Windows (the `platforms` job in ci.yml).
+ */
+
+const fs = require('fs');
+const os = require('os');
+const path = require('path');
+
+const {
+  findExecutable,
+  commandExists,
+  resolveCommand,
+  quoteWindowsArg,
+  spawnArgs,
+  run,
+  runAsync,
+  git,
+  grepFiles,
+  toPosix,
+  relativePath
+} = require('../lib/utils/exec');
+
+describe('exec', () => {
+  // A Windows PATH with an npm shim, a native binary, and a globally installed CLI
+  const files = new Set([
+    'C:\\Program Files\\nodejs\\npm.cmd',
+    'C:\\Program Files\\nodejs\\node.exe',
+    'C:\\Users\\dev\\AppData\\Roaming\\npm\\jscpd.cmd',
+    'C:\\repo\\node_modules\\.bin\\jest.cmd'
+  ]);
+  const fakeFs = {
+    statSync: file => {
+      if (!files.has(file)) throw new Error('ENOENT');
+      return { isFile: () => true };
+    },
+    accessSync: () => {}
+  };
+  const windows = {
+    platform: 'win32',
+    env: { Path: '"C:\\Program Files\\nodejs";C:\\Users\\dev\\AppData\\Roaming\\npm', PATHEXT: '.COM;.EXE;.BAT;.CMD', ComSpec: 'C:\\Windows\\system32\\cmd.exe' },
+    fs: fakeFs
+  };
+
+  it('should resolve commands through PATH and PATHEXT', () => {
+    expect(findExecutable('npm', windows)).toBe('C:\\Program Files\\nodejs\\npm.cmd');
+    expect(findExecutable('jscpd', windows)).toBe('C:\\Users\\dev\\AppData\\Roaming\\npm\\jscpd.cmd');
+    expect(findExecutable('C:\\repo\\node_modules\\.bin\\jest.cmd', windows)).toBe('C:\\repo\\node_modules\\.bin\\jest.cmd');
+    expect(findExecutable('git', windows)).toBeNull();
+    expect(commandExists('git', windows)).toBe(false);
+
+    expect(resolveCommand('npm', windows)).toEqual({ file: 'C:\\Program Files\\nodejs\\npm.cmd', shell: true });
+    expect(resolveCommand('node', windows)).toEqual({ file: 'C:\\Program Files\\nodejs\\node.exe', shell: false });
+    expect(resolveCommand('npm', { platform: 'linux' })).toEqual({ file: 'npm', shell: false });
+
+    expect(findExecutable('npm', {
+      platform: 'linux',
+      env: { PATH: '/usr/local/bin:/usr/bin' },
+      fs: { statSync: file => ({ isFile: () => file === '/usr/bin/npm' }), accessSync: () => {} }
+    })).toBe('/usr/bin/npm');
+  });
+
+  it('should quote arguments for cmd.exe', () => {
+    expect(quoteWindowsArg('plain')).toBe('^"plain^"');
+    expect(quoteWindowsArg('a&b |c')).toBe('^"a^&b^ ^|c^"');
+    expect(quoteWindowsArg('100%')).toBe('^"100^%^"');
+    expect(quoteWindowsArg('say "hi"')).toBe('^"say^ \\^"hi\\^"^"');
+    expect(quoteWindowsArg('C:\\dir\\')).toBe('^"C:\\dir\\\\^"');
+    expect(quoteWindowsArg('a&b', true)).toBe('^^^"a^^^&b^^^"');
+
+    expect(spawnArgs(['npm', 'view', 'left-pad&calc', 'version'], windows)).toEqual({
+      file: 'C:\\Windows\\system32\\cmd.exe',
+      args: ['/d', '/s', '/c', '"C:\\Program^ Files\\nodejs\\npm.cmd ^"view^" ^"left-pad^&calc^" ^"version^""'],
+      options: { windowsVerbatimArguments: true }
+    });
+    expect(spawnArgs(['C:\\repo\\node_modules\\.bin\\jest.cmd', 'a b'], windows).args[3]).toBe('"C:\\repo\\node_modules\\.bin\\jest.cmd ^^^"a^^^ b^^^""');
+    expect(spawnArgs(['node', '-v'], windows)).toEqual({ file: 'C:\\Program Files\\nodejs\\node.exe', args: ['-v'], options: {} });
+    expect(spawnArgs(['git', 'status'], { platform: 'darwin' })).toEqual({ file: 'git', args: ['status'], options: {} });
+  });
+
+  describe('on this platform', () => {
+    let dir;
+
+    beforeEach(() => {
+      dir = fs.mkdtempSync(path.join(os.tmpdir(), 'exec test '));
+    });
+
+    afterEach(() => {
+      fs.rmSync(dir, { recursive: true, force: true });
+    });
+
+    it('should pass arguments through unchanged and report failures', async () => {
+      const echo = ['-e', 'process.stdout.write(JSON.stringify(process.argv.slice(1)))'];
+      const args = ['two words', 'a&b|c', 'say "hi"', '100%', 'C:\\dir\\', '$HOME', ''];
+      expect(JSON.parse(run(dir, [process.execPath, ...echo, ...args]))).toEqual(args);
+      expect(JSON.parse(await runAsync(dir, [process.execPath, ...echo, ...args]))).toEqual(args);
+
+      const fail = [process.execPath, '-e', 'process.stdout.write("partial"); process.exit(3)'];
+      expect(run(dir, fail)).toBeNull();
+      expect(run(dir, fail, { keepOutput: true })).toBe('partial');
+      expect(await runAsync(dir, fail, { keepOutput: true })).toBe('partial');
+      expect(run(dir, ['awesome-slash-missing-command'])).toBeNull();
+      const quiet = [process.execPath, '-e', 'process.exit(1)'];
+      expect(run(dir, quiet, { okCodes: [1] })).toBe('');
+      expect(await runAsync(dir, quiet, { okCodes: [1] })).toBe('');
+      expect(run(dir, fail, { okCodes: [1] })).toBeNull();
+
+      expect(commandExists('node')).toBe(true);
+      expect(commandExists('awesome-slash-missing-command')).toBe(false);
+    });
+
+    it('should run git and search files in a path with spaces', () => {
+      git(dir, ['init', '-q']);
+      fs.mkdirSync(path.join(dir, 'src dir'));
+      fs.writeFileSync(path.join(dir, 'src dir', 'app.js'), "const db = require('knex');\n");
+      fs.writeFileSync(path.join(dir, 'README.md'), '# app\n');
+      git(dir, ['add', '.']);
+
+      expect(git(dir, ['ls-files']).split('\n').filter(Boolean)).toEqual(['README.md', 'src dir/app.js']);
+      expect(grepFiles(dir, "require\\('(knex|prisma)'\\)")).toEqual(['src dir/app.js']);
+      expect(grepFiles(dir, 'knex', ['*.md'])).toEqual([]);
+      expect(git(dir, ['rev-parse', 'no-such-ref'])).toBeNull();
+
+      expect(relativePath(dir, path.join(dir, 'src dir', 'app.js'))).toBe('src dir/app.js');
+      expect(relativePath(dir, dir)).toBe('');
+      expect(toPosix('src\\dir\\app.js', '\\')).toBe('src/dir/app.js');
+      expect(toPosix('src\\app.js', '/')).toBe('src\\app.js');
+    });
+  });
+});
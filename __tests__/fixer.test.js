@@ -0,0 +1,128 @@
+/**
+ * Tests for the slop auto-fixer
+ */
+
+const fs = require('fs');
+const os = require('os');
+const path = require('path');
+
+const { generateFixes, writeFixes, formatUnifiedDiff } = require('../lib/patterns/fixer');
+const { slopPatterns } = require('../lib/patterns/slop-patterns');
+
+describe('fixer', () => {
+  let root;
+
+  function write(files) {
+    for (const [file, content] of Object.entries(files)) {
+      const fullPath = path.join(root, file);
+      fs.mkdirSync(path.dirname(fullPath), { recursive: true });
+      fs.writeFileSync(fullPath, content);
+    }
+  }
+
+  function finding(file, line, patternName) {
+    return { file, line, patternName, autoFix: slopPatterns[patternName].autoFix };
+  }
+
+  function fixFile(file, content, findings) {
+    write({ [file]: content });
+    return generateFixes(root, findings, { patterns: slopPatterns });
+  }
+
+  beforeEach(() => {
+    root = fs.mkdtempSync(path.join(os.tmpdir(), 'fixer-'));
+  });
+
+  afterEach(() => {
+    fs.rmSync(root, { recursive: true, force: true });
+  });
+
+  it('should remove single and multi-line statements but not brace-less bodies', () => {
+    const content = [
+      'function run(x) {',
+      '  console.log("start");',
+      '  if (x)',
+      '    console.log("only body");',
+      '  console.debug(',
+      '    "multi", x',
+      '  );',
+      '  return x;',
+      '}',
+      ''
+    ].join('\n');
+    const fixes = fixFile('src/run.js', content, [
+      finding('src/run.js', 2, 'console_debugging'),
+      finding('src/run.js', 4, 'console_debugging'),
+      finding('src/run.js', 5, 'console_debugging')
+    ]);
+
+    expect(fixes.files[0].updated).toBe('function run(x) {\n  if (x)\n    console.log("only body");\n  return x;\n}\n');
+    expect(fixes.skipped).toEqual([
+      { finding: expect.objectContaining({ line: 4 }), reason: 'statement is the body of a brace-less block' }
+    ]);
+  });
+
+  it('should strip trailing comments and whitespace and skip overlapping edits', () => {
+    const fixes = fixFile('a.js', 'const a = 1; // fixes #12  \nconst b = 2;  \n', [
+      finding('a.js', 1, 'issue_pr_references'),
+      finding('a.js', 1, 'trailing_whitespace'),
+      finding('a.js', 2, 'trailing_whitespace')
+    ]);
+
+    expect(fixes.files[0].updated).toBe('const a = 1;\nconst b = 2;\n');
+    expect(fixes.skipped).toHaveLength(1);
+    expect(fixes.skipped[0].reason).toMatch(/^overlaps the fix for issue_pr_references on line 1/);
+  });
+
+  it('should add logging to empty catch blocks', () => {
+    const fixes = fixFile('a.ts', 'try { a(); } catch (err) {}\ntry {\n  b();\n} catch {\n}\n', [
+      finding('a.ts', 1, 'empty_catch_js'),
+      finding('a.ts', 4, 'empty_catch_js')
+    ]);
+
+    expect(fixes.files[0].updated).toBe(
+      'try { a(); } catch (err) { console.error(err); }\ntry {\n  b();\n} catch (error) {\n  console.error(error);\n}\n'
+    );
+  });
+
+  it('should keep Python blocks valid and import logging', () => {
+    const content = '"""Module doc."""\n\ndef f():\n    try:\n        print("x")\n    except ValueError: pass\n';
+    const fixes = fixFile('m.py', content, [
+      finding('m.py', 5, 'python_debugging'),
+      finding('m.py', 6, 'empty_except_py')
+    ]);
+
+    expect(fixes.files[0].updated).toBe(
+      '"""Module doc."""\nimport logging\n\ndef f():\n    try:\n        pass\n    except ValueError:\n        logging.exception("Suppressed exception")\n'
+    );
+  });
+
+  it('should log in an except whose pass is on the next line', () => {
+    const content = 'import os\n\ntry:\n    os.remove(p)\nexcept Exception:\n    pass\n';
+    const fixes = fixFile('m.py', content, [finding('m.py', 5, 'broad_except_pass_py')]);
+
+    expect(fixes.files[0].updated).toBe(
+      'import logging\nimport os\n\ntry:\n    os.remove(p)\nexcept Exception:\n    logging.exception("Suppressed exception")\n'
+    );
+  });
+
+  it('should render a unified diff and write files', () => {
+    const content = ['a', 'console.log(1);', 'b', 'c', 'd', 'e', 'f', 'g', 'h', 'console.log(2);', 'i'].join('\n');
+    const fixes = fixFile('x.js', content, [finding('x.js', 2, 'console_debugging'), finding('x.js', 10, 'console_debugging')]);
+
+    expect(fixes.files[0].diff).toBe([
+      '--- a/x.js', '+++ b/x.js',
+      '@@ -1,5 +1,4 @@', ' a', '-console.log(1);', ' b', ' c', ' d',
+      '@@ -7,5 +6,4 @@', ' f', ' g', ' h', '-console.log(2);', ' i', '\\ No newline at end of file',
+      ''
+    ].join('\n'));
+
+    expect(writeFixes(root, fixes)).toEqual({ filesChanged: 1, findingsFixed: 2 });
+    expect(fs.readFileSync(path.join(root, 'x.js'), 'utf8')).toBe('a\nb\nc\nd\ne\nf\ng\nh\ni');
+  });
+
+  it('should number insertions in diff hunks', () => {
+    const diff = formatUnifiedDiff('m.py', ['import os', 'x = 1'], [{ start: 0, end: -1, lines: ['import logging'] }]);
+    expect(diff).toBe('--- a/m.py\n+++ b/m.py\n@@ -1,2 +1,3 @@\n+import logging\n import os\n x = 1\n');
+  });
+});
@@ -0,0 +1,15 @@
+#include "sample.h"
+#include <stdlib.h>
+
+static int counter = 0;
+
+static int next_id(void) { return ++counter; }
+
+int sample_add(int a, int b) { return a + b; }
+
+Sample *sample_create(const char *name) {
+    Sample *s = malloc(sizeof(Sample));
+    s->id = next_id();
+    (void)name;
+    return s;
+}
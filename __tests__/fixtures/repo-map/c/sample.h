@@ -0,0 +1,22 @@
+#ifndef SAMPLE_H
+#define SAMPLE_H
+
+#include <stddef.h>
+
+#define SAMPLE_MAX 64
+#define SAMPLE_MIN(a, b) ((a) < (b) ? (a) : (b))
+
+typedef struct {
+    int id;
+    char name[SAMPLE_MAX];
+} Sample;
+
+struct sample_list {
+    Sample *items;
+    size_t count;
+};
+
+int sample_add(int a, int b);
+Sample *sample_create(const char *name);
+
+#endif
@@ -0,0 +1,9 @@
+#include "shape.hpp"
+
+namespace geometry {
+
+Circle::Circle(double radius) : radius_(radius) {}
+
+double Circle::area() const { return 3.14159 * radius_ * radius_; }
+
+}
@@ -0,0 +1,22 @@
+#pragma once
+
+#include <string>
+
+namespace geometry {
+
+class Shape {
+public:
+    virtual ~Shape() = default;
+    virtual double area() const = 0;
+};
+
+class Circle : public Shape {
+public:
+    explicit Circle(double radius);
+    double area() const override;
+
+private:
+    double radius_;
+};
+
+}
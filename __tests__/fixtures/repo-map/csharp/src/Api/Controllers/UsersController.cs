@@ -0,0 +1,27 @@
+using Microsoft.AspNetCore.Mvc;
+using Sample.Core;
+
+namespace Sample.Api.Controllers
+{
+    [ApiController]
+    [Route("api/[controller]")]
+    public class UsersController : ControllerBase
+    {
+        private readonly IUserStore _store;
+
+        public UsersController(IUserStore store)
+        {
+            _store = store;
+        }
+
+        [HttpGet("{id}")]
+        [ProducesResponseType(typeof(User), 200)]
+        public async Task<ActionResult<User>> GetAsync(string id)
+        {
+            var user = await _store.FindAsync(id);
+            return user is null ? NotFound() : Ok(user);
+        }
+
+        private static string Normalize(string id) { return id.Trim(); }
+    }
+}
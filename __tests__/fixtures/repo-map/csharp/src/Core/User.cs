@@ -0,0 +1,15 @@
+namespace Sample.Core;
+
+public record User(string Id, string Name);
+
+public interface IUserStore
+{
+    Task<User?> FindAsync(string id);
+}
+
+internal sealed class MemoryUserStore : IUserStore
+{
+    private readonly Dictionary<string, User> _users = new();
+
+    public Task<User?> FindAsync(string id) => Task.FromResult(_users.GetValueOrDefault(id));
+}
@@ -0,0 +1,49 @@
+package sample
+
+import "fmt"
+
+// Shape is implemented by Rect and *Circle.
+type Shape interface {
+	Area() float64
+	fmt.Stringer
+}
+
+type (
+	Rect struct {
+		Width, Height float64
+		label         string `json:"label"`
+	}
+
+	Circle struct {
+		Shape
+		Radius float64
+	}
+)
+
+type Celsius float64
+
+type Stack[T any] struct {
+	items []T
+}
+
+func (r Rect) Area() float64 { return r.Width * r.Height }
+func (r Rect) String() string { return fmt.Sprintf("%vx%v", r.Width, r.Height) }
+
+func (c *Circle) Area() float64 { return 3.14 * c.Radius * c.Radius }
+func (c *Circle) String() string { return "circle" }
+func (c *Circle) scale(by float64) { c.Radius *= by }
+
+func (s *Stack[T]) Push(item T) { s.items = append(s.items, item) }
+func (s *Stack[T]) Pop() (T, bool) {
+	var zero T
+	if len(s.items) == 0 {
+		return zero, false
+	}
+	item := s.items[len(s.items)-1]
+	s.items = s.items[:len(s.items)-1]
+	return item, true
+}
+
+func (c Celsius) Error() string { return "too hot" }
+
+func NewRect(width, height float64) Rect { return Rect{Width: width, Height: height} }
@@ -13,3 +13,28 @@ public class Sample {
 class PackagePrivate {
     public int value() { return 1; }
 }
+
+@RestController
+@RequestMapping("/users")
+public class UserController extends BaseController implements Auditable, Closeable {
+    @GetMapping("/{id}")
+    public User getUser(@PathVariable String id) { return null; }
+
+    @Override
+    public void close() {}
+
+    private void audit() {}
+}
+
+@Entity
+@Table(
+    name = "users"
+)
+class User {
+    @Id
+    private Long id;
+}
+
+public @interface Audited {
+    String value() default "";
+}
@@ -0,0 +1,33 @@
+package sample.kotlin
+
+import kotlinx.coroutines.flow.Flow
+
+const val MAX_USERS = 100
+
+typealias UserId = String
+
+@Serializable
+data class User(val id: UserId, val name: String)
+
+interface Repository {
+    suspend fun find(id: UserId): User?
+}
+
+@Service
+class UserService(private val repo: Repository) : Repository by repo, AutoCloseable {
+    override suspend fun find(id: UserId): User? = repo.find(id)
+
+    private fun log(message: String) {}
+
+    override fun close() {}
+}
+
+object Registry {
+    fun register(user: User) {}
+}
+
+fun String.toUserId(): UserId = this.trim()
+
+internal fun helper() {}
+
+private fun secret() {}
@@ -3,6 +3,7 @@ __all__ = ["PublicClass", "public_function"]
 import os, sys
 from math import sqrt
 from collections import deque, defaultdict
+from dataclasses import dataclass
 
 
 def public_function(value):
@@ -20,3 +21,23 @@ class PublicClass:
 
 class _PrivateClass:
     pass
+
+
+@dataclass(frozen=True)
+class Point:
+    x: int
+    y: int
+
+    @property
+    def norm(self):
+        return sqrt(self.x ** 2 + self.y ** 2)
+
+
+class Child(PublicClass, metaclass=type):
+    async def method(self):
+        return "child"
+
+
+@app.route("/users", methods=["GET"])
+def list_users():
+    return []
@@ -0,0 +1,21 @@
+require_relative '../models/user'
+
+module Admin
+  class UsersController < ApplicationController
+    before_action :authenticate
+
+    def index
+      @users = User.active
+      if @users.empty?
+        head :no_content
+      end
+    end
+
+    def show; end
+
+    def authenticate
+      true
+    end
+    private :authenticate
+  end
+end
@@ -0,0 +1,9 @@
+module Searchable
+  extend ActiveSupport::Concern
+
+  class_methods do
+    def search(query)
+      where('name LIKE ?', "%#{query}%")
+    end
+  end
+end
@@ -0,0 +1,29 @@
+require 'securerandom'
+
+class User < ApplicationRecord
+  include Searchable
+
+  ROLES = %w[admin member].freeze
+
+  has_many :posts, dependent: :destroy
+  belongs_to :team
+  before_save :normalize_email
+  scope :active, -> { where(active: true) }
+
+  def self.find_by_token(token)
+    find_by(token: token)
+  end
+
+  def display_name
+    <<~TEXT
+      def not_a_method
+      end
+    TEXT
+  end
+
+  private
+
+  def normalize_email
+    self.email = email.downcase
+  end
+end
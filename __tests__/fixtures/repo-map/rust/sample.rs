@@ -25,3 +25,27 @@ pub fn public_fn() -> i32 {
 fn private_fn() -> i32 {
     2
 }
+
+pub struct UnitMarker;
+struct Wrapper(i32);
+
+impl PublicStruct {
+    pub fn new(value: i32) -> Self {
+        PublicStruct { value }
+    }
+}
+
+impl fmt::Display for PublicStruct {
+    fn fmt(&self, f: &mut fmt::Formatter) -> fmt::Result {
+        write!(f, "{}", self.value)
+    }
+}
+
+#[macro_export]
+macro_rules! public_macro {
+    ($x:expr) => { $x };
+}
+
+macro_rules! private_macro {
+    () => {};
+}
@@ -0,0 +1,14 @@
+import React from 'react';
+
+export interface ButtonProps {
+  label: string;
+  onClick?: (event: MouseEvent) => void;
+}
+
+export function Button({ label, onClick }: ButtonProps) {
+  return <button onClick={onClick}>{label}</button>;
+}
+
+export const Badge: React.FC<{ count: number }> = ({ count }) => <span>{count}</span>;
+
+export const formatLabel = (label: string): string => label.trim();
@@ -13,3 +13,12 @@ type LocalType = string;
 interface LocalInterface { active: boolean; }
 const enum LocalEnum { A, B }
 abstract class AbstractThing { abstract run(): void; }
+
+export interface Repository<T extends { id: string }> extends Iterable<T> {
+  readonly name: string;
+  find(id: string): Promise<T | undefined>;
+  limit?: number;
+}
+export type Result<T, E = Error> = { ok: true; value: T } | { ok: false; error: E };
+export async function loadAll<T>(repo: Repository<T>, limit = 10): Promise<T[]> { return []; }
+export class Store<T> extends AbstractThing implements Iterable<T> { run(): void {} }
@@ -0,0 +1,148 @@
+/**
+ * Tests for flaky test detection from CI history
+ */
+
+const fs = require('fs');
+
+const {
+  parseJUnit,
+  parseGoTestJson,
+  parseJestJson,
+  parseGitlabTestReport,
+  detectFormat,
+  listRunsCommand,
+  parseRuns,
+  findFlakyTests,
+  detectFlakyTests,
+  renderReport
+} = require('../lib/flaky');
+
+const junit = (status) => `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites>
+  <testsuite name="cart" file="/home/runner/work/shop/shop/src/cart.test.js">
+    <testcase classname="cart" name="adds items" time="0.01"/>
+    <testcase classname="cart" name="applies &quot;SAVE10&quot;">
+      ${status === 'failed' ? '<failure message="expected 90 &lt; 100">AssertionError</failure>' : ''}
+    </testcase>
+    <testcase classname="cart" name="syncs"><skipped/></testcase>
+  </testsuite>
+</testsuites>`;
+
+describe('flaky', () => {
+  describe('parsers', () => {
+    it('should parse JUnit XML', () => {
+      expect(parseJUnit(junit('failed'))).toEqual([
+        { id: 'cart::adds items', name: 'adds items', suite: 'cart', file: 'src/cart.test.js', status: 'passed', message: null },
+        { id: 'cart::applies "SAVE10"', name: 'applies "SAVE10"', suite: 'cart', file: 'src/cart.test.js', status: 'failed', message: 'expected 90 < 100' },
+        { id: 'cart::syncs', name: 'syncs', suite: 'cart', file: 'src/cart.test.js', status: 'skipped', message: null }
+      ]);
+    });
+
+    it('should parse go test -json output', () => {
+      const events = [
+        { Action: 'run', Package: 'example.com/shop/cart', Test: 'TestTotal' },
+        { Action: 'output', Package: 'example.com/shop/cart', Test: 'TestTotal', Output: '=== RUN   TestTotal\n' },
+        { Action: 'output', Package: 'example.com/shop/cart', Test: 'TestTotal', Output: '    cart_test.go:12: got 90, want 100\n' },
+        { Action: 'output', Package: 'example.com/shop/cart', Test: 'TestTotal', Output: '--- FAIL: TestTotal (0.00s)\n' },
+        { Action: 'fail', Package: 'example.com/shop/cart', Test: 'TestTotal' },
+        { Action: 'pass', Package: 'example.com/shop/cart', Test: 'TestEmpty' },
+        { Action: 'fail', Package: 'example.com/shop/cart' }
+      ].map(event => JSON.stringify({ Time: '2026-01-01T00:00:00Z', ...event })).join('\n');
+
+      expect(detectFormat('go-test.json', events)).toBe('go');
+      expect(parseGoTestJson(events).map(result => [result.id, result.status, result.message])).toEqual([
+        ['example.com/shop/cart::TestTotal', 'failed', 'cart_test.go:12: got 90, want 100'],
+        ['example.com/shop/cart::TestEmpty', 'passed', null]
+      ]);
+    });
+
+    it('should parse Jest JSON and GitLab test reports', () => {
+      const jest = JSON.stringify({
+        testResults: [{
+          name: '/builds/acme/shop/src/cart.test.js',
+          assertionResults: [
+            { fullName: 'cart adds items', ancestorTitles: ['cart'], title: 'adds items', status: 'failed', failureMessages: ['Error: timeout'] },
+            { fullName: 'cart syncs', ancestorTitles: ['cart'], title: 'syncs', status: 'pending', failureMessages: [] }
+          ]
+        }]
+      });
+      expect(detectFormat('results.json', jest)).toBe('jest');
+      expect(parseJestJson(jest)[0]).toEqual({
+        id: 'src/cart.test.js::cart adds items', name: 'cart adds items', suite: 'cart', file: 'src/cart.test.js', status: 'failed', message: 'Error: timeout'
+      });
+      expect(parseJestJson(jest)[1].status).toBe('skipped');
+
+      const gitlab = { test_suites: [{ name: 'rspec', test_cases: [{ name: 'checks out', classname: 'Cart', status: 'error', system_output: 'Net::ReadTimeout' }] }] };
+      expect(parseGitlabTestReport(gitlab)).toEqual([
+        { id: 'Cart::checks out', name: 'checks out', suite: 'Cart', file: null, status: 'failed', message: 'Net::ReadTimeout' }
+      ]);
+      expect(detectFormat('package.json', '{"name": "shop"}')).toBeNull();
+    });
+  });
+
+  describe('runs', () => {
+    it('should build list commands and normalize run lists', () => {
+      expect(listRunsCommand('github-actions', { limit: 10, workflow: 'ci.yml' })).toEqual([
+        'gh', 'run', 'list', '--limit', '10', '--json', 'databaseId,headSha,headBranch,conclusion,createdAt,url,attempt,workflowName', '--workflow', 'ci.yml'
+      ]);
+      expect(listRunsCommand('gitlab-ci', { branch: 'main' })).toEqual(['glab', 'api', 'projects/:id/pipelines?per_page=30&ref=main']);
+      expect(listRunsCommand('jenkins')).toBeNull();
+
+      expect(parseRuns('gitlab-ci', JSON.stringify([{ id: 7, sha: 'abc', ref: 'main', status: 'failed', created_at: '2026-01-02', web_url: 'https://gitlab.com/p/-/pipelines/7' }])))
+        .toEqual([{ id: '7', commit: 'abc', branch: 'main', status: 'failed', createdAt: '2026-01-02', url: 'https://gitlab.com/p/-/pipelines/7', attempt: 1, workflow: null }]);
+      expect(parseRuns('github-actions', 'not json')).toEqual([]);
+    });
+  });
+
+  describe('findFlakyTests', () => {
+    it('should only report tests that disagree at the same commit', () => {
+      const result = (id, status) => ({ id, name: id, suite: null, file: null, status, message: status === 'failed' ? `${id} broke` : null });
+      const history = [
+        { run: { id: '1', commit: 'a', createdAt: '2026-01-01' }, results: [result('login', 'failed'), result('cart', 'failed')] },
+        { run: { id: '2', commit: 'a', createdAt: '2026-01-02' }, results: [result('login', 'passed'), result('cart', 'failed')] },
+        { run: { id: '3', commit: 'b', createdAt: '2026-01-03' }, results: [result('login', 'failed'), result('cart', 'passed')] },
+        { run: { id: '4', commit: 'b', createdAt: '2026-01-04' }, results: [result('login', 'passed'), result('cart', 'passed')] }
+      ];
+
+      const tests = findFlakyTests(history);
+      expect(tests).toHaveLength(1);
+      expect(tests[0]).toMatchObject({ id: 'login', executions: 4, failures: 2, flakeRate: 0.5, flakyCommits: 2 });
+      expect(tests[0].lastFailure).toMatchObject({ run: '3', commit: 'b', message: 'login broke' });
+    });
+  });
+
+  describe('detectFlakyTests', () => {
+    it('should download artifacts per run and report flaky tests', () => {
+      const runs = [
+        { databaseId: 11, headSha: 'abc', headBranch: 'main', conclusion: 'failure', createdAt: '2026-02-01T10:00:00Z', url: 'https://github.com/acme/shop/actions/runs/11', attempt: 1 },
+        { databaseId: 12, headSha: 'abc', headBranch: 'main', conclusion: 'success', createdAt: '2026-02-01T11:00:00Z', url: 'https://github.com/acme/shop/actions/runs/12', attempt: 2 },
+        { databaseId: 13, headSha: 'def', headBranch: 'main', conclusion: 'cancelled', createdAt: '2026-02-02T11:00:00Z', url: null, attempt: 1 },
+        { databaseId: 14, headSha: 'def', headBranch: 'main', conclusion: 'success', createdAt: '2026-02-03T11:00:00Z', url: null, attempt: 1 }
+      ];
+      const calls = [];
+      const run = (basePath, argv) => {
+        calls.push(argv.slice(0, 4).join(' '));
+        if (argv[1] === 'run' && argv[2] === 'list') return JSON.stringify(runs);
+        if (argv[3] === '14') return null;
+        const dir = argv[argv.indexOf('--dir') + 1];
+        fs.writeFileSync(`${dir}/junit.xml`, junit(argv[3] === '11' ? 'failed' : 'passed'));
+        return '';
+      };
+
+      const report = detectFlakyTests('.', { platform: 'github-actions', run });
+      expect(calls).toEqual(['gh run list --limit', 'gh run download 11', 'gh run download 12', 'gh run download 14']);
+      expect(report).toMatchObject({ success: true, runs: 3, analyzed: 2, skipped: ['14'], commits: 1 });
+      expect(report.tests.map(test => test.id)).toEqual(['cart::applies "SAVE10"']);
+
+      const content = renderReport(report);
+      expect(content).toContain('**Runs**: 2 analyzed of 3 (1 commits); 1 without test reports');
+      expect(content).toContain('| `cart::applies "SAVE10"` | 50% | 1/2 | 1 | 2026-02-01 ([run](https://github.com/acme/shop/actions/runs/11)) |');
+      expect(content).toContain('expected 90 < 100');
+    });
+
+    it('should refuse unsupported platforms and failed listings', () => {
+      expect(detectFlakyTests('.', { platform: 'jenkins' }).error).toMatch(/github-actions and gitlab-ci/);
+      expect(detectFlakyTests('.', { platform: 'gitlab-ci', run: () => null }).error).toMatch(/glab is installed/);
+    });
+  });
+});
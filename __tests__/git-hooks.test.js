@@ -0,0 +1,131 @@
+/**
+ * Tests for pre-commit hook installation
+ */
+
+const fs = require('fs');
+const os = require('os');
+const path = require('path');
+
+const {
+  detectFramework,
+  detectPackageManager,
+  preCommitConfig,
+  planHooks,
+  applyHooks,
+  renderPlan
+} = require('../lib/git-hooks');
+
+const VERSION = require('../package.json').version;
+
+describe('git-hooks', () => {
+  let dir;
+
+  const write = (file, content) => {
+    fs.mkdirSync(path.dirname(path.join(dir, file)), { recursive: true });
+    fs.writeFileSync(path.join(dir, file), content);
+  };
+  const read = file => fs.readFileSync(path.join(dir, file), 'utf8');
+
+  beforeEach(() => {
+    dir = fs.mkdtempSync(path.join(os.tmpdir(), 'git-hooks-'));
+  });
+
+  afterEach(() => {
+    fs.rmSync(dir, { recursive: true, force: true });
+  });
+
+  describe('detectFramework', () => {
+    it('should prefer the hook manager a project already uses', () => {
+      write('package.json', JSON.stringify({ devDependencies: { husky: '^9.0.0' } }));
+      expect(detectFramework(dir).framework).toBe('husky');
+      write('.pre-commit-config.yaml', 'repos: []\n');
+      expect(detectFramework(dir).framework).toBe('pre-commit');
+    });
+
+    it('should fall back by project type', () => {
+      expect(detectFramework(dir)).toEqual({ framework: 'git', reason: 'No hook manager or package manifest' });
+      write('pyproject.toml', '[project]\nname = "shop"\n');
+      expect(detectFramework(dir)).toEqual({ framework: 'pre-commit', reason: 'Python project without a hook manager' });
+      write('package.json', '{}');
+      expect(detectFramework(dir)).toEqual({ framework: 'simple-git-hooks', reason: 'Node project without a hook manager' });
+      write('pnpm-lock.yaml', '');
+      expect(detectPackageManager(dir)).toBe('pnpm');
+    });
+  });
+
+  describe('preCommitConfig', () => {
+    it('should append to repos at the existing indentation', () => {
+      const existing = 'repos:\n    - repo: https://github.com/psf/black\n      rev: 24.1.0\n      hooks:\n        - id: black\n';
+      const change = preCommitConfig(existing, 'high');
+      expect(change.action).toBe('update');
+      expect(change.content).toBe(`${existing}    - repo: https://github.com/avifenesh/awesome-slash\n      rev: v${VERSION}\n      hooks:\n        - id: deslop\n          args: [--fail-on, high]\n`);
+      expect(preCommitConfig(change.content, 'high').action).toBe('unchanged');
+    });
+
+    it('should leave configs with keys after repos to the user', () => {
+      const change = preCommitConfig('repos:\n  - repo: local\nci:\n  autofix: true\n', 'critical');
+      expect(change.action).toBe('manual');
+      expect(change.snippet).toContain('  - repo: https://github.com/avifenesh/awesome-slash');
+    });
+  });
+
+  describe('planHooks', () => {
+    it('should chain simple-git-hooks and add a prepare script, keeping indentation', () => {
+      write('package.json', JSON.stringify({ name: 'shop', 'simple-git-hooks': { 'pre-commit': 'npx lint-staged' } }, null, 4));
+      const plan = planHooks(dir);
+
+      expect(plan.framework).toBe('simple-git-hooks');
+      expect(plan.commands).toEqual([
+        ['npm', 'install', '--save-dev', 'simple-git-hooks', 'awesome-slash'],
+        ['npx', 'simple-git-hooks']
+      ]);
+      applyHooks(dir, plan);
+      const pkg = JSON.parse(read('package.json'));
+      expect(pkg['simple-git-hooks']['pre-commit'])
+        .toBe('npx lint-staged && npx --no-install awesome-slash detect --staged --quick --compact --redact --fail-on critical');
+      expect(pkg.scripts).toEqual({ prepare: 'simple-git-hooks' });
+      expect(read('package.json')).toMatch(/^ {4}"name"/m);
+      expect(planHooks(dir).changes).toEqual([{ file: 'package.json', action: 'unchanged' }]);
+    });
+
+    it('should append to a husky hook and refuse hooks that exit early', () => {
+      write('.husky/pre-commit', 'npx lint-staged\n');
+      write('package.json', JSON.stringify({ devDependencies: { husky: '^9.0.0', 'awesome-slash': '^3.0.0' } }));
+      const plan = planHooks(dir, { failOn: 'high' });
+      expect(plan.commands).toEqual([]);
+      applyHooks(dir, plan);
+      expect(read('.husky/pre-commit')).toBe('npx lint-staged\nnpx --no-install awesome-slash detect --staged --quick --compact --redact --fail-on high\n');
+      expect(fs.statSync(path.join(dir, '.husky/pre-commit')).mode & 0o111).toBeTruthy();
+
+      write('.husky/pre-commit', 'npm test\nexit 0\n');
+      expect(planHooks(dir).changes[0].action).toBe('manual');
+    });
+
+    it('should create a plain git hook that runs detect.js directly', () => {
+      const run = jest.fn(() => '.git/hooks\n');
+      const plan = planHooks(dir, { run, detectPath: '/opt/awesome-slash/detect.js' });
+
+      expect(run).toHaveBeenCalledWith(dir, ['git', 'rev-parse', '--git-path', 'hooks']);
+      expect(plan.changes).toEqual([{
+        file: '.git/hooks/pre-commit',
+        mode: 0o755,
+        action: 'create',
+        content: '#!/bin/sh\nnode "/opt/awesome-slash/detect.js" --staged --quick --compact --redact --fail-on critical\n'
+      }]);
+      expect(planHooks(dir, { run: () => null }).error).toBe('Not a git repository');
+    });
+
+    it('should reject unknown severities and managers', () => {
+      expect(planHooks(dir, { failOn: 'blocker' }).error).toBe('--fail-on must be one of critical, high, medium, low');
+      expect(planHooks(dir, { framework: 'lefthook' }).error).toBe('Unknown hook manager lefthook (pre-commit, husky, simple-git-hooks, git)');
+    });
+  });
+
+  it('should render the plan with follow-up commands', () => {
+    write('requirements.txt', 'flask\n');
+    const output = renderPlan(planHooks(dir));
+    expect(output).toContain('**Manager**: pre-commit (Python project without a hook manager) | **Blocks on**: critical and above');
+    expect(output).toContain('| .pre-commit-config.yaml | create |');
+    expect(output).toContain('- `pre-commit install`');
+  });
+});
@@ -0,0 +1,95 @@
+/**
+ * Tests for lib/utils/git (worktree and submodule layout)
+ */
+
+const fs = require('fs');
+const os = require('os');
+const path = require('path');
+
+const git = require('../lib/utils/git');
+const { createIgnoreFilter } = require('../lib/utils/ignore');
+const runner = require('../lib/repo-map/runner');
+
+describe('git layout', () => {
+  let root;
+  const write = (file, content = '') => {
+    fs.mkdirSync(path.dirname(path.join(root, file)), { recursive: true });
+    fs.writeFileSync(path.join(root, file), content);
+  };
+
+  beforeEach(() => {
+    root = fs.realpathSync(fs.mkdtempSync(path.join(os.tmpdir(), 'git-layout-')));
+    // Main checkout with a linked worktree at wt/fix and a submodule at vendor-lib
+    write('.git/HEAD', 'ref: refs/heads/main\n');
+    write('.git/worktrees/fix/commondir', '../..\n');
+    write('.git/worktrees/fix/gitdir', `${path.join(root, 'wt', 'fix', '.git')}\n`);
+    write('.git/modules/vendor-lib/HEAD', '0123abcd\n');
+    write('wt/fix/.git', `gitdir: ${path.join(root, '.git', 'worktrees', 'fix')}\n`);
+    write('.gitmodules', '[submodule "lib"]\n\tpath = vendor-lib\n\turl = https://example.com/lib.git\n[submodule "docs"]\n\tpath = docs/site/\n\turl = ../site.git\n');
+    write('vendor-lib/.git', 'gitdir: ../.git/modules/vendor-lib\n');
+    write('src/app.js', 'export const app = 1;\n');
+    write('wt/fix/src/app.js', 'export const app = 2;\n');
+    write('vendor-lib/.gitignore', 'generated/\n');
+    write('vendor-lib/index.js', 'export const lib = 1;\n');
+    write('vendor-lib/generated/out.js', 'export const out = 1;\n');
+  });
+
+  afterEach(() => {
+    fs.rmSync(root, { recursive: true, force: true });
+  });
+
+  it('should resolve the git and common dirs of main and linked worktrees', () => {
+    expect(git.resolveGitDirs(path.join(root, 'src'))).toEqual({
+      root,
+      gitDir: path.join(root, '.git'),
+      commonDir: path.join(root, '.git'),
+      linked: false
+    });
+    expect(git.resolveGitDirs(path.join(root, 'wt', 'fix', 'src'))).toEqual({
+      root: path.join(root, 'wt', 'fix'),
+      gitDir: path.join(root, '.git', 'worktrees', 'fix'),
+      commonDir: path.join(root, '.git'),
+      linked: true
+    });
+    expect(git.resolveGitDirs(path.join(root, 'vendor-lib'))).toMatchObject({
+      gitDir: path.join(root, '.git', 'modules', 'vendor-lib'),
+      linked: false
+    });
+    expect(git.listWorktrees(path.join(root, '.git'))).toEqual([
+      { path: root, main: true },
+      { path: path.join(root, 'wt', 'fix'), main: false }
+    ]);
+  });
+
+  it('should list submodules and the checkouts nested under a directory', () => {
+    expect(git.listSubmodules(root)).toEqual([
+      { name: 'lib', path: 'vendor-lib', url: 'https://example.com/lib.git', initialized: true },
+      { name: 'docs', path: 'docs/site', url: '../site.git', initialized: false }
+    ]);
+    expect(git.nestedCheckouts(root)).toEqual({ worktrees: ['wt/fix'], submodules: ['docs/site', 'vendor-lib'] });
+    // From inside the linked worktree, the main checkout is not nested
+    expect(git.nestedCheckouts(path.join(root, 'wt', 'fix'))).toEqual({ worktrees: [], submodules: [] });
+  });
+
+  it('should skip other checkouts in walks and scan submodules only when asked', () => {
+    const isIgnored = createIgnoreFilter(root);
+    expect(isIgnored('wt/fix', true)).toBe(true);
+    expect(isIgnored('wt/fix/src/app.js')).toBe(true);
+    expect(isIgnored('vendor-lib/index.js')).toBe(true);
+    expect(isIgnored('wt/notes.md')).toBe(false);
+    expect(createIgnoreFilter(root, { nestedCheckouts: false })('vendor-lib/index.js')).toBe(false);
+
+    const relative = files => files.map(file => path.relative(root, file).split(path.sep).join('/')).sort();
+    expect(relative(runner.findFilesForLanguage(root, 'javascript'))).toEqual(['src/app.js']);
+    // The submodule's own .gitignore applies to its files
+    expect(relative(runner.findFilesForLanguage(root, 'javascript', { submodules: true }))).toEqual(['src/app.js', 'vendor-lib/index.js']);
+  });
+
+  it('should read git settings from the project config', () => {
+    expect(git.readSettings(root)).toEqual({ submodules: false, error: null });
+    write('.awesome-slash.json', JSON.stringify({ git: { submodules: true } }));
+    expect(git.readSettings(root)).toEqual({ submodules: true, error: null });
+    write('.awesome-slash.json', JSON.stringify({ git: { submodules: 'yes' } }));
+    expect(git.readSettings(root).error).toBe('.awesome-slash.json: git.submodules must be true or false');
+  });
+});
@@ -0,0 +1,137 @@
+/**
+ * Tests for the GitHub Actions reporter (annotations and job summaries)
+ */
+
+const fs = require('fs');
+const os = require('os');
+const path = require('path');
+
+const {
+  MAX_ANNOTATIONS_PER_LEVEL,
+  isGitHubActions,
+  formatAnnotation,
+  slopLevel,
+  slopAnnotations,
+  reviewAnnotations,
+  renderSummary,
+  reportSlop,
+  reportReview,
+  requestReviewers,
+  describeReviewers
+} = require('../lib/patterns/github-actions');
+
+describe('github-actions reporter', () => {
+  let dir;
+
+  beforeEach(() => {
+    dir = fs.mkdtempSync(path.join(os.tmpdir(), 'gha-'));
+  });
+
+  afterEach(() => {
+    fs.rmSync(dir, { recursive: true, force: true });
+  });
+
+  const env = summary => ({
+    GITHUB_ACTIONS: 'true',
+    GITHUB_STEP_SUMMARY: summary,
+    GITHUB_SERVER_URL: 'https://github.com',
+    GITHUB_REPOSITORY: 'acme/shop',
+    GITHUB_SHA: 'abc123'
+  });
+
+  it('should only turn on inside GitHub Actions', () => {
+    expect(isGitHubActions({ GITHUB_ACTIONS: 'true' })).toBe(true);
+    expect(isGitHubActions({ CI: 'true' })).toBe(false);
+  });
+
+  it('should escape workflow command messages and properties', () => {
+    expect(formatAnnotation({ level: 'warning', file: 'src/a,b.js', line: 3, endLine: 5, title: 'Review: security', message: '50% done\nnext: line' }))
+      .toBe('::warning file=src/a%2Cb.js,line=3,endLine=5,title=Review%3A security::50%25 done%0Anext: line');
+    expect(formatAnnotation({ level: 'notice', message: 'no location' })).toBe('::notice::no location');
+  });
+
+  it('should map slop severities through the severity gate', () => {
+    expect(slopLevel('critical')).toBe('error');
+    expect(slopLevel('high')).toBe('warning');
+    expect(slopLevel('high', { failOn: 'high' })).toBe('error');
+    expect(slopLevel('low', { failOn: 'high' })).toBe('notice');
+    expect(slopLevel('critical', { failOn: 'none', warnOn: 'none' })).toBe('notice');
+  });
+
+  it('should annotate slop findings with workspace paths and no secret content', () => {
+    const findings = [
+      { file: 'a.js', line: 2, patternName: 'console_debugging', severity: 'medium', description: 'Console.log left in code' },
+      { file: 'keys.js', line: 1, patternName: 'github_token', severity: 'critical', description: 'GitHub token', content: "const t = 'ghp_secret';" }
+    ];
+    const annotations = slopAnnotations(path.join(dir, 'src'), findings, { workspace: dir });
+    expect(annotations.map(formatAnnotation)).toEqual([
+      '::error file=src/keys.js,line=1,title=Slop%3A github_token::GitHub token',
+      '::warning file=src/a.js,line=2,title=Slop%3A console_debugging::Console.log left in code'
+    ]);
+  });
+
+  it('should annotate review findings with suggestions and skip false positives', () => {
+    const annotations = reviewAnnotations(dir, [
+      { file: 'src/db.js', line: 10, endLine: 12, category: 'security', severity: 'high', description: 'SQL built from input', suggestion: 'Use placeholders' },
+      { file: 'src/db.js', line: 20, category: 'style', severity: 'low', description: 'Naming', falsePositive: true }
+    ], { workspace: dir });
+    expect(annotations.map(formatAnnotation)).toEqual([
+      '::error file=src/db.js,line=10,endLine=12,title=Review%3A security::SQL built from input%0ASuggestion: Use placeholders'
+    ]);
+  });
+
+  it('should write a job summary and cap annotations per level', () => {
+    const summary = path.join(dir, 'summary.md');
+    const findings = Array.from({ length: MAX_ANNOTATIONS_PER_LEVEL + 2 }, (_, i) => ({
+      file: 'a.js', line: i + 1, patternName: 'console_debugging', severity: 'high', description: 'Debug | output'
+    }));
+    const lines = [];
+    const result = reportSlop(dir, { findings, baseline: { suppressed: 3 } }, {
+      gate: { failOn: 'high' }, workspace: dir, env: env(summary), write: line => lines.push(line)
+    });
+
+    expect(result).toEqual({ annotated: MAX_ANNOTATIONS_PER_LEVEL, hidden: 2, summary });
+    expect(lines).toHaveLength(MAX_ANNOTATIONS_PER_LEVEL);
+    const markdown = fs.readFileSync(summary, 'utf8');
+    expect(markdown).toContain('## Slop detection\n\n**Findings**: 12 | high 12');
+    expect(markdown).toContain('**Result**: failing, 12 findings at or above `high`');
+    expect(markdown).toContain('**Baseline**: 3 known findings suppressed');
+    expect(markdown).toContain('| high | [a.js:1](https://github.com/acme/shop/blob/abc123/a.js#L1) | `console_debugging` | Debug \\| output |');
+    expect(markdown).toContain('2 findings are listed here but not annotated');
+  });
+
+  it('should group findings by CODEOWNERS and request review from the owners of flagged files', () => {
+    fs.writeFileSync(path.join(dir, 'CODEOWNERS'), '* @lead\napi/ @sam @acme/api dev@acme.io\n');
+    const event = path.join(dir, 'event.json');
+    fs.writeFileSync(event, JSON.stringify({ pull_request: { number: 7, user: { login: 'sam' } } }));
+    const summary = path.join(dir, 'summary.md');
+    const findings = [
+      { file: 'api/users.js', line: 4, patternName: 'hardcoded_secrets', severity: 'critical', description: 'Secret' },
+      { file: 'web/app.js', line: 9, patternName: 'console_debugging', severity: 'low', description: 'Debug output' }
+    ];
+    const commands = [];
+    const result = reportSlop(dir, { findings }, {
+      gate: { failOn: 'high' }, workspace: dir, env: { ...env(summary), GITHUB_EVENT_PATH: event }, write: () => {},
+      requestReviewers: true, run: argv => commands.push(argv)
+    });
+
+    expect(fs.readFileSync(summary, 'utf8')).toContain('**By owner**: @acme/api 1, @lead 1, @sam 1, dev@acme.io 1');
+    // The author (sam) and email owners cannot be requested; @lead only owns a notice
+    expect(result.reviewers).toEqual({ success: true, users: [], teams: ['acme/api'] });
+    expect(commands).toEqual([['gh', 'pr', 'edit', '7', '--add-reviewer', 'acme/api', '--repo', 'acme/shop']]);
+    expect(describeReviewers(result.reviewers)).toBe('Requested review from @acme/api');
+
+    const failed = requestReviewers([{ level: 'error', owners: ['@ana'] }], {
+      env: { GITHUB_EVENT_PATH: event }, run: () => { throw Object.assign(new Error('exit 1'), { stderr: 'HTTP 403' }); }
+    });
+    expect(describeReviewers(failed)).toBe('Reviewers not requested: HTTP 403');
+    expect(requestReviewers([], { env: {} })).toEqual({ success: false, error: 'Not running for a pull request' });
+  });
+
+  it('should say when there is nothing to report', () => {
+    const summary = path.join(dir, 'summary.md');
+    expect(reportReview(dir, [], { workspace: dir, env: env(summary), write: () => {} }).annotated).toBe(0);
+    expect(fs.readFileSync(summary, 'utf8')).toBe('## Code review\n\nNo findings.\n\n');
+    expect(renderSummary('Slop detection', [], { notes: ['**Result**: passing'] })).toContain('No findings.\n\n**Result**: passing');
+  });
+});
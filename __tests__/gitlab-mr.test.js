@@ -0,0 +1,167 @@
+/**
+ * Tests for GitLab merge request discussion threads
+ */
+
+const fs = require('fs');
+const os = require('os');
+const path = require('path');
+
+const {
+  mergeRequestContext,
+  parseDiffLines,
+  slopThreads,
+  reviewThreads,
+  threadBody,
+  planThreads,
+  syncMergeRequest,
+  describeSync
+} = require('../lib/patterns/gitlab-mr');
+
+const DIFF = [
+  '@@ -1,3 +1,4 @@',
+  ' const total = 1;',
+  "+console.log('total', total);",
+  '-const old = 2;',
+  "+const token = 'ghp_x';",
+  ' module.exports = total;'
+].join('\n');
+
+describe('gitlab-mr', () => {
+  let dir;
+
+  beforeEach(() => {
+    dir = fs.mkdtempSync(path.join(os.tmpdir(), 'gitlab-mr-'));
+    fs.mkdirSync(path.join(dir, 'src'));
+    fs.writeFileSync(path.join(dir, 'src', 'cart.js'), "const total = 1;\nconsole.log('total', total);\nconst token = 'ghp_x';\nmodule.exports = total;\n");
+  });
+
+  afterEach(() => {
+    fs.rmSync(dir, { recursive: true, force: true });
+  });
+
+  const slopFindings = () => [
+    { file: 'src/cart.js', line: 2, patternName: 'console_debugging', severity: 'medium', description: 'Console debugging left in code' },
+    { file: 'src/cart.js', line: 3, patternName: 'github_token', severity: 'critical', description: 'GitHub token', content: "const token = 'ghp_x';" }
+  ];
+
+  // Fake GitLab API: serves the merge request and records every write
+  const gitlab = (discussions = []) => {
+    const calls = [];
+    const request = async (method, pathname, body) => {
+      if (method !== 'GET') {
+        calls.push([method, pathname, body]);
+        return {};
+      }
+      if (pathname === '/versions') return [{ base_commit_sha: 'base', start_commit_sha: 'start', head_commit_sha: 'head' }];
+      if (pathname.startsWith('/diffs')) return [{ old_path: 'src/cart.js', new_path: 'src/cart.js', diff: DIFF }];
+      if (pathname.startsWith('/discussions')) return discussions;
+      throw new Error(`unexpected ${pathname}`);
+    };
+    return { calls, request };
+  };
+
+  const thread = (id, finding, resolved = false) => ({ id, notes: [{ body: threadBody(finding), resolved }] });
+
+  it('should only turn on in merge request pipelines', () => {
+    const env = { GITLAB_CI: 'true', CI_PROJECT_ID: '42', CI_MERGE_REQUEST_IID: '7', CI_API_V4_URL: 'https://gitlab.acme.io/api/v4/', GLAB_TOKEN: 't', CI_PROJECT_DIR: '/builds/shop' };
+    expect(mergeRequestContext(env)).toEqual({ apiUrl: 'https://gitlab.acme.io/api/v4', projectId: '42', iid: '7', token: 't', workspace: '/builds/shop' });
+    expect(mergeRequestContext({ ...env, CI_MERGE_REQUEST_IID: undefined })).toBeNull();
+    expect(mergeRequestContext({ GITHUB_ACTIONS: 'true' })).toBeNull();
+  });
+
+  it('should map changed and context lines of a diff', () => {
+    expect(Array.from(parseDiffLines(DIFF))).toEqual([[1, 1], [2, null], [3, null], [4, 3]]);
+  });
+
+  it('should open threads on changed lines without secret content', async () => {
+    const { calls, request } = gitlab();
+    const threads = slopThreads(path.join(dir, 'src'), slopFindings().map(finding => ({ ...finding, file: 'cart.js' })), { workspace: dir });
+    const result = await syncMergeRequest(threads, { source: 'slop', request });
+
+    expect(result).toEqual({ success: true, created: 2, reopened: 0, resolved: 0, unchanged: 0, outsideDiff: 0, deferred: 0 });
+    expect(calls.map(([method, pathname]) => `${method} ${pathname}`)).toEqual(['POST /discussions', 'POST /discussions']);
+    const [, , token] = calls[0];
+    expect(token.position).toEqual({
+      position_type: 'text', base_sha: 'base', start_sha: 'start', head_sha: 'head',
+      old_path: 'src/cart.js', new_path: 'src/cart.js', new_line: 3
+    });
+    expect(token.body).toMatch(/^\*\*Slop\*\* `github_token` \(critical\)/);
+    expect(token.body).toMatch(/<!-- awesome-slash:finding fingerprint=[0-9a-f]{16} source=slop -->$/);
+    expect(JSON.stringify(calls)).not.toContain('ghp_x');
+  });
+
+  it('should skip existing threads, reopen returning findings, and resolve fixed ones', async () => {
+    const [debug, token] = slopThreads(dir, slopFindings(), { workspace: dir });
+    const gone = { ...debug, fingerprint: 'f'.repeat(16) };
+    const review = { ...gone, source: 'review' };
+    const { calls, request } = gitlab([
+      thread('d1', debug),
+      thread('d2', token, true),
+      thread('d3', gone),
+      thread('d4', review),
+      { id: 'd5', notes: [{ body: 'A human comment' }] }
+    ]);
+
+    const result = await syncMergeRequest([debug, token], { source: 'slop', request });
+    expect(describeSync(result)).toBe('Merge request threads: 0 opened, 1 reopened, 1 resolved, 1 unchanged');
+    expect(calls).toEqual([
+      ['PUT', '/discussions/d2', { resolved: false }],
+      ['POST', '/discussions/d3/notes', { body: 'No longer reported; resolving.' }],
+      ['PUT', '/discussions/d3', { resolved: true }]
+    ]);
+  });
+
+  it('should name CODEOWNERS on new threads and request their review once', async () => {
+    fs.writeFileSync(path.join(dir, 'CODEOWNERS'), '* @lead\nsrc/ @ana @acme/web\n');
+    const [debug, token] = slopThreads(dir, slopFindings(), { workspace: dir });
+    expect(token.owners).toEqual(['@ana', '@acme/web']);
+    expect(threadBody(token)).toContain('**Owners**: `@ana`, `@acme/web`');
+
+    const { calls, request } = gitlab([thread('d1', debug)]);
+    const result = await syncMergeRequest([debug, token], { source: 'slop', request, requestReviewers: true });
+    expect(result.reviewers).toEqual(['ana']);
+    expect(calls[calls.length - 1]).toEqual(['POST', '/notes', { body: '/assign_reviewer @ana' }]);
+    expect(describeSync(result)).toBe('Merge request threads: 1 opened, 0 reopened, 0 resolved, 1 unchanged, reviewers @ana requested');
+
+    // Nothing new to post: nobody is asked again
+    const rerun = gitlab([thread('d1', debug), thread('d2', token)]);
+    expect((await syncMergeRequest([debug, token], { source: 'slop', request: rerun.request, requestReviewers: true })).reviewers).toEqual([]);
+    expect(rerun.calls).toEqual([]);
+  });
+
+  it('should count findings outside the diff, below the severity floor, or over the limit', () => {
+    const mergeRequest = { refs: {}, files: new Map([['src/cart.js', { oldPath: 'src/cart.js', lines: parseDiffLines(DIFF) }]]), threads: [] };
+    const threads = slopThreads(dir, [
+      ...slopFindings(),
+      { file: 'src/cart.js', line: 4, patternName: 'placeholder_text', severity: 'low', description: 'Placeholder' },
+      { file: 'src/other.js', line: 1, patternName: 'console_debugging', severity: 'high', description: 'Console debugging left in code' }
+    ], { workspace: dir });
+
+    const plan = planThreads(threads, mergeRequest, { source: 'slop', limit: 1 });
+    expect(plan.create.map(entry => entry.finding.title)).toEqual(['github_token']);
+    expect([plan.outsideDiff, plan.deferred]).toEqual([1, 1]);
+    expect(planThreads(threads, mergeRequest, { source: 'slop', minSeverity: 'low' }).create).toHaveLength(3);
+  });
+
+  it('should post review findings with suggestions and keep old_line on context lines', async () => {
+    const { calls, request } = gitlab();
+    const threads = reviewThreads(dir, [
+      { file: 'src/cart.js', line: 4, category: 'maintainability', severity: 'high', description: 'Exported mutable total', suggestion: 'Export a getter' },
+      { file: 'src/cart.js', line: 1, category: 'style', severity: 'high', description: 'Naming', falsePositive: true }
+    ], { workspace: dir });
+    await syncMergeRequest(threads, { source: 'review', request });
+
+    expect(calls).toHaveLength(1);
+    expect(calls[0][2].position).toEqual(expect.objectContaining({ new_line: 4, old_line: 3 }));
+    expect(calls[0][2].body).toContain('Exported mutable total\n\n**Suggestion**: Export a getter');
+    expect(calls[0][2].body).toContain('source=review -->');
+  });
+
+  it('should explain why threads were skipped', async () => {
+    const context = { apiUrl: 'https://gitlab.com/api/v4', projectId: '1', iid: '2', token: null, workspace: dir };
+    expect(describeSync(await syncMergeRequest([], { source: 'slop', context })))
+      .toBe('Merge request threads skipped: Set GITLAB_TOKEN or GLAB_TOKEN (api scope) to post merge request threads; CI_JOB_TOKEN cannot');
+    const failing = async () => { throw new Error('GitLab GET /versions returned 401'); };
+    expect(await syncMergeRequest([], { source: 'slop', request: failing })).toEqual({ success: false, error: 'GitLab GET /versions returned 401' });
+  });
+});
@@ -0,0 +1,135 @@
+/**
+ * Tests for finding history and trends
+ */
+
+const fs = require('fs');
+const os = require('os');
+const path = require('path');
+
+const {
+  readSettings,
+  recordRun,
+  computeTrend,
+  trends,
+  renderTrends
+} = require('../lib/history');
+
+const SHA = 'abc1234def5678abc1234def5678abc1234def56';
+
+describe('history', () => {
+  let dir;
+
+  beforeEach(() => {
+    dir = fs.mkdtempSync(path.join(os.tmpdir(), 'history-'));
+  });
+
+  afterEach(() => {
+    fs.rmSync(dir, { recursive: true, force: true });
+  });
+
+  const write = (file, content) => {
+    fs.mkdirSync(path.dirname(path.join(dir, file)), { recursive: true });
+    fs.writeFileSync(path.join(dir, file), typeof content === 'string' ? content : JSON.stringify(content));
+  };
+  // Stands in for git: the temporary directory is the repository root
+  const git = ({ dirty = false } = {}) => (cwd, argv) => {
+    const command = argv.slice(1).join(' ');
+    if (command === 'rev-parse --show-toplevel') return `${dir}\n`;
+    if (command === 'rev-parse HEAD') return `${SHA}\n`;
+    if (command === 'rev-parse --abbrev-ref HEAD') return 'main\n';
+    if (command.startsWith('status')) return dirty ? ' M src/app.js\n' : '';
+    return null;
+  };
+  const finding = (patternName, severity) => ({ patternName, severity, file: 'src/app.js', line: 1 });
+
+  it('should validate settings', () => {
+    expect(readSettings(dir)).toMatchObject({ enabled: true, maxRuns: 200 });
+    write('.awesome-slash.json', { history: { enabled: false, maxRuns: 5 } });
+    expect(readSettings(dir)).toMatchObject({ enabled: false, maxRuns: 5 });
+    write('.awesome-slash.json', { history: { maxRuns: 0 } });
+    expect(readSettings(dir).error).toBe('.awesome-slash.json: history.maxRuns must be a positive integer');
+    write('.awesome-slash.json', { history: { enabled: 'yes' } });
+    expect(readSettings(dir).error).toBe('.awesome-slash.json: history.enabled must be true or false');
+  });
+
+  it('should record counts with the commit and prune old runs', () => {
+    write('.awesome-slash.json', { history: { maxRuns: 2 } });
+    fs.mkdirSync(path.join(dir, 'src'));
+    const findings = [
+      finding('console_debugging', 'medium'),
+      finding('console_debugging', 'medium'),
+      finding('placeholder_text', 'high')
+    ];
+
+    const first = recordRun(path.join(dir, 'src'), 'slop', findings, { mode: 'normal', baselined: 4, timestamp: '2026-10-01T09:30:00.123Z', run: git() });
+    expect(first.recorded).toBe(true);
+    expect(first.file).toBe('.awesome-slash/history/slop/2026-10-01T09-30-00Z-abc1234.json');
+    expect(JSON.parse(fs.readFileSync(path.join(dir, first.file), 'utf8'))).toMatchObject({
+      scanner: 'slop',
+      scope: 'src',
+      mode: 'normal',
+      commit: SHA,
+      branch: 'main',
+      dirty: false,
+      total: 3,
+      bySeverity: { critical: 0, high: 1, medium: 2, low: 0 },
+      byCategory: { console_debugging: 2, placeholder_text: 1 },
+      baselined: 4
+    });
+
+    const review = recordRun(dir, 'review', [
+      { category: 'security', severity: 'high' },
+      { pass: 'performance', severity: 'low', falsePositive: true }
+    ], { timestamp: '2026-10-01T10:00:00Z', run: git({ dirty: true }) });
+    expect(review.run).toMatchObject({ scope: '.', dirty: true, total: 1, byCategory: { security: 1 } });
+
+    recordRun(dir, 'slop', [], { timestamp: '2026-10-02T09:30:00Z', run: git() });
+    const third = recordRun(dir, 'slop', [], { timestamp: '2026-10-03T09:30:00Z', run: git() });
+    expect(third.pruned).toBe(1);
+    expect(fs.existsSync(path.join(dir, first.file))).toBe(false);
+
+    expect(recordRun(dir, 'lint', [], { run: git() }).error).toBe('Unknown scanner "lint". Use slop or review.');
+    write('.awesome-slash.json', { history: { enabled: false } });
+    expect(recordRun(dir, 'slop', findings, { run: git() })).toEqual({ recorded: false, reason: 'disabled' });
+  });
+
+  it('should report the direction and movers of each series', () => {
+    const entry = (day, total, byCategory, extra = {}) => ({
+      scanner: 'slop',
+      scope: '.',
+      mode: 'normal',
+      timestamp: `2026-10-0${day}T09:00:00Z`,
+      commit: SHA,
+      total,
+      bySeverity: { medium: total },
+      byCategory,
+      ...extra
+    });
+    const runs = [
+      entry(1, 10, { console_debugging: 6, placeholder_text: 4 }),
+      entry(2, 12, { console_debugging: 8, placeholder_text: 4 }),
+      entry(3, 8, { console_debugging: 4, placeholder_text: 4 }),
+      entry(4, 6, { console_debugging: 1, placeholder_text: 3, magic_numbers: 2 }, { baselined: 3 })
+    ];
+    const trend = computeTrend(runs);
+    expect(trend).toMatchObject({ change: -4, percent: -40, direction: 'falling', bySeverity: { medium: -4 } });
+    expect(trend.rising).toEqual([{ category: 'magic_numbers', change: 2 }]);
+    expect(trend.falling).toEqual([{ category: 'console_debugging', change: -5 }, { category: 'placeholder_text', change: -1 }]);
+    expect(computeTrend([entry(1, 5, {}), entry(2, 6, {}), entry(3, 5, {})]).direction).toBe('flat');
+
+    // The newest run sits under the legacy spelling, which is still read
+    runs.forEach((run, index) => write(`${index === runs.length - 1 ? '.awsome-slash' : '.awesome-slash'}/history/slop/run-${index}.json`, run));
+    write('.awesome-slash/history/slop/thorough.json', entry(5, 20, { console_debugging: 20 }, { mode: 'thorough' }));
+    write('.awesome-slash/history/slop/broken.json', '{');
+    const report = trends(dir, { last: 3, run: git() });
+    expect(report.series.map(series => [series.mode, series.runs.length, series.total])).toEqual([['thorough', 1, 1], ['normal', 3, 4]]);
+
+    const rendered = renderTrends(report);
+    expect(rendered).toContain('### Slop (normal)');
+    expect(rendered).toContain('Last 3 runs, 2026-10-02 to 2026-10-04');
+    expect(rendered).toContain('**Trend**: falling █▃▁, 12 → 6 findings (-6, -50%)');
+    expect(rendered).toContain('| 2026-10-04 | `abc1234` | 6 | 0 | 0 | 6 | 0 | 3 |');
+    expect(rendered).toContain('1 run, 2026-10-05 to 2026-10-05');
+    expect(renderTrends(trends(dir, { scanners: ['review'], run: git() }))).toContain('No runs recorded yet');
+  });
+});
@@ -0,0 +1,155 @@
+/**
+ * Tests for shared ignore rules
+ */
+
+const fs = require('fs');
+const os = require('os');
+const path = require('path');
+
+const {
+  createIgnoreFilter,
+  parseIgnoreContent,
+  matchPatterns,
+  DEFAULT_EXCLUDE_DIRS
+} = require('../lib/utils/ignore');
+const { countSourceFiles } = require('../lib/patterns/slop-analyzers');
+const { findFilesForLanguage } = require('../lib/repo-map/runner');
+
+describe('ignore', () => {
+  let tempDir;
+
+  const write = (relativePath, content = '') => {
+    const fullPath = path.join(tempDir, relativePath);
+    fs.mkdirSync(path.dirname(fullPath), { recursive: true });
+    fs.writeFileSync(fullPath, content);
+  };
+
+  beforeEach(() => {
+    tempDir = fs.mkdtempSync(path.join(os.tmpdir(), 'ignore-'));
+  });
+
+  afterEach(() => {
+    fs.rmSync(tempDir, { recursive: true, force: true });
+  });
+
+  describe('parseIgnoreContent', () => {
+    it('accepts file content or a glob list', () => {
+      expect(parseIgnoreContent('# comment\n\n*.log\n')).toHaveLength(1);
+      expect(parseIgnoreContent(['*.log', 'tmp/'])).toHaveLength(2);
+    });
+
+    it('applies the last matching pattern', () => {
+      const patterns = parseIgnoreContent('*.log\n!keep.log');
+      expect(matchPatterns(patterns, 'debug.log')).toBe(true);
+      expect(matchPatterns(patterns, 'keep.log')).toBe(false);
+    });
+  });
+
+  describe('createIgnoreFilter', () => {
+    it('always excludes built-in directories', () => {
+      const isIgnored = createIgnoreFilter(tempDir);
+      expect(DEFAULT_EXCLUDE_DIRS).toContain('node_modules');
+      expect(isIgnored('node_modules', true)).toBe(true);
+      expect(isIgnored('packages/app/dist/index.js')).toBe(true);
+      expect(isIgnored('.awesome-slash/history/slop/run.json')).toBe(true);
+      expect(isIgnored('.awsome-slash/cache/osv/lodash.json')).toBe(true);
+      expect(isIgnored('src/index.js')).toBe(false);
+    });
+
+    it('combines .gitignore, .awesome-slashignore, and caller globs', () => {
+      write('.gitignore', 'generated/\n');
+      write('.awesome-slashignore', 'fixtures/\n');
+      const isIgnored = createIgnoreFilter(tempDir, { patterns: ['*.min.js'] });
+
+      expect(isIgnored('src/generated', true)).toBe(true);
+      expect(isIgnored('test/fixtures', true)).toBe(true);
+      expect(isIgnored('lib/app.min.js')).toBe(true);
+      expect(isIgnored('lib/app.js')).toBe(false);
+    });
+
+    it('reads the legacy .awsome-slashignore name', () => {
+      write('.awsome-slashignore', 'legacy/\n');
+      expect(createIgnoreFilter(tempDir)('legacy', true)).toBe(true);
+    });
+
+    it('ignores files inside ignored directories', () => {
+      write('.gitignore', 'generated/\n!generated/keep.js\n');
+      const isIgnored = createIgnoreFilter(tempDir);
+      expect(isIgnored('src/generated/types.js')).toBe(true);
+      expect(isIgnored('generated/keep.js')).toBe(true);
+    });
+
+    it('ignores only the contents of a trailing /**', () => {
+      write('.gitignore', 'foo/**\n!foo/keep\n');
+      const isIgnored = createIgnoreFilter(tempDir);
+      expect(isIgnored('foo', true)).toBe(false);
+      expect(isIgnored('foo/x')).toBe(true);
+      expect(isIgnored('foo/bar/x.js')).toBe(true);
+      expect(isIgnored('foo/keep')).toBe(false);
+    });
+
+    it('skips .gitignore when respectGitignore is false', () => {
+      write('.gitignore', 'generated/\n');
+      write('.awesome-slashignore', 'fixtures/\n');
+      const isIgnored = createIgnoreFilter(tempDir, { respectGitignore: false });
+      expect(isIgnored('generated', true)).toBe(false);
+      expect(isIgnored('fixtures', true)).toBe(true);
+    });
+
+    it('reads nested .gitignore files relative to their directory', () => {
+      write('.gitignore', '*.log\n');
+      write('packages/api/.gitignore', '/tmp/\n!keep.log\n');
+      write('packages/api/src/.gitignore', 'secret.json\n');
+      const isIgnored = createIgnoreFilter(tempDir, { patterns: ['packages/api/src/config.json'] });
+
+      expect(isIgnored('packages/api/tmp', true)).toBe(true);
+      expect(isIgnored('tmp', true)).toBe(false);
+      expect(isIgnored('packages/api/src/tmp', true)).toBe(false);
+      expect(isIgnored('packages/api/keep.log')).toBe(false);
+      expect(isIgnored('packages/web/debug.log')).toBe(true);
+      expect(isIgnored('packages/api/src/secret.json')).toBe(true);
+      expect(isIgnored('secret.json')).toBe(false);
+      expect(isIgnored('packages/api/src/config.json')).toBe(true);
+      expect(createIgnoreFilter(tempDir, { respectGitignore: false })('packages/api/tmp', true)).toBe(false);
+    });
+
+    it('translates bracket classes', () => {
+      write('.gitignore', '*.py[co]\nlog[0-9].txt\n[!.]*.bak\n');
+      const isIgnored = createIgnoreFilter(tempDir);
+      expect(isIgnored('app/models.pyc')).toBe(true);
+      expect(isIgnored('app/models.pyo')).toBe(true);
+      expect(isIgnored('app/models.py')).toBe(false);
+      expect(isIgnored('log7.txt')).toBe(true);
+      expect(isIgnored('logs.txt')).toBe(false);
+      expect(isIgnored('notes.bak')).toBe(true);
+      expect(isIgnored('.env.bak')).toBe(false);
+    });
+
+    it('honors custom excludeDirs', () => {
+      const isIgnored = createIgnoreFilter(tempDir, { excludeDirs: ['.venv'] });
+      expect(isIgnored('.venv/lib/site.py')).toBe(true);
+      expect(isIgnored('node_modules/pkg/index.js')).toBe(false);
+    });
+  });
+
+  describe('walkers', () => {
+    beforeEach(() => {
+      write('src/index.js', 'module.exports = {};\n');
+      write('src/vendored/lib.js', 'module.exports = {};\n');
+      write('node_modules/pkg/index.js', 'module.exports = {};\n');
+      write('dist/bundle.js', 'module.exports = {};\n');
+      write('.awesome-slashignore', 'vendored/\n');
+    });
+
+    it('slop scanner skips ignored paths', () => {
+      write('src/api.generated.js', 'module.exports = {};\n');
+      const { files } = countSourceFiles(tempDir, { ignore: ['*.generated.js'] });
+      expect(files).toEqual([path.join('src', 'index.js')]);
+    });
+
+    it('repo-map scanner skips ignored paths', () => {
+      const files = findFilesForLanguage(tempDir, 'javascript').map(file => path.relative(tempDir, file));
+      expect(files).toEqual([path.join('src', 'index.js')]);
+    });
+  });
+});
@@ -0,0 +1,214 @@
+/**
+ * Tests for filing scanner findings as tracker issues
+ */
+
+const fs = require('fs');
+const os = require('os');
+const path = require('path');
+
+const {
+  parseFindings,
+  dedupeFindings,
+  parseCodeowners,
+  ownersOf,
+  readSettings,
+  issueBody,
+  planIssues,
+  actionCommands,
+  applyPlan,
+  renderPlan
+} = require('../lib/issues');
+
+const GITHUB = { host: 'github', url: 'https://github.com/acme/shop' };
+const SHA = 'a'.repeat(40);
+
+describe('issues', () => {
+  let dir;
+
+  const write = (file, content) => {
+    fs.mkdirSync(path.dirname(path.join(dir, file)), { recursive: true });
+    fs.writeFileSync(path.join(dir, file), content);
+  };
+
+  beforeEach(() => {
+    dir = fs.mkdtempSync(path.join(os.tmpdir(), 'issues-'));
+    write('src/cart.js', "const total = 1;\nconsole.log('total', total);\nconst token = 'ghp_x';\n");
+  });
+
+  afterEach(() => {
+    fs.rmSync(dir, { recursive: true, force: true });
+  });
+
+  const slopOutput = () => ({
+    findings: [
+      { file: 'src/cart.js', line: 2, patternName: 'console_debugging', severity: 'medium', description: 'Console debugging left in code' },
+      { file: 'src/cart.js', line: 3, patternName: 'github_token', severity: 'critical', description: 'GitHub token', content: "const token = 'ghp_x';" }
+    ],
+    summary: { totalFindings: 2 }
+  });
+
+  // Fake forge: answers the issue list with `existing` and records every other command
+  const forge = (existing = [], labels = []) => {
+    const calls = [];
+    const runCommand = (basePath, argv) => {
+      calls.push(argv);
+      if (argv[1] === 'issue' && argv[2] === 'list') return JSON.stringify(existing);
+      if (argv[1] === 'label' && argv[2] === 'list') return JSON.stringify(labels.map(name => ({ name })));
+      if (argv[1] === 'issue' && argv[2] === 'create') return `https://github.com/acme/shop/issues/${calls.length}\n`;
+      return '';
+    };
+    return { calls, run: runCommand };
+  };
+
+  describe('parseFindings', () => {
+    it('should read slop output and keep secrets out of issue text', () => {
+      const findings = parseFindings(dir, slopOutput());
+      expect(findings.map(finding => [finding.source, finding.rule, finding.severity])).toEqual([
+        ['slop', 'console_debugging', 'medium'],
+        ['security', 'github_token', 'critical']
+      ]);
+      expect(findings[0].fingerprint).toMatch(/^[0-9a-f]{16}$/);
+      expect(JSON.stringify(findings)).not.toContain('ghp_x');
+    });
+
+    it('should keep fingerprints stable when code moves', () => {
+      const before = parseFindings(dir, slopOutput())[0].fingerprint;
+      write('src/cart.js', "// header\n\nconst total = 1;\nconsole.log('total', total);\n");
+      const moved = slopOutput();
+      moved.findings[0].line = 4;
+      expect(parseFindings(dir, moved)[0].fingerprint).toBe(before);
+    });
+
+    it('should read deps-audit reports, review queues, and SARIF', () => {
+      const deps = parseFindings(dir, {
+        success: true,
+        managers: ['npm'],
+        vulnerabilities: [{ ecosystem: 'npm', name: 'lodash', version: '4.17.15', id: 'GHSA-1', aliases: ['CVE-2020-8203'], summary: 'Prototype pollution', severity: 'high', fixed: '4.17.19' }],
+        unused: [{ ecosystem: 'npm', name: 'left-pad', file: 'package.json', dev: false, confidence: 'high' }]
+      });
+      expect(deps.map(finding => [finding.rule, finding.severity, finding.title])).toEqual([
+        ['GHSA-1', 'high', 'lodash: GHSA-1 Prototype pollution'],
+        ['unused-dependency', 'low', 'Unused dependency left-pad']
+      ]);
+      expect(deps[0].suggestion).toBe('Upgrade lodash to 4.17.19 or later.');
+
+      const review = parseFindings(dir, [
+        { file: 'src/cart.js', line: 1, category: 'security', severity: 'high', description: 'SQL built from input' },
+        { file: 'src/cart.js', line: 2, category: 'performance', severity: 'low', description: 'Loop', falsePositive: true }
+      ]);
+      expect(review.map(finding => [finding.source, finding.rule])).toEqual([['security', 'review/security']]);
+
+      const sarif = parseFindings(dir, {
+        runs: [{
+          tool: { driver: { name: 'Semgrep', rules: [{ id: 'xss', properties: { tags: ['security'] } }] } },
+          results: [{ ruleId: 'xss', level: 'error', message: { text: 'Unescaped output' }, locations: [{ physicalLocation: { artifactLocation: { uri: 'src/cart.js' }, region: { startLine: 2 } } }] }]
+        }]
+      });
+      expect(sarif).toEqual([expect.objectContaining({ source: 'security', tool: 'Semgrep', rule: 'xss', severity: 'high', file: 'src/cart.js', line: 2 })]);
+
+      expect(parseFindings(dir, { something: 'else' })).toBeNull();
+    });
+
+    it('should merge findings sharing a fingerprint', () => {
+      const findings = parseFindings(dir, slopOutput());
+      const unique = dedupeFindings([...findings, { ...findings[0] }]);
+      expect(unique.map(finding => [finding.rule, finding.occurrences])).toEqual([['github_token', 1], ['console_debugging', 2]]);
+    });
+  });
+
+  describe('CODEOWNERS', () => {
+    it('should pick the last matching rule and skip teams and emails', () => {
+      const rules = parseCodeowners([
+        '# owners',
+        '* @lead',
+        '[Frontend]',
+        'src/ @ana @acme/web dev@acme.io',
+        '*.md @docs-writer',
+        '/src/payments/ @sam'
+      ].join('\n'));
+      expect(ownersOf(rules, 'src/cart.js')).toEqual(['ana']);
+      expect(ownersOf(rules, 'src/payments/charge.js')).toEqual(['sam']);
+      expect(ownersOf(rules, 'lib/README.md')).toEqual(['docs-writer']);
+      expect(ownersOf(rules, 'package.json')).toEqual(['lead']);
+      expect(ownersOf(rules, null)).toEqual(['lead']);
+    });
+  });
+
+  describe('readSettings', () => {
+    it('should validate the issues config', () => {
+      write('.awesome-slash.json', JSON.stringify({ issues: { labels: 'triage', minSeverity: 'high', limit: 5, assign: false } }));
+      expect(readSettings(dir)).toEqual({ labels: ['triage'], minSeverity: 'high', limit: 5, assign: false, error: null });
+
+      write('.awesome-slash.json', JSON.stringify({ issues: { minSeverity: 'urgent' } }));
+      expect(readSettings(dir).error).toBe('.awesome-slash.json: issues.minSeverity must be one of critical, high, medium, low');
+    });
+  });
+
+  describe('planIssues', () => {
+    it('should create issues with labels and CODEOWNERS assignees', () => {
+      write('.github/CODEOWNERS', 'src/ @ana\n');
+      const { run } = forge();
+      const plan = planIssues(dir, parseFindings(dir, slopOutput()), { repository: GITHUB, commit: SHA, run });
+      expect(plan.success).toBe(true);
+      expect(plan.codeowners).toBe('.github/CODEOWNERS');
+      expect(plan.actions.map(action => [action.action, action.title, action.assignees])).toEqual([
+        ['create', 'Security: GitHub token in src/cart.js', ['ana']],
+        ['create', 'Slop: Console debugging left in code in src/cart.js', ['ana']]
+      ]);
+      expect(plan.actions[0].labels).toEqual(['awesome-slash', 'security', 'severity:critical']);
+      expect(plan.actions[1].body).toContain(`- **Location**: [src/cart.js:2](https://github.com/acme/shop/blob/${SHA}/src/cart.js#L2)`);
+      expect(plan.actions[1].body).toMatch(/<!-- awesome-slash:issue fingerprint=[0-9a-f]{16} source=slop -->$/);
+    });
+
+    it('should update matching issues instead of duplicating them', () => {
+      const findings = parseFindings(dir, slopOutput());
+      const [token, debug] = dedupeFindings(findings);
+      const existing = [
+        { number: 7, title: 'Security: GitHub token in src/cart.js', body: issueBody(token, GITHUB, 'b'.repeat(40)), state: 'OPEN', labels: [{ name: 'awesome-slash' }, { name: 'security' }, { name: 'severity:critical' }], url: 'https://github.com/acme/shop/issues/7' },
+        { number: 8, title: 'old title', body: issueBody(debug, GITHUB, SHA), state: 'CLOSED', labels: [], url: 'https://github.com/acme/shop/issues/8' },
+        { number: 9, title: 'Slop: gone', body: issueBody({ ...debug, fingerprint: 'f'.repeat(16) }, GITHUB, SHA), state: 'OPEN', labels: [], url: 'https://github.com/acme/shop/issues/9' },
+        { number: 10, title: 'Unrelated', body: 'no marker', state: 'OPEN', labels: [], url: 'https://github.com/acme/shop/issues/10' }
+      ];
+      const plan = planIssues(dir, findings, { repository: GITHUB, commit: SHA, run: forge(existing).run });
+      expect(plan.actions.map(action => [action.action, action.number])).toEqual([['unchanged', 7], ['closed', 8], ['resolved', 9]]);
+
+      const reopened = planIssues(dir, findings, { repository: GITHUB, commit: SHA, run: forge(existing).run, reopen: true, closeResolved: true });
+      expect(reopened.actions.map(action => action.action)).toEqual(['unchanged', 'reopen', 'close']);
+      expect(actionCommands(GITHUB, reopened.actions[1])[0]).toEqual(['gh', 'issue', 'reopen', '8']);
+      expect(renderPlan(reopened)).toContain('| reopen | medium | [#8](https://github.com/acme/shop/issues/8) Slop: Console debugging left in code in src/cart.js | - |');
+    });
+
+    it('should hold back low findings and new issues over the limit', () => {
+      const findings = parseFindings(dir, slopOutput());
+      const plan = planIssues(dir, findings, { repository: GITHUB, commit: SHA, run: forge().run, minSeverity: 'high' });
+      expect(plan.actions.map(action => action.title)).toEqual(['Security: GitHub token in src/cart.js']);
+
+      const limited = planIssues(dir, findings, { repository: GITHUB, commit: SHA, run: forge().run, limit: 1 });
+      expect(limited.actions.map(action => action.action)).toEqual(['create', 'deferred']);
+    });
+
+    it('should fail without a supported forge or a working CLI', () => {
+      expect(planIssues(dir, [], { repository: null }).error).toMatch(/GitHub or GitLab/);
+      expect(planIssues(dir, [], { repository: GITHUB, run: () => null }).error).toBe('Could not list issues; check that gh is installed and authenticated');
+    });
+  });
+
+  describe('applyPlan', () => {
+    it('should create missing labels, then file issues', () => {
+      const { calls, run } = forge([], ['awesome-slash']);
+      const plan = planIssues(dir, parseFindings(dir, slopOutput()), { repository: GITHUB, commit: SHA, run, assign: false });
+      const result = applyPlan(dir, plan, { run });
+      expect(result.success).toBe(true);
+      expect(result.applied.map(entry => entry.action)).toEqual(['create', 'create']);
+      expect(result.applied[0].url).toMatch(/^https:\/\/github\.com\/acme\/shop\/issues\/\d+$/);
+      expect(calls.filter(argv => argv[2] === 'create' && argv[1] === 'label').map(argv => argv[3])).toEqual(['security', 'severity:critical', 'severity:medium', 'slop']);
+    });
+
+    it('should build GitLab commands', () => {
+      const gitlab = { host: 'gitlab', url: 'https://gitlab.com/acme/shop' };
+      const create = { action: 'create', title: 'T', body: 'B', labels: ['awesome-slash', 'slop'], assignees: ['ana', 'sam'] };
+      expect(actionCommands(gitlab, create)).toEqual([['glab', 'issue', 'create', '--title', 'T', '--description', 'B', '--label', 'awesome-slash,slop', '--assignee', 'ana,sam']]);
+      expect(actionCommands(gitlab, { action: 'close', number: 4 }).map(argv => argv.slice(0, 4))).toEqual([['glab', 'issue', 'note', '4'], ['glab', 'issue', 'close', '4']]);
+    });
+  });
+});
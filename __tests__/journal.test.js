@@ -0,0 +1,189 @@
+/**
+ * Tests for lib/journal (dry runs and rollback)
+ */
+
+const fs = require('fs');
+const os = require('os');
+const path = require('path');
+
+const journal = require('../lib/journal');
+const release = require('../lib/release');
+
+describe('journal', () => {
+  let root;
+  let savedStateDir;
+  let calls;
+  let head;
+  let failing;
+
+  // Fake git/gh: records calls, prints a URL for created issues
+  function fakeRun(_basePath, argv) {
+    calls.push(argv.join(' '));
+    if (failing.includes(argv.join(' '))) return null;
+    if (argv[0] === 'git' && argv[1] === 'rev-parse') return `${head}\n`;
+    if (argv.join(' ').startsWith('gh issue create')) return 'https://github.com/acme/shop/issues/7\n';
+    if (argv[0] === 'fail') return null;
+    return '';
+  }
+
+  const read = file => fs.readFileSync(path.join(root, file), 'utf8');
+
+  beforeEach(() => {
+    root = fs.mkdtempSync(path.join(os.tmpdir(), 'journal-'));
+    savedStateDir = process.env.AI_STATE_DIR;
+    process.env.AI_STATE_DIR = '.state';
+    calls = [];
+    failing = [];
+    head = 'abc1234def';
+    fs.writeFileSync(path.join(root, 'package.json'), '{\n  "name": "shop",\n  "version": "1.0.0"\n}\n');
+  });
+
+  afterEach(() => {
+    if (savedStateDir === undefined) delete process.env.AI_STATE_DIR;
+    else process.env.AI_STATE_DIR = savedStateDir;
+    fs.rmSync(root, { recursive: true, force: true });
+  });
+
+  it('should record a dry run as diffs and commands without changing anything', () => {
+    const tx = journal.begin(root, 'release', { dryRun: true, run: fakeRun });
+    release.applyRelease(root, { files: [{ file: 'package.json' }], version: '1.1.0' }, { projectType: 'nodejs', journal: tx });
+    tx.run(['git', 'tag', '-a', 'v1.1.0', '-m', 'v1.1.0']);
+    tx.run(['git', 'log', '-1']);
+
+    expect(read('package.json')).toContain('"version": "1.0.0"');
+    expect(calls).toEqual(['git log -1']);
+    expect(journal.renderChanges(tx.changes)).toBe([
+      'write package.json',
+      '--- a/package.json',
+      '+++ b/package.json',
+      '@@ -1,4 +1,4 @@',
+      ' {',
+      '   "name": "shop",',
+      '-  "version": "1.0.0"',
+      '+  "version": "1.1.0"',
+      ' }',
+      'run git tag -a v1.1.0 -m v1.1.0'
+    ].join('\n'));
+    expect(tx.commit()).toEqual({ id: null, changes: 2 });
+    expect(fs.existsSync(journal.journalPath(root))).toBe(false);
+  });
+
+  it('should roll back files and commands of the last run in reverse order', () => {
+    const tx = journal.begin(root, 'release', { run: fakeRun });
+    tx.writeFile('package.json', read('package.json').replace('1.0.0', '1.1.0'));
+    tx.writeFile(path.join(root, '.release-notes.md'), 'notes\n');
+    tx.run(['git', 'add', 'package.json']);
+    tx.run(['git', 'commit', '-m', 'chore(release): v1.1.0']);
+    tx.run(['git', 'tag', '-a', 'v1.1.0', '-m', 'v1.1.0']);
+    tx.run(['git', 'push', '--follow-tags']);
+    tx.run(['gh', 'release', 'create', 'v1.1.0', '--draft']);
+    tx.removeFile('.release-notes.md');
+    expect(tx.run(['fail'])).toBeNull();
+    const committed = tx.commit();
+    expect(committed.changes).toBe(6);
+    expect(tx.commit()).toBe(committed);
+
+    calls = [];
+    const preview = journal.rollback(root, { dryRun: true, run: fakeRun });
+    expect(preview.undone).toEqual([
+      'run gh release delete v1.1.0 --yes',
+      'run git tag -d v1.1.0',
+      'run git reset --soft HEAD~1',
+      'run git reset --quiet -- package.json',
+      'restore package.json'
+    ]);
+    expect(preview.manual).toEqual([{ change: 'run git push --follow-tags', note: expect.stringContaining('stay on the remote') }]);
+    expect(read('package.json')).toContain('1.1.0');
+    expect(calls).toEqual(['git rev-parse HEAD']);
+
+    const result = journal.rollback(root, { run: fakeRun });
+    expect(result.success).toBe(true);
+    expect(calls.slice(1)).toEqual([
+      'gh release delete v1.1.0 --yes',
+      'git tag -d v1.1.0',
+      'git rev-parse HEAD',
+      'git reset --soft HEAD~1',
+      'git reset --quiet -- package.json'
+    ]);
+    expect(read('package.json')).toContain('"version": "1.0.0"');
+    expect(journal.readJournal(root).runs).toEqual([]);
+    expect(journal.rollback(root).error).toBe('No run to roll back');
+  });
+
+  it('should leave edited files and moved commits alone unless forced', () => {
+    const tx = journal.begin(root, 'deslop', { run: fakeRun });
+    tx.writeFile('package.json', '{}\n');
+    tx.writeFile('src/new.js', 'module.exports = 1;\n');
+    tx.run(['git', 'commit', '-m', 'fix']);
+    tx.commit();
+
+    fs.writeFileSync(path.join(root, 'package.json'), '{ "edited": true }\n');
+    head = 'fff0000';
+    const refused = journal.rollback(root, { run: fakeRun });
+    expect(refused.success).toBe(false);
+    expect(refused.skipped).toEqual([
+      { change: 'run git commit -m fix', reason: 'HEAD is no longer abc1234' },
+      { change: 'write package.json', reason: expect.stringContaining('--force') }
+    ]);
+    expect(fs.existsSync(path.join(root, 'src/new.js'))).toBe(false);
+    expect(journal.readJournal(root).runs).toHaveLength(1);
+
+    head = 'abc1234def';
+    const forced = journal.rollback(root, { force: true, run: fakeRun });
+    expect(forced.success).toBe(true);
+    expect(read('package.json')).toContain('"version": "1.0.0"');
+    expect(journal.renderRollback(forced)).toContain('- restore package.json');
+  });
+
+  it('should retry only what a failed rollback left behind', () => {
+    const tx = journal.begin(root, 'release', { run: fakeRun });
+    tx.writeFile('package.json', read('package.json').replace('1.0.0', '1.1.0'));
+    tx.run(['git', 'tag', '-a', 'v1.1.0', '-m', 'v1.1.0']);
+    tx.run(['gh', 'release', 'create', 'v1.1.0', '--draft']);
+    tx.commit();
+
+    failing = ['gh release delete v1.1.0 --yes'];
+    const first = journal.rollback(root, { run: fakeRun });
+    expect(first.success).toBe(false);
+    expect(first.undone).toEqual(['run git tag -d v1.1.0', 'restore package.json']);
+    expect(first.skipped).toEqual([{ change: 'run gh release create v1.1.0 --draft', reason: expect.stringContaining('gh release delete v1.1.0 --yes') }]);
+    expect(journal.readJournal(root).runs).toHaveLength(1);
+
+    failing = [];
+    calls = [];
+    const retry = journal.rollback(root, { run: fakeRun });
+    expect(retry.success).toBe(true);
+    expect(retry.undone).toEqual(['run gh release delete v1.1.0 --yes']);
+    expect(calls).toEqual(['gh release delete v1.1.0 --yes']);
+    expect(read('package.json')).toContain('"version": "1.0.0"');
+    expect(journal.readJournal(root).runs).toEqual([]);
+  });
+
+  it('should know how to undo forge issue commands and pick runs by command', () => {
+    const issueTx = journal.begin(root, 'issue', { run: fakeRun });
+    issueTx.run(['gh', 'label', 'create', 'slop', '--color', 'ededed']);
+    issueTx.run(['gh', 'issue', 'create', '--title', 'Slop', '--body', 'x']);
+    issueTx.run(['gh', 'issue', 'close', '12', '--comment', 'gone']);
+    issueTx.run(['gh', 'issue', 'edit', '3', '--title', 'New']);
+    issueTx.run(['gh', 'issue', 'list', '--json', 'title']);
+    issueTx.commit();
+    const fixTx = journal.begin(root, 'deslop', { run: fakeRun });
+    fixTx.writeFile('package.json', '{}\n');
+    fixTx.commit();
+
+    const result = journal.rollback(root, { command: 'issue', dryRun: true, run: fakeRun });
+    expect(result.run.command).toBe('issue');
+    expect(result.undone).toEqual([
+      'run gh issue reopen 12',
+      'run gh issue close https://github.com/acme/shop/issues/7 --comment Rolled back by awesome-slash',
+      'run gh label delete slop --yes'
+    ]);
+    expect(result.manual).toEqual([{ change: 'run gh issue edit 3 --title New', note: 'edits to existing issues are not undone' }]);
+
+    expect(journal.isReadOnly(['gh', 'api', 'repos/a/b'])).toBe(true);
+    expect(journal.isReadOnly(['gh', 'api', 'repos/a/b/labels', '-f', 'name=x'])).toBe(false);
+    expect(journal.isReadOnly(['git', 'remote', 'add', 'up', 'url'])).toBe(false);
+    expect(journal.inverseCommand(root, ['glab', 'issue', 'create', '--title', 'x'], 'https://gitlab.com/a/b/-/issues/9', fakeRun))
+      .toEqual({ undo: ['glab', 'issue', 'close', '9'] });
+  });
+});
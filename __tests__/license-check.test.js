@@ -0,0 +1,191 @@
+/**
+ * Tests for dependency license checks
+ */
+
+const fs = require('fs');
+const os = require('os');
+const path = require('path');
+
+const {
+  parsePackageLock,
+  parsePnpmLock,
+  parseLockfile,
+  normalizeLicense,
+  classifyLicenseText,
+  categoryOf,
+  readPolicy,
+  evaluateLicense,
+  checkLicenses,
+  renderReport
+} = require('../lib/license-check');
+
+const defaultPolicy = { allow: [], deny: [] };
+
+describe('license-check', () => {
+  let dir;
+
+  beforeEach(() => {
+    dir = fs.mkdtempSync(path.join(os.tmpdir(), 'license-check-'));
+  });
+
+  afterEach(() => {
+    fs.rmSync(dir, { recursive: true, force: true });
+  });
+
+  const write = (file, content) => {
+    fs.mkdirSync(path.dirname(path.join(dir, file)), { recursive: true });
+    fs.writeFileSync(path.join(dir, file), typeof content === 'string' ? content : JSON.stringify(content));
+  };
+
+  describe('lockfiles', () => {
+    it('should read every package from npm lockfiles, nested ones included', () => {
+      const lock = JSON.stringify({
+        lockfileVersion: 3,
+        packages: {
+          '': { name: 'shop', version: '1.0.0' },
+          'node_modules/express': { version: '4.18.2', license: 'MIT' },
+          'node_modules/express/node_modules/debug': { version: '2.6.9', license: 'MIT' },
+          'node_modules/jest': { version: '29.7.0', dev: true, license: 'MIT' },
+          'node_modules/local-lib': { link: true, resolved: 'packages/local-lib' }
+        }
+      });
+      expect(parsePackageLock(lock).map(dep => [dep.name, dep.version, dep.dev, dep.path])).toEqual([
+        ['express', '4.18.2', false, 'node_modules/express'],
+        ['debug', '2.6.9', false, 'node_modules/express/node_modules/debug'],
+        ['jest', '29.7.0', true, 'node_modules/jest']
+      ]);
+    });
+
+    it('should read pnpm, Cargo, Pipfile, and go.mod packages', () => {
+      expect(parsePnpmLock("lockfileVersion: '9.0'\n\nimporters:\n  .:\n    dependencies:\n      react:\n        version: 18.2.0\n\npackages:\n\n  react@18.2.0:\n    resolution: {integrity: sha512-x}\n\n  '@types/react@18.2.1(react@18.2.0)':\n    resolution: {integrity: sha512-y}\n"))
+        .toEqual([{ name: 'react', version: '18.2.0', dev: false }, { name: '@types/react', version: '18.2.1', dev: false }]);
+      expect(parseLockfile('Cargo.lock', '[[package]]\nname = "shop"\nversion = "0.1.0"\n\n[[package]]\nname = "serde"\nversion = "1.0.190"\nsource = "registry+https://github.com/rust-lang/crates.io-index"\n'))
+        .toEqual([{ name: 'serde', version: '1.0.190', dev: false }]);
+      expect(parseLockfile('Pipfile.lock', JSON.stringify({ default: { requests: { version: '==2.31.0' } }, develop: { pytest: { version: '==7.4.0' } } })))
+        .toEqual([{ name: 'requests', version: '2.31.0', dev: false }, { name: 'pytest', version: '7.4.0', dev: true }]);
+      expect(parseLockfile('go.mod', 'module example.com/shop\n\nrequire (\n\tgithub.com/gin-gonic/gin v1.9.1\n\tgolang.org/x/net v0.17.0 // indirect\n)\n'))
+        .toEqual([{ name: 'github.com/gin-gonic/gin', version: 'v1.9.1', dev: false }, { name: 'golang.org/x/net', version: 'v0.17.0', dev: false }]);
+    });
+  });
+
+  describe('licenses', () => {
+    it('should normalize metadata values to SPDX expressions', () => {
+      expect(normalizeLicense('Apache License, Version 2.0')).toBe('Apache-2.0');
+      expect(normalizeLicense('License :: OSI Approved :: GNU General Public License v3 (GPLv3)')).toBe('GPL-3.0');
+      expect(normalizeLicense([{ type: 'MIT' }, { type: 'Apache-2.0' }])).toBe('MIT OR Apache-2.0');
+      expect(normalizeLicense('MIT/Apache-2.0')).toBe('MIT OR Apache-2.0');
+      expect(normalizeLicense('(mit or GPL-2.0-only)')).toBe('(MIT OR GPL-2.0-only)');
+      expect(normalizeLicense('SEE LICENSE IN LICENSE.md')).toBeNull();
+      expect(normalizeLicense('UNKNOWN')).toBeNull();
+    });
+
+    it('should identify license files by their text', () => {
+      expect(classifyLicenseText('Copyright (c) 2020\n\nPermission is hereby granted, free of charge, to any person obtaining a copy\nof this software')).toBe('MIT');
+      expect(classifyLicenseText('GNU LESSER GENERAL PUBLIC LICENSE\n                       Version 3, 29 June 2007')).toBe('LGPL-3.0');
+      expect(classifyLicenseText('All rights reserved.')).toBeNull();
+    });
+
+    it('should categorize licenses and apply the policy', () => {
+      expect(categoryOf('BSD-3-Clause')).toBe('permissive');
+      expect(categoryOf('AGPL-3.0-only')).toBe('copyleft');
+      expect(categoryOf('GPL-2.0-only', 'Classpath-exception-2.0')).toBe('weak-copyleft');
+      expect(categoryOf('CC-BY-NC-4.0')).toBe('proprietary');
+
+      expect(evaluateLicense('MIT OR GPL-3.0-only', defaultPolicy).status).toBe('allowed');
+      expect(evaluateLicense('MIT AND GPL-3.0-only', defaultPolicy)).toEqual({ status: 'denied', category: 'copyleft', reason: 'copyleft license' });
+      expect(evaluateLicense('MPL-2.0', defaultPolicy).status).toBe('warning');
+      expect(evaluateLicense('Custom terms', defaultPolicy).reason).toBe('unrecognized license "Custom terms"');
+      expect(evaluateLicense(null, defaultPolicy).reason).toBe('no license found');
+
+      const policy = { allow: ['MIT', 'LGPL-*'], deny: ['GPL-3.0'] };
+      expect(evaluateLicense('LGPL-2.1-or-later', policy).status).toBe('allowed');
+      expect(evaluateLicense('ISC', policy).reason).toBe('ISC is not in the allow list');
+      expect(evaluateLicense('GPL-3.0-or-later', policy).reason).toBe('GPL-3.0-or-later is denied by policy');
+    });
+  });
+
+  describe('readPolicy', () => {
+    it('should read and validate the licenseCheck config', () => {
+      expect(readPolicy(dir)).toEqual({ file: null, allow: [], deny: [], ignore: [], dev: false, error: null });
+
+      write('.awesome-slash.json', { licenseCheck: { deny: ['AGPL-*'], ignore: ['internal-pkg'], dev: true } });
+      expect(readPolicy(dir)).toEqual({ file: '.awesome-slash.json', allow: [], deny: ['AGPL-*'], ignore: ['internal-pkg'], dev: true, error: null });
+
+      write('.awesome-slash.json', { licenseCheck: { allow: 'MIT' } });
+      expect(readPolicy(dir).error).toBe('.awesome-slash.json: licenseCheck.allow must be an array of strings');
+    });
+  });
+
+  describe('checkLicenses', () => {
+    it('should resolve licenses from lockfile, installed packages, license files, and registries', async () => {
+      write('package-lock.json', {
+        lockfileVersion: 3,
+        packages: {
+          '': { name: 'shop' },
+          'node_modules/express': { version: '4.18.2', license: 'MIT' },
+          'node_modules/left-pad': { version: '1.3.0' },
+          'node_modules/vendored': { version: '0.1.0' },
+          'node_modules/readline-gpl': { version: '2.0.0' },
+          'node_modules/jest': { version: '29.7.0', dev: true }
+        }
+      });
+      write('node_modules/left-pad/package.json', { name: 'left-pad', license: 'WTFPL' });
+      write('node_modules/vendored/package.json', { name: 'vendored' });
+      write('node_modules/vendored/LICENSE', 'Permission to use, copy, modify, and/or distribute this software for any\npurpose with or without fee is hereby granted.');
+      write('Cargo.lock', '[[package]]\nname = "ring"\nversion = "0.17.5"\nsource = "registry+https://github.com/rust-lang/crates.io-index"\n');
+
+      const urls = [];
+      const request = async url => {
+        urls.push(url);
+        if (url.includes('readline-gpl')) return { license: 'GPL-3.0-only' };
+        return null;
+      };
+      const report = await checkLicenses(dir, { run: () => null, request });
+
+      expect(urls).toEqual([
+        'https://registry.npmjs.org/readline-gpl/2.0.0',
+        'https://crates.io/api/v1/crates/ring/0.17.5'
+      ]);
+      expect(report.lockfiles.map(lockfile => [lockfile.file, lockfile.count])).toEqual([['package-lock.json', 4], ['Cargo.lock', 1]]);
+      expect(report.packages.map(dep => [dep.name, dep.license, dep.source])).toEqual([
+        ['express', 'MIT', 'lockfile'],
+        ['left-pad', 'WTFPL', 'installed'],
+        ['vendored', 'ISC', 'license-file'],
+        ['readline-gpl', 'GPL-3.0-only', 'registry'],
+        ['ring', null, null]
+      ]);
+      expect(report.violations.map(dep => [dep.name, dep.reason])).toEqual([
+        ['readline-gpl', 'copyleft license'],
+        ['ring', 'no license found']
+      ]);
+
+      const content = renderReport(report);
+      expect(content).toContain('**Packages**: 5 checked | **Violations**: 2 | **Warnings**: 0');
+      expect(content).toContain('| `readline-gpl` | 2.0.0 | npm | GPL-3.0-only | copyleft license |');
+      expect(content).toContain('| MIT | 1 |');
+    });
+
+    it('should honor ignores and dev settings, and skip registries offline', async () => {
+      write('.awesome-slash.json', { licenseCheck: { ignore: ['readline-gpl'], dev: true } });
+      write('package-lock.json', {
+        lockfileVersion: 3,
+        packages: {
+          'node_modules/readline-gpl': { version: '2.0.0', license: 'GPL-3.0-only' },
+          'node_modules/jest': { version: '29.7.0', dev: true, license: 'MIT' }
+        }
+      });
+
+      const report = await checkLicenses(dir, { offline: true, request: () => { throw new Error('no network'); } });
+      expect(report).toMatchObject({ success: true, ignored: 1, violations: [], warnings: [] });
+      expect(report.packages.map(dep => dep.name)).toEqual(['jest']);
+      expect(renderReport(report)).toContain('All 1 packages pass the license policy.');
+    });
+
+    it('should fail without a lockfile or with an invalid policy', async () => {
+      expect((await checkLicenses(dir)).error).toMatch(/^No lockfile found/);
+      write('package-lock.json', { lockfileVersion: 3, packages: {} });
+      write('.awesome-slash.json', { licenseCheck: [] });
+      expect(await checkLicenses(dir)).toEqual({ success: false, error: '.awesome-slash.json: licenseCheck must be an object' });
+    });
+  });
+});
@@ -0,0 +1,89 @@
+/**
+ * Tests for lib/utils/logger.js
+ */
+
+const { createLogger, isEnabled, formatRecord, applyLogArgs, setSink, ENV } = require('../lib/utils/logger');
+
+describe('logger', () => {
+  const saved = {};
+  let lines;
+
+  beforeEach(() => {
+    for (const name of Object.values(ENV)) {
+      saved[name] = process.env[name];
+      delete process.env[name];
+    }
+    lines = [];
+    setSink((line, level) => lines.push([level, line]));
+  });
+
+  afterEach(() => {
+    setSink(null);
+    for (const [name, value] of Object.entries(saved)) {
+      if (value === undefined) delete process.env[name];
+      else process.env[name] = value;
+    }
+  });
+
+  it('should write warnings and errors by default', () => {
+    const log = createLogger('platform:detect');
+    log.error('broken', { file: 'a.json' });
+    log.warn('odd');
+    log.info('hidden');
+    log.debug('hidden');
+    expect(lines).toEqual([
+      ['error', 'platform:detect: broken (file=a.json)'],
+      ['warn', 'platform:detect: odd']
+    ]);
+  });
+
+  it('should follow the level and namespace settings', () => {
+    process.env[ENV.level] = 'debug';
+    process.env[ENV.namespaces] = 'platform:*, plugins';
+    expect(isEnabled('platform:detect', 'debug')).toBe(true);
+    expect(isEnabled('plugins', 'info')).toBe(true);
+    expect(isEnabled('plugins:extra', 'info')).toBe(false);
+    expect(isEnabled('patterns:pipeline', 'debug')).toBe(false);
+    expect(isEnabled('patterns:pipeline', 'warn')).toBe(true);
+    expect(isEnabled('platform:detect', 'trace')).toBe(false);
+
+    process.env[ENV.level] = 'silent';
+    expect(isEnabled('platform:detect', 'error')).toBe(false);
+    process.env[ENV.level] = 'loud';
+    expect(isEnabled('platform:detect', 'warn')).toBe(true);
+    expect(isEnabled('platform:detect', 'info')).toBe(false);
+
+    process.env[ENV.level] = 'trace';
+    delete process.env[ENV.namespaces];
+    const child = createLogger('platform').child('detectors');
+    expect(child.namespace).toBe('platform:detectors');
+    expect(child.enabled('trace')).toBe(true);
+  });
+
+  it('should format records as text or JSON', () => {
+    const record = { level: 'debug', namespace: 'state', message: 'Corrupted', fields: { error: new Error('bad'), list: [1], skip: undefined } };
+    expect(formatRecord(record, 'text')).toBe('state: Corrupted (error=bad, list=[1])');
+
+    const parsed = JSON.parse(formatRecord(record, 'json'));
+    expect(parsed).toEqual({ time: expect.any(String), level: 'debug', namespace: 'state', message: 'Corrupted', error: 'bad', list: [1] });
+
+    process.env[ENV.format] = 'json';
+    createLogger('mcp').error('Fatal');
+    expect(JSON.parse(lines[0][1])).toMatchObject({ level: 'error', namespace: 'mcp', message: 'Fatal' });
+  });
+
+  it('should apply --log-* flags to the environment and strip them', () => {
+    expect(applyLogArgs(['src', '--log-level', 'debug', '--log-format=json', '--quick', '--log-namespaces', 'platform:*'])).toEqual({
+      argv: ['src', '--quick'],
+      error: null
+    });
+    expect(process.env[ENV.level]).toBe('debug');
+    expect(process.env[ENV.format]).toBe('json');
+    expect(process.env[ENV.namespaces]).toBe('platform:*');
+
+    expect(applyLogArgs(['--log-level', 'loud']).error).toBe('--log-level must be one of silent, error, warn, info, debug, trace');
+    expect(applyLogArgs(['--log-format', 'xml']).error).toBe('--log-format must be one of text, json');
+    expect(applyLogArgs(['--log-level']).error).toBe('--log-level requires a value');
+    expect(process.env[ENV.level]).toBe('debug');
+  });
+});
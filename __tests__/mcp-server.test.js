@@ -2,11 +2,9 @@
  * Tests for MCP Server functionality
  */
 
-const { promisify } = require('util');
-
 // Mock implementations
 jest.mock('child_process', () => ({
-  exec: jest.fn(),
+  execFile: jest.fn(),
 }));
 
 jest.mock('fs', () => ({
@@ -28,13 +26,21 @@ jest.mock('fs', () => ({
 jest.mock('../lib/repo-map', () => ({
   init: jest.fn(),
   update: jest.fn(),
-  status: jest.fn()
+  status: jest.fn(),
+  render: jest.fn(),
+  load: jest.fn()
+}));
+
+jest.mock('../lib/env-check', () => ({
+  checkEnv: jest.fn(),
+  renderReport: jest.fn()
 }));
 
 // Import after mocks are set up
-const { exec: mockExec } = require('child_process');
+const { execFile: mockExecFile } = require('child_process');
 const fs = require('fs');
 const repoMap = require('../lib/repo-map');
+const envCheck = require('../lib/env-check');
 
 // Import the actual tool handlers from the MCP server
 // Tests MUST fail if module cannot be imported - no fallback to ensure we test actual code
@@ -61,18 +67,15 @@ describe('MCP Server - task_discover', () => {
       }
     ];
 
-    mockExec.mockImplementation((cmd, callback) => {
+    mockExecFile.mockImplementation((file, args, options, callback) => {
+      const cmd = [file, ...args].join(' ');
       if (cmd.includes('gh --version')) {
-        callback(null, { stdout: 'gh version 2.40.0' });
+        callback(null, 'gh version 2.40.0');
       } else if (cmd.includes('gh issue list')) {
-        callback(null, { stdout: JSON.stringify(mockIssues) });
+        callback(null, JSON.stringify(mockIssues));
       }
     });
 
-    // Make execAsync work with our mock
-    const execAsync = promisify(mockExec);
-    global.execAsync = execAsync;
-
     const result = await toolHandlers.task_discover({ source: 'gh-issues' });
     const parsed = JSON.parse(result.content[0].text);
 
@@ -99,17 +102,15 @@ describe('MCP Server - task_discover', () => {
       }
     ];
 
-    mockExec.mockImplementation((cmd, callback) => {
+    mockExecFile.mockImplementation((file, args, options, callback) => {
+      const cmd = [file, ...args].join(' ');
       if (cmd.includes('gh --version')) {
-        callback(null, { stdout: 'gh version 2.40.0' });
+        callback(null, 'gh version 2.40.0');
       } else if (cmd.includes('gh issue list')) {
-        callback(null, { stdout: JSON.stringify(mockIssues) });
+        callback(null, JSON.stringify(mockIssues));
       }
     });
 
-    const execAsync = promisify(mockExec);
-    global.execAsync = execAsync;
-
     const result = await toolHandlers.task_discover({
       source: 'gh-issues',
       filter: 'bug'
@@ -121,15 +122,12 @@ describe('MCP Server - task_discover', () => {
   });
 
   test('should handle missing gh CLI gracefully', async () => {
-    mockExec.mockImplementation((cmd, callback) => {
-      if (cmd.includes('gh --version')) {
+    mockExecFile.mockImplementation((file, args, options, callback) => {
+      if (file === 'gh' && args[0] === '--version') {
         callback(new Error('gh: command not found'));
       }
     });
 
-    const execAsync = promisify(mockExec);
-    global.execAsync = execAsync;
-
     const result = await toolHandlers.task_discover({ source: 'gh-issues' });
 
     expect(result.isError).toBe(true);
@@ -361,6 +359,24 @@ describe('MCP Server - repo_map', () => {
     expect(parsed.result.success).toBe(true);
   });
 
+  test('should render with a token budget', async () => {
+    repoMap.render.mockReturnValue({ success: true, text: 'src/a.js:\n  function a :1\n', tokens: 7, fits: true });
+
+    const result = await toolHandlers.repo_map({ action: 'render', maxTokens: 100 });
+    const parsed = JSON.parse(result.content[0].text);
+
+    expect(repoMap.render).toHaveBeenCalledWith(expect.any(String), { maxTokens: 100 });
+    expect(parsed.result.tokens).toBe(7);
+  });
+
+  test('should pass render format options through', async () => {
+    repoMap.render.mockReturnValue({ success: true, format: 'mermaid', text: 'graph LR\n', files: 0, edges: 0 });
+
+    await toolHandlers.repo_map({ action: 'render', format: 'mermaid', maxFiles: 20 });
+
+    expect(repoMap.render).toHaveBeenCalledWith(expect.any(String), expect.objectContaining({ format: 'mermaid', maxFiles: 20 }));
+  });
+
   test('should handle invalid repo_map action', async () => {
     const result = await toolHandlers.repo_map({ action: 'unknown' });
 
@@ -368,3 +384,79 @@ describe('MCP Server - repo_map', () => {
     expect(result.content[0].text).toContain('Invalid action');
   });
 });
+
+describe('MCP Server - repo_query', () => {
+  const map = {
+    files: {
+      'src/math.js': {
+        symbols: {
+          functions: [{ name: 'add', kind: 'function', line: 1 }, { name: 'addAll', kind: 'function', line: 9 }],
+          classes: [{ name: 'Calculator', kind: 'class', line: 20, methods: [{ name: 'add', line: 22 }] }]
+        }
+      },
+      'src/app.js': { symbols: { functions: [{ name: 'main', kind: 'function', line: 3 }] } }
+    },
+    callGraph: {
+      'src/math.js': { add: [{ file: 'src/app.js', line: 5 }] }
+    }
+  };
+
+  beforeEach(() => {
+    jest.clearAllMocks();
+  });
+
+  test('should find symbols with exact names first', async () => {
+    repoMap.load.mockReturnValue(map);
+
+    const result = await toolHandlers.repo_query({ name: 'add' });
+    const parsed = JSON.parse(result.content[0].text);
+
+    expect(parsed.total).toBe(3);
+    expect(parsed.symbols.map(symbol => `${symbol.qualifiedName}:${symbol.kind}`)).toEqual(['add:function', 'Calculator.add:method', 'addAll:function']);
+  });
+
+  test('should list cross-file callers', async () => {
+    repoMap.load.mockReturnValue(map);
+
+    const result = await toolHandlers.repo_query({ action: 'callers', name: 'add' });
+    const parsed = JSON.parse(result.content[0].text);
+
+    expect(parsed.definitions).toEqual([{ file: 'src/math.js', line: 1, callers: [{ file: 'src/app.js', line: 5 }] }]);
+    expect(parsed.total).toBe(1);
+  });
+
+  test('should ask for a repo map when none exists', async () => {
+    repoMap.load.mockReturnValue(null);
+
+    const result = await toolHandlers.repo_query({ name: 'add' });
+
+    expect(result.isError).toBe(true);
+    expect(result.content[0].text).toContain('No repo-map found');
+  });
+});
+
+describe('MCP Server - scan_run', () => {
+  beforeEach(() => {
+    jest.clearAllMocks();
+  });
+
+  test('should return the markdown report by default', async () => {
+    repoMap.load.mockReturnValue(null);
+    envCheck.checkEnv.mockReturnValue({ success: true, missing: [] });
+    envCheck.renderReport.mockReturnValue('## Environment Variables\n');
+
+    const result = await toolHandlers.scan_run({ scanner: 'env' });
+
+    expect(envCheck.checkEnv).toHaveBeenCalledWith(expect.any(String), { map: null });
+    expect(result.content[0].text).toBe('## Environment Variables\n');
+  });
+
+  test('should surface scanner failures and unknown scanners', async () => {
+    envCheck.checkEnv.mockReturnValue({ success: false, error: 'No repo map' });
+
+    expect((await toolHandlers.scan_run({ scanner: 'env' })).content[0].text).toContain('No repo map');
+    const unknown = await toolHandlers.scan_run({ scanner: 'slop' });
+    expect(unknown.isError).toBe(true);
+    expect(unknown.content[0].text).toContain('Unknown scanner: slop');
+  });
+});
@@ -0,0 +1,113 @@
+/**
+ * Tests for lib/messages (message catalog and locale selection)
+ */
+
+const fs = require('fs');
+const os = require('os');
+const path = require('path');
+
+const messages = require('../lib/messages');
+const { runPipeline } = require('../lib/patterns/pipeline');
+const { describeTriage } = require('../lib/patterns/triage');
+
+const english = require('../lib/messages/locales/en.json');
+
+/**
+ * Every literal id passed to t() in the JavaScript under a directory
+ */
+function usedKeys(dir, keys = new Set()) {
+  for (const entry of fs.readdirSync(dir, { withFileTypes: true })) {
+    const full = path.join(dir, entry.name);
+    if (entry.isDirectory()) {
+      usedKeys(full, keys);
+    } else if (entry.name.endsWith('.js')) {
+      const source = fs.readFileSync(full, 'utf8');
+      for (const match of source.matchAll(/\bt\('([\w.-]+)'/g)) keys.add(match[1]);
+    }
+  }
+  return keys;
+}
+
+describe('messages', () => {
+  let root;
+
+  beforeEach(() => {
+    root = fs.realpathSync(fs.mkdtempSync(path.join(os.tmpdir(), 'messages-')));
+  });
+
+  afterEach(() => {
+    messages.reset();
+    fs.rmSync(root, { recursive: true, force: true });
+  });
+
+  it('should pick the locale from the environment and the project config', () => {
+    expect(['de_DE.UTF-8', 'pt-br', 'zh-Hant-TW', 'C', 'POSIX', ''].map(messages.normalizeLocale))
+      .toEqual(['de-DE', 'pt-BR', 'zh-Hant-TW', null, null, null]);
+
+    const config = { locale: 'fr', dir: null };
+    expect(messages.resolveLocale(config, { LANG: 'de_DE.UTF-8' })).toBe('fr');
+    expect(messages.resolveLocale(config, { AWESOME_SLASH_LOCALE: 'es', LANG: 'de_DE.UTF-8' })).toBe('es');
+    expect(messages.resolveLocale({ locale: null }, { LC_ALL: 'C', LC_MESSAGES: 'it_IT', LANG: 'de' })).toBe('it-IT');
+    expect(messages.resolveLocale({ locale: null }, {})).toBe('en');
+
+    fs.writeFileSync(path.join(root, '.awesome-slash.json'), JSON.stringify({ i18n: { locale: 'pt_BR', dir: 'locales' } }));
+    expect(messages.readSettings(root)).toEqual({ locale: 'pt-BR', dir: path.join(root, 'locales'), error: null });
+
+    fs.writeFileSync(path.join(root, '.awesome-slash.json'), JSON.stringify({ i18n: { dir: '' } }));
+    expect(messages.readSettings(root).error).toBe('.awesome-slash.json: i18n.dir must be a directory path');
+  });
+
+  it('should fall back from project catalogs to the base language and English', () => {
+    const dir = path.join(root, 'locales');
+    fs.mkdirSync(dir);
+    fs.writeFileSync(path.join(dir, 'pt-BR.json'), JSON.stringify({ 'fix.undo': 'Desfaça com: awesome-slash rollback' }));
+    fs.writeFileSync(path.join(dir, 'pt.json'), JSON.stringify({
+      'fix.undo': 'Desfazer com: awesome-slash rollback',
+      'triage.summary.fixed': { one: '{fixed} corrigido em {count} arquivo', other: '{fixed} corrigidos em {count} arquivos' }
+    }));
+    fs.writeFileSync(path.join(dir, 'en.json'), '{ not json');
+
+    expect(messages.fallbackChain('pt-BR')).toEqual(['pt-BR', 'pt', 'en']);
+    const { catalogs, errors } = messages.loadCatalogs('pt-BR', dir);
+    expect(errors).toHaveLength(1);
+    expect(errors[0]).toContain(path.join(dir, 'en.json'));
+
+    const t = messages.createTranslator('pt-BR', catalogs);
+    expect(t('fix.undo')).toBe('Desfaça com: awesome-slash rollback');
+    expect(t('triage.summary.fixed', { fixed: 1, count: 1 })).toBe('1 corrigido em 1 arquivo');
+    expect(t('triage.summary.fixed', { fixed: 3, count: 2 })).toBe('3 corrigidos em 2 arquivos');
+    // Keys the project does not translate come from the built-in English
+    expect(t('fix.written', { findings: 2, files: 1 })).toBe('Fixed 2 findings in 1 files');
+    expect(t('pattern.unknown', { name: 'x' }, 'Custom {name} {missing}')).toBe('Custom x {missing}');
+    expect(t('no.such.key')).toBe('no.such.key');
+  });
+
+  it('should define every message id the code uses', () => {
+    const keys = usedKeys(path.join(__dirname, '..', 'lib'), usedKeys(path.join(__dirname, '..', 'plugins', 'deslop', 'scripts')));
+    const missing = [...keys].filter(key => !(key in english));
+    expect(missing).toEqual([]);
+  });
+
+  it('should translate findings and reports for the configured project', () => {
+    fs.mkdirSync(path.join(root, 'src'));
+    fs.writeFileSync(path.join(root, 'src', 'app.js'), 'function app() {\n  console.log("debug");\n}\n');
+    fs.mkdirSync(path.join(root, 'i18n'));
+    fs.writeFileSync(path.join(root, 'i18n', 'de.json'), JSON.stringify({
+      'pattern.console_debugging': 'Debug-Ausgabe',
+      'triage.summary.reviewed': '{reviewed}/{total} geprüft'
+    }));
+    fs.writeFileSync(path.join(root, '.awesome-slash.json'), JSON.stringify({ i18n: { locale: 'de', dir: 'i18n' } }));
+
+    expect(messages.configure(root, { env: {} })).toEqual({ locale: 'de', errors: [] });
+    expect(messages.getLocale()).toBe('de');
+
+    const result = runPipeline(root, { thoroughness: 'quick', targetFiles: ['src/app.js'], baseline: false, linters: [], runtimes: [], repoMap: null });
+    const finding = result.findings.find(entry => entry.patternName === 'console_debugging');
+    expect(finding.description).toBe('Debug-Ausgabe');
+    expect(describeTriage({ reviewed: 1, fixed: 0, baselined: 0, fixSkipped: [] }, 1)).toBe('1/1 geprüft');
+
+    // AWESOME_SLASH_LOCALE overrides the project's locale
+    messages.configure(root, { env: { AWESOME_SLASH_LOCALE: 'en' } });
+    expect(messages.t('pattern.console_debugging', {}, 'fallback')).toBe('fallback');
+  });
+});
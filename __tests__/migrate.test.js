@@ -0,0 +1,136 @@
+/**
+ * Tests for framework and runtime migration planning
+ */
+
+const fs = require('fs');
+const os = require('os');
+const path = require('path');
+
+const {
+  parseTarget,
+  findGuide,
+  planMigration,
+  applyCodemods,
+  renderChecklist,
+  migrate
+} = require('../lib/migrate');
+
+const map = {
+  files: {
+    'src/server.js': {
+      language: 'javascript',
+      symbols: { functions: [{ name: 'start', line: 3 }], classes: [] },
+      imports: [{ source: 'express' }]
+    },
+    'src/config.mjs': { language: 'javascript', symbols: {}, imports: [] },
+    'app/models.py': {
+      language: 'python',
+      symbols: { functions: [{ name: 'load', line: 8 }], classes: [{ name: 'User', line: 3 }] },
+      imports: [{ source: 'pydantic' }]
+    },
+    'app/report.py': { language: 'python', symbols: {}, imports: [{ source: 'json' }] }
+  }
+};
+
+const sources = {
+  'src/server.js': "const util = require('util');\n\nfunction start(app) {\n  if (util.isArray(app.routes) && util.isDate(app.at)) return;\n  app.del('/x', handler);\n  res.send(404, 'nope');\n}\n",
+  'src/config.mjs': "import data from './data.json' assert { type: 'json' };\n",
+  'app/models.py': 'from pydantic import BaseModel, validator\n\nclass User(BaseModel):\n    class Config:\n        orm_mode = True\n\n\ndef load(raw):\n    return User.parse_raw(raw).dict()\n',
+  'app/report.py': 'def summary(row):\n    return row.dict()\n'
+};
+const readFile = file => sources[file] ?? null;
+
+describe('migrate', () => {
+  describe('parseTarget', () => {
+    it('should read names and versions', () => {
+      expect(parseTarget('Node 18→22')).toEqual({ name: 'node', from: '18', to: '22' });
+      expect(parseTarget('Pydantic v1→v2')).toEqual({ name: 'pydantic', from: '1', to: '2' });
+      expect(parseTarget('python 3.8 to 3.12')).toEqual({ name: 'python', from: '3.8', to: '3.12' });
+      expect(parseTarget('express -> 5')).toEqual({ name: 'express', from: null, to: '5' });
+      expect(parseTarget('upgrade everything')).toBeNull();
+      expect(findGuide('NodeJS').key).toBe('node');
+    });
+  });
+
+  describe('planMigration', () => {
+    it('should pick steps in the version range with their call sites', () => {
+      const plan = planMigration(map, 'Node 18→22', { readFile });
+      expect(plan.success).toBe(true);
+      expect(plan.steps.map(step => [step.id, step.automatic, step.sites.length])).toEqual([
+        ['util-isarray', true, 1],
+        ['util-is', false, 1],
+        ['import-assertions', true, 1]
+      ]);
+      expect(plan.steps[0].sites[0]).toEqual({
+        file: 'src/server.js',
+        line: 4,
+        symbol: 'start',
+        text: 'if (util.isArray(app.routes) && util.isDate(app.at)) return;',
+        replacement: '  if (Array.isArray(app.routes) && util.isDate(app.at)) return;'
+      });
+      expect(plan.manual).toContain('Replace `--experimental-loader` hooks with `module.register()`');
+      expect(planMigration(map, 'Node 20→22', { readFile }).manual).not.toContain('Replace `--experimental-loader` hooks with `module.register()`');
+    });
+
+    it('should limit ambiguous steps to files importing the package', () => {
+      const plan = planMigration(map, 'pydantic 1→2', { readFile });
+      const sites = Object.fromEntries(plan.steps.map(step => [step.id, step.sites.map(site => `${site.file}:${site.line}`)]));
+      expect(sites).toEqual({
+        'parse-raw': ['app/models.py:9'],
+        'config-class': ['app/models.py:4'],
+        'model-methods': ['app/models.py:9']
+      });
+      expect(plan.steps.find(step => step.id === 'config-class').sites[0].symbol).toBe('User');
+    });
+
+    it('should reject unknown guides and bad ranges', () => {
+      expect(planMigration(map, 'Cobol 1→2', { readFile }).error).toContain('No migration guide for cobol');
+      expect(planMigration(map, 'Node 22→20', { readFile }).error).toBe('Target 20 is not newer than 22');
+      expect(planMigration(map, 'express → 5', { readFile }).error).toContain('Could not detect the current Express version');
+      expect(planMigration(map, 'express → 5', { readFile, from: '4.18.2' }).steps.map(step => step.id)).toEqual(['app-del', 'status-first']);
+    });
+  });
+
+  describe('applyCodemods', () => {
+    it('should rewrite planned lines and skip changed ones', () => {
+      const files = { ...sources };
+      const plan = planMigration(map, 'express 4→5', { readFile: file => files[file] });
+      files['src/server.js'] = files['src/server.js'].replace("res.send(404, 'nope')", "res.send(404, 'gone')");
+      const result = applyCodemods(plan, { readFile: file => files[file], writeFile: (file, content) => { files[file] = content; } });
+      expect(result).toEqual({ files: ['src/server.js'], applied: 1, stale: [{ file: 'src/server.js', line: 6 }] });
+      expect(files['src/server.js']).toContain("  app.delete('/x', handler);");
+      expect(files['src/server.js']).toContain("res.send(404, 'gone')");
+    });
+
+    it('should apply several codemods on one line', () => {
+      const files = { 'tests/test_api.py': 'self.assertEquals(a, b); self.assertRegexpMatches(s, r)\n' };
+      const pyMap = { files: { 'tests/test_api.py': { language: 'python', symbols: {}, imports: [] } } };
+      const plan = planMigration(pyMap, 'python 3.11→3.12', { readFile: file => files[file] });
+      applyCodemods(plan, { readFile: file => files[file], writeFile: (file, content) => { files[file] = content; } });
+      expect(files['tests/test_api.py']).toBe('self.assertEqual(a, b); self.assertRegex(s, r)\n');
+    });
+  });
+
+  describe('renderChecklist', () => {
+    it('should list codemods, manual changes and the checklist', () => {
+      const text = renderChecklist(planMigration(map, 'Node 18→22', { readFile }));
+      expect(text).toContain('## Migration: Node.js 18 → 22');
+      expect(text).toContain('- [ ] `util.isArray()` is deprecated: use `Array.isArray()` - 1 site(s)\n  - `src/server.js:4` (start)');
+      expect(text).toContain('### Manual changes');
+      expect(text).toContain('- [ ] Run the test suite on the target version');
+    });
+  });
+
+  describe('migrate', () => {
+    it('should detect the current version', () => {
+      const dir = fs.mkdtempSync(path.join(os.tmpdir(), 'migrate-'));
+      try {
+        fs.writeFileSync(path.join(dir, 'package.json'), JSON.stringify({ dependencies: { express: '^4.18.0' } }));
+        const plan = migrate(dir, 'express → 5', { map: { files: {} } });
+        expect(plan).toMatchObject({ success: true, from: '4.18.0', to: '5', steps: [] });
+      } finally {
+        fs.rmSync(dir, { recursive: true, force: true });
+      }
+    });
+  });
+});
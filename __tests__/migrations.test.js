@@ -0,0 +1,67 @@
+/**
+ * Tests for migration safety helpers
+ */
+
+const {
+  isMigrationFile,
+  extractDroppedColumns,
+  findDroppedColumnReferences
+} = require('../lib/patterns/migrations');
+
+describe('migrations', () => {
+  describe('isMigrationFile', () => {
+    it('should recognize migration directories across frameworks', () => {
+      expect(isMigrationFile('db/migrate/20240101120000_remove_email.rb')).toBe(true);
+      expect(isMigrationFile('app/users/migrations/0004_remove_user_email.py')).toBe(true);
+      expect(isMigrationFile('alembic/versions/3f2a_drop_email.py')).toBe(true);
+      expect(isMigrationFile('prisma/migrations/20240101_init/migration.sql')).toBe(true);
+      expect(isMigrationFile('src/main/resources/db/V2__drop_email.sql')).toBe(true);
+      expect(isMigrationFile('app/users/migrations/__init__.py')).toBe(false);
+      expect(isMigrationFile('src/models/user.js')).toBe(false);
+    });
+  });
+
+  describe('extractDroppedColumns', () => {
+    it('should find dropped columns in Rails, Alembic, Django, SQL and Knex migrations', () => {
+      expect(extractDroppedColumns('class X < ActiveRecord::Migration[7.1]\n  def change\n    remove_column :users, :legacy_email, :string\n  end\nend\n'))
+        .toEqual([{ table: 'users', column: 'legacy_email', line: 3 }]);
+      expect(extractDroppedColumns("def upgrade():\n    op.drop_column('users', 'nickname')\n"))
+        .toEqual([{ table: 'users', column: 'nickname', line: 2 }]);
+      expect(extractDroppedColumns("migrations.RemoveField(model_name='user', name='nickname'),"))
+        .toEqual([{ table: 'user', column: 'nickname', line: 1 }]);
+      expect(extractDroppedColumns('ALTER TABLE "users" DROP COLUMN IF EXISTS "nickname";\nALTER TABLE users DROP CONSTRAINT users_fk;\n'))
+        .toEqual([{ table: 'users', column: 'nickname', line: 1 }]);
+      expect(extractDroppedColumns("table.dropColumn('nickname');"))
+        .toEqual([{ table: null, column: 'nickname', line: 1 }]);
+    });
+  });
+
+  describe('findDroppedColumnReferences', () => {
+    const contents = {
+      'db/migrate/1_remove_nickname.rb': 'remove_column :users, :nickname\nremove_column :users, :fax_number\n',
+      'db/schema.rb': 't.string "nickname"\n',
+      'app/models/user.rb': 'class User\n  def display\n    nickname || email\n  end\nend\n',
+      'app/views/show.erb': '<%= user.email %>\n'
+    };
+    const readFile = file => {
+      if (!(file in contents)) throw new Error(`ENOENT: ${file}`);
+      return contents[file];
+    };
+
+    it('should report code still using a dropped column, searching repo-map files', () => {
+      const map = { files: { 'app/models/user.rb': {}, 'app/views/show.erb': {}, 'db/schema.rb': {}, 'db/migrate/1_remove_nickname.rb': {} } };
+      expect(findDroppedColumnReferences('/repo', ['db/migrate/1_remove_nickname.rb'], { map, readFile })).toEqual([{
+        migration: 'db/migrate/1_remove_nickname.rb',
+        line: 1,
+        table: 'users',
+        column: 'nickname',
+        references: [{ file: 'app/models/user.rb', line: 3 }]
+      }]);
+    });
+
+    it('should fall back to an explicit file list and skip missing migrations', () => {
+      expect(findDroppedColumnReferences('/repo', ['db/migrate/missing.rb'], { files: Object.keys(contents), readFile })).toEqual([]);
+      expect(findDroppedColumnReferences('/repo', ['db/migrate/1_remove_nickname.rb'], { files: ['app/views/show.erb'], readFile })).toEqual([]);
+    });
+  });
+});
@@ -0,0 +1,153 @@
+/**
+ * Tests for Slack and Discord notifications
+ */
+
+const fs = require('fs');
+const os = require('os');
+const path = require('path');
+
+const {
+  readSettings,
+  renderMessage,
+  planNotification,
+  notify,
+  ciRun,
+  releaseVariables,
+  slopGateVariables,
+  flakyVariables
+} = require('../lib/notify');
+
+const SLACK_URL = 'https://hooks.slack.com/services/T0/B0/secret';
+const DISCORD_URL = 'https://discord.com/api/webhooks/1/secret';
+
+describe('notify', () => {
+  let dir;
+
+  const writeConfig = config => fs.writeFileSync(path.join(dir, '.awesome-slash.json'), JSON.stringify(config));
+
+  const routed = (commands = {}) => ({
+    notify: {
+      channels: {
+        team: { type: 'slack', urlEnv: 'SLACK_WEBHOOK_URL' },
+        ci: { type: 'discord', url: DISCORD_URL }
+      },
+      commands
+    }
+  });
+
+  beforeEach(() => {
+    dir = fs.mkdtempSync(path.join(os.tmpdir(), 'notify-'));
+  });
+
+  afterEach(() => {
+    fs.rmSync(dir, { recursive: true, force: true });
+  });
+
+  describe('readSettings', () => {
+    it('should read channels and command routes', () => {
+      writeConfig(routed({ release: { channels: ['team', 'ci'], template: '{{tag}} is out' }, flaky: false }));
+      expect(readSettings(dir)).toEqual({
+        channels: { team: { type: 'slack', urlEnv: 'SLACK_WEBHOOK_URL' }, ci: { type: 'discord', url: DISCORD_URL } },
+        commands: { release: { channels: ['team', 'ci'], template: '{{tag}} is out' } },
+        error: null
+      });
+    });
+
+    it('should reject unknown channels, commands, and template variables', () => {
+      writeConfig(routed({ release: { channels: ['ops'] } }));
+      expect(readSettings(dir).error).toBe('.awesome-slash.json: notify.commands.release.channels: no channel named ops');
+
+      writeConfig(routed({ ship: { channels: ['team'] } }));
+      expect(readSettings(dir).error).toBe('.awesome-slash.json: notify.commands.ship is not a notifying command (release, deslop, flaky)');
+
+      writeConfig(routed({ flaky: { channels: ['team'], template: '{{tag}}' } }));
+      expect(readSettings(dir).error).toBe('.awesome-slash.json: notify.commands.flaky.template uses unknown variable {{tag}} (project, count, runs, platform, tests)');
+
+      writeConfig({ notify: { channels: { team: { type: 'teams', url: SLACK_URL } } } });
+      expect(readSettings(dir).error).toBe('.awesome-slash.json: notify.channels.team.type must be one of slack, discord');
+
+      writeConfig({ notify: { channels: { team: { type: 'slack', url: 'http://hooks.example.com' } } } });
+      expect(readSettings(dir).error).toBe('.awesome-slash.json: notify.channels.team.url must be an https URL');
+    });
+  });
+
+  describe('renderMessage', () => {
+    it('should drop lines whose variables are all missing and close gaps', () => {
+      const template = 'Slop gate failed on {{project}} {{ref}}: {{failing}}\n{{url}}';
+      expect(renderMessage(template, { project: 'shop', failing: '2 critical' }, 'discord')).toBe('Slop gate failed on shop: 2 critical');
+    });
+
+    it('should escape Slack control characters in values only', () => {
+      expect(renderMessage('<!here> {{tests}}', { tests: '<script> & <@U1>' }, 'slack')).toBe('<!here> &lt;script&gt; &amp; &lt;@U1&gt;');
+      expect(renderMessage('{{tests}}', { tests: 'x'.repeat(3000) }, 'discord')).toHaveLength(2000);
+    });
+  });
+
+  describe('notify', () => {
+    it('should send nothing for commands without a route', async () => {
+      writeConfig(routed());
+      const request = jest.fn();
+      expect(await notify(dir, 'flaky-tests-detected', { count: 1 }, { request })).toEqual({
+        success: true, event: 'flaky-tests-detected', configured: false, sent: [], failed: [], skipped: []
+      });
+      expect(request).not.toHaveBeenCalled();
+    });
+
+    it('should post each channel its payload format', async () => {
+      writeConfig(routed({ release: { channels: ['team', 'ci'] } }));
+      const calls = [];
+      const request = async (url, payload) => { calls.push([url, payload]); };
+      const plan = { version: '1.3.0', tag: 'v1.3.0', from: 'v1.2.0', commits: 4, compare: 'https://github.com/acme/shop/compare/v1.2.0...v1.3.0' };
+
+      const result = await notify(dir, 'release-published', { project: 'shop', ...releaseVariables(plan) }, { request, env: { SLACK_WEBHOOK_URL: SLACK_URL } });
+      expect(result.sent).toEqual(['team', 'ci']);
+      expect(calls).toEqual([
+        [SLACK_URL, { text: 'Released shop v1.3.0 (4 commits)\nhttps://github.com/acme/shop/compare/v1.2.0...v1.3.0' }],
+        [DISCORD_URL, { content: 'Released shop v1.3.0 (4 commits)\nhttps://github.com/acme/shop/compare/v1.2.0...v1.3.0', allowed_mentions: { parse: [] } }]
+      ]);
+    });
+
+    it('should skip channels whose variable is unset and report failures without the URL', async () => {
+      writeConfig(routed({ deslop: { channels: ['team', 'ci'] } }));
+      const request = async () => { throw new Error('webhook returned 404'); };
+      const result = await notify(dir, 'slop-gate-failed', {}, { request, env: {} });
+      expect(result).toEqual({
+        success: false,
+        event: 'slop-gate-failed',
+        configured: true,
+        sent: [],
+        failed: [{ channel: 'ci', error: 'webhook returned 404' }],
+        skipped: [{ channel: 'team', reason: 'SLACK_WEBHOOK_URL is not set' }]
+      });
+      expect(JSON.stringify(result)).not.toContain('secret');
+    });
+
+    it('should refuse unknown events and invalid config', async () => {
+      expect(planNotification(dir, 'deployed', {}).error).toBe('Unknown event deployed (release-published, slop-gate-failed, flaky-tests-detected)');
+      fs.writeFileSync(path.join(dir, '.awesome-slash.json'), '{');
+      expect((await notify(dir, 'release-published', {})).error).toMatch(/^\.awesome-slash\.json: invalid JSON/);
+    });
+  });
+
+  describe('event variables', () => {
+    it('should describe a failing slop gate with the CI run', () => {
+      const gate = { exitCode: 2, failing: { critical: 1, high: 2 }, warnings: { medium: 4 } };
+      const env = { GITHUB_ACTIONS: 'true', GITHUB_REPOSITORY: 'acme/shop', GITHUB_RUN_ID: '99', GITHUB_REF_NAME: 'main' };
+      expect(slopGateVariables(gate, 'high', env)).toEqual({
+        failOn: 'high', failing: '1 critical, 2 high', count: 3, url: 'https://github.com/acme/shop/actions/runs/99', ref: 'main'
+      });
+      expect(ciRun({ GITLAB_CI: 'true', CI_PIPELINE_URL: 'https://gitlab.com/acme/shop/-/pipelines/5', CI_COMMIT_REF_NAME: 'dev' }))
+        .toEqual({ url: 'https://gitlab.com/acme/shop/-/pipelines/5', ref: 'dev' });
+    });
+
+    it('should list the most flaky tests', () => {
+      const tests = Array.from({ length: 6 }, (_, i) => ({ id: `cart > total ${i}`, flakeRate: 0.25, failures: 1, executions: 4 }));
+      const variables = flakyVariables({ platform: 'github-actions', analyzed: 30, tests });
+      expect(variables.count).toBe(6);
+      expect(variables.tests.split('\n')).toEqual([
+        ...tests.slice(0, 5).map(test => `- ${test.id} (25%, 1/4 failed)`),
+        '...and 1 more'
+      ]);
+    });
+  });
+});
@@ -0,0 +1,163 @@
+/**
+ * Tests for ONBOARDING.md generation
+ */
+
+const fs = require('fs');
+const os = require('os');
+const path = require('path');
+
+const {
+  readProjectInfo,
+  buildCommands,
+  summarizeMap,
+  renderOnboarding,
+  generateOnboarding
+} = require('../lib/onboard');
+
+const map = {
+  files: {
+    'src/index.js': {
+      language: 'javascript',
+      symbols: { exports: [{ name: 'createApp', kind: 'function', line: 4 }], functions: [{ name: 'createApp', line: 4, exported: true }] },
+      imports: [{ source: './db' }, { source: './routes/users' }]
+    },
+    'src/db.js': {
+      language: 'javascript',
+      symbols: { exports: [{ name: 'connect', kind: 'function', line: 1 }], functions: [{ name: 'connect', line: 1, exported: true }] },
+      imports: []
+    },
+    'src/routes/users.js': {
+      language: 'javascript',
+      symbols: { exports: [{ name: 'listUsers', kind: 'function', line: 3 }], functions: [{ name: 'listUsers', line: 3, exported: true }] },
+      imports: [{ source: '../db' }]
+    },
+    'bin/serve.js': { language: 'javascript', symbols: {}, imports: [{ source: '../src/index' }] },
+    'tests/db.test.js': { language: 'javascript', symbols: {}, imports: [{ source: '../src/db' }] }
+  }
+};
+
+const detection = {
+  projectType: 'nodejs',
+  packageManager: 'pnpm',
+  deployments: ['vercel'],
+  ciPipelines: [{ platform: 'github-actions', file: '.github/workflows/ci.yml', name: 'CI', jobs: ['test', 'deploy'] }],
+  containerization: null,
+  infrastructure: null,
+  serverless: null,
+  monorepo: null,
+  mainBranch: 'main',
+  branching: { model: 'github-flow', developBranch: null, productionBranch: null, releaseBranches: [], latestTag: 'v1.2.0', environments: [] }
+};
+
+describe('onboard', () => {
+  describe('readProjectInfo', () => {
+    it('should read package.json, Cargo.toml, and go.mod', () => {
+      const files = { 'package.json': JSON.stringify({ name: 'shop', description: 'Storefront', scripts: { build: 'tsc' } }) };
+      expect(readProjectInfo(file => files[file] ?? null, 'dir')).toEqual({ name: 'shop', description: 'Storefront', scripts: { build: 'tsc' } });
+
+      const cargo = { 'Cargo.toml': '[package]\nname = "ferry"\ndescription = "File sync"\n\n[dependencies]\nname = "nope"\n' };
+      expect(readProjectInfo(file => cargo[file] ?? null, 'dir')).toEqual({ name: 'ferry', description: 'File sync', scripts: {} });
+
+      const go = { 'go.mod': 'module github.com/acme/relay\n\ngo 1.22\n' };
+      expect(readProjectInfo(file => go[file] ?? null, 'dir').name).toBe('relay');
+      expect(readProjectInfo(() => null, 'dir').name).toBe('dir');
+    });
+  });
+
+  describe('buildCommands', () => {
+    it('should use the package manager and build scripts', () => {
+      const info = { scripts: { build: 'tsc', dev: 'vite', start: 'node .' } };
+      expect(buildCommands(detection, info, () => null)).toEqual({
+        install: 'pnpm install --frozen-lockfile',
+        build: ['pnpm run build'],
+        run: ['pnpm run dev', 'pnpm run start']
+      });
+    });
+
+    it('should fall back to project type defaults and Makefile targets', () => {
+      const files = { 'Makefile': 'build:\n\tgo build ./cmd/relay\nrun: build\n\t./relay\n' };
+      expect(buildCommands({ projectType: 'go', packageManager: 'go' }, { scripts: {} }, file => files[file] ?? null)).toEqual({
+        install: 'go mod download',
+        build: ['go build ./...', 'make build'],
+        run: ['make run']
+      });
+      const python = { 'requirements.txt': 'flask\n' };
+      expect(buildCommands({ projectType: 'python', packageManager: null }, { scripts: {} }, file => python[file] ?? null).install)
+        .toBe('pip install -r requirements.txt');
+    });
+  });
+
+  describe('summarizeMap', () => {
+    it('should list entry points, central files, and exported symbols without tests', () => {
+      const files = { 'package.json': JSON.stringify({ bin: { serve: './bin/serve.js' } }) };
+      const code = summarizeMap(map, { readFile: file => files[file] ?? null });
+
+      expect(code.entryPoints).toEqual(['bin/serve.js', 'src/index.js']);
+      expect(code.files[0].file).toBe('src/db.js');
+      expect(code.files.map(entry => entry.file)).not.toContain('tests/db.test.js');
+      expect(code.symbols.map(symbol => symbol.name)).toContain('connect');
+      expect(code.layout).toEqual([
+        { path: 'src/', files: 3, language: 'javascript' },
+        { path: 'bin/', files: 1, language: 'javascript' },
+        { path: 'tests/', files: 1, language: 'javascript' }
+      ]);
+    });
+  });
+
+  describe('renderOnboarding', () => {
+    it('should render build, test, deploy, and code sections', () => {
+      const content = renderOnboarding({
+        name: 'shop',
+        description: 'Storefront',
+        detection,
+        commands: { install: 'pnpm install --frozen-lockfile', build: ['pnpm run build'], run: [] },
+        runtimes: { runtimes: [{ name: 'node', version: null, minimum: '20.0.0', sources: [{ file: '.nvmrc' }] }] },
+        tests: { frameworks: [{ name: 'vitest' }], commands: [{ command: 'pnpm test', source: 'package.json#scripts.test' }] },
+        linters: { linters: [{ name: 'eslint', lints: true, formats: false }] },
+        code: summarizeMap(map)
+      });
+
+      expect(content).toMatch(/^# Onboarding: shop\n\nStorefront/);
+      expect(content).toContain('- **node**: >=20.0.0 (.nvmrc)');
+      expect(content).toContain('- Install dependencies: `pnpm install --frozen-lockfile`');
+      expect(content).toContain('- `pnpm test` (package.json#scripts.test)');
+      expect(content).toContain('Lint and format: eslint (lint)');
+      expect(content).toContain('- **Deploys to**: vercel');
+      expect(content).toContain('`.github/workflows/ci.yml` - jobs: test, deploy');
+      expect(content).toContain('- **Latest tag**: `v1.2.0`');
+      expect(content).toContain('| `src/` | 3 | javascript |');
+      expect(content).toMatch(/\| `connect` \| function \| `src\/db\.js:1` \|/);
+    });
+
+    it('should point to /repo-map init without a map', () => {
+      const content = renderOnboarding({ name: 'shop', detection: { projectType: 'unknown' }, commands: {}, code: null });
+      expect(content).toContain('No build steps detected.');
+      expect(content).toContain('No test command detected.');
+      expect(content).toContain('Run /repo-map init');
+    });
+  });
+
+  describe('generateOnboarding', () => {
+    it('should gather runtime and test facts from the project', async () => {
+      const dir = fs.mkdtempSync(path.join(os.tmpdir(), 'onboard-'));
+      try {
+        fs.writeFileSync(path.join(dir, 'package.json'), JSON.stringify({
+          name: 'shop',
+          scripts: { test: 'jest', build: 'tsc' },
+          engines: { node: '>=20' },
+          devDependencies: { jest: '^29.0.0' }
+        }));
+
+        const result = await generateOnboarding(dir, { detection: { ...detection, packageManager: 'npm' }, map });
+        expect(result.success).toBe(true);
+        expect(result.file).toBe('ONBOARDING.md');
+        expect(result.content).toContain('- Build: `npm run build`');
+        expect(result.content).toContain('Frameworks: jest');
+        expect(result.content).toContain('- **node**: >=');
+        expect(result.content).toContain('### Entry points');
+      } finally {
+        fs.rmSync(dir, { recursive: true, force: true });
+      }
+    });
+  });
+});
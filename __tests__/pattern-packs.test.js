@@ -0,0 +1,73 @@
+/**
+ * Tests for project review pattern packs
+ */
+
+const fs = require('fs');
+const os = require('os');
+const path = require('path');
+
+const { validatePack, mergePatterns, loadReviewPatterns } = require('../lib/patterns/pattern-packs');
+const { reviewPatterns } = require('../lib/patterns/review-patterns');
+
+describe('pattern-packs', () => {
+  describe('validatePack', () => {
+    it('should keep valid categories and report invalid entries', () => {
+      const result = validatePack({
+        express: { api_conventions: ['Use respond()'], bad: 'nope' },
+        'Bad Name': { x: ['y'] },
+        internal: []
+      }, 'p.js');
+      expect(result.patterns).toEqual({ express: { api_conventions: ['Use respond()'] } });
+      expect(result.errors).toEqual([
+        'p.js: express.bad: expected a non-empty array of strings',
+        'p.js: invalid framework name "Bad Name"',
+        'p.js: internal: expected an object of categories'
+      ]);
+      expect(validatePack(null, 'p.js').errors).toHaveLength(1);
+    });
+  });
+
+  describe('mergePatterns', () => {
+    it('should append to built-in categories without mutating them', () => {
+      const base = { go: { errors: ['a'] } };
+      const merged = mergePatterns(base, [{ go: { errors: ['b', 'a'], style: ['c'] } }, { mine: { x: ['d'] } }]);
+      expect(merged).toEqual({ go: { errors: ['a', 'b'], style: ['c'] }, mine: { x: ['d'] } });
+      expect(base.go.errors).toEqual(['a']);
+    });
+  });
+
+  describe('loadReviewPatterns', () => {
+    let root;
+
+    beforeEach(() => {
+      root = fs.mkdtempSync(path.join(os.tmpdir(), 'pattern-packs-'));
+    });
+
+    afterEach(() => {
+      fs.rmSync(root, { recursive: true, force: true });
+    });
+
+    it('should return built-ins when the project has no packs', () => {
+      const result = loadReviewPatterns(root);
+      expect(result.patterns).toEqual(reviewPatterns);
+      expect(result).toMatchObject({ sets: [], packs: [], errors: [] });
+    });
+
+    it('should merge project packs and report broken ones', () => {
+      const dir = path.join(root, '.awesome-slash', 'patterns');
+      fs.mkdirSync(dir, { recursive: true });
+      fs.writeFileSync(path.join(dir, 'api.js'), "module.exports = { express: { api_conventions: ['Handlers must use respond()'] }, 'internal-api': { versioning: ['Routes missing /v{n}'] } };\n");
+      fs.writeFileSync(path.join(dir, 'broken.js'), 'module.exports = {;\n');
+      fs.writeFileSync(path.join(dir, 'notes.md'), '# ignored\n');
+
+      const result = loadReviewPatterns(root);
+      expect(result.patterns.express.api_conventions).toEqual(['Handlers must use respond()']);
+      expect(result.patterns.express.security).toEqual(reviewPatterns.express.security);
+      expect(result.patterns['internal-api']).toEqual({ versioning: ['Routes missing /v{n}'] });
+      expect(result.sets).toEqual(['internal-api']);
+      expect(result.packs.map(pack => pack.file)).toEqual(['.awesome-slash/patterns/api.js']);
+      expect(result.errors).toHaveLength(1);
+      expect(result.errors[0]).toMatch(/^\.awesome-slash\/patterns\/broken\.js: /);
+    });
+  });
+});
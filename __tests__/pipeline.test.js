@@ -11,12 +11,16 @@ const {
   runPipeline,
   runPhase1,
   runMultiPassAnalyzers,
+  runRuntimeChecks,
   buildSummary,
+  evaluateSeverityGate,
   formatHandoffPrompt,
   formatCompactPrompt,
   CERTAINTY,
-  THOROUGHNESS
+  THOROUGHNESS,
+  SEVERITY_EXIT_CODES
 } = require('../lib/patterns/pipeline');
+const { slopPatterns } = require('../lib/patterns/slop-patterns');
 
 describe('pipeline', () => {
   // Test directory setup
@@ -205,6 +209,96 @@ function process(data) {
       const docRatioFindings = findings.filter(f => f.patternName === 'doc_code_ratio');
       expect(docRatioFindings.length).toBe(0);
     });
+
+    it('should report unreferenced files and exports from the repo map', () => {
+      fs.writeFileSync(path.join(tmpDir, 'index.js'), 'const { add } = require("./math");\nadd();\n');
+      fs.writeFileSync(path.join(tmpDir, 'math.js'), 'function add() {}\nfunction sub() {}\nmodule.exports = { add, sub };\n');
+      fs.writeFileSync(path.join(tmpDir, 'legacy.js'), 'module.exports = {};\n');
+      const entry = (exports, imports = []) => ({
+        language: 'javascript',
+        symbols: { exports: exports.map(([name, line]) => ({ name, kind: 'function', line })) },
+        imports: imports.map(source => ({ source, kind: 'import', line: 1 }))
+      });
+      const repoMap = {
+        files: {
+          'index.js': entry([], ['./math']),
+          'math.js': entry([['add', 1], ['sub', 2]]),
+          'legacy.js': entry([])
+        }
+      };
+
+      const findings = runMultiPassAnalyzers(tmpDir, ['index.js', 'math.js', 'legacy.js'], { repoMap, duplicates: false })
+        .filter(f => f.patternName.startsWith('dead_code_'));
+      expect(findings.map(f => [f.patternName, f.file, f.line, f.certainty])).toEqual([
+        ['dead_code_unreferenced_file', 'legacy.js', 1, CERTAINTY.LOW],
+        ['dead_code_unused_export', 'math.js', 2, CERTAINTY.MEDIUM]
+      ]);
+      expect(findings[1].details.suggestion).toBe('Delete sub: nothing references it');
+      expect(runMultiPassAnalyzers(tmpDir, ['index.js'], { repoMap, duplicates: false })
+        .filter(f => f.patternName.startsWith('dead_code_'))).toEqual([]);
+    });
+
+    it('should report comments that restate the next line with explain severity', () => {
+      fs.writeFileSync(path.join(tmpDir, 'loader.js'), 'function load(id) {\n  // get user by id\n  return getUserById(id);\n}\n');
+
+      const findings = runMultiPassAnalyzers(tmpDir, ['loader.js'])
+        .filter(f => f.patternName === 'ai_artifact_restating_comment');
+      expect(findings).toHaveLength(1);
+      expect(findings[0]).toMatchObject({ line: 2, severity: 'explain', certainty: CERTAINTY.MEDIUM });
+    });
+  });
+
+  describe('runRuntimeChecks', () => {
+    it('should flag features newer than the declared runtime minimum', () => {
+      fs.writeFileSync(path.join(tmpDir, 'app.js'), [
+        'const copy = structuredClone(input);',
+        'const sorted = items.toSorted();',
+        '// items.toSorted() in a comment is ignored'
+      ].join('\n'));
+      fs.writeFileSync(path.join(tmpDir, 'app.py'), 'type Point = tuple[int, int]\n');
+
+      const runtimes = [{ name: 'node', minimum: '18', minimumSource: 'package.json#engines.node' }];
+      const findings = runRuntimeChecks(tmpDir, ['app.js', 'app.py'], runtimes);
+
+      expect(findings).toHaveLength(1);
+      expect(findings[0]).toMatchObject({
+        file: 'app.js',
+        line: 2,
+        patternName: 'runtime_feature',
+        certainty: CERTAINTY.MEDIUM,
+        description: 'Array .toSorted()/.toReversed()/.toSpliced() requires Node 20.0; project declares 18 (package.json#engines.node)',
+        details: { runtime: 'node', feature: 'change_array_by_copy', since: '20.0', declared: '18' }
+      });
+    });
+
+    it('should check each runtime against its own language', () => {
+      fs.writeFileSync(path.join(tmpDir, 'main.go'), 'import "slices"\n\nfor i := range 10 {\n}\n');
+      fs.writeFileSync(path.join(tmpDir, 'lib.rs'), 'let Some(x) = value else { return };\n');
+
+      const findings = runRuntimeChecks(tmpDir, ['main.go', 'lib.rs'], [
+        { name: 'go', minimum: '1.21', minimumSource: 'go.mod#go' },
+        { name: 'rust', minimum: '1.60', minimumSource: 'Cargo.toml#package.rust-version' }
+      ]);
+
+      expect(findings.map(f => [f.file, f.details.feature])).toEqual([
+        ['main.go', 'range_over_int'],
+        ['lib.rs', 'let_else']
+      ]);
+    });
+
+    it('should run from runPipeline using the detected runtimes', () => {
+      fs.writeFileSync(path.join(tmpDir, 'go.mod'), 'module app\n\ngo 1.20\n');
+      fs.writeFileSync(path.join(tmpDir, 'main.go'), 'import "log/slog"\n');
+
+      const normal = runPipeline(tmpDir, { thoroughness: 'normal', targetFiles: ['main.go'] });
+      expect(normal.findings.filter(f => f.patternName === 'runtime_feature')).toHaveLength(1);
+
+      const quick = runPipeline(tmpDir, { thoroughness: 'quick', targetFiles: ['main.go'] });
+      expect(quick.findings.some(f => f.patternName === 'runtime_feature')).toBe(false);
+
+      const skipped = runPipeline(tmpDir, { thoroughness: 'normal', targetFiles: ['main.go'], runtimes: [] });
+      expect(skipped.findings.some(f => f.patternName === 'runtime_feature')).toBe(false);
+    });
   });
 
   describe('buildSummary', () => {
@@ -286,6 +380,33 @@ function process(data) {
     });
   });
 
+  describe('evaluateSeverityGate', () => {
+    const counts = { critical: 0, high: 2, medium: 3, low: 4 };
+
+    it('should fail only on critical findings by default', () => {
+      expect(evaluateSeverityGate(counts)).toEqual({ exitCode: 0, failing: {}, warnings: { high: 2, medium: 3 } });
+      expect(evaluateSeverityGate({ ...counts, critical: 1 }).exitCode).toBe(SEVERITY_EXIT_CODES.critical);
+    });
+
+    it('should fail with the exit code of the most severe failing finding', () => {
+      expect(evaluateSeverityGate(counts, { failOn: 'high' })).toEqual({ exitCode: 3, failing: { high: 2 }, warnings: { medium: 3 } });
+      expect(evaluateSeverityGate({ low: 1 }, { failOn: 'low' }).exitCode).toBe(5);
+    });
+
+    it('should rank explain below low and ignore it unless asked', () => {
+      expect(evaluateSeverityGate({ explain: 7 }, { failOn: 'low', warnOn: 'low' })).toEqual({ exitCode: 0, failing: {}, warnings: {} });
+      expect(evaluateSeverityGate({ explain: 7 }, { warnOn: 'explain' }).warnings).toEqual({ explain: 7 });
+      expect(evaluateSeverityGate({ explain: 7 }, { failOn: 'explain' }).exitCode).toBe(SEVERITY_EXIT_CODES.explain);
+    });
+
+    it('should support disabling either threshold', () => {
+      expect(evaluateSeverityGate(counts, { failOn: 'none', warnOn: 'none' })).toEqual({ exitCode: 0, failing: {}, warnings: {} });
+      expect(evaluateSeverityGate(counts, { failOn: 'medium', warnOn: 'low' })).toEqual({
+        exitCode: 3, failing: { high: 2, medium: 3 }, warnings: { low: 4 }
+      });
+    });
+  });
+
   describe('formatHandoffPrompt', () => {
     it('should return no issues message for empty findings', () => {
       const prompt = formatHandoffPrompt([], 'report');
@@ -635,6 +756,97 @@ function foo() {
       expect(result.phase3Prompt).toContain('Mode: **apply**');
       expect(result.phase3Prompt).toContain('HIGH Certainty');
     });
+
+    it('should skip findings the project linters already enforce', () => {
+      fs.writeFileSync(path.join(tmpDir, '.eslintrc.json'), '{"rules":{"no-console":"error"}}');
+      fs.writeFileSync(path.join(tmpDir, 'app.js'), 'console.log("debug");\n');
+      fs.writeFileSync(path.join(tmpDir, 'app.py'), 'print("debug")\n');
+
+      const result = runPipeline(tmpDir, { thoroughness: 'quick', targetFiles: ['app.js', 'app.py'] });
+
+      expect(result.findings.some(f => f.patternName === 'console_debugging')).toBe(false);
+      expect(result.findings.some(f => f.patternName === 'python_debugging')).toBe(true);
+      expect(result.linterEnforced).toEqual([
+        { patternName: 'console_debugging', enforcedBy: 'eslint:no-console', count: 1 }
+      ]);
+
+      const all = runPipeline(tmpDir, { thoroughness: 'quick', targetFiles: ['app.js'], includeLinterEnforced: true });
+      expect(all.findings.some(f => f.patternName === 'console_debugging')).toBe(true);
+      expect(all.linterEnforced).toEqual([]);
+    });
+
+    it('should skip Rust inline tests and report dead_code allows once per file', () => {
+      fs.mkdirSync(path.join(tmpDir, 'src'));
+      fs.writeFileSync(path.join(tmpDir, 'src/lib.rs'), [
+        '#[allow(dead_code)]',
+        'fn a() {}',
+        '#[allow(dead_code)]',
+        'fn b() {}',
+        '#[allow(dead_code)]',
+        'fn c() {}',
+        'pub fn run(v: Option<u32>) -> u32 { v.unwrap() }',
+        '#[cfg(test)]',
+        'mod tests {',
+        '    #[test]',
+        '    fn works() { assert_eq!(super::run(Some(1)), 1); Some(2).unwrap(); }',
+        '}'
+      ].join('\n'));
+
+      const findings = runPhase1(tmpDir, ['src/lib.rs'], 'rust');
+      const rust = findings.filter(f => f.patternName.startsWith('rust_'));
+
+      expect(rust.map(f => `${f.line}:${f.patternName}`)).toEqual(['7:rust_unwrap', '1:rust_allow_dead_code']);
+      expect(rust[1].details).toEqual({ lines: [1, 3, 5], count: 3 });
+    });
+
+    it('should report multiline matches at their first line', () => {
+      fs.writeFileSync(path.join(tmpDir, 'svc.py'), [
+        'from typing import Any',
+        '',
+        '',
+        'def load(',
+        '    path: str,',
+        '    opts: Any = None,',
+        ') -> dict:',
+        '    try:',
+        '        return read(path)',
+        '    except Exception:',
+        '        pass'
+      ].join('\n'));
+
+      const findings = runPhase1(tmpDir, ['svc.py'], 'python');
+
+      expect(findings.filter(f => f.patternName === 'any_typed_public_py').map(f => f.line)).toEqual([4]);
+      expect(findings.filter(f => f.patternName === 'broad_except_pass_py').map(f => f.line)).toEqual([10]);
+    });
+
+    it('should run test-scoped patterns on test files only', () => {
+      fs.writeFileSync(path.join(tmpDir, 'worker.go'), 'package worker\n\nfunc Wait() {\n\ttime.Sleep(time.Second)\n}\n');
+      fs.writeFileSync(path.join(tmpDir, 'worker_test.go'), 'package worker\n\nfunc TestWait(t *testing.T) {\n\ttime.Sleep(time.Second)\n\tfmt.Println("done")\n}\n');
+
+      const result = runPipeline(tmpDir, { thoroughness: 'quick', linters: [], baseline: false });
+
+      expect(result.findings.map(f => `${f.file}:${f.line}:${f.patternName}`)).toEqual(['worker_test.go:4:go_sleep_in_tests']);
+    });
+
+    it('should report one secret per line, check entropy, and redact on request', () => {
+      fs.writeFileSync(path.join(tmpDir, '.env'), 'STRIPE_KEY=rk_9f8AbQ2mZx71LpWe\n');
+      fs.writeFileSync(path.join(tmpDir, 'config.js'), [
+        'const secretToken = "q8Zr2LmXv9TbWn4Kc7Ys";',
+        'const apiTokenHash = "a1a1a1a1a1a1a1a1a1a1";',
+        'charge("rk_9f8AbQ2mZx71LpWe");',
+        ''
+      ].join('\n'));
+      const options = { targetFiles: ['config.js'], linters: [], baseline: false, runtimes: [], repoMap: null, duplicates: false };
+
+      const plain = runPipeline(tmpDir, options).findings.filter(f => slopPatterns[f.patternName]?.category === 'secrets');
+      expect(plain.map(f => `${f.line}:${f.patternName}`)).toEqual(['1:hardcoded_secrets', '3:env_value_in_source']);
+      expect(plain[0].content).toContain('q8Zr2LmXv9TbWn4Kc7Ys');
+      expect(plain[1].content).not.toContain('rk_9f8AbQ2mZx71LpWe');
+
+      const redacted = runPipeline(tmpDir, { ...options, redactSecrets: true }).findings;
+      expect(redacted.map(f => f.content).join('\n')).not.toMatch(/q8Zr2LmXv9TbWn4Kc7Ys|rk_9f8AbQ2mZx71LpWe/);
+    });
   });
 
   describe('mode inheritance', () => {
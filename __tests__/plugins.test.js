@@ -0,0 +1,184 @@
+/**
+ * Tests for lib/plugins
+ */
+
+const fs = require('fs');
+const os = require('os');
+const path = require('path');
+
+describe('plugins', () => {
+  const originalStateDir = process.env.AI_STATE_DIR;
+  const detect = async () => ({ projectType: 'nodejs', ci: 'github-actions' });
+  let root;
+
+  function write(files) {
+    for (const [file, content] of Object.entries(files)) {
+      const fullPath = path.join(root, file);
+      fs.mkdirSync(path.dirname(fullPath), { recursive: true });
+      fs.writeFileSync(fullPath, content);
+    }
+  }
+
+  // Fresh registries, like a new process
+  function load() {
+    let modules;
+    jest.isolateModules(() => {
+      modules = {
+        plugins: require('../lib/plugins'),
+        detectors: require('../lib/platform/detector-registry')
+      };
+    });
+    return modules;
+  }
+
+  const command = name => `module.exports = ({ registerCommand }) => registerCommand({
+  name: '${name}',
+  description: 'Echo the context',
+  run: (context, args) => ({ projectType: context.detection.projectType, config: context.config, args })
+});
+`;
+
+  beforeEach(() => {
+    root = fs.realpathSync(fs.mkdtempSync(path.join(os.tmpdir(), 'plugins-')));
+    process.env.AI_STATE_DIR = '.state';
+  });
+
+  afterEach(() => {
+    if (originalStateDir === undefined) delete process.env.AI_STATE_DIR;
+    else process.env.AI_STATE_DIR = originalStateDir;
+    fs.rmSync(root, { recursive: true, force: true });
+  });
+
+  it('should validate command definitions', () => {
+    const { plugins } = load();
+    expect(() => plugins.registerCommand({ name: 'Bad Name', run() {} })).toThrow('Invalid command name');
+    expect(() => plugins.registerCommand({ name: 'no-run' })).toThrow('must provide a run() function');
+    expect(() => plugins.registerCommand({ name: 'deslop', run() {} })).toThrow('is a built-in command');
+
+    plugins.registerCommand({ name: 'hotspots', run() {}, plugin: 'acme' });
+    expect(() => plugins.registerCommand({ name: 'hotspots', run() {} })).toThrow('already registered by acme');
+    expect(plugins.getCommands().map(c => c.name)).toEqual(['hotspots']);
+    expect(plugins.unregisterCommand('hotspots')).toBe(true);
+    expect(plugins.getCommand('hotspots')).toBeNull();
+  });
+
+  it('should list every built-in command', () => {
+    const { plugins } = load();
+    const pluginsDir = path.join(__dirname, '..', 'plugins');
+    const files = fs.readdirSync(pluginsDir).flatMap(plugin => {
+      const dir = path.join(pluginsDir, plugin, 'commands');
+      return fs.existsSync(dir) ? fs.readdirSync(dir).map(file => file.replace(/\.md$/, '')) : [];
+    });
+    for (const name of plugins.BUILTIN_COMMANDS) expect(files).toContain(name);
+    // The rest are parts of a command (e.g. ship-ci-review-loop)
+    for (const name of files) {
+      expect(plugins.BUILTIN_COMMANDS.some(builtin => name === builtin || name.startsWith(`${builtin}-`))).toBe(true);
+    }
+  });
+
+  it('should discover packages, project plugins, and configured paths', () => {
+    write({
+      'node_modules/awesome-slash-plugin-licenses/index.js': command('licenses'),
+      'node_modules/@acme/awsome-slash-plugin-deploy/package.json': '{"main":"lib/main.js"}',
+      'node_modules/@acme/awsome-slash-plugin-deploy/lib/main.js': command('deploy-check'),
+      'node_modules/awesome-slash-plugin-/index.js': command('empty'),
+      'node_modules/lodash/index.js': '',
+      '.state/plugins/notes.js': command('notes'),
+      '.state/plugins/legacy/index.js': command('legacy'),
+      'tools/slash.js': command('slash'),
+      '.awesome-slash.json': '{"plugins":{"paths":["tools/slash.js"],"disable":["legacy"]}}'
+    });
+    const { plugins } = load();
+
+    expect(plugins.discoverPlugins(root).map(p => [p.name, p.source, p.kind])).toEqual([
+      ['@acme/awsome-slash-plugin-deploy', 'node_modules/@acme/awsome-slash-plugin-deploy', 'package'],
+      ['awesome-slash-plugin-licenses', 'node_modules/awesome-slash-plugin-licenses', 'package'],
+      ['notes', '.state/plugins/notes.js', 'project'],
+      ['slash', 'tools/slash.js', 'config']
+    ]);
+
+    const { plugins: loaded, errors } = plugins.loadPlugins(root);
+    expect(errors).toEqual([]);
+    expect(loaded.map(p => p.commands)).toEqual([['deploy-check'], ['licenses'], ['notes'], ['slash']]);
+    expect(plugins.getCommand('notes')).toMatchObject({ plugin: 'notes', source: '.state/plugins/notes.js' });
+  });
+
+  it('should report broken plugins and undo their registrations', () => {
+    write({
+      '.state/plugins/a-broken.js': 'throw new Error("boom");\n',
+      '.state/plugins/b-half.js': `module.exports = ({ registerCommand, registerDetector }) => {
+  registerCommand({ name: 'half', run() {} });
+  registerDetector({ name: 'half-detector', detect: () => null });
+  registerCommand({ name: 'ship', run() {} });
+};
+`,
+      '.state/plugins/c-empty.js': 'module.exports = 42;\n',
+      '.state/plugins/d-detector.js': "module.exports = { detectors: [{ name: 'acme-paas', detect: () => null }] };\n"
+    });
+    const { plugins, detectors } = load();
+
+    const { plugins: loaded, errors } = plugins.loadPlugins(root);
+    expect(errors).toEqual([
+      { plugin: 'a-broken', source: '.state/plugins/a-broken.js', error: 'boom' },
+      { plugin: 'b-half', source: '.state/plugins/b-half.js', error: 'Command "ship" is a built-in command' },
+      { plugin: 'c-empty', source: '.state/plugins/c-empty.js', error: 'Plugin must export a function or an object with commands' }
+    ]);
+    expect(loaded).toEqual([
+      { name: 'd-detector', source: '.state/plugins/d-detector.js', kind: 'project', commands: [], detectors: ['acme-paas'] }
+    ]);
+    expect(plugins.getCommands()).toEqual([]);
+    expect(detectors.getDetectors().map(d => d.name)).toEqual(['acme-paas']);
+    expect(plugins.loadPlugins(root)).toBe(plugins.loadPlugins(root));
+  });
+
+  it('should report invalid plugin settings', () => {
+    write({ '.awesome-slash.json': '{"plugins":{"paths":"tools"}}' });
+    const { plugins } = load();
+    expect(plugins.loadPlugins(root).errors).toEqual([
+      { plugin: null, source: '.awesome-slash.json', error: '.awesome-slash.json: plugins.paths must be an array of strings' }
+    ]);
+  });
+
+  it('should run commands with detection, repo map, and config', async () => {
+    write({
+      '.state/plugins/echo.js': command('echo'),
+      '.state/plugins/fails.js': "module.exports = { commands: [{ name: 'fails', run: async () => { throw new Error('nope'); } }] };\n",
+      '.awesome-slash.json': '{"branchStrategy":"gitflow"}'
+    });
+    const { plugins } = load();
+
+    expect(await plugins.runCommand('echo', ['--json'], { basePath: root, detect })).toEqual({
+      success: true,
+      command: 'echo',
+      result: { projectType: 'nodejs', config: { branchStrategy: 'gitflow' }, args: ['--json'] }
+    });
+    expect(await plugins.runCommand('fails', [], { basePath: root, detect })).toEqual({ success: false, command: 'fails', error: 'nope' });
+    expect((await plugins.runCommand('deslop', [], { basePath: root, detect })).error).toContain('is built in');
+
+    const context = await plugins.createContext(root, { detect });
+    expect(context.configFile).toBe('.awesome-slash.json');
+    expect(context.repoMap.exists()).toBe(false);
+    expect(context.repoMap.findSymbols('parse')).toEqual(expect.objectContaining({ total: 0 }));
+  });
+
+  it('should sync slash command wrappers without touching user files', () => {
+    write({
+      '.state/plugins/cmds.js': `module.exports = { commands: [
+  { name: 'hotspots', description: 'Most-called symbols.', argumentHint: '[symbol]', run() {} },
+  { name: 'mine', run() {} }
+] };
+`,
+      '.claude/commands/mine.md': '# My own command\n'
+    });
+    const { plugins } = load();
+    plugins.loadPlugins(root);
+
+    expect(plugins.syncCommandFiles(root)).toEqual({ written: ['.claude/commands/hotspots.md'], skipped: ['.claude/commands/mine.md'] });
+    const content = fs.readFileSync(path.join(root, '.claude/commands/hotspots.md'), 'utf8');
+    expect(content).toContain('description: "Most-called symbols"');
+    expect(content).toContain('argument-hint: "[symbol]"');
+    expect(content).toContain('npx --no-install awesome-slash run hotspots $ARGUMENTS');
+    expect(fs.readFileSync(path.join(root, '.claude/commands/mine.md'), 'utf8')).toBe('# My own command\n');
+    expect(plugins.syncCommandFiles(root).written).toEqual([]);
+  });
+});
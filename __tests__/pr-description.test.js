@@ -0,0 +1,143 @@
+/**
+ * Tests for PR title and description generation
+ */
+
+const fs = require('fs');
+const os = require('os');
+const path = require('path');
+
+const { parseCommit } = require('../lib/changelog');
+const {
+  findPrTemplate,
+  parseTemplate,
+  groupFiles,
+  assessRisk,
+  suggestTitle,
+  buildDescription,
+  hasToken,
+  updateCommand
+} = require('../lib/pr-description');
+
+const commits = subjects => subjects.map((subject, i) => parseCommit({ hash: String(i), subject }));
+
+describe('pr-description', () => {
+  describe('findPrTemplate', () => {
+    let dir;
+
+    beforeEach(() => {
+      dir = fs.mkdtempSync(path.join(os.tmpdir(), 'pr-template-'));
+    });
+
+    afterEach(() => {
+      fs.rmSync(dir, { recursive: true, force: true });
+    });
+
+    it('should find templates in GitHub lookup order', () => {
+      expect(findPrTemplate(dir)).toBeNull();
+      fs.mkdirSync(path.join(dir, '.github', 'PULL_REQUEST_TEMPLATE'), { recursive: true });
+      fs.writeFileSync(path.join(dir, '.github', 'PULL_REQUEST_TEMPLATE', 'feature.md'), '## Feature\n');
+      expect(findPrTemplate(dir).file).toBe('.github/PULL_REQUEST_TEMPLATE/feature.md');
+      fs.writeFileSync(path.join(dir, '.github', 'pull_request_template.md'), '## Summary\n');
+      expect(findPrTemplate(dir)).toEqual({ file: '.github/pull_request_template.md', content: '## Summary\n' });
+    });
+  });
+
+  describe('parseTemplate', () => {
+    it('should map headings to generated sections once each', () => {
+      const { preamble, sections } = parseTemplate('<!-- Thanks! -->\n## What does this PR do?\n\n## How was it tested?\n- [ ] Unit\n\n## Checklist\n- [ ] Docs\n\n## Description\n');
+      expect(preamble).toBe('<!-- Thanks! -->');
+      expect(sections.map(section => [section.heading, section.section])).toEqual([
+        ['What does this PR do?', 'summary'],
+        ['How was it tested?', 'testPlan'],
+        ['Checklist', null],
+        ['Description', null]
+      ]);
+      expect(sections[2].body.trim()).toBe('- [ ] Docs');
+    });
+  });
+
+  describe('groupFiles', () => {
+    it('should group files by kind', () => {
+      const groups = groupFiles([
+        { file: 'src/api.js', status: 'modified' },
+        { file: '__tests__/api.test.js', status: 'added' },
+        { file: 'db/migrate/20240101_add_users.rb', status: 'added' },
+        { file: '.github/workflows/ci.yml', status: 'modified' },
+        { file: 'package.json', status: 'modified' },
+        { file: 'README.md', status: 'modified' }
+      ]);
+      expect(groups.map(group => [group.name, group.files.length])).toEqual([
+        ['Migrations', 1], ['Tests', 1], ['CI', 1], ['Docs', 1], ['Dependencies', 1], ['Source', 1]
+      ]);
+    });
+  });
+
+  describe('assessRisk', () => {
+    it('should rate low, medium and high risk changes', () => {
+      expect(assessRisk({ files: [{ file: 'src/a.js', status: 'modified' }, { file: 'src/a.test.js', status: 'modified' }] })).toEqual({ level: 'low', factors: [] });
+      expect(assessRisk({ files: [{ file: 'src/a.js', status: 'modified' }] }).level).toBe('medium');
+      const high = assessRisk({
+        files: [{ file: 'migrations/0002_drop.sql', status: 'added' }, { file: 'src/a.test.js', status: 'added' }],
+        breaking: [{ name: 'getUser', change: 'removed' }]
+      });
+      expect(high.level).toBe('high');
+      expect(high.factors[0]).toContain('getUser: removed');
+    });
+  });
+
+  describe('suggestTitle', () => {
+    it('should use the most significant commit', () => {
+      expect(suggestTitle(commits(['fix(api): handle empty body (#12)']))).toBe('fix(api): handle empty body');
+      expect(suggestTitle(commits(['fix(cart): round totals', 'feat(cart): add coupons', 'test(cart): cover coupons']))).toBe('feat(cart): add coupons');
+      expect(suggestTitle(commits(['fix: a', 'feat(ui)!: b']))).toBe('feat(ui)!: b');
+      expect(suggestTitle([], 'feature/add-dark-mode')).toBe('add dark mode');
+    });
+  });
+
+  describe('buildDescription', () => {
+    const files = [
+      { file: 'src/users.js', status: 'modified' },
+      { file: 'src/old.js', status: 'renamed', from: 'src/legacy.js' },
+      { file: '__tests__/users.test.js', status: 'modified' }
+    ];
+    const diff = {
+      symbols: {
+        added: [{ name: 'listUsers', file: 'src/users.js', exported: true }],
+        removed: [{ name: 'getUsers', file: 'src/users.js', exported: true, kind: 'function', line: 3 }],
+        renamed: [],
+        changed: []
+      }
+    };
+
+    it('should render the default sections', () => {
+      const result = buildDescription({ files, diff, commits: commits(['feat(users): paginate the list']), testCommand: 'npm test' });
+      expect(result.title).toBe('feat(users): paginate the list');
+      expect(result.template).toBeNull();
+      expect(result.risk).toBe('high');
+      expect(result.body).toContain('## Summary\n\n- **users:** paginate the list');
+      expect(result.body).toContain('- `src/old.js` (renamed from `src/legacy.js`)');
+      expect(result.body).toContain('**Symbols**\n- Added: `listUsers`\n- Removed: `getUsers`');
+      expect(result.body).toContain('- [ ] `npm test` passes\n- [ ] `__tests__/users.test.js` covers the change');
+      expect(result.body).toContain('## Risk\n\n**High**\n- Breaking changes to public API (getUsers: removed)');
+    });
+
+    it('should fill the template headings and keep the rest', () => {
+      const template = { file: '.github/pull_request_template.md', content: '<!-- Describe it -->\n### Description\n\n### Checklist\n- [ ] Docs updated\n\n### Testing\n' };
+      const result = buildDescription({ files, commits: commits(['fix: handle nulls']), template });
+      expect(result.template).toBe('.github/pull_request_template.md');
+      expect(result.body).toMatch(/^### Description\n\n- handle nulls\n\n### Checklist\n\n- \[ \] Docs updated\n\n### Testing\n\n- \[ \] Test suite passes/);
+      expect(result.body).toContain('## Changes\n\n**Tests**');
+      expect(result.body).toContain('## Risk\n\n**Low**');
+    });
+  });
+
+  describe('updateCommand', () => {
+    it('should target the host CLI', () => {
+      expect(updateCommand('github', 'feat: x', { file: '.pr-body.md', text: 'body' })).toEqual(['gh', 'pr', 'edit', '--title', 'feat: x', '--body-file', '.pr-body.md']);
+      expect(updateCommand('gitlab', 'feat: x', { file: '.pr-body.md', text: 'body' })).toEqual(['glab', 'mr', 'update', '--title', 'feat: x', '--description', 'body']);
+      expect(updateCommand('bitbucket', 'feat: x', { file: '.pr-body.md', text: 'body' })).toBeNull();
+      expect(hasToken('github', { GITHUB_TOKEN: 'x' })).toBe(true);
+      expect(hasToken('gitlab', { GITHUB_TOKEN: 'x' })).toBe(false);
+    });
+  });
+});
@@ -0,0 +1,131 @@
+/**
+ * Tests for release planning and version bumping
+ */
+
+const fs = require('fs');
+const os = require('os');
+const path = require('path');
+
+const { parseCommit } = require('../lib/changelog');
+const { git } = require('../lib/utils/exec');
+const {
+  parseSemver,
+  recommendBump,
+  nextVersion,
+  findVersionFiles,
+  bumpContent,
+  planRelease
+} = require('../lib/release');
+
+const commits = subjects => subjects.map((subject, i) => parseCommit({ hash: String(i), subject }));
+
+describe('release', () => {
+  describe('parseSemver', () => {
+    it('should parse versions with prefixes and prereleases', () => {
+      expect(parseSemver('v1.2.3-rc.1+build.5')).toEqual({ major: 1, minor: 2, patch: 3, prerelease: 'rc.1' });
+      expect(parseSemver('1.2')).toBeNull();
+    });
+  });
+
+  describe('recommendBump', () => {
+    it('should follow conventional commit types', () => {
+      expect(recommendBump(commits(['fix: a', 'feat!: b']), '1.4.0')).toBe('major');
+      expect(recommendBump(commits(['feat(api)!: b']), '0.4.0')).toBe('minor');
+      expect(recommendBump(commits(['fix: a', 'feat: b']), '1.4.0')).toBe('minor');
+      expect(recommendBump(commits(['perf: a']), '1.4.0')).toBe('patch');
+      expect(recommendBump(commits(['chore: a', 'docs: b']), '1.4.0')).toBeNull();
+    });
+  });
+
+  describe('nextVersion', () => {
+    it('should bump and handle prereleases', () => {
+      expect(nextVersion('1.4.2', 'major')).toBe('2.0.0');
+      expect(nextVersion('1.4.2', 'minor')).toBe('1.5.0');
+      expect(nextVersion('1.4.2', 'patch')).toBe('1.4.3');
+      expect(nextVersion('1.4.2', 'minor', 'rc')).toBe('1.5.0-rc.0');
+      expect(nextVersion('1.5.0-rc.0', 'minor', 'rc')).toBe('1.5.0-rc.1');
+      expect(nextVersion('1.5.0-rc.1', 'minor')).toBe('1.5.0');
+      expect(nextVersion('1.5.0-rc.1', 'major')).toBe('2.0.0');
+      expect(nextVersion('1.4.2', 'huge')).toBeNull();
+      expect(nextVersion('1.4.2', 'patch', 'rc.*')).toBeNull();
+    });
+  });
+
+  describe('findVersionFiles', () => {
+    it('should read versions from the files for the project type', () => {
+      const files = {
+        'pyproject.toml': '[build-system]\nrequires = ["hatchling"]\n\n[project]\nname = "shop"\nversion = "0.9.1"\n',
+        'setup.cfg': '[metadata]\nname = shop\n'
+      };
+      expect(findVersionFiles('/repo', 'python', file => files[file] ?? null)).toEqual([{ file: 'pyproject.toml', version: '0.9.1' }]);
+      expect(findVersionFiles('/repo', 'go', () => 'package main\n\nconst Version = "v1.2.0"\n')).toEqual([
+        { file: 'version.go', version: '1.2.0' },
+        { file: 'internal/version/version.go', version: '1.2.0' }
+      ]);
+      expect(findVersionFiles('/repo', 'unknown', () => '')).toEqual([]);
+    });
+  });
+
+  describe('bumpContent', () => {
+    it('should replace only the package version', () => {
+      const pkg = '{\n  "name": "shop",\n  "version": "1.0.0",\n  "dependencies": { "x": "^1.0.0" }\n}\n';
+      expect(bumpContent('nodejs', 'package.json', pkg, '1.1.0')).toBe(pkg.replace('"version": "1.0.0"', '"version": "1.1.0"'));
+
+      const lock = '{\n  "name": "shop",\n  "version": "1.0.0",\n  "packages": {\n    "": {\n      "name": "shop",\n      "version": "1.0.0"\n    },\n    "node_modules/x": {\n      "version": "1.0.0"\n    }\n  }\n}\n';
+      const bumped = bumpContent('nodejs', 'package-lock.json', lock, '1.1.0');
+      expect(bumped.match(/"version": "1\.1\.0"/g)).toHaveLength(2);
+      expect(bumped).toContain('"node_modules/x": {\n      "version": "1.0.0"');
+    });
+
+    it('should bump Cargo.toml, pyproject.toml and Go constants', () => {
+      const cargo = '[package]\nname = "shop"\nversion = "0.3.0"\n\n[dependencies]\nserde = { version = "1" }\n';
+      expect(bumpContent('rust', 'Cargo.toml', cargo, '0.4.0')).toBe(cargo.replace('version = "0.3.0"', 'version = "0.4.0"'));
+      expect(bumpContent('python', 'pyproject.toml', '[tool.poetry]\nversion = \'1.0.0\'\n', '1.0.1')).toBe('[tool.poetry]\nversion = \'1.0.1\'\n');
+      expect(bumpContent('go', 'version.go', 'const Version = "v1.2.0"\n', '1.3.0')).toBe('const Version = "v1.3.0"\n');
+      expect(bumpContent('go', 'version.go', 'var Version string = "1.2.0"\n', '1.3.0')).toBe('var Version string = "1.3.0"\n');
+      expect(bumpContent('rust', 'Cargo.toml', '[workspace]\nmembers = []\n', '1.0.0')).toBeNull();
+    });
+
+    it('should find the version after array values in the same table', () => {
+      const cargo = '[package]\nname = "shop"\nauthors = ["Ada <ada@example.com>"]\nkeywords = ["cli", "shop"]\nversion = "0.3.0"\n\n[dependencies]\nserde = { version = "1" }\n';
+      expect(bumpContent('rust', 'Cargo.toml', cargo, '0.4.0')).toBe(cargo.replace('version = "0.3.0"', 'version = "0.4.0"'));
+      const pyproject = '[project]\nname = "shop"\nauthors = [{ name = "Ada" }]\nclassifiers = [\n  "Programming Language :: Python",\n]\nversion = "0.9.1"\n';
+      expect(findVersionFiles('/repo', 'python', file => (file === 'pyproject.toml' ? pyproject : null))).toEqual([{ file: 'pyproject.toml', version: '0.9.1' }]);
+      expect(bumpContent('python', 'pyproject.toml', pyproject, '0.10.0')).toBe(pyproject.replace('0.9.1', '0.10.0'));
+      // A version key in a later table is not the package version
+      expect(bumpContent('rust', 'Cargo.toml', '[package]\nname = "shop"\n\n[dependencies.serde]\nversion = "1"\n', '2.0.0')).toBeNull();
+    });
+  });
+
+  describe('planRelease', () => {
+    let dir;
+
+    beforeEach(() => {
+      dir = fs.mkdtempSync(path.join(os.tmpdir(), 'release-'));
+      git(dir, ['init', '-q']);
+      git(dir, ['-c', 'user.name=t', '-c', 'user.email=t@example.com', 'commit', '-q', '--allow-empty', '-m', 'feat: add checkout']);
+    });
+
+    afterEach(() => {
+      fs.rmSync(dir, { recursive: true, force: true });
+    });
+
+    it('should stage the version files and CHANGELOG.md before tagging', () => {
+      fs.writeFileSync(path.join(dir, 'package.json'), '{\n  "name": "shop",\n  "version": "1.0.0"\n}\n');
+      fs.writeFileSync(path.join(dir, 'CHANGELOG.md'), '# Changelog\n');
+      const plan = planRelease(dir, { projectType: 'nodejs' });
+      expect(plan).toMatchObject({ success: true, version: '1.1.0', tag: 'v1.1.0' });
+      expect(plan.commands.slice(0, 3)).toEqual([
+        ['git', 'add', 'package.json', 'CHANGELOG.md'],
+        ['git', 'commit', '-m', 'chore(release): v1.1.0'],
+        ['git', 'tag', '-a', 'v1.1.0', '-m', 'v1.1.0']
+      ]);
+    });
+
+    it('should only tag when there is nothing to write', () => {
+      const plan = planRelease(dir, { projectType: 'go' });
+      expect(plan).toMatchObject({ success: true, current: '0.0.0', version: '0.1.0', files: [] });
+      expect(plan.commands.map(argv => argv.slice(0, 2))).toEqual([['git', 'tag'], ['git', 'push']]);
+    });
+  });
+});
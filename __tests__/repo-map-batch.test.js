@@ -4,6 +4,8 @@ const path = require('path');
 
 const installer = require('../lib/repo-map/installer');
 const runner = require('../lib/repo-map/runner');
+const { linkHeaderDeclarations } = require('../lib/repo-map/headers');
+const { linkGoMethods } = require('../lib/repo-map/golang');
 
 const fixtureRoot = path.join(__dirname, 'fixtures', 'repo-map');
 const languages = Object.keys(runner.LANGUAGE_EXTENSIONS);
@@ -79,6 +81,8 @@ function buildExpectedMap(basePath, langs, cmd) {
     }
   }
 
+  linkGoMethods(expected);
+  linkHeaderDeclarations(expected);
   return expected;
 }
 
@@ -0,0 +1,172 @@
+/**
+ * Tests for repo-map call-site extraction and cross-file call graph
+ */
+
+const { extractCallSites, buildCallGraph } = require('../lib/repo-map/calls');
+const { createFileIndex, resolveImport } = require('../lib/repo-map/imports');
+
+/**
+ * Build a minimal file entry
+ */
+function fileEntry(language, { functions = [], classes = [], imports = [], calls = [] } = {}) {
+  return {
+    language,
+    symbols: { exports: [], functions, classes, types: [], constants: [] },
+    imports,
+    calls
+  };
+}
+
+describe('extractCallSites', () => {
+  test('skips comments, strings, and member calls on values', () => {
+    const content = [
+      "import { add } from './math';",
+      '// add(ignored)',
+      "const label = 'add(nope)';",
+      'export function total(xs) { return xs.reduce((a, b) => add(a, b), 0); }',
+      'utils.format(x); this.run(); obj.items.map(f);'
+    ].join('\n');
+
+    expect(extractCallSites('javascript', content)).toEqual([
+      { name: 'total', line: 4 },
+      { name: 'reduce', line: 4, qualifier: 'xs' },
+      { name: 'add', line: 4 },
+      { name: 'format', line: 5, qualifier: 'utils' }
+    ]);
+  });
+
+  test('ignores python docstrings and hash comments', () => {
+    const content = 'def load():\n    """Calls fetch() eventually"""\n    # parse(x)\n    return models.fetch(1)\n';
+    expect(extractCallSites('python', content)).toEqual([
+      { name: 'load', line: 1 },
+      { name: 'fetch', line: 4, qualifier: 'models' }
+    ]);
+  });
+
+  test('keeps rust path calls and survives lifetimes', () => {
+    expect(extractCallSites('rust', "fn a<'a>(x: &'a str) { Foo::new(x); b('c'); }")).toEqual([
+      { name: 'new', line: 1, qualifier: 'Foo' },
+      { name: 'b', line: 1 }
+    ]);
+  });
+});
+
+describe('resolveImport', () => {
+  const index = createFileIndex([
+    'src/math.ts',
+    'src/utils/index.js',
+    'pkg/models/__init__.py',
+    'pkg/models/user.py',
+    'internal/store/store.go',
+    'internal/store/store_test.go',
+    'src/main/java/com/acme/Util.java'
+  ]);
+
+  test('probes relative JavaScript specifiers', () => {
+    expect(resolveImport('src/app.ts', './math', 'typescript', index)).toEqual(['src/math.ts']);
+    expect(resolveImport('src/app.js', './utils', 'javascript', index)).toEqual(['src/utils/index.js']);
+    expect(resolveImport('src/app.js', 'lodash', 'javascript', index)).toEqual([]);
+  });
+
+  test('resolves python, go, and java module paths', () => {
+    expect(resolveImport('pkg/api.py', 'pkg.models.user', 'python', index)).toEqual(['pkg/models/user.py']);
+    expect(resolveImport('pkg/models/user.py', '.', 'python', index)).toEqual(['pkg/models/__init__.py']);
+    expect(resolveImport('cmd/main.go', 'github.com/acme/app/internal/store', 'go', index)).toEqual(['internal/store/store.go']);
+    expect(resolveImport('src/main/java/com/acme/App.java', 'com.acme.Util', 'java', index))
+      .toEqual(['src/main/java/com/acme/Util.java']);
+  });
+});
+
+describe('buildCallGraph', () => {
+  test('resolves calls through imports and namespace aliases', () => {
+    const map = {
+      files: {
+        'src/math.js': fileEntry('javascript', {
+          functions: [{ name: 'add', line: 1, exported: true }]
+        }),
+        'src/utils.js': fileEntry('javascript', {
+          functions: [{ name: 'format', line: 3, exported: true }]
+        }),
+        'src/app.js': fileEntry('javascript', {
+          imports: [{ source: './math', kind: 'named' }, { source: './utils', kind: 'require' }],
+          calls: [{ name: 'add', line: 4 }, { name: 'format', line: 7, qualifier: 'utils' }]
+        })
+      }
+    };
+
+    expect(buildCallGraph(map)).toEqual({
+      'src/math.js': { add: [{ file: 'src/app.js', line: 4 }] },
+      'src/utils.js': { format: [{ file: 'src/app.js', line: 7 }] }
+    });
+  });
+
+  test('skips locally defined names and other languages', () => {
+    const map = {
+      files: {
+        'a.js': fileEntry('javascript', {
+          functions: [{ name: 'run', line: 1, exported: true }],
+          calls: [{ name: 'run', line: 5 }]
+        }),
+        'b.js': fileEntry('javascript', { functions: [{ name: 'run', line: 1, exported: true }] }),
+        'c.py': fileEntry('python', { calls: [{ name: 'run', line: 2 }] })
+      }
+    };
+
+    expect(buildCallGraph(map)).toEqual({});
+  });
+
+  test('links go calls within a package and across imports', () => {
+    const map = {
+      files: {
+        'store/store.go': fileEntry('go', { functions: [{ name: 'Open', line: 5, exported: true }] }),
+        'store/cache.go': fileEntry('go', { calls: [{ name: 'Open', line: 9 }] }),
+        'cmd/main.go': fileEntry('go', {
+          imports: [{ source: 'example.com/app/store', kind: 'import' }],
+          calls: [{ name: 'Open', line: 12, qualifier: 'store' }]
+        })
+      }
+    };
+
+    expect(buildCallGraph(map)).toEqual({
+      'store/store.go': {
+        Open: [{ file: 'cmd/main.go', line: 12 }, { file: 'store/cache.go', line: 9 }]
+      }
+    });
+  });
+
+  test('falls back to a unique exported definition and static class calls', () => {
+    const map = {
+      files: {
+        'lib/slug.rb': fileEntry('ruby', { functions: [{ name: 'slugify', line: 2, exported: true }] }),
+        'app/models/user.rb': fileEntry('ruby', {
+          functions: [{ name: 'build', line: 4, exported: true, parent: 'User' }]
+        }),
+        'app/controllers/users_controller.rb': fileEntry('ruby', {
+          calls: [{ name: 'slugify', line: 3 }, { name: 'build', line: 8, qualifier: 'User' }]
+        })
+      }
+    };
+
+    expect(buildCallGraph(map)).toEqual({
+      'lib/slug.rb': { slugify: [{ file: 'app/controllers/users_controller.rb', line: 3 }] },
+      'app/models/user.rb': { build: [{ file: 'app/controllers/users_controller.rb', line: 8 }] }
+    });
+  });
+
+  test('attributes header declarations to their definition file', () => {
+    const map = {
+      files: {
+        'src/shape.h': fileEntry('c', { functions: [{ name: 'area', line: 3, definedIn: 'src/shape.c' }] }),
+        'src/shape.c': fileEntry('c', { functions: [{ name: 'area', line: 10, declaredIn: 'src/shape.h' }] }),
+        'src/main.c': fileEntry('c', {
+          imports: [{ source: 'shape.h', kind: 'include' }],
+          calls: [{ name: 'area', line: 6 }]
+        })
+      }
+    };
+
+    expect(buildCallGraph(map)).toEqual({
+      'src/shape.c': { area: [{ file: 'src/main.c', line: 6 }] }
+    });
+  });
+});
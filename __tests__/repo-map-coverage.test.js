@@ -0,0 +1,128 @@
+/**
+ * Tests for repo-map coverage summaries
+ */
+
+const {
+  detectFormat,
+  parseReport,
+  resolvePaths,
+  mergeFiles,
+  coverageCommand,
+  formatRanges,
+  summarize,
+  renderSummary
+} = require('../lib/repo-map/coverage');
+
+const lines = entries => new Map(entries);
+
+describe('repo-map coverage', () => {
+  describe('parseReport', () => {
+    it('should parse lcov', () => {
+      const report = parseReport('TN:\nSF:/repo/src/cart.js\nDA:1,1\nDA:2,0\nend_of_record\nSF:src/util.js\nDA:4,3\nend_of_record\n');
+      expect(report.format).toBe('lcov');
+      expect(report.files['/repo/src/cart.js']).toEqual(lines([[1, 1], [2, 0]]));
+      expect(report.files['src/util.js']).toEqual(lines([[4, 3]]));
+    });
+
+    it('should parse Cobertura XML relative to its source', () => {
+      const xml = '<?xml version="1.0" ?>\n<coverage line-rate="0.5">\n<sources><source>/repo/app</source></sources>\n<packages><package name="app"><classes>\n' +
+        '<class name="billing.py" filename="billing.py"><lines><line number="1" hits="1"/><line number="3" hits="0" branch="false"/></lines></class>\n' +
+        '</classes></package></packages></coverage>';
+      const report = parseReport(xml);
+      expect(report.format).toBe('cobertura');
+      expect(report.files).toEqual({ '/repo/app/billing.py': lines([[1, 1], [3, 0]]) });
+    });
+
+    it('should parse Go coverprofiles as line ranges', () => {
+      const report = parseReport('mode: set\nexample.com/app/store/store.go:10.20,12.3 2 1\nexample.com/app/store/store.go:12.3,14.2 1 0\n');
+      expect(report.format).toBe('go');
+      expect(report.files['example.com/app/store/store.go']).toEqual(lines([[10, 1], [11, 1], [12, 1], [13, 0], [14, 0]]));
+    });
+
+    it('should parse coverage.py JSON', () => {
+      const report = parseReport(JSON.stringify({ meta: {}, files: { 'app/api.py': { executed_lines: [1, 2], missing_lines: [5] } } }));
+      expect(report.format).toBe('coveragepy');
+      expect(report.files['app/api.py']).toEqual(lines([[1, 1], [2, 1], [5, 0]]));
+    });
+
+    it('should not guess unknown formats', () => {
+      expect(detectFormat('hello')).toBeNull();
+      expect(parseReport('hello')).toEqual({ format: null, files: {} });
+    });
+  });
+
+  describe('resolvePaths', () => {
+    it('should map absolute and module paths to map files', () => {
+      const { files, unmatched } = resolvePaths({
+        '/repo/src/cart.js': lines([[1, 1]]),
+        'example.com/app/store/store.go': lines([[10, 0]]),
+        'node_modules/x/index.js': lines([[1, 1]])
+      }, ['src/cart.js', 'store/store.go'], '/repo');
+      expect(Object.keys(files)).toEqual(['src/cart.js', 'store/store.go']);
+      expect(unmatched).toEqual(['node_modules/x/index.js']);
+    });
+
+    it('should resolve package-relative paths and merge package reports', () => {
+      const mapFiles = ['packages/api/src/index.js', 'packages/web/src/index.js'];
+      const api = resolvePaths({ 'src/index.js': lines([[1, 1], [2, 0]]) }, mapFiles, '/repo', 'packages/api');
+      const web = resolvePaths({ 'src/index.js': lines([[1, 0]]) }, mapFiles, '/repo', 'packages/web');
+      expect(Object.keys(api.files)).toEqual(['packages/api/src/index.js']);
+
+      const merged = mergeFiles(mergeFiles({}, api.files), web.files);
+      expect(merged).toEqual({
+        'packages/api/src/index.js': lines([[1, 1], [2, 0]]),
+        'packages/web/src/index.js': lines([[1, 0]])
+      });
+      expect(coverageCommand({ frameworks: [{ name: 'ava' }, { name: 'vitest' }] })).toEqual(['npx', 'vitest', 'run', '--coverage', '--coverage.reporter=lcov']);
+      expect(coverageCommand(null)).toBeNull();
+    });
+  });
+
+  describe('formatRanges', () => {
+    it('should compact consecutive lines', () => {
+      expect(formatRanges([3, 4, 5, 9, 11, 12])).toBe('3-5, 9, 11-12');
+      expect(formatRanges([])).toBe('');
+    });
+  });
+
+  describe('summarize', () => {
+    const map = {
+      files: {
+        'src/cart.js': {
+          symbols: {
+            functions: [
+              { name: 'total', line: 1, exported: true },
+              { name: 'discount', line: 10, exported: true },
+              { name: 'round', line: 20, exported: false }
+            ],
+            classes: [{ name: 'Cart', line: 30, exported: true }]
+          }
+        },
+        'src/cart.test.js': { symbols: { functions: [] } }
+      }
+    };
+    const coverage = {
+      'src/cart.js': lines([[2, 1], [3, 1], [11, 0], [12, 0], [13, 1], [21, 0], [31, 1]]),
+      'src/cart.test.js': lines([[1, 1]])
+    };
+
+    it('should rank functions by coverage', () => {
+      const summary = summarize(map, coverage);
+      expect(summary.totals).toEqual({ covered: 4, total: 7, percent: 57.1, files: 1 });
+      expect(summary.functions.map(item => [item.name, item.percent, item.uncovered, item.target])).toEqual([
+        ['round', 0, '21', 'src/cart.js:round'],
+        ['discount', 33.3, '11-12', 'src/cart.js:discount'],
+        ['total', 100, '', 'src/cart.js:total']
+      ]);
+    });
+
+    it('should render exported functions by default', () => {
+      const text = renderSummary({ ...summarize(map, coverage), report: 'coverage/lcov.info', format: 'lcov', unmatched: [] });
+      expect(text).toContain('**Lines**: 4/7 (57.1%) across 1 files');
+      expect(text).toContain('| `discount` | src/cart.js:10 | 33.3% (1/3) | 11-12 |');
+      expect(text).not.toContain('`round`');
+      expect(text).not.toContain('`total`');
+      expect(text).toContain('| src/cart.js | 57.1% (4/7) |');
+    });
+  });
+});
@@ -0,0 +1,93 @@
+/**
+ * Tests for repo-map unreferenced file and export detection
+ */
+
+const { isEntryPoint, findUnreferenced } = require('../lib/repo-map/dead');
+
+/**
+ * Build a map from `{file: {exports, imports, language}}`
+ */
+function createMap(entries, callGraph = {}) {
+  const files = {};
+  for (const [file, entry] of Object.entries(entries)) {
+    files[file] = {
+      language: entry.language || 'javascript',
+      symbols: {
+        exports: (entry.exports || []).map(([name, line]) => ({ name, kind: 'function', line })),
+        functions: [],
+        classes: [],
+        types: [],
+        constants: []
+      },
+      imports: (entry.imports || []).map(source => ({ source, kind: 'import', line: 1 }))
+    };
+  }
+  return { version: '1.0.0', files, callGraph };
+}
+
+/**
+ * readFile over an in-memory tree
+ */
+function reader(contents) {
+  return file => {
+    if (!(file in contents)) throw new Error(`ENOENT: ${file}`);
+    return contents[file];
+  };
+}
+
+describe('isEntryPoint', () => {
+  test('recognizes conventional entry points, tests and declared files', () => {
+    expect(isEntryPoint('src/index.ts')).toBe(true);
+    expect(isEntryPoint('pkg/__main__.py')).toBe(true);
+    expect(isEntryPoint('scripts/release.js')).toBe(true);
+    expect(isEntryPoint('src/util.test.js')).toBe(true);
+    expect(isEntryPoint('vite.config.ts')).toBe(true);
+    expect(isEntryPoint('src/util.js')).toBe(false);
+    expect(isEntryPoint('src/plugin.js', new Set(['src/plugin.js']))).toBe(true);
+  });
+});
+
+describe('findUnreferenced', () => {
+  const map = createMap({
+    'src/index.js': { imports: ['./app'] },
+    'src/app.js': { imports: ['./math'], exports: [['main', 1]] },
+    'src/math.js': { exports: [['add', 1], ['round', 5], ['sub', 9]] },
+    'src/legacy.js': { exports: [['old', 1]] },
+    'src/called.js': { exports: [['helper', 1]] },
+    'pkg/orphan.py': { language: 'python' },
+    'cmd/orphan.go': { language: 'go' }
+  }, {
+    'src/called.js': { helper: [{ file: 'src/app.js', line: 4 }] }
+  });
+
+  const contents = {
+    'src/index.js': 'require("./app");\n',
+    'src/app.js': 'const { add } = require("./math");\nfunction main() { add(); helper(); }\nmodule.exports = { main };\n',
+    'src/math.js': 'function add() { return round(1); }\nfunction round(x) { return x; }\nfunction sub() {}\nmodule.exports = { add, round, sub };\n',
+    'src/legacy.js': 'exports.old = () => 1;\n',
+    'src/called.js': 'exports.helper = () => 1;\n',
+    'pkg/orphan.py': 'x = 1\n',
+    'cmd/orphan.go': 'package main\n'
+  };
+
+  test('reports files nothing imports or calls, in import-scoped languages only', () => {
+    const result = findUnreferenced(map);
+    expect(result.files.map(entry => entry.file)).toEqual(['pkg/orphan.py', 'src/legacy.js']);
+    expect(result.files[1].suggestion).toBe('Delete src/legacy.js: no file imports it or calls into it');
+    expect(result.exports).toEqual([]);
+  });
+
+  test('reports exports no other file mentions with a safe-delete suggestion', () => {
+    const result = findUnreferenced(map, { readFile: reader(contents) });
+    expect(result.exports).toEqual([
+      { file: 'src/math.js', name: 'round', kind: 'function', line: 5, usedLocally: true, suggestion: 'Stop exporting round: only src/math.js uses it' },
+      { file: 'src/math.js', name: 'sub', kind: 'function', line: 9, usedLocally: false, suggestion: 'Delete sub: nothing references it' }
+    ]);
+  });
+
+  test('treats package.json entry points and explicit entry points as used', () => {
+    const withPackage = { ...contents, 'package.json': JSON.stringify({ bin: { tool: './src/legacy.js' } }) };
+    expect(findUnreferenced(map, { readFile: reader(withPackage) }).files.map(entry => entry.file)).toEqual(['pkg/orphan.py']);
+    expect(findUnreferenced(map, { entryPoints: ['pkg/orphan.py'] }).files.map(entry => entry.file)).toEqual(['src/legacy.js']);
+  });
+});
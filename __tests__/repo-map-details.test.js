@@ -0,0 +1,534 @@
+/**
+ * Tests for repo-map language-specific symbol details
+ */
+
+const fs = require('fs');
+const path = require('path');
+
+const {
+  applySymbolDetails,
+  parsePythonDefinitions,
+  parseTypeScriptDeclarations,
+  parseJvmDeclarations,
+  parseMacros,
+  parseRubyDefinitions,
+  parseCSharpDeclarations,
+  parseGoDeclarations
+} = require('../lib/repo-map/details');
+
+const fixtureRoot = path.join(__dirname, 'fixtures', 'repo-map');
+
+function createMaps(entries = {}) {
+  const maps = {
+    exports: new Map(),
+    functions: new Map(),
+    classes: new Map(),
+    types: new Map(),
+    constants: new Map()
+  };
+  for (const [category, items] of Object.entries(entries)) {
+    for (const item of items) {
+      maps[category].set(item.name, { kind: category === 'classes' ? 'class' : 'function', ...item });
+    }
+  }
+  return maps;
+}
+
+describe('repo-map symbol details', () => {
+  describe('python', () => {
+    const content = fs.readFileSync(path.join(fixtureRoot, 'python', 'sample.py'), 'utf8');
+
+    test('parses decorators, bases, and methods', () => {
+      const { classes, functions } = parsePythonDefinitions(content);
+
+      const point = classes.find(c => c.name === 'Point');
+      expect(point.decorators).toEqual(['dataclass(frozen=True)']);
+      expect(point.methods.map(m => m.name)).toEqual(['norm']);
+      expect(point.methods[0].decorators).toEqual(['property']);
+
+      const child = classes.find(c => c.name === 'Child');
+      expect(child.bases).toEqual(['PublicClass']);
+
+      const route = functions.find(f => f.name === 'list_users');
+      expect(route.decorators).toEqual(['app.route("/users", methods=["GET"])']);
+      expect(route.parent).toBeNull();
+    });
+
+    test('moves methods under their class and marks dataclasses', () => {
+      const maps = createMaps({
+        functions: [
+          { name: 'method', line: 18 },
+          { name: 'norm', line: 32 },
+          { name: 'public_function', line: 9 },
+          { name: 'list_users', line: 42 }
+        ],
+        classes: [
+          { name: 'PublicClass', line: 17 },
+          { name: 'Point', line: 27 },
+          { name: 'Child', line: 36 }
+        ]
+      });
+
+      applySymbolDetails('python', content, maps);
+
+      expect(maps.functions.has('method')).toBe(false);
+      expect(maps.functions.has('norm')).toBe(false);
+      expect(maps.functions.get('list_users').decorators).toEqual(['app.route("/users", methods=["GET"])']);
+
+      expect(maps.classes.get('Point').kind).toBe('dataclass');
+      expect(maps.classes.get('Child').bases).toEqual(['PublicClass']);
+      expect(maps.classes.get('Child').methods).toEqual([{ name: 'method', line: 37, async: true }]);
+      expect(maps.classes.get('PublicClass').methods).toEqual([{ name: 'method', line: 18 }]);
+    });
+
+    test('keeps module-level function when a method shares its name', () => {
+      const source = [
+        'class Service:',
+        '    def run(self):',
+        '        pass',
+        '',
+        'def run():',
+        '    pass'
+      ].join('\n');
+      const maps = createMaps({ functions: [{ name: 'run', line: 2 }], classes: [{ name: 'Service', line: 1 }] });
+
+      applySymbolDetails('python', source, maps);
+
+      expect(maps.functions.get('run').line).toBe(5);
+      expect(maps.classes.get('Service').methods).toEqual([{ name: 'run', line: 2 }]);
+    });
+
+    test('ignores nested functions and docstring contents', () => {
+      const source = [
+        'def outer():',
+        '    """',
+        '    def fake():',
+        '    """',
+        '    def inner():',
+        '        pass',
+        '    return inner'
+      ].join('\n');
+
+      const { functions } = parsePythonDefinitions(source);
+
+      expect(functions.map(f => f.name)).toEqual(['outer', 'inner']);
+      expect(functions[1].nested).toBe(true);
+    });
+
+    test('handles multi-line decorators and signatures', () => {
+      const source = [
+        '@pytest.fixture(',
+        '    scope="module",',
+        ')',
+        'def client(',
+        '    app,',
+        '):',
+        '    def helper():',
+        '        pass',
+        '    return app'
+      ].join('\n');
+
+      const { functions } = parsePythonDefinitions(source);
+
+      expect(functions[0].decorators).toEqual(['pytest.fixture(scope="module",)']);
+      expect(functions[1].nested).toBe(true);
+    });
+  });
+
+  describe('typescript', () => {
+    const sample = fs.readFileSync(path.join(fixtureRoot, 'typescript', 'sample.ts'), 'utf8');
+    const component = fs.readFileSync(path.join(fixtureRoot, 'typescript', 'component.tsx'), 'utf8');
+
+    test('parses generics, heritage, and interface members', () => {
+      const { interfaces, typeAliases, classes } = parseTypeScriptDeclarations(sample);
+
+      const repo = interfaces.find(i => i.name === 'Repository');
+      expect(repo.line).toBe(17);
+      expect(repo.typeParams).toBe('<T extends { id: string }>');
+      expect(repo.extends).toEqual(['Iterable<T>']);
+      expect(repo.members).toEqual([
+        { name: 'name', type: 'string', readonly: true },
+        { name: 'find', type: '(id: string) => Promise<T | undefined>' },
+        { name: 'limit', type: 'number', optional: true }
+      ]);
+
+      const result = typeAliases.find(t => t.name === 'Result');
+      expect(result.typeParams).toBe('<T, E = Error>');
+      expect(result.definition).toBe('{ ok: true; value: T } | { ok: false; error: E }');
+      expect(result.members).toBeUndefined();
+
+      const store = classes.find(c => c.name === 'Store');
+      expect(store.extends).toBe('AbstractThing');
+      expect(store.implements).toEqual(['Iterable<T>']);
+    });
+
+    test('attaches signatures and type shapes to symbols', () => {
+      const maps = createMaps({
+        functions: [{ name: 'loadAll', line: 23 }, { name: 'exportedFn', line: 10 }],
+        types: [{ name: 'SampleType', line: 5 }, { name: 'SampleEnum', line: 6 }, { name: 'Repository', line: 17 }],
+        classes: [{ name: 'Store', line: 24 }]
+      });
+
+      applySymbolDetails('typescript', sample, maps);
+
+      const loadAll = maps.functions.get('loadAll');
+      expect(loadAll.signature).toBe('<T>(repo: Repository<T>, limit = 10): Promise<T[]>');
+      expect(loadAll.returnType).toBe('Promise<T[]>');
+      expect(loadAll.async).toBe(true);
+      expect(maps.functions.get('exportedFn').signature).toBe('(input: TypeA): number');
+
+      expect(maps.types.get('SampleType')).toMatchObject({ kind: 'type-alias', members: [{ name: 'id', type: 'number' }] });
+      expect(maps.types.get('SampleEnum')).toMatchObject({ kind: 'enum', members: [{ name: 'One' }, { name: 'Two' }] });
+      expect(maps.types.get('Repository').kind).toBe('interface');
+      expect(maps.classes.get('Store')).toMatchObject({ typeParams: '<T>', extends: 'AbstractThing' });
+    });
+
+    test('detects React components and resolves props', () => {
+      const maps = createMaps({
+        functions: [
+          { name: 'Button', line: 8 },
+          { name: 'Badge', line: 12 },
+          { name: 'formatLabel', line: 14 }
+        ]
+      });
+
+      applySymbolDetails('typescript', component, maps);
+
+      const button = maps.functions.get('Button');
+      expect(button.kind).toBe('component');
+      expect(button.propsType).toBe('ButtonProps');
+      expect(button.props.map(p => p.name)).toEqual(['label', 'onClick']);
+
+      const badge = maps.functions.get('Badge');
+      expect(badge.kind).toBe('component');
+      expect(badge.props).toEqual([{ name: 'count', type: 'number' }]);
+
+      expect(maps.functions.get('formatLabel').kind).toBe('function');
+      expect(maps.functions.get('formatLabel').signature).toBe('(label: string): string');
+    });
+
+    test('ignores declarations inside comments', () => {
+      const source = [
+        '// export function hidden(a: string): void {}',
+        '/* interface Ghost { x: number } */',
+        'export function shown(a: number) {}'
+      ].join('\n');
+
+      const { functions, interfaces } = parseTypeScriptDeclarations(source);
+
+      expect(functions.map(f => f.name)).toEqual(['shown']);
+      expect(interfaces).toEqual([]);
+    });
+
+    test('adds signatures to plain JavaScript functions', () => {
+      const source = 'const add = (a, b = 1) => a + b;\nfunction Card(props) { return null; }\n';
+      const maps = createMaps({ functions: [{ name: 'add', line: 1 }, { name: 'Card', line: 2 }] });
+
+      applySymbolDetails('javascript', source, maps);
+
+      expect(maps.functions.get('add').signature).toBe('(a, b = 1)');
+      expect(maps.functions.get('Card').kind).toBe('component');
+    });
+  });
+
+  describe('java', () => {
+    const content = fs.readFileSync(path.join(fixtureRoot, 'java', 'Sample.java'), 'utf8');
+
+    test('parses package, annotations, supertypes, and methods', () => {
+      const parsed = parseJvmDeclarations(content, 'java');
+
+      expect(parsed.package).toBe('sample');
+
+      const controller = parsed.types.find(t => t.name === 'UserController');
+      expect(controller.annotations).toEqual(['RestController', 'RequestMapping("/users")']);
+      expect(controller.extends).toEqual(['BaseController']);
+      expect(controller.implements).toEqual(['Auditable', 'Closeable']);
+      expect(controller.methods.map(m => m.name)).toEqual(['getUser', 'close', 'audit']);
+
+      const entity = parsed.types.find(t => t.name === 'User');
+      expect(entity.annotations).toEqual(['Entity', 'Table(name = "users")']);
+      expect(entity.visibility).toBe('package');
+
+      expect(parsed.types.find(t => t.name === 'Audited').kind).toBe('annotation');
+    });
+
+    test('attaches details to class and method symbols', () => {
+      const maps = createMaps({
+        functions: [{ name: 'getUser', line: 21 }, { name: 'secret', line: 10 }],
+        classes: [{ name: 'UserController', line: 19 }, { name: 'Audited', line: 38 }]
+      });
+
+      applySymbolDetails('java', content, maps);
+
+      const controller = maps.classes.get('UserController');
+      expect(controller.package).toBe('sample');
+      expect(controller.annotations).toEqual(['RestController', 'RequestMapping("/users")']);
+      expect(controller.methods).toEqual([{ name: 'getUser', line: 21 }, { name: 'close', line: 24 }]);
+      expect(maps.classes.get('Audited').kind).toBe('annotation');
+
+      expect(maps.functions.get('getUser')).toMatchObject({
+        parent: 'UserController',
+        visibility: 'public',
+        annotations: ['GetMapping("/{id}")']
+      });
+      expect(maps.functions.get('secret').visibility).toBe('private');
+    });
+
+    test('ignores statements inside method bodies', () => {
+      const source = [
+        'public class Worker {',
+        '    public void run() {',
+        '        String value = compute();',
+        '        return helper(value);',
+        '    }',
+        '}'
+      ].join('\n');
+
+      const { methods } = parseJvmDeclarations(source, 'java');
+
+      expect(methods.map(m => m.name)).toEqual(['run']);
+    });
+  });
+
+  describe('kotlin', () => {
+    const content = fs.readFileSync(path.join(fixtureRoot, 'kotlin', 'Sample.kt'), 'utf8');
+
+    test('parses data classes, objects, and extension functions', () => {
+      const parsed = parseJvmDeclarations(content, 'kotlin');
+
+      expect(parsed.package).toBe('sample.kotlin');
+
+      const user = parsed.types.find(t => t.name === 'User');
+      expect(user.kind).toBe('dataclass');
+      expect(user.annotations).toEqual(['Serializable']);
+
+      const service = parsed.types.find(t => t.name === 'UserService');
+      expect(service.extends).toEqual(['Repository', 'AutoCloseable']);
+      expect(service.methods.map(m => m.name)).toEqual(['find', 'log', 'close']);
+
+      expect(parsed.types.find(t => t.name === 'Registry').kind).toBe('object');
+
+      const ext = parsed.methods.find(m => m.name === 'toUserId');
+      expect(ext.receiver).toBe('String');
+      expect(ext.parent).toBeNull();
+    });
+
+    test('defaults to public visibility', () => {
+      const maps = createMaps({
+        functions: [
+          { name: 'toUserId', line: 29 },
+          { name: 'helper', line: 31 },
+          { name: 'secret', line: 33 }
+        ]
+      });
+
+      applySymbolDetails('kotlin', content, maps);
+
+      expect(maps.functions.get('toUserId').visibility).toBe('public');
+      expect(maps.functions.get('helper').visibility).toBe('internal');
+      expect(maps.functions.get('secret').visibility).toBe('private');
+    });
+  });
+
+  describe('c/c++', () => {
+    const header = fs.readFileSync(path.join(fixtureRoot, 'c', 'sample.h'), 'utf8');
+    const source = fs.readFileSync(path.join(fixtureRoot, 'c', 'sample.c'), 'utf8');
+
+    test('parses macros and skips include guards', () => {
+      expect(parseMacros(header)).toEqual([
+        { name: 'SAMPLE_MAX', line: 6, params: false },
+        { name: 'SAMPLE_MIN', line: 7, params: true }
+      ]);
+    });
+
+    test('adds macros and marks static functions', () => {
+      const headerMaps = createMaps();
+      applySymbolDetails('c', header, headerMaps);
+      expect(headerMaps.constants.get('SAMPLE_MIN')).toEqual({ name: 'SAMPLE_MIN', line: 7, kind: 'macro', params: true });
+
+      const sourceMaps = createMaps({ functions: [{ name: 'next_id', line: 6 }, { name: 'sample_add', line: 8 }] });
+      applySymbolDetails('c', source, sourceMaps);
+      expect(sourceMaps.functions.get('next_id').storage).toBe('static');
+      expect(sourceMaps.functions.get('sample_add').storage).toBeUndefined();
+    });
+
+    test('records C++ base classes', () => {
+      const content = fs.readFileSync(path.join(fixtureRoot, 'cpp', 'shape.hpp'), 'utf8');
+      const maps = createMaps({ classes: [{ name: 'Circle', line: 13 }, { name: 'Shape', line: 7 }] });
+
+      applySymbolDetails('cpp', content, maps);
+
+      expect(maps.classes.get('Circle').bases).toEqual(['Shape']);
+      expect(maps.classes.get('Shape').bases).toBeUndefined();
+    });
+  });
+
+  describe('ruby', () => {
+    const rubyRoot = path.join(fixtureRoot, 'ruby');
+    const read = (file) => fs.readFileSync(path.join(rubyRoot, file), 'utf8');
+
+    test('parses nesting, visibility, and Rails DSL calls', () => {
+      const { classes, constants } = parseRubyDefinitions(read('app/models/user.rb'));
+
+      const user = classes.find(c => c.name === 'User');
+      expect(user.superclass).toBe('ApplicationRecord');
+      expect(user.mixins).toEqual([{ kind: 'include', name: 'Searchable' }]);
+      expect(user.associations.map(a => a.name)).toEqual(['posts', 'team']);
+      expect(user.callbacks).toEqual([{ kind: 'before_save', name: 'normalize_email' }]);
+      expect(user.scopes).toEqual(['active']);
+      expect(user.methods.map(m => [m.name, m.scope, m.visibility])).toEqual([
+        ['find_by_token', 'class', 'public'],
+        ['display_name', 'instance', 'public'],
+        ['normalize_email', 'instance', 'private']
+      ]);
+      expect(constants).toEqual([{ name: 'ROLES', line: 6, namespace: 'User' }]);
+    });
+
+    test('tracks namespaces and symbol-list visibility', () => {
+      const { classes } = parseRubyDefinitions(read('app/controllers/users_controller.rb'));
+
+      const controller = classes.find(c => c.name === 'UsersController');
+      expect(controller.namespace).toBe('Admin');
+      expect(controller.methods.map(m => m.name)).toEqual(['index', 'show', 'authenticate']);
+      expect(controller.methods[2].visibility).toBe('private');
+    });
+
+    test('assigns Rails roles from superclass, concern, and path', () => {
+      const modelMaps = createMaps({
+        classes: [{ name: 'User', line: 3 }],
+        functions: [{ name: 'normalize_email', line: 26 }, { name: 'find_by_token', line: 13 }]
+      });
+      applySymbolDetails('ruby', read('app/models/user.rb'), modelMaps, { file: 'app/models/user.rb' });
+
+      const user = modelMaps.classes.get('User');
+      expect(user.rails).toBe('model');
+      expect(user.methods).toEqual([
+        { name: 'find_by_token', line: 13, scope: 'class' },
+        { name: 'display_name', line: 17 }
+      ]);
+      expect(modelMaps.functions.get('normalize_email').visibility).toBe('private');
+      expect(modelMaps.constants.get('ROLES')).toEqual({ name: 'ROLES', line: 6, kind: 'constant', namespace: 'User' });
+
+      const concernMaps = createMaps({ classes: [{ name: 'Searchable', line: 1 }] });
+      applySymbolDetails('ruby', read('app/models/concerns/searchable.rb'), concernMaps, {
+        file: 'app/models/concerns/searchable.rb'
+      });
+      expect(concernMaps.classes.get('Searchable')).toMatchObject({ kind: 'module', rails: 'concern' });
+
+      const serviceMaps = createMaps({ classes: [{ name: 'BillingService', line: 1 }] });
+      applySymbolDetails('ruby', 'class BillingService\nend\n', serviceMaps, { file: 'app/services/billing_service.rb' });
+      expect(serviceMaps.classes.get('BillingService').rails).toBe('service');
+    });
+  });
+
+  describe('csharp', () => {
+    const csharpRoot = path.join(fixtureRoot, 'csharp', 'src');
+    const controller = fs.readFileSync(path.join(csharpRoot, 'Api', 'Controllers', 'UsersController.cs'), 'utf8');
+    const core = fs.readFileSync(path.join(csharpRoot, 'Core', 'User.cs'), 'utf8');
+
+    test('parses block and file-scoped namespaces, records, and visibility', () => {
+      const { namespaces, types } = parseCSharpDeclarations(core);
+
+      expect(namespaces).toEqual(['Sample.Core']);
+      expect(types.map(t => [t.name, t.kind, t.visibility])).toEqual([
+        ['User', 'record', 'public'],
+        ['IUserStore', 'interface', 'public'],
+        ['MemoryUserStore', 'class', 'internal']
+      ]);
+      expect(types[2].bases).toEqual(['IUserStore']);
+      expect(types[1].methods[0].visibility).toBe('public');
+    });
+
+    test('attaches attributes and async flags', () => {
+      const maps = createMaps({
+        classes: [{ name: 'UsersController', line: 8 }],
+        functions: [{ name: 'GetAsync', line: 19 }, { name: 'Normalize', line: 25 }]
+      });
+
+      applySymbolDetails('csharp', controller, maps);
+
+      expect(maps.classes.get('UsersController')).toMatchObject({
+        namespace: 'Sample.Api.Controllers',
+        attributes: ['ApiController', 'Route("api/[controller]")'],
+        bases: ['ControllerBase'],
+        methods: [{ name: 'GetAsync', line: 19 }]
+      });
+      expect(maps.functions.get('GetAsync')).toMatchObject({
+        async: true,
+        returnType: 'Task<ActionResult<User>>',
+        attributes: ['HttpGet("{id}")', 'ProducesResponseType(typeof(User), 200)']
+      });
+      expect(maps.functions.get('Normalize').visibility).toBe('private');
+    });
+
+    test('skips constructors and assembly attributes', () => {
+      const source = [
+        '[assembly: InternalsVisibleTo("Tests")]',
+        'public class Worker',
+        '{',
+        '    public Worker() { }',
+        '    public void Run() { }',
+        '}'
+      ].join('\n');
+
+      const { types, methods } = parseCSharpDeclarations(source);
+
+      expect(types[0].attributes).toEqual([]);
+      expect(methods.map(m => m.name)).toEqual(['Run']);
+    });
+  });
+
+  describe('go', () => {
+    const content = fs.readFileSync(path.join(fixtureRoot, 'go', 'shapes.go'), 'utf8');
+
+    test('parses receivers, struct fields, and interface method sets', () => {
+      const { package: pkg, methods, types } = parseGoDeclarations(content);
+
+      expect(pkg).toBe('sample');
+      expect(methods.find(m => m.receiver === 'Circle' && m.name === 'Area')).toMatchObject({ pointer: true, arity: [0, 1] });
+      expect(methods.find(m => m.receiver === 'Stack' && m.name === 'Pop')).toMatchObject({
+        signature: '() (T, bool)',
+        arity: [0, 2]
+      });
+
+      const rect = types.find(t => t.name === 'Rect');
+      expect(rect.kind).toBe('struct');
+      expect(rect.fields).toEqual([
+        { name: 'Width', type: 'float64', exported: true },
+        { name: 'Height', type: 'float64', exported: true },
+        { name: 'label', type: 'string', exported: false }
+      ]);
+      expect(types.find(t => t.name === 'Circle').embeds).toEqual(['Shape']);
+
+      const shape = types.find(t => t.name === 'Shape');
+      expect(shape.methods).toEqual([{ name: 'Area', signature: '() float64', arity: [0, 1] }]);
+      expect(shape.embeds).toEqual(['fmt.Stringer']);
+      expect(types.find(t => t.name === 'Stack').typeParams).toBe('[T any]');
+      expect(types.find(t => t.name === 'Celsius')).toMatchObject({ kind: 'type', underlying: 'float64' });
+    });
+
+    test('keys methods by receiver so same-named methods stay distinct', () => {
+      const maps = createMaps({
+        functions: [{ name: 'Area', line: 29 }, { name: 'String', line: 30 }, { name: 'NewRect', line: 48 }],
+        types: [{ name: 'Rect', line: 12, kind: 'type' }, { name: 'Shape', line: 6, kind: 'type' }]
+      });
+
+      applySymbolDetails('go', content, maps);
+
+      expect(Array.from(maps.functions.keys()).sort()).toEqual([
+        'Circle.Area', 'Circle.String', 'NewRect', 'Rect.Area', 'Rect.String'
+      ]);
+      expect(maps.functions.get('Circle.Area')).toMatchObject({ name: 'Area', kind: 'method', receiver: 'Circle', pointerReceiver: true });
+      expect(maps.functions.get('NewRect')).toMatchObject({ kind: 'function', signature: '(width, height float64) Rect' });
+      expect(maps.types.get('Rect').members.map(m => m.name)).toEqual(['Width', 'Height', 'label']);
+      expect(maps.types.get('Shape').kind).toBe('interface');
+      expect(maps.types.get('Celsius')).toMatchObject({ kind: 'type', line: 23 });
+    });
+  });
+
+  test('ignores languages without detail rules', () => {
+    const maps = createMaps({ functions: [{ name: 'main', line: 1 }] });
+    applySymbolDetails('unknown', 'fn main() {}', maps);
+    expect(maps.functions.get('main')).toEqual({ name: 'main', line: 1, kind: 'function' });
+  });
+});
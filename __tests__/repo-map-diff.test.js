@@ -0,0 +1,131 @@
+/**
+ * Tests for repo-map symbol diffs between git refs
+ */
+
+const { execFileSync } = require('child_process');
+const fs = require('fs');
+const os = require('os');
+const path = require('path');
+
+const { diffRefs, diffStaged, renderDiff, resolveRef } = require('../lib/repo-map/diff');
+
+describe('repo-map diff', () => {
+  let repo;
+
+  function git(...args) {
+    return execFileSync('git', args, { cwd: repo, encoding: 'utf8', stdio: ['pipe', 'pipe', 'pipe'] }).trim();
+  }
+
+  function write(file, content) {
+    const fullPath = path.join(repo, file);
+    fs.mkdirSync(path.dirname(fullPath), { recursive: true });
+    fs.writeFileSync(fullPath, content);
+  }
+
+  function commit(message) {
+    git('add', '-A');
+    git('commit', '-q', '-m', message);
+    return git('rev-parse', 'HEAD');
+  }
+
+  /**
+   * Stand-in extractor: `function name(params)` lines become functions
+   */
+  function extract(ref, files) {
+    const result = {};
+    for (const file of files) {
+      let content;
+      try {
+        content = git('show', `${ref}:${file}`);
+      } catch {
+        continue;
+      }
+      const functions = [];
+      content.split('\n').forEach((line, i) => {
+        const match = line.match(/^(export )?function (\w+)(\(.*\))/);
+        if (match) functions.push({ name: match[2], line: i + 1, kind: 'function', signature: match[3], exported: Boolean(match[1]) });
+      });
+      result[file] = { exports: [], functions, classes: [], types: [], constants: [] };
+    }
+    return result;
+  }
+
+  beforeEach(() => {
+    repo = fs.mkdtempSync(path.join(os.tmpdir(), 'repo-map-diff-'));
+    git('init', '-q');
+    git('config', 'user.email', 'test@example.com');
+    git('config', 'user.name', 'Test');
+
+    write('src/math.js', 'export function add(a, b)\nexport function sub(a, b)\nfunction helper(x)\n');
+    write('src/old.js', 'export function legacy()\n');
+    write('src/move.js', 'export function moved(value)\nfunction keep()\nfunction keep2()\n');
+    write('README.md', '# Sample\n');
+    commit('base');
+  });
+
+  afterEach(() => {
+    fs.rmSync(repo, { recursive: true, force: true });
+  });
+
+  test('reports added, removed, renamed, and changed symbols', () => {
+    write('src/math.js', 'export function add(a, b, c)\nexport function minus(a, b)\nexport function helper(x)\nfunction mul(x, y)\n');
+    fs.rmSync(path.join(repo, 'src/old.js'));
+    git('mv', 'src/move.js', 'src/moved.js');
+    write('src/new.js', 'export function fresh()\n');
+    write('README.md', '# Changed\n');
+    commit('head');
+
+    const result = diffRefs(repo, 'HEAD~1', 'HEAD', { extract });
+
+    expect(result.success).toBe(true);
+    expect(result.files).toEqual({
+      added: ['src/new.js'],
+      removed: ['src/old.js'],
+      modified: ['src/math.js'],
+      renamed: [{ from: 'src/move.js', to: 'src/moved.js' }]
+    });
+    expect(result.symbols.added.map(s => `${s.file}:${s.name}`)).toEqual(['src/math.js:mul', 'src/new.js:fresh']);
+    expect(result.symbols.removed.map(s => `${s.file}:${s.name}`)).toEqual(['src/old.js:legacy']);
+    expect(result.symbols.renamed).toEqual([expect.objectContaining({
+      file: 'src/math.js',
+      name: 'minus',
+      from: { file: 'src/math.js', name: 'sub', line: 2 }
+    })]);
+    expect(result.symbols.changed).toEqual([
+      expect.objectContaining({ name: 'add', signature: '(a, b, c)', before: { kind: 'function', signature: '(a, b)', exported: true } }),
+      expect.objectContaining({ name: 'helper', exported: true, before: expect.objectContaining({ exported: false }) })
+    ]);
+
+    const text = renderDiff(result);
+    expect(text).toContain('+ function fresh()  src/new.js:1');
+    expect(text).toContain('- function legacy()  src/old.js:1');
+    expect(text).toContain('~ sub -> function minus(a, b)  src/math.js:2');
+    expect(text).toContain('* function add(a, b) -> function add(a, b, c)  src/math.js:1');
+    expect(text).toContain('(now exported)');
+  });
+
+  test('diffs staged changes against HEAD', () => {
+    write('src/math.js', 'export function add(a, b)\nexport function sub(a, b)\nfunction helper(x)\nexport function mul(x, y)\n');
+    write('src/unstaged.js', 'export function ignored()\n');
+    git('add', 'src/math.js');
+    // Working tree edits after staging are not part of the commit
+    write('src/math.js', 'export function add(a, b)\n');
+
+    const result = diffStaged(repo, { extract });
+    expect(result.head).toBe('index');
+    expect(result.files.modified).toEqual(['src/math.js']);
+    expect(result.symbols.added.map(s => s.name)).toEqual(['mul']);
+    expect(result.symbols.removed).toEqual([]);
+  });
+
+  test('rejects unknown refs and option-like refs', () => {
+    expect(diffRefs(repo, 'does-not-exist', 'HEAD', { extract })).toEqual({ success: false, error: 'Unknown ref: does-not-exist' });
+    expect(resolveRef(repo, '--all')).toBeNull();
+  });
+
+  test('renders an empty diff', () => {
+    const result = diffRefs(repo, 'HEAD', 'HEAD', { extract });
+    expect(result.symbols).toEqual({ added: [], removed: [], renamed: [], changed: [] });
+    expect(renderDiff(result)).toBe('No symbol changes\n');
+  });
+});
@@ -0,0 +1,62 @@
+/**
+ * Tests for repo-map .NET project grouping
+ */
+
+const path = require('path');
+
+const runner = require('../lib/repo-map/runner');
+const { groupDotnetProjects, parseSolution, findOwningProject } = require('../lib/repo-map/dotnet');
+
+const fixtureRoot = path.join(__dirname, 'fixtures', 'repo-map', 'csharp');
+
+describe('repo-map .NET projects', () => {
+  test('finds project and solution files', () => {
+    const manifests = runner.findDotnetManifests(fixtureRoot);
+    expect(manifests.projects.sort()).toEqual(['src/Api/Api.csproj', 'src/Core/Core.csproj']);
+    expect(manifests.solutions).toEqual(['App.sln']);
+  });
+
+  test('parses solution project paths', () => {
+    expect(parseSolution(fixtureRoot, 'App.sln')).toEqual(['src/Api/Api.csproj', 'src/Core/Core.csproj']);
+  });
+
+  test('assigns files to the nearest project', () => {
+    const projects = [{ path: 'Root.csproj' }, { path: 'src/Api/Api.csproj' }];
+    expect(findOwningProject('src/Api/Controllers/A.cs', projects).path).toBe('src/Api/Api.csproj');
+    expect(findOwningProject('tools/Gen.cs', projects).path).toBe('Root.csproj');
+    expect(findOwningProject('tools/Gen.cs', [{ path: 'src/Api/Api.csproj' }])).toBeNull();
+  });
+
+  test('groups C# files with project metadata', () => {
+    const map = {
+      files: {
+        'src/Api/Controllers/UsersController.cs': { language: 'csharp' },
+        'src/Core/User.cs': { language: 'csharp' },
+        'scripts/build.js': { language: 'javascript' }
+      }
+    };
+
+    groupDotnetProjects(map, fixtureRoot, runner.findDotnetManifests(fixtureRoot));
+
+    expect(map.files['src/Core/User.cs'].project).toBe('src/Core/Core.csproj');
+    expect(map.files['scripts/build.js'].project).toBeUndefined();
+
+    const api = map.projects['src/Api/Api.csproj'];
+    expect(api).toMatchObject({
+      name: 'Api',
+      sdk: 'Microsoft.NET.Sdk.Web',
+      targetFrameworks: ['net8.0'],
+      references: ['src/Core/Core.csproj'],
+      packages: ['Swashbuckle.AspNetCore'],
+      solutions: ['App.sln'],
+      files: 1
+    });
+    expect(map.projects['src/Core/Core.csproj'].targetFrameworks).toEqual(['net8.0', 'netstandard2.1']);
+  });
+
+  test('removes grouping when no projects remain', () => {
+    const map = { files: {}, projects: { 'old.csproj': {} } };
+    groupDotnetProjects(map, fixtureRoot, { projects: [], solutions: [] });
+    expect(map.projects).toBeUndefined();
+  });
+});
@@ -0,0 +1,46 @@
+/**
+ * Tests for repo-map environment variable extraction
+ */
+
+const { extractEnvReads } = require('../lib/repo-map/env');
+
+describe('extractEnvReads', () => {
+  test('finds JavaScript reads, defaults, destructuring, and computed names', () => {
+    const content = [
+      'const port = process.env.PORT || 3000;',
+      '// process.env.COMMENTED',
+      'const { API_URL, DEBUG = false } = process.env;',
+      "const key = process.env['STRIPE_KEY'];",
+      'const base = import.meta.env.VITE_BASE ?? "/";',
+      'const value = process.env[name];'
+    ].join('\n');
+
+    expect(extractEnvReads('javascript', content)).toEqual([
+      { name: 'PORT', line: 1, fallback: true },
+      { name: 'API_URL', line: 3, fallback: false },
+      { name: 'DEBUG', line: 3, fallback: true },
+      { name: 'STRIPE_KEY', line: 4, fallback: false },
+      { name: 'VITE_BASE', line: 5, fallback: true },
+      { name: null, line: 6, fallback: false, dynamic: true }
+    ]);
+  });
+
+  test('finds Python, Go, Rust, and Ruby reads', () => {
+    expect(extractEnvReads('python', "import os\nurl = os.environ['DATABASE_URL']\nlevel = os.getenv('LOG_LEVEL', 'info')\n# os.getenv('NOPE')\n"))
+      .toEqual([
+        { name: 'DATABASE_URL', line: 2, fallback: false },
+        { name: 'LOG_LEVEL', line: 3, fallback: true }
+      ]);
+    expect(extractEnvReads('go', 'host := os.Getenv("HOST")\n_, ok := os.LookupEnv("TOKEN")\n'))
+      .toEqual([{ name: 'HOST', line: 1, fallback: false }, { name: 'TOKEN', line: 2, fallback: false }]);
+    expect(extractEnvReads('rust', 'let port = env::var("PORT").unwrap_or("80".into());\nconst V: &str = env!("CARGO_PKG_VERSION");\n'))
+      .toEqual([{ name: 'PORT', line: 1, fallback: true }, { name: 'CARGO_PKG_VERSION', line: 2, fallback: false }]);
+    expect(extractEnvReads('ruby', "secret = ENV.fetch('SECRET_KEY_BASE')\nregion = ENV['REGION'] || 'us'\n"))
+      .toEqual([{ name: 'SECRET_KEY_BASE', line: 1, fallback: false }, { name: 'REGION', line: 2, fallback: true }]);
+  });
+
+  test('returns nothing for languages without patterns', () => {
+    expect(extractEnvReads('unknown', 'getenv("X")')).toEqual([]);
+    expect(extractEnvReads('c', 'const char *home = getenv("HOME");')).toEqual([{ name: 'HOME', line: 1, fallback: false }]);
+  });
+});
@@ -0,0 +1,92 @@
+/**
+ * Tests for repo-map output formats
+ */
+
+const { formatMap, FORMATS } = require('../lib/repo-map/formats');
+
+/**
+ * Build a small map: app imports math and format, cli imports math
+ */
+function createMap() {
+  const entry = (name, imports = []) => ({
+    language: 'javascript',
+    symbols: {
+      exports: [{ name, kind: 'function', line: 1 }],
+      functions: [{ name, kind: 'function', line: 1, exported: true }],
+      classes: [],
+      types: [],
+      constants: []
+    },
+    imports: imports.map(source => ({ source, kind: 'import', line: 1 })),
+    calls: []
+  });
+
+  return {
+    version: '1.0.0',
+    generated: '2026-01-01T00:00:00.000Z',
+    git: { commit: 'abc123' },
+    project: { languages: ['javascript'] },
+    files: {
+      'src/app.js': entry('main', ['./math', './format', 'lodash']),
+      'src/cli.js': entry('run', ['./math']),
+      'src/format.js': entry('format'),
+      'src/math.js': entry('add')
+    },
+    callGraph: {}
+  };
+}
+
+describe('formatMap', () => {
+  test('lists supported formats', () => {
+    expect(FORMATS).toEqual(['text', 'json', 'mermaid', 'dot']);
+  });
+
+  test('json includes symbols and resolved dependencies', () => {
+    const result = formatMap(createMap(), 'json');
+    const parsed = JSON.parse(result.text);
+
+    expect(parsed.commit).toBe('abc123');
+    expect(parsed.files['src/app.js'].imports).toEqual(['./math', './format', 'lodash']);
+    expect(parsed.files['src/math.js'].symbols.functions[0].name).toBe('add');
+    expect(parsed.dependencies['src/cli.js']).toEqual(['src/math.js']);
+    expect(result.edges).toBe(3);
+  });
+
+  test('mermaid declares each file once and links dependencies', () => {
+    const { text } = formatMap(createMap(), 'mermaid');
+    const lines = text.trim().split('\n');
+
+    expect(lines[0]).toBe('graph LR');
+    expect(lines).toContain('  n0["src/app.js"]');
+    expect(lines).toContain('  n0 --> n3');
+    expect(lines).toContain('  n1 --> n3');
+  });
+
+  test('dot emits a quoted digraph', () => {
+    const { text } = formatMap(createMap(), 'DOT');
+
+    expect(text.startsWith('digraph repo_map {')).toBe(true);
+    expect(text).toContain('  "src/app.js" -> "src/math.js";');
+    expect(text.trim().endsWith('}')).toBe(true);
+  });
+
+  test('maxFiles keeps the highest-ranked files', () => {
+    const result = formatMap(createMap(), 'dot', { maxFiles: 1 });
+
+    expect(result.files).toBe(1);
+    expect(result.omittedFiles).toBe(3);
+    expect(result.text).toContain('"src/math.js";');
+    expect(result.edges).toBe(0);
+  });
+
+  test('text delegates to the token-budgeted renderer', () => {
+    const result = formatMap(createMap(), 'text', { maxTokens: 1000 });
+    expect(result.format).toBe('text');
+    expect(result.text).toContain('src/math.js:');
+    expect(result.fits).toBe(true);
+  });
+
+  test('rejects unknown formats', () => {
+    expect(() => formatMap(createMap(), 'yaml')).toThrow('Unknown format');
+  });
+});
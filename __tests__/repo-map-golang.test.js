@@ -0,0 +1,90 @@
+/**
+ * Tests for repo-map Go method grouping and interface satisfaction
+ */
+
+const { linkGoMethods } = require('../lib/repo-map/golang');
+const { renderMap } = require('../lib/repo-map/render');
+
+function fileData(functions = [], types = []) {
+  return {
+    language: 'go',
+    symbols: { exports: [], functions, classes: [], types, constants: [] },
+    imports: []
+  };
+}
+
+function method(receiver, name, line, arity, extra = {}) {
+  return { name, line, kind: 'method', receiver, arity, exported: true, ...extra };
+}
+
+describe('repo-map Go method linking', () => {
+  function createMap() {
+    return {
+      files: {
+        'store/store.go': fileData([], [
+          { name: 'Store', line: 3, kind: 'interface', exported: true, methods: [{ name: 'Get', arity: [1, 2] }], embeds: ['io.Closer'] },
+          { name: 'memory', line: 8, kind: 'struct', exported: false }
+        ]),
+        'store/memory.go': fileData([
+          method('memory', 'Get', 4, [1, 2], { exported: false }),
+          method('memory', 'Close', 9, [0, 1], { exported: false, pointerReceiver: true }),
+          method('memory', 'reset', 12, [0, 0], { exported: false, pointerReceiver: true })
+        ]),
+        'api/server.go': fileData([
+          method('Server', 'Get', 10, [1, 2]),
+          method('Server', 'Close', 14, [0, 0]),
+          method('Server', 'String', 18, [0, 1])
+        ], [{ name: 'Server', line: 5, kind: 'struct', exported: true }])
+      }
+    };
+  }
+
+  test('groups methods declared anywhere in the package under the receiver', () => {
+    const map = createMap();
+
+    expect(linkGoMethods(map)).toBe(6);
+
+    const memory = map.files['store/store.go'].symbols.types[1];
+    expect(memory.methods).toEqual([
+      { name: 'Close', line: 9, exported: false, pointer: true, file: 'store/memory.go' },
+      { name: 'Get', line: 4, exported: false, file: 'store/memory.go' },
+      { name: 'reset', line: 12, exported: false, pointer: true, file: 'store/memory.go' }
+    ]);
+    expect(map.files['store/memory.go'].symbols.functions.every(entry => entry.memberOf === 'memory')).toBe(true);
+  });
+
+  test('detects local, cross-package, and standard interfaces by method set', () => {
+    const map = createMap();
+    linkGoMethods(map);
+
+    // Pointer-receiver Close still counts toward *memory's method set
+    expect(map.files['store/store.go'].symbols.types[1].implements).toEqual(['Store', 'io.Closer']);
+    // Close() with no result does not match io.Closer / store.Store
+    expect(map.files['api/server.go'].symbols.types[0].implements).toEqual(['fmt.Stringer']);
+  });
+
+  test('relinks cleanly after methods change', () => {
+    const map = createMap();
+    linkGoMethods(map);
+    map.files['api/server.go'].symbols.functions[1].arity = [0, 1];
+    map.files['store/memory.go'].symbols.functions = [];
+
+    linkGoMethods(map);
+
+    const memory = map.files['store/store.go'].symbols.types[1];
+    expect(memory.methods).toBeUndefined();
+    expect(memory.implements).toBeUndefined();
+    expect(map.files['api/server.go'].symbols.types[0].implements).toEqual(['store.Store', 'fmt.Stringer', 'io.Closer']);
+  });
+
+  test('renders methods under their type instead of as top-level functions', () => {
+    const map = createMap();
+    linkGoMethods(map);
+
+    const { text } = renderMap({ ...map, files: { 'api/server.go': map.files['api/server.go'] } });
+
+    expect(text).toContain('struct Server < fmt.Stringer :5');
+    expect(text).toContain('    String()');
+    expect(text).not.toContain('method String');
+  });
+});
@@ -0,0 +1,131 @@
+/**
+ * Tests for the repo-map module dependency graph
+ */
+
+const fs = require('fs');
+const os = require('os');
+const path = require('path');
+
+const repoMap = require('../lib/repo-map');
+const cache = require('../lib/repo-map/cache');
+const installer = require('../lib/repo-map/installer');
+const updater = require('../lib/repo-map/updater');
+const {
+  buildDependencyGraph,
+  findCycles,
+  findNewCycles,
+  getDependents
+} = require('../lib/repo-map/graph');
+
+/**
+ * Build a map from `{file: [import sources]}`
+ */
+function createMap(imports, language = 'javascript') {
+  const files = {};
+  for (const [file, sources] of Object.entries(imports)) {
+    files[file] = {
+      language,
+      symbols: { exports: [], functions: [], classes: [], types: [], constants: [] },
+      imports: sources.map(source => ({ source, kind: 'import', line: 1 }))
+    };
+  }
+  return { version: '1.0.0', files, dependencies: {} };
+}
+
+describe('buildDependencyGraph', () => {
+  test('resolves relative imports and drops packages and self-imports', () => {
+    const graph = buildDependencyGraph(createMap({
+      'src/app.js': ['./math', './format.js', 'lodash', './app'],
+      'src/math.js': [],
+      'src/format.js': ['./math']
+    }));
+
+    expect(graph).toEqual({
+      'src/app.js': ['src/format.js', 'src/math.js'],
+      'src/format.js': ['src/math.js'],
+      'src/math.js': []
+    });
+    expect(getDependents(graph, 'src/math.js')).toEqual(['src/app.js', 'src/format.js']);
+  });
+
+  test('resolves Python modules', () => {
+    const graph = buildDependencyGraph(createMap({
+      'pkg/a.py': ['pkg.b'],
+      'pkg/b.py': []
+    }, 'python'));
+    expect(graph['pkg/a.py']).toEqual(['pkg/b.py']);
+  });
+});
+
+describe('findCycles', () => {
+  test('reports one shortest cycle per strongly connected component', () => {
+    const cycles = findCycles({
+      a: ['b'],
+      b: ['c'],
+      c: ['a', 'd'],
+      d: [],
+      x: ['y'],
+      y: ['x']
+    });
+
+    expect(cycles).toEqual([['a', 'b', 'c'], ['x', 'y']]);
+  });
+
+  test('returns nothing for acyclic graphs', () => {
+    expect(findCycles({ a: ['b'], b: ['c'], c: [] })).toEqual([]);
+  });
+
+  test('handles deep chains without recursion limits', () => {
+    const graph = {};
+    for (let i = 0; i < 20000; i++) graph[`f${i}`] = i < 19999 ? [`f${i + 1}`] : ['f0'];
+    expect(findCycles(graph)[0]).toHaveLength(20000);
+  });
+});
+
+describe('findNewCycles', () => {
+  test('flags only cycles closed by added edges', () => {
+    const before = { a: ['b'], b: [], x: ['y'], y: ['x'] };
+    const after = { a: ['b'], b: ['a'], x: ['y'], y: ['x'] };
+
+    expect(findNewCycles(before, after)).toEqual([
+      { cycle: ['a', 'b'], edge: { from: 'b', to: 'a' } }
+    ]);
+    expect(findNewCycles(after, after)).toEqual([]);
+  });
+});
+
+describe('checkCycles', () => {
+  let tempDir;
+  const originalStateDir = process.env.AI_STATE_DIR;
+
+  beforeEach(() => {
+    tempDir = fs.mkdtempSync(path.join(os.tmpdir(), 'repo-map-graph-'));
+    process.env.AI_STATE_DIR = '.test-state';
+    cache.save(tempDir, createMap({ 'src/a.js': ['./b'], 'src/b.js': [] }));
+  });
+
+  afterEach(() => {
+    jest.restoreAllMocks();
+    process.env.AI_STATE_DIR = originalStateDir;
+    fs.rmSync(tempDir, { recursive: true, force: true });
+  });
+
+  test('reports cycles introduced by re-scanned files', () => {
+    jest.spyOn(installer, 'getCommand').mockReturnValue('sg-not-installed');
+    jest.spyOn(updater, 'updateFiles').mockImplementation((basePath, map) => {
+      map.files['src/b.js'].imports.push({ source: './a', kind: 'import', line: 1 });
+    });
+
+    const result = repoMap.checkCycles(tempDir, { files: ['src/b.js'] });
+
+    expect(result.success).toBe(true);
+    expect(result.cycles).toEqual([['src/a.js', 'src/b.js']]);
+    expect(result.newCycles[0].edge).toEqual({ from: 'src/b.js', to: 'src/a.js' });
+    expect(cache.load(tempDir).files['src/b.js'].imports).toEqual([]);
+  });
+
+  test('checks the cached map when no files are given', () => {
+    const result = repoMap.checkCycles(tempDir);
+    expect(result).toEqual({ success: true, cycles: [], newCycles: [] });
+  });
+});
@@ -0,0 +1,69 @@
+/**
+ * Tests for repo-map C/C++ header/source pairing
+ */
+
+const { pairHeaders, linkHeaderDeclarations, countFileSymbols } = require('../lib/repo-map/headers');
+
+function fileData(language, functions = [], classes = []) {
+  return {
+    language,
+    symbols: { exports: [], functions, classes, types: [], constants: [] },
+    imports: []
+  };
+}
+
+describe('repo-map header pairing', () => {
+  test('prefers headers in the same directory', () => {
+    const pairs = pairHeaders(['src/util.c', 'src/util.h', 'other/util.h', 'src/main.c']);
+    expect(Array.from(pairs)).toEqual([['src/util.c', 'src/util.h']]);
+  });
+
+  test('pairs parallel include/src layouts', () => {
+    const pairs = pairHeaders(['include/net/socket.hpp', 'src/net/socket.cpp', 'include/fs/socket.hpp']);
+    expect(pairs.get('src/net/socket.cpp')).toBe('include/net/socket.hpp');
+  });
+
+  test('skips ambiguous headers', () => {
+    const pairs = pairHeaders(['a/log.h', 'b/log.h', 'c/log.c']);
+    expect(pairs.size).toBe(0);
+  });
+
+  test('links declarations to definitions and counts them once', () => {
+    const map = {
+      files: {
+        'sample.h': fileData('c', [{ name: 'sample_add', line: 19, kind: 'declaration' }]),
+        'sample.c': fileData('c', [
+          { name: 'sample_add', line: 8, kind: 'function' },
+          { name: 'next_id', line: 6, kind: 'function' }
+        ]),
+        'shape.hpp': fileData('cpp', [], [{ name: 'Circle', line: 13, kind: 'class' }]),
+        'shape.cpp': fileData('cpp', [{ name: 'Circle::area', line: 7, kind: 'method' }])
+      }
+    };
+    map.files['shape.hpp'].symbols.functions.push({ name: 'area', line: 16, kind: 'declaration' });
+
+    const linked = linkHeaderDeclarations(map);
+
+    expect(linked).toBe(2);
+    expect(map.files['sample.c'].symbols.functions[0].declaredIn).toBe('sample.h');
+    expect(map.files['sample.h'].symbols.functions[0].definedIn).toBe('sample.c');
+    expect(map.files['shape.cpp'].symbols.functions[0].declaredIn).toBe('shape.hpp');
+    expect(countFileSymbols(map.files['sample.c'].symbols)).toBe(1);
+    expect(countFileSymbols(map.files['sample.h'].symbols)).toBe(1);
+  });
+
+  test('clears stale links when a pair disappears', () => {
+    const map = {
+      files: {
+        'sample.h': fileData('c', [{ name: 'sample_add', line: 19 }]),
+        'sample.c': fileData('c', [{ name: 'sample_add', line: 8 }])
+      }
+    };
+    linkHeaderDeclarations(map);
+    delete map.files['sample.c'];
+
+    linkHeaderDeclarations(map);
+
+    expect(map.files['sample.h'].symbols.functions[0].definedIn).toBeUndefined();
+  });
+});
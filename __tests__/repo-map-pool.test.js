@@ -0,0 +1,90 @@
+/**
+ * Tests for parallel repo-map extraction
+ */
+
+const fs = require('fs');
+const os = require('os');
+const path = require('path');
+
+const runner = require('../lib/repo-map/runner');
+const { extractParallel, getWorkerCount, shardEntries } = require('../lib/repo-map/pool');
+
+describe('getWorkerCount', () => {
+  test('caps workers by concurrency and shard size', () => {
+    expect(getWorkerCount(1000, { concurrency: 4 })).toBe(4);
+    expect(getWorkerCount(120, { concurrency: 4, minFilesPerWorker: 50 })).toBe(2);
+    expect(getWorkerCount(10, { concurrency: 4 })).toBe(1);
+  });
+
+  test('defaults to the CPU count, capped at eight', () => {
+    const expected = Math.min(os.cpus().length, 8);
+    expect(getWorkerCount(100000)).toBe(Math.max(1, expected));
+  });
+});
+
+describe('shardEntries', () => {
+  test('keeps every entry and balances content size', () => {
+    const entries = [100, 90, 60, 40, 10].map((size, i) => ({ relativePath: `f${i}.js`, content: 'x'.repeat(size) }));
+    const shards = shardEntries(entries, 2);
+    const sizes = shards.map(shard => shard.reduce((sum, entry) => sum + entry.content.length, 0));
+
+    expect(shards.flat()).toHaveLength(5);
+    expect(Math.abs(sizes[0] - sizes[1])).toBeLessThanOrEqual(20);
+  });
+
+  test('drops empty shards', () => {
+    expect(shardEntries([{ content: 'a' }], 4)).toHaveLength(1);
+  });
+});
+
+describe('extractParallel', () => {
+  let tempDir;
+
+  beforeEach(() => {
+    tempDir = fs.mkdtempSync(path.join(os.tmpdir(), 'repo-map-pool-'));
+  });
+
+  afterEach(() => {
+    fs.rmSync(tempDir, { recursive: true, force: true });
+  });
+
+  /**
+   * Write `count` small files and return extraction entries
+   */
+  function createEntries(count) {
+    const entries = [];
+    for (let i = 0; i < count; i++) {
+      const relativePath = `src/file${i}.js`;
+      const content = `export function f${i}() {\n  return helper${i}(${i});\n}\n`;
+      const file = path.join(tempDir, relativePath);
+      fs.mkdirSync(path.dirname(file), { recursive: true });
+      fs.writeFileSync(file, content);
+      entries.push({ file, relativePath, content });
+    }
+    return entries;
+  }
+
+  test('matches in-process extraction when sharded across workers', async () => {
+    const entries = createEntries(12);
+    const extract = jest.fn(runner.extractFiles);
+
+    const parallel = await extractParallel('sg-not-installed', tempDir, 'javascript', entries, {
+      concurrency: 3,
+      minFilesPerWorker: 4,
+      extract
+    });
+
+    expect(extract).not.toHaveBeenCalled();
+    expect(parallel).toEqual(runner.extractFiles('sg-not-installed', tempDir, 'javascript', entries));
+    expect(parallel['src/file3.js'].calls).toContainEqual({ name: 'helper3', line: 2 });
+  });
+
+  test('runs small batches in-process', async () => {
+    const entries = createEntries(2);
+    const extract = jest.fn(runner.extractFiles);
+
+    await extractParallel('sg-not-installed', tempDir, 'javascript', entries, { concurrency: 4, extract });
+
+    expect(extract).toHaveBeenCalledTimes(1);
+  });
+});
@@ -0,0 +1,99 @@
+/**
+ * Tests for repo-map symbol ranking and truncation
+ */
+
+const { rankFiles, rankSymbols, truncateMap } = require('../lib/repo-map/rank');
+
+/**
+ * Build a small JavaScript map: app -> math, app -> format, cli -> math
+ */
+function createMap() {
+  const entry = (functions, imports = []) => ({
+    language: 'javascript',
+    symbols: {
+      exports: functions.filter(fn => fn.exported).map(fn => ({ name: fn.name, kind: 'function', line: fn.line })),
+      functions,
+      classes: [],
+      types: [],
+      constants: []
+    },
+    imports
+  });
+
+  return {
+    stats: { totalSymbols: 6 },
+    files: {
+      'src/math.js': entry([
+        { name: 'add', kind: 'function', line: 1, exported: true },
+        { name: 'round', kind: 'function', line: 9, exported: false }
+      ]),
+      'src/format.js': entry([{ name: 'format', kind: 'function', line: 1, exported: true }]),
+      'src/app.js': entry(
+        [{ name: 'main', kind: 'function', line: 3, exported: true }],
+        [{ source: './math' }, { source: './format' }]
+      ),
+      'src/cli.js': entry([{ name: 'run', kind: 'function', line: 2, exported: true }], [{ source: './math' }]),
+      'src/unused.js': entry([{ name: 'legacy', kind: 'function', line: 1, exported: true }])
+    },
+    callGraph: {
+      'src/math.js': {
+        add: [{ file: 'src/app.js', line: 5 }, { file: 'src/cli.js', line: 4 }]
+      },
+      'src/format.js': {
+        format: [{ file: 'src/app.js', line: 6 }]
+      }
+    }
+  };
+}
+
+describe('rankFiles', () => {
+  test('ranks heavily imported files first and sums to one', () => {
+    const ranks = rankFiles(createMap());
+    const ordered = Array.from(ranks.entries()).sort((a, b) => b[1] - a[1]).map(([file]) => file);
+
+    expect(ordered[0]).toBe('src/math.js');
+    const total = Array.from(ranks.values()).reduce((sum, rank) => sum + rank, 0);
+    expect(Math.abs(total - 1)).toBeLessThan(1e-6);
+  });
+
+  test('focus files bias the ranking toward their dependencies', () => {
+    const ranks = rankFiles(createMap(), { focus: ['src/app.js'] });
+    expect(ranks.get('src/format.js')).toBeGreaterThan(ranks.get('src/cli.js'));
+    expect(ranks.get('src/unused.js')).toBe(0);
+  });
+
+  test('returns an empty ranking for empty maps', () => {
+    expect(rankFiles({ files: {} }).size).toBe(0);
+  });
+});
+
+describe('rankSymbols', () => {
+  test('orders referenced exports above private and unused symbols', () => {
+    const ranked = rankSymbols(createMap());
+    const names = ranked.map(item => item.name);
+
+    expect(names[0]).toBe('add');
+    expect(ranked[0].references).toBe(2);
+    expect(names.indexOf('format')).toBeLessThan(names.indexOf('legacy'));
+    expect(names.indexOf('add')).toBeLessThan(names.indexOf('round'));
+  });
+});
+
+describe('truncateMap', () => {
+  test('keeps only the top symbols and drops empty files', () => {
+    const truncated = truncateMap(createMap(), 2);
+    const kept = Object.values(truncated.files).flatMap(file => file.symbols.functions.map(fn => fn.name));
+
+    expect(kept).toHaveLength(2);
+    expect(kept).toContain('add');
+    expect(truncated.files['src/unused.js']).toBeUndefined();
+    expect(truncated.files['src/math.js'].symbols.exports.map(item => item.name)).toEqual(['add']);
+    expect(truncated.truncated).toEqual({ limit: 2, totalSymbols: 6 });
+  });
+
+  test('does not modify the source map', () => {
+    const map = createMap();
+    truncateMap(map, 1);
+    expect(map.files['src/math.js'].symbols.functions).toHaveLength(2);
+  });
+});
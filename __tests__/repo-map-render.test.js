@@ -0,0 +1,93 @@
+/**
+ * Tests for token-budgeted repo-map rendering
+ */
+
+const { renderMap, estimateTokens } = require('../lib/repo-map/render');
+
+/**
+ * Build a map where core.ts is imported by every other file
+ */
+function createMap(extraFiles = 0) {
+  const files = {
+    'src/core.ts': {
+      language: 'typescript',
+      symbols: {
+        exports: [],
+        functions: [
+          { name: 'load', kind: 'function', line: 3, exported: true, signature: '(id: string): Promise<User>' },
+          { name: 'cacheKey', kind: 'function', line: 20, exported: false, signature: '(id: string): string' }
+        ],
+        classes: [],
+        types: [
+          { name: 'User', kind: 'interface', line: 1, exported: true, members: [{ name: 'id', type: 'string' }] }
+        ],
+        constants: []
+      },
+      imports: []
+    }
+  };
+  for (let i = 0; i < extraFiles; i++) {
+    files[`src/feature${i}.ts`] = {
+      language: 'typescript',
+      symbols: {
+        exports: [],
+        functions: [{ name: `feature${i}`, kind: 'function', line: 1, exported: true, signature: '(): void' }],
+        classes: [],
+        types: [],
+        constants: []
+      },
+      imports: [{ source: './core' }]
+    };
+  }
+  return { files, callGraph: {} };
+}
+
+describe('renderMap', () => {
+  test('renders every symbol with details when unbounded', () => {
+    const result = renderMap(createMap());
+
+    expect(result.text).toBe([
+      'src/core.ts:',
+      '  interface User :1',
+      '    id: string',
+      '  function load(id: string): Promise<User> :3',
+      '  function cacheKey(id: string): string :20',
+      ''
+    ].join('\n'));
+    expect(result.fits).toBe(true);
+    expect(result.droppedPrivate).toBe(false);
+  });
+
+  test('drops private symbols before details', () => {
+    const full = renderMap(createMap());
+    const result = renderMap(createMap(), { maxTokens: full.tokens - 1 });
+
+    expect(result.droppedPrivate).toBe(true);
+    expect(result.droppedDetails).toBe(false);
+    expect(result.text).not.toContain('cacheKey');
+    expect(result.text).toContain('load(id: string)');
+  });
+
+  test('drops details, then low-ranked files, to fit the budget', () => {
+    const map = createMap(6);
+    const compact = renderMap(map, { maxTokens: 1 });
+    expect(compact.droppedDetails).toBe(true);
+
+    const result = renderMap(map, { maxTokens: 40 });
+    expect(result.fits).toBe(true);
+    expect(result.tokens).toBeLessThanOrEqual(40);
+    expect(result.omittedFiles).toBeGreaterThan(0);
+    expect(result.text.startsWith('src/core.ts:')).toBe(true);
+    expect(result.text).toContain('lower-ranked files omitted');
+  });
+
+  test('uses a custom tokenizer', () => {
+    const words = text => text.split(/\s+/).filter(Boolean).length;
+    const result = renderMap(createMap(), { maxTokens: 1000, tokenizer: words });
+    expect(result.tokens).toBe(words(result.text));
+  });
+
+  test('estimates about four characters per token', () => {
+    expect(estimateTokens('abcdefgh')).toBe(2);
+  });
+});
@@ -0,0 +1,61 @@
+/**
+ * Tests for repo-map Rust impl blocks and macros
+ */
+
+const path = require('path');
+
+const installer = require('../lib/repo-map/installer');
+const runner = require('../lib/repo-map/runner');
+
+const fixtureRoot = path.join(__dirname, 'fixtures', 'repo-map');
+const fixture = path.join(fixtureRoot, 'rust', 'sample.rs');
+
+describe('repo-map Rust extraction', () => {
+  test('marks only #[macro_export] macros as exported', () => {
+    const content = [
+      '#[macro_export]',
+      'macro_rules! public_macro { ($x:expr) => { $x }; }',
+      '#[macro_export(local_inner_macros)]',
+      '#[doc(hidden)]',
+      'macro_rules! inner_macro { () => {}; }',
+      'macro_rules! private_macro { () => {}; }',
+      '#[derive(Debug)]',
+      'struct NotAMacro;'
+    ].join('\n');
+
+    expect(runner.extractRustExportedMacros(content)).toEqual(['public_macro', 'inner_macro']);
+    expect(runner.extractRustExportedMacros('macro_rules! private_macro { () => {}; }')).toEqual([]);
+    expect(runner.extractRustExportedMacros('')).toEqual([]);
+  });
+
+  test('builds trait impl names from the pattern template', () => {
+    const match = { metaVariables: { single: { TRAIT: { text: 'fmt::Display' }, NAME: { text: 'PublicStruct' } } } };
+    expect(runner.applyNameTemplate(match, '$TRAIT for $NAME')).toBe('fmt::Display for PublicStruct');
+
+    const generic = { metaVariables: { TRAIT: { text: 'From<\n    Vec<u8>>' }, NAME: { text: 'Bytes' } } };
+    expect(runner.applyNameTemplate(generic, '$TRAIT for $NAME')).toBe('From< Vec<u8>> for Bytes');
+
+    expect(runner.applyNameTemplate({ metaVariables: { single: { NAME: { text: 'Bytes' } } } }, '$TRAIT for $NAME')).toBeNull();
+  });
+
+  const installed = installer.checkInstalledSync();
+  const runTest = installed.found ? test : test.skip;
+
+  runTest('extracts impl blocks and macros from the fixture', () => {
+    const fileData = runner.scanSingleFile(installed.command, fixture, fixtureRoot);
+    const { classes, functions, exports } = fileData.symbols;
+
+    expect(classes.map(item => [item.name, item.kind])).toEqual(expect.arrayContaining([
+      ['PublicStruct', 'impl'],
+      ['fmt::Display for PublicStruct', 'trait-impl']
+    ]));
+
+    const macros = functions.filter(item => item.kind === 'macro');
+    expect(macros.map(item => [item.name, item.exported])).toEqual([
+      ['private_macro', false],
+      ['public_macro', true]
+    ]);
+    expect(exports.map(item => item.name)).toContain('public_macro');
+    expect(exports.map(item => item.name)).not.toContain('private_macro');
+  });
+});
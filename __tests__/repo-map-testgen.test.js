@@ -0,0 +1,146 @@
+/**
+ * Tests for repo-map test scaffolding
+ */
+
+const {
+  resolveTarget,
+  pickFramework,
+  detectTestLayout,
+  testPathFor,
+  parseParams,
+  scaffoldTests
+} = require('../lib/repo-map/testgen');
+
+/**
+ * Build a map from `{file: {language, functions, classes}}`
+ */
+function createMap(entries) {
+  const files = {};
+  for (const [file, entry] of Object.entries(entries)) {
+    files[file] = {
+      language: entry.language || 'javascript',
+      symbols: {
+        exports: [],
+        functions: entry.functions || [],
+        classes: entry.classes || [],
+        types: [],
+        constants: []
+      },
+      imports: []
+    };
+  }
+  return { version: '1.0.0', files };
+}
+
+describe('repo-map testgen', () => {
+  const map = createMap({
+    'src/math.js': {
+      functions: [
+        { name: 'add', kind: 'function', line: 1, exported: true, signature: '(a, b = 1)' },
+        { name: 'helper', kind: 'function', line: 5, exported: false, signature: '()' }
+      ],
+      classes: [{ name: 'Calculator', kind: 'class', line: 9, exported: true }]
+    },
+    'src/math.test.js': {},
+    'pkg/store/store.go': {
+      language: 'go',
+      functions: [
+        { name: 'Open', kind: 'function', line: 3, exported: true, signature: '(path string, retries, timeout int) (*DB, error)' },
+        { name: 'Close', kind: 'method', receiver: '*DB', line: 9, exported: true, signature: '() error' }
+      ]
+    },
+    'app/billing.py': {
+      language: 'python',
+      functions: [{ name: 'total', kind: 'function', line: 2, exported: true }]
+    },
+    'tests/test_users.py': { language: 'python' }
+  });
+
+  describe('resolveTarget', () => {
+    it('should resolve files to their exported symbols', () => {
+      const resolved = resolveTarget(map, './src/math.js');
+      expect(resolved.file).toBe('src/math.js');
+      expect(resolved.symbols.map(symbol => `${symbol.category}:${symbol.name}`)).toEqual(['functions:add', 'classes:Calculator']);
+    });
+
+    it('should resolve bare symbols and file:symbol targets', () => {
+      expect(resolveTarget(map, 'total').file).toBe('app/billing.py');
+      expect(resolveTarget(map, 'src/math.js:helper').symbols.map(symbol => symbol.name)).toEqual(['helper']);
+      expect(resolveTarget(map, 'src/math.js:missing')).toBeNull();
+      expect(resolveTarget(map, 'nothing')).toBeNull();
+    });
+  });
+
+  describe('pickFramework', () => {
+    it('should prefer a detected framework for the language', () => {
+      expect(pickFramework('typescript', [{ name: 'pytest' }, { name: 'vitest' }])).toBe('vitest');
+      expect(pickFramework('go', [])).toBe('go-test');
+      expect(pickFramework('rust', [])).toBeNull();
+    });
+  });
+
+  describe('detectTestLayout and testPathFor', () => {
+    it('should follow where existing tests live', () => {
+      expect(detectTestLayout(['__tests__/a.test.js', '__tests__/b.test.js', 'src/c.test.js'], 'javascript'))
+        .toEqual({ dir: '__tests__', style: 'test' });
+      expect(detectTestLayout(['src/a.spec.ts', 'src/b.spec.ts'], 'typescript')).toEqual({ dir: null, style: 'spec' });
+      expect(detectTestLayout([], 'python')).toEqual({ dir: 'tests', style: 'test' });
+    });
+
+    it('should build paths per language', () => {
+      expect(testPathFor('src/lib/math.ts', 'typescript', { dir: null, style: 'spec' })).toBe('src/lib/math.spec.ts');
+      expect(testPathFor('lib/math.js', 'javascript', { dir: '__tests__', style: 'test' })).toBe('__tests__/math.test.js');
+      expect(testPathFor('app/billing.py', 'python', { dir: 'tests', style: 'test' })).toBe('tests/test_billing.py');
+      expect(testPathFor('pkg/store/store.go', 'go', { dir: 'tests', style: 'test' })).toBe('pkg/store/store_test.go');
+    });
+  });
+
+  describe('parseParams', () => {
+    it('should read TypeScript, Python and Go parameters', () => {
+      expect(parseParams('(a: number, opts?: Options, ...rest: string[]): void', 'typescript').map(param => param.name))
+        .toEqual(['a', 'opts', 'rest']);
+      expect(parseParams('(self, items: list[int], *, tax: float = 0.1, **kwargs)', 'python'))
+        .toEqual([{ name: 'items', type: 'list[int]' }, { name: 'tax', type: 'float', keyword: true }]);
+      expect(parseParams('(a, b int, s []string) error', 'go')).toEqual([
+        { name: 'a', type: 'int' }, { name: 'b', type: 'int' }, { name: 's', type: '[]string' }
+      ]);
+      expect(parseParams('(int, string)', 'go')).toEqual([{ name: 'arg0', type: 'int' }, { name: 'arg1', type: 'string' }]);
+    });
+  });
+
+  describe('scaffoldTests', () => {
+    it('should write describe/it tests next to colocated tests', () => {
+      const result = scaffoldTests(map, 'src/math.js', { frameworks: [{ name: 'jest' }], readFile: () => 'module.exports = {};' });
+      expect(result.success).toBe(true);
+      expect(result.testFile).toBe('src/math.test.js');
+      expect(result.content).toContain("const { add, Calculator } = require('./math');");
+      expect(result.content).toContain('expect(add(a, b)).toEqual(expected);');
+      expect(result.content).toContain('expect(instance).toBeInstanceOf(Calculator);');
+    });
+
+    it('should write parametrized pytest tests', () => {
+      const result = scaffoldTests(map, 'total', {
+        readFile: () => '\ndef total(items, *, tax=0.1):\n    return sum(items)\n'
+      });
+      expect(result.testFile).toBe('tests/test_billing.py');
+      expect(result.content).toContain('from app.billing import total');
+      expect(result.content).toContain('@pytest.mark.parametrize("items, tax, expected", [');
+      expect(result.content).toContain('assert total(items, tax=tax) == expected');
+    });
+
+    it('should write table-driven Go tests', () => {
+      const result = scaffoldTests(map, 'pkg/store/store.go', { readFile: () => 'package store\n' });
+      expect(result.framework).toBe('go-test');
+      expect(result.content).toContain('package store');
+      expect(result.content).toContain('\t\tretries int\n');
+      expect(result.content).toContain('got, err := Open(tt.path, tt.retries, tt.timeout)');
+      expect(result.content).toContain('func TestDB_Close(t *testing.T) {');
+      expect(result.content).toContain('err := (&DB{}).Close()');
+    });
+
+    it('should report unknown targets and unsupported frameworks', () => {
+      expect(scaffoldTests(map, 'nope').success).toBe(false);
+      expect(scaffoldTests(map, 'src/math.js', { framework: 'pytest' }).error).toMatch(/No supported test framework/);
+    });
+  });
+});
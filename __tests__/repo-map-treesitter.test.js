@@ -0,0 +1,110 @@
+/**
+ * Tests for optional tree-sitter declaration parsing
+ */
+
+const treesitter = require('../lib/repo-map/treesitter');
+
+/**
+ * Minimal syntax node stand-in
+ */
+function node(type, { text = '', row = 0, fields = {}, children = [] } = {}) {
+  const allChildren = [...Object.values(fields), ...children];
+  return {
+    type,
+    text,
+    startPosition: { row, column: 0 },
+    namedChildCount: allChildren.length,
+    namedChild: index => allChildren[index],
+    childForFieldName: name => fields[name] || null
+  };
+}
+
+/**
+ * Module loader that provides `tree-sitter` and one grammar returning `root`
+ */
+function createLoader(grammar, root) {
+  class Parser {
+    setLanguage(language) {
+      this.language = language;
+    }
+
+    parse() {
+      return { rootNode: root };
+    }
+  }
+  return name => {
+    if (name === 'tree-sitter') return Parser;
+    if (name === grammar) return { typescript: 'ts-grammar' };
+    throw new Error(`Cannot find module '${name}'`);
+  };
+}
+
+describe('repo-map tree-sitter parsing', () => {
+  const root = node('program', {
+    children: [
+      node('export_statement', {
+        row: 0,
+        children: [node('class_declaration', {
+          row: 0,
+          fields: { name: node('type_identifier', { text: 'Repository' }) },
+          children: [node('class_body', {
+            children: [node('function_declaration', { fields: { name: node('identifier', { text: 'inner' }) } })]
+          })]
+        })]
+      }),
+      node('function_declaration', {
+        row: 12,
+        fields: { name: node('identifier', { text: 'createRepository' }) },
+        children: [node('statement_block', {
+          children: [node('function_declaration', { row: 13, fields: { name: node('identifier', { text: 'helper' }) } })]
+        })]
+      }),
+      node('interface_declaration', { row: 20, fields: { name: node('type_identifier', { text: 'Options' }) } })
+    ]
+  });
+
+  test('returns null when tree-sitter or the grammar is missing', () => {
+    const missing = () => { throw new Error('Cannot find module'); };
+
+    expect(treesitter.loadParser('typescript', { require: missing })).toBeNull();
+    expect(treesitter.isAvailable('typescript', { require: createLoader('tree-sitter-python', root) })).toBe(false);
+    expect(treesitter.extractDeclarations('typescript', 'class A {}', { require: missing })).toBeNull();
+    expect(treesitter.loadParser('cobol', { require: missing })).toBeNull();
+  });
+
+  test('loads the grammar export for multi-grammar packages', () => {
+    const parser = treesitter.loadParser('typescript', { require: createLoader('tree-sitter-typescript', root) });
+    expect(parser.language).toBe('ts-grammar');
+  });
+
+  test('extracts file-scope declarations without descending into bodies', () => {
+    const declarations = treesitter.extractDeclarations('typescript', '', {
+      require: createLoader('tree-sitter-typescript', root)
+    });
+
+    expect(declarations).toEqual([
+      { category: 'classes', name: 'Repository', line: 1, kind: 'class' },
+      { category: 'functions', name: 'createRepository', line: 13, kind: 'function' },
+      { category: 'types', name: 'Options', line: 21, kind: 'interface' }
+    ]);
+  });
+
+  test('adds only declarations the pattern pass missed', () => {
+    const symbolMaps = {
+      exports: new Map(),
+      functions: new Map([['createRepository', { name: 'createRepository', line: 13, kind: 'function', async: true }]]),
+      classes: new Map(),
+      types: new Map(),
+      constants: new Map()
+    };
+
+    const added = treesitter.mergeDeclarations('typescript', '', symbolMaps, {
+      require: createLoader('tree-sitter-typescript', root)
+    });
+
+    expect(added).toBe(2);
+    expect(symbolMaps.functions.get('createRepository').async).toBe(true);
+    expect(symbolMaps.classes.get('Repository')).toEqual({ name: 'Repository', line: 1, kind: 'class' });
+    expect(symbolMaps.types.has('Options')).toBe(true);
+  });
+});
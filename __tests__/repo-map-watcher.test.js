@@ -0,0 +1,115 @@
+/**
+ * Tests for repo-map watch mode
+ */
+
+const fs = require('fs');
+const os = require('os');
+const path = require('path');
+
+const cache = require('../lib/repo-map/cache');
+const installer = require('../lib/repo-map/installer');
+const updater = require('../lib/repo-map/updater');
+const { watch, getActiveWatcher } = require('../lib/repo-map/watcher');
+
+/**
+ * Resolve once `predicate` holds, polling briefly
+ */
+function waitFor(predicate, timeoutMs = 3000) {
+  return new Promise((resolve, reject) => {
+    const started = Date.now();
+    const tick = () => {
+      if (predicate()) return resolve();
+      if (Date.now() - started > timeoutMs) return reject(new Error('timed out'));
+      setTimeout(tick, 20);
+    };
+    tick();
+  });
+}
+
+describe('repo-map watcher', () => {
+  let tempDir;
+  let handle;
+  const originalStateDir = process.env.AI_STATE_DIR;
+
+  beforeEach(() => {
+    tempDir = fs.mkdtempSync(path.join(os.tmpdir(), 'repo-map-watch-'));
+    process.env.AI_STATE_DIR = '.test-state';
+    jest.spyOn(installer, 'getCommand').mockReturnValue('sg-not-installed');
+    fs.mkdirSync(path.join(tempDir, 'src'));
+    fs.writeFileSync(path.join(tempDir, 'src', 'old.js'), 'export const old = 1;\n');
+    cache.save(tempDir, {
+      version: '1.0.0',
+      project: { languages: ['javascript'] },
+      stats: { totalFiles: 1, totalSymbols: 0 },
+      files: { 'src/old.js': { hash: 'stale', language: 'javascript', symbols: {}, imports: [] } },
+      dependencies: {}
+    });
+  });
+
+  afterEach(() => {
+    if (handle) handle.close();
+    handle = null;
+    jest.restoreAllMocks();
+    process.env.AI_STATE_DIR = originalStateDir;
+    fs.rmSync(tempDir, { recursive: true, force: true });
+  });
+
+  test('re-scans created files and drops deleted ones', async () => {
+    const updates = [];
+    handle = watch(tempDir, { debounceMs: 20, onUpdate: changes => updates.push(changes) });
+
+    fs.writeFileSync(path.join(tempDir, 'src', 'added.js'), 'export function added() {}\n');
+    fs.unlinkSync(path.join(tempDir, 'src', 'old.js'));
+
+    await waitFor(() => {
+      const map = cache.load(tempDir);
+      return map.files['src/added.js'] && !map.files['src/old.js'];
+    });
+    expect(updates.length).toBeGreaterThan(0);
+    expect(cache.load(tempDir).stats.totalFiles).toBe(1);
+  });
+
+  test('ignores non-source files and the state directory', async () => {
+    const spy = jest.spyOn(updater, 'updateFiles');
+    handle = watch(tempDir, { debounceMs: 10 });
+
+    fs.writeFileSync(path.join(tempDir, 'notes.txt'), 'hello\n');
+    fs.writeFileSync(path.join(tempDir, '.test-state', 'scratch.js'), 'x();\n');
+    await new Promise(resolve => setTimeout(resolve, 150));
+
+    expect(spy).not.toHaveBeenCalled();
+  });
+
+  test('records a marker while running', () => {
+    handle = watch(tempDir, { debounceMs: 10 });
+    expect(getActiveWatcher(tempDir)).toMatchObject({ pid: process.pid });
+
+    handle.close();
+    handle = null;
+    expect(getActiveWatcher(tempDir)).toBeNull();
+  });
+
+  test('requires an existing map', () => {
+    fs.rmSync(path.join(tempDir, '.test-state'), { recursive: true, force: true });
+    expect(() => watch(tempDir)).toThrow('No repo map found');
+  });
+});
+
+describe('updateFiles', () => {
+  test('skips files whose hash is unchanged', () => {
+    const tempDir = fs.mkdtempSync(path.join(os.tmpdir(), 'repo-map-update-files-'));
+    try {
+      fs.writeFileSync(path.join(tempDir, 'a.js'), 'export const a = 1;\n');
+      const map = { stats: {}, files: {}, dependencies: {} };
+
+      const first = updater.updateFiles(tempDir, map, 'sg-not-installed', ['a.js']);
+      const second = updater.updateFiles(tempDir, map, 'sg-not-installed', ['a.js', 'missing.js']);
+
+      expect(first).toMatchObject({ total: 1, updated: ['a.js'], deleted: [] });
+      expect(second.total).toBe(0);
+      expect(map.stats.totalFiles).toBe(1);
+    } finally {
+      fs.rmSync(tempDir, { recursive: true, force: true });
+    }
+  });
+});
@@ -1,5 +1,5 @@
 /**
- * Tests for repo-map cache utilities
+ * Tests for repo-map cache utilities and project detection
  */
 
 const fs = require('fs');
@@ -7,6 +7,8 @@ const os = require('os');
 const path = require('path');
 
 const cache = require('../lib/repo-map/cache');
+const installer = require('../lib/repo-map/installer');
+const runner = require('../lib/repo-map/runner');
 
 describe('repo-map cache', () => {
   let tempDir;
@@ -49,3 +51,125 @@ describe('repo-map cache', () => {
     expect(cache.isMarkedStale(tempDir)).toBe(false);
   });
 });
+
+describe('repo-map file cache', () => {
+  let tempDir;
+  const originalStateDir = process.env.AI_STATE_DIR;
+
+  const symbols = {
+    exports: [{ name: 'add', kind: 'function', line: 1 }],
+    functions: [{ name: 'add', kind: 'function', line: 1, exported: true }],
+    classes: [],
+    types: [],
+    constants: []
+  };
+
+  beforeEach(() => {
+    tempDir = fs.mkdtempSync(path.join(os.tmpdir(), 'repo-map-file-cache-'));
+    process.env.AI_STATE_DIR = '.test-state';
+  });
+
+  afterEach(() => {
+    jest.restoreAllMocks();
+    process.env.AI_STATE_DIR = originalStateDir;
+    fs.rmSync(tempDir, { recursive: true, force: true });
+  });
+
+  test('stores hashes and symbols in the shared cache directory', () => {
+    const map = {
+      files: {
+        'src/add.js': { hash: 'abc', language: 'javascript', size: 10, symbols, imports: [] },
+        'src/other.py': { hash: 'def', language: 'python', size: 5, symbols, imports: [] }
+      }
+    };
+
+    cache.saveFileCache(tempDir, map, { javascript: 'fp-js' });
+
+    expect(cache.getFileCachePath(tempDir)).toBe(path.join(tempDir, '.awesome-slash', 'cache', 'repo-map', 'files.json'));
+    const loaded = cache.loadFileCache(tempDir);
+    expect(loaded.extractors).toEqual({ javascript: 'fp-js' });
+    expect(Object.keys(loaded.files)).toEqual(['src/add.js']);
+    expect(loaded.files['src/add.js']).toEqual({ hash: 'abc', language: 'javascript', symbols, imports: [] });
+
+    cache.clearFileCache(tempDir);
+    expect(cache.loadFileCache(tempDir)).toBeNull();
+  });
+
+  test('ignores caches written by another format version', () => {
+    const cachePath = cache.getFileCachePath(tempDir);
+    fs.mkdirSync(path.dirname(cachePath), { recursive: true });
+    fs.writeFileSync(cachePath, JSON.stringify({ version: 0, files: {} }));
+    expect(cache.loadFileCache(tempDir)).toBeNull();
+  });
+
+  test('extractor fingerprints are stable and per language', () => {
+    expect(runner.getExtractorFingerprint('javascript')).toBe(runner.getExtractorFingerprint('javascript'));
+    expect(runner.getExtractorFingerprint('javascript')).not.toBe(runner.getExtractorFingerprint('python'));
+    expect(runner.getExtractorFingerprint('cobol')).toBeNull();
+  });
+
+  test('full scan reuses cached symbols for unchanged files', async () => {
+    jest.spyOn(installer, 'getCommand').mockReturnValue('sg-not-called');
+    fs.mkdirSync(path.join(tempDir, 'src'));
+    fs.writeFileSync(path.join(tempDir, 'src', 'add.js'), 'export function add(a, b) { return a + b; }\n');
+
+    const first = await runner.fullScan(tempDir, ['javascript'], { includeDocs: false });
+    const hash = first.files['src/add.js'].hash;
+    const imports = [{ source: './math', kind: 'import', line: 1 }];
+    const fileCache = {
+      extractors: { javascript: runner.getExtractorFingerprint('javascript') },
+      files: { 'src/add.js': { hash, language: 'javascript', symbols, imports } }
+    };
+
+    const map = await runner.fullScan(tempDir, ['javascript'], { includeDocs: false, fileCache });
+
+    expect(map.stats.cachedFiles).toBe(1);
+    expect(map.stats.totalSymbols).toBe(1);
+    expect(map.files['src/add.js'].symbols).toEqual(symbols);
+    expect(map.dependencies['src/add.js']).toEqual(['./math']);
+  });
+
+  test('full scan ignores cache entries from other extractor versions', async () => {
+    jest.spyOn(installer, 'getCommand').mockReturnValue('sg-not-called');
+    fs.mkdirSync(path.join(tempDir, 'src'));
+    fs.writeFileSync(path.join(tempDir, 'src', 'add.js'), 'export function add(a, b) { return a + b; }\n');
+
+    const first = await runner.fullScan(tempDir, ['javascript'], { includeDocs: false });
+    const fileCache = {
+      extractors: { javascript: 'outdated' },
+      files: { 'src/add.js': { hash: first.files['src/add.js'].hash, language: 'javascript', symbols, imports: [] } }
+    };
+
+    const map = await runner.fullScan(tempDir, ['javascript'], { includeDocs: false, fileCache });
+
+    expect(map.stats.cachedFiles).toBe(0);
+    expect(map.files['src/add.js'].symbols.functions).toEqual([]);
+  });
+});
+
+describe('repo-map framework detection', () => {
+  let tempDir;
+
+  beforeEach(() => {
+    tempDir = fs.mkdtempSync(path.join(os.tmpdir(), 'repo-map-frameworks-'));
+  });
+
+  afterEach(() => {
+    fs.rmSync(tempDir, { recursive: true, force: true });
+  });
+
+  test('detects rails from the Gemfile', () => {
+    fs.writeFileSync(path.join(tempDir, 'Gemfile'), "source 'https://rubygems.org'\ngem 'rails', '~> 7.1'\n");
+    expect(runner.detectFrameworks(tempDir)).toEqual(['rails']);
+  });
+
+  test('detects spring from Maven or Gradle builds', () => {
+    fs.writeFileSync(path.join(tempDir, 'build.gradle.kts'), 'plugins {\n  id("org.springframework.boot") version "3.3.0"\n}\n');
+    expect(runner.detectFrameworks(tempDir)).toEqual(['spring']);
+  });
+
+  test('returns no frameworks for plain ruby projects', () => {
+    fs.writeFileSync(path.join(tempDir, 'Gemfile'), "gem 'rake'\n");
+    expect(runner.detectFrameworks(tempDir)).toEqual([]);
+  });
+});
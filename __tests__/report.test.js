@@ -0,0 +1,128 @@
+/**
+ * Tests for the HTML report
+ */
+
+const fs = require('fs');
+const os = require('os');
+const path = require('path');
+
+const {
+  readInput,
+  collectReport,
+  renderHtml,
+  generateReport
+} = require('../lib/report');
+
+const SHA = 'b'.repeat(40);
+
+describe('report', () => {
+  let dir;
+
+  const write = (file, content) => {
+    fs.mkdirSync(path.dirname(path.join(dir, file)), { recursive: true });
+    fs.writeFileSync(path.join(dir, file), typeof content === 'string' ? content : JSON.stringify(content));
+  };
+  const git = () => `${SHA}\n`;
+
+  beforeEach(() => {
+    dir = fs.mkdtempSync(path.join(os.tmpdir(), 'report-'));
+    write('src/cart.js', "const total = 1;\nconsole.log('total', total);\nconst token = 'ghp_x';\nmodule.exports = total;\n");
+  });
+
+  afterEach(() => {
+    fs.rmSync(dir, { recursive: true, force: true });
+  });
+
+  const slop = {
+    findings: [
+      { file: 'src/cart.js', line: 2, patternName: 'console_debugging', severity: 'medium', description: 'Console <debugging> left in code' },
+      { file: 'src/cart.js', line: 3, patternName: 'github_token', severity: 'critical', description: 'GitHub token', content: "const token = 'ghp_x';" }
+    ]
+  };
+  const deps = {
+    success: true,
+    managers: ['npm'],
+    dependencies: 4,
+    vulnerabilities: [{ ecosystem: 'npm', name: 'lodash', version: '4.17.0', id: 'GHSA-1', severity: 'high', summary: 'Prototype pollution', fixed: '4.17.21' }],
+    outdated: [{ ecosystem: 'npm', manager: 'npm', name: 'react', current: '17.0.2', latest: '18.2.0', major: true }],
+    unused: []
+  };
+  const sarif = {
+    runs: [{
+      tool: { driver: { name: 'deslop', rules: [{ id: 'console_debugging' }] } },
+      results: [{ ruleId: 'console_debugging', level: 'warning', message: { text: 'Console <debugging> left in code' }, locations: [{ physicalLocation: { artifactLocation: { uri: 'src/cart.js' }, region: { startLine: 2 } } }] }]
+    }]
+  };
+
+  it('should recognize each kind of input', () => {
+    const kind = content => (readInput(dir, 'input', typeof content === 'string' ? content : JSON.stringify(content)) || {}).kind;
+    expect(kind(slop)).toBe('slop');
+    expect(kind({ findings: [] })).toBe('slop');
+    expect(kind({ items: [{ file: 'src/cart.js', line: 1, category: 'security', severity: 'high', description: 'SQL built from input' }] })).toBe('review');
+    expect(kind(deps)).toBe('deps');
+    expect(kind(sarif)).toBe('sarif');
+    expect(kind('SF:src/cart.js\nDA:1,1\nDA:2,0\nend_of_record\n')).toBe('coverage');
+    expect(kind({ report: 'lcov.info', totals: { covered: 1, total: 2, percent: 50, files: 1 }, files: [] })).toBe('coverage');
+    expect(kind({ hello: 'world' })).toBeUndefined();
+    expect(kind('not a report')).toBeUndefined();
+
+    const workspaces = readInput(dir, 'deps.json', JSON.stringify({ success: true, packages: [{ name: 'web', path: 'packages/web', report: deps }, { name: 'api', path: 'packages/api', report: { success: false } }] }));
+    expect(workspaces.deps).toHaveLength(1);
+    expect(workspaces.findings.map(finding => finding.rule)).toEqual(['GHSA-1']);
+
+    const coverage = readInput(dir, 'lcov.info', `SF:${dir}/src/cart.js\nDA:1,1\nDA:2,0\nDA:4,1\nend_of_record\n`).coverage;
+    expect(coverage.totals).toMatchObject({ covered: 2, total: 3, files: 1 });
+    expect(coverage.files[0].file).toBe('src/cart.js');
+  });
+
+  it('should merge findings and keep secrets out of snippets', () => {
+    const report = collectReport(dir, [
+      { name: 'slop.json', content: JSON.stringify(slop) },
+      { name: 'slop.sarif', content: JSON.stringify(sarif) },
+      { name: 'deps.json', content: JSON.stringify(deps) },
+      { name: 'notes.txt', content: 'hello' }
+    ], { run: git });
+
+    expect(report.inputs.map(input => input.kind)).toEqual(['slop', 'sarif', 'deps']);
+    expect(report.errors).toEqual(['notes.txt: not slop, review, deps-audit, SARIF, or coverage output; skipped']);
+    expect(report.commit).toBe(SHA);
+    expect(report.counts).toEqual({ critical: 1, high: 1, medium: 1, low: 0 });
+    expect(report.sources).toEqual({
+      security: { critical: 1, high: 0, medium: 0, low: 0 },
+      slop: { critical: 0, high: 0, medium: 1, low: 0 },
+      dependencies: { critical: 0, high: 1, medium: 0, low: 0 }
+    });
+
+    const consoleFinding = report.findings.find(finding => finding.rule === 'console_debugging');
+    expect(consoleFinding.occurrences).toBe(2);
+    expect(consoleFinding.snippet).toEqual({ start: 1, line: 2, lines: ['const total = 1;', "console.log('total', total);", '[hidden: flagged as a secret]', 'module.exports = total;'] });
+    expect(report.findings.find(finding => finding.rule === 'github_token').snippet).toBeNull();
+  });
+
+  it('should render a standalone page with escaped content', () => {
+    write('slop.json', slop);
+    write('deps.json', deps);
+    write('lcov.info', 'SF:src/cart.js\nDA:1,1\nDA:2,0\nend_of_record\n');
+
+    const result = generateReport(dir, ['slop.json', 'deps.json', 'lcov.info', 'missing.json'], { title: 'Shop <scan>', generatedAt: '2026-10-14T08:00:00.000Z', run: git });
+    expect(result.success).toBe(true);
+    const { html } = result;
+    expect(html.startsWith('<!DOCTYPE html>')).toBe(true);
+    expect(html).toContain('<html lang="en">');
+    expect(html).toContain('<title>Shop &lt;scan&gt;</title>');
+    expect(html).toContain('Generated 2026-10-14 08:00:00 UTC · Commit bbbbbbbbbbbb');
+    expect(html).toContain('Console &lt;debugging&gt; left in code');
+    expect(html).not.toContain('<debugging>');
+    expect(html).not.toContain('ghp_x');
+    expect(html).toContain('<svg class="chart"');
+    expect(html).toContain('<div class="filters" data-table="findings-table">');
+    expect(html).toContain('<code>react</code>');
+    expect(html).toContain('1/2 lines across 1 files');
+    expect(html).toContain('missing.json: cannot read');
+    expect(html).not.toMatch(/<(?:link|script)[^>]+(?:src|href)=/);
+
+    expect(renderHtml(collectReport(dir, [], { run: () => null }), { generatedAt: '2026-10-14T08:00:00Z' })).toContain('<p>No findings.</p>');
+    expect(generateReport(dir, []).success).toBe(false);
+    expect(generateReport(dir, ['missing.json']).error).toMatch(/^missing\.json: cannot read/);
+  });
+});
@@ -0,0 +1,198 @@
+/**
+ * Tests for lib/resolve (merge conflict classification and resolution)
+ */
+
+const { execFileSync } = require('child_process');
+const fs = require('fs');
+const os = require('os');
+const path = require('path');
+
+const resolve = require('../lib/resolve');
+const journal = require('../lib/journal');
+
+describe('resolve', () => {
+  it('should parse conflict hunks with and without a base section', () => {
+    const { hunks } = resolve.parseConflicts([
+      'const a = 1;',
+      '<<<<<<< HEAD',
+      'const b = 2;',
+      '||||||| base',
+      'const b = 0;',
+      '=======',
+      'const b = 3;',
+      '>>>>>>> feature',
+      'const c = 4;',
+      '<<<<<<< HEAD',
+      '=======',
+      'const d = 5;',
+      '>>>>>>> feature',
+      ''
+    ].join('\n'));
+
+    expect(hunks).toEqual([
+      { line: 2, end: 8, oursLine: 2, ours: ['const b = 2;'], base: ['const b = 0;'], theirs: ['const b = 3;'], labels: { ours: 'HEAD', theirs: 'feature' } },
+      { line: 10, end: 13, oursLine: 4, ours: [], base: null, theirs: ['const d = 5;'], labels: { ours: 'HEAD', theirs: 'feature' } }
+    ]);
+  });
+
+  it('should keep both sides of import conflicts and re-sort them', () => {
+    const js = resolve.classifyConflict('/repo', { file: 'src/cart.js', status: 'both-modified' }, {
+      readFile: () => [
+        "import { a } from './a';",
+        '<<<<<<< HEAD',
+        "import { b } from './b';",
+        "import { d } from './d';",
+        '||||||| base',
+        "import { d } from './d';",
+        "import { old } from './old';",
+        '=======',
+        "import { c } from './c';",
+        "import { d } from './d';",
+        "import { old } from './old';",
+        '>>>>>>> feature',
+        '',
+        'export const total = 1;',
+        ''
+      ].join('\n')
+    });
+    expect(js.category).toBe('imports');
+    // `old` was removed on our side, so it stays removed
+    expect(js.content).toBe([
+      "import { a } from './a';",
+      "import { b } from './b';",
+      "import { c } from './c';",
+      "import { d } from './d';",
+      '',
+      'export const total = 1;',
+      ''
+    ].join('\n'));
+
+    const py = resolve.classifyConflict('/repo', { file: 'app/views.py', status: 'both-modified' }, {
+      readFile: () => '<<<<<<< HEAD\nfrom app import models\nimport os\n=======\nimport os\nimport sys\n>>>>>>> main\n'
+    });
+    expect(py.content).toBe('from app import models\nimport os\nimport sys\n');
+
+    const logic = resolve.classifyConflict('/repo', { file: 'src/cart.js', status: 'both-modified' }, {
+      readFile: () => "<<<<<<< HEAD\nimport { b } from './b';\nconst x = 1;\n=======\nconst x = 2;\n>>>>>>> main\n"
+    });
+    expect(logic).toMatchObject({ category: 'logic', reason: '1 of 1 hunk(s) change code on both sides' });
+  });
+
+  it('should classify lockfiles, generated files, and whitespace conflicts', () => {
+    const classify = (file, content = '', status = 'both-modified') => resolve.classifyConflict('/repo', { file, status }, {
+      generate: ['npm', 'run', 'generate'],
+      readFile: () => content
+    });
+
+    expect(classify('packages/api/package-lock.json')).toMatchObject({
+      category: 'lockfile',
+      manifest: 'packages/api/package.json',
+      command: ['npm', 'install', '--package-lock-only', '--ignore-scripts', '--prefix', 'packages/api']
+    });
+    expect(classify('go.sum').command).toEqual(['go', '-C', '.', 'mod', 'tidy']);
+    expect(classify('sub/Gemfile.lock').command).toBeNull();
+    expect(classify('api/user.pb.go', '<<<<<<< HEAD\na\n=======\nb\n>>>>>>> x\n')).toMatchObject({ category: 'generated', command: ['npm', 'run', 'generate'] });
+    expect(classify('src/schema.ts', '// @generated by codegen\n<<<<<<< HEAD\na\n=======\nb\n>>>>>>> x\n').category).toBe('generated');
+    expect(classify('README.md', '<<<<<<< HEAD\nSee  the docs.\n=======\nSee the\ndocs.\n>>>>>>> x\n')).toMatchObject({ category: 'whitespace', content: 'See  the docs.\n' });
+    expect(classify('src/old.js', '', 'deleted-by-them')).toMatchObject({ category: 'logic', reason: 'deleted by them: decide whether the file stays' });
+  });
+
+  it('should read the generator from the project config or package scripts', () => {
+    const root = fs.mkdtempSync(path.join(os.tmpdir(), 'resolve-settings-'));
+    try {
+      expect(resolve.readSettings(root)).toEqual({ generate: null, error: null });
+      fs.writeFileSync(path.join(root, 'package.json'), JSON.stringify({ scripts: { codegen: 'graphql-codegen' } }));
+      fs.writeFileSync(path.join(root, 'pnpm-lock.yaml'), '');
+      expect(resolve.generatorCommand(root, resolve.readSettings(root))).toEqual(['pnpm', 'run', 'codegen']);
+      fs.writeFileSync(path.join(root, '.awesome-slash.json'), JSON.stringify({ resolve: { generate: 'make proto' } }));
+      expect(resolve.generatorCommand(root, resolve.readSettings(root))).toEqual(['make', 'proto']);
+      fs.writeFileSync(path.join(root, '.awesome-slash.json'), JSON.stringify({ resolve: { generate: [] } }));
+      expect(resolve.readSettings(root).error).toBe('.awesome-slash.json: resolve.generate must be a command string or an array of arguments');
+    } finally {
+      fs.rmSync(root, { recursive: true, force: true });
+    }
+  });
+
+  describe('merge', () => {
+    let root;
+    const write = (file, content) => {
+      fs.mkdirSync(path.dirname(path.join(root, file)), { recursive: true });
+      fs.writeFileSync(path.join(root, file), content);
+    };
+    const git = (...args) => execFileSync('git', args, { cwd: root, stdio: 'pipe', encoding: 'utf8' });
+
+    beforeEach(() => {
+      root = fs.realpathSync(fs.mkdtempSync(path.join(os.tmpdir(), 'resolve-')));
+      git('init', '-q', '-b', 'main');
+      git('config', 'user.email', 'test@example.com');
+      git('config', 'user.name', 'Test');
+      git('config', 'merge.conflictStyle', 'diff3');
+      write('package.json', '{ "name": "shop" }\n');
+      write('package-lock.json', '{ "lockfileVersion": 3 }\n');
+      write('src/cart.js', "import { a } from './a';\n\nexport function total(items) {\n  return items.length;\n}\n");
+      git('add', '.');
+      git('commit', '-q', '-m', 'init');
+
+      git('checkout', '-q', '-b', 'feature');
+      write('package-lock.json', '{ "lockfileVersion": 3, "feature": true }\n');
+      write('src/cart.js', "import { a } from './a';\nimport { c } from './c';\n\nexport function total(items) {\n  return items.reduce((sum, item) => sum + item.price, 0);\n}\n");
+      git('commit', '-q', '-am', 'Sum prices');
+
+      git('checkout', '-q', 'main');
+      write('package-lock.json', '{ "lockfileVersion": 3, "main": true }\n');
+      write('src/cart.js', "import { a } from './a';\nimport { b } from './b';\n\nexport function total(items) {\n  return items.filter(Boolean).length;\n}\n");
+      git('commit', '-q', '-am', 'Skip empty items');
+      try {
+        git('merge', '-q', 'feature');
+      } catch {
+        // Conflicts expected
+      }
+    });
+
+    afterEach(() => {
+      fs.rmSync(root, { recursive: true, force: true });
+    });
+
+    it('should plan the merge with context for logic conflicts', () => {
+      expect(resolve.listConflicts(root)).toEqual([
+        { file: 'package-lock.json', status: 'both-modified' },
+        { file: 'src/cart.js', status: 'both-modified' }
+      ]);
+      const map = { files: { 'src/cart.js': { symbols: { functions: [{ name: 'total', line: 4 }] } } } };
+      const plan = resolve.planResolution(root, { map });
+
+      expect(plan.operation.type).toBe('merge');
+      expect(plan.conflicts.map(entry => [entry.file, entry.category])).toEqual([['src/cart.js', 'logic'], ['package-lock.json', 'lockfile']]);
+      const [cart] = plan.conflicts;
+      expect(cart.hunks.map(hunk => hunk.symbol)).toEqual([null, 'total']);
+      expect(cart.hunks[0].merged).toEqual(["import { b } from './b';", "import { c } from './c';"]);
+      expect(cart.hunks[1].base).toEqual(['  return items.length;']);
+      expect(cart.reason).toBe('1 of 2 hunk(s) change code on both sides');
+      expect(cart.commits.ours.map(commit => commit.subject)).toEqual(['Skip empty items']);
+      expect(cart.commits.theirs.map(commit => commit.subject)).toEqual(['Sum prices']);
+
+      const text = resolve.renderPlan(plan);
+      expect(text).toContain('**Conflicts**: 2 (1 logic, 1 lockfile)');
+      expect(text).toContain('Hunk 1, line 2, mechanical:');
+      expect(text).toContain('Hunk 2, line 10 in `total`:');
+    });
+
+    it('should regenerate lockfiles through the journal and stage them', () => {
+      const commands = [];
+      const run = (basePath, argv) => {
+        commands.push(argv.join(' '));
+        return argv[0] === 'git' ? execFileSync(argv[0], argv.slice(1), { cwd: basePath, encoding: 'utf8' }) : '';
+      };
+      const plan = resolve.planResolution(root);
+      const tx = journal.begin(root, 'resolve', { run });
+      const applied = resolve.applyResolution(root, plan, { journal: tx });
+      tx.commit();
+
+      expect(applied.resolved).toEqual([{ file: 'package-lock.json', category: 'lockfile' }]);
+      expect(applied.deferred).toEqual([]);
+      expect(commands).toEqual(['npm install --package-lock-only --ignore-scripts', 'git add -- package-lock.json']);
+      expect(fs.readFileSync(path.join(root, 'package-lock.json'), 'utf8')).toBe('{ "lockfileVersion": 3, "main": true }\n');
+      expect(resolve.listConflicts(root).map(entry => entry.file)).toEqual(['src/cart.js']);
+    });
+  });
+});
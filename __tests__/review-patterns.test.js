@@ -15,7 +15,11 @@ const {
   getPatternCount,
   getTotalPatternCount,
   searchPatterns,
-  getFrameworksWithCategory
+  getFrameworksWithCategory,
+  getPatternSetsForFrameworks,
+  getPatternsForFrameworkVersion,
+  satisfiesRange,
+  getSecurityTags
 } = require('../lib/patterns/review-patterns');
 
 describe('review-patterns', () => {
@@ -32,6 +36,15 @@ describe('review-patterns', () => {
       expect(reviewPatterns).toHaveProperty('express');
       expect(reviewPatterns).toHaveProperty('rust');
       expect(reviewPatterns).toHaveProperty('go');
+      expect(reviewPatterns).toHaveProperty('rails');
+      expect(reviewPatterns).toHaveProperty('nuxt');
+      expect(reviewPatterns).toHaveProperty('svelte');
+      expect(reviewPatterns).toHaveProperty('nextjs');
+      expect(reviewPatterns).toHaveProperty('spring');
+      expect(reviewPatterns).toHaveProperty('security');
+      expect(reviewPatterns).toHaveProperty('accessibility');
+      expect(reviewPatterns).toHaveProperty('migrations');
+      expect(reviewPatterns).toHaveProperty('api_design');
     });
 
     it('should have categories with arrays of patterns', () => {
@@ -234,6 +247,69 @@ describe('review-patterns', () => {
     });
   });
 
+  describe('getPatternSetsForFrameworks', () => {
+    it('should add accessibility patterns for frontend frameworks', () => {
+      expect(getPatternSetsForFrameworks(['React'])).toEqual(['react', 'accessibility', 'security']);
+      expect(getPatternSetsForFrameworks(['svelte', 'express'])).toEqual(['svelte', 'express', 'accessibility', 'api_design', 'security']);
+    });
+
+    it('should only apply security patterns to backend or unknown stacks', () => {
+      expect(getPatternSetsForFrameworks('django')).toEqual(['django', 'api_design', 'security']);
+      expect(getPatternSetsForFrameworks(['unknown'])).toEqual(['security']);
+      expect(getPatternSetsForFrameworks()).toEqual(['security']);
+    });
+
+    it('should add migration patterns when reviewed files include migrations', () => {
+      expect(getPatternSetsForFrameworks('rails', { files: ['app/models/user.rb', 'db/migrate/20240101_drop_email.rb'] }))
+        .toEqual(['rails', 'api_design', 'migrations', 'security']);
+      expect(getPatternSetsForFrameworks('rails', { files: ['app/models/user.rb'] })).toEqual(['rails', 'api_design', 'security']);
+    });
+  });
+
+  describe('satisfiesRange', () => {
+    it('should check comparator ranges with alternatives', () => {
+      expect(satisfiesRange('18.2.0', '>=18')).toBe(true);
+      expect(satisfiesRange('17.0.2', '>=18')).toBe(false);
+      expect(satisfiesRange('4.0.1', '>=3.1 <4.1')).toBe(true);
+      expect(satisfiesRange('4.1', '>=3.1 <4.1')).toBe(false);
+      expect(satisfiesRange('3.0.0', '<2 || >=3')).toBe(true);
+      expect(satisfiesRange('unknown', '>=1')).toBe(false);
+    });
+  });
+
+  describe('getPatternsForFrameworkVersion', () => {
+    it('should keep only patterns whose range matches the detected version', () => {
+      const react17 = getPatternsForFrameworkVersion('react', { react: '17.0.2' });
+      expect(react17.hooks_rules).toEqual(reviewPatterns.react.hooks_rules);
+      expect(react17.concurrent_rendering).toEqual(['Relying on setState batching outside event handlers (React 17 only batches inside handlers)']);
+      expect(react17.react_19).toEqual(['Using use(), useActionState or ref-as-prop APIs that need React 19']);
+      expect(getPatternsForFrameworkVersion('react', { react: '19.0.0' }).react_19).toHaveLength(3);
+    });
+
+    it('should check ranges against another package and annotate unknown versions', () => {
+      expect(getPatternsForFrameworkVersion('fastapi', { pydantic: '1.10.13' }).pydantic_version)
+        .toEqual(['Pydantic v2-only APIs (model_dump, field_validator, ConfigDict) on a v1 install']);
+      const unknown = getPatternsForFrameworkVersion('django');
+      expect(unknown.async_views[0]).toBe('async def views (not supported before Django 3.1) [django <3.1]');
+      expect(getPatternsForFrameworkVersion('nonexistent')).toBeNull();
+    });
+  });
+
+  describe('getSecurityTags', () => {
+    it('should extract CWE and OWASP IDs', () => {
+      expect(getSecurityTags('SQL injection (CWE-89, OWASP A03:2021) see cwe-89')).toEqual({ cwe: ['CWE-89'], owasp: ['A03:2021'] });
+      expect(getSecurityTags(undefined)).toEqual({ cwe: [], owasp: [] });
+    });
+
+    it('should tag every cross-language security pattern', () => {
+      Object.values(reviewPatterns.security).flat().forEach(pattern => {
+        const tags = getSecurityTags(pattern);
+        expect(tags.cwe.length).toBeGreaterThan(0);
+        expect(tags.owasp.length).toBeGreaterThan(0);
+      });
+    });
+  });
+
   describe('Pattern Structure Validation', () => {
     describe('pattern content quality', () => {
       it('should have non-empty patterns with meaningful content', () => {
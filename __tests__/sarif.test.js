@@ -0,0 +1,98 @@
+/**
+ * Tests for SARIF output
+ */
+
+const fs = require('fs');
+const os = require('os');
+const path = require('path');
+
+const { slopToSarif, reviewToSarif } = require('../lib/patterns/sarif');
+const { generateFixes } = require('../lib/patterns/fixer');
+const { slopPatterns } = require('../lib/patterns/slop-patterns');
+
+describe('sarif', () => {
+  let root;
+
+  beforeEach(() => {
+    root = fs.mkdtempSync(path.join(os.tmpdir(), 'sarif-'));
+  });
+
+  afterEach(() => {
+    fs.rmSync(root, { recursive: true, force: true });
+  });
+
+  it('should emit rules, levels, and fingerprints for slop findings', () => {
+    fs.mkdirSync(path.join(root, 'src'));
+    fs.writeFileSync(path.join(root, 'src/app.js'), 'try { run(); } catch (e) {}\nconsole.log(1);\n');
+    const findings = [
+      { file: 'src/app.js', line: 1, patternName: 'empty_catch_js', severity: 'high', certainty: 'HIGH', description: 'Empty catch', autoFix: 'add_logging', phase: 1 },
+      { file: 'src/app.js', line: 2, patternName: 'console_debugging', severity: 'medium', certainty: 'HIGH', description: 'Console', autoFix: 'remove', phase: 1 },
+      { file: 'src/app.js', line: 2, patternName: 'console_debugging', severity: 'medium', certainty: 'HIGH', description: 'Console', autoFix: 'remove', phase: 1 }
+    ];
+
+    const sarif = slopToSarif(root, findings, { toolVersion: '1.2.3' });
+    const run = sarif.runs[0];
+
+    expect(sarif.version).toBe('2.1.0');
+    expect(run.tool.driver).toMatchObject({ name: 'deslop', version: '1.2.3' });
+    expect(run.tool.driver.rules.map(rule => [rule.id, rule.defaultConfiguration.level])).toEqual([
+      ['empty_catch_js', 'error'],
+      ['console_debugging', 'warning']
+    ]);
+    expect(run.tool.driver.rules[0].shortDescription.text).toBe(slopPatterns.empty_catch_js.description);
+    expect(run.results.map(result => result.ruleIndex)).toEqual([0, 1, 1]);
+    expect(run.results[0].locations[0].physicalLocation).toEqual({
+      artifactLocation: { uri: 'src/app.js', uriBaseId: '%SRCROOT%' },
+      region: { startLine: 1 }
+    });
+    expect(run.results[0].partialFingerprints['slopFingerprint/v1']).toMatch(/^[0-9a-f]{16}$/);
+    expect(run.results[0].fixes).toBeUndefined();
+  });
+
+  it('should include auto-fixer patches as SARIF fixes', () => {
+    fs.writeFileSync(path.join(root, 'a.js'), 'run();\nconsole.log(1);\ndone();\n');
+    const findings = [{ file: 'a.js', line: 2, patternName: 'console_debugging', severity: 'medium', autoFix: 'remove' }];
+
+    const sarif = slopToSarif(root, findings, { fixes: generateFixes(root, findings, { patterns: slopPatterns }) });
+    expect(sarif.runs[0].results[0].fixes).toEqual([{
+      description: { text: 'Apply remove fix for console_debugging' },
+      artifactChanges: [{
+        artifactLocation: { uri: 'a.js', uriBaseId: '%SRCROOT%' },
+        replacements: [{
+          deletedRegion: { startLine: 2, startColumn: 1, endLine: 3, endColumn: 1 },
+          insertedContent: { text: '' }
+        }]
+      }]
+    }]);
+  });
+
+  it('should convert review findings and skip false positives', () => {
+    const sarif = reviewToSarif(root, [
+      { file: 'api/auth.ts', line: 10, severity: 'critical', category: 'security', description: 'Token logged', suggestion: 'Redact it' },
+      { file: 'api/auth.ts', line: 20, severity: 'medium', category: 'security', description: 'Weak hash' },
+      { file: 'ui/list.tsx', line: 5, severity: 'low', pass: 'performance', description: 'Inline closure' },
+      { file: 'ui/list.tsx', line: 9, severity: 'high', category: 'performance', description: 'Nope', falsePositive: true }
+    ]);
+    const run = sarif.runs[0];
+
+    expect(run.tool.driver.rules.map(rule => rule.id)).toEqual(['review/security', 'review/performance']);
+    expect(run.tool.driver.rules[0].properties).toEqual({ tags: ['review', 'security'], 'security-severity': '9.0' });
+    expect(run.results).toHaveLength(3);
+    expect(run.results[0]).toMatchObject({ level: 'error', message: { text: 'Token logged\nSuggestion: Redact it' } });
+    expect(run.results[2]).toMatchObject({ ruleId: 'review/performance', level: 'note' });
+  });
+
+  it('should carry CWE and OWASP IDs on review results and rule tags', () => {
+    const sarif = reviewToSarif(root, [
+      { file: 'api/db.py', line: 3, severity: 'high', category: 'security', description: 'SQL built with f-strings (CWE-89, OWASP A03:2021)' },
+      { file: 'api/files.py', line: 8, severity: 'high', category: 'security', description: 'Path joined from input', cwe: 'CWE-22', owasp: ['A01:2021'] }
+    ]);
+    const run = sarif.runs[0];
+
+    expect(run.results[0].properties).toMatchObject({ cwe: ['CWE-89'], owasp: ['A03:2021'] });
+    expect(run.results[1].properties).toMatchObject({ cwe: ['CWE-22'], owasp: ['A01:2021'] });
+    expect(run.tool.driver.rules[0].properties.tags).toEqual([
+      'review', 'security', 'external/cwe/cwe-89', 'external/owasp/a03:2021', 'external/cwe/cwe-22', 'external/owasp/a01:2021'
+    ]);
+  });
+});
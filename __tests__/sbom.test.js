@@ -0,0 +1,253 @@
+/**
+ * Tests for SBOM generation
+ */
+
+const fs = require('fs');
+const os = require('os');
+const path = require('path');
+
+const {
+  integrityHashes,
+  purlOf,
+  readLockGraph,
+  generateSbom
+} = require('../lib/sbom');
+
+const SHA512 = 'sha512-' + Buffer.alloc(64, 1).toString('base64');
+const SHA512_HEX = '01'.repeat(64);
+const SHA256_HEX = 'ab'.repeat(32);
+
+describe('sbom', () => {
+  let dir;
+
+  beforeEach(() => {
+    dir = fs.mkdtempSync(path.join(os.tmpdir(), 'sbom-'));
+  });
+
+  afterEach(() => {
+    fs.rmSync(dir, { recursive: true, force: true });
+  });
+
+  const write = (file, content) => {
+    fs.mkdirSync(path.dirname(path.join(dir, file)), { recursive: true });
+    fs.writeFileSync(path.join(dir, file), typeof content === 'string' ? content : JSON.stringify(content));
+  };
+  const edges = graph => Object.fromEntries([...graph.nodes].map(([id, node]) => [id, node.dependsOn]));
+
+  it('should convert lockfile hashes and build package URLs', () => {
+    expect(integrityHashes(`${SHA512} sha1-AQID`)).toEqual([
+      { alg: 'SHA-512', content: SHA512_HEX },
+      { alg: 'SHA-1', content: '010203' }
+    ]);
+    expect(purlOf('npm', '@babel/core', '7.23.0')).toBe('pkg:npm/%40babel/core@7.23.0');
+    expect(purlOf('python', 'Typing_Extensions', '4.8.0')).toBe('pkg:pypi/typing-extensions@4.8.0');
+    expect(purlOf('go', 'github.com/gorilla/mux', 'v1.8.0')).toBe('pkg:golang/github.com/gorilla/mux@v1.8.0');
+    expect(purlOf('rust', 'serde', '1.0.0+build')).toBe('pkg:cargo/serde@1.0.0%2Bbuild');
+  });
+
+  it('should read edges and hashes from npm, yarn, and pnpm lockfiles', () => {
+    const npm = readLockGraph('package-lock.json', JSON.stringify({
+      lockfileVersion: 3,
+      packages: {
+        '': { name: 'shop', dependencies: { express: '^4' } },
+        'node_modules/express': { version: '4.18.2', integrity: SHA512, dependencies: { debug: '2.6.9', ms: '^2' } },
+        'node_modules/express/node_modules/debug': { version: '2.6.9', dependencies: { ms: '2.0.0' } },
+        'node_modules/ms': { version: '2.1.3' }
+      }
+    }));
+    expect(npm.direct).toEqual(['express@4.18.2']);
+    expect(edges(npm)).toEqual({ 'express@4.18.2': ['debug@2.6.9', 'ms@2.1.3'], 'debug@2.6.9': ['ms@2.1.3'], 'ms@2.1.3': [] });
+    expect(npm.nodes.get('express@4.18.2').hashes).toEqual([{ alg: 'SHA-512', content: SHA512_HEX }]);
+
+    const yarn = readLockGraph('yarn.lock', [
+      '"@babel/code-frame@^7.0.0", "@babel/code-frame@^7.22.13":',
+      '  version "7.22.13"',
+      '  resolved "https://registry.yarnpkg.com/@babel/code-frame/-/code-frame-7.22.13.tgz#0123456789abcdef0123456789abcdef01234567"',
+      '  dependencies:',
+      '    js-tokens "^4.0.0"',
+      '',
+      'js-tokens@^4.0.0:',
+      '  version "4.0.0"',
+      `  integrity ${SHA512}`,
+      ''
+    ].join('\n'), { dependencies: { '@babel/code-frame': '^7.22.13' } });
+    expect(yarn.direct).toEqual(['@babel/code-frame@7.22.13']);
+    expect(edges(yarn)['@babel/code-frame@7.22.13']).toEqual(['js-tokens@4.0.0']);
+    expect(yarn.nodes.get('@babel/code-frame@7.22.13')).toMatchObject({
+      hashes: [{ alg: 'SHA-1', content: '0123456789abcdef0123456789abcdef01234567' }],
+      resolved: 'https://registry.yarnpkg.com/@babel/code-frame/-/code-frame-7.22.13.tgz'
+    });
+
+    const pnpm = readLockGraph('pnpm-lock.yaml', [
+      "lockfileVersion: '9.0'",
+      '',
+      'importers:',
+      '  .:',
+      '    dependencies:',
+      '      react-dom:',
+      '        specifier: ^18',
+      '        version: 18.2.0(react@18.2.0)',
+      '',
+      'packages:',
+      '',
+      '  react-dom@18.2.0:',
+      `    resolution: {integrity: ${SHA512}}`,
+      '',
+      '  react@18.2.0:',
+      '    resolution: {integrity: sha512-AQID}',
+      '',
+      'snapshots:',
+      '',
+      '  react-dom@18.2.0(react@18.2.0):',
+      '    dependencies:',
+      '      react: 18.2.0',
+      '',
+      '  react@18.2.0: {}',
+      ''
+    ].join('\n'));
+    expect(pnpm.direct).toEqual(['react-dom@18.2.0']);
+    expect(edges(pnpm)).toEqual({ 'react-dom@18.2.0': ['react@18.2.0'], 'react@18.2.0': [] });
+    expect(pnpm.nodes.get('react-dom@18.2.0').hashes[0].content).toBe(SHA512_HEX);
+  });
+
+  it('should read Cargo, uv, poetry, requirements, Gemfile, and go.mod graphs', () => {
+    const cargo = readLockGraph('Cargo.lock', [
+      '[[package]]', 'name = "app"', 'version = "0.1.0"', 'dependencies = [', ' "serde 1.0.190",', ']', '',
+      '[[package]]', 'name = "serde"', 'version = "1.0.190"', 'source = "registry+https://github.com/rust-lang/crates.io-index"',
+      `checksum = "${SHA256_HEX}"`, 'dependencies = [', ' "serde_derive",', ']', '',
+      '[[package]]', 'name = "serde_derive"', 'version = "1.0.190"', 'source = "registry+https://github.com/rust-lang/crates.io-index"', ''
+    ].join('\n'));
+    expect(cargo.direct).toEqual(['serde@1.0.190']);
+    expect(edges(cargo)).toEqual({ 'serde@1.0.190': ['serde_derive@1.0.190'], 'serde_derive@1.0.190': [] });
+    expect(cargo.nodes.get('serde@1.0.190').hashes).toEqual([{ alg: 'SHA-256', content: SHA256_HEX }]);
+
+    const uv = readLockGraph('uv.lock', [
+      '[[package]]', 'name = "anyio"', 'version = "4.0.0"', 'source = { registry = "https://pypi.org/simple" }',
+      'dependencies = [', '    { name = "idna" },', ']',
+      `sdist = { url = "https://files/anyio.tar.gz", hash = "sha256:${SHA256_HEX}", size = 1 }`, '',
+      '[[package]]', 'name = "idna"', 'version = "3.4"', 'source = { registry = "https://pypi.org/simple" }', '',
+      '[[package]]', 'name = "shop"', 'version = "0.1.0"', 'source = { editable = "." }', 'dependencies = [{ name = "AnyIO" }]', '',
+      '[package.dev-dependencies]', 'dev = [', '    { name = "idna" },', ']', ''
+    ].join('\n'));
+    expect(uv.direct).toEqual(['anyio@4.0.0', 'idna@3.4']);
+    expect(edges(uv)).toEqual({ 'anyio@4.0.0': ['idna@3.4'], 'idna@3.4': [] });
+    expect(uv.nodes.get('anyio@4.0.0').hashes).toEqual([{ alg: 'SHA-256', content: SHA256_HEX }]);
+
+    const poetry = readLockGraph('poetry.lock', [
+      '[[package]]', 'name = "requests"', 'version = "2.31.0"', '',
+      '[package.dependencies]', 'urllib3 = ">=1.21.1,<3"', '',
+      '[[package]]', 'name = "urllib3"', 'version = "2.0.7"', '',
+      '[metadata.files]', 'urllib3 = [', `    {file = "urllib3-2.0.7.tar.gz", hash = "sha256:${SHA256_HEX}"},`, ']', ''
+    ].join('\n'));
+    expect(edges(poetry)).toEqual({ 'requests@2.31.0': ['urllib3@2.0.7'], 'urllib3@2.0.7': [] });
+    expect(poetry.nodes.get('urllib3@2.0.7').hashes).toHaveLength(1);
+
+    const requirements = readLockGraph('requirements.txt', `idna==3.4 \\\n    --hash=sha256:${SHA256_HEX}\nflask>=2\n`);
+    expect([...requirements.nodes.keys()]).toEqual(['idna@3.4']);
+    expect(requirements.nodes.get('idna@3.4').hashes[0].content).toBe(SHA256_HEX);
+
+    const gems = readLockGraph('Gemfile.lock', [
+      'GEM', '  remote: https://rubygems.org/', '  specs:',
+      '    rack (3.0.8)', '    rack-test (2.1.0)', '      rack (>= 1.3)', '    nokogiri (1.15.4-x86_64-linux)', '',
+      'DEPENDENCIES', '  nokogiri', '  rack-test', '',
+      'CHECKSUMS', `  rack (3.0.8) sha256=${SHA256_HEX}`, ''
+    ].join('\n'));
+    expect(gems.direct).toEqual(['nokogiri@1.15.4', 'rack-test@2.1.0']);
+    expect(edges(gems)['rack-test@2.1.0']).toEqual(['rack@3.0.8']);
+    expect(gems.nodes.get('rack@3.0.8').hashes[0].alg).toBe('SHA-256');
+
+    const go = readLockGraph('go.mod', 'module shop\n\nrequire (\n\tgithub.com/gorilla/mux v1.8.0\n\tgolang.org/x/net v0.17.0 // indirect\n)\n');
+    expect(go.direct).toEqual(['github.com/gorilla/mux@v1.8.0']);
+    expect(go.nodes.size).toBe(2);
+  });
+
+  describe('documents', () => {
+    beforeEach(() => {
+      write('package.json', { name: 'shop', version: '2.0.0', dependencies: { express: '^4' }, devDependencies: { jest: '^29' } });
+      write('package-lock.json', {
+        lockfileVersion: 3,
+        packages: {
+          '': { name: 'shop', version: '2.0.0', dependencies: { express: '^4' }, devDependencies: { jest: '^29' } },
+          'node_modules/express': {
+            version: '4.18.2',
+            resolved: 'https://registry.npmjs.org/express/-/express-4.18.2.tgz',
+            integrity: SHA512,
+            license: 'MIT',
+            dependencies: { debug: '2.6.9' }
+          },
+          'node_modules/debug': { version: '2.6.9', license: 'UNLICENSED' },
+          'node_modules/jest': { version: '29.7.0', dev: true, license: 'MIT', dependencies: { debug: '^2' } }
+        }
+      });
+    });
+
+    const options = { offline: true, run: () => null, timestamp: '2026-01-01T00:00:00Z', uuid: '00000000-0000-4000-8000-000000000000' };
+
+    it('should write a CycloneDX document with hashes, licenses, and the dependency graph', async () => {
+      const result = await generateSbom(dir, options);
+      expect(result).toMatchObject({ success: true, format: 'cyclonedx', components: 2, hashed: 1, licensed: 2 });
+
+      const bom = result.document;
+      expect(bom).toMatchObject({
+        bomFormat: 'CycloneDX',
+        specVersion: '1.5',
+        serialNumber: 'urn:uuid:00000000-0000-4000-8000-000000000000',
+        metadata: { timestamp: '2026-01-01T00:00:00Z', component: { name: 'shop', version: '2.0.0', 'bom-ref': 'shop@2.0.0' } }
+      });
+      expect(bom.components.map(component => component.purl)).toEqual(['pkg:npm/debug@2.6.9', 'pkg:npm/express@4.18.2']);
+      expect(bom.components[1]).toMatchObject({
+        scope: 'required',
+        hashes: [{ alg: 'SHA-512', content: SHA512_HEX }],
+        licenses: [{ license: { id: 'MIT' } }],
+        externalReferences: [{ type: 'distribution', url: 'https://registry.npmjs.org/express/-/express-4.18.2.tgz' }]
+      });
+      expect(bom.components[0].licenses).toEqual([{ license: { name: 'UNLICENSED' } }]);
+      expect(bom.dependencies).toEqual([
+        { ref: 'shop@2.0.0', dependsOn: ['pkg:npm/express@4.18.2'] },
+        { ref: 'pkg:npm/debug@2.6.9', dependsOn: [] },
+        { ref: 'pkg:npm/express@4.18.2', dependsOn: ['pkg:npm/debug@2.6.9'] }
+      ]);
+
+      const withDev = (await generateSbom(dir, { ...options, dev: true })).document;
+      expect(withDev.components.find(component => component.name === 'jest').scope).toBe('excluded');
+      expect(withDev.dependencies[0].dependsOn).toEqual(['pkg:npm/express@4.18.2', 'pkg:npm/jest@29.7.0']);
+    });
+
+    it('should write an SPDX document with checksums, purls, and relationships', async () => {
+      const result = await generateSbom(dir, { ...options, format: 'spdx', dev: true });
+      const doc = result.document;
+      expect(doc).toMatchObject({
+        spdxVersion: 'SPDX-2.3',
+        dataLicense: 'CC0-1.0',
+        SPDXID: 'SPDXRef-DOCUMENT',
+        documentNamespace: 'https://spdx.org/spdxdocs/shop-00000000-0000-4000-8000-000000000000',
+        creationInfo: { created: '2026-01-01T00:00:00Z' }
+      });
+      const express = doc.packages.find(pkg => pkg.name === 'express');
+      expect(express).toMatchObject({
+        SPDXID: 'SPDXRef-Package-npm-express-4.18.2',
+        versionInfo: '4.18.2',
+        downloadLocation: 'https://registry.npmjs.org/express/-/express-4.18.2.tgz',
+        checksums: [{ algorithm: 'SHA512', checksumValue: SHA512_HEX }],
+        licenseDeclared: 'MIT',
+        externalRefs: [{ referenceCategory: 'PACKAGE-MANAGER', referenceType: 'purl', referenceLocator: 'pkg:npm/express@4.18.2' }]
+      });
+      expect(doc.packages.find(pkg => pkg.name === 'debug')).toMatchObject({ licenseDeclared: 'NOASSERTION', licenseComments: 'Declared as "UNLICENSED"' });
+      expect(doc.relationships).toEqual([
+        { spdxElementId: 'SPDXRef-DOCUMENT', relationshipType: 'DESCRIBES', relatedSpdxElement: 'SPDXRef-Package-shop' },
+        { spdxElementId: 'SPDXRef-Package-shop', relationshipType: 'DEPENDS_ON', relatedSpdxElement: 'SPDXRef-Package-npm-express-4.18.2' },
+        { spdxElementId: 'SPDXRef-Package-npm-jest-29.7.0', relationshipType: 'DEV_DEPENDENCY_OF', relatedSpdxElement: 'SPDXRef-Package-shop' },
+        { spdxElementId: 'SPDXRef-Package-npm-express-4.18.2', relationshipType: 'DEPENDS_ON', relatedSpdxElement: 'SPDXRef-Package-npm-debug-2.6.9' },
+        { spdxElementId: 'SPDXRef-Package-npm-jest-29.7.0', relationshipType: 'DEPENDS_ON', relatedSpdxElement: 'SPDXRef-Package-npm-debug-2.6.9' }
+      ]);
+    });
+
+    it('should fail without a lockfile or with an unknown format', async () => {
+      expect(await generateSbom(dir, { ...options, format: 'swid' })).toEqual({ success: false, error: 'Unknown SBOM format "swid". Use cyclonedx or spdx.' });
+      fs.rmSync(path.join(dir, 'package-lock.json'));
+      const result = await generateSbom(dir, options);
+      expect(result.success).toBe(false);
+      expect(result.error).toMatch(/^No lockfile found/);
+    });
+  });
+});
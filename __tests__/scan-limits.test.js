@@ -0,0 +1,106 @@
+/**
+ * Tests for lib/utils/scan-limits (binary, minified, generated, and oversized files)
+ */
+
+const fs = require('fs');
+const os = require('os');
+const path = require('path');
+
+const scanLimits = require('../lib/utils/scan-limits');
+const { countSourceFiles } = require('../lib/patterns/slop-analyzers');
+const { runPipeline } = require('../lib/patterns/pipeline');
+const runner = require('../lib/repo-map/runner');
+
+describe('scan limits', () => {
+  let root;
+  const write = (file, content = '') => {
+    fs.mkdirSync(path.dirname(path.join(root, file)), { recursive: true });
+    fs.writeFileSync(path.join(root, file), content);
+  };
+
+  beforeEach(() => {
+    root = fs.realpathSync(fs.mkdtempSync(path.join(os.tmpdir(), 'scan-limits-')));
+    write('src/app.js', 'export function app() {\n  return 1;\n}\n');
+    write('src/big.js', `${'const fixture = 1;\n'.repeat(4000)}`);
+    write('src/bundle.js', `${'var a=1;'.repeat(200)}\n${'var b=2;'.repeat(200)}\n`);
+    write('src/vendor.min.js', 'var a=1;\n');
+    write('src/image.js', Buffer.from([0x47, 0x49, 0x46, 0x00, 0x01]));
+    write('src/schema.ts', '// @generated by codegen\nexport type A = string;\n');
+    write('api/user.pb.go', 'package api\n');
+    write('src/client/index.ts', 'export const client = 1;\n');
+    write('src/zed.js', 'export const zed = 1;\n');
+    write('.gitattributes', '# Checked-in client\nsrc/client/** linguist-generated\n*.pb.go -linguist-generated\n');
+  });
+
+  afterEach(() => {
+    fs.rmSync(root, { recursive: true, force: true });
+  });
+
+  it('should classify files by size, content, path, and .gitattributes', () => {
+    const skip = scanLimits.createFileFilter(root, { settings: { ...scanLimits.DEFAULTS, maxFileSizeKb: 64 } });
+    const reasons = {};
+    for (const file of ['src/app.js', 'src/big.js', 'src/bundle.js', 'src/vendor.min.js', 'src/image.js', 'src/schema.ts', 'api/user.pb.go', 'src/client/index.ts']) {
+      reasons[file] = skip(file);
+    }
+
+    expect(reasons).toEqual({
+      'src/app.js': null,
+      'src/big.js': 'too-large',
+      'src/bundle.js': 'minified',
+      'src/vendor.min.js': 'minified',
+      'src/image.js': 'binary',
+      'src/schema.ts': 'generated',
+      // -linguist-generated overrides the generated path
+      'api/user.pb.go': null,
+      'src/client/index.ts': 'generated'
+    });
+    // Absolute paths are checked against the same root and recorded once
+    expect(skip(path.join(root, 'src/big.js'))).toBe('too-large');
+
+    const summary = skip.summary();
+    expect(summary).toMatchObject({ total: 6, byReason: { 'too-large': 1, minified: 2, binary: 1, generated: 2 }, maxFileSizeKb: 64 });
+    expect(summary.files[0].file).toBe('src/big.js');
+    expect(scanLimits.renderSkipped(summary)).toBe('6 files: 1 larger than 64 KB, 1 binary, 2 minified, 2 generated');
+    expect(scanLimits.renderSkipped(scanLimits.summarize([]))).toBe('');
+  });
+
+  it('should scan generated and minified files when the config opts in', () => {
+    expect(scanLimits.readSettings(root)).toEqual({ maxFileSizeKb: 1024, generated: false, minified: false, error: null });
+
+    write('.awesome-slash.json', JSON.stringify({ scan: { maxFileSizeKb: 0, generated: true, minified: true } }));
+    const settings = scanLimits.readSettings(root);
+    expect(settings).toEqual({ maxFileSizeKb: 0, generated: true, minified: true, error: null });
+    const skip = scanLimits.createFileFilter(root, { settings });
+    expect(['src/big.js', 'src/bundle.js', 'src/schema.ts', 'src/client/index.ts', 'src/image.js'].map(skip)).toEqual([null, null, null, null, 'binary']);
+
+    write('.awesome-slash.json', JSON.stringify({ scan: { maxFileSizeKb: '1MB' } }));
+    expect(scanLimits.readSettings(root)).toMatchObject({
+      maxFileSizeKb: 1024,
+      error: '.awesome-slash.json: scan.maxFileSizeKb must be a number of kilobytes (0 for no limit)'
+    });
+  });
+
+  it('should leave skipped files out of slop scans and repo-map walks', () => {
+    const skip = scanLimits.createFileFilter(root, { settings: { ...scanLimits.DEFAULTS, maxFileSizeKb: 64 } });
+    const { files } = countSourceFiles(root, { maxFiles: 3, skip });
+    // Skipped files do not count toward maxFiles
+    expect(files.sort()).toEqual(['api/user.pb.go', 'src/app.js', 'src/zed.js'].map(file => path.normalize(file)).sort());
+
+    write('.awesome-slash.json', JSON.stringify({ scan: { maxFileSizeKb: 64 } }));
+    const result = runPipeline(root, {
+      thoroughness: 'quick',
+      targetFiles: ['src/app.js', 'src/bundle.js', 'src/big.js'],
+      baseline: false,
+      linters: [],
+      runtimes: [],
+      repoMap: null
+    });
+    expect(result.metadata.filesAnalyzed).toBe(1);
+    expect(result.skipped).toMatchObject({ total: 2, byReason: { 'too-large': 1, minified: 1 }, error: null });
+    expect(runPipeline(root, { thoroughness: 'quick', targetFiles: ['src/bundle.js'], scanLimits: false, baseline: false, linters: [] }).skipped).toBeNull();
+
+    const walked = runner.findFilesForLanguage(root, 'typescript', { skip: scanLimits.createFileFilter(root) })
+      .map(file => path.relative(root, file).replace(/\\/g, '/'));
+    expect(walked).toEqual([]);
+  });
+});
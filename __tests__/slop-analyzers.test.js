@@ -6,6 +6,7 @@
 const {
   analyzeDocCodeRatio,
   analyzeVerbosityRatio,
+  analyzeRestatingComments,
   analyzeOverEngineering,
   analyzeInfrastructureWithoutImplementation,
   analyzeDeadCode,
@@ -18,6 +19,7 @@ const {
   detectCommentLanguage,
   shouldExclude,
   isTestFile,
+  findRustTestRanges,
   parseGitignore,
   ENTRY_POINTS,
   EXPORT_PATTERNS,
@@ -806,6 +808,28 @@ function func${i}() {
     });
   });
 
+  describe('findRustTestRanges', () => {
+    it('should find cfg(test) modules and bodiless items', () => {
+      const content = [
+        'fn run() { x.unwrap(); }',
+        '#[cfg(test)]',
+        'mod tests {',
+        "    fn t() { let c = '}'; let s = \"}\"; // }",
+        '    }',
+        '}',
+        '#[cfg(test)] use helpers::fixture;',
+        '#[cfg(not(test))]',
+        'fn prod() {}'
+      ].join('\n');
+
+      expect(findRustTestRanges(content)).toEqual([[2, 6], [7, 7]]);
+    });
+
+    it('should return no ranges without test items', () => {
+      expect(findRustTestRanges('fn main() {}\n')).toEqual([]);
+    });
+  });
+
   describe('countExportsInContent', () => {
     describe('JavaScript/TypeScript', () => {
       it('should count export function', () => {
@@ -1445,6 +1469,47 @@ function acceptable() {
     });
   });
 
+  describe('analyzeRestatingComments', () => {
+    it('should flag comments whose words all appear in the next line', () => {
+      const code = [
+        'function load(id) {',
+        '  // get user by id',
+        '  const user = getUserById(id);',
+        '  // Return the result',
+        '  return result;',
+        '}'
+      ].join('\n');
+
+      expect(analyzeRestatingComments(code, { filePath: 'a.js' })).toEqual([
+        { line: 2, comment: 'get user by id', code: 'const user = getUserById(id);' },
+        { line: 4, comment: 'Return the result', code: 'return result;' }
+      ]);
+    });
+
+    it('should keep comments that explain why', () => {
+      const code = [
+        '// Retry once: the first request warms the cache',
+        'const user = getUserById(id);',
+        '// increment counter',
+        'count++;',
+        '// TODO remove user cache',
+        'removeUserCache();',
+        '// The API returns users sorted by id,',
+        '// so return users as-is',
+        'return users;'
+      ].join('\n');
+
+      expect(analyzeRestatingComments(code, { filePath: 'a.js' })).toEqual([]);
+    });
+
+    it('should use # comments for Python and require minWords meaningful words', () => {
+      const code = '# loop over items\nfor item in items:\n# the result\nreturn result\n';
+      expect(analyzeRestatingComments(code, { filePath: 'job.py' }).map(v => v.line)).toEqual([]);
+      expect(analyzeRestatingComments('# save items\nsave(items)\n', { filePath: 'job.py' }).map(v => v.line)).toEqual([1]);
+      expect(analyzeRestatingComments('# save items\nsave(items)\n', { filePath: 'job.py', minWords: 3 })).toEqual([]);
+    });
+  });
+
   // ============================================================================
   // Buzzword Inflation Detection Tests
   // ============================================================================
@@ -3246,7 +3311,8 @@ src/j.js`;
         });
 
         expect(mockExec).toHaveBeenCalledWith(
-          expect.stringContaining('-n 50'),
+          '/repo',
+          expect.arrayContaining(['-n', '50']),
           expect.any(Object)
         );
       });
@@ -3260,7 +3326,8 @@ src/j.js`;
         });
 
         expect(mockExec).toHaveBeenCalledWith(
-          expect.stringContaining('-n 100'), // Default commitLimit
+          '/repo',
+          expect.arrayContaining(['-n', '100']), // Default commitLimit
           expect.any(Object)
         );
       });
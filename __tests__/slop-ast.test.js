@@ -0,0 +1,142 @@
+/**
+ * Tests for AST-backed slop detection
+ */
+
+const fs = require('fs');
+const os = require('os');
+const path = require('path');
+
+const slopAst = require('../lib/patterns/slop-ast');
+const { slopPatterns } = require('../lib/patterns/slop-patterns');
+const { runPhase1 } = require('../lib/patterns/pipeline');
+
+/**
+ * Minimal Babel-style node with a start line
+ */
+function node(type, line, props = {}) {
+  return { type, loc: { start: { line, column: 0 } }, ...props };
+}
+
+function consoleCall(line, property, computed = false) {
+  return node('ExpressionStatement', line, {
+    expression: node('CallExpression', line, {
+      callee: node('MemberExpression', line, {
+        object: node('Identifier', line, { name: 'console' }),
+        property: computed ? node('StringLiteral', line, { value: property }) : node('Identifier', line, { name: property }),
+        computed
+      }),
+      arguments: []
+    })
+  });
+}
+
+/**
+ * Module loader providing a fake `@babel/parser` that returns `program`
+ */
+function createLoader(program, calls = []) {
+  return name => {
+    if (name !== '@babel/parser') throw new Error(`Cannot find module '${name}'`);
+    return {
+      parse(content, options) {
+        calls.push(options);
+        return { type: 'File', program };
+      }
+    };
+  };
+}
+
+describe('slop-ast', () => {
+  it('should report unavailable when the parser cannot be resolved', () => {
+    const loader = () => { throw new Error('missing'); };
+    expect(slopAst.isAvailable({ require: loader })).toBe(false);
+    expect(slopAst.findAstMatches('console.log(1)', 'a.js', slopPatterns, { require: loader })).toBeNull();
+  });
+
+  it('should match computed console calls and ignore other members', () => {
+    const program = node('Program', 1, {
+      body: [
+        consoleCall(1, 'log'),
+        consoleCall(2, 'log', true),
+        consoleCall(3, 'error'),
+        node('ExpressionStatement', 4, {
+          expression: node('StringLiteral', 4, { value: 'console.log("in a string")' })
+        })
+      ]
+    });
+
+    const matches = slopAst.findAstMatches('', 'src/app.js', { console_debugging: slopPatterns.console_debugging }, {
+      require: createLoader(program)
+    });
+    expect(matches.get('console_debugging')).toEqual([1, 2]);
+  });
+
+  it('should match empty catch blocks, empty functions, and placeholder throws', () => {
+    const emptyBlock = line => node('BlockStatement', line, { body: [] });
+    const program = node('Program', 1, {
+      body: [
+        node('TryStatement', 1, {
+          block: node('BlockStatement', 1, { body: [consoleCall(2, 'warn')] }),
+          handler: node('CatchClause', 3, { body: emptyBlock(3) })
+        }),
+        node('TryStatement', 5, {
+          block: emptyBlock(5),
+          handler: node('CatchClause', 6, { body: { ...emptyBlock(6), innerComments: [{ value: ' ignore: optional' }] } })
+        }),
+        node('FunctionDeclaration', 8, { id: node('Identifier', 8, { name: 'noop' }), body: emptyBlock(8) }),
+        node('ThrowStatement', 9, {
+          argument: node('NewExpression', 9, {
+            callee: node('Identifier', 9, { name: 'Error' }),
+            arguments: [node('TemplateLiteral', 9, { quasis: [{ value: { cooked: 'TODO: implement' } }], expressions: [] })]
+          })
+        })
+      ]
+    });
+
+    const patterns = {
+      empty_catch_js: slopPatterns.empty_catch_js,
+      placeholder_empty_function_js: slopPatterns.placeholder_empty_function_js,
+      placeholder_not_implemented_js: slopPatterns.placeholder_not_implemented_js
+    };
+    const matches = slopAst.findAstMatches('', 'src/app.ts', patterns, { require: createLoader(program) });
+    expect(Object.fromEntries(matches)).toEqual({
+      empty_catch_js: [3],
+      placeholder_empty_function_js: [8],
+      placeholder_not_implemented_js: [9]
+    });
+  });
+
+  it('should select TypeScript syntax plugins by extension', () => {
+    const calls = [];
+    const loader = createLoader(node('Program', 1, { body: [] }), calls);
+    slopAst.findAstMatches('', 'view.tsx', slopPatterns, { require: loader });
+    slopAst.findAstMatches('', 'app.mjs', slopPatterns, { require: loader });
+
+    expect(calls[0].plugins).toEqual(expect.arrayContaining(['typescript', 'jsx']));
+    expect(calls[1].plugins).not.toContain('typescript');
+  });
+
+  describe('runPhase1 integration', () => {
+    let tmpDir;
+
+    beforeEach(() => {
+      tmpDir = fs.mkdtempSync(path.join(os.tmpdir(), 'slop-ast-'));
+    });
+
+    afterEach(() => {
+      fs.rmSync(tmpDir, { recursive: true, force: true });
+    });
+
+    it('should use AST matches in place of the regex and fall back without a parser', () => {
+      fs.writeFileSync(path.join(tmpDir, 'app.js'), 'const msg = "console.log(";\nconsole["log"](msg);\n');
+      const program = node('Program', 1, { body: [consoleCall(2, 'log', true)] });
+
+      const astFindings = runPhase1(tmpDir, ['app.js'], null, { require: createLoader(program) })
+        .filter(f => f.patternName === 'console_debugging');
+      expect(astFindings).toEqual([expect.objectContaining({ line: 2, content: 'console["log"](msg);', details: { engine: 'ast' } })]);
+
+      const regexFindings = runPhase1(tmpDir, ['app.js'], null, false)
+        .filter(f => f.patternName === 'console_debugging');
+      expect(regexFindings.map(f => f.line)).toEqual([1]);
+    });
+  });
+});
@@ -0,0 +1,162 @@
+/**
+ * Tests for lib/task-runner (parallel task execution)
+ */
+
+const fs = require('fs');
+const os = require('os');
+const path = require('path');
+
+const taskRunner = require('../lib/task-runner');
+const { auditWorkspaces, renderWorkspaceReport } = require('../lib/deps');
+
+describe('task-runner', () => {
+  const delay = ms => new Promise(resolve => setTimeout(resolve, ms));
+
+  it('should respect the concurrency limit and dependency order', async () => {
+    let running = 0;
+    let peak = 0;
+    const started = [];
+    const task = (id, deps = []) => ({
+      id,
+      deps,
+      run: async ({ results }) => {
+        started.push(id);
+        running++;
+        peak = Math.max(peak, running);
+        await delay(10);
+        running--;
+        return [id, ...deps.map(dep => results[dep])].join('+');
+      }
+    });
+    const events = [];
+    const result = await taskRunner.runTasks([task('c', ['a', 'b']), task('a'), task('b'), task('d')], {
+      concurrency: 2,
+      onProgress: event => events.push(`${event.type}:${event.task.id}`)
+    });
+
+    expect(result.success).toBe(true);
+    expect(result.concurrency).toBe(2);
+    expect(peak).toBe(2);
+    expect(started.indexOf('c')).toBeGreaterThan(Math.max(started.indexOf('a'), started.indexOf('b')));
+    expect(result.results.c).toBe('c+a+b');
+    expect(result.tasks.map(entry => entry.id)).toEqual(['c', 'a', 'b', 'd']);
+    expect(events.filter(event => event.startsWith('done'))).toHaveLength(4);
+  });
+
+  it('should skip dependents of a failed task and keep running the rest', async () => {
+    const result = await taskRunner.runTasks([
+      { id: 'build', run: () => { throw new Error('tsc exited 2'); } },
+      { id: 'test', deps: ['build'], run: () => 'never' },
+      { id: 'e2e', deps: ['test'], run: () => 'never' },
+      { id: 'lint', run: () => 'clean' }
+    ], { concurrency: 1 });
+
+    expect(result.success).toBe(false);
+    expect(result.tasks.map(({ id, status, error }) => [id, status, error])).toEqual([
+      ['build', 'failed', 'tsc exited 2'],
+      ['test', 'skipped', 'build failed'],
+      ['e2e', 'skipped', 'test skipped'],
+      ['lint', 'done', undefined]
+    ]);
+
+    const stopped = await taskRunner.runTasks([
+      { id: 'a', run: () => Promise.reject(new Error('boom')) },
+      { id: 'b', run: () => 'ok' }
+    ], { concurrency: 1, failFast: true });
+    expect(stopped.tasks[1]).toMatchObject({ status: 'skipped', error: 'stopped after a failure' });
+  });
+
+  it('should reject cycles, unknown dependencies, and bad arguments', async () => {
+    const run = () => null;
+    expect(taskRunner.orderTasks([{ id: 'a', deps: ['b'], run }, { id: 'b', deps: ['a'], run }]).error).toBe('Dependency cycle: a -> b -> a');
+    expect(taskRunner.orderTasks([{ id: 'a', deps: ['x'], run }]).error).toBe('Task a depends on unknown task x');
+    expect(taskRunner.orderTasks([{ id: 'a', run }, { id: 'a', run }]).error).toBe('Duplicate task id: a');
+    expect((await taskRunner.runTasks([{ id: 'a', deps: ['a'], run }])).error).toBe('Dependency cycle: a -> a');
+
+    expect(taskRunner.parseConcurrency(['--concurrency', '3'])).toEqual({ concurrency: 3, error: null });
+    expect(taskRunner.parseConcurrency(['--fix'])).toEqual({ concurrency: undefined, error: null });
+    expect(taskRunner.parseConcurrency(['--concurrency', '0']).error).toBe('--concurrency needs a whole number of 1 or more');
+    expect(taskRunner.resolveConcurrency()).toBeLessThanOrEqual(taskRunner.MAX_DEFAULT_CONCURRENCY);
+  });
+
+  it('should print progress lines and a run summary', async () => {
+    const lines = [];
+    const progress = taskRunner.createProgress({ write: line => lines.push(line) });
+    progress({ type: 'start', task: { label: 'api' }, completed: 0, total: 12 });
+    progress({ type: 'done', task: { label: 'api' }, completed: 3, total: 12, durationMs: 1234 });
+    progress({ type: 'skipped', task: { label: 'web' }, completed: 4, total: 12, error: 'api failed' });
+    expect(lines).toEqual(['[ 3/12] done api (1.2s)\n', '[ 4/12] skipped web: api failed\n']);
+
+    expect(taskRunner.renderSummary({
+      concurrency: 8,
+      durationMs: 8100,
+      tasks: [{ status: 'done' }, { status: 'done' }, { status: 'skipped' }]
+    })).toBe('3 tasks in 8.1s (concurrency 8): 2 done, 1 skipped');
+  });
+
+  describe('workspaces', () => {
+    let root;
+    const write = (file, content) => {
+      fs.mkdirSync(path.dirname(path.join(root, file)), { recursive: true });
+      fs.writeFileSync(path.join(root, file), typeof content === 'string' ? content : JSON.stringify(content));
+    };
+
+    beforeEach(() => {
+      root = fs.mkdtempSync(path.join(os.tmpdir(), 'task-runner-'));
+      write('package.json', { name: 'shop', private: true, workspaces: ['packages/*'] });
+      write('package-lock.json', {
+        lockfileVersion: 3,
+        packages: {
+          '': { name: 'shop' },
+          'node_modules/lodash': { version: '4.17.20' },
+          'node_modules/chalk': { version: '5.3.0' }
+        }
+      });
+      write('packages/api/package.json', { name: '@shop/api', dependencies: { lodash: '^4.17.0', '@shop/core': '*' } });
+      write('packages/web/package.json', { name: '@shop/web', dependencies: { chalk: '^5.0.0', lodash: '^4.17.0' } });
+      write('packages/core/package.json', { name: '@shop/core' });
+    });
+
+    afterEach(() => {
+      fs.rmSync(root, { recursive: true, force: true });
+    });
+
+    const packages = [
+      { name: '@shop/api', path: 'packages/api' },
+      { name: '@shop/web', path: 'packages/web' },
+      { name: '@shop/core', path: 'packages/core' }
+    ];
+
+    it('should read sibling package dependencies from package.json', () => {
+      expect(taskRunner.workspaceDependencies(root, packages)).toEqual({
+        'packages/api': ['packages/core'],
+        'packages/web': [],
+        'packages/core': []
+      });
+    });
+
+    it('should audit each package with one OSV query for all of them', async () => {
+      const queries = [];
+      const request = async (method, pathname, body) => {
+        if (pathname === '/v1/querybatch') {
+          queries.push(body.queries.map(query => query.package.name).sort());
+          return { results: body.queries.map(query => (query.package.name === 'lodash' ? { vulns: [{ id: 'GHSA-1' }] } : {})) };
+        }
+        return { id: 'GHSA-1', summary: 'Prototype pollution', affected: [] };
+      };
+      const result = await auditWorkspaces(root, packages, { request, outdated: false, concurrency: 2 });
+
+      expect(result.success).toBe(true);
+      expect(queries).toEqual([['chalk', 'lodash']]);
+      expect(result.packages.map(pkg => [pkg.path, pkg.report.dependencies, pkg.report.vulnerabilities.length])).toEqual([
+        ['.', 0, 0],
+        ['packages/api', 2, 1],
+        ['packages/web', 2, 1],
+        ['packages/core', 0, 0]
+      ]);
+      expect(result.vulnerabilities).toBe(1);
+      expect(result.run.summary).toMatch(/^5 tasks in .* \(concurrency 2\): 5 done$/);
+      expect(renderWorkspaceReport(result)).toContain('## Dependency Audit (4 packages)');
+    });
+  });
+});
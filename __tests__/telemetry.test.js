@@ -0,0 +1,125 @@
+/**
+ * Tests for lib/telemetry (opt-in usage metrics)
+ */
+
+const fs = require('fs');
+const os = require('os');
+const path = require('path');
+
+const telemetry = require('../lib/telemetry');
+
+describe('telemetry', () => {
+  let root;
+  let savedStateDir;
+
+  const writeConfig = config => fs.writeFileSync(path.join(root, '.awesome-slash.json'), JSON.stringify(config));
+
+  beforeEach(() => {
+    root = fs.mkdtempSync(path.join(os.tmpdir(), 'telemetry-'));
+    savedStateDir = process.env.AI_STATE_DIR;
+    process.env.AI_STATE_DIR = '.state';
+    fs.writeFileSync(path.join(root, 'a.js'), 'x'.repeat(1234));
+  });
+
+  afterEach(() => {
+    if (savedStateDir === undefined) delete process.env.AI_STATE_DIR;
+    else process.env.AI_STATE_DIR = savedStateDir;
+    fs.rmSync(root, { recursive: true, force: true });
+  });
+
+  it('should record nothing unless enabled, and honor the opt-out variables', () => {
+    expect(telemetry.record(root, { command: 'deslop', durationMs: 10 }, { env: {} })).toEqual({ recorded: false });
+    writeConfig({ telemetry: { enabled: true } });
+    expect(telemetry.record(root, { command: 'deslop', durationMs: 10 }, { env: { DO_NOT_TRACK: '1' } })).toEqual({ recorded: false });
+    expect(telemetry.record(root, { command: 'deslop', durationMs: 10 }, { env: { AWESOME_SLASH_TELEMETRY: 'off' } })).toEqual({ recorded: false });
+    expect(fs.existsSync(telemetry.storePath(root))).toBe(false);
+
+    writeConfig({ telemetry: { enabled: 'yes' } });
+    expect(telemetry.readSettings(root, {}).error).toBe('.awesome-slash.json: telemetry.enabled must be true or false');
+    writeConfig({ telemetry: { enabled: true, endpoint: 'http://metrics.example.com' } });
+    expect(telemetry.readSettings(root, {}).error).toBe('.awesome-slash.json: telemetry.endpoint must be an https URL');
+  });
+
+  it('should record anonymous events with rounded sizes and error categories', () => {
+    writeConfig({ telemetry: { enabled: true, maxEvents: 2 } });
+    const first = telemetry.record(root, { command: 'deslop', durationMs: 12.6 }, { env: {} });
+    expect(first.event).toEqual({
+      time: expect.any(String),
+      command: 'deslop',
+      durationMs: 13,
+      success: true,
+      error: null,
+      repo: { files: 1, bytes: 1200 },
+      os: process.platform,
+      node: Number(process.versions.node.split('.')[0]),
+      version: require('../package.json').version
+    });
+    expect(JSON.stringify(first.event)).not.toContain(root);
+
+    const error = Object.assign(new Error('connect ECONNREFUSED 10.0.0.1:443'), { code: 'ECONNREFUSED' });
+    telemetry.record(root, { command: 'repo-map', durationMs: 50, error }, { env: {} });
+    telemetry.record(root, { command: 'repo-map', durationMs: 70, success: false, error: 'Not a git repository' }, { env: {} });
+    const { events } = telemetry.readStore(root);
+    expect(events.map(event => event.error)).toEqual(['network', 'git']);
+    expect(JSON.stringify(events)).not.toContain('10.0.0.1');
+
+    expect(telemetry.roundSize(123456)).toBe(120000);
+    expect(telemetry.roundSize(7)).toBe(7);
+    expect(telemetry.categorizeError(new Error('something odd'))).toBe('other');
+    expect(telemetry.categorizeError({ code: 'ENOENT', message: 'x' })).toBe('not-found');
+  });
+
+  it('should time runs and summarize them per command', async () => {
+    writeConfig({ telemetry: { enabled: true } });
+    const saved = process.env.DO_NOT_TRACK;
+    delete process.env.DO_NOT_TRACK;
+    try {
+      expect(await telemetry.track(root, 'repo-map:update', async () => ({ success: true }))).toEqual({ success: true });
+      await expect(telemetry.track(root, 'repo-map:update', async () => {
+        throw Object.assign(new Error('spawn timed out'), { code: 'ETIMEDOUT' });
+      })).rejects.toThrow('spawn timed out');
+      const run = telemetry.startRun(root, 'deslop');
+      expect(run.end({ exitCode: 3 }).recorded).toBe(true);
+      expect(run.end({ exitCode: 1 }).event.success).toBe(true);
+    } finally {
+      if (saved !== undefined) process.env.DO_NOT_TRACK = saved;
+    }
+
+    const summary = telemetry.summarize(telemetry.readStore(root).events);
+    expect(summary.map(entry => entry.command).sort()).toEqual(['deslop', 'repo-map:update']);
+    expect(summary.find(entry => entry.command === 'repo-map:update')).toMatchObject({ runs: 2, failures: 1, errors: { timeout: 1 } });
+    expect(telemetry.status(root)).toMatchObject({ enabled: true, endpoint: false, events: 3, pending: 0 });
+  });
+
+  it('should send unsent events in batches and keep them when the endpoint fails', async () => {
+    writeConfig({ telemetry: { enabled: true, endpointEnv: 'METRICS_URL', batchSize: 2 } });
+    const env = { METRICS_URL: 'https://metrics.example.com/ingest' };
+    for (let i = 0; i < 3; i++) telemetry.record(root, { command: 'deslop', durationMs: i }, { env });
+
+    const posts = [];
+    let failNext = true;
+    const request = async (url, payload) => {
+      if (failNext) {
+        failNext = false;
+        throw new Error('endpoint returned 503');
+      }
+      posts.push([url, payload.events.map(event => event.durationMs)]);
+    };
+    expect(await telemetry.flush(root, { env, request })).toEqual({ success: false, sent: 0, pending: 3, error: 'endpoint returned 503' });
+    expect(await telemetry.flush(root, { env, request })).toEqual({ success: true, sent: 3, pending: 0 });
+    expect(posts).toEqual([['https://metrics.example.com/ingest', [0, 1]], ['https://metrics.example.com/ingest', [2]]]);
+    expect(await telemetry.flush(root, { env, request })).toEqual({ success: true, sent: 0, pending: 0 });
+
+    expect((await telemetry.flush(root, { env: {}, request })).error).toMatch(/No telemetry.endpoint/);
+
+    // Opting out after recording keeps the queued events local
+    telemetry.record(root, { command: 'deslop', durationMs: 3 }, { env });
+    posts.length = 0;
+    expect(await telemetry.flush(root, { env: { ...env, DO_NOT_TRACK: '1' }, request })).toMatchObject({ success: false, sent: 0, pending: 1 });
+    expect(await telemetry.flush(root, { env: { ...env, AWESOME_SLASH_TELEMETRY: '0' }, request })).toMatchObject({ success: false, sent: 0, pending: 1 });
+    writeConfig({ telemetry: { enabled: false, endpointEnv: 'METRICS_URL' } });
+    expect(await telemetry.flush(root, { env, request })).toMatchObject({ success: false, sent: 0, pending: 1, error: expect.stringContaining('not enabled') });
+    expect(posts).toEqual([]);
+    expect(telemetry.clear(root)).toEqual({ removed: 4 });
+  });
+});
@@ -0,0 +1,181 @@
+/**
+ * Tests for TODO triage with git blame ages
+ */
+
+const { execFileSync } = require('child_process');
+const fs = require('fs');
+const os = require('os');
+const path = require('path');
+
+const {
+  findMarkers,
+  parseBlame,
+  enrichTodos,
+  groupByOwner,
+  groupByStaleness,
+  readSettings,
+  issueTitle,
+  issueCommand,
+  triageTodos,
+  renderReport
+} = require('../lib/todos');
+
+const DAY = 24 * 60 * 60;
+const NOW = Date.UTC(2026, 5, 1);
+
+describe('todos', () => {
+  describe('findMarkers', () => {
+    it('should find markers that open a comment', () => {
+      const content = [
+        'function a() {}  // TODO: handle retries',
+        '# FIXME(ana): wrong timezone',
+        ' * HACK - works around #12 */',
+        '-- XXX drop this column',
+        'const pattern = /(TODO|FIXME):/;',
+        'const TODOS = [];',
+        "log('TODO list')"
+      ].join('\n');
+      expect(findMarkers(content)).toEqual([
+        { line: 1, marker: 'TODO', tag: null, text: 'handle retries' },
+        { line: 2, marker: 'FIXME', tag: 'ana', text: 'wrong timezone' },
+        { line: 3, marker: 'HACK', tag: null, text: 'works around #12' },
+        { line: 4, marker: 'XXX', tag: null, text: 'drop this column' }
+      ]);
+    });
+  });
+
+  describe('parseBlame', () => {
+    it('should read author and time per final line', () => {
+      const sha = 'a'.repeat(40);
+      const output = [
+        `${sha} 1 1 2`, 'author Ana', 'author-mail <ana@example.com>', 'author-time 1700000000', 'summary x', '\tline one',
+        `${sha} 2 2`, 'author Ana', 'author-mail <ana@example.com>', 'author-time 1700000000', '\tline two',
+        `${'0'.repeat(40)} 3 3 1`, 'author Not Committed Yet', 'author-mail <not.committed.yet>', 'author-time 1800000000', '\tline three'
+      ].join('\n');
+      const lines = parseBlame(output);
+      expect(lines.get(2)).toEqual({ commit: sha, author: 'Ana', email: 'ana@example.com', time: 1700000000 });
+      expect(lines.get(3).commit).toBe('0'.repeat(40));
+    });
+  });
+
+  describe('enrichTodos', () => {
+    it('should date items, prefer the tag as owner, and treat uncommitted lines as new', () => {
+      const blame = () => [
+        `${'b'.repeat(40)} 1 1 1`, 'author Ben', 'author-mail <ben@example.com>', `author-time ${NOW / 1000 - 400 * DAY}`, '\tx',
+        `${'c'.repeat(40)} 2 2 1`, 'author Ben', 'author-mail <ben@example.com>', `author-time ${NOW / 1000 - 40 * DAY}`, '\tx',
+        `${'0'.repeat(40)} 3 3 1`, 'author Not Committed Yet', 'author-time 0', '\tx'
+      ].join('\n');
+      const items = enrichTodos('.', [
+        { file: 'a.js', line: 1, marker: 'TODO', tag: null, text: 'one' },
+        { file: 'a.js', line: 2, marker: 'FIXME', tag: 'ana', text: 'two' },
+        { file: 'a.js', line: 3, marker: 'TODO', tag: null, text: 'three' }
+      ], { now: NOW, blame });
+
+      expect(items.map(item => [item.owner, item.ageDays, item.staleness])).toEqual([
+        ['Ben', 400, 'ancient'],
+        ['ana', 40, 'aging'],
+        ['uncommitted', 0, 'fresh']
+      ]);
+      expect(items[0].date).toBe('2025-04-27');
+      expect(items[2].commit).toBeNull();
+
+      expect(groupByOwner(items).map(group => [group.owner, group.count, group.oldestDays])).toEqual([
+        ['Ben', 1, 400],
+        ['ana', 1, 40],
+        ['uncommitted', 1, 0]
+      ]);
+      expect(groupByStaleness(items)).toEqual([
+        { name: 'fresh', count: 1 },
+        { name: 'aging', count: 1 },
+        { name: 'stale', count: 0 },
+        { name: 'ancient', count: 1 }
+      ]);
+    });
+  });
+
+  describe('issueCommand', () => {
+    const item = {
+      file: 'src/a.js', line: 7, marker: 'FIXME', tag: null, text: 'wrong timezone',
+      commit: 'd'.repeat(40), author: 'Ana', date: '2025-01-02', ageDays: 150
+    };
+
+    it('should build gh and glab argv with a permalink to the line', () => {
+      const github = issueCommand({ host: 'github', url: 'https://github.com/acme/shop' }, item, { labels: ['tech-debt', 'todo'] });
+      expect(github.slice(0, 5)).toEqual(['gh', 'issue', 'create', '--title', 'FIXME: wrong timezone']);
+      expect(github[6]).toContain(`[src/a.js:7](https://github.com/acme/shop/blob/${'d'.repeat(40)}/src/a.js#L7)`);
+      expect(github.slice(7)).toEqual(['--label', 'tech-debt', '--label', 'todo']);
+
+      const gitlab = issueCommand({ host: 'gitlab', url: 'https://gitlab.com/acme/shop' }, item, { labels: ['tech-debt', 'todo'] });
+      expect(gitlab[5]).toBe('--description');
+      expect(gitlab[6]).toContain('https://gitlab.com/acme/shop/-/blob/');
+      expect(gitlab.slice(7)).toEqual(['--label', 'tech-debt,todo']);
+
+      expect(issueCommand({ host: 'bitbucket', url: 'https://bitbucket.org/acme/shop' }, item)).toBeNull();
+      expect(issueTitle({ ...item, text: 'x'.repeat(100) })).toHaveLength(72);
+    });
+  });
+
+  describe('triageTodos', () => {
+    let repo;
+
+    function git(args, env = {}) {
+      return execFileSync('git', args, { cwd: repo, encoding: 'utf8', stdio: ['pipe', 'pipe', 'pipe'], env: { ...process.env, ...env } });
+    }
+
+    function commitAt(message, daysAgo) {
+      const date = new Date(NOW - daysAgo * DAY * 1000).toISOString();
+      git(['add', '-A']);
+      git(['commit', '-q', '-m', message], { GIT_AUTHOR_DATE: date, GIT_COMMITTER_DATE: date });
+    }
+
+    beforeEach(() => {
+      repo = fs.mkdtempSync(path.join(os.tmpdir(), 'todos-'));
+      git(['init', '-q']);
+      git(['config', 'user.email', 'ana@example.com']);
+      git(['config', 'user.name', 'Ana']);
+      git(['remote', 'add', 'origin', 'git@github.com:acme/shop.git']);
+    });
+
+    afterEach(() => {
+      fs.rmSync(repo, { recursive: true, force: true });
+    });
+
+    it('should find overdue items by blame age', () => {
+      fs.writeFileSync(path.join(repo, 'a.js'), 'const a = 1; // TODO: old item\n');
+      fs.writeFileSync(path.join(repo, 'NOTES.md'), '# TODO: not code\n');
+      commitAt('first', 200);
+      fs.appendFileSync(path.join(repo, 'a.js'), '// FIXME(ben): newer item\n');
+      commitAt('second', 10);
+
+      const report = triageTodos(repo, { now: NOW });
+      expect(report.success).toBe(true);
+      expect(report.total).toBe(2);
+      expect(report.threshold).toBe(90);
+      expect(report.overdue.map(item => [item.text, item.ageDays, item.owner])).toEqual([['old item', 200, 'Ana']]);
+      expect(report.repository).toEqual({ host: 'github', url: 'https://github.com/acme/shop' });
+
+      const content = renderReport(report);
+      expect(content).toContain('**Items**: 2 | **Overdue** (90+ days): 1');
+      expect(content).toContain('| 200 | TODO | `a.js:1` | Ana | old item |');
+    });
+
+    it('should read the threshold and labels from the project config', () => {
+      fs.writeFileSync(path.join(repo, '.awesome-slash.json'), JSON.stringify({ todoTriage: { threshold: 5, labels: 'todo' } }));
+      fs.writeFileSync(path.join(repo, 'a.py'), '# TODO: item\n');
+      commitAt('first', 10);
+
+      expect(readSettings(repo)).toEqual({ threshold: 5, labels: ['todo'], error: null });
+      const report = triageTodos(repo, { now: NOW });
+      expect(report.overdue).toHaveLength(1);
+      expect(triageTodos(repo, { now: NOW, threshold: 30 }).overdue).toHaveLength(0);
+
+      fs.writeFileSync(path.join(repo, '.awesome-slash.json'), JSON.stringify({ todoTriage: { threshold: -1 } }));
+      expect(triageTodos(repo, { now: NOW }).configError).toMatch(/threshold must be a positive number/);
+    });
+
+    it('should fail outside a git repository', () => {
+      fs.rmSync(path.join(repo, '.git'), { recursive: true, force: true });
+      expect(triageTodos(repo).success).toBe(false);
+    });
+  });
+});
@@ -0,0 +1,104 @@
+/**
+ * Tests for lib/patterns/triage.js
+ */
+
+const fs = require('fs');
+const os = require('os');
+const path = require('path');
+
+const { renderPreview, editorCommand, runTriage, describeTriage } = require('../lib/patterns/triage');
+const { loadBaseline, filterBaseline } = require('../lib/patterns/baseline');
+
+describe('triage', () => {
+  let root;
+
+  const source = [
+    'function total(items) {',
+    '  console.log("debug", items);',
+    '  // todo implement caching',
+    '  console.log("again");',
+    '  return items.length;',
+    '}',
+    ''
+  ].join('\n');
+
+  const findings = [
+    { file: 'src/a.js', line: 2, patternName: 'console_debugging', severity: 'medium', certainty: 'HIGH', autoFix: 'remove' },
+    { file: 'src/a.js', line: 3, patternName: 'placeholder_text', severity: 'high', certainty: 'HIGH', autoFix: 'flag' },
+    { file: 'src/a.js', line: 4, patternName: 'console_debugging', severity: 'medium', certainty: 'HIGH', autoFix: 'remove' }
+  ];
+
+  function scripted(answers) {
+    const output = [];
+    const opened = [];
+    return {
+      output,
+      opened,
+      io: {
+        ask: async () => answers.shift(),
+        write: text => output.push(text),
+        open: async argv => { opened.push(argv); }
+      }
+    };
+  }
+
+  beforeEach(() => {
+    root = fs.mkdtempSync(path.join(os.tmpdir(), 'triage-'));
+    fs.mkdirSync(path.join(root, 'src'));
+    fs.writeFileSync(path.join(root, 'src', 'a.js'), source);
+  });
+
+  afterEach(() => {
+    fs.rmSync(root, { recursive: true, force: true });
+  });
+
+  it('should preview the flagged line with context and highlight the match', () => {
+    expect(renderPreview(root, findings[0], { context: 1 })).toBe([
+      '  1 | function total(items) {',
+      '> 2 |   console.log("debug", items);',
+      '  3 |   // todo implement caching'
+    ].join('\n'));
+
+    const colored = renderPreview(root, findings[0], { context: 0, color: true });
+    expect(colored).toContain('\x1b[1;31mconsole.log(\x1b[22;39m');
+    expect(renderPreview(root, { ...findings[0], file: 'src/missing.js' })).toBe('');
+  });
+
+  it('should build editor commands that jump to the line', () => {
+    expect(editorCommand('/r/a.js', 4, { EDITOR: 'vim' })).toEqual(['vim', '+4', '/r/a.js']);
+    expect(editorCommand('/r/a.js', 4, { VISUAL: 'code --wait', EDITOR: 'vim' })).toEqual(['code', '--wait', '-g', '/r/a.js:4']);
+    expect(editorCommand('/r/a.js', 4, { EDITOR: '/usr/local/bin/subl' })).toEqual(['/usr/local/bin/subl', '/r/a.js:4']);
+    expect(editorCommand('/r/a.js', 4, { EDITOR: 'ed' })).toEqual(['ed', '/r/a.js']);
+    expect(editorCommand('/r/a.js', 4, {})).toBeNull();
+  });
+
+  it('should apply fixes and baseline entries decided in the session', async () => {
+    // f fixes, f on a flag-only pattern refuses, p goes back, s clears a decision
+    const session = scripted(['?', 'f', 'f', 'b', 'p', 's', 'p', 'b', 'o', 'd', 'f']);
+    const result = await runTriage(root, findings, session.io, { env: { EDITOR: 'nano' } });
+
+    expect(result).toMatchObject({ fixed: 2, filesChanged: 1, baselined: 1, reviewed: 3, fixSkipped: [], error: null });
+    expect(session.output.some(text => text.includes('f - fix this finding'))).toBe(true);
+    expect(session.output).toContain('No fix: placeholder_text has no auto-fix. Use b to baseline it or o to edit it.');
+    expect(session.output.some(text => text.startsWith('\n(2/3) src/a.js:3 placeholder_text high HIGH [baseline]'))).toBe(true);
+    expect(session.opened).toEqual([['nano', '+4', path.join(root, 'src/a.js')]]);
+
+    expect(fs.readFileSync(path.join(root, 'src/a.js'), 'utf8')).not.toContain('console.log');
+    const { baseline } = loadBaseline(root);
+    expect(baseline.entries.map(entry => entry.patternName)).toEqual(['placeholder_text']);
+    expect(filterBaseline(root, [{ ...findings[1], line: 2 }], baseline).findings).toEqual([]);
+    expect(describeTriage(result, 3)).toBe('Reviewed 3/3, fixed 2 in 1 file, baselined 1 (.slop-baseline.json)');
+  });
+
+  it('should change nothing when quitting before any decision', async () => {
+    const session = scripted(['s', 'q']);
+    const result = await runTriage(root, findings, session.io);
+
+    expect(result).toMatchObject({ fixed: 0, baselined: 0, reviewed: 2 });
+    expect(fs.readFileSync(path.join(root, 'src/a.js'), 'utf8')).toBe(source);
+    expect(fs.existsSync(path.join(root, '.slop-baseline.json'))).toBe(false);
+
+    const ended = await runTriage(root, findings, scripted([]).io);
+    expect(ended.reviewed).toBe(1);
+  });
+});
@@ -8,12 +8,12 @@
  * Remove:   npm uninstall -g awesome-slash && awesome-slash --remove
  */
 
-const { execSync } = require('child_process');
 const fs = require('fs');
 const path = require('path');
 const readline = require('readline');
 
 const VERSION = require('../package.json').version;
+const { commandExists, execSync, run } = require('../lib/utils/exec');
 // Use the installed npm package directory as source (no git clone needed)
 const PACKAGE_DIR = path.join(__dirname, '..');
 
@@ -33,15 +33,6 @@ function getConfigPath(platform) {
   return null;
 }
 
-function commandExists(cmd) {
-  try {
-    execSync(`${process.platform === 'win32' ? 'where' : 'which'} ${cmd}`, { stdio: 'pipe' });
-    return true;
-  } catch {
-    return false;
-  }
-}
-
 /**
  * Interactive multi-select prompt
  */
@@ -103,13 +94,13 @@ function copyFromPackage(installDir) {
 
 function installDependencies(installDir) {
   console.log('Installing dependencies...');
-  execSync('npm install --production', { cwd: installDir, stdio: 'inherit' });
+  execSync(installDir, ['npm', 'install', '--production'], { stdio: 'inherit' });
 
   // Also install MCP server dependencies
   const mcpDir = path.join(installDir, 'mcp-server');
   if (fs.existsSync(path.join(mcpDir, 'package.json'))) {
     console.log('Installing MCP server dependencies...');
-    execSync('npm install --production', { cwd: mcpDir, stdio: 'inherit' });
+    execSync(mcpDir, ['npm', 'install', '--production'], { stdio: 'inherit' });
   }
 }
 
@@ -128,26 +119,16 @@ function installForClaude() {
   try {
     // Add GitHub marketplace
     console.log('Adding marketplace...');
-    try {
-      execSync('claude plugin marketplace add avifenesh/awesome-slash', { stdio: 'pipe' });
-    } catch {
-      // May already exist
-    }
+    // May already exist
+    run(process.cwd(), ['claude', 'plugin', 'marketplace', 'add', 'avifenesh/awesome-slash']);
 
     // PLUGINS_ARRAY - Install or update plugins
     const plugins = ['next-task', 'ship', 'deslop', 'audit-project', 'drift-detect', 'enhance', 'sync-docs', 'repo-map'];
     for (const plugin of plugins) {
       console.log(`  Installing ${plugin}...`);
-      try {
-        // Try install first
-        execSync(`claude plugin install ${plugin}@awesome-slash`, { stdio: 'pipe' });
-      } catch {
-        // If install fails (already installed), try update
-        try {
-          execSync(`claude plugin update ${plugin}@awesome-slash`, { stdio: 'pipe' });
-        } catch {
-          // Ignore if update also fails
-        }
+      // If install fails (already installed), try update; ignore if update also fails
+      if (run(process.cwd(), ['claude', 'plugin', 'install', `${plugin}@awesome-slash`]) === null) {
+        run(process.cwd(), ['claude', 'plugin', 'update', `${plugin}@awesome-slash`]);
       }
     }
 
@@ -249,7 +230,28 @@ function installForOpenCode(installDir, options = {}) {
     ['audit-project.md', 'audit-project', 'audit-project.md'],
     ['ship.md', 'ship', 'ship.md'],
     ['drift-detect.md', 'drift-detect', 'drift-detect.md'],
-    ['repo-map.md', 'repo-map', 'repo-map.md']
+    ['repo-map.md', 'repo-map', 'repo-map.md'],
+    ['test-gen.md', 'repo-map', 'test-gen.md'],
+    ['coverage.md', 'repo-map', 'coverage.md'],
+    ['migrate.md', 'repo-map', 'migrate.md'],
+    ['onboard.md', 'repo-map', 'onboard.md'],
+    ['docs-gen.md', 'repo-map', 'docs-gen.md'],
+    ['todo-triage.md', 'deslop', 'todo-triage.md'],
+    ['install-hooks.md', 'deslop', 'install-hooks.md'],
+    ['flaky.md', 'ship', 'flaky.md'],
+    ['benchmark.md', 'ship', 'benchmark.md'],
+    ['changelog.md', 'ship', 'changelog.md'],
+    ['release.md', 'ship', 'release.md'],
+    ['pr-description.md', 'ship', 'pr-description.md'],
+    ['commit.md', 'ship', 'commit.md'],
+    ['resolve.md', 'ship', 'resolve.md'],
+    ['deps-audit.md', 'audit-project', 'deps-audit.md'],
+    ['env-check.md', 'audit-project', 'env-check.md'],
+    ['license-check.md', 'audit-project', 'license-check.md'],
+    ['sbom.md', 'audit-project', 'sbom.md'],
+    ['trends.md', 'audit-project', 'trends.md'],
+    ['report.md', 'audit-project', 'report.md'],
+    ['issue.md', 'audit-project', 'issue.md']
   ];
 
   // Helper function to transform content for OpenCode
@@ -389,7 +391,7 @@ function installForOpenCode(installDir, options = {}) {
   console.log(`   Agents: ${agentsDir}`);
   console.log(`   Plugin: ${pluginDir}`);
   console.log('   Access via: /next-task, /ship, /deslop, /audit-project, /drift-detect, /enhance, /sync-docs');
-  console.log('   MCP tools: workflow_status, workflow_start, workflow_resume, task_discover, review_code, slop_detect, enhance_analyze, repo_map');
+  console.log('   MCP tools: workflow_status, workflow_start, workflow_resume, task_discover, review_code, slop_detect, enhance_analyze, repo_map, repo_query, platform_detect, scan_run');
   console.log('   Native features: Auto-thinking selection, workflow enforcement, session compaction\n');
   return true;
 }
@@ -489,14 +491,56 @@ AI_STATE_DIR = ".codex"
       'Use when user asks to "ship this", "create PR", "merge to main", "deploy changes", "push to production". Complete PR workflow: commit, create PR, monitor CI, merge, deploy, validate.'],
     ['deslop', 'deslop', 'deslop.md',
       'Use when user asks to "clean up slop", "remove AI artifacts", "deslop the codebase", "find debug statements", "remove console.logs", "repo hygiene". Detects and removes AI-generated slop patterns.'],
+    ['todo-triage', 'deslop', 'todo-triage.md',
+      'Use when user asks to "triage TODOs", "find old FIXMEs", "who owns these TODOs", "stale TODO comments", "turn TODOs into issues". Dates TODO/FIXME/HACK comments with git blame, groups them by owner and staleness, and opens tracking issues.'],
+    ['install-hooks', 'deslop', 'install-hooks.md',
+      'Use when user asks to "add a pre-commit hook", "block secrets on commit", "run deslop before commit", "set up husky", "set up pre-commit". Installs a staged-lines slop and secret scan in pre-commit, husky, or simple-git-hooks, whichever the project uses.'],
     ['audit-project', 'audit-project', 'audit-project.md',
       'Use when user asks to "review my code", "check for issues", "run code review", "analyze PR quality". Multi-agent iterative review that loops until all critical/high issues are resolved.'],
+    ['deps-audit', 'audit-project', 'deps-audit.md',
+      'Use when user asks to "audit dependencies", "check for vulnerable packages", "find outdated dependencies", "find unused dependencies". Reports outdated, vulnerable (OSV), and unused dependencies across package managers.'],
+    ['env-check', 'audit-project', 'env-check.md',
+      'Use when user asks to "check env vars", "find undocumented environment variables", "validate .env.example", "find unused env vars". Cross-checks environment variables read in code against example files, CI, and deploy config.'],
+    ['license-check', 'audit-project', 'license-check.md',
+      'Use when user asks to "check dependency licenses", "license compliance", "find GPL dependencies", "license policy", "fail CI on copyleft". Checks every locked package license against an allow/deny policy.'],
+    ['sbom', 'audit-project', 'sbom.md',
+      'Use when user asks to "generate an SBOM", "software bill of materials", "CycloneDX", "SPDX", "export dependencies for compliance". Writes a CycloneDX or SPDX document from the lockfiles with transitive dependencies, hashes, and licenses.'],
+    ['trends', 'audit-project', 'trends.md',
+      'Use when user asks to "show slop trends", "is tech debt going down", "finding history", "review debt over time", "justify cleanup work". Compares recorded slop and review finding counts over the last runs by severity and category.'],
+    ['report', 'audit-project', 'report.md',
+      'Use when user asks to "make an HTML report", "share scan results", "report for the team", "CI artifact of findings", "findings for stakeholders". Renders slop, review, deps-audit, SARIF, and coverage results into one self-contained HTML file.'],
+    ['issue', 'audit-project', 'issue.md',
+      'Use when user asks to "file issues for these findings", "open GitHub issues from the scan", "create GitLab issues", "track security findings as issues", "sync findings to the tracker". Files slop, security, dependency, and SARIF findings as deduplicated issues with labels and CODEOWNERS assignees.'],
     ['drift-detect', 'drift-detect', 'drift-detect.md',
       'Use when user asks to "check plan drift", "compare docs to code", "verify roadmap", "scan for reality gaps". Analyzes documentation vs actual code to detect drift and outdated plans.'],
     ['repo-map', 'repo-map', 'repo-map.md',
       'Use when user asks to "create repo map", "generate repo map", "update repo map", "repo map status", "map symbols". Builds and updates AST-based repo map using ast-grep.'],
+    ['test-gen', 'repo-map', 'test-gen.md',
+      'Use when user asks to "generate tests", "scaffold tests", "write tests for this file", "add test file". Scaffolds tests from repo-map signatures in the detected test framework and test layout.'],
+    ['coverage', 'repo-map', 'coverage.md',
+      'Use when user asks to "check coverage", "summarize coverage report", "what is untested", "find uncovered functions". Parses lcov, Cobertura, Go and coverage.py reports and ranks the least-covered exported functions.'],
+    ['migrate', 'repo-map', 'migrate.md',
+      'Use when user asks to "upgrade Node", "migrate to Pydantic v2", "upgrade React", "framework upgrade", "runtime upgrade". Finds affected call sites via the repo map, applies safe codemods, and lists manual steps.'],
+    ['onboard', 'repo-map', 'onboard.md',
+      'Use when user asks to "onboard me", "write ONBOARDING.md", "how do I build this project", "where does the code live", "project overview for new contributors". Generates an orientation doc from platform detection and the repo map.'],
+    ['docs-gen', 'repo-map', 'docs-gen.md',
+      'Use when user asks to "generate API docs", "API reference", "document the exported functions", "godoc for this repo", "reference docs per package". Generates markdown reference pages from repo-map symbols and doc comments.'],
     ['delivery-approval', 'next-task', 'delivery-approval.md',
       'Use when user asks to "validate delivery", "approve for shipping", "check if ready to ship", "verify task completion". Autonomous validation that tests pass, build succeeds, and requirements are met.'],
+    ['changelog', 'ship', 'changelog.md',
+      'Use when user asks to "generate changelog", "write release notes", "update CHANGELOG.md", "what changed since the last release". Builds release notes from conventional commits since the last tag.'],
+    ['release', 'ship', 'release.md',
+      'Use when user asks to "cut a release", "bump the version", "tag a release", "publish a new version". Computes the next semver from commits, bumps version files, tags, and drafts a forge release.'],
+    ['pr-description', 'ship', 'pr-description.md',
+      'Use when user asks to "write a PR description", "generate PR title", "describe this PR", "fill the PR template". Builds a PR title and description from the diff, symbol changes, commits, and PR template.'],
+    ['commit', 'ship', 'commit.md',
+      'Use when user asks to "write a commit message", "commit this", "conventional commit", "what should the commit message be", "commit staged changes". Proposes a conventional commit message from the staged diff, symbol changes, and commitlint config.'],
+    ['resolve', 'ship', 'resolve.md',
+      'Use when user asks to "resolve merge conflicts", "fix conflicts", "rebase conflicts", "lockfile conflict", "help me merge". Classifies conflicted files, regenerates lockfiles and generated files, merges imports, and lays out the logic conflicts with context.'],
+    ['flaky', 'ship', 'flaky.md',
+      'Use when user asks to "find flaky tests", "which tests are flaky", "tests fail randomly in CI", "flake rate", "intermittent test failures". Reads recent GitHub Actions or GitLab CI runs and finds tests that passed and failed at the same commit.'],
+    ['benchmark', 'ship', 'benchmark.md',
+      'Use when user asks to "run benchmarks", "check for performance regressions", "compare benchmarks to main", "did this get slower", "benchmark baseline". Runs go test -bench, criterion, pytest-benchmark, or vitest bench and compares with a baseline ref using a significance test.'],
     ['sync-docs', 'sync-docs', 'sync-docs.md',
       'Use when user asks to "update docs", "sync documentation", "fix outdated docs", "refresh README". Compares documentation to actual code and fixes discrepancies.']
   ];
@@ -549,7 +593,7 @@ AI_STATE_DIR = ".codex"
   console.log(`   Config: ${configPath}`);
   console.log(`   Skills: ${skillsDir}`);
   console.log('   Access via: $next-task, $ship, $deslop, etc.');
-  console.log('   MCP tools: workflow_status, workflow_start, workflow_resume, task_discover, review_code, slop_detect, enhance_analyze, repo_map\n');
+  console.log('   MCP tools: workflow_status, workflow_start, workflow_resume, task_discover, review_code, slop_detect, enhance_analyze, repo_map, repo_query, platform_detect, scan_run\n');
   return true;
 }
 
@@ -572,11 +616,231 @@ function removeInstallation() {
   console.log('  - Codex: Remove [mcp_servers.awesome-slash] from ~/.codex/config.toml');
 }
 
+/**
+ * Run the MCP server from the package
+ */
+async function serveMcp() {
+  const serverDir = path.join(PACKAGE_DIR, 'mcp-server');
+  let server;
+  try {
+    server = require(path.join(serverDir, 'index.js'));
+  } catch (err) {
+    if (err.code === 'MODULE_NOT_FOUND' && err.message.includes('@modelcontextprotocol/sdk')) {
+      console.error(`MCP SDK not installed. Run: npm install --production --prefix "${serverDir}"`);
+      process.exit(1);
+    }
+    throw err;
+  }
+  server.setupErrorBoundary();
+  await server.main();
+}
+
+/**
+ * Check the project config in the current directory against the schema
+ */
+function validateProjectConfig() {
+  const { loadConfig, validateConfig, CONFIG_FILENAMES } = require(path.join(PACKAGE_DIR, 'lib', 'config'));
+  const { parseScanSettings } = require(path.join(PACKAGE_DIR, 'lib', 'patterns', 'custom-patterns'));
+  const { config, file, error } = loadConfig(process.cwd());
+  if (!file) {
+    console.log(`No project config (${CONFIG_FILENAMES.join(', ')})`);
+    return;
+  }
+  const errors = error ? [error] : Array.from(new Set([
+    ...validateConfig(config, file).errors,
+    ...parseScanSettings(config).errors.map(message => `${file}: ${message}`)
+  ]));
+  if (errors.length === 0) {
+    console.log(`✓ ${file} is valid`);
+    return;
+  }
+  console.error(`✗ ${file} is invalid:`);
+  for (const message of errors) console.error(`  - ${message}`);
+  process.exit(1);
+}
+
+/**
+ * List plugin commands, or write their slash command files (`sync`)
+ * @param {string} [action='list'] - list or sync
+ */
+function managePlugins(action = 'list') {
+  const plugins = require(path.join(PACKAGE_DIR, 'lib', 'plugins'));
+  const { plugins: loaded, errors } = plugins.loadPlugins(process.cwd());
+  if (action === 'sync') {
+    const { written, skipped } = plugins.syncCommandFiles(process.cwd());
+    console.log(written.length ? `Updated: ${written.join(', ')}` : 'No command files changed');
+    for (const file of skipped) console.log(`Skipped ${file} (not generated by awesome-slash)`);
+  } else if (loaded.length === 0) {
+    console.log('No plugins found (awesome-slash-plugin-* packages, <state-dir>/plugins/, plugins.paths)');
+  } else {
+    for (const plugin of loaded) {
+      console.log(`${plugin.name} (${plugin.source})`);
+      for (const name of plugin.commands) {
+        const command = plugins.getCommand(name);
+        console.log(`  /${name}${command.argumentHint ? ` ${command.argumentHint}` : ''}${command.description ? ` - ${command.description}` : ''}`);
+      }
+      if (plugin.detectors.length) console.log(`  detectors: ${plugin.detectors.join(', ')}`);
+    }
+  }
+  for (const { plugin, source, error } of errors) {
+    console.error(`✗ ${plugin || source}: ${error}`);
+  }
+  if (errors.length) process.exitCode = 1;
+}
+
+/**
+ * Run a plugin command and print its JSON result
+ * @param {string} name - Command name
+ * @param {string[]} commandArgs - Arguments
+ */
+async function runPluginCommand(name, commandArgs) {
+  const plugins = require(path.join(PACKAGE_DIR, 'lib', 'plugins'));
+  const { errors } = plugins.loadPlugins(process.cwd());
+  for (const { plugin, source, error } of errors) {
+    console.error(`✗ ${plugin || source}: ${error}`);
+  }
+  const result = await plugins.runCommand(name, commandArgs);
+  console.log(JSON.stringify(result, null, process.stdout.isTTY ? 2 : 0));
+  if (!result.success) process.exitCode = 1;
+}
+
+/**
+ * Flush opted-in usage metrics once a batch is pending (telemetry.endpoint set)
+ */
+async function flushTelemetry() {
+  try {
+    const telemetry = require(path.join(PACKAGE_DIR, 'lib', 'telemetry'));
+    const settings = telemetry.readSettings(process.cwd());
+    const status = telemetry.status(process.cwd());
+    if (settings.enabled && settings.endpoint && status.pending >= settings.batchSize) {
+      await telemetry.flush(process.cwd());
+    }
+  } catch {
+    // Metrics never block a command
+  }
+}
+
 async function main() {
-  const args = process.argv.slice(2);
+  // Log flags apply to every subcommand (and child processes, via the environment)
+  const { applyLogArgs } = require(path.join(PACKAGE_DIR, 'lib', 'utils', 'logger'));
+  const { argv: args, error: logArgsError } = applyLogArgs(process.argv.slice(2));
+  if (logArgsError) {
+    console.error(logArgsError);
+    process.exit(1);
+  }
+  process.argv.splice(2, process.argv.length - 2, ...args);
   const stripModels = args.includes('--strip-models') ||
     ['1', 'true', 'yes'].includes((process.env.AWESOME_SLASH_STRIP_MODELS || '').toLowerCase());
 
+  // Handle serve --mcp (stdout carries the protocol, so nothing else may print)
+  if (args[0] === 'serve') {
+    if (!args.includes('--mcp')) {
+      console.error('Usage: awesome-slash serve --mcp');
+      process.exit(1);
+    }
+    await serveMcp();
+    return;
+  }
+
+  // Handle config validate
+  if (args[0] === 'config') {
+    if (args[1] !== 'validate') {
+      console.error('Usage: awesome-slash config validate');
+      process.exit(1);
+    }
+    validateProjectConfig();
+    return;
+  }
+
+  // Handle plugins [list|sync]
+  if (args[0] === 'plugins') {
+    if (args[1] && !['list', 'sync'].includes(args[1])) {
+      console.error('Usage: awesome-slash plugins [list|sync]');
+      process.exit(1);
+    }
+    managePlugins(args[1]);
+    return;
+  }
+
+  // Handle run <command> (plugin commands)
+  if (args[0] === 'run') {
+    if (!args[1]) {
+      console.error('Usage: awesome-slash run <command> [args...]');
+      process.exit(1);
+    }
+    await runPluginCommand(args[1], args.slice(2));
+    return;
+  }
+
+  // Handle detect (slop scan, used by pre-commit hooks); detect.js reads its own argv
+  if (args[0] === 'detect') {
+    process.argv.splice(2, 1);
+    require(path.join(PACKAGE_DIR, 'plugins', 'deslop', 'scripts', 'detect.js'));
+    return;
+  }
+
+  // Handle telemetry [status|show|flush|clear] (opt-in usage metrics)
+  if (args[0] === 'telemetry') {
+    const telemetry = require(path.join(PACKAGE_DIR, 'lib', 'telemetry'));
+    const action = args[1] || 'status';
+    const indent = process.stdout.isTTY ? 2 : 0;
+    if (action === 'status') {
+      console.log(JSON.stringify(telemetry.status(process.cwd()), null, indent));
+    } else if (action === 'show') {
+      console.log(JSON.stringify(telemetry.summarize(telemetry.readStore(process.cwd()).events), null, indent));
+    } else if (action === 'clear') {
+      console.log(`Removed ${telemetry.clear(process.cwd()).removed} events`);
+    } else if (action === 'flush') {
+      const result = await telemetry.flush(process.cwd());
+      console.log(result.success ? `Sent ${result.sent} events` : `${result.error} (${result.pending} pending)`);
+      if (!result.success) process.exitCode = 1;
+    } else {
+      console.error('Usage: awesome-slash telemetry [status|show|flush|clear]');
+      process.exit(1);
+    }
+    return;
+  }
+
+  // Handle cache [stats|clear] (shared .awesome-slash/cache directory)
+  if (args[0] === 'cache') {
+    const cache = require(path.join(PACKAGE_DIR, 'lib', 'cache'));
+    const action = args[1] && !args[1].startsWith('-') ? args[1] : 'stats';
+    const namespace = args.includes('--namespace') ? args[args.indexOf('--namespace') + 1] : undefined;
+    const indent = process.stdout.isTTY ? 2 : 0;
+    if (action === 'stats') {
+      console.log(JSON.stringify(cache.stats(process.cwd()), null, indent));
+    } else if (action === 'clear') {
+      const result = cache.clear(process.cwd(), { namespace, expired: args.includes('--expired') });
+      console.log(`Removed ${result.removed} cache entries (${Math.round(result.bytes / 1024)} KB)`);
+    } else {
+      console.error('Usage: awesome-slash cache [stats|clear] [--namespace NAME] [--expired]');
+      process.exit(1);
+    }
+    return;
+  }
+
+  // Send a full batch of opted-in metrics before the command; failures wait for the next run
+  await flushTelemetry();
+
+  // Handle rollback (undo the last journaled run of a mutating command)
+  if (args[0] === 'rollback' || args.includes('--rollback')) {
+    const journal = require(path.join(PACKAGE_DIR, 'lib', 'journal'));
+    const value = flag => (args.includes(flag) ? args[args.indexOf(flag) + 1] : undefined);
+    const dryRun = args.includes('--dry-run');
+    const result = journal.rollback(process.cwd(), { command: value('--command'), dryRun, force: args.includes('--force') });
+    console.log(journal.renderRollback(result, dryRun));
+    if (!result.success) process.exitCode = 1;
+    return;
+  }
+
+  // Handle triage (interactive slop review); runs detect.js --interactive
+  if (args[0] === 'triage') {
+    process.argv.splice(2, 1);
+    process.argv.push('--interactive');
+    require(path.join(PACKAGE_DIR, 'plugins', 'deslop', 'scripts', 'detect.js'));
+    return;
+  }
+
   // Handle --remove / --uninstall
   if (args.includes('--remove') || args.includes('--uninstall')) {
     removeInstallation();
@@ -598,11 +862,23 @@ Usage:
   awesome-slash              Interactive installer (select platforms)
   awesome-slash --strip-models  Skip per-agent model overrides (OpenCode)
   awesome-slash --remove     Remove local installation
+  awesome-slash serve --mcp  Run the MCP server on stdio (repository = current directory)
+  awesome-slash detect --staged  Scan staged lines for slop and secrets (pre-commit hooks)
+  awesome-slash telemetry [show|flush|clear]  Opt-in usage metrics (telemetry.enabled in .awesome-slash.json)
+  awesome-slash cache [stats|clear]  Shared cache (detection, repo-map, OSV, CI); clear takes --namespace NAME, --expired
+  awesome-slash rollback [--dry-run]  Undo the last release, auto-fix, or issue run (--command NAME, --force)
+  awesome-slash triage [path]  Step through slop findings: fix, baseline, or open each in $EDITOR
+  awesome-slash config validate  Check .awesome-slash.json against the config schema
+  awesome-slash plugins [sync]  List plugin commands, or write their .claude/commands files
+  awesome-slash run <command>   Run a plugin command
+  awesome-slash ... --log-level debug  Diagnostics on stderr (silent, error, warn, info, debug, trace)
+  awesome-slash ... --log-format json  One JSON object per log line (with --log-namespaces platform:*)
   awesome-slash --version    Show version
   awesome-slash --help       Show this help
 
 Environment:
   AWESOME_SLASH_STRIP_MODELS=1  Same as --strip-models
+  AWESOME_SLASH_LOG_LEVEL, AWESOME_SLASH_LOG_FORMAT, AWESOME_SLASH_LOG_NAMESPACES  Same as the --log-* flags
 
 Supported Platforms:
   1) Claude Code  - /next-task, /ship, /deslop, /audit-project
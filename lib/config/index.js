@@ -1,14 +1,189 @@
 /**
  * Configuration Module
  *
- * Placeholder for future configuration management.
- * This module exists to satisfy lib/index.js imports.
+ * Reads the project config file at the repository root: `.awesome-slash.json`,
+ * or `awesome-slash.config.js` for a config that needs code (the
+ * `awsome-slash` spellings are also read). Settings are optional and described
+ * by lib/schemas/project-config.schema.json; `validateConfig` checks a whole
+ * file against it, and each consumer still validates the keys it uses, e.g.
+ * `branchStrategy` in lib/platform/detect-platform.js.
  *
  * @module config
  * @author Avi Fenesh
  * @license MIT
  */
 
+const fs = require('fs');
+const path = require('path');
+const { SchemaValidator } = require('../schemas/validator');
+
+/**
+ * Config filenames in lookup order
+ */
+const CONFIG_FILENAMES = [
+  '.awesome-slash.json',
+  '.awsome-slash.json',
+  'awesome-slash.config.js',
+  'awsome-slash.config.js'
+];
+
+/**
+ * Project config schema: published URL (for `"$schema"`) and local copy
+ */
+const SCHEMA_URL = 'https://raw.githubusercontent.com/avifenesh/awesome-slash/main/lib/schemas/project-config.schema.json';
+const SCHEMA_PATH = path.join(__dirname, '..', 'schemas', 'project-config.schema.json');
+
+/**
+ * Maximum config size (1MB)
+ */
+const MAX_CONFIG_SIZE_BYTES = 1024 * 1024;
+
+/**
+ * Parse config file content
+ * @param {string} content - File content
+ * @param {string} file - Filename for error messages
+ * @returns {{config: Object, error: string|null}} Empty config with an error when invalid
+ */
+function parseConfig(content, file) {
+  if (typeof content !== 'string' || content.length > MAX_CONFIG_SIZE_BYTES) {
+    return { config: {}, error: `${file}: file is empty or too large` };
+  }
+  let config;
+  try {
+    config = JSON.parse(content);
+  } catch (error) {
+    return { config: {}, error: `${file}: invalid JSON (${error.message})` };
+  }
+  if (!config || typeof config !== 'object' || Array.isArray(config)) {
+    return { config: {}, error: `${file}: top-level value must be an object` };
+  }
+  return { config, error: null };
+}
+
+/**
+ * Whether a config file is a JavaScript module
+ * @param {string} file - Config filename
+ * @returns {boolean}
+ */
+function isScriptConfig(file) {
+  return file.endsWith('.js');
+}
+
+/**
+ * Load a JavaScript config (`module.exports = {...}`)
+ * The module is read fresh on every call, so edits apply without a restart.
+ * @param {string} filePath - Absolute path
+ * @param {string} file - Filename for error messages
+ * @returns {{config: Object, error: string|null}}
+ */
+function loadScriptConfig(filePath, file) {
+  let config;
+  try {
+    delete require.cache[require.resolve(filePath)];
+    config = require(filePath);
+  } catch (error) {
+    return { config: {}, error: `${file}: failed to load (${error.message.split('\n')[0]})` };
+  }
+  if (config && config.__esModule && config.default) config = config.default;
+  if (!config || typeof config !== 'object' || Array.isArray(config)) {
+    return { config: {}, error: `${file}: module.exports must be an object` };
+  }
+  return { config, error: null };
+}
+
+/**
+ * Read one config file
+ * @param {string} basePath - Repository root
+ * @param {string} file - Config filename
+ * @returns {{config: Object, error: string|null}|null} null when the file does not exist
+ */
+function readConfigFile(basePath, file) {
+  const filePath = path.resolve(basePath, file);
+  if (isScriptConfig(file)) {
+    return fs.existsSync(filePath) ? loadScriptConfig(filePath, file) : null;
+  }
+  let content;
+  try {
+    content = fs.readFileSync(filePath, 'utf8');
+  } catch {
+    return null;
+  }
+  return parseConfig(content, file);
+}
+
+/**
+ * Load the project config
+ * @param {string} [basePath=process.cwd()] - Repository root
+ * @returns {{config: Object, file: string|null, error: string|null}} `file` is null when there is no config
+ */
+function loadConfig(basePath = process.cwd()) {
+  for (const file of CONFIG_FILENAMES) {
+    const result = readConfigFile(basePath, file);
+    if (result) return { ...result, file };
+  }
+  return { config: {}, file: null, error: null };
+}
+
+/**
+ * Edit distance between two short strings
+ * @param {string} a
+ * @param {string} b
+ * @returns {number}
+ */
+function editDistance(a, b) {
+  let previous = Array.from({ length: b.length + 1 }, (_, i) => i);
+  for (let i = 1; i <= a.length; i++) {
+    const current = [i];
+    for (let j = 1; j <= b.length; j++) {
+      current[j] = Math.min(previous[j] + 1, current[j - 1] + 1, previous[j - 1] + (a[i - 1] === b[j - 1] ? 0 : 1));
+    }
+    previous = current;
+  }
+  return previous[b.length];
+}
+
+/**
+ * Check a parsed config against the project config schema
+ * Unknown top-level keys name the closest known key, since most are typos.
+ * @param {Object} config - Parsed config
+ * @param {string} [file] - Filename prefixed to each error
+ * @returns {{valid: boolean, errors: string[]}}
+ */
+function validateConfig(config, file) {
+  const known = Object.keys(SchemaValidator.loadSchema(SCHEMA_PATH).properties);
+  const errors = SchemaValidator.validateProjectConfig(config).errors.map(error => {
+    const match = error.match(/^Unexpected property: (.+)$/);
+    if (!match) return error;
+    const closest = known.find(key => editDistance(key.toLowerCase(), match[1].toLowerCase()) <= 2);
+    return `unknown key ${match[1]}${closest ? ` (did you mean ${closest}?)` : ''}`;
+  });
+  return {
+    valid: errors.length === 0,
+    errors: file ? errors.map(error => `${file}: ${error}`) : errors
+  };
+}
+
+/**
+ * Arguments for a command, falling back to `commands.<name>.args`
+ * @param {string} command - Command name without the slash (e.g. `todo-triage`)
+ * @param {string} given - Arguments the command was invoked with
+ * @param {string} [basePath=process.cwd()] - Repository root
+ * @returns {string[]} Whitespace-separated arguments
+ */
+function commandArgs(command, given, basePath = process.cwd()) {
+  const split = text => String(text || '').split(/\s+/).filter(Boolean);
+  if (split(given).length > 0) return split(given);
+  const { config } = loadConfig(basePath);
+  const defaults = config.commands && config.commands[command];
+  return defaults && typeof defaults.args === 'string' ? split(defaults.args) : [];
+}
+
 module.exports = {
-  // Configuration will be added as needed
+  CONFIG_FILENAMES,
+  SCHEMA_URL,
+  parseConfig,
+  readConfigFile,
+  loadConfig,
+  validateConfig,
+  commandArgs
 };
@@ -0,0 +1,868 @@
+#!/usr/bin/env node
+/**
+ * Dependency Audit
+ *
+ * One report across npm/pnpm/yarn, pip/poetry, cargo, and Go modules:
+ * outdated packages from each package manager's own `outdated` command,
+ * known vulnerabilities from the OSV API (https://osv.dev) for the locked
+ * versions, and declared dependencies no file imports according to the
+ * repo map.
+ *
+ * In a monorepo each workspace package is audited, with the per-package
+ * steps running in parallel (`--concurrency`); `--root` audits only the root.
+ *
+ * Usage: node lib/deps/index.js [--offline] [--no-cache] [--root] [--concurrency N]
+ * Output: JSON report (progress on stderr)
+ *
+ * @module lib/deps
+ */
+
+const fs = require('fs');
+const https = require('https');
+const path = require('path');
+
+const { createCache } = require('../cache');
+const { runTasks, runCommand, parseConcurrency, createProgress, renderSummary } = require('../task-runner');
+const { collectDependencies, normalizePythonName } = require('../platform/detect-database');
+const { npmLockVersion, pythonLockVersion } = require('../platform/detect-framework-versions');
+const exec = require('../utils/exec');
+
+/**
+ * OSV ecosystem names
+ */
+const OSV_ECOSYSTEMS = {
+  npm: 'npm',
+  python: 'PyPI',
+  rust: 'crates.io',
+  go: 'Go'
+};
+
+/**
+ * Package managers by lockfile, first match per ecosystem wins
+ * Manifests without a lockfile fall back to npm and pip.
+ */
+const MANAGERS = [
+  { manager: 'pnpm', ecosystem: 'npm', file: 'pnpm-lock.yaml' },
+  { manager: 'yarn', ecosystem: 'npm', file: 'yarn.lock' },
+  { manager: 'npm', ecosystem: 'npm', file: 'package-lock.json' },
+  { manager: 'npm', ecosystem: 'npm', file: 'package.json' },
+  { manager: 'poetry', ecosystem: 'python', file: 'poetry.lock' },
+  { manager: 'pip', ecosystem: 'python', file: 'pyproject.toml' },
+  { manager: 'pip', ecosystem: 'python', file: 'requirements.txt' },
+  { manager: 'cargo', ecosystem: 'rust', file: 'Cargo.toml' },
+  { manager: 'go', ecosystem: 'go', file: 'go.mod' }
+];
+
+/**
+ * Commands that list outdated packages, as JSON where the tool supports it
+ * cargo needs the cargo-outdated plugin.
+ */
+const OUTDATED_COMMANDS = {
+  npm: ['npm', 'outdated', '--json'],
+  pnpm: ['pnpm', 'outdated', '--format', 'json'],
+  yarn: ['yarn', 'outdated', '--json'],
+  pip: ['pip', 'list', '--outdated', '--format=json'],
+  poetry: ['poetry', 'show', '--outdated', '--top-level'],
+  cargo: ['cargo', 'outdated', '--root-deps-only', '--format', 'json'],
+  go: ['go', 'list', '-u', '-m', '-json', 'all']
+};
+
+/**
+ * Python distributions whose import name differs from the package name
+ */
+const PYTHON_IMPORT_NAMES = {
+  'beautifulsoup4': ['bs4'],
+  'pillow': ['PIL'],
+  'pyyaml': ['yaml'],
+  'scikit-learn': ['sklearn'],
+  'python-dateutil': ['dateutil'],
+  'python-dotenv': ['dotenv'],
+  'opencv-python': ['cv2'],
+  'opencv-python-headless': ['cv2'],
+  'protobuf': ['google'],
+  'psycopg2-binary': ['psycopg2'],
+  'pyjwt': ['jwt'],
+  'pymysql': ['pymysql'],
+  'attrs': ['attr', 'attrs'],
+  'typing-extensions': ['typing_extensions']
+};
+
+/**
+ * Dependencies that are used without being imported (tools, plugins, type stubs)
+ */
+const TOOLING = {
+  npm: /^(?:@types\/|eslint|prettier|typescript$|ts-node$|tsx$|jest|vitest|mocha|@vitest\/|@jest\/|babel|@babel\/|webpack|vite$|rollup|esbuild$|husky$|lint-staged$|nodemon$|concurrently$|rimraf$|cross-env$|@commitlint\/|stylelint|postcss|autoprefixer$|tailwindcss$)/,
+  python: /^(?:pytest|black$|ruff$|mypy$|flake8|pylint$|isort$|coverage$|tox$|pre-commit$|types-|sphinx|mkdocs|gunicorn$|uvicorn$|wheel$|setuptools$|build$|twine$|hatchling$)/,
+  rust: /^$/,
+  go: /^$/
+};
+
+/**
+ * Run a command and return stdout; outdated commands exit non-zero when
+ * something is outdated, so their stdout is kept
+ * @param {string} basePath - Project root
+ * @param {string[]} argv - Command and arguments
+ * @returns {string|null}
+ */
+function run(basePath, argv) {
+  return exec.run(basePath, argv, { timeout: 120000, keepOutput: true });
+}
+
+/**
+ * `run` without blocking: outdated commands of several managers and
+ * workspace packages overlap
+ * @param {string} basePath - Project root
+ * @param {string[]} argv - Command and arguments
+ * @returns {Promise<string|null>}
+ */
+function runAsync(basePath, argv) {
+  return runCommand(basePath, argv);
+}
+
+/**
+ * Read a project file
+ * @param {string} basePath - Project root
+ * @param {string} file - Relative path
+ * @returns {string|null}
+ */
+function readFile(basePath, file) {
+  try {
+    return fs.readFileSync(path.join(basePath, file), 'utf8');
+  } catch {
+    return null;
+  }
+}
+
+/**
+ * Package managers in use, one per ecosystem
+ * @param {string} basePath - Project root
+ * @returns {Array<{manager: string, ecosystem: string, file: string}>}
+ */
+function detectManagers(basePath) {
+  const found = [];
+  for (const entry of MANAGERS) {
+    if (found.some(existing => existing.ecosystem === entry.ecosystem)) continue;
+    if (fs.existsSync(path.join(basePath, entry.file))) found.push({ manager: entry.manager, ecosystem: entry.ecosystem, file: entry.file });
+  }
+  return found;
+}
+
+/**
+ * Required versions from go.mod, with the `// indirect` marker
+ * @param {string} content - go.mod content
+ * @returns {Map<string, {version: string, indirect: boolean}>}
+ */
+function parseGoRequires(content) {
+  const requires = new Map();
+  let inBlock = false;
+  for (const line of String(content || '').split('\n')) {
+    const text = line.trim();
+    if (/^require\s*\($/.test(text)) {
+      inBlock = true;
+      continue;
+    }
+    if (inBlock && text === ')') {
+      inBlock = false;
+      continue;
+    }
+    const match = inBlock ? text.match(/^(\S+)\s+(v\S+)(.*)$/) : text.match(/^require\s+(\S+)\s+(v\S+)(.*)$/);
+    if (match) requires.set(match[1], { version: match[2], indirect: /\/\/\s*indirect/.test(match[3]) });
+  }
+  return requires;
+}
+
+/**
+ * Locked version of a crate in Cargo.lock
+ * @param {string} content - Cargo.lock content
+ * @param {string} name - Crate name
+ * @returns {string|null}
+ */
+function cargoLockVersion(content, name) {
+  for (const block of String(content || '').split(/^\[\[package\]\]\s*$/m).slice(1)) {
+    const packageName = block.match(/^name\s*=\s*"([^"]+)"/m);
+    if (!packageName || packageName[1] !== name) continue;
+    const version = block.match(/^version\s*=\s*"([^"]+)"/m);
+    return version ? version[1] : null;
+  }
+  return null;
+}
+
+/**
+ * Declared dependencies with their locked versions
+ * @param {string} basePath - Project root
+ * @param {Object} [options]
+ * @param {string} [options.lockRoot] - Workspace root whose npm lockfile and Cargo.lock apply when the project has none
+ * @returns {Promise<Array<{ecosystem: string, name: string, file: string, version: string|null, dev: boolean, indirect: boolean}>>}
+ */
+async function readDependencies(basePath, options = {}) {
+  const declared = (await collectDependencies(basePath)).filter(dep => OSV_ECOSYSTEMS[dep.ecosystem]);
+
+  let pkg = {};
+  try {
+    pkg = JSON.parse(readFile(basePath, 'package.json') || '{}');
+  } catch {
+    pkg = {};
+  }
+  const sharedLock = file => readFile(basePath, file) || (options.lockRoot ? readFile(options.lockRoot, file) : null);
+  const npmLocks = ['package-lock.json', 'yarn.lock', 'pnpm-lock.yaml']
+    .map(file => ({ file, content: sharedLock(file) }))
+    .filter(lock => lock.content);
+  const pythonLocks = ['poetry.lock', 'uv.lock', 'Pipfile.lock']
+    .map(file => ({ file, content: readFile(basePath, file) }))
+    .filter(lock => lock.content);
+  const cargoLock = sharedLock('Cargo.lock');
+  const goRequires = parseGoRequires(readFile(basePath, 'go.mod'));
+
+  const seen = new Set();
+  const dependencies = [];
+  for (const dep of declared) {
+    const key = `${dep.ecosystem}:${dep.name}`;
+    if (seen.has(key)) continue;
+    seen.add(key);
+
+    let version = null;
+    let dev = false;
+    let indirect = false;
+    if (dep.ecosystem === 'npm') {
+      for (const lock of npmLocks) {
+        version = npmLockVersion(lock.file, lock.content, dep.name);
+        if (version) break;
+      }
+      dev = Boolean(pkg.devDependencies && pkg.devDependencies[dep.name]) &&
+        !(pkg.dependencies && pkg.dependencies[dep.name]);
+    } else if (dep.ecosystem === 'python') {
+      for (const lock of pythonLocks) {
+        version = pythonLockVersion(lock.file, lock.content, dep.name);
+        if (version) break;
+      }
+      if (!version && dep.file.endsWith('.txt')) {
+        const pinned = (readFile(basePath, dep.file) || '').split('\n')
+          .map(line => line.replace(/#.*/, '').match(/^\s*([A-Za-z0-9][\w.-]*)(?:\[[^\]]*\])?\s*==\s*([\w.]+)/))
+          .find(match => match && normalizePythonName(match[1]) === dep.name);
+        if (pinned) version = pinned[2];
+      }
+    } else if (dep.ecosystem === 'rust') {
+      version = cargoLockVersion(cargoLock, dep.name);
+    } else if (dep.ecosystem === 'go') {
+      const required = goRequires.get(dep.name);
+      version = required ? required.version : null;
+      indirect = Boolean(required && required.indirect);
+    }
+    dependencies.push({ ...dep, version, dev, indirect });
+  }
+  return dependencies;
+}
+
+/**
+ * Parse a package manager's outdated output
+ * @param {string} manager - Package manager
+ * @param {string} output - Command stdout
+ * @returns {Array<{name: string, current: string|null, wanted: string|null, latest: string}>}
+ */
+function parseOutdated(manager, output) {
+  const text = String(output || '').trim();
+  if (!text) return [];
+  const entry = (name, current, wanted, latest) => ({ name, current: current || null, wanted: wanted || null, latest });
+  const json = () => {
+    try {
+      return JSON.parse(text);
+    } catch {
+      return null;
+    }
+  };
+
+  if (manager === 'npm' || manager === 'pnpm') {
+    const data = json() || {};
+    // pnpm 9 returns an array; npm and older pnpm key by name
+    const items = Array.isArray(data) ? data.map(item => [item.packageName || item.name, item]) : Object.entries(data);
+    return items.filter(([, info]) => info && info.latest)
+      .map(([name, info]) => entry(name, info.current, info.wanted, info.latest));
+  }
+  if (manager === 'yarn') {
+    // yarn 1 prints NDJSON; the table row is [name, current, wanted, latest, type, url]
+    const outdated = [];
+    for (const line of text.split('\n')) {
+      let event;
+      try {
+        event = JSON.parse(line);
+      } catch {
+        continue;
+      }
+      if (event.type !== 'table') continue;
+      for (const row of event.data.body || []) outdated.push(entry(row[0], row[1], row[2], row[3]));
+    }
+    return outdated;
+  }
+  if (manager === 'pip') {
+    return (json() || []).map(item => entry(item.name, item.version, null, item.latest_version));
+  }
+  if (manager === 'poetry') {
+    // `name  current  latest  description`, `(!)` marks packages not installed
+    return text.split('\n').map(line => line.replace(/\(!\)\s*/, '').trim().split(/\s+/))
+      .filter(parts => parts.length >= 3 && /^\d/.test(parts[1]) && /^\d/.test(parts[2]))
+      .map(parts => entry(parts[0], parts[1], null, parts[2]));
+  }
+  if (manager === 'cargo') {
+    const data = json() || {};
+    return (data.dependencies || []).filter(dep => dep.latest && dep.latest !== dep.project && dep.latest !== 'Removed')
+      .map(dep => entry(dep.name, dep.project, dep.compat && dep.compat !== '---' ? dep.compat : null, dep.latest));
+  }
+  if (manager === 'go') {
+    // `go list -json` prints one object per module, not an array
+    const outdated = [];
+    for (const chunk of text.split(/\n(?=\{)/)) {
+      let mod;
+      try {
+        mod = JSON.parse(chunk);
+      } catch {
+        continue;
+      }
+      if (!mod.Main && !mod.Indirect && mod.Update) outdated.push(entry(mod.Path, mod.Version, null, mod.Update.Version));
+    }
+    return outdated;
+  }
+  return [];
+}
+
+/**
+ * Outdated packages for each detected package manager
+ * @param {string} basePath - Project root
+ * @param {Array<{manager: string, ecosystem: string}>} managers - Result of detectManagers
+ * @param {Function} [runCommand] - `(basePath, argv) => stdout|null`
+ * @returns {{outdated: Object[], skipped: string[]}} skipped lists managers whose command failed
+ */
+function getOutdated(basePath, managers, runCommand = run) {
+  return outdatedFrom(managers, managers.map(({ manager }) => runCommand(basePath, OUTDATED_COMMANDS[manager])));
+}
+
+/**
+ * Outdated packages with the commands running side by side
+ * @param {string} basePath - Project root
+ * @param {Array<{manager: string, ecosystem: string}>} managers - Result of detectManagers
+ * @param {Function} [runCommand] - `(basePath, argv) => stdout|null`, or a promise of it
+ * @returns {Promise<{outdated: Object[], skipped: string[]}>}
+ */
+async function collectOutdated(basePath, managers, runCommand = runAsync) {
+  return outdatedFrom(managers, await Promise.all(managers.map(({ manager }) => runCommand(basePath, OUTDATED_COMMANDS[manager]))));
+}
+
+/**
+ * Parse the output of each manager's outdated command
+ * @param {Array<{manager: string, ecosystem: string}>} managers - Managers the outputs belong to
+ * @param {Array<string|null>} outputs - Output per manager, null when the command did not run
+ * @returns {{outdated: Object[], skipped: string[]}}
+ */
+function outdatedFrom(managers, outputs) {
+  const outdated = [];
+  const skipped = [];
+  managers.forEach(({ manager, ecosystem }, i) => {
+    if (outputs[i] === null) {
+      skipped.push(manager);
+      return;
+    }
+    for (const item of parseOutdated(manager, outputs[i])) {
+      outdated.push({ ecosystem, manager, ...item, major: majorOf(item.latest) !== majorOf(item.current) });
+    }
+  });
+  return { outdated, skipped };
+}
+
+/**
+ * Leading version number, for spotting major upgrades
+ * @param {string|null} version
+ * @returns {string|null}
+ */
+function majorOf(version) {
+  const match = String(version || '').match(/(\d+)/);
+  return match ? match[1] : null;
+}
+
+/**
+ * POST or GET JSON against the OSV API
+ * @param {string} method - HTTP method
+ * @param {string} pathname - API path
+ * @param {Object} [body] - JSON body
+ * @returns {Promise<Object>}
+ */
+function osvRequest(method, pathname, body) {
+  return new Promise((resolve, reject) => {
+    const payload = body ? JSON.stringify(body) : null;
+    const req = https.request({
+      hostname: 'api.osv.dev',
+      path: pathname,
+      method,
+      headers: payload ? { 'Content-Type': 'application/json', 'Content-Length': Buffer.byteLength(payload) } : {},
+      timeout: 30000
+    }, res => {
+      let data = '';
+      res.setEncoding('utf8');
+      res.on('data', chunk => { data += chunk; });
+      res.on('end', () => {
+        if (res.statusCode >= 400) {
+          reject(new Error(`OSV ${pathname} returned ${res.statusCode}`));
+          return;
+        }
+        try {
+          resolve(JSON.parse(data));
+        } catch (error) {
+          reject(error);
+        }
+      });
+    });
+    req.on('timeout', () => req.destroy(new Error('OSV request timed out')));
+    req.on('error', reject);
+    if (payload) req.write(payload);
+    req.end();
+  });
+}
+
+/**
+ * Severity label from an OSV record
+ * GitHub advisories carry `database_specific.severity`; others are `unknown`.
+ * @param {Object} vuln - OSV vulnerability
+ * @returns {string} critical, high, moderate, low, or unknown
+ */
+function osvSeverity(vuln) {
+  const label = vuln.database_specific && vuln.database_specific.severity;
+  return label ? String(label).toLowerCase() : 'unknown';
+}
+
+/**
+ * First fixed version for a package in an OSV record
+ * @param {Object} vuln - OSV vulnerability
+ * @param {string} name - Package name
+ * @returns {string|null}
+ */
+function fixedVersion(vuln, name) {
+  for (const affected of vuln.affected || []) {
+    if (affected.package && affected.package.name !== name) continue;
+    for (const range of affected.ranges || []) {
+      const fixed = (range.events || []).find(event => event.fixed);
+      if (fixed) return fixed.fixed;
+    }
+  }
+  return null;
+}
+
+/**
+ * Known vulnerabilities for locked dependency versions
+ * Dependencies without a locked version are not queried. With a cache, the
+ * ids found per package version and the vulnerability records are reused
+ * until the `osv` namespace TTL runs out.
+ * @param {Object[]} dependencies - Result of readDependencies
+ * @param {Object} [options]
+ * @param {Function} [options.request] - `(method, path, body) => Promise<Object>`, defaults to the OSV API
+ * @param {Object} [options.cache] - Result of `cache.createCache(basePath, 'osv')`
+ * @returns {Promise<Array<{ecosystem: string, name: string, version: string, id: string, aliases: string[], summary: string, severity: string, fixed: string|null}>>}
+ */
+async function queryVulnerabilities(dependencies, options = {}) {
+  const request = options.request || osvRequest;
+  const cache = options.cache || null;
+  const queryOf = dep => ({ package: { name: dep.name, ecosystem: OSV_ECOSYSTEMS[dep.ecosystem] }, version: dep.version.replace(/^v(?=\d)/, '') });
+  const queryKey = dep => `query:${dep.ecosystem}:${dep.name}@${dep.version}`;
+  const queried = dependencies.filter(dep => dep.version && OSV_ECOSYSTEMS[dep.ecosystem]);
+  const vulnerabilities = [];
+  const details = new Map();
+
+  const uncached = [];
+  for (const dep of queried) {
+    const ids = cache ? cache.get(queryKey(dep)) : undefined;
+    if (!Array.isArray(ids)) uncached.push(dep);
+    else ids.forEach(id => vulnerabilities.push({ dep, id }));
+  }
+
+  // querybatch accepts up to 1000 queries and returns ids only
+  for (let start = 0; start < uncached.length; start += 1000) {
+    const batch = uncached.slice(start, start + 1000);
+    const response = await request('POST', '/v1/querybatch', { queries: batch.map(queryOf) });
+    batch.forEach((dep, i) => {
+      const ids = ((response.results || [])[i]?.vulns || []).map(vuln => vuln.id);
+      ids.forEach(id => vulnerabilities.push({ dep, id }));
+      if (cache && response.results) cache.set(queryKey(dep), ids);
+    });
+  }
+
+  for (const { id } of vulnerabilities) {
+    if (details.has(id)) continue;
+    const cached = cache ? cache.get(`vuln:${id}`) : undefined;
+    const vuln = cached !== undefined ? cached : await request('GET', `/v1/vulns/${encodeURIComponent(id)}`);
+    if (cache && cached === undefined) cache.set(`vuln:${id}`, vuln);
+    details.set(id, vuln);
+  }
+
+  const severityOrder = ['critical', 'high', 'moderate', 'medium', 'low', 'unknown'];
+  return vulnerabilities.map(({ dep, id }) => {
+    const vuln = details.get(id) || {};
+    return {
+      ecosystem: dep.ecosystem,
+      name: dep.name,
+      version: dep.version,
+      id,
+      aliases: vuln.aliases || [],
+      summary: vuln.summary || '',
+      severity: osvSeverity(vuln),
+      fixed: fixedVersion(vuln, dep.name)
+    };
+  }).sort((a, b) => severityOrder.indexOf(a.severity) - severityOrder.indexOf(b.severity) || a.name.localeCompare(b.name));
+}
+
+/**
+ * Package a JS import specifier belongs to
+ * @param {string} source - Import specifier
+ * @returns {string|null} null for relative, absolute, and builtin imports
+ */
+function npmPackageOf(source) {
+  if (!source || /^[./]|^node:|^#/.test(source)) return null;
+  const parts = source.split('/');
+  return source.startsWith('@') ? parts.slice(0, 2).join('/') : parts[0];
+}
+
+/**
+ * Declared dependencies that no mapped file imports
+ * npm dependencies named in package.json scripts count as used; known
+ * tooling (linters, test runners, type stubs) and indirect Go modules are
+ * not reported. Unused dev dependencies get `low` confidence.
+ * @param {Object[]} dependencies - Result of readDependencies
+ * @param {Object} map - Repo map
+ * @param {Object} [options]
+ * @param {string} [options.scripts] - package.json scripts text
+ * @returns {Array<{ecosystem: string, name: string, file: string, dev: boolean, confidence: string}>}
+ */
+function findUnused(dependencies, map, options = {}) {
+  if (!map || !map.files) return [];
+  const imported = { npm: new Set(), python: new Set(), rust: new Set(), go: [] };
+  const languages = new Set();
+
+  for (const fileData of Object.values(map.files)) {
+    languages.add(fileData.language);
+    for (const imp of fileData.imports || []) {
+      const source = String(imp.source || '');
+      if (fileData.language === 'javascript' || fileData.language === 'typescript') {
+        const name = npmPackageOf(source);
+        if (name) imported.npm.add(name);
+      } else if (fileData.language === 'python') {
+        if (!source.startsWith('.')) imported.python.add(source.split('.')[0].toLowerCase());
+      } else if (fileData.language === 'rust') {
+        imported.rust.add(source.replace(/^::/, '').split('::')[0]);
+      } else if (fileData.language === 'go') {
+        imported.go.push(source);
+      }
+    }
+  }
+
+  const scannedLanguage = {
+    npm: languages.has('javascript') || languages.has('typescript'),
+    python: languages.has('python'),
+    rust: languages.has('rust'),
+    go: languages.has('go')
+  };
+  const scripts = options.scripts || '';
+
+  const used = dep => {
+    switch (dep.ecosystem) {
+      case 'npm':
+        return imported.npm.has(dep.name) ||
+          (dep.name.startsWith('@types/') && imported.npm.has(dep.name.slice(7).replace(/^(.+?)__(.+)$/, '@$1/$2'))) ||
+          new RegExp(`(?:^|[\\s"'/])${dep.name.replace(/[.*+?^${}()|[\]\\/]/g, '\\$&')}(?:$|[\\s"'/@])`).test(scripts);
+      case 'python': {
+        const names = (PYTHON_IMPORT_NAMES[dep.name] || [dep.name.replace(/-/g, '_'), dep.name.replace(/^python-|^py-/, '').replace(/-/g, '_')])
+          .map(name => name.toLowerCase());
+        return names.some(name => imported.python.has(name));
+      }
+      case 'rust':
+        return imported.rust.has(dep.name.replace(/-/g, '_'));
+      case 'go':
+        return imported.go.some(source => source === dep.name || source.startsWith(`${dep.name}/`));
+      default:
+        return true;
+    }
+  };
+
+  return dependencies
+    .filter(dep => scannedLanguage[dep.ecosystem] && !dep.indirect && !TOOLING[dep.ecosystem].test(dep.name) && !used(dep))
+    .map(dep => ({
+      ecosystem: dep.ecosystem,
+      name: dep.name,
+      file: dep.file,
+      dev: dep.dev,
+      // Rust crates are often used by path (`serde_json::json!`) without `use`
+      confidence: dep.dev || dep.ecosystem === 'rust' ? 'low' : 'high'
+    }));
+}
+
+/**
+ * Audit dependencies
+ * @param {string} basePath - Project root
+ * @param {Object} [options]
+ * @param {Object} [options.map] - Repo map; unused detection is skipped without it
+ * @param {boolean} [options.offline] - Skip the OSV lookup
+ * @param {boolean} [options.outdated=true] - Run the outdated commands
+ * @param {Function} [options.request] - OSV request function (tests)
+ * @param {Object} [options.cache] - Result of `cache.createCache(basePath, 'osv')`
+ * @param {Function} [options.run] - Command runner (tests)
+ * @returns {Promise<Object>} `{success, managers, dependencies, outdated, vulnerabilities, unused, skipped, errors}`
+ */
+async function auditDependencies(basePath, options = {}) {
+  const managers = detectManagers(basePath);
+  if (managers.length === 0) {
+    return { success: false, error: 'No npm, Python, Cargo, or Go manifest found' };
+  }
+
+  const dependencies = await readDependencies(basePath);
+  const errors = [];
+
+  const [{ outdated, skipped }, vulnerabilities] = await Promise.all([
+    options.outdated === false ? { outdated: [], skipped: [] } : collectOutdated(basePath, managers, options.run),
+    options.offline
+      ? []
+      : queryVulnerabilities(dependencies, { request: options.request, cache: options.cache }).catch(error => {
+        errors.push(`OSV lookup failed: ${error.message}`);
+        return [];
+      })
+  ]);
+
+  return buildReport(basePath, { managers, dependencies, outdated, skipped, vulnerabilities, map: options.map, errors });
+}
+
+/**
+ * Assemble an audit report from its parts
+ * @param {string} basePath - Project root
+ * @param {Object} parts - `{managers, dependencies, outdated, skipped, vulnerabilities, map, errors}`
+ * @returns {Object} See auditDependencies
+ */
+function buildReport(basePath, parts) {
+  let scripts = '';
+  try {
+    scripts = JSON.stringify(JSON.parse(readFile(basePath, 'package.json') || '{}').scripts || {});
+  } catch {
+    scripts = '';
+  }
+  const unused = findUnused(parts.dependencies, parts.map, { scripts });
+
+  return {
+    success: true,
+    managers: parts.managers.map(entry => entry.manager),
+    dependencies: parts.dependencies.length,
+    unlocked: parts.dependencies.filter(dep => !dep.version).map(dep => dep.name),
+    outdated: parts.outdated,
+    vulnerabilities: parts.vulnerabilities,
+    unused: parts.map ? unused : null,
+    skipped: parts.skipped,
+    errors: parts.errors
+  };
+}
+
+/**
+ * Audit every workspace package of a monorepo
+ * Manifest reads and outdated commands run as parallel tasks per package;
+ * one OSV query covers the locked versions of all packages once they are
+ * read. Packages without an npm or Cargo lockfile of their own use the
+ * root one.
+ * @param {string} basePath - Repository root
+ * @param {Array<{name: string, path: string}>} packages - Workspace packages (`detection.monorepo.packages`)
+ * @param {Object} [options] - auditDependencies options, plus:
+ * @param {number} [options.concurrency] - Tasks at once (default: CPU count, capped at 8)
+ * @param {Function} [options.onProgress] - Progress handler, e.g. `taskRunner.createProgress(process.stderr)`
+ * @returns {Promise<Object>} `{success, packages: [{name, path, report}], dependencies, vulnerabilities, outdated, unused, run, errors}`
+ */
+async function auditWorkspaces(basePath, packages, options = {}) {
+  const targets = [{ name: '(root)', path: '.' }, ...packages]
+    .filter(pkg => detectManagers(path.join(basePath, pkg.path)).length > 0);
+  if (targets.length === 0) {
+    return { success: false, error: 'No npm, Python, Cargo, or Go manifest found' };
+  }
+
+  const tasks = [];
+  for (const pkg of targets) {
+    const dir = path.join(basePath, pkg.path);
+    tasks.push({ id: `read:${pkg.path}`, label: `${pkg.path} manifests`, run: () => readDependencies(dir, { lockRoot: basePath }) });
+    if (options.outdated !== false) {
+      tasks.push({ id: `outdated:${pkg.path}`, label: `${pkg.path} outdated`, run: () => collectOutdated(dir, detectManagers(dir), options.run) });
+    }
+  }
+  const depKey = dep => `${dep.ecosystem}:${dep.name}@${dep.version}`;
+  if (!options.offline) {
+    tasks.push({
+      id: 'osv',
+      label: 'OSV lookup',
+      deps: targets.map(pkg => `read:${pkg.path}`),
+      run: ({ results }) => {
+        const unique = new Map(targets.flatMap(pkg => results[`read:${pkg.path}`]).map(dep => [depKey(dep), dep]));
+        return queryVulnerabilities(Array.from(unique.values()), { request: options.request, cache: options.cache });
+      }
+    });
+  }
+
+  const run = await runTasks(tasks, { concurrency: options.concurrency, onProgress: options.onProgress });
+  if (run.error) return { success: false, error: run.error };
+  const failure = id => run.tasks.find(task => task.id === id && task.status !== 'done');
+  const errors = [];
+  const osvFailed = failure('osv');
+  if (osvFailed) errors.push(`OSV lookup ${osvFailed.status === 'failed' ? 'failed' : 'skipped'}: ${osvFailed.error}`);
+  const vulnerabilities = run.results.osv || [];
+
+  const reports = targets.map(pkg => {
+    const read = failure(`read:${pkg.path}`);
+    if (read) return { name: pkg.name, path: pkg.path, report: { success: false, error: `Could not read manifests: ${read.error}` } };
+    const dir = path.join(basePath, pkg.path);
+    const dependencies = run.results[`read:${pkg.path}`];
+    const keys = new Set(dependencies.map(depKey));
+    const prefix = pkg.path === '.' ? '' : `${pkg.path.replace(/\/$/, '')}/`;
+    const map = options.map && options.map.files
+      ? { ...options.map, files: Object.fromEntries(Object.entries(options.map.files).filter(([file]) => file.startsWith(prefix))) }
+      : options.map;
+    const outdatedFailed = failure(`outdated:${pkg.path}`);
+    const report = buildReport(dir, {
+      managers: detectManagers(dir),
+      dependencies,
+      ...(run.results[`outdated:${pkg.path}`] || { outdated: [], skipped: [] }),
+      vulnerabilities: vulnerabilities.filter(vuln => keys.has(depKey(vuln))),
+      map,
+      errors: outdatedFailed ? [`Outdated commands failed: ${outdatedFailed.error}`] : []
+    });
+    return { name: pkg.name, path: pkg.path, report };
+  });
+
+  const ok = reports.filter(entry => entry.report.success).map(entry => entry.report);
+  return {
+    success: true,
+    packages: reports,
+    dependencies: ok.reduce((sum, report) => sum + report.dependencies, 0),
+    vulnerabilities: new Set(vulnerabilities.map(vuln => `${depKey(vuln)}:${vuln.id}`)).size,
+    outdated: ok.reduce((sum, report) => sum + report.outdated.length, 0),
+    unused: options.map ? ok.reduce((sum, report) => sum + report.unused.length, 0) : null,
+    run: { concurrency: run.concurrency, durationMs: run.durationMs, summary: renderSummary(run) },
+    errors
+  };
+}
+
+/**
+ * Render an audit as Markdown
+ * @param {Object} report - Result of auditDependencies
+ * @returns {string}
+ */
+function renderReport(report) {
+  const lines = [
+    '## Dependency Audit',
+    '',
+    `**Managers**: ${report.managers.join(', ')}`,
+    `**Dependencies**: ${report.dependencies} declared (${report.unlocked.length} without a locked version)`,
+    `**Vulnerable**: ${report.vulnerabilities.length} | **Outdated**: ${report.outdated.length} | **Unused**: ${report.unused ? report.unused.length : 'not checked (no repo map)'}`,
+    ''
+  ];
+
+  if (report.vulnerabilities.length > 0) {
+    lines.push('### Vulnerabilities', '', '| Severity | Package | Version | Advisory | Fixed in |', '|----------|---------|---------|----------|----------|');
+    for (const vuln of report.vulnerabilities) {
+      const id = [vuln.id, ...vuln.aliases.filter(alias => alias.startsWith('CVE-'))].join(', ');
+      lines.push(`| ${vuln.severity} | ${vuln.name} | ${vuln.version} | ${id}${vuln.summary ? `: ${vuln.summary}` : ''} | ${vuln.fixed || '-'} |`);
+    }
+    lines.push('');
+  }
+  if (report.outdated.length > 0) {
+    lines.push('### Outdated', '', '| Package | Current | Latest | Manager |', '|---------|---------|--------|---------|');
+    for (const item of report.outdated) {
+      lines.push(`| ${item.name}${item.major ? ' (major)' : ''} | ${item.current || '-'} | ${item.latest} | ${item.manager} |`);
+    }
+    lines.push('');
+  }
+  if (report.unused && report.unused.length > 0) {
+    lines.push('### Unused', '', '| Package | Declared in | Confidence |', '|---------|-------------|------------|');
+    for (const item of report.unused) {
+      lines.push(`| ${item.name}${item.dev ? ' (dev)' : ''} | ${item.file} | ${item.confidence} |`);
+    }
+    lines.push('');
+  }
+  for (const note of [
+    ...report.skipped.map(manager => `\`${OUTDATED_COMMANDS[manager].join(' ')}\` failed or is not installed; outdated ${manager} packages not listed`),
+    ...report.errors
+  ]) {
+    lines.push(`- ${note}`);
+  }
+
+  return `${lines.join('\n').trim()}\n`;
+}
+
+/**
+ * Render a workspace audit as Markdown
+ * A summary row per package, then the details of packages with findings.
+ * @param {Object} result - Result of auditWorkspaces
+ * @returns {string}
+ */
+function renderWorkspaceReport(result) {
+  const lines = [
+    `## Dependency Audit (${result.packages.length} packages)`,
+    '',
+    `**Dependencies**: ${result.dependencies} declared`,
+    `**Vulnerable**: ${result.vulnerabilities} | **Outdated**: ${result.outdated} | **Unused**: ${result.unused === null ? 'not checked (no repo map)' : result.unused}`,
+    `**Run**: ${result.run.summary}`,
+    '',
+    '| Package | Path | Vulnerable | Outdated | Unused |',
+    '|---------|------|------------|----------|--------|'
+  ];
+  for (const { name, path: pkgPath, report } of result.packages) {
+    if (!report.success) {
+      lines.push(`| ${name} | ${pkgPath} | - | - | ${report.error} |`);
+      continue;
+    }
+    lines.push(`| ${name} | ${pkgPath} | ${report.vulnerabilities.length} | ${report.outdated.length} | ${report.unused ? report.unused.length : '-'} |`);
+  }
+  lines.push('');
+
+  for (const { name, path: pkgPath, report } of result.packages) {
+    if (!report.success) continue;
+    if (report.vulnerabilities.length + report.outdated.length + (report.unused || []).length + report.skipped.length + report.errors.length === 0) continue;
+    lines.push(renderReport(report).replace(/^### /gm, '#### ').replace(/^## Dependency Audit/, `### ${name} (\`${pkgPath}\`)`).trim(), '');
+  }
+  for (const error of result.errors) lines.push(`- ${error}`);
+
+  return `${lines.join('\n').trim()}\n`;
+}
+
+// When run directly, output JSON
+if (require.main === module) {
+  const args = process.argv.slice(2);
+  const basePath = process.cwd();
+  const map = require('../repo-map').load(basePath);
+  const { detectWorkspaces } = require('../platform/detect-platform');
+  const { concurrency, error } = parseConcurrency(args);
+  if (error) {
+    console.error(error);
+    process.exit(1);
+  }
+  const options = {
+    map,
+    offline: args.includes('--offline'),
+    cache: args.includes('--no-cache') ? null : createCache(basePath, 'osv'),
+    concurrency,
+    onProgress: createProgress(process.stderr)
+  };
+  detectWorkspaces()
+    .catch(() => null)
+    .then(monorepo => (monorepo && monorepo.packages.length > 0 && !args.includes('--root')
+      ? auditWorkspaces(basePath, monorepo.packages, options)
+      : auditDependencies(basePath, options)))
+    .then(report => {
+      const indent = process.stdout.isTTY ? 2 : 0;
+      console.log(JSON.stringify(report, null, indent));
+      if (!report.success) process.exitCode = 1;
+    });
+}
+
+module.exports = {
+  OSV_ECOSYSTEMS,
+  OUTDATED_COMMANDS,
+  detectManagers,
+  parseGoRequires,
+  cargoLockVersion,
+  readDependencies,
+  parseOutdated,
+  getOutdated,
+  collectOutdated,
+  queryVulnerabilities,
+  findUnused,
+  auditDependencies,
+  auditWorkspaces,
+  renderReport,
+  renderWorkspaceReport
+};
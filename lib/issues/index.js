@@ -0,0 +1,725 @@
+#!/usr/bin/env node
+/**
+ * Findings to Tracker Issues
+ *
+ * Files scanner findings as GitHub or GitLab issues without duplicates.
+ * Reads slop detection output (`detect.js` JSON, secrets included), review
+ * queues (/audit-project security and other passes), /deps-audit reports,
+ * and SARIF from any tool. Each finding gets a fingerprint that does not
+ * depend on its line number; the fingerprint goes into the issue body, so a
+ * re-run updates the matching issue instead of opening another one.
+ * Existing issues are found through the `awesome-slash` label every filed
+ * issue carries. Assignees come from CODEOWNERS.
+ *
+ * Usage: node lib/issues/index.js <findings.json>... [--min-severity <level>] [--limit N]
+ * Output: JSON plan (nothing is filed; the /issue command applies it)
+ *
+ * @module lib/issues
+ */
+
+const crypto = require('crypto');
+const fs = require('fs');
+const path = require('path');
+
+const { loadConfig } = require('../config');
+const { getRepository } = require('../changelog');
+const { keyFindings, fingerprintFinding } = require('../patterns/baseline');
+const { slopPatterns } = require('../patterns/slop-patterns');
+const { CODEOWNERS_FILES, parseCodeowners, readCodeowners, ownersFor } = require('../utils/codeowners');
+const exec = require('../utils/exec');
+
+const CONFIG_KEY = 'issues';
+
+/**
+ * Label on every filed issue; existing issues are listed by it
+ */
+const TRACKING_LABEL = 'awesome-slash';
+
+const SEVERITIES = ['critical', 'high', 'medium', 'low'];
+
+const DEFAULTS = { minSeverity: 'medium', limit: 20, assign: true };
+
+/**
+ * Scanner severities and SARIF levels -> issue severity
+ */
+const SEVERITY_ALIASES = {
+  critical: 'critical',
+  high: 'high',
+  error: 'high',
+  medium: 'medium',
+  moderate: 'medium',
+  warning: 'medium',
+  unknown: 'medium',
+  low: 'low',
+  note: 'low',
+  explain: 'low',
+  none: 'low'
+};
+
+/**
+ * Label per finding source
+ */
+const SOURCE_LABELS = {
+  slop: 'slop',
+  security: 'security',
+  dependencies: 'dependencies',
+  review: 'code-review'
+};
+
+const LABEL_COLORS = {
+  critical: 'b60205',
+  high: 'd93f0b',
+  medium: 'fbca04',
+  low: '0e8a16',
+  [TRACKING_LABEL]: '5319e7'
+};
+
+const TITLE_LENGTH = 120;
+const MAX_ASSIGNEES = 10;
+const GITLAB_PAGE_SIZE = 100;
+
+// <!-- awesome-slash:issue fingerprint=0123456789abcdef source=slop -->
+const MARKER = /<!-- awesome-slash:issue fingerprint=([0-9a-f]{16}) source=([\w-]+) -->/;
+
+/**
+ * Run a command and return stdout, or null on failure
+ * @param {string} basePath - Repository root
+ * @param {string[]} argv - Command and arguments
+ * @returns {string|null}
+ */
+function run(basePath, argv) {
+  return exec.run(basePath, argv, { timeout: 120000 });
+}
+
+/**
+ * 16-char hex digest of the parts
+ * @param {...string} parts
+ * @returns {string}
+ */
+function hash(...parts) {
+  return crypto.createHash('sha256').update(parts.join('\0')).digest('hex').slice(0, 16);
+}
+
+/**
+ * Normalize a scanner severity
+ * @param {string} severity - Scanner severity or SARIF level
+ * @returns {string} One of SEVERITIES
+ */
+function normalizeSeverity(severity) {
+  return SEVERITY_ALIASES[String(severity || '').toLowerCase()] || 'medium';
+}
+
+/**
+ * Findings from slop pipeline output
+ * Secrets-category findings become `security` findings; their flagged
+ * content never goes into an issue.
+ * @param {string} basePath - Repository root
+ * @param {Object[]} findings - Pipeline findings
+ * @returns {Object[]} Normalized findings
+ */
+function fromSlop(basePath, findings) {
+  return keyFindings(basePath, findings.filter(finding => finding && finding.file)).map(({ file, patternName, fingerprint, finding }) => {
+    const pattern = slopPatterns[patternName] || {};
+    const source = pattern.category === 'secrets' ? 'security' : 'slop';
+    return {
+      source,
+      tool: 'deslop',
+      rule: patternName || 'unknown',
+      severity: normalizeSeverity(finding.severity),
+      message: finding.description || pattern.description || patternName,
+      file,
+      line: finding.line || null,
+      fingerprint: hash(source, file, patternName, fingerprint)
+    };
+  });
+}
+
+/**
+ * Findings from a review queue
+ * @param {string} basePath - Repository root
+ * @param {Object[]} items - Review findings ({file, line, severity, category|pass, description, suggestion})
+ * @returns {Object[]} Normalized findings
+ */
+function fromReview(basePath, items) {
+  const kept = items.filter(item => item && item.file && !item.falsePositive);
+  const keyed = keyFindings(basePath, kept.map(item => ({ ...item, patternName: `review/${item.category || item.pass || 'general'}`, content: item.description })));
+  return keyed.map(({ file, patternName, fingerprint, finding }) => {
+    const category = patternName.slice('review/'.length);
+    const source = category === 'security' ? 'security' : 'review';
+    return {
+      source,
+      tool: 'review',
+      rule: patternName,
+      severity: normalizeSeverity(finding.severity),
+      message: finding.description || `${category} review finding`,
+      suggestion: finding.suggestion || null,
+      file,
+      line: finding.line || null,
+      fingerprint: hash(source, file, patternName, fingerprint)
+    };
+  });
+}
+
+/**
+ * Findings from a /deps-audit report
+ * Vulnerabilities are keyed by package and advisory, not version, so an
+ * upgrade that is still affected updates the same issue.
+ * @param {Object} report - Result of deps.auditDependencies
+ * @returns {Object[]} Normalized findings
+ */
+function fromDeps(report) {
+  const findings = (report.vulnerabilities || []).map(vuln => ({
+    source: 'dependencies',
+    tool: 'deps-audit',
+    rule: vuln.id,
+    severity: normalizeSeverity(vuln.severity),
+    message: `${vuln.name}@${vuln.version} (${vuln.ecosystem}) is affected by ${[vuln.id, ...(vuln.aliases || [])].join(', ')}${vuln.summary ? `: ${vuln.summary}` : ''}`,
+    suggestion: vuln.fixed ? `Upgrade ${vuln.name} to ${vuln.fixed} or later.` : 'No fixed version is published yet.',
+    title: `${vuln.name}: ${vuln.id}${vuln.summary ? ` ${vuln.summary}` : ''}`,
+    labels: ['security'],
+    file: null,
+    line: null,
+    fingerprint: hash('dependencies', vuln.ecosystem, vuln.name, vuln.id)
+  }));
+  for (const dep of report.unused || []) {
+    findings.push({
+      source: 'dependencies',
+      tool: 'deps-audit',
+      rule: 'unused-dependency',
+      severity: 'low',
+      message: `${dep.name} is declared${dep.dev ? ' as a dev dependency' : ''} in ${dep.file} but no file imports it (${dep.confidence} confidence).`,
+      suggestion: `Remove ${dep.name} from ${dep.file} if nothing loads it dynamically.`,
+      title: `Unused dependency ${dep.name}`,
+      file: dep.file || null,
+      line: null,
+      fingerprint: hash('dependencies', dep.ecosystem, dep.name, 'unused')
+    });
+  }
+  return findings;
+}
+
+/**
+ * Findings from a SARIF log
+ * A result's `partialFingerprints` are reused when present; otherwise the
+ * flagged line's content is hashed like the slop baseline does.
+ * @param {string} basePath - Repository root
+ * @param {Object} log - SARIF 2.1.0 log
+ * @returns {Object[]} Normalized findings
+ */
+function fromSarif(basePath, log) {
+  const findings = [];
+  const cache = new Map();
+  const linesOf = file => {
+    if (!cache.has(file)) {
+      try {
+        cache.set(file, fs.readFileSync(path.join(basePath, file), 'utf8').split('\n'));
+      } catch {
+        cache.set(file, null);
+      }
+    }
+    return cache.get(file);
+  };
+
+  for (const sarifRun of log.runs || []) {
+    const driver = sarifRun.tool?.driver || {};
+    const tool = driver.name || 'sarif';
+    const rules = new Map((driver.rules || []).map(rule => [rule.id, rule]));
+    for (const result of sarifRun.results || []) {
+      const rule = rules.get(result.ruleId) || (driver.rules || [])[result.ruleIndex] || {};
+      const ruleId = result.ruleId || rule.id || 'unknown';
+      const physical = result.locations?.[0]?.physicalLocation || {};
+      const file = physical.artifactLocation?.uri
+        ? decodeURIComponent(physical.artifactLocation.uri).replace(/^file:\/\//, '').replace(/^\.?\//, '')
+        : null;
+      const line = physical.region?.startLine || null;
+      const tags = rule.properties?.tags || [];
+      const source = tool === 'deslop' ? 'slop'
+        : tags.includes('security') || rule.properties?.['security-severity'] ? 'security'
+          : tool.toLowerCase().replace(/[^\w-]+/g, '-');
+      const partial = Object.values(result.partialFingerprints || result.fingerprints || {})[0];
+      const inner = partial || fingerprintFinding({ patternName: ruleId, line, content: result.message?.text }, file ? linesOf(file) : null);
+      findings.push({
+        source,
+        tool,
+        rule: ruleId,
+        severity: normalizeSeverity(result.properties?.severity || result.level || rule.defaultConfiguration?.level || 'warning'),
+        message: result.message?.text || rule.shortDescription?.text || ruleId,
+        file,
+        line,
+        fingerprint: hash(source, file || '', ruleId, inner)
+      });
+    }
+  }
+  return findings;
+}
+
+/**
+ * Normalize scanner output of any supported format
+ * @param {string} basePath - Repository root
+ * @param {Object|Object[]} data - Parsed JSON
+ * @returns {Object[]|null} Findings, or null when the format is not recognized
+ */
+function parseFindings(basePath, data) {
+  if (!data || typeof data !== 'object') return null;
+  if (Array.isArray(data.runs)) return fromSarif(basePath, data);
+  if (Array.isArray(data.vulnerabilities) && Array.isArray(data.managers)) return fromDeps(data);
+  const list = Array.isArray(data) ? data : data.findings || data.items;
+  if (!Array.isArray(list)) return null;
+  return list.some(item => item && item.patternName) ? fromSlop(basePath, list) : fromReview(basePath, list);
+}
+
+/**
+ * Merge findings that share a fingerprint
+ * The same line flagged twice (or the same advisory in two files) is one issue.
+ * @param {Object[]} findings - Normalized findings
+ * @returns {Object[]} Unique findings with `occurrences`, most severe first
+ */
+function dedupeFindings(findings) {
+  const unique = new Map();
+  for (const finding of findings) {
+    const existing = unique.get(finding.fingerprint);
+    if (existing) {
+      existing.occurrences++;
+      if (SEVERITIES.indexOf(finding.severity) < SEVERITIES.indexOf(existing.severity)) existing.severity = finding.severity;
+    } else {
+      unique.set(finding.fingerprint, { ...finding, occurrences: 1 });
+    }
+  }
+  return [...unique.values()].sort((a, b) =>
+    SEVERITIES.indexOf(a.severity) - SEVERITIES.indexOf(b.severity) ||
+    String(a.file).localeCompare(String(b.file)) || (a.line || 0) - (b.line || 0));
+}
+
+/**
+ * Assignable owners of a path (last matching rule wins)
+ * Teams (`@org/team`, GitLab groups) and email owners cannot be assigned
+ * and are left out.
+ * @param {Object[]} rules - Result of parseCodeowners
+ * @param {string|null} file - Path relative to the root; null matches only catch-all rules
+ * @returns {string[]} Usernames without `@`
+ */
+function ownersOf(rules, file) {
+  return ownersFor(rules, file)
+    .filter(owner => /^@[\w.-]+$/.test(owner))
+    .map(owner => owner.slice(1))
+    .slice(0, MAX_ASSIGNEES);
+}
+
+/**
+ * Read `issues` settings from the project config
+ * @param {string} basePath - Repository root
+ * @returns {{labels: string[], minSeverity: string, limit: number, assign: boolean, error: string|null}}
+ */
+function readSettings(basePath) {
+  const { config, file, error } = loadConfig(basePath);
+  const settings = { labels: [], ...DEFAULTS, error };
+  const value = config[CONFIG_KEY];
+  if (error || value === undefined) return settings;
+
+  if (!value || typeof value !== 'object' || Array.isArray(value)) {
+    return { ...settings, error: `${file}: ${CONFIG_KEY} must be an object` };
+  }
+  if (value.labels !== undefined) {
+    if (![].concat(value.labels).every(label => typeof label === 'string')) {
+      return { ...settings, error: `${file}: ${CONFIG_KEY}.labels must be a string or an array of strings` };
+    }
+    settings.labels = [].concat(value.labels);
+  }
+  if (value.minSeverity !== undefined) {
+    if (!SEVERITIES.includes(value.minSeverity)) {
+      return { ...settings, error: `${file}: ${CONFIG_KEY}.minSeverity must be one of ${SEVERITIES.join(', ')}` };
+    }
+    settings.minSeverity = value.minSeverity;
+  }
+  if (value.limit !== undefined) {
+    if (!Number.isInteger(value.limit) || value.limit < 1) {
+      return { ...settings, error: `${file}: ${CONFIG_KEY}.limit must be a positive integer` };
+    }
+    settings.limit = value.limit;
+  }
+  if (value.assign !== undefined) {
+    if (typeof value.assign !== 'boolean') {
+      return { ...settings, error: `${file}: ${CONFIG_KEY}.assign must be true or false` };
+    }
+    settings.assign = value.assign;
+  }
+  return settings;
+}
+
+/**
+ * Issue title for a finding
+ * @param {Object} finding - Normalized finding
+ * @returns {string}
+ */
+function issueTitle(finding) {
+  const prefix = { slop: 'Slop', security: 'Security', dependencies: 'Dependencies', review: 'Review' }[finding.source] || finding.tool;
+  const text = finding.title || `${finding.message.split('\n')[0]}${finding.file ? ` in ${finding.file}` : ''}`;
+  const title = `${prefix}: ${text}`;
+  return title.length > TITLE_LENGTH ? `${title.slice(0, TITLE_LENGTH - 3).trimEnd()}...` : title;
+}
+
+/**
+ * Issue body for a finding, ending with the fingerprint marker
+ * @param {Object} finding - Normalized finding
+ * @param {{host: string, url: string}|null} repository - From changelog.getRepository
+ * @param {string|null} commit - Commit the findings were produced at, for permalinks
+ * @returns {string}
+ */
+function issueBody(finding, repository, commit) {
+  const location = finding.file ? `${finding.file}${finding.line ? `:${finding.line}` : ''}` : null;
+  const blob = location && repository && commit
+    ? `${repository.url}${repository.host === 'gitlab' ? '/-' : ''}/blob/${commit}/${finding.file}${finding.line ? `#L${finding.line}` : ''}`
+    : null;
+  return [
+    finding.message,
+    '',
+    `- **Rule**: \`${finding.rule}\` (${finding.tool})`,
+    `- **Severity**: ${finding.severity}`,
+    ...(location ? [`- **Location**: ${blob ? `[${location}](${blob})` : `\`${location}\``}`] : []),
+    ...(finding.occurrences > 1 ? [`- **Occurrences**: ${finding.occurrences}`] : []),
+    ...(finding.suggestion ? ['', `**Suggested fix**: ${finding.suggestion}`] : []),
+    '',
+    'Filed by /issue. Re-running it updates this issue; close it once the finding is fixed.',
+    `<!-- awesome-slash:issue fingerprint=${finding.fingerprint} source=${finding.source} -->`
+  ].join('\n');
+}
+
+/**
+ * Labels for a finding
+ * @param {Object} finding - Normalized finding
+ * @param {string[]} [extra] - Labels from configuration
+ * @returns {string[]}
+ */
+function issueLabels(finding, extra = []) {
+  return [...new Set([TRACKING_LABEL, SOURCE_LABELS[finding.source] || finding.source, ...(finding.labels || []), `severity:${finding.severity}`, ...extra])];
+}
+
+/**
+ * Command listing issues filed by earlier runs
+ * @param {string} host - `github` or `gitlab`
+ * @param {number} [page=1] - GitLab page (100 issues each)
+ * @returns {string[]|null}
+ */
+function listIssuesCommand(host, page = 1) {
+  if (host === 'github') return ['gh', 'issue', 'list', '--state', 'all', '--label', TRACKING_LABEL, '--limit', '1000', '--json', 'number,title,body,state,labels,url'];
+  if (host === 'gitlab') return ['glab', 'api', `projects/:id/issues?labels=${TRACKING_LABEL}&per_page=${GITLAB_PAGE_SIZE}&page=${page}`];
+  return null;
+}
+
+/**
+ * Parse issue list output into issues carrying a fingerprint marker
+ * @param {string} host - `github` or `gitlab`
+ * @param {Object[]} list - Parsed `gh issue list --json` or GitLab API output
+ * @returns {Array<{number: number, title: string, body: string, open: boolean, labels: string[], url: string, fingerprint: string, source: string}>}
+ */
+function parseIssues(host, list) {
+  const issues = [];
+  for (const issue of list) {
+    const body = (host === 'gitlab' ? issue.description : issue.body) || '';
+    const marker = body.match(MARKER);
+    if (!marker) continue;
+    issues.push({
+      number: host === 'gitlab' ? issue.iid : issue.number,
+      title: issue.title,
+      body,
+      open: /^open/i.test(issue.state),
+      labels: (issue.labels || []).map(label => (typeof label === 'string' ? label : label.name)),
+      url: host === 'gitlab' ? issue.web_url : issue.url,
+      fingerprint: marker[1],
+      source: marker[2]
+    });
+  }
+  return issues;
+}
+
+/**
+ * Issues filed by earlier runs
+ * @param {string} basePath - Repository root
+ * @param {string} host - `github` or `gitlab`
+ * @param {Function} runCommand - `(basePath, argv) => stdout|null`
+ * @returns {Object[]|null} Result of parseIssues, or null when listing fails
+ */
+function listIssues(basePath, host, runCommand) {
+  const list = [];
+  for (let page = 1; ; page++) {
+    const output = runCommand(basePath, listIssuesCommand(host, page));
+    let parsed;
+    try {
+      parsed = JSON.parse(output);
+    } catch {
+      return null;
+    }
+    if (!Array.isArray(parsed)) return null;
+    list.push(...parsed);
+    if (host !== 'gitlab' || parsed.length < GITLAB_PAGE_SIZE) break;
+  }
+  return parseIssues(host, list);
+}
+
+/**
+ * Whether two bodies differ beyond the commit in their permalinks
+ * @param {string} a
+ * @param {string} b
+ * @returns {boolean}
+ */
+function bodiesDiffer(a, b) {
+  const normalize = text => String(text).replace(/\/blob\/[0-9a-f]{7,40}\//g, '/blob/-/').trim();
+  return normalize(a) !== normalize(b);
+}
+
+/**
+ * Decide what to do for every finding and previously filed issue
+ *
+ * - `create`: no issue has the fingerprint (at most `limit` per run; the rest are `deferred`)
+ * - `update`: the open issue's title, body, or labels changed
+ * - `unchanged`: the open issue is current
+ * - `closed`: the issue was closed; left alone unless `reopen` is set (`reopen`)
+ * - `resolved`: an open issue from a scanned source whose finding is gone
+ *   (`close` when `closeResolved` is set)
+ *
+ * @param {string} basePath - Repository root
+ * @param {Object[]} findings - Normalized findings (all severities; used to detect resolved issues)
+ * @param {Object} [options]
+ * @param {string} [options.minSeverity] - Least severe finding filed (default: config, then medium)
+ * @param {number} [options.limit] - New issues per run (default: config, then 20)
+ * @param {boolean} [options.reopen=false] - Reopen closed issues whose finding is back
+ * @param {boolean} [options.closeResolved=false] - Close open issues whose finding is gone
+ * @param {boolean} [options.assign] - Assign CODEOWNERS (default: config, then true)
+ * @param {{host: string, url: string}|null} [options.repository] - Defaults to the origin remote
+ * @param {string} [options.commit] - Commit for permalinks (default: HEAD)
+ * @param {Function} [options.run] - `(basePath, argv) => stdout|null`
+ * @returns {Object} `{success, repository, actions, labels, codeowners, configError, error}`
+ */
+function planIssues(basePath, findings, options = {}) {
+  const repository = options.repository !== undefined ? options.repository : getRepository(basePath, options.ciPlatform);
+  if (!repository || !listIssuesCommand(repository.host)) {
+    return { success: false, error: 'Issues can only be filed on GitHub or GitLab; no such origin remote found' };
+  }
+  const runCommand = options.run || run;
+  const issues = listIssues(basePath, repository.host, runCommand);
+  if (issues === null) {
+    return { success: false, error: `Could not list issues; check that ${repository.host === 'gitlab' ? 'glab' : 'gh'} is installed and authenticated` };
+  }
+
+  const settings = readSettings(basePath);
+  const minSeverity = options.minSeverity || settings.minSeverity;
+  const limit = options.limit || settings.limit;
+  const assign = options.assign !== undefined ? options.assign : settings.assign;
+  const commit = options.commit !== undefined ? options.commit : (runCommand(basePath, ['git', 'rev-parse', 'HEAD']) || '').trim() || null;
+  const codeowners = assign ? readCodeowners(basePath) : null;
+  const byFingerprint = new Map(issues.map(issue => [issue.fingerprint, issue]));
+  const unique = dedupeFindings(findings);
+
+  const actions = [];
+  let created = 0;
+  for (const finding of unique) {
+    const issue = byFingerprint.get(finding.fingerprint);
+    if (!issue && SEVERITIES.indexOf(finding.severity) > SEVERITIES.indexOf(minSeverity)) continue;
+    const title = issueTitle(finding);
+    const body = issueBody(finding, repository, commit);
+    const labels = issueLabels(finding, settings.labels);
+    const base = { fingerprint: finding.fingerprint, source: finding.source, severity: finding.severity, title, body, labels };
+
+    if (!issue) {
+      const assignees = codeowners ? ownersOf(codeowners.rules, finding.file) : [];
+      actions.push({ ...base, action: created < limit ? 'create' : 'deferred', assignees });
+      if (created < limit) created++;
+    } else if (!issue.open) {
+      actions.push({ ...base, action: options.reopen ? 'reopen' : 'closed', number: issue.number, url: issue.url });
+    } else {
+      const changed = issue.title !== title || bodiesDiffer(issue.body, body) || labels.some(label => !issue.labels.includes(label));
+      actions.push({ ...base, action: changed ? 'update' : 'unchanged', number: issue.number, url: issue.url });
+    }
+  }
+
+  const current = new Set(unique.map(finding => finding.fingerprint));
+  const scanned = new Set(unique.map(finding => finding.source));
+  for (const issue of issues) {
+    if (!issue.open || current.has(issue.fingerprint) || !scanned.has(issue.source)) continue;
+    actions.push({
+      action: options.closeResolved ? 'close' : 'resolved',
+      fingerprint: issue.fingerprint,
+      source: issue.source,
+      title: issue.title,
+      number: issue.number,
+      url: issue.url
+    });
+  }
+
+  const labels = new Set(actions.filter(action => action.labels && ['create', 'update', 'reopen'].includes(action.action)).flatMap(action => action.labels));
+  return {
+    success: true,
+    repository,
+    commit,
+    minSeverity,
+    actions,
+    labels: [...labels].sort(),
+    codeowners: codeowners ? codeowners.file : null,
+    configError: settings.error
+  };
+}
+
+/**
+ * Forge CLI commands that carry out one action
+ * @param {{host: string}} repository - Plan repository
+ * @param {Object} action - One of plan.actions
+ * @returns {string[][]} argv list, empty for actions that change nothing
+ */
+function actionCommands(repository, action) {
+  const github = repository.host === 'github';
+  const closeNote = 'No longer reported by the scanner that filed this issue; closing.';
+  switch (action.action) {
+    case 'create':
+      return github
+        ? [['gh', 'issue', 'create', '--title', action.title, '--body', action.body,
+          ...action.labels.flatMap(label => ['--label', label]), ...action.assignees.flatMap(user => ['--assignee', user])]]
+        : [['glab', 'issue', 'create', '--title', action.title, '--description', action.body, '--label', action.labels.join(','),
+          ...(action.assignees.length ? ['--assignee', action.assignees.join(',')] : [])]];
+    case 'reopen':
+    case 'update': {
+      const number = String(action.number);
+      const edit = github
+        ? ['gh', 'issue', 'edit', number, '--title', action.title, '--body', action.body, '--add-label', action.labels.join(',')]
+        : ['glab', 'issue', 'update', number, '--title', action.title, '--description', action.body, '--label', action.labels.join(',')];
+      return action.action === 'reopen' ? [[github ? 'gh' : 'glab', 'issue', 'reopen', number], edit] : [edit];
+    }
+    case 'close':
+      return github
+        ? [['gh', 'issue', 'close', String(action.number), '--comment', closeNote]]
+        : [['glab', 'issue', 'note', String(action.number), '--message', closeNote], ['glab', 'issue', 'close', String(action.number)]];
+    default:
+      return [];
+  }
+}
+
+/**
+ * Commands creating labels a plan uses that the GitHub repository lacks
+ * GitLab creates missing labels on use.
+ * @param {string} basePath - Repository root
+ * @param {Object} plan - Result of planIssues
+ * @param {Object} [options]
+ * @param {Function} [options.run] - `(basePath, argv) => stdout|null`
+ * @returns {string[][]}
+ */
+function labelCommands(basePath, plan, options = {}) {
+  if (plan.repository.host !== 'github' || plan.labels.length === 0) return [];
+  const output = (options.run || run)(basePath, ['gh', 'label', 'list', '--limit', '1000', '--json', 'name']);
+  let existing = [];
+  try {
+    existing = JSON.parse(output || '[]').map(label => label.name.toLowerCase());
+  } catch {
+    existing = [];
+  }
+  return plan.labels
+    .filter(label => !existing.includes(label.toLowerCase()))
+    .map(label => ['gh', 'label', 'create', label, '--color', LABEL_COLORS[label.replace(/^severity:/, '')] || 'ededed', '--description', 'Filed by awesome-slash /issue']);
+}
+
+/**
+ * Carry out a plan
+ * Stops at the first failing command, since later ones would fail the same way
+ * (authentication, permissions).
+ * @param {string} basePath - Repository root
+ * @param {Object} plan - Result of planIssues
+ * @param {Object} [options]
+ * @param {Function} [options.run] - `(basePath, argv) => stdout|null`
+ * @returns {{success: boolean, applied: Array<{action: string, title: string, url: string|null}>, error?: string}}
+ */
+function applyPlan(basePath, plan, options = {}) {
+  const runCommand = options.run || run;
+  for (const argv of labelCommands(basePath, plan, options)) {
+    if (runCommand(basePath, argv) === null) return { success: false, applied: [], error: `Could not create label ${argv[3]}` };
+  }
+  const applied = [];
+  for (const action of plan.actions) {
+    for (const argv of actionCommands(plan.repository, action)) {
+      const output = runCommand(basePath, argv);
+      if (output === null) {
+        return { success: false, applied, error: `\`${argv.slice(0, 3).join(' ')}\` failed for "${action.title}"` };
+      }
+      if (action.action === 'create') action.url = (output.match(/https?:\/\/\S+/) || [null])[0];
+    }
+    if (['create', 'update', 'reopen', 'close'].includes(action.action)) applied.push({ action: action.action, title: action.title, url: action.url || null });
+  }
+  return { success: true, applied };
+}
+
+/**
+ * Render a plan as markdown
+ * @param {Object} plan - Result of planIssues
+ * @returns {string}
+ */
+function renderPlan(plan) {
+  const count = name => plan.actions.filter(action => action.action === name).length;
+  const lines = ['## Issues', ''];
+  lines.push(`**Create**: ${count('create')} | **Update**: ${count('update')} | **Unchanged**: ${count('unchanged')} | **Closed**: ${count('closed') + count('reopen')} | **Resolved**: ${count('resolved') + count('close')}${count('deferred') ? ` | **Deferred**: ${count('deferred')}` : ''}`);
+  lines.push(`**Repository**: ${plan.repository.url} | **Minimum severity**: ${plan.minSeverity}${plan.codeowners ? ` | **Owners**: ${plan.codeowners}` : ''}`);
+  if (plan.configError) lines.push(`**Config**: ${plan.configError} (defaults used)`);
+  lines.push('');
+
+  const rows = plan.actions.filter(action => action.action !== 'unchanged');
+  if (rows.length === 0) {
+    lines.push('Every finding already has an up-to-date issue.');
+    return lines.join('\n');
+  }
+  lines.push('| Action | Severity | Issue | Assignees |', '|--------|----------|-------|-----------|');
+  for (const action of rows) {
+    const issue = action.number ? `[#${action.number}](${action.url}) ${action.title}` : action.title;
+    lines.push(`| ${action.action} | ${action.severity || '-'} | ${issue.replace(/\|/g, '\\|')} | ${(action.assignees || []).join(', ') || '-'} |`);
+  }
+  if (count('deferred')) lines.push('', `${count('deferred')} more findings are deferred to the next run (limit reached).`);
+  return lines.join('\n');
+}
+
+if (require.main === module) {
+  const args = process.argv.slice(2);
+  const value = flag => (args.includes(flag) ? args[args.indexOf(flag) + 1] : undefined);
+  const flagValues = new Set(['--min-severity', '--limit'].map(value).filter(Boolean));
+  const files = args.filter(arg => !arg.startsWith('--') && !flagValues.has(arg));
+  const findings = [];
+  const errors = [];
+  for (const file of files) {
+    let parsed = null;
+    try {
+      parsed = parseFindings(process.cwd(), JSON.parse(fs.readFileSync(file, 'utf8')));
+    } catch (error) {
+      errors.push(`${file}: ${error.message}`);
+      continue;
+    }
+    if (parsed) findings.push(...parsed);
+    else errors.push(`${file}: not slop, review, deps-audit, or SARIF output`);
+  }
+  const plan = planIssues(process.cwd(), findings, { minSeverity: value('--min-severity'), limit: Number(value('--limit')) || undefined });
+  const indent = process.stdout.isTTY ? 2 : 0;
+  console.log(JSON.stringify({ ...plan, errors }, null, indent));
+  if (!plan.success) process.exitCode = 1;
+}
+
+module.exports = {
+  CONFIG_KEY,
+  TRACKING_LABEL,
+  SEVERITIES,
+  CODEOWNERS_FILES,
+  normalizeSeverity,
+  fromSlop,
+  fromReview,
+  fromDeps,
+  fromSarif,
+  parseFindings,
+  dedupeFindings,
+  parseCodeowners,
+  readCodeowners,
+  ownersOf,
+  readSettings,
+  issueTitle,
+  issueBody,
+  issueLabels,
+  listIssuesCommand,
+  parseIssues,
+  listIssues,
+  planIssues,
+  actionCommands,
+  labelCommands,
+  applyPlan,
+  renderPlan
+};
@@ -0,0 +1,234 @@
+/**
+ * Slop Baseline
+ *
+ * Records the findings a codebase already has so later scans only report new
+ * or changed occurrences. The baseline (`.slop-baseline.json` at the
+ * repository root, meant to be committed) stores one entry per file, pattern
+ * and fingerprint with an occurrence count. Fingerprints hash the pattern name
+ * and the normalized source line, not the line number, so unrelated edits
+ * that shift code up or down keep findings suppressed while editing the
+ * flagged line itself reports it again.
+ *
+ * @module patterns/baseline
+ * @author Avi Fenesh
+ * @license MIT
+ */
+
+const crypto = require('crypto');
+const fs = require('fs');
+const path = require('path');
+
+/**
+ * Default baseline filename (repository root)
+ */
+const BASELINE_FILE = '.slop-baseline.json';
+
+/**
+ * Baseline format version; bump when fingerprints change
+ */
+const BASELINE_VERSION = 1;
+
+/**
+ * Normalize a path to forward slashes relative to the repository
+ * @param {string} repoPath - Repository root
+ * @param {string} file - Finding file path
+ * @returns {string}
+ */
+function normalizeFile(repoPath, file) {
+  const relative = path.isAbsolute(file) ? path.relative(repoPath, file) : file;
+  return relative.replace(/\\/g, '/').replace(/^\.\//, '');
+}
+
+/**
+ * Build a reader that returns source lines, memoized per file
+ * @param {string} repoPath - Repository root
+ * @returns {Function} (file) => string[]|null
+ */
+function createLineReader(repoPath) {
+  const cache = new Map();
+  return file => {
+    if (!cache.has(file)) {
+      let lines = null;
+      try {
+        lines = fs.readFileSync(path.join(repoPath, file), 'utf8').split('\n');
+      } catch {
+        lines = null;
+      }
+      cache.set(file, lines);
+    }
+    return cache.get(file);
+  };
+}
+
+/**
+ * Fingerprint a finding from its pattern and the flagged source line
+ * @param {Object} finding - Pipeline finding
+ * @param {string[]|null} lines - Source lines of the finding's file
+ * @returns {string} 16-char hex digest
+ */
+function fingerprintFinding(finding, lines) {
+  const lineText = lines && finding.line ? lines[finding.line - 1] : undefined;
+  const text = lineText !== undefined ? lineText : (finding.content || '');
+  const normalized = text.replace(/\s+/g, ' ').trim();
+  return crypto.createHash('sha256')
+    .update(`${finding.patternName || ''}\0${normalized}`)
+    .digest('hex')
+    .slice(0, 16);
+}
+
+/**
+ * Key findings by file, pattern and fingerprint
+ * @param {string} repoPath - Repository root
+ * @param {Array} findings - Pipeline findings
+ * @returns {Array<{key: string, file: string, patternName: string, fingerprint: string, finding: Object}>}
+ */
+function keyFindings(repoPath, findings) {
+  const readLines = createLineReader(repoPath);
+  return findings.map(finding => {
+    const file = normalizeFile(repoPath, finding.file || '');
+    const fingerprint = fingerprintFinding(finding, readLines(file));
+    const patternName = finding.patternName || '';
+    return { key: `${file}\0${patternName}\0${fingerprint}`, file, patternName, fingerprint, finding };
+  });
+}
+
+/**
+ * Create a baseline from pipeline findings
+ * @param {string} repoPath - Repository root
+ * @param {Array} findings - Pipeline findings
+ * @returns {{version: number, entries: Array<{file: string, patternName: string, fingerprint: string, count: number}>}}
+ */
+function createBaseline(repoPath, findings) {
+  const entries = new Map();
+  for (const { key, file, patternName, fingerprint } of keyFindings(repoPath, findings)) {
+    if (!entries.has(key)) entries.set(key, { file, patternName, fingerprint, count: 0 });
+    entries.get(key).count++;
+  }
+
+  const sorted = Array.from(entries.values()).sort((a, b) =>
+    a.file.localeCompare(b.file) || a.patternName.localeCompare(b.patternName) || a.fingerprint.localeCompare(b.fingerprint));
+  return { version: BASELINE_VERSION, entries: sorted };
+}
+
+/**
+ * Write a baseline file
+ * @param {string} repoPath - Repository root
+ * @param {Array} findings - Pipeline findings
+ * @param {string} [file=BASELINE_FILE] - Baseline path, relative to repoPath
+ * @returns {{file: string, entries: number, findings: number}} Written path and counts
+ */
+function writeBaseline(repoPath, findings, file = BASELINE_FILE) {
+  const baseline = createBaseline(repoPath, findings);
+  const target = path.resolve(repoPath, file);
+  fs.writeFileSync(target, JSON.stringify(baseline, null, 2) + '\n');
+  return { file: target, entries: baseline.entries.length, findings: findings.length };
+}
+
+/**
+ * Add findings to a baseline file, keeping the entries it already has
+ * A missing file starts a new baseline; an unreadable one is an error.
+ * @param {string} repoPath - Repository root
+ * @param {Array} findings - Pipeline findings to suppress from now on
+ * @param {string} [file=BASELINE_FILE] - Baseline path, relative to repoPath
+ * @returns {{file: string, entries: number, added: number, findings: number, error: string|null}}
+ *   `added` counts entries the file did not have; findings already in it only raise a count
+ */
+function addToBaseline(repoPath, findings, file = BASELINE_FILE) {
+  const target = path.resolve(repoPath, file);
+  const loaded = loadBaseline(repoPath, file);
+  if (loaded.error) return { file: target, entries: 0, added: 0, findings: findings.length, error: loaded.error };
+
+  const entries = new Map();
+  let added = 0;
+  const keyOf = entry => `${entry.file}\0${entry.patternName}\0${entry.fingerprint}`;
+  for (const entry of (loaded.baseline || { entries: [] }).entries) {
+    entries.set(keyOf(entry), { ...entry, count: entry.count || 1 });
+  }
+  for (const entry of createBaseline(repoPath, findings).entries) {
+    const existing = entries.get(keyOf(entry));
+    if (existing) {
+      existing.count += entry.count;
+    } else {
+      entries.set(keyOf(entry), entry);
+      added++;
+    }
+  }
+
+  const sorted = Array.from(entries.values()).sort((a, b) =>
+    a.file.localeCompare(b.file) || a.patternName.localeCompare(b.patternName) || a.fingerprint.localeCompare(b.fingerprint));
+  fs.writeFileSync(target, JSON.stringify({ version: BASELINE_VERSION, entries: sorted }, null, 2) + '\n');
+  return { file: target, entries: sorted.length, added, findings: findings.length, error: null };
+}
+
+/**
+ * Read a baseline file
+ * @param {string} repoPath - Repository root
+ * @param {string} [file=BASELINE_FILE] - Baseline path, relative to repoPath
+ * @returns {{baseline: Object|null, error: string|null}} baseline is null when missing or invalid
+ */
+function loadBaseline(repoPath, file = BASELINE_FILE) {
+  let content;
+  try {
+    content = fs.readFileSync(path.resolve(repoPath, file), 'utf8');
+  } catch {
+    return { baseline: null, error: null };
+  }
+
+  let baseline;
+  try {
+    baseline = JSON.parse(content);
+  } catch (error) {
+    return { baseline: null, error: `${file}: invalid JSON (${error.message})` };
+  }
+  if (!baseline || !Array.isArray(baseline.entries)) {
+    return { baseline: null, error: `${file}: missing entries array` };
+  }
+  if (baseline.version !== BASELINE_VERSION) {
+    return { baseline: null, error: `${file}: unsupported version ${baseline.version}; regenerate with \`detect.js baseline\`` };
+  }
+  return { baseline, error: null };
+}
+
+/**
+ * Drop findings recorded in a baseline
+ *
+ * Each entry suppresses up to `count` matching findings; extra occurrences of
+ * the same line (e.g. a copy-pasted block) are reported as new.
+ *
+ * @param {string} repoPath - Repository root
+ * @param {Array} findings - Pipeline findings
+ * @param {Object} baseline - Baseline from loadBaseline/createBaseline
+ * @returns {{findings: Array, suppressed: number}} New findings in original order
+ */
+function filterBaseline(repoPath, findings, baseline) {
+  const remaining = new Map();
+  for (const entry of baseline.entries) {
+    const key = `${entry.file}\0${entry.patternName}\0${entry.fingerprint}`;
+    remaining.set(key, (remaining.get(key) || 0) + (entry.count || 1));
+  }
+
+  const kept = [];
+  let suppressed = 0;
+  for (const { key, finding } of keyFindings(repoPath, findings)) {
+    const left = remaining.get(key) || 0;
+    if (left > 0) {
+      remaining.set(key, left - 1);
+      suppressed++;
+    } else {
+      kept.push(finding);
+    }
+  }
+  return { findings: kept, suppressed };
+}
+
+module.exports = {
+  BASELINE_FILE,
+  BASELINE_VERSION,
+  fingerprintFinding,
+  keyFindings,
+  createBaseline,
+  writeBaseline,
+  addToBaseline,
+  loadBaseline,
+  filterBaseline
+};
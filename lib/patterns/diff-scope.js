@@ -0,0 +1,226 @@
+/**
+ * Diff-Scoped Scanning
+ *
+ * Restricts slop detection to the lines a branch adds or modifies, which is
+ * what PR checks want. Changed lines come from a zero-context diff between
+ * the merge base of `<base>` and HEAD and the working tree, i.e. the same
+ * changes as `git diff <base>...HEAD` plus any uncommitted edits, so line
+ * numbers are the new-side numbers of the files on disk and findings map
+ * onto them directly. Pre-commit hooks use the staged changes instead
+ * (`git diff --cached`). Paths are relative to the scanned directory.
+ *
+ * @module patterns/diff-scope
+ * @author Avi Fenesh
+ * @license MIT
+ */
+
+const path = require('path');
+const { SOURCE_EXTENSIONS, isTestFile } = require('./slop-analyzers');
+const ignore = require('../utils/ignore');
+const exec = require('../utils/exec');
+
+/**
+ * Refs accepted as a diff base (no leading dash, no whitespace)
+ */
+const SAFE_REF = /^(?!-)[\w./~^@{}:+-]{1,200}$/;
+
+/**
+ * Unquote a path from a diff header (`"a/sp\303\244ce"` style quoting)
+ * @param {string} value - Header path
+ * @returns {string}
+ */
+function unquotePath(value) {
+  if (!value.startsWith('"')) return value;
+  const bytes = [];
+  const body = value.slice(1, -1);
+  for (let i = 0; i < body.length; i++) {
+    if (body[i] !== '\\') {
+      bytes.push(...Buffer.from(body[i]));
+      continue;
+    }
+    const next = body[++i];
+    if (/[0-7]/.test(next)) {
+      bytes.push(parseInt(body.substr(i, 3), 8));
+      i += 2;
+    } else {
+      bytes.push(({ n: 10, t: 9, '"': 34, '\\': 92 })[next] || next.charCodeAt(0));
+    }
+  }
+  return Buffer.from(bytes).toString('utf8');
+}
+
+/**
+ * Parse a unified diff into added line numbers per file
+ * @param {string} diff - `git diff` output
+ * @returns {Map<string, number[]>} New-side path => sorted 1-based added lines (deleted files are omitted)
+ */
+function parseUnifiedDiff(diff) {
+  const files = new Map();
+  let current = null;
+  let inHeader = false;
+  let newLine = 0;
+
+  for (const line of diff.split('\n')) {
+    if (line.startsWith('diff --git ')) {
+      current = null;
+      inHeader = true;
+      continue;
+    }
+    // Header lines only until the first hunk; `+++`/`---` later are content
+    if (inHeader && line.startsWith('+++ ')) {
+      const target = line.slice(4).replace(/\t.*$/, '');
+      if (target === '/dev/null') {
+        current = null;
+      } else {
+        const file = unquotePath(target).replace(/^b\//, '');
+        current = files.get(file) || [];
+        files.set(file, current);
+      }
+      continue;
+    }
+    const hunk = line.match(/^@@ -\d+(?:,\d+)? \+(\d+)(?:,\d+)? @@/);
+    if (hunk) {
+      newLine = parseInt(hunk[1], 10);
+      inHeader = false;
+      continue;
+    }
+    if (inHeader) continue;
+    if (!current) continue;
+
+    if (line.startsWith('+')) {
+      current.push(newLine++);
+    } else if (line.startsWith(' ')) {
+      newLine++;
+    }
+  }
+
+  for (const [file, lines] of files) {
+    if (lines.length === 0) files.delete(file);
+    else lines.sort((a, b) => a - b);
+  }
+  return files;
+}
+
+/**
+ * Run git and return stdout, throwing on failure
+ * @param {string} repoPath - Repository root
+ * @param {string[]} args - git arguments
+ * @param {Function} [execFileSync] - `(file, args, options)` executor (for testing)
+ * @returns {string}
+ */
+function runGit(repoPath, args, execFileSync) {
+  if (execFileSync) return execFileSync('git', args, { cwd: repoPath, encoding: 'utf8' });
+  return exec.execSync(repoPath, ['git', ...args]);
+}
+
+/**
+ * Collect lines added or modified since `base`
+ * @param {string} repoPath - Repository root
+ * @param {string} base - Base ref, e.g. `origin/main`
+ * @param {Object} [options]
+ * @param {Function} [options.execFileSync] - Command executor (for testing)
+ * @returns {{base: string, mergeBase: string|null, files: Map<string, number[]>, error: string|null}}
+ */
+function getChangedLines(repoPath, base, options = {}) {
+  const empty = { base, mergeBase: null, files: new Map() };
+
+  if (typeof base !== 'string' || !SAFE_REF.test(base)) {
+    return { ...empty, error: `Invalid diff base: ${base}` };
+  }
+
+  let mergeBase;
+  try {
+    mergeBase = runGit(repoPath, ['merge-base', base, 'HEAD'], options.execFileSync).trim();
+  } catch (error) {
+    const detail = String(error.stderr || error.message).trim().split('\n')[0];
+    return { ...empty, error: `Cannot find merge base of ${base} and HEAD: ${detail}` };
+  }
+
+  let diff;
+  try {
+    diff = runGit(repoPath, [
+      '-c', 'core.quotePath=false', 'diff', '--no-color', '--no-ext-diff', '--unified=0',
+      '--relative', '--src-prefix=a/', '--dst-prefix=b/', '--find-renames', '--diff-filter=ACMR', mergeBase, '--'
+    ], options.execFileSync);
+  } catch (error) {
+    const detail = String(error.stderr || error.message).trim().split('\n')[0];
+    return { ...empty, mergeBase, error: `git diff failed: ${detail}` };
+  }
+
+  return { base, mergeBase, files: parseUnifiedDiff(diff), error: null };
+}
+
+/**
+ * Collect lines the index adds or modifies relative to HEAD (what a commit would record)
+ * Line numbers are those of the staged content, which matches the file on
+ * disk unless it has unstaged edits.
+ * @param {string} repoPath - Repository root
+ * @param {Object} [options]
+ * @param {Function} [options.execFileSync] - Command executor (for testing)
+ * @returns {{base: string, mergeBase: null, files: Map<string, number[]>, error: string|null}}
+ */
+function getStagedLines(repoPath, options = {}) {
+  let diff;
+  try {
+    diff = runGit(repoPath, [
+      '-c', 'core.quotePath=false', 'diff', '--cached', '--no-color', '--no-ext-diff', '--unified=0',
+      '--relative', '--src-prefix=a/', '--dst-prefix=b/', '--find-renames', '--diff-filter=ACMR', '--'
+    ], options.execFileSync);
+  } catch (error) {
+    const detail = String(error.stderr || error.message).trim().split('\n')[0];
+    return { base: 'staged', mergeBase: null, files: new Map(), error: `git diff --cached failed: ${detail}` };
+  }
+  return { base: 'staged', mergeBase: null, files: parseUnifiedDiff(diff), error: null };
+}
+
+/**
+ * Changed files the pipeline would scan (source extensions, not tests or ignored paths)
+ * @param {string} repoPath - Repository root
+ * @param {Map<string, number[]>} files - Changed lines per file
+ * @param {Object} [options]
+ * @param {boolean} [options.includeTests=false] - Keep test files
+ * @returns {string[]}
+ */
+function selectSourceFiles(repoPath, files, options = {}) {
+  const extensions = new Set(Object.values(SOURCE_EXTENSIONS).flat());
+  const isIgnored = ignore.createIgnoreFilter(repoPath);
+  return Array.from(files.keys()).filter(file =>
+    extensions.has(path.extname(file)) &&
+    !isIgnored(file, false) &&
+    (options.includeTests || !isTestFile(file)));
+}
+
+/**
+ * Keep findings on changed lines
+ *
+ * Block findings (`details.startLine`..`details.endLine`) are kept when any
+ * line in the block changed; findings without a line are dropped.
+ *
+ * @param {Array} findings - Pipeline findings
+ * @param {Map<string, number[]>} files - Changed lines per file
+ * @returns {{findings: Array, outOfScope: number}}
+ */
+function filterToChangedLines(findings, files) {
+  const changed = new Map(Array.from(files, ([file, lines]) => [file, new Set(lines)]));
+  const kept = [];
+  for (const finding of findings) {
+    const lines = changed.get(String(finding.file || '').replace(/\\/g, '/').replace(/^\.\//, ''));
+    const details = finding.details || {};
+    const start = details.startLine || finding.line;
+    const end = details.endLine || finding.line;
+    let inScope = false;
+    if (lines && start) {
+      for (let line = start; line <= end && !inScope; line++) inScope = lines.has(line);
+    }
+    if (inScope) kept.push(finding);
+  }
+  return { findings: kept, outOfScope: findings.length - kept.length };
+}
+
+module.exports = {
+  parseUnifiedDiff,
+  getChangedLines,
+  getStagedLines,
+  selectSourceFiles,
+  filterToChangedLines
+};
@@ -0,0 +1,309 @@
+/**
+ * SARIF Output
+ *
+ * Converts slop pipeline findings and review findings (the review queue
+ * written by /audit-project and /next-task review passes) to SARIF 2.1.0 so
+ * they show up as GitHub code scanning annotations and in IDE SARIF viewers.
+ * Rules carry pattern metadata and default levels; slop results include
+ * `fixes` for every finding the auto-fixer can patch, and a line-content
+ * fingerprint (the one the baseline uses) so alerts survive unrelated edits.
+ *
+ * Usage:
+ *   node sarif.js review <review-queue.json> > review.sarif
+ *
+ * @module patterns/sarif
+ * @author Avi Fenesh
+ * @license MIT
+ */
+
+const fs = require('fs');
+const path = require('path');
+const { slopPatterns } = require('./slop-patterns');
+const { keyFindings } = require('./baseline');
+const { getSecurityTags } = require('./review-patterns');
+
+const SARIF_VERSION = '2.1.0';
+const SARIF_SCHEMA = 'https://json.schemastore.org/sarif-2.1.0.json';
+const INFORMATION_URI = 'https://github.com/avifenesh/awesome-slash';
+
+/**
+ * Finding severity => SARIF level
+ */
+const SEVERITY_LEVELS = {
+  critical: 'error',
+  high: 'error',
+  medium: 'warning',
+  low: 'note',
+  explain: 'note'
+};
+
+/**
+ * Finding severity => GitHub `security-severity` score (security rules only)
+ */
+const SECURITY_SEVERITY = {
+  critical: '9.0',
+  high: '7.0',
+  medium: '5.0',
+  low: '3.0'
+};
+
+/**
+ * Certainty/confidence => SARIF rule precision
+ */
+const PRECISION = {
+  high: 'high',
+  medium: 'medium',
+  low: 'low'
+};
+
+/**
+ * Forward-slash path relative to the repository
+ * @param {string} repoPath - Repository root
+ * @param {string} file - Finding path
+ * @returns {string}
+ */
+function toUri(repoPath, file) {
+  const relative = path.isAbsolute(file) ? path.relative(repoPath, file) : file;
+  return relative.replace(/\\/g, '/').replace(/^\.\//, '').split('/').map(encodeURIComponent).join('/');
+}
+
+/**
+ * SARIF level for a severity
+ * @param {string} severity
+ * @returns {string}
+ */
+function levelFor(severity) {
+  return SEVERITY_LEVELS[severity] || 'warning';
+}
+
+/**
+ * Physical location for a finding
+ * @param {string} repoPath - Repository root
+ * @param {Object} finding - Finding with file and optional line/details
+ * @returns {Object}
+ */
+function locationFor(repoPath, finding) {
+  const physicalLocation = {
+    artifactLocation: { uri: toUri(repoPath, finding.file), uriBaseId: '%SRCROOT%' }
+  };
+  const details = finding.details || {};
+  const startLine = details.startLine || finding.line;
+  if (startLine) {
+    physicalLocation.region = { startLine };
+    const endLine = details.endLine || finding.endLine;
+    if (endLine && endLine > startLine) physicalLocation.region.endLine = endLine;
+  }
+  return { physicalLocation };
+}
+
+/**
+ * SARIF fix from fixer edits (whole-line replacements)
+ * @param {string} uri - Artifact URI
+ * @param {Object[]} edits - Fixer edits ({start, end, lines}, 1-based inclusive; end < start inserts)
+ * @param {string} description - Fix description
+ * @returns {Object}
+ */
+function fixFromEdits(uri, edits, description) {
+  return {
+    description: { text: description },
+    artifactChanges: [{
+      artifactLocation: { uri, uriBaseId: '%SRCROOT%' },
+      replacements: edits.map(edit => ({
+        deletedRegion: edit.end < edit.start
+          ? { startLine: edit.start, startColumn: 1, endLine: edit.start, endColumn: 1 }
+          : { startLine: edit.start, startColumn: 1, endLine: edit.end + 1, endColumn: 1 },
+        insertedContent: { text: edit.lines.map(line => line + '\n').join('') }
+      }))
+    }]
+  };
+}
+
+/**
+ * Assemble a SARIF log with one run
+ * @param {Object} driver - tool.driver
+ * @param {Object[]} results - SARIF results
+ * @returns {Object}
+ */
+function buildLog(driver, results) {
+  return {
+    $schema: SARIF_SCHEMA,
+    version: SARIF_VERSION,
+    runs: [{ tool: { driver }, results, columnKind: 'utf16CodeUnits' }]
+  };
+}
+
+/**
+ * Convert slop detection findings to SARIF
+ *
+ * @param {string} repoPath - Repository root
+ * @param {Array} findings - Pipeline findings
+ * @param {Object} [options]
+ * @param {Object} [options.patterns] - Pattern metadata by name (defaults to built-in patterns)
+ * @param {Object} [options.fixes] - Result of fixer.generateFixes for `findings`
+ * @param {string} [options.toolVersion] - Reported tool version
+ * @returns {Object} SARIF 2.1.0 log
+ */
+function slopToSarif(repoPath, findings, options = {}) {
+  const patterns = options.patterns || slopPatterns;
+
+  // Fixes per finding object
+  const fixByFinding = new Map();
+  for (const entry of (options.fixes && options.fixes.files) || []) {
+    const uri = toUri(repoPath, entry.file);
+    for (const { finding, edits } of entry.fixes) {
+      fixByFinding.set(finding, fixFromEdits(uri, edits, `Apply ${finding.autoFix || 'remove'} fix for ${finding.patternName}`));
+    }
+  }
+
+  const rules = [];
+  const ruleIndex = new Map();
+  const results = [];
+  const keyed = keyFindings(repoPath, findings.filter(finding => finding.file));
+
+  for (const { finding, fingerprint } of keyed) {
+    const ruleId = finding.patternName || 'unknown';
+    if (!ruleIndex.has(ruleId)) {
+      const pattern = patterns[ruleId] || {};
+      const description = pattern.description || finding.description || ruleId;
+      ruleIndex.set(ruleId, rules.length);
+      rules.push({
+        id: ruleId,
+        name: ruleId.split('_').map(word => word.charAt(0).toUpperCase() + word.slice(1)).join(''),
+        shortDescription: { text: description },
+        fullDescription: { text: description },
+        defaultConfiguration: { level: levelFor(pattern.severity || finding.severity) },
+        helpUri: `${INFORMATION_URI}/tree/main/plugins/deslop#readme`,
+        properties: {
+          tags: ['slop', ...(pattern.language ? [pattern.language] : []), ...(pattern.custom ? ['custom'] : [])],
+          precision: PRECISION[String(finding.certainty || 'medium').toLowerCase()] || 'medium',
+          autoFix: pattern.autoFix || finding.autoFix || 'flag'
+        }
+      });
+    }
+
+    const result = {
+      ruleId,
+      ruleIndex: ruleIndex.get(ruleId),
+      level: levelFor(finding.severity),
+      message: { text: finding.description || ruleId },
+      locations: [locationFor(repoPath, finding)],
+      partialFingerprints: { 'slopFingerprint/v1': fingerprint },
+      properties: { certainty: finding.certainty || null, phase: finding.phase || null }
+    };
+    const fix = fixByFinding.get(finding);
+    if (fix) result.fixes = [fix];
+    results.push(result);
+  }
+
+  const driver = {
+    name: 'deslop',
+    informationUri: INFORMATION_URI,
+    rules
+  };
+  if (options.toolVersion) driver.version = options.toolVersion;
+  return buildLog(driver, results);
+}
+
+/**
+ * Convert review findings (review queue items) to SARIF
+ *
+ * Findings marked `falsePositive` are omitted. `suggestion` is appended to
+ * the message, since review suggestions are prose rather than patches.
+ * CWE and OWASP IDs (`cwe`/`owasp` fields, or IDs in the description) become
+ * result properties and `external/cwe/...` rule tags.
+ *
+ * @param {string} repoPath - Repository root
+ * @param {Array} findings - Review findings ({file, line, severity, category|pass, description, suggestion, confidence, cwe, owasp})
+ * @param {Object} [options]
+ * @param {string} [options.toolVersion] - Reported tool version
+ * @returns {Object} SARIF 2.1.0 log
+ */
+function reviewToSarif(repoPath, findings, options = {}) {
+  const rules = [];
+  const ruleIndex = new Map();
+  const results = [];
+
+  for (const finding of findings) {
+    if (!finding || !finding.file || finding.falsePositive) continue;
+    const category = finding.category || finding.pass || 'general';
+    const ruleId = `review/${category}`;
+    if (!ruleIndex.has(ruleId)) {
+      const properties = { tags: category === 'security' ? ['review', 'security'] : ['review', category] };
+      ruleIndex.set(ruleId, rules.length);
+      rules.push({
+        id: ruleId,
+        name: `Review${category.charAt(0).toUpperCase()}${category.slice(1).replace(/[-_](\w)/g, (m, c) => c.toUpperCase())}`,
+        shortDescription: { text: `${category} review finding` },
+        defaultConfiguration: { level: 'warning' },
+        properties
+      });
+    }
+
+    const text = finding.suggestion
+      ? `${finding.description || category}\nSuggestion: ${finding.suggestion}`
+      : (finding.description || category);
+    const result = {
+      ruleId,
+      ruleIndex: ruleIndex.get(ruleId),
+      level: levelFor(finding.severity),
+      message: { text },
+      locations: [locationFor(repoPath, finding)],
+      properties: {
+        severity: finding.severity || null,
+        confidence: finding.confidence || null
+      }
+    };
+    // CWE/OWASP IDs from explicit fields or the description (security patterns carry them)
+    const tags = getSecurityTags([].concat(finding.cwe || [], finding.owasp || [], finding.description || '').join(' '));
+    if (tags.cwe.length > 0) result.properties.cwe = tags.cwe;
+    if (tags.owasp.length > 0) result.properties.owasp = tags.owasp;
+    results.push(result);
+
+    const ruleTags = rules[ruleIndex.get(ruleId)].properties.tags;
+    for (const tag of [
+      ...tags.cwe.map(id => `external/cwe/${id.toLowerCase()}`),
+      ...tags.owasp.map(id => `external/owasp/${id.toLowerCase()}`)
+    ]) {
+      if (!ruleTags.includes(tag)) ruleTags.push(tag);
+    }
+
+    // GitHub reads security-severity from the rule; use the worst finding's score
+    const score = category === 'security' && SECURITY_SEVERITY[finding.severity];
+    const ruleProperties = rules[ruleIndex.get(ruleId)].properties;
+    if (score && !(parseFloat(ruleProperties['security-severity']) >= parseFloat(score))) {
+      ruleProperties['security-severity'] = score;
+    }
+  }
+
+  const driver = {
+    name: 'awesome-slash review',
+    informationUri: INFORMATION_URI,
+    rules
+  };
+  if (options.toolVersion) driver.version = options.toolVersion;
+  return buildLog(driver, results);
+}
+
+module.exports = {
+  SARIF_VERSION,
+  SEVERITY_LEVELS,
+  slopToSarif,
+  reviewToSarif
+};
+
+// CLI usage
+if (require.main === module) {
+  const [kind, file] = process.argv.slice(2);
+  if (kind !== 'review' || !file) {
+    console.error('Usage: node sarif.js review <review-queue.json>');
+    process.exit(1);
+  }
+  try {
+    const queue = JSON.parse(fs.readFileSync(file, 'utf8'));
+    const findings = Array.isArray(queue) ? queue : (queue.items || []);
+    console.log(JSON.stringify(reviewToSarif(process.cwd(), findings), null, 2));
+  } catch (error) {
+    console.error(`Failed to convert ${file}: ${error.message}`);
+    process.exit(1);
+  }
+}
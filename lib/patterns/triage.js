@@ -0,0 +1,336 @@
+/**
+ * Interactive Finding Triage
+ *
+ * Walks scanner findings one at a time, `git add -p` style: each finding is
+ * shown with the code around it (the flagged line and the matched text
+ * highlighted), then a one-letter answer decides it:
+ *
+ *   f  fix it (auto-fixable patterns; `d` shows the patch first)
+ *   b  add it to the baseline, so later scans stop reporting it
+ *   o  open the file at the line in $VISUAL / $EDITOR
+ *   s  skip, p  previous, q  quit and apply, ?  help
+ *
+ * Decisions are collected and applied when the session ends: baseline
+ * entries first (they fingerprint the current line text), then every fix
+ * in one pass, so fixes in the same file don't shift each other's lines.
+ *
+ * @module patterns/triage
+ * @author Avi Fenesh
+ * @license MIT
+ */
+
+const fs = require('fs');
+const path = require('path');
+const { slopPatterns } = require('./slop-patterns');
+const { generateFixes, writeFixes } = require('./fixer');
+const { addToBaseline, BASELINE_FILE } = require('./baseline');
+const { t } = require('../messages');
+
+/**
+ * Answers and the message describing each
+ */
+const ACTIONS = {
+  f: 'triage.action.fix',
+  d: 'triage.action.diff',
+  b: 'triage.action.baseline',
+  o: 'triage.action.open',
+  s: 'triage.action.skip',
+  p: 'triage.action.previous',
+  q: 'triage.action.quit',
+  '?': 'triage.action.help'
+};
+
+/**
+ * Editors that take `+<line> <file>`
+ */
+const PLUS_LINE_EDITORS = ['vi', 'vim', 'nvim', 'gvim', 'mvim', 'nano', 'pico', 'emacs', 'emacsclient', 'micro', 'kak', 'joe', 'ne', 'mg'];
+
+/**
+ * Editors that take `-g <file>:<line>`
+ */
+const GOTO_EDITORS = ['code', 'code-insiders', 'codium', 'cursor', 'windsurf'];
+
+/**
+ * Editors that take `<file>:<line>`
+ */
+const COLON_EDITORS = ['subl', 'zed', 'hx', 'helix', 'mate'];
+
+/**
+ * ANSI styles, or none when color is off
+ * @param {boolean} color - Emit escape codes
+ * @returns {Object<string, Function>}
+ */
+function styles(color) {
+  const wrap = (open, close) => text => (color ? `\x1b[${open}m${text}\x1b[${close}m` : String(text));
+  return {
+    bold: wrap(1, 22),
+    dim: wrap(2, 22),
+    red: wrap(31, 39),
+    green: wrap(32, 39),
+    yellow: wrap(33, 39),
+    cyan: wrap(36, 39),
+    highlight: wrap('1;31', '22;39')
+  };
+}
+
+/**
+ * Read a finding's file as lines
+ * @param {string} repoPath - Repository root
+ * @param {string} file - Finding file
+ * @returns {string[]|null} null when unreadable
+ */
+function readLines(repoPath, file) {
+  try {
+    return fs.readFileSync(path.isAbsolute(file) ? file : path.join(repoPath, file), 'utf8').split(/\r?\n/);
+  } catch {
+    return null;
+  }
+}
+
+/**
+ * The part of a line a finding's pattern matched
+ * @param {Object} finding - Pipeline finding
+ * @param {string} line - Source line
+ * @returns {{start: number, end: number}|null}
+ */
+function matchSpan(finding, line) {
+  const pattern = slopPatterns[finding.patternName] && slopPatterns[finding.patternName].pattern;
+  if (!(pattern instanceof RegExp)) return null;
+  const match = new RegExp(pattern.source, pattern.flags.replace('g', '')).exec(line);
+  return match && match[0].length > 0 ? { start: match.index, end: match.index + match[0].length } : null;
+}
+
+/**
+ * Code around a finding, with line numbers
+ * @param {string} repoPath - Repository root
+ * @param {Object} finding - Pipeline finding
+ * @param {Object} [options]
+ * @param {number} [options.context=3] - Lines before and after
+ * @param {boolean} [options.color=false] - Highlight with ANSI escapes
+ * @returns {string} Empty when the file can't be read or the finding has no line
+ */
+function renderPreview(repoPath, finding, options = {}) {
+  const context = options.context === undefined ? 3 : options.context;
+  const style = styles(Boolean(options.color));
+  const lines = finding.line ? readLines(repoPath, finding.file) : null;
+  if (!lines) return '';
+
+  const start = Math.max(1, finding.line - context);
+  const end = Math.min(lines.length, (finding.details && finding.details.endLine) || finding.line);
+  const last = Math.min(lines.length, end + context);
+  const width = String(last).length;
+  const out = [];
+  for (let number = start; number <= last; number++) {
+    const text = lines[number - 1];
+    const flagged = number >= finding.line && number <= end;
+    const gutter = `${flagged ? '>' : ' '} ${String(number).padStart(width)} | `;
+    if (!flagged) {
+      out.push(style.dim(gutter) + text);
+      continue;
+    }
+    const span = matchSpan(finding, text);
+    const body = span
+      ? text.slice(0, span.start) + style.highlight(text.slice(span.start, span.end)) + text.slice(span.end)
+      : style.bold(text);
+    out.push(style.yellow(gutter) + body);
+  }
+  return out.join('\n');
+}
+
+/**
+ * Header for a finding: position, location, pattern, severity
+ * @param {Object} finding - Pipeline finding
+ * @param {number} index - 0-based position
+ * @param {number} total - Number of findings
+ * @param {string|null} decision - fix, baseline, or null
+ * @param {boolean} [color=false] - Use ANSI escapes
+ * @returns {string}
+ */
+function renderHeader(finding, index, total, decision, color = false) {
+  const style = styles(color);
+  const severityStyle = { critical: style.red, high: style.red, medium: style.yellow }[finding.severity] || style.dim;
+  const location = `${finding.file}${finding.line ? `:${finding.line}` : ''}`;
+  const marks = decision ? ` ${style.green(`[${decision}]`)}` : '';
+  return [
+    `${style.dim(`(${index + 1}/${total})`)} ${style.bold(location)} ${style.cyan(finding.patternName)} ${severityStyle(finding.severity)} ${style.dim(finding.certainty || '')}${marks}`,
+    finding.description ? style.dim(finding.description) : null
+  ].filter(Boolean).join('\n');
+}
+
+/**
+ * Command that opens an editor at a line
+ * Editors without a known line syntax just open the file.
+ * @param {string} file - Absolute or repo-relative path
+ * @param {number} [line] - 1-based line
+ * @param {Object} [env=process.env] - Reads VISUAL, then EDITOR
+ * @returns {string[]|null} argv, or null when no editor is set
+ */
+function editorCommand(file, line, env = process.env) {
+  const editor = String(env.VISUAL || env.EDITOR || '').trim();
+  if (!editor) return null;
+  const [program, ...flags] = editor.split(/\s+/);
+  const name = path.basename(program).replace(/\.(exe|cmd|bat)$/i, '').toLowerCase();
+  if (!line) return [program, ...flags, file];
+  if (PLUS_LINE_EDITORS.includes(name)) return [program, ...flags, `+${line}`, file];
+  if (GOTO_EDITORS.includes(name)) return [program, ...flags, '-g', `${file}:${line}`];
+  if (COLON_EDITORS.includes(name)) return [program, ...flags, `${file}:${line}`];
+  if (name === 'idea' || name === 'webstorm' || name === 'pycharm' || name === 'goland') {
+    return [program, ...flags, '--line', String(line), file];
+  }
+  return [program, ...flags, file];
+}
+
+/**
+ * Fix for a single finding, or why there is none
+ * @param {string} repoPath - Repository root
+ * @param {Object} finding - Pipeline finding
+ * @returns {{diff: string|null, reason: string|null}}
+ */
+function previewFix(repoPath, finding) {
+  const fixes = generateFixes(repoPath, [finding]);
+  if (fixes.files.length > 0) return { diff: fixes.files[0].diff, reason: null };
+  const skipped = fixes.skipped.find(entry => entry.finding === finding);
+  return { diff: null, reason: skipped ? skipped.reason : t('triage.noAutoFix', { pattern: finding.patternName }) };
+}
+
+/**
+ * Apply triage decisions
+ * @param {string} repoPath - Repository root
+ * @param {Array<{finding: Object, decision: string}>} decisions - fix or baseline
+ * @param {Object} [options]
+ * @param {string} [options.baselineFile=BASELINE_FILE] - Baseline path, relative to repoPath
+ * @param {Object} [options.journal] - Transaction from lib/journal `begin`; records the writes for rollback
+ * @returns {{baselined: number, baselineFile: string|null, fixed: number, filesChanged: number,
+ *   fixSkipped: Array<{finding: Object, reason: string}>, error: string|null}}
+ */
+function applyDecisions(repoPath, decisions, options = {}) {
+  const result = { baselined: 0, baselineFile: null, fixed: 0, filesChanged: 0, fixSkipped: [], error: null };
+  const toBaseline = decisions.filter(entry => entry.decision === 'baseline').map(entry => entry.finding);
+  const toFix = decisions.filter(entry => entry.decision === 'fix').map(entry => entry.finding);
+
+  if (toBaseline.length > 0) {
+    if (options.journal) options.journal.track(path.resolve(repoPath, options.baselineFile || BASELINE_FILE));
+    const written = addToBaseline(repoPath, toBaseline, options.baselineFile || BASELINE_FILE);
+    if (written.error) {
+      result.error = written.error;
+    } else {
+      result.baselined = written.findings;
+      result.baselineFile = written.file;
+    }
+  }
+  if (toFix.length > 0) {
+    const fixes = generateFixes(repoPath, toFix);
+    const { filesChanged, findingsFixed } = writeFixes(repoPath, fixes, { journal: options.journal });
+    result.fixed = findingsFixed;
+    result.filesChanged = filesChanged;
+    result.fixSkipped = fixes.skipped;
+  }
+  return result;
+}
+
+/**
+ * Run an interactive triage session
+ * @param {string} repoPath - Repository root
+ * @param {Object[]} findings - Pipeline findings
+ * @param {Object} io - Terminal access
+ * @param {Function} io.ask - `async (question) => answer`; null or undefined means input ended
+ * @param {Function} io.write - Prints a block of text
+ * @param {Function} [io.open] - `async (argv) => void`; runs the editor
+ * @param {Object} [options]
+ * @param {boolean} [options.color=false] - Highlight with ANSI escapes
+ * @param {number} [options.context=3] - Preview lines around each finding
+ * @param {string} [options.baselineFile] - Baseline path, relative to repoPath
+ * @param {Object} [options.journal] - Transaction from lib/journal `begin`
+ * @param {Object} [options.env=process.env] - For the editor lookup
+ * @returns {Promise<Object>} applyDecisions result plus `reviewed` (findings shown)
+ */
+async function runTriage(repoPath, findings, io, options = {}) {
+  const style = styles(Boolean(options.color));
+  const decisions = new Map();
+  const choices = Object.keys(ACTIONS).join(',');
+  let index = 0;
+  let reviewed = 0;
+  let shown = -1;
+
+  while (index >= 0 && index < findings.length) {
+    const finding = findings[index];
+    if (shown !== index) {
+      const preview = renderPreview(repoPath, finding, options);
+      io.write(`\n${renderHeader(finding, index, findings.length, decisions.get(index), options.color)}${preview ? `\n${preview}` : ''}`);
+      shown = index;
+      reviewed = Math.max(reviewed, index + 1);
+    }
+
+    const answer = await io.ask(style.bold(t('triage.prompt', { choices })));
+    if (answer === null || answer === undefined) break;
+    const key = String(answer).trim().toLowerCase().charAt(0);
+
+    if (key === 'f') {
+      const fix = previewFix(repoPath, finding);
+      if (fix.diff) {
+        decisions.set(index, 'fix');
+        index++;
+      } else {
+        io.write(style.yellow(t('triage.noFixHint', { reason: fix.reason })));
+      }
+    } else if (key === 'd') {
+      const fix = previewFix(repoPath, finding);
+      io.write(fix.diff ? fix.diff.trimEnd() : style.yellow(t('triage.noFix', { reason: fix.reason })));
+    } else if (key === 'b') {
+      decisions.set(index, 'baseline');
+      index++;
+    } else if (key === 'o') {
+      const argv = editorCommand(path.join(repoPath, finding.file), finding.line, options.env || process.env);
+      if (!argv || !io.open) {
+        io.write(t('triage.noEditor', { location: `${path.join(repoPath, finding.file)}:${finding.line || 1}` }));
+      } else {
+        await io.open(argv);
+        shown = -1;
+      }
+    } else if (key === 's' || key === 'n') {
+      decisions.delete(index);
+      index++;
+    } else if (key === 'p' || key === 'k') {
+      if (index === 0) io.write(t('triage.noPrevious'));
+      else index--;
+    } else if (key === 'q') {
+      break;
+    } else {
+      io.write(Object.entries(ACTIONS).map(([letter, key]) => `${letter} - ${t(key)}`).join('\n'));
+    }
+  }
+
+  const ordered = Array.from(decisions.entries())
+    .sort((a, b) => a[0] - b[0])
+    .map(([position, decision]) => ({ finding: findings[position], decision }));
+  return {
+    ...applyDecisions(repoPath, ordered, { baselineFile: options.baselineFile, journal: options.journal }),
+    reviewed
+  };
+}
+
+/**
+ * One-line summary of a finished session
+ * @param {Object} result - runTriage result
+ * @param {number} total - Number of findings
+ * @returns {string}
+ */
+function describeTriage(result, total) {
+  const parts = [t('triage.summary.reviewed', { reviewed: result.reviewed, total })];
+  if (result.fixed > 0) parts.push(t('triage.summary.fixed', { fixed: result.fixed, count: result.filesChanged }));
+  if (result.baselined > 0) parts.push(t('triage.summary.baselined', { count: result.baselined, file: path.basename(result.baselineFile) }));
+  if (result.fixSkipped.length > 0) parts.push(t('triage.summary.notApplied', { count: result.fixSkipped.length }));
+  if (result.error) parts.push(t('triage.summary.baselineError', { error: result.error }));
+  return parts.join(', ');
+}
+
+module.exports = {
+  ACTIONS,
+  renderPreview,
+  renderHeader,
+  editorCommand,
+  previewFix,
+  applyDecisions,
+  runTriage,
+  describeTriage
+};
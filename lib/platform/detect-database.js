@@ -0,0 +1,452 @@
+#!/usr/bin/env node
+/**
+ * Database and ORM Detection
+ * Identifies databases, the ORM in use, and migration directories so review
+ * and ship workflows can apply migration-safety checks
+ *
+ * Usage: node lib/platform/detect-database.js [path]
+ * Output: JSON with detected databases, ORMs, and migrations (or null)
+ *
+ * @module lib/platform/detect-database
+ */
+
+const fs = require('fs');
+const path = require('path');
+
+const { DATABASE_CONFIGS, ORM_CONFIGS } = require('./detection-configs');
+
+const fsPromises = fs.promises;
+
+/**
+ * Maximum manifest size to read (1MB)
+ */
+const MAX_FILE_SIZE_BYTES = 1024 * 1024;
+
+/**
+ * Read a small text file relative to the project root
+ * @param {string} basePath - Project root
+ * @param {string} file - Relative path
+ * @returns {Promise<string|null>} Content, or null if missing or too large
+ */
+async function readText(basePath, file) {
+  try {
+    const filePath = path.join(basePath, file);
+    const stats = await fsPromises.stat(filePath);
+    if (!stats.isFile() || stats.size > MAX_FILE_SIZE_BYTES) return null;
+    return await fsPromises.readFile(filePath, 'utf8');
+  } catch {
+    return null;
+  }
+}
+
+/**
+ * List entries of a directory relative to the project root
+ * @param {string} basePath - Project root
+ * @param {string} dir - Relative directory
+ * @returns {Promise<fs.Dirent[]>} Entries, or [] if the directory is missing
+ */
+async function listDir(basePath, dir) {
+  try {
+    return await fsPromises.readdir(path.join(basePath, dir), { withFileTypes: true });
+  } catch {
+    return [];
+  }
+}
+
+/**
+ * Normalize a Python distribution name (PEP 503)
+ * @param {string} name - Package name
+ * @returns {string}
+ */
+function normalizePythonName(name) {
+  return name.toLowerCase().replace(/[-_.]+/g, '-');
+}
+
+/**
+ * Dependency names from package.json
+ * @param {string} content - package.json content
+ * @returns {string[]}
+ */
+function parseNpmDependencies(content) {
+  let pkg;
+  try {
+    pkg = JSON.parse(content);
+  } catch {
+    return [];
+  }
+  const sections = ['dependencies', 'devDependencies', 'peerDependencies', 'optionalDependencies'];
+  return sections.flatMap(section =>
+    pkg && typeof pkg[section] === 'object' && pkg[section] ? Object.keys(pkg[section]) : []);
+}
+
+/**
+ * Dependency names from requirements.txt, pyproject.toml, or Pipfile
+ * TOML files are read per table: keys of dependency tables (Poetry, Pipfile)
+ * and quoted requirement strings inside dependency arrays (PEP 621, groups).
+ * @param {string} content - File content
+ * @param {string} file - File name, used to pick the format
+ * @returns {string[]} Normalized names
+ */
+function parsePythonDependencies(content, file) {
+  const names = [];
+  const requirement = /^\s*([A-Za-z0-9][A-Za-z0-9._-]*)/;
+
+  if (file.endsWith('.txt')) {
+    for (const line of content.split('\n')) {
+      const text = line.replace(/#.*/, '');
+      if (/^\s*-/.test(text)) continue;
+      const match = text.match(requirement);
+      if (match) names.push(normalizePythonName(match[1]));
+    }
+    return names;
+  }
+
+  let table = '';
+  let inArray = false;
+  for (const line of content.split('\n')) {
+    const text = line.replace(/#.*/, '').trim();
+    const header = text.match(/^\[+([^\]]+)\]+$/);
+    if (header) {
+      table = header[1].trim();
+      inArray = false;
+      continue;
+    }
+    const dependencyTable = /dependencies|dependency-groups|^packages$|^dev-packages$/.test(table);
+    const assignment = text.match(/^["']?([A-Za-z0-9][A-Za-z0-9._-]*)["']?\s*=\s*(.*)$/);
+
+    if (!inArray && assignment) {
+      const value = assignment[2];
+      if (value.startsWith('[') && (dependencyTable || (table === 'project' && assignment[1] === 'dependencies'))) {
+        inArray = true;
+      } else if (dependencyTable && !value.startsWith('[')) {
+        if (assignment[1] !== 'python') names.push(normalizePythonName(assignment[1]));
+        continue;
+      }
+    }
+    if (inArray) {
+      for (const match of text.matchAll(/["']([A-Za-z0-9][A-Za-z0-9._-]*)[^"']*["']/g)) {
+        names.push(normalizePythonName(match[1]));
+      }
+      if (text.replace(/(["'])(?:(?!\1).)*\1/g, '').includes(']')) inArray = false;
+    }
+  }
+  return names;
+}
+
+/**
+ * Module paths required by go.mod
+ * @param {string} content - go.mod content
+ * @returns {string[]}
+ */
+function parseGoModules(content) {
+  const modules = [];
+  let inBlock = false;
+  for (const line of content.split('\n')) {
+    const text = line.replace(/\/\/.*/, '').trim();
+    if (/^require\s*\($/.test(text)) {
+      inBlock = true;
+      continue;
+    }
+    if (inBlock && text === ')') {
+      inBlock = false;
+      continue;
+    }
+    const match = inBlock ? text.match(/^(\S+)\s+\S+/) : text.match(/^require\s+(\S+)\s+\S+/);
+    if (match) modules.push(match[1]);
+  }
+  return modules;
+}
+
+/**
+ * Gem names from a Gemfile
+ * @param {string} content - Gemfile content
+ * @returns {string[]}
+ */
+function parseGemfile(content) {
+  return Array.from(content.matchAll(/^\s*gem\s+['"]([^'"]+)['"]/gm), match => match[1]);
+}
+
+/**
+ * Crate names from Cargo.toml dependency tables
+ * @param {string} content - Cargo.toml content
+ * @returns {string[]}
+ */
+function parseCargoDependencies(content) {
+  const crates = [];
+  let inDependencies = false;
+  for (const line of content.split('\n')) {
+    const text = line.replace(/#.*/, '').trim();
+    const header = text.match(/^\[([^\]]+)\]$/);
+    if (header) {
+      const table = header[1].trim();
+      const dotted = table.match(/(?:^|\.)(?:dev-|build-)?dependencies\.([\w-]+)$/);
+      if (dotted) crates.push(dotted[1]);
+      inDependencies = !dotted && /(?:^|\.)(?:dev-|build-)?dependencies$/.test(table);
+      continue;
+    }
+    const key = inDependencies && text.match(/^([\w-]+)\s*=/);
+    if (key) crates.push(key[1]);
+  }
+  return crates;
+}
+
+/**
+ * Collect declared dependencies per ecosystem from root manifests
+ * @param {string} basePath - Project root
+ * @returns {Promise<Array<{ecosystem: string, name: string, file: string}>>}
+ */
+async function collectDependencies(basePath) {
+  const requirementFiles = (await listDir(basePath, '.'))
+    .filter(entry => entry.isFile() && /^requirements[\w.-]*\.txt$/.test(entry.name))
+    .map(entry => entry.name)
+    .sort();
+
+  const sources = [
+    { file: 'package.json', ecosystem: 'npm', parse: parseNpmDependencies },
+    ...requirementFiles.map(file => ({ file, ecosystem: 'python', parse: parsePythonDependencies })),
+    { file: 'pyproject.toml', ecosystem: 'python', parse: parsePythonDependencies },
+    { file: 'Pipfile', ecosystem: 'python', parse: parsePythonDependencies },
+    { file: 'go.mod', ecosystem: 'go', parse: parseGoModules },
+    { file: 'Gemfile', ecosystem: 'ruby', parse: parseGemfile },
+    { file: 'Cargo.toml', ecosystem: 'rust', parse: parseCargoDependencies }
+  ];
+
+  const contents = await Promise.all(sources.map(({ file }) => readText(basePath, file)));
+  const dependencies = [];
+  sources.forEach(({ file, ecosystem, parse }, i) => {
+    if (contents[i] === null) return;
+    for (const name of new Set(parse(contents[i], file))) {
+      dependencies.push({ ecosystem, name, file });
+    }
+  });
+  return dependencies;
+}
+
+/**
+ * Check whether a dependency matches a configured name
+ * Go module paths also match their subpaths and major versions.
+ * @param {string} ecosystem - Dependency ecosystem
+ * @param {string} name - Declared dependency
+ * @param {string} expected - Configured name
+ * @returns {boolean}
+ */
+function dependencyMatches(ecosystem, name, expected) {
+  if (ecosystem === 'go') return name === expected || name.startsWith(`${expected}/`);
+  if (ecosystem === 'python') return name === normalizePythonName(expected);
+  return name === expected;
+}
+
+/**
+ * Services and images from a Compose file
+ * @param {string} content - Compose YAML
+ * @returns {Array<{service: string, image: string}>}
+ */
+function parseComposeServices(content) {
+  const services = [];
+  let inServices = false;
+  let serviceIndent = null;
+  let propertyIndent = null;
+  let service = null;
+  for (const line of content.split('\n')) {
+    if (/^\s*(#|$)/.test(line)) continue;
+    if (/^\S/.test(line)) {
+      inServices = /^services:\s*$/.test(line);
+      serviceIndent = null;
+      service = null;
+      continue;
+    }
+    if (!inServices) continue;
+
+    const indent = line.match(/^\s*/)[0].length;
+    const key = line.match(/^\s*["']?([\w.-]+)["']?:\s*$/);
+    if (key && (serviceIndent === null || indent === serviceIndent)) {
+      serviceIndent = indent;
+      propertyIndent = null;
+      service = key[1];
+      continue;
+    }
+    if (!service || indent <= serviceIndent) continue;
+    // Only the service's own `image:`, not keys nested under environment etc.
+    if (propertyIndent === null) propertyIndent = indent;
+    const image = line.match(/^\s+image:\s*["']?([^\s"'#]+)/);
+    if (image && indent === propertyIndent) services.push({ service, image: image[1] });
+  }
+  return services;
+}
+
+/**
+ * Strip registry, tag, and digest from an image reference
+ * @param {string} image - Image reference (e.g. `docker.io/library/postgres:16`)
+ * @returns {string} Repository path (e.g. `library/postgres`)
+ */
+function normalizeImage(image) {
+  let name = image.toLowerCase().split('@')[0];
+  const parts = name.split('/');
+  if (parts.length > 1 && /[.:]|^localhost$/.test(parts[0])) parts.shift();
+  name = parts.join('/');
+  return name.replace(/:[^/]*$/, '');
+}
+
+/**
+ * Database named by ORM or schema configuration
+ * @param {string} orm - ORM name
+ * @param {string} content - Schema or config file content
+ * @returns {string|null}
+ */
+function dialectFromConfig(orm, content) {
+  const patterns = {
+    prisma: /datasource\s+\w+\s*\{[^}]*?provider\s*=\s*"(\w+)"/,
+    drizzle: /(?:dialect|driver)\s*:\s*['"]([\w-]+)['"]/,
+    sqlalchemy: /^\s*sqlalchemy\.url\s*=\s*(\w+)/m,
+    activerecord: /^\s*adapter:\s*["']?(\w+)/m
+  };
+  const match = patterns[orm] && content.match(patterns[orm]);
+  return match ? DATABASE_CONFIGS.dialects[match[1].toLowerCase()] || null : null;
+}
+
+/**
+ * Detect ORMs from dependencies and marker files
+ * @param {string} basePath - Project root
+ * @param {Array<Object>} dependencies - Result of `collectDependencies`
+ * @returns {Promise<Array<{name: string, database: string|null, sources: string[]}>>}
+ */
+async function detectOrms(basePath, dependencies) {
+  const orms = [];
+  for (const config of ORM_CONFIGS) {
+    const sources = dependencies
+      .filter(dep => dep.ecosystem === config.ecosystem &&
+        config.dependencies.some(expected => dependencyMatches(dep.ecosystem, dep.name, expected)))
+      .map(dep => `${dep.file}:${dep.name}`);
+
+    let database = null;
+    for (const file of config.files) {
+      const content = await readText(basePath, file);
+      if (content === null) continue;
+      sources.push(file);
+      database = database || dialectFromConfig(config.name, content);
+    }
+    if (sources.length === 0) continue;
+
+    if (!database && config.name === 'gorm') {
+      const driver = dependencies.find(dep => dep.ecosystem === 'go' && dep.name.startsWith('gorm.io/driver/'));
+      database = driver ? DATABASE_CONFIGS.dialects[driver.name.split('/')[2]] || null : null;
+    }
+    orms.push({ name: config.name, database, sources });
+  }
+  return orms;
+}
+
+/**
+ * Guess the migration tool from a generic directory's contents
+ * @param {fs.Dirent[]} entries - Directory entries
+ * @returns {string|null}
+ */
+function inferMigrationTool(entries) {
+  const names = entries.filter(entry => entry.isFile()).map(entry => entry.name);
+  if (names.includes('__init__.py')) return 'django';
+  if (names.some(name => /\.up\.sql$/.test(name))) return 'golang-migrate';
+  return null;
+}
+
+/**
+ * Detect migration directories
+ * A directory is skipped when it contains one that was already claimed
+ * (e.g. `migrations/` around Alembic's `migrations/versions/`).
+ * @param {string} basePath - Project root
+ * @returns {Promise<Array<{path: string, tool: string|null}>>}
+ */
+async function detectMigrations(basePath) {
+  const migrations = [];
+  const claimed = dir => migrations.some(({ path: found }) => found === dir || found.startsWith(`${dir}/`));
+
+  for (const { dir, tool } of DATABASE_CONFIGS.migrationDirs) {
+    if (claimed(dir)) continue;
+    const entries = await listDir(basePath, dir);
+    if (entries.length === 0) continue;
+    migrations.push({ path: dir, tool: tool || inferMigrationTool(entries) });
+  }
+
+  // Django apps keep migrations next to models: <app>/migrations/__init__.py
+  for (const entry of await listDir(basePath, '.')) {
+    if (!entry.isDirectory() || entry.name.startsWith('.')) continue;
+    const dir = `${entry.name}/migrations`;
+    if (claimed(dir)) continue;
+    const entries = await listDir(basePath, dir);
+    if (entries.some(child => child.isFile() && child.name === '__init__.py')) {
+      migrations.push({ path: dir, tool: 'django' });
+    }
+  }
+
+  return migrations.sort((a, b) => a.path.localeCompare(b.path));
+}
+
+/**
+ * Detect databases, ORMs, and migration directories
+ * @param {string} [basePath=process.cwd()] - Project root (or a workspace package)
+ * @returns {Promise<Object|null>} `{databases: [{name, sources}], orms, migrations}` or null when nothing is found
+ */
+async function detectDatabases(basePath = process.cwd()) {
+  const found = new Map();
+  const addSource = (database, source) => {
+    if (!found.has(database)) found.set(database, new Set());
+    found.get(database).add(source);
+  };
+
+  const dependencies = await collectDependencies(basePath);
+  for (const dep of dependencies) {
+    const known = DATABASE_CONFIGS.dependencies[dep.ecosystem] || {};
+    const database = Object.keys(known).find(expected => dependencyMatches(dep.ecosystem, dep.name, expected));
+    if (database) addSource(known[database], `${dep.file}:${dep.name}`);
+  }
+
+  for (const file of DATABASE_CONFIGS.composeFiles) {
+    const content = await readText(basePath, file);
+    if (content === null) continue;
+    for (const { service, image } of parseComposeServices(content)) {
+      const match = DATABASE_CONFIGS.composeImages.find(({ pattern }) => pattern.test(normalizeImage(image)));
+      if (match) addSource(match.database, `${file}:${service}`);
+    }
+  }
+
+  const orms = await detectOrms(basePath, dependencies);
+  for (const orm of orms) {
+    const configured = orm.sources.find(source => !source.includes(':'));
+    if (orm.database && configured) addSource(orm.database, configured);
+  }
+
+  const migrations = await detectMigrations(basePath);
+
+  if (found.size === 0 && orms.length === 0 && migrations.length === 0) {
+    return null;
+  }
+
+  const databases = DATABASE_CONFIGS.types
+    .filter(name => found.has(name))
+    .map(name => ({ name, sources: Array.from(found.get(name)) }));
+
+  return { databases, orms, migrations };
+}
+
+// When run directly, output JSON
+if (require.main === module) {
+  detectDatabases(process.argv[2] || process.cwd()).then(result => {
+    const indent = process.stdout.isTTY ? 2 : 0;
+    console.log(JSON.stringify(result, null, indent));
+  });
+}
+
+module.exports = {
+  detectDatabases,
+  collectDependencies,
+  dependencyMatches,
+  normalizePythonName,
+  readText,
+  listDir,
+  detectOrms,
+  detectMigrations,
+  parsePythonDependencies,
+  parseGoModules,
+  parseCargoDependencies,
+  parseComposeServices,
+  normalizeImage
+};
@@ -0,0 +1,310 @@
+#!/usr/bin/env node
+/**
+ * Release Planning and Version Bumping
+ *
+ * Computes the next semver from conventional commits since the last tag,
+ * finds the version files for the project type (package.json, Cargo.toml,
+ * pyproject.toml, version.go, ...), and builds the tag and forge release
+ * steps. `planRelease` only reads; `applyRelease` writes the version files,
+ * and the tag and forge commands are returned for the caller to run so
+ * `--dry-run` and the real run share one plan.
+ *
+ * Usage: node lib/release/index.js [--bump major|minor|patch] [--version <v>] [--from <ref>] [--prerelease <id>]
+ * Output: JSON release plan
+ *
+ * @module lib/release
+ */
+
+const fs = require('fs');
+const path = require('path');
+
+const changelog = require('../changelog');
+const { getStateDir } = require('../platform/state-dir');
+
+const SEMVER = /^v?(\d+)\.(\d+)\.(\d+)(?:-([0-9A-Za-z.-]+))?(?:\+[0-9A-Za-z.-]+)?$/;
+
+const BUMPS = ['major', 'minor', 'patch'];
+
+/**
+ * Version files per project type
+ * `pattern` captures the text before the version (1) and the version (2);
+ * the first matching file read sets the current version. TOML patterns stay
+ * inside their table: they stop at the next `[section]` header, not at a `[`
+ * that opens an array value.
+ */
+const VERSION_FILES = {
+  nodejs: [
+    { file: 'package.json', pattern: /^(\s*"version"\s*:\s*")([^"]+)"/m },
+    { file: 'package-lock.json', pattern: /^( {2}"version"\s*:\s*")([^"]+)"/m }
+  ],
+  rust: [
+    { file: 'Cargo.toml', pattern: /^(\[package\](?:(?!^\[)[\s\S])*?^version\s*=\s*")([^"]+)"/m }
+  ],
+  python: [
+    { file: 'pyproject.toml', pattern: /^(\[(?:project|tool\.poetry)\](?:(?!^\[)[\s\S])*?^version\s*=\s*["'])([^"']+)["']/m },
+    { file: 'setup.py', pattern: /(\bversion\s*=\s*["'])([^"']+)["']/ },
+    { file: 'setup.cfg', pattern: /^(version\s*=\s*)(\S+)$/m }
+  ],
+  go: [
+    { file: 'version.go', pattern: /(\bVersion\s*(?:string\s*)?=\s*")v?([^"]+)"/ },
+    { file: 'internal/version/version.go', pattern: /(\bVersion\s*(?:string\s*)?=\s*")v?([^"]+)"/ }
+  ],
+  // Maven versions live beside parent and dependency versions; use `mvn versions:set`
+  java: [
+    { file: 'gradle.properties', pattern: /^(version\s*=\s*)(\S+)$/m }
+  ]
+};
+
+/**
+ * Forge CLIs that draft a release from a notes file
+ */
+const FORGE_COMMANDS = {
+  github: (tag, notesFile) => ['gh', 'release', 'create', tag, '--draft', '--title', tag, '--notes-file', notesFile],
+  gitlab: (tag, notesFile) => ['glab', 'release', 'create', tag, '--name', tag, '--notes-file', notesFile]
+};
+
+/**
+ * Parse a semver string
+ * @param {string} version - e.g. `1.2.3`, `v2.0.0-rc.1`
+ * @returns {{major: number, minor: number, patch: number, prerelease: string|null}|null}
+ */
+function parseSemver(version) {
+  const match = String(version || '').trim().match(SEMVER);
+  if (!match) return null;
+  return { major: Number(match[1]), minor: Number(match[2]), patch: Number(match[3]), prerelease: match[4] || null };
+}
+
+/**
+ * Bump level implied by parsed commits
+ * Breaking changes are major (minor before 1.0.0), features minor, fixes
+ * and performance work patch; nothing releasable returns null.
+ * @param {Object[]} commits - Results of changelog.parseCommit
+ * @param {string} current - Current version
+ * @returns {string|null} `major`, `minor`, `patch`, or null
+ */
+function recommendBump(commits, current) {
+  const parsed = parseSemver(current);
+  if (commits.some(commit => commit.breaking)) return parsed && parsed.major === 0 ? 'minor' : 'major';
+  if (commits.some(commit => commit.type === 'feat')) return 'minor';
+  if (commits.some(commit => ['fix', 'perf', 'revert', 'security'].includes(commit.type))) return 'patch';
+  return null;
+}
+
+/**
+ * Next version for a bump
+ * @param {string} current - Current version
+ * @param {string} bump - `major`, `minor`, or `patch`
+ * @param {string} [prerelease] - Prerelease id (`rc` gives `1.3.0-rc.0`, then `-rc.1`)
+ * @returns {string|null}
+ */
+function nextVersion(current, bump, prerelease) {
+  const parsed = parseSemver(current);
+  if (!parsed || !BUMPS.includes(bump) || (prerelease && !/^[0-9A-Za-z-]+$/.test(prerelease))) return null;
+  let { major, minor, patch } = parsed;
+
+  // Graduating or continuing a prerelease of the same target
+  if (parsed.prerelease) {
+    const base = `${major}.${minor}.${patch}`;
+    const isTarget = bump === 'patch' || (bump === 'minor' && patch === 0) || (bump === 'major' && minor === 0 && patch === 0);
+    if (isTarget) {
+      if (!prerelease) return base;
+      const match = parsed.prerelease.match(new RegExp(`^${prerelease}\\.(\\d+)$`));
+      return `${base}-${prerelease}.${match ? Number(match[1]) + 1 : 0}`;
+    }
+  }
+
+  if (bump === 'major') [major, minor, patch] = [major + 1, 0, 0];
+  else if (bump === 'minor') [minor, patch] = [minor + 1, 0];
+  else if (!parsed.prerelease) patch += 1;
+  const base = `${major}.${minor}.${patch}`;
+  return prerelease ? `${base}-${prerelease}.0` : base;
+}
+
+/**
+ * Version files present for a project type, with their current versions
+ * @param {string} basePath - Project root
+ * @param {string} projectType - From detectProjectType
+ * @param {Function} [readFile] - (file) => content or null (for testing)
+ * @returns {Array<{file: string, version: string}>}
+ */
+function findVersionFiles(basePath, projectType, readFile) {
+  const read = readFile || (file => {
+    try {
+      return fs.readFileSync(path.join(basePath, file), 'utf8');
+    } catch {
+      return null;
+    }
+  });
+  const found = [];
+  for (const config of VERSION_FILES[projectType] || []) {
+    const content = read(config.file);
+    if (content === null || content === undefined) continue;
+    const match = content.match(config.pattern);
+    if (match && !/^\$\{|^\{\{/.test(match[2])) found.push({ file: config.file, version: match[2].trim() });
+  }
+  return found;
+}
+
+/**
+ * Replace the version in a file's content
+ * @param {string} projectType - From detectProjectType
+ * @param {string} file - Version file path
+ * @param {string} content - Current content
+ * @param {string} version - New version
+ * @returns {string|null} Updated content, or null when the file has no version to replace
+ */
+function bumpContent(projectType, file, content, version) {
+  const config = (VERSION_FILES[projectType] || []).find(candidate => candidate.file === file);
+  if (!config || !config.pattern.test(content)) return null;
+  if (file === 'package-lock.json') {
+    // The lockfile's own version and its root package entry (`packages[""]`), not dependencies
+    const root = content.match(/("packages"\s*:\s*\{\s*""\s*:\s*\{[\s\S]*?"version"\s*:\s*")([^"]+)"/);
+    let updated = content.replace(config.pattern, (_, before) => `${before}${version}"`);
+    if (root) updated = updated.replace(root[0], `${root[1]}${version}"`);
+    return updated;
+  }
+  return content.replace(config.pattern, (whole, before, old) => {
+    // Keep a `v` prefix a Go constant already uses
+    const prefix = whole.slice(before.length).startsWith('v') ? 'v' : '';
+    return whole.replace(`${before}${prefix}${old}`, `${before}${prefix}${version}`);
+  });
+}
+
+/**
+ * Plan a release: version, files to bump, notes, tag, and forge release
+ * @param {string} basePath - Repository root
+ * @param {Object} options
+ * @param {string} options.projectType - From detectProjectType
+ * @param {string|null} [options.ciPlatform] - From detectCI, for forge detection
+ * @param {string} [options.bump] - Force a bump level
+ * @param {string} [options.version] - Force the version
+ * @param {string} [options.prerelease] - Prerelease id
+ * @param {string} [options.from] - Start ref (defaults to the last tag)
+ * @param {string} [options.date] - Release date for the notes
+ * @returns {Object} `{success, current, bump, version, tag, files, notes, forge, commands, error}`
+ */
+function planRelease(basePath, options = {}) {
+  const range = changelog.getCommits(basePath, { from: options.from });
+  if (!range) return { success: false, error: 'Could not read git history since the last tag' };
+
+  const files = findVersionFiles(basePath, options.projectType);
+  const tagVersion = range.from && parseSemver(range.from) ? range.from.replace(/^v/, '') : null;
+  const current = (files[0] && files[0].version) || tagVersion || '0.0.0';
+
+  const repository = changelog.getRepository(basePath, options.ciPlatform);
+  const commits = range.commits.map(commit => changelog.parseCommit(commit, repository && repository.host));
+
+  const bump = options.bump || recommendBump(commits, current);
+  let version = options.version ? options.version.replace(/^v/, '') : null;
+  if (!version && bump) version = nextVersion(current, bump, options.prerelease);
+  if (!version) {
+    return {
+      success: false,
+      current,
+      commits: commits.length,
+      error: commits.length === 0 ? `No commits since ${range.from || 'the first commit'}` : 'No feat, fix or breaking commits to release; pass --bump to force one'
+    };
+  }
+  if (!parseSemver(version)) return { success: false, current, error: `Not a semver version: ${version}` };
+
+  // Tags follow the existing convention; `v` when there are none yet
+  const tag = `${range.from && !range.from.startsWith('v') && parseSemver(range.from) ? '' : 'v'}${version}`;
+  const compare = changelog.compareUrl(repository, range.from, version, tag);
+  const notes = changelog.renderRelease(commits, { version, date: options.date, repository, compare });
+  const forge = repository && FORGE_COMMANDS[repository.host] ? repository.host : null;
+  // Kept out of the worktree root so a failed run does not leave it behind
+  const notesFile = `${getStateDir(basePath)}/release-notes.md`;
+  // With no version file or CHANGELOG.md to write there is nothing to commit; HEAD is tagged as is
+  const staged = [...files.map(({ file }) => file), ...(fs.existsSync(path.join(basePath, 'CHANGELOG.md')) ? ['CHANGELOG.md'] : [])];
+
+  return {
+    success: true,
+    current,
+    bump: bump || null,
+    version,
+    tag,
+    from: range.from,
+    commits: commits.length,
+    files: files.map(({ file, version: from }) => ({ file, from, to: version })),
+    notes,
+    compare,
+    forge,
+    notesFile,
+    commands: [
+      ...(staged.length > 0 ? [['git', 'add', ...staged], ['git', 'commit', '-m', `chore(release): ${tag}`]] : []),
+      ['git', 'tag', '-a', tag, '-m', tag],
+      ['git', 'push', '--follow-tags'],
+      ...(forge ? [FORGE_COMMANDS[forge](tag, notesFile)] : [])
+    ]
+  };
+}
+
+/**
+ * Write the version files and CHANGELOG.md for a plan
+ * @param {string} basePath - Repository root
+ * @param {Object} plan - Result of planRelease
+ * @param {Object} options
+ * @param {string} options.projectType - From detectProjectType
+ * @param {Object} [options.journal] - Transaction from lib/journal `begin`; records (or, in a dry run, only plans) the writes
+ * @returns {{written: string[], skipped: string[]}}
+ */
+function applyRelease(basePath, plan, options = {}) {
+  const write = (file, content) => (options.journal ? options.journal.writeFile(path.resolve(file), content) : fs.writeFileSync(file, content));
+  const written = [];
+  const skipped = [];
+  for (const { file } of plan.files) {
+    const filePath = path.join(basePath, file);
+    const updated = bumpContent(options.projectType, file, fs.readFileSync(filePath, 'utf8'), plan.version);
+    if (updated === null) {
+      skipped.push(file);
+      continue;
+    }
+    write(filePath, updated);
+    written.push(file);
+  }
+
+  const changelogPath = path.join(basePath, 'CHANGELOG.md');
+  if (plan.notes && fs.existsSync(changelogPath)) {
+    const existing = fs.readFileSync(changelogPath, 'utf8');
+    write(changelogPath, changelog.updateChangelog(existing, plan.notes, { version: plan.version, compare: plan.compare }));
+    written.push('CHANGELOG.md');
+  }
+  if (plan.forge) {
+    const notesPath = path.join(basePath, plan.notesFile);
+    if (!options.journal) fs.mkdirSync(path.dirname(notesPath), { recursive: true });
+    write(notesPath, plan.notes || `Release ${plan.tag}\n`);
+  }
+  return { written, skipped };
+}
+
+// When run directly, output the plan as JSON
+if (require.main === module) {
+  const args = process.argv.slice(2);
+  const value = flag => (args.includes(flag) ? args[args.indexOf(flag) + 1] : undefined);
+  const { detectProjectType, detectCI } = require('../platform/detect-platform');
+
+  Promise.all([detectProjectType(), detectCI().catch(() => null)]).then(([projectType, ciPlatform]) => {
+    const plan = planRelease(process.cwd(), {
+      projectType,
+      ciPlatform,
+      bump: value('--bump'),
+      version: value('--version'),
+      prerelease: value('--prerelease'),
+      from: value('--from')
+    });
+    const indent = process.stdout.isTTY ? 2 : 0;
+    console.log(JSON.stringify({ projectType, ...plan }, null, indent));
+    if (!plan.success) process.exitCode = 1;
+  });
+}
+
+module.exports = {
+  VERSION_FILES,
+  parseSemver,
+  recommendBump,
+  nextVersion,
+  findVersionFiles,
+  bumpContent,
+  planRelease,
+  applyRelease
+};
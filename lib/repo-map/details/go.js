@@ -0,0 +1,326 @@
+/**
+ * Go symbol details (receivers, struct fields, interface method sets)
+ *
+ * Methods are keyed `Receiver.Method` in the function map so methods that
+ * share a name on different types stay distinct; `lib/repo-map/golang`
+ * later groups them under their receiver types package-wide.
+ *
+ * @module lib/repo-map/details/go
+ */
+
+'use strict';
+
+const { splitArgs, maskComments, findClosing, createLineLookup, compact, skipWhitespace } = require('./utils');
+
+const FUNC_DECLARATION = /^func[ \t]*(\([^)]*\))?[ \t]*([A-Za-z_]\w*)[ \t]*(\[[^\]]*\])?[ \t]*\(/gm;
+const TYPE_DECLARATION = /^type[ \t]+(\(|[A-Za-z_]\w*)/gm;
+const TYPE_SPEC = /^([A-Za-z_]\w*)(\[[^\]]*\])?\s*(=\s*)?/;
+const FIELD = /^([A-Za-z_]\w*(?:\s*,\s*[A-Za-z_]\w*)*)\s+([^`]+?)\s*(`[^`]*`|"[^"]*")?$/;
+const EMBEDDED = /^\*?([A-Za-z_][\w.]*)(?:\[[^\]]*\])?\s*(`[^`]*`|"[^"]*")?$/;
+const INTERFACE_METHOD = /^([A-Za-z_]\w*)\s*\(/;
+
+/**
+ * Determine if a Go identifier is exported
+ * @param {string} name - Identifier
+ * @returns {boolean}
+ */
+function isExported(name) {
+  if (!name) return false;
+  const first = name[0];
+  return first.toUpperCase() === first && first.toLowerCase() !== first;
+}
+
+/**
+ * Parse a receiver clause such as `(s *Stack[T])`
+ * @param {string} text - Receiver text including parentheses
+ * @returns {{type: string, pointer: boolean}|null}
+ */
+function parseReceiver(text) {
+  const inner = text.slice(1, -1).trim();
+  const match = inner.match(/^(?:[A-Za-z_]\w*\s+)?(\*)?\s*([A-Za-z_]\w*)/);
+  return match ? { type: match[2], pointer: Boolean(match[1]) } : null;
+}
+
+/**
+ * Read a result type list starting after the parameter list
+ * Stops at the body brace, skipping `struct{...}`/`interface{...}` types.
+ * @param {string} text - Masked content
+ * @param {number} start - Index after the closing parenthesis
+ * @returns {string}
+ */
+function readResults(text, start) {
+  let i = skipWhitespace(text, start);
+  if (text[i] === '(') {
+    const close = findClosing(text, i);
+    return close === -1 ? '' : text.slice(i, close + 1);
+  }
+
+  let end = i;
+  while (end < text.length && text[end] !== '\n') {
+    const ch = text[end];
+    if (ch === '[' || ch === '(') {
+      const close = findClosing(text, end);
+      if (close === -1) break;
+      end = close + 1;
+      continue;
+    }
+    if (ch === '{') {
+      if (!/(?:struct|interface)\s*$/.test(text.slice(i, end))) break;
+      const close = findClosing(text, end);
+      if (close === -1) break;
+      end = close + 1;
+      continue;
+    }
+    end++;
+  }
+  return text.slice(i, end).trim();
+}
+
+/**
+ * Count entries in a parameter or result list
+ * @param {string} text - `(a, b int)`, `error`, or empty
+ * @returns {number}
+ */
+function countList(text) {
+  const trimmed = (text || '').trim();
+  if (!trimmed) return 0;
+  if (!trimmed.startsWith('(')) return 1;
+  return splitArgs(trimmed.slice(1, -1)).length;
+}
+
+/**
+ * Parse struct fields and embedded types
+ * @param {string} body - Text between the struct braces
+ * @returns {{fields: Object[], embeds: string[]}}
+ */
+function parseStructBody(body) {
+  const fields = [];
+  const embeds = [];
+  for (const raw of body.split(/\n|;/)) {
+    const text = raw.trim();
+    if (!text) continue;
+    const embedded = text.match(EMBEDDED);
+    if (embedded) {
+      embeds.push(embedded[1]);
+      continue;
+    }
+    const field = text.match(FIELD);
+    if (!field) continue;
+    for (const name of field[1].split(',').map(part => part.trim())) {
+      fields.push({ name, type: compact(field[2], 80), exported: isExported(name) });
+    }
+  }
+  return { fields, embeds };
+}
+
+/**
+ * Parse interface methods, embedded interfaces, and type constraints
+ * @param {string} body - Text between the interface braces
+ * @returns {{methods: Object[], embeds: string[], constraint: boolean}}
+ */
+function parseInterfaceBody(body) {
+  const methods = [];
+  const embeds = [];
+  let constraint = false;
+  for (const raw of body.split(/\n|;/)) {
+    const text = raw.trim();
+    if (!text) continue;
+    const method = text.match(INTERFACE_METHOD);
+    if (method) {
+      const open = text.indexOf('(');
+      const close = findClosing(text, open);
+      const params = close === -1 ? text.slice(open) : text.slice(open, close + 1);
+      const results = close === -1 ? '' : readResults(text, close + 1);
+      methods.push({
+        name: method[1],
+        signature: compact(`${params}${results ? ` ${results}` : ''}`),
+        arity: [countList(params), countList(results)]
+      });
+      continue;
+    }
+    if (/[|~]/.test(text)) {
+      constraint = true;
+    } else if (/^[A-Za-z_][\w.]*$/.test(text)) {
+      embeds.push(text);
+    }
+  }
+  return { methods, embeds, constraint };
+}
+
+/**
+ * Parse one type spec (after `type`, or a line inside `type ( ... )`)
+ * @param {string} text - Masked content
+ * @param {number} start - Index of the type name
+ * @param {Function} lineAt - Offset to line lookup
+ * @returns {{decl: Object, end: number}|null}
+ */
+function parseTypeSpec(text, start, lineAt) {
+  const spec = text.slice(start).match(TYPE_SPEC);
+  if (!spec) return null;
+
+  const decl = { name: spec[1], line: lineAt(start), kind: spec[3] ? 'alias' : 'type' };
+  if (spec[2]) decl.typeParams = spec[2];
+
+  const valueStart = start + spec[0].length;
+  const keyword = text.slice(valueStart).match(/^(struct|interface)\s*\{/);
+  if (!keyword) {
+    const lineEnd = text.indexOf('\n', valueStart);
+    const underlying = text.slice(valueStart, lineEnd === -1 ? text.length : lineEnd).trim();
+    if (underlying && decl.kind === 'type') decl.underlying = compact(underlying, 80);
+    return { decl, end: lineEnd === -1 ? text.length : lineEnd };
+  }
+
+  const open = valueStart + keyword[0].length - 1;
+  const close = findClosing(text, open);
+  const body = text.slice(open + 1, close === -1 ? text.length : close);
+  decl.kind = keyword[1];
+  if (keyword[1] === 'struct') {
+    Object.assign(decl, parseStructBody(body));
+  } else {
+    Object.assign(decl, parseInterfaceBody(body));
+  }
+  return { decl, end: close === -1 ? text.length : close + 1 };
+}
+
+/**
+ * Parse Go functions, methods, and type declarations
+ * @param {string} content - File content
+ * @returns {{package: string|null, functions: Object[], methods: Object[], types: Object[]}}
+ */
+function parseGoDeclarations(content) {
+  const text = maskComments(content);
+  const lineAt = createLineLookup(text);
+  const pkg = text.match(/^package\s+([A-Za-z_]\w*)/m);
+  const result = { package: pkg ? pkg[1] : null, functions: [], methods: [], types: [] };
+
+  for (const match of text.matchAll(FUNC_DECLARATION)) {
+    const open = match.index + match[0].length - 1;
+    const close = findClosing(text, open);
+    if (close === -1) continue;
+    const params = text.slice(open, close + 1);
+    const results = readResults(text, close + 1);
+    const decl = {
+      name: match[2],
+      line: lineAt(match.index),
+      signature: compact(`${match[3] || ''}${params}${results ? ` ${results}` : ''}`),
+      arity: [countList(params), countList(results)]
+    };
+
+    const receiver = match[1] ? parseReceiver(match[1]) : null;
+    if (receiver) {
+      result.methods.push({ ...decl, receiver: receiver.type, pointer: receiver.pointer });
+    } else {
+      result.functions.push(decl);
+    }
+  }
+
+  for (const match of text.matchAll(TYPE_DECLARATION)) {
+    if (match[1] !== '(') {
+      const parsed = parseTypeSpec(text, match.index + match[0].length - match[1].length, lineAt);
+      if (parsed) result.types.push(parsed.decl);
+      continue;
+    }
+
+    // Grouped declaration: type ( A struct{...}; B interface{...} )
+    const groupOpen = match.index + match[0].length - 1;
+    const groupClose = findClosing(text, groupOpen);
+    let i = groupOpen + 1;
+    const end = groupClose === -1 ? text.length : groupClose;
+    while (i < end) {
+      i = skipWhitespace(text, i);
+      if (i >= end) break;
+      const parsed = parseTypeSpec(text, i, lineAt);
+      if (!parsed) {
+        const next = text.indexOf('\n', i);
+        i = next === -1 ? end : next + 1;
+        continue;
+      }
+      result.types.push(parsed.decl);
+      i = parsed.end;
+    }
+  }
+
+  return result;
+}
+
+/**
+ * Attach receivers, signatures, fields, and interface method sets to Go symbols
+ * @param {string} content - File content
+ * @param {Object} symbolMaps - Symbol maps
+ */
+function applyGoDetails(content, symbolMaps) {
+  const parsed = parseGoDeclarations(content);
+
+  if (symbolMaps.functions) {
+    const found = new Set(Array.from(symbolMaps.functions.values()).map(entry => entry.name));
+    const previous = new Map(symbolMaps.functions);
+    symbolMaps.functions.clear();
+
+    for (const decl of parsed.functions) {
+      if (!found.has(decl.name) || symbolMaps.functions.has(decl.name)) continue;
+      const entry = previous.get(decl.name) || { name: decl.name, kind: 'function' };
+      symbolMaps.functions.set(decl.name, {
+        ...entry,
+        name: decl.name,
+        line: decl.line,
+        kind: 'function',
+        signature: decl.signature,
+        arity: decl.arity
+      });
+    }
+
+    for (const decl of parsed.methods) {
+      const key = `${decl.receiver}.${decl.name}`;
+      if (!found.has(decl.name) || symbolMaps.functions.has(key)) continue;
+      const entry = {
+        name: decl.name,
+        line: decl.line,
+        kind: 'method',
+        receiver: decl.receiver,
+        signature: decl.signature,
+        arity: decl.arity
+      };
+      if (decl.pointer) entry.pointerReceiver = true;
+      symbolMaps.functions.set(key, entry);
+    }
+
+    // Keep anything the parser could not place
+    for (const [key, entry] of previous) {
+      if (!symbolMaps.functions.has(key) && !parsed.methods.some(decl => decl.name === entry.name)) {
+        symbolMaps.functions.set(key, entry);
+      }
+    }
+  }
+
+  if (symbolMaps.types) {
+    // Named types (`type Celsius float64`) and generic types are not matched by patterns
+    for (const decl of parsed.types) {
+      if (!symbolMaps.types.has(decl.name)) {
+        symbolMaps.types.set(decl.name, { name: decl.name, line: decl.line, kind: decl.kind });
+      }
+    }
+
+    for (const entry of symbolMaps.types.values()) {
+      const decl = parsed.types.find(item => item.name === entry.name && item.line === entry.line)
+        || parsed.types.find(item => item.name === entry.name);
+      if (!decl) continue;
+      entry.kind = decl.kind;
+      if (decl.typeParams) entry.typeParams = decl.typeParams;
+      if (decl.underlying) entry.underlying = decl.underlying;
+      if (decl.fields && decl.fields.length > 0) {
+        entry.members = decl.fields.map(field => ({ name: field.name, type: field.type, exported: field.exported }));
+      }
+      if (decl.embeds && decl.embeds.length > 0) entry.embeds = decl.embeds;
+      if (decl.kind === 'interface') {
+        entry.methods = decl.methods.map(method => ({ name: method.name, signature: method.signature, arity: method.arity }));
+        if (decl.constraint) entry.constraint = true;
+      }
+    }
+  }
+}
+
+module.exports = {
+  applyGoDetails,
+  parseGoDeclarations,
+  isExported
+};
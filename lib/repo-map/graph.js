@@ -0,0 +1,218 @@
+/**
+ * Module dependency graph and cycle detection
+ *
+ * Edges come from each file's recorded imports, resolved to files in the map
+ * (JS relative paths, Python modules, Go packages, Java/Kotlin classes, ...).
+ * External packages are dropped.
+ *
+ * @module lib/repo-map/graph
+ */
+
+'use strict';
+
+const { createFileIndex, resolveImport } = require('./imports');
+
+/**
+ * Build the file-level dependency graph
+ * @param {Object} map - Repo map
+ * @returns {Object<string, string[]>} - File -> sorted imported files (every file has an entry)
+ */
+function buildDependencyGraph(map) {
+  const files = Object.keys((map && map.files) || {}).sort();
+  const index = createFileIndex(files);
+  const graph = {};
+  for (const file of files) {
+    const fileData = map.files[file];
+    const targets = new Set();
+    for (const imp of fileData.imports || []) {
+      for (const target of resolveImport(file, imp.source, fileData.language, index)) {
+        if (target !== file) targets.add(target);
+      }
+    }
+    graph[file] = Array.from(targets).sort();
+  }
+  return graph;
+}
+
+/**
+ * Find strongly connected components (iterative Tarjan)
+ * @param {Object<string, string[]>} graph - Dependency graph
+ * @returns {string[][]} - Components, each sorted
+ */
+function findStronglyConnected(graph) {
+  const indexOf = new Map();
+  const lowLink = new Map();
+  const onStack = new Set();
+  const stack = [];
+  const components = [];
+  let nextIndex = 0;
+
+  for (const root of Object.keys(graph)) {
+    if (indexOf.has(root)) continue;
+
+    const work = [{ node: root, edge: 0 }];
+    indexOf.set(root, nextIndex);
+    lowLink.set(root, nextIndex);
+    nextIndex++;
+    stack.push(root);
+    onStack.add(root);
+
+    while (work.length > 0) {
+      const frame = work[work.length - 1];
+      const targets = graph[frame.node] || [];
+
+      if (frame.edge < targets.length) {
+        const target = targets[frame.edge++];
+        if (!(target in graph)) continue;
+        if (!indexOf.has(target)) {
+          indexOf.set(target, nextIndex);
+          lowLink.set(target, nextIndex);
+          nextIndex++;
+          stack.push(target);
+          onStack.add(target);
+          work.push({ node: target, edge: 0 });
+        } else if (onStack.has(target)) {
+          lowLink.set(frame.node, Math.min(lowLink.get(frame.node), indexOf.get(target)));
+        }
+        continue;
+      }
+
+      work.pop();
+      if (work.length > 0) {
+        const parent = work[work.length - 1].node;
+        lowLink.set(parent, Math.min(lowLink.get(parent), lowLink.get(frame.node)));
+      }
+
+      if (lowLink.get(frame.node) === indexOf.get(frame.node)) {
+        const component = [];
+        let member;
+        do {
+          member = stack.pop();
+          onStack.delete(member);
+          component.push(member);
+        } while (member !== frame.node);
+        components.push(component.sort());
+      }
+    }
+  }
+
+  return components;
+}
+
+/**
+ * Shortest path between two files (BFS), optionally restricted to a node set
+ * @param {Object<string, string[]>} graph - Dependency graph
+ * @param {string} from - Start file
+ * @param {string} to - Target file
+ * @param {Set<string>} [within] - Allowed nodes
+ * @returns {string[]|null} - Path including both ends
+ */
+function shortestPath(graph, from, to, within) {
+  const previous = new Map([[from, null]]);
+  const queue = [from];
+  for (let i = 0; i < queue.length; i++) {
+    const node = queue[i];
+    if (node === to) {
+      const path = [];
+      for (let step = to; step !== null; step = previous.get(step)) path.unshift(step);
+      return path;
+    }
+    for (const target of graph[node] || []) {
+      if (previous.has(target) || (within && !within.has(target))) continue;
+      previous.set(target, node);
+      queue.push(target);
+    }
+  }
+  return null;
+}
+
+/**
+ * Find the shortest cycle that starts with the edge `from -> to`
+ * @param {Object<string, string[]>} graph - Dependency graph
+ * @param {string} from - Importing file
+ * @param {string} to - Imported file
+ * @param {Set<string>} [within] - Allowed nodes
+ * @returns {string[]|null} - Files in cycle order (`[from, to, ...]`), or null
+ */
+function cycleThroughEdge(graph, from, to, within) {
+  const back = shortestPath(graph, to, from, within);
+  return back ? [from, ...back.slice(0, -1)] : null;
+}
+
+/**
+ * Rotate a cycle to start at its smallest file so equal cycles compare equal
+ * @param {string[]} cycle - Files in cycle order
+ * @returns {string[]}
+ */
+function normalizeCycle(cycle) {
+  let start = 0;
+  for (let i = 1; i < cycle.length; i++) {
+    if (cycle[i] < cycle[start]) start = i;
+  }
+  return [...cycle.slice(start), ...cycle.slice(0, start)];
+}
+
+/**
+ * Find dependency cycles: one shortest cycle per strongly connected component
+ * @param {Object<string, string[]>} graph - Dependency graph
+ * @returns {string[][]} - Cycles in import order, each starting at its smallest file
+ */
+function findCycles(graph) {
+  const cycles = [];
+  for (const component of findStronglyConnected(graph)) {
+    if (component.length < 2) continue;
+    const members = new Set(component);
+    const start = component[0];
+    let best = null;
+    for (const target of graph[start] || []) {
+      if (!members.has(target)) continue;
+      const cycle = cycleThroughEdge(graph, start, target, members);
+      if (cycle && (!best || cycle.length < best.length)) best = cycle;
+    }
+    if (best) cycles.push(normalizeCycle(best));
+  }
+  return cycles.sort((a, b) => a[0].localeCompare(b[0]));
+}
+
+/**
+ * Find cycles closed by edges present in `after` but not in `before`
+ * @param {Object<string, string[]>} before - Graph before the change
+ * @param {Object<string, string[]>} after - Graph after the change
+ * @returns {Array<{cycle: string[], edge: {from: string, to: string}}>}
+ */
+function findNewCycles(before, after) {
+  const seen = new Set();
+  const results = [];
+  for (const [from, targets] of Object.entries(after)) {
+    const previous = new Set(before[from] || []);
+    for (const to of targets) {
+      if (previous.has(to)) continue;
+      const cycle = cycleThroughEdge(after, from, to);
+      if (!cycle) continue;
+      const normalized = normalizeCycle(cycle);
+      const key = normalized.join('\n');
+      if (seen.has(key)) continue;
+      seen.add(key);
+      results.push({ cycle: normalized, edge: { from, to } });
+    }
+  }
+  return results;
+}
+
+/**
+ * List files that import a given file
+ * @param {Object<string, string[]>} graph - Dependency graph
+ * @param {string} file - Imported file
+ * @returns {string[]}
+ */
+function getDependents(graph, file) {
+  return Object.keys(graph).filter(source => graph[source].includes(file)).sort();
+}
+
+module.exports = {
+  buildDependencyGraph,
+  findStronglyConnected,
+  findCycles,
+  findNewCycles,
+  getDependents
+};
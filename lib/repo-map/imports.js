@@ -0,0 +1,165 @@
+/**
+ * Resolve import sources to repository files
+ *
+ * Resolution is best-effort and path based: relative specifiers are probed
+ * next to the importing file, and module paths (Python, Java, Go, Ruby, C
+ * includes) are matched against file path suffixes.
+ *
+ * @module lib/repo-map/imports
+ */
+
+'use strict';
+
+const path = require('path');
+
+const JS_EXTENSIONS = ['.ts', '.tsx', '.js', '.jsx', '.mjs', '.cjs'];
+
+/**
+ * Build a lookup index over map files
+ * @param {string[]} files - Repository-relative file paths
+ * @returns {{files: Set<string>, byBasename: Map<string, string[]>, byDir: Map<string, string[]>}}
+ */
+function createFileIndex(files) {
+  const index = { files: new Set(files), byBasename: new Map(), byDir: new Map() };
+  for (const file of files) {
+    const base = path.posix.basename(file);
+    if (!index.byBasename.has(base)) index.byBasename.set(base, []);
+    index.byBasename.get(base).push(file);
+
+    const dir = path.posix.dirname(file);
+    if (!index.byDir.has(dir)) index.byDir.set(dir, []);
+    index.byDir.get(dir).push(file);
+  }
+  return index;
+}
+
+/**
+ * Return the first candidate path present in the index
+ * @param {Object} index - File index
+ * @param {string[]} candidates - Candidate relative paths
+ * @returns {string[]}
+ */
+function probe(index, candidates) {
+  for (const candidate of candidates) {
+    const normalized = path.posix.normalize(candidate);
+    if (index.files.has(normalized)) return [normalized];
+  }
+  return [];
+}
+
+/**
+ * Find files whose path ends with one of the given suffixes
+ * @param {Object} index - File index
+ * @param {string[]} suffixes - Relative path suffixes (e.g. `pkg/mod.py`)
+ * @returns {string[]}
+ */
+function matchSuffix(index, suffixes) {
+  for (const suffix of suffixes) {
+    const matches = (index.byBasename.get(path.posix.basename(suffix)) || [])
+      .filter(file => file === suffix || file.endsWith(`/${suffix}`));
+    if (matches.length > 0) return matches;
+  }
+  return [];
+}
+
+/**
+ * Resolve a JavaScript/TypeScript specifier
+ * @param {string} fromFile - Importing file
+ * @param {string} source - Specifier
+ * @param {Object} index - File index
+ * @returns {string[]}
+ */
+function resolveJs(fromFile, source, index) {
+  if (!source.startsWith('.')) return [];
+  const target = path.posix.join(path.posix.dirname(fromFile), source);
+  const stem = target.replace(/\.(?:js|jsx|mjs|cjs)$/, '');
+  return probe(index, [
+    target,
+    ...JS_EXTENSIONS.map(ext => `${stem}${ext}`),
+    ...JS_EXTENSIONS.map(ext => `${target}/index${ext}`)
+  ]);
+}
+
+/**
+ * Resolve a Python module (absolute or relative)
+ * @param {string} fromFile - Importing file
+ * @param {string} source - Module path (`pkg.mod`, `.mod`, `..pkg`)
+ * @param {Object} index - File index
+ * @returns {string[]}
+ */
+function resolvePython(fromFile, source, index) {
+  const dots = source.match(/^\.*/)[0].length;
+  const modulePath = source.slice(dots).replace(/\./g, '/');
+  if (dots > 0) {
+    let dir = path.posix.dirname(fromFile);
+    for (let i = 1; i < dots; i++) dir = path.posix.dirname(dir);
+    const target = modulePath ? path.posix.join(dir, modulePath) : dir;
+    return probe(index, [`${target}.py`, `${target}/__init__.py`]);
+  }
+  if (!modulePath) return [];
+  return matchSuffix(index, [`${modulePath}.py`, `${modulePath}/__init__.py`]);
+}
+
+/**
+ * Resolve a Go import path to the files of its package directory
+ * @param {string} source - Import path
+ * @param {Object} index - File index
+ * @returns {string[]}
+ */
+function resolveGo(source, index) {
+  const segments = source.split('/');
+  for (let start = 0; start < segments.length; start++) {
+    const dir = segments.slice(start).join('/');
+    const files = (index.byDir.get(dir) || []).filter(file => file.endsWith('.go') && !file.endsWith('_test.go'));
+    if (files.length > 0) return files;
+  }
+  return [];
+}
+
+/**
+ * Resolve an import source to repository files
+ * @param {string} fromFile - Importing file (repository-relative)
+ * @param {string} source - Import source as recorded in the map
+ * @param {string} language - Importing file's language
+ * @param {Object} index - Index from `createFileIndex`
+ * @returns {string[]} - Matching files (empty for external modules)
+ */
+function resolveImport(fromFile, source, language, index) {
+  if (!source) return [];
+
+  switch (language) {
+    case 'javascript':
+    case 'typescript':
+      return resolveJs(fromFile, source, index);
+    case 'python':
+      return resolvePython(fromFile, source, index);
+    case 'go':
+      return resolveGo(source, index);
+    case 'java':
+    case 'kotlin': {
+      const modulePath = source.replace(/\.\*$/, '').replace(/\./g, '/');
+      return matchSuffix(index, [`${modulePath}.java`, `${modulePath}.kt`]);
+    }
+    case 'ruby': {
+      const target = source.replace(/\.rb$/, '');
+      if (target.startsWith('.')) {
+        return probe(index, [`${path.posix.join(path.posix.dirname(fromFile), target)}.rb`]);
+      }
+      return probe(index, [`${path.posix.join(path.posix.dirname(fromFile), target)}.rb`])
+        .concat(matchSuffix(index, [`lib/${target}.rb`, `${target}.rb`]))
+        .slice(0, 1);
+    }
+    case 'c':
+    case 'cpp': {
+      const local = probe(index, [path.posix.join(path.posix.dirname(fromFile), source)]);
+      return local.length > 0 ? local : matchSuffix(index, [source]);
+    }
+    default:
+      return [];
+  }
+}
+
+module.exports = {
+  createFileIndex,
+  resolveImport
+};
@@ -0,0 +1,431 @@
+/**
+ * Test scaffolding from the repo map
+ *
+ * Given a file or symbol, builds a test file skeleton in the project's test
+ * framework: describe/it blocks for Jest, Vitest and Mocha, `@pytest.mark.
+ * parametrize` cases for pytest, and table-driven tests for Go. Parameter
+ * names come from the signatures the map records (TypeScript/JavaScript and
+ * Go); Python signatures are read from the `def` line. The test file goes
+ * where the project already keeps tests: a `__tests__/`, `test/` or `tests/`
+ * directory when most existing tests live there, next to the source
+ * otherwise, and always next to the source for Go.
+ *
+ * @module lib/repo-map/testgen
+ */
+
+'use strict';
+
+const path = require('path');
+
+const { splitArgs, findClosing } = require('./details/utils');
+
+const CATEGORIES = ['functions', 'classes'];
+
+/**
+ * Test framework used for each map language, most common first
+ */
+const LANGUAGE_FRAMEWORKS = {
+  javascript: ['jest', 'vitest', 'mocha'],
+  typescript: ['jest', 'vitest', 'mocha'],
+  python: ['pytest', 'unittest'],
+  go: ['go-test', 'testify']
+};
+
+const TEST_FILE_PATTERNS = [
+  /(?:^|\/)__tests__\//,
+  /[._-](?:test|spec)\.[^/]+$/,
+  /(?:^|\/)test_[^/]+\.py$/,
+  /_test\.py$/,
+  /_test\.go$/
+];
+
+/**
+ * Check whether a path is a test file
+ * @param {string} file - Repository-relative path
+ * @returns {boolean}
+ */
+function isTestFile(file) {
+  return TEST_FILE_PATTERNS.some(pattern => pattern.test(file));
+}
+
+/**
+ * Resolve a target (`file`, `symbol`, or `file:symbol`) against the map
+ * @param {Object} map - Repo map
+ * @param {string} target - File path, symbol name, or both joined by `:`
+ * @returns {{file: string, symbols: Object[]}|null} Testable symbols (with their `category`); exported ones when a file is given
+ */
+function resolveTarget(map, target) {
+  const files = map && map.files ? map.files : {};
+  const normalized = String(target || '').replace(/\\/g, '/').replace(/^\.\//, '');
+  const split = normalized.lastIndexOf(':');
+  const [file, name] = files[normalized] || split === -1
+    ? [normalized, null]
+    : [normalized.slice(0, split), normalized.slice(split + 1)];
+
+  const symbolsOf = entry => CATEGORIES.flatMap(category =>
+    ((entry.symbols && entry.symbols[category]) || []).map(symbol => ({ ...symbol, category })));
+
+  if (files[file]) {
+    const symbols = symbolsOf(files[file]).filter(symbol => name ? symbol.name === name : symbol.exported !== false);
+    return symbols.length > 0 || !name ? { file, symbols } : null;
+  }
+
+  // Bare symbol: the first non-test file that exports it
+  for (const [candidate, entry] of Object.entries(files)) {
+    if (isTestFile(candidate)) continue;
+    const symbols = symbolsOf(entry).filter(symbol => symbol.name === normalized);
+    if (symbols.length > 0) return { file: candidate, symbols };
+  }
+  return null;
+}
+
+/**
+ * Pick the test framework for a language from detected frameworks
+ * @param {string} language - Map language
+ * @param {Array<{name: string}>} [detected] - Result of detectTestFrameworks().frameworks
+ * @returns {string|null}
+ */
+function pickFramework(language, detected) {
+  const candidates = LANGUAGE_FRAMEWORKS[language] || [];
+  const names = (detected || []).map(framework => framework.name);
+  return candidates.find(name => names.includes(name)) || candidates[0] || null;
+}
+
+/**
+ * Where the project keeps tests for a language
+ * @param {string[]} files - Repository-relative paths
+ * @param {string} language - Map language
+ * @returns {{dir: string|null, style: string}} `dir` is a test root (`__tests__`, `tests`) or null for colocated tests
+ */
+function detectTestLayout(files, language) {
+  const extensions = language === 'python' ? /\.py$/ : /\.[cm]?[jt]sx?$/;
+  const tests = files.filter(file => extensions.test(file) && isTestFile(file));
+  const suffix = tests.some(file => /[._-]spec\.[^/]+$/.test(file)) && !tests.some(file => /[._-]test\.[^/]+$/.test(file)) ? 'spec' : 'test';
+
+  const roots = new Map();
+  let colocated = 0;
+  for (const file of tests) {
+    const root = file.match(/^(?:(__tests__|tests?|spec)\/)/);
+    if (root) roots.set(root[1], (roots.get(root[1]) || 0) + 1);
+    else colocated++;
+  }
+  const [dir, count] = Array.from(roots.entries()).sort((a, b) => b[1] - a[1])[0] || [null, 0];
+  if (dir && count >= colocated) return { dir, style: suffix };
+  if (tests.length === 0 && language === 'python') return { dir: 'tests', style: suffix };
+  return { dir: null, style: suffix };
+}
+
+/**
+ * Test file path for a source file
+ * @param {string} file - Source file
+ * @param {string} language - Map language
+ * @param {{dir: string|null, style: string}} layout - Result of detectTestLayout
+ * @returns {string}
+ */
+function testPathFor(file, language, layout) {
+  const ext = path.posix.extname(file);
+  const base = path.posix.basename(file, ext);
+  const dirname = path.posix.dirname(file);
+
+  if (language === 'go') return path.posix.join(dirname, `${base}_test.go`);
+  if (language === 'python') {
+    const name = `test_${base}.py`;
+    return layout.dir ? path.posix.join(layout.dir, name) : path.posix.join(dirname, name);
+  }
+  const name = `${base}.${layout.style}${ext}`;
+  return layout.dir ? path.posix.join(layout.dir, name) : path.posix.join(dirname, name);
+}
+
+/**
+ * Parameter names from a signature's parameter list
+ * @param {string} signature - e.g. `(a: number, b = 2): number` or `(a, b int) error`
+ * @param {string} language - Map language
+ * @returns {Array<{name: string, type: string|null}>}
+ */
+function parseParams(signature, language) {
+  const text = String(signature || '');
+  let open = text.indexOf('(');
+  // Go generics: `[T any](items []T) T`
+  if (language === 'go' && text.startsWith('[')) open = text.indexOf('(', findClosing(text, 0));
+  if (open === -1) return [];
+  const close = findClosing(text, open);
+  const list = splitArgs(text.slice(open + 1, close === -1 ? text.length : close), { angles: language !== 'go' })
+    .map(item => item.trim())
+    .filter(Boolean);
+
+  if (language === 'go') {
+    // `a, b int` shares the type; unnamed params have only a type
+    const params = [];
+    let pending = [];
+    for (const item of list) {
+      const match = item.match(/^([A-Za-z_]\w*)\s+(.+)$/);
+      if (match) {
+        pending.forEach(name => params.push({ name, type: match[2] }));
+        pending = [];
+        params.push({ name: match[1], type: match[2] });
+      } else if (/^[A-Za-z_]\w*$/.test(item) && list.length > 1) {
+        pending.push(item);
+      } else {
+        params.push({ name: `arg${params.length}`, type: item });
+      }
+    }
+    // Only types, no names: `(int, string)`
+    pending.forEach(type => params.push({ name: `arg${params.length}`, type }));
+    return params;
+  }
+
+  // Python: parameters after `*` or `*args` are keyword-only; variadics are skipped
+  let keyword = false;
+  const params = [];
+  for (const item of list) {
+    if (language === 'python' && item.startsWith('*')) {
+      if (!item.startsWith('**')) keyword = true;
+      continue;
+    }
+    const name = item.replace(/^(?:public|private|protected|readonly)\s+/, '').split(/[:=]/)[0].trim().replace(/^\.\.\.|\?$/g, '');
+    const type = item.includes(':') ? item.slice(item.indexOf(':') + 1).split('=')[0].trim() : null;
+    if (!/^[A-Za-z_$][\w$]*$/.test(name) || ['self', 'cls'].includes(name)) continue;
+    params.push(keyword ? { name, type, keyword } : { name, type });
+  }
+  return params;
+}
+
+/**
+ * Read a Python function's signature from its `def` line
+ * @param {string} content - File content
+ * @param {Object} symbol - Map symbol with a `line`
+ * @returns {string}
+ */
+function pythonSignature(content, symbol) {
+  const lines = content.split('\n');
+  const start = Math.max(0, (symbol.line || 1) - 1);
+  const text = lines.slice(start, start + 20).join('\n');
+  const def = text.match(new RegExp(`def\\s+${symbol.name}\\s*\\(`));
+  if (!def) return '';
+  const open = def.index + def[0].length - 1;
+  const close = findClosing(text, open);
+  return close === -1 ? '' : text.slice(open, close + 1);
+}
+
+/**
+ * Relative import specifier from the test file to the source
+ * @param {string} testFile - Test file path
+ * @param {string} file - Source file path
+ * @returns {string}
+ */
+function importPath(testFile, file) {
+  const relative = path.posix.relative(path.posix.dirname(testFile), file).replace(/\.[cm]?[jt]sx?$/, '');
+  return relative.startsWith('.') ? relative : `./${relative}`;
+}
+
+/**
+ * Render describe/it tests for Jest, Vitest or Mocha
+ * @param {Object} context - Scaffold context
+ * @returns {string}
+ */
+function renderDescribe(context) {
+  const { framework, file, testFile, symbols, esm } = context;
+  const names = symbols.map(symbol => symbol.name);
+  const source = importPath(testFile, file);
+  const lines = [];
+
+  if (framework === 'vitest') lines.push("import { describe, it, expect } from 'vitest';");
+  if (framework === 'mocha') lines.push(esm ? "import assert from 'node:assert/strict';" : "const assert = require('node:assert/strict');");
+  lines.push(esm ? `import { ${names.join(', ')} } from '${source}';` : `const { ${names.join(', ')} } = require('${source}');`);
+  lines.push('');
+  lines.push(`describe('${path.posix.basename(file).replace(/\.[^.]+$/, '')}', () => {`);
+
+  symbols.forEach((symbol, index) => {
+    if (index > 0) lines.push('');
+    lines.push(`  describe('${symbol.name}', () => {`);
+    if (symbol.category === 'classes') {
+      lines.push(`    it('should construct', () => {`);
+      lines.push(`      const instance = new ${symbol.name}();`);
+      lines.push(framework === 'mocha' ? `      assert.ok(instance instanceof ${symbol.name});` : `      expect(instance).toBeInstanceOf(${symbol.name});`);
+    } else {
+      const params = parseParams(symbol.signature, context.language);
+      const args = params.map(param => param.name).join(', ');
+      const call = `${symbol.async ? 'await ' : ''}${symbol.name}(${args})`;
+      lines.push(`    it('should return the expected result', ${symbol.async ? 'async ' : ''}() => {`);
+      params.forEach(param => lines.push(`      const ${param.name} = undefined;`));
+      lines.push(`      const expected = undefined;`);
+      lines.push(framework === 'mocha' ? `      assert.deepEqual(${call}, expected);` : `      expect(${call}).toEqual(expected);`);
+    }
+    lines.push('    });');
+    lines.push('  });');
+  });
+
+  lines.push('});');
+  return lines.join('\n') + '\n';
+}
+
+/**
+ * Render parametrized pytest tests
+ * @param {Object} context - Scaffold context
+ * @returns {string}
+ */
+function renderPytest(context) {
+  const { file, symbols, content } = context;
+  const module = file.replace(/\.py$/, '').replace(/^src\//, '').split('/').filter(part => part !== '__init__').join('.');
+  const lines = ['import pytest', '', `from ${module} import ${symbols.map(symbol => symbol.name).join(', ')}`];
+
+  for (const symbol of symbols) {
+    lines.push('', '');
+    if (symbol.category === 'classes') {
+      lines.push(`class Test${symbol.name}:`);
+      lines.push('    def test_construct(self):');
+      lines.push(`        assert isinstance(${symbol.name}(), ${symbol.name})`);
+      continue;
+    }
+    const params = parseParams(pythonSignature(content || '', symbol), 'python');
+    const argnames = [...params.map(param => param.name), 'expected'];
+    const placeholder = argnames.length === 1 ? 'None' : `(${argnames.map(() => 'None').join(', ')})`;
+    const call = `${symbol.name}(${params.map(param => param.keyword ? `${param.name}=${param.name}` : param.name).join(', ')})`;
+    lines.push(`@pytest.mark.parametrize("${argnames.join(', ')}", [`);
+    lines.push(`    ${placeholder},`);
+    lines.push('])');
+    if (symbol.async) {
+      lines.push('@pytest.mark.asyncio');
+      lines.push(`async def test_${symbol.name}(${argnames.join(', ')}):`);
+      lines.push(`    assert await ${call} == expected`);
+    } else {
+      lines.push(`def test_${symbol.name}(${argnames.join(', ')}):`);
+      lines.push(`    assert ${call} == expected`);
+    }
+  }
+  return lines.join('\n') + '\n';
+}
+
+/**
+ * Render table-driven Go tests
+ * @param {Object} context - Scaffold context
+ * @returns {string}
+ */
+function renderGoTable(context) {
+  const { framework, symbols, content } = context;
+  const pkg = ((content || '').match(/^package\s+(\w+)/m) || [null, 'main'])[1];
+  const testify = framework === 'testify';
+  const lines = [`package ${pkg}`, '', 'import ('];
+  if (testify) lines.push('\t"testing"', '', '\t"github.com/stretchr/testify/assert"');
+  else lines.push('\t"reflect"', '\t"testing"');
+  lines.push(')');
+
+  for (const symbol of symbols.filter(item => item.category === 'functions')) {
+    const params = parseParams(symbol.signature, 'go');
+    const signature = String(symbol.signature || '');
+    const close = findClosing(signature, signature.indexOf('('));
+    const results = close === -1 ? '' : signature.slice(close + 1).trim().replace(/^\(|\)$/g, '');
+    const resultTypes = results ? splitArgs(results).map(item => item.trim().split(/\s+/).pop()) : [];
+    const returnsError = resultTypes[resultTypes.length - 1] === 'error';
+    const wantType = resultTypes.filter((type, i) => !(returnsError && i === resultTypes.length - 1))[0];
+
+    const testName = symbol.receiver ? `Test${symbol.receiver.replace(/^\*/, '')}_${symbol.name}` : `Test${symbol.name[0].toUpperCase()}${symbol.name.slice(1)}`;
+    const call = symbol.receiver
+      ? `(${symbol.receiver.startsWith('*') ? '&' : ''}${symbol.receiver.replace(/^\*/, '')}{}).${symbol.name}(${params.map(param => `tt.${param.name}`).join(', ')})`
+      : `${symbol.name}(${params.map(param => `tt.${param.name}`).join(', ')})`;
+    const outputs = [wantType ? 'got' : null, returnsError ? 'err' : null].filter(Boolean);
+
+    lines.push('', `func ${testName}(t *testing.T) {`);
+    // Fields aligned the way gofmt lays them out
+    const fields = [['name', 'string'], ...params.map(param => [param.name, param.type])];
+    if (wantType) fields.push(['want', wantType]);
+    if (returnsError) fields.push(['wantErr', 'bool']);
+    const width = Math.max(...fields.map(([name]) => name.length));
+    lines.push('\ttests := []struct {');
+    fields.forEach(([name, type]) => lines.push(`\t\t${name.padEnd(width)} ${type}`));
+    lines.push('\t}{');
+    lines.push('\t\t{name: "basic"},');
+    lines.push('\t}');
+    lines.push('\tfor _, tt := range tests {');
+    lines.push('\t\tt.Run(tt.name, func(t *testing.T) {');
+    lines.push(outputs.length > 0 ? `\t\t\t${outputs.join(', ')} := ${call}` : `\t\t\t${call}`);
+    if (returnsError) {
+      lines.push('\t\t\tif (err != nil) != tt.wantErr {');
+      lines.push(`\t\t\t\tt.Fatalf("${symbol.name}() error = %v, wantErr %v", err, tt.wantErr)`);
+      lines.push('\t\t\t}');
+    }
+    if (wantType) {
+      if (testify) {
+        lines.push('\t\t\tassert.Equal(t, tt.want, got)');
+      } else {
+        lines.push('\t\t\tif !reflect.DeepEqual(got, tt.want) {');
+        lines.push(`\t\t\t\tt.Errorf("${symbol.name}() = %v, want %v", got, tt.want)`);
+        lines.push('\t\t\t}');
+      }
+    }
+    lines.push('\t\t})');
+    lines.push('\t}');
+    lines.push('}');
+  }
+
+  const text = lines.join('\n') + '\n';
+  // Drop the assertion import when no test compares results
+  if (!/reflect\.DeepEqual|assert\.Equal/.test(text)) return text.replace(testify ? '\n\n\t"github.com/stretchr/testify/assert"' : '\t"reflect"\n', '');
+  return text;
+}
+
+/**
+ * Build test scaffolding for a file or symbol
+ * @param {Object} map - Repo map
+ * @param {string} target - File path, symbol name, or `file:symbol`
+ * @param {Object} [options]
+ * @param {string} [options.framework] - Test framework (for example from detectTestFrameworks)
+ * @param {Array<{name: string}>} [options.frameworks] - Detected frameworks to choose from
+ * @param {Function} [options.readFile] - (file) => content, for package names and Python signatures
+ * @returns {{success: boolean, file?: string, testFile?: string, framework?: string, symbols?: string[], content?: string, error?: string}}
+ */
+function scaffoldTests(map, target, options = {}) {
+  const resolved = resolveTarget(map, target);
+  if (!resolved) return { success: false, error: `No file or symbol matching "${target}" in the repo map` };
+
+  const { file } = resolved;
+  const language = map.files[file].language;
+  const framework = options.framework || pickFramework(language, options.frameworks);
+  if (!framework || !(LANGUAGE_FRAMEWORKS[language] || []).includes(framework)) {
+    return { success: false, error: `No supported test framework for ${language} (${file})` };
+  }
+  if (framework === 'unittest') {
+    return { success: false, error: 'unittest scaffolding is not supported; use pytest' };
+  }
+  if (resolved.symbols.length === 0) return { success: false, error: `No exported functions or classes in ${file}` };
+
+  let content = '';
+  try {
+    content = options.readFile ? options.readFile(file) : '';
+  } catch {
+    content = '';
+  }
+
+  const layout = language === 'go' ? { dir: null, style: 'test' } : detectTestLayout(Object.keys(map.files), language);
+  const testFile = testPathFor(file, language, layout);
+  const context = {
+    framework,
+    language,
+    file,
+    testFile,
+    content,
+    symbols: resolved.symbols,
+    esm: language === 'typescript' || /\.mjs$/.test(file) || /^\s*(?:import|export)\s/m.test(content)
+  };
+
+  const render = language === 'go' ? renderGoTable : language === 'python' ? renderPytest : renderDescribe;
+  return {
+    success: true,
+    file,
+    testFile,
+    framework,
+    symbols: resolved.symbols.map(symbol => symbol.name),
+    content: render(context)
+  };
+}
+
+module.exports = {
+  LANGUAGE_FRAMEWORKS,
+  isTestFile,
+  resolveTarget,
+  pickFramework,
+  detectTestLayout,
+  testPathFor,
+  parseParams,
+  scaffoldTests
+};
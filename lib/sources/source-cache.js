@@ -13,6 +13,9 @@
 const fs = require('fs');
 const path = require('path');
 const { getStateDir } = require('../platform/state-dir');
+const { createLogger } = require('../utils/logger');
+
+const log = createLogger('sources:cache');
 
 const PREFERENCE_FILE = 'preference.json';
 
@@ -61,7 +64,7 @@ function getPreference() {
   try {
     return JSON.parse(fs.readFileSync(filePath, 'utf8'));
   } catch (err) {
-    console.error(`Failed to read preference file:`, err.message);
+    log.error('Failed to read preference file', { path: filePath, error: err });
     return null;
   }
 }
@@ -91,7 +94,7 @@ function savePreference(preference) {
 function getToolCapabilities(toolName) {
   // Prevent path traversal
   if (!isValidToolName(toolName)) {
-    console.error(`Invalid tool name: ${toolName}`);
+    log.error('Invalid tool name', { tool: toolName });
     return null;
   }
   const filePath = path.join(getSourcesDir(), `${toolName}.json`);
@@ -101,7 +104,7 @@ function getToolCapabilities(toolName) {
   try {
     return JSON.parse(fs.readFileSync(filePath, 'utf8'));
   } catch (err) {
-    console.error(`Failed to read tool capabilities for ${toolName}:`, err.message);
+    log.error('Failed to read tool capabilities', { tool: toolName, error: err });
     return null;
   }
 }
@@ -116,7 +119,7 @@ function getToolCapabilities(toolName) {
 function saveToolCapabilities(toolName, capabilities) {
   // Prevent path traversal
   if (!isValidToolName(toolName)) {
-    console.error(`Invalid tool name: ${toolName}`);
+    log.error('Invalid tool name', { tool: toolName });
     return;
   }
   ensureDir();
@@ -0,0 +1,266 @@
+/**
+ * Parallel Task Runner
+ * Runs independent steps concurrently, the way per-workspace work in a
+ * monorepo wants to run: a task starts once every task it depends on has
+ * finished, at most `concurrency` run at a time, and a failed task skips
+ * its dependents instead of stopping the rest. Progress events let the
+ * command print one line per finished task and a summary at the end.
+ *
+ *   const result = await runTasks([
+ *     { id: 'read:api', run: () => readDependencies('packages/api') },
+ *     { id: 'osv', deps: ['read:api', 'read:web'], run: ({ results }) => query(results) }
+ *   ], { concurrency: 4, onProgress: createProgress(process.stderr) });
+ *
+ * Synchronous work blocks the other tasks; run commands with `runCommand`
+ * rather than execFileSync so they overlap.
+ *
+ * @module lib/task-runner
+ */
+
+const exec = require('../utils/exec');
+const fs = require('fs');
+const os = require('os');
+const path = require('path');
+
+/**
+ * Default cap on concurrent tasks; most steps spawn package managers or
+ * test runners, which contend for CPU and disk past this
+ */
+const MAX_DEFAULT_CONCURRENCY = 8;
+
+/**
+ * Concurrency to use
+ * @param {number} [value] - Requested concurrency
+ * @returns {number} `value` when it is a positive integer, else CPU count capped at 8
+ */
+function resolveConcurrency(value) {
+  if (Number.isInteger(value) && value > 0) return value;
+  return Math.max(1, Math.min(os.cpus().length || 1, MAX_DEFAULT_CONCURRENCY));
+}
+
+/**
+ * Read `--concurrency N` from command arguments
+ * @param {string[]} args - Command arguments
+ * @returns {{concurrency: number|undefined, error: string|null}} undefined when the flag is absent
+ */
+function parseConcurrency(args) {
+  const index = args.indexOf('--concurrency');
+  if (index === -1) return { concurrency: undefined, error: null };
+  const value = Number(args[index + 1]);
+  if (!Number.isInteger(value) || value < 1) {
+    return { concurrency: undefined, error: '--concurrency needs a whole number of 1 or more' };
+  }
+  return { concurrency: value, error: null };
+}
+
+/**
+ * Check task ids and dependencies and put tasks in a runnable order
+ * @param {Array<{id: string, deps?: string[]}>} tasks - Tasks to run
+ * @returns {{order: string[], error: string|null}} Dependencies before dependents, otherwise input order
+ */
+function orderTasks(tasks) {
+  const byId = new Map();
+  for (const task of tasks) {
+    if (!task || typeof task.id !== 'string' || !task.id) return { order: [], error: 'Every task needs an id' };
+    if (byId.has(task.id)) return { order: [], error: `Duplicate task id: ${task.id}` };
+    if (typeof task.run !== 'function') return { order: [], error: `Task ${task.id} has no run function` };
+    byId.set(task.id, task);
+  }
+  for (const task of tasks) {
+    const missing = (task.deps || []).find(dep => !byId.has(dep));
+    if (missing) return { order: [], error: `Task ${task.id} depends on unknown task ${missing}` };
+  }
+
+  const order = [];
+  const state = new Map();
+  const visit = (id, trail) => {
+    if (state.get(id) === 'done') return null;
+    if (state.get(id) === 'visiting') return [...trail.slice(trail.indexOf(id)), id].join(' -> ');
+    state.set(id, 'visiting');
+    for (const dep of byId.get(id).deps || []) {
+      const cycle = visit(dep, [...trail, id]);
+      if (cycle) return cycle;
+    }
+    state.set(id, 'done');
+    order.push(id);
+    return null;
+  };
+  for (const task of tasks) {
+    const cycle = visit(task.id, []);
+    if (cycle) return { order: [], error: `Dependency cycle: ${cycle}` };
+  }
+  return { order, error: null };
+}
+
+/**
+ * Run tasks concurrently in dependency order
+ * Each `run` receives `{id, results}`, where `results` maps finished task
+ * ids to their values. A task whose run throws is `failed`; its dependents
+ * are `skipped`. With `failFast`, no new task starts after a failure.
+ * @param {Array<{id: string, label?: string, deps?: string[], run: Function}>} tasks - Tasks to run
+ * @param {Object} [options]
+ * @param {number} [options.concurrency] - Maximum tasks at once (see resolveConcurrency)
+ * @param {Function} [options.onProgress] - Called with `{type, task, completed, total, running, durationMs, error}`
+ * @param {boolean} [options.failFast] - Stop starting tasks after the first failure
+ * @returns {Promise<{success: boolean, concurrency: number, durationMs: number,
+ *   tasks: Array<{id: string, label: string, status: string, value?: *, error?: string, durationMs?: number}>,
+ *   results: Object<string, *>, error?: string}>} `tasks` in input order
+ */
+async function runTasks(tasks, options = {}) {
+  const concurrency = resolveConcurrency(options.concurrency);
+  const { order, error } = orderTasks(tasks);
+  if (error) return { success: false, concurrency, durationMs: 0, tasks: [], results: {}, error };
+
+  const onProgress = options.onProgress || (() => {});
+  const started = Date.now();
+  const byId = new Map(tasks.map(task => [task.id, task]));
+  const outcomes = new Map();
+  const results = {};
+  const pending = [...order];
+  let running = 0;
+  let stopped = false;
+
+  const report = (type, task, extra = {}) => onProgress({
+    type,
+    task: { id: task.id, label: task.label || task.id },
+    completed: outcomes.size,
+    total: tasks.length,
+    running,
+    ...extra
+  });
+  const finish = (task, outcome) => {
+    outcomes.set(task.id, { id: task.id, label: task.label || task.id, ...outcome });
+    report(outcome.status, task, { durationMs: outcome.durationMs, error: outcome.error });
+  };
+
+  await new Promise(resolve => {
+    const schedule = () => {
+      for (let i = 0; i < pending.length;) {
+        const task = byId.get(pending[i]);
+        const deps = task.deps || [];
+        const blocked = deps.find(dep => outcomes.has(dep) && outcomes.get(dep).status !== 'done');
+        if (blocked || stopped) {
+          pending.splice(i, 1);
+          const reason = blocked ? `${blocked} ${outcomes.get(blocked).status}` : 'stopped after a failure';
+          finish(task, { status: 'skipped', error: reason });
+          i = 0;
+          continue;
+        }
+        if (running >= concurrency || !deps.every(dep => outcomes.has(dep))) {
+          i++;
+          continue;
+        }
+        pending.splice(i, 1);
+        running++;
+        report('start', task);
+        const taskStarted = Date.now();
+        Promise.resolve()
+          .then(() => task.run({ id: task.id, results }))
+          .then(value => {
+            results[task.id] = value;
+            running--;
+            finish(task, { status: 'done', value, durationMs: Date.now() - taskStarted });
+          }, taskError => {
+            running--;
+            if (options.failFast) stopped = true;
+            finish(task, { status: 'failed', error: taskError && taskError.message ? taskError.message : String(taskError), durationMs: Date.now() - taskStarted });
+          })
+          .then(schedule);
+      }
+      if (running === 0 && pending.length === 0) resolve();
+    };
+    schedule();
+  });
+
+  const list = tasks.map(task => outcomes.get(task.id));
+  return {
+    success: list.every(outcome => outcome.status === 'done'),
+    concurrency,
+    durationMs: Date.now() - started,
+    tasks: list,
+    results
+  };
+}
+
+/**
+ * Run a command without blocking other tasks
+ * Same contract as the synchronous runners in lib/deps and lib/flaky:
+ * stdout, the stdout of a non-zero exit, or null when nothing ran.
+ * @param {string} cwd - Working directory
+ * @param {string[]} argv - Command and arguments
+ * @param {Object} [options]
+ * @param {number} [options.timeout=120000] - Milliseconds before the command is killed
+ * @returns {Promise<string|null>}
+ */
+function runCommand(cwd, argv, options = {}) {
+  return exec.runAsync(cwd, argv, { timeout: options.timeout || 120000, keepOutput: true });
+}
+
+/**
+ * Sibling workspace packages each package depends on
+ * Read from package.json dependency fields, so only Node workspaces get
+ * edges; other packages have none and run in any order.
+ * @param {string} basePath - Repository root
+ * @param {Array<{name: string, path: string}>} packages - Workspace packages (detectWorkspaces().packages)
+ * @returns {Object<string, string[]>} Package path to the paths it depends on
+ */
+function workspaceDependencies(basePath, packages) {
+  const pathByName = new Map(packages.map(pkg => [pkg.name, pkg.path]));
+  const edges = {};
+  for (const pkg of packages) {
+    let manifest = {};
+    try {
+      manifest = JSON.parse(fs.readFileSync(path.join(basePath, pkg.path, 'package.json'), 'utf8'));
+    } catch {
+      // Not a Node package
+    }
+    const names = ['dependencies', 'devDependencies', 'peerDependencies', 'optionalDependencies']
+      .flatMap(field => Object.keys((manifest && manifest[field]) || {}));
+    edges[pkg.path] = Array.from(new Set(names.map(name => pathByName.get(name))))
+      .filter(dep => dep && dep !== pkg.path)
+      .sort();
+  }
+  return edges;
+}
+
+/**
+ * Progress reporter for runTasks
+ * Prints one line per finished task: `[3/24] done packages/api (1.2s)`.
+ * @param {{write: Function}} stream - Usually process.stderr
+ * @returns {Function} onProgress handler
+ */
+function createProgress(stream) {
+  return event => {
+    if (event.type === 'start') return;
+    const width = String(event.total).length;
+    const seconds = event.durationMs !== undefined ? ` (${(event.durationMs / 1000).toFixed(1)}s)` : '';
+    const reason = event.error ? `: ${event.error}` : '';
+    stream.write(`[${String(event.completed).padStart(width)}/${event.total}] ${event.type} ${event.task.label}${seconds}${reason}\n`);
+  };
+}
+
+/**
+ * One-line summary of a run
+ * @param {Object} result - Result of runTasks
+ * @returns {string} `24 tasks in 8.1s (concurrency 8): 22 done, 1 failed, 1 skipped`
+ */
+function renderSummary(result) {
+  if (result.error) return result.error;
+  const counts = ['done', 'failed', 'skipped']
+    .map(status => [status, result.tasks.filter(task => task.status === status).length])
+    .filter(([status, count]) => count > 0 || status === 'done')
+    .map(([status, count]) => `${count} ${status}`);
+  return `${result.tasks.length} tasks in ${(result.durationMs / 1000).toFixed(1)}s (concurrency ${result.concurrency}): ${counts.join(', ')}`;
+}
+
+module.exports = {
+  MAX_DEFAULT_CONCURRENCY,
+  resolveConcurrency,
+  parseConcurrency,
+  orderTasks,
+  runTasks,
+  runCommand,
+  workspaceDependencies,
+  createProgress,
+  renderSummary
+};
@@ -0,0 +1,377 @@
+#!/usr/bin/env node
+/**
+ * TODO Triage
+ *
+ * Finds TODO/FIXME/HACK/XXX comments in tracked files and dates each one
+ * with `git blame` (one blame per file). Items are owned by the name in
+ * `TODO(name):` when present, otherwise by the author of the line, and fall
+ * into staleness buckets by age. Items older than the threshold can become
+ * tracking issues; `issueCommand` returns the forge CLI argv so the caller
+ * confirms before anything is created.
+ *
+ * Usage: node lib/todos/index.js [--older-than <days>]
+ * Output: JSON triage report
+ *
+ * @module lib/todos
+ */
+
+const fs = require('fs');
+const path = require('path');
+
+const { loadConfig } = require('../config');
+const { getRepository } = require('../changelog');
+const { slopPatterns } = require('../patterns/slop-patterns');
+const { git } = require('../utils/exec');
+
+const MARKERS = ['TODO', 'FIXME', 'HACK', 'XXX'];
+
+// The marker must open a comment: `// TODO`, `# FIXME(ana):`, ` * HACK -`, `-- XXX`
+const MARKER_COMMENT = new RegExp(
+  `(?:^|\\s|;)(?:\\/\\/+|#+|\\/\\*+|\\*|--|<!--|;+)\\s*(${MARKERS.join('|')})\\b(?:\\(([^)]*)\\))?[:\\s-]*(.*?)\\s*(?:\\*\\/|-->)?\\s*$`
+);
+
+/**
+ * Staleness buckets by age in days (upper bound exclusive)
+ */
+const STALENESS = [
+  { name: 'fresh', maxDays: 30 },
+  { name: 'aging', maxDays: 90 },
+  { name: 'stale', maxDays: 365 },
+  { name: 'ancient', maxDays: Infinity }
+];
+
+/**
+ * Days before an item is overdue; matches the old_todos slop pattern
+ */
+const DEFAULT_THRESHOLD = slopPatterns.old_todos.ageThreshold;
+
+/**
+ * Config key in .awesome-slash.json: `{threshold, labels}`
+ */
+const CONFIG_KEY = 'todoTriage';
+
+// Prose and data files: markers there are notes, not code debt
+const SKIPPED_FILES = /\.(?:md|mdx|rst|txt|json|lock|csv|svg|min\.js|map)$|(?:^|\/)(?:CHANGELOG|LICENSE)[^/]*$|(?:package-lock\.json|yarn\.lock|pnpm-lock\.yaml)$/i;
+
+const MAX_FILE_BYTES = 1024 * 1024;
+const UNCOMMITTED = /^0{40}$/;
+const DAY_MS = 24 * 60 * 60 * 1000;
+const TITLE_LENGTH = 72;
+
+/**
+ * Find marker comments in file content
+ * @param {string} content - File content
+ * @returns {Array<{line: number, marker: string, tag: string|null, text: string}>}
+ */
+function findMarkers(content) {
+  const found = [];
+  content.split('\n').forEach((source, i) => {
+    const match = source.match(MARKER_COMMENT);
+    if (!match) return;
+    found.push({
+      line: i + 1,
+      marker: match[1],
+      tag: match[2] ? match[2].trim() || null : null,
+      text: match[3]
+    });
+  });
+  return found;
+}
+
+/**
+ * Scan tracked files for marker comments
+ * @param {string} basePath - Repository root
+ * @param {Object} [options]
+ * @param {string[]} [options.files] - Files to scan (default: `git ls-files`)
+ * @returns {Array<{file: string, line: number, marker: string, tag: string|null, text: string}>}
+ */
+function scanTodos(basePath, options = {}) {
+  const files = options.files || (git(basePath, ['ls-files', '-z']) || '').split('\0').filter(Boolean);
+  const items = [];
+  for (const file of files) {
+    if (SKIPPED_FILES.test(file)) continue;
+    let content;
+    try {
+      const full = path.join(basePath, file);
+      if (fs.statSync(full).size > MAX_FILE_BYTES) continue;
+      content = fs.readFileSync(full, 'utf8');
+    } catch {
+      continue;
+    }
+    if (content.includes('\0')) continue;
+    for (const marker of findMarkers(content)) items.push({ file, ...marker });
+  }
+  return items;
+}
+
+/**
+ * Parse `git blame --line-porcelain` output
+ * @param {string} output - Blame output
+ * @returns {Map<number, {commit: string, author: string|null, email: string|null, time: number|null}>} Final line -> origin
+ */
+function parseBlame(output) {
+  const lines = new Map();
+  let current = null;
+  for (const row of String(output || '').split('\n')) {
+    const header = row.match(/^([0-9a-f]{40}) \d+ (\d+)/);
+    if (header) {
+      current = { commit: header[1], author: null, email: null, time: null };
+      lines.set(Number(header[2]), current);
+    } else if (current && row.startsWith('author ')) {
+      current.author = row.slice(7);
+    } else if (current && row.startsWith('author-mail ')) {
+      current.email = row.slice(12).replace(/^<|>$/g, '');
+    } else if (current && row.startsWith('author-time ')) {
+      current.time = Number(row.slice(12));
+    }
+  }
+  return lines;
+}
+
+/**
+ * Staleness bucket for an age
+ * @param {number} days - Age in days
+ * @returns {string}
+ */
+function stalenessOf(days) {
+  return STALENESS.find(bucket => days < bucket.maxDays).name;
+}
+
+/**
+ * Add author, date, age, owner, and staleness from git blame
+ * @param {string} basePath - Repository root
+ * @param {Object[]} items - Results of `scanTodos`
+ * @param {Object} [options]
+ * @param {number} [options.now=Date.now()] - Reference time (ms)
+ * @param {Function} [options.blame] - (file) => `--line-porcelain` output
+ * @returns {Object[]} Items with `commit, author, email, date, ageDays, owner, staleness`;
+ *   uncommitted lines have a null commit and age 0
+ */
+function enrichTodos(basePath, items, options = {}) {
+  const now = options.now || Date.now();
+  const blame = options.blame || (file => git(basePath, ['blame', '--line-porcelain', '-w', '--', file]));
+  const byFile = new Map();
+
+  return items.map(item => {
+    if (!byFile.has(item.file)) byFile.set(item.file, parseBlame(blame(item.file)));
+    const origin = byFile.get(item.file).get(item.line);
+    const committed = origin && !UNCOMMITTED.test(origin.commit) && origin.time;
+    const ageDays = committed ? Math.max(0, Math.floor((now - origin.time * 1000) / DAY_MS)) : 0;
+    const author = committed ? origin.author : null;
+    return {
+      ...item,
+      commit: committed ? origin.commit : null,
+      author,
+      email: committed ? origin.email : null,
+      date: committed ? new Date(origin.time * 1000).toISOString().slice(0, 10) : null,
+      ageDays,
+      owner: item.tag || author || 'uncommitted',
+      staleness: stalenessOf(ageDays)
+    };
+  });
+}
+
+/**
+ * Group items by owner, most items first
+ * @param {Object[]} items - Results of `enrichTodos`
+ * @returns {Array<{owner: string, count: number, oldestDays: number, items: Object[]}>}
+ */
+function groupByOwner(items) {
+  const owners = new Map();
+  for (const item of items) {
+    if (!owners.has(item.owner)) owners.set(item.owner, []);
+    owners.get(item.owner).push(item);
+  }
+  return Array.from(owners, ([owner, list]) => ({
+    owner,
+    count: list.length,
+    oldestDays: Math.max(...list.map(item => item.ageDays)),
+    items: list.sort((a, b) => b.ageDays - a.ageDays)
+  })).sort((a, b) => b.count - a.count || b.oldestDays - a.oldestDays || a.owner.localeCompare(b.owner));
+}
+
+/**
+ * Count items per staleness bucket
+ * @param {Object[]} items - Results of `enrichTodos`
+ * @returns {Array<{name: string, count: number}>}
+ */
+function groupByStaleness(items) {
+  return STALENESS.map(({ name }) => ({ name, count: items.filter(item => item.staleness === name).length }));
+}
+
+/**
+ * Read `todoTriage` settings from the project config
+ * @param {string} basePath - Repository root
+ * @returns {{threshold: number|null, labels: string[], error: string|null}}
+ */
+function readSettings(basePath) {
+  const { config, file, error } = loadConfig(basePath);
+  const settings = { threshold: null, labels: [], error };
+  const value = config[CONFIG_KEY];
+  if (error || value === undefined) return settings;
+
+  if (!value || typeof value !== 'object' || Array.isArray(value)) {
+    return { ...settings, error: `${file}: ${CONFIG_KEY} must be an object` };
+  }
+  if (value.threshold !== undefined) {
+    if (!Number.isInteger(value.threshold) || value.threshold < 1) {
+      return { ...settings, error: `${file}: ${CONFIG_KEY}.threshold must be a positive number of days` };
+    }
+    settings.threshold = value.threshold;
+  }
+  if (value.labels !== undefined) {
+    settings.labels = [].concat(value.labels).filter(label => typeof label === 'string');
+  }
+  return settings;
+}
+
+/**
+ * Tracking issue title
+ * @param {Object} item - Enriched item
+ * @returns {string}
+ */
+function issueTitle(item) {
+  const text = item.text || `in ${item.file}`;
+  const title = `${item.marker}: ${text}`;
+  return title.length > TITLE_LENGTH ? `${title.slice(0, TITLE_LENGTH - 3).trimEnd()}...` : title;
+}
+
+/**
+ * Tracking issue body, linking the line at the commit that added it
+ * @param {Object} item - Enriched item
+ * @param {{host: string, url: string}|null} repository - From changelog.getRepository
+ * @returns {string}
+ */
+function issueBody(item, repository) {
+  const location = `${item.file}:${item.line}`;
+  const blob = repository && item.commit
+    ? `${repository.url}${repository.host === 'gitlab' ? '/-' : ''}/blob/${item.commit}/${item.file}#L${item.line}`
+    : null;
+  return [
+    `\`${item.marker}\` comment found by /todo-triage.`,
+    '',
+    `> ${item.text || '(no description)'}`,
+    '',
+    `- **Location**: ${blob ? `[${location}](${blob})` : `\`${location}\``}`,
+    `- **Added by**: ${item.author || 'uncommitted'}${item.date ? ` on ${item.date} (${item.ageDays} days ago)` : ''}`,
+    ...(item.tag ? [`- **Owner**: ${item.tag}`] : []),
+    '',
+    'Close this issue when the comment is resolved or removed.'
+  ].join('\n');
+}
+
+/**
+ * Forge CLI argv that opens a tracking issue
+ * @param {{host: string, url: string}|null} repository - From changelog.getRepository
+ * @param {Object} item - Enriched item
+ * @param {Object} [options]
+ * @param {string[]} [options.labels] - Labels to apply
+ * @returns {string[]|null} argv, or null when the host has no supported CLI
+ */
+function issueCommand(repository, item, options = {}) {
+  const labels = options.labels || [];
+  const title = issueTitle(item);
+  const body = issueBody(item, repository);
+  if (repository?.host === 'github') {
+    return ['gh', 'issue', 'create', '--title', title, '--body', body, ...labels.flatMap(label => ['--label', label])];
+  }
+  if (repository?.host === 'gitlab') {
+    return ['glab', 'issue', 'create', '--title', title, '--description', body, ...(labels.length ? ['--label', labels.join(',')] : [])];
+  }
+  return null;
+}
+
+/**
+ * Scan, date, and group marker comments
+ * @param {string} basePath - Repository root
+ * @param {Object} [options]
+ * @param {number} [options.threshold] - Days before an item is overdue (default: config, then 90)
+ * @param {number} [options.now] - Reference time (ms)
+ * @param {string[]} [options.files] - Files to scan
+ * @param {Function} [options.blame] - (file) => `--line-porcelain` output
+ * @param {string|null} [options.ciPlatform] - Detected CI platform, for forge detection
+ * @returns {Object} `{success, total, threshold, labels, items, owners, staleness, overdue, repository, configError, error}`
+ */
+function triageTodos(basePath, options = {}) {
+  if (!options.blame && git(basePath, ['rev-parse', '--is-inside-work-tree']) === null) {
+    return { success: false, error: `${basePath} is not a git repository` };
+  }
+  const settings = readSettings(basePath);
+  const threshold = options.threshold || settings.threshold || DEFAULT_THRESHOLD;
+  const items = enrichTodos(basePath, scanTodos(basePath, options), options)
+    .sort((a, b) => b.ageDays - a.ageDays || a.file.localeCompare(b.file) || a.line - b.line);
+
+  return {
+    success: true,
+    total: items.length,
+    threshold,
+    labels: settings.labels,
+    items,
+    owners: groupByOwner(items),
+    staleness: groupByStaleness(items),
+    overdue: items.filter(item => item.commit && item.ageDays >= threshold),
+    repository: getRepository(basePath, options.ciPlatform),
+    configError: settings.error
+  };
+}
+
+/**
+ * Render the triage report as markdown
+ * @param {Object} report - Result of `triageTodos`
+ * @param {Object} [options]
+ * @param {number} [options.limit=20] - Overdue rows listed
+ * @returns {string}
+ */
+function renderReport(report, options = {}) {
+  const limit = options.limit || 20;
+  const lines = ['## TODO Triage', ''];
+  lines.push(`**Items**: ${report.total} | **Overdue** (${report.threshold}+ days): ${report.overdue.length}`);
+  lines.push(`**Staleness**: ${report.staleness.map(bucket => `${bucket.name} ${bucket.count}`).join(' | ')}`);
+  if (report.configError) lines.push(`**Config**: ${report.configError} (defaults used)`);
+  lines.push('');
+  if (report.total === 0) {
+    lines.push('No TODO, FIXME, HACK, or XXX comments found.');
+    return lines.join('\n');
+  }
+
+  lines.push('### By owner', '', '| Owner | Items | Oldest (days) |', '|-------|-------|---------------|');
+  for (const owner of report.owners) lines.push(`| ${owner.owner} | ${owner.count} | ${owner.oldestDays} |`);
+  lines.push('');
+
+  if (report.overdue.length) {
+    lines.push('### Overdue', '', '| Age (days) | Marker | Location | Owner | Text |', '|------------|--------|----------|-------|------|');
+    for (const item of report.overdue.slice(0, limit)) {
+      lines.push(`| ${item.ageDays} | ${item.marker} | \`${item.file}:${item.line}\` | ${item.owner} | ${item.text.replace(/\|/g, '\\|')} |`);
+    }
+    if (report.overdue.length > limit) lines.push('', `...and ${report.overdue.length - limit} more`);
+    lines.push('');
+  }
+  return lines.join('\n');
+}
+
+if (require.main === module) {
+  const args = process.argv.slice(2);
+  const value = flag => (args.includes(flag) ? args[args.indexOf(flag) + 1] : undefined);
+  const report = triageTodos(process.cwd(), { threshold: Number(value('--older-than')) || undefined });
+  const indent = process.stdout.isTTY ? 2 : 0;
+  console.log(JSON.stringify(report, null, indent));
+}
+
+module.exports = {
+  MARKERS,
+  STALENESS,
+  DEFAULT_THRESHOLD,
+  CONFIG_KEY,
+  findMarkers,
+  scanTodos,
+  parseBlame,
+  enrichTodos,
+  groupByOwner,
+  groupByStaleness,
+  readSettings,
+  issueTitle,
+  issueBody,
+  issueCommand,
+  triageTodos,
+  renderReport
+};
@@ -0,0 +1,160 @@
+/**
+ * CODEOWNERS
+ * Who owns a path, read from the repository's CODEOWNERS file the way
+ * GitHub and GitLab match it: gitignore-style patterns, last matching rule
+ * wins, and a rule without owners leaves the path unowned.
+ *
+ * Findings carry their file's owners (`owners`, as written: `@user`,
+ * `@org/team`, `dev@example.com`) so reports can group them per team and
+ * the GitHub and GitLab integrations can request the owners' review.
+ *
+ * @module lib/utils/codeowners
+ * @author Avi Fenesh
+ * @license MIT
+ */
+
+const fs = require('fs');
+const path = require('path');
+const { compilePattern } = require('./ignore');
+
+/**
+ * CODEOWNERS locations, in the order GitHub and GitLab look for them
+ */
+const CODEOWNERS_FILES = ['.github/CODEOWNERS', 'CODEOWNERS', 'docs/CODEOWNERS', '.gitlab/CODEOWNERS'];
+
+/**
+ * Group name for findings without owners
+ */
+const UNOWNED = null;
+
+/**
+ * Compile a CODEOWNERS pattern
+ * The syntax is gitignore's, except that `docs/*` owns only the files
+ * directly in `docs/`; gitignore would also match everything below them.
+ * @param {string} pattern - CODEOWNERS pattern
+ * @returns {RegExp}
+ */
+function compileOwnerPattern(pattern) {
+  const { regex } = compilePattern(pattern);
+  if (!pattern.endsWith('/*')) return regex;
+  return new RegExp(regex.source.replace(/\(\?:\$\|\\?\/\)$/, '$'));
+}
+
+/**
+ * Parse CODEOWNERS content
+ * GitLab section headers (`[Docs]`, `^[Docs][2] @owner`) are skipped;
+ * their default owners do not apply, since GitHub has no sections.
+ * @param {string} content - CODEOWNERS file
+ * @returns {Array<{pattern: string, regex: RegExp, owners: string[]}>}
+ */
+function parseCodeowners(content) {
+  const rules = [];
+  for (const raw of String(content || '').split('\n')) {
+    const line = raw.replace(/(^|\s)#.*$/, '').trim();
+    if (!line || /^\^?\[/.test(line)) continue;
+    const [pattern, ...owners] = line.split(/\s+/);
+    rules.push({ pattern, regex: compileOwnerPattern(pattern), owners });
+  }
+  return rules;
+}
+
+/**
+ * Read the repository's CODEOWNERS, if any
+ * @param {string} basePath - Repository root
+ * @returns {{file: string, rules: Object[]}|null}
+ */
+function readCodeowners(basePath) {
+  for (const file of CODEOWNERS_FILES) {
+    try {
+      return { file, rules: parseCodeowners(fs.readFileSync(path.join(basePath, file), 'utf8')) };
+    } catch {
+      // try the next location
+    }
+  }
+  return null;
+}
+
+/**
+ * Owners of a path (last matching rule wins)
+ * @param {Object[]} rules - Result of parseCodeowners
+ * @param {string|null} file - Path relative to the root; null matches only catch-all rules
+ * @returns {string[]} Owners as written in CODEOWNERS
+ */
+function ownersFor(rules, file) {
+  let owners = [];
+  const normalized = file ? String(file).replace(/\\/g, '/').replace(/^\.\//, '') : null;
+  for (const rule of rules) {
+    if (normalized ? rule.regex.test(normalized) : rule.pattern === '*') owners = rule.owners;
+  }
+  return owners.slice();
+}
+
+/**
+ * Copies of findings with their file's owners in `owners`
+ * Findings that already carry `owners` keep them.
+ * @param {Object[]} findings - Findings with `file` relative to the CODEOWNERS root
+ * @param {{rules: Object[]}|null} codeowners - Result of readCodeowners
+ * @returns {Object[]} The findings unchanged when there is no CODEOWNERS
+ */
+function attachOwners(findings, codeowners) {
+  if (!codeowners) return findings;
+  return findings.map(finding => (Array.isArray(finding.owners)
+    ? finding
+    : { ...finding, owners: ownersFor(codeowners.rules, finding.file || finding.path || null) }));
+}
+
+/**
+ * Group findings per owner, most findings first
+ * A finding with several owners is listed under each of them.
+ * @param {Object[]} findings - Findings with `owners`
+ * @returns {Array<{owner: string|null, findings: Object[]}>} `owner` is null for unowned findings, listed last
+ */
+function groupByOwner(findings) {
+  const groups = new Map();
+  for (const finding of findings) {
+    const owners = finding.owners && finding.owners.length > 0 ? finding.owners : [UNOWNED];
+    for (const owner of owners) {
+      if (!groups.has(owner)) groups.set(owner, []);
+      groups.get(owner).push(finding);
+    }
+  }
+  return [...groups.entries()]
+    .map(([owner, grouped]) => ({ owner, findings: grouped }))
+    .sort((a, b) => (a.owner === UNOWNED) - (b.owner === UNOWNED) ||
+      b.findings.length - a.findings.length || String(a.owner).localeCompare(String(b.owner)));
+}
+
+/**
+ * Owners that can be asked for a review, from the findings' owners
+ * Email owners cannot be requested and are left out.
+ * @param {Object[]} findings - Findings with `owners`
+ * @param {Object} [options]
+ * @param {string[]} [options.exclude] - Usernames to leave out (e.g. the pull request author)
+ * @param {number} [options.limit=10] - Most reviewers returned
+ * @returns {{users: string[], teams: string[]}} Without `@`; teams as `org/team` (GitLab: `group/subgroup`)
+ */
+function reviewersFor(findings, options = {}) {
+  const exclude = new Set((options.exclude || []).map(user => user.toLowerCase()));
+  const limit = options.limit || 10;
+  const users = new Set();
+  const teams = new Set();
+  for (const finding of findings) {
+    for (const owner of finding.owners || []) {
+      if (!/^@[\w.-]+(?:\/[\w.-]+)*$/.test(owner)) continue;
+      const name = owner.slice(1);
+      if (name.includes('/')) teams.add(name);
+      else if (!exclude.has(name.toLowerCase())) users.add(name);
+    }
+  }
+  return { users: [...users].slice(0, limit), teams: [...teams].slice(0, limit) };
+}
+
+module.exports = {
+  CODEOWNERS_FILES,
+  parseCodeowners,
+  readCodeowners,
+  ownersFor,
+  attachOwners,
+  groupByOwner,
+  reviewersFor
+};
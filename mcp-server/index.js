@@ -7,7 +7,7 @@
  * - OpenCode
  * - Codex CLI
  *
- * Run: node mcp-server/index.js
+ * Run: node mcp-server/index.js (or: awesome-slash serve --mcp)
  */
 
 const { Server } = require('@modelcontextprotocol/sdk/server/index.js');
@@ -20,15 +20,22 @@ const {
 const path = require('path');
 const fs = require('fs').promises;
 const fsSync = require('fs');
-const { promisify } = require('util');
-const { exec } = require('child_process');
 const { Worker } = require('worker_threads');
-const execAsync = promisify(exec);
 const workflowState = require('../lib/state/workflow-state.js');
 const { runPipeline, formatHandoffPrompt, CERTAINTY, THOROUGHNESS } = require('../lib/patterns/pipeline.js');
 const crossPlatform = require('../lib/cross-platform/index.js');
 const enhance = require('../lib/enhance/index.js');
 const repoMap = require('../lib/repo-map');
+const repoQuery = require('../lib/repo-map/query');
+const detectPlatform = require('../lib/platform/detect-platform.js');
+const deps = require('../lib/deps');
+const licenseCheck = require('../lib/license-check');
+const todos = require('../lib/todos');
+const envCheck = require('../lib/env-check');
+const { createLogger } = require('../lib/utils/logger');
+const exec = require('../lib/utils/exec');
+
+const log = createLogger('mcp');
 
 // Plugin root for relative paths
 const PLUGIN_ROOT = process.env.PLUGIN_ROOT || path.join(__dirname, '..');
@@ -273,9 +280,22 @@ const TOOLS = [
       properties: {
         action: {
           type: 'string',
-          enum: ['init', 'update', 'status', 'rebuild'],
+          enum: ['init', 'update', 'status', 'rebuild', 'render'],
           description: 'Action to perform (default: status)'
         },
+        maxTokens: {
+          type: 'number',
+          description: 'Token budget for render; drops private symbols, then details, then low-rank files to fit'
+        },
+        format: {
+          type: 'string',
+          enum: ['text', 'json', 'mermaid', 'dot'],
+          description: 'Render output format (default: text). json/mermaid/dot emit the file dependency graph'
+        },
+        maxFiles: {
+          type: 'number',
+          description: 'For json/mermaid/dot renders, keep only the highest-ranked files'
+        },
         includeDocs: {
           type: 'boolean',
           description: 'Include documentation analysis (default: true)'
@@ -300,6 +320,88 @@ const TOOLS = [
       },
       required: []
     }
+  },
+  {
+    name: 'repo_query',
+    description: 'Find symbol definitions or cross-file callers in the repo map',
+    inputSchema: {
+      type: 'object',
+      properties: {
+        action: {
+          type: 'string',
+          enum: ['symbol', 'callers'],
+          description: 'symbol=find definitions by name, callers=who calls it (default: symbol)'
+        },
+        name: {
+          type: 'string',
+          description: 'Symbol name or Class.method; symbol search matches substrings'
+        },
+        file: {
+          type: 'string',
+          description: 'callers: only the definition in this repo-relative file'
+        },
+        kind: {
+          type: 'string',
+          enum: ['function', 'class', 'method', 'type', 'constant'],
+          description: 'symbol: only this kind'
+        },
+        exact: {
+          type: 'boolean',
+          description: 'symbol: exact, case-sensitive name only (default: false)'
+        },
+        limit: {
+          type: 'number',
+          description: 'symbol: maximum results (default: 50)'
+        },
+        cwd: {
+          type: 'string',
+          description: 'Repo-relative root (default: repo root)'
+        }
+      },
+      required: ['name']
+    }
+  },
+  {
+    name: 'platform_detect',
+    description: 'Detect CI, deployment, project type, package manager, and branching',
+    inputSchema: {
+      type: 'object',
+      properties: {
+        refresh: {
+          type: 'boolean',
+          description: 'Ignore the cached detection (default: false)'
+        }
+      },
+      required: []
+    }
+  },
+  {
+    name: 'scan_run',
+    description: 'Run a scanner: dependency audit, license check, TODO triage, or env check',
+    inputSchema: {
+      type: 'object',
+      properties: {
+        scanner: {
+          type: 'string',
+          enum: ['deps', 'licenses', 'todos', 'env'],
+          description: 'Scanner to run'
+        },
+        format: {
+          type: 'string',
+          enum: ['markdown', 'json'],
+          description: 'Report format (default: markdown, far fewer tokens)'
+        },
+        offline: {
+          type: 'boolean',
+          description: 'deps/licenses: skip OSV and registry lookups'
+        },
+        cwd: {
+          type: 'string',
+          description: 'Repo-relative root (default: repo root)'
+        }
+      },
+      required: ['scanner']
+    }
   }
 ];
 
@@ -428,9 +530,7 @@ const toolHandlers = {
       let tasks = [];
 
       if (taskSource === 'gh-issues') {
-        try {
-          await execAsync('gh --version');
-        } catch (error) {
+        if (await exec.runAsync(process.cwd(), ['gh', '--version']) === null) {
           return {
             content: [{
               type: 'text',
@@ -447,7 +547,8 @@ const toolHandlers = {
           '--json', 'number,title,labels,createdAt',
           '--limit', String(maxTasks)
         ];
-        const { stdout } = await execAsync(`gh ${ghArgs.join(' ')}`);
+        const stdout = await exec.runAsync(process.cwd(), ['gh', ...ghArgs]);
+        if (stdout === null) throw new Error('gh issue list failed');
 
         const issues = JSON.parse(stdout || '[]');
 
@@ -475,9 +576,7 @@ const toolHandlers = {
 
       } else if (taskSource === 'linear') {
         // Linear integration via GitHub issues containing Linear URLs
-        try {
-          await execAsync('gh --version');
-        } catch (error) {
+        if (await exec.runAsync(process.cwd(), ['gh', '--version']) === null) {
           return {
             content: [{
               type: 'text',
@@ -493,7 +592,8 @@ const toolHandlers = {
           '--json', 'number,title,body,labels,createdAt',
           '--limit', String(Math.min(maxTasks * 2, 100)) // Fetch more since we'll filter for Linear links
         ];
-        const { stdout } = await execAsync(`gh ${ghArgs.join(' ')}`);
+        const stdout = await exec.runAsync(process.cwd(), ['gh', ...ghArgs]);
+        if (stdout === null) throw new Error('gh issue list failed');
         const issues = JSON.parse(stdout || '[]');
 
         // Extract Linear URLs from issue bodies (length-limited to prevent ReDoS)
@@ -668,7 +768,7 @@ const toolHandlers = {
       };
 
     } catch (error) {
-      console.error('Error discovering tasks:', error);
+      log.error('Error discovering tasks', { error, stack: error.stack });
       return {
         content: [{
           type: 'text',
@@ -686,21 +786,19 @@ const toolHandlers = {
       // Auto-detect files from git if not provided
       if (!filesToReview.length) {
         try {
-          const { stdout } = await execAsync('git diff --name-only HEAD');
-          filesToReview = stdout.trim().split('\n').filter(f => f);
+          const changed = await exec.runAsync(process.cwd(), ['git', 'diff', '--name-only', 'HEAD']);
+          if (changed === null) throw new Error('git diff --name-only HEAD failed');
+          filesToReview = changed.trim().split('\n').filter(f => f);
 
           if (!filesToReview.length) {
-            const { stdout: stagedOut } = await execAsync('git diff --cached --name-only');
-            filesToReview = stagedOut.trim().split('\n').filter(f => f);
+            const stagedOut = await exec.runAsync(process.cwd(), ['git', 'diff', '--cached', '--name-only']);
+            filesToReview = (stagedOut || '').trim().split('\n').filter(f => f);
           }
 
           if (!filesToReview.length) {
-            try {
-              const { stdout: lastCommit } = await execAsync('git diff --name-only HEAD~1');
-              filesToReview = lastCommit.trim().split('\n').filter(f => f);
-            } catch (e) {
-              // HEAD~1 doesn't exist (single-commit repo)
-            }
+            // Null when HEAD~1 doesn't exist (single-commit repo)
+            const lastCommit = await exec.runAsync(process.cwd(), ['git', 'diff', '--name-only', 'HEAD~1']);
+            filesToReview = (lastCommit || '').trim().split('\n').filter(f => f);
           }
         } catch (error) {
           return crossPlatform.errorResponse(
@@ -734,7 +832,7 @@ const toolHandlers = {
           mode: 'report'
         });
       } catch (error) {
-        console.warn(`Pipeline worker failed, falling back to sync run: ${error.message}`);
+        log.warn('Pipeline worker failed, falling back to sync run', { error });
         result = runPipeline(process.cwd(), {
           thoroughness: thoroughness || THOROUGHNESS.NORMAL,
           targetFiles: filesToReview,
@@ -757,7 +855,7 @@ const toolHandlers = {
       });
 
     } catch (error) {
-      console.error('Error during code review:', error);
+      log.error('Error during code review', { error, stack: error.stack });
       return crossPlatform.errorResponse('Code review failed. Check server logs.');
     }
   },
@@ -786,7 +884,7 @@ const toolHandlers = {
           mode: mode || 'report'
         });
       } catch (error) {
-        console.warn(`Pipeline worker failed, falling back to sync run: ${error.message}`);
+        log.warn('Pipeline worker failed, falling back to sync run', { error });
         result = runPipeline(resolvedTargetPath, {
           thoroughness: thoroughness || THOROUGHNESS.NORMAL,
           mode: mode || 'report'
@@ -817,7 +915,7 @@ const toolHandlers = {
       });
 
     } catch (error) {
-      console.error('Error during slop detection:', error);
+      log.error('Error during slop detection', { error, stack: error.stack });
       return crossPlatform.errorResponse('Slop detection failed. Check server logs.');
     }
   },
@@ -847,7 +945,7 @@ const toolHandlers = {
             summary.plugin = result.findings.length;
           }
         } catch (e) {
-          console.error('Plugin analyzer error:', e.message);
+          log.error('Plugin analyzer error', { error: e });
         }
       }
 
@@ -859,7 +957,7 @@ const toolHandlers = {
             summary.agent = result.findings.length;
           }
         } catch (e) {
-          console.error('Agent analyzer error:', e.message);
+          log.error('Agent analyzer error', { error: e });
         }
       }
 
@@ -871,7 +969,7 @@ const toolHandlers = {
             summary.docs = result.findings.length;
           }
         } catch (e) {
-          console.error('Docs analyzer error:', e.message);
+          log.error('Docs analyzer error', { error: e });
         }
       }
 
@@ -883,7 +981,7 @@ const toolHandlers = {
             summary.claudemd = result.findings.length;
           }
         } catch (e) {
-          console.error('Project memory analyzer error:', e.message);
+          log.error('Project memory analyzer error', { error: e });
         }
       }
 
@@ -895,7 +993,7 @@ const toolHandlers = {
             summary.prompt = result.findings.length;
           }
         } catch (e) {
-          console.error('Prompt analyzer error:', e.message);
+          log.error('Prompt analyzer error', { error: e });
         }
       }
 
@@ -924,7 +1022,7 @@ const toolHandlers = {
               else if (fix.analyzer === 'prompt') enhance.promptApplyFixes([fix]);
               fixResults.applied++;
             } catch (e) {
-              console.error(`Fix failed for ${fix.file}:`, e.message);
+              log.error('Fix failed', { file: fix.file, error: e });
             }
           }
         }
@@ -956,12 +1054,12 @@ const toolHandlers = {
       });
 
     } catch (error) {
-      console.error('Error during enhance analysis:', error);
+      log.error('Error during enhance analysis', { error, stack: error.stack });
       return crossPlatform.errorResponse('Enhance analysis failed. Check server logs.');
     }
   },
 
-  async repo_map({ action, includeDocs, docsDepth, full, force, cwd }) {
+  async repo_map({ action, includeDocs, docsDepth, full, force, maxTokens, format, maxFiles, cwd }) {
     try {
       const requestedPath = cwd || REPO_ROOT;
       const resolvedBasePath = resolveRepoPath(requestedPath);
@@ -984,8 +1082,10 @@ const toolHandlers = {
         result = await repoMap.update(basePath, { full: full === true });
       } else if (act === 'status') {
         result = repoMap.status(basePath);
+      } else if (act === 'render') {
+        result = repoMap.render(basePath, { maxTokens, format, maxFiles });
       } else {
-        return crossPlatform.errorResponse('Invalid action. Use init, update, status, or rebuild.');
+        return crossPlatform.errorResponse('Invalid action. Use init, update, status, rebuild, or render.');
       }
 
       if (result?.success === false) {
@@ -1006,9 +1106,78 @@ const toolHandlers = {
         result
       });
     } catch (error) {
-      console.error('Error during repo-map:', error);
+      log.error('Error during repo-map', { error, stack: error.stack });
       return crossPlatform.errorResponse('Repo-map failed. Check server logs.');
     }
+  },
+
+  async repo_query({ action, name, file, kind, exact, limit, cwd }) {
+    const basePath = resolveRepoPath(cwd || REPO_ROOT);
+    if (!basePath) {
+      return crossPlatform.errorResponse(`Invalid path outside repository: ${cwd}`);
+    }
+    if (!name) {
+      return crossPlatform.errorResponse('name is required');
+    }
+
+    const map = repoMap.load(basePath);
+    if (!map) {
+      return crossPlatform.errorResponse('No repo-map found', { hint: 'Run repo_map action=init first' });
+    }
+
+    const act = (action || 'symbol').toLowerCase();
+    if (act === 'symbol') {
+      return crossPlatform.successResponse({ query: name, ...repoQuery.findSymbols(map, name, { kind, exact, limit }) });
+    }
+    if (act === 'callers') {
+      return crossPlatform.successResponse({
+        symbol: name,
+        ...repoQuery.findCallers(map, name, { file }),
+        note: 'Cross-file calls only; calls inside the defining file are not recorded'
+      });
+    }
+    return crossPlatform.errorResponse('Invalid action. Use symbol or callers.');
+  },
+
+  async platform_detect({ refresh }) {
+    try {
+      return crossPlatform.successResponse(await detectPlatform.detect(refresh === true));
+    } catch (error) {
+      log.error('Error during platform detection', { error, stack: error.stack });
+      return crossPlatform.errorResponse('Platform detection failed. Check server logs.');
+    }
+  },
+
+  async scan_run({ scanner, format, offline, cwd }) {
+    const basePath = resolveRepoPath(cwd || REPO_ROOT);
+    if (!basePath) {
+      return crossPlatform.errorResponse(`Invalid path outside repository: ${cwd}`);
+    }
+
+    const scanners = {
+      deps: () => deps.auditDependencies(basePath, { map: repoMap.load(basePath), offline: offline === true }),
+      licenses: () => licenseCheck.checkLicenses(basePath, { offline: offline === true }),
+      todos: () => todos.triageTodos(basePath),
+      env: () => envCheck.checkEnv(basePath, { map: repoMap.load(basePath) })
+    };
+    const renderers = { deps, licenses: licenseCheck, todos, env: envCheck };
+    if (!scanners[scanner]) {
+      return crossPlatform.errorResponse(`Unknown scanner: ${scanner}`, { hint: `Use ${Object.keys(scanners).join(', ')}` });
+    }
+
+    try {
+      const report = await scanners[scanner]();
+      if (report && report.success === false) {
+        return crossPlatform.errorResponse(report.error || `${scanner} scan failed`);
+      }
+      if (format === 'json') {
+        return crossPlatform.successResponse({ scanner, report });
+      }
+      return { content: [{ type: 'text', text: renderers[scanner].renderReport(report) }] };
+    } catch (error) {
+      log.error(`Error during ${scanner} scan`, { error, stack: error.stack });
+      return crossPlatform.errorResponse(`${scanner} scan failed. Check server logs.`);
+    }
   }
 };
 
@@ -1058,12 +1227,12 @@ const server = new Server(
   const transport = new StdioServerTransport();
   await server.connect(transport);
 
-  console.error('awesome-slash MCP server running');
+  log.info('awesome-slash MCP server running', { repo: REPO_ROOT });
 }
 
 // Export for testing
 if (typeof module !== 'undefined' && module.exports) {
-  module.exports = { toolHandlers };
+  module.exports = { TOOLS, toolHandlers, main, setupErrorBoundary };
 }
 
 /**
@@ -1078,8 +1247,7 @@ function setupErrorBoundary() {
 
   // Handle uncaught exceptions
   process.on('uncaughtException', (error) => {
-    console.error('Uncaught Exception:', error);
-    console.error('Stack:', error.stack);
+    log.error('Uncaught exception', { error, stack: error && error.stack });
 
     // Track error rate for graceful shutdown decision
     const now = Date.now();
@@ -1092,17 +1260,16 @@ function setupErrorBoundary() {
 
     // If too many errors in short time, exit gracefully
     if (errorCount >= MAX_ERRORS) {
-      console.error(`${MAX_ERRORS} uncaught exceptions in ${ERROR_WINDOW}ms - exiting to prevent corrupted state`);
+      log.error(`${MAX_ERRORS} uncaught exceptions in ${ERROR_WINDOW}ms - exiting to prevent corrupted state`);
       process.exit(1);
     }
 
-    console.error(`Error count: ${errorCount}/${MAX_ERRORS} in current window`);
+    log.warn(`Error count: ${errorCount}/${MAX_ERRORS} in current window`);
   });
 
   // Handle unhandled promise rejections
   process.on('unhandledRejection', (reason, promise) => {
-    console.error('Unhandled Rejection at:', promise);
-    console.error('Reason:', reason);
+    log.error('Unhandled rejection', { reason: reason instanceof Error ? reason.message : String(reason), stack: reason && reason.stack });
 
     // Track error rate
     const now = Date.now();
@@ -1115,22 +1282,22 @@ function setupErrorBoundary() {
 
     // If too many errors in short time, exit gracefully
     if (errorCount >= MAX_ERRORS) {
-      console.error(`${MAX_ERRORS} unhandled rejections in ${ERROR_WINDOW}ms - exiting to prevent corrupted state`);
+      log.error(`${MAX_ERRORS} unhandled rejections in ${ERROR_WINDOW}ms - exiting to prevent corrupted state`);
       process.exit(1);
     }
 
-    console.error(`Error count: ${errorCount}/${MAX_ERRORS} in current window`);
+    log.warn(`Error count: ${errorCount}/${MAX_ERRORS} in current window`);
   });
 
   // Handle SIGINT gracefully
   process.on('SIGINT', () => {
-    console.error('Received SIGINT, shutting down gracefully...');
+    log.info('Received SIGINT, shutting down gracefully...');
     process.exit(0);
   });
 
   // Handle SIGTERM gracefully
   process.on('SIGTERM', () => {
-    console.error('Received SIGTERM, shutting down gracefully...');
+    log.info('Received SIGTERM, shutting down gracefully...');
     process.exit(0);
   });
 }
@@ -1140,8 +1307,7 @@ if (require.main === module) {
   setupErrorBoundary();
 
   main().catch((error) => {
-    console.error('Fatal error in main():', error);
-    console.error('Stack:', error.stack);
+    log.error('Fatal error in main()', { error, stack: error.stack });
     process.exit(1);
   });
 }
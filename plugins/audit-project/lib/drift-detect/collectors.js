@@ -8,10 +8,12 @@
  * @module lib/drift-detect/collectors
  */
 
-const { execFileSync } = require('child_process');
 const fs = require('fs');
 const path = require('path');
 
+const { createIgnoreFilter, DEFAULT_EXCLUDE_DIRS } = require('../utils/ignore');
+const exec = require('../utils/exec');
+
 /**
  * Default options for data collection
  */
@@ -23,7 +25,8 @@ const DEFAULT_OPTIONS = {
   issueLimit: 100,
   prLimit: DEFAULT_PR_LIMIT,
   timeout: 10000, // 10s
-  cwd: process.cwd()
+  cwd: process.cwd(),
+  ignore: [] // Extra gitignore-style globs to skip when scanning code
 };
 
 /**
@@ -62,13 +65,9 @@ function safeReadFile(filePath, basePath) {
  * @returns {Object|null} Parsed JSON result or null
  */
 function execGh(args, options = {}) {
+  const result = exec.run(options.cwd || DEFAULT_OPTIONS.cwd, ['gh', ...args], { timeout: options.timeout || DEFAULT_OPTIONS.timeout });
+  if (result === null) return null;
   try {
-    const result = execFileSync('gh', args, {
-      encoding: 'utf8',
-      stdio: 'pipe',
-      timeout: options.timeout || DEFAULT_OPTIONS.timeout,
-      cwd: options.cwd || DEFAULT_OPTIONS.cwd
-    });
     return JSON.parse(result);
   } catch {
     return null;
@@ -80,16 +79,7 @@ function execGh(args, options = {}) {
  * @returns {boolean} True if gh is ready
  */
 function isGhAvailable() {
-  try {
-    execFileSync('gh', ['auth', 'status'], {
-      encoding: 'utf8',
-      stdio: 'pipe',
-      timeout: 5000
-    });
-    return true;
-  } catch {
-    return false;
-  }
+  return exec.run(process.cwd(), ['gh', 'auth', 'status'], { timeout: 5000 }) !== null;
 }
 
 /**
@@ -519,8 +509,14 @@ function scanCodebase(options = {}) {
   // Check for TypeScript
   result.hasTypeScript = fs.existsSync(path.join(basePath, 'tsconfig.json'));
 
+  // Built-in excludes, .gitignore, .awesome-slashignore, and opts.ignore globs
+  const isIgnored = createIgnoreFilter(basePath, {
+    excludeDirs: [...DEFAULT_EXCLUDE_DIRS, '.claude', '.opencode', '.codex'],
+    patterns: opts.ignore
+  });
+
   // Scan directory structure (internal)
-  scanDirectory({ structure: internalStructure, fileStats: result.fileStats }, basePath, '', opts.depth === 'thorough' ? 3 : 2);
+  scanDirectory({ structure: internalStructure, fileStats: result.fileStats }, basePath, '', opts.depth === 'thorough' ? 3 : 2, 0, isIgnored);
 
   // Extract summary from internal structure
   result.summary.totalDirs = Object.keys(internalStructure).length;
@@ -539,7 +535,7 @@ function scanCodebase(options = {}) {
   if (opts.depth === 'thorough') {
     findImplementedFeatures({ ...result, structure: internalStructure }, basePath);
     // Extract symbols from source files
-    result.symbols = scanFileSymbols(basePath, result.topLevelDirs);
+    result.symbols = scanFileSymbols(basePath, result.topLevelDirs, isIgnored);
   }
 
   // Limit fileStats to top 10 extensions
@@ -652,9 +648,10 @@ function extractSymbols(content) {
  * Scan key source files for symbols (recursive)
  * @param {string} basePath - Project root
  * @param {string[]} topLevelDirs - Top-level directories
+ * @param {Function} [isIgnored] - Ignore predicate from createIgnoreFilter
  * @returns {Object} File -> symbols mapping
  */
-function scanFileSymbols(basePath, topLevelDirs) {
+function scanFileSymbols(basePath, topLevelDirs, isIgnored = () => false) {
   const sourceSymbols = {};
   const sourceDirs = ['lib', 'src', 'app', 'pages', 'components', 'utils', 'services', 'api'];
   const dirsToScan = topLevelDirs.filter(d => sourceDirs.includes(d));
@@ -678,8 +675,10 @@ function scanFileSymbols(basePath, topLevelDirs) {
         if (entry.isDirectory()) {
           // Skip common non-source dirs
           if (['node_modules', '__tests__', 'test', 'tests', 'dist', 'build'].includes(entry.name)) continue;
+          if (isIgnored(relPath, true)) continue;
           scanDir(fullPath, relPath, depth + 1);
         } else if (entry.isFile()) {
+          if (isIgnored(relPath, false)) continue;
           if (!/\.(js|ts|jsx|tsx)$/.test(entry.name)) continue;
           if (entry.name.includes('.test.') || entry.name.includes('.spec.')) continue;
 
@@ -716,7 +715,7 @@ function scanFileSymbols(basePath, topLevelDirs) {
 /**
  * Scan directory structure recursively
  */
-function scanDirectory(result, basePath, relativePath, maxDepth, depth = 0) {
+function scanDirectory(result, basePath, relativePath, maxDepth, depth = 0, isIgnored = null) {
   if (depth >= maxDepth) return;
 
   const fullPath = path.join(basePath, relativePath);
@@ -728,6 +727,9 @@ function scanDirectory(result, basePath, relativePath, maxDepth, depth = 0) {
     const files = [];
 
     for (const entry of entries) {
+      const entryPath = relativePath ? `${relativePath}/${entry.name}` : entry.name;
+      if (isIgnored && isIgnored(entryPath, entry.isDirectory())) continue;
+
       // Skip common excluded directories
       if (entry.isDirectory()) {
         if (['node_modules', '.git', 'dist', 'build', 'coverage', '.claude'].includes(entry.name)) {
@@ -751,7 +753,7 @@ function scanDirectory(result, basePath, relativePath, maxDepth, depth = 0) {
 
     // Recurse into subdirectories
     for (const dir of dirs) {
-      scanDirectory(result, basePath, path.join(relativePath, dir), maxDepth, depth + 1);
+      scanDirectory(result, basePath, path.join(relativePath, dir), maxDepth, depth + 1, isIgnored);
     }
   } catch {
     // Permission or read errors
@@ -0,0 +1,400 @@
+#!/usr/bin/env node
+/**
+ * Finding History
+ *
+ * Keeps the finding counts of each scanner run (total, by severity, by
+ * category) with the commit it ran on, so the trend of slop and review
+ * debt can be shown over time. Runs are JSON files under
+ * `.awesome-slash/history/<scanner>/` at the repository root, one per run,
+ * named by time and short SHA; the oldest are pruned past
+ * `history.maxRuns` per scanner. Runs under `.awsome-slash/history/` are
+ * still read. Commit the directory to share the history, or cache it
+ * between CI runs.
+ *
+ * Only whole scans are recorded: diff-scoped and staged runs see a slice of
+ * the code and would skew the series. Runs are compared only with runs of
+ * the same scanner, scope, and mode (e.g. `slop` on `.` at `normal`).
+ *
+ * Usage: node lib/history/index.js [trends] [--scanner slop|review] [--last N] [--json]
+ * Output: markdown trend report (JSON with --json)
+ *
+ * @module lib/history
+ */
+
+const fs = require('fs');
+const path = require('path');
+
+const { loadConfig } = require('../config');
+const { t } = require('../messages');
+const exec = require('../utils/exec');
+
+/**
+ * History directories, the first is written
+ */
+const HISTORY_DIRS = ['.awesome-slash/history', '.awsome-slash/history'];
+
+const CONFIG_KEY = 'history';
+
+const DEFAULTS = { enabled: true, maxRuns: 200, last: 10 };
+
+/**
+ * Scanners that record runs
+ */
+const SCANNERS = ['slop', 'review'];
+
+const SEVERITIES = ['critical', 'high', 'medium', 'low', 'explain'];
+
+const RUN_VERSION = 1;
+
+/**
+ * Categories listed per direction in a trend report
+ */
+const MOVERS = 5;
+
+const SPARK = '▁▂▃▄▅▆▇█';
+
+/**
+ * Run a command and return stdout
+ * @param {string} basePath - Working directory
+ * @param {string[]} argv - Command and arguments
+ * @returns {string|null} null when the command fails
+ */
+function run(basePath, argv) {
+  return exec.run(basePath, argv, { timeout: 30000 });
+}
+
+/**
+ * History settings from the project config
+ * @param {string} basePath - Repository root
+ * @returns {{enabled: boolean, maxRuns: number, error: string|null}}
+ */
+function readSettings(basePath) {
+  const { config, file, error } = loadConfig(basePath);
+  const settings = { enabled: DEFAULTS.enabled, maxRuns: DEFAULTS.maxRuns, error };
+  const value = config[CONFIG_KEY];
+  if (error || value === undefined) return settings;
+
+  const fail = message => ({ ...settings, error: `${file}: ${CONFIG_KEY}${message}` });
+  if (!value || typeof value !== 'object' || Array.isArray(value)) return fail(' must be an object');
+  if (value.enabled !== undefined && typeof value.enabled !== 'boolean') return fail('.enabled must be true or false');
+  if (value.maxRuns !== undefined && !(Number.isInteger(value.maxRuns) && value.maxRuns > 0)) return fail('.maxRuns must be a positive integer');
+  return {
+    ...settings,
+    enabled: value.enabled !== false,
+    maxRuns: value.maxRuns || DEFAULTS.maxRuns
+  };
+}
+
+/**
+ * Repository root and the commit a scan ran on
+ * @param {string} scanPath - Directory that was scanned
+ * @param {Function} [runCommand] - `(basePath, argv) => stdout|null`
+ * @returns {{root: string, scope: string, commit: string|null, branch: string|null, dirty: boolean}}
+ *   `scope` is the scanned directory relative to the root (`.` for the root)
+ */
+function gitContext(scanPath, runCommand = run) {
+  const absolute = path.resolve(scanPath);
+  const root = (runCommand(absolute, ['git', 'rev-parse', '--show-toplevel']) || '').trim() || absolute;
+  const commit = (runCommand(root, ['git', 'rev-parse', 'HEAD']) || '').trim() || null;
+  const branch = (runCommand(root, ['git', 'rev-parse', '--abbrev-ref', 'HEAD']) || '').trim();
+  return {
+    root,
+    scope: exec.relativePath(fs.realpathSync(root), fs.realpathSync(absolute)) || '.',
+    commit,
+    branch: branch && branch !== 'HEAD' ? branch : null,
+    dirty: commit ? Boolean((runCommand(root, ['git', 'status', '--porcelain', '--untracked-files=no']) || '').trim()) : false
+  };
+}
+
+/**
+ * Counts of one run from its findings
+ * @param {Object[]} findings - Slop findings (`patternName`) or review findings (`category`/`pass`)
+ * @returns {{total: number, bySeverity: Object<string, number>, byCategory: Object<string, number>}}
+ *   Review findings marked `falsePositive` are not counted
+ */
+function countFindings(findings) {
+  const counts = { total: 0, bySeverity: Object.fromEntries(SEVERITIES.slice(0, 4).map(severity => [severity, 0])), byCategory: {} };
+  for (const finding of findings || []) {
+    if (!finding || finding.falsePositive) continue;
+    const severity = finding.severity || 'medium';
+    const category = finding.patternName || finding.category || finding.pass || 'other';
+    counts.total++;
+    counts.bySeverity[severity] = (counts.bySeverity[severity] || 0) + 1;
+    counts.byCategory[category] = (counts.byCategory[category] || 0) + 1;
+  }
+  return counts;
+}
+
+/**
+ * Runs stored for a scanner, oldest first
+ * @param {string} root - Repository root
+ * @param {string} scanner - `slop` or `review`
+ * @returns {Object[]} Runs with `file` relative to the root
+ */
+function loadRuns(root, scanner) {
+  const runs = [];
+  const seen = new Set();
+  for (const dir of HISTORY_DIRS) {
+    let names = [];
+    try {
+      names = fs.readdirSync(path.join(root, dir, scanner));
+    } catch {
+      continue;
+    }
+    for (const name of names.filter(entry => entry.endsWith('.json') && !seen.has(entry))) {
+      seen.add(name);
+      try {
+        const entry = JSON.parse(fs.readFileSync(path.join(root, dir, scanner, name), 'utf8'));
+        if (entry && typeof entry.total === 'number') runs.push({ ...entry, file: `${dir}/${scanner}/${name}` });
+      } catch {
+        // Unreadable runs are left out of the series
+      }
+    }
+  }
+  return runs.sort((a, b) => String(a.timestamp).localeCompare(String(b.timestamp)));
+}
+
+/**
+ * Remove the oldest runs of a scanner past the limit
+ * @param {string} root - Repository root
+ * @param {string} scanner - Scanner name
+ * @param {number} maxRuns - Runs kept
+ * @returns {number} Runs removed
+ */
+function pruneRuns(root, scanner, maxRuns) {
+  const runs = loadRuns(root, scanner);
+  const excess = runs.slice(0, Math.max(0, runs.length - maxRuns));
+  for (const entry of excess) fs.rmSync(path.join(root, entry.file), { force: true });
+  return excess.length;
+}
+
+/**
+ * Record the counts of a scanner run
+ * @param {string} scanPath - Directory that was scanned
+ * @param {string} scanner - `slop` or `review`
+ * @param {Object[]} findings - The run's findings
+ * @param {Object} [options]
+ * @param {string} [options.mode] - What kind of scan it was (slop: thoroughness)
+ * @param {number} [options.baselined] - Findings the baseline suppressed (counted separately)
+ * @param {string} [options.timestamp] - Run time (default: now)
+ * @param {Function} [options.run] - Command runner, for tests
+ * @returns {{recorded: boolean, file?: string, run?: Object, pruned?: number, reason?: string, error?: string}}
+ */
+function recordRun(scanPath, scanner, findings, options = {}) {
+  if (!SCANNERS.includes(scanner)) return { recorded: false, error: `Unknown scanner "${scanner}". Use ${SCANNERS.join(' or ')}.` };
+  const context = gitContext(scanPath, options.run);
+  const settings = readSettings(context.root);
+  if (settings.error) return { recorded: false, error: settings.error };
+  if (!settings.enabled) return { recorded: false, reason: 'disabled' };
+
+  const timestamp = options.timestamp || new Date().toISOString();
+  const entry = {
+    version: RUN_VERSION,
+    scanner,
+    scope: context.scope,
+    mode: options.mode || null,
+    timestamp,
+    commit: context.commit,
+    branch: context.branch,
+    dirty: context.dirty,
+    ...countFindings(findings),
+    baselined: options.baselined || 0
+  };
+
+  const dir = path.join(context.root, HISTORY_DIRS[0], scanner);
+  fs.mkdirSync(dir, { recursive: true });
+  const stamp = timestamp.replace(/\.\d+Z$/, 'Z').replace(/:/g, '-');
+  const name = `${stamp}-${context.commit ? context.commit.slice(0, 7) : 'nogit'}`;
+  let file = path.join(dir, `${name}.json`);
+  for (let n = 2; fs.existsSync(file); n++) file = path.join(dir, `${name}-${n}.json`);
+  fs.writeFileSync(file, JSON.stringify(entry, null, 2) + '\n');
+
+  return {
+    recorded: true,
+    file: exec.relativePath(context.root, file),
+    run: entry,
+    pruned: pruneRuns(context.root, scanner, settings.maxRuns)
+  };
+}
+
+/**
+ * Least-squares slope of a series, per run
+ * @param {number[]} values - Oldest first
+ * @returns {number}
+ */
+function slope(values) {
+  const n = values.length;
+  if (n < 2) return 0;
+  const meanX = (n - 1) / 2;
+  const meanY = values.reduce((sum, value) => sum + value, 0) / n;
+  let numerator = 0;
+  let denominator = 0;
+  values.forEach((value, x) => {
+    numerator += (x - meanX) * (value - meanY);
+    denominator += (x - meanX) ** 2;
+  });
+  return numerator / denominator;
+}
+
+/**
+ * Trend of one series of runs
+ * The direction follows the least-squares fit, so one noisy run does not
+ * flip it; it is `flat` when the fit moves less than one finding overall.
+ * @param {Object[]} runs - Runs of one scanner, scope, and mode, oldest first
+ * @returns {Object} `{runs, first, last, change, percent, direction, slope, bySeverity, rising, falling}`;
+ *   `rising`/`falling` are the categories that moved most, as `{category, change}`
+ */
+function computeTrend(runs) {
+  const first = runs[0];
+  const last = runs[runs.length - 1];
+  const fit = slope(runs.map(entry => entry.total));
+  const direction = Math.abs(fit * (runs.length - 1)) < 1 ? 'flat' : (fit > 0 ? 'rising' : 'falling');
+
+  const bySeverity = {};
+  for (const severity of SEVERITIES) {
+    const change = ((last.bySeverity || {})[severity] || 0) - ((first.bySeverity || {})[severity] || 0);
+    if (change !== 0 || ((last.bySeverity || {})[severity] || 0) > 0) bySeverity[severity] = change;
+  }
+
+  const categories = new Set([...Object.keys(first.byCategory || {}), ...Object.keys(last.byCategory || {})]);
+  const changes = [...categories]
+    .map(category => ({ category, change: ((last.byCategory || {})[category] || 0) - ((first.byCategory || {})[category] || 0) }))
+    .filter(entry => entry.change !== 0);
+  const order = (a, b) => Math.abs(b.change) - Math.abs(a.change) || a.category.localeCompare(b.category);
+
+  return {
+    runs,
+    first,
+    last,
+    change: last.total - first.total,
+    percent: first.total > 0 ? Math.round(((last.total - first.total) / first.total) * 100) : null,
+    direction,
+    slope: Math.round(fit * 100) / 100,
+    bySeverity,
+    rising: changes.filter(entry => entry.change > 0).sort(order).slice(0, MOVERS),
+    falling: changes.filter(entry => entry.change < 0).sort(order).slice(0, MOVERS)
+  };
+}
+
+/**
+ * Trends of the last runs per scanner, scope, and mode
+ * @param {string} basePath - Any directory in the repository
+ * @param {Object} [options]
+ * @param {string[]} [options.scanners] - Scanners to report (default: both)
+ * @param {number} [options.last=10] - Runs per series
+ * @param {Function} [options.run] - Command runner, for tests
+ * @returns {{root: string, last: number, series: Object[]}} Each series: `{scanner, scope, mode, total, ...computeTrend}`;
+ *   `total` is every stored run of the series
+ */
+function trends(basePath, options = {}) {
+  const { root } = gitContext(basePath, options.run);
+  const last = options.last || DEFAULTS.last;
+  const series = [];
+  for (const scanner of options.scanners || SCANNERS) {
+    const groups = new Map();
+    for (const entry of loadRuns(root, scanner)) {
+      const key = `${entry.scope || '.'}\0${entry.mode || ''}`;
+      if (!groups.has(key)) groups.set(key, []);
+      groups.get(key).push(entry);
+    }
+    for (const runs of groups.values()) {
+      const recent = runs.slice(-last);
+      series.push({ scanner, scope: recent[0].scope || '.', mode: recent[0].mode || null, total: runs.length, ...computeTrend(recent) });
+    }
+  }
+  // The most recently scanned series first
+  series.sort((a, b) => SCANNERS.indexOf(a.scanner) - SCANNERS.indexOf(b.scanner) || String(b.last.timestamp).localeCompare(String(a.last.timestamp)));
+  return { root, last, series };
+}
+
+/**
+ * Sparkline of a series, e.g. "▇▆▆▄▂"
+ * @param {number[]} values - Oldest first
+ * @returns {string}
+ */
+function sparkline(values) {
+  const min = Math.min(...values);
+  const max = Math.max(...values);
+  return values.map(value => SPARK[max === min ? 0 : Math.round(((value - min) / (max - min)) * (SPARK.length - 1))]).join('');
+}
+
+/**
+ * Render trends as markdown
+ * @param {Object} report - Result of trends
+ * @returns {string}
+ */
+function renderTrends(report) {
+  const lines = [t('trends.title'), ''];
+  if (report.series.length === 0) {
+    lines.push(t('trends.empty'));
+    return lines.join('\n');
+  }
+
+  const signed = value => (value > 0 ? `+${value}` : String(value));
+  const day = timestamp => String(timestamp).slice(0, 10);
+  for (const series of report.series) {
+    const label = t(`trends.scanner.${series.scanner}`);
+    const qualifiers = [series.scope !== '.' ? series.scope : null, series.mode].filter(Boolean).join(', ');
+    lines.push(`### ${label}${qualifiers ? ` (${qualifiers})` : ''}`, '');
+    lines.push(t('trends.range', { count: series.runs.length, from: day(series.first.timestamp), to: day(series.last.timestamp) }));
+    lines.push(t(`trends.direction.${series.direction}`, {
+      from: series.first.total,
+      to: series.last.total,
+      change: signed(series.change),
+      percent: series.percent === null ? '' : `, ${signed(series.percent)}%`,
+      spark: sparkline(series.runs.map(entry => entry.total))
+    }));
+    const severities = Object.entries(series.bySeverity).map(([severity, change]) => `${severity} ${signed(change)}`);
+    if (severities.length > 0) lines.push(t('trends.bySeverity', { changes: severities.join(', ') }));
+    const movers = entries => entries.map(entry => `\`${entry.category}\` ${signed(entry.change)}`).join(', ');
+    if (series.rising.length > 0) lines.push(t('trends.rising', { categories: movers(series.rising) }));
+    if (series.falling.length > 0) lines.push(t('trends.falling', { categories: movers(series.falling) }));
+    lines.push('');
+
+    lines.push(t('trends.header'), '|------|--------|-------|----------|------|--------|-----|-----------|');
+    for (const entry of series.runs.slice().reverse()) {
+      const counts = entry.bySeverity || {};
+      const commit = entry.commit ? `\`${entry.commit.slice(0, 7)}\`${entry.dirty ? '*' : ''}` : '-';
+      lines.push(`| ${day(entry.timestamp)} | ${commit} | ${entry.total} | ${counts.critical || 0} | ${counts.high || 0} | ${counts.medium || 0} | ${counts.low || 0} | ${entry.baselined || 0} |`);
+    }
+    if (series.runs.some(entry => entry.dirty)) lines.push('', t('trends.dirty'));
+    lines.push('');
+  }
+  return lines.join('\n').trimEnd();
+}
+
+if (require.main === module) {
+  const argv = process.argv.slice(2);
+  const valueOf = flag => {
+    const index = argv.indexOf(flag);
+    return index === -1 ? undefined : argv[index + 1];
+  };
+  const scanner = valueOf('--scanner');
+  if (scanner && !SCANNERS.includes(scanner)) {
+    console.error(`Unknown scanner "${scanner}". Use ${SCANNERS.join(' or ')}.`);
+    process.exit(1);
+  }
+  const messages = require('../messages');
+  const locale = messages.configure(process.cwd());
+  if (locale.errors.length > 0) console.error(t('messages.errors', { errors: locale.errors.join('; ') }));
+
+  const report = trends(process.cwd(), { scanners: scanner ? [scanner] : undefined, last: Number(valueOf('--last')) || undefined });
+  console.log(argv.includes('--json') ? JSON.stringify(report, null, 2) : renderTrends(report));
+}
+
+module.exports = {
+  HISTORY_DIRS,
+  CONFIG_KEY,
+  DEFAULTS,
+  SCANNERS,
+  readSettings,
+  gitContext,
+  countFindings,
+  loadRuns,
+  pruneRuns,
+  recordRun,
+  computeTrend,
+  trends,
+  sparkline,
+  renderTrends
+};
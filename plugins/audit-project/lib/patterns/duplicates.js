@@ -0,0 +1,214 @@
+/**
+ * Duplicate Code Detection
+ *
+ * Token-based copy-paste detection across files. Sources are tokenized with
+ * comments and whitespace dropped, so blocks that differ only in formatting
+ * or comments still match. Literals and identifiers are kept as-is:
+ * normalizing them mostly matches unrelated data tables. Each file is fingerprinted with winnowing (Schleimer et al., 2003):
+ * hashes of every k-token window, keeping the minimum of each run of `window`
+ * hashes. Shared fingerprints seed candidate clones, which are then extended
+ * token by token to the longest identical run and reported when both copies
+ * span at least `minLines` lines.
+ *
+ * @module patterns/duplicates
+ * @author Avi Fenesh
+ * @license MIT
+ */
+
+const fs = require('fs');
+const path = require('path');
+const { detectLanguage } = require('./slop-analyzers');
+
+/**
+ * Defaults: tokens per fingerprint (k), winnowing window, and clone size
+ */
+const DEFAULTS = {
+  minLines: 8,
+  minTokens: 50,
+  kgram: 25,
+  window: 8,
+  // Fingerprints shared by more locations are boilerplate (license headers, imports)
+  maxOccurrences: 20
+};
+
+/**
+ * Token regex per comment style; string literals are single tokens, so comment markers inside them are ignored
+ */
+const TOKEN_REGEX = {
+  slash: /\/\/[^\n]*|\/\*[\s\S]*?\*\/|`(?:\\[\s\S]|[^\\`])*`|"(?:\\.|[^\\"\n])*"|'(?:\\.|[^\\'\n])*'|\b\d[\w.]*|[A-Za-z_$][\w$]*|\S/g,
+  hash: /#[^\n]*|"""[\s\S]*?"""|'''[\s\S]*?'''|"(?:\\.|[^\\"\n])*"|'(?:\\.|[^\\'\n])*'|\b\d[\w.]*|[A-Za-z_][\w]*|\S/g
+};
+
+/**
+ * Tokenize source into tokens with line numbers, skipping comments
+ * @param {string} content - Source text
+ * @param {string} language - Language key from detectLanguage (js, python, rust, go, java)
+ * @returns {Array<{value: string, line: number}>}
+ */
+function tokenize(content, language) {
+  const regex = new RegExp(TOKEN_REGEX[language === 'python' ? 'hash' : 'slash']);
+  const tokens = [];
+  let line = 1;
+  let counted = 0;
+  let match;
+
+  while ((match = regex.exec(content)) !== null) {
+    for (; counted < match.index; counted++) {
+      if (content.charCodeAt(counted) === 10) line++;
+    }
+    const text = match[0];
+    const isComment = text.startsWith('//') || text.startsWith('/*') || (language === 'python' && text[0] === '#');
+    if (!isComment) tokens.push({ value: text, line });
+  }
+  return tokens;
+}
+
+/**
+ * 32-bit hash of a token value
+ * @param {string} value
+ * @returns {number}
+ */
+function hashToken(value) {
+  let hash = 2166136261;
+  for (let i = 0; i < value.length; i++) {
+    hash = Math.imul(hash ^ value.charCodeAt(i), 16777619);
+  }
+  return hash >>> 0;
+}
+
+/**
+ * Winnowed fingerprints of a token sequence
+ * @param {Array<{value: string}>} tokens - Source tokens
+ * @param {number} kgram - Tokens per hashed window
+ * @param {number} window - Winnowing window (hashes per selection)
+ * @returns {Array<{hash: number, pos: number}>} Selected hashes with the token index where their k-gram starts
+ */
+function winnow(tokens, kgram, window) {
+  if (tokens.length < kgram) return [];
+
+  // Rolling polynomial hash over token hashes
+  const BASE = 31;
+  let power = 1;
+  for (let i = 1; i < kgram; i++) power = Math.imul(power, BASE) >>> 0;
+  const values = tokens.map(token => hashToken(token.value));
+  const hashes = new Array(tokens.length - kgram + 1);
+  let hash = 0;
+  for (let i = 0; i < kgram; i++) hash = (Math.imul(hash, BASE) + values[i]) >>> 0;
+  hashes[0] = hash;
+  for (let i = 1; i < hashes.length; i++) {
+    hash = (hash - Math.imul(values[i - 1], power)) >>> 0;
+    hash = (Math.imul(hash, BASE) + values[i + kgram - 1]) >>> 0;
+    hashes[i] = hash;
+  }
+
+  // Rightmost minimum per window, recorded once per position
+  const size = Math.min(window, hashes.length);
+  const selected = [];
+  let last = -1;
+  for (let start = 0; start + size <= hashes.length; start++) {
+    let min = start;
+    for (let i = start + 1; i < start + size; i++) {
+      if (hashes[i] <= hashes[min]) min = i;
+    }
+    if (min !== last) {
+      selected.push({ hash: hashes[min], pos: min });
+      last = min;
+    }
+  }
+  return selected;
+}
+
+/**
+ * Find duplicated blocks across (and within) files
+ *
+ * Only files of the same language are compared. Clones are reported once per
+ * pair of locations; a block copied three times yields three pairs.
+ *
+ * @param {string} repoPath - Repository root
+ * @param {string[]} files - Files to compare (relative to repoPath)
+ * @param {Object} [options]
+ * @param {number} [options.minLines=8] - Minimum lines each copy must span
+ * @param {number} [options.minTokens=50] - Minimum tokens in a clone
+ * @param {number} [options.kgram=25] - Tokens per fingerprint
+ * @param {number} [options.window=8] - Winnowing window
+ * @param {number} [options.maxOccurrences=20] - Ignore fingerprints shared by more locations
+ * @param {Function} [options.readFile] - File reader (for testing)
+ * @returns {Array<{a: Object, b: Object, lines: number, tokens: number}>} Clone pairs; a/b are {file, startLine, endLine}
+ */
+function findDuplicates(repoPath, files, options = {}) {
+  const settings = { ...DEFAULTS, ...options };
+  const kgram = Math.max(1, Math.min(settings.kgram, settings.minTokens));
+  const readFile = options.readFile || (file => fs.readFileSync(path.isAbsolute(file) ? file : path.join(repoPath, file), 'utf8'));
+
+  const sources = [];
+  const index = new Map();
+  for (const file of files) {
+    let content;
+    try {
+      content = readFile(file);
+    } catch {
+      continue;
+    }
+    const language = detectLanguage(file);
+    const tokens = tokenize(content, language);
+    const id = sources.length;
+    sources.push({ file, language, tokens });
+    for (const { hash, pos } of winnow(tokens, kgram, settings.window)) {
+      const key = `${language}:${hash}`;
+      if (!index.has(key)) index.set(key, []);
+      index.get(key).push({ id, pos });
+    }
+  }
+
+  const clones = [];
+  const seen = new Set();
+  for (const locations of index.values()) {
+    if (locations.length < 2 || locations.length > settings.maxOccurrences) continue;
+
+    for (let i = 0; i < locations.length; i++) {
+      for (let j = i + 1; j < locations.length; j++) {
+        const first = locations[i];
+        const second = locations[j];
+        const a = sources[first.id].tokens;
+        const b = sources[second.id].tokens;
+
+        // Extend to the longest identical run
+        let startA = first.pos;
+        let startB = second.pos;
+        while (startA > 0 && startB > 0 && a[startA - 1].value === b[startB - 1].value) {
+          startA--;
+          startB--;
+        }
+        let length = 0;
+        while (startA + length < a.length && startB + length < b.length &&
+               a[startA + length].value === b[startB + length].value) {
+          length++;
+        }
+        // Within one file, copies must not overlap
+        if (first.id === second.id && startA + length > startB) length = startB - startA;
+        if (length < settings.minTokens) continue;
+
+        const key = `${first.id}:${startA}:${second.id}:${startB}:${length}`;
+        if (seen.has(key)) continue;
+        seen.add(key);
+
+        const spanA = { file: sources[first.id].file, startLine: a[startA].line, endLine: a[startA + length - 1].line };
+        const spanB = { file: sources[second.id].file, startLine: b[startB].line, endLine: b[startB + length - 1].line };
+        const lines = Math.min(spanA.endLine - spanA.startLine, spanB.endLine - spanB.startLine) + 1;
+        if (lines < settings.minLines) continue;
+
+        clones.push({ a: spanA, b: spanB, lines, tokens: length });
+      }
+    }
+  }
+
+  return clones.sort((x, y) =>
+    x.b.file.localeCompare(y.b.file) || x.b.startLine - y.b.startLine || x.a.file.localeCompare(y.a.file) || x.a.startLine - y.a.startLine);
+}
+
+module.exports = {
+  DEFAULTS,
+  tokenize,
+  winnow,
+  findDuplicates
+};
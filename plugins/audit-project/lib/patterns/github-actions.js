@@ -0,0 +1,429 @@
+/**
+ * GitHub Actions Reporter
+ *
+ * Reports slop and review findings inside a GitHub Actions job: workflow
+ * commands (`::error file=...,line=...::message`) put annotations on the
+ * flagged lines of the run and the PR diff, and a markdown table is appended
+ * to the job summary (`$GITHUB_STEP_SUMMARY`). The reporter turns itself on
+ * when `GITHUB_ACTIONS=true`, so a CI step needs no extra flags.
+ *
+ * GitHub shows at most 10 annotations per level for a step, so the most
+ * severe findings are annotated and the summary lists the rest. Findings
+ * carry their CODEOWNERS; with `requestReviewers` the owners of flagged
+ * files are asked to review the pull request.
+ *
+ * Usage:
+ *   node github-actions.js review <review-queue.json> [--request-reviewers]
+ *
+ * @module patterns/github-actions
+ * @author Avi Fenesh
+ * @license MIT
+ */
+
+const fs = require('fs');
+const path = require('path');
+const { SEVERITIES } = require('./pipeline');
+const { t } = require('../messages');
+const codeowners = require('../utils/codeowners');
+const exec = require('../utils/exec');
+
+/**
+ * Annotations GitHub displays per level and step
+ */
+const MAX_ANNOTATIONS_PER_LEVEL = 10;
+
+/**
+ * Findings listed in the job summary
+ */
+const MAX_SUMMARY_ROWS = 100;
+
+/**
+ * Review severity => annotation level
+ */
+const REVIEW_LEVELS = {
+  critical: 'error',
+  high: 'error',
+  medium: 'warning',
+  low: 'notice'
+};
+
+/**
+ * Whether the process runs in a GitHub Actions job
+ * @param {Object} [env=process.env] - Environment
+ * @returns {boolean}
+ */
+function isGitHubActions(env = process.env) {
+  return env.GITHUB_ACTIONS === 'true';
+}
+
+/**
+ * Escape a workflow command message
+ * @param {string} value
+ * @returns {string}
+ */
+function escapeData(value) {
+  return String(value).replace(/%/g, '%25').replace(/\r/g, '%0D').replace(/\n/g, '%0A');
+}
+
+/**
+ * Escape a workflow command property value
+ * @param {string} value
+ * @returns {string}
+ */
+function escapeProperty(value) {
+  return escapeData(value).replace(/:/g, '%3A').replace(/,/g, '%2C');
+}
+
+/**
+ * Format one annotation workflow command
+ * @param {Object} annotation
+ * @param {string} annotation.level - error | warning | notice
+ * @param {string} annotation.message - Annotation text
+ * @param {string} [annotation.file] - Path relative to the workspace
+ * @param {number} [annotation.line] - 1-based line
+ * @param {number} [annotation.endLine] - Last line of a range
+ * @param {string} [annotation.title] - Annotation title
+ * @returns {string}
+ */
+function formatAnnotation(annotation) {
+  const properties = [];
+  if (annotation.file) properties.push(`file=${escapeProperty(annotation.file)}`);
+  if (annotation.file && annotation.line) properties.push(`line=${annotation.line}`);
+  if (annotation.file && annotation.endLine > annotation.line) properties.push(`endLine=${annotation.endLine}`);
+  if (annotation.title) properties.push(`title=${escapeProperty(annotation.title)}`);
+  return `::${annotation.level}${properties.length ? ` ${properties.join(',')}` : ''}::${escapeData(annotation.message)}`;
+}
+
+/**
+ * Finding path relative to the workspace (annotations need repository paths)
+ * @param {string} repoPath - Scanned root, which pipeline paths are relative to
+ * @param {string} file - Finding path
+ * @param {string} workspace - Checkout root
+ * @returns {string}
+ */
+function workspacePath(repoPath, file, workspace) {
+  return path.relative(workspace, path.resolve(repoPath, file)).replace(/\\/g, '/');
+}
+
+/**
+ * Annotation level of a slop finding under the severity gate
+ * Failing severities are errors, warned ones warnings, the rest notices.
+ * @param {string} severity - Finding severity
+ * @param {Object} [gate] - `{failOn, warnOn}` as passed to evaluateSeverityGate
+ * @returns {string}
+ */
+function slopLevel(severity, gate = {}) {
+  const rank = level => (level === 'none' ? -1 : SEVERITIES.indexOf(level));
+  const index = SEVERITIES.indexOf(severity);
+  if (index !== -1 && index <= rank(gate.failOn || 'critical')) return 'error';
+  if (index !== -1 && index <= rank(gate.warnOn || 'medium')) return 'warning';
+  return 'notice';
+}
+
+/**
+ * Keep the most severe annotations GitHub will display
+ * @param {Object[]} annotations - With `level`, in severity order
+ * @returns {{shown: Object[], hidden: number}}
+ */
+function capAnnotations(annotations) {
+  const counts = {};
+  const shown = [];
+  for (const annotation of annotations) {
+    counts[annotation.level] = (counts[annotation.level] || 0) + 1;
+    if (counts[annotation.level] <= MAX_ANNOTATIONS_PER_LEVEL) shown.push(annotation);
+  }
+  return { shown, hidden: annotations.length - shown.length };
+}
+
+/**
+ * Findings with their CODEOWNERS owners
+ * @param {string} repoPath - Root the finding paths are relative to
+ * @param {Object[]} findings
+ * @param {Object} options - `codeowners`: parsed CODEOWNERS, false for none (default: read from repoPath)
+ * @returns {Object[]}
+ */
+function withOwners(repoPath, findings, options) {
+  const owners = options.codeowners !== undefined ? options.codeowners || null : codeowners.readCodeowners(repoPath);
+  return codeowners.attachOwners(findings, owners);
+}
+
+/**
+ * Annotations for slop findings, most severe first
+ * Only the description is used: finding content may hold a secret.
+ * @param {string} repoPath - Scanned root
+ * @param {Array} findings - Pipeline findings
+ * @param {Object} [options]
+ * @param {Object} [options.gate] - `{failOn, warnOn}`
+ * @param {string} [options.workspace] - Checkout root (default: GITHUB_WORKSPACE, then cwd)
+ * @param {Object|false} [options.codeowners] - Parsed CODEOWNERS for findings without `owners` (default: read from repoPath)
+ * @returns {Object[]} Annotations with the finding's severity, pattern, and owners
+ */
+function slopAnnotations(repoPath, findings, options = {}) {
+  const workspace = options.workspace || process.env.GITHUB_WORKSPACE || process.cwd();
+  return withOwners(repoPath, findings.filter(finding => finding.file), options)
+    .map(finding => ({
+      level: slopLevel(finding.severity, options.gate),
+      severity: finding.severity || 'medium',
+      file: workspacePath(repoPath, finding.file, workspace),
+      line: finding.line || undefined,
+      title: `Slop: ${finding.patternName || 'unknown'}`,
+      message: finding.description || finding.patternName || 'Slop finding',
+      ...(finding.owners ? { owners: finding.owners } : {})
+    }))
+    .sort((a, b) => SEVERITIES.indexOf(a.severity) - SEVERITIES.indexOf(b.severity));
+}
+
+/**
+ * Annotations for review findings, most severe first
+ * False positives are skipped; suggestions are appended to the message.
+ * @param {string} repoPath - Root the review paths are relative to
+ * @param {Array} findings - Review findings ({file, line, endLine, severity, category|pass, description, suggestion})
+ * @param {Object} [options]
+ * @param {string} [options.workspace] - Checkout root (default: GITHUB_WORKSPACE, then cwd)
+ * @param {Object|false} [options.codeowners] - Parsed CODEOWNERS (default: read from repoPath)
+ * @returns {Object[]}
+ */
+function reviewAnnotations(repoPath, findings, options = {}) {
+  const workspace = options.workspace || process.env.GITHUB_WORKSPACE || process.cwd();
+  return withOwners(repoPath, findings.filter(finding => finding && finding.file && !finding.falsePositive), options)
+    .map(finding => ({
+      level: REVIEW_LEVELS[finding.severity] || 'warning',
+      severity: finding.severity || 'medium',
+      file: workspacePath(repoPath, finding.file, workspace),
+      line: finding.line || undefined,
+      endLine: finding.endLine || undefined,
+      title: `Review: ${finding.category || finding.pass || 'general'}`,
+      message: finding.suggestion
+        ? `${finding.description || 'Review finding'}\nSuggestion: ${finding.suggestion}`
+        : (finding.description || 'Review finding'),
+      ...(finding.owners ? { owners: finding.owners } : {})
+    }))
+    .sort((a, b) => SEVERITIES.indexOf(a.severity) - SEVERITIES.indexOf(b.severity));
+}
+
+/**
+ * Link to a line at the job's commit, or null outside a job
+ * @param {Object} annotation
+ * @param {Object} env - Environment
+ * @returns {string|null}
+ */
+function blobUrl(annotation, env) {
+  if (!env.GITHUB_SERVER_URL || !env.GITHUB_REPOSITORY || !env.GITHUB_SHA) return null;
+  return `${env.GITHUB_SERVER_URL}/${env.GITHUB_REPOSITORY}/blob/${env.GITHUB_SHA}/${annotation.file}${annotation.line ? `#L${annotation.line}` : ''}`;
+}
+
+/**
+ * Finding counts per owner, e.g. "@acme/web 3, @ana 1, unowned 2"
+ * @param {Object[]} findings - Findings or annotations with `owners`
+ * @returns {string}
+ */
+function describeOwners(findings) {
+  return codeowners.groupByOwner(findings)
+    .map(group => `${group.owner || t('owners.unowned')} ${group.findings.length}`)
+    .join(', ');
+}
+
+/**
+ * Render the job summary for a set of annotations
+ * @param {string} title - Summary heading
+ * @param {Object[]} annotations - Result of slopAnnotations/reviewAnnotations
+ * @param {Object} [options]
+ * @param {string[]} [options.notes] - Lines shown under the counts (gate result, baseline, diff scope)
+ * @param {number} [options.hidden=0] - Findings not annotated because of GitHub's limit
+ * @param {Object} [options.env=process.env] - Environment, for links
+ * @returns {string}
+ */
+function renderSummary(title, annotations, options = {}) {
+  const env = options.env || process.env;
+  const lines = [`## ${title}`, ''];
+  if (annotations.length === 0) {
+    lines.push(t('summary.noFindings'), ...(options.notes || []).flatMap(note => ['', note]), '');
+    return lines.join('\n');
+  }
+
+  const bySeverity = SEVERITIES
+    .map(severity => [severity, annotations.filter(annotation => annotation.severity === severity).length])
+    .filter(([, count]) => count > 0);
+  lines.push(t('summary.findings', { total: annotations.length, counts: bySeverity.map(([severity, count]) => `${severity} ${count}`).join(' | ') }));
+  for (const note of options.notes || []) lines.push('', note);
+  if (annotations.some(annotation => annotation.owners)) lines.push('', t('summary.byOwner', { owners: describeOwners(annotations) }));
+  lines.push('', t('summary.header'), '|----------|----------|------|-------------|');
+  for (const annotation of annotations.slice(0, MAX_SUMMARY_ROWS)) {
+    const location = `${annotation.file}${annotation.line ? `:${annotation.line}` : ''}`;
+    const url = blobUrl(annotation, env);
+    const message = annotation.message.split('\n')[0].replace(/\|/g, '\\|');
+    lines.push(`| ${annotation.severity} | ${url ? `[${location}](${url})` : `\`${location}\``} | \`${annotation.title.replace(/^\w+: /, '')}\` | ${message} |`);
+  }
+  if (annotations.length > MAX_SUMMARY_ROWS) lines.push('', t('summary.more', { count: annotations.length - MAX_SUMMARY_ROWS }));
+  if (options.hidden) lines.push('', t('summary.hidden', { count: options.hidden, limit: MAX_ANNOTATIONS_PER_LEVEL }));
+  lines.push('');
+  return lines.join('\n');
+}
+
+/**
+ * Emit annotations and append the job summary
+ * @param {string} title - Summary heading
+ * @param {Object[]} annotations - Result of slopAnnotations/reviewAnnotations
+ * @param {Object} [options]
+ * @param {string[]} [options.notes] - Lines shown under the counts
+ * @param {Object} [options.env=process.env] - Environment
+ * @param {Function} [options.write] - Receives each workflow command line (default: stderr, keeping stdout for JSON/SARIF)
+ * @returns {{annotated: number, hidden: number, summary: string|null}} summary is the written file, if any
+ */
+function reportToActions(title, annotations, options = {}) {
+  const env = options.env || process.env;
+  const write = options.write || (line => process.stderr.write(line + '\n'));
+  const { shown, hidden } = capAnnotations(annotations);
+  for (const annotation of shown) write(formatAnnotation(annotation));
+
+  let summary = null;
+  if (env.GITHUB_STEP_SUMMARY) {
+    fs.appendFileSync(env.GITHUB_STEP_SUMMARY, renderSummary(title, annotations, { notes: options.notes, hidden, env }) + '\n');
+    summary = env.GITHUB_STEP_SUMMARY;
+  }
+  return { annotated: shown.length, hidden, summary };
+}
+
+/**
+ * Report a slop pipeline result to the Actions job
+ * @param {string} repoPath - Scanned root
+ * @param {Object} result - runPipeline result
+ * @param {Object} [options]
+ * @param {Object} [options.gate] - `{failOn, warnOn}`; failing severities become errors
+ * @param {string} [options.workspace] - Checkout root
+ * @param {Object} [options.env=process.env] - Environment
+ * @param {Function} [options.write] - Workflow command sink
+ * @param {boolean} [options.requestReviewers=false] - Request review from the flagged files' CODEOWNERS
+ * @returns {{annotated: number, hidden: number, summary: string|null, reviewers?: Object}} `reviewers` from requestReviewers
+ */
+function reportSlop(repoPath, result, options = {}) {
+  const annotations = slopAnnotations(repoPath, result.findings || [], options);
+  const notes = [];
+  const failing = annotations.filter(annotation => annotation.level === 'error').length;
+  if (options.gate) {
+    notes.push(failing > 0
+      ? t('summary.failing', { count: failing, threshold: options.gate.failOn || 'critical' })
+      : t('summary.passing', { threshold: options.gate.failOn || 'critical' }));
+  }
+  if (result.diffScope) notes.push(t('summary.diffScope', { lines: result.diffScope.changedLines, files: result.diffScope.changedFiles, base: result.diffScope.base }));
+  if (result.baseline && !result.baseline.error) notes.push(t('summary.baseline', { suppressed: result.baseline.suppressed }));
+  const report = reportToActions(t('summary.slopTitle'), annotations, { ...options, notes });
+  if (options.requestReviewers) report.reviewers = requestReviewers(annotations, options);
+  return report;
+}
+
+/**
+ * Report review findings to the Actions job
+ * @param {string} repoPath - Root the review paths are relative to
+ * @param {Array} findings - Review findings
+ * @param {Object} [options]
+ * @param {string} [options.workspace] - Checkout root
+ * @param {Object} [options.env=process.env] - Environment
+ * @param {Function} [options.write] - Workflow command sink
+ * @param {boolean} [options.requestReviewers=false] - Request review from the flagged files' CODEOWNERS
+ * @returns {{annotated: number, hidden: number, summary: string|null, reviewers?: Object}}
+ */
+function reportReview(repoPath, findings, options = {}) {
+  const annotations = reviewAnnotations(repoPath, findings, options);
+  const report = reportToActions(t('summary.reviewTitle'), annotations, options);
+  if (options.requestReviewers) report.reviewers = requestReviewers(annotations, options);
+  return report;
+}
+
+/**
+ * Pull request the job runs for, from the event payload
+ * @param {Object} [env=process.env] - Environment
+ * @returns {{number: number, author: string|null}|null} null outside pull_request events
+ */
+function pullRequestContext(env = process.env) {
+  if (!env.GITHUB_EVENT_PATH) return null;
+  try {
+    const event = JSON.parse(fs.readFileSync(env.GITHUB_EVENT_PATH, 'utf8'));
+    const pr = event.pull_request;
+    return pr && pr.number ? { number: pr.number, author: pr.user ? pr.user.login : null } : null;
+  } catch {
+    return null;
+  }
+}
+
+/**
+ * Request review from the owners of the annotated findings
+ * Notices are left out; the pull request author cannot be requested.
+ * Runs `gh pr edit --add-reviewer`, so the job needs `GH_TOKEN` with
+ * pull request write access (team reviewers need a token that can read the org).
+ * @param {Object[]} annotations - Result of slopAnnotations/reviewAnnotations
+ * @param {Object} [options]
+ * @param {Object} [options.env=process.env] - Environment
+ * @param {Function} [options.run] - `(argv) => void`, throws on failure (default: lib/utils/exec `execSync`)
+ * @returns {{success: boolean, users?: string[], teams?: string[], error?: string}}
+ */
+function requestReviewers(annotations, options = {}) {
+  const env = options.env || process.env;
+  const pr = pullRequestContext(env);
+  if (!pr) return { success: false, error: 'Not running for a pull request' };
+
+  const flagged = annotations.filter(annotation => annotation.level !== 'notice');
+  const { users, teams } = codeowners.reviewersFor(flagged, { exclude: pr.author ? [pr.author] : [] });
+  if (users.length + teams.length === 0) return { success: true, users, teams };
+
+  const argv = ['gh', 'pr', 'edit', String(pr.number), '--add-reviewer', [...users, ...teams].join(',')];
+  if (env.GITHUB_REPOSITORY) argv.push('--repo', env.GITHUB_REPOSITORY);
+  const run = options.run || (command => exec.execSync(process.cwd(), command, { stdio: ['ignore', 'pipe', 'pipe'], timeout: 60000 }));
+  try {
+    run(argv);
+    return { success: true, users, teams };
+  } catch (error) {
+    const stderr = error.stderr ? String(error.stderr).trim() : '';
+    return { success: false, users, teams, error: stderr || error.message };
+  }
+}
+
+/**
+ * One-line outcome of requestReviewers for CI logs
+ * @param {Object} result - Result of requestReviewers
+ * @returns {string}
+ */
+function describeReviewers(result) {
+  if (!result.success) return t('reviewers.skipped', { error: result.error });
+  const names = [...result.users, ...result.teams].map(name => `@${name}`);
+  return names.length > 0 ? t('reviewers.requested', { reviewers: names.join(', ') }) : t('reviewers.none');
+}
+
+module.exports = {
+  MAX_ANNOTATIONS_PER_LEVEL,
+  isGitHubActions,
+  escapeData,
+  escapeProperty,
+  formatAnnotation,
+  slopLevel,
+  capAnnotations,
+  slopAnnotations,
+  reviewAnnotations,
+  renderSummary,
+  reportToActions,
+  reportSlop,
+  reportReview,
+  describeOwners,
+  pullRequestContext,
+  requestReviewers,
+  describeReviewers
+};
+
+// CLI usage
+if (require.main === module) {
+  const [kind, file, ...flags] = process.argv.slice(2);
+  if (kind !== 'review' || !file) {
+    console.error('Usage: node github-actions.js review <review-queue.json> [--request-reviewers]');
+    process.exit(1);
+  }
+  try {
+    const queue = JSON.parse(fs.readFileSync(file, 'utf8'));
+    const findings = Array.isArray(queue) ? queue : (queue.items || []);
+    const result = reportReview(process.cwd(), findings, { requestReviewers: flags.includes('--request-reviewers') });
+    console.error(`Annotated ${result.annotated} review findings${result.summary ? '; job summary written' : ''}`);
+    if (result.reviewers) console.error(describeReviewers(result.reviewers));
+  } catch (error) {
+    console.error(`Failed to report ${file}: ${error.message}`);
+    process.exit(1);
+  }
+}
@@ -8,6 +8,9 @@
  * @license MIT
  */
 
+const { isMigrationFile } = require('./migrations');
+const { compareVersions, parseVersion } = require('../platform/detect-runtimes');
+
 /**
  * Deep freeze an object for V8 optimization and immutability
  * @param {Object} obj - Object to freeze
@@ -64,6 +67,44 @@ const reviewPatterns = {
     ]
   },
 
+  /**
+   * Next.js App Router patterns (review alongside react)
+   */
+  nextjs: {
+    server_components: [
+      '"use client" at the top of pages/layouts that only need one interactive child',
+      '"use client" files importing server-only modules (db clients, fs, secrets)',
+      'Server components importing client-only modules (window, hooks, browser SDKs) without a client boundary',
+      'Non-serializable props (functions, class instances, Dates) passed from server to client components',
+      'Missing import "server-only" in modules that must never reach the client bundle'
+    ],
+    request_apis: [
+      'cookies(), headers() or draftMode() not awaited (async since Next.js 15)',
+      'params/searchParams used synchronously in pages and layouts (they are Promises in Next.js 15)',
+      'Reading cookies()/headers() in a layout and opting the whole subtree into dynamic rendering',
+      'Request APIs called inside cached functions (unstable_cache, "use cache")'
+    ],
+    caching: [
+      'fetch() assumed cached: Next.js 15 defaults to no-store, Next.js 14 to force-cache',
+      'Dynamic data without export const dynamic / revalidate route segment config',
+      'Mutations without revalidatePath/revalidateTag, leaving stale cached pages',
+      'Per-user data fetched with caching enabled (leaks between users)',
+      'Conflicting revalidate values between a layout and its pages'
+    ],
+    server_actions: [
+      'Server actions without authentication and authorization checks (they are public endpoints)',
+      'Server actions trusting FormData without validation',
+      'redirect() called inside try/catch (it throws to navigate)',
+      'Secrets or internal ids returned to the client from actions'
+    ],
+    routing: [
+      'Missing loading.tsx/error.tsx for slow or failure-prone segments',
+      'error.tsx without "use client"',
+      'Missing generateMetadata/metadata on public pages',
+      'Middleware doing database work on every request'
+    ]
+  },
+
   /**
    * Vue.js framework patterns
    */
@@ -81,7 +122,24 @@ const reviewPatterns = {
       'Forgetting .value when accessing refs',
       'Creating refs/reactive objects outside setup()',
       'Not destructuring reactive objects properly',
-      'Missing proper TypeScript types for refs'
+      'Missing proper TypeScript types for refs',
+      'Destructuring defineProps() or reactive() outside toRefs/props destructure (Vue < 3.5 loses reactivity)',
+      'Passing props.x to a composable instead of a getter or toRef (value is captured once)',
+      'Composables called conditionally, in loops, or after await in setup',
+      'Composables called outside setup() or <script setup> (no active instance)'
+    ],
+    watchers: [
+      'watch/watchEffect starting timers, listeners or requests without onCleanup/onWatcherCleanup',
+      'Watchers created asynchronously (after await, in setTimeout) and never stopped',
+      'Watching a reactive property directly instead of a getter (() => state.x)',
+      'watch with immediate: true duplicating onMounted logic',
+      'Event listeners added in onMounted without removal in onUnmounted'
+    ],
+    templates: [
+      'v-for without :key, or keyed by index on reorderable lists',
+      'Using v-html with user-controlled content (XSS)',
+      'Mutating v-model bound props instead of emitting update:modelValue',
+      'Missing defineEmits declaration for emitted events'
     ],
     performance: [
       'v-for without proper key binding',
@@ -92,6 +150,63 @@ const reviewPatterns = {
     ]
   },
 
+  /**
+   * Nuxt 3 framework patterns
+   */
+  nuxt: {
+    server_client_boundary: [
+      'Accessing window, document or localStorage during SSR (outside onMounted or import.meta.client)',
+      'Importing server/ utilities or secrets into components or pages',
+      'Private runtimeConfig keys read on the client (only runtimeConfig.public is exposed)',
+      'Hydration mismatches from Date.now(), Math.random() or locale-dependent output in templates',
+      'Browser-only libraries rendered without <ClientOnly> or a .client component suffix'
+    ],
+    data_fetching: [
+      'Using $fetch in setup instead of useFetch/useAsyncData (fetches twice: server and client)',
+      'useAsyncData without a unique key, or keys shared across pages',
+      'useFetch called inside event handlers instead of $fetch',
+      'Missing error and pending handling for useFetch/useAsyncData',
+      'Module-level state shared across requests instead of useState (cross-request leaks)'
+    ],
+    server_routes: [
+      'server/api handlers trusting getQuery/readBody without validation',
+      'Missing createError with statusCode for failures',
+      'Server middleware doing work for every request that belongs in a route'
+    ]
+  },
+
+  /**
+   * Svelte and SvelteKit framework patterns
+   */
+  svelte: {
+    stores: [
+      'store.subscribe() in components or modules without calling the returned unsubscribe',
+      'Manual subscribe where $store auto-subscription would clean up automatically',
+      'Writable stores exported from modules shared across SSR requests (cross-request leaks)',
+      'get(store) used in hot paths (subscribes and unsubscribes on every call)'
+    ],
+    reactivity: [
+      '$: reactive statements with side effects (fetches, logging, DOM writes) instead of explicit handlers',
+      '$: blocks depending on variables they also assign (ordering and loop surprises)',
+      'Mutating arrays/objects with push/splice without reassignment (Svelte 4 does not react)',
+      '$effect used to derive state that should be $derived (Svelte 5)',
+      'onMount returning nothing when it registers listeners or intervals'
+    ],
+    load_functions: [
+      'Client-only work (window, localStorage, document) in universal +page.js/+layout.js load',
+      'Secrets or private env ($env/static/private) imported outside +page.server/+layout.server',
+      'Using global fetch instead of the load event fetch (loses cookies and SSR dedupe)',
+      'Sequential awaits in load instead of parallel or streamed promises',
+      'Missing error()/redirect() from @sveltejs/kit for expected failures'
+    ],
+    form_actions: [
+      'Form actions reading request.formData() without validating or coercing fields',
+      'Missing fail() with status and returned values on validation errors',
+      'Actions without authorization checks (locals.user) for mutating operations',
+      'Forms without use:enhance losing progressive enhancement feedback'
+    ]
+  },
+
   /**
    * Angular framework patterns
    */
@@ -158,6 +273,59 @@ const reviewPatterns = {
     ]
   },
 
+  /**
+   * Ruby on Rails framework patterns
+   * Category names match repo-map `rails` roles (model -> models, ...)
+   */
+  rails: {
+    models: [
+      'N+1 queries (missing includes/preload/eager_load)',
+      'Callbacks with side effects (emails, API calls) instead of service objects',
+      'Missing database-level constraints behind validates uniqueness',
+      'default_scope hiding records unexpectedly',
+      'Missing dependent: option on has_many associations',
+      'Business logic spread across fat models without concerns',
+      'update_attribute/update_column skipping validations unintentionally',
+      'update_all/delete_all/insert_all skipping validations, callbacks and dependent: cleanup',
+      'N+1 from associations used in views or serializers without includes (check with Bullet or strict_loading)',
+      'after_save callbacks enqueuing jobs before commit (use after_commit)'
+    ],
+    controllers: [
+      'Strong parameters missing or using permit! on user input',
+      'params passed straight to create/update/new without require(...).permit(...)',
+      'Missing before_action authorization on member actions',
+      'Business logic in controllers instead of models/services',
+      'Rescuing StandardError broadly and hiding failures',
+      'Rendering or redirecting twice in one action (DoubleRenderError)',
+      'Skipping verify_authenticity_token without API-only justification'
+    ],
+    concerns: [
+      'Concern depending on host class internals without documenting them',
+      'Concern used as a grab bag of unrelated methods',
+      'Missing included do block for callbacks/associations in concerns'
+    ],
+    jobs: [
+      'Passing ActiveRecord objects instead of ids to perform_later',
+      'Jobs that are not idempotent under retry',
+      'Missing retry_on/discard_on for expected failures'
+    ],
+    security: [
+      'String interpolation in where/order/pluck (SQL injection)',
+      'html_safe or raw on user-controlled content (XSS)',
+      'Mass assignment of admin/role attributes',
+      'Secrets committed instead of credentials.yml.enc or ENV',
+      'send/constantize/public_send with user input'
+    ],
+    migrations: [
+      'Adding a column with a default on a large table without a backfill plan',
+      'Missing indexes on foreign keys',
+      'Data migrations referencing model classes that may change',
+      'Irreversible migrations without down or reversible blocks',
+      'remove_column/change_column in change without the type needed to reverse it',
+      'execute with raw SQL in change (not reversible)'
+    ]
+  },
+
   /**
    * FastAPI framework patterns
    */
@@ -167,20 +335,33 @@ const reviewPatterns = {
       'Using blocking I/O in async endpoints',
       'Not using await with async database calls',
       'Creating sync database sessions in async context',
-      'Missing async database driver'
+      'Missing async database driver',
+      'Blocking calls in async def endpoints (requests, time.sleep, sync ORM, open()) instead of def or run_in_threadpool',
+      'CPU-heavy work in async endpoints stalling the event loop'
     ],
     validation: [
       'Not using Pydantic models for request validation',
       'Missing response models',
       'Not validating query parameters',
       'Returning raw dictionaries instead of models',
-      'Missing field validators for complex validation'
+      'Missing field validators for complex validation',
+      'Endpoints without response_model or return annotation (leaks internal fields such as password hashes)',
+      'Mixing Pydantic v1 and v2 APIs (validator vs field_validator, .dict() vs .model_dump(), class Config vs model_config)',
+      'Importing from pydantic.v1 alongside v2 models in the same schema tree'
     ],
     dependencies: [
       'Dependency injection not used for database sessions',
       'Not using Depends() for authentication',
       'Missing dependency override for testing',
-      'Expensive operations in dependencies without caching'
+      'Expensive operations in dependencies without caching',
+      'Dependencies opening sessions/connections with return instead of yield plus finally cleanup',
+      'yield dependencies swallowing exceptions instead of re-raising after cleanup'
+    ],
+    background_tasks: [
+      'BackgroundTasks used for long or unbounded work (runs in the worker process, no retry or limit)',
+      'asyncio.create_task without keeping a reference or handling exceptions',
+      'Background tasks reusing the request-scoped database session after the response',
+      'No queue (Celery, arq, RQ) for work that must survive restarts'
     ]
   },
 
@@ -226,20 +407,30 @@ const reviewPatterns = {
    */
   go: {
     concurrency: [
-      'Goroutines without context cancellation',
-      'Mutex not unlocked in defer',
-      'Channels not closed properly',
-      'Race conditions in tests',
-      'WaitGroup.Add() called in wrong goroutine',
-      'Goroutine leaks (no cleanup mechanism)',
+      'Goroutines launched without a ctx (or done channel) to cancel them; loops not selecting on ctx.Done()',
+      'sync.Mutex or sync.WaitGroup copied by value (value receivers, struct copies, range values; go vet copylocks)',
+      'Mutex not unlocked in defer, or unlocked on only some return paths',
+      'Channel sends or receives that can block forever: no select with ctx.Done() or a timeout',
+      'Channels closed by the receiver, closed twice, or sent to after close (panics)',
+      'sync.WaitGroup.Add called inside the goroutine instead of before the go statement (Wait can return early)',
+      'Loop variables captured by goroutine closures (before Go 1.22)',
+      'Goroutine leaks: workers with no exit path when the consumer stops reading',
+      'Maps read and written from several goroutines without a lock or sync.Map',
+      'time.After in a hot select loop (allocates a timer per iteration; use time.NewTimer and Reset)',
+      'Race conditions in tests (run with -race)',
       'Using global variables in concurrent code'
     ],
     error_handling: [
       'Ignored errors (using _ for error returns)',
-      'Error strings start with capital letter (go convention)',
-      'Not using fmt.Errorf with %w for error wrapping',
-      'Panic instead of returning error',
+      'Comparing errors with == instead of errors.Is (breaks once the error is wrapped)',
+      'Type assertions on errors (err.(*MyErr)) instead of errors.As',
+      'Not using fmt.Errorf with %w for error wrapping, or %w used where callers should not match the cause',
+      'Error strings start with capital letter or end with punctuation (go convention)',
+      'Returning a typed nil pointer as error (non-nil interface)',
+      'defer inside loops (resources held until the function returns; wrap the body in a func)',
+      'defer f.Close() on writable files without checking the Close error',
       'Not checking errors from Close() or Flush()',
+      'Panic instead of returning error',
       'Using panic/recover for control flow'
     ],
     performance: [
@@ -260,6 +451,183 @@ const reviewPatterns = {
     ]
   },
 
+  /**
+   * Java Spring Boot patterns
+   */
+  spring: {
+    dependency_injection: [
+      'Field injection with @Autowired instead of constructor injection (hides dependencies, blocks final fields)',
+      'Circular dependencies worked around with @Lazy or setter injection',
+      'Beans holding per-request state in singleton scope',
+      'Multiple constructors without @Autowired marking the injection point'
+    ],
+    transactions: [
+      '@Transactional on private or final methods (proxies ignore them)',
+      'Self-invocation of @Transactional methods from the same class (bypasses the proxy)',
+      'Checked exceptions not rolling back without rollbackFor',
+      'Read paths missing @Transactional(readOnly = true)',
+      'Remote calls or long work inside a transaction holding connections'
+    ],
+    data_access: [
+      'N+1 queries from lazy @OneToMany/@ManyToOne relations accessed in loops (use JOIN FETCH or @EntityGraph)',
+      'FetchType.EAGER on collections loading whole object graphs',
+      'LazyInitializationException patched with open-in-view instead of fetching explicitly',
+      'findAll() without paging on large tables',
+      'Native queries built with string concatenation (SQL injection)'
+    ],
+    web: [
+      '@RequestBody parameters without @Valid/@Validated (bean validation never runs)',
+      'Entities returned from controllers instead of DTOs (leaks fields, triggers lazy loads)',
+      '@CrossOrigin("*") or allowedOrigins("*") with credentials on authenticated endpoints',
+      'Exceptions handled per-controller instead of @ControllerAdvice',
+      'Missing @ResponseStatus or ResponseEntity status codes for errors'
+    ],
+    security: [
+      'permitAll() on broad path patterns such as /api/**',
+      'CSRF disabled for browser-facing session endpoints',
+      'Actuator endpoints exposed without authentication',
+      'Secrets in application.properties instead of environment or a vault'
+    ]
+  },
+
+  /**
+   * Cross-language security patterns, applied regardless of framework
+   * Each pattern ends with its CWE and OWASP Top 10 (2021) IDs; see getSecurityTags
+   */
+  security: {
+    injection: [
+      'SQL built with string concatenation, interpolation or f-strings instead of bound parameters (CWE-89, OWASP A03:2021)',
+      'Shell commands built from user input (exec, system, shell=True, child_process.exec) (CWE-78, OWASP A03:2021)',
+      'User input rendered as HTML without escaping (innerHTML, dangerouslySetInnerHTML, v-html, |safe) (CWE-79, OWASP A03:2021)',
+      'User input evaluated as code (eval, new Function, exec, template compilation) (CWE-94, OWASP A03:2021)',
+      'NoSQL queries accepting raw objects from request bodies ($where, $ne operators) (CWE-943, OWASP A03:2021)',
+      'LDAP or XPath queries built from user input (CWE-90, OWASP A03:2021)'
+    ],
+    broken_auth: [
+      'Endpoints missing authentication or authorization checks (CWE-862, OWASP A01:2021)',
+      'Object ids from the request used without an ownership check (IDOR) (CWE-639, OWASP A01:2021)',
+      'Passwords hashed with MD5/SHA-1/unsalted SHA-256 instead of bcrypt, scrypt or argon2 (CWE-916, OWASP A02:2021)',
+      'JWTs decoded without signature verification or accepting alg none (CWE-347, OWASP A07:2021)',
+      'Secrets or tokens compared with == instead of a constant-time comparison (CWE-208, OWASP A07:2021)',
+      'Session ids not rotated on login, or cookies missing HttpOnly/Secure/SameSite (CWE-384, OWASP A07:2021)',
+      'No rate limiting or lockout on login and password reset (CWE-307, OWASP A07:2021)'
+    ],
+    ssrf: [
+      'Server-side requests to URLs taken from user input without an allowlist (CWE-918, OWASP A10:2021)',
+      'URL allowlists checked before redirects or DNS resolution (rebinding, 169.254.169.254 metadata access) (CWE-918, OWASP A10:2021)',
+      'Webhook and image-fetch features reaching internal hosts (CWE-918, OWASP A10:2021)'
+    ],
+    insecure_deserialization: [
+      'Deserializing untrusted data with pickle, marshal, yaml.load, Java ObjectInputStream or PHP unserialize (CWE-502, OWASP A08:2021)',
+      'Merging parsed JSON into objects without guarding __proto__/constructor (prototype pollution) (CWE-1321, OWASP A08:2021)',
+      'XML parsers with external entities enabled (XXE) (CWE-611, OWASP A05:2021)'
+    ],
+    path_traversal: [
+      'File paths joined from user input without resolving and checking they stay under a base directory (CWE-22, OWASP A01:2021)',
+      'Archive extraction without validating entry names (Zip Slip) (CWE-22, OWASP A01:2021)',
+      'Uploaded files saved under client-supplied names or served from the web root (CWE-434, OWASP A04:2021)'
+    ],
+    misconfiguration: [
+      'Debug mode, stack traces or verbose errors enabled in production (CWE-209, OWASP A05:2021)',
+      'CORS allowing any origin with credentials (CWE-942, OWASP A05:2021)',
+      'TLS certificate verification disabled (verify=False, rejectUnauthorized: false) (CWE-295, OWASP A02:2021)',
+      'Hardcoded credentials or keys in source (CWE-798, OWASP A07:2021)',
+      'Open redirects to URLs taken from query parameters (CWE-601, OWASP A01:2021)'
+    ]
+  },
+
+  /**
+   * Accessibility patterns for component frameworks (JSX, Vue and Svelte templates)
+   * Activated by getPatternSetsForFrameworks when a frontend framework is detected
+   */
+  accessibility: {
+    images_media: [
+      '<img> without alt, or alt repeating the file name ("image.png", "photo")',
+      'Decorative images missing alt="" so screen readers announce them',
+      'Icon-only buttons and links without aria-label or visually hidden text',
+      'Video or audio without captions or transcripts'
+    ],
+    interactive_elements: [
+      'onClick/@click/on:click on div or span without role, tabindex and key handlers (use <button>)',
+      'Mouse-only handlers (onMouseOver, hover menus) with no keyboard equivalent',
+      'Positive tabindex values reordering focus',
+      'aria-hidden="true" on focusable elements',
+      'Custom controls missing role and aria-expanded/aria-pressed/aria-checked state'
+    ],
+    forms: [
+      'Inputs without an associated <label>, aria-label or aria-labelledby',
+      'Placeholder used as the only label',
+      'Validation errors shown by color only or not linked with aria-describedby',
+      'Required fields not marked with required or aria-required'
+    ],
+    links: [
+      'Low-information link text ("click here", "read more", "here") without context',
+      'Links used as buttons (href="#" with a click handler)',
+      'Links opening new windows without warning the user'
+    ],
+    focus_management: [
+      'Modals and dialogs without a focus trap, or not using <dialog>/role="dialog" with aria-modal',
+      'Focus not moved into a modal on open or restored to the trigger on close',
+      'Escape key not closing modals and popovers',
+      'Focus outlines removed (outline: none) without a visible replacement',
+      'Route changes in SPAs not announced or not moving focus to the new content'
+    ]
+  },
+
+  /**
+   * Database migration safety patterns, applied when a change touches migrations
+   * Dropped columns are cross-checked with findDroppedColumnReferences (patterns/migrations)
+   */
+  migrations: {
+    locking: [
+      'CREATE INDEX without CONCURRENTLY on Postgres tables with traffic (blocks writes; Rails: algorithm: :concurrently)',
+      'CREATE INDEX CONCURRENTLY inside a transaction (fails; Rails needs disable_ddl_transaction!)',
+      'Foreign keys or CHECK constraints added without NOT VALID then VALIDATE CONSTRAINT',
+      'Changing a column type that rewrites the table (int to bigint, varchar shrink)',
+      'Renaming tables or columns that running code still uses'
+    ],
+    columns: [
+      'Dropping a column that code still references (ignore it in the model first, drop in a later deploy)',
+      'Adding a NOT NULL column without a default to a large table',
+      'Setting NOT NULL on an existing column without backfilling first (full table scan under lock)',
+      'Adding a column with a volatile default (now(), random()) that rewrites every row',
+      'Backfills in the same migration as schema changes, in one transaction'
+    ],
+    reversibility: [
+      'down/downgrade that raises or is empty for a destructive change',
+      'Data deleted or transformed with no way to restore it on rollback',
+      'Migrations depending on application models that may change later',
+      'Edited migrations that have already run in other environments'
+    ]
+  },
+
+  /**
+   * REST and GraphQL API design patterns, applied to backend frameworks
+   * Routes, resolvers and exported-symbol diffs are checked by patterns/api-design
+   */
+  api_design: {
+    rest: [
+      'Verbs in resource paths (/getUsers, /createOrder) instead of HTTP methods',
+      'Singular collection names (/user/{id}) mixed with plural ones',
+      'List endpoints without pagination (limit/cursor) or a maximum page size',
+      'Non-idempotent GET handlers that create or change state',
+      'Wrong status codes (200 with an error body, 500 for validation errors, 200 instead of 201 on create)',
+      'Inconsistent error response shapes across endpoints'
+    ],
+    graphql: [
+      'Field resolvers querying once per parent object without a DataLoader (N+1)',
+      'Lists without connection/cursor pagination or a maximum page size',
+      'No query depth or complexity limits on public schemas',
+      'Nullable-to-non-null or argument changes that break existing clients'
+    ],
+    compatibility: [
+      'Exported functions, types or routes removed or renamed without a deprecation period',
+      'Required parameters added to public functions or request bodies',
+      'Response fields removed or retyped without versioning the endpoint',
+      'Enum values removed from public schemas'
+    ]
+  },
+
   /**
    * Express.js (Node.js) patterns
    */
@@ -291,8 +659,55 @@ const reviewPatterns = {
   }
 };
 
+/**
+ * Patterns that only apply to some major versions
+ * `versions` is a comparator range (`>=18`, `>=3.1 <4.1`, `<2 || >=3`) checked
+ * against the detected version of `on` (defaults to the framework itself);
+ * versions come from lockfiles via lib/platform/detect-framework-versions
+ */
+const versionedPatterns = {
+  react: {
+    concurrent_rendering: [
+      { versions: '<18', pattern: 'Relying on setState batching outside event handlers (React 17 only batches inside handlers)' },
+      { versions: '>=18', pattern: 'ReactDOM.render or hydrate instead of createRoot/hydrateRoot (legacy root disables concurrent features)' },
+      { versions: '>=18', pattern: 'Effects without cleanup that break when StrictMode mounts components twice in development' },
+      { versions: '>=18', pattern: 'Expensive non-urgent updates not wrapped in startTransition or useDeferredValue' },
+      { versions: '>=18', pattern: 'Reading external mutable stores without useSyncExternalStore (tearing under concurrent rendering)' }
+    ],
+    react_19: [
+      { versions: '>=19', pattern: 'forwardRef wrappers where ref can be a regular prop (React 19)' },
+      { versions: '>=19', pattern: 'propTypes or defaultProps on function components (removed in React 19)' },
+      { versions: '>=19', pattern: 'Hand-rolled pending/error state for form submissions instead of useActionState/useFormStatus' },
+      { versions: '<19', pattern: 'Using use(), useActionState or ref-as-prop APIs that need React 19' }
+    ]
+  },
+  django: {
+    async_views: [
+      { versions: '<3.1', pattern: 'async def views (not supported before Django 3.1)' },
+      { versions: '>=3.1 <4.1', pattern: 'ORM queries in async views not wrapped in sync_to_async (no async ORM before Django 4.1)' },
+      { versions: '>=4.1', pattern: 'Sync ORM calls in async views instead of the async methods (aget, acreate, afirst, async for)' },
+      { versions: '>=5.0', pattern: 'Python-side defaults or save() overrides where Field.db_default or GeneratedField fits (Django 5)' }
+    ]
+  },
+  fastapi: {
+    pydantic_version: [
+      { on: 'pydantic', versions: '>=2', pattern: 'Pydantic v1 APIs on v2 (.dict(), .json(), parse_obj, @validator, class Config): use model_dump, model_validate, @field_validator, model_config' },
+      { on: 'pydantic', versions: '<2', pattern: 'Pydantic v2-only APIs (model_dump, field_validator, ConfigDict) on a v1 install' },
+      { on: 'pydantic', versions: '>=2', pattern: 'Optional[X] fields without a default (required in v2, optional in v1)' }
+    ]
+  },
+  vue: {
+    version_specific: [
+      { versions: '<3', pattern: 'New reactive keys added without Vue.set/this.$set (Vue 2 reactivity caveats)' },
+      { versions: '>=3 <3.5', pattern: 'Destructuring defineProps() without toRefs (loses reactivity before Vue 3.5)' },
+      { versions: '>=3.5', pattern: 'toRefs(props) or withDefaults boilerplate where reactive props destructure works (Vue 3.5+)' }
+    ]
+  }
+};
+
 // Freeze the patterns object for V8 optimization
 deepFreeze(reviewPatterns);
+deepFreeze(versionedPatterns);
 
 // ============================================================================
 // Pre-indexed Maps for O(1) lookup performance (#18)
@@ -379,6 +794,117 @@ function ensureIndexesBuilt() {
   _indexesBuilt = true;
 }
 
+/**
+ * Frameworks that render UI and get accessibility patterns
+ */
+const FRONTEND_FRAMEWORKS = new Set(['react', 'nextjs', 'vue', 'nuxt', 'svelte', 'angular']);
+
+/**
+ * Frameworks that serve HTTP APIs and get API design patterns
+ */
+const BACKEND_FRAMEWORKS = new Set(['express', 'fastapi', 'django', 'rails', 'spring']);
+
+/**
+ * Pattern sets selected by project traits rather than by framework name
+ */
+const CROSS_CUTTING_SETS = new Set(['accessibility', 'api_design', 'migrations', 'security']);
+
+/**
+ * Pattern sets to apply for a project's detected frameworks
+ *
+ * Detected frameworks with patterns come first, then `accessibility` when a
+ * frontend framework is present, `api_design` when a backend framework is,
+ * `migrations` when the reviewed files include a database migration, and the
+ * cross-language `security` set.
+ *
+ * @param {string|string[]} frameworks - Detected framework names
+ * @param {Object} [options]
+ * @param {string[]} [options.files] - Files under review (changed files for a diff)
+ * @returns {string[]} Keys of reviewPatterns, e.g. ['react', 'accessibility', 'security']
+ */
+function getPatternSetsForFrameworks(frameworks, options = {}) {
+  const detected = [].concat(frameworks || [])
+    .filter(name => typeof name === 'string')
+    .map(name => name.toLowerCase());
+  const sets = detected.filter(name => _frameworksSet.has(name) && !CROSS_CUTTING_SETS.has(name));
+  if (detected.some(name => FRONTEND_FRAMEWORKS.has(name))) sets.push('accessibility');
+  if (detected.some(name => BACKEND_FRAMEWORKS.has(name))) sets.push('api_design');
+  if ((options.files || []).some(isMigrationFile)) sets.push('migrations');
+  sets.push('security');
+  return Array.from(new Set(sets));
+}
+
+/**
+ * Check whether a version satisfies a comparator range
+ * Comparators (`>=`, `>`, `<=`, `<`, `=`) are ANDed within an alternative and
+ * alternatives are ORed with `||`; missing components compare as 0.
+ * @param {string} version - Version, e.g. '18.2.0'
+ * @param {string} range - Range, e.g. '>=3.1 <4.1'
+ * @returns {boolean}
+ */
+function satisfiesRange(version, range) {
+  if (!parseVersion(version)) return false;
+  return String(range).split('||').some(alternative => alternative.trim().split(/\s+/).filter(Boolean).every(token => {
+    const match = token.match(/^(>=|>|<=|<|=)?v?(\d+(?:\.\d+)*)$/);
+    if (!match) return false;
+    const diff = compareVersions(version, match[2]);
+    switch (match[1]) {
+      case '>=': return diff >= 0;
+      case '>': return diff > 0;
+      case '<=': return diff <= 0;
+      case '<': return diff < 0;
+      default: return diff === 0;
+    }
+  }));
+}
+
+/**
+ * Review patterns for a framework, with version-specific patterns resolved
+ *
+ * Version-specific patterns are kept when the detected version satisfies
+ * their range and dropped when it does not. When the version is unknown they
+ * are kept with the range appended, so reviewers can check it themselves.
+ *
+ * @param {string} framework - Framework name
+ * @param {Object<string, string>} [versions] - Detected versions by framework name (e.g. {react: '18.2.0', pydantic: '2.6.1'})
+ * @returns {Object<string, string[]>|null} Category -> patterns, or null for unknown frameworks
+ */
+function getPatternsForFrameworkVersion(framework, versions = {}) {
+  const name = framework.toLowerCase();
+  const base = reviewPatterns[name];
+  const versioned = versionedPatterns[name];
+  if (!base && !versioned) return null;
+
+  const result = {};
+  for (const [category, patterns] of Object.entries(base || {})) result[category] = patterns.slice();
+  for (const [category, entries] of Object.entries(versioned || {})) {
+    const selected = [];
+    for (const entry of entries) {
+      const target = entry.on || name;
+      const version = versions[target];
+      if (!version) {
+        selected.push(`${entry.pattern} [${target} ${entry.versions}]`);
+      } else if (satisfiesRange(version, entry.versions)) {
+        selected.push(entry.pattern);
+      }
+    }
+    if (selected.length > 0) result[category] = (result[category] || []).concat(selected);
+  }
+  return result;
+}
+
+/**
+ * Extract CWE and OWASP Top 10 IDs from a security pattern or finding text
+ * @param {string} text - Pattern or finding description
+ * @returns {{cwe: string[], owasp: string[]}} e.g. {cwe: ['CWE-89'], owasp: ['A03:2021']}
+ */
+function getSecurityTags(text) {
+  const source = String(text || '');
+  const cwe = Array.from(new Set((source.match(/\bCWE-\d+\b/gi) || []).map(id => id.toUpperCase())));
+  const owasp = Array.from(new Set((source.match(/\bA(?:0[1-9]|10):20\d\d\b/g) || [])));
+  return { cwe, owasp };
+}
+
 /**
  * Get review patterns for a detected framework (O(1) lookup)
  * @param {string} framework - Framework name
@@ -539,6 +1065,7 @@ function getFrameworksWithCategory(category) {
 
 module.exports = {
   reviewPatterns,
+  versionedPatterns,
   // Pre-indexed lookup functions (O(1) performance)
   getPatternsForFramework,
   getPatternsByCategory,
@@ -554,5 +1081,9 @@ module.exports = {
   getTotalPatternCount,
   // Search functions
   searchPatterns,
-  getFrameworksWithCategory
+  getFrameworksWithCategory,
+  getPatternSetsForFrameworks,
+  getPatternsForFrameworkVersion,
+  satisfiesRange,
+  getSecurityTags
 };
@@ -102,7 +102,8 @@ function createSecretPattern(pattern, description, additionalExcludes = []) {
     severity: 'critical',
     autoFix: 'flag',
     language: null,
-    description
+    description,
+    category: 'secrets'
   };
 }
 
@@ -113,6 +114,32 @@ function createSecretPattern(pattern, description, additionalExcludes = []) {
  * - add_logging: Add proper error logging
  * - flag: Mark for manual review
  * - none: Report only, no auto-fix
+ *
+ * `ast` declares a syntax-tree matcher (see slop-ast.js) used instead of
+ * `pattern` for JavaScript/TypeScript when a parser is installed.
+ *
+ * `enforcedBy` lists linter rules (`tool:rule`) or formatters (`tool`) that
+ * already catch a pattern; a nested array means all of them are required.
+ * The pipeline skips these findings when the project enables them.
+ *
+ * `skipInlineTests` ignores matches inside Rust `#[cfg(test)]` items, where
+ * unit tests live next to the code they test.
+ *
+ * `multiline` runs the pattern over the whole file instead of line by line,
+ * for constructs such as signatures spanning lines; findings are reported at
+ * the line where the match starts.
+ *
+ * `minOccurrences` reports a file once, at its first match, when the pattern
+ * matches at least that many lines.
+ *
+ * `include` limits a pattern to files matching these globs. Test files are
+ * not scanned by default, so patterns aimed at tests (e.g. sleeps in Go
+ * tests) list them here and the pipeline adds matching test files.
+ *
+ * `category: 'secrets'` marks credential patterns: one secret finding is
+ * reported per line, and `redactSecrets` masks the value in finding content.
+ * `requiresEntropyCheck` drops matches whose value (first capture group, or
+ * the match without quotes) has less than `entropyThreshold` bits per char.
  */
 
 const slopPatterns = {
@@ -134,7 +161,9 @@ const slopPatterns = {
     severity: 'medium',
     autoFix: 'remove',
     language: 'javascript',
-    description: 'Console.log statements left in production code (excludes scripts, E2E, seeds)'
+    ast: { matcher: 'member_call', object: 'console', properties: ['log', 'debug', 'info', 'warn'] },
+    description: 'Console.log statements left in production code (excludes scripts, E2E, seeds)',
+    enforcedBy: ['eslint:no-console', 'biome:suspicious/noConsole', 'biome:suspicious/noConsoleLog']
   },
 
   /**
@@ -146,19 +175,71 @@ const slopPatterns = {
     severity: 'medium',
     autoFix: 'remove',
     language: 'python',
-    description: 'Debug print/breakpoint statements in production'
+    description: 'Debug print/breakpoint statements in production',
+    enforcedBy: [['ruff:T20', 'ruff:T10'], ['flake8:T20', 'flake8:T10']]
   },
 
   /**
-   * Rust debugging macros
+   * Rust dbg! macro
+   * Removal is only applied to standalone `dbg!(...);` statements
    */
   rust_debugging: {
-    pattern: /(println!|dbg!|eprintln!)\(/,
-    exclude: ['*_test.rs', '*_tests.rs'],
+    pattern: /\bdbg!\s*\(/,
+    exclude: ['*_test.rs', '*_tests.rs', '**/tests/**'],
     severity: 'medium',
     autoFix: 'remove',
     language: 'rust',
-    description: 'Debug print macros in production code'
+    skipInlineTests: true,
+    description: 'dbg! macro left in production code',
+    enforcedBy: ['clippy:dbg_macro']
+  },
+
+  /**
+   * Rust print macros used for debugging
+   * Excludes binaries, examples and build scripts, where printing is output
+   */
+  rust_print_debugging: {
+    pattern: /\be?print(?:ln)?!\s*\(/,
+    exclude: [
+      '*_test.rs', '*_tests.rs', '**/tests/**', '**/benches/**',
+      'src/main.rs', '**/src/main.rs', 'src/bin/**', '**/src/bin/**',
+      'examples/**', '**/examples/**', 'build.rs', '**/build.rs'
+    ],
+    severity: 'medium',
+    autoFix: 'flag',
+    language: 'rust',
+    skipInlineTests: true,
+    description: 'println!/eprintln! debugging in Rust library code',
+    enforcedBy: [['clippy:print_stdout', 'clippy:print_stderr']]
+  },
+
+  /**
+   * Rust unwrap()/expect() outside tests
+   */
+  rust_unwrap: {
+    pattern: /\.(?:unwrap|expect)\s*\(/,
+    exclude: ['*_test.rs', '*_tests.rs', '**/tests/**', '**/benches/**', 'examples/**', '**/examples/**', 'build.rs', '**/build.rs'],
+    severity: 'medium',
+    autoFix: 'flag',
+    language: 'rust',
+    skipInlineTests: true,
+    description: 'unwrap()/expect() in Rust production code (propagate with ? or handle the error)',
+    enforcedBy: [['clippy:unwrap_used', 'clippy:expect_used']]
+  },
+
+  /**
+   * Rust #[allow(dead_code)] accumulating in one file
+   * A single allow is often deliberate; several mean unused code is piling up
+   */
+  rust_allow_dead_code: {
+    pattern: /#!?\[allow\([^)]*\bdead_code\b/,
+    exclude: [],
+    severity: 'low',
+    autoFix: 'flag',
+    language: 'rust',
+    skipInlineTests: true,
+    description: '#[allow(dead_code)] accumulating in one file',
+    minOccurrences: 3
   },
 
   /**
@@ -171,6 +252,7 @@ const slopPatterns = {
     autoFix: 'flag',
     language: null, // All languages
     description: 'TODO/FIXME comments older than 90 days',
+    enforcedBy: ['eslint:no-warning-comments', 'ruff:FIX', 'golangci-lint:godox'],
     requiresAgeCheck: true,
     ageThreshold: 90 // days
   },
@@ -185,6 +267,7 @@ const slopPatterns = {
     autoFix: 'remove',
     language: null,
     description: 'Large blocks of commented-out code',
+    enforcedBy: ['ruff:ERA001'],
     minConsecutiveLines: 5
   },
 
@@ -232,6 +315,7 @@ const slopPatterns = {
     severity: 'high',
     autoFix: 'flag',
     language: 'javascript',
+    ast: { matcher: 'placeholder_throw', message: /TODO|implement|not\s+impl/i },
     description: 'throw new Error("TODO: implement...") placeholder'
   },
 
@@ -244,6 +328,7 @@ const slopPatterns = {
     severity: 'high',
     autoFix: 'flag',
     language: 'javascript',
+    ast: { matcher: 'empty_function' },
     description: 'Empty function body (placeholder)'
   },
 
@@ -256,6 +341,7 @@ const slopPatterns = {
     severity: 'high',
     autoFix: 'flag',
     language: 'rust',
+    skipInlineTests: true,
     description: 'Rust todo!() or unimplemented!() macro'
   },
 
@@ -343,7 +429,9 @@ const slopPatterns = {
     severity: 'high',
     autoFix: 'add_logging',
     language: 'javascript',
-    description: 'Empty catch blocks without error handling'
+    ast: { matcher: 'empty_catch' },
+    description: 'Empty catch blocks without error handling',
+    enforcedBy: ['eslint:no-empty', 'biome:suspicious/noEmptyBlockStatements']
   },
 
   /**
@@ -355,7 +443,64 @@ const slopPatterns = {
     severity: 'high',
     autoFix: 'add_logging',
     language: 'python',
-    description: 'Empty except blocks with just pass'
+    description: 'Empty except blocks with just pass',
+    enforcedBy: ['ruff:S110']
+  },
+
+  /**
+   * Python: bare or broad except with a `pass` body on the next line
+   * The one-line form is reported by empty_except_py
+   */
+  broad_except_pass_py: {
+    pattern: /^[ \t]*except(?:[ \t]*|[ \t(]+(?:Exception|BaseException)\b[^:\n]*):[ \t]*(?:#[^\n]*)?\n[ \t]*pass[ \t]*(?:#[^\n]*)?$/m,
+    exclude: [],
+    severity: 'high',
+    autoFix: 'add_logging',
+    language: 'python',
+    multiline: true,
+    description: 'except Exception / bare except that silently passes',
+    enforcedBy: ['ruff:S110']
+  },
+
+  /**
+   * Python: public function typed with Any
+   */
+  any_typed_public_py: {
+    pattern: /^[ \t]*(?:async[ \t]+)?def[ \t]+(?!_)\w+[ \t]*\([^)]*?(?::[ \t]*(?:typing\.)?Any\b|\)[ \t]*->[ \t]*(?:typing\.)?Any\b)/m,
+    exclude: ['test_*.py', '*_test.py', 'conftest.py', '**/tests/**'],
+    severity: 'medium',
+    autoFix: 'flag',
+    language: 'python',
+    multiline: true,
+    description: 'Public function with Any-typed parameters or return (use a concrete type or Protocol)',
+    enforcedBy: ['ruff:ANN401']
+  },
+
+  /**
+   * Python: blanket `# type: ignore` / `# noqa` without error codes
+   */
+  blanket_suppression_py: {
+    pattern: /#\s*type:\s*ignore\b(?!\[)|#\s*noqa\b(?!\s*:)/i,
+    exclude: [],
+    severity: 'medium',
+    autoFix: 'flag',
+    language: 'python',
+    description: 'Blanket # type: ignore or # noqa without error codes',
+    enforcedBy: [['ruff:PGH003', 'ruff:PGH004']]
+  },
+
+  /**
+   * Python: mutable default arguments
+   */
+  mutable_default_arg_py: {
+    pattern: /^[ \t]*(?:async[ \t]+)?def[ \t]+\w+[ \t]*\([^)]*?=[ \t]*(?:\[|\{|(?:list|dict|set)\(\))/m,
+    exclude: [],
+    severity: 'high',
+    autoFix: 'flag',
+    language: 'python',
+    multiline: true,
+    description: 'Mutable default argument (shared across calls; default to None)',
+    enforcedBy: [['ruff:B006'], ['flake8:B006']]
   },
 
   /**
@@ -402,7 +547,8 @@ const slopPatterns = {
     severity: 'low',
     autoFix: 'flag',
     language: null,
-    description: 'Magic numbers (2-3 digits) in business logic that should be named constants (excludes styling, configs, HTTP codes)'
+    description: 'Magic numbers (2-3 digits) in business logic that should be named constants (excludes styling, configs, HTTP codes)',
+    enforcedBy: ['eslint:no-magic-numbers', 'ruff:PLR2004', 'golangci-lint:mnd', 'golangci-lint:gomnd']
   },
 
   /**
@@ -411,7 +557,7 @@ const slopPatterns = {
    * Flag for review but not aggressive auto-fix
    */
   disabled_linter: {
-    pattern: /(eslint-disable|pylint: disable|#\s*noqa|@SuppressWarnings|#\[allow\()/,
+    pattern: /(eslint-disable|pylint: disable|#\s*noqa\s*:|@SuppressWarnings|#\[allow\()/,
     exclude: ['*.test.*', '*.spec.*', '**/tests/**', '**/test/**'],
     severity: 'low',  // Reduced from medium - many disables are justified
     autoFix: 'flag',
@@ -428,7 +574,8 @@ const slopPatterns = {
     severity: 'low',
     autoFix: 'remove',
     language: null,
-    description: 'Imports marked as unused'
+    description: 'Imports marked as unused',
+    enforcedBy: ['eslint:no-unused-vars', 'biome:correctness/noUnusedImports', 'ruff:F401', 'flake8:F401']
   },
 
   /**
@@ -453,7 +600,8 @@ const slopPatterns = {
     severity: 'low',
     autoFix: 'replace',
     language: null,
-    description: 'Mixed tabs and spaces'
+    description: 'Mixed tabs and spaces',
+    enforcedBy: ['prettier', 'biome', 'black', 'ruff', 'rustfmt', 'eslint:no-mixed-spaces-and-tabs', 'ruff:E101', 'flake8:E101']
   },
 
   /**
@@ -465,7 +613,8 @@ const slopPatterns = {
     severity: 'low',
     autoFix: 'remove',
     language: null,
-    description: 'Trailing whitespace at end of lines'
+    description: 'Trailing whitespace at end of lines',
+    enforcedBy: ['prettier', 'biome', 'black', 'ruff', 'rustfmt', 'eslint:no-trailing-spaces', 'ruff:W291', 'flake8:W291']
   },
 
   /**
@@ -477,7 +626,8 @@ const slopPatterns = {
     severity: 'low',
     autoFix: 'replace',
     language: null,
-    description: 'More than 2 consecutive blank lines'
+    description: 'More than 2 consecutive blank lines',
+    enforcedBy: ['prettier', 'biome', 'black', 'ruff', 'rustfmt', 'eslint:no-multiple-empty-lines', 'ruff:E303', 'flake8:E303']
   },
 
   /**
@@ -498,7 +648,28 @@ const slopPatterns = {
     severity: 'critical',
     autoFix: 'flag',
     language: null,
-    description: 'Potential hardcoded credentials (excludes test/mock/example values)'
+    description: 'Potential hardcoded credentials (excludes test/mock/example values)',
+    category: 'secrets'
+  },
+
+  /**
+   * Random-looking values assigned to token/secret/password names
+   * Catches names hardcoded_secrets misses (`accessTokenValue`, `DB_PASSWORD_PROD`);
+   * values need a digit and 3.5 bits/char, so labels like "user-password-input" pass
+   */
+  secret_entropy_assignment: {
+    pattern: /(?:token|secret|passw(?:or)?d|pwd)[\w$]*["']?\s*(?::=|[:=])\s*["'`]((?=[^"'`\s]*\d)[^"'`\s]{16,})["'`]/i,
+    exclude: [
+      '*.test.*', '*.spec.*', '*.example.*', '*.sample.*', '*.md', '*.lock',
+      '**/fixtures/**', '**/mocks/**', '**/test/**', '**/tests/**'
+    ],
+    severity: 'critical',
+    autoFix: 'flag',
+    language: null,
+    description: 'High-entropy value assigned to a token/secret/password variable',
+    category: 'secrets',
+    requiresEntropyCheck: true,
+    entropyThreshold: 3.5
   },
 
   /**
@@ -526,10 +697,10 @@ const slopPatterns = {
   ),
 
   /**
-   * AWS credentials (access key IDs and secret keys)
+   * AWS credentials (long-term AKIA and temporary ASIA key IDs, secret keys)
    */
   aws_credentials: createSecretPattern(
-    /(AKIA[0-9A-Z]{16}|aws_secret_access_key\s*[:=]\s*["'`][A-Za-z0-9/+=]{40}["'`])/i,
+    /(\b(?:AKIA|ASIA|ABIA|ACCA)[0-9A-Z]{16}\b|aws_secret_access_key\s*[:=]\s*["'`][A-Za-z0-9/+=]{40}["'`])/i,
     'Hardcoded AWS credentials'
   ),
 
@@ -590,10 +761,10 @@ const slopPatterns = {
   ),
 
   /**
-   * Private keys (RSA, DSA, EC, PGP)
+   * Private keys (PKCS#8, RSA, DSA, EC, OpenSSH, encrypted, PGP)
    */
   private_key: createSecretPattern(
-    /-----BEGIN\s+(RSA\s+)?PRIVATE\s+KEY-----/,
+    /-----BEGIN\s+(?:(?:RSA|DSA|EC|OPENSSH|ENCRYPTED|PGP)\s+)?PRIVATE\s+KEY(?:\s+BLOCK)?-----/,
     'Private key in source code',
     ['*.pem.example'] // Additional excludes beyond standard test files
   ),
@@ -608,10 +779,27 @@ const slopPatterns = {
     autoFix: 'flag',
     language: null,
     description: 'High-entropy string that may be a secret',
+    category: 'secrets',
     requiresEntropyCheck: true,
     entropyThreshold: 4.5
   },
 
+  /**
+   * Values from local .env files pasted into source (multi-pass analysis)
+   * Compares source lines against the values in .env, .env.local, ... at the
+   * repository root; templates (.env.example, .env.sample) are ignored
+   */
+  env_value_in_source: {
+    pattern: null, // Requires multi-pass analysis
+    requiresMultiPass: true,
+    exclude: ['*.md', '*.lock'],
+    severity: 'critical',
+    autoFix: 'flag',
+    language: null,
+    description: 'Value from a local .env file committed into source',
+    category: 'secrets'
+  },
+
   /**
    * Process.exit in libraries
    * IMPROVED: Excludes scripts/, prisma/, and all legitimate script locations
@@ -629,7 +817,9 @@ const slopPatterns = {
     severity: 'high',
     autoFix: 'flag',
     language: 'javascript',
-    description: 'process.exit() should not be in library code (excludes scripts, seeds, migrations)'
+    ast: { matcher: 'member_call', object: 'process', properties: ['exit'] },
+    description: 'process.exit() should not be in library code (excludes scripts, seeds, migrations)',
+    enforcedBy: ['eslint:n/no-process-exit', 'eslint:node/no-process-exit', 'eslint:no-process-exit']
   },
 
   /**
@@ -758,6 +948,76 @@ const slopPatterns = {
     minCodeLines: 3
   },
 
+  // ============================================================================
+  // AI Artifact Detection
+  // Detects LLM conversation residue left in comments; severity `explain`
+  // marks findings that only need a reviewer's glance, never a build failure
+  // ============================================================================
+
+  /**
+   * Assistant self-references in comments ("As an AI language model...")
+   */
+  ai_artifact_self_reference: {
+    pattern: /(?:\/\/|#|\/\*|^\s*\*).*\b(?:as an ai\b|as a (?:large )?language model|i(?:'m| am) (?:just )?an ai\b|my (?:knowledge|training) (?:cutoff|data)|i (?:cannot|can't|don't have the ability to) (?:browse|access|run|execute))/i,
+    exclude: ['*.md', '*.txt'],
+    severity: 'explain',
+    autoFix: 'flag',
+    language: null,
+    description: 'Assistant self-reference in a comment - chat output pasted into code'
+  },
+
+  /**
+   * Chat replies left in comments ("Here's the updated code", "Hope this helps")
+   */
+  ai_artifact_chat_residue: {
+    pattern: /(?:\/\/|#|\/\*|^\s*\*)\s*(?:\.{3}\s*)?(?:here(?:'s| is) (?:the|your|an?) (?:updated|modified|revised|complete|full|corrected|fixed|final|new) (?:code|version|implementation|function|file|snippet)|i(?:'ve| have) (?:updated|modified|revised|rewritten|refactored) (?:the|your)\b|let me know if you|hope this helps|this (?:updated|revised|modified) (?:code|version)|(?:the )?rest of (?:the|your) code (?:remains|stays) (?:the same|unchanged))/i,
+    exclude: ['*.md', '*.txt'],
+    severity: 'explain',
+    autoFix: 'flag',
+    language: null,
+    description: 'Chat reply left in a comment ("Here\'s the updated code") - describes the edit, not the code'
+  },
+
+  /**
+   * Prompt fragments: role markers, chat template tokens, markdown fences in comments
+   */
+  ai_artifact_prompt_fragment: {
+    pattern: /^\s*(?:\/\/|#|\/\*|\*)\s*(?:(?:human|assistant)\s*:\s|(?:please )?(?:write|implement|create|generate) (?:a|an|the) (?:function|class|method|script|component) (?:that|which|to)\b|`{3}[a-z]*\s*$)|<\|im_(?:start|end)\|>|\[\/?INST\]/i,
+    exclude: ['*.md', '*.txt', '*.test.*', '*.spec.*', '**/prompts/**'],
+    severity: 'explain',
+    autoFix: 'flag',
+    language: null,
+    description: 'Prompt fragment in code (role marker, chat template token or markdown fence)'
+  },
+
+  /**
+   * Emoji-heavy section banners in comments
+   */
+  ai_artifact_emoji_banner: {
+    pattern: /^\s*(?:\/\/|#|\/\*+|\*)[^\n]*?\p{Extended_Pictographic}[^\n]*?\p{Extended_Pictographic}/u,
+    exclude: ['*.md', '*.txt', '*.json', '*.yml', '*.yaml'],
+    severity: 'explain',
+    autoFix: 'flag',
+    language: null,
+    description: 'Emoji-decorated comment banner - typical generated-code styling'
+  },
+
+  /**
+   * Comments that restate the next line word for word
+   * e.g. `// return the result` above `return result;`
+   * Requires multi-pass analysis (comment vs following line)
+   */
+  ai_artifact_restating_comment: {
+    pattern: null, // Requires multi-pass analysis
+    requiresMultiPass: true,
+    exclude: ['*.test.*', '*.spec.*', '*.md', '*.d.ts'],
+    severity: 'explain',
+    autoFix: 'flag',
+    language: null,
+    description: 'Comment restates the next line - says what, not why',
+    minWords: 2
+  },
+
   // ============================================================================
   // Over-Engineering Detection
   // Detects excessive complexity relative to public API surface
@@ -821,6 +1081,76 @@ const slopPatterns = {
     requiresMultiPass: true
   },
 
+  // ============================================================================
+  // Go Detection
+  // Debug output, discarded errors, and shortcuts that linters leave to config
+  // ============================================================================
+
+  /**
+   * Go: fmt.Print* / builtin print debugging
+   * Excludes main packages and cmd/, where printing is the program's output
+   */
+  go_debugging: {
+    pattern: /(?:\bfmt\.Print(?:ln|f)?|^\s*print(?:ln)?)\s*\(/,
+    exclude: ['*_test.go', '**/testdata/**', 'main.go', '**/main.go', 'cmd/**', '**/cmd/**'],
+    severity: 'medium',
+    autoFix: 'remove',
+    language: 'go',
+    description: 'fmt.Print/println debugging in Go library code',
+    enforcedBy: ['golangci-lint:forbidigo']
+  },
+
+  /**
+   * Go: error discarded with `_ =` or an unchecked call
+   * Bare calls are limited to well-known error-returning functions
+   */
+  go_ignored_error: {
+    pattern: /^\s*(?:_\s*=\s*(?:err\b|[\w.]+\()|(?:os\.(?:Remove|RemoveAll|Rename|Setenv|Unsetenv|Chdir|Chmod|Mkdir|MkdirAll|WriteFile)|[\w.]+\.(?:Close|Flush|Sync))\s*\(.*\)\s*$)/,
+    exclude: ['*_test.go', '**/testdata/**'],
+    severity: 'high',
+    autoFix: 'flag',
+    language: 'go',
+    description: 'Go error return discarded (`_ = err`, `_ = f()` or unchecked Close/Remove)'
+  },
+
+  /**
+   * Go: panic() in library packages
+   * panic("TODO") placeholders are reported by placeholder_panic_go instead
+   */
+  go_panic_library: {
+    pattern: /\bpanic\s*\((?!\s*["'`][^"'`]*(?:TODO|[Ii]mplement|[Nn]ot\s+impl))/,
+    exclude: ['*_test.go', '**/testdata/**', 'main.go', '**/main.go', 'cmd/**', '**/cmd/**'],
+    severity: 'medium',
+    autoFix: 'flag',
+    language: 'go',
+    description: 'panic() in Go library code (return an error to the caller)'
+  },
+
+  /**
+   * Go: time.Sleep in tests (flaky, slow synchronization)
+   */
+  go_sleep_in_tests: {
+    pattern: /\btime\.Sleep\s*\(/,
+    include: ['*_test.go'],
+    exclude: ['**/testdata/**'],
+    severity: 'medium',
+    autoFix: 'flag',
+    language: 'go',
+    description: 'time.Sleep in Go tests (wait on a channel, sync primitive or fake clock instead)'
+  },
+
+  /**
+   * Go: context.TODO() left in production paths
+   */
+  go_context_todo: {
+    pattern: /\bcontext\.TODO\s*\(\s*\)/,
+    exclude: ['*_test.go', '**/testdata/**'],
+    severity: 'medium',
+    autoFix: 'flag',
+    language: 'go',
+    description: 'context.TODO() in Go production code (thread the caller\'s context through)'
+  },
+
   // ============================================================================
   // Code Smell Detection (#106)
   // High-impact code smell patterns for maintainability and readability
@@ -888,6 +1218,35 @@ const slopPatterns = {
     autoFix: 'flag',
     language: null,
     description: 'Unreachable code after return/throw/break/continue',
+    enforcedBy: ['eslint:no-unreachable', 'biome:correctness/noUnreachable'],
+    requiresMultiPass: true
+  },
+
+  /**
+   * Unreferenced files (multi-pass analysis, needs a repo map)
+   * JS/TS/Python files no other file imports or calls into, except entry points
+   */
+  dead_code_unreferenced_file: {
+    pattern: null, // Requires the repo-map dependency and call graphs
+    exclude: ['*.test.*', '*.spec.*', '*.d.ts'],
+    severity: 'medium',
+    autoFix: 'flag',
+    language: null,
+    description: 'File is never imported or called - safe to delete unless loaded dynamically',
+    requiresMultiPass: true
+  },
+
+  /**
+   * Unused exports (multi-pass analysis, needs a repo map)
+   * Exported symbols whose name no other file mentions
+   */
+  dead_code_unused_export: {
+    pattern: null, // Requires the repo-map symbol index
+    exclude: ['*.test.*', '*.spec.*', '*.d.ts'],
+    severity: 'low',
+    autoFix: 'flag',
+    language: null,
+    description: 'Export is never referenced outside its file',
     requiresMultiPass: true
   },
 
@@ -910,6 +1269,22 @@ const slopPatterns = {
     clusterThreshold: 5      // Flag clusters with 5+ files changing together
   },
 
+  /**
+   * Duplicate code blocks (multi-pass analysis)
+   * Token-based winnowing across files; see duplicates.js
+   */
+  duplicate_code: {
+    pattern: null, // Requires multi-pass analysis
+    exclude: ['*.test.*', '*.spec.*', '**/tests/**', '**/fixtures/**', '*.d.ts'],
+    severity: 'medium',
+    autoFix: 'flag',
+    language: null,
+    description: 'Duplicated code block (extract a shared function)',
+    requiresMultiPass: true,
+    minLines: 8,    // Both copies span at least 8 lines
+    minTokens: 50   // and at least 50 tokens
+  },
+
   // REMOVED: feature_envy
   // Reason: 100% false positive rate with regex. Requires AST analysis.
   // Alternative: Use eslint-plugin-clean-code for proper feature envy detection.
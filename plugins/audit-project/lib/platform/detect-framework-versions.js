@@ -0,0 +1,214 @@
+#!/usr/bin/env node
+/**
+ * Framework Version Detection
+ * Reads the installed versions of frameworks with review patterns (React,
+ * Next.js, Vue, Django, Pydantic, Rails, ...) from lockfiles, so reviews pick
+ * the rules for the major version in use. Manifest ranges (package.json,
+ * requirements*.txt) are the fallback when no lockfile resolves a package.
+ *
+ * Synchronous, like detect-runtimes.
+ *
+ * Usage: node lib/platform/detect-framework-versions.js [path]
+ * Output: JSON with detected framework versions (or null)
+ *
+ * @module lib/platform/detect-framework-versions
+ */
+
+const fs = require('fs');
+const path = require('path');
+const { FRAMEWORK_VERSION_CONFIGS } = require('./detection-configs');
+const { readText, parseJsonc } = require('./detect-linters');
+const { lowerBound, parseVersion } = require('./detect-runtimes');
+const { normalizePythonName } = require('./detect-database');
+
+/**
+ * Read a lockfile, allowing larger files than config readers do
+ * @param {string} basePath - Project root
+ * @param {string} file - Relative path
+ * @returns {string|null}
+ */
+function readLockfile(basePath, file) {
+  try {
+    const filePath = path.join(basePath, file);
+    const stats = fs.statSync(filePath);
+    if (!stats.isFile() || stats.size > FRAMEWORK_VERSION_CONFIGS.maxLockfileBytes) return null;
+    return fs.readFileSync(filePath, 'utf8');
+  } catch {
+    return null;
+  }
+}
+
+/**
+ * Escape a package name for use in a regex
+ * @param {string} name
+ * @returns {string}
+ */
+function escapeRegex(name) {
+  return name.replace(/[.*+?^${}()|[\]\\/]/g, '\\$&');
+}
+
+/**
+ * Resolved version of a package in an npm, yarn or pnpm lockfile
+ * @param {string} file - Lockfile name
+ * @param {string} content - Lockfile content
+ * @param {string} name - Package name
+ * @returns {string|null}
+ */
+function npmLockVersion(file, content, name) {
+  if (file.endsWith('.json')) {
+    let lock;
+    try {
+      lock = JSON.parse(content);
+    } catch {
+      return null;
+    }
+    const entry = (lock.packages && lock.packages[`node_modules/${name}`]) ||
+      (lock.dependencies && lock.dependencies[name]);
+    return entry && typeof entry.version === 'string' ? entry.version : null;
+  }
+
+  const escaped = escapeRegex(name);
+  if (file === 'yarn.lock') {
+    // `"react@^18.2.0", react@^18.0.0:` then `  version "18.2.0"` (v1) or `  version: 18.2.0` (berry)
+    const header = new RegExp(`^"?${escaped}@[^\\n]*:\\s*\\n(?:[ \\t]+[^\\n]*\\n)*?[ \\t]+version:?\\s+"?([^"\\s]+)"?`, 'm');
+    const match = content.match(header);
+    return match ? match[1] : null;
+  }
+
+  // pnpm: `/react@18.2.0:` (v6), `react@18.2.0:` (v9), `/react/18.2.0:` (v5)
+  const key = new RegExp(`^\\s+['"]?/?${escaped}[@/](\\d+\\.\\d+\\.\\d+[^:'"(\\s]*)`, 'm');
+  const match = content.match(key);
+  return match ? match[1] : null;
+}
+
+/**
+ * Resolved version of a package in a Poetry, uv or Pipenv lockfile
+ * @param {string} file - Lockfile name
+ * @param {string} content - Lockfile content
+ * @param {string} name - Normalized package name
+ * @returns {string|null}
+ */
+function pythonLockVersion(file, content, name) {
+  if (file === 'Pipfile.lock') {
+    let lock;
+    try {
+      lock = JSON.parse(content);
+    } catch {
+      return null;
+    }
+    for (const section of ['default', 'develop']) {
+      const entries = lock[section] || {};
+      const key = Object.keys(entries).find(entry => normalizePythonName(entry) === name);
+      if (key && typeof entries[key].version === 'string') return entries[key].version.replace(/^==/, '');
+    }
+    return null;
+  }
+
+  for (const block of content.split(/^\[\[package\]\]\s*$/m).slice(1)) {
+    const packageName = block.match(/^name\s*=\s*"([^"]+)"/m);
+    if (!packageName || normalizePythonName(packageName[1]) !== name) continue;
+    const version = block.match(/^version\s*=\s*"([^"]+)"/m);
+    return version ? version[1] : null;
+  }
+  return null;
+}
+
+/**
+ * Resolved version of a gem in Gemfile.lock
+ * @param {string} content - Lockfile content
+ * @param {string} name - Gem name
+ * @returns {string|null}
+ */
+function gemLockVersion(content, name) {
+  const match = content.match(new RegExp(`^ {4}${escapeRegex(name)} \\(([^)]+)\\)`, 'm'));
+  return match ? match[1] : null;
+}
+
+/**
+ * Lowest version a manifest range allows, when no lockfile resolves the package
+ * @param {string} basePath - Project root
+ * @param {string} ecosystem - npm or python
+ * @param {string} name - Package name
+ * @returns {{version: string, source: string}|null}
+ */
+function manifestVersion(basePath, ecosystem, name) {
+  if (ecosystem === 'npm') {
+    const pkg = parseJsonc(readText(basePath, 'package.json') || '');
+    if (!pkg) return null;
+    for (const field of ['dependencies', 'devDependencies', 'peerDependencies']) {
+      const range = pkg[field] && pkg[field][name];
+      const bound = typeof range === 'string' ? lowerBound(range) : null;
+      if (bound) return { version: bound, source: `package.json#${field}` };
+    }
+    return null;
+  }
+  if (ecosystem === 'python') {
+    for (const file of ['requirements.txt', 'pyproject.toml']) {
+      const content = readText(basePath, file);
+      if (!content) continue;
+      for (const match of content.matchAll(/^\s*["']?([A-Za-z0-9][\w.-]*)(?:\[[^\]]*\])?\s*((?:[<>=!~]=?|===)\s*[\w.*]+(?:\s*,\s*(?:[<>=!~]=?)\s*[\w.*]+)*)/gm)) {
+        if (normalizePythonName(match[1]) !== name) continue;
+        const bound = lowerBound(match[2]);
+        if (bound) return { version: bound, source: file };
+      }
+    }
+  }
+  return null;
+}
+
+/**
+ * Detect installed framework versions
+ * `version` is the locked version, or the manifest range's lower bound when
+ * nothing is locked; `major` is its first component.
+ * @param {string} [basePath=process.cwd()] - Project root
+ * @returns {Object|null} `{frameworks: [{name, package, version, major, source}]}` or null when nothing is found
+ */
+function detectFrameworkVersions(basePath = process.cwd()) {
+  const lockfiles = {};
+  for (const [ecosystem, files] of Object.entries(FRAMEWORK_VERSION_CONFIGS.lockfiles)) {
+    lockfiles[ecosystem] = files
+      .map(file => ({ file, content: readLockfile(basePath, file) }))
+      .filter(entry => entry.content !== null);
+  }
+
+  const frameworks = [];
+  for (const { framework, ecosystem, name } of FRAMEWORK_VERSION_CONFIGS.packages) {
+    const lookupName = ecosystem === 'python' ? normalizePythonName(name) : name;
+    let found = null;
+    for (const { file, content } of lockfiles[ecosystem] || []) {
+      const version = ecosystem === 'npm' ? npmLockVersion(file, content, lookupName)
+        : ecosystem === 'python' ? pythonLockVersion(file, content, lookupName)
+          : gemLockVersion(content, lookupName);
+      if (version && parseVersion(version)) {
+        found = { version, source: file };
+        break;
+      }
+    }
+    if (!found) found = manifestVersion(basePath, ecosystem, lookupName);
+    if (!found) continue;
+
+    frameworks.push({
+      name: framework,
+      package: name,
+      version: found.version,
+      major: parseVersion(found.version)[0],
+      source: found.source
+    });
+  }
+
+  return frameworks.length > 0 ? { frameworks } : null;
+}
+
+// When run directly, output JSON
+if (require.main === module) {
+  const result = detectFrameworkVersions(process.argv[2] || process.cwd());
+  const indent = process.stdout.isTTY ? 2 : 0;
+  console.log(JSON.stringify(result, null, indent));
+}
+
+module.exports = {
+  detectFrameworkVersions,
+  npmLockVersion,
+  pythonLockVersion,
+  gemLockVersion
+};
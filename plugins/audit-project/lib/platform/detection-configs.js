@@ -16,22 +16,59 @@ const CI_CONFIGS = [
   { file: '.gitlab-ci.yml', platform: 'gitlab-ci' },
   { file: '.circleci/config.yml', platform: 'circleci' },
   { file: 'Jenkinsfile', platform: 'jenkins' },
-  { file: '.travis.yml', platform: 'travis' }
+  { file: '.travis.yml', platform: 'travis' },
+  { file: '.buildkite', platform: 'buildkite' },
+  { file: 'azure-pipelines.yml', platform: 'azure-pipelines' },
+  { file: 'azure-pipelines.yaml', platform: 'azure-pipelines' },
+  { file: '.drone.yml', platform: 'drone' },
+  { file: '.woodpecker.yml', platform: 'woodpecker' },
+  { file: '.woodpecker.yaml', platform: 'woodpecker' },
+  { file: '.woodpecker', platform: 'woodpecker' }
 ];
 
+/**
+ * Pipeline definition locations per CI platform
+ * `dir` entries are scanned for *.yml/*.yaml files
+ */
+const CI_PIPELINE_SOURCES = {
+  'github-actions': { dir: '.github/workflows' },
+  'gitlab-ci': { files: ['.gitlab-ci.yml'] },
+  circleci: { files: ['.circleci/config.yml'] },
+  travis: { files: ['.travis.yml'] },
+  buildkite: { dir: '.buildkite' },
+  'azure-pipelines': { files: ['azure-pipelines.yml', 'azure-pipelines.yaml'] },
+  drone: { files: ['.drone.yml'] },
+  woodpecker: { files: ['.woodpecker.yml', '.woodpecker.yaml'], dir: '.woodpecker' }
+};
+
 /**
  * Deployment platform detection configuration
- * Order matters - first match wins
+ * Order matters - first match wins for `deployment`; every match is listed in
+ * `deployments`. `contains` requires the file content to match, and only the
+ * first matching config per file counts. `weight` is the candidate evidence
+ * weight (default `CONFIDENCE_CONFIGS.deployment.config`) for markers other
+ * tools share.
  */
 const DEPLOYMENT_CONFIGS = [
   { file: 'railway.json', platform: 'railway' },
-  { file: 'railway.toml', platform: 'railway' },  // Legacy Railway marker
+  { file: 'railway.toml', platform: 'railway', weight: 0.6 },  // Legacy Railway marker
   { file: 'vercel.json', platform: 'vercel' },
   { file: 'netlify.toml', platform: 'netlify' },
-  { file: '.netlify', platform: 'netlify' },       // Legacy Netlify marker
+  { file: '.netlify', platform: 'netlify', weight: 0.6 },       // Legacy Netlify marker
   { file: 'fly.toml', platform: 'fly' },
   { file: '.platform.app.yaml', platform: 'platformsh' },
-  { file: 'render.yaml', platform: 'render' }
+  { file: 'render.yaml', platform: 'render' },
+  { file: 'wrangler.toml', platform: 'cloudflare-pages', contains: /^\s*pages_build_output_dir\s*=/m },
+  { file: 'wrangler.toml', platform: 'cloudflare-workers' },
+  { file: 'wrangler.json', platform: 'cloudflare-pages', contains: /"pages_build_output_dir"\s*:/ },
+  { file: 'wrangler.json', platform: 'cloudflare-workers' },
+  { file: 'wrangler.jsonc', platform: 'cloudflare-pages', contains: /"pages_build_output_dir"\s*:/ },
+  { file: 'wrangler.jsonc', platform: 'cloudflare-workers' },
+  { file: 'amplify.yml', platform: 'aws-amplify' },
+  { file: 'Procfile', platform: 'heroku', weight: 0.5 },  // Also used by Dokku, foreman, etc.
+  { file: 'app.json', platform: 'heroku', contains: /"(?:buildpacks|formation|addons|stack)"\s*:/ },
+  { file: 'deno.json', platform: 'deno-deploy', contains: /"deploy"\s*:/ },
+  { file: 'deno.jsonc', platform: 'deno-deploy', contains: /"deploy"\s*:/ }
 ];
 
 /**
@@ -70,12 +107,529 @@ const PACKAGE_MANAGER_CONFIGS = [
 ];
 
 /**
- * Branch strategy patterns
+ * Monorepo task runners detected by marker file
+ * `patterns` are default project locations for tools that do not rely on
+ * package-manager workspaces
+ */
+const WORKSPACE_TOOL_CONFIGS = [
+  { file: 'turbo.json', tool: 'turborepo' },
+  { file: 'nx.json', tool: 'nx', patterns: ['apps/*', 'libs/*', 'packages/*'] }
+];
+
+/**
+ * Container and Kubernetes file detection configuration
+ * Matched against file names found within `maxDepth` directories of the root
+ */
+const CONTAINER_CONFIGS = {
+  maxDepth: 3,
+  dockerfile: /^(?:Dockerfile|Containerfile)(?:\..+)?$|\.dockerfile$/i,
+  compose: /^(?:docker-)?compose(?:\.[\w-]+)*\.ya?ml$/i,
+  helmChart: /^Chart\.ya?ml$/,
+  kustomization: /^(?:kustomization\.ya?ml|Kustomization)$/,
+  // YAML under these directories is checked for `apiVersion:` + `kind:`
+  manifestDirs: ['k8s', 'kubernetes', 'kube', 'manifests', 'deploy', 'deployment', 'deployments', 'infra'],
+  maxManifests: 100
+};
+
+/**
+ * Infrastructure-as-code detection configuration
+ * Matched against files within `maxDepth` directories of the root; each
+ * directory holding a tool's files is one project. CloudFormation candidates
+ * (by name or directory) are confirmed by `cloudformationMarker`.
+ * `providers` maps Terraform providers and Pulumi/CDKTF packages to a cloud;
+ * unlisted providers (random, null, tls, ...) are ignored.
+ */
+const IAC_CONFIGS = {
+  maxDepth: 3,
+  terraform: /\.tf(?:\.json)?$/,
+  pulumi: /^Pulumi\.ya?ml$/,
+  cdk: /^cdk\.json$/,
+  cdktf: /^cdktf\.json$/,
+  cloudformationCandidate: /(?:^|\/)(?:[^/]*(?:template|cfn|cloudformation|stack)[^/]*\.(?:ya?ml|json)|[^/]+\.template)$|(?:^|\/)(?:cfn|cloudformation|templates?)\/(?:[^/]+\/)*[^/]+\.(?:ya?ml|json)$/i,
+  cloudformationMarker: /^AWSTemplateFormatVersion\s*:|"AWSTemplateFormatVersion"\s*:|^Transform:\s*['"]?AWS::Serverless/m,
+  maxTemplates: 100,
+  providers: {
+    aws: 'aws',
+    google: 'gcp',
+    'google-beta': 'gcp',
+    gcp: 'gcp',
+    azurerm: 'azure',
+    azuread: 'azure',
+    azure: 'azure',
+    'azure-native': 'azure',
+    kubernetes: 'kubernetes',
+    cloudflare: 'cloudflare',
+    digitalocean: 'digitalocean',
+    vercel: 'vercel'
+  }
+};
+
+/**
+ * Serverless function detection configuration
+ * Serverless Framework configs, SAM templates (CloudFormation with the
+ * Serverless transform), Google Cloud Functions (Functions Framework
+ * dependency plus registered entry points), and Firebase Functions.
+ * `deployCommands` target a single function; `{name}`, `{runtime}`,
+ * `{entryPoint}`, and `{path}` are filled per function.
+ */
+const SERVERLESS_CONFIGS = {
+  maxDepth: 3,
+  serverless: /^serverless\.(?:ya?ml|json|ts|js)$/,
+  samTransform: /^Transform:\s*['"]?AWS::Serverless|"Transform"\s*:\s*"AWS::Serverless/m,
+  samFunctionType: 'AWS::Serverless::Function',
+  gcpDependencies: {
+    node: /"@google-cloud\/functions-framework"\s*:/,
+    python: /^functions-framework\b/im,
+    go: /github\.com\/GoogleCloudPlatform\/functions-framework-go\b/
+  },
+  gcpEntryPoints: {
+    node: /\bfunctions\.(http|cloudEvent)\(\s*['"]([\w-]+)['"]/g,
+    python: /@functions_framework\.(http|cloud_event)\s*\n\s*def\s+(\w+)/g,
+    go: /\bfunctions\.(HTTP|CloudEvent)\(\s*"([\w-]+)"/g
+  },
+  gcpSources: {
+    node: ['index.js', 'index.mjs', 'index.cjs', 'src/index.js', 'src/index.ts'],
+    python: ['main.py']
+  },
+  firebaseSources: ['index.js', 'src/index.js', 'src/index.ts', 'main.py'],
+  firebaseEntryPoints: [
+    /^\s*(?:exports\.(\w+)|export\s+const\s+(\w+))\s*=\s*(?:functions\.|v2\.|on[A-Z]\w*\()/gm,
+    /@\w+_fn\.on_\w+\([^)]*\)\s*\n\s*def\s+(\w+)/g
+  ],
+  deployCommands: {
+    serverless: 'serverless deploy function --function {name}',
+    sam: 'sam sync --code --resource-id {name}',
+    'gcp-functions': 'gcloud functions deploy {name} --gen2 --runtime {runtime} --entry-point {entryPoint} --source {path}',
+    'firebase-functions': 'firebase deploy --only functions:{name}'
+  }
+};
+
+/**
+ * Ranked candidate configuration
+ * Each piece of evidence has a weight in [0, 1]; a candidate's confidence is
+ * 1 - Π(1 - weight). A category is ambiguous when its runner-up reaches
+ * `ambiguityThreshold`.
+ */
+const CONFIDENCE_CONFIGS = {
+  ambiguityThreshold: 0.5,
+  ci: { pipeline: 0.9, marker: 0.6 },
+  deployment: { config: 0.9, ciCommand: 0.5 },
+  packageManager: { lockfile: 0.9, packageManagerField: 0.95 },
+  projectTypes: [
+    { file: 'package.json', type: 'nodejs', weight: 0.8 },
+    { file: 'requirements.txt', type: 'python', weight: 0.7 },
+    { file: 'pyproject.toml', type: 'python', weight: 0.9 },
+    { file: 'setup.py', type: 'python', weight: 0.8 },
+    { file: 'Cargo.toml', type: 'rust', weight: 0.9 },
+    { file: 'go.mod', type: 'go', weight: 0.9 },
+    { file: 'pom.xml', type: 'java', weight: 0.9 },
+    { file: 'build.gradle', type: 'java', weight: 0.9 }
+  ],
+  // Deploy steps in CI pipeline files
+  deployCommands: [
+    { platform: 'vercel', pattern: /\bvercel\s+(?:deploy\b|--prod\b)|amondnet\/vercel-action/ },
+    { platform: 'netlify', pattern: /\bnetlify\s+deploy\b|nwtgck\/actions-netlify/ },
+    { platform: 'fly', pattern: /\bfly(?:ctl)?\s+deploy\b|superfly\/flyctl-actions/ },
+    { platform: 'railway', pattern: /\brailway\s+up\b/ },
+    { platform: 'render', pattern: /api\.render\.com\/deploy/ },
+    { platform: 'cloudflare-pages', pattern: /\bwrangler\s+pages\s+deploy\b|cloudflare\/pages-action/ },
+    { platform: 'cloudflare-workers', pattern: /\bwrangler\s+(?:deploy|publish)\b/ },
+    { platform: 'heroku', pattern: /\bgit\s+push\s+heroku\b|akhileshns\/heroku-deploy|\bheroku\s+container:release\b/ },
+    { platform: 'deno-deploy', pattern: /\bdeployctl\s+deploy\b|denoland\/deployctl/ }
+  ]
+};
+
+/**
+ * Database detection configuration
+ * `dependencies` maps package names per ecosystem to a database; Go entries
+ * also match module subpaths (`github.com/jackc/pgx/v5`)
+ */
+const DATABASE_CONFIGS = {
+  // Reporting order
+  types: ['postgres', 'mysql', 'sqlite', 'mongodb', 'redis'],
+  dependencies: {
+    npm: {
+      'pg': 'postgres',
+      'postgres': 'postgres',
+      '@neondatabase/serverless': 'postgres',
+      '@vercel/postgres': 'postgres',
+      'mysql': 'mysql',
+      'mysql2': 'mysql',
+      'mariadb': 'mysql',
+      'sqlite3': 'sqlite',
+      'better-sqlite3': 'sqlite',
+      '@libsql/client': 'sqlite',
+      'mongodb': 'mongodb',
+      'mongoose': 'mongodb',
+      'redis': 'redis',
+      'ioredis': 'redis',
+      '@upstash/redis': 'redis'
+    },
+    python: {
+      'psycopg': 'postgres',
+      'psycopg2': 'postgres',
+      'psycopg2-binary': 'postgres',
+      'asyncpg': 'postgres',
+      'mysqlclient': 'mysql',
+      'pymysql': 'mysql',
+      'aiomysql': 'mysql',
+      'aiosqlite': 'sqlite',
+      'pymongo': 'mongodb',
+      'motor': 'mongodb',
+      'mongoengine': 'mongodb',
+      'redis': 'redis'
+    },
+    go: {
+      'github.com/lib/pq': 'postgres',
+      'github.com/jackc/pgx': 'postgres',
+      'gorm.io/driver/postgres': 'postgres',
+      'github.com/go-sql-driver/mysql': 'mysql',
+      'gorm.io/driver/mysql': 'mysql',
+      'github.com/mattn/go-sqlite3': 'sqlite',
+      'modernc.org/sqlite': 'sqlite',
+      'gorm.io/driver/sqlite': 'sqlite',
+      'go.mongodb.org/mongo-driver': 'mongodb',
+      'github.com/redis/go-redis': 'redis',
+      'github.com/go-redis/redis': 'redis'
+    },
+    ruby: {
+      'pg': 'postgres',
+      'mysql2': 'mysql',
+      'trilogy': 'mysql',
+      'sqlite3': 'sqlite',
+      'mongoid': 'mongodb',
+      'redis': 'redis'
+    },
+    rust: {
+      'postgres': 'postgres',
+      'tokio-postgres': 'postgres',
+      'mysql': 'mysql',
+      'mysql_async': 'mysql',
+      'rusqlite': 'sqlite',
+      'mongodb': 'mongodb',
+      'redis': 'redis'
+    }
+  },
+  // Compose service images (registry and tag stripped before matching)
+  composeFiles: ['docker-compose.yml', 'docker-compose.yaml', 'compose.yml', 'compose.yaml'],
+  composeImages: [
+    { pattern: /(?:^|\/)(?:postgres|postgresql|postgis|timescaledb|pgvector)$/, database: 'postgres' },
+    { pattern: /(?:^|\/)(?:mysql|mariadb|mysql-server)$/, database: 'mysql' },
+    { pattern: /(?:^|\/)(?:mongo|mongodb|mongodb-community-server)$/, database: 'mongodb' },
+    { pattern: /(?:^|\/)(?:redis|redis-stack|valkey|keydb)$/, database: 'redis' }
+  ],
+  // ORM/schema `provider`, `dialect`, or `adapter` values
+  dialects: {
+    postgres: 'postgres', postgresql: 'postgres', pg: 'postgres', cockroachdb: 'postgres', postgis: 'postgres',
+    mysql: 'mysql', mysql2: 'mysql', mariadb: 'mysql', trilogy: 'mysql',
+    sqlite: 'sqlite', sqlite3: 'sqlite', 'better-sqlite': 'sqlite', turso: 'sqlite',
+    mongodb: 'mongodb'
+  },
+  // Checked in order; the first tool to claim a directory wins
+  migrationDirs: [
+    { dir: 'prisma/migrations', tool: 'prisma' },
+    { dir: 'drizzle', tool: 'drizzle' },
+    { dir: 'alembic/versions', tool: 'alembic' },
+    { dir: 'migrations/versions', tool: 'alembic' },
+    { dir: 'db/migrate', tool: 'activerecord' },
+    { dir: 'supabase/migrations', tool: 'supabase' },
+    { dir: 'db/migrations', tool: null },
+    { dir: 'migrations', tool: null }
+  ]
+};
+
+/**
+ * ORM detection configuration
+ * An ORM is detected by a dependency in `ecosystem` or any of `files`
+ */
+const ORM_CONFIGS = [
+  { name: 'prisma', ecosystem: 'npm', dependencies: ['prisma', '@prisma/client'], files: ['prisma/schema.prisma', 'schema.prisma'] },
+  { name: 'drizzle', ecosystem: 'npm', dependencies: ['drizzle-orm'], files: ['drizzle.config.ts', 'drizzle.config.js', 'drizzle.config.mjs'] },
+  { name: 'sqlalchemy', ecosystem: 'python', dependencies: ['sqlalchemy', 'flask-sqlalchemy', 'sqlmodel'], files: ['alembic.ini'] },
+  { name: 'gorm', ecosystem: 'go', dependencies: ['gorm.io/gorm', 'github.com/jinzhu/gorm'], files: [] },
+  { name: 'activerecord', ecosystem: 'ruby', dependencies: ['activerecord', 'rails'], files: ['config/database.yml'] }
+];
+
+/**
+ * Test framework detection configuration
+ * A framework is detected by a dependency in `ecosystem`, any of `files`, a
+ * `contains` pattern matching the named file, or an `imports` pattern in test
+ * modules. `command` is the fallback invocation when no script, Makefile
+ * target, or tox config runs the tests.
+ */
+const TEST_FRAMEWORK_CONFIGS = [
+  {
+    name: 'jest', language: 'javascript', ecosystem: 'npm', dependencies: ['jest'],
+    files: ['jest.config.js', 'jest.config.ts', 'jest.config.mjs', 'jest.config.cjs', 'jest.config.json'],
+    contains: { 'package.json': /"jest"\s*:\s*\{/ },
+    command: 'npx jest'
+  },
+  {
+    name: 'vitest', language: 'javascript', ecosystem: 'npm', dependencies: ['vitest'],
+    files: ['vitest.config.ts', 'vitest.config.js', 'vitest.config.mts', 'vitest.config.mjs', 'vitest.workspace.ts'],
+    command: 'npx vitest run'
+  },
+  {
+    name: 'mocha', language: 'javascript', ecosystem: 'npm', dependencies: ['mocha'],
+    files: ['.mocharc.js', '.mocharc.cjs', '.mocharc.json', '.mocharc.yml', '.mocharc.yaml'],
+    command: 'npx mocha'
+  },
+  {
+    name: 'pytest', language: 'python', ecosystem: 'python', dependencies: ['pytest'],
+    files: ['pytest.ini', 'conftest.py', 'tests/conftest.py'],
+    contains: { 'pyproject.toml': /^\[tool\.pytest/m, 'setup.cfg': /^\[tool:pytest\]/m, 'tox.ini': /^\[pytest\]/m },
+    command: 'pytest'
+  },
+  {
+    name: 'unittest', language: 'python',
+    // Test modules under these directories are checked for the import
+    imports: { dirs: ['tests', 'test', '.'], pattern: /^\s*(?:import unittest\b|from unittest\b)/m },
+    command: 'python -m unittest discover'
+  },
+  { name: 'go-test', language: 'go', files: ['go.mod'], command: 'go test ./...' },
+  { name: 'testify', language: 'go', ecosystem: 'go', dependencies: ['github.com/stretchr/testify'] },
+  { name: 'cargo-test', language: 'rust', files: ['Cargo.toml'], command: 'cargo test' },
+  { name: 'cargo-nextest', language: 'rust', files: ['.config/nextest.toml'], command: 'cargo nextest run' },
+  {
+    name: 'junit', language: 'java',
+    contains: {
+      'pom.xml': /<artifactId>junit(?:-jupiter[\w-]*|-bom)?<\/artifactId>/,
+      'build.gradle': /junit/i,
+      'build.gradle.kts': /junit/i
+    }
+  },
+  {
+    name: 'rspec', language: 'ruby', ecosystem: 'ruby', dependencies: ['rspec', 'rspec-core', 'rspec-rails'],
+    files: ['.rspec', 'spec/spec_helper.rb'],
+    command: 'bundle exec rspec'
+  }
+];
+
+/**
+ * Test invocation sources checked before framework defaults
+ * The npm default `test` script ("no test specified") is ignored.
+ */
+const TEST_COMMAND_CONFIGS = {
+  makeTargets: ['test', 'tests', 'check'],
+  pythonRunners: [
+    { file: 'poetry.lock', prefix: 'poetry run' },
+    { file: 'uv.lock', prefix: 'uv run' },
+    { file: 'Pipfile.lock', prefix: 'pipenv run' }
+  ],
+  placeholderScript: /no test specified/
+};
+
+/**
+ * Linter and formatter detection configuration
+ * `language` uses slop-analyzer language keys. `recommended` and `defaults`
+ * list the rules (of those slop patterns map to) enabled by a preset or with
+ * no explicit selection; `select`/`ignore` are code-prefix defaults.
+ */
+const LINTER_CONFIGS = {
+  eslint: {
+    language: 'js', lints: true,
+    files: [
+      'eslint.config.js', 'eslint.config.mjs', 'eslint.config.cjs', 'eslint.config.ts',
+      '.eslintrc.json', '.eslintrc', '.eslintrc.js', '.eslintrc.cjs', '.eslintrc.yml', '.eslintrc.yaml'
+    ],
+    packageKey: 'eslintConfig',
+    recommended: ['no-empty', 'no-unused-vars', 'no-unreachable', 'no-debugger', 'no-mixed-spaces-and-tabs']
+  },
+  biome: {
+    language: 'js', lints: true, formats: true,
+    files: ['biome.json', 'biome.jsonc'],
+    recommended: ['correctness/noUnreachable', 'suspicious/noDebugger']
+  },
+  prettier: {
+    language: 'js', formats: true,
+    files: [
+      '.prettierrc', '.prettierrc.json', '.prettierrc.json5', '.prettierrc.yml', '.prettierrc.yaml', '.prettierrc.toml',
+      '.prettierrc.js', '.prettierrc.cjs', '.prettierrc.mjs', 'prettier.config.js', 'prettier.config.cjs', 'prettier.config.mjs', 'prettier.config.ts'
+    ],
+    packageKey: 'prettier'
+  },
+  ruff: {
+    language: 'python', lints: true,
+    files: ['ruff.toml', '.ruff.toml'],
+    pyprojectTable: 'tool.ruff',
+    select: ['E4', 'E7', 'E9', 'F'],
+    ignore: []
+  },
+  black: {
+    language: 'python', formats: true,
+    files: [],
+    pyprojectTable: 'tool.black'
+  },
+  flake8: {
+    language: 'python', lints: true,
+    files: ['.flake8'],
+    iniSections: { 'setup.cfg': 'flake8', 'tox.ini': 'flake8' },
+    select: ['E', 'F', 'W', 'C90'],
+    ignore: ['E121', 'E123', 'E126', 'E226', 'E24', 'E704', 'W503', 'W504']
+  },
+  'golangci-lint': {
+    language: 'go', lints: true,
+    files: ['.golangci.yml', '.golangci.yaml', '.golangci.toml', '.golangci.json'],
+    defaults: ['errcheck', 'gosimple', 'govet', 'ineffassign', 'staticcheck', 'unused']
+  },
+  clippy: {
+    language: 'rust', lints: true,
+    files: ['clippy.toml', '.clippy.toml'],
+    // Lint -> group, for lints that are only on when their group is enabled
+    groups: {
+      dbg_macro: 'restriction', print_stdout: 'restriction', print_stderr: 'restriction', todo: 'restriction',
+      unwrap_used: 'restriction', expect_used: 'restriction'
+    }
+  },
+  rustfmt: {
+    language: 'rust', formats: true,
+    files: ['rustfmt.toml', '.rustfmt.toml']
+  }
+};
+
+/**
+ * pre-commit hook ids that run a linter or formatter
+ */
+const PRE_COMMIT_HOOKS = {
+  'eslint': 'eslint',
+  'prettier': 'prettier',
+  'biome-check': 'biome',
+  'biome-lint': 'biome',
+  'ruff': 'ruff',
+  'ruff-format': 'ruff',
+  'black': 'black',
+  'flake8': 'flake8',
+  'golangci-lint': 'golangci-lint',
+  'clippy': 'clippy',
+  'fmt': 'rustfmt',
+  'rustfmt': 'rustfmt'
+};
+
+/**
+ * Secrets-management and env tooling detection configuration
+ * Tools are detected by `files`, dependencies per ecosystem, or `commands`
+ * found in scripts, Makefiles, and CI pipeline files. Env files matching
+ * `envTemplate` are safe to commit; other env files and `neverCommitFiles`
+ * must stay out of git.
+ */
+const SECRETS_CONFIGS = {
+  envFile: /^\.env(?:\..+)?$/,
+  envTemplate: /\.(?:example|sample|template|dist|defaults)$/i,
+  // Encrypted-by-SOPS candidates; confirmed by the `sops` metadata block
+  sopsFile: /\.(?:enc|sops)\.(?:ya?ml|json|env)$|^secrets?\.(?:ya?ml|json)$/i,
+  sopsMetadata: /^sops:\s*$|"sops"\s*:\s*\{/m,
+  neverCommitFiles: ['.vault-token', '.envrc.local'],
+  scanDepth: 2,
+  commandFiles: ['package.json', 'Makefile', 'Justfile', 'Procfile', '.gitlab-ci.yml', 'Dockerfile', 'docker-compose.yml', 'compose.yaml'],
+  commandDirs: ['.github/workflows', '.circleci', '.buildkite'],
+  tools: [
+    { name: 'direnv', files: ['.envrc'] },
+    {
+      name: 'doppler',
+      files: ['doppler.yaml', '.doppler.yaml'],
+      commands: /\bdoppler\s+(?:run|secrets|setup)\b|dopplerhq\/(?:cli|secrets-fetch)-action/
+    },
+    {
+      name: 'vault',
+      files: ['vault.hcl', 'vault-agent.hcl'],
+      dependencies: { npm: ['node-vault'], python: ['hvac'], go: ['github.com/hashicorp/vault/api'], ruby: ['vault'] },
+      commands: /\bvault\s+(?:kv|read|login|agent)\b|hashicorp\/vault-action/
+    },
+    {
+      name: 'sops',
+      files: ['.sops.yaml', '.sops.yml'],
+      commands: /\bsops\s+(?:-d|--decrypt|exec-env|exec-file)\b/
+    },
+    {
+      name: 'aws-secrets-manager',
+      dependencies: {
+        npm: ['@aws-sdk/client-secrets-manager'],
+        python: ['aws-secretsmanager-caching'],
+        go: ['github.com/aws/aws-sdk-go-v2/service/secretsmanager', 'github.com/aws/aws-sdk-go/service/secretsmanager'],
+        rust: ['aws-sdk-secretsmanager']
+      },
+      commands: /\baws\s+secretsmanager\b|aws-actions\/aws-secretsmanager-get-secrets/
+    }
+  ]
+};
+
+/**
+ * Runtime version detection configuration
+ * `toolVersions` maps asdf/mise plugin names in `.tool-versions` to runtimes;
+ * `nodeCodenames` resolves `lts/<codename>` aliases in `.nvmrc`.
+ */
+const RUNTIME_CONFIGS = {
+  toolVersions: {
+    nodejs: 'node',
+    node: 'node',
+    python: 'python',
+    golang: 'go',
+    go: 'go',
+    rust: 'rust',
+    ruby: 'ruby',
+    java: 'java'
+  },
+  nodeCodenames: {
+    argon: '4',
+    boron: '6',
+    carbon: '8',
+    dubnium: '10',
+    erbium: '12',
+    fermium: '14',
+    gallium: '16',
+    hydrogen: '18',
+    iron: '20',
+    jod: '22'
+  }
+};
+
+/**
+ * Framework version detection configuration
+ * Frameworks (review pattern keys) and the package that carries their version;
+ * lockfiles are read in order and the first resolved version wins.
+ */
+const FRAMEWORK_VERSION_CONFIGS = {
+  packages: [
+    { framework: 'react', ecosystem: 'npm', name: 'react' },
+    { framework: 'nextjs', ecosystem: 'npm', name: 'next' },
+    { framework: 'vue', ecosystem: 'npm', name: 'vue' },
+    { framework: 'nuxt', ecosystem: 'npm', name: 'nuxt' },
+    { framework: 'svelte', ecosystem: 'npm', name: 'svelte' },
+    { framework: 'sveltekit', ecosystem: 'npm', name: '@sveltejs/kit' },
+    { framework: 'angular', ecosystem: 'npm', name: '@angular/core' },
+    { framework: 'express', ecosystem: 'npm', name: 'express' },
+    { framework: 'django', ecosystem: 'python', name: 'django' },
+    { framework: 'fastapi', ecosystem: 'python', name: 'fastapi' },
+    { framework: 'pydantic', ecosystem: 'python', name: 'pydantic' },
+    { framework: 'rails', ecosystem: 'ruby', name: 'rails' }
+  ],
+  lockfiles: {
+    npm: ['package-lock.json', 'npm-shrinkwrap.json', 'yarn.lock', 'pnpm-lock.yaml'],
+    python: ['poetry.lock', 'uv.lock', 'Pipfile.lock'],
+    ruby: ['Gemfile.lock']
+  },
+  // Lockfiles in large monorepos run to tens of megabytes
+  maxLockfileBytes: 32 * 1024 * 1024
+};
+
+/**
+ * Branch strategy (branching model) detection configuration
+ * `models` are the values `branching.model` can take; branch lists are in
+ * priority order. `tagTrigger` and `environment` match CI pipeline content:
+ * tag-triggered deploys (GitHub `on.push.tags`, GitLab `$CI_COMMIT_TAG` or
+ * `only: tags`) and deployment environments (`environment: production`).
  */
 const BRANCH_STRATEGIES = {
-  gitflow: ['develop', 'main', 'master'],
-  githubflow: ['main'],
-  trunkbased: ['main', 'trunk']
+  models: ['github-flow', 'gitflow', 'production-branch', 'release-branch', 'tag-based', 'trunk-based'],
+  trunkBranches: ['main', 'master', 'trunk'],
+  developBranches: ['develop', 'development'],
+  productionBranches: ['stable', 'production', 'prod'],
+  releaseBranch: /^release[/-]/,
+  hotfixBranch: /^hotfix[/-]/,
+  releaseTag: /^v?\d+\.\d+(\.\d+)?([-+][\w.]+)?$/,
+  maxListed: 10,
+  tagTrigger: /^\s*tags:\s*(\[|$)|\$CI_COMMIT_TAG\b|^\s*only:\s*\[?\s*-?\s*tags\b|refs\/tags\//m,
+  environment: /^\s*environment:\s*(?:name:\s*)?["']?([\w-]+)["']?\s*$|^\s*environment:\s*\n\s+name:\s*["']?([\w-]+)/gm
 };
 
 /**
@@ -85,9 +639,24 @@ const MAIN_BRANCH_CANDIDATES = ['main', 'master', 'trunk', 'develop'];
 
 module.exports = {
   CI_CONFIGS,
+  CI_PIPELINE_SOURCES,
   DEPLOYMENT_CONFIGS,
   PROJECT_TYPE_CONFIGS,
   PACKAGE_MANAGER_CONFIGS,
+  WORKSPACE_TOOL_CONFIGS,
+  CONTAINER_CONFIGS,
+  IAC_CONFIGS,
+  SERVERLESS_CONFIGS,
+  CONFIDENCE_CONFIGS,
+  DATABASE_CONFIGS,
+  ORM_CONFIGS,
+  TEST_FRAMEWORK_CONFIGS,
+  TEST_COMMAND_CONFIGS,
+  LINTER_CONFIGS,
+  PRE_COMMIT_HOOKS,
+  SECRETS_CONFIGS,
+  RUNTIME_CONFIGS,
+  FRAMEWORK_VERSION_CONFIGS,
   BRANCH_STRATEGIES,
   MAIN_BRANCH_CANDIDATES
 };
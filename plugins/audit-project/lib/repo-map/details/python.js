@@ -0,0 +1,206 @@
+/**
+ * Python symbol details (decorators, class membership, base classes)
+ *
+ * @module lib/repo-map/details/python
+ */
+
+'use strict';
+
+const { splitArgs } = require('./utils');
+
+const MAX_DECORATOR_LENGTH = 200;
+
+/**
+ * Count leading indentation (tabs count as 4 spaces)
+ * @param {string} line - Source line
+ * @returns {number}
+ */
+function getIndent(line) {
+  let indent = 0;
+  for (const ch of line) {
+    if (ch === ' ') indent++;
+    else if (ch === '\t') indent += 4;
+    else break;
+  }
+  return indent;
+}
+
+/**
+ * Read a possibly multi-line bracketed expression starting at a line
+ * @param {string[]} lines - Source lines
+ * @param {number} start - Start index
+ * @returns {{text: string, end: number}}
+ */
+function readBalanced(lines, start) {
+  let text = lines[start].trim();
+  let depth = bracketDepth(text);
+  let end = start;
+  while (depth > 0 && end + 1 < lines.length) {
+    end++;
+    const next = lines[end].trim();
+    text += ' ' + next;
+    depth += bracketDepth(next);
+  }
+  return { text: text.replace(/\s+/g, ' ').replace(/([([{]) /g, '$1').replace(/ ([)\]}])/g, '$1'), end };
+}
+
+function bracketDepth(text) {
+  let depth = 0;
+  for (const ch of text.replace(/(['"])(?:\\.|(?!\1).)*\1/g, '')) {
+    if (ch === '(' || ch === '[' || ch === '{') depth++;
+    else if (ch === ')' || ch === ']' || ch === '}') depth--;
+  }
+  return depth;
+}
+
+/**
+ * Parse Python definitions with their decorators and enclosing classes
+ * @param {string} content - File content
+ * @returns {{classes: Object[], functions: Object[]}}
+ */
+function parsePythonDefinitions(content) {
+  const lines = content.split(/\r?\n/);
+  const classes = [];
+  const functions = [];
+  const scope = []; // stack of { indent, cls } (cls is null for function bodies)
+  let pendingDecorators = [];
+  let docstringQuote = null;
+
+  for (let i = 0; i < lines.length; i++) {
+    const line = lines[i];
+    const trimmed = line.trim();
+
+    if (docstringQuote) {
+      if (trimmed.includes(docstringQuote)) docstringQuote = null;
+      continue;
+    }
+    if (!trimmed || trimmed.startsWith('#')) continue;
+
+    const quote = trimmed.match(/^[rbuRBU]{0,2}("""|''')/);
+    if (quote && trimmed.split(quote[1]).length === 2) {
+      docstringQuote = quote[1];
+      continue;
+    }
+
+    const indent = getIndent(line);
+    while (scope.length > 0 && indent <= scope[scope.length - 1].indent) {
+      scope.pop();
+    }
+    const top = scope.length > 0 ? scope[scope.length - 1] : null;
+    const parent = top ? top.cls : null;
+    const nested = scope.some(entry => entry.cls === null);
+
+    if (trimmed.startsWith('@')) {
+      const { text, end } = readBalanced(lines, i);
+      pendingDecorators.push(text.slice(1).trim().slice(0, MAX_DECORATOR_LENGTH));
+      i = end;
+      continue;
+    }
+
+    const classMatch = trimmed.match(/^class\s+([A-Za-z_]\w*)\s*(\(|:)/);
+    if (classMatch) {
+      const { text, end } = readBalanced(lines, i);
+      const basesMatch = text.match(/^class\s+\w+\s*\((.*)\)\s*:/);
+      const bases = basesMatch
+        ? splitArgs(basesMatch[1]).filter(base => base && !base.includes('='))
+        : [];
+      const cls = {
+        name: classMatch[1],
+        line: i + 1,
+        decorators: pendingDecorators,
+        bases,
+        parent: parent ? parent.name : null,
+        nested,
+        methods: []
+      };
+      classes.push(cls);
+      scope.push({ indent, cls });
+      pendingDecorators = [];
+      i = end;
+      continue;
+    }
+
+    const defMatch = trimmed.match(/^(async\s+)?def\s+([A-Za-z_]\w*)\s*\(/);
+    if (defMatch) {
+      const { end } = readBalanced(lines, i);
+      const def = {
+        name: defMatch[2],
+        line: i + 1,
+        async: Boolean(defMatch[1]),
+        decorators: pendingDecorators,
+        parent: parent ? parent.name : null,
+        nested: nested && !parent
+      };
+      functions.push(def);
+      if (parent) parent.methods.push(def);
+      pendingDecorators = [];
+      scope.push({ indent, cls: null });
+      i = end;
+      continue;
+    }
+
+    pendingDecorators = [];
+  }
+
+  return { classes, functions };
+}
+
+/**
+ * Determine whether a decorator list marks a dataclass
+ * @param {string[]} decorators - Decorator expressions
+ * @returns {boolean}
+ */
+function isDataclass(decorators) {
+  return decorators.some(dec => /^(?:dataclasses\.)?dataclass\b|^(?:attr|attrs)\.(?:s|define|frozen)\b|^define\b/.test(dec));
+}
+
+/**
+ * Attach decorators, bases, and methods to Python symbols
+ * Methods are moved off the module-level function list onto their class.
+ * @param {string} content - File content
+ * @param {Object} symbolMaps - Symbol maps
+ */
+function applyPythonDetails(content, symbolMaps) {
+  const { classes, functions } = parsePythonDefinitions(content);
+  const functionMap = symbolMaps.functions;
+  const classMap = symbolMaps.classes;
+
+  if (functionMap) {
+    for (const [name, entry] of Array.from(functionMap.entries())) {
+      const moduleDef = functions.find(def => def.name === name && !def.parent && !def.nested);
+      if (!moduleDef) {
+        if (functions.some(def => def.name === name)) {
+          functionMap.delete(name);
+        }
+        continue;
+      }
+      entry.line = moduleDef.line;
+      if (moduleDef.async) entry.async = true;
+      if (moduleDef.decorators.length > 0) entry.decorators = moduleDef.decorators;
+    }
+  }
+
+  if (classMap) {
+    for (const entry of classMap.values()) {
+      const cls = classes.find(c => c.name === entry.name && c.line === entry.line)
+        || classes.find(c => c.name === entry.name);
+      if (!cls) continue;
+
+      if (cls.bases.length > 0) entry.bases = cls.bases;
+      if (cls.decorators.length > 0) entry.decorators = cls.decorators;
+      if (cls.parent) entry.parent = cls.parent;
+      if (isDataclass(cls.decorators)) entry.kind = 'dataclass';
+      entry.methods = cls.methods.map(method => {
+        const item = { name: method.name, line: method.line };
+        if (method.async) item.async = true;
+        if (method.decorators.length > 0) item.decorators = method.decorators;
+        return item;
+      });
+    }
+  }
+}
+
+module.exports = {
+  applyPythonDetails,
+  parsePythonDefinitions
+};
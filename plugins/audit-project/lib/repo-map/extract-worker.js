@@ -0,0 +1,20 @@
+/**
+ * Worker thread entry for parallel repo-map extraction
+ *
+ * Receives one shard via `workerData` and posts back `{results}` or `{error}`.
+ *
+ * @module lib/repo-map/extract-worker
+ */
+
+'use strict';
+
+const { parentPort, workerData } = require('worker_threads');
+
+const runner = require('./runner');
+
+try {
+  const { cmd, basePath, language, entries } = workerData;
+  parentPort.postMessage({ results: runner.extractFiles(cmd, basePath, language, entries) });
+} catch (err) {
+  parentPort.postMessage({ error: err.message });
+}
@@ -0,0 +1,36 @@
+/**
+ * C++ query patterns for ast-grep
+ */
+
+'use strict';
+
+const c = require('./c');
+
+module.exports = {
+  exports: [],
+  functions: [
+    ...c.functions,
+    { pattern: '$RET $CLASS::$NAME($$$) { $$$ }', kind: 'method', nameTemplate: '$CLASS::$NAME', nameVar: 'NAME' },
+    { pattern: '$CLASS::$NAME($$$) { $$$ }', kind: 'constructor', nameTemplate: '$CLASS::$NAME', nameVar: 'NAME' },
+    { pattern: 'template <$$$> $RET $NAME($$$) { $$$ }', nameVar: 'NAME' },
+    { pattern: 'auto $NAME($$$) -> $RET { $$$ }', nameVar: 'NAME' }
+  ],
+  classes: [
+    { pattern: 'class $NAME { $$$ };', nameVar: 'NAME' },
+    { pattern: 'class $NAME : $$$ { $$$ };', nameVar: 'NAME' },
+    { pattern: 'class $NAME final { $$$ };', nameVar: 'NAME' },
+    { pattern: 'template <$$$> class $NAME { $$$ };', nameVar: 'NAME' },
+    { pattern: 'template <$$$> struct $NAME { $$$ };', kind: 'struct', nameVar: 'NAME' }
+  ],
+  types: [
+    ...c.types,
+    { pattern: 'enum class $NAME { $$$ };', kind: 'enum', nameVar: 'NAME' },
+    { pattern: 'using $NAME = $$$;', kind: 'alias', nameVar: 'NAME' },
+    { pattern: 'namespace $NAME { $$$ }', kind: 'namespace', nameVar: 'NAME' }
+  ],
+  constants: [
+    ...c.constants,
+    { pattern: 'constexpr $TYPE $NAME = $$$;', nameVar: 'NAME' }
+  ],
+  imports: c.imports
+};
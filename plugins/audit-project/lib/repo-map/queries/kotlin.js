@@ -0,0 +1,47 @@
+/**
+ * Kotlin query patterns for ast-grep
+ */
+
+'use strict';
+
+module.exports = {
+  exports: [],
+  functions: [
+    { pattern: 'fun $NAME($$$) { $$$ }', nameVar: 'NAME' },
+    { pattern: 'fun $NAME($$$): $RET { $$$ }', nameVar: 'NAME' },
+    { pattern: 'fun $NAME($$$) = $$$', nameVar: 'NAME' },
+    { pattern: 'fun $NAME($$$): $RET = $$$', nameVar: 'NAME' },
+    { pattern: 'suspend fun $NAME($$$) { $$$ }', nameVar: 'NAME' },
+    { pattern: 'suspend fun $NAME($$$): $RET { $$$ }', nameVar: 'NAME' },
+    { pattern: 'fun $RECV.$NAME($$$) { $$$ }', nameVar: 'NAME' },
+    { pattern: 'fun $RECV.$NAME($$$): $RET { $$$ }', nameVar: 'NAME' },
+    { pattern: 'fun $RECV.$NAME($$$): $RET = $$$', nameVar: 'NAME' }
+  ],
+  classes: [
+    { pattern: 'class $NAME { $$$ }', nameVar: 'NAME' },
+    { pattern: 'class $NAME($$$)', nameVar: 'NAME' },
+    { pattern: 'class $NAME($$$) { $$$ }', nameVar: 'NAME' },
+    { pattern: 'class $NAME($$$) : $$$ { $$$ }', nameVar: 'NAME' },
+    { pattern: 'class $NAME : $$$ { $$$ }', nameVar: 'NAME' },
+    { pattern: 'data class $NAME($$$)', nameVar: 'NAME' },
+    { pattern: 'data class $NAME($$$) { $$$ }', nameVar: 'NAME' },
+    { pattern: 'sealed class $NAME { $$$ }', nameVar: 'NAME' },
+    { pattern: 'abstract class $NAME { $$$ }', nameVar: 'NAME' },
+    { pattern: 'enum class $NAME { $$$ }', nameVar: 'NAME' },
+    { pattern: 'interface $NAME { $$$ }', nameVar: 'NAME' },
+    { pattern: 'sealed interface $NAME { $$$ }', nameVar: 'NAME' },
+    { pattern: 'object $NAME { $$$ }', nameVar: 'NAME' },
+    { pattern: 'object $NAME : $$$ { $$$ }', nameVar: 'NAME' },
+    { pattern: 'annotation class $NAME', nameVar: 'NAME' }
+  ],
+  types: [
+    { pattern: 'typealias $NAME = $$$', nameVar: 'NAME' }
+  ],
+  constants: [
+    { pattern: 'const val $NAME = $$$', nameVar: 'NAME' },
+    { pattern: 'const val $NAME: $TYPE = $$$', nameVar: 'NAME' }
+  ],
+  imports: [
+    { pattern: 'import $SOURCE', sourceVar: 'SOURCE', kind: 'import' }
+  ]
+};
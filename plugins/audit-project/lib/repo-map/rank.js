@@ -0,0 +1,189 @@
+/**
+ * Symbol importance ranking for token-budgeted maps
+ *
+ * Runs PageRank over the file graph (resolved imports plus call-graph edges),
+ * then spreads each caller's rank onto the symbols it calls. Symbols nobody
+ * references keep a share of their file's rank, weighted toward exports.
+ *
+ * @module lib/repo-map/rank
+ */
+
+'use strict';
+
+const { createFileIndex, resolveImport } = require('./imports');
+
+const DAMPING = 0.85;
+const MAX_ITERATIONS = 50;
+const TOLERANCE = 1e-6;
+const UNEXPORTED_WEIGHT = 0.1;
+const SYMBOL_CATEGORIES = ['functions', 'classes', 'types', 'constants'];
+
+/**
+ * Build weighted file edges from imports and the call graph
+ * @param {Object} map - Repo map
+ * @returns {Map<string, Map<string, number>>} - source -> target -> weight
+ */
+function buildFileEdges(map) {
+  const edges = new Map();
+  const addEdge = (from, to, weight) => {
+    if (from === to || !map.files[to]) return;
+    if (!edges.has(from)) edges.set(from, new Map());
+    const targets = edges.get(from);
+    targets.set(to, (targets.get(to) || 0) + weight);
+  };
+
+  const index = createFileIndex(Object.keys(map.files));
+  for (const [file, fileData] of Object.entries(map.files)) {
+    for (const imp of fileData.imports || []) {
+      for (const target of resolveImport(file, imp.source, fileData.language, index)) {
+        addEdge(file, target, 1);
+      }
+    }
+  }
+
+  for (const [target, symbols] of Object.entries(map.callGraph || {})) {
+    for (const callers of Object.values(symbols)) {
+      for (const caller of callers) addEdge(caller.file, target, 1);
+    }
+  }
+  return edges;
+}
+
+/**
+ * Compute PageRank over repository files
+ * @param {Object} map - Repo map
+ * @param {Object} [options]
+ * @param {string[]} [options.focus] - Files to bias the random walk toward (e.g. a diff)
+ * @returns {Map<string, number>} - file -> rank (sums to 1)
+ */
+function rankFiles(map, options = {}) {
+  const files = Object.keys((map && map.files) || {});
+  const ranks = new Map();
+  if (files.length === 0) return ranks;
+
+  const edges = buildFileEdges(map);
+  const focus = (options.focus || []).filter(file => map.files[file]);
+  const teleport = new Map(files.map(file => [file, focus.length > 0 ? 0 : 1 / files.length]));
+  for (const file of focus) teleport.set(file, 1 / focus.length);
+
+  const outWeight = new Map();
+  for (const [from, targets] of edges) {
+    let total = 0;
+    for (const weight of targets.values()) total += weight;
+    outWeight.set(from, total);
+  }
+
+  for (const file of files) ranks.set(file, 1 / files.length);
+
+  for (let iteration = 0; iteration < MAX_ITERATIONS; iteration++) {
+    let dangling = 0;
+    for (const file of files) {
+      if (!outWeight.has(file)) dangling += ranks.get(file);
+    }
+
+    const next = new Map();
+    for (const file of files) {
+      next.set(file, (1 - DAMPING + DAMPING * dangling) * teleport.get(file));
+    }
+    for (const [from, targets] of edges) {
+      const share = DAMPING * ranks.get(from) / outWeight.get(from);
+      for (const [to, weight] of targets) next.set(to, next.get(to) + share * weight);
+    }
+
+    let delta = 0;
+    for (const file of files) delta += Math.abs(next.get(file) - ranks.get(file));
+    for (const file of files) ranks.set(file, next.get(file));
+    if (delta < TOLERANCE) break;
+  }
+
+  return ranks;
+}
+
+/**
+ * Rank every symbol in the map
+ * @param {Object} map - Repo map
+ * @param {Object} [options] - Options for `rankFiles`
+ * @returns {Array<{file: string, name: string, category: string, kind: string, line: number, exported: boolean, references: number, score: number}>}
+ */
+function rankSymbols(map, options = {}) {
+  const fileRanks = rankFiles(map, options);
+  const outgoing = new Map();
+  for (const symbols of Object.values(map.callGraph || {})) {
+    for (const callers of Object.values(symbols)) {
+      for (const caller of callers) outgoing.set(caller.file, (outgoing.get(caller.file) || 0) + 1);
+    }
+  }
+
+  const ranked = [];
+  for (const [file, fileData] of Object.entries((map && map.files) || {})) {
+    const entries = SYMBOL_CATEGORIES.flatMap(category => (fileData.symbols?.[category] || [])
+      .filter(entry => !entry.declaredIn)
+      .map(entry => ({ category, entry })));
+    if (entries.length === 0) continue;
+
+    const fileRank = fileRanks.get(file) || 0;
+    const weights = entries.map(({ entry }) => (entry.exported === false ? UNEXPORTED_WEIGHT : 1));
+    const totalWeight = weights.reduce((sum, weight) => sum + weight, 0);
+    const called = (map.callGraph || {})[file] || {};
+
+    entries.forEach(({ category, entry }, i) => {
+      const callers = called[entry.name] || [];
+      let score = fileRank * weights[i] / totalWeight;
+      for (const caller of callers) {
+        score += (fileRanks.get(caller.file) || 0) / outgoing.get(caller.file);
+      }
+      ranked.push({
+        file,
+        name: entry.name,
+        category,
+        kind: entry.kind,
+        line: entry.line,
+        exported: entry.exported !== false,
+        references: callers.length,
+        score
+      });
+    });
+  }
+
+  ranked.sort((a, b) => b.score - a.score
+    || a.file.localeCompare(b.file)
+    || (a.line || 0) - (b.line || 0));
+  return ranked;
+}
+
+/**
+ * Copy a map keeping only its highest-ranked symbols
+ * Files left without symbols are dropped; exports follow the kept symbols.
+ * @param {Object} map - Repo map
+ * @param {number} limit - Number of symbols to keep
+ * @param {Object} [options] - Options for `rankFiles`
+ * @returns {Object}
+ */
+function truncateMap(map, limit, options = {}) {
+  const kept = new Map();
+  for (const item of rankSymbols(map, options).slice(0, Math.max(0, limit))) {
+    if (!kept.has(item.file)) kept.set(item.file, new Set());
+    kept.get(item.file).add(`${item.category}:${item.name}`);
+  }
+
+  const files = {};
+  for (const [file, fileData] of Object.entries(map.files)) {
+    const keys = kept.get(file);
+    if (!keys) continue;
+    const symbols = {};
+    for (const category of SYMBOL_CATEGORIES) {
+      symbols[category] = (fileData.symbols?.[category] || []).filter(entry => keys.has(`${category}:${entry.name}`));
+    }
+    const names = new Set(SYMBOL_CATEGORIES.flatMap(category => symbols[category].map(entry => entry.name)));
+    symbols.exports = (fileData.symbols?.exports || []).filter(entry => names.has(entry.name));
+    files[file] = { ...fileData, symbols };
+  }
+
+  return { ...map, files, truncated: { limit, totalSymbols: map.stats?.totalSymbols || 0 } };
+}
+
+module.exports = {
+  rankFiles,
+  rankSymbols,
+  truncateMap
+};
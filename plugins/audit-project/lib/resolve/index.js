@@ -0,0 +1,608 @@
+#!/usr/bin/env node
+/**
+ * Merge Conflict Resolution
+ *
+ * Lists the conflicted files of an in-progress merge, rebase, cherry-pick,
+ * or revert and sorts each into one category:
+ *
+ * - `lockfile`: regenerated with the package manager once its manifest is clean
+ * - `generated`: regenerated with the project's generator once no logic
+ *   conflicts remain (`resolve.generate` in the project config, else a
+ *   `generate` or `codegen` package script)
+ * - `imports`: every hunk is import lines; both sides are kept and re-sorted
+ * - `whitespace`: the sides differ only in whitespace; ours is kept
+ * - `logic`: everything else, returned with both sides, the base (with
+ *   `merge.conflictStyle diff3`), the enclosing symbol, and the commits on
+ *   each side that touched the file
+ *
+ * Mechanical resolutions are written through a journal transaction and
+ * staged, so a run can be previewed with a dry run and rolled back.
+ *
+ * Usage: node lib/resolve/index.js [--apply] [--dry-run]
+ * Output: JSON with the operation, the classified conflicts, and what --apply resolved
+ *
+ * @module lib/resolve
+ */
+
+const fs = require('fs');
+const path = require('path');
+
+const { loadConfig } = require('../config');
+const { resolveGitDirs } = require('../utils/git');
+const { enclosingSymbol } = require('../migrate');
+const scanLimits = require('../utils/scan-limits');
+const { git } = require('../utils/exec');
+
+const CONFIG_KEY = 'resolve';
+
+/**
+ * Lockfiles, the manifest they are derived from, and how to regenerate them
+ * `command` receives the lockfile's directory (`.` at the root) and returns
+ * the argv, or null when the tool cannot target that directory.
+ */
+const LOCKFILES = {
+  'package-lock.json': { manifest: 'package.json', command: dir => ['npm', 'install', '--package-lock-only', '--ignore-scripts', ...(dir === '.' ? [] : ['--prefix', dir])] },
+  'npm-shrinkwrap.json': { manifest: 'package.json', command: dir => ['npm', 'install', '--package-lock-only', '--ignore-scripts', ...(dir === '.' ? [] : ['--prefix', dir])] },
+  'pnpm-lock.yaml': { manifest: 'package.json', command: dir => ['pnpm', 'install', '--lockfile-only', '--ignore-scripts', '--dir', dir] },
+  'yarn.lock': { manifest: 'package.json', command: dir => (dir === '.' ? ['yarn', 'install'] : ['yarn', '--cwd', dir, 'install']) },
+  'bun.lock': { manifest: 'package.json', command: dir => ['bun', 'install', '--lockfile-only', '--cwd', dir] },
+  'Cargo.lock': { manifest: 'Cargo.toml', command: dir => ['cargo', 'update', '--workspace', '--manifest-path', path.posix.join(dir, 'Cargo.toml')] },
+  'go.sum': { manifest: 'go.mod', command: dir => ['go', '-C', dir, 'mod', 'tidy'] },
+  'poetry.lock': { manifest: 'pyproject.toml', command: dir => ['poetry', 'lock', '--directory', dir] },
+  'uv.lock': { manifest: 'pyproject.toml', command: dir => ['uv', 'lock', '--directory', dir] },
+  'Pipfile.lock': { manifest: 'Pipfile', command: dir => (dir === '.' ? ['pipenv', 'lock'] : null) },
+  'Gemfile.lock': { manifest: 'Gemfile', command: dir => (dir === '.' ? ['bundle', 'lock'] : null) },
+  'composer.lock': { manifest: 'composer.json', command: dir => ['composer', 'update', '--lock', '--working-dir', dir] }
+};
+
+/**
+ * Single-line import statements per file extension
+ */
+const IMPORT_LINES = {
+  js: /^\s*(?:import\s+(?:[\w*${},\s]+\s+from\s+)?['"][^'"]+['"]|export\s+(?:\*|\{[^}]*\})\s+from\s+['"][^'"]+['"]|(?:const|let|var)\s+[\w${},:\s]+=\s*require\(['"][^'"]+['"]\)(?:\.\w+)*);?\s*$/,
+  py: /^\s*(?:from\s+[\w.]+\s+)?import\s+[\w.*,\s]+(?:\s+as\s+\w+)?\s*$/,
+  go: /^\s*(?:import\s+)?(?:[\w.]+\s+)?"[^"]+"\s*$/,
+  rs: /^\s*(?:pub(?:\([\w\s]+\))?\s+)?use\s+[^;]+;\s*$/,
+  java: /^\s*import\s+(?:static\s+)?[\w.*]+;?\s*$/,
+  cs: /^\s*(?:global\s+)?using\s+(?:static\s+)?[\w.=\s]+;\s*$/
+};
+
+const IMPORT_EXTENSIONS = {
+  js: IMPORT_LINES.js, jsx: IMPORT_LINES.js, mjs: IMPORT_LINES.js, cjs: IMPORT_LINES.js, ts: IMPORT_LINES.js, tsx: IMPORT_LINES.js, mts: IMPORT_LINES.js, cts: IMPORT_LINES.js,
+  py: IMPORT_LINES.py, pyi: IMPORT_LINES.py,
+  go: IMPORT_LINES.go,
+  rs: IMPORT_LINES.rs,
+  java: IMPORT_LINES.java, kt: IMPORT_LINES.java, scala: IMPORT_LINES.java,
+  cs: IMPORT_LINES.cs
+};
+
+/**
+ * Categories in the order they are resolved and reported
+ */
+const CATEGORIES = ['imports', 'whitespace', 'logic', 'generated', 'lockfile'];
+
+/**
+ * `git status --porcelain` codes of unmerged paths
+ */
+const UNMERGED_STATUS = {
+  UU: 'both-modified',
+  AA: 'both-added',
+  DU: 'deleted-by-us',
+  UD: 'deleted-by-them',
+  AU: 'added-by-us',
+  UA: 'added-by-them',
+  DD: 'both-deleted'
+};
+
+/**
+ * State files of in-progress operations and the ref of the side being merged in
+ */
+const OPERATIONS = [
+  { type: 'merge', file: 'MERGE_HEAD', theirs: 'MERGE_HEAD' },
+  { type: 'rebase', file: 'rebase-merge', theirs: 'REBASE_HEAD' },
+  { type: 'rebase', file: 'rebase-apply', theirs: 'REBASE_HEAD' },
+  { type: 'cherry-pick', file: 'CHERRY_PICK_HEAD', theirs: 'CHERRY_PICK_HEAD' },
+  { type: 'revert', file: 'REVERT_HEAD', theirs: 'REVERT_HEAD' }
+];
+
+const MAX_SIDE_LINES = 40;
+const MAX_SIDE_COMMITS = 5;
+
+/**
+ * Resolution settings from the project config
+ * @param {string} basePath - Repository root
+ * @returns {{generate: string[]|null, error: string|null}} `generate`: argv that regenerates generated files
+ */
+function readSettings(basePath) {
+  const { config, file, error } = loadConfig(basePath);
+  const settings = { generate: null, error };
+  const value = config[CONFIG_KEY];
+  if (error || value === undefined) return settings;
+
+  const fail = message => ({ ...settings, error: `${file}: ${CONFIG_KEY}${message}` });
+  if (!value || typeof value !== 'object' || Array.isArray(value)) return fail(' must be an object');
+  if (value.generate !== undefined) {
+    const argv = typeof value.generate === 'string' ? value.generate.trim().split(/\s+/).filter(Boolean) : value.generate;
+    if (!Array.isArray(argv) || argv.length === 0 || argv.some(arg => typeof arg !== 'string' || !arg)) {
+      return fail('.generate must be a command string or an array of arguments');
+    }
+    settings.generate = argv;
+  }
+  return settings;
+}
+
+/**
+ * Command that regenerates generated files
+ * The configured `resolve.generate`, else a `generate` or `codegen` script
+ * run with the package manager the lockfile implies.
+ * @param {string} basePath - Repository root
+ * @param {{generate: string[]|null}} settings - Result of readSettings
+ * @returns {string[]|null}
+ */
+function generatorCommand(basePath, settings) {
+  if (settings && settings.generate) return settings.generate;
+  let scripts = {};
+  try {
+    scripts = JSON.parse(fs.readFileSync(path.join(basePath, 'package.json'), 'utf8')).scripts || {};
+  } catch {
+    return null;
+  }
+  const script = ['generate', 'codegen'].find(name => typeof scripts[name] === 'string');
+  if (!script) return null;
+  const exists = file => fs.existsSync(path.join(basePath, file));
+  const manager = exists('pnpm-lock.yaml') ? 'pnpm' : exists('yarn.lock') ? 'yarn' : exists('bun.lock') ? 'bun' : 'npm';
+  return [manager, 'run', script];
+}
+
+/**
+ * The in-progress operation that left conflicts
+ * During a rebase "ours" is the branch being rebased onto and "theirs" the
+ * commit being replayed, the reverse of a merge.
+ * @param {string} basePath - Repository root
+ * @returns {{type: string, theirs: string, labels: {ours: string, theirs: string}}|null} null when nothing is in progress
+ */
+function getOperation(basePath) {
+  let dirs = null;
+  try {
+    dirs = resolveGitDirs(basePath);
+  } catch {
+    dirs = null;
+  }
+  if (!dirs) return null;
+  const operation = OPERATIONS.find(entry => fs.existsSync(path.join(dirs.gitDir, entry.file)));
+  if (!operation) return null;
+  const labels = operation.type === 'rebase'
+    ? { ours: 'upstream (rebasing onto)', theirs: 'your commit being replayed' }
+    : { ours: 'HEAD (current branch)', theirs: `${operation.theirs} (incoming)` };
+  return { type: operation.type, theirs: operation.theirs, labels };
+}
+
+/**
+ * Unmerged paths
+ * @param {string} basePath - Repository root
+ * @returns {Array<{file: string, status: string}>|null} null outside a repository
+ */
+function listConflicts(basePath) {
+  const out = git(basePath, ['status', '--porcelain=v1', '-z', '--untracked-files=no']);
+  if (out === null) return null;
+  const conflicts = [];
+  const entries = out.split('\0');
+  for (let i = 0; i < entries.length; i++) {
+    const entry = entries[i];
+    if (!entry) continue;
+    const code = entry.slice(0, 2);
+    // Renames and copies carry the source path as the next entry
+    if (/^[RC]|^.[RC]/.test(code)) i++;
+    if (UNMERGED_STATUS[code]) conflicts.push({ file: entry.slice(3), status: UNMERGED_STATUS[code] });
+  }
+  return conflicts.sort((a, b) => a.file.localeCompare(b.file));
+}
+
+/**
+ * Split a file with conflict markers into hunks
+ * @param {string} content - File content
+ * @returns {{lines: string[], hunks: Array<{line: number, end: number, oursLine: number, ours: string[], base: string[]|null, theirs: string[], labels: {ours: string, theirs: string}}>}}
+ *   `line`/`end`: 1-based marker lines; `oursLine`: where the hunk starts in our version
+ */
+function parseConflicts(content) {
+  const lines = String(content).split('\n');
+  const hunks = [];
+  let current = null;
+  let section = null;
+  let oursLine = 1;
+  lines.forEach((raw, index) => {
+    const line = raw.replace(/\r$/, '');
+    const start = /^<{7}(?: (.*))?$/.exec(line);
+    if (start && !current) {
+      current = { line: index + 1, end: null, oursLine, ours: [], base: null, theirs: [], labels: { ours: start[1] || '', theirs: '' } };
+      section = 'ours';
+      return;
+    }
+    if (current && /^\|{7}(?: .*)?$/.test(line) && section === 'ours') {
+      current.base = [];
+      section = 'base';
+      return;
+    }
+    if (current && /^={7}$/.test(line) && section !== 'theirs') {
+      section = 'theirs';
+      return;
+    }
+    const end = /^>{7}(?: (.*))?$/.exec(line);
+    if (current && end && section === 'theirs') {
+      current.end = index + 1;
+      current.labels.theirs = end[1] || '';
+      hunks.push(current);
+      oursLine += current.ours.length;
+      current = null;
+      section = null;
+      return;
+    }
+    if (current) current[section].push(raw);
+    else oursLine++;
+  });
+  return { lines, hunks };
+}
+
+/**
+ * Import pattern for a file, by extension
+ * @param {string} file - File path
+ * @returns {RegExp|null}
+ */
+function importPattern(file) {
+  return IMPORT_EXTENSIONS[path.posix.extname(file).slice(1).toLowerCase()] || null;
+}
+
+/**
+ * Merge a hunk whose sides are all import lines
+ * Lines either side added are kept and lines either side removed from the
+ * base are dropped; sorted sides stay sorted.
+ * @param {Object} hunk - Hunk from parseConflicts
+ * @param {RegExp} pattern - Import pattern for the file
+ * @returns {string[]|null} Merged lines, or null when a line is not an import
+ */
+function mergeImports(hunk, pattern) {
+  const sides = [hunk.ours, hunk.theirs, hunk.base || []];
+  if (sides.some(side => side.some(line => line.trim() && !pattern.test(line)))) return null;
+  const compare = (a, b) => (a.trim() < b.trim() ? -1 : a.trim() > b.trim() ? 1 : 0);
+  const sorted = side => side.every((line, i) => i === 0 || compare(side[i - 1], line) <= 0);
+
+  const key = line => line.trim();
+  const removed = new Set((hunk.base || []).map(key).filter(line => line && !(hunk.ours.some(o => key(o) === line) && hunk.theirs.some(t => key(t) === line))));
+  const seen = new Set();
+  const merged = [];
+  for (const line of [...hunk.ours, ...hunk.theirs]) {
+    if (!line.trim()) {
+      // Blank lines separate groups on our side only
+      if (hunk.ours.includes(line) && merged.length > 0 && merged[merged.length - 1].trim()) merged.push(line);
+      continue;
+    }
+    if (removed.has(key(line)) || seen.has(key(line))) continue;
+    seen.add(key(line));
+    merged.push(line);
+  }
+  while (merged.length > 0 && !merged[merged.length - 1].trim()) merged.pop();
+  const grouped = merged.some(line => !line.trim());
+  return !grouped && sorted(hunk.ours) && sorted(hunk.theirs) ? merged.sort(compare) : merged;
+}
+
+/**
+ * Whether a hunk's sides differ only in whitespace
+ * @param {Object} hunk - Hunk from parseConflicts
+ * @returns {boolean}
+ */
+function whitespaceOnly(hunk) {
+  const squash = side => side.join('\n').replace(/\s+/g, '');
+  return squash(hunk.ours) === squash(hunk.theirs);
+}
+
+/**
+ * Replace each hunk with its resolved lines
+ * @param {{lines: string[], hunks: Object[]}} parsed - Result of parseConflicts
+ * @param {Array<string[]>} resolved - Lines per hunk
+ * @returns {string}
+ */
+function applyHunks(parsed, resolved) {
+  const out = [];
+  let next = 0;
+  parsed.hunks.forEach((hunk, i) => {
+    out.push(...parsed.lines.slice(next, hunk.line - 1), ...resolved[i]);
+    next = hunk.end;
+  });
+  out.push(...parsed.lines.slice(next));
+  return out.join('\n');
+}
+
+/**
+ * Classify one conflicted file
+ * @param {string} basePath - Repository root
+ * @param {{file: string, status: string}} conflict - Entry from listConflicts
+ * @param {Object} [options]
+ * @param {string[]|null} [options.generate] - generatorCommand result
+ * @param {Function} [options.readFile] - `(file) => content|null` (for testing)
+ * @returns {Object} `{file, status, category, hunks, ...}`; `content` for imports and whitespace,
+ *   `manifest` and `command` for lockfiles, `command` for generated files, `reason` for logic
+ */
+function classifyConflict(basePath, conflict, options = {}) {
+  const read = options.readFile || (file => {
+    try {
+      return fs.readFileSync(path.join(basePath, file), 'utf8');
+    } catch {
+      return null;
+    }
+  });
+  const { file, status } = conflict;
+  const name = path.posix.basename(file);
+  const dir = path.posix.dirname(file);
+  const entry = { file, status, category: 'logic', hunks: [] };
+
+  if (status !== 'both-modified' && status !== 'both-added') {
+    return { ...entry, reason: `${status.replace(/-/g, ' ')}: decide whether the file stays` };
+  }
+  if (LOCKFILES[name]) {
+    const command = LOCKFILES[name].command(dir);
+    return { ...entry, category: 'lockfile', manifest: path.posix.join(dir, LOCKFILES[name].manifest), command };
+  }
+
+  const content = read(file);
+  if (content === null) return { ...entry, reason: 'file could not be read' };
+  const parsed = parseConflicts(content);
+  const hunks = parsed.hunks;
+  if (scanLimits.isGenerated(file, content) || scanLimits.MINIFIED_PATHS.test(file)) {
+    return { ...entry, category: 'generated', hunks, command: options.generate || null };
+  }
+  if (hunks.length === 0) return { ...entry, reason: 'no conflict markers; check the file and stage it' };
+
+  const pattern = importPattern(file);
+  const imports = hunks.map(hunk => (pattern ? mergeImports(hunk, pattern) : null));
+  if (hunks.every((hunk, i) => imports[i] || whitespaceOnly(hunk))) {
+    const resolved = hunks.map((hunk, i) => imports[i] || hunk.ours);
+    return {
+      ...entry,
+      category: imports.some(Boolean) ? 'imports' : 'whitespace',
+      hunks,
+      content: applyHunks(parsed, resolved)
+    };
+  }
+  // Mechanical hunks of a logic file carry their merge for the reviewer
+  const annotated = hunks.map((hunk, i) => (imports[i] ? { ...hunk, merged: imports[i] } : whitespaceOnly(hunk) ? { ...hunk, merged: hunk.ours } : hunk));
+  const logic = annotated.filter(hunk => !hunk.merged).length;
+  return { ...entry, hunks: annotated, reason: `${logic} of ${hunks.length} hunk(s) change code on both sides` };
+}
+
+/**
+ * Commits on one side that touched a file since the merge base
+ * @param {string} basePath - Repository root
+ * @param {string|null} mergeBase - Merge base commit
+ * @param {string} ref - Side ref
+ * @param {string} file - File path
+ * @returns {Array<{hash: string, subject: string}>}
+ */
+function sideCommits(basePath, mergeBase, ref, file) {
+  const range = mergeBase ? `${mergeBase}..${ref}` : ref;
+  const out = git(basePath, ['log', '--format=%h%x09%s', '-n', String(MAX_SIDE_COMMITS), range, '--', file]) || '';
+  return out.split('\n').filter(Boolean).map(line => {
+    const [hash, ...subject] = line.split('\t');
+    return { hash, subject: subject.join('\t') };
+  });
+}
+
+/**
+ * Classify every conflict of the in-progress operation
+ * @param {string} basePath - Repository root
+ * @param {Object} [options]
+ * @param {Object} [options.map] - Loaded repo map, for the symbol enclosing each logic hunk
+ * @returns {{success: boolean, operation?: Object, conflicts?: Object[], counts?: Object, error?: string}}
+ */
+function planResolution(basePath, options = {}) {
+  const conflicts = listConflicts(basePath);
+  if (conflicts === null) return { success: false, error: 'Not a git repository' };
+  const operation = getOperation(basePath);
+  const settings = readSettings(basePath);
+  const generate = generatorCommand(basePath, settings);
+
+  const mergeBase = operation ? (git(basePath, ['merge-base', 'HEAD', operation.theirs]) || '').trim() || null : null;
+  const classified = conflicts.map(conflict => {
+    const entry = classifyConflict(basePath, conflict, { generate });
+    if (entry.category !== 'logic') return entry;
+    const fileData = options.map && options.map.files ? options.map.files[entry.file] : null;
+    return {
+      ...entry,
+      hunks: entry.hunks.map(hunk => ({ ...hunk, symbol: fileData ? enclosingSymbol(fileData, hunk.oursLine) : null })),
+      commits: operation
+        ? { ours: sideCommits(basePath, mergeBase, 'HEAD', entry.file), theirs: sideCommits(basePath, mergeBase, operation.theirs, entry.file) }
+        : { ours: [], theirs: [] }
+    };
+  });
+  classified.sort((a, b) => CATEGORIES.indexOf(a.category) - CATEGORIES.indexOf(b.category) || a.file.localeCompare(b.file));
+
+  const counts = Object.fromEntries(CATEGORIES.map(category => [category, classified.filter(entry => entry.category === category).length]));
+  return { success: true, operation, conflicts: classified, counts, settingsError: settings.error };
+}
+
+/**
+ * Content of one side of a conflicted file from the index
+ * @param {string} basePath - Repository root
+ * @param {string} file - File path
+ * @param {number} stage - 2 for ours, 3 for theirs
+ * @returns {string|null}
+ */
+function stageContent(basePath, file, stage) {
+  return git(basePath, ['show', `:${stage}:${file}`]);
+}
+
+/**
+ * Resolve the mechanical conflicts of a plan
+ * Imports and whitespace are written directly. Lockfiles start from our
+ * side and are regenerated once their manifest is no longer conflicted;
+ * generated files are regenerated once no logic conflict is left. Each
+ * resolved file is staged with `git add`.
+ * @param {string} basePath - Repository root
+ * @param {Object} plan - Result of planResolution
+ * @param {Object} options
+ * @param {Object} options.journal - Journal transaction (`journal.begin(basePath, 'resolve', {dryRun})`)
+ * @returns {{resolved: Array<{file: string, category: string}>, deferred: Array<{file: string, reason: string}>, failed: Array<{file: string, error: string}>}}
+ */
+function applyResolution(basePath, plan, options) {
+  const tx = options.journal;
+  const resolved = [];
+  const deferred = [];
+  const failed = [];
+  const conflicted = new Set(plan.conflicts.map(entry => entry.file));
+  const stage = entry => {
+    if (tx.run(['git', 'add', '--', entry.file]) === null) {
+      failed.push({ file: entry.file, error: 'git add failed' });
+      return;
+    }
+    conflicted.delete(entry.file);
+    resolved.push({ file: entry.file, category: entry.category });
+  };
+  // Every entry starts from our side; one command run regenerates them all
+  const regenerate = (entries, command) => {
+    for (const entry of entries) {
+      const ours = stageContent(basePath, entry.file, 2);
+      if (ours !== null) tx.writeFile(entry.file, ours);
+      else tx.track(entry.file);
+    }
+    if (tx.run(command) === null) {
+      entries.forEach(entry => failed.push({ file: entry.file, error: `${command.join(' ')} failed` }));
+      return;
+    }
+    entries.forEach(stage);
+  };
+
+  for (const entry of plan.conflicts) {
+    if (entry.category !== 'imports' && entry.category !== 'whitespace') continue;
+    tx.writeFile(entry.file, entry.content);
+    stage(entry);
+  }
+
+  const generated = plan.conflicts.filter(entry => entry.category === 'generated');
+  const logicLeft = plan.conflicts.filter(entry => entry.category === 'logic' && conflicted.has(entry.file));
+  if (generated.length > 0) {
+    const command = generated[0].command;
+    if (!command) generated.forEach(entry => deferred.push({ file: entry.file, reason: 'no generator: set resolve.generate or add a generate script' }));
+    else if (logicLeft.length > 0) generated.forEach(entry => deferred.push({ file: entry.file, reason: `regenerate after resolving ${logicLeft.map(item => item.file).join(', ')}` }));
+    else regenerate(generated, command);
+  }
+  for (const entry of plan.conflicts.filter(item => item.category === 'lockfile')) {
+    if (!entry.command) deferred.push({ file: entry.file, reason: `no lockfile command for ${path.posix.dirname(entry.file)}; regenerate it by hand` });
+    else if (conflicted.has(entry.manifest)) deferred.push({ file: entry.file, reason: `resolve ${entry.manifest} first` });
+    else regenerate([entry], entry.command);
+  }
+
+  return { resolved, deferred, failed };
+}
+
+/**
+ * Limit a side to MAX_SIDE_LINES
+ * @param {string[]} lines - Side lines
+ * @returns {string}
+ */
+function clip(lines) {
+  if (lines.length <= MAX_SIDE_LINES) return lines.join('\n');
+  return `${lines.slice(0, MAX_SIDE_LINES).join('\n')}\n... (${lines.length - MAX_SIDE_LINES} more lines)`;
+}
+
+/**
+ * Render a plan (and optionally what was applied) as Markdown
+ * Logic conflicts get both sides, the base, and the commits behind them.
+ * @param {Object} plan - Result of planResolution
+ * @param {Object} [applied] - Result of applyResolution
+ * @returns {string}
+ */
+function renderPlan(plan, applied = null) {
+  const lines = ['## Merge Conflicts', ''];
+  const operation = plan.operation;
+  lines.push(`**Operation**: ${operation ? operation.type : 'none in progress'}`);
+  lines.push(`**Conflicts**: ${plan.conflicts.length} (${CATEGORIES.filter(category => plan.counts[category] > 0).map(category => `${plan.counts[category]} ${category}`).join(', ') || 'none'})`);
+  if (plan.settingsError) lines.push(`**Config**: ${plan.settingsError}`);
+  lines.push('');
+
+  const mechanical = plan.conflicts.filter(entry => entry.category !== 'logic');
+  if (mechanical.length > 0) {
+    lines.push('### Mechanical', '', '| File | Category | Resolution |', '|------|----------|------------|');
+    for (const entry of mechanical) {
+      const how = entry.category === 'lockfile' ? (entry.command ? `\`${entry.command.join(' ')}\`` : 'regenerate by hand')
+        : entry.category === 'generated' ? (entry.command ? `\`${entry.command.join(' ')}\`` : 'no generator configured')
+          : entry.category === 'imports' ? 'keep both sides\' imports' : 'keep ours';
+      lines.push(`| \`${entry.file}\` | ${entry.category} | ${how} |`);
+    }
+    lines.push('');
+  }
+
+  if (applied) {
+    lines.push('### Applied', '');
+    applied.resolved.forEach(entry => lines.push(`- Resolved \`${entry.file}\` (${entry.category})`));
+    applied.deferred.forEach(entry => lines.push(`- Deferred \`${entry.file}\`: ${entry.reason}`));
+    applied.failed.forEach(entry => lines.push(`- Failed \`${entry.file}\`: ${entry.error}`));
+    if (applied.resolved.length + applied.deferred.length + applied.failed.length === 0) lines.push('- Nothing to resolve mechanically');
+    lines.push('');
+  }
+
+  const logic = plan.conflicts.filter(entry => entry.category === 'logic');
+  if (logic.length > 0) {
+    const labels = operation ? operation.labels : { ours: 'ours', theirs: 'theirs' };
+    lines.push('### Needs a Decision', '');
+    for (const entry of logic) {
+      lines.push(`#### \`${entry.file}\``, '', entry.reason);
+      const commits = (side, label) => {
+        if (entry.commits && entry.commits[side].length > 0) {
+          lines.push(`- ${label}: ${entry.commits[side].map(commit => `${commit.hash} ${commit.subject}`).join('; ')}`);
+        }
+      };
+      lines.push('');
+      commits('ours', `Ours, ${labels.ours}`);
+      commits('theirs', `Theirs, ${labels.theirs}`);
+      entry.hunks.forEach((hunk, i) => {
+        const where = `Hunk ${i + 1}, line ${hunk.line}${hunk.symbol ? ` in \`${hunk.symbol}\`` : ''}`;
+        if (hunk.merged) {
+          lines.push('', `${where}, mechanical:`, '', '```text', clip(hunk.merged), '```');
+          return;
+        }
+        lines.push('', `${where}:`, '');
+        lines.push('```text', '<<<<<<< ours', clip(hunk.ours));
+        if (hunk.base) lines.push('||||||| base', clip(hunk.base));
+        lines.push('=======', clip(hunk.theirs), '>>>>>>> theirs', '```');
+      });
+      lines.push('');
+    }
+  }
+  return lines.join('\n').trim();
+}
+
+// When run directly, output JSON
+if (require.main === module) {
+  const args = process.argv.slice(2);
+  const basePath = process.cwd();
+  const plan = planResolution(basePath, { map: require('../repo-map').load(basePath) });
+  if (!plan.success) {
+    console.log(JSON.stringify(plan));
+    process.exitCode = 1;
+  } else {
+    let applied = null;
+    if (args.includes('--apply') || args.includes('--dry-run')) {
+      const journal = require('../journal');
+      const tx = journal.begin(basePath, 'resolve', { dryRun: args.includes('--dry-run') });
+      applied = applyResolution(basePath, plan, { journal: tx });
+      tx.commit();
+    }
+    const indent = process.stdout.isTTY ? 2 : 0;
+    console.log(JSON.stringify({ ...plan, applied }, null, indent));
+  }
+}
+
+module.exports = {
+  CONFIG_KEY,
+  LOCKFILES,
+  CATEGORIES,
+  readSettings,
+  generatorCommand,
+  getOperation,
+  listConflicts,
+  parseConflicts,
+  mergeImports,
+  classifyConflict,
+  planResolution,
+  applyResolution,
+  renderPlan
+};
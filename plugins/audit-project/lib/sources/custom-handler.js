@@ -5,8 +5,11 @@
  * @module lib/sources/custom-handler
  */
 
-const { execFileSync } = require('child_process');
+const { commandExists } = require('../utils/exec');
 const sourceCache = require('./source-cache');
+const { createLogger } = require('../utils/logger');
+
+const log = createLogger('sources:custom');
 
 /**
  * Validate tool name to prevent command injection
@@ -93,17 +96,13 @@ function probeCLI(toolName) {
 
   // Validate tool name to prevent command injection
   if (!isValidToolName(toolName)) {
-    console.error(`Invalid tool name: ${toolName}`);
+    log.error('Invalid tool name', { tool: toolName });
     return capabilities;
   }
 
-  try {
-    // Check if tool exists using execFileSync (prevents command injection)
-    execFileSync(toolName, ['--version'], { encoding: 'utf8', stdio: 'pipe' });
-    capabilities.available = true;
-  } catch {
-    return capabilities;
-  }
+  // PATH lookup, no shell: prevents command injection and works without which/where
+  if (!commandExists(toolName)) return capabilities;
+  capabilities.available = true;
 
   // Known CLI patterns
   const knownPatterns = {
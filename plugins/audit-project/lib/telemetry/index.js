@@ -0,0 +1,476 @@
+#!/usr/bin/env node
+/**
+ * Opt-in Usage Metrics
+ *
+ * Records how long commands take, how large the repository is, and what
+ * kind of error ended a failed run, so performance work can follow real
+ * data. Nothing is recorded unless the project config sets
+ * `telemetry.enabled: true`; `DO_NOT_TRACK=1` or `AWESOME_SLASH_TELEMETRY=0`
+ * turn it off again for one environment.
+ *
+ * Events are anonymous: the command name, duration, success, an error
+ * category (never the message), repository file count and size rounded to
+ * two significant digits, the OS, the Node.js major version, and the
+ * awesome-slash version. No paths, names, remotes, or content. They are
+ * kept in `<state-dir>/telemetry.json` (the latest 1000) and only leave the
+ * machine when `telemetry.endpoint` (or `endpointEnv`) is set: unsent
+ * events are POSTed there in batches.
+ *
+ * Usage: node lib/telemetry/index.js [status|show|flush|clear]
+ * Output: JSON (status: settings and counts; show: per-command summary)
+ *
+ * @module lib/telemetry
+ */
+
+const fs = require('fs');
+const https = require('https');
+const path = require('path');
+
+const { loadConfig } = require('../config');
+const { getStateDirPath } = require('../platform/state-dir');
+const exec = require('../utils/exec');
+
+const CONFIG_KEY = 'telemetry';
+
+/**
+ * Event store inside the state directory
+ */
+const TELEMETRY_FILE = 'telemetry.json';
+
+const STORE_VERSION = 1;
+
+/**
+ * Defaults: events kept locally, events per POST
+ */
+const DEFAULTS = { maxEvents: 1000, batchSize: 50 };
+
+/**
+ * Error categories, first match wins
+ * `codes` are Node.js error codes; `pattern` matches the message, which
+ * itself is never recorded.
+ */
+const ERROR_CATEGORIES = [
+  { category: 'timeout', codes: ['ETIMEDOUT', 'ESOCKETTIMEDOUT'], pattern: /timed? ?out/i },
+  { category: 'network', codes: ['ECONNREFUSED', 'ECONNRESET', 'ENOTFOUND', 'EAI_AGAIN', 'EHOSTUNREACH'], pattern: /network|request failed|returned [45]\d\d/i },
+  { category: 'permission', codes: ['EACCES', 'EPERM'], pattern: /permission|not authorized|auth/i },
+  { category: 'not-found', codes: ['ENOENT', 'ENOTDIR'], pattern: /not found|no such file/i },
+  { category: 'git', codes: [], pattern: /\bgit\b|not a git repository|diff/i },
+  { category: 'config', codes: [], pattern: /config|\.awesome-slash|must be one of|invalid (json|option|argument)/i },
+  { category: 'parse', codes: [], pattern: /unexpected token|parse|syntax/i },
+  { category: 'resource', codes: ['ENOMEM', 'EMFILE', 'ENOSPC', 'ERR_CHILD_PROCESS_STDIO_MAXBUFFER'], pattern: /out of memory|heap|maxbuffer/i }
+];
+
+/**
+ * Read and validate the `telemetry` config
+ * @param {string} basePath - Repository root
+ * @param {Object} [env=process.env] - For the opt-out variables and `endpointEnv`
+ * @returns {{enabled: boolean, endpoint: string|null, maxEvents: number, batchSize: number,
+ *   optedOut: boolean, error: string|null}}
+ */
+function readSettings(basePath, env = process.env) {
+  const { config, file, error } = loadConfig(basePath);
+  const optedOut = ['1', 'true'].includes(String(env.DO_NOT_TRACK || '').toLowerCase()) ||
+    ['0', 'false', 'off'].includes(String(env.AWESOME_SLASH_TELEMETRY || '').toLowerCase());
+  const settings = { enabled: false, endpoint: null, ...DEFAULTS, optedOut, error };
+  const value = config[CONFIG_KEY];
+  if (error || value === undefined) return settings;
+
+  const fail = message => ({ ...settings, error: `${file}: ${CONFIG_KEY}${message}` });
+  if (!value || typeof value !== 'object' || Array.isArray(value)) return fail(' must be an object');
+  if (value.enabled !== undefined && typeof value.enabled !== 'boolean') return fail('.enabled must be true or false');
+  if (value.endpoint !== undefined && value.endpointEnv !== undefined) return fail(' needs at most one of endpoint, endpointEnv');
+  if (value.endpoint !== undefined && !/^https:\/\//.test(String(value.endpoint))) return fail('.endpoint must be an https URL');
+  if (value.endpointEnv !== undefined && !/^[A-Za-z_]\w*$/.test(String(value.endpointEnv))) return fail('.endpointEnv must be an environment variable name');
+  for (const key of ['maxEvents', 'batchSize']) {
+    if (value[key] !== undefined && !(Number.isInteger(value[key]) && value[key] > 0)) return fail(`.${key} must be a positive integer`);
+  }
+
+  const endpoint = value.endpoint || (value.endpointEnv ? env[value.endpointEnv] || null : null);
+  if (endpoint && !/^https:\/\//.test(endpoint)) return fail(`: ${value.endpointEnv} must hold an https URL`);
+  return {
+    ...settings,
+    enabled: value.enabled === true && !optedOut,
+    endpoint: endpoint || null,
+    maxEvents: value.maxEvents || DEFAULTS.maxEvents,
+    batchSize: value.batchSize || DEFAULTS.batchSize
+  };
+}
+
+/**
+ * Store path for a repository
+ * @param {string} basePath - Repository root
+ * @returns {string}
+ */
+function storePath(basePath) {
+  return path.join(getStateDirPath(basePath), TELEMETRY_FILE);
+}
+
+/**
+ * Read the event store
+ * @param {string} basePath - Repository root
+ * @returns {{version: number, events: Object[], sent: number}} `sent` counts events already POSTed
+ */
+function readStore(basePath) {
+  try {
+    const store = JSON.parse(fs.readFileSync(storePath(basePath), 'utf8'));
+    if (store && store.version === STORE_VERSION && Array.isArray(store.events)) {
+      return { version: STORE_VERSION, events: store.events, sent: Math.min(Number(store.sent) || 0, store.events.length) };
+    }
+  } catch {
+    // Missing or corrupt: start over
+  }
+  return { version: STORE_VERSION, events: [], sent: 0 };
+}
+
+/**
+ * Write the event store, keeping the latest `maxEvents`
+ * @param {string} basePath - Repository root
+ * @param {Object} store - {version, events, sent}
+ * @param {number} maxEvents - Events kept
+ * @returns {Object} The store as written
+ */
+function writeStore(basePath, store, maxEvents) {
+  const dropped = Math.max(0, store.events.length - maxEvents);
+  const written = { version: STORE_VERSION, events: store.events.slice(dropped), sent: Math.max(0, store.sent - dropped) };
+  const file = storePath(basePath);
+  fs.mkdirSync(path.dirname(file), { recursive: true });
+  fs.writeFileSync(file, JSON.stringify(written, null, 2) + '\n');
+  return written;
+}
+
+/**
+ * Round to two significant digits, so sizes describe a repository without identifying it
+ * @param {number} value - Non-negative number
+ * @returns {number}
+ */
+function roundSize(value) {
+  if (!(value > 0)) return 0;
+  const scale = Math.pow(10, Math.max(0, Math.floor(Math.log10(value)) - 1));
+  return Math.round(value / scale) * scale;
+}
+
+/**
+ * Category of an error, never its message
+ * @param {Error|string|null} error - What ended the run
+ * @returns {string|null} null without an error
+ */
+function categorizeError(error) {
+  if (!error) return null;
+  const code = error.code || '';
+  const message = String(error.message || error);
+  const match = ERROR_CATEGORIES.find(entry => entry.codes.includes(code)) ||
+    ERROR_CATEGORIES.find(entry => entry.pattern.test(message));
+  return match ? match.category : 'other';
+}
+
+/**
+ * Tracked file count and total size, both rounded
+ * Falls back to a bounded walk outside git.
+ * @param {string} basePath - Repository root
+ * @returns {{files: number, bytes: number}}
+ */
+function repoSize(basePath) {
+  const tracked = exec.git(basePath, ['ls-files', '-z'], { maxBuffer: 256 * 1024 * 1024 });
+  const files = tracked === null ? [] : tracked.split('\0').filter(Boolean);
+  if (tracked === null) {
+    const walk = (dir, depth) => {
+      if (depth > 8 || files.length >= 20000) return;
+      let entries = [];
+      try {
+        entries = fs.readdirSync(path.join(basePath, dir), { withFileTypes: true });
+      } catch {
+        return;
+      }
+      for (const entry of entries) {
+        if (entry.name.startsWith('.') || entry.name === 'node_modules') continue;
+        const rel = path.join(dir, entry.name);
+        if (entry.isDirectory()) walk(rel, depth + 1);
+        else if (entry.isFile()) files.push(rel);
+      }
+    };
+    walk('', 0);
+  }
+
+  let bytes = 0;
+  for (const file of files) {
+    try {
+      bytes += fs.statSync(path.join(basePath, file)).size;
+    } catch {
+      // Deleted but still tracked
+    }
+  }
+  return { files: roundSize(files.length), bytes: roundSize(bytes) };
+}
+
+/**
+ * awesome-slash version from package.json or, inside a plugin, plugin.json
+ * @returns {string|null}
+ */
+function toolVersion() {
+  for (const file of [path.join(__dirname, '..', '..', 'package.json'), path.join(__dirname, '..', '..', '.claude-plugin', 'plugin.json')]) {
+    try {
+      return JSON.parse(fs.readFileSync(file, 'utf8')).version || null;
+    } catch {
+      // Try the next location
+    }
+  }
+  return null;
+}
+
+/**
+ * Build an event (only the fields listed in the module doc)
+ * @param {string} basePath - Repository root
+ * @param {Object} run
+ * @param {string} run.command - Command name
+ * @param {number} run.durationMs - Wall time
+ * @param {boolean} [run.success=true] - Whether the run succeeded
+ * @param {Error|string} [run.error] - What ended a failed run
+ * @returns {Object}
+ */
+function buildEvent(basePath, run) {
+  const error = categorizeError(run.error);
+  return {
+    time: new Date().toISOString(),
+    command: String(run.command),
+    durationMs: Math.max(0, Math.round(run.durationMs)),
+    success: run.success === undefined ? !error : Boolean(run.success),
+    error,
+    repo: repoSize(basePath),
+    os: process.platform,
+    node: Number(process.versions.node.split('.')[0]),
+    version: toolVersion()
+  };
+}
+
+/**
+ * POST a batch of events
+ * @param {string} url - Endpoint
+ * @param {Object} payload - `{events}`
+ * @returns {Promise<void>} Rejects with the status code only
+ */
+function postEvents(url, payload) {
+  return new Promise((resolve, reject) => {
+    const body = JSON.stringify(payload);
+    const req = https.request(url, {
+      method: 'POST',
+      headers: { 'Content-Type': 'application/json', 'Content-Length': Buffer.byteLength(body) },
+      timeout: 5000
+    }, res => {
+      res.resume();
+      res.on('end', () => {
+        if (res.statusCode >= 400) reject(new Error(`endpoint returned ${res.statusCode}`));
+        else resolve();
+      });
+    });
+    req.on('timeout', () => req.destroy(new Error('endpoint request timed out')));
+    req.on('error', error => reject(new Error(`endpoint request failed (${error.code || 'network error'})`)));
+    req.write(body);
+    req.end();
+  });
+}
+
+/**
+ * Record a finished run when telemetry is enabled
+ * Synchronous, so it also works in a `process.on('exit')` handler.
+ * @param {string} basePath - Repository root
+ * @param {Object} run - See buildEvent
+ * @param {Object} [options]
+ * @param {Object} [options.env=process.env] - Environment
+ * @returns {{recorded: boolean, event?: Object, pending?: number}} `pending` counts unsent events when an endpoint is set
+ */
+function record(basePath, run, options = {}) {
+  const settings = readSettings(basePath, options.env || process.env);
+  if (!settings.enabled) return { recorded: false };
+  const event = buildEvent(basePath, run);
+  let store;
+  try {
+    const existing = readStore(basePath);
+    existing.events.push(event);
+    store = writeStore(basePath, existing, settings.maxEvents);
+  } catch {
+    // Read-only checkout: metrics are best effort
+    return { recorded: false };
+  }
+  return { recorded: true, event, ...(settings.endpoint ? { pending: store.events.length - store.sent } : {}) };
+}
+
+/**
+ * Start timing a run
+ * @param {string} basePath - Repository root
+ * @param {string} command - Command name
+ * @returns {{fail: Function, end: Function}} `fail(error)` notes what went wrong; `end({success, exitCode})`
+ *   records once (exit code 1 is a failure, the slop gate's 2-6 are not)
+ */
+function startRun(basePath, command) {
+  const started = Date.now();
+  let failure = null;
+  let done = null;
+  return {
+    fail(error) {
+      failure = error;
+    },
+    end(outcome = {}) {
+      if (done) return done;
+      const success = outcome.success !== undefined ? outcome.success : !failure && !(outcome.exitCode === 1);
+      done = record(basePath, { command, durationMs: Date.now() - started, success, error: failure || (success ? null : 'other') });
+      return done;
+    }
+  };
+}
+
+/**
+ * Time an async function and record the outcome; errors are rethrown
+ * @param {string} basePath - Repository root
+ * @param {string} command - Command name
+ * @param {Function} fn - `async () => result`
+ * @returns {Promise<*>} fn's result
+ */
+async function track(basePath, command, fn) {
+  const runTimer = startRun(basePath, command);
+  try {
+    const result = await fn();
+    runTimer.end({ success: !(result && result.success === false) });
+    return result;
+  } catch (error) {
+    runTimer.fail(error);
+    runTimer.end({ success: false });
+    throw error;
+  }
+}
+
+/**
+ * POST unsent events to the configured endpoint
+ * Nothing is sent unless telemetry is enabled now: events recorded before
+ * the user opted out stay local.
+ * @param {string} basePath - Repository root
+ * @param {Object} [options]
+ * @param {Object} [options.env=process.env] - Environment
+ * @param {Function} [options.request] - `(url, payload) => Promise` (default: HTTPS POST)
+ * @returns {Promise<{success: boolean, sent: number, pending: number, error?: string}>}
+ */
+async function flush(basePath, options = {}) {
+  const settings = readSettings(basePath, options.env || process.env);
+  if (settings.error) return { success: false, sent: 0, pending: 0, error: settings.error };
+  const store = readStore(basePath);
+  const pending = store.events.length - store.sent;
+  if (settings.optedOut) return { success: false, sent: 0, pending, error: 'Telemetry is turned off in this environment (DO_NOT_TRACK or AWESOME_SLASH_TELEMETRY)' };
+  if (!settings.enabled) return { success: false, sent: 0, pending, error: 'Telemetry is not enabled (telemetry.enabled); events stay local' };
+  if (!settings.endpoint) return { success: false, sent: 0, pending, error: 'No telemetry.endpoint configured; events stay local' };
+
+  const request = options.request || postEvents;
+  let sent = 0;
+  while (store.sent < store.events.length) {
+    const batch = store.events.slice(store.sent, store.sent + settings.batchSize);
+    try {
+      await request(settings.endpoint, { events: batch });
+    } catch (error) {
+      writeStore(basePath, store, settings.maxEvents);
+      return { success: false, sent, pending: store.events.length - store.sent, error: error.message };
+    }
+    store.sent += batch.length;
+    sent += batch.length;
+  }
+  writeStore(basePath, store, settings.maxEvents);
+  return { success: true, sent, pending: 0 };
+}
+
+/**
+ * Per-command summary of recorded events
+ * @param {Object[]} events - Stored events
+ * @returns {Array<{command: string, runs: number, failures: number, p50Ms: number, p95Ms: number, maxMs: number,
+ *   errors: Object<string, number>, largestRepo: {files: number, bytes: number}}>} Slowest p95 first
+ */
+function summarize(events) {
+  const byCommand = new Map();
+  for (const event of events) {
+    if (!byCommand.has(event.command)) byCommand.set(event.command, []);
+    byCommand.get(event.command).push(event);
+  }
+  const percentile = (sorted, p) => sorted[Math.min(sorted.length - 1, Math.ceil(p * sorted.length) - 1)];
+  return Array.from(byCommand, ([command, runs]) => {
+    const durations = runs.map(event => event.durationMs).sort((a, b) => a - b);
+    const errors = {};
+    for (const event of runs) if (event.error) errors[event.error] = (errors[event.error] || 0) + 1;
+    const largest = runs.reduce((max, event) => ((event.repo && event.repo.files) > max.files ? event.repo : max), { files: 0, bytes: 0 });
+    return {
+      command,
+      runs: runs.length,
+      failures: runs.filter(event => !event.success).length,
+      p50Ms: percentile(durations, 0.5),
+      p95Ms: percentile(durations, 0.95),
+      maxMs: durations[durations.length - 1],
+      errors,
+      largestRepo: { files: largest.files, bytes: largest.bytes }
+    };
+  }).sort((a, b) => b.p95Ms - a.p95Ms);
+}
+
+/**
+ * Delete the local events
+ * @param {string} basePath - Repository root
+ * @returns {{removed: number}}
+ */
+function clear(basePath) {
+  const removed = readStore(basePath).events.length;
+  fs.rmSync(storePath(basePath), { force: true });
+  return { removed };
+}
+
+/**
+ * Settings and counts, for `telemetry status`
+ * @param {string} basePath - Repository root
+ * @returns {{enabled: boolean, optedOut: boolean, endpoint: boolean, events: number, pending: number, file: string, error: string|null}}
+ *   `endpoint` only says whether one is set; the URL may hold a token
+ */
+function status(basePath) {
+  const settings = readSettings(basePath);
+  const store = readStore(basePath);
+  return {
+    enabled: settings.enabled,
+    optedOut: settings.optedOut,
+    endpoint: Boolean(settings.endpoint),
+    events: store.events.length,
+    pending: settings.endpoint ? store.events.length - store.sent : 0,
+    file: path.relative(basePath, storePath(basePath)),
+    error: settings.error
+  };
+}
+
+if (require.main === module) {
+  const action = process.argv[2] || 'status';
+  const indent = process.stdout.isTTY ? 2 : 0;
+  const print = value => console.log(JSON.stringify(value, null, indent));
+  if (action === 'status') print(status(process.cwd()));
+  else if (action === 'show') print(summarize(readStore(process.cwd()).events));
+  else if (action === 'clear') print(clear(process.cwd()));
+  else if (action === 'flush') {
+    flush(process.cwd()).then(result => {
+      print(result);
+      if (!result.success) process.exitCode = 1;
+    });
+  } else {
+    console.error('Usage: node lib/telemetry/index.js [status|show|flush|clear]');
+    process.exitCode = 1;
+  }
+}
+
+module.exports = {
+  CONFIG_KEY,
+  TELEMETRY_FILE,
+  ERROR_CATEGORIES,
+  readSettings,
+  storePath,
+  readStore,
+  roundSize,
+  categorizeError,
+  repoSize,
+  buildEvent,
+  postEvents,
+  record,
+  startRun,
+  track,
+  flush,
+  summarize,
+  clear,
+  status
+};
@@ -0,0 +1,307 @@
+/**
+ * Process and Path Helpers
+ * Runs external commands and reports paths the same way on Windows,
+ * macOS, and Linux, so callers never branch on the platform:
+ *
+ * - Package-manager entry points (`npm`, `npx`, `pnpm`, `yarn`, globally
+ *   installed CLIs) are `.cmd` shims on Windows. `execFile('npm')` fails
+ *   with ENOENT, and Node refuses to start a `.cmd` without a shell
+ *   (EINVAL since 18.20.2/20.12.2). Commands are resolved through `PATH`
+ *   and `PATHEXT`; batch files run through cmd.exe with every argument
+ *   quoted and its metacharacters escaped, so a branch name or path with
+ *   spaces, `&`, or `%` reaches the program unchanged.
+ * - Arguments are always passed as an array. Nothing is joined into a
+ *   POSIX shell string, so quoting rules of sh and cmd never matter.
+ * - `which`, `where`, and `grep` are not assumed to exist: `commandExists`
+ *   searches `PATH` itself, and file searches go through `git grep`.
+ * - Paths handed to users, globs, repo maps, and git are `/`-separated.
+ *
+ * @module lib/utils/exec
+ * @author Avi Fenesh
+ * @license MIT
+ */
+
+const childProcess = require('child_process');
+const fs = require('fs');
+const path = require('path');
+
+const MAX_BUFFER = 64 * 1024 * 1024;
+
+/**
+ * Executable extensions tried on Windows when `PATHEXT` is unset
+ */
+const DEFAULT_PATHEXT = '.COM;.EXE;.BAT;.CMD';
+
+/**
+ * Extensions Windows can only run through cmd.exe
+ */
+const BATCH_EXTENSIONS = ['.bat', '.cmd'];
+
+/**
+ * cmd.exe metacharacters, escaped with `^`
+ */
+const CMD_META = /([()\][%!^"`<>&|;, *?])/g;
+
+/**
+ * npm-generated shims re-parse their arguments through cmd a second time
+ */
+const CMD_SHIM = /node_modules[\\/]\.bin[\\/][^\\/]+\.cmd$/i;
+
+// command + PATH + PATHEXT -> resolved command; PATH lookups hit the disk
+const resolved = new Map();
+
+/**
+ * Read an environment variable; Windows names are case-insensitive (`Path`)
+ * @param {Object} env - Environment
+ * @param {string} name - Upper-case variable name
+ * @returns {string|undefined}
+ */
+function envValue(env, name) {
+  if (env[name] !== undefined) return env[name];
+  const key = Object.keys(env).find(entry => entry.toUpperCase() === name);
+  return key === undefined ? undefined : env[key];
+}
+
+/**
+ * Whether a path is a file the current user can run
+ * @param {Object} fileSystem - File system module
+ * @param {string} file - Candidate path
+ * @param {boolean} windows - Skip the execute bit, which Windows does not have
+ * @returns {boolean}
+ */
+function isExecutable(fileSystem, file, windows) {
+  try {
+    if (!fileSystem.statSync(file).isFile()) return false;
+    if (!windows) fileSystem.accessSync(file, fs.constants.X_OK);
+    return true;
+  } catch {
+    return false;
+  }
+}
+
+/**
+ * Locate a command the way the platform's shell would
+ * @param {string} command - Name (`npm`) or path (`./node_modules/.bin/jest`)
+ * @param {Object} [options]
+ * @param {string} [options.platform] - `process.platform` value (for testing)
+ * @param {Object} [options.env] - Environment (for testing)
+ * @param {Object} [options.fs] - File system module (for testing)
+ * @returns {string|null} Full path, or null when it is not installed
+ */
+function findExecutable(command, options = {}) {
+  const windows = (options.platform || process.platform) === 'win32';
+  const env = options.env || process.env;
+  const fileSystem = options.fs || fs;
+  const pathModule = windows ? path.win32 : path.posix;
+
+  const names = windows && !pathModule.extname(command)
+    ? (envValue(env, 'PATHEXT') || DEFAULT_PATHEXT).split(';').filter(Boolean).map(ext => command + ext.toLowerCase())
+    : [command];
+  const dirs = /[\\/]/.test(command) ? [''] : (envValue(env, 'PATH') || '').split(windows ? ';' : ':').filter(Boolean);
+
+  for (const dir of dirs) {
+    for (const name of names) {
+      const candidate = dir ? pathModule.join(dir.replace(/^"(.*)"$/, '$1'), name) : name;
+      if (isExecutable(fileSystem, candidate, windows)) return candidate;
+    }
+  }
+  return null;
+}
+
+/**
+ * Whether a command is installed, without `which` or `where`
+ * @param {string} command - Command name
+ * @param {Object} [options] - findExecutable options
+ * @returns {boolean}
+ */
+function commandExists(command, options = {}) {
+  return findExecutable(command, options) !== null;
+}
+
+/**
+ * How a command has to be started on this platform
+ * @param {string} command - Command name or path
+ * @param {Object} [options] - findExecutable options
+ * @returns {{file: string, shell: boolean}} `shell` when it is a batch file (Windows only)
+ */
+function resolveCommand(command, options = {}) {
+  if ((options.platform || process.platform) !== 'win32') return { file: command, shell: false };
+  const env = options.env || process.env;
+  const key = `${command}\0${envValue(env, 'PATH')}\0${envValue(env, 'PATHEXT')}`;
+  if (!options.fs && resolved.has(key)) return resolved.get(key);
+
+  const file = findExecutable(command, options) || command;
+  const result = { file, shell: BATCH_EXTENSIONS.includes(path.win32.extname(file).toLowerCase()) };
+  if (!options.fs) resolved.set(key, result);
+  return result;
+}
+
+/**
+ * Quote one argument for a cmd.exe command line
+ * The argument is quoted as the C runtime parses it (backslashes before a
+ * quote doubled), then cmd's metacharacters are escaped so `&`, `|`, `%`,
+ * and `^` are not interpreted.
+ * @param {string} arg - Argument
+ * @param {boolean} [doubleEscape] - Escape twice, for npm's `node_modules/.bin` shims
+ * @returns {string}
+ */
+function quoteWindowsArg(arg, doubleEscape = false) {
+  let quoted = `"${String(arg).replace(/(\\*)"/g, '$1$1\\"').replace(/(\\*)$/, '$1$1')}"`;
+  quoted = quoted.replace(CMD_META, '^$1');
+  return doubleEscape ? quoted.replace(CMD_META, '^$1') : quoted;
+}
+
+/**
+ * File, arguments, and extra spawn options that start a command
+ * @param {string[]} argv - Command and arguments
+ * @param {Object} [options] - findExecutable options
+ * @returns {{file: string, args: string[], options: Object}}
+ */
+function spawnArgs(argv, options = {}) {
+  const { file, shell } = resolveCommand(argv[0], options);
+  if (!shell) return { file, args: argv.slice(1), options: {} };
+
+  const doubleEscape = CMD_SHIM.test(file);
+  const line = [path.win32.normalize(file).replace(CMD_META, '^$1'), ...argv.slice(1).map(arg => quoteWindowsArg(arg, doubleEscape))].join(' ');
+  return {
+    file: envValue(options.env || process.env, 'COMSPEC') || 'cmd.exe',
+    args: ['/d', '/s', '/c', `"${line}"`],
+    options: { windowsVerbatimArguments: true }
+  };
+}
+
+/**
+ * Spawn options shared by the runners
+ * @param {string} cwd - Working directory
+ * @param {Object} options - Caller options
+ * @returns {Object}
+ */
+function baseOptions(cwd, options) {
+  const { keepOutput, okCodes, platform, fs: fileSystem, ...rest } = options;
+  return { cwd, encoding: 'utf8', stdio: ['pipe', 'pipe', 'pipe'], maxBuffer: MAX_BUFFER, windowsHide: true, ...rest };
+}
+
+/**
+ * Run a command and return stdout, throwing when it fails
+ * @param {string} cwd - Working directory
+ * @param {string[]} argv - Command and arguments
+ * @param {Object} [options] - `child_process.execFileSync` options (`timeout`, `input`, `env`, ...)
+ * @returns {string}
+ */
+function execSync(cwd, argv, options = {}) {
+  const command = spawnArgs(argv, options);
+  return childProcess.execFileSync(command.file, command.args, { ...baseOptions(cwd, options), ...command.options });
+}
+
+/**
+ * Run a command and return stdout, or null on failure
+ * @param {string} cwd - Working directory
+ * @param {string[]} argv - Command and arguments
+ * @param {Object} [options] - execSync options, plus:
+ * @param {boolean} [options.keepOutput] - Return the stdout of a non-zero exit
+ *   (tools such as `npm outdated` report through the exit code)
+ * @param {number[]} [options.okCodes] - Non-zero exit codes that still count as
+ *   success, returning stdout even when empty (`git check-ignore` exits 1 when nothing matches)
+ * @returns {string|null}
+ */
+function run(cwd, argv, options = {}) {
+  try {
+    return execSync(cwd, argv, options);
+  } catch (error) {
+    if ((options.okCodes || []).includes(error.status)) return String(error.stdout || '');
+    return options.keepOutput && error.stdout ? String(error.stdout) : null;
+  }
+}
+
+/**
+ * `run` without blocking
+ * @param {string} cwd - Working directory
+ * @param {string[]} argv - Command and arguments
+ * @param {Object} [options] - run options
+ * @returns {Promise<string|null>}
+ */
+function runAsync(cwd, argv, options = {}) {
+  return new Promise(resolve => {
+    const command = spawnArgs(argv, options);
+    childProcess.execFile(command.file, command.args, { ...baseOptions(cwd, options), ...command.options }, (error, stdout) => {
+      if (!error) resolve(stdout);
+      else if ((options.okCodes || []).includes(error.code)) resolve(String(stdout || ''));
+      else resolve(options.keepOutput && stdout ? String(stdout) : null);
+    });
+  });
+}
+
+/**
+ * Run an interactive command (an editor, a pager) attached to the terminal
+ * @param {string[]} argv - Command and arguments
+ * @param {Object} [options] - `child_process.spawnSync` options
+ * @returns {Object} spawnSync result
+ */
+function spawnInteractive(argv, options = {}) {
+  const command = spawnArgs(argv, options);
+  return childProcess.spawnSync(command.file, command.args, { stdio: 'inherit', ...options, ...command.options });
+}
+
+/**
+ * Run git and return stdout, or null on failure
+ * @param {string} cwd - Working directory
+ * @param {string[]} args - git arguments
+ * @param {Object} [options] - run options
+ * @returns {string|null}
+ */
+function git(cwd, args, options = {}) {
+  return run(cwd, ['git', ...args], options);
+}
+
+/**
+ * Tracked files containing a pattern, through `git grep` rather than grep
+ * @param {string} cwd - Repository root
+ * @param {string} pattern - Extended regular expression
+ * @param {string[]} [pathspecs] - Limit the search (`['*.js']`)
+ * @returns {string[]|null} `/`-separated paths, or null outside a repository
+ */
+function grepFiles(cwd, pattern, pathspecs = []) {
+  const output = childProcess.spawnSync('git', ['grep', '-l', '-I', '-E', '-e', pattern, '--', ...pathspecs], { cwd, encoding: 'utf8', maxBuffer: MAX_BUFFER, windowsHide: true });
+  // git grep exits 1 when nothing matches and 128 outside a repository
+  if (output.error || output.status > 1) return null;
+  return output.stdout.split('\n').filter(Boolean);
+}
+
+/**
+ * Make a path `/`-separated
+ * Only the platform separator is replaced; on macOS and Linux a backslash
+ * is a valid file name character.
+ * @param {string} file - Path
+ * @param {string} [sep] - Platform separator (for testing)
+ * @returns {string}
+ */
+function toPosix(file, sep = path.sep) {
+  return sep === '/' ? file : file.split(sep).join('/');
+}
+
+/**
+ * Relative path between two locations, `/`-separated
+ * @param {string} from - Base directory
+ * @param {string} to - Target path
+ * @returns {string} Empty when they are the same
+ */
+function relativePath(from, to) {
+  return toPosix(path.relative(from, to));
+}
+
+module.exports = {
+  MAX_BUFFER,
+  findExecutable,
+  commandExists,
+  resolveCommand,
+  quoteWindowsArg,
+  spawnArgs,
+  execSync,
+  run,
+  runAsync,
+  spawnInteractive,
+  git,
+  grepFiles,
+  toPosix,
+  relativePath
+};
@@ -0,0 +1,206 @@
+/**
+ * Git Layout
+ * Where a checkout's git data lives and which directories under it belong
+ * to other checkouts, read from the files git writes rather than by
+ * spawning git, so every walker can afford it.
+ *
+ * - In a linked worktree `.git` is a file (`gitdir: <common>/worktrees/<name>`);
+ *   refs, hooks, and config live in the common dir it points back to
+ * - Linked worktrees can sit inside the main one (`git worktree add wt/fix`);
+ *   walking the main tree would scan the same files twice
+ * - Submodules are separate repositories with their own ignore rules and
+ *   history, so walkers leave them out and scan them only when asked
+ *   (`git.submodules` in the project config)
+ *
+ * @module lib/utils/git
+ * @author Avi Fenesh
+ * @license MIT
+ */
+
+const { loadConfig } = require('../config');
+
+const CONFIG_KEY = 'git';
+
+/**
+ * Read a file, or null when it is missing or unreadable
+ * @param {Object} fs - File system module
+ * @param {string} file - Absolute path
+ * @returns {string|null}
+ */
+function readText(fs, file) {
+  try {
+    return fs.readFileSync(file, 'utf8');
+  } catch {
+    return null;
+  }
+}
+
+/**
+ * Whether a path exists
+ * @param {Object} fs - File system module
+ * @param {string} file - Absolute path
+ * @returns {boolean}
+ */
+function exists(fs, file) {
+  try {
+    fs.statSync(file);
+    return true;
+  } catch {
+    return false;
+  }
+}
+
+/**
+ * Locate the checkout containing a directory and its git dirs
+ * @param {string} basePath - Any directory inside the checkout
+ * @param {Object} [options]
+ * @param {Object} [options.fs] - File system module (for testing)
+ * @param {Object} [options.path] - Path module (for testing)
+ * @returns {{root: string, gitDir: string, commonDir: string, linked: boolean}|null}
+ *   `linked` for a linked worktree; null outside a checkout
+ */
+function resolveGitDirs(basePath, options = {}) {
+  const fs = options.fs || require('fs');
+  const path = options.path || require('path');
+
+  let dir = path.resolve(basePath);
+  for (;;) {
+    const dotGit = path.join(dir, '.git');
+    let stat = null;
+    try {
+      stat = fs.statSync(dotGit);
+    } catch {
+      stat = null;
+    }
+    if (stat && stat.isDirectory()) {
+      return { root: dir, gitDir: dotGit, commonDir: dotGit, linked: false };
+    }
+    if (stat && stat.isFile()) {
+      const match = /^gitdir:\s*(.+?)\s*$/m.exec(readText(fs, dotGit) || '');
+      if (!match) return null;
+      const gitDir = path.resolve(dir, match[1]);
+      const common = (readText(fs, path.join(gitDir, 'commondir')) || '').trim();
+      // Submodules also use a `.git` file, but their git dir has no commondir
+      return common
+        ? { root: dir, gitDir, commonDir: path.resolve(gitDir, common), linked: true }
+        : { root: dir, gitDir, commonDir: gitDir, linked: false };
+    }
+    const parent = path.dirname(dir);
+    if (parent === dir) return null;
+    dir = parent;
+  }
+}
+
+/**
+ * Submodules declared in a checkout's `.gitmodules`
+ * @param {string} root - Checkout root (resolveGitDirs().root)
+ * @param {Object} [options] - `fs` and `path` (for testing)
+ * @returns {Array<{name: string, path: string, url: string|null, initialized: boolean}>}
+ *   `path` relative to `root`; `initialized` once `git submodule update` checked it out
+ */
+function listSubmodules(root, options = {}) {
+  const fs = options.fs || require('fs');
+  const path = options.path || require('path');
+
+  const submodules = [];
+  let current = null;
+  for (const line of (readText(fs, path.join(root, '.gitmodules')) || '').split(/\r?\n/)) {
+    const section = /^\s*\[submodule\s+"(.+)"\]\s*$/.exec(line);
+    if (section) {
+      current = { name: section[1], path: null, url: null };
+      submodules.push(current);
+      continue;
+    }
+    const entry = /^\s*(path|url)\s*=\s*(.+?)\s*$/.exec(line);
+    if (current && entry) current[entry[1]] = entry[2];
+  }
+
+  return submodules
+    .filter(sub => sub.path)
+    .map(sub => {
+      const subPath = sub.path.replace(/\\/g, '/').replace(/^\.\//, '').replace(/\/+$/, '');
+      return { ...sub, path: subPath, initialized: exists(fs, path.join(root, subPath, '.git')) };
+    });
+}
+
+/**
+ * Every worktree of a repository, from its common dir
+ * @param {string} commonDir - resolveGitDirs().commonDir
+ * @param {Object} [options] - `fs` and `path` (for testing)
+ * @returns {Array<{path: string, main: boolean}>} Absolute worktree roots
+ */
+function listWorktrees(commonDir, options = {}) {
+  const fs = options.fs || require('fs');
+  const path = options.path || require('path');
+
+  const worktrees = [];
+  if (path.basename(commonDir) === '.git') {
+    worktrees.push({ path: path.dirname(commonDir), main: true });
+  }
+  let names = [];
+  try {
+    names = fs.readdirSync(path.join(commonDir, 'worktrees'));
+  } catch {
+    names = [];
+  }
+  for (const name of names.sort()) {
+    // `gitdir` holds the path of the worktree's `.git` file
+    const target = (readText(fs, path.join(commonDir, 'worktrees', name, 'gitdir')) || '').trim();
+    if (target) worktrees.push({ path: path.dirname(path.resolve(commonDir, 'worktrees', name, target)), main: false });
+  }
+  return worktrees;
+}
+
+/**
+ * Directories under a path that belong to other checkouts
+ * Linked worktrees (or the main one) nested inside the tree and the
+ * checkout's submodules; file walkers skip both.
+ * @param {string} basePath - Directory being walked
+ * @param {Object} [options] - `fs` and `path` (for testing)
+ * @returns {{worktrees: string[], submodules: string[]}} Paths relative to `basePath`, posix
+ */
+function nestedCheckouts(basePath, options = {}) {
+  const path = options.path || require('path');
+  const dirs = resolveGitDirs(basePath, options);
+  if (!dirs) return { worktrees: [], submodules: [] };
+
+  const base = path.resolve(basePath);
+  const inside = target => {
+    const rel = path.relative(base, target);
+    return rel && !rel.startsWith('..') && !path.isAbsolute(rel) ? rel.split(path.sep).join('/') : null;
+  };
+  const worktrees = listWorktrees(dirs.commonDir, options)
+    .filter(worktree => path.resolve(worktree.path) !== dirs.root)
+    .map(worktree => inside(worktree.path))
+    .filter(Boolean);
+  const submodules = listSubmodules(dirs.root, options)
+    .map(sub => inside(path.join(dirs.root, sub.path)))
+    .filter(Boolean);
+  return { worktrees: Array.from(new Set(worktrees)).sort(), submodules: submodules.sort() };
+}
+
+/**
+ * Git settings from the project config
+ * @param {string} basePath - Repository root
+ * @returns {{submodules: boolean, error: string|null}} `submodules`: scan submodules too
+ */
+function readSettings(basePath) {
+  const { config, file, error } = loadConfig(basePath);
+  const settings = { submodules: false, error };
+  const value = config[CONFIG_KEY];
+  if (error || value === undefined) return settings;
+
+  const fail = message => ({ ...settings, error: `${file}: ${CONFIG_KEY}${message}` });
+  if (!value || typeof value !== 'object' || Array.isArray(value)) return fail(' must be an object');
+  if (value.submodules !== undefined && typeof value.submodules !== 'boolean') return fail('.submodules must be true or false');
+  return { ...settings, submodules: value.submodules === true };
+}
+
+module.exports = {
+  CONFIG_KEY,
+  resolveGitDirs,
+  listSubmodules,
+  listWorktrees,
+  nestedCheckouts,
+  readSettings
+};
@@ -0,0 +1,377 @@
+#!/usr/bin/env node
+/**
+ * Environment Variable Check
+ *
+ * Cross-references the environment variables code reads (repo-map `env`
+ * entries) with the places that document or set them: `.env.example` and
+ * friends, CI configuration (GitHub Actions `env`/`secrets`/`vars`, GitLab
+ * `variables`, CircleCI `environment`), and deploy configuration (Vercel,
+ * Netlify, Fly.io, Render, Heroku `app.json`, Compose, Dockerfiles,
+ * Serverless). Variables read but set nowhere are undocumented; variables
+ * in `.env.example` or deploy configuration that no code reads are unused.
+ *
+ * Usage: node lib/env-check/index.js
+ * Output: JSON environment report
+ *
+ * @module lib/env-check
+ */
+
+const fs = require('fs');
+const path = require('path');
+
+const { extractEnvReads } = require('../repo-map/env');
+const { isTestFile } = require('../repo-map/testgen');
+
+/**
+ * Example env files, in the order they are listed
+ */
+const EXAMPLE_FILES = [
+  '.env.example', '.env.sample', '.env.template', '.env.dist', '.env.defaults',
+  'example.env', 'sample.env', '.env.local.example', '.env.development.example'
+];
+
+const DEPLOY_FILES = ['vercel.json', 'netlify.toml', 'fly.toml', 'render.yaml', 'app.json', 'serverless.yml', 'serverless.yaml'];
+const COMPOSE_FILE = /^(?:docker-)?compose(?:\.[\w-]+)?\.ya?ml$/;
+const DOCKERFILE = /^(?:Dockerfile(?:\.[\w-]+)?|[\w-]+\.Dockerfile)$/;
+
+// Set by the OS, the runtime, CI, or the hosting platform rather than the project
+const PROVIDED_NAMES = new Set([
+  'HOME', 'PATH', 'USER', 'SHELL', 'PWD', 'TMPDIR', 'TEMP', 'TMP', 'TERM', 'LANG', 'LC_ALL', 'TZ', 'HOSTNAME',
+  'CI', 'NODE_ENV', 'NODE_OPTIONS', 'PYTHONPATH', 'PYTHONUNBUFFERED', 'PYTHONDONTWRITEBYTECODE', 'GOPATH', 'GOFLAGS',
+  'CARGO_MANIFEST_DIR', 'OUT_DIR', 'K_SERVICE', 'K_REVISION',
+  // Build image settings (Netlify, Render, Heroku buildpacks)
+  'NODE_VERSION', 'PYTHON_VERSION', 'RUBY_VERSION', 'GO_VERSION'
+]);
+const PROVIDED_PREFIXES = /^(?:GITHUB_|RUNNER_|CI_|GITLAB_|CIRCLE_|VERCEL|NETLIFY|FLY_|RAILWAY_|RENDER|HEROKU_|AWS_LAMBDA_|AWS_EXECUTION_|npm_|CARGO_PKG_)/;
+
+const NAME = /^[A-Za-z_][A-Za-z0-9_]*$/;
+
+/**
+ * Read a file relative to the project, or null
+ * @param {string} basePath - Project root
+ * @param {string} file - Relative path
+ * @returns {string|null}
+ */
+function readText(basePath, file) {
+  try {
+    return fs.readFileSync(path.join(basePath, file), 'utf8');
+  } catch {
+    return null;
+  }
+}
+
+/**
+ * List file names in a project directory
+ * @param {string} basePath - Project root
+ * @param {string} dir - Relative directory
+ * @returns {string[]}
+ */
+function listDir(basePath, dir) {
+  try {
+    return fs.readdirSync(path.join(basePath, dir));
+  } catch {
+    return [];
+  }
+}
+
+/**
+ * Variable names in a dotenv file; commented-out assignments count, since
+ * they document optional settings
+ * @param {string} content - File content
+ * @returns {string[]}
+ */
+function parseDotenv(content) {
+  const names = [];
+  for (const line of String(content).split('\n')) {
+    const match = line.match(/^\s*#?\s*(?:export\s+)?([A-Za-z_][A-Za-z0-9_]*)\s*=/);
+    if (match) names.push(match[1]);
+  }
+  return Array.from(new Set(names));
+}
+
+/**
+ * Child keys of every `key:` block in YAML, as mapping keys or `- NAME=value` items
+ * @param {string} content - YAML content
+ * @param {string} key - Block key (`env`, `variables`, `environment`)
+ * @returns {string[]}
+ */
+function yamlBlockKeys(content, key) {
+  const lines = String(content).split('\n');
+  const names = [];
+  const opener = new RegExp(`^(\\s*)(?:-\\s+)?${key}:\\s*(?:#.*)?$`);
+  lines.forEach((line, i) => {
+    const open = line.match(opener);
+    if (!open) return;
+    const indent = open[1].length;
+    let level = null;
+    for (const child of lines.slice(i + 1)) {
+      if (!child.trim() || /^\s*#/.test(child)) continue;
+      const childIndent = child.match(/^\s*/)[0].length;
+      // Sequences may sit at the key's own indent
+      if (childIndent < indent || (childIndent === indent && !/^\s*-\s/.test(child))) break;
+      if (level === null) level = childIndent;
+      if (childIndent !== level) continue;
+      const mapping = child.match(/^\s*["']?([A-Za-z_][A-Za-z0-9_]*)["']?\s*:/);
+      const item = child.match(/^\s*-\s*["']?([A-Za-z_][A-Za-z0-9_]*)(?:=|["']?\s*$)/);
+      if (mapping) names.push(mapping[1]);
+      else if (item) names.push(item[1]);
+    }
+  });
+  return names;
+}
+
+/**
+ * Keys in TOML sections whose name matches
+ * @param {string} content - TOML content
+ * @param {RegExp} section - Section name pattern (without brackets)
+ * @returns {string[]}
+ */
+function tomlSectionKeys(content, section) {
+  const names = [];
+  let inSection = false;
+  for (const line of String(content).split('\n')) {
+    const header = line.match(/^\s*\[([^\]]+)\]\s*$/);
+    if (header) {
+      inSection = section.test(header[1].trim());
+      continue;
+    }
+    const key = inSection && line.match(/^\s*["']?([A-Za-z_][A-Za-z0-9_]*)["']?\s*=/);
+    if (key) names.push(key[1]);
+  }
+  return names;
+}
+
+/**
+ * Object keys at a path in JSON
+ * @param {string} content - JSON content
+ * @param {string[][]} paths - Key paths (`[['env'], ['build', 'env']]`)
+ * @returns {string[]}
+ */
+function jsonKeys(content, paths) {
+  let data;
+  try {
+    data = JSON.parse(content);
+  } catch {
+    return [];
+  }
+  return paths.flatMap(keys => {
+    const value = keys.reduce((node, keyName) => (node && typeof node === 'object' ? node[keyName] : undefined), data);
+    return value && typeof value === 'object' && !Array.isArray(value) ? Object.keys(value) : [];
+  });
+}
+
+/**
+ * Variables documented or set per file
+ * @param {string} basePath - Project root
+ * @returns {Array<{file: string, kind: string, names: string[]}>} kind is `example`, `ci`, or `deploy`
+ */
+function collectSources(basePath) {
+  const sources = [];
+  const add = (file, kind, names) => {
+    const valid = Array.from(new Set(names.filter(name => NAME.test(name))));
+    if (valid.length > 0) sources.push({ file, kind, names: valid });
+  };
+  const read = file => readText(basePath, file);
+
+  for (const file of EXAMPLE_FILES) {
+    const content = read(file);
+    if (content !== null) add(file, 'example', parseDotenv(content));
+  }
+
+  for (const name of listDir(basePath, '.github/workflows').filter(entry => /\.ya?ml$/.test(entry)).sort()) {
+    const file = `.github/workflows/${name}`;
+    const content = read(file) || '';
+    const references = Array.from(content.matchAll(/\$\{\{\s*(?:secrets|vars)\.([A-Za-z_][A-Za-z0-9_]*)\s*\}\}/g), match => match[1]);
+    add(file, 'ci', [...yamlBlockKeys(content, 'env'), ...references.filter(ref => ref !== 'GITHUB_TOKEN')]);
+  }
+  const gitlab = read('.gitlab-ci.yml');
+  if (gitlab) add('.gitlab-ci.yml', 'ci', yamlBlockKeys(gitlab, 'variables'));
+  const circle = read('.circleci/config.yml');
+  if (circle) add('.circleci/config.yml', 'ci', yamlBlockKeys(circle, 'environment'));
+
+  for (const file of DEPLOY_FILES) {
+    const content = read(file);
+    if (content === null) continue;
+    if (file === 'vercel.json') add(file, 'deploy', jsonKeys(content, [['env'], ['build', 'env']]));
+    else if (file === 'app.json') add(file, 'deploy', jsonKeys(content, [['env']]));
+    else if (file === 'netlify.toml') add(file, 'deploy', tomlSectionKeys(content, /(?:^|\.)environment$/));
+    else if (file === 'fly.toml') add(file, 'deploy', tomlSectionKeys(content, /^env$/));
+    else if (file === 'render.yaml') add(file, 'deploy', Array.from(content.matchAll(/^\s*-\s*key:\s*["']?([A-Za-z_][A-Za-z0-9_]*)/gm), match => match[1]));
+    else add(file, 'deploy', yamlBlockKeys(content, 'environment'));
+  }
+
+  for (const file of listDir(basePath, '.').sort()) {
+    if (COMPOSE_FILE.test(file)) {
+      add(file, 'deploy', yamlBlockKeys(read(file) || '', 'environment'));
+    } else if (DOCKERFILE.test(file)) {
+      // ENV instructions only; ARG values are build inputs, not runtime settings
+      const names = Array.from((read(file) || '').matchAll(/^\s*ENV\s+(.+)$/gim), match => match[1])
+        .flatMap(rest => (/=/.test(rest) ? Array.from(rest.matchAll(/([A-Za-z_][A-Za-z0-9_]*)=/g), m => m[1]) : [rest.split(/\s+/)[0]]));
+      add(file, 'deploy', names);
+    }
+  }
+  return sources;
+}
+
+/**
+ * Environment reads per variable from the repo map
+ * Files without an `env` entry (maps built before env extraction) are read
+ * and extracted directly. Test files are skipped.
+ * @param {Object} map - Repo map
+ * @param {Function} readFile - (file) => content or null
+ * @returns {{reads: Map<string, Array<{file: string, line: number, fallback: boolean}>>, dynamic: Array<{file: string, line: number}>}}
+ */
+function collectReads(map, readFile) {
+  const reads = new Map();
+  const dynamic = [];
+  for (const [file, entry] of Object.entries(map.files || {})) {
+    if (isTestFile(file)) continue;
+    const env = Array.isArray(entry.env) ? entry.env : extractEnvReads(entry.language, readFile(file) || '');
+    for (const read of env) {
+      if (read.dynamic) {
+        dynamic.push({ file, line: read.line });
+        continue;
+      }
+      if (!reads.has(read.name)) reads.set(read.name, []);
+      reads.get(read.name).push({ file, line: read.line, fallback: Boolean(read.fallback) });
+    }
+  }
+  return { reads, dynamic };
+}
+
+/**
+ * Check whether a variable comes from the OS, runtime, CI, or host
+ * @param {string} name - Variable name
+ * @returns {boolean}
+ */
+function isProvided(name) {
+  return PROVIDED_NAMES.has(name) || PROVIDED_PREFIXES.test(name);
+}
+
+/**
+ * Compare environment reads with documented variables
+ * @param {Object} map - Repo map
+ * @param {Array<{file: string, kind: string, names: string[]}>} sources - Result of `collectSources`
+ * @param {Object} [options]
+ * @param {Function} [options.readFile] - (file) => content, for files without map `env` entries
+ * @returns {Object} `{variables, undocumented, unused, exampleGaps, dynamic, sources}`
+ */
+function compareEnv(map, sources, options = {}) {
+  const { reads, dynamic } = collectReads(map, options.readFile || (() => null));
+  const documented = new Map();
+  for (const source of sources) {
+    for (const name of source.names) {
+      if (!documented.has(name)) documented.set(name, []);
+      documented.get(name).push({ file: source.file, kind: source.kind });
+    }
+  }
+
+  const variables = Array.from(reads.keys()).sort().map(name => ({
+    name,
+    reads: reads.get(name),
+    documented: documented.get(name) || [],
+    provided: isProvided(name)
+  }));
+
+  const undocumented = variables
+    .filter(variable => variable.documented.length === 0 && !variable.provided)
+    .map(variable => ({
+      name: variable.name,
+      reads: variable.reads,
+      severity: variable.reads.some(read => !read.fallback) ? 'high' : 'low'
+    }))
+    .sort((a, b) => (a.severity === b.severity ? a.name.localeCompare(b.name) : a.severity === 'high' ? -1 : 1));
+
+  // A computed read could be any variable, so nothing is unused when there is one
+  const unused = dynamic.length > 0 ? [] : Array.from(documented.entries())
+    .filter(([name, where]) => !reads.has(name) && !isProvided(name) && where.some(source => source.kind !== 'ci'))
+    .map(([name, where]) => ({ name, documented: where.filter(source => source.kind !== 'ci') }))
+    .sort((a, b) => a.name.localeCompare(b.name));
+
+  const hasExample = sources.some(source => source.kind === 'example');
+  const exampleGaps = hasExample
+    ? variables.filter(variable => !variable.provided && variable.documented.length > 0 && !variable.documented.some(source => source.kind === 'example'))
+      .map(variable => ({ name: variable.name, documented: variable.documented }))
+    : [];
+
+  return {
+    variables,
+    undocumented,
+    unused,
+    exampleGaps,
+    dynamic,
+    sources: sources.map(({ file, kind, names }) => ({ file, kind, count: names.length }))
+  };
+}
+
+/**
+ * Check environment variables for a project
+ * @param {string} basePath - Project root
+ * @param {Object} options
+ * @param {Object|null} options.map - Repo map
+ * @returns {Object} Result of `compareEnv` with `success`, or `{success: false, error}`
+ */
+function checkEnv(basePath, options = {}) {
+  if (!options.map) return { success: false, error: 'No repo-map found. Run /repo-map init first.' };
+  const report = compareEnv(options.map, collectSources(basePath), { readFile: file => readText(basePath, file) });
+  return { success: true, ...report };
+}
+
+/**
+ * Render the environment report as markdown
+ * @param {Object} report - Result of `checkEnv`
+ * @returns {string}
+ */
+function renderReport(report) {
+  const location = read => `\`${read.file}:${read.line}\``;
+  const lines = ['## Environment Check', ''];
+  lines.push(`**Variables read**: ${report.variables.length} | **Undocumented**: ${report.undocumented.length} | **Unused**: ${report.unused.length}`);
+  lines.push(`**Sources**: ${report.sources.map(source => `${source.file} (${source.kind})`).join(', ') || 'none found'}`, '');
+
+  if (report.undocumented.length) {
+    lines.push('### Used but undocumented', '', '| Variable | Default in code | Read at |', '|----------|-----------------|---------|');
+    for (const variable of report.undocumented) {
+      const reads = variable.reads.slice(0, 3).map(location).join(', ') + (variable.reads.length > 3 ? ` +${variable.reads.length - 3}` : '');
+      lines.push(`| \`${variable.name}\` | ${variable.severity === 'high' ? 'no' : 'yes'} | ${reads} |`);
+    }
+    lines.push('');
+  }
+  if (report.unused.length) {
+    lines.push('### Documented but unused', '', '| Variable | Documented in |', '|----------|---------------|');
+    for (const variable of report.unused) lines.push(`| \`${variable.name}\` | ${variable.documented.map(source => source.file).join(', ')} |`);
+    lines.push('');
+  }
+  if (report.exampleGaps.length) {
+    lines.push('### Missing from the example file', '', 'Set in CI or deploy configuration, but a local setup would not know about them.', '');
+    for (const variable of report.exampleGaps) lines.push(`- \`${variable.name}\` (${variable.documented.map(source => source.file).join(', ')})`);
+    lines.push('');
+  }
+  if (report.dynamic.length) {
+    lines.push(`**Computed reads** (unused check skipped): ${report.dynamic.map(location).join(', ')}`, '');
+  }
+  if (!report.undocumented.length && !report.unused.length && !report.exampleGaps.length) {
+    lines.push('Every variable read in code is documented, and every documented variable is read.');
+  }
+  return lines.join('\n');
+}
+
+if (require.main === module) {
+  const repoMap = require('../repo-map');
+  const report = checkEnv(process.cwd(), { map: repoMap.load(process.cwd()) });
+  const indent = process.stdout.isTTY ? 2 : 0;
+  console.log(JSON.stringify(report, null, indent));
+  if (!report.success) process.exitCode = 1;
+}
+
+module.exports = {
+  EXAMPLE_FILES,
+  parseDotenv,
+  yamlBlockKeys,
+  tomlSectionKeys,
+  collectSources,
+  collectReads,
+  isProvided,
+  compareEnv,
+  checkEnv,
+  renderReport
+};
@@ -0,0 +1,334 @@
+#!/usr/bin/env node
+/**
+ * Pre-commit Hook Installation
+ *
+ * Wires a staged-lines slop and secret scan (`detect.js --staged --quick`)
+ * into the hook manager the project already uses: pre-commit
+ * (`.pre-commit-config.yaml`), husky (`.husky/pre-commit`), or
+ * simple-git-hooks (`package.json`). Projects without one get pre-commit
+ * when they are Python, simple-git-hooks when they are Node, and a plain
+ * `.git/hooks/pre-commit` otherwise. The quick scan only reads staged
+ * source files with the regex patterns and returns before loading anything
+ * when a commit touches no source file, so typical commits finish in well
+ * under a second.
+ *
+ * `planHooks` only reads; `applyHooks` writes the files, and install
+ * commands (`npm install --save-dev ...`, `pre-commit install`) are returned
+ * for the caller to run.
+ *
+ * Usage: node lib/git-hooks/index.js [--framework pre-commit|husky|simple-git-hooks|git] [--fail-on <severity>]
+ * Output: JSON hook plan
+ *
+ * @module lib/git-hooks
+ */
+
+const fs = require('fs');
+const path = require('path');
+
+const VERSION = require('../../package.json').version;
+const exec = require('../utils/exec');
+
+const FRAMEWORKS = ['pre-commit', 'husky', 'simple-git-hooks', 'git'];
+
+const SEVERITIES = ['critical', 'high', 'medium', 'low'];
+
+/**
+ * Hook repository for pre-commit (`.pre-commit-hooks.yaml` at its root)
+ */
+const PRE_COMMIT_REPO = 'https://github.com/avifenesh/awesome-slash';
+const PRE_COMMIT_HOOK_ID = 'deslop';
+
+/**
+ * Scan flags shared by every hook; the pre-commit hook definition carries the same entry
+ */
+const SCAN_ARGS = ['--staged', '--quick', '--compact', '--redact'];
+
+const PYTHON_MARKERS = ['pyproject.toml', 'setup.py', 'setup.cfg', 'requirements.txt', 'Pipfile'];
+
+const LOCKFILE_MANAGERS = [
+  ['pnpm-lock.yaml', 'pnpm'],
+  ['yarn.lock', 'yarn'],
+  ['bun.lockb', 'bun'],
+  ['bun.lock', 'bun']
+];
+
+const DEV_INSTALL = {
+  npm: ['npm', 'install', '--save-dev'],
+  pnpm: ['pnpm', 'add', '--save-dev'],
+  yarn: ['yarn', 'add', '--dev'],
+  bun: ['bun', 'add', '--dev']
+};
+
+// An existing hook already running the scan
+const INSTALLED = /awesome-slash detect|detect\.js\b.*--staged/;
+
+/**
+ * Run a command and return stdout, or null on failure
+ * @param {string} basePath - Project root
+ * @param {string[]} argv - Command and arguments
+ * @returns {string|null}
+ */
+function run(basePath, argv) {
+  return exec.run(basePath, argv, { timeout: 30000 });
+}
+
+function readFile(basePath, file) {
+  try {
+    return fs.readFileSync(path.join(basePath, file), 'utf8');
+  } catch {
+    return null;
+  }
+}
+
+function readPackageJson(basePath) {
+  const content = readFile(basePath, 'package.json');
+  if (content === null) return null;
+  try {
+    return { content, data: JSON.parse(content) };
+  } catch {
+    return { content, data: null };
+  }
+}
+
+function hasDependency(pkg, name) {
+  return Boolean(pkg && pkg.data && ((pkg.data.devDependencies || {})[name] || (pkg.data.dependencies || {})[name]));
+}
+
+/**
+ * Pick the hook manager for a project
+ * @param {string} basePath - Project root
+ * @returns {{framework: string, reason: string}}
+ */
+function detectFramework(basePath) {
+  const exists = file => fs.existsSync(path.join(basePath, file));
+  const pkg = readPackageJson(basePath);
+
+  if (exists('.pre-commit-config.yaml')) return { framework: 'pre-commit', reason: '.pre-commit-config.yaml exists' };
+  if (exists('.husky') || hasDependency(pkg, 'husky')) return { framework: 'husky', reason: 'husky is set up' };
+  if (hasDependency(pkg, 'simple-git-hooks') || (pkg && pkg.data && pkg.data['simple-git-hooks'])) {
+    return { framework: 'simple-git-hooks', reason: 'simple-git-hooks is set up' };
+  }
+  if (pkg) return { framework: 'simple-git-hooks', reason: 'Node project without a hook manager' };
+  if (PYTHON_MARKERS.some(exists)) return { framework: 'pre-commit', reason: 'Python project without a hook manager' };
+  return { framework: 'git', reason: 'No hook manager or package manifest' };
+}
+
+/**
+ * Package manager from the lockfile
+ * @param {string} basePath - Project root
+ * @returns {string} npm, pnpm, yarn, or bun
+ */
+function detectPackageManager(basePath) {
+  const match = LOCKFILE_MANAGERS.find(([file]) => fs.existsSync(path.join(basePath, file)));
+  return match ? match[1] : 'npm';
+}
+
+/**
+ * Shell command a hook runs
+ * @param {string} framework - Hook manager
+ * @param {Object} options
+ * @param {string} options.failOn - Blocking severity
+ * @param {string} [options.detectPath] - detect.js for plain git hooks
+ * @returns {string}
+ */
+function hookCommand(framework, options) {
+  const args = [...SCAN_ARGS, '--fail-on', options.failOn].join(' ');
+  if (framework === 'git') return `node "${options.detectPath}" ${args}`;
+  return `npx --no-install awesome-slash detect ${args}`;
+}
+
+/**
+ * Append a command to a shell hook, unless something stops it from running
+ * @param {string|null} existing - Current hook
+ * @param {string} command - Command to add
+ * @param {string} header - Content of a new hook before the command
+ * @returns {{action: string, content?: string, reason?: string}}
+ */
+function appendToShellHook(existing, command, header) {
+  if (existing === null) return { action: 'create', content: `${header}${command}\n` };
+  if (INSTALLED.test(existing)) return { action: 'unchanged' };
+  if (/^\s*exec\s|^\s*exit\b/m.test(existing)) {
+    return { action: 'manual', reason: 'The hook exits or execs before the end; add the command yourself' };
+  }
+  return { action: 'update', content: `${existing.replace(/\s*$/, '\n')}${command}\n` };
+}
+
+/**
+ * pre-commit config with the hook repository added
+ * Appends to `repos:` when it is the last top-level key, which keeps the
+ * file's comments and layout; anything else is left to the user.
+ * @param {string|null} existing - Current `.pre-commit-config.yaml`
+ * @param {string} failOn - Blocking severity
+ * @returns {{action: string, content?: string, reason?: string, snippet: string}}
+ */
+function preCommitConfig(existing, failOn) {
+  const entry = indent => [
+    `${indent}- repo: ${PRE_COMMIT_REPO}`,
+    `${indent}  rev: v${VERSION}`,
+    `${indent}  hooks:`,
+    `${indent}    - id: ${PRE_COMMIT_HOOK_ID}`,
+    `${indent}      args: [--fail-on, ${failOn}]`
+  ].join('\n');
+  const snippet = entry('  ');
+
+  if (existing === null) return { action: 'create', content: `repos:\n${snippet}\n`, snippet };
+  if (existing.includes(PRE_COMMIT_REPO)) return { action: 'unchanged', snippet };
+
+  const lines = existing.split('\n');
+  const reposLine = lines.findIndex(line => /^repos:\s*(#.*)?$/.test(line));
+  const laterKey = reposLine >= 0 && lines.slice(reposLine + 1).some(line => /^[^\s#-]/.test(line));
+  if (reposLine < 0 || laterKey) {
+    return { action: 'manual', reason: '`repos:` is not the last top-level key; add the entry under it', snippet };
+  }
+  const item = lines.slice(reposLine + 1).find(line => /^\s*- /.test(line));
+  const indent = item ? item.match(/^(\s*)/)[1] : '  ';
+  return { action: 'update', content: `${existing.replace(/\s*$/, '\n')}${entry(indent)}\n`, snippet };
+}
+
+/**
+ * Plan the hook changes for a project
+ * @param {string} basePath - Project root
+ * @param {Object} [options]
+ * @param {string} [options.framework] - Hook manager (default: detected)
+ * @param {string} [options.failOn='critical'] - Blocking severity (secrets are critical)
+ * @param {string} [options.detectPath] - detect.js for plain git hooks (default: next to this library)
+ * @param {Function} [options.run] - `(basePath, argv) => stdout|null`
+ * @returns {Object} `{success, framework, reason, failOn, command, changes, commands, notes, error}`
+ */
+function planHooks(basePath, options = {}) {
+  const failOn = options.failOn || 'critical';
+  if (!SEVERITIES.includes(failOn)) {
+    return { success: false, error: `--fail-on must be one of ${SEVERITIES.join(', ')}` };
+  }
+  if (options.framework && !FRAMEWORKS.includes(options.framework)) {
+    return { success: false, error: `Unknown hook manager ${options.framework} (${FRAMEWORKS.join(', ')})` };
+  }
+  const runCommand = options.run || run;
+  const detected = options.framework ? { framework: options.framework, reason: 'requested' } : detectFramework(basePath);
+  const { framework } = detected;
+  const detectPath = options.detectPath || [
+    path.join(__dirname, '..', '..', 'scripts', 'detect.js'),
+    path.join(__dirname, '..', '..', 'plugins', 'deslop', 'scripts', 'detect.js')
+  ].find(file => fs.existsSync(file));
+  const command = hookCommand(framework, { failOn, detectPath });
+  const pkg = readPackageJson(basePath);
+  const manager = detectPackageManager(basePath);
+  const changes = [];
+  const commands = [];
+  const notes = [];
+  const devInstall = names => {
+    const missing = names.filter(name => !hasDependency(pkg, name));
+    if (missing.length) commands.push([...DEV_INSTALL[manager], ...missing]);
+  };
+
+  if (framework === 'pre-commit') {
+    const change = preCommitConfig(readFile(basePath, '.pre-commit-config.yaml'), failOn);
+    changes.push({ file: '.pre-commit-config.yaml', ...change });
+    commands.push(['pre-commit', 'install']);
+    notes.push('pre-commit installs the hook in its own Node environment; no project dependency is needed');
+  } else if (framework === 'husky') {
+    const existing = readFile(basePath, '.husky/pre-commit');
+    // husky 8 and older source their helper from each hook
+    const header = fs.existsSync(path.join(basePath, '.husky', '_', 'husky.sh'))
+      ? '#!/usr/bin/env sh\n. "$(dirname -- "$0")/_/husky.sh"\n\n'
+      : '';
+    changes.push({ file: '.husky/pre-commit', mode: 0o755, ...appendToShellHook(existing, command, header) });
+    devInstall(['husky', 'awesome-slash']);
+  } else if (framework === 'simple-git-hooks') {
+    if (!pkg || !pkg.data) {
+      return { success: false, framework, error: pkg ? 'package.json is not valid JSON' : 'simple-git-hooks needs a package.json' };
+    }
+    const data = JSON.parse(pkg.content);
+    const hooks = data['simple-git-hooks'] || {};
+    const current = hooks['pre-commit'];
+    if (current && INSTALLED.test(current)) {
+      changes.push({ file: 'package.json', action: 'unchanged' });
+    } else {
+      data['simple-git-hooks'] = { ...hooks, 'pre-commit': current ? `${current} && ${command}` : command };
+      // Teammates get the hook on install
+      if (!data.scripts || !data.scripts.prepare) data.scripts = { ...data.scripts, prepare: 'simple-git-hooks' };
+      const indentation = (pkg.content.match(/^[ \t]+(?=")/m) || ['  '])[0];
+      changes.push({ file: 'package.json', action: 'update', content: `${JSON.stringify(data, null, indentation)}\n` });
+    }
+    devInstall(['simple-git-hooks', 'awesome-slash']);
+    commands.push(['npx', 'simple-git-hooks']);
+    if (data.scripts && data.scripts.prepare && !data.scripts.prepare.includes('simple-git-hooks')) {
+      notes.push('The prepare script does not run simple-git-hooks; teammates must run `npx simple-git-hooks` once');
+    }
+  } else {
+    const hooksDir = (runCommand(basePath, ['git', 'rev-parse', '--git-path', 'hooks']) || '').trim();
+    if (!hooksDir) return { success: false, framework, error: 'Not a git repository' };
+    if (!detectPath) return { success: false, framework, error: 'detect.js not found; pass detectPath' };
+    const file = exec.relativePath(basePath, path.resolve(basePath, hooksDir, 'pre-commit'));
+    changes.push({ file, mode: 0o755, ...appendToShellHook(readFile(basePath, file), command, '#!/bin/sh\n') });
+    notes.push('Git hooks are local to this clone; use pre-commit or a Node hook manager to share it');
+  }
+
+  return { success: true, framework, reason: detected.reason, failOn, command, changes, commands, notes };
+}
+
+/**
+ * Write the planned hook files
+ * @param {string} basePath - Project root
+ * @param {Object} plan - Result of planHooks
+ * @returns {{written: string[]}}
+ */
+function applyHooks(basePath, plan) {
+  const written = [];
+  for (const change of plan.changes) {
+    if (change.action !== 'create' && change.action !== 'update') continue;
+    const target = path.join(basePath, change.file);
+    fs.mkdirSync(path.dirname(target), { recursive: true });
+    fs.writeFileSync(target, change.content);
+    if (change.mode) fs.chmodSync(target, change.mode);
+    written.push(change.file);
+  }
+  return { written };
+}
+
+/**
+ * Render a hook plan as markdown
+ * @param {Object} plan - Result of planHooks
+ * @returns {string}
+ */
+function renderPlan(plan) {
+  const lines = ['## Pre-commit Hook', ''];
+  lines.push(`**Manager**: ${plan.framework} (${plan.reason}) | **Blocks on**: ${plan.failOn} and above`);
+  lines.push(`**Runs**: \`${plan.command}\``, '');
+  lines.push('| File | Action |', '|------|--------|');
+  for (const change of plan.changes) {
+    lines.push(`| ${change.file} | ${change.action}${change.reason ? ` - ${change.reason}` : ''} |`);
+  }
+  const manual = plan.changes.find(change => change.action === 'manual' && change.snippet);
+  if (manual) lines.push('', 'Add to `.pre-commit-config.yaml` under `repos:`:', '', '```yaml', manual.snippet, '```');
+  if (plan.commands.length) {
+    lines.push('', '### Then run', '');
+    for (const argv of plan.commands) lines.push(`- \`${argv.join(' ')}\``);
+  }
+  if (plan.notes.length) lines.push('', ...plan.notes.map(note => `> ${note}`));
+  return lines.join('\n');
+}
+
+// When run directly, output the plan as JSON
+if (require.main === module) {
+  const args = process.argv.slice(2);
+  const value = flag => (args.includes(flag) ? args[args.indexOf(flag) + 1] : undefined);
+  const plan = planHooks(process.cwd(), { framework: value('--framework'), failOn: value('--fail-on') });
+  const indent = process.stdout.isTTY ? 2 : 0;
+  console.log(JSON.stringify(plan, null, indent));
+  if (!plan.success) process.exitCode = 1;
+}
+
+module.exports = {
+  FRAMEWORKS,
+  PRE_COMMIT_REPO,
+  PRE_COMMIT_HOOK_ID,
+  SCAN_ARGS,
+  detectFramework,
+  detectPackageManager,
+  hookCommand,
+  preCommitConfig,
+  planHooks,
+  applyHooks,
+  renderPlan
+};
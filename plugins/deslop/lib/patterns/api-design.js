@@ -0,0 +1,269 @@
+/**
+ * API Design Checks
+ *
+ * Support for the `api_design` review pattern set. Route definitions
+ * (Express/Fastify/Koa routers, FastAPI/Flask decorators, Spring mappings)
+ * are extracted line by line and checked for verbs in paths, singular
+ * resource names, and list endpoints without pagination. GraphQL resolvers
+ * are checked for per-parent queries that run once per item (N+1). Breaking
+ * changes to public APIs come from a repo-map symbol diff between two refs:
+ * exported symbols that were removed, renamed, unexported, or changed
+ * signature.
+ *
+ * Usage:
+ *   node api-design.js [--base <ref>] [file...]
+ *
+ * @module patterns/api-design
+ * @author Avi Fenesh
+ * @license MIT
+ */
+
+const fs = require('fs');
+const path = require('path');
+
+/**
+ * Route definitions; each yields the HTTP method and path
+ */
+const ROUTE_PATTERNS = [
+  // app.get('/users', ...), router.post(`/orders/:id`, ...)
+  { regex: /\b(?:app|router|server|api|fastify|routes?|\w+Router)\.(get|post|put|patch|delete)\(\s*(['"`])(\/[^'"`]*)\2/i, method: 1, path: 3 },
+  // @app.get("/users"), @router.post('/orders/{id}')
+  { regex: /^\s*@\w+\.(get|post|put|patch|delete)\(\s*["'](\/[^"']*)["']/i, method: 1, path: 2 },
+  // @app.route('/users', methods=['GET', 'POST'])
+  { regex: /^\s*@\w+\.route\(\s*["'](\/[^"']*)["'](?:[^)]*methods\s*=\s*[[(]\s*["'](\w+)["'])?/i, method: 2, path: 1 },
+  // @GetMapping("/users/{id}"), @PostMapping(value = "/orders")
+  { regex: /@(Get|Post|Put|Patch|Delete)Mapping\(\s*(?:(?:value|path)\s*=\s*)?\{?\s*"(\/[^"]*)"/, method: 1, path: 2 }
+];
+
+/**
+ * Path segments that name an action instead of a resource
+ */
+const VERB_SEGMENT = /^(?:get|create|update|delete|remove|add|fetch|list|set|save|edit|modify|insert|do)(?:[-_A-Z]|$)/;
+
+/**
+ * Static segments that are singular by design
+ */
+const SINGULAR_OK = /^(?:v\d+|api|me|auth|health|status|search|config|settings|profile|info|data|metadata|admin|graphql|login|logout|session|account|current)$/i;
+
+/**
+ * Parameters and calls that indicate a paginated list
+ */
+const PAGINATION_HINT = /\b(?:limit|offset|page|per_?page|page_?size|pageSize|cursor|take|skip|first|after|paginat\w*|Pageable)\b/i;
+
+/**
+ * A query keyed on the parent object inside a resolver, and the loaders that batch it
+ */
+const PER_PARENT_QUERY = /\b(?:parent|root|obj|source)\.\w+/;
+const QUERY_CALL = /\.(?:findUnique|findFirst|findOne|findById|findByPk|findAll|findMany|find|get|filter|query|fetch)\s*\(/;
+const BATCHED = /\bDataLoader\b|\.load(?:Many)?\s*\(/;
+const RESOLVER_FILE = /resolver/i;
+const RESOLVER_CONTENT = /\bresolvers?\b|@Resolver\b|@ResolveField\b|@strawberry\.(?:type|field)\b|graphene\.ObjectType/;
+
+/**
+ * Lines of a handler scanned for pagination hints
+ */
+const MAX_HANDLER_LINES = 40;
+
+/**
+ * Whether a path segment is a parameter (`:id`, `{id}`, `<int:id>`, `*`)
+ * @param {string} segment
+ * @returns {boolean}
+ */
+function isParam(segment) {
+  return /^(?::|\{|<|\*)/.test(segment);
+}
+
+/**
+ * Extract route definitions from a source file
+ * @param {string} content - File content
+ * @returns {Array<{method: string, path: string, line: number, body: string}>} body is the handler text up to the next route
+ */
+function extractRoutes(content) {
+  const lines = content.split('\n');
+  const routes = [];
+  lines.forEach((text, index) => {
+    for (const { regex, method, path: pathGroup } of ROUTE_PATTERNS) {
+      const match = text.match(regex);
+      if (!match) continue;
+      routes.push({ method: (match[method] || 'GET').toUpperCase(), path: match[pathGroup], line: index + 1 });
+      break;
+    }
+  });
+  routes.forEach((route, i) => {
+    const end = Math.min(i + 1 < routes.length ? routes[i + 1].line - 1 : lines.length, route.line - 1 + MAX_HANDLER_LINES);
+    route.body = lines.slice(route.line - 1, end).join('\n');
+  });
+  return routes;
+}
+
+/**
+ * Check route paths and handlers against REST conventions
+ * @param {Array<{method: string, path: string, line: number, body?: string}>} routes - Extracted routes
+ * @returns {Array<{line: number, rule: string, severity: string, message: string, suggestion: string}>}
+ */
+function checkRoutes(routes) {
+  const findings = [];
+  for (const route of routes) {
+    const segments = route.path.split('/').filter(Boolean);
+    const label = `${route.method} ${route.path}`;
+
+    const verb = segments.find(segment => !isParam(segment) && VERB_SEGMENT.test(segment));
+    if (verb) {
+      findings.push({
+        line: route.line,
+        rule: 'verb_in_path',
+        severity: 'low',
+        message: `${label}: "${verb}" names an action; the HTTP method already does`,
+        suggestion: 'Use a resource path and let the method carry the action (POST /users, not POST /createUser)'
+      });
+    }
+
+    segments.forEach((segment, i) => {
+      const next = segments[i + 1];
+      if (!next || !isParam(next) || isParam(segment) || segment === verb) return;
+      if (/s$/i.test(segment) || SINGULAR_OK.test(segment)) return;
+      findings.push({
+        line: route.line,
+        rule: 'singular_resource',
+        severity: 'low',
+        message: `${label}: collection "${segment}" is singular`,
+        suggestion: `Use plural collection names (/${segment}s/{id})`
+      });
+    });
+
+    const last = segments[segments.length - 1];
+    const isCollection = last && !isParam(last) && /s$/i.test(last) && !SINGULAR_OK.test(last) && last !== verb;
+    if (route.method === 'GET' && isCollection && !PAGINATION_HINT.test(route.body || '')) {
+      findings.push({
+        line: route.line,
+        rule: 'missing_pagination',
+        severity: 'medium',
+        message: `${label}: list endpoint without pagination`,
+        suggestion: 'Accept limit/cursor (or page/pageSize) parameters and cap the page size'
+      });
+    }
+  }
+  return findings;
+}
+
+/**
+ * Find resolver lines that query per parent object without batching
+ * @param {string} content - File content
+ * @param {string} file - File path
+ * @returns {Array<{line: number, rule: string, severity: string, message: string, suggestion: string}>}
+ */
+function checkResolvers(content, file) {
+  if (!RESOLVER_FILE.test(file) && !RESOLVER_CONTENT.test(content)) return [];
+  const findings = [];
+  content.split('\n').forEach((text, index) => {
+    if (!PER_PARENT_QUERY.test(text) || !QUERY_CALL.test(text) || BATCHED.test(text)) return;
+    findings.push({
+      line: index + 1,
+      rule: 'graphql_n_plus_one',
+      severity: 'medium',
+      message: 'Resolver queries once per parent object (N+1 across a list)',
+      suggestion: 'Batch with a DataLoader (loader.load(parent.id)) or fetch the relation in the parent query'
+    });
+  });
+  return findings;
+}
+
+/**
+ * Check files for REST route and GraphQL resolver issues
+ * @param {string} repoPath - Repository root
+ * @param {string[]} files - Files to check (relative to repoPath)
+ * @param {Object} [options]
+ * @param {Function} [options.readFile] - File reader (for testing)
+ * @returns {Array<{file: string, line: number, rule: string, severity: string, message: string, suggestion: string}>}
+ */
+function checkApiDesign(repoPath, files, options = {}) {
+  const readFile = options.readFile || (file => fs.readFileSync(path.isAbsolute(file) ? file : path.join(repoPath, file), 'utf8'));
+  const findings = [];
+  for (const file of files) {
+    if (!/\.(?:[cm]?[jt]sx?|py|java|kt)$/.test(file)) continue;
+    let content;
+    try {
+      content = readFile(file);
+    } catch {
+      continue;
+    }
+    for (const finding of [...checkRoutes(extractRoutes(content)), ...checkResolvers(content, file)]) {
+      findings.push({ file, ...finding });
+    }
+  }
+  return findings;
+}
+
+/**
+ * Breaking changes to public API symbols in a repo-map diff
+ * @param {Object} diff - Result of repo-map `diffRefs`
+ * @param {Object} [options]
+ * @param {Function} [options.includeFile] - (file) => boolean; limits the check to public API files
+ * @returns {Array<{file: string, line: number|null, name: string, kind: string, change: string, before?: string, after?: string}>}
+ */
+function findBreakingChanges(diff, options = {}) {
+  if (!diff || !diff.symbols) return [];
+  const include = options.includeFile || (() => true);
+  const changes = [];
+
+  for (const item of diff.symbols.removed || []) {
+    if (item.exported && include(item.file)) {
+      changes.push({ file: item.file, line: item.line, name: item.name, kind: item.kind, change: 'removed' });
+    }
+  }
+  for (const item of diff.symbols.renamed || []) {
+    if (item.exported && include(item.file)) {
+      changes.push({ file: item.file, line: item.line, name: item.name, kind: item.kind, change: 'renamed', before: item.from.name });
+    }
+  }
+  for (const item of diff.symbols.changed || []) {
+    if (!item.before.exported || !include(item.file)) continue;
+    if (!item.exported) {
+      changes.push({ file: item.file, line: item.line, name: item.name, kind: item.kind, change: 'unexported' });
+    } else if ((item.before.signature || '') !== (item.signature || '')) {
+      changes.push({
+        file: item.file,
+        line: item.line,
+        name: item.name,
+        kind: item.kind,
+        change: 'signature',
+        before: item.before.signature || '',
+        after: item.signature || ''
+      });
+    }
+  }
+  return changes;
+}
+
+module.exports = {
+  extractRoutes,
+  checkRoutes,
+  checkResolvers,
+  checkApiDesign,
+  findBreakingChanges
+};
+
+// CLI usage
+if (require.main === module) {
+  const args = process.argv.slice(2);
+  const baseIndex = args.indexOf('--base');
+  const base = baseIndex >= 0 ? args[baseIndex + 1] : null;
+  const repoPath = process.cwd();
+  let files = baseIndex >= 0 ? args.filter((arg, i) => i !== baseIndex && i !== baseIndex + 1) : args;
+  if (files.length === 0) {
+    const tracked = require('../utils/exec').git(repoPath, ['ls-files']);
+    if (tracked === null) {
+      console.error('Error: git ls-files failed; run inside a git repository or pass the files to check');
+      process.exit(1);
+    }
+    files = tracked.split('\n').filter(Boolean);
+  }
+
+  const output = { findings: checkApiDesign(repoPath, files) };
+  if (base) {
+    const diff = require('../repo-map').diff(repoPath, { base });
+    output.breakingChanges = diff.success ? findBreakingChanges(diff) : null;
+    if (!diff.success) output.error = diff.error;
+  }
+  console.log(JSON.stringify(output, null, 2));
+}
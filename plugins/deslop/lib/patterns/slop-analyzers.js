@@ -8,6 +8,10 @@
  * @license MIT
  */
 
+const ignore = require('../utils/ignore');
+const exec = require('../utils/exec');
+const { t } = require('../messages');
+
 /**
  * Analyze JSDoc-to-function ratio to detect excessive documentation
  *
@@ -428,6 +432,87 @@ function analyzeVerbosityRatio(content, options = {}) {
   return violations;
 }
 
+/**
+ * Words ignored when comparing a comment with the line it describes
+ */
+const RESTATING_FILLER = new Set([
+  'a', 'an', 'the', 'this', 'that', 'these', 'those', 'it', 'its', 'we', 'our',
+  'to', 'of', 'for', 'from', 'in', 'into', 'on', 'at', 'with', 'and', 'or',
+  'is', 'are', 'be', 'then', 'now', 'here', 'new', 'value', 'variable'
+]);
+
+/**
+ * Comments that are directives or markers rather than descriptions
+ */
+const COMMENT_DIRECTIVE = /\b(?:TODO|FIXME|HACK|XXX|NOTE)\b|eslint|prettier|istanbul|noqa|pylint|nolint|type:\s*ignore|@ts-|c8 ignore|#region|#endregion/i;
+
+/**
+ * Split text into lowercase words, breaking identifiers on camelCase and snake_case
+ * @param {string} text
+ * @returns {string[]}
+ */
+function splitWords(text) {
+  return text
+    .replace(/([a-z\d])([A-Z])/g, '$1 $2')
+    .replace(/([A-Z]+)([A-Z][a-z])/g, '$1 $2')
+    .toLowerCase()
+    .split(/[^a-z\d]+/)
+    .filter(Boolean);
+}
+
+/**
+ * Analyze single-line comments that only restate the following line
+ *
+ * Flags a short comment when every meaningful word in it also appears in the
+ * next line's identifiers or keywords, e.g. `// get user by id` above
+ * `const user = getUserById(id);`. Comments inside a run of comment lines, or
+ * above a line that opens a block, are skipped - they usually explain or
+ * label a section rather than narrate a statement.
+ *
+ * @param {string} content - File content to analyze
+ * @param {Object} options - Analysis options
+ * @param {number} [options.minWords=2] - Minimum meaningful words in the comment
+ * @param {number} [options.maxWords=8] - Longer comments are treated as explanations
+ * @param {string} [options.filePath] - File path for language detection
+ * @returns {Array<Object>} Array of violations: { line, comment, code }
+ */
+function analyzeRestatingComments(content, options = {}) {
+  const minWords = options.minWords || 2;
+  const maxWords = options.maxWords || 8;
+  const lang = detectCommentLanguage(options.filePath);
+  const commentLine = lang === 'python' ? /^\s*#(?![!#])\s*(.*)$/ : /^\s*\/\/(?![/!])\s*(.*)$/;
+  const isComment = COMMENT_SYNTAX[lang].line;
+  const lines = content.split('\n');
+  const violations = [];
+
+  for (let i = 0; i < lines.length - 1; i++) {
+    const match = lines[i].match(commentLine);
+    if (!match) continue;
+    if (i > 0 && isComment.test(lines[i - 1])) continue;
+
+    // A comment above a block summarizes the block, not the line
+    const next = lines[i + 1];
+    if (!next.trim() || isComment.test(next) || /[{([:]\s*$|=>\s*$/.test(next)) continue;
+
+    const text = match[1].trim();
+    if (COMMENT_DIRECTIVE.test(text)) continue;
+
+    const words = splitWords(text);
+    if (words.length > maxWords) continue;
+    const meaningful = words.filter(word => !RESTATING_FILLER.has(word));
+    if (meaningful.length < minWords) continue;
+
+    const codeWords = new Set(splitWords(next));
+    const restated = meaningful.every(word =>
+      codeWords.has(word) || (word.endsWith('s') && codeWords.has(word.slice(0, -1))));
+    if (restated) {
+      violations.push({ line: i + 1, comment: text, code: next.trim() });
+    }
+  }
+
+  return violations;
+}
+
 // ============================================================================
 // Over-Engineering Detection
 // ============================================================================
@@ -492,14 +577,11 @@ const SOURCE_EXTENSIONS = {
 /**
  * Directories to exclude from analysis
  */
-const EXCLUDE_DIRS = [
-  'node_modules', 'vendor', 'dist', 'build', 'out', 'target',
-  '.git', '.svn', '.hg', '__pycache__', '.pytest_cache',
-  'coverage', '.nyc_output', '.next', '.nuxt', '.cache'
-];
+const EXCLUDE_DIRS = ignore.DEFAULT_EXCLUDE_DIRS;
 
 /**
  * Parse .gitignore file and return a matcher function
+ * Only reads .gitignore; walkers use ignore.createIgnoreFilter for the full rule set.
  * @param {string} repoPath - Repository root path
  * @param {Object} fs - File system module
  * @param {Object} path - Path module
@@ -515,90 +597,9 @@ function parseGitignore(repoPath, fs, path) {
     return null; // No .gitignore file
   }
 
-  const patterns = content
-    .split('\n')
-    .map(line => line.trim())
-    .filter(line => line && !line.startsWith('#'))
-    .map(rawPattern => {
-      let pattern = rawPattern;
-
-      // Track if pattern is negated
-      const negated = pattern.startsWith('!');
-      if (negated) pattern = pattern.slice(1);
-
-      // Track if pattern is directory-only
-      const dirOnly = pattern.endsWith('/');
-      if (dirOnly) pattern = pattern.slice(0, -1);
-
-      // Track if pattern is anchored (explicit / at start)
-      const anchored = pattern.startsWith('/');
-      if (anchored) pattern = pattern.slice(1);
-
-      // Check if pattern starts with ** (matches any leading path)
-      const matchesAnywhere = pattern.startsWith('**/');
-
-      // Convert gitignore pattern to regex
-      // Step 1: Replace globstar patterns with placeholders (before escaping)
-      let regexStr = pattern
-        .replace(/^\*\*\//, '\x00LEADING\x00')
-        .replace(/\/\*\*$/, '\x00TRAILING\x00')
-        .replace(/\*\*\//g, '\x00ANYPATH\x00')
-        .replace(/\/\*\*/g, '\x00ANYPATH2\x00')
-        .replace(/\*\*/g, '\x00STAR2\x00');
-
-      // Step 2: Escape special regex chars (except * and ?) and handle remaining globs
-      regexStr = regexStr
-        .replace(/[.+^${}()|[\]\\]/g, '\\$&')
-        .replace(/\*/g, '[^/]*')
-        .replace(/\?/g, '[^/]');
-
-      // Step 3: Restore globstar patterns with proper regex
-      regexStr = regexStr
-        .replace(/\x00LEADING\x00/g, '(?:.*/)?')
-        .replace(/\x00TRAILING\x00/g, '(?:/.*)?')
-        .replace(/\x00ANYPATH\x00/g, '(?:.*/)?')
-        .replace(/\x00ANYPATH2\x00/g, '(?:/.*)?')
-        .replace(/\x00STAR2\x00/g, '.*');
-
-      // Pattern matching rules:
-      // - Anchored (starts with /): match from root only
-      // - Starts with **: match anywhere (leading ** already handled in regex)
-      // - Contains /: match relative to root
-      // - Simple name: match anywhere in path
-      if (anchored) {
-        regexStr = '^' + regexStr;
-      } else if (matchesAnywhere) {
-        // Leading ** already allows matching anywhere via (?:.*/)? prefix
-        regexStr = '^' + regexStr;
-      } else if (pattern.includes('/')) {
-        regexStr = '^' + regexStr;
-      } else {
-        // Simple patterns match anywhere
-        regexStr = '(?:^|/)' + regexStr;
-      }
-
-      return {
-        regex: new RegExp(regexStr + '(?:$|/)'),
-        negated,
-        dirOnly
-      };
-    });
-
+  const patterns = ignore.parseIgnoreContent(content);
   return function isIgnored(relativePath, isDirectory = false) {
-    // Normalize path separators
-    const normalized = relativePath.replace(/\\/g, '/');
-    let ignored = false;
-
-    for (const { regex, negated, dirOnly } of patterns) {
-      // Skip directory-only patterns for files
-      if (dirOnly && !isDirectory) continue;
-
-      if (regex.test(normalized)) {
-        ignored = !negated;
-      }
-    }
-
-    return ignored;
+    return ignore.matchPatterns(patterns, relativePath, isDirectory);
   };
 }
 
@@ -663,6 +664,65 @@ function isTestFile(filePath) {
   return testPatterns.some(p => p.test(filePath));
 }
 
+/**
+ * Find Rust `#[cfg(test)]` items (usually the inline `mod tests { ... }`)
+ *
+ * Braces are counted outside strings, char literals and `//` comments; an
+ * item without a body (e.g. `#[cfg(test)] use ...;`) ends at its semicolon.
+ *
+ * @param {string} content - Rust source
+ * @returns {Array<[number, number]>} 1-based inclusive line ranges
+ */
+function findRustTestRanges(content) {
+  const lines = content.split('\n');
+  const ranges = [];
+
+  for (let start = 0; start < lines.length; start++) {
+    const attribute = lines[start].match(/^\s*#\[cfg\((?:all\()?test\b[^\]]*\]/);
+    if (!attribute) continue;
+
+    let depth = 0;
+    let opened = false;
+    let inString = false;
+    let end = -1;
+    for (let i = start; i < lines.length && end === -1; i++) {
+      const line = lines[i];
+      for (let j = i === start ? attribute.index + attribute[0].length : 0; j < line.length; j++) {
+        const char = line[j];
+        if (inString) {
+          if (char === '\\') j++;
+          else if (char === '"') inString = false;
+          continue;
+        }
+        if (char === '/' && line[j + 1] === '/') break;
+        if (char === '"') {
+          inString = true;
+        } else if (char === "'" && /^'(?:\\.|[^\\'])'/.test(line.slice(j, j + 4))) {
+          j += line[j + 1] === '\\' ? 3 : 2;
+        } else if (char === '{') {
+          depth++;
+          opened = true;
+        } else if (char === '}') {
+          depth--;
+          if (opened && depth === 0) {
+            end = i;
+            break;
+          }
+        } else if (char === ';' && !opened) {
+          end = i;
+          break;
+        }
+      }
+    }
+
+    if (end === -1) end = lines.length - 1;
+    ranges.push([start + 1, end + 1]);
+    start = end;
+  }
+
+  return ranges;
+}
+
 /**
  * Count source files in directory (recursive, excludes tests/vendor by default)
  *
@@ -673,6 +733,8 @@ function isTestFile(filePath) {
  * @param {number} options.maxFiles - Maximum files to count (default 10000)
  * @param {boolean} options.includeTests - Include test files (default false)
  * @param {boolean} options.respectGitignore - Respect .gitignore patterns (default true)
+ * @param {string[]} options.ignore - Extra gitignore-style globs to skip
+ * @param {Function} options.skip - Content filter from scan-limits `createFileFilter`; skipped files don't count
  * @returns {Object} { count, files[] }
  */
 function countSourceFiles(repoPath, options = {}) {
@@ -687,8 +749,8 @@ function countSourceFiles(repoPath, options = {}) {
   // Pre-compute extension list for performance (avoid recalculation in loop)
   const allExts = Object.values(SOURCE_EXTENSIONS).flat();
 
-  // Parse .gitignore if enabled
-  const isIgnored = respectGitignore ? parseGitignore(repoPath, fs, path) : null;
+  // .gitignore (if enabled), .awesome-slashignore, and caller globs
+  const isIgnored = ignore.createIgnoreFilter(repoPath, { fs, path, respectGitignore, patterns: options.ignore });
 
   function walk(dir, depth = 0) {
     if (count >= maxFiles) return;
@@ -707,10 +769,7 @@ function countSourceFiles(repoPath, options = {}) {
       const fullPath = path.join(dir, entry.name);
       const relativePath = path.relative(repoPath, fullPath);
 
-      if (shouldExclude(relativePath)) continue;
-
-      // Check gitignore
-      if (isIgnored && isIgnored(relativePath, entry.isDirectory())) continue;
+      if (isIgnored(relativePath, entry.isDirectory())) continue;
 
       if (entry.isDirectory()) {
         walk(fullPath, depth + 1);
@@ -718,6 +777,7 @@ function countSourceFiles(repoPath, options = {}) {
         const ext = path.extname(entry.name);
 
         if (allExts.includes(ext) && (includeTests || !isTestFile(relativePath))) {
+          if (options.skip && options.skip(relativePath)) continue;
           files.push(relativePath);
           count++;
         }
@@ -893,17 +953,19 @@ function getMaxDirectoryDepth(repoPath, startDir = 'src', options = {}) {
   const path = options.path || require('path');
 
   const rootDir = path.join(repoPath, startDir);
+  const isIgnored = ignore.createIgnoreFilter(repoPath, { fs, path, patterns: options.ignore });
   let maxDepth = 0;
 
-  function walk(dir, depth) {
+  function walk(dir, relativeDir, depth) {
     if (depth > maxDepth) maxDepth = depth;
     if (depth > 20) return; // Safety limit
 
     try {
       const entries = fs.readdirSync(dir, { withFileTypes: true });
       for (const entry of entries) {
-        if (entry.isDirectory() && !shouldExclude(entry.name)) {
-          walk(path.join(dir, entry.name), depth + 1);
+        const relativePath = `${relativeDir}/${entry.name}`;
+        if (entry.isDirectory() && !isIgnored(relativePath, true)) {
+          walk(path.join(dir, entry.name), relativePath, depth + 1);
         }
       }
     } catch {
@@ -914,7 +976,7 @@ function getMaxDirectoryDepth(repoPath, startDir = 'src', options = {}) {
   try {
     const stat = fs.statSync(rootDir);
     if (stat.isDirectory()) {
-      walk(rootDir, 1);
+      walk(rootDir, startDir.replace(/\\/g, '/').replace(/\/+$/, ''), 1);
     }
   } catch {
     return 0;
@@ -1245,6 +1307,7 @@ function extractClaims(content, filePath, buzzwordCategories = BUZZWORD_CATEGORI
 function findClaimSourceFiles(repoPath, options = {}) {
   const fs = options.fs || require('fs');
   const path = options.path || require('path');
+  const isIgnored = ignore.createIgnoreFilter(repoPath, { fs, path, patterns: options.ignore });
 
   const files = [];
   const docPatterns = [
@@ -1274,7 +1337,7 @@ function findClaimSourceFiles(repoPath, options = {}) {
       const fullPath = path.join(dir, entry.name);
       const relativePath = path.relative(repoPath, fullPath);
 
-      if (shouldExclude(relativePath)) continue;
+      if (isIgnored(relativePath, entry.isDirectory())) continue;
 
       if (entry.isDirectory()) {
         walk(fullPath, depth + 1);
@@ -1438,7 +1501,7 @@ function detectGaps(claims, repoPath, evidencePatterns, minEvidenceMatches, file
         evidenceCount: evidence.total,
         evidenceRequired: minEvidenceMatches,
         severity: evidence.total === 0 ? 'high' : 'medium',
-        message: `Claim "${claim.buzzword}" without sufficient evidence (found ${evidence.total}/${minEvidenceMatches} required)`
+        message: t('finding.buzzword', { buzzword: claim.buzzword, found: evidence.total, required: minEvidenceMatches })
       });
     }
   }
@@ -1668,7 +1731,7 @@ function analyzeInfrastructureWithoutImplementation(rootPath, options = {}) {
         type: setup.type,
         content: setup.content,
         severity: 'high',
-        message: `Infrastructure component "${setup.varName}" (${setup.type}) is created but never used`
+        message: t('finding.unusedInfrastructure', setup)
       });
     }
   }
@@ -1884,17 +1947,17 @@ function analyzeDeadCode(content, options = {}) {
  * @param {Object} options - Analysis options
  * @param {number} [options.commitLimit=100] - Number of commits to analyze
  * @param {number} [options.clusterThreshold=5] - Min files changed together to flag
- * @param {Function} [options.execSync] - Command executor (for testing)
+ * @param {Function} [options.execSync] - `(cwd, argv, options)` executor like lib/utils/exec `execSync` (for testing)
  * @returns {Object} Analysis results: { clusters, violations, verdict }
  */
 function analyzeShotgunSurgery(repoPath, options = {}) {
-  // Validate commitLimit to prevent command injection
+  // Validate commitLimit
   let commitLimit = parseInt(options.commitLimit, 10) || 100;
   if (!Number.isInteger(commitLimit) || commitLimit < 1 || commitLimit > 10000) {
     commitLimit = 100;
   }
   const clusterThreshold = options.clusterThreshold || 5;
-  const execSync = options.execSync || require('child_process').execSync;
+  const execSync = options.execSync || exec.execSync;
   const path = options.path || require('path');
 
   const violations = [];
@@ -1902,10 +1965,7 @@ function analyzeShotgunSurgery(repoPath, options = {}) {
 
   try {
     // Get commit hashes with file changes
-    const logResult = execSync(
-      `git log --name-only --pretty=format:"COMMIT:%H" -n ${commitLimit}`,
-      { cwd: repoPath, encoding: 'utf8', maxBuffer: 10 * 1024 * 1024 }
-    );
+    const logResult = execSync(repoPath, ['git', 'log', '--name-only', '--pretty=format:COMMIT:%H', '-n', String(commitLimit)], {});
 
     // Parse commits and their files
     const commits = [];
@@ -1989,7 +2049,7 @@ function analyzeShotgunSurgery(repoPath, options = {}) {
         coupledCount,
         coupledWith,
         severity: coupledCount >= clusterThreshold * 2 ? 'high' : 'medium',
-        message: `"${file}" changes with ${coupledCount} other files frequently (shotgun surgery indicator)`
+        message: t('finding.shotgunCoupling', { file, count: coupledCount })
       });
     }
 
@@ -2233,6 +2293,7 @@ function analyzeStubFunctionsPython(content) {
 module.exports = {
   analyzeDocCodeRatio,
   analyzeVerbosityRatio,
+  analyzeRestatingComments,
   analyzeOverEngineering,
   analyzeBuzzwordInflation,
   analyzeInfrastructureWithoutImplementation,
@@ -2251,6 +2312,7 @@ module.exports = {
   detectCommentLanguage,
   shouldExclude,
   isTestFile,
+  findRustTestRanges,
   parseGitignore,
   // Buzzword inflation helpers (for testing)
   extractClaims,
@@ -2265,6 +2327,7 @@ module.exports = {
   SOURCE_EXTENSIONS,
   EXCLUDE_DIRS,
   COMMENT_SYNTAX,
+  RESTATING_FILLER,
   // Buzzword inflation constants
   BUZZWORD_CATEGORIES,
   EVIDENCE_PATTERNS,
@@ -0,0 +1,245 @@
+#!/usr/bin/env node
+/**
+ * Test Framework Detection
+ * Identifies test frameworks and how the project runs its tests so workflows
+ * can run the right command instead of guessing from the project type
+ *
+ * Usage: node lib/platform/detect-tests.js [path]
+ * Output: JSON with detected frameworks and test commands (or null)
+ *
+ * @module lib/platform/detect-tests
+ */
+
+const fs = require('fs');
+const path = require('path');
+
+const {
+  PACKAGE_MANAGER_CONFIGS,
+  TEST_FRAMEWORK_CONFIGS,
+  TEST_COMMAND_CONFIGS
+} = require('./detection-configs');
+const { collectDependencies, dependencyMatches, readText, listDir } = require('./detect-database');
+
+const fsPromises = fs.promises;
+
+/**
+ * Maximum test modules inspected for `imports` patterns
+ */
+const MAX_IMPORT_SCAN_FILES = 50;
+
+/**
+ * Check whether a path exists relative to the project root
+ * @param {string} basePath - Project root
+ * @param {string} file - Relative path
+ * @returns {Promise<boolean>}
+ */
+async function exists(basePath, file) {
+  try {
+    await fsPromises.access(path.join(basePath, file));
+    return true;
+  } catch {
+    return false;
+  }
+}
+
+/**
+ * Escape a string for use in a RegExp
+ * @param {string} text - Literal text
+ * @returns {string}
+ */
+function escapeRegExp(text) {
+  return text.replace(/[.*+?^${}()|[\]\\]/g, '\\$&');
+}
+
+/**
+ * Targets defined in a Makefile
+ * @param {string} content - Makefile content
+ * @returns {string[]}
+ */
+function parseMakeTargets(content) {
+  const targets = new Set();
+  for (const match of content.matchAll(/^([A-Za-z0-9_.-]+(?:[ \t]+[A-Za-z0-9_.-]+)*)[ \t]*:(?!=)/gm)) {
+    for (const target of match[1].split(/\s+/)) {
+      if (!target.startsWith('.')) targets.add(target);
+    }
+  }
+  return Array.from(targets);
+}
+
+/**
+ * Find a test module matching an `imports` pattern
+ * @param {string} basePath - Project root
+ * @param {{dirs: string[], pattern: RegExp}} imports - Directories and import pattern
+ * @returns {Promise<string|null>} Relative path of the first match
+ */
+async function findImport(basePath, imports) {
+  let scanned = 0;
+  for (const dir of imports.dirs) {
+    const entries = (await listDir(basePath, dir))
+      .filter(entry => entry.isFile() && /^test.*\.py$|_test\.py$/.test(entry.name))
+      .map(entry => entry.name)
+      .sort();
+    for (const name of entries) {
+      if (scanned++ >= MAX_IMPORT_SCAN_FILES) return null;
+      const file = dir === '.' ? name : `${dir}/${name}`;
+      const content = await readText(basePath, file);
+      if (content && imports.pattern.test(content)) return file;
+    }
+  }
+  return null;
+}
+
+/**
+ * Detect test frameworks from dependencies, config files, and scripts
+ * @param {string} basePath - Project root
+ * @param {Array<Object>} dependencies - Result of `collectDependencies`
+ * @param {Object<string, string>} scripts - package.json scripts
+ * @returns {Promise<Array<{name: string, language: string, sources: string[]}>>}
+ */
+async function detectFrameworks(basePath, dependencies, scripts) {
+  const frameworks = [];
+  for (const config of TEST_FRAMEWORK_CONFIGS) {
+    const sources = dependencies
+      .filter(dep => dep.ecosystem === config.ecosystem &&
+        (config.dependencies || []).some(expected => dependencyMatches(dep.ecosystem, dep.name, expected)))
+      .map(dep => `${dep.file}:${dep.name}`);
+
+    for (const file of config.files || []) {
+      if (await exists(basePath, file)) sources.push(file);
+    }
+    for (const [file, pattern] of Object.entries(config.contains || {})) {
+      const content = await readText(basePath, file);
+      if (content && pattern.test(content)) sources.push(file);
+    }
+    if (config.ecosystem === 'npm') {
+      const invoked = new RegExp(`(?:^|[\\s/&;|(])${escapeRegExp(config.name)}(?:\\s|$)`);
+      for (const [key, script] of Object.entries(scripts)) {
+        if (typeof script === 'string' && invoked.test(script)) sources.push(`package.json#scripts.${key}`);
+      }
+    }
+    if (config.imports) {
+      const file = await findImport(basePath, config.imports);
+      if (file) sources.push(file);
+    }
+
+    if (sources.length > 0) {
+      frameworks.push({ name: config.name, language: config.language, sources: Array.from(new Set(sources)) });
+    }
+  }
+  return frameworks;
+}
+
+/**
+ * Default command for a detected framework
+ * @param {string} basePath - Project root
+ * @param {Object} framework - Detected framework
+ * @param {Object} config - Framework config
+ * @returns {Promise<string|null>}
+ */
+async function frameworkCommand(basePath, framework, config) {
+  if (framework.name === 'junit') {
+    const gradle = framework.sources.some(source => source.startsWith('build.gradle'));
+    if (gradle) return (await exists(basePath, 'gradlew')) ? './gradlew test' : 'gradle test';
+    return (await exists(basePath, 'mvnw')) ? './mvnw test' : 'mvn test';
+  }
+  if (!config.command) return null;
+  if (framework.language === 'python') {
+    for (const { file, prefix } of TEST_COMMAND_CONFIGS.pythonRunners) {
+      if (await exists(basePath, file)) return `${prefix} ${config.command}`;
+    }
+  }
+  return config.command;
+}
+
+/**
+ * Detect how tests are invoked, most specific first
+ * Order: package.json `test` script, Makefile target, tox, framework defaults.
+ * @param {string} basePath - Project root
+ * @param {Object<string, string>} scripts - package.json scripts
+ * @param {Array<Object>} frameworks - Result of `detectFrameworks`
+ * @returns {Promise<Array<{command: string, source: string}>>}
+ */
+async function detectTestCommands(basePath, scripts, frameworks) {
+  const commands = [];
+  const add = (command, source) => {
+    if (command && !commands.some(existing => existing.command === command)) commands.push({ command, source });
+  };
+
+  if (typeof scripts.test === 'string' && !TEST_COMMAND_CONFIGS.placeholderScript.test(scripts.test)) {
+    let manager = 'npm';
+    for (const { file, manager: candidate } of PACKAGE_MANAGER_CONFIGS) {
+      if (['pnpm', 'yarn', 'bun', 'npm'].includes(candidate) && await exists(basePath, file)) {
+        manager = candidate;
+        break;
+      }
+    }
+    // `bun test` runs Bun's own test runner, not the script
+    add(manager === 'bun' ? 'bun run test' : `${manager} test`, 'package.json#scripts.test');
+  }
+
+  const makefile = await readText(basePath, 'Makefile');
+  if (makefile) {
+    const targets = parseMakeTargets(makefile);
+    const target = TEST_COMMAND_CONFIGS.makeTargets.find(name => targets.includes(name));
+    if (target) add(`make ${target}`, 'Makefile');
+  }
+
+  if (await exists(basePath, 'tox.ini')) {
+    add('tox', 'tox.ini');
+  } else {
+    const pyproject = await readText(basePath, 'pyproject.toml');
+    if (pyproject && /^\[tool\.tox\]/m.test(pyproject)) add('tox', 'pyproject.toml');
+  }
+
+  for (const framework of frameworks) {
+    const config = TEST_FRAMEWORK_CONFIGS.find(candidate => candidate.name === framework.name);
+    add(await frameworkCommand(basePath, framework, config), `framework:${framework.name}`);
+  }
+  return commands;
+}
+
+/**
+ * Detect test frameworks and test commands
+ * @param {string} [basePath=process.cwd()] - Project root (or a workspace package)
+ * @returns {Promise<Object|null>} `{frameworks, command, commands}` or null when nothing is found
+ */
+async function detectTestFrameworks(basePath = process.cwd()) {
+  let scripts = {};
+  const pkg = await readText(basePath, 'package.json');
+  if (pkg) {
+    try {
+      const parsed = JSON.parse(pkg);
+      if (parsed && typeof parsed.scripts === 'object' && parsed.scripts) scripts = parsed.scripts;
+    } catch {
+      // Invalid package.json: dependencies are skipped the same way
+    }
+  }
+
+  const dependencies = await collectDependencies(basePath);
+  const frameworks = await detectFrameworks(basePath, dependencies, scripts);
+  const commands = await detectTestCommands(basePath, scripts, frameworks);
+
+  if (frameworks.length === 0 && commands.length === 0) {
+    return null;
+  }
+
+  return {
+    frameworks,
+    command: commands.length > 0 ? commands[0].command : null,
+    commands
+  };
+}
+
+// When run directly, output JSON
+if (require.main === module) {
+  detectTestFrameworks(process.argv[2] || process.cwd()).then(result => {
+    const indent = process.stdout.isTTY ? 2 : 0;
+    console.log(JSON.stringify(result, null, indent));
+  });
+}
+
+module.exports = {
+  detectTestFrameworks,
+  detectTestCommands,
+  parseMakeTargets
+};
@@ -0,0 +1,99 @@
+/**
+ * C/C++ symbol details (macros, storage class, class bases)
+ *
+ * @module lib/repo-map/details/c
+ */
+
+'use strict';
+
+const { splitArgs, maskComments, createLineLookup, compact } = require('./utils');
+
+const DEFINE = /^[ \t]*#[ \t]*define[ \t]+([A-Za-z_]\w*)(\()?/gm;
+const IFNDEF = /^[ \t]*#[ \t]*(?:ifndef[ \t]+([A-Za-z_]\w*)|if[ \t]+!defined\(?[ \t]*([A-Za-z_]\w*))/gm;
+const CLASS_HEAD = /^[ \t]*(?:template[ \t]*<[^>]*>\s*)?(class|struct)[ \t]+(?:\w+[ \t]+)*?([A-Za-z_]\w*)(?:[ \t]+final)?[ \t]*:([^{;]+)\{/gm;
+
+/**
+ * Parse preprocessor macros, skipping include guards
+ * @param {string} content - File content
+ * @returns {Array<{name: string, line: number, params: boolean}>}
+ */
+function parseMacros(content) {
+  const text = maskComments(content);
+  const lineAt = createLineLookup(text);
+  const guards = new Set();
+  let match;
+
+  IFNDEF.lastIndex = 0;
+  while ((match = IFNDEF.exec(text)) !== null) {
+    guards.add(match[1] || match[2]);
+  }
+
+  const macros = [];
+  DEFINE.lastIndex = 0;
+  while ((match = DEFINE.exec(text)) !== null) {
+    const name = match[1];
+    const lineEnd = text.indexOf('\n', match.index);
+    const rest = text.slice(match.index + match[0].length, lineEnd === -1 ? undefined : lineEnd).trim();
+    if (guards.has(name) && !match[2] && !rest) continue;
+    macros.push({ name, line: lineAt(match.index), params: Boolean(match[2]) });
+  }
+  return macros;
+}
+
+/**
+ * Parse C++ class/struct base clauses
+ * @param {string} content - File content
+ * @returns {Map<string, string[]>} - class name -> bases
+ */
+function parseClassBases(content) {
+  const text = maskComments(content);
+  const bases = new Map();
+  let match;
+  CLASS_HEAD.lastIndex = 0;
+  while ((match = CLASS_HEAD.exec(text)) !== null) {
+    const list = splitArgs(match[3], { angles: true })
+      .map(item => compact(item.replace(/\b(?:public|protected|private|virtual)\b/g, '')))
+      .filter(Boolean);
+    if (list.length > 0) bases.set(match[2], list);
+  }
+  return bases;
+}
+
+/**
+ * Attach macros, `static`/`inline` storage, and C++ bases to symbols
+ * @param {string} content - File content
+ * @param {Object} symbolMaps - Symbol maps
+ */
+function applyCDetails(content, symbolMaps) {
+  const lines = content.split('\n');
+
+  if (symbolMaps.constants) {
+    for (const macro of parseMacros(content)) {
+      if (symbolMaps.constants.has(macro.name)) continue;
+      const entry = { name: macro.name, line: macro.line, kind: 'macro' };
+      if (macro.params) entry.params = true;
+      symbolMaps.constants.set(macro.name, entry);
+    }
+  }
+
+  if (symbolMaps.functions) {
+    for (const entry of symbolMaps.functions.values()) {
+      const text = lines[(entry.line || 0) - 1] || '';
+      if (/^\s*(?:inline\s+)?static\b|^\s*static\s+inline\b/.test(text)) entry.storage = 'static';
+      if (/^\s*(?:static\s+)?inline\b/.test(text)) entry.inline = true;
+    }
+  }
+
+  if (symbolMaps.classes) {
+    const bases = parseClassBases(content);
+    for (const entry of symbolMaps.classes.values()) {
+      if (bases.has(entry.name)) entry.bases = bases.get(entry.name);
+    }
+  }
+}
+
+module.exports = {
+  applyCDetails,
+  parseMacros,
+  parseClassBases
+};
@@ -0,0 +1,120 @@
+/**
+ * Environment variable reads
+ *
+ * Finds reads of environment variables by name (`process.env.PORT`,
+ * `os.Getenv("PORT")`, `os.environ.get("PORT", "8080")`, `ENV.fetch`,
+ * `System.getenv`, `env::var`, ...) outside comments. A read with a default
+ * value (`|| '8080'`, `?? x`, a second argument to `getenv`/`get`/`fetch`)
+ * is marked `fallback`. Reads through a computed name cannot be resolved
+ * and are recorded as `dynamic`.
+ *
+ * @module lib/repo-map/env
+ */
+
+'use strict';
+
+const { maskComments, createLineLookup } = require('./details/utils');
+
+const HASH_COMMENT_LANGUAGES = new Set(['python', 'ruby']);
+
+const NAME = '([A-Za-z_][A-Za-z0-9_]*)';
+const QUOTED = `["'\`]${NAME}["'\`]`;
+
+/**
+ * Read patterns per language
+ * `pattern` captures the variable name (1); `args` matches a second argument
+ * after the name (a default value); `fallback` matches an operator that
+ * follows the read. `dynamic` patterns match reads through a computed name.
+ */
+const ENV_PATTERNS = {
+  javascript: [
+    { pattern: new RegExp(`\\b(?:process\\.env|import\\.meta\\.env)\\.${NAME}\\b`, 'g'), fallback: /^\s*(?:\|\||\?\?)/ },
+    { pattern: new RegExp(`\\b(?:process\\.env|import\\.meta\\.env)\\[\\s*${QUOTED}\\s*\\]`, 'g'), fallback: /^\s*(?:\|\||\?\?)/ },
+    { pattern: new RegExp(`\\b(?:Deno\\.env\\.get|Bun\\.env\\.get)\\(\\s*${QUOTED}\\s*\\)`, 'g'), fallback: /^\s*(?:\|\||\?\?)/ },
+    { pattern: /\b(?:process\.env|import\.meta\.env)\[\s*(?!["'`])[^\]]/g, dynamic: true }
+  ],
+  python: [
+    { pattern: new RegExp(`\\bos\\.environ\\[\\s*${QUOTED}\\s*\\]`, 'g') },
+    { pattern: new RegExp(`\\b(?:os\\.environ\\.get|os\\.getenv|environ\\.get|getenv)\\(\\s*${QUOTED}`, 'g'), args: /^\s*,/ },
+    { pattern: /\bos\.(?:environ\.get|getenv)\(\s*(?!["'])[A-Za-z_]/g, dynamic: true }
+  ],
+  go: [
+    { pattern: new RegExp(`\\bos\\.(?:Getenv|LookupEnv)\\(\\s*"${NAME}"\\s*\\)`, 'g') },
+    { pattern: /\bos\.(?:Getenv|LookupEnv)\(\s*(?!")[A-Za-z_]/g, dynamic: true }
+  ],
+  rust: [
+    { pattern: new RegExp(`\\b(?:std::)?env::var(?:_os)?\\(\\s*"${NAME}"\\s*\\)`, 'g'), fallback: /^\s*\.\s*(?:unwrap_or|unwrap_or_else|unwrap_or_default)\b/ },
+    { pattern: new RegExp(`\\b(?:env|option_env)!\\(\\s*"${NAME}"`, 'g') }
+  ],
+  ruby: [
+    { pattern: new RegExp(`\\bENV\\[\\s*${QUOTED}\\s*\\]`, 'g'), fallback: /^\s*\|\|/ },
+    { pattern: new RegExp(`\\bENV\\.fetch\\(\\s*${QUOTED}`, 'g'), args: /^\s*(?:,|\)\s*\{)/ }
+  ],
+  java: [
+    { pattern: new RegExp(`\\bSystem\\.getenv\\(\\s*"${NAME}"\\s*\\)`, 'g') }
+  ],
+  kotlin: [
+    { pattern: new RegExp(`\\bSystem\\.getenv\\(\\s*"${NAME}"\\s*\\)`, 'g'), fallback: /^\s*\?:/ }
+  ],
+  csharp: [
+    { pattern: new RegExp(`\\bEnvironment\\.GetEnvironmentVariable\\(\\s*"${NAME}"`, 'g'), fallback: /^\s*\)\s*\?\?/ }
+  ],
+  c: [
+    { pattern: new RegExp(`\\b(?:getenv|secure_getenv)\\(\\s*"${NAME}"\\s*\\)`, 'g') }
+  ]
+};
+ENV_PATTERNS.typescript = ENV_PATTERNS.javascript;
+ENV_PATTERNS.cpp = ENV_PATTERNS.c;
+
+// Destructuring: const { API_URL, PORT = 3000 } = process.env
+const JS_DESTRUCTURE = /\b(?:const|let|var)\s*\{([^}]*)\}\s*=\s*(?:process\.env|import\.meta\.env)\b/g;
+
+/**
+ * Extract environment variable reads from file content
+ * @param {string} language - Language name
+ * @param {string} content - File content
+ * @returns {Array<{name: string|null, line: number, fallback: boolean, dynamic?: boolean}>} Sorted by line
+ */
+function extractEnvReads(language, content) {
+  const patterns = ENV_PATTERNS[language];
+  if (!patterns || !content) return [];
+  const masked = maskComments(content, { hashComments: HASH_COMMENT_LANGUAGES.has(language) });
+  const lineAt = createLineLookup(masked);
+  const reads = [];
+  const seen = new Set();
+  const add = (name, index, fallback, dynamic) => {
+    const line = lineAt(index);
+    const key = `${name}:${line}`;
+    if (seen.has(key)) return;
+    seen.add(key);
+    reads.push(dynamic ? { name: null, line, fallback: false, dynamic: true } : { name, line, fallback });
+  };
+
+  for (const { pattern, args, fallback, dynamic } of patterns) {
+    pattern.lastIndex = 0;
+    let match;
+    while ((match = pattern.exec(masked)) !== null) {
+      const rest = masked.slice(match.index + match[0].length, match.index + match[0].length + 40);
+      const hasDefault = Boolean((args && args.test(rest)) || (fallback && fallback.test(rest)));
+      add(dynamic ? null : match[1], match.index, hasDefault, dynamic);
+    }
+  }
+
+  if (language === 'javascript' || language === 'typescript') {
+    JS_DESTRUCTURE.lastIndex = 0;
+    let match;
+    while ((match = JS_DESTRUCTURE.exec(masked)) !== null) {
+      for (const part of match[1].split(',')) {
+        const binding = part.match(/^\s*([A-Za-z_][A-Za-z0-9_]*)\s*(?::\s*[\w$]+\s*)?(=)?/);
+        if (binding && binding[1]) add(binding[1], match.index, Boolean(binding[2]));
+      }
+    }
+  }
+
+  return reads.sort((a, b) => a.line - b.line || String(a.name).localeCompare(String(b.name)));
+}
+
+module.exports = {
+  ENV_PATTERNS,
+  extractEnvReads
+};
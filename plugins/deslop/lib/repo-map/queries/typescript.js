@@ -9,22 +9,47 @@ const javascript = require('./javascript');
 module.exports = {
   exports: [
     ...javascript.exports,
+    { pattern: 'export function $NAME($$$): $RET { $$$ }', kind: 'function', nameVar: 'NAME' },
+    { pattern: 'export async function $NAME($$$): $RET { $$$ }', kind: 'function', nameVar: 'NAME' },
+    { pattern: 'export function $NAME<$$$>($$$) { $$$ }', kind: 'function', nameVar: 'NAME' },
+    { pattern: 'export function $NAME<$$$>($$$): $RET { $$$ }', kind: 'function', nameVar: 'NAME' },
+    { pattern: 'export const $NAME: $TYPE = $$$', kind: 'constant', nameVar: 'NAME' },
+    { pattern: 'export class $NAME<$$$> { $$$ }', kind: 'class', nameVar: 'NAME' },
+    { pattern: 'export abstract class $NAME { $$$ }', kind: 'class', nameVar: 'NAME' },
     { pattern: 'export interface $NAME { $$$ }', kind: 'type', nameVar: 'NAME' },
+    { pattern: 'export interface $NAME<$$$> { $$$ }', kind: 'type', nameVar: 'NAME' },
     { pattern: 'export type $NAME = $$$', kind: 'type', nameVar: 'NAME' },
+    { pattern: 'export type $NAME<$$$> = $$$', kind: 'type', nameVar: 'NAME' },
     { pattern: 'export enum $NAME { $$$ }', kind: 'type', nameVar: 'NAME' },
     { pattern: 'export namespace $NAME { $$$ }', kind: 'type', nameVar: 'NAME' },
     { pattern: 'export const enum $NAME { $$$ }', kind: 'type', nameVar: 'NAME' },
+    { pattern: 'export declare function $NAME($$$): $RET', kind: 'function', nameVar: 'NAME' },
+    { pattern: 'export declare const $NAME: $TYPE', kind: 'constant', nameVar: 'NAME' },
     { pattern: 'export = $NAME', kind: 'value', nameVar: 'NAME' },
     { pattern: 'export as namespace $NAME', kind: 'namespace', nameVar: 'NAME' }
   ],
-  functions: javascript.functions,
+  functions: [
+    ...javascript.functions,
+    { pattern: 'function $NAME($$$): $RET { $$$ }', nameVar: 'NAME' },
+    { pattern: 'async function $NAME($$$): $RET { $$$ }', nameVar: 'NAME' },
+    { pattern: 'function $NAME<$$$>($$$) { $$$ }', nameVar: 'NAME' },
+    { pattern: 'function $NAME<$$$>($$$): $RET { $$$ }', nameVar: 'NAME' },
+    { pattern: 'const $NAME = ($$$): $RET => $$$', nameVar: 'NAME' },
+    { pattern: 'const $NAME = async ($$$): $RET => $$$', nameVar: 'NAME' },
+    { pattern: 'const $NAME = <$$$>($$$) => $$$', nameVar: 'NAME' },
+    { pattern: 'const $NAME: $TYPE = ($$$) => $$$', nameVar: 'NAME' }
+  ],
   classes: [
     ...javascript.classes,
-    { pattern: 'abstract class $NAME { $$$ }', nameVar: 'NAME' }
+    { pattern: 'class $NAME<$$$> { $$$ }', nameVar: 'NAME' },
+    { pattern: 'abstract class $NAME { $$$ }', nameVar: 'NAME' },
+    { pattern: 'abstract class $NAME<$$$> { $$$ }', nameVar: 'NAME' }
   ],
   types: [
     { pattern: 'interface $NAME { $$$ }', nameVar: 'NAME' },
+    { pattern: 'interface $NAME<$$$> { $$$ }', nameVar: 'NAME' },
     { pattern: 'type $NAME = $$$', nameVar: 'NAME' },
+    { pattern: 'type $NAME<$$$> = $$$', nameVar: 'NAME' },
     { pattern: 'enum $NAME { $$$ }', nameVar: 'NAME' },
     { pattern: 'namespace $NAME { $$$ }', nameVar: 'NAME' },
     { pattern: 'const enum $NAME { $$$ }', nameVar: 'NAME' }
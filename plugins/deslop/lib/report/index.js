@@ -0,0 +1,562 @@
+#!/usr/bin/env node
+/**
+ * HTML Report
+ *
+ * Renders slop detection output, review queues, /deps-audit reports, SARIF,
+ * and coverage into one self-contained HTML file: no external scripts,
+ * styles, or fonts, so it can be uploaded as a CI artifact and opened
+ * offline by readers who do not use the CLI. Findings are read the way
+ * /issue reads them and merged by fingerprint; each carries the flagged
+ * lines from the working tree, except secrets, whose content is never
+ * copied into the report. Coverage comes from lcov, Cobertura, Go, or
+ * coverage.py reports (mapped to functions when a repo map exists) or from
+ * a saved `repoMap.coverage()` summary.
+ *
+ * Usage: node lib/report/index.js <results>... [--output report.html] [--title TEXT]
+ * Output: The HTML file; a one-line summary on stdout
+ *
+ * @module lib/report
+ */
+
+const fs = require('fs');
+const path = require('path');
+
+const issues = require('../issues');
+const coverageReport = require('../repo-map/coverage');
+const { slopPatterns } = require('../patterns/slop-patterns');
+const { t, getLocale } = require('../messages');
+const exec = require('../utils/exec');
+
+const DEFAULT_OUTPUT = 'report.html';
+
+const SEVERITIES = issues.SEVERITIES;
+
+const SEVERITY_COLORS = {
+  critical: '#b60205',
+  high: '#d93f0b',
+  medium: '#c99a06',
+  low: '#2c974b'
+};
+
+/**
+ * Lines shown around a flagged line
+ */
+const SNIPPET_CONTEXT = 2;
+
+/**
+ * Findings that get a snippet; the rest link to their location only
+ */
+const MAX_SNIPPETS = 500;
+
+const MAX_SNIPPET_LINE = 240;
+
+/**
+ * Rows per coverage table
+ */
+const COVERAGE_ROWS = 50;
+
+/**
+ * Rules whose flagged line may hold a credential (SARIF from other tools)
+ */
+const SECRET_RULE = /secret|credential|password|private[-_ ]?key|token|api[-_ ]?key/i;
+
+/**
+ * Run a command and return stdout, or null on failure
+ * @param {string} basePath - Repository root
+ * @param {string[]} argv - Command and arguments
+ * @returns {string|null}
+ */
+function run(basePath, argv) {
+  return exec.run(basePath, argv, { timeout: 30000 });
+}
+
+/**
+ * Escape text for HTML content and attribute values
+ * @param {*} value
+ * @returns {string}
+ */
+function escapeHtml(value) {
+  return String(value === null || value === undefined ? '' : value)
+    .replace(/&/g, '&amp;')
+    .replace(/</g, '&lt;')
+    .replace(/>/g, '&gt;')
+    .replace(/"/g, '&quot;')
+    .replace(/'/g, '&#39;');
+}
+
+/**
+ * Whether a finding's flagged line must stay out of the report
+ * @param {Object} finding - Normalized finding
+ * @returns {boolean}
+ */
+function isSecret(finding) {
+  if ((slopPatterns[finding.rule] || {}).category === 'secrets') return true;
+  return finding.source === 'security' && finding.tool !== 'review' && SECRET_RULE.test(finding.rule);
+}
+
+/**
+ * Lines around a finding from the working tree
+ * Lines flagged as secrets by any finding are masked, so one next to a
+ * secret does not show it as context.
+ * @param {string} basePath - Repository root
+ * @param {Object} finding - Normalized finding with `file` and `line`
+ * @param {Map<string, string[]|null>} cache - File lines by path
+ * @param {Set<string>} [hidden] - `file:line` of secret findings
+ * @returns {{start: number, line: number, lines: string[]}|null}
+ */
+function snippetOf(basePath, finding, cache, hidden = new Set()) {
+  if (!finding.file || !finding.line || isSecret(finding)) return null;
+  if (!cache.has(finding.file)) {
+    try {
+      cache.set(finding.file, fs.readFileSync(path.join(basePath, finding.file), 'utf8').split('\n'));
+    } catch {
+      cache.set(finding.file, null);
+    }
+  }
+  const lines = cache.get(finding.file);
+  if (!lines || finding.line > lines.length) return null;
+  const start = Math.max(1, finding.line - SNIPPET_CONTEXT);
+  const end = Math.min(lines.length, finding.line + SNIPPET_CONTEXT);
+  return {
+    start,
+    line: finding.line,
+    lines: lines.slice(start - 1, end).map((text, i) => {
+      if (hidden.has(`${finding.file}:${start + i}`)) return t('html.redacted');
+      return text.length > MAX_SNIPPET_LINE ? `${text.slice(0, MAX_SNIPPET_LINE)}…` : text;
+    })
+  };
+}
+
+/**
+ * Coverage summary of a raw report
+ * With a repo map, uncovered lines are mapped to functions as /coverage
+ * does; without one only files are summarized.
+ * @param {string} basePath - Repository root
+ * @param {string} name - Report path, for display
+ * @param {string} content - Report content
+ * @returns {Object|null} summarize() fields plus `report` and `format`, or null when not a coverage report
+ */
+function coverageOf(basePath, name, content) {
+  const parsed = coverageReport.parseReport(content);
+  if (!parsed.format) return null;
+  let map = null;
+  try {
+    map = require('../repo-map').load(basePath);
+  } catch {
+    map = null;
+  }
+  const root = path.resolve(basePath).replace(/\\/g, '/');
+  const reported = Object.keys(parsed.files).map(file => {
+    const normalized = file.replace(/\\/g, '/').replace(/^\.\//, '');
+    return normalized.startsWith(`${root}/`) ? normalized.slice(root.length + 1) : normalized;
+  });
+  const mapFiles = map && map.files ? Object.keys(map.files) : reported;
+  const { files, unmatched } = coverageReport.resolvePaths(parsed.files, mapFiles, basePath);
+  return { report: name, format: parsed.format, ...coverageReport.summarize(map || { files: {} }, files), unmatched };
+}
+
+/**
+ * Classify and read one input
+ * @param {string} basePath - Repository root
+ * @param {string} name - Input path, for display
+ * @param {string} content - Input content
+ * @returns {{kind: string, findings?: Object[], coverage?: Object, deps?: Object|Object[]}|null}
+ *   `kind` is `slop`, `review`, `deps`, `sarif`, or `coverage`; null when not recognized
+ */
+function readInput(basePath, name, content) {
+  let data = null;
+  if (coverageReport.detectFormat(content) !== 'coveragepy') {
+    try {
+      data = JSON.parse(content);
+    } catch {
+      data = null;
+    }
+  }
+  if (!data) {
+    const coverage = coverageOf(basePath, name, content);
+    return coverage ? { kind: 'coverage', coverage } : null;
+  }
+  if (data.totals && Array.isArray(data.files)) {
+    return { kind: 'coverage', coverage: { report: data.report || name, functions: [], ...data } };
+  }
+
+  // A monorepo audit holds one full report per workspace package
+  if (Array.isArray(data.packages) && data.packages.some(pkg => pkg && pkg.report && Array.isArray(pkg.report.managers))) {
+    const reports = data.packages.filter(pkg => pkg.report && pkg.report.success).map(pkg => ({ ...pkg.report, package: pkg.name }));
+    return { kind: 'deps', findings: reports.flatMap(report => issues.fromDeps(report)), deps: reports };
+  }
+
+  const findings = issues.parseFindings(basePath, data);
+  if (!findings) return null;
+  if (Array.isArray(data.runs)) return { kind: 'sarif', findings };
+  if (Array.isArray(data.vulnerabilities) && Array.isArray(data.managers)) return { kind: 'deps', findings, deps: data };
+  const review = Array.isArray(data.items) || (Array.isArray(data) && !data.some(item => item && item.patternName));
+  return { kind: review ? 'review' : 'slop', findings };
+}
+
+/**
+ * Collect report data from scanner outputs
+ * @param {string} basePath - Repository root
+ * @param {{name: string, content: string}[]} inputs - Files to include
+ * @param {Object} [options]
+ * @param {Function} [options.run] - Command runner, for tests
+ * @returns {Object} `{inputs, findings, counts, sources, coverage, deps, commit, errors}`;
+ *   `counts` is findings per severity, `sources` is `{source: {severity: count}}`
+ */
+function collectReport(basePath, inputs, options = {}) {
+  const runCommand = options.run || run;
+  const collected = [];
+  const coverage = [];
+  const deps = [];
+  const errors = [];
+  const read = [];
+
+  for (const { name, content } of inputs) {
+    const input = readInput(basePath, name, content);
+    if (!input) {
+      errors.push(t('html.unrecognized', { file: name }));
+      continue;
+    }
+    read.push({ name, kind: input.kind });
+    if (input.findings) collected.push(...input.findings);
+    if (input.coverage) coverage.push(input.coverage);
+    if (input.deps) deps.push(...[].concat(input.deps));
+  }
+
+  const cache = new Map();
+  const hidden = new Set(collected.filter(finding => finding.file && finding.line && isSecret(finding)).map(finding => `${finding.file}:${finding.line}`));
+  const findings = issues.dedupeFindings(collected).map((finding, index) => ({
+    ...finding,
+    snippet: index < MAX_SNIPPETS ? snippetOf(basePath, finding, cache, hidden) : null
+  }));
+
+  const counts = Object.fromEntries(SEVERITIES.map(severity => [severity, 0]));
+  const sources = {};
+  for (const finding of findings) {
+    counts[finding.severity]++;
+    if (!sources[finding.source]) sources[finding.source] = Object.fromEntries(SEVERITIES.map(severity => [severity, 0]));
+    sources[finding.source][finding.severity]++;
+  }
+
+  return {
+    inputs: read,
+    findings,
+    counts,
+    sources,
+    coverage,
+    deps,
+    commit: (runCommand(basePath, ['git', 'rev-parse', 'HEAD']) || '').trim() || null,
+    errors
+  };
+}
+
+/**
+ * Horizontal bar chart of findings per severity
+ * @param {Object<string, number>} counts - Findings per severity
+ * @returns {string} Inline SVG
+ */
+function severityChart(counts) {
+  const max = Math.max(1, ...SEVERITIES.map(severity => counts[severity] || 0));
+  const width = 360;
+  const bars = SEVERITIES.map((severity, i) => {
+    const count = counts[severity] || 0;
+    const y = i * 28;
+    return [
+      `<text x="0" y="${y + 17}">${escapeHtml(t(`html.severity.${severity}`))}</text>`,
+      `<rect x="80" y="${y + 4}" width="${Math.round((count / max) * width)}" height="18" rx="3" fill="${SEVERITY_COLORS[severity]}"></rect>`,
+      `<text x="${88 + Math.round((count / max) * width)}" y="${y + 17}">${count}</text>`
+    ].join('');
+  });
+  return `<svg class="chart" role="img" aria-label="${escapeHtml(t('html.chart'))}" viewBox="0 0 480 ${SEVERITIES.length * 28}" width="480" height="${SEVERITIES.length * 28}">${bars.join('')}</svg>`;
+}
+
+/**
+ * Coverage bar
+ * @param {number|null} percent
+ * @returns {string}
+ */
+function meter(percent) {
+  if (percent === null || percent === undefined) return '-';
+  const tone = percent >= 80 ? 'good' : percent >= 50 ? 'fair' : 'poor';
+  return `<span class="meter"><span class="${tone}" style="width:${Math.max(0, Math.min(100, percent))}%"></span></span> ${percent}%`;
+}
+
+/**
+ * Text filter and selects above a table
+ * @param {string} table - Table id
+ * @param {Object<string, string[]>} [selects] - Row attribute -> values
+ * @param {number} total - Rows in the table
+ * @returns {string}
+ */
+function filterBar(table, total, selects = {}) {
+  const controls = [`<input type="search" data-field="text" placeholder="${escapeHtml(t('html.filter.text'))}">`];
+  for (const [field, values] of Object.entries(selects)) {
+    const options = values.map(value => `<option value="${escapeHtml(value)}">${escapeHtml(field === 'severity' ? t(`html.severity.${value}`) : value)}</option>`);
+    controls.push(`<select data-field="${field}"><option value="">${escapeHtml(t(`html.filter.${field}`))}</option>${options.join('')}</select>`);
+  }
+  const template = escapeHtml(t('html.filter.shown', { shown: '{shown}', total: '{total}' }));
+  return `<div class="filters" data-table="${table}">${controls.join('')}<span class="shown" data-template="${template}">${escapeHtml(t('html.filter.shown', { shown: total, total }))}</span></div>`;
+}
+
+/**
+ * Table markup
+ * @param {string} id - Table id
+ * @param {string[]} headers - Column headers (already translated)
+ * @param {string[]} rows - `<tr>` markup
+ * @returns {string}
+ */
+function table(id, headers, rows) {
+  return `<table id="${id}"><thead><tr>${headers.map(header => `<th>${escapeHtml(header)}</th>`).join('')}</tr></thead><tbody>${rows.join('')}</tbody></table>`;
+}
+
+/**
+ * Findings section
+ * @param {Object} report - Result of collectReport
+ * @returns {string}
+ */
+function findingsSection(report) {
+  const lines = [`<section id="findings"><h2>${escapeHtml(t('html.findings'))}</h2>`];
+  if (report.findings.length === 0) {
+    lines.push(`<p>${escapeHtml(t('html.noFindings'))}</p></section>`);
+    return lines.join('');
+  }
+
+  const present = SEVERITIES.filter(severity => report.counts[severity] > 0);
+  lines.push(filterBar('findings-table', report.findings.length, { severity: present, source: Object.keys(report.sources).sort() }));
+  const rows = report.findings.map(finding => {
+    const location = finding.file ? `${finding.file}${finding.line ? `:${finding.line}` : ''}` : '-';
+    let where = `<code>${escapeHtml(location)}</code>`;
+    if (finding.snippet) {
+      const code = finding.snippet.lines.map((text, i) => {
+        const number = finding.snippet.start + i;
+        return `<span class="${number === finding.snippet.line ? 'hit' : ''}"><i>${number}</i>${escapeHtml(text)}</span>`;
+      });
+      where = `<details><summary>${where}</summary><pre>${code.join('\n')}</pre></details>`;
+    }
+    const extra = [finding.occurrences > 1 ? t('html.occurrences', { count: finding.occurrences }) : null, finding.suggestion]
+      .filter(Boolean).map(text => `<div class="note">${escapeHtml(text)}</div>`).join('');
+    return `<tr data-severity="${escapeHtml(finding.severity)}" data-source="${escapeHtml(finding.source)}">` +
+      `<td><span class="badge ${escapeHtml(finding.severity)}">${escapeHtml(t(`html.severity.${finding.severity}`))}</span></td>` +
+      `<td>${escapeHtml(finding.source)}</td><td><code>${escapeHtml(finding.rule)}</code></td><td>${where}</td>` +
+      `<td>${escapeHtml(finding.message)}${extra}</td></tr>`;
+  });
+  lines.push(table('findings-table', [t('html.column.severity'), t('html.column.source'), t('html.column.rule'), t('html.column.location'), t('html.column.message')], rows));
+  lines.push('</section>');
+  return lines.join('');
+}
+
+/**
+ * Dependencies section: outdated packages and unused dependencies
+ * Vulnerabilities are findings and listed with them.
+ * @param {Object[]} deps - /deps-audit reports
+ * @returns {string}
+ */
+function depsSection(deps) {
+  const outdated = deps.flatMap(report => report.outdated || []);
+  const vulnerable = deps.reduce((sum, report) => sum + (report.vulnerabilities || []).length, 0);
+  const unused = deps.reduce((sum, report) => sum + (report.unused || []).length, 0);
+  const dependencies = deps.reduce((sum, report) => sum + (Number(report.dependencies) || 0), 0);
+  const lines = [
+    `<section id="dependencies"><h2>${escapeHtml(t('html.deps'))}</h2>`,
+    `<p>${escapeHtml(t('html.deps.summary', { dependencies, vulnerable, outdated: outdated.length, unused }))}</p>`
+  ];
+  if (outdated.length > 0) {
+    const rows = outdated.map(item => `<tr><td><code>${escapeHtml(item.name)}</code></td><td>${escapeHtml(item.ecosystem)}</td>` +
+      `<td>${escapeHtml(item.current)}</td><td>${escapeHtml(item.latest)}${item.major ? ` <span class="badge high">${escapeHtml(t('html.deps.major'))}</span>` : ''}</td></tr>`);
+    lines.push(`<h3>${escapeHtml(t('html.deps.outdated'))}</h3>`, filterBar('outdated-table', rows.length));
+    lines.push(table('outdated-table', [t('html.column.package'), t('html.column.ecosystem'), t('html.column.current'), t('html.column.latest')], rows));
+  }
+  lines.push('</section>');
+  return lines.join('');
+}
+
+/**
+ * Coverage section, one block per report
+ * @param {Object[]} coverage - Coverage summaries
+ * @returns {string}
+ */
+function coverageSection(coverage) {
+  const lines = [`<section id="coverage"><h2>${escapeHtml(t('html.coverage'))}</h2>`];
+  coverage.forEach((summary, index) => {
+    const { totals } = summary;
+    lines.push(`<h3>${escapeHtml(summary.report)}${summary.format ? ` <small>${escapeHtml(summary.format)}</small>` : ''}</h3>`);
+    lines.push(`<p>${meter(totals.percent)} ${escapeHtml(t('html.coverage.lines', { covered: totals.covered, total: totals.total, files: totals.files }))}</p>`);
+
+    const functions = (summary.functions || []).filter(item => item.exported && item.percent < 100).slice(0, COVERAGE_ROWS);
+    if (functions.length > 0) {
+      const rows = functions.map(item => `<tr><td><code>${escapeHtml(item.name)}</code></td><td><code>${escapeHtml(`${item.file}:${item.line}`)}</code></td>` +
+        `<td>${meter(item.percent)}</td><td>${escapeHtml(item.uncovered)}</td></tr>`);
+      lines.push(`<h4>${escapeHtml(t('html.coverage.functions'))}</h4>`, filterBar(`functions-${index}`, rows.length));
+      lines.push(table(`functions-${index}`, [t('html.column.function'), t('html.column.location'), t('html.column.coverage'), t('html.column.uncovered')], rows));
+    }
+    const files = (summary.files || []).filter(item => item.percent < 100).slice(0, COVERAGE_ROWS);
+    if (files.length > 0) {
+      const rows = files.map(item => `<tr><td><code>${escapeHtml(item.file)}</code></td><td>${meter(item.percent)} (${item.covered}/${item.total})</td></tr>`);
+      lines.push(`<h4>${escapeHtml(t('html.coverage.files'))}</h4>`, filterBar(`files-${index}`, rows.length));
+      lines.push(table(`files-${index}`, [t('html.column.file'), t('html.column.coverage')], rows));
+    }
+  });
+  lines.push('</section>');
+  return lines.join('');
+}
+
+const STYLE = `
+:root{--fg:#1f2328;--muted:#59636e;--bg:#fff;--panel:#f6f8fa;--line:#d1d9e0}
+@media (prefers-color-scheme:dark){:root{--fg:#e6edf3;--muted:#9198a1;--bg:#0d1117;--panel:#161b22;--line:#30363d}}
+body{margin:0 auto;max-width:1200px;padding:24px;font:14px/1.5 -apple-system,"Segoe UI",Helvetica,Arial,sans-serif;color:var(--fg);background:var(--bg)}
+h1{margin:0}h2{border-bottom:1px solid var(--line);padding-bottom:4px;margin-top:32px}
+.meta,small,.note,.shown{color:var(--muted)}
+.cards{display:flex;flex-wrap:wrap;gap:12px;margin:16px 0}
+.card{background:var(--panel);border:1px solid var(--line);border-radius:6px;padding:8px 16px;min-width:96px}
+.card b{display:block;font-size:24px}
+.chart text{fill:var(--fg);font-size:13px}
+table{border-collapse:collapse;width:100%;margin:8px 0}
+th,td{border:1px solid var(--line);padding:4px 8px;text-align:left;vertical-align:top}
+th{background:var(--panel)}
+code,pre{font:12px/1.45 ui-monospace,SFMono-Regular,Menlo,Consolas,monospace}
+pre{background:var(--panel);margin:4px 0;padding:6px;overflow-x:auto}
+pre span{display:block}pre i{display:inline-block;width:40px;color:var(--muted);font-style:normal;user-select:none}
+pre .hit{background:rgba(212,167,44,.25)}
+summary{cursor:pointer}
+.badge{color:#fff;border-radius:10px;padding:0 8px;font-size:12px;white-space:nowrap}
+.critical{background:#b60205}.high{background:#d93f0b}.medium{background:#c99a06}.low{background:#2c974b}
+.filters{display:flex;flex-wrap:wrap;gap:8px;align-items:center;margin:8px 0}
+.filters input,.filters select{padding:4px 6px;border:1px solid var(--line);border-radius:6px;background:var(--bg);color:var(--fg)}
+.meter{display:inline-block;width:80px;height:8px;background:var(--line);border-radius:4px;overflow:hidden;vertical-align:middle}
+.meter span{display:block;height:100%}.good{background:#2c974b}.fair{background:#c99a06}.poor{background:#d93f0b}
+.errors{color:#d93f0b}
+`;
+
+// Filters every table that has a filter bar; no data is embedded here
+const SCRIPT = `
+document.querySelectorAll('.filters').forEach(function (bar) {
+  var rows = Array.prototype.slice.call(document.querySelectorAll('#' + bar.getAttribute('data-table') + ' tbody tr'));
+  var controls = Array.prototype.slice.call(bar.querySelectorAll('[data-field]'));
+  var shown = bar.querySelector('.shown');
+  function apply() {
+    var count = 0;
+    rows.forEach(function (row) {
+      var visible = controls.every(function (control) {
+        var value = control.value.trim().toLowerCase();
+        if (!value) return true;
+        var field = control.getAttribute('data-field');
+        return field === 'text' ? row.textContent.toLowerCase().indexOf(value) !== -1 : row.getAttribute('data-' + field) === control.value;
+      });
+      row.hidden = !visible;
+      if (visible) count++;
+    });
+    shown.textContent = shown.getAttribute('data-template').replace('{shown}', count).replace('{total}', rows.length);
+  }
+  controls.forEach(function (control) { control.addEventListener('input', apply); });
+});
+`;
+
+/**
+ * Render collected data as a standalone HTML document
+ * @param {Object} report - Result of collectReport
+ * @param {Object} [options]
+ * @param {string} [options.title] - Page title
+ * @param {string} [options.generatedAt] - ISO time shown in the header (default: now)
+ * @returns {string}
+ */
+function renderHtml(report, options = {}) {
+  const title = options.title || t('html.title');
+  const generatedAt = options.generatedAt || new Date().toISOString();
+  const meta = [t('html.generated', { date: generatedAt.replace('T', ' ').replace(/\.\d+Z$|Z$/, ' UTC') })];
+  if (report.commit) meta.push(t('html.commit', { commit: report.commit.slice(0, 12) }));
+  meta.push(t('html.inputs', { inputs: report.inputs.map(input => `${input.name} (${input.kind})`).join(', ') || '-' }));
+
+  const total = report.findings.length;
+  const cards = [`<div class="card"><b>${total}</b>${escapeHtml(t('html.total'))}</div>`]
+    .concat(SEVERITIES.map(severity => `<div class="card"><b style="color:${SEVERITY_COLORS[severity]}">${report.counts[severity]}</b>${escapeHtml(t(`html.severity.${severity}`))}</div>`));
+  report.coverage.forEach(summary => {
+    cards.push(`<div class="card"><b>${summary.totals.percent === null ? '-' : `${summary.totals.percent}%`}</b>${escapeHtml(t('html.coverage'))}</div>`);
+  });
+
+  const body = [
+    `<header><h1>${escapeHtml(title)}</h1><p class="meta">${meta.map(escapeHtml).join(' · ')}</p></header>`,
+    `<section id="summary"><h2>${escapeHtml(t('html.summary'))}</h2><div class="cards">${cards.join('')}</div>`
+  ];
+  if (total > 0) {
+    body.push(severityChart(report.counts));
+    const rows = Object.entries(report.sources).sort(([a], [b]) => a.localeCompare(b))
+      .map(([source, counts]) => `<tr><td>${escapeHtml(source)}</td>${SEVERITIES.map(severity => `<td>${counts[severity]}</td>`).join('')}</tr>`);
+    body.push(table('sources-table', [t('html.column.source'), ...SEVERITIES.map(severity => t(`html.severity.${severity}`))], rows));
+  }
+  body.push('</section>', findingsSection(report));
+  if (report.deps.length > 0) body.push(depsSection(report.deps));
+  if (report.coverage.length > 0) body.push(coverageSection(report.coverage));
+  if (report.errors.length > 0) {
+    body.push(`<section id="errors"><h2>${escapeHtml(t('html.errors'))}</h2><ul class="errors">${report.errors.map(error => `<li>${escapeHtml(error)}</li>`).join('')}</ul></section>`);
+  }
+
+  return [
+    '<!DOCTYPE html>',
+    `<html lang="${escapeHtml(getLocale())}">`,
+    `<head><meta charset="utf-8"><meta name="viewport" content="width=device-width, initial-scale=1"><title>${escapeHtml(title)}</title><style>${STYLE}</style></head>`,
+    `<body>${body.join('\n')}<script>${SCRIPT}</script></body>`,
+    '</html>',
+    ''
+  ].join('\n');
+}
+
+/**
+ * Build the HTML report from result files
+ * @param {string} basePath - Repository root
+ * @param {string[]} files - Scanner output and coverage report paths
+ * @param {Object} [options] - renderHtml options, plus `run` for tests
+ * @returns {{success: boolean, html?: string, report?: Object, error?: string}}
+ */
+function generateReport(basePath, files, options = {}) {
+  if (files.length === 0) return { success: false, error: t('html.noInputs') };
+  const inputs = [];
+  const unreadable = [];
+  for (const file of files) {
+    try {
+      inputs.push({ name: file, content: fs.readFileSync(path.resolve(basePath, file), 'utf8') });
+    } catch (err) {
+      unreadable.push(t('html.unreadable', { file, error: err.message }));
+    }
+  }
+  const report = collectReport(basePath, inputs, options);
+  report.errors.unshift(...unreadable);
+  if (report.inputs.length === 0) return { success: false, error: report.errors.join('\n'), report };
+  return { success: true, html: renderHtml(report, options), report };
+}
+
+if (require.main === module) {
+  const argv = process.argv.slice(2);
+  const valueOf = flag => (argv.includes(flag) ? argv[argv.indexOf(flag) + 1] : undefined);
+  const flagValues = new Set(['--output', '--title'].map(valueOf).filter(Boolean));
+  const files = argv.filter(arg => !arg.startsWith('--') && !flagValues.has(arg));
+
+  const messages = require('../messages');
+  const locale = messages.configure(process.cwd());
+  if (locale.errors.length > 0) console.error(t('messages.errors', { errors: locale.errors.join('; ') }));
+
+  const generatedAt = process.env.SOURCE_DATE_EPOCH ? new Date(Number(process.env.SOURCE_DATE_EPOCH) * 1000).toISOString() : undefined;
+  const result = generateReport(process.cwd(), files, { title: valueOf('--title'), generatedAt });
+  if (!result.success) {
+    console.error(result.error);
+    process.exit(1);
+  }
+  const output = valueOf('--output') || DEFAULT_OUTPUT;
+  fs.writeFileSync(output, result.html);
+  for (const error of result.report.errors) console.error(error);
+  const { report } = result;
+  console.log(t('html.written', { file: output, findings: report.findings.length, coverage: report.coverage.length, inputs: report.inputs.length }));
+}
+
+module.exports = {
+  DEFAULT_OUTPUT,
+  SEVERITY_COLORS,
+  escapeHtml,
+  isSecret,
+  snippetOf,
+  coverageOf,
+  readInput,
+  collectReport,
+  severityChart,
+  renderHtml,
+  generateReport
+};
@@ -3,7 +3,22 @@
  * Slop Detection CLI
  * Runs the detection pipeline and outputs structured findings
  *
- * Usage: node detect.js [path] [--apply] [--deep] [--compact]
+ * Usage: node detect.js [path] [--apply] [--deep] [--compact] [--include-linted] [--baseline FILE | --no-baseline]
+ *        node detect.js [path] --dry-run | --write
+ *        node detect.js [path] --diff <base>
+ *        node detect.js [path] --staged --quick   (pre-commit hook)
+ *        node detect.js [path] --sarif > slop.sarif
+ *        node detect.js [path] --fail-on high [--warn-on medium]
+ *        node detect.js [path] --redact
+ *        node detect.js [path] --github-actions | --no-github-actions
+ *        node detect.js [path] --gitlab-mr | --no-gitlab-mr
+ *        node detect.js [path] --fail-on high --notify
+ *        node detect.js [path] --compact --by-owner   (one table per CODEOWNERS owner)
+ *        node detect.js [path] --request-reviewers     (ask CODEOWNERS to review the PR/MR in CI)
+ *        node detect.js [path] --interactive   (triage findings one by one)
+ *        node detect.js baseline [path] [--deep] [--baseline FILE]
+ *        node detect.js trends [path] [--last N]   (slop counts over recorded runs)
+ *        node detect.js [path] --log-level debug [--log-format json]
  */
 
 const path = require('path');
@@ -11,20 +26,60 @@ const fs = require('fs');
 
 // Resolve lib relative to script location (works with ${CLAUDE_PLUGIN_ROOT})
 const libPath = path.join(__dirname, '..', 'lib');
-const { runPipeline } = require(path.join(libPath, 'patterns', 'pipeline'));
+const { runPipeline, evaluateSeverityGate, SEVERITIES, SEVERITY_EXIT_CODES } = require(path.join(libPath, 'patterns', 'pipeline'));
+const { writeBaseline, BASELINE_FILE } = require(path.join(libPath, 'patterns', 'baseline'));
+const { generateFixes, writeFixes } = require(path.join(libPath, 'patterns', 'fixer'));
+const triage = require(path.join(libPath, 'patterns', 'triage'));
+const journal = require(path.join(libPath, 'journal'));
+const { slopToSarif } = require(path.join(libPath, 'patterns', 'sarif'));
+const diffScope = require(path.join(libPath, 'patterns', 'diff-scope'));
+const { loadScanSettings } = require(path.join(libPath, 'patterns', 'custom-patterns'));
+const { isFileExcluded } = require(path.join(libPath, 'patterns', 'slop-patterns'));
+const githubActions = require(path.join(libPath, 'patterns', 'github-actions'));
+const gitlabMr = require(path.join(libPath, 'patterns', 'gitlab-mr'));
+const notify = require(path.join(libPath, 'notify'));
+const { applyLogArgs } = require(path.join(libPath, 'utils', 'logger'));
+const { renderSkipped } = require(path.join(libPath, 'utils', 'scan-limits'));
+const { groupByOwner } = require(path.join(libPath, 'utils', 'codeowners'));
+const telemetry = require(path.join(libPath, 'telemetry'));
+const history = require(path.join(libPath, 'history'));
+const messages = require(path.join(libPath, 'messages'));
+
+const { t } = messages;
 
 function parseArgs(args) {
   const options = {
+    command: 'detect',
     path: '.',
     mode: 'report',
     thoroughness: 'normal',
     compact: false,
+    includeLinterEnforced: false,
+    baseline: undefined,
+    fix: null,
+    diffBase: null,
+    staged: false,
+    sarif: false,
+    failOn: null,
+    warnOn: null,
+    duplicateLines: null,
+    redactSecrets: false,
+    githubActions: githubActions.isGitHubActions(),
+    gitlabMr: gitlabMr.mergeRequestContext() !== null,
+    notify: false,
+    byOwner: false,
+    requestReviewers: false,
+    interactive: false,
+    history: true,
+    last: null,
     maxFindings: 10
   };
 
   for (let i = 0; i < args.length; i++) {
     const arg = args[i];
-    if (arg === '--apply') {
+    if (i === 0 && (arg === 'baseline' || arg === 'trends')) {
+      options.command = arg;
+    } else if (arg === '--apply') {
       options.mode = 'apply';
     } else if (arg === '--deep') {
       options.thoroughness = 'deep';
@@ -32,6 +87,50 @@ function parseArgs(args) {
       options.thoroughness = 'quick';
     } else if (arg === '--compact') {
       options.compact = true;
+    } else if (arg === '--include-linted') {
+      options.includeLinterEnforced = true;
+    } else if (arg === '--baseline' && args[i + 1]) {
+      options.baseline = args[++i];
+    } else if (arg === '--diff' && args[i + 1]) {
+      options.diffBase = args[++i];
+    } else if (arg === '--staged') {
+      options.staged = true;
+    } else if (arg === '--fail-on' && args[i + 1]) {
+      options.failOn = args[++i];
+    } else if (arg === '--warn-on' && args[i + 1]) {
+      options.warnOn = args[++i];
+    } else if (arg === '--sarif') {
+      options.sarif = true;
+    } else if (arg === '--redact') {
+      options.redactSecrets = true;
+    } else if (arg === '--github-actions') {
+      options.githubActions = true;
+    } else if (arg === '--no-github-actions') {
+      options.githubActions = false;
+    } else if (arg === '--gitlab-mr') {
+      options.gitlabMr = true;
+    } else if (arg === '--no-gitlab-mr') {
+      options.gitlabMr = false;
+    } else if (arg === '--notify') {
+      options.notify = true;
+    } else if (arg === '--by-owner') {
+      options.byOwner = true;
+    } else if (arg === '--request-reviewers') {
+      options.requestReviewers = true;
+    } else if (arg === '--interactive' || arg === '-i') {
+      options.interactive = true;
+    } else if (arg === '--dry-run') {
+      options.fix = 'dry-run';
+    } else if (arg === '--write') {
+      options.fix = 'write';
+    } else if (arg === '--no-baseline') {
+      options.baseline = false;
+    } else if (arg === '--duplicate-lines' && args[i + 1]) {
+      options.duplicateLines = parseInt(args[++i], 10);
+    } else if (arg === '--no-history') {
+      options.history = false;
+    } else if (arg === '--last' && args[i + 1]) {
+      options.last = parseInt(args[++i], 10);
     } else if (arg === '--max' && args[i + 1]) {
       options.maxFindings = parseInt(args[++i], 10);
     } else if (!arg.startsWith('-')) {
@@ -42,40 +141,205 @@ function parseArgs(args) {
   return options;
 }
 
-function formatFindings(result, compact, maxFindings = 10) {
+function printTable(findings, maxFindings) {
+  console.log(t('report.header'));
+  console.log('|------|------|---------|----------|-----------|');
+
+  for (const finding of findings.slice(0, maxFindings)) {
+    const file = (finding.file || finding.path || '').length > 30
+      ? '...' + (finding.file || finding.path || '').slice(-27)
+      : (finding.file || finding.path || '');
+    const line = finding.line || finding.lineNumber || '-';
+    const pattern = finding.patternName || finding.pattern || finding.type || 'unknown';
+    const severity = finding.severity || 'medium';
+    const certainty = finding.certainty || 'MEDIUM';
+    console.log(`| ${file} | ${line} | ${pattern} | ${severity} | ${certainty} |`);
+  }
+}
+
+function formatFindings(result, compact, maxFindings = 10, byOwner = false) {
   // Handle pipeline output format (has 'findings' array or 'summary' object)
   const findings = result.findings || [];
   const summary = result.summary || {};
 
   if (compact) {
     // Compact table format for token efficiency
-    console.log('\n## Slop Detection Results\n');
-    console.log('| File | Line | Pattern | Severity | Certainty |');
-    console.log('|------|------|---------|----------|-----------|');
-
-    for (const finding of findings.slice(0, maxFindings)) {
-      const file = (finding.file || finding.path || '').length > 30
-        ? '...' + (finding.file || finding.path || '').slice(-27)
-        : (finding.file || finding.path || '');
-      const line = finding.line || finding.lineNumber || '-';
-      const pattern = finding.patternName || finding.pattern || finding.type || 'unknown';
-      const severity = finding.severity || 'medium';
-      const certainty = finding.certainty || 'MEDIUM';
-      console.log(`| ${file} | ${line} | ${pattern} | ${severity} | ${certainty} |`);
+    console.log(`\n## ${t('report.title')}\n`);
+    if (byOwner && result.codeowners) {
+      groupByOwner(findings).forEach((group, index) => {
+        console.log(`${index > 0 ? '\n' : ''}### ${t('report.ownerGroup', { owner: group.owner || t('owners.unowned'), count: group.findings.length })}\n`);
+        printTable(group.findings, maxFindings);
+      });
+    } else {
+      printTable(findings, maxFindings);
     }
 
     const total = summary.totalFindings || findings.length;
     const bySeverity = summary.bySeverity || {};
-    console.log(`\n**Total**: ${total} findings`);
-    console.log(`**By Severity**: critical=${bySeverity.critical || 0}, high=${bySeverity.high || 0}, medium=${bySeverity.medium || 0}, low=${bySeverity.low || 0}`);
+    console.log(`\n${t('report.total', { total })}`);
+    console.log(t('report.bySeverity', Object.fromEntries(SEVERITIES.map(severity => [severity, bySeverity[severity] || 0]))));
+    if (result.codeowners && findings.length > 0) {
+      console.log(t('report.byOwner', { owners: githubActions.describeOwners(findings) }));
+    }
+
+    const configErrors = result.customPatternErrors || [];
+    if (configErrors.length > 0) {
+      console.log(t('report.customPatternErrors', { errors: configErrors.join('; ') }));
+    }
+    const settingsErrors = result.configErrors || [];
+    if (settingsErrors.length > 0) {
+      console.log(t('report.configErrors', { errors: settingsErrors.join('; ') }));
+    }
+
+    if (result.diffScope) {
+      const scope = result.diffScope;
+      console.log(t(scope.base === 'staged' ? 'report.diffScope.staged' : 'report.diffScope.since', { lines: scope.changedLines, files: scope.changedFiles, base: scope.base }));
+    }
+
+    const baseline = result.baseline;
+    if (baseline && baseline.error) {
+      console.log(t('report.baselineIgnored', { error: baseline.error }));
+    } else if (baseline) {
+      console.log(t('report.baseline', { suppressed: baseline.suppressed, file: baseline.file }));
+    }
+
+    const linted = result.linterEnforced || [];
+    if (linted.length > 0) {
+      const skipped = linted.map(entry => `${entry.patternName} (${entry.enforcedBy}, ${entry.count})`).join(', ');
+      console.log(t('report.linterEnforced', { patterns: skipped }));
+    }
+
+    const skippedFiles = result.skipped;
+    if (skippedFiles && skippedFiles.error) {
+      console.log(t('report.scanLimitErrors', { error: skippedFiles.error }));
+    }
+    if (skippedFiles && skippedFiles.total > 0) {
+      console.log(t('report.skippedFiles', { skipped: renderSkipped(skippedFiles) }));
+    }
   } else {
     // Full JSON output
     console.log(JSON.stringify(result, null, 2));
   }
 }
 
-function main() {
-  const args = process.argv.slice(2);
+/**
+ * Plugin version from plugin.json, if available
+ */
+function readPluginVersion() {
+  try {
+    return JSON.parse(fs.readFileSync(path.join(__dirname, '..', '.claude-plugin', 'plugin.json'), 'utf8')).version;
+  } catch {
+    return undefined;
+  }
+}
+
+/**
+ * Report the severity policy on stderr and return the exit code
+ * The gate is opt-in: without --fail-on or severity.failOn the scan exits 0.
+ */
+function applySeverityGate(result, options) {
+  if (!options.failOn) return 0;
+  const gate = evaluateSeverityGate(result.summary?.bySeverity || {}, { failOn: options.failOn, warnOn: options.warnOn });
+  const describe = counts => Object.entries(counts).map(([severity, count]) => `${count} ${severity}`).join(', ');
+
+  if (Object.keys(gate.failing).length > 0) {
+    console.error(t('gate.failing', { counts: describe(gate.failing), threshold: options.failOn }));
+  }
+  if (Object.keys(gate.warnings).length > 0) {
+    console.error(t('gate.warning', { counts: describe(gate.warnings), threshold: options.warnOn }));
+  }
+  return gate.exitCode;
+}
+
+/**
+ * Print (dry run) or apply auto-fixes; the patch goes to stdout, the summary to stderr
+ */
+function reportFixes(repoPath, findings, fixMode) {
+  const fixes = generateFixes(repoPath, findings);
+
+  if (fixMode === 'write') {
+    const tx = journal.begin(repoPath, 'deslop');
+    const written = writeFixes(repoPath, fixes, { journal: tx });
+    const recorded = tx.commit();
+    console.log(t('fix.written', { findings: written.findingsFixed, files: written.filesChanged }));
+    if (recorded.id) console.error(t('fix.undo'));
+  } else {
+    for (const entry of fixes.files) process.stdout.write(entry.diff);
+    const count = fixes.files.reduce((total, entry) => total + entry.fixed.length, 0);
+    console.error(t('fix.dryRun', { count, files: fixes.files.length }));
+  }
+
+  if (fixes.skipped.length > 0) {
+    console.error(t('fix.skipped', { count: fixes.skipped.length }));
+    for (const { finding, reason } of fixes.skipped) {
+      console.error(`  ${finding.file}:${finding.line} ${finding.patternName} - ${reason}`);
+    }
+  }
+}
+
+/**
+ * Triage findings at the terminal; Ctrl-C leaves every file untouched
+ */
+async function runInteractive(repoPath, findings, options) {
+  if (findings.length === 0) {
+    console.log(t('triage.noFindings'));
+    return;
+  }
+  const readline = require('readline');
+  const { spawnInteractive } = require(path.join(libPath, 'utils', 'exec'));
+  const rl = readline.createInterface({ input: process.stdin, output: process.stdout });
+  rl.on('SIGINT', () => {
+    rl.close();
+    console.log(`\n${t('triage.aborted')}`);
+    process.exit(130);
+  });
+  // Input closing (Ctrl-D) ends the session like q
+  let pending = null;
+  let ended = false;
+  rl.on('close', () => {
+    ended = true;
+    if (pending) pending(null);
+  });
+
+  const io = {
+    ask: question => (ended ? Promise.resolve(null) : new Promise(resolve => {
+      pending = resolve;
+      rl.question(question, answer => {
+        pending = null;
+        resolve(answer);
+      });
+    })),
+    write: text => console.log(text),
+    open: async argv => {
+      rl.pause();
+      const run = spawnInteractive(argv);
+      if (run.error) console.error(t('triage.editorFailed', { command: argv[0], error: run.error.message }));
+      rl.resume();
+    }
+  };
+
+  const tx = journal.begin(repoPath, 'deslop');
+  const result = await triage.runTriage(repoPath, findings, io, {
+    color: Boolean(process.stdout.isTTY) && !process.env.NO_COLOR,
+    baselineFile: options.baseline || undefined,
+    journal: tx
+  });
+  rl.close();
+  const recorded = tx.commit();
+  console.log(triage.describeTriage(result, findings.length));
+  if (recorded.id) console.log(t('fix.undo'));
+  for (const { finding, reason } of result.fixSkipped) {
+    console.error(`  ${finding.file}:${finding.line} ${finding.patternName} - ${reason}`);
+  }
+  if (result.error) process.exit(1);
+}
+
+async function main() {
+  const { argv: args, error: logArgsError } = applyLogArgs(process.argv.slice(2));
+  if (logArgsError) {
+    console.error(`Error: ${logArgsError}`);
+    process.exit(1);
+  }
 
   // Help
   if (args.includes('--help') || args.includes('-h')) {
@@ -83,25 +347,83 @@ function main() {
 Slop Detection CLI
 
 Usage: node detect.js [path] [options]
+       node detect.js baseline [path] [options]
+       node detect.js trends [path] [--last N]
 
 Options:
   --apply      Apply auto-fixes (default: report only)
   --deep       Deep analysis with all analyzers
   --quick      Quick regex-only scan
   --compact    Output as markdown table (token efficient)
+  --include-linted  Keep findings the project's linters already enforce
+  --baseline FILE   Baseline file to read or write (default: ${BASELINE_FILE})
+  --no-baseline     Report findings recorded in the baseline too
+  --diff BASE  Only report findings on lines changed since BASE (git diff BASE...HEAD)
+  --staged     Only report findings on staged lines (git diff --cached); for pre-commit hooks
+  --sarif      Output all findings as SARIF 2.1.0 (GitHub code scanning, IDE viewers)
+  --fail-on SEVERITY  Exit non-zero on findings at or above SEVERITY (default: severity.failOn in config, else never fail)
+  --warn-on SEVERITY  Warn on stderr for findings at or above SEVERITY (default: severity.warnOn in config, else medium)
+  --redact     Mask secret values in finding content (use when output lands in CI logs)
+  --github-actions     Annotate findings and write the job summary (default when GITHUB_ACTIONS=true)
+  --no-github-actions  Skip annotations and the job summary inside GitHub Actions
+  --gitlab-mr     Post findings as merge request threads and resolve fixed ones (default in GitLab MR pipelines; needs GITLAB_TOKEN)
+  --no-gitlab-mr  Skip merge request threads inside GitLab CI
+  --notify     Post to the notify.commands.deslop channels when the --fail-on gate fails
+  --by-owner   With --compact, one findings table per CODEOWNERS owner
+  --request-reviewers  Request review from the CODEOWNERS of flagged files (GitHub: needs GH_TOKEN; GitLab: new threads only)
+  -i, --interactive  Step through findings with a code preview: fix, baseline, or open each in $EDITOR
+  --dry-run    Print auto-fixes as a unified diff without changing files
+  --write      Apply auto-fixes (remove/replace/add_logging) to files
+  --duplicate-lines N  Minimum lines for a duplicated block (default: 8; normal/deep only)
   --max N      Maximum findings to return (default: 10)
+  --no-history Do not record this scan's counts in .awesome-slash/history/ (whole scans are recorded by default)
+  --last N     With trends, runs to compare (default: 10)
+  --log-level LEVEL    Diagnostics on stderr: silent, error, warn (default), info, debug, trace
+  --log-format FORMAT  text (default) or json (one object per line)
+  --log-namespaces NS  Limit info and below to these namespaces (e.g. patterns:*)
   --help       Show this help
 
 Examples:
   node detect.js                    # Scan current directory
   node detect.js src/               # Scan src/ directory
   node detect.js --apply --compact  # Fix and show compact results
+  node detect.js baseline           # Record current findings; later runs report only new ones
+  node detect.js trends --last 20   # Is slop rising or falling over the last 20 runs?
+  node detect.js src/ --dry-run     # Preview auto-fixes as a patch
+  node detect.js --diff origin/main # PR check: new slop only
+  node detect.js --fail-on high     # CI gate: fail on high, warn on medium, ignore low
+  node detect.js src/ -i            # Triage findings one at a time
+
+Exit codes:
+  0  No findings at or above --fail-on
+  1  Error (bad arguments, path not found, git failure)
+${SEVERITIES.map(severity => `  ${SEVERITY_EXIT_CODES[severity]}  Most severe failing finding is ${severity}`).join('\n')}
 `);
     process.exit(0);
   }
 
   const options = parseArgs(args);
 
+  // Opt-in metrics (telemetry.enabled); recorded on exit so every process.exit path counts
+  const telemetryRun = telemetry.startRun(options.path, options.interactive ? 'triage' : `deslop${options.command !== 'detect' ? `:${options.command}` : ''}`);
+  process.on('exit', code => telemetryRun.end({ exitCode: code }));
+
+  // Report language from `i18n` in the project config and the locale environment
+  const locale = messages.configure(options.path);
+  if (locale.errors.length > 0) console.error(t('messages.errors', { errors: locale.errors.join('; ') }));
+
+  // Gate defaults from `severity` in the project config; no failOn means no gate
+  const scanSettings = loadScanSettings(options.path);
+  options.failOn = options.failOn || scanSettings.severity.failOn || null;
+  options.warnOn = options.warnOn || scanSettings.severity.warnOn || 'medium';
+
+  for (const flag of ['failOn', 'warnOn']) {
+    if (options[flag] !== null && options[flag] !== 'none' && !SEVERITIES.includes(options[flag])) {
+      console.error(`Error: --${flag === 'failOn' ? 'fail-on' : 'warn-on'} must be one of ${SEVERITIES.join(', ')}, none`);
+      process.exit(1);
+    }
+  }
+
   // Validate path exists
   if (!fs.existsSync(options.path)) {
     console.error(`Error: Path not found: ${options.path}`);
@@ -109,20 +431,110 @@ Examples:
   }
 
   try {
+    if (options.command === 'baseline') {
+      const result = runPipeline(options.path, {
+        thoroughness: options.thoroughness,
+        includeLinterEnforced: options.includeLinterEnforced,
+        baseline: false,
+        scanSettings
+      });
+      const written = writeBaseline(options.path, result.findings, options.baseline || BASELINE_FILE);
+      console.log(t('baseline.written', { findings: written.findings, entries: written.entries, file: path.relative(process.cwd(), written.file) || written.file }));
+      return;
+    }
+
+    if (options.command === 'trends') {
+      console.log(history.renderTrends(history.trends(options.path, { scanners: ['slop'], last: options.last || undefined })));
+      return;
+    }
+
+    // Staged lines, resolved up front so commits without source changes skip the scan
+    let changedLines;
+    if (options.staged) {
+      const staged = diffScope.getStagedLines(options.path);
+      if (staged.error) throw new Error(staged.error);
+      const sources = diffScope.selectSourceFiles(options.path, staged.files)
+        .filter(file => !isFileExcluded(file, scanSettings.ignore));
+      if (sources.length === 0) return;
+      changedLines = staged.files;
+    }
+
     // runPipeline takes (repoPath, options) - synchronous function
     const result = runPipeline(options.path, {
       mode: options.mode,
-      thoroughness: options.thoroughness
+      thoroughness: options.thoroughness,
+      includeLinterEnforced: options.includeLinterEnforced,
+      baseline: options.baseline,
+      diffBase: options.staged ? 'staged' : (options.diffBase || undefined),
+      changedLines,
+      redactSecrets: options.redactSecrets,
+      scanSettings,
+      duplicates: options.duplicateLines > 0 ? { minLines: options.duplicateLines } : undefined
     });
 
-    formatFindings(result, options.compact, options.maxFindings);
+    // Whole scans feed the finding history; diff-scoped counts would skew it
+    if (options.history && !options.staged && !options.diffBase && !options.interactive) {
+      const recorded = history.recordRun(options.path, 'slop', result.findings, {
+        mode: options.thoroughness,
+        baselined: result.baseline ? result.baseline.suppressed : 0
+      });
+      if (recorded.error) console.error(`History not recorded: ${recorded.error}`);
+    }
+
+    if (options.interactive) {
+      if (!process.stdin.isTTY) throw new Error('--interactive needs a terminal');
+      await runInteractive(options.path, result.findings, options);
+      return;
+    }
 
-    // Exit with error code if critical findings
-    const bySeverity = result.summary?.bySeverity || {};
-    if (bySeverity.critical > 0) {
-      process.exit(2);
+    if (options.fix) {
+      reportFixes(options.path, result.findings, options.fix);
+      return;
+    }
+
+    if (options.sarif) {
+      const sarif = slopToSarif(options.path, result.findings, {
+        fixes: generateFixes(options.path, result.findings),
+        toolVersion: readPluginVersion()
+      });
+      console.log(JSON.stringify(sarif, null, 2));
+    } else {
+      formatFindings(result, options.compact, options.maxFindings, options.byOwner);
+    }
+
+    if (options.githubActions) {
+      const reported = githubActions.reportSlop(options.path, result, {
+        gate: options.failOn ? { failOn: options.failOn, warnOn: options.warnOn } : undefined,
+        requestReviewers: options.requestReviewers
+      });
+      if (reported.reviewers) console.error(githubActions.describeReviewers(reported.reviewers));
+    }
+
+    if (options.gitlabMr) {
+      const context = gitlabMr.mergeRequestContext();
+      const threads = gitlabMr.slopThreads(options.path, result.findings, { workspace: context ? context.workspace : undefined });
+      const synced = await gitlabMr.syncMergeRequest(threads, {
+        source: 'slop',
+        context,
+        minSeverity: options.warnOn === 'none' ? options.failOn : options.warnOn,
+        requestReviewers: options.requestReviewers
+      });
+      console.error(gitlabMr.describeSync(synced));
+    }
+
+    const exitCode = applySeverityGate(result, options);
+    if (exitCode !== 0 && options.notify) {
+      const gate = evaluateSeverityGate(result.summary?.bySeverity || {}, { failOn: options.failOn, warnOn: options.warnOn });
+      const sent = await notify.notify(process.cwd(), 'slop-gate-failed', notify.slopGateVariables(gate, options.failOn));
+      if (!sent.success) console.error(`Notification failed: ${sent.error || sent.failed.map(entry => `${entry.channel}: ${entry.error}`).join('; ')}`);
+      else if (sent.sent.length > 0) console.error(`Notified ${sent.sent.join(', ')}`);
+      for (const entry of sent.skipped || []) console.error(`Notification skipped for ${entry.channel}: ${entry.reason}`);
+    }
+    if (exitCode !== 0) {
+      process.exit(exitCode);
     }
   } catch (error) {
+    telemetryRun.fail(error);
     console.error(`Error running detection: ${error.message}`);
     process.exit(1);
   }
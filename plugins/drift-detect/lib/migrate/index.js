@@ -0,0 +1,450 @@
+#!/usr/bin/env node
+/**
+ * Framework and Runtime Migration Guides
+ *
+ * A migration target ("Node 18→22", "Pydantic v1→v2") selects a guide and
+ * the steps introduced between the two versions. Call sites are found by
+ * scanning the repo map's files for the guide's language (or only files that
+ * import the package, for names that are common elsewhere) and attributed
+ * to the enclosing function. Steps with a codemod are mechanical renames that
+ * keep behavior; everything else becomes a manual checklist item.
+ *
+ * Usage: node lib/migrate/index.js "<name> <from>→<to>"
+ * Output: JSON migration plan
+ *
+ * @module lib/migrate
+ */
+
+const fs = require('fs');
+const path = require('path');
+
+const { compareVersions, detectRuntimes } = require('../platform/detect-runtimes');
+const { detectFrameworkVersions } = require('../platform/detect-framework-versions');
+
+const JS_LANGUAGES = ['javascript', 'typescript'];
+
+const UNITTEST_ALIASES = {
+  assertEquals: 'assertEqual',
+  assertNotEquals: 'assertNotEqual',
+  assertAlmostEquals: 'assertAlmostEqual',
+  assertNotAlmostEquals: 'assertNotAlmostEqual',
+  assertRegexpMatches: 'assertRegex',
+  assertNotRegexpMatches: 'assertNotRegex',
+  assertRaisesRegexp: 'assertRaisesRegex',
+  failUnless: 'assertTrue',
+  failIf: 'assertFalse',
+  assert_: 'assertTrue'
+};
+
+const COLLECTIONS_ABCS = 'Awaitable|Coroutine|AsyncIterable|AsyncIterator|AsyncGenerator|Hashable|Iterable|Iterator|Generator|Reversible|Sized|Container|Callable|Collection|Set|MutableSet|Mapping|MutableMapping|MappingView|KeysView|ItemsView|ValuesView|Sequence|MutableSequence|ByteString';
+
+/**
+ * Migration guides
+ * `detect` names the runtime (detect-runtimes) or framework
+ * (detect-framework-versions) that supplies the current version. A step
+ * applies when `from < since <= to`; `scope: 'importers'` limits it to files
+ * importing `package`. `codemod` is the replacement for the matched text.
+ */
+const GUIDES = {
+  node: {
+    label: 'Node.js',
+    aliases: ['node', 'nodejs', 'node.js'],
+    detect: { runtime: 'node' },
+    languages: JS_LANGUAGES,
+    steps: [
+      { id: 'new-buffer', since: '10', pattern: /\bnew Buffer\(/, description: '`new Buffer()` is deprecated: use `Buffer.from()` for data or `Buffer.alloc()` for a size' },
+      { id: 'fs-rmdir-recursive', since: '16', pattern: /\b(fs(?:\.promises)?\.)rmdir(Sync)?\(([^,()]+),\s*\{\s*recursive:\s*true\s*\}\)/, description: '`fs.rmdir(path, { recursive: true })` is deprecated: use `fs.rm`', codemod: '$1rm$2($3, { recursive: true, force: true })' },
+      { id: 'punycode', since: '21', pattern: /(?:require\s*\(\s*|from\s+)['"](?:node:)?punycode['"]/, description: 'The `punycode` core module is deprecated: install `punycode` from npm and import `punycode/`' },
+      { id: 'util-isarray', since: '22', pattern: /\butil\.isArray\(/, description: '`util.isArray()` is deprecated: use `Array.isArray()`', codemod: 'Array.isArray(' },
+      { id: 'util-is', since: '22', pattern: /\butil\.is(?:Boolean|Buffer|Date|Error|Function|Null|NullOrUndefined|Number|Object|Primitive|RegExp|String|Symbol|Undefined)\(/, description: '`util.is*()` type checks are deprecated: use `typeof`, `instanceof`, or `util.types`' },
+      { id: 'import-assertions', since: '22', pattern: /(\bfrom\s+['"][^'"]+['"]\s+)assert(\s*\{\s*type\s*:)/, description: 'Import assertions (`assert { type }`) were removed: use import attributes (`with { type }`)', codemod: '$1with$2' },
+      { id: 'dynamic-import-assertions', since: '22', pattern: /(\bimport\s*\([^)]*,\s*\{\s*)assert(\s*:)/, description: 'Dynamic import assertions were removed: use `{ with: { type } }`', codemod: '$1with$2' },
+      { id: 'create-cipher', since: '22', pattern: /\bcreate(?:Cipher|Decipher)\s*\(/, description: '`crypto.createCipher()`/`createDecipher()` were removed: use `createCipheriv()` with an explicit key and IV' },
+      { id: 'util-log', since: '23', pattern: /\butil\.log\(/, description: '`util.log()` was removed: use `console.log()` with your own timestamp' }
+    ],
+    manual: [
+      { since: '0', text: 'Update `engines.node`, `.nvmrc`/`.node-version`, Docker base images, and the CI matrix' },
+      { since: '0', text: 'Bump `@types/node` to the target major' },
+      { since: '0', text: 'Rebuild native addons (`npm rebuild`); the ABI changes every major' },
+      { since: '20', text: 'Replace `--experimental-loader` hooks with `module.register()`' },
+      { since: '21', text: 'Global `fetch`, `WebSocket`, and `navigator` may shadow polyfills; remove polyfills that are no longer needed' }
+    ]
+  },
+  python: {
+    label: 'Python',
+    aliases: ['python', 'py', 'cpython'],
+    detect: { runtime: 'python' },
+    languages: ['python'],
+    steps: [
+      { id: 'collections-abc', since: '3.10', pattern: new RegExp(`^(\\s*from\\s+collections)(\\s+import\\s+)((?:${COLLECTIONS_ABCS})(?:\\s*,\\s*(?:${COLLECTIONS_ABCS}))*)\\s*$`), description: 'ABCs were removed from `collections`: import them from `collections.abc`', codemod: '$1.abc$2$3' },
+      { id: 'collections-abc-attribute', since: '3.10', pattern: new RegExp(`\\bcollections\\.(?:${COLLECTIONS_ABCS})\\b`), description: '`collections.<ABC>` was removed: use `collections.abc.<ABC>`' },
+      { id: 'asyncio-coroutine', since: '3.11', pattern: /@asyncio\.coroutine\b/, description: '`@asyncio.coroutine` was removed: use `async def`' },
+      { id: 'distutils', since: '3.12', pattern: /^\s*(?:from|import)\s+distutils\b/, description: '`distutils` was removed: use `setuptools`, `shutil`, or `sysconfig`' },
+      { id: 'imp', since: '3.12', pattern: /^\s*(?:from\s+imp\s+import|import\s+imp\b)/, description: 'The `imp` module was removed: use `importlib`' },
+      { id: 'asyncore', since: '3.12', pattern: /^\s*(?:from|import)\s+(?:asyncore|asynchat|smtpd)\b/, description: '`asyncore`, `asynchat`, and `smtpd` were removed: use `asyncio` (or `aiosmtpd`)' },
+      { id: 'unittest-aliases', since: '3.12', pattern: new RegExp(`\\.(${Object.keys(UNITTEST_ALIASES).join('|')})\\(`), description: 'Deprecated `unittest` aliases were removed', codemod: (match, name) => `.${UNITTEST_ALIASES[name]}(` },
+      { id: 'utcnow', since: '3.12', pattern: /\bdatetime\.utc(?:now|fromtimestamp)\(/, description: '`datetime.utcnow()`/`utcfromtimestamp()` are deprecated: use `datetime.now(timezone.utc)` (returns an aware datetime; check comparisons against naive values)' },
+      { id: 'get-event-loop', since: '3.12', pattern: /\basyncio\.get_event_loop\(\)/, description: '`asyncio.get_event_loop()` warns without a running loop: use `asyncio.run()` or `asyncio.get_running_loop()`' }
+    ],
+    manual: [
+      { since: '0', text: 'Update `requires-python`, `.python-version`, Docker base images, and the CI matrix' },
+      { since: '0', text: 'Recreate the virtualenv and re-lock dependencies; check that compiled dependencies publish wheels for the target version' }
+    ]
+  },
+  pydantic: {
+    label: 'Pydantic',
+    aliases: ['pydantic'],
+    detect: { framework: 'pydantic' },
+    package: 'pydantic',
+    languages: ['python'],
+    steps: [
+      { id: 'parse-obj', since: '2', pattern: /\.parse_obj\(/, description: '`parse_obj()` is `model_validate()`', codemod: '.model_validate(' },
+      { id: 'parse-raw', since: '2', pattern: /\.parse_raw\(/, description: '`parse_raw()` is `model_validate_json()`', codemod: '.model_validate_json(' },
+      { id: 'update-forward-refs', since: '2', pattern: /\.update_forward_refs\(/, description: '`update_forward_refs()` is `model_rebuild()`', codemod: '.model_rebuild(' },
+      { id: 'fields', since: '2', pattern: /\.__fields__\b/, description: '`__fields__` is `model_fields`', codemod: '.model_fields' },
+      { id: 'field-regex', since: '2', pattern: /(\bField\([^)]*?)\bregex=/, description: '`Field(regex=...)` is `Field(pattern=...)`', codemod: '$1pattern=' },
+      { id: 'validator', since: '2', pattern: /@validator\(/, description: '`@validator` is `@field_validator` (add `@classmethod`; `values` becomes `info.data`)' },
+      { id: 'root-validator', since: '2', pattern: /@root_validator\b/, description: '`@root_validator` is `@model_validator(mode="before"|"after")`' },
+      { id: 'config-class', since: '2', pattern: /^\s*class Config\s*:/, description: '`class Config` is `model_config = ConfigDict(...)` (`orm_mode` → `from_attributes`, `allow_population_by_field_name` → `populate_by_name`)', scope: 'importers' },
+      { id: 'base-settings', since: '2', pattern: /\bfrom\s+pydantic\s+import\s+.*\bBaseSettings\b/, description: '`BaseSettings` moved to the `pydantic-settings` package' },
+      { id: 'parse-obj-as', since: '2', pattern: /\bparse_obj_as\(/, description: '`parse_obj_as(T, data)` is `TypeAdapter(T).validate_python(data)`' },
+      { id: 'from-orm', since: '2', pattern: /\.from_orm\(/, description: '`from_orm()` is `model_validate()` with `from_attributes=True` in the model config' },
+      { id: 'model-methods', since: '2', pattern: /\.(?:dict|json|copy|schema|schema_json)\(/, description: '`dict()`/`json()`/`copy()`/`schema()` are `model_dump()`/`model_dump_json()`/`model_copy()`/`model_json_schema()` on models (check the receiver is a model)', scope: 'importers' }
+    ],
+    manual: [
+      { since: '2', text: 'Run `bump-pydantic` for the remaining mechanical changes, then review its diff' },
+      { since: '2', text: '`Optional[X]` fields are now required unless they have a default: add `= None` where v1 relied on the implicit default' },
+      { since: '2', text: 'Coercion is stricter (numbers are no longer coerced to `str`); run the test suite against real payloads' },
+      { since: '2', text: 'Upgrade libraries that wrap Pydantic together (FastAPI >= 0.100, SQLModel, pydantic-settings)' }
+    ]
+  },
+  react: {
+    label: 'React',
+    aliases: ['react', 'react-dom'],
+    detect: { framework: 'react' },
+    package: 'react-dom',
+    languages: JS_LANGUAGES,
+    steps: [
+      { id: 'render', since: '18', pattern: /\bReactDOM\.render\(/, description: '`ReactDOM.render()` is `createRoot(container).render()` from `react-dom/client`' },
+      { id: 'named-render', since: '18', pattern: /\bimport\s*\{[^}]*\b(?:render|hydrate)\b[^}]*\}\s*from\s*['"]react-dom['"]/, description: '`render`/`hydrate` imported from `react-dom`: switch to `createRoot`/`hydrateRoot` from `react-dom/client`' },
+      { id: 'hydrate', since: '18', pattern: /\bReactDOM\.hydrate\(/, description: '`ReactDOM.hydrate()` is `hydrateRoot()` from `react-dom/client`' },
+      { id: 'unmount', since: '18', pattern: /\bunmountComponentAtNode\(/, description: '`unmountComponentAtNode()` is `root.unmount()`' },
+      { id: 'node-stream', since: '18', pattern: /\brenderToNodeStream\(/, description: '`renderToNodeStream()` is `renderToPipeableStream()`' },
+      { id: 'test-utils-act', since: '19', pattern: /^(\s*import\s*\{\s*act\s*\}\s*from\s*)(['"])react-dom\/test-utils\2/, description: '`act` moved from `react-dom/test-utils` to `react`', codemod: '$1$2react$2' },
+      { id: 'find-dom-node', since: '19', pattern: /\bfindDOMNode\(/, description: '`findDOMNode()` was removed: use a ref' },
+      { id: 'function-default-props', since: '19', pattern: /^\s*\w+\.(?:defaultProps|propTypes)\s*=/, description: '`defaultProps`/`propTypes` on function components are ignored: use default parameters and TypeScript' },
+      { id: 'string-refs', since: '19', pattern: /\bref=["']\w+["']/, description: 'String refs were removed: use `useRef` or callback refs' },
+      { id: 'legacy-context', since: '19', pattern: /\b(?:contextTypes|childContextTypes|getChildContext)\b/, description: 'Legacy context was removed: use `createContext`' }
+    ],
+    manual: [
+      { since: '0', text: 'Upgrade `react`, `react-dom`, `@types/react`, and `@types/react-dom` together' },
+      { since: '18', text: 'Updates are batched everywhere (timeouts, promises); check code that read the DOM right after `setState`' },
+      { since: '18', text: '`StrictMode` mounts effects twice in development; make effects clean up after themselves' }
+    ]
+  },
+  express: {
+    label: 'Express',
+    aliases: ['express', 'expressjs'],
+    detect: { framework: 'express' },
+    package: 'express',
+    languages: JS_LANGUAGES,
+    steps: [
+      { id: 'app-del', since: '5', pattern: /\b(app|router)\.del\(/, description: '`app.del()` is `app.delete()`', codemod: '$1.delete(' },
+      { id: 'sendfile', since: '5', pattern: /\bres\.sendfile\(/, description: '`res.sendfile()` is `res.sendFile()`', codemod: 'res.sendFile(' },
+      { id: 'status-first', since: '5', pattern: /\bres\.(json|jsonp|send)\(\s*(\d{3})\s*,\s*/, description: '`res.send(status, body)` is `res.status(status).send(body)`', codemod: 'res.status($2).$1(' },
+      { id: 'status-last', since: '5', pattern: /\bres\.(?:json|jsonp|send)\([^()]*,\s*\d{3}\s*\)/, description: '`res.json(body, status)` is `res.status(status).json(body)`' },
+      { id: 'req-param', since: '5', pattern: /\breq\.param\(/, description: '`req.param()` was removed: read `req.params`, `req.body`, or `req.query`' },
+      { id: 'redirect-back', since: '5', pattern: /\bres\.(?:redirect|location)\(\s*['"]back['"]/, description: "The `'back'` shortcut was removed: use `req.get('Referrer') || '/'`" },
+      { id: 'path-syntax', since: '5', pattern: /\.(?:get|post|put|patch|delete|all|use)\(\s*['"][^'"]*(?:\*|\?|\()[^'"]*['"]/, description: 'Route paths use path-to-regexp 8: `*` needs a name (`/*splat`), optional segments use braces (`/:id{.:ext}`), and regex groups are gone' }
+    ],
+    manual: [
+      { since: '5', text: 'Express 5 needs Node 18+; update `@types/express` to v5' },
+      { since: '5', text: 'Rejected promises from async handlers now reach the error handler: remove wrappers like `express-async-handler` once tests pass' },
+      { since: '5', text: '`express.urlencoded()` defaults to `extended: false`; set it explicitly where nested forms are parsed' }
+    ]
+  }
+};
+
+/**
+ * Parse a migration target ("Node 18→22", "pydantic v1 -> v2", "python 3.8 to 3.12")
+ * @param {string} text - Target text
+ * @returns {{name: string, from: string|null, to: string}|null}
+ */
+function parseTarget(text) {
+  const match = String(text || '').trim()
+    .match(/^([A-Za-z][\w.@/-]*?)\s*(?:v?(\d[\w.]*))?\s*(?:→|->|=>|\bto\b)\s*v?(\d[\w.]*)$/i);
+  if (!match) return null;
+  return { name: match[1].toLowerCase(), from: match[2] || null, to: match[3] };
+}
+
+/**
+ * Find the guide for a name
+ * @param {string} name - Target name
+ * @returns {{key: string, guide: Object}|null}
+ */
+function findGuide(name) {
+  const normalized = String(name || '').toLowerCase();
+  for (const [key, guide] of Object.entries(GUIDES)) {
+    if (guide.aliases.includes(normalized)) return { key, guide };
+  }
+  return null;
+}
+
+/**
+ * Check whether a version falls in `(from, to]`
+ * @param {string} since - Version the change lands in
+ * @param {string} from - Current version
+ * @param {string} to - Target version
+ * @returns {boolean}
+ */
+function applies(since, from, to) {
+  return compareVersions(since, from) > 0 && compareVersions(since, to) <= 0;
+}
+
+/**
+ * Current version from runtime or framework detection
+ * @param {string} basePath - Project root
+ * @param {Object} guide - Migration guide
+ * @returns {string|null}
+ */
+function detectCurrentVersion(basePath, guide) {
+  if (guide.detect.runtime) {
+    const detected = detectRuntimes(basePath);
+    const runtime = detected && detected.runtimes.find(entry => entry.name === guide.detect.runtime);
+    return runtime ? runtime.minimum || runtime.version : null;
+  }
+  const detected = detectFrameworkVersions(basePath);
+  const framework = detected && detected.frameworks.find(entry => entry.name === guide.detect.framework);
+  return framework ? framework.version : null;
+}
+
+/**
+ * Function or class enclosing a line, from the map's start lines
+ * @param {Object} fileData - Map file entry
+ * @param {number} line - Line number
+ * @returns {string|null}
+ */
+function enclosingSymbol(fileData, line) {
+  const symbols = fileData.symbols || {};
+  let best = null;
+  for (const symbol of [...(symbols.functions || []), ...(symbols.classes || [])]) {
+    if (symbol.line && symbol.line <= line && (!best || symbol.line > best.line)) best = symbol;
+  }
+  if (!best) return null;
+  return best.receiver ? `${best.receiver}.${best.name}` : best.name;
+}
+
+/**
+ * Check whether a file imports a package (or a subpath of it)
+ * @param {Object} fileData - Map file entry
+ * @param {string} name - Package name
+ * @returns {boolean}
+ */
+function importsPackage(fileData, name) {
+  return (fileData.imports || []).some(imp => {
+    const source = String(imp.source || '');
+    return source === name || source.startsWith(`${name}/`) || source.startsWith(`${name}.`);
+  });
+}
+
+/**
+ * Plan a migration: steps in range with their call sites
+ * @param {Object} map - Repo map
+ * @param {string|Object} target - Target text or result of parseTarget
+ * @param {Object} options
+ * @param {string} [options.from] - Current version (overrides the target's)
+ * @param {Function} options.readFile - `(file) => content|null`
+ * @returns {{success: boolean, guide?: string, label?: string, from?: string, to?: string, steps?: Object[], manual?: string[], error?: string}}
+ */
+function planMigration(map, target, options = {}) {
+  const parsed = typeof target === 'string' ? parseTarget(target) : target;
+  if (!parsed) {
+    return { success: false, error: 'Usage: /migrate "<name> <from>→<to>", e.g. "Node 18→22" or "Pydantic v1→v2"' };
+  }
+  const found = findGuide(parsed.name);
+  if (!found) {
+    return { success: false, error: `No migration guide for ${parsed.name}. Known: ${Object.values(GUIDES).map(guide => guide.label).join(', ')}` };
+  }
+  const from = options.from || parsed.from;
+  if (!from) {
+    return { success: false, error: `Could not detect the current ${found.guide.label} version; give it in the target (e.g. "${found.guide.label} 1→${parsed.to}")` };
+  }
+  if (compareVersions(parsed.to, from) <= 0) {
+    return { success: false, error: `Target ${parsed.to} is not newer than ${from}` };
+  }
+
+  const { guide } = found;
+  const files = Object.entries((map && map.files) || {})
+    .filter(([, fileData]) => guide.languages.includes(fileData.language))
+    .sort(([a], [b]) => a.localeCompare(b));
+  const contents = new Map();
+  const content = file => {
+    if (!contents.has(file)) contents.set(file, options.readFile(file));
+    return contents.get(file);
+  };
+
+  const steps = guide.steps.filter(step => applies(step.since, from, parsed.to)).map(step => {
+    const sites = [];
+    for (const [file, fileData] of files) {
+      if (step.scope === 'importers' && !importsPackage(fileData, guide.package)) continue;
+      const text = content(file);
+      if (!text) continue;
+      text.split('\n').forEach((line, index) => {
+        if (!step.pattern.test(line)) return;
+        const site = { file, line: index + 1, symbol: enclosingSymbol(fileData, index + 1), text: line.trim() };
+        if (step.codemod) site.replacement = rewrite(step, line);
+        sites.push(site);
+      });
+    }
+    return { id: step.id, since: step.since, description: step.description, automatic: Boolean(step.codemod), sites };
+  }).filter(step => step.sites.length > 0);
+
+  const manual = guide.manual.filter(item => item.since === '0' || applies(item.since, from, parsed.to)).map(item => item.text);
+
+  return { success: true, guide: found.key, label: guide.label, from, to: parsed.to, steps, manual };
+}
+
+/**
+ * Apply a step's codemod to every match on a line
+ * @param {Object} step - Guide step with a codemod
+ * @param {string} line - Source line
+ * @returns {string}
+ */
+function rewrite(step, line) {
+  return line.replace(new RegExp(step.pattern.source, `${step.pattern.flags.replace('g', '')}g`), step.codemod);
+}
+
+/**
+ * Apply the plan's codemods
+ * A line is rewritten only when it still reads as planned; several steps
+ * matching one line are applied in guide order.
+ * @param {Object} plan - Result of planMigration
+ * @param {Object} io
+ * @param {Function} io.readFile - `(file) => content`
+ * @param {Function} io.writeFile - `(file, content) => void`
+ * @returns {{files: string[], applied: number, stale: Array<{file: string, line: number}>}}
+ */
+function applyCodemods(plan, io) {
+  const rules = GUIDES[plan.guide].steps;
+  const byFile = new Map();
+  for (const step of plan.steps.filter(candidate => candidate.automatic)) {
+    const rule = rules.find(candidate => candidate.id === step.id);
+    for (const site of step.sites) {
+      if (!byFile.has(site.file)) byFile.set(site.file, new Map());
+      const byLine = byFile.get(site.file);
+      if (!byLine.has(site.line)) byLine.set(site.line, { text: site.text, rules: [] });
+      byLine.get(site.line).rules.push(rule);
+    }
+  }
+
+  const files = [];
+  const stale = [];
+  let applied = 0;
+  for (const [file, byLine] of byFile) {
+    const lines = io.readFile(file).split('\n');
+    let changed = false;
+    for (const [number, planned] of byLine) {
+      const current = lines[number - 1];
+      if (current === undefined || current.trim() !== planned.text) {
+        stale.push({ file, line: number });
+        continue;
+      }
+      const next = planned.rules.reduce((line, rule) => rewrite(rule, line), current);
+      if (next !== current) {
+        lines[number - 1] = next;
+        changed = true;
+        applied++;
+      }
+    }
+    if (changed) {
+      io.writeFile(file, lines.join('\n'));
+      files.push(file);
+    }
+  }
+  return { files, applied, stale };
+}
+
+/**
+ * Render a plan as a Markdown checklist
+ * @param {Object} plan - Result of planMigration
+ * @param {Object} [result] - Result of applyCodemods
+ * @returns {string}
+ */
+function renderChecklist(plan, result) {
+  const lines = [`## Migration: ${plan.label} ${plan.from} → ${plan.to}`, ''];
+  const automatic = plan.steps.filter(step => step.automatic);
+  const manual = plan.steps.filter(step => !step.automatic);
+  const site = item => `\`${item.file}:${item.line}\`${item.symbol ? ` (${item.symbol})` : ''}`;
+
+  if (automatic.length > 0) {
+    lines.push(`### Codemods${result ? ` (${result.applied} applied in ${result.files.length} files)` : ''}`, '');
+    for (const step of automatic) {
+      lines.push(`- [${result ? 'x' : ' '}] ${step.description} - ${step.sites.length} site(s)`);
+      step.sites.forEach(item => lines.push(`  - ${site(item)}`));
+    }
+    lines.push('');
+  }
+  if (manual.length > 0) {
+    lines.push('### Manual changes', '');
+    for (const step of manual) {
+      lines.push(`- [ ] ${step.description}`);
+      step.sites.forEach(item => lines.push(`  - ${site(item)}: \`${item.text}\``));
+    }
+    lines.push('');
+  }
+  if (plan.steps.length === 0) lines.push('No affected call sites found.', '');
+  lines.push('### Checklist', '', ...plan.manual.map(text => `- [ ] ${text}`), '- [ ] Run the test suite on the target version');
+  if (result && result.stale.length > 0) {
+    lines.push('', `${result.stale.length} site(s) changed since planning and were skipped: ${result.stale.map(item => `${item.file}:${item.line}`).join(', ')}`);
+  }
+  return `${lines.join('\n').trim()}\n`;
+}
+
+/**
+ * Plan a migration for a repository, detecting the current version
+ * @param {string} basePath - Repository root
+ * @param {string} target - Target text
+ * @param {Object} options
+ * @param {Object} options.map - Repo map
+ * @param {string} [options.from] - Current version
+ * @returns {Object} Result of planMigration
+ */
+function migrate(basePath, target, options = {}) {
+  const parsed = parseTarget(target);
+  const found = parsed && findGuide(parsed.name);
+  const from = options.from || (parsed && parsed.from) || (found ? detectCurrentVersion(basePath, found.guide) : null);
+  return planMigration(options.map, parsed || target, {
+    from,
+    readFile: file => {
+      try {
+        return fs.readFileSync(path.join(basePath, file), 'utf8');
+      } catch {
+        return null;
+      }
+    }
+  });
+}
+
+// When run directly, output JSON
+if (require.main === module) {
+  const basePath = process.cwd();
+  const map = require('../repo-map').load(basePath);
+  const plan = map
+    ? migrate(basePath, process.argv.slice(2).join(' '), { map })
+    : { success: false, error: 'No repo map found. Run /repo-map init first.' };
+  const indent = process.stdout.isTTY ? 2 : 0;
+  console.log(JSON.stringify(plan, null, indent));
+  if (!plan.success) process.exitCode = 1;
+}
+
+module.exports = {
+  GUIDES,
+  parseTarget,
+  findGuide,
+  detectCurrentVersion,
+  enclosingSymbol,
+  planMigration,
+  applyCodemods,
+  renderChecklist,
+  migrate
+};
@@ -0,0 +1,555 @@
+/**
+ * Slop Auto-Fixer
+ *
+ * Turns findings whose pattern declares `autoFix: 'remove' | 'replace' |
+ * 'add_logging'` into line edits, groups them per file, and either renders
+ * a unified diff (dry run) or writes the result. Edits that overlap another
+ * edit in the same file are skipped instead of applied with shifted offsets,
+ * and a fix is only generated when it is safe without a parser:
+ *
+ * - remove: whole-line statements (`console.log(...)`, `print(...)`,
+ *   `dbg!(...)`, multi-line calls included), comment lines, trailing
+ *   comments, and trailing whitespace. Statements that are the only body of
+ *   a brace-less `if`/loop are left alone; a Python block that would become
+ *   empty gets `pass`.
+ * - replace: custom patterns that define a `replacement`.
+ * - add_logging: empty JS/TS `catch` blocks log the error; Python
+ *   `except ...: pass` logs via `logging.exception` (adding `import logging`).
+ *
+ * Everything else is reported in `skipped` with a reason.
+ *
+ * @module patterns/fixer
+ * @author Avi Fenesh
+ * @license MIT
+ */
+
+const fs = require('fs');
+const path = require('path');
+const { slopPatterns } = require('./slop-patterns');
+const { detectLanguage } = require('./slop-analyzers');
+const { loadCustomPatterns } = require('./custom-patterns');
+const { t } = require('../messages');
+
+/**
+ * Strategies this module can apply
+ */
+const FIXABLE = new Set(['remove', 'replace', 'add_logging']);
+
+/**
+ * Lines of context around each diff hunk
+ */
+const DIFF_CONTEXT = 3;
+
+/**
+ * Maximum lines a removed statement may span
+ */
+const MAX_STATEMENT_LINES = 50;
+
+/**
+ * Comment markers per detected language
+ */
+const LINE_COMMENTS = {
+  js: ['//'],
+  rust: ['//'],
+  go: ['//'],
+  java: ['//'],
+  python: ['#'],
+  shell: ['#'],
+  ruby: ['#']
+};
+
+/**
+ * Split file content into lines, remembering the line ending style
+ * @param {string} content - File content
+ * @returns {{lines: string[], eol: string, finalNewline: boolean}}
+ */
+function splitLines(content) {
+  const eol = content.includes('\r\n') ? '\r\n' : '\n';
+  const lines = content.split(/\r?\n/);
+  const finalNewline = lines.length > 1 && lines[lines.length - 1] === '';
+  if (finalNewline) lines.pop();
+  return { lines, eol, finalNewline };
+}
+
+/**
+ * Join lines back into file content
+ * @param {string[]} lines - Lines
+ * @param {string} eol - Line ending
+ * @param {boolean} finalNewline - Whether the file ends with a newline
+ * @returns {string}
+ */
+function joinLines(lines, eol, finalNewline) {
+  return lines.join(eol) + (finalNewline && lines.length > 0 ? eol : '');
+}
+
+/**
+ * Leading whitespace of a line
+ * @param {string} line
+ * @returns {string}
+ */
+function indentOf(line) {
+  return line.match(/^\s*/)[0];
+}
+
+/**
+ * Whether a quote character leaves `prefix` inside an unterminated string
+ * @param {string} prefix - Text before a position on the line
+ * @returns {boolean}
+ */
+function insideString(prefix) {
+  let quote = null;
+  for (let i = 0; i < prefix.length; i++) {
+    const ch = prefix[i];
+    if (quote) {
+      if (ch === '\\') i++;
+      else if (ch === quote) quote = null;
+    } else if (ch === '"' || ch === "'" || ch === '`') {
+      quote = ch;
+    }
+  }
+  return quote !== null;
+}
+
+/**
+ * Whether the rest of a line is empty, a semicolon, or a comment
+ * @param {string} rest - Text after a statement
+ * @param {string[]} markers - Line comment markers
+ * @returns {boolean}
+ */
+function isStatementTail(rest, markers) {
+  const tail = rest.replace(/^\s*;?\s*/, '');
+  return tail === '' || markers.some(marker => tail.startsWith(marker));
+}
+
+/**
+ * Find where a call starting at `column` on `lineIndex` closes
+ * @param {string[]} lines - File lines
+ * @param {number} lineIndex - 0-based line of the call
+ * @param {number} column - Index of the opening parenthesis
+ * @returns {{line: number, column: number}|null} Position after the closing parenthesis
+ */
+function findCallEnd(lines, lineIndex, column) {
+  let depth = 0;
+  let quote = null;
+  const last = Math.min(lines.length, lineIndex + MAX_STATEMENT_LINES);
+  for (let l = lineIndex; l < last; l++) {
+    const line = lines[l];
+    for (let c = l === lineIndex ? column : 0; c < line.length; c++) {
+      const ch = line[c];
+      if (quote) {
+        if (ch === '\\') c++;
+        else if (ch === quote) quote = null;
+        continue;
+      }
+      if (ch === '"' || ch === "'" || ch === '`') quote = ch;
+      else if (ch === '(') depth++;
+      else if (ch === ')' && --depth === 0) return { line: l, column: c + 1 };
+    }
+    if (quote && quote !== '`') return null;
+  }
+  return null;
+}
+
+/**
+ * Previous and next non-blank line indexes around a range
+ * @param {string[]} lines - File lines
+ * @param {number} start - 0-based first line
+ * @param {number} end - 0-based last line
+ * @returns {{prev: number, next: number}} -1 when there is none
+ */
+function neighbors(lines, start, end) {
+  let prev = start - 1;
+  while (prev >= 0 && lines[prev].trim() === '') prev--;
+  let next = end + 1;
+  while (next < lines.length && lines[next].trim() === '') next++;
+  return { prev, next: next < lines.length ? next : -1 };
+}
+
+/**
+ * Replacement lines when removing lines [start, end]
+ *
+ * Returns null when removal would change control flow (brace-less `if` or
+ * loop body), and `pass` when it would empty a Python block.
+ *
+ * @param {string[]} lines - File lines
+ * @param {number} start - 0-based first line
+ * @param {number} end - 0-based last line
+ * @param {string} language - Detected language
+ * @returns {string[]|null}
+ */
+function removalLines(lines, start, end, language) {
+  const { prev, next } = neighbors(lines, start, end);
+  const prevLine = prev >= 0 ? lines[prev].trim() : '';
+
+  if (language === 'python') {
+    const opensBlock = /:\s*(#.*)?$/.test(prevLine);
+    const indent = indentOf(lines[start]);
+    const closesBlock = next === -1 || indentOf(lines[next]).length < indent.length;
+    return opensBlock && closesBlock ? [`${indent}pass`] : [];
+  }
+
+  if (/^(\}\s*)?(if|else\s+if|for|while)\b.*\)$/.test(prevLine) || /(=>|\belse|\bdo)$/.test(prevLine)) {
+    return null;
+  }
+  return [];
+}
+
+/**
+ * Strategy: remove statements or comments matched by a pattern
+ * @param {Object} ctx - Fix context
+ * @returns {Object|string} Edit, or a skip reason
+ */
+function fixRemove(ctx) {
+  const { lines, index, match, language } = ctx;
+  const line = lines[index];
+  const markers = LINE_COMMENTS[language] || ['//', '#'];
+  const prefix = line.slice(0, match.index);
+  const trimmed = line.trim();
+
+  // Whole-line comment
+  if (markers.some(marker => trimmed.startsWith(marker))) {
+    if (ctx.patternName === 'commented_code' &&
+        !/[;{}()=\[\]]\s*$|^\s*(\/\/|#)\s*(if|for|while|return|const|let|var|def|import|function|class)\b/.test(line)) {
+      return t('fixer.skip.notCode');
+    }
+    return { start: index, end: index, lines: [] };
+  }
+
+  // Trailing comment after code
+  const marker = markers.find(m => line.startsWith(m, match.index));
+  if (marker && prefix.trim() !== '' && !insideString(prefix)) {
+    return { start: index, end: index, lines: [prefix.replace(/\s+$/, '')] };
+  }
+
+  // Whitespace-only match, e.g. trailing whitespace
+  if (match[0].trim() === '') {
+    return { start: index, end: index, lines: [line.slice(0, match.index) + line.slice(match.index + match[0].length)] };
+  }
+
+  // Statement on its own line(s)
+  if (prefix.trim() !== '') return t('fixer.skip.notStatement');
+  let endLine = index;
+  let endColumn = match.index + match[0].length;
+  if (match[0].endsWith('(')) {
+    const close = findCallEnd(lines, index, endColumn - 1);
+    if (!close) return t('fixer.skip.noCallEnd');
+    endLine = close.line;
+    endColumn = close.column;
+  }
+  if (!isStatementTail(lines[endLine].slice(endColumn), markers)) return t('fixer.skip.sharedLine');
+
+  const replacement = removalLines(lines, index, endLine, language);
+  if (!replacement) return t('fixer.skip.bracelessBody');
+  return { start: index, end: endLine, lines: replacement };
+}
+
+/**
+ * Strategy: rewrite the matched text
+ * @param {Object} ctx - Fix context
+ * @returns {Object|string} Edit, or a skip reason
+ */
+function fixReplace(ctx) {
+  const { lines, index, pattern } = ctx;
+  const line = lines[index];
+  if (typeof pattern.replacement !== 'string') return t('fixer.skip.noReplacement');
+  const flags = pattern.pattern.flags.includes('g') ? pattern.pattern.flags : pattern.pattern.flags + 'g';
+  const updated = line.replace(new RegExp(pattern.pattern.source, flags), pattern.replacement);
+  return updated === line ? 'replacement leaves the line unchanged' : { start: index, end: index, lines: [updated] };
+}
+
+/**
+ * Strategy: log errors swallowed by empty handlers
+ * @param {Object} ctx - Fix context
+ * @returns {Object|string} Edit, or a skip reason
+ */
+function fixAddLogging(ctx) {
+  const { lines, index, language } = ctx;
+  const line = lines[index];
+
+  if (language === 'python') {
+    const handler = line.match(/^(\s*)(except\b[^:]*:)\s*pass\s*$/);
+    if (handler) {
+      return {
+        start: index,
+        end: index,
+        lines: [`${handler[1]}${handler[2]}`, `${handler[1]}    logging.exception("Suppressed exception")`],
+        requiresImport: 'logging'
+      };
+    }
+    // `except ...:` followed by a line holding only `pass`
+    const body = index + 1 < lines.length && lines[index + 1].match(/^(\s*)pass\s*(?:#.*)?$/);
+    if (/^\s*except\b[^:]*:\s*(?:#.*)?$/.test(line) && body) {
+      return {
+        start: index + 1,
+        end: index + 1,
+        lines: [`${body[1]}logging.exception("Suppressed exception")`],
+        requiresImport: 'logging'
+      };
+    }
+    return t('fixer.skip.notExceptPass');
+  }
+
+  if (language !== 'js') return t('fixer.skip.noLoggingFix');
+
+  const inline = line.match(/catch\s*(?:\(\s*([A-Za-z_$][\w$]*)?\s*\))?\s*\{\s*\}/);
+  if (inline) {
+    const name = inline[1] || 'error';
+    const fixed = `catch (${name}) { console.error(${name}); }`;
+    return { start: index, end: index, lines: [line.slice(0, inline.index) + fixed + line.slice(inline.index + inline[0].length)] };
+  }
+
+  // `catch (e) {` followed by a line holding only `}`
+  const opener = line.match(/catch\s*(?:\(\s*([A-Za-z_$][\w$]*)?\s*\))?\s*\{\s*$/);
+  if (opener && index + 1 < lines.length && /^\s*\}/.test(lines[index + 1])) {
+    const name = opener[1] || 'error';
+    const head = opener[1] ? line : line.slice(0, opener.index) + `catch (${name}) {`;
+    return {
+      start: index,
+      end: index,
+      lines: [head, `${indentOf(lines[index + 1])}  console.error(${name});`]
+    };
+  }
+  return t('fixer.skip.unsupportedCatch');
+}
+
+/**
+ * Strategy implementations by autoFix value
+ */
+const STRATEGIES = {
+  remove: fixRemove,
+  replace: fixReplace,
+  add_logging: fixAddLogging
+};
+
+/**
+ * Line index for a Python `import` statement to add
+ * @param {string[]} lines - File lines
+ * @param {string} moduleName - Module to import
+ * @returns {{index: number, line: string}|null} null when already imported
+ */
+function pythonImportEdit(lines, moduleName) {
+  const imported = new RegExp(`^(import\\s+(\\w+\\s*,\\s*)*${moduleName}\\b|from\\s+${moduleName}\\s+import)`);
+  if (lines.some(line => imported.test(line))) return null;
+
+  const firstImport = lines.findIndex(line => /^(import|from)\s+\w/.test(line) && !/^from\s+__future__\b/.test(line));
+  if (firstImport !== -1) return { index: firstImport, line: `import ${moduleName}` };
+
+  // After shebang, encoding line, and a leading docstring
+  let index = 0;
+  while (index < lines.length && /^#!|^#.*coding[:=]/.test(lines[index])) index++;
+  const docstring = index < lines.length && lines[index].match(/^\s*(?:[rRuU]?)("""|''')/);
+  if (docstring) {
+    const quote = docstring[1];
+    const rest = lines[index].slice(lines[index].indexOf(quote) + 3);
+    if (!rest.includes(quote)) {
+      index++;
+      while (index < lines.length && !lines[index].includes(quote)) index++;
+    }
+    index++;
+  }
+  while (index < lines.length && /^from\s+__future__\b/.test(lines[index])) index++;
+  return { index, line: `import ${moduleName}` };
+}
+
+/**
+ * Apply non-overlapping edits bottom-up
+ * @param {string[]} lines - Original lines
+ * @param {Object[]} edits - Sorted edits ({start, end, lines}, 0-based inclusive; end < start inserts)
+ * @returns {string[]}
+ */
+function applyEdits(lines, edits) {
+  const result = lines.slice();
+  for (const edit of edits.slice().reverse()) {
+    result.splice(edit.start, edit.end - edit.start + 1, ...edit.lines);
+  }
+  return result;
+}
+
+/**
+ * Render a unified diff for one file from its edits
+ * @param {string} file - Path shown in the header
+ * @param {string[]} oldLines - Original lines
+ * @param {Object[]} edits - Sorted, non-overlapping edits
+ * @param {Object} [options]
+ * @param {boolean} [options.finalNewline=true] - Whether the file ends with a newline
+ * @returns {string} Diff text (empty when there are no edits)
+ */
+function formatUnifiedDiff(file, oldLines, edits, options = {}) {
+  if (edits.length === 0) return '';
+  const finalNewline = options.finalNewline !== false;
+  const out = [`--- a/${file}`, `+++ b/${file}`];
+
+  // Group edits whose context windows touch
+  const groups = [];
+  for (const edit of edits) {
+    const group = groups[groups.length - 1];
+    if (group && edit.start - group[group.length - 1].end - 1 <= DIFF_CONTEXT * 2) group.push(edit);
+    else groups.push([edit]);
+  }
+
+  let delta = 0;
+  for (const group of groups) {
+    const from = Math.max(0, group[0].start - DIFF_CONTEXT);
+    const to = Math.min(oldLines.length - 1, Math.max(group[group.length - 1].end, group[group.length - 1].start - 1) + DIFF_CONTEXT);
+    const body = [];
+    let oldCount = 0;
+    let newCount = 0;
+    let cursor = from;
+    const markEof = (index, text) => {
+      body.push(text);
+      if (!finalNewline && index === oldLines.length - 1) body.push('\\ No newline at end of file');
+    };
+
+    for (const edit of group) {
+      for (; cursor < edit.start; cursor++) {
+        markEof(cursor, ` ${oldLines[cursor]}`);
+        oldCount++;
+        newCount++;
+      }
+      for (let i = edit.start; i <= edit.end; i++) {
+        markEof(i, `-${oldLines[i]}`);
+        oldCount++;
+      }
+      for (const added of edit.lines) {
+        body.push(`+${added}`);
+        newCount++;
+      }
+      cursor = Math.max(cursor, edit.end + 1);
+    }
+    for (; cursor <= to; cursor++) {
+      markEof(cursor, ` ${oldLines[cursor]}`);
+      oldCount++;
+      newCount++;
+    }
+
+    const oldStart = oldCount === 0 ? from : from + 1;
+    const newStart = newCount === 0 ? from + delta : from + delta + 1;
+    out.push(`@@ -${oldStart},${oldCount} +${newStart},${newCount} @@`, ...body);
+    delta += newCount - oldCount;
+  }
+
+  return out.join('\n') + '\n';
+}
+
+/**
+ * Generate fixes for pipeline findings
+ *
+ * @param {string} repoPath - Repository root
+ * @param {Array} findings - Pipeline findings
+ * @param {Object} [options]
+ * @param {Object} [options.patterns] - Patterns by name (defaults to built-in plus project custom patterns)
+ * @returns {{files: Array<{file: string, edits: Object[], fixed: Object[], fixes: Array<{finding: Object, edits: Object[]}>,
+ *   original: string, updated: string, diff: string}>, skipped: Array<{finding: Object, reason: string}>}}
+ *   Edits are 1-based inclusive line ranges; `fixes` lists the edits each finding needs
+ */
+function generateFixes(repoPath, findings, options = {}) {
+  const patterns = options.patterns || { ...slopPatterns, ...loadCustomPatterns(repoPath).patterns };
+  const skipped = [];
+  const byFile = new Map();
+
+  for (const finding of findings) {
+    const pattern = patterns[finding.patternName];
+    if (!pattern || !FIXABLE.has(pattern.autoFix || finding.autoFix)) continue;
+    if (!finding.file || !finding.line) {
+      skipped.push({ finding, reason: t('fixer.skip.noLine') });
+      continue;
+    }
+    if (!byFile.has(finding.file)) byFile.set(finding.file, []);
+    byFile.get(finding.file).push(finding);
+  }
+
+  const files = [];
+  for (const [file, fileFindings] of byFile) {
+    let original;
+    try {
+      original = fs.readFileSync(path.isAbsolute(file) ? file : path.join(repoPath, file), 'utf8');
+    } catch {
+      for (const finding of fileFindings) skipped.push({ finding, reason: t('fixer.skip.unreadable') });
+      continue;
+    }
+
+    const { lines, eol, finalNewline } = splitLines(original);
+    const language = detectLanguage(file);
+    const candidates = [];
+    for (const finding of fileFindings) {
+      const pattern = patterns[finding.patternName];
+      const index = finding.line - 1;
+      const match = pattern.pattern && index < lines.length ? lines[index].match(pattern.pattern) : null;
+      if (!match && (pattern.autoFix || finding.autoFix) !== 'add_logging') {
+        skipped.push({ finding, reason: t('fixer.skip.noLongerMatches') });
+        continue;
+      }
+      const strategy = STRATEGIES[pattern.autoFix || finding.autoFix];
+      const edit = strategy({ lines, index, match, pattern, patternName: finding.patternName, language });
+      if (typeof edit === 'string') skipped.push({ finding, reason: edit });
+      else candidates.push({ ...edit, finding });
+    }
+
+    // Keep the first edit of any overlapping pair so offsets stay valid
+    candidates.sort((a, b) => a.start - b.start || a.end - b.end);
+    const edits = [];
+    for (const edit of candidates) {
+      const previous = edits[edits.length - 1];
+      if (previous && edit.start <= previous.end) {
+        skipped.push({ finding: edit.finding, reason: t('fixer.skip.overlaps', { pattern: previous.finding.patternName, line: previous.start + 1 }) });
+        continue;
+      }
+      edits.push(edit);
+    }
+    if (edits.length === 0) continue;
+
+    const imports = new Set(edits.filter(edit => edit.requiresImport).map(edit => edit.requiresImport));
+    for (const moduleName of imports) {
+      const insert = pythonImportEdit(lines, moduleName);
+      if (!insert) continue;
+      const at = edits.findIndex(edit => edit.start >= insert.index);
+      const importEdit = { start: insert.index, end: insert.index - 1, lines: [insert.line], finding: null };
+      edits.splice(at === -1 ? edits.length : at, 0, importEdit);
+    }
+
+    const toPublic = ({ start, end, lines: added }) => ({ start: start + 1, end: end + 1, lines: added });
+    const importEdits = edits.filter(edit => !edit.finding);
+    const updatedLines = applyEdits(lines, edits);
+    files.push({
+      file,
+      edits: edits.map(toPublic),
+      fixed: edits.filter(edit => edit.finding).map(edit => edit.finding),
+      fixes: edits.filter(edit => edit.finding).map(edit => ({
+        finding: edit.finding,
+        edits: (edit.requiresImport ? [...importEdits, edit] : [edit]).map(toPublic)
+      })),
+      original,
+      updated: joinLines(updatedLines, eol, finalNewline),
+      diff: formatUnifiedDiff(file.replace(/\\/g, '/'), lines, edits, { finalNewline })
+    });
+  }
+
+  return { files, skipped };
+}
+
+/**
+ * Write generated fixes to disk
+ * @param {string} repoPath - Repository root
+ * @param {Object} fixes - Result of generateFixes
+ * @param {Object} [options]
+ * @param {Object} [options.journal] - Transaction from lib/journal `begin`; records the writes for rollback
+ * @returns {{filesChanged: number, findingsFixed: number}}
+ */
+function writeFixes(repoPath, fixes, options = {}) {
+  let findingsFixed = 0;
+  for (const entry of fixes.files) {
+    const target = path.isAbsolute(entry.file) ? entry.file : path.join(repoPath, entry.file);
+    if (options.journal) options.journal.writeFile(path.resolve(target), entry.updated);
+    else fs.writeFileSync(target, entry.updated);
+    findingsFixed += entry.fixed.length;
+  }
+  return { filesChanged: fixes.files.length, findingsFixed };
+}
+
+module.exports = {
+  FIXABLE,
+  generateFixes,
+  writeFixes,
+  formatUnifiedDiff,
+  applyEdits
+};
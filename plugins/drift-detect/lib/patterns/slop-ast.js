@@ -0,0 +1,270 @@
+/**
+ * AST-backed Slop Detection
+ *
+ * When `@babel/parser` can be resolved (`npm install @babel/parser`, or via
+ * `NODE_PATH`), JavaScript/TypeScript patterns that declare an `ast` matcher
+ * are checked against the syntax tree instead of their regex. This skips
+ * matches inside strings and comments and catches forms the regex misses,
+ * such as `console["log"](...)` or `console?.log(...)`. Without a parser, or
+ * when a file fails to parse, the regex patterns run unchanged.
+ *
+ * Matchers (`pattern.ast.matcher`):
+ * - call: `name(...)` and `new name(...)` calls, e.g. `{ names: ['eval'] }`
+ * - member_call: `object.property(...)` calls, e.g. `{ object: 'console', properties: ['log'] }`
+ * - import: `import`/`export ... from`, `require()` and `import()` of `sources`
+ *   (`'lodash/*'` also matches subpaths)
+ * - empty_catch: `catch` blocks with no statements and no comments
+ * - empty_function: function bodies with no statements and no comments
+ * - placeholder_throw: `throw new Error('TODO ...')` where the message matches `message`
+ *
+ * @module patterns/slop-ast
+ * @author Avi Fenesh
+ * @license MIT
+ */
+
+const path = require('path');
+
+/**
+ * Parser package tried for JavaScript/TypeScript
+ */
+const PARSER_MODULE = '@babel/parser';
+
+/**
+ * Keys that never hold child nodes
+ */
+const SKIP_KEYS = new Set(['loc', 'start', 'end', 'range', 'extra', 'leadingComments', 'trailingComments', 'innerComments', 'comments', 'tokens']);
+
+const FUNCTION_TYPES = new Set(['FunctionDeclaration', 'FunctionExpression', 'ArrowFunctionExpression', 'ObjectMethod', 'ClassMethod', 'ClassPrivateMethod']);
+
+let _cachedParser;
+
+/**
+ * Load the JavaScript/TypeScript parser
+ * @param {Object} [options]
+ * @param {Function} [options.require] - Module loader (defaults to `require`)
+ * @returns {Object|null} Parser module, or null when it is not installed
+ */
+function loadParser(options = {}) {
+  if (!options.require && _cachedParser !== undefined) return _cachedParser;
+
+  let parser = null;
+  try {
+    const loaded = (options.require || require)(PARSER_MODULE);
+    parser = loaded && typeof loaded.parse === 'function' ? loaded : null;
+  } catch {
+    parser = null;
+  }
+
+  if (!options.require) _cachedParser = parser;
+  return parser;
+}
+
+/**
+ * Check whether AST detection is available
+ * @param {Object} [options] - See `loadParser`
+ * @returns {boolean}
+ */
+function isAvailable(options = {}) {
+  return loadParser(options) !== null;
+}
+
+/**
+ * Parse a JavaScript/TypeScript file
+ * @param {Object} parser - Parser module
+ * @param {string} content - File content
+ * @param {string} file - File path (extension selects syntax plugins)
+ * @returns {Object|null} AST, or null on a fatal parse error
+ */
+function parseSource(parser, content, file) {
+  const ext = path.extname(file).toLowerCase();
+  const isTs = ['.ts', '.tsx', '.mts', '.cts'].includes(ext);
+  const plugins = isTs ? ['typescript', 'decorators-legacy'] : ['jsx', 'decorators-legacy'];
+  if (ext === '.tsx') plugins.push('jsx');
+  try {
+    return parser.parse(content, {
+      sourceType: 'unambiguous',
+      errorRecovery: true,
+      allowReturnOutsideFunction: true,
+      allowAwaitOutsideFunction: true,
+      plugins
+    });
+  } catch {
+    return null;
+  }
+}
+
+/**
+ * Static name of a member expression property (`a.b` or `a["b"]`)
+ * @param {Object} node - MemberExpression
+ * @returns {string|null}
+ */
+function memberPropertyName(node) {
+  const property = node.property;
+  if (!property) return null;
+  if (!node.computed && (property.type === 'Identifier' || property.type === 'PrivateName')) {
+    return property.name || (property.id && property.id.name) || null;
+  }
+  if (property.type === 'StringLiteral' || (property.type === 'Literal' && typeof property.value === 'string')) {
+    return property.value;
+  }
+  if (property.type === 'TemplateLiteral' && property.expressions.length === 0) {
+    return property.quasis.map(quasi => quasi.value.cooked).join('');
+  }
+  return null;
+}
+
+/**
+ * Whether a block has no statements and no comments
+ * @param {Object} block - BlockStatement
+ * @returns {boolean}
+ */
+function isEmptyBlock(block) {
+  return Boolean(block) && block.type === 'BlockStatement' && block.body.length === 0 &&
+    !(block.innerComments && block.innerComments.length > 0);
+}
+
+/**
+ * String value of a literal or expression-free template literal
+ * @param {Object} node - AST node
+ * @returns {string|null}
+ */
+function stringValue(node) {
+  if (!node) return null;
+  if (node.type === 'StringLiteral' || (node.type === 'Literal' && typeof node.value === 'string')) return node.value;
+  if (node.type === 'TemplateLiteral') return node.quasis.map(quasi => quasi.value.cooked || '').join('');
+  return null;
+}
+
+/**
+ * Whether a module specifier is listed, honoring `pkg/*` subpath entries
+ * @param {string|null} specifier - Module specifier
+ * @param {string[]} sources - Listed specifiers
+ * @returns {boolean}
+ */
+function matchesSource(specifier, sources) {
+  if (typeof specifier !== 'string') return false;
+  return sources.some(source => source.endsWith('/*')
+    ? specifier === source.slice(0, -2) || specifier.startsWith(source.slice(0, -1))
+    : specifier === source);
+}
+
+/**
+ * Module specifier loaded by an import, re-export, `require()` or `import()`
+ * @param {Object} node - AST node
+ * @returns {string|null}
+ */
+function importedSource(node) {
+  if (node.type === 'ImportDeclaration' || node.type === 'ExportAllDeclaration' ||
+      (node.type === 'ExportNamedDeclaration' && node.source)) {
+    return stringValue(node.source);
+  }
+  if (node.type === 'ImportExpression') return stringValue(node.source);
+  if (node.type === 'CallExpression' && node.callee &&
+      (node.callee.type === 'Import' || (node.callee.type === 'Identifier' && node.callee.name === 'require'))) {
+    return stringValue(node.arguments && node.arguments[0]);
+  }
+  return null;
+}
+
+/**
+ * Matcher implementations: (node, spec) => boolean
+ */
+const MATCHERS = {
+  call(node, spec) {
+    if (node.type !== 'CallExpression' && node.type !== 'OptionalCallExpression' && node.type !== 'NewExpression') return false;
+    return Boolean(node.callee) && node.callee.type === 'Identifier' && spec.names.includes(node.callee.name);
+  },
+
+  member_call(node, spec) {
+    if (node.type !== 'CallExpression' && node.type !== 'OptionalCallExpression') return false;
+    const callee = node.callee;
+    if (!callee || (callee.type !== 'MemberExpression' && callee.type !== 'OptionalMemberExpression')) return false;
+    if (!callee.object || callee.object.type !== 'Identifier' || callee.object.name !== spec.object) return false;
+    return spec.properties.includes(memberPropertyName(callee));
+  },
+
+  import(node, spec) {
+    return matchesSource(importedSource(node), spec.sources);
+  },
+
+  empty_catch(node) {
+    return node.type === 'CatchClause' && isEmptyBlock(node.body);
+  },
+
+  empty_function(node) {
+    return FUNCTION_TYPES.has(node.type) && isEmptyBlock(node.body);
+  },
+
+  placeholder_throw(node, spec) {
+    if (node.type !== 'ThrowStatement' || !node.argument) return false;
+    const error = node.argument;
+    if ((error.type !== 'NewExpression' && error.type !== 'CallExpression') || !error.callee || !/Error$/.test(error.callee.name || '')) {
+      return false;
+    }
+    const message = stringValue(error.arguments && error.arguments[0]);
+    return message !== null && spec.message.test(message);
+  }
+};
+
+/**
+ * Visit every node in an AST
+ * @param {Object} root - AST root
+ * @param {Function} visit - Called with each node
+ */
+function walk(root, visit) {
+  const stack = [root];
+  while (stack.length > 0) {
+    const node = stack.pop();
+    if (!node || typeof node.type !== 'string') continue;
+    visit(node);
+    for (const key of Object.keys(node)) {
+      if (SKIP_KEYS.has(key)) continue;
+      const value = node[key];
+      if (Array.isArray(value)) {
+        for (let i = value.length - 1; i >= 0; i--) {
+          if (value[i] && typeof value[i] === 'object') stack.push(value[i]);
+        }
+      } else if (value && typeof value === 'object') {
+        stack.push(value);
+      }
+    }
+  }
+}
+
+/**
+ * Run AST matchers for the given patterns against one file
+ * @param {string} content - File content
+ * @param {string} file - File path
+ * @param {Object<string, Object>} patterns - Slop patterns by name; only those with `ast` are checked
+ * @param {Object} [options] - See `loadParser`
+ * @returns {Map<string, number[]>|null} Sorted 1-based match lines per pattern name, or null when
+ *   the parser is unavailable or the file cannot be parsed (callers fall back to regex)
+ */
+function findAstMatches(content, file, patterns, options = {}) {
+  const checks = Object.entries(patterns)
+    .filter(([, pattern]) => pattern.ast && MATCHERS[pattern.ast.matcher]);
+  if (checks.length === 0) return null;
+
+  const parser = loadParser(options);
+  if (!parser) return null;
+  const ast = parseSource(parser, content, file);
+  if (!ast) return null;
+
+  const matches = new Map(checks.map(([name]) => [name, new Set()]));
+  walk(ast.program || ast, node => {
+    if (!node.loc) return;
+    for (const [name, pattern] of checks) {
+      if (MATCHERS[pattern.ast.matcher](node, pattern.ast)) matches.get(name).add(node.loc.start.line);
+    }
+  });
+
+  return new Map(Array.from(matches, ([name, lines]) => [name, Array.from(lines).sort((a, b) => a - b)]));
+}
+
+module.exports = {
+  PARSER_MODULE,
+  MATCHERS,
+  loadParser,
+  isAvailable,
+  findAstMatches
+};
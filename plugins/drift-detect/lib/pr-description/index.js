@@ -0,0 +1,384 @@
+#!/usr/bin/env node
+/**
+ * PR Title and Description from Diff Analysis
+ *
+ * Combines the changed-file list, the repo-map symbol diff, and the commits
+ * on the branch into a title and a description with summary, changes, test
+ * plan, and risk sections. When the repository has a PR template, its
+ * headings are kept and filled in: a heading about the summary, the changes,
+ * testing, or risk gets that section, and other headings keep the template
+ * text for the author.
+ *
+ * Usage: node lib/pr-description/index.js [--base <ref>]
+ * Output: JSON with title, body, and the template used
+ *
+ * @module lib/pr-description
+ */
+
+const fs = require('fs');
+const path = require('path');
+
+const changelog = require('../changelog');
+const { isMigrationFile } = require('../patterns/migrations');
+const { findBreakingChanges } = require('../patterns/api-design');
+const { git } = require('../utils/exec');
+
+/**
+ * PR and merge request template locations, in GitHub's lookup order
+ * Directory entries use the first Markdown file in them.
+ */
+const TEMPLATE_PATHS = [
+  '.github/pull_request_template.md',
+  '.github/PULL_REQUEST_TEMPLATE.md',
+  'pull_request_template.md',
+  'PULL_REQUEST_TEMPLATE.md',
+  'docs/pull_request_template.md',
+  'docs/PULL_REQUEST_TEMPLATE.md',
+  { dir: '.github/PULL_REQUEST_TEMPLATE' },
+  { dir: '.gitlab/merge_request_templates' }
+];
+
+/**
+ * File groups for the changes section, first match wins
+ */
+const FILE_GROUPS = [
+  { name: 'Migrations', test: isMigrationFile },
+  { name: 'Tests', test: file => /(?:^|\/)(?:__tests__|tests?|spec)\/|[._-](?:test|spec)\.[^/]+$|(?:^|\/)test_[^/]+\.py$|_test\.go$/.test(file) },
+  { name: 'CI', test: file => /^(?:\.github\/workflows|\.gitlab-ci|\.circleci|\.buildkite)|^Jenkinsfile$|^azure-pipelines\./.test(file) },
+  { name: 'Docs', test: file => /\.(?:md|mdx|rst|adoc|txt)$/i.test(file) || /^docs?\//.test(file) },
+  { name: 'Dependencies', test: file => /(?:^|\/)(?:package(?:-lock)?\.json|yarn\.lock|pnpm-lock\.yaml|Cargo\.(?:toml|lock)|go\.(?:mod|sum)|requirements[^/]*\.txt|poetry\.lock|uv\.lock|Pipfile(?:\.lock)?|Gemfile(?:\.lock)?|pyproject\.toml)$/.test(file) },
+  { name: 'Config', test: file => /\.(?:ya?ml|toml|ini|env(?:\.\w+)?|json)$/.test(file) || /(?:^|\/)(?:Dockerfile|docker-compose[^/]*|\.env[^/]*)$/.test(file) },
+  { name: 'Source', test: () => true }
+];
+
+/**
+ * Template headings and the generated section they receive
+ */
+const SECTION_HEADINGS = [
+  { section: 'summary', pattern: /summary|description|what|overview|why|motivation|context/i },
+  { section: 'changes', pattern: /changes?|implementation|details/i },
+  { section: 'testPlan', pattern: /test|verif|qa|how.*(?:check|review)/i },
+  { section: 'risk', pattern: /risk|impact|rollback|breaking/i }
+];
+
+const STATUS_NAMES = { A: 'added', M: 'modified', D: 'deleted', R: 'renamed', C: 'copied', T: 'modified' };
+
+/**
+ * Find the repository's PR template
+ * @param {string} basePath - Repository root
+ * @returns {{file: string, content: string}|null}
+ */
+function findPrTemplate(basePath) {
+  for (const entry of TEMPLATE_PATHS) {
+    if (typeof entry === 'string') {
+      try {
+        return { file: entry, content: fs.readFileSync(path.join(basePath, entry), 'utf8') };
+      } catch {
+        continue;
+      }
+    }
+    let names;
+    try {
+      names = fs.readdirSync(path.join(basePath, entry.dir)).filter(name => /\.md$/i.test(name)).sort();
+    } catch {
+      continue;
+    }
+    // GitLab's default template is named Default.md
+    const name = names.find(candidate => /^default\.md$/i.test(candidate)) || names[0];
+    if (name) {
+      const file = `${entry.dir}/${name}`;
+      return { file, content: fs.readFileSync(path.join(basePath, file), 'utf8') };
+    }
+  }
+  return null;
+}
+
+/**
+ * Split a template into headed sections
+ * @param {string} content - Template Markdown
+ * @returns {{preamble: string, sections: Array<{heading: string, level: number, body: string, section: string|null}>}}
+ */
+function parseTemplate(content) {
+  const lines = String(content || '').replace(/\r\n/g, '\n').split('\n');
+  const sections = [];
+  const preamble = [];
+  for (const line of lines) {
+    const heading = line.match(/^(#{1,6})\s+(.+?)\s*#*\s*$/);
+    if (heading) {
+      const mapped = SECTION_HEADINGS.find(candidate => candidate.pattern.test(heading[2]));
+      sections.push({ heading: heading[2], level: heading[1].length, body: '', section: mapped ? mapped.section : null });
+    } else if (sections.length > 0) {
+      sections[sections.length - 1].body += `${line}\n`;
+    } else {
+      preamble.push(line);
+    }
+  }
+  // Each generated section fills only the first heading that asks for it
+  const used = new Set();
+  for (const section of sections) {
+    if (!section.section) continue;
+    if (used.has(section.section)) section.section = null;
+    else used.add(section.section);
+  }
+  return { preamble: preamble.join('\n').trim(), sections };
+}
+
+/**
+ * Changed files between the merge base and HEAD
+ * @param {string} basePath - Repository root
+ * @param {string} base - Base ref
+ * @returns {Array<{file: string, status: string, from?: string}>|null}
+ */
+function getChangedFiles(basePath, base) {
+  if (!base || base.startsWith('-')) return null;
+  const out = git(basePath, ['diff', '--name-status', '-M', `${base}...HEAD`]);
+  if (out === null) return null;
+  return out.split('\n').filter(Boolean).map(line => {
+    const [status, first, second] = line.split('\t');
+    const kind = STATUS_NAMES[status[0]] || 'modified';
+    return second ? { file: second, status: kind, from: first } : { file: first, status: kind };
+  });
+}
+
+/**
+ * Group changed files for the changes section
+ * @param {Array<{file: string, status: string}>} files - Changed files
+ * @returns {Array<{name: string, files: Array<{file: string, status: string}>}>}
+ */
+function groupFiles(files) {
+  const groups = FILE_GROUPS.map(group => ({ name: group.name, files: [] }));
+  for (const entry of files) {
+    const index = FILE_GROUPS.findIndex(group => group.test(entry.file));
+    groups[index].files.push(entry);
+  }
+  return groups.filter(group => group.files.length > 0);
+}
+
+/**
+ * Risk factors for a change
+ * @param {Object} analysis
+ * @param {Array<{file: string, status: string}>} analysis.files - Changed files
+ * @param {Object[]} [analysis.breaking] - Result of findBreakingChanges
+ * @param {Object[]} [analysis.commits] - Parsed commits
+ * @returns {{level: string, factors: string[]}} level is `low`, `medium`, or `high`
+ */
+function assessRisk({ files, breaking = [], commits = [] }) {
+  const factors = [];
+  let score = 0;
+  const groups = groupFiles(files);
+  const count = name => (groups.find(group => group.name === name) || { files: [] }).files.length;
+
+  if (breaking.length > 0 || commits.some(commit => commit.breaking)) {
+    factors.push(`Breaking changes to public API (${breaking.map(change => `${change.name}: ${change.change}`).join(', ') || 'marked in commits'})`);
+    score += 3;
+  }
+  if (count('Migrations') > 0) {
+    factors.push(`${count('Migrations')} database migration(s); check locking and rollback`);
+    score += 2;
+  }
+  if (count('CI') > 0 || count('Config') > 0) {
+    factors.push('CI or configuration changes affect every environment');
+    score += 1;
+  }
+  if (count('Dependencies') > 0) {
+    factors.push('Dependency changes');
+    score += 1;
+  }
+  const deleted = files.filter(entry => entry.status === 'deleted').length;
+  if (deleted > 0) factors.push(`${deleted} file(s) deleted`);
+  if (count('Source') > 0 && count('Tests') === 0) {
+    factors.push('Source changes without test changes');
+    score += 1;
+  }
+  if (files.length > 40) {
+    factors.push(`Large change (${files.length} files); consider splitting`);
+    score += 1;
+  }
+
+  return { level: score >= 3 ? 'high' : score >= 1 ? 'medium' : 'low', factors };
+}
+
+/**
+ * PR title from the branch's commits
+ * One commit keeps its subject; several use the most significant
+ * conventional type and the shared scope, if any.
+ * @param {Object[]} commits - Parsed commits, oldest first
+ * @param {string} [branch] - Branch name, used when commits say nothing
+ * @returns {string}
+ */
+function suggestTitle(commits, branch) {
+  const meaningful = commits.filter(commit => !/^Merge /.test(commit.description));
+  if (meaningful.length === 1) {
+    const [commit] = meaningful;
+    return commit.type ? `${commit.type}${commit.scope ? `(${commit.scope})` : ''}${commit.breaking ? '!' : ''}: ${commit.description}` : commit.description;
+  }
+  const order = ['feat', 'fix', 'perf', 'refactor', 'docs', 'test', 'chore'];
+  const typed = meaningful.filter(commit => commit.type);
+  const type = order.find(candidate => typed.some(commit => commit.type === candidate)) || (typed[0] && typed[0].type);
+  const lead = typed.find(commit => commit.type === type) || meaningful[0];
+  if (!lead) {
+    return String(branch || 'Update').replace(/^(?:feature|feat|fix|bugfix|chore)[/-]/, '').replace(/[-_/]+/g, ' ').trim();
+  }
+  const scopes = new Set(typed.map(commit => commit.scope).filter(Boolean));
+  const scope = scopes.size === 1 ? Array.from(scopes)[0] : null;
+  const breaking = meaningful.some(commit => commit.breaking);
+  return type ? `${type}${scope ? `(${scope})` : ''}${breaking ? '!' : ''}: ${lead.description}` : lead.description;
+}
+
+/**
+ * Generated sections as Markdown
+ * @param {Object} analysis - See buildDescription
+ * @returns {{summary: string, changes: string, testPlan: string, risk: string}}
+ */
+function renderSections({ files, commits = [], symbols = null, breaking = [], testCommand = null }) {
+  const typed = commits.filter(commit => !/^Merge /.test(commit.description));
+  const summary = typed.length > 0
+    ? typed.map(commit => `- ${commit.scope ? `**${commit.scope}:** ` : ''}${commit.description}`).join('\n')
+    : '- _Describe what this change does and why._';
+
+  const changes = [];
+  for (const group of groupFiles(files)) {
+    changes.push(`**${group.name}**`);
+    group.files.forEach(entry => changes.push(`- \`${entry.file}\`${entry.status === 'modified' ? '' : ` (${entry.status}${entry.from ? ` from \`${entry.from}\`` : ''})`}`));
+    changes.push('');
+  }
+  if (symbols) {
+    const line = (label, items, name = item => item.name) => {
+      if (items && items.length > 0) changes.push(`- ${label}: ${items.map(item => `\`${name(item)}\``).join(', ')}`);
+    };
+    const before = changes.length;
+    line('Added', symbols.added);
+    line('Removed', symbols.removed);
+    line('Renamed', symbols.renamed, item => `${item.from.name} → ${item.name}`);
+    line('Changed', symbols.changed);
+    if (changes.length > before) changes.splice(before, 0, '**Symbols**');
+  }
+
+  const tests = files.filter(entry => FILE_GROUPS[1].test(entry.file) && entry.status !== 'deleted');
+  const testPlan = [
+    testCommand ? `- [ ] \`${testCommand}\` passes` : '- [ ] Test suite passes',
+    ...tests.map(entry => `- [ ] \`${entry.file}\` covers the change`),
+    ...(tests.length === 0 ? ['- [ ] _Add the manual or automated checks for this change_'] : [])
+  ].join('\n');
+
+  const { level, factors } = assessRisk({ files, breaking, commits });
+  const risk = [`**${level[0].toUpperCase()}${level.slice(1)}**`, ...factors.map(factor => `- ${factor}`)].join('\n');
+
+  return { summary, changes: changes.join('\n').trim() || '- _No file changes_', testPlan, risk };
+}
+
+/**
+ * Build a PR title and body
+ * @param {Object} analysis
+ * @param {Array<{file: string, status: string}>} analysis.files - Changed files
+ * @param {Object[]} [analysis.commits] - Parsed commits (changelog.parseCommit)
+ * @param {Object} [analysis.diff] - repo-map diff result; symbols and breaking changes come from it
+ * @param {string} [analysis.testCommand] - Detected test command
+ * @param {string} [analysis.branch] - Branch name
+ * @param {{file: string, content: string}|null} [analysis.template] - Result of findPrTemplate
+ * @returns {{title: string, body: string, template: string|null, risk: string}}
+ */
+function buildDescription(analysis) {
+  const symbols = analysis.diff && analysis.diff.symbols ? analysis.diff.symbols : null;
+  const breaking = findBreakingChanges(analysis.diff);
+  const sections = renderSections({ ...analysis, symbols, breaking });
+  const title = suggestTitle(analysis.commits || [], analysis.branch);
+  const { level } = assessRisk({ files: analysis.files, breaking, commits: analysis.commits });
+
+  let body;
+  if (analysis.template) {
+    const { preamble, sections: headed } = parseTemplate(analysis.template.content);
+    const filled = new Set(headed.map(section => section.section).filter(Boolean));
+    const parts = preamble ? [preamble.replace(/<!--[\s\S]*?-->/g, '').trim()].filter(Boolean) : [];
+    for (const section of headed) {
+      const text = section.section ? sections[section.section] : section.body.trim();
+      parts.push(`${'#'.repeat(section.level)} ${section.heading}\n\n${text}`);
+    }
+    // Sections the template has no heading for go at the end
+    for (const [name, heading] of [['summary', 'Summary'], ['changes', 'Changes'], ['testPlan', 'Test plan'], ['risk', 'Risk']]) {
+      if (!filled.has(name)) parts.push(`## ${heading}\n\n${sections[name]}`);
+    }
+    body = parts.join('\n\n');
+  } else {
+    body = [
+      `## Summary\n\n${sections.summary}`,
+      `## Changes\n\n${sections.changes}`,
+      `## Test plan\n\n${sections.testPlan}`,
+      `## Risk\n\n${sections.risk}`
+    ].join('\n\n');
+  }
+
+  return { title, body: `${body.trim()}\n`, template: analysis.template ? analysis.template.file : null, risk: level };
+}
+
+/**
+ * Token environment variables each host's CLI reads
+ */
+const TOKEN_VARIABLES = {
+  github: ['GH_TOKEN', 'GITHUB_TOKEN'],
+  gitlab: ['GITLAB_TOKEN', 'GLAB_TOKEN']
+};
+
+/**
+ * Check whether a host token is set in the environment
+ * A logged-in CLI (`gh auth status`) also works; the command checks that.
+ * @param {string|null} host - Git host from changelog.getRepository
+ * @param {Object} [env=process.env] - Environment
+ * @returns {boolean}
+ */
+function hasToken(host, env = process.env) {
+  return (TOKEN_VARIABLES[host] || []).some(name => Boolean(env[name]));
+}
+
+/**
+ * Command that writes the title and body to the branch's open PR
+ * @param {string|null} host - Git host from changelog.getRepository
+ * @param {string} title - PR title
+ * @param {Object} body
+ * @param {string} body.file - File holding the body (gh)
+ * @param {string} body.text - Body text (glab has no file option)
+ * @returns {string[]|null} argv, or null when the host has no supported CLI
+ */
+function updateCommand(host, title, body) {
+  if (host === 'github') return ['gh', 'pr', 'edit', '--title', title, '--body-file', body.file];
+  if (host === 'gitlab') return ['glab', 'mr', 'update', '--title', title, '--description', body.text];
+  return null;
+}
+
+// When run directly, output JSON
+if (require.main === module) {
+  const args = process.argv.slice(2);
+  const basePath = process.cwd();
+  const base = args.includes('--base') ? args[args.indexOf('--base') + 1] : 'origin/HEAD';
+  const files = getChangedFiles(basePath, base);
+  if (!files) {
+    console.log(JSON.stringify({ success: false, error: `Could not diff against ${base}` }));
+    process.exitCode = 1;
+  } else {
+    const mergeBase = (git(basePath, ['merge-base', base, 'HEAD']) || '').trim();
+    const range = changelog.getCommits(basePath, { from: mergeBase || base });
+    const diff = require('../repo-map').diff(basePath, { base: mergeBase || base });
+    const result = buildDescription({
+      files,
+      commits: range ? range.commits.map(commit => changelog.parseCommit(commit)) : [],
+      diff: diff.success ? diff : null,
+      branch: (git(basePath, ['rev-parse', '--abbrev-ref', 'HEAD']) || '').trim(),
+      template: findPrTemplate(basePath)
+    });
+    const indent = process.stdout.isTTY ? 2 : 0;
+    console.log(JSON.stringify({ success: true, ...result }, null, indent));
+  }
+}
+
+module.exports = {
+  TEMPLATE_PATHS,
+  findPrTemplate,
+  parseTemplate,
+  getChangedFiles,
+  groupFiles,
+  assessRisk,
+  suggestTitle,
+  buildDescription,
+  hasToken,
+  updateCommand
+};
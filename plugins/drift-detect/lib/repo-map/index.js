@@ -9,10 +9,27 @@
 
 'use strict';
 
+const fs = require('fs');
+const path = require('path');
+
 const installer = require('./installer');
 const runner = require('./runner');
 const cache = require('./cache');
 const updater = require('./updater');
+const rank = require('./rank');
+const renderer = require('./render');
+const formats = require('./formats');
+const graph = require('./graph');
+const watcher = require('./watcher');
+const treesitter = require('./treesitter');
+const symbolDiff = require('./diff');
+const dead = require('./dead');
+const testgen = require('./testgen');
+const coverageReport = require('./coverage');
+const query = require('./query');
+const taskRunner = require('../task-runner');
+const git = require('../utils/git');
+const { detectTestFrameworks } = require('../platform/detect-tests');
 
 /**
  * Initialize a new repo map (full scan)
@@ -20,6 +37,9 @@ const updater = require('./updater');
  * @param {Object} options - Options
  * @param {boolean} options.force - Force rebuild even if map exists
  * @param {string[]} options.languages - Languages to scan (auto-detect if not specified)
+ * @param {boolean} options.noCache - Re-extract every file instead of reusing cached symbols
+ * @param {number} options.concurrency - Maximum extraction worker threads (default: CPU count, capped at 8)
+ * @param {boolean} options.submodules - Scan checked-out submodules too (default: `git.submodules` from the project config)
  * @returns {Promise<{success: boolean, map?: Object, error?: string}>}
  */
 async function init(basePath, options = {}) {
@@ -64,12 +84,16 @@ async function init(basePath, options = {}) {
   const startTime = Date.now();
   const map = await runner.fullScan(basePath, languages, {
     includeDocs: options.includeDocs,
-    docsDepth: options.docsDepth
+    docsDepth: options.docsDepth,
+    fileCache: options.noCache ? null : cache.loadFileCache(basePath),
+    concurrency: options.concurrency,
+    submodules: options.submodules !== undefined ? options.submodules : git.readSettings(basePath).submodules
   });
   map.stats.scanDurationMs = Date.now() - startTime;
 
   // Save map
   cache.save(basePath, map);
+  cache.saveFileCache(basePath, map, runner.getExtractorFingerprints(map));
 
   return {
     success: true,
@@ -77,7 +101,10 @@ async function init(basePath, options = {}) {
     summary: {
       files: Object.keys(map.files).length,
       symbols: map.stats.totalSymbols,
+      cached: map.stats.cachedFiles,
       languages: map.project.languages,
+      submodules: (map.submodules || []).map(sub => sub.path),
+      skipped: map.stats.skipped,
       duration: map.stats.scanDurationMs
     }
   };
@@ -88,6 +115,8 @@ async function init(basePath, options = {}) {
  * @param {string} basePath - Repository root path
  * @param {Object} options - Options
  * @param {boolean} options.full - Force full rebuild instead of incremental
+ * @param {boolean} options.noCache - With `full`, re-extract every file instead of reusing cached symbols
+ * @param {number} options.concurrency - With `full`, maximum extraction worker threads
  * @returns {Promise<{success: boolean, changes?: Object, error?: string}>}
  */
 async function update(basePath, options = {}) {
@@ -120,7 +149,13 @@ async function update(basePath, options = {}) {
 
   // Force full rebuild if requested
   if (options.full) {
-    return init(basePath, { force: true });
+    return init(basePath, {
+      force: true,
+      noCache: options.noCache,
+      concurrency: options.concurrency,
+      // Keep scanning submodules if the map was built with them
+      submodules: existing.submodules ? true : undefined
+    });
   }
 
   // Incremental update
@@ -128,11 +163,52 @@ async function update(basePath, options = {}) {
   
   if (result.success) {
     cache.save(basePath, result.map);
+    cache.saveFileCache(basePath, result.map, runner.getExtractorFingerprints(result.map));
   }
 
   return result;
 }
 
+/**
+ * Start watch mode: keep the cached map fresh as files change
+ * @param {string} basePath - Repository root path
+ * @param {Object} options - Options
+ * @param {number} options.debounceMs - Quiet period before re-scanning
+ * @param {Function} options.onUpdate - Called after each saved batch of changes
+ * @param {Function} options.onError - Called on watcher or scan errors
+ * @returns {Promise<{success: boolean, watcher?: Object, error?: string}>}
+ */
+async function watch(basePath, options = {}) {
+  const installed = await installer.checkInstalled();
+  if (!installed.found) {
+    return {
+      success: false,
+      error: 'ast-grep not found',
+      installSuggestion: installer.getInstallInstructions()
+    };
+  }
+
+  if (!cache.exists(basePath)) {
+    return {
+      success: false,
+      error: 'No repo map found. Run /repo-map init first.'
+    };
+  }
+
+  const active = watcher.getActiveWatcher(basePath);
+  if (active && active.pid !== process.pid) {
+    return {
+      success: false,
+      error: `Repo map watcher already running (pid ${active.pid})`
+    };
+  }
+
+  return {
+    success: true,
+    watcher: watcher.watch(basePath, options)
+  };
+}
+
 /**
  * Get repo map status
  * @param {string} basePath - Repository root path
@@ -156,11 +232,281 @@ function status(basePath) {
       files: Object.keys(map.files).length,
       symbols: map.stats?.totalSymbols || 0,
       languages: map.project?.languages || [],
+      watching: Boolean(watcher.getActiveWatcher(basePath)),
       staleness
     }
   };
 }
 
+/**
+ * Render the cached repo map as text (within a token budget) or as JSON, Mermaid, or DOT
+ * @param {string} basePath - Repository root path
+ * @param {Object} options - Options
+ * @param {string} options.format - `text` (default), `json`, `mermaid`, or `dot`
+ * @param {number} options.maxTokens - Token budget for `text` (unbounded if omitted)
+ * @param {Function} options.tokenizer - Custom `(text) => tokenCount`
+ * @param {number} options.maxFiles - Keep only the highest-ranked files in graph formats
+ * @param {string[]} options.focus - Files to rank first
+ * @returns {{success: boolean, format?: string, text?: string, tokens?: number, error?: string}}
+ */
+function render(basePath, options = {}) {
+  const map = cache.load(basePath);
+  if (!map) {
+    return {
+      success: false,
+      error: 'No repo map found. Run /repo-map init first.'
+    };
+  }
+
+  if (options.format && !formats.FORMATS.includes(String(options.format).toLowerCase())) {
+    return {
+      success: false,
+      error: `Unknown format: ${options.format}. Use ${formats.FORMATS.join(', ')}.`
+    };
+  }
+
+  return {
+    success: true,
+    ...formats.formatMap(map, options.format, options)
+  };
+}
+
+/**
+ * Report dependency cycles, and cycles introduced by edits to `files`
+ *
+ * The cached map is the baseline; the listed files are re-scanned from the
+ * working tree into a copy, and any cycle closed by an import edge that the
+ * baseline lacks is reported in `newCycles`.
+ *
+ * @param {string} basePath - Repository root path
+ * @param {Object} options - Options
+ * @param {string[]} options.files - Changed files (relative paths) to re-scan
+ * @returns {{success: boolean, cycles?: string[][], newCycles?: Array<{cycle: string[], edge: Object}>, error?: string}}
+ */
+function checkCycles(basePath, options = {}) {
+  const map = cache.load(basePath);
+  if (!map) {
+    return {
+      success: false,
+      error: 'No repo map found. Run /repo-map init first.'
+    };
+  }
+
+  const before = graph.buildDependencyGraph(map);
+  let after = before;
+  const files = options.files || [];
+  if (files.length > 0) {
+    const cmd = installer.getCommand();
+    if (!cmd) {
+      return {
+        success: false,
+        error: 'ast-grep not found',
+        installSuggestion: installer.getInstallInstructions()
+      };
+    }
+    const working = JSON.parse(JSON.stringify(map));
+    updater.updateFiles(basePath, working, cmd, files);
+    after = graph.buildDependencyGraph(working);
+  }
+
+  return {
+    success: true,
+    cycles: graph.findCycles(after),
+    newCycles: graph.findNewCycles(before, after)
+  };
+}
+
+/**
+ * Diff symbols between two git refs, or HEAD and the index
+ * @param {string} basePath - Repository root path
+ * @param {Object} options - Options
+ * @param {string} options.base - Base ref
+ * @param {string} [options.head='HEAD'] - Head ref
+ * @param {boolean} [options.staged] - Diff staged changes instead of refs
+ * @returns {{success: boolean, base?: string, head?: string, files?: Object, symbols?: Object, text?: string, error?: string}}
+ */
+function diff(basePath, options = {}) {
+  if (!options.base && !options.staged) {
+    return { success: false, error: 'Usage: /repo-map diff <base> [head] | --staged' };
+  }
+
+  const cmd = installer.getCommand();
+  if (!cmd) {
+    return {
+      success: false,
+      error: 'ast-grep not found',
+      installSuggestion: installer.getInstallInstructions()
+    };
+  }
+
+  const result = options.staged
+    ? symbolDiff.diffStaged(basePath, { cmd })
+    : symbolDiff.diffRefs(basePath, options.base, options.head || 'HEAD', { cmd });
+  return result.success ? { ...result, text: symbolDiff.renderDiff(result) } : result;
+}
+
+/**
+ * Scaffold a test file for a file or symbol
+ * @param {string} basePath - Repository root path
+ * @param {Object} options - Options
+ * @param {string} options.target - File path, symbol name, or `file:symbol`
+ * @param {string} [options.framework] - Test framework (detected if omitted)
+ * @returns {Promise<{success: boolean, file?: string, testFile?: string, framework?: string, symbols?: string[], content?: string, exists?: boolean, error?: string}>}
+ */
+async function scaffoldTests(basePath, options = {}) {
+  const map = cache.load(basePath);
+  if (!map) {
+    return {
+      success: false,
+      error: 'No repo map found. Run /repo-map init first.'
+    };
+  }
+  if (!options.target) {
+    return { success: false, error: 'Usage: /test-gen <file|symbol|file:symbol>' };
+  }
+
+  let frameworks = [];
+  if (!options.framework) {
+    const detected = await detectTestFrameworks(basePath);
+    frameworks = detected ? detected.frameworks : [];
+  }
+
+  const result = testgen.scaffoldTests(map, options.target, {
+    framework: options.framework,
+    frameworks,
+    readFile: file => fs.readFileSync(path.join(basePath, file), 'utf8')
+  });
+  return result.success ? { ...result, exists: fs.existsSync(path.join(basePath, result.testFile)) } : result;
+}
+
+/**
+ * Summarize a coverage report against the repo map
+ * @param {string} basePath - Repository root path
+ * @param {Object} options - Options
+ * @param {string} [options.report] - Report path (probed from common locations if omitted)
+ * @param {string} [options.format] - Report format (detected if omitted)
+ * @returns {{success: boolean, report?: string, format?: string, totals?: Object, files?: Object[], functions?: Object[], unmatched?: string[], error?: string}}
+ */
+function coverage(basePath, options = {}) {
+  const map = cache.load(basePath);
+  if (!map) {
+    return {
+      success: false,
+      error: 'No repo map found. Run /repo-map init first.'
+    };
+  }
+
+  const report = options.report || coverageReport.REPORT_PATHS.find(file => fs.existsSync(path.join(basePath, file)));
+  if (!report) {
+    return { success: false, error: `No coverage report found (looked for ${coverageReport.REPORT_PATHS.join(', ')})` };
+  }
+
+  let content;
+  try {
+    content = fs.readFileSync(path.resolve(basePath, report), 'utf8');
+  } catch (err) {
+    return { success: false, error: `Cannot read ${report}: ${err.message}` };
+  }
+
+  const parsed = coverageReport.parseReport(content, options.format);
+  if (!parsed.format) {
+    return { success: false, error: `Unrecognized coverage format in ${report}; expected lcov, Cobertura XML, Go coverprofile, or coverage.py JSON` };
+  }
+
+  const { files, unmatched } = coverageReport.resolvePaths(parsed.files, Object.keys(map.files || {}), basePath);
+  return { success: true, report, format: parsed.format, ...coverageReport.summarize(map, files), unmatched };
+}
+
+/**
+ * Summarize coverage across workspace packages
+ * Each package's report (the first of REPORT_PATHS inside the package) is
+ * mapped onto the repo map, and all of them merge into one summary with a
+ * row per package. With `run`, each package's suite runs with coverage
+ * first, in parallel, after the suites of the workspace packages it
+ * depends on.
+ * @param {string} basePath - Repository root path
+ * @param {Array<{name: string, path: string}>} packages - Workspace packages (`detection.monorepo.packages`)
+ * @param {Object} [options]
+ * @param {boolean} [options.run] - Run each package's tests with coverage first
+ * @param {number} [options.concurrency] - Tasks at once (default: CPU count, capped at 8)
+ * @param {Function} [options.onProgress] - Progress handler, e.g. `taskRunner.createProgress(process.stderr)`
+ * @param {Function} [options.runCommand] - `(cwd, argv, options) => Promise<stdout|null>` (tests)
+ * @returns {Promise<Object>} coverage() fields plus `packages: [{name, path, report, format, totals, error}]` and `run`
+ */
+async function coverageWorkspaces(basePath, packages, options = {}) {
+  const map = cache.load(basePath);
+  if (!map) {
+    return { success: false, error: 'No repo map found. Run /repo-map init first.' };
+  }
+
+  const mapFiles = Object.keys(map.files || {});
+  const edges = taskRunner.workspaceDependencies(basePath, packages);
+  const exec = options.runCommand || taskRunner.runCommand;
+  const tasks = [];
+  for (const pkg of packages) {
+    const dir = path.join(basePath, pkg.path);
+    if (options.run) {
+      tasks.push({
+        id: `test:${pkg.path}`,
+        label: `${pkg.path} tests`,
+        deps: edges[pkg.path].map(dep => `test:${dep}`),
+        run: async () => {
+          const argv = coverageReport.coverageCommand(await detectTestFrameworks(dir));
+          if (!argv) return null;
+          // Failing tests still write a report; only a command that never ran fails the task
+          if (await exec(dir, argv, { timeout: 10 * 60 * 1000 }) === null) throw new Error(`${argv.join(' ')} did not run`);
+          return argv.join(' ');
+        }
+      });
+    }
+    tasks.push({
+      id: `report:${pkg.path}`,
+      label: `${pkg.path} report`,
+      deps: options.run ? [`test:${pkg.path}`] : [],
+      run: () => {
+        const report = coverageReport.REPORT_PATHS.find(file => fs.existsSync(path.join(dir, file)));
+        if (!report) return null;
+        const file = path.posix.join(pkg.path, report);
+        const parsed = coverageReport.parseReport(fs.readFileSync(path.join(dir, report), 'utf8'));
+        if (!parsed.format) throw new Error(`Unrecognized coverage format in ${file}`);
+        return { report: file, format: parsed.format, ...coverageReport.resolvePaths(parsed.files, mapFiles, basePath, pkg.path) };
+      }
+    });
+  }
+
+  const run = await taskRunner.runTasks(tasks, { concurrency: options.concurrency, onProgress: options.onProgress });
+  if (run.error) return { success: false, error: run.error };
+
+  const merged = {};
+  const unmatched = [];
+  const formats = new Set();
+  const rows = packages.map(pkg => {
+    const outcome = run.tasks.find(task => task.id === `report:${pkg.path}`);
+    if (outcome.status !== 'done') return { name: pkg.name, path: pkg.path, report: null, error: outcome.error };
+    if (!outcome.value) return { name: pkg.name, path: pkg.path, report: null };
+    coverageReport.mergeFiles(merged, outcome.value.files);
+    unmatched.push(...outcome.value.unmatched);
+    formats.add(outcome.value.format);
+    const { totals } = coverageReport.summarize(map, outcome.value.files);
+    return { name: pkg.name, path: pkg.path, report: outcome.value.report, format: outcome.value.format, totals };
+  });
+  const runInfo = { concurrency: run.concurrency, durationMs: run.durationMs, summary: taskRunner.renderSummary(run) };
+
+  const found = rows.filter(row => row.report).length;
+  if (found === 0) {
+    return { success: false, error: `No coverage report found in ${packages.length} workspace packages (looked for ${coverageReport.REPORT_PATHS.join(', ')})`, packages: rows, run: runInfo };
+  }
+  return {
+    success: true,
+    report: `${found} package reports`,
+    format: Array.from(formats).join(', '),
+    ...coverageReport.summarize(map, merged),
+    unmatched,
+    packages: rows,
+    run: runInfo
+  };
+}
+
 /**
  * Load repo map (if exists)
  * @param {string} basePath - Repository root path
@@ -199,6 +545,13 @@ module.exports = {
   init,
   update,
   status,
+  render,
+  checkCycles,
+  diff,
+  scaffoldTests,
+  coverage,
+  coverageWorkspaces,
+  watch,
   load,
   exists,
   checkAstGrepInstalled,
@@ -208,5 +561,16 @@ module.exports = {
   installer,
   runner,
   cache,
-  updater
+  updater,
+  rank,
+  renderer,
+  formats,
+  graph,
+  watcher,
+  treesitter,
+  symbolDiff,
+  dead,
+  testgen,
+  coverageReport,
+  query
 };
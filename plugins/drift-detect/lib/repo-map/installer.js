@@ -6,12 +6,9 @@
 
 'use strict';
 
-const { execSync, exec } = require('child_process');
 const fs = require('fs');
 const path = require('path');
-const { promisify } = require('util');
-
-const execAsync = promisify(exec);
+const exec = require('../utils/exec');
 
 // Commands to try (sg is the common alias)
 const AST_GREP_COMMANDS = ['sg', 'ast-grep'];
@@ -44,38 +41,19 @@ function pickCommandPath(pathOutput) {
  */
 async function checkInstalled() {
   for (const cmd of AST_GREP_COMMANDS) {
-    try {
-      // Try to get version
-      const { stdout } = await execAsync(`${cmd} --version`, {
-        timeout: 5000,
-        windowsHide: true
-      });
-      
-      const version = stdout.trim().replace(/^ast-grep\s*/i, '');
-      
-      // Try to get path
-      let cmdPath = null;
-      try {
-        const whereCmd = process.platform === 'win32' ? 'where' : 'which';
-        const { stdout: pathOut } = await execAsync(`${whereCmd} ${cmd}`, {
-          timeout: 5000,
-          windowsHide: true
-        });
-        cmdPath = pickCommandPath(pathOut);
-      } catch {
-        // Path lookup failed, but command works
-      }
-
-      return {
-        found: true,
-        version,
-        path: cmdPath,
-        command: cmdPath || cmd
-      };
-    } catch {
-      // This command not found, try next
-      continue;
-    }
+    const stdout = await exec.runAsync(process.cwd(), [cmd, '--version'], { timeout: 5000 });
+    // This command not found, try next
+    if (stdout === null) continue;
+
+    const version = stdout.trim().replace(/^ast-grep\s*/i, '');
+    const cmdPath = pickCommandPath(exec.findExecutable(cmd));
+
+    return {
+      found: true,
+      version,
+      path: cmdPath,
+      command: cmdPath || cmd
+    };
   }
   
   return { found: false };
@@ -87,34 +65,13 @@ async function checkInstalled() {
  */
 function checkInstalledSync() {
   for (const cmd of AST_GREP_COMMANDS) {
-    try {
-      const stdout = execSync(`${cmd} --version`, {
-        timeout: 5000,
-        windowsHide: true,
-        encoding: 'utf8',
-        stdio: ['pipe', 'pipe', 'pipe']
-      });
-      
-      const version = stdout.trim().replace(/^ast-grep\s*/i, '');
-      let cmdPath = null;
-
-      try {
-        const whereCmd = process.platform === 'win32' ? 'where' : 'which';
-        const pathOut = execSync(`${whereCmd} ${cmd}`, {
-          timeout: 5000,
-          windowsHide: true,
-          encoding: 'utf8',
-          stdio: ['pipe', 'pipe', 'pipe']
-        });
-        cmdPath = pickCommandPath(pathOut);
-      } catch {
-        // Path lookup failed, but command works
-      }
-
-      return { found: true, version, command: cmdPath || cmd, path: cmdPath };
-    } catch {
-      continue;
-    }
+    const stdout = exec.run(process.cwd(), [cmd, '--version'], { timeout: 5000 });
+    if (stdout === null) continue;
+
+    const version = stdout.trim().replace(/^ast-grep\s*/i, '');
+    const cmdPath = pickCommandPath(exec.findExecutable(cmd));
+
+    return { found: true, version, command: cmdPath || cmd, path: cmdPath };
   }
   
   return { found: false };
@@ -38,7 +38,13 @@ module.exports = {
     { pattern: 'pub(crate) mod $NAME { $$$ }', kind: 'module', nameVar: 'NAME' },
     { pattern: 'pub(super) mod $NAME { $$$ }', kind: 'module', nameVar: 'NAME' },
     { pattern: 'pub(in $PATH) mod $NAME { $$$ }', kind: 'module', nameVar: 'NAME' },
-    { pattern: 'pub mod $NAME;', kind: 'module', nameVar: 'NAME' }
+    { pattern: 'pub mod $NAME;', kind: 'module', nameVar: 'NAME' },
+    { pattern: 'pub struct $NAME;', kind: 'type', nameVar: 'NAME' },
+    { pattern: 'pub struct $NAME($$$);', kind: 'type', nameVar: 'NAME' },
+    { pattern: 'pub(crate) struct $NAME($$$);', kind: 'type', nameVar: 'NAME' },
+    { pattern: 'pub const fn $NAME($$$) { $$$ }', kind: 'function', nameVar: 'NAME' },
+    { pattern: 'pub unsafe fn $NAME($$$) { $$$ }', kind: 'function', nameVar: 'NAME' },
+    { pattern: 'pub async fn $NAME($$$) { $$$ }', kind: 'function', nameVar: 'NAME' }
   ],
   functions: [
     { pattern: 'fn $NAME($$$) { $$$ }', nameVar: 'NAME' },
@@ -48,9 +54,20 @@ module.exports = {
     { pattern: 'pub(super) fn $NAME($$$) { $$$ }', nameVar: 'NAME' },
     { pattern: 'pub(in $PATH) fn $NAME($$$) { $$$ }', nameVar: 'NAME' },
     { pattern: 'pub async fn $NAME($$$) { $$$ }', nameVar: 'NAME' },
-    { pattern: 'pub(crate) async fn $NAME($$$) { $$$ }', nameVar: 'NAME' }
+    { pattern: 'pub(crate) async fn $NAME($$$) { $$$ }', nameVar: 'NAME' },
+    { pattern: 'const fn $NAME($$$) { $$$ }', nameVar: 'NAME' },
+    { pattern: 'unsafe fn $NAME($$$) { $$$ }', nameVar: 'NAME' },
+    { pattern: 'pub const fn $NAME($$$) { $$$ }', nameVar: 'NAME' },
+    { pattern: 'pub unsafe fn $NAME($$$) { $$$ }', nameVar: 'NAME' },
+    { pattern: 'macro_rules! $NAME { $$$ }', kind: 'macro', nameVar: 'NAME' }
+  ],
+  classes: [
+    { pattern: 'impl $NAME { $$$ }', kind: 'impl', nameVar: 'NAME' },
+    { pattern: 'impl<$$$> $NAME { $$$ }', kind: 'impl', nameVar: 'NAME' },
+    { pattern: 'impl $TRAIT for $NAME { $$$ }', kind: 'trait-impl', nameTemplate: '$TRAIT for $NAME' },
+    { pattern: 'impl<$$$> $TRAIT for $NAME { $$$ }', kind: 'trait-impl', nameTemplate: '$TRAIT for $NAME' },
+    { pattern: 'unsafe impl $TRAIT for $NAME { $$$ }', kind: 'trait-impl', nameTemplate: '$TRAIT for $NAME' }
   ],
-  classes: [],
   types: [
     { pattern: 'struct $NAME { $$$ }', nameVar: 'NAME' },
     { pattern: 'enum $NAME { $$$ }', nameVar: 'NAME' },
@@ -59,7 +76,12 @@ module.exports = {
     { pattern: 'pub struct $NAME { $$$ }', nameVar: 'NAME' },
     { pattern: 'pub enum $NAME { $$$ }', nameVar: 'NAME' },
     { pattern: 'pub trait $NAME { $$$ }', nameVar: 'NAME' },
-    { pattern: 'pub type $NAME = $$$', nameVar: 'NAME' }
+    { pattern: 'pub type $NAME = $$$', nameVar: 'NAME' },
+    { pattern: 'struct $NAME;', nameVar: 'NAME' },
+    { pattern: 'struct $NAME($$$);', nameVar: 'NAME' },
+    { pattern: 'pub struct $NAME;', nameVar: 'NAME' },
+    { pattern: 'pub struct $NAME($$$);', nameVar: 'NAME' },
+    { pattern: 'union $NAME { $$$ }', nameVar: 'NAME' }
   ],
   constants: [
     { pattern: 'const $NAME: $TYPE = $$$', nameVar: 'NAME' },
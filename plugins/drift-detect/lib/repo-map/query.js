@@ -0,0 +1,108 @@
+/**
+ * Symbol lookup and caller queries over a repo map
+ *
+ * `findSymbols` searches definitions (functions, classes, types, constants,
+ * and class methods as `Class.method`) by name. `findCallers` reads the call
+ * graph, which only records calls that cross files, so callers inside the
+ * defining file are not listed.
+ *
+ * @module lib/repo-map/query
+ */
+
+'use strict';
+
+const CATEGORY_KINDS = { functions: 'function', classes: 'class', types: 'type', constants: 'constant' };
+const DEFAULT_LIMIT = 50;
+
+/**
+ * Definitions of a file, including class methods
+ * @param {string} file - File path
+ * @param {Object} fileData - Map entry
+ * @returns {Array<{name: string, qualifiedName: string, file: string, line: number|null, kind: string, category: string, exported: boolean}>}
+ */
+function fileDefinitions(file, fileData) {
+  const definitions = [];
+  for (const [category, defaultKind] of Object.entries(CATEGORY_KINDS)) {
+    for (const entry of fileData.symbols?.[category] || []) {
+      if (entry.declaredIn) continue;
+      const base = { file, kind: entry.kind || defaultKind, category, exported: entry.exported !== false };
+      definitions.push({ ...base, name: entry.name, qualifiedName: entry.name, line: entry.line || null });
+      for (const method of entry.methods || []) {
+        definitions.push({ ...base, kind: 'method', name: method.name, qualifiedName: `${entry.name}.${method.name}`, line: method.line || null });
+      }
+    }
+  }
+  return definitions;
+}
+
+/**
+ * Find symbol definitions by name
+ * Exact matches come first, then prefix matches, then other substring
+ * matches (case-insensitive unless `exact`).
+ * @param {Object} map - Repo map
+ * @param {string} query - Name, or `Class.method`
+ * @param {Object} [options]
+ * @param {boolean} [options.exact=false] - Only exact, case-sensitive matches
+ * @param {string} [options.kind] - Only this kind (`function`, `class`, `method`, ...)
+ * @param {number} [options.limit=50] - Maximum results
+ * @returns {{total: number, symbols: Object[]}}
+ */
+function findSymbols(map, query, options = {}) {
+  const needle = String(query || '').trim();
+  if (!needle || !map || !map.files) return { total: 0, symbols: [] };
+  const lower = needle.toLowerCase();
+  const score = definition => {
+    const names = [definition.name, definition.qualifiedName];
+    if (names.includes(needle)) return 0;
+    if (options.exact) return -1;
+    const lowered = names.map(name => name.toLowerCase());
+    if (lowered.includes(lower)) return 1;
+    if (lowered.some(name => name.startsWith(lower))) return 2;
+    return lowered.some(name => name.includes(lower)) ? 3 : -1;
+  };
+
+  const matches = [];
+  for (const [file, fileData] of Object.entries(map.files)) {
+    for (const definition of fileDefinitions(file, fileData)) {
+      if (options.kind && definition.kind !== options.kind) continue;
+      const rank = score(definition);
+      if (rank >= 0) matches.push({ rank, definition });
+    }
+  }
+  matches.sort((a, b) => a.rank - b.rank
+    || a.definition.qualifiedName.localeCompare(b.definition.qualifiedName)
+    || a.definition.file.localeCompare(b.definition.file));
+  return {
+    total: matches.length,
+    symbols: matches.slice(0, options.limit || DEFAULT_LIMIT).map(match => match.definition)
+  };
+}
+
+/**
+ * Cross-file callers of a function or class
+ * @param {Object} map - Repo map
+ * @param {string} name - Symbol name
+ * @param {Object} [options]
+ * @param {string} [options.file] - Only the definition in this file
+ * @returns {{definitions: Array<{file: string, line: number|null, callers: Array<{file: string, line: number}>}>, total: number}}
+ */
+function findCallers(map, name, options = {}) {
+  const definitions = [];
+  if (!map || !map.files) return { definitions, total: 0 };
+  const graph = map.callGraph || {};
+
+  for (const [file, fileData] of Object.entries(map.files)) {
+    if (options.file && file !== options.file) continue;
+    const definition = fileDefinitions(file, fileData).find(item => item.name === name && item.kind !== 'method');
+    const callers = (graph[file] || {})[name] || [];
+    if (!definition && callers.length === 0) continue;
+    definitions.push({ file, line: definition ? definition.line : null, callers });
+  }
+  definitions.sort((a, b) => b.callers.length - a.callers.length || a.file.localeCompare(b.file));
+  return { definitions, total: definitions.reduce((sum, item) => sum + item.callers.length, 0) };
+}
+
+module.exports = {
+  findSymbols,
+  findCallers
+};
@@ -0,0 +1,154 @@
+/**
+ * Render a repo map as prompt-ready text within a token budget
+ *
+ * When the full rendering exceeds the budget, output is reduced in stages:
+ * private symbols are dropped, then symbol details (signatures, members),
+ * then the lowest-ranked files, until it fits.
+ *
+ * @module lib/repo-map/render
+ */
+
+'use strict';
+
+const { rankFiles } = require('./rank');
+
+const CATEGORY_KINDS = { classes: 'class', types: 'type', functions: 'function', constants: 'constant' };
+const LEVELS = [
+  { includePrivate: true, includeDetails: true },
+  { includePrivate: false, includeDetails: true },
+  { includePrivate: false, includeDetails: false }
+];
+
+/**
+ * Default tokenizer (~4 characters per token)
+ * @param {string} text - Text to measure
+ * @returns {number}
+ */
+function estimateTokens(text) {
+  return Math.ceil(text.length / 4);
+}
+
+/**
+ * Render a symbol's headline
+ * @param {Object} entry - Symbol entry
+ * @param {string} category - Symbol category
+ * @param {boolean} includeDetails - Include signatures and type parameters
+ * @returns {string}
+ */
+function renderHeadline(entry, category, includeDetails) {
+  const kind = entry.kind || CATEGORY_KINDS[category];
+  let text = `${kind} ${entry.name}`;
+  if (includeDetails) {
+    if (entry.signature) {
+      text += entry.signature;
+    } else {
+      if (entry.typeParams) text += entry.typeParams;
+      if (entry.returnType) text += `: ${entry.returnType}`;
+    }
+    const bases = [].concat(entry.extends || entry.superclass || [], entry.implements || [], entry.bases || []);
+    if (bases.length > 0) text += ` < ${bases.join(', ')}`;
+  }
+  return entry.line ? `${text} :${entry.line}` : text;
+}
+
+/**
+ * Render nested members/methods for a symbol
+ * @param {Object} entry - Symbol entry
+ * @returns {string[]}
+ */
+function renderMembers(entry) {
+  const lines = [];
+  for (const member of entry.members || []) {
+    lines.push(`    ${member.name}${member.type ? `: ${member.type}` : ''}`);
+  }
+  for (const method of entry.methods || []) {
+    lines.push(`    ${method.scope === 'class' ? 'self.' : ''}${method.name}()`);
+  }
+  return lines;
+}
+
+/**
+ * Render one file block
+ * @param {string} file - File path
+ * @param {Object} fileData - File entry
+ * @param {Object} level - Reduction level
+ * @returns {string|null} - Null when no symbols survive the level
+ */
+function renderFile(file, fileData, level) {
+  const lines = [`${file}:`];
+  for (const category of Object.keys(CATEGORY_KINDS)) {
+    for (const entry of fileData.symbols?.[category] || []) {
+      if (entry.declaredIn || entry.memberOf) continue;
+      if (!level.includePrivate && entry.exported === false) continue;
+      lines.push(`  ${renderHeadline(entry, category, level.includeDetails)}`);
+      if (level.includeDetails) lines.push(...renderMembers(entry));
+    }
+  }
+  return lines.length > 1 ? lines.join('\n') : null;
+}
+
+/**
+ * Join file blocks with an omission note
+ * @param {string[]} blocks - Rendered file blocks
+ * @param {number} omitted - Number of files left out
+ * @returns {string}
+ */
+function joinBlocks(blocks, omitted) {
+  const parts = blocks.slice();
+  if (omitted > 0) parts.push(`... ${omitted} lower-ranked file${omitted === 1 ? '' : 's'} omitted`);
+  return parts.join('\n\n') + (parts.length > 0 ? '\n' : '');
+}
+
+/**
+ * Render a repo map, shrinking it to fit `maxTokens`
+ * @param {Object} map - Repo map
+ * @param {Object} [options]
+ * @param {number} [options.maxTokens] - Token budget (unbounded if omitted)
+ * @param {Function} [options.tokenizer] - `(text) => tokenCount`; defaults to ~4 chars/token
+ * @param {string[]} [options.focus] - Files to rank first (see `rank.rankFiles`)
+ * @returns {{text: string, tokens: number, files: number, omittedFiles: number, droppedPrivate: boolean, droppedDetails: boolean, fits: boolean}}
+ */
+function renderMap(map, options = {}) {
+  const tokenizer = options.tokenizer || estimateTokens;
+  const budget = options.maxTokens > 0 ? options.maxTokens : Infinity;
+  const ranks = rankFiles(map, { focus: options.focus });
+  const files = Object.keys((map && map.files) || {})
+    .sort((a, b) => (ranks.get(b) || 0) - (ranks.get(a) || 0) || a.localeCompare(b));
+
+  const result = (blocks, omitted, level) => {
+    const text = joinBlocks(blocks, omitted);
+    const tokens = tokenizer(text);
+    return {
+      text,
+      tokens,
+      files: blocks.length,
+      omittedFiles: omitted,
+      droppedPrivate: !level.includePrivate,
+      droppedDetails: !level.includeDetails,
+      fits: tokens <= budget
+    };
+  };
+
+  let blocks = [];
+  for (const level of LEVELS) {
+    blocks = files.map(file => renderFile(file, map.files[file], level)).filter(Boolean);
+    const rendered = result(blocks, 0, level);
+    if (rendered.fits) return rendered;
+  }
+
+  // Keep the longest prefix of ranked files that fits
+  const level = LEVELS[LEVELS.length - 1];
+  let lo = 0;
+  let hi = blocks.length - 1;
+  while (lo < hi) {
+    const mid = (lo + hi + 1) >> 1;
+    if (result(blocks.slice(0, mid), blocks.length - mid, level).fits) lo = mid;
+    else hi = mid - 1;
+  }
+  return result(blocks.slice(0, lo), blocks.length - lo, level);
+}
+
+module.exports = {
+  renderMap,
+  estimateTokens
+};
@@ -0,0 +1,234 @@
+/**
+ * Optional tree-sitter declaration parsing
+ *
+ * When the `tree-sitter` package and a language grammar can be resolved
+ * (`npm install tree-sitter tree-sitter-typescript`, or via `NODE_PATH`), declarations are
+ * read from the syntax tree, which catches multi-line and generic
+ * declarations that single-line ast-grep patterns miss. Without a grammar the
+ * pattern and regex extractors are used unchanged.
+ *
+ * @module lib/repo-map/treesitter
+ */
+
+'use strict';
+
+// language -> grammar package (and export property for multi-grammar packages)
+const GRAMMARS = {
+  javascript: { module: 'tree-sitter-javascript' },
+  typescript: { module: 'tree-sitter-typescript', property: 'typescript' },
+  python: { module: 'tree-sitter-python' },
+  go: { module: 'tree-sitter-go' },
+  rust: { module: 'tree-sitter-rust' },
+  java: { module: 'tree-sitter-java' },
+  kotlin: { module: 'tree-sitter-kotlin' },
+  c: { module: 'tree-sitter-c' },
+  cpp: { module: 'tree-sitter-cpp' },
+  ruby: { module: 'tree-sitter-ruby' },
+  csharp: { module: 'tree-sitter-c-sharp' }
+};
+
+// node type -> [category, kind] for declarations at file/namespace scope
+const DECLARATIONS = {
+  javascript: {
+    function_declaration: ['functions', 'function'],
+    generator_function_declaration: ['functions', 'function'],
+    class_declaration: ['classes', 'class']
+  },
+  typescript: {
+    function_declaration: ['functions', 'function'],
+    generator_function_declaration: ['functions', 'function'],
+    class_declaration: ['classes', 'class'],
+    abstract_class_declaration: ['classes', 'class'],
+    interface_declaration: ['types', 'interface'],
+    type_alias_declaration: ['types', 'type'],
+    enum_declaration: ['types', 'enum']
+  },
+  python: {
+    function_definition: ['functions', 'function'],
+    class_definition: ['classes', 'class']
+  },
+  go: {
+    function_declaration: ['functions', 'function'],
+    method_declaration: ['functions', 'method'],
+    type_spec: ['types', 'type']
+  },
+  rust: {
+    function_item: ['functions', 'function'],
+    struct_item: ['types', 'struct'],
+    enum_item: ['types', 'enum'],
+    trait_item: ['types', 'trait'],
+    type_item: ['types', 'type']
+  },
+  java: {
+    class_declaration: ['classes', 'class'],
+    interface_declaration: ['types', 'interface'],
+    enum_declaration: ['types', 'enum'],
+    record_declaration: ['classes', 'record']
+  },
+  kotlin: {
+    function_declaration: ['functions', 'function'],
+    class_declaration: ['classes', 'class'],
+    object_declaration: ['classes', 'object']
+  },
+  c: {
+    function_definition: ['functions', 'function'],
+    struct_specifier: ['types', 'struct'],
+    enum_specifier: ['types', 'enum']
+  },
+  cpp: {
+    function_definition: ['functions', 'function'],
+    class_specifier: ['classes', 'class'],
+    struct_specifier: ['types', 'struct'],
+    enum_specifier: ['types', 'enum']
+  },
+  ruby: {
+    method: ['functions', 'function'],
+    class: ['classes', 'class'],
+    module: ['classes', 'module']
+  },
+  csharp: {
+    class_declaration: ['classes', 'class'],
+    interface_declaration: ['types', 'interface'],
+    struct_declaration: ['types', 'struct'],
+    enum_declaration: ['types', 'enum'],
+    record_declaration: ['classes', 'record']
+  }
+};
+
+// Wrapper nodes whose children are still file/namespace scope
+const CONTAINERS = new Set([
+  'program', 'source_file', 'module', 'translation_unit', 'compilation_unit',
+  'export_statement', 'decorated_definition', 'type_declaration',
+  'namespace_declaration', 'file_scoped_namespace_declaration', 'declaration_list',
+  'namespace_definition', 'linkage_specification', 'ambient_declaration',
+  'mod_item'
+]);
+
+// Go, C, and C++ nest the declared name under a declarator
+const NAME_FIELDS = ['name', 'declarator'];
+
+const parserCache = new Map();
+
+/**
+ * Load a tree-sitter parser for a language
+ * @param {string} language - Language name
+ * @param {Object} [options]
+ * @param {Function} [options.require] - Module loader (defaults to `require`)
+ * @returns {Object|null} - Parser, or null when tree-sitter or the grammar is missing
+ */
+function loadParser(language, options = {}) {
+  const grammar = GRAMMARS[language];
+  if (!grammar) return null;
+
+  const load = options.require || require;
+  if (!options.require && parserCache.has(language)) return parserCache.get(language);
+
+  let parser = null;
+  try {
+    const Parser = load('tree-sitter');
+    const module = load(grammar.module);
+    parser = new Parser();
+    parser.setLanguage(grammar.property ? module[grammar.property] : module);
+  } catch {
+    parser = null;
+  }
+
+  if (!options.require) parserCache.set(language, parser);
+  return parser;
+}
+
+/**
+ * Check whether tree-sitter parsing is available for a language
+ * @param {string} language - Language name
+ * @param {Object} [options] - See `loadParser`
+ * @returns {boolean}
+ */
+function isAvailable(language, options = {}) {
+  return loadParser(language, options) !== null;
+}
+
+/**
+ * Read a declaration's name, descending through declarators
+ * @param {Object} node - Syntax node
+ * @returns {string|null}
+ */
+function getDeclaredName(node) {
+  let current = node;
+  for (let depth = 0; current && depth < 6; depth++) {
+    const next = NAME_FIELDS.map(field => current.childForFieldName(field)).find(Boolean);
+    if (!next) break;
+    if (/identifier$|^name$|^constant$/.test(next.type)) return next.text;
+    current = next;
+  }
+  return null;
+}
+
+/**
+ * Extract file and namespace scope declarations from source
+ * @param {string} language - Language name
+ * @param {string} content - File content
+ * @param {Object} [options] - See `loadParser`
+ * @returns {Array<{category: string, name: string, line: number, kind: string}>|null} - Null when no grammar is installed
+ */
+function extractDeclarations(language, content, options = {}) {
+  const parser = loadParser(language, options);
+  const types = DECLARATIONS[language];
+  if (!parser || !types) return null;
+
+  let tree;
+  try {
+    tree = parser.parse(content);
+  } catch {
+    return null;
+  }
+
+  const declarations = [];
+  const stack = [tree.rootNode];
+  while (stack.length > 0) {
+    const node = stack.pop();
+    const declaration = types[node.type];
+    if (declaration) {
+      const name = getDeclaredName(node);
+      if (name) {
+        declarations.push({ category: declaration[0], name, line: node.startPosition.row + 1, kind: declaration[1] });
+      }
+    }
+    // Only file/namespace scope: never descend into function or class bodies
+    if (CONTAINERS.has(node.type)) {
+      for (let i = node.namedChildCount - 1; i >= 0; i--) stack.push(node.namedChild(i));
+    }
+  }
+
+  return declarations.sort((a, b) => a.line - b.line);
+}
+
+/**
+ * Add declarations the pattern pass missed to symbol maps in place
+ * Existing entries win so pattern `extra` fields and kinds are preserved.
+ * @param {string} language - Language name
+ * @param {string} content - File content
+ * @param {Object} symbolMaps - Maps of exports/functions/classes/types/constants
+ * @param {Object} [options] - See `loadParser`
+ * @returns {number} - Number of symbols added
+ */
+function mergeDeclarations(language, content, symbolMaps, options = {}) {
+  const declarations = extractDeclarations(language, content, options);
+  if (!declarations) return 0;
+
+  let added = 0;
+  for (const declaration of declarations) {
+    const target = symbolMaps[declaration.category];
+    if (!target || target.has(declaration.name)) continue;
+    target.set(declaration.name, { name: declaration.name, line: declaration.line, kind: declaration.kind });
+    added++;
+  }
+  return added;
+}
+
+module.exports = {
+  GRAMMARS,
+  loadParser,
+  isAvailable,
+  extractDeclarations,
+  mergeDeclarations
+};
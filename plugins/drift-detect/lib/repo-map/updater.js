@@ -8,12 +8,18 @@
 
 const fs = require('fs');
 const path = require('path');
-const { execSync } = require('child_process');
 
 const runner = require('./runner');
 const cache = require('./cache');
 const installer = require('./installer');
+const { linkHeaderDeclarations, countFileSymbols } = require('./headers');
+const { groupDotnetProjects } = require('./dotnet');
+const { linkGoMethods } = require('./golang');
+const { buildCallGraph } = require('./calls');
 const { analyzeDocumentation } = require('../drift-detect/collectors');
+const git = require('../utils/git');
+const exec = require('../utils/exec');
+const scanLimits = require('../utils/scan-limits');
 
 /**
  * Perform incremental update based on git diff
@@ -69,9 +75,14 @@ async function incrementalUpdate(basePath, map) {
   }
 
   const changes = parseDiff(diff);
+  dropSubmoduleLinks(changes, git.nestedCheckouts(basePath).submodules);
+
+  // Submodule history is not in the parent's diff; compare their commits
+  const submodules = map.submodules ? runner.getSubmoduleCommits(basePath) : null;
+  const movedSubmodules = submodules ? changedSubmodules(map.submodules, submodules) : [];
 
   // No changes - just update metadata
-  if (changes.total === 0) {
+  if (changes.total === 0 && movedSubmodules.length === 0) {
     map.git = gitInfo;
     map.updated = new Date().toISOString();
     return {
@@ -99,11 +110,17 @@ async function incrementalUpdate(basePath, map) {
     }
   }
 
-  // Apply added/modified
+  // Apply added/modified; files that became too large, minified, or generated leave the map
+  const skip = scanLimits.createFileFilter(basePath);
   const updatedFiles = [...changes.added, ...changes.modified];
   for (const file of updatedFiles) {
     const fullPath = path.join(basePath, file);
     if (!fs.existsSync(fullPath)) continue;
+    if (skip(file)) {
+      delete map.files[file];
+      delete map.dependencies[file];
+      continue;
+    }
 
     const fileData = runner.scanSingleFile(installed.command, fullPath, basePath);
     if (fileData) {
@@ -116,13 +133,25 @@ async function incrementalUpdate(basePath, map) {
     }
   }
 
+  // Re-scan submodules that moved to another commit
+  const submoduleChanges = { added: 0, modified: 0, deleted: 0 };
+  for (const dir of movedSubmodules) {
+    const counts = rescanSubmodule(basePath, map, dir, installed.command, skip);
+    submoduleChanges.added += counts.added;
+    submoduleChanges.modified += counts.modified;
+    submoduleChanges.deleted += counts.deleted;
+  }
+  if (submodules) map.submodules = submodules;
+
   // Update docs if markdown changed and docs exist in map
   if (map.docs && changes.docsChanged) {
     map.docs = analyzeDocumentation({ cwd: basePath, depth: 'thorough' });
   }
 
   // Recalculate stats
+  regroupProjects(basePath, map);
   recalculateStats(map);
+  map.callGraph = buildCallGraph(map);
 
   // Update git metadata
   map.git = gitInfo;
@@ -132,15 +161,82 @@ async function incrementalUpdate(basePath, map) {
     success: true,
     map,
     changes: {
-      total: changes.total,
-      updated: updatedFiles.length,
-      added: changes.added.length,
-      deleted: changes.deleted.length,
-      renamed: changes.renamed.length
+      total: changes.total + submoduleChanges.added + submoduleChanges.modified + submoduleChanges.deleted,
+      updated: updatedFiles.length + submoduleChanges.modified,
+      added: changes.added.length + submoduleChanges.added,
+      deleted: changes.deleted.length + submoduleChanges.deleted,
+      renamed: changes.renamed.length,
+      ...(movedSubmodules.length > 0 ? { submodules: movedSubmodules } : {})
     }
   };
 }
 
+/**
+ * Drop submodule entries (gitlinks) from a parsed diff
+ * A moved submodule shows up as a modified path that is a directory.
+ * @param {Object} changes - Result of parseDiff, updated in place
+ * @param {string[]} submodules - Submodule paths relative to the root
+ */
+function dropSubmoduleLinks(changes, submodules) {
+  if (submodules.length === 0) return;
+  const links = new Set(submodules);
+  for (const key of ['added', 'modified', 'deleted']) {
+    changes[key] = changes[key].filter(file => !links.has(file));
+  }
+  changes.total = changes.added.length + changes.modified.length + changes.deleted.length + changes.renamed.length;
+}
+
+/**
+ * Submodules whose commit differs from the one recorded in the map
+ * @param {Array<{path: string, commit: string|null}>} recorded - `map.submodules`
+ * @param {Array<{path: string, commit: string|null}>} current - Result of runner.getSubmoduleCommits
+ * @returns {string[]} Paths added, removed, or moved
+ */
+function changedSubmodules(recorded, current) {
+  const before = new Map(recorded.map(sub => [sub.path, sub.commit]));
+  const after = new Map(current.map(sub => [sub.path, sub.commit]));
+  const paths = new Set([...before.keys(), ...after.keys()]);
+  return Array.from(paths).filter(dir => !before.has(dir) || !after.has(dir) || before.get(dir) !== after.get(dir)).sort();
+}
+
+/**
+ * Replace a submodule's files in the map with a fresh scan
+ * @param {string} basePath - Repository root
+ * @param {Object} map - Repo map, updated in place
+ * @param {string} dir - Submodule path relative to the root
+ * @param {string} cmd - ast-grep command
+ * @param {Function} skip - Content filter from scan-limits `createFileFilter`
+ * @returns {{added: number, modified: number, deleted: number}}
+ */
+function rescanSubmodule(basePath, map, dir, cmd, skip) {
+  const prefix = `${dir}/`;
+  const previous = new Map(Object.entries(map.files).filter(([file]) => file.startsWith(prefix)));
+  for (const file of previous.keys()) {
+    delete map.files[file];
+    delete map.dependencies[file];
+  }
+
+  const counts = { added: 0, modified: 0, deleted: 0 };
+  const root = path.join(basePath, dir);
+  for (const lang of map.project?.languages || []) {
+    for (const fullPath of runner.findFilesForLanguage(root, lang, { submodules: true, skip })) {
+      const file = path.relative(basePath, fullPath).replace(/\\/g, '/');
+      if (map.files[file]) continue;
+      const fileData = runner.scanSingleFile(cmd, fullPath, basePath);
+      if (!fileData) continue;
+      map.files[file] = fileData;
+      if (fileData.imports && fileData.imports.length > 0) {
+        map.dependencies[file] = Array.from(new Set(fileData.imports.map(imp => imp.source)));
+      }
+      if (!previous.has(file)) counts.added++;
+      else if (previous.get(file).hash !== fileData.hash) counts.modified++;
+      previous.delete(file);
+    }
+  }
+  counts.deleted = previous.size;
+  return counts;
+}
+
 /**
  * Update without git (hash comparison)
  * @param {string} basePath - Repository root
@@ -151,9 +247,10 @@ async function incrementalUpdate(basePath, map) {
 async function updateWithoutGit(basePath, map, cmd) {
   const currentFiles = new Set();
   const languages = map.project?.languages || [];
+  const skip = scanLimits.createFileFilter(basePath);
 
   for (const lang of languages) {
-    const files = runner.findFilesForLanguage(basePath, lang);
+    const files = runner.findFilesForLanguage(basePath, lang, { submodules: Boolean(map.submodules), skip });
     for (const file of files) {
       currentFiles.add(path.relative(basePath, file).replace(/\\/g, '/'));
     }
@@ -210,7 +307,10 @@ async function updateWithoutGit(basePath, map, cmd) {
 
   changes.total = changes.added.length + changes.modified.length + changes.deleted.length;
 
+  if (map.submodules) map.submodules = runner.getSubmoduleCommits(basePath);
+  regroupProjects(basePath, map);
   recalculateStats(map);
+  map.callGraph = buildCallGraph(map);
   map.updated = new Date().toISOString();
 
   return {
@@ -226,6 +326,54 @@ async function updateWithoutGit(basePath, map, cmd) {
   };
 }
 
+/**
+ * Re-scan specific files in place (watch mode)
+ * Missing files and files scan limits leave out are removed from the map;
+ * files with an unchanged hash are skipped.
+ * @param {string} basePath - Repository root
+ * @param {Object} map - Repo map (mutated)
+ * @param {string} cmd - ast-grep command
+ * @param {string[]} files - Repository-relative file paths
+ * @returns {{total: number, updated: string[], deleted: string[]}}
+ */
+function updateFiles(basePath, map, cmd, files) {
+  const changes = { total: 0, updated: [], deleted: [] };
+  const skip = scanLimits.createFileFilter(basePath);
+
+  for (const file of files) {
+    const fullPath = path.join(basePath, file);
+    if (!fs.existsSync(fullPath) || skip(file)) {
+      if (map.files[file]) {
+        delete map.files[file];
+        delete map.dependencies[file];
+        changes.deleted.push(file);
+      }
+      continue;
+    }
+
+    const fileData = runner.scanSingleFile(cmd, fullPath, basePath);
+    if (!fileData || (map.files[file] && map.files[file].hash === fileData.hash)) continue;
+
+    map.files[file] = fileData;
+    if (fileData.imports && fileData.imports.length > 0) {
+      map.dependencies[file] = Array.from(new Set(fileData.imports.map(imp => imp.source)));
+    } else {
+      delete map.dependencies[file];
+    }
+    changes.updated.push(file);
+  }
+
+  changes.total = changes.updated.length + changes.deleted.length;
+  if (changes.total > 0) {
+    regroupProjects(basePath, map);
+    recalculateStats(map);
+    map.callGraph = buildCallGraph(map);
+    map.updated = new Date().toISOString();
+  }
+
+  return changes;
+}
+
 /**
  * Check if repo-map is stale
  * @param {string} basePath - Repository root
@@ -266,6 +414,14 @@ function checkStaleness(basePath, map) {
     result.suggestFullRebuild = true;
   }
 
+  if (map.submodules) {
+    const moved = changedSubmodules(map.submodules, runner.getSubmoduleCommits(basePath));
+    if (moved.length > 0) {
+      result.isStale = true;
+      result.reason = result.reason || `Submodule changed: ${moved.join(', ')}`;
+    }
+  }
+
   const commitsBehind = getCommitsBehind(basePath, map.git.commit);
   if (commitsBehind > 0) {
     result.isStale = true;
@@ -333,15 +489,8 @@ function parseDiff(diff) {
  * @returns {string|null}
  */
 function getGitDiff(basePath, sinceCommit) {
-  try {
-    return execSync(`git diff --name-status -M ${sinceCommit} HEAD`, {
-      cwd: basePath,
-      encoding: 'utf8',
-      stdio: ['pipe', 'pipe', 'pipe']
-    }).trim();
-  } catch {
-    return null;
-  }
+  const out = exec.git(basePath, ['diff', '--name-status', '-M', sinceCommit, 'HEAD']);
+  return out === null ? null : out.trim();
 }
 
 /**
@@ -351,15 +500,7 @@ function getGitDiff(basePath, sinceCommit) {
  * @returns {boolean}
  */
 function commitExists(basePath, commit) {
-  try {
-    execSync(`git cat-file -e ${commit}`, {
-      cwd: basePath,
-      stdio: ['pipe', 'pipe', 'pipe']
-    });
-    return true;
-  } catch {
-    return false;
-  }
+  return exec.git(basePath, ['cat-file', '-e', commit]) !== null;
 }
 
 /**
@@ -368,15 +509,8 @@ function commitExists(basePath, commit) {
  * @returns {string|null}
  */
 function getCurrentBranch(basePath) {
-  try {
-    return execSync('git rev-parse --abbrev-ref HEAD', {
-      cwd: basePath,
-      encoding: 'utf8',
-      stdio: ['pipe', 'pipe', 'pipe']
-    }).trim();
-  } catch {
-    return null;
-  }
+  const out = exec.git(basePath, ['rev-parse', '--abbrev-ref', 'HEAD']);
+  return out === null ? null : out.trim();
 }
 
 /**
@@ -386,16 +520,7 @@ function getCurrentBranch(basePath) {
  * @returns {number}
  */
 function getCommitsBehind(basePath, commit) {
-  try {
-    const out = execSync(`git rev-list ${commit}..HEAD --count`, {
-      cwd: basePath,
-      encoding: 'utf8',
-      stdio: ['pipe', 'pipe', 'pipe']
-    }).trim();
-    return Number(out) || 0;
-  } catch {
-    return 0;
-  }
+  return Number(exec.git(basePath, ['rev-list', '--count', `${commit}..HEAD`])) || 0;
 }
 
 /**
@@ -407,24 +532,32 @@ function normalizePath(filePath) {
   return filePath ? filePath.replace(/\\/g, '/') : filePath;
 }
 
+/**
+ * Refresh .NET project grouping after file changes
+ * @param {string} basePath - Repository root
+ * @param {Object} map - Repo map
+ */
+function regroupProjects(basePath, map) {
+  if (!(map.project?.languages || []).includes('csharp')) return;
+  groupDotnetProjects(map, basePath, runner.findDotnetManifests(basePath));
+}
+
 /**
  * Recalculate map stats
  * @param {Object} map - Repo map
  */
 function recalculateStats(map) {
+  linkGoMethods(map);
+  linkHeaderDeclarations(map);
   const files = Object.values(map.files || {});
   map.stats.totalFiles = files.length;
-  map.stats.totalSymbols = files.reduce((sum, file) => {
-    return sum +
-      (file.symbols?.functions?.length || 0) +
-      (file.symbols?.classes?.length || 0) +
-      (file.symbols?.types?.length || 0) +
-      (file.symbols?.constants?.length || 0);
-  }, 0);
+  map.stats.totalSymbols = files.reduce((sum, file) => sum + countFileSymbols(file.symbols), 0);
 }
 
 module.exports = {
   incrementalUpdate,
   updateWithoutGit,
-  checkStaleness
+  updateFiles,
+  checkStaleness,
+  parseDiff
 };
@@ -0,0 +1,231 @@
+/**
+ * Repo map watch mode
+ *
+ * Keeps the cached map fresh during an editing session: file system events
+ * are debounced, changed source files are re-scanned with `updateFiles`, and
+ * the map is saved after each batch. A marker in the state directory lets
+ * other commands see that a watcher is running.
+ *
+ * @module lib/repo-map/watcher
+ */
+
+'use strict';
+
+const fs = require('fs');
+const path = require('path');
+
+const runner = require('./runner');
+const cache = require('./cache');
+const installer = require('./installer');
+const updater = require('./updater');
+const ignore = require('../utils/ignore');
+const { getStateDirPath } = require('../platform/state-dir');
+
+const WATCH_FILENAME = 'repo-map.watch';
+const DEFAULT_DEBOUNCE_MS = 300;
+
+/**
+ * Get watch marker path
+ * @param {string} basePath - Repository root
+ * @returns {string}
+ */
+function getWatchPath(basePath) {
+  return path.join(getStateDirPath(basePath), WATCH_FILENAME);
+}
+
+/**
+ * Read the running watcher's marker, if its process is alive
+ * @param {string} basePath - Repository root
+ * @returns {{pid: number, started: string}|null}
+ */
+function getActiveWatcher(basePath) {
+  try {
+    const marker = JSON.parse(fs.readFileSync(getWatchPath(basePath), 'utf8'));
+    process.kill(marker.pid, 0);
+    return marker;
+  } catch {
+    return null;
+  }
+}
+
+/**
+ * Watch the repository and keep the cached repo map up to date
+ * @param {string} basePath - Repository root
+ * @param {Object} [options]
+ * @param {number} [options.debounceMs=300] - Quiet period before re-scanning
+ * @param {Function} [options.onUpdate] - Called with `{total, updated, deleted}` after each saved batch
+ * @param {Function} [options.onError] - Called with watcher or scan errors
+ * @returns {{close: Function, flush: Function, recursive: boolean}}
+ */
+function watch(basePath, options = {}) {
+  const cmd = installer.getCommand();
+  if (!cmd) {
+    throw new Error('ast-grep not found');
+  }
+
+  const map = cache.load(basePath);
+  if (!map) {
+    throw new Error('No repo map found. Run /repo-map init first.');
+  }
+
+  const debounceMs = options.debounceMs ?? DEFAULT_DEBOUNCE_MS;
+  const onUpdate = options.onUpdate || (() => {});
+  const onError = options.onError || (() => {});
+  const extensions = new Set(Object.values(runner.LANGUAGE_EXTENSIONS).flat());
+  const isIgnored = ignore.createIgnoreFilter(basePath, { excludeDirs: runner.EXCLUDE_DIRS });
+  const stateDir = path.relative(basePath, getStateDirPath(basePath)).replace(/\\/g, '/');
+  const watchers = new Map();
+  const pending = new Set();
+  // Native recursive watching exists on macOS/Windows; elsewhere watch each directory so excluded trees cost nothing
+  const recursive = process.platform === 'darwin' || process.platform === 'win32';
+  let timer = null;
+  let closed = false;
+
+  // Mirrors the full scan: hidden directories, excluded dirs, and ignored paths are skipped
+  const excluded = (relativePath, isDir) => {
+    const parts = relativePath.split('/');
+    const dirs = isDir ? parts : parts.slice(0, -1);
+    return relativePath === stateDir
+      || relativePath.startsWith(`${stateDir}/`)
+      || dirs.some(part => part.startsWith('.'))
+      || isIgnored(relativePath, isDir);
+  };
+
+  /**
+   * Re-scan queued files and persist the map
+   * @returns {{total: number, updated: string[], deleted: string[]}|null}
+   */
+  function flush() {
+    clearTimeout(timer);
+    timer = null;
+    if (pending.size === 0) return null;
+
+    const files = Array.from(pending);
+    pending.clear();
+    try {
+      const changes = updater.updateFiles(basePath, map, cmd, files);
+      if (changes.total > 0) {
+        cache.save(basePath, map);
+        cache.saveFileCache(basePath, map, runner.getExtractorFingerprints(map));
+        onUpdate(changes);
+      }
+      return changes;
+    } catch (err) {
+      onError(err);
+      return null;
+    }
+  }
+
+  const schedule = () => {
+    clearTimeout(timer);
+    timer = setTimeout(flush, debounceMs);
+  };
+
+  const handleEvent = (relativePath) => {
+    if (closed || !relativePath || excluded(relativePath, false)) return;
+
+    let stat = null;
+    try {
+      stat = fs.statSync(path.join(basePath, relativePath));
+    } catch {
+      // Deleted or renamed away
+    }
+
+    if (stat && stat.isDirectory()) {
+      if (!recursive) watchTree(relativePath);
+      return;
+    }
+
+    if (extensions.has(path.extname(relativePath).toLowerCase())) {
+      pending.add(relativePath);
+    } else if (!stat) {
+      // A removed directory takes its files with it
+      for (const file of Object.keys(map.files)) {
+        if (file.startsWith(`${relativePath}/`)) pending.add(file);
+      }
+    }
+    if (pending.size > 0) schedule();
+  };
+
+  /**
+   * Watch one directory (platforms without native recursive watch)
+   * @param {string} relativeDir - Directory relative to root ('' for root)
+   */
+  function watchDir(relativeDir) {
+    if (watchers.has(relativeDir)) return;
+    try {
+      const watcher = fs.watch(path.join(basePath, relativeDir), (event, filename) => {
+        if (filename) handleEvent(path.posix.join(relativeDir, String(filename).replace(/\\/g, '/')));
+      });
+      watcher.on('error', () => {
+        watcher.close();
+        watchers.delete(relativeDir);
+      });
+      watchers.set(relativeDir, watcher);
+    } catch (err) {
+      onError(err);
+    }
+  }
+
+  /**
+   * Watch a directory and all non-excluded subdirectories
+   * @param {string} relativeDir - Directory relative to root
+   */
+  function watchTree(relativeDir) {
+    watchDir(relativeDir);
+    let entries = [];
+    try {
+      entries = fs.readdirSync(path.join(basePath, relativeDir), { withFileTypes: true });
+    } catch {
+      return;
+    }
+    for (const entry of entries) {
+      if (!entry.isDirectory()) continue;
+      const child = relativeDir ? `${relativeDir}/${entry.name}` : entry.name;
+      if (!excluded(child, true)) watchTree(child);
+    }
+  }
+
+  if (recursive) {
+    const watcher = fs.watch(basePath, { recursive: true }, (event, filename) => {
+      if (filename) handleEvent(String(filename).replace(/\\/g, '/'));
+    });
+    watcher.on('error', onError);
+    watchers.set('', watcher);
+  } else {
+    watchTree('');
+  }
+
+  const markerPath = getWatchPath(basePath);
+  try {
+    fs.mkdirSync(path.dirname(markerPath), { recursive: true });
+    fs.writeFileSync(markerPath, JSON.stringify({ pid: process.pid, started: new Date().toISOString() }), 'utf8');
+  } catch (err) {
+    onError(err);
+  }
+
+  /**
+   * Stop watching, flushing queued changes first
+   */
+  function close() {
+    if (closed) return;
+    flush();
+    closed = true;
+    for (const watcher of watchers.values()) watcher.close();
+    watchers.clear();
+    try {
+      const marker = JSON.parse(fs.readFileSync(markerPath, 'utf8'));
+      if (marker.pid === process.pid) fs.unlinkSync(markerPath);
+    } catch {
+      // Marker already gone
+    }
+  }
+
+  return { close, flush, recursive };
+}
+
+module.exports = {
+  watch,
+  getActiveWatcher,
+  getWatchPath
+};
@@ -15,6 +15,9 @@ const fs = require('fs');
 const path = require('path');
 const crypto = require('crypto');
 const { getStateDir } = require('../platform/state-dir');
+const { createLogger } = require('../utils/logger');
+
+const log = createLogger('state');
 
 // File paths
 const TASKS_FILE = 'tasks.json';
@@ -121,7 +124,7 @@ function readTasks(projectPath = process.cwd()) {
     }
     return data;
   } catch (e) {
-    console.error(`[CRITICAL] Corrupted tasks.json at ${tasksPath}: ${e.message}`);
+    log.error('Corrupted tasks.json', { path: tasksPath, error: e });
     return { active: null };
   }
 }
@@ -193,7 +196,7 @@ function readFlow(worktreePath = process.cwd()) {
   try {
     return JSON.parse(fs.readFileSync(flowPath, 'utf8'));
   } catch (e) {
-    console.error(`[CRITICAL] Corrupted flow.json at ${flowPath}: ${e.message}`);
+    log.error('Corrupted flow.json', { path: flowPath, error: e });
     return null;
   }
 }
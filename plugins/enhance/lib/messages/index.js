@@ -0,0 +1,273 @@
+/**
+ * Message Catalog
+ *
+ * User-facing report text keyed by id, so teams can ship translated
+ * findings and reports without patching source. English lives in
+ * `locales/en.json`; a catalog for another locale only needs the keys it
+ * translates, and anything missing falls back to the base language (`pt`
+ * for `pt-BR`), then to English.
+ *
+ * Locale, first one set wins: `AWESOME_SLASH_LOCALE`, `i18n.locale` in the
+ * project config, `LC_ALL`, `LC_MESSAGES`, `LANG`. Catalogs are read from
+ * `i18n.dir` in the project (`<dir>/<locale>.json`, checked in next to the
+ * code) before the built-in ones, so a project can also reword English.
+ *
+ * Messages are `{name}` templates. A message can be an object of plural
+ * forms keyed by `Intl.PluralRules` category (`one`, `few`, `other`, ...),
+ * chosen by the `count` parameter. Slop pattern descriptions are looked up
+ * as `pattern.<name>`, falling back to the description in the pattern
+ * library.
+ *
+ * @module lib/messages
+ */
+
+const fs = require('fs');
+const path = require('path');
+
+const { loadConfig } = require('../config');
+
+const CONFIG_KEY = 'i18n';
+
+const DEFAULT_LOCALE = 'en';
+
+/**
+ * Built-in catalogs
+ */
+const LOCALES_DIR = path.join(__dirname, 'locales');
+
+/**
+ * Environment variables naming the locale, in precedence order
+ */
+const LOCALE_ENV = ['AWESOME_SLASH_LOCALE', 'LC_ALL', 'LC_MESSAGES', 'LANG'];
+
+const LOCALE_PATTERN = /^[a-z]{2,3}(?:-[a-z0-9]{2,8})*$/i;
+
+/**
+ * Translator used by t() until configure() is called
+ * @type {Function|null}
+ */
+let current = null;
+
+/**
+ * Normalize a locale name: POSIX `de_DE.UTF-8` and BCP 47 `de-de` become `de-DE`
+ * @param {string} value - Locale name
+ * @returns {string|null} Null for `C`, `POSIX`, and names that are not locales
+ */
+function normalizeLocale(value) {
+  const name = String(value || '').trim().replace(/[.@].*$/, '').replace(/_/g, '-');
+  if (!LOCALE_PATTERN.test(name)) return null;
+  const [language, ...subtags] = name.split('-');
+  return [language.toLowerCase(), ...subtags.map(tag => (tag.length === 2 ? tag.toUpperCase() : tag))].join('-');
+}
+
+/**
+ * Locale settings from the project config
+ * @param {string} basePath - Repository root
+ * @returns {{locale: string|null, dir: string|null, error: string|null}} `dir` is absolute
+ */
+function readSettings(basePath) {
+  const { config, file, error } = loadConfig(basePath);
+  const settings = { locale: null, dir: null, error };
+  const value = config[CONFIG_KEY];
+  if (error || value === undefined) return settings;
+
+  const fail = message => ({ ...settings, error: `${file}: ${CONFIG_KEY}${message}` });
+  if (!value || typeof value !== 'object' || Array.isArray(value)) return fail(' must be an object');
+  if (value.locale !== undefined && !normalizeLocale(value.locale)) return fail('.locale must be a locale name such as "de" or "pt-BR"');
+  if (value.dir !== undefined && (typeof value.dir !== 'string' || !value.dir)) return fail('.dir must be a directory path');
+  return {
+    ...settings,
+    locale: value.locale !== undefined ? normalizeLocale(value.locale) : null,
+    dir: value.dir ? path.resolve(basePath, value.dir) : null
+  };
+}
+
+/**
+ * Pick the locale: AWESOME_SLASH_LOCALE, then the config, then the POSIX variables
+ * @param {Object} settings - From readSettings
+ * @param {Object} [env=process.env]
+ * @returns {string}
+ */
+function resolveLocale(settings, env = process.env) {
+  const [explicit, ...posix] = LOCALE_ENV.map(name => normalizeLocale(env[name]));
+  return explicit || settings.locale || posix.find(Boolean) || DEFAULT_LOCALE;
+}
+
+/**
+ * Locales to look a key up in, most specific first
+ * @param {string} locale - Normalized locale
+ * @returns {string[]} e.g. ['pt-BR', 'pt', 'en']
+ */
+function fallbackChain(locale) {
+  const chain = [];
+  const parts = locale.split('-');
+  for (let i = parts.length; i > 0; i--) chain.push(parts.slice(0, i).join('-'));
+  if (!chain.includes(DEFAULT_LOCALE)) chain.push(DEFAULT_LOCALE);
+  return chain;
+}
+
+/**
+ * Read one catalog file
+ * @param {string} file - Absolute path
+ * @returns {{messages: Object|null, error: string|null}} Null messages when the file is missing
+ */
+function readCatalog(file) {
+  let text;
+  try {
+    text = fs.readFileSync(file, 'utf8');
+  } catch {
+    return { messages: null, error: null };
+  }
+  try {
+    const messages = JSON.parse(text);
+    if (!messages || typeof messages !== 'object' || Array.isArray(messages)) {
+      return { messages: null, error: `${file}: must be an object of message ids` };
+    }
+    return { messages, error: null };
+  } catch (err) {
+    return { messages: null, error: `${file}: ${err.message}` };
+  }
+}
+
+/**
+ * Catalogs for a locale, most specific first
+ * @param {string} locale - Normalized locale
+ * @param {string|null} [dir] - Project catalog directory, read before the built-in one
+ * @returns {{catalogs: Object[], errors: string[]}}
+ */
+function loadCatalogs(locale, dir = null) {
+  const catalogs = [];
+  const errors = [];
+  for (const name of fallbackChain(locale)) {
+    for (const base of dir ? [dir, LOCALES_DIR] : [LOCALES_DIR]) {
+      const { messages, error } = readCatalog(path.join(base, `${name}.json`));
+      if (messages) catalogs.push(messages);
+      if (error) errors.push(error);
+    }
+  }
+  return { catalogs, errors };
+}
+
+/**
+ * Fill `{name}` placeholders; unknown names are left as written
+ * @param {string} template - Message text
+ * @param {Object} [params]
+ * @returns {string}
+ */
+function interpolate(template, params = {}) {
+  return String(template).replace(/\{(\w+)\}/g, (match, name) => (params[name] === undefined || params[name] === null ? match : String(params[name])));
+}
+
+/**
+ * Build a translator for a locale
+ * @param {string} locale - Normalized locale
+ * @param {Object[]} catalogs - From loadCatalogs
+ * @returns {Function} `t(key, params, fallback)`, with `locale` and `has(key)`
+ */
+function createTranslator(locale, catalogs) {
+  let plurals;
+  try {
+    plurals = new Intl.PluralRules(locale);
+  } catch {
+    plurals = new Intl.PluralRules(DEFAULT_LOCALE);
+  }
+
+  const lookup = key => {
+    for (const catalog of catalogs) {
+      if (Object.prototype.hasOwnProperty.call(catalog, key)) return catalog[key];
+    }
+    return undefined;
+  };
+
+  /**
+   * Translate a message
+   * @param {string} key - Message id
+   * @param {Object} [params] - Placeholder values; `count` picks the plural form
+   * @param {string} [fallback] - Template used when no catalog has the key (default: the key)
+   * @returns {string}
+   */
+  function t(key, params = {}, fallback) {
+    let message = lookup(key);
+    if (message && typeof message === 'object') {
+      message = message[plurals.select(Number(params.count))] || message.other;
+    }
+    if (typeof message !== 'string') message = fallback !== undefined ? fallback : key;
+    return interpolate(message, params);
+  }
+  t.locale = locale;
+  t.has = key => lookup(key) !== undefined;
+  return t;
+}
+
+/**
+ * Set the locale for a repository: the config, its catalogs, and the environment
+ * Entry points call this once; reporters then use t().
+ * @param {string} basePath - Repository root
+ * @param {Object} [options]
+ * @param {Object} [options.env=process.env]
+ * @param {string} [options.locale] - Overrides every other source
+ * @returns {{locale: string, errors: string[]}} Config and catalog errors
+ */
+function configure(basePath, options = {}) {
+  const settings = readSettings(basePath);
+  const locale = normalizeLocale(options.locale) || resolveLocale(settings, options.env || process.env);
+  const { catalogs, errors } = loadCatalogs(locale, settings.dir);
+  current = createTranslator(locale, catalogs);
+  return { locale, errors: settings.error ? [settings.error, ...errors] : errors };
+}
+
+/**
+ * Translator for the configured locale
+ * Before configure(), only the environment picks the locale and only built-in catalogs are read.
+ * @returns {Function}
+ */
+function translator() {
+  if (!current) {
+    const locale = resolveLocale({ locale: null });
+    current = createTranslator(locale, loadCatalogs(locale).catalogs);
+  }
+  return current;
+}
+
+/**
+ * Translate with the configured locale
+ * @param {string} key - Message id
+ * @param {Object} [params] - Placeholder values; `count` picks the plural form
+ * @param {string} [fallback] - Template used when no catalog has the key
+ * @returns {string}
+ */
+function t(key, params, fallback) {
+  return translator()(key, params, fallback);
+}
+
+/**
+ * Locale t() uses
+ * @returns {string}
+ */
+function getLocale() {
+  return translator().locale;
+}
+
+/**
+ * Forget the configured locale (for tests)
+ */
+function reset() {
+  current = null;
+}
+
+module.exports = {
+  CONFIG_KEY,
+  DEFAULT_LOCALE,
+  LOCALES_DIR,
+  normalizeLocale,
+  readSettings,
+  resolveLocale,
+  fallbackChain,
+  loadCatalogs,
+  interpolate,
+  createTranslator,
+  configure,
+  t,
+  getLocale,
+  reset
+};
@@ -0,0 +1,166 @@
+/**
+ * Project Review Pattern Packs
+ *
+ * Loads review patterns a repository ships in `.awesome-slash/patterns/*.js`
+ * (`.awsome-slash/patterns/` is also read) and merges them with the built-in
+ * library from review-patterns.js. A pack exports the same shape as the
+ * built-ins, framework -> category -> descriptions:
+ *
+ *   module.exports = {
+ *     express: { api_conventions: ['Handlers not using the shared respond() helper'] },
+ *     'internal-api': { versioning: ['Routes missing the /v{n} prefix'] }
+ *   };
+ *
+ * Categories extend built-in frameworks; new top-level keys become extra
+ * pattern sets that apply to every review. Packs are JavaScript and run with
+ * the reviewer's permissions, like the project's own build scripts. Invalid
+ * entries are skipped and reported; they never stop a review.
+ *
+ * Usage:
+ *   node pattern-packs.js [repo-path] > project-patterns.json
+ *
+ * @module patterns/pattern-packs
+ * @author Avi Fenesh
+ * @license MIT
+ */
+
+const fs = require('fs');
+const path = require('path');
+const { reviewPatterns } = require('./review-patterns');
+
+/**
+ * Pack directories, relative to the repository root
+ */
+const PACK_DIRS = ['.awesome-slash/patterns', '.awsome-slash/patterns'];
+
+/**
+ * Allowed framework and category names
+ */
+const NAME_PATTERN = /^[a-z][a-z0-9_-]{0,63}$/;
+
+/**
+ * Validate a pack's exports
+ * @param {*} exported - Value the pack module exported
+ * @param {string} file - Pack path for error messages
+ * @returns {{patterns: Object<string, Object<string, string[]>>, errors: string[]}}
+ */
+function validatePack(exported, file) {
+  const errors = [];
+  const patterns = {};
+  const pack = exported && exported.default && typeof exported.default === 'object' ? exported.default : exported;
+  if (!pack || typeof pack !== 'object' || Array.isArray(pack)) {
+    return { patterns, errors: [`${file}: expected an object of framework -> category -> patterns`] };
+  }
+
+  for (const [framework, categories] of Object.entries(pack)) {
+    if (!NAME_PATTERN.test(framework)) {
+      errors.push(`${file}: invalid framework name "${framework}"`);
+      continue;
+    }
+    if (!categories || typeof categories !== 'object' || Array.isArray(categories)) {
+      errors.push(`${file}: ${framework}: expected an object of categories`);
+      continue;
+    }
+    for (const [category, list] of Object.entries(categories)) {
+      if (!NAME_PATTERN.test(category)) {
+        errors.push(`${file}: ${framework}: invalid category name "${category}"`);
+        continue;
+      }
+      if (!Array.isArray(list) || list.length === 0 || !list.every(item => typeof item === 'string' && item.trim())) {
+        errors.push(`${file}: ${framework}.${category}: expected a non-empty array of strings`);
+        continue;
+      }
+      if (!patterns[framework]) patterns[framework] = {};
+      patterns[framework][category] = list.map(item => item.trim());
+    }
+  }
+  return { patterns, errors };
+}
+
+/**
+ * Merge pattern packs over a base library without mutating either
+ * @param {Object} base - Built-in patterns
+ * @param {Object[]} packs - Validated pack patterns, in load order
+ * @returns {Object} Merged patterns; lists are concatenated and deduplicated
+ */
+function mergePatterns(base, packs) {
+  const merged = {};
+  for (const [framework, categories] of Object.entries(base)) {
+    merged[framework] = {};
+    for (const [category, list] of Object.entries(categories)) merged[framework][category] = list.slice();
+  }
+  for (const pack of packs) {
+    for (const [framework, categories] of Object.entries(pack)) {
+      if (!merged[framework]) merged[framework] = {};
+      for (const [category, list] of Object.entries(categories)) {
+        const existing = merged[framework][category] || [];
+        merged[framework][category] = Array.from(new Set([...existing, ...list]));
+      }
+    }
+  }
+  return merged;
+}
+
+/**
+ * List pack files in the repository, sorted per directory
+ * @param {string} repoPath - Repository root
+ * @returns {string[]} Repository-relative paths
+ */
+function listPackFiles(repoPath) {
+  const files = [];
+  for (const dir of PACK_DIRS) {
+    let names;
+    try {
+      names = fs.readdirSync(path.join(repoPath, dir));
+    } catch {
+      continue;
+    }
+    names.filter(name => /\.(?:c?js)$/.test(name)).sort().forEach(name => files.push(`${dir}/${name}`));
+  }
+  return files;
+}
+
+/**
+ * Load review patterns with the project's packs merged in
+ * @param {string} repoPath - Repository root
+ * @returns {{patterns: Object, sets: string[], packs: Array<{file: string, patterns: Object}>, errors: string[]}}
+ *   `sets` are the pack-defined pattern sets that are not built-in frameworks
+ */
+function loadReviewPatterns(repoPath) {
+  const packs = [];
+  const errors = [];
+
+  for (const file of listPackFiles(repoPath)) {
+    const absolute = path.resolve(repoPath, file);
+    let exported;
+    try {
+      delete require.cache[absolute];
+      exported = require(absolute);
+    } catch (error) {
+      errors.push(`${file}: ${error.message.split('\n')[0]}`);
+      continue;
+    }
+    const result = validatePack(exported, file);
+    errors.push(...result.errors);
+    packs.push({ file, patterns: result.patterns });
+  }
+
+  const sets = Array.from(new Set(packs.flatMap(pack => Object.keys(pack.patterns))))
+    .filter(name => !Object.prototype.hasOwnProperty.call(reviewPatterns, name));
+  return { patterns: mergePatterns(reviewPatterns, packs.map(pack => pack.patterns)), sets, packs, errors };
+}
+
+module.exports = {
+  PACK_DIRS,
+  validatePack,
+  mergePatterns,
+  listPackFiles,
+  loadReviewPatterns
+};
+
+// CLI usage
+if (require.main === module) {
+  const { sets, packs, errors } = loadReviewPatterns(path.resolve(process.argv[2] || process.cwd()));
+  // Only what the packs add; built-ins come from review-patterns.js
+  console.log(JSON.stringify({ sets, packs, errors }, null, 2));
+}
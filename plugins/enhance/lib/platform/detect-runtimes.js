@@ -0,0 +1,247 @@
+#!/usr/bin/env node
+/**
+ * Runtime Version Detection
+ * Reads the language runtime versions a project pins or supports (Node, Python,
+ * Go, Rust, Ruby, Java) so reviews can flag code that needs a newer runtime
+ *
+ * Synchronous so `runPipeline` can call it directly.
+ *
+ * Usage: node lib/platform/detect-runtimes.js [path]
+ * Output: JSON with detected runtimes (or null)
+ *
+ * @module lib/platform/detect-runtimes
+ */
+
+const { RUNTIME_CONFIGS } = require('./detection-configs');
+const { readText, parseJsonc } = require('./detect-linters');
+
+/**
+ * Parse the numeric components of a version ("v20.11.1", "go1.22", "3.11.*")
+ * @param {string} value - Version text
+ * @returns {number[]|null} Components up to the first non-numeric part
+ */
+function parseVersion(value) {
+  const match = String(value || '').trim().match(/^(?:v|go|python-|ruby-)?(\d+)((?:\.\d+)*)/i);
+  if (!match) return null;
+  return [match[1], ...match[2].split('.').filter(Boolean)].map(Number);
+}
+
+/**
+ * Compare two versions component by component (missing components are 0)
+ * @param {string} a - Version
+ * @param {string} b - Version
+ * @returns {number} Negative, zero, or positive
+ */
+function compareVersions(a, b) {
+  const left = parseVersion(a) || [];
+  const right = parseVersion(b) || [];
+  for (let i = 0; i < Math.max(left.length, right.length); i++) {
+    const diff = (left[i] || 0) - (right[i] || 0);
+    if (diff !== 0) return diff;
+  }
+  return 0;
+}
+
+/**
+ * Lowest version a constraint allows
+ * Handles npm ranges (`>=18`, `^18.17 || >=20`, `18.x`) and PEP 440
+ * specifiers (`>=3.9,<4`, `~=3.10`). Upper bounds and exclusions are ignored.
+ * @param {string} constraint - Version constraint
+ * @returns {string|null} Dotted version, or null when there is no lower bound
+ */
+function lowerBound(constraint) {
+  let lowest = null;
+  for (const alternative of String(constraint || '').split('||')) {
+    let bound = null;
+    let bounded = false;
+    for (const token of alternative.split(/[\s,]+/).filter(Boolean)) {
+      const match = token.match(/^(>=|>|\^|~=|~|==|=)?\s*v?(\d+(?:\.(?:\d+|[x*]))*)$/i);
+      if (!match) continue;
+      bounded = true;
+      const version = match[2].split('.').filter(part => /^\d+$/.test(part)).join('.');
+      if (!bound || compareVersions(version, bound) > 0) bound = version;
+    }
+    if (!bounded) continue;
+    if (!lowest || compareVersions(bound, lowest) < 0) lowest = bound;
+  }
+  return lowest;
+}
+
+/**
+ * Read a string value from a TOML table
+ * @param {string} content - TOML content
+ * @param {string} table - Table name ('' for top-level keys)
+ * @param {string} key - Key name
+ * @returns {string|null}
+ */
+function readTomlString(content, table, key) {
+  let current = '';
+  for (const line of content.split('\n')) {
+    const text = line.replace(/\s+#.*$/, '').trim();
+    const header = text.match(/^\[([^\]]+)\]$/);
+    if (header) {
+      current = header[1].trim();
+      continue;
+    }
+    if (current !== table) continue;
+    const match = text.match(/^["']?([\w.-]+)["']?\s*=\s*["']([^"']*)["']/);
+    if (match && match[1] === key) return match[2];
+  }
+  return null;
+}
+
+/**
+ * Parse `.tool-versions` (asdf/mise) entries
+ * @param {string} content - File content
+ * @returns {Array<{runtime: string, value: string}>}
+ */
+function parseToolVersions(content) {
+  const entries = [];
+  for (const line of content.split('\n')) {
+    const [plugin, value] = line.replace(/#.*/, '').trim().split(/\s+/);
+    const runtime = RUNTIME_CONFIGS.toolVersions[plugin];
+    if (runtime && value) entries.push({ runtime, value });
+  }
+  return entries;
+}
+
+/**
+ * Normalize an `.nvmrc` value, resolving `lts/<codename>` aliases
+ * @param {string} value - .nvmrc content
+ * @returns {string} Version, or the original alias when it cannot be resolved
+ */
+function normalizeNodeAlias(value) {
+  const alias = value.trim().toLowerCase().match(/^lts\/(\w+)$/);
+  if (alias && RUNTIME_CONFIGS.nodeCodenames[alias[1]]) return RUNTIME_CONFIGS.nodeCodenames[alias[1]];
+  return value.trim();
+}
+
+/**
+ * Collect version declarations per runtime
+ * `kind` is `exact` for a pinned version and `range` for a supported range.
+ * @param {string} basePath - Project root
+ * @returns {Array<{runtime: string, file: string, value: string, kind: string}>}
+ */
+function collectDeclarations(basePath) {
+  const declarations = [];
+  const add = (runtime, file, value, kind) => {
+    if (typeof value === 'string' && value.trim()) declarations.push({ runtime, file, value: value.trim(), kind });
+  };
+  const firstLine = content => content.split('\n').map(line => line.trim()).find(line => line && !line.startsWith('#')) || '';
+
+  const toolVersions = readText(basePath, '.tool-versions');
+  if (toolVersions) {
+    for (const { runtime, value } of parseToolVersions(toolVersions)) add(runtime, '.tool-versions', value, 'exact');
+  }
+
+  // Node
+  for (const file of ['.nvmrc', '.node-version']) {
+    const content = readText(basePath, file);
+    if (content) add('node', file, normalizeNodeAlias(firstLine(content)), 'exact');
+  }
+  const pkg = parseJsonc(readText(basePath, 'package.json') || '');
+  if (pkg) {
+    if (pkg.volta && typeof pkg.volta.node === 'string') add('node', 'package.json#volta.node', pkg.volta.node, 'exact');
+    if (pkg.engines && typeof pkg.engines.node === 'string') add('node', 'package.json#engines.node', pkg.engines.node, 'range');
+  }
+
+  // Python
+  const pythonVersion = readText(basePath, '.python-version');
+  if (pythonVersion) add('python', '.python-version', firstLine(pythonVersion), 'exact');
+  const runtimeTxt = readText(basePath, 'runtime.txt');
+  if (runtimeTxt && /^python-/.test(firstLine(runtimeTxt))) add('python', 'runtime.txt', firstLine(runtimeTxt).replace(/^python-/, ''), 'exact');
+  const pyproject = readText(basePath, 'pyproject.toml');
+  if (pyproject) {
+    add('python', 'pyproject.toml#project.requires-python', readTomlString(pyproject, 'project', 'requires-python'), 'range');
+    add('python', 'pyproject.toml#tool.poetry.dependencies.python', readTomlString(pyproject, 'tool.poetry.dependencies', 'python'), 'range');
+  }
+  const pipfile = readText(basePath, 'Pipfile');
+  if (pipfile) add('python', 'Pipfile#requires.python_version', readTomlString(pipfile, 'requires', 'python_version'), 'exact');
+
+  // Go: the `go` directive is the minimum language version
+  const goMod = readText(basePath, 'go.mod');
+  if (goMod) {
+    const directive = goMod.match(/^go\s+(\d+(?:\.\d+)*)\s*$/m);
+    if (directive) add('go', 'go.mod#go', directive[1], 'range');
+    const toolchain = goMod.match(/^toolchain\s+go(\d+(?:\.\d+)*)\s*$/m);
+    if (toolchain) add('go', 'go.mod#toolchain', toolchain[1], 'exact');
+  }
+
+  // Rust
+  const toolchainToml = readText(basePath, 'rust-toolchain.toml');
+  if (toolchainToml) {
+    add('rust', 'rust-toolchain.toml', readTomlString(toolchainToml, 'toolchain', 'channel'), 'exact');
+  } else {
+    const legacy = readText(basePath, 'rust-toolchain');
+    if (legacy) add('rust', 'rust-toolchain', readTomlString(legacy, 'toolchain', 'channel') || firstLine(legacy), 'exact');
+  }
+  const cargo = readText(basePath, 'Cargo.toml');
+  if (cargo) {
+    add('rust', 'Cargo.toml#package.rust-version', readTomlString(cargo, 'package', 'rust-version') ||
+      readTomlString(cargo, 'workspace.package', 'rust-version'), 'range');
+  }
+
+  // Ruby and Java
+  const rubyVersion = readText(basePath, '.ruby-version');
+  if (rubyVersion) add('ruby', '.ruby-version', firstLine(rubyVersion), 'exact');
+  const gemfile = readText(basePath, 'Gemfile');
+  const gemRuby = gemfile && gemfile.match(/^\s*ruby\s+["']([^"']+)["']/m);
+  if (gemRuby) add('ruby', 'Gemfile#ruby', gemRuby[1], 'range');
+  const javaVersion = readText(basePath, '.java-version');
+  if (javaVersion) add('java', '.java-version', firstLine(javaVersion), 'exact');
+
+  return declarations;
+}
+
+/**
+ * Detect declared runtime versions
+ * `version` is the first pinned version; `minimum` is the oldest version the
+ * project claims to support (the lowest range bound, else the pin), which is
+ * what feature checks compare against; `minimumSource` names its file.
+ * @param {string} [basePath=process.cwd()] - Project root
+ * @returns {Object|null} `{runtimes: [{name, version, minimum, minimumSource, sources}]}` or null when nothing is declared
+ */
+function detectRuntimes(basePath = process.cwd()) {
+  const byRuntime = new Map();
+  for (const declaration of collectDeclarations(basePath)) {
+    if (!byRuntime.has(declaration.runtime)) byRuntime.set(declaration.runtime, []);
+    byRuntime.get(declaration.runtime).push(declaration);
+  }
+
+  const runtimes = [];
+  for (const [name, declarations] of byRuntime) {
+    const exact = declarations.find(entry => entry.kind === 'exact' && parseVersion(entry.value));
+    const bounds = declarations
+      .filter(entry => entry.kind === 'range')
+      .map(entry => ({ file: entry.file, version: lowerBound(entry.value) }))
+      .filter(bound => bound.version)
+      .sort((a, b) => compareVersions(a.version, b.version));
+    const minimum = bounds.length > 0
+      ? bounds[0]
+      : (exact ? { file: exact.file, version: parseVersion(exact.value).join('.') } : null);
+
+    runtimes.push({
+      name,
+      version: exact ? exact.value.replace(/^v/, '') : null,
+      minimum: minimum ? minimum.version : null,
+      minimumSource: minimum ? minimum.file : null,
+      sources: declarations.map(({ file, value }) => ({ file, value }))
+    });
+  }
+
+  return runtimes.length > 0 ? { runtimes } : null;
+}
+
+// When run directly, output JSON
+if (require.main === module) {
+  const result = detectRuntimes(process.argv[2] || process.cwd());
+  const indent = process.stdout.isTTY ? 2 : 0;
+  console.log(JSON.stringify(result, null, indent));
+}
+
+module.exports = {
+  detectRuntimes,
+  lowerBound,
+  compareVersions,
+  parseVersion
+};
@@ -0,0 +1,422 @@
+/**
+ * Coverage report parsing and symbol mapping
+ *
+ * Reads lcov, Cobertura XML, Go coverprofiles, and coverage.py JSON into
+ * per-line hit counts, then maps uncovered lines onto repo-map functions.
+ * The map records where a symbol starts, not where it ends, so a function
+ * spans up to the next function or class in the same file.
+ *
+ * @module lib/repo-map/coverage
+ */
+
+'use strict';
+
+const path = require('path');
+
+const { isTestFile } = require('./testgen');
+
+/**
+ * Report locations probed when no report is given, most specific first
+ */
+const REPORT_PATHS = [
+  'coverage/lcov.info',
+  'lcov.info',
+  'coverage/cobertura-coverage.xml',
+  'coverage.xml',
+  'cobertura.xml',
+  'target/site/cobertura/coverage.xml',
+  'coverage.json',
+  'coverage.out',
+  'cover.out',
+  'coverage.txt'
+];
+
+/**
+ * Commands that write a report to one of REPORT_PATHS, per test framework
+ * (names from platform/detect-tests)
+ */
+const COVERAGE_COMMANDS = {
+  jest: ['npx', 'jest', '--coverage', '--coverageReporters=lcov'],
+  vitest: ['npx', 'vitest', 'run', '--coverage', '--coverage.reporter=lcov'],
+  mocha: ['npx', 'c8', '--reporter=lcov', 'mocha'],
+  pytest: ['pytest', '--cov', '--cov-report=xml'],
+  'go-test': ['go', 'test', '-coverprofile=coverage.out', './...'],
+  'cargo-test': ['cargo', 'llvm-cov', '--lcov', '--output-path', 'lcov.info']
+};
+
+/**
+ * Coverage command for a project's test frameworks
+ * @param {Object|null} detected - Result of detectTestFrameworks
+ * @returns {string[]|null} argv, or null when no framework has one
+ */
+function coverageCommand(detected) {
+  const framework = ((detected && detected.frameworks) || []).find(item => COVERAGE_COMMANDS[item.name]);
+  return framework ? COVERAGE_COMMANDS[framework.name] : null;
+}
+
+/**
+ * Detect a report's format from its content
+ * @param {string} content - Report content
+ * @returns {'lcov'|'cobertura'|'go'|'coveragepy'|null}
+ */
+function detectFormat(content) {
+  const text = String(content || '');
+  if (/^mode: (?:set|count|atomic)\s*$/m.test(text.slice(0, 200))) return 'go';
+  if (/^SF:/m.test(text)) return 'lcov';
+  if (/<coverage[\s>]/.test(text) && /<class\b/.test(text)) return 'cobertura';
+  if (/^\s*\{/.test(text) && /"executed_lines"/.test(text)) return 'coveragepy';
+  return null;
+}
+
+/**
+ * Record hits for a line, keeping the highest count
+ * @param {Object<string, Map<number, number>>} files - Coverage being built
+ * @param {string} file - Report path
+ * @param {number} line - Line number
+ * @param {number} hits - Hit count
+ */
+function addLine(files, file, line, hits) {
+  if (!files[file]) files[file] = new Map();
+  const lines = files[file];
+  lines.set(line, Math.max(lines.get(line) || 0, hits));
+}
+
+/**
+ * Parse lcov (`SF:`, `DA:<line>,<hits>`, `end_of_record`)
+ * @param {string} content - lcov.info content
+ * @returns {Object<string, Map<number, number>>}
+ */
+function parseLcov(content) {
+  const files = {};
+  let current = null;
+  for (const line of String(content).split('\n')) {
+    if (line.startsWith('SF:')) {
+      current = line.slice(3).trim();
+      if (!files[current]) files[current] = new Map();
+    } else if (current && line.startsWith('DA:')) {
+      const [number, hits] = line.slice(3).split(',');
+      addLine(files, current, Number(number), Number(hits) || 0);
+    } else if (line.startsWith('end_of_record')) {
+      current = null;
+    }
+  }
+  return files;
+}
+
+/**
+ * Parse Cobertura XML (`<class filename>` with `<line number hits>`)
+ * Filenames are joined to the first `<source>` when one is given.
+ * @param {string} content - XML content
+ * @returns {Object<string, Map<number, number>>}
+ */
+function parseCobertura(content) {
+  const files = {};
+  const source = (String(content).match(/<source>\s*([^<]+?)\s*<\/source>/) || [])[1];
+  for (const block of String(content).matchAll(/<class\b([^>]*)>([\s\S]*?)<\/class>/g)) {
+    const filename = (block[1].match(/\bfilename="([^"]+)"/) || [])[1];
+    if (!filename) continue;
+    const file = source && !path.isAbsolute(filename) && source !== '.' ? `${source.replace(/[\\/]+$/, '')}/${filename}` : filename;
+    if (!files[file]) files[file] = new Map();
+    for (const line of block[2].matchAll(/<line\b[^>]*\bnumber="(\d+)"[^>]*\bhits="(\d+)"/g)) {
+      addLine(files, file, Number(line[1]), Number(line[2]));
+    }
+  }
+  return files;
+}
+
+/**
+ * Parse a Go coverprofile (`file:l.c,l.c statements count`)
+ * Blocks cover line ranges; a line is covered when any block on it ran.
+ * @param {string} content - Profile content
+ * @returns {Object<string, Map<number, number>>}
+ */
+function parseGoProfile(content) {
+  const files = {};
+  for (const line of String(content).split('\n')) {
+    const match = line.match(/^(.+):(\d+)\.\d+,(\d+)\.\d+ \d+ (\d+)$/);
+    if (!match) continue;
+    const [, file, start, end, count] = match;
+    for (let number = Number(start); number <= Number(end); number++) {
+      addLine(files, file, number, Number(count));
+    }
+  }
+  return files;
+}
+
+/**
+ * Parse coverage.py JSON (`coverage json`)
+ * @param {string} content - JSON content
+ * @returns {Object<string, Map<number, number>>}
+ */
+function parseCoveragePy(content) {
+  const files = {};
+  let data;
+  try {
+    data = JSON.parse(content);
+  } catch {
+    return files;
+  }
+  for (const [file, entry] of Object.entries(data.files || {})) {
+    files[file] = new Map();
+    for (const number of entry.executed_lines || []) addLine(files, file, number, 1);
+    for (const number of entry.missing_lines || []) addLine(files, file, number, 0);
+  }
+  return files;
+}
+
+/**
+ * Parse a coverage report of any supported format
+ * @param {string} content - Report content
+ * @param {string} [format] - Format (detected if omitted)
+ * @returns {{format: string|null, files: Object<string, Map<number, number>>}}
+ */
+function parseReport(content, format) {
+  const detected = format || detectFormat(content);
+  const parsers = { lcov: parseLcov, cobertura: parseCobertura, go: parseGoProfile, coveragepy: parseCoveragePy };
+  return { format: detected, files: parsers[detected] ? parsers[detected](content) : {} };
+}
+
+/**
+ * Map report paths onto repo-map files
+ * Absolute paths are made relative to the repository; module paths (Go
+ * import paths, Cobertura package-relative names) match by path suffix.
+ * A workspace package's report names files relative to the package, so
+ * `prefix` (the package path) is tried before suffix matching.
+ * @param {Object<string, Map<number, number>>} files - Parsed coverage
+ * @param {string[]} mapFiles - Repository-relative files in the map
+ * @param {string} basePath - Repository root
+ * @param {string} [prefix] - Package path the report's relative paths start from
+ * @returns {{files: Object<string, Map<number, number>>, unmatched: string[]}}
+ */
+function resolvePaths(files, mapFiles, basePath, prefix) {
+  const known = new Set(mapFiles);
+  const root = path.resolve(basePath).replace(/\\/g, '/');
+  const resolved = {};
+  const unmatched = [];
+
+  for (const [reported, lines] of Object.entries(files)) {
+    let file = reported.replace(/\\/g, '/').replace(/^\.\//, '');
+    if (file.startsWith(`${root}/`)) file = file.slice(root.length + 1);
+    if (prefix && !known.has(file) && known.has(path.posix.join(prefix, file))) file = path.posix.join(prefix, file);
+    if (!known.has(file)) {
+      const candidates = mapFiles.filter(candidate => file.endsWith(`/${candidate}`) || candidate.endsWith(`/${file}`));
+      // Prefer the longest shared path when several files share a basename
+      candidates.sort((a, b) => b.length - a.length);
+      file = candidates[0] || null;
+    }
+    if (!file) {
+      unmatched.push(reported);
+      continue;
+    }
+    if (resolved[file]) {
+      for (const [line, hits] of lines) addLine(resolved, file, line, hits);
+    } else {
+      resolved[file] = lines;
+    }
+  }
+  return { files: resolved, unmatched };
+}
+
+/**
+ * Merge resolved coverage into another, keeping the highest hit count per line
+ * @param {Object<string, Map<number, number>>} target - Coverage to merge into
+ * @param {Object<string, Map<number, number>>} files - Result of resolvePaths().files
+ * @returns {Object<string, Map<number, number>>} target
+ */
+function mergeFiles(target, files) {
+  for (const [file, lines] of Object.entries(files)) {
+    for (const [line, hits] of lines) addLine(target, file, line, hits);
+  }
+  return target;
+}
+
+/**
+ * Compact line numbers into ranges (`3-5, 9`)
+ * @param {number[]} lines - Sorted line numbers
+ * @returns {string}
+ */
+function formatRanges(lines) {
+  const ranges = [];
+  for (const line of lines) {
+    const last = ranges[ranges.length - 1];
+    if (last && line === last[1] + 1) last[1] = line;
+    else ranges.push([line, line]);
+  }
+  return ranges.map(([start, end]) => (start === end ? String(start) : `${start}-${end}`)).join(', ');
+}
+
+/**
+ * Line counts for a set of instrumented lines
+ * @param {Map<number, number>} lines - Line hits
+ * @param {number} [start=1] - First line
+ * @param {number} [end=Infinity] - Last line
+ * @returns {{covered: number, total: number, uncovered: number[]}}
+ */
+function countLines(lines, start = 1, end = Infinity) {
+  let covered = 0;
+  let total = 0;
+  const uncovered = [];
+  for (const [line, hits] of lines) {
+    if (line < start || line > end) continue;
+    total++;
+    if (hits > 0) covered++;
+    else uncovered.push(line);
+  }
+  uncovered.sort((a, b) => a - b);
+  return { covered, total, uncovered };
+}
+
+/**
+ * Percentage with one decimal, or null when nothing is instrumented
+ * @param {number} covered
+ * @param {number} total
+ * @returns {number|null}
+ */
+function percent(covered, total) {
+  return total > 0 ? Math.round((covered / total) * 1000) / 10 : null;
+}
+
+/**
+ * Functions of a map file with the line span each one covers
+ * @param {Object} fileData - Map file entry
+ * @returns {Array<{name: string, symbol: string, line: number, end: number, exported: boolean}>}
+ */
+function functionSpans(fileData) {
+  const symbols = fileData.symbols || {};
+  const starts = [...(symbols.functions || []), ...(symbols.classes || [])]
+    .map(symbol => symbol.line)
+    .filter(Boolean)
+    .sort((a, b) => a - b);
+  return (symbols.functions || []).filter(symbol => symbol.line).map(symbol => {
+    const next = starts.find(line => line > symbol.line);
+    return {
+      name: symbol.receiver ? `${symbol.receiver}.${symbol.name}` : symbol.name,
+      symbol: symbol.name,
+      line: symbol.line,
+      end: next ? next - 1 : Infinity,
+      exported: symbol.exported !== false
+    };
+  });
+}
+
+/**
+ * Summarize coverage per file and per function
+ * Test files and files without instrumented lines are left out. `target`
+ * is the `/test-gen` argument for a function. Functions are ordered least covered first, with
+ * more uncovered lines breaking ties.
+ * @param {Object} map - Repo map
+ * @param {Object<string, Map<number, number>>} files - Coverage keyed by map file
+ * @returns {{totals: Object, files: Object[], functions: Object[]}}
+ */
+function summarize(map, files) {
+  const fileSummaries = [];
+  const functions = [];
+  let covered = 0;
+  let total = 0;
+
+  for (const [file, lines] of Object.entries(files)) {
+    if (isTestFile(file)) continue;
+    const counts = countLines(lines);
+    if (counts.total === 0) continue;
+    covered += counts.covered;
+    total += counts.total;
+    fileSummaries.push({ file, covered: counts.covered, total: counts.total, percent: percent(counts.covered, counts.total) });
+
+    const fileData = map.files && map.files[file];
+    if (!fileData) continue;
+    for (const span of functionSpans(fileData)) {
+      const spanCounts = countLines(lines, span.line, span.end);
+      if (spanCounts.total === 0) continue;
+      functions.push({
+        file,
+        name: span.name,
+        target: `${file}:${span.symbol}`,
+        line: span.line,
+        exported: span.exported,
+        covered: spanCounts.covered,
+        total: spanCounts.total,
+        percent: percent(spanCounts.covered, spanCounts.total),
+        uncovered: formatRanges(spanCounts.uncovered)
+      });
+    }
+  }
+
+  const byCoverage = (a, b) => a.percent - b.percent || (b.total - b.covered) - (a.total - a.covered) || a.file.localeCompare(b.file);
+  fileSummaries.sort(byCoverage);
+  functions.sort((a, b) => byCoverage(a, b) || a.line - b.line);
+
+  return {
+    totals: { covered, total, percent: percent(covered, total), files: fileSummaries.length },
+    files: fileSummaries,
+    functions
+  };
+}
+
+/**
+ * Render a summary as Markdown
+ * @param {Object} summary - Result of summarize, with `format` and `report`
+ * @param {Object} [options]
+ * @param {number} [options.limit=15] - Rows per table
+ * @param {boolean} [options.all] - Include unexported functions
+ * @returns {string}
+ */
+function renderSummary(summary, options = {}) {
+  const limit = options.limit || 15;
+  const functions = summary.functions.filter(item => options.all || item.exported);
+  const lines = [
+    '## Coverage',
+    '',
+    `**Report**: ${summary.report} (${summary.format})`,
+    `**Lines**: ${summary.totals.covered}/${summary.totals.total} (${summary.totals.percent === null ? '-' : `${summary.totals.percent}%`}) across ${summary.totals.files} files`,
+    ''
+  ];
+
+  if (summary.packages) {
+    if (summary.run) lines.splice(lines.length - 1, 0, `**Run**: ${summary.run.summary}`);
+    lines.push('### Packages', '', '| Package | Report | Coverage |', '|---------|--------|----------|');
+    for (const pkg of summary.packages) {
+      const coverage = pkg.totals ? `${pkg.totals.percent === null ? '-' : `${pkg.totals.percent}%`} (${pkg.totals.covered}/${pkg.totals.total})` : pkg.error || 'no report';
+      lines.push(`| ${pkg.name} | ${pkg.report || '-'} | ${coverage} |`);
+    }
+    lines.push('');
+  }
+
+  const under = functions.filter(item => item.percent < 100).slice(0, limit);
+  if (under.length > 0) {
+    lines.push(`### Least-covered ${options.all ? '' : 'exported '}functions`, '', '| Function | File | Coverage | Uncovered lines |', '|----------|------|----------|-----------------|');
+    for (const item of under) {
+      lines.push(`| \`${item.name}\` | ${item.file}:${item.line} | ${item.percent}% (${item.covered}/${item.total}) | ${item.uncovered} |`);
+    }
+    lines.push('');
+  }
+
+  const files = summary.files.filter(item => item.percent < 100).slice(0, limit);
+  if (files.length > 0) {
+    lines.push('### Least-covered files', '', '| File | Coverage |', '|------|----------|');
+    for (const item of files) lines.push(`| ${item.file} | ${item.percent}% (${item.covered}/${item.total}) |`);
+    lines.push('');
+  }
+
+  if (summary.unmatched && summary.unmatched.length > 0) {
+    lines.push(`${summary.unmatched.length} report file(s) not in the repo map: ${summary.unmatched.slice(0, 5).join(', ')}${summary.unmatched.length > 5 ? ', ...' : ''}`);
+  }
+  return `${lines.join('\n').trim()}\n`;
+}
+
+module.exports = {
+  REPORT_PATHS,
+  COVERAGE_COMMANDS,
+  coverageCommand,
+  detectFormat,
+  parseLcov,
+  parseCobertura,
+  parseGoProfile,
+  parseCoveragePy,
+  parseReport,
+  resolvePaths,
+  mergeFiles,
+  formatRanges,
+  functionSpans,
+  summarize,
+  renderSummary
+};
@@ -0,0 +1,34 @@
+/**
+ * C query patterns for ast-grep
+ */
+
+'use strict';
+
+module.exports = {
+  exports: [],
+  functions: [
+    { pattern: '$RET $NAME($$$) { $$$ }', nameVar: 'NAME' },
+    { pattern: '$RET *$NAME($$$) { $$$ }', nameVar: 'NAME' },
+    { pattern: 'static $RET $NAME($$$) { $$$ }', nameVar: 'NAME' },
+    { pattern: 'static inline $RET $NAME($$$) { $$$ }', nameVar: 'NAME' },
+    { pattern: '$RET $NAME($$$);', kind: 'declaration', nameVar: 'NAME' },
+    { pattern: '$RET *$NAME($$$);', kind: 'declaration', nameVar: 'NAME' },
+    { pattern: 'extern $RET $NAME($$$);', kind: 'declaration', nameVar: 'NAME' }
+  ],
+  classes: [],
+  types: [
+    { pattern: 'struct $NAME { $$$ };', kind: 'struct', nameVar: 'NAME' },
+    { pattern: 'typedef struct $$$ $NAME;', kind: 'struct', nameVar: 'NAME' },
+    { pattern: 'union $NAME { $$$ };', kind: 'union', nameVar: 'NAME' },
+    { pattern: 'enum $NAME { $$$ };', kind: 'enum', nameVar: 'NAME' },
+    { pattern: 'typedef enum $$$ $NAME;', kind: 'enum', nameVar: 'NAME' },
+    { pattern: 'typedef $TYPE $NAME;', nameVar: 'NAME' }
+  ],
+  constants: [
+    { pattern: 'const $TYPE $NAME = $$$;', nameVar: 'NAME' },
+    { pattern: 'static const $TYPE $NAME = $$$;', nameVar: 'NAME' }
+  ],
+  imports: [
+    { pattern: '#include $SOURCE', sourceVar: 'SOURCE', kind: 'include' }
+  ]
+};
@@ -14,6 +14,11 @@ const python = require('./python');
 const rust = require('./rust');
 const go = require('./go');
 const java = require('./java');
+const kotlin = require('./kotlin');
+const c = require('./c');
+const cpp = require('./cpp');
+const ruby = require('./ruby');
+const csharp = require('./csharp');
 
 /**
  * Get query patterns for a language
@@ -38,6 +43,20 @@ function getQueriesForLanguage(language) {
       return go;
     case 'java':
       return java;
+    case 'kotlin':
+    case 'kt':
+      return kotlin;
+    case 'c':
+      return c;
+    case 'cpp':
+    case 'c++':
+      return cpp;
+    case 'ruby':
+    case 'rb':
+      return ruby;
+    case 'csharp':
+    case 'cs':
+      return csharp;
     default:
       return null;
   }
@@ -66,6 +85,20 @@ function getSgLanguage(language) {
       return 'go';
     case 'java':
       return 'java';
+    case 'kotlin':
+    case 'kt':
+      return 'kotlin';
+    case 'c':
+      return 'c';
+    case 'cpp':
+    case 'c++':
+      return 'cpp';
+    case 'ruby':
+    case 'rb':
+      return 'ruby';
+    case 'csharp':
+    case 'cs':
+      return 'csharp';
     default:
       return 'javascript';
   }
@@ -0,0 +1,727 @@
+#!/usr/bin/env node
+/**
+ * Benchmarks
+ *
+ * Detects the project's benchmark harnesses (`go test -bench`, Rust
+ * criterion, pytest-benchmark, `vitest bench`), runs them, stores the
+ * results per commit under `.awesome-slash/bench/` (results stored under
+ * `.awsome-slash/bench/` are still read), and compares them with the
+ * results of a baseline ref using Welch's t-test, so a slowdown is only
+ * reported when it is larger than run-to-run noise.
+ *
+ * Usage: node lib/benchmark/index.js [--base REF] [--count N] [--filter PATTERN]
+ * Output: JSON report; exit code 1 when benchmarks cannot run, 2 on regressions
+ *
+ * @module lib/benchmark
+ */
+
+const fs = require('fs');
+const os = require('os');
+const path = require('path');
+
+const { loadConfig } = require('../config');
+const { collectDependencies } = require('../platform/detect-database');
+const exec = require('../utils/exec');
+
+/**
+ * Result directories, the first is written
+ */
+const BENCH_DIRS = ['.awesome-slash/bench', '.awsome-slash/bench'];
+
+/**
+ * Project config key holding comparison settings
+ */
+const CONFIG_KEY = 'benchmark';
+
+/**
+ * Comparison defaults: slowdowns below `threshold` percent or with a p-value
+ * at or above `alpha` are noise; `count` is the number of go test runs
+ */
+const DEFAULTS = { threshold: 5, alpha: 0.05, count: 6 };
+
+/**
+ * Directories skipped when looking for benchmark files
+ */
+const SKIP_DIRS = new Set(['node_modules', 'vendor', 'target', 'dist', 'build', '__pycache__', 'venv']);
+
+/**
+ * Maximum files inspected when looking for benchmark files
+ */
+const MAX_SCAN_FILES = 5000;
+
+/**
+ * Supported harnesses
+ * `parse` turns the command output (stdout, or the JSON file the harness
+ * writes to `outFile`) into named samples or summaries in nanoseconds.
+ */
+const HARNESSES = {
+  go: {
+    label: 'go test -bench',
+    command: ({ filter, count }) => ['go', 'test', '-run', '^$', '-bench', filter || '.', '-benchmem', '-count', String(count), './...'],
+    parse: ({ stdout }) => parseGoBench(stdout)
+  },
+  criterion: {
+    label: 'cargo bench (criterion)',
+    command: ({ filter }) => ['cargo', 'bench', ...(filter ? ['--', filter] : [])],
+    parse: ({ dir, startedAt }) => readCriterionResults(path.join(dir, 'target', 'criterion'), startedAt)
+  },
+  'pytest-benchmark': {
+    label: 'pytest-benchmark',
+    command: ({ filter, outFile, python }) => [python, '-m', 'pytest', '--benchmark-only', `--benchmark-json=${outFile}`, ...(filter ? ['-k', filter] : [])],
+    parse: ({ output }) => parsePytestBenchmark(output)
+  },
+  vitest: {
+    label: 'vitest bench',
+    command: ({ filter, outFile, runner }) => [...runner, 'vitest', 'bench', '--run', '--outputJson', outFile, ...(filter ? ['-t', filter] : [])],
+    parse: ({ output }) => parseVitestBench(output)
+  }
+};
+
+/**
+ * Run a command and return stdout
+ * @param {string} basePath - Working directory
+ * @param {string[]} argv - Command and arguments
+ * @returns {string|null} null when the command fails
+ */
+function run(basePath, argv) {
+  return exec.run(basePath, argv);
+}
+
+/**
+ * Read a file
+ * @param {string} file - Absolute path
+ * @returns {string|null}
+ */
+function readFile(file) {
+  try {
+    return fs.readFileSync(file, 'utf8');
+  } catch {
+    return null;
+  }
+}
+
+/**
+ * Project files matching a name pattern, skipping dependency and build directories
+ * @param {string} basePath - Project root
+ * @param {RegExp} pattern - File name pattern
+ * @returns {string[]} Relative paths
+ */
+function findFiles(basePath, pattern) {
+  const found = [];
+  const pending = ['.'];
+  let scanned = 0;
+  while (pending.length && scanned < MAX_SCAN_FILES) {
+    const dir = pending.shift();
+    let entries;
+    try {
+      entries = fs.readdirSync(path.join(basePath, dir), { withFileTypes: true });
+    } catch {
+      continue;
+    }
+    for (const entry of entries) {
+      const rel = dir === '.' ? entry.name : `${dir}/${entry.name}`;
+      if (entry.isDirectory()) {
+        if (!entry.name.startsWith('.') && !SKIP_DIRS.has(entry.name)) pending.push(rel);
+      } else if (++scanned <= MAX_SCAN_FILES && pattern.test(entry.name)) {
+        found.push(rel);
+      }
+    }
+  }
+  return found.sort();
+}
+
+/**
+ * Mean, sample standard deviation, and median of samples
+ * @param {number[]} samples - Measurements
+ * @returns {{mean: number, sd: number, n: number, median: number, min: number}}
+ */
+function summarize(samples) {
+  const n = samples.length;
+  const mean = samples.reduce((sum, value) => sum + value, 0) / n;
+  const variance = n > 1 ? samples.reduce((sum, value) => sum + (value - mean) ** 2, 0) / (n - 1) : 0;
+  const sorted = [...samples].sort((a, b) => a - b);
+  const median = n % 2 ? sorted[(n - 1) / 2] : (sorted[n / 2 - 1] + sorted[n / 2]) / 2;
+  return { mean, sd: Math.sqrt(variance), n, median, min: sorted[0] };
+}
+
+/**
+ * Benchmarks from `go test -bench` output, one sample per `-count` run
+ * Names drop the `-N` GOMAXPROCS suffix and are prefixed with the package.
+ * @param {string} output - go test stdout
+ * @returns {Array<{id: string, harness: string, mean: number, sd: number, n: number, median: number, min: number, bytesPerOp?: number, allocsPerOp?: number}>}
+ */
+function parseGoBench(output) {
+  const samples = new Map();
+  let pkg = null;
+  for (const line of String(output || '').split('\n')) {
+    const pkgLine = line.match(/^pkg:\s+(\S+)/);
+    if (pkgLine) {
+      pkg = pkgLine[1];
+      continue;
+    }
+    const match = line.match(/^(Benchmark\S+?)(?:-\d+)?\s+\d+\s+([\d.]+) ns\/op(.*)$/);
+    if (!match) continue;
+    const id = pkg ? `${pkg}.${match[1]}` : match[1];
+    if (!samples.has(id)) samples.set(id, { values: [], bytes: [], allocs: [] });
+    const entry = samples.get(id);
+    entry.values.push(Number(match[2]));
+    const bytes = match[3].match(/([\d.]+) B\/op/);
+    const allocs = match[3].match(/([\d.]+) allocs\/op/);
+    if (bytes) entry.bytes.push(Number(bytes[1]));
+    if (allocs) entry.allocs.push(Number(allocs[1]));
+  }
+  return [...samples].map(([id, entry]) => ({
+    id,
+    harness: 'go',
+    ...summarize(entry.values),
+    ...(entry.bytes.length ? { bytesPerOp: Math.max(...entry.bytes) } : {}),
+    ...(entry.allocs.length ? { allocsPerOp: Math.max(...entry.allocs) } : {})
+  }));
+}
+
+/**
+ * Benchmarks criterion wrote to `target/criterion` since a run started
+ * Each `new/sample.json` holds total times per iteration count.
+ * @param {string} dir - target/criterion directory
+ * @param {number} [since=0] - Ignore results older than this (ms since epoch)
+ * @returns {Object[]}
+ */
+function readCriterionResults(dir, since = 0) {
+  const results = [];
+  const visit = current => {
+    let entries;
+    try {
+      entries = fs.readdirSync(current, { withFileTypes: true });
+    } catch {
+      return;
+    }
+    if (path.basename(current) === 'new' && entries.some(entry => entry.name === 'sample.json')) {
+      const sampleFile = path.join(current, 'sample.json');
+      if (fs.statSync(sampleFile).mtimeMs < since) return;
+      let sample;
+      let info;
+      try {
+        sample = JSON.parse(readFile(sampleFile));
+        info = JSON.parse(readFile(path.join(current, 'benchmark.json')) || '{}');
+      } catch {
+        return;
+      }
+      const times = (sample.iters || []).map((iters, i) => sample.times[i] / iters).filter(Number.isFinite);
+      const id = info.full_id || exec.relativePath(dir, path.dirname(current));
+      if (times.length) results.push({ id, harness: 'criterion', ...summarize(times) });
+      return;
+    }
+    for (const entry of entries) {
+      if (entry.isDirectory() && entry.name !== 'report' && entry.name !== 'base') visit(path.join(current, entry.name));
+    }
+  };
+  visit(dir);
+  return results.sort((a, b) => a.id.localeCompare(b.id));
+}
+
+/**
+ * Benchmarks from a pytest-benchmark JSON report (times in seconds)
+ * @param {string} output - Report content
+ * @returns {Object[]}
+ */
+function parsePytestBenchmark(output) {
+  let report;
+  try {
+    report = JSON.parse(output);
+  } catch {
+    return [];
+  }
+  return (report.benchmarks || []).filter(bench => bench.stats).map(bench => {
+    const { stats } = bench;
+    const summary = Array.isArray(stats.data) && stats.data.length
+      ? summarize(stats.data.map(seconds => seconds * 1e9))
+      : { mean: stats.mean * 1e9, sd: (stats.stddev || 0) * 1e9, n: stats.rounds || 1, median: (stats.median || stats.mean) * 1e9, min: (stats.min || stats.mean) * 1e9 };
+    return { id: bench.fullname || bench.name, harness: 'pytest-benchmark', ...summary };
+  });
+}
+
+/**
+ * Benchmarks from a `vitest bench --outputJson` report (times in milliseconds)
+ * @param {string} output - Report content
+ * @returns {Object[]}
+ */
+function parseVitestBench(output) {
+  let report;
+  try {
+    report = JSON.parse(output);
+  } catch {
+    return [];
+  }
+  const results = [];
+  for (const file of report.files || []) {
+    for (const group of file.groups || []) {
+      for (const bench of group.benchmarks || []) {
+        if (!Number.isFinite(bench.mean)) continue;
+        const summary = Array.isArray(bench.samples) && bench.samples.length
+          ? summarize(bench.samples.map(ms => ms * 1e6))
+          : { mean: bench.mean * 1e6, sd: (bench.sd || 0) * 1e6, n: bench.sampleCount || 1, median: (bench.median || bench.mean) * 1e6, min: (bench.min || bench.mean) * 1e6 };
+        results.push({ id: `${group.fullName} > ${bench.name}`, harness: 'vitest', ...summary });
+      }
+    }
+  }
+  return results;
+}
+
+/**
+ * Benchmark harnesses in the project
+ * @param {string} basePath - Project root
+ * @returns {Promise<Array<{harness: string, label: string, files: string[]}>>}
+ */
+async function detectHarnesses(basePath) {
+  const dependencies = await collectDependencies(basePath);
+  const has = (ecosystem, name) => dependencies.some(dep => dep.ecosystem === ecosystem && dep.name === name);
+  const found = [];
+
+  if (fs.existsSync(path.join(basePath, 'go.mod'))) {
+    const files = findFiles(basePath, /_test\.go$/)
+      .filter(file => /^func Benchmark\w*\(\w+ \*testing\.B\)/m.test(readFile(path.join(basePath, file)) || ''));
+    if (files.length) found.push({ harness: 'go', files });
+  }
+  if (has('rust', 'criterion')) {
+    found.push({ harness: 'criterion', files: findFiles(path.join(basePath, 'benches'), /\.rs$/).map(file => `benches/${file}`) });
+  }
+  if (has('python', 'pytest-benchmark')) {
+    const files = findFiles(basePath, /^(?:test_.*|.*_test)\.py$/)
+      .filter(file => /\bbenchmark\b/.test(readFile(path.join(basePath, file)) || ''));
+    if (files.length) found.push({ harness: 'pytest-benchmark', files });
+  }
+  if (has('npm', 'vitest')) {
+    const files = findFiles(basePath, /\.bench\.[cm]?[jt]sx?$/);
+    if (files.length) found.push({ harness: 'vitest', files });
+  }
+  return found.map(entry => ({ ...entry, label: HARNESSES[entry.harness].label }));
+}
+
+/**
+ * Read comparison settings from the project config
+ * @param {string} basePath - Project root
+ * @returns {{threshold: number, alpha: number, count: number, error: string|null}}
+ */
+function readSettings(basePath) {
+  const { config, file, error } = loadConfig(basePath);
+  const settings = { ...DEFAULTS, error };
+  const value = config[CONFIG_KEY];
+  if (error || value === undefined) return settings;
+
+  if (!value || typeof value !== 'object' || Array.isArray(value)) {
+    return { ...settings, error: `${file}: ${CONFIG_KEY} must be an object` };
+  }
+  if (value.threshold !== undefined) {
+    if (typeof value.threshold !== 'number' || value.threshold < 0) {
+      return { ...settings, error: `${file}: ${CONFIG_KEY}.threshold must be a non-negative percentage` };
+    }
+    settings.threshold = value.threshold;
+  }
+  if (value.alpha !== undefined) {
+    if (typeof value.alpha !== 'number' || value.alpha <= 0 || value.alpha >= 1) {
+      return { ...settings, error: `${file}: ${CONFIG_KEY}.alpha must be between 0 and 1` };
+    }
+    settings.alpha = value.alpha;
+  }
+  if (value.count !== undefined) {
+    if (!Number.isInteger(value.count) || value.count < 1) {
+      return { ...settings, error: `${file}: ${CONFIG_KEY}.count must be a positive integer` };
+    }
+    settings.count = value.count;
+  }
+  return settings;
+}
+
+/**
+ * Run the detected harnesses
+ * @param {string} dir - Directory to run in (the project or a worktree of it)
+ * @param {Array<{harness: string}>} harnesses - Result of detectHarnesses
+ * @param {Object} [options]
+ * @param {string} [options.filter] - Benchmark name filter
+ * @param {number} [options.count] - go test -count
+ * @param {Function} [options.run] - Command runner
+ * @returns {{benchmarks: Object[], errors: string[]}}
+ */
+function runHarnesses(dir, harnesses, options = {}) {
+  const runCommand = options.run || run;
+  const benchmarks = [];
+  const errors = [];
+  const venv = ['.venv/bin/python', 'venv/bin/python'].find(file => fs.existsSync(path.join(dir, file)));
+  const runner = fs.existsSync(path.join(dir, 'pnpm-lock.yaml')) ? ['pnpm', 'exec']
+    : fs.existsSync(path.join(dir, 'yarn.lock')) ? ['yarn'] : ['npx'];
+
+  for (const { harness } of harnesses) {
+    const outDir = fs.mkdtempSync(path.join(os.tmpdir(), 'bench-'));
+    const outFile = path.join(outDir, 'results.json');
+    try {
+      const startedAt = Date.now();
+      const argv = HARNESSES[harness].command({
+        filter: options.filter,
+        count: options.count || DEFAULTS.count,
+        outFile,
+        python: venv ? path.join(dir, venv) : 'python3',
+        runner
+      });
+      const stdout = runCommand(dir, argv);
+      const results = HARNESSES[harness].parse({ stdout, output: readFile(outFile), dir, startedAt });
+      if (!results.length) {
+        errors.push(`${HARNESSES[harness].label}: ${stdout === null ? 'command failed' : 'no results'} (${argv.join(' ')})`);
+      }
+      benchmarks.push(...results);
+    } finally {
+      fs.rmSync(outDir, { recursive: true, force: true });
+    }
+  }
+  return { benchmarks, errors };
+}
+
+/**
+ * Directory results are stored in
+ * @param {string} basePath - Project root
+ * @returns {string}
+ */
+function benchDir(basePath) {
+  return path.join(basePath, BENCH_DIRS[0]);
+}
+
+/**
+ * Stored results for a commit
+ * @param {string} basePath - Project root
+ * @param {string} commit - Full commit SHA
+ * @returns {Object|null}
+ */
+function loadResults(basePath, commit) {
+  for (const dir of BENCH_DIRS) {
+    const content = readFile(path.join(basePath, dir, `${commit}.json`));
+    if (!content) continue;
+    try {
+      return JSON.parse(content);
+    } catch {
+      return null;
+    }
+  }
+  return null;
+}
+
+/**
+ * Store results for a commit; a dirty working tree is stored as `<sha>-dirty.json`
+ * so it never serves as a baseline
+ * @param {string} basePath - Project root
+ * @param {Object} results - `{commit, dirty, ...}`
+ * @returns {string} Relative path of the written file
+ */
+function saveResults(basePath, results) {
+  const dir = benchDir(basePath);
+  fs.mkdirSync(dir, { recursive: true });
+  const file = path.join(dir, `${results.commit}${results.dirty ? '-dirty' : ''}.json`);
+  fs.writeFileSync(file, JSON.stringify(results, null, 2) + '\n');
+  return exec.relativePath(basePath, file);
+}
+
+/**
+ * Natural log of the gamma function (Lanczos approximation)
+ * @param {number} x - Positive number
+ * @returns {number}
+ */
+function logGamma(x) {
+  const c = [76.18009172947146, -86.50532032941677, 24.01409824083091, -1.231739572450155, 0.1208650973866179e-2, -0.5395239384953e-5];
+  let y = x;
+  const tmp = x + 5.5 - (x + 0.5) * Math.log(x + 5.5);
+  let series = 1.000000000190015;
+  for (const coefficient of c) series += coefficient / ++y;
+  return -tmp + Math.log(2.5066282746310005 * series / x);
+}
+
+/**
+ * Regularized incomplete beta function I_x(a, b)
+ * @param {number} x - Upper limit in [0, 1]
+ * @param {number} a - Shape
+ * @param {number} b - Shape
+ * @returns {number}
+ */
+function incompleteBeta(x, a, b) {
+  if (x <= 0) return 0;
+  if (x >= 1) return 1;
+  if (x > (a + 1) / (a + b + 2)) return 1 - incompleteBeta(1 - x, b, a);
+
+  const front = Math.exp(logGamma(a + b) - logGamma(a) - logGamma(b) + a * Math.log(x) + b * Math.log(1 - x)) / a;
+  // Lentz's continued fraction
+  const tiny = 1e-30;
+  let f = 1;
+  let c = 1;
+  let d = 0;
+  for (let i = 0; i <= 200; i++) {
+    const m = Math.floor(i / 2);
+    let numerator;
+    if (i === 0) numerator = 1;
+    else if (i % 2 === 0) numerator = (m * (b - m) * x) / ((a + 2 * m - 1) * (a + 2 * m));
+    else numerator = -((a + m) * (a + b + m) * x) / ((a + 2 * m) * (a + 2 * m + 1));
+    d = 1 + numerator * d;
+    d = Math.abs(d) < tiny ? tiny : d;
+    d = 1 / d;
+    c = 1 + numerator / c;
+    c = Math.abs(c) < tiny ? tiny : c;
+    const step = c * d;
+    f *= step;
+    if (Math.abs(1 - step) < 1e-10) break;
+  }
+  return front * (f - 1);
+}
+
+/**
+ * Welch's t-test on two summaries
+ * @param {{mean: number, sd: number, n: number}} a - First sample
+ * @param {{mean: number, sd: number, n: number}} b - Second sample
+ * @returns {{t: number, df: number, pValue: number}|null} Two-sided p-value; null with fewer than two samples on a side
+ */
+function welchTTest(a, b) {
+  if (a.n < 2 || b.n < 2) return null;
+  const va = (a.sd ** 2) / a.n;
+  const vb = (b.sd ** 2) / b.n;
+  if (va + vb === 0) return { t: a.mean === b.mean ? 0 : Infinity, df: a.n + b.n - 2, pValue: a.mean === b.mean ? 1 : 0 };
+  const t = (b.mean - a.mean) / Math.sqrt(va + vb);
+  const df = (va + vb) ** 2 / ((va ** 2) / (a.n - 1) + (vb ** 2) / (b.n - 1));
+  return { t, df, pValue: incompleteBeta(df / (df + t * t), df / 2, 0.5) };
+}
+
+/**
+ * Compare current benchmarks with a baseline
+ * A change is a regression or improvement when it is at least `threshold`
+ * percent and Welch's t-test puts it below `alpha`; with fewer than two
+ * samples on a side a large change is `inconclusive`.
+ * @param {Object[]} baseline - Baseline benchmarks
+ * @param {Object[]} current - Current benchmarks
+ * @param {Object} [options]
+ * @param {number} [options.threshold=5] - Minimum change in percent
+ * @param {number} [options.alpha=0.05] - Significance level
+ * @returns {Array<{id: string, harness: string, status: string, change: number|null, pValue: number|null, base: Object|null, current: Object|null}>}
+ */
+function compareResults(baseline, current, options = {}) {
+  const threshold = options.threshold === undefined ? DEFAULTS.threshold : options.threshold;
+  const alpha = options.alpha || DEFAULTS.alpha;
+  const key = bench => `${bench.harness}:${bench.id}`;
+  const baseById = new Map(baseline.map(bench => [key(bench), bench]));
+  const pick = bench => ({ mean: bench.mean, sd: bench.sd, n: bench.n });
+  const rows = [];
+
+  for (const bench of current) {
+    const base = baseById.get(key(bench));
+    baseById.delete(key(bench));
+    if (!base) {
+      rows.push({ id: bench.id, harness: bench.harness, status: 'added', change: null, pValue: null, base: null, current: pick(bench) });
+      continue;
+    }
+    const change = base.mean ? ((bench.mean - base.mean) / base.mean) * 100 : 0;
+    const test = welchTTest(base, bench);
+    let status = 'unchanged';
+    if (Math.abs(change) >= threshold) {
+      if (!test) status = 'inconclusive';
+      else if (test.pValue < alpha) status = change > 0 ? 'regression' : 'improvement';
+    }
+    rows.push({ id: bench.id, harness: bench.harness, status, change, pValue: test ? test.pValue : null, base: pick(base), current: pick(bench) });
+  }
+  for (const base of baseById.values()) {
+    rows.push({ id: base.id, harness: base.harness, status: 'removed', change: null, pValue: null, base: pick(base), current: null });
+  }
+
+  const order = ['regression', 'inconclusive', 'improvement', 'unchanged', 'added', 'removed'];
+  return rows.sort((a, b) => order.indexOf(a.status) - order.indexOf(b.status) ||
+    Math.abs(b.change || 0) - Math.abs(a.change || 0) || a.id.localeCompare(b.id));
+}
+
+/**
+ * Run the baseline commit's benchmarks in a temporary worktree
+ * `node_modules` is linked from the project so JavaScript harnesses resolve.
+ * @param {string} basePath - Project root
+ * @param {string} commit - Baseline commit
+ * @param {Object} options - runHarnesses options plus `harnesses`
+ * @returns {{benchmarks: Object[], errors: string[]}|null} null when the worktree cannot be created
+ */
+function runAtCommit(basePath, commit, options) {
+  const runCommand = options.run || run;
+  const worktree = path.join(fs.mkdtempSync(path.join(os.tmpdir(), 'bench-base-')), 'tree');
+  if (runCommand(basePath, ['git', 'worktree', 'add', '--detach', worktree, commit]) === null) return null;
+  try {
+    const modules = path.join(basePath, 'node_modules');
+    if (fs.existsSync(modules) && !fs.existsSync(path.join(worktree, 'node_modules'))) {
+      fs.symlinkSync(modules, path.join(worktree, 'node_modules'), 'dir');
+    }
+    return runHarnesses(worktree, options.harnesses, options);
+  } finally {
+    runCommand(basePath, ['git', 'worktree', 'remove', '--force', worktree]);
+    fs.rmSync(path.dirname(worktree), { recursive: true, force: true });
+  }
+}
+
+/**
+ * Run benchmarks, store them, and compare with a baseline ref
+ * @param {string} basePath - Project root
+ * @param {Object} [options]
+ * @param {string} [options.base='origin/HEAD'] - Baseline ref; its merge base with HEAD is used
+ * @param {string} [options.filter] - Benchmark name filter
+ * @param {number} [options.count] - go test -count
+ * @param {boolean} [options.runBaseline=true] - Run the baseline in a worktree when it has no stored results
+ * @param {Function} [options.run] - Command runner, for tests
+ * @returns {Promise<Object>} `{success, commit, harnesses, benchmarks, file, baseline, comparison, regressions, errors, settings}`
+ */
+async function runBenchmarks(basePath, options = {}) {
+  const runCommand = options.run || run;
+  const settings = readSettings(basePath);
+  if (settings.error) return { success: false, error: settings.error };
+
+  const harnesses = await detectHarnesses(basePath);
+  if (!harnesses.length) {
+    return { success: false, error: 'No benchmarks found. Looked for go test Benchmark functions, criterion, pytest-benchmark, and vitest *.bench files.' };
+  }
+
+  const commit = (runCommand(basePath, ['git', 'rev-parse', 'HEAD']) || '').trim();
+  if (!commit) return { success: false, error: 'Not a git repository with commits.' };
+  const dirty = Boolean((runCommand(basePath, ['git', 'status', '--porcelain', '--untracked-files=no']) || '').trim());
+  const runOptions = { filter: options.filter, count: options.count || settings.count, run: runCommand };
+
+  const current = runHarnesses(basePath, harnesses, runOptions);
+  if (!current.benchmarks.length) {
+    return { success: false, error: `No benchmark results. ${current.errors.join('; ')}` };
+  }
+  const results = {
+    commit,
+    dirty,
+    createdAt: new Date().toISOString(),
+    filter: options.filter || null,
+    harnesses: harnesses.map(entry => entry.harness),
+    benchmarks: current.benchmarks
+  };
+  const file = saveResults(basePath, results);
+
+  const baseRef = options.base || 'origin/HEAD';
+  const baseCommit = (runCommand(basePath, ['git', 'merge-base', baseRef, 'HEAD']) || '').trim() || null;
+  const baseline = { ref: baseRef, commit: baseCommit, source: null };
+  const errors = [...current.errors];
+  let baseBenchmarks = null;
+
+  if (!baseCommit) {
+    errors.push(`Could not resolve ${baseRef}; nothing to compare against`);
+  } else if (baseCommit === commit && !dirty) {
+    baseline.source = 'self';
+  } else {
+    const stored = loadResults(basePath, baseCommit);
+    if (stored && (stored.filter || null) === (options.filter || null)) {
+      baseline.source = 'stored';
+      baseBenchmarks = stored.benchmarks;
+    } else if (options.runBaseline !== false) {
+      const ran = runAtCommit(basePath, baseCommit, { ...runOptions, harnesses });
+      if (ran && ran.benchmarks.length) {
+        baseline.source = 'ran';
+        baseBenchmarks = ran.benchmarks;
+        saveResults(basePath, { ...results, commit: baseCommit, dirty: false, createdAt: new Date().toISOString(), benchmarks: ran.benchmarks });
+      } else {
+        errors.push(`Baseline ${baseCommit.slice(0, 7)} produced no results${ran ? `: ${ran.errors.join('; ')}` : ' (git worktree failed)'}`);
+      }
+    }
+  }
+
+  const comparison = baseBenchmarks
+    ? compareResults(baseBenchmarks, current.benchmarks, { threshold: settings.threshold, alpha: settings.alpha })
+    : null;
+
+  return {
+    success: true,
+    commit,
+    dirty,
+    harnesses,
+    benchmarks: current.benchmarks,
+    file,
+    baseline,
+    comparison,
+    regressions: comparison ? comparison.filter(row => row.status === 'regression') : [],
+    errors,
+    settings: { threshold: settings.threshold, alpha: settings.alpha, count: runOptions.count }
+  };
+}
+
+/**
+ * Format nanoseconds with a readable unit
+ * @param {number} ns - Duration in nanoseconds
+ * @returns {string}
+ */
+function formatDuration(ns) {
+  const units = [[1e9, 's'], [1e6, 'ms'], [1e3, 'µs']];
+  const [scale, unit] = units.find(([size]) => ns >= size) || [1, 'ns'];
+  const value = ns / scale;
+  return `${value >= 100 ? value.toFixed(0) : value >= 10 ? value.toFixed(1) : value.toFixed(2)} ${unit}`;
+}
+
+/**
+ * Render the report as markdown
+ * @param {Object} report - Result of runBenchmarks
+ * @returns {string}
+ */
+function renderReport(report) {
+  const lines = ['## Benchmarks', ''];
+  lines.push(`**Harnesses**: ${report.harnesses.map(entry => entry.label).join(', ')}`);
+  lines.push(`**Commit**: ${report.commit.slice(0, 7)}${report.dirty ? ' (uncommitted changes)' : ''} | **Results**: ${report.file}`);
+  const { baseline } = report;
+  const source = { stored: 'stored results', ran: 'ran in a worktree', self: 'same commit' }[baseline.source];
+  lines.push(`**Baseline**: ${baseline.ref}${baseline.commit ? ` (${baseline.commit.slice(0, 7)}, ${source || 'no results'})` : ''}`);
+
+  if (report.comparison) {
+    const count = status => report.comparison.filter(row => row.status === status).length;
+    lines.push(`**Regressions**: ${count('regression')} | **Improvements**: ${count('improvement')} | **Unchanged**: ${count('unchanged')}`);
+    lines.push(`Changes under ${report.settings.threshold}% or with p ≥ ${report.settings.alpha} count as noise.`, '');
+    lines.push('| Benchmark | Baseline | Current | Change | p | Status |', '|-----------|----------|---------|--------|---|--------|');
+    for (const row of report.comparison) {
+      const time = side => (side ? `${formatDuration(side.mean)} ± ${formatDuration(side.sd)}` : '-');
+      const change = row.change === null ? '-' : `${row.change > 0 ? '+' : ''}${row.change.toFixed(1)}%`;
+      const p = row.pValue === null ? '-' : row.pValue < 0.001 ? '<0.001' : row.pValue.toFixed(3);
+      lines.push(`| \`${row.id}\` | ${time(row.base)} | ${time(row.current)} | ${change} | ${p} | ${row.status} |`);
+    }
+  } else {
+    lines.push('');
+    lines.push('| Benchmark | Mean | ± | Samples |', '|-----------|------|---|---------|');
+    for (const bench of report.benchmarks) {
+      lines.push(`| \`${bench.id}\` | ${formatDuration(bench.mean)} | ${formatDuration(bench.sd)} | ${bench.n} |`);
+    }
+  }
+  lines.push('');
+  if (report.errors.length) lines.push(`**Errors**: ${report.errors.join('; ')}`, '');
+  if (report.regressions.length) {
+    lines.push(`${report.regressions.length} benchmark${report.regressions.length === 1 ? '' : 's'} got significantly slower than ${baseline.ref}.`);
+  }
+  return lines.join('\n');
+}
+
+if (require.main === module) {
+  const args = process.argv.slice(2);
+  const value = flag => (args.includes(flag) ? args[args.indexOf(flag) + 1] : undefined);
+  runBenchmarks(process.cwd(), {
+    base: value('--base'),
+    count: Number(value('--count')) || undefined,
+    filter: value('--filter')
+  }).then(report => {
+    const indent = process.stdout.isTTY ? 2 : 0;
+    console.log(JSON.stringify(report, null, indent));
+    if (!report.success) process.exitCode = 1;
+    else if (report.regressions.length) process.exitCode = 2;
+  });
+}
+
+module.exports = {
+  BENCH_DIRS,
+  CONFIG_KEY,
+  DEFAULTS,
+  HARNESSES,
+  summarize,
+  parseGoBench,
+  readCriterionResults,
+  parsePytestBenchmark,
+  parseVitestBench,
+  detectHarnesses,
+  readSettings,
+  runHarnesses,
+  loadResults,
+  saveResults,
+  welchTTest,
+  compareResults,
+  runBenchmarks,
+  renderReport
+};
@@ -17,6 +17,9 @@
 
 const path = require('path');
 const fs = require('fs');
+const { createLogger } = require('../utils/logger');
+
+const log = createLogger('cross-platform');
 
 /**
  * Platform detection and configuration
@@ -94,12 +97,12 @@ const TOOL_SCHEMA_GUIDELINES = {
 function createToolDefinition(name, description, properties = {}, required = []) {
   // Validate name
   if (!TOOL_SCHEMA_GUIDELINES.namingPattern.test(name)) {
-    console.warn(`Tool name "${name}" should be snake_case`);
+    log.warn(`Tool name "${name}" should be snake_case`);
   }
 
   // Warn if description too long
   if (description.length > TOOL_SCHEMA_GUIDELINES.maxDescriptionLength) {
-    console.warn(`Tool "${name}" description exceeds ${TOOL_SCHEMA_GUIDELINES.maxDescriptionLength} chars`);
+    log.warn(`Tool "${name}" description exceeds ${TOOL_SCHEMA_GUIDELINES.maxDescriptionLength} chars`);
   }
 
   return {
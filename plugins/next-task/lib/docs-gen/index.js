@@ -0,0 +1,576 @@
+#!/usr/bin/env node
+/**
+ * API Reference Generator
+ *
+ * Turns the repo map into markdown reference pages, one per package
+ * (directory): the package doc, every exported type, function, and
+ * constant with its declaration and doc comment read from the source, and
+ * links to the packages it imports and the ones importing it. Doc comments
+ * are read at generation time (JSDoc/Javadoc blocks, `//`, `///`, and `#`
+ * comment runs, Python docstrings), so the map does not carry them.
+ *
+ * Usage: node lib/docs-gen/index.js [--out DIR] [--write]
+ * Output: JSON summary of the pages (written to DIR, default docs/api, with --write)
+ *
+ * @module lib/docs-gen
+ */
+
+const fs = require('fs');
+const path = require('path');
+
+const { buildDependencyGraph } = require('../repo-map/graph');
+const { isTestFile } = require('../repo-map/testgen');
+
+const OUTPUT_DIR = 'docs/api';
+
+/**
+ * First line of every generated page; pages carrying it are replaced or
+ * removed on the next write, any other file in the directory is left alone
+ */
+const GENERATED_MARKER = '<!-- Generated by /docs-gen from the repo map. Do not edit; regenerate instead. -->';
+
+/**
+ * Page sections and the symbol categories they list
+ */
+const SECTIONS = [
+  { title: 'Types', categories: ['classes', 'types'] },
+  { title: 'Functions', categories: ['functions'] },
+  { title: 'Constants', categories: ['constants'] }
+];
+
+const CATEGORY_KINDS = { classes: 'class', types: 'type', functions: 'function', constants: 'constant' };
+
+/**
+ * Code fence language per map language
+ */
+const FENCE_LANGUAGES = { javascript: 'js', typescript: 'ts', python: 'python', go: 'go', rust: 'rust', java: 'java', kotlin: 'kotlin', ruby: 'ruby', csharp: 'csharp', c: 'c', cpp: 'cpp' };
+
+const HASH_COMMENT_LANGUAGES = new Set(['python', 'ruby']);
+
+/**
+ * Lines above a declaration that sit between it and its doc comment
+ * (decorators, annotations, Rust and C# attributes)
+ */
+const ATTRIBUTE_LINE = /^\s*(?:@[\w.]+|#\[|\[[A-Z]\w*)/;
+
+/**
+ * Leading comments that are license headers rather than documentation
+ */
+const LICENSE_HEADER = /^(?:Copyright\b|\(c\)|SPDX-License-Identifier|Licensed under|This Source Code Form)/i;
+
+/**
+ * Read a project file as lines
+ * @param {string} basePath - Project root
+ * @param {string} file - Relative path
+ * @returns {string[]|null}
+ */
+function readLines(basePath, file) {
+  try {
+    return fs.readFileSync(path.join(basePath, file), 'utf8').split(/\r?\n/);
+  } catch {
+    return null;
+  }
+}
+
+/**
+ * Clean comment text: drop comment markers and indentation, stop at block tags
+ * JSDoc/Javadoc tags (`@param`, `@returns`) and C# XML tags are left out;
+ * `@deprecated` is reported separately.
+ * @param {string[]} raw - Comment lines
+ * @returns {{text: string, deprecated: boolean}|null}
+ */
+function cleanComment(raw) {
+  const lines = raw.map(line => line
+    .replace(/^\s*\/\*\*?/, '')
+    .replace(/\*\/\s*$/, '')
+    .replace(/^\s*(?:\/\/[/!]?|#|\*(?!\*))\s?/, '')
+    .replace(/<\/?(?:summary|remarks|para|returns|param[^>]*|see[^>]*|c|code)>/g, '')
+    .replace(/\s+$/, ''));
+  const deprecated = lines.some(line => /^\s*@deprecated\b|^Deprecated:/.test(line.trim()));
+  const tagAt = lines.findIndex(line => /^\s*@\w+/.test(line));
+  const body = (tagAt === -1 ? lines : lines.slice(0, tagAt));
+  while (body.length && !body[0].trim()) body.shift();
+  while (body.length && !body[body.length - 1].trim()) body.pop();
+  // The first line sets no indentation: docstrings start right after the quotes
+  const indent = Math.min(...body.slice(1).filter(line => line.trim()).map(line => line.match(/^\s*/)[0].length));
+  const text = body.map((line, i) => (i === 0 ? line.trim() : line.slice(Number.isFinite(indent) ? indent : 0))).join('\n').trim();
+  return text || deprecated ? { text, deprecated } : null;
+}
+
+/**
+ * Python docstring starting at a line, if there is one
+ * @param {string[]} lines - File lines
+ * @param {number} start - Index of the first line to look at
+ * @returns {string[]|null} Docstring lines without the quotes
+ */
+function docstringAt(lines, start) {
+  let i = start;
+  while (i < lines.length && !lines[i].trim()) i++;
+  const open = i < lines.length && lines[i].trim().match(/^[rRuU]?("""|''')/);
+  if (!open) return null;
+  const quote = open[1];
+  const first = lines[i].trim().slice(open[0].length);
+  if (first.includes(quote)) return [first.slice(0, first.indexOf(quote))];
+  const collected = [first];
+  for (i++; i < lines.length; i++) {
+    const end = lines[i].indexOf(quote);
+    if (end !== -1) {
+      collected.push(lines[i].slice(0, end));
+      return collected;
+    }
+    collected.push(lines[i]);
+  }
+  return collected;
+}
+
+/**
+ * Comment block ending right above a line
+ * @param {string[]} lines - File lines
+ * @param {number} index - Index of the declaration line
+ * @param {string} language - Map language
+ * @returns {string[]|null}
+ */
+function commentAbove(lines, index, language) {
+  let i = index - 1;
+  while (i >= 0 && ATTRIBUTE_LINE.test(lines[i]) && !(HASH_COMMENT_LANGUAGES.has(language) && /^\s*#/.test(lines[i]))) i--;
+  if (i < 0) return null;
+
+  const line = lines[i].trim();
+  if (line.endsWith('*/')) {
+    let start = i;
+    while (start >= 0 && !lines[start].includes('/*')) start--;
+    return start < 0 ? null : lines.slice(start, i + 1);
+  }
+  const prefix = HASH_COMMENT_LANGUAGES.has(language) ? /^\s*#(?![!\[])/ : /^\s*\/\/(?!\s*(?:eslint|@ts-|prettier|nolint|go:))/;
+  if (!prefix.test(lines[i])) return null;
+  let start = i;
+  while (start > 0 && prefix.test(lines[start - 1])) start--;
+  return lines.slice(start, i + 1);
+}
+
+/**
+ * Doc comment of the declaration at a line
+ * Python docstrings win over comments above the `def`/`class`.
+ * @param {string[]} lines - File lines
+ * @param {number} line - 1-based declaration line
+ * @param {string} language - Map language
+ * @returns {{text: string, deprecated: boolean}|null}
+ */
+function readDocComment(lines, line, language) {
+  const index = line - 1;
+  if (!lines || index < 0 || index >= lines.length) return null;
+  if (language === 'python') {
+    let end = index;
+    while (end < Math.min(lines.length - 1, index + 20) && !/:\s*(?:#.*)?$/.test(lines[end])) end++;
+    const docstring = docstringAt(lines, end + 1);
+    if (docstring) return cleanComment(docstring);
+  }
+  const block = commentAbove(lines, index, language);
+  return block ? cleanComment(block) : null;
+}
+
+/**
+ * Module or package documentation at the top of a file
+ * Go uses the comment above `package`; Python the module docstring; other
+ * languages the first comment block, unless it is a license header.
+ * @param {string[]} lines - File lines
+ * @param {string} language - Map language
+ * @returns {{text: string, deprecated: boolean}|null}
+ */
+function readModuleDoc(lines, language) {
+  if (!lines) return null;
+  if (language === 'go') {
+    const index = lines.findIndex(line => /^package\s+\w+/.test(line));
+    return index > 0 ? readDocComment(lines, index + 1, language) : null;
+  }
+
+  let i = 0;
+  while (i < lines.length && (!lines[i].trim() || /^#!|^\s*['"]use strict['"];?\s*$|^#\s*-\*-|^#\s*frozen_string_literal/.test(lines[i]))) i++;
+  if (language === 'python') {
+    const docstring = docstringAt(lines, i);
+    return docstring ? cleanComment(docstring) : null;
+  }
+  if (language === 'rust' && /^\s*\/\/!/.test(lines[i] || '')) {
+    let end = i;
+    while (end + 1 < lines.length && /^\s*\/\/!/.test(lines[end + 1])) end++;
+    return cleanComment(lines.slice(i, end + 1));
+  }
+
+  while (i < lines.length) {
+    const first = lines[i].trim();
+    let end = i;
+    if (first.startsWith('/*')) {
+      while (end < lines.length && !lines[end].includes('*/')) end++;
+    } else if (/^(?:\/\/|#)/.test(first)) {
+      while (end + 1 < lines.length && /^\s*(?:\/\/|#)/.test(lines[end + 1])) end++;
+    } else {
+      return null;
+    }
+    const doc = cleanComment(lines.slice(i, end + 1));
+    if (doc && doc.text && !LICENSE_HEADER.test(doc.text)) return doc;
+    i = end + 1;
+    while (i < lines.length && !lines[i].trim()) i++;
+  }
+  return null;
+}
+
+/**
+ * Declaration text at a line, continued until parentheses balance, without the body
+ * @param {string[]} lines - File lines
+ * @param {number} line - 1-based declaration line
+ * @param {string} name - Symbol name, which the declaration must contain
+ * @returns {string|null}
+ */
+function declarationAt(lines, line, name) {
+  if (!lines || !lines[line - 1] || !lines[line - 1].includes(name)) return null;
+  let text = '';
+  let depth = 0;
+  for (let i = line - 1; i < Math.min(lines.length, line + 11); i++) {
+    const part = lines[i].trim();
+    text += (text && !/^[)\]]/.test(part) && !/[([]$/.test(text) ? ' ' : '') + part;
+    for (const char of part) {
+      if (char === '(' || char === '[') depth++;
+      else if (char === ')' || char === ']') depth--;
+    }
+    if (depth <= 0) break;
+  }
+  text = text
+    .replace(/\s*(?:=>\s*)?[{[(]\s*$/, '')
+    .replace(/\s*\{.*\}\s*;?\s*$/, '')
+    .replace(/\s*[:=]\s*$/, '')
+    .replace(/;\s*$/, '');
+  return text.length > 240 ? `${text.slice(0, 237)}...` : text;
+}
+
+/**
+ * Markdown heading anchor (GitHub style)
+ * @param {string} heading - Heading text
+ * @returns {string}
+ */
+function anchorOf(heading) {
+  return heading.toLowerCase().replace(/[^\w\s-]/g, '').trim().replace(/\s/g, '-');
+}
+
+/**
+ * Page file name for a package directory
+ * @param {string} dir - Package directory ('.' for the root)
+ * @returns {string}
+ */
+function pageName(dir) {
+  return dir === '.' ? 'root.md' : `${dir.replace(/\//g, '-')}.md`;
+}
+
+/**
+ * First sentence of a doc text
+ * @param {string} text - Doc text
+ * @returns {string}
+ */
+function firstSentence(text) {
+  const paragraph = String(text || '').split(/\n\s*\n/)[0].replace(/\s+/g, ' ').trim();
+  const match = paragraph.match(/^.*?[.!?](?=\s|$)/);
+  return match ? match[0] : paragraph;
+}
+
+/**
+ * Exported symbols of a file, methods nested under their type
+ * @param {Object} fileData - Map file entry
+ * @returns {Array<{category: string, entry: Object}>}
+ */
+function exportedSymbols(fileData) {
+  const symbols = [];
+  for (const category of Object.keys(CATEGORY_KINDS)) {
+    for (const entry of fileData.symbols?.[category] || []) {
+      if (entry.exported === false || entry.memberOf || entry.declaredIn) continue;
+      if (fileData.language === 'python' && entry.name.startsWith('_')) continue;
+      symbols.push({ category, entry });
+    }
+  }
+  return symbols.sort((a, b) => a.entry.name.localeCompare(b.entry.name));
+}
+
+/**
+ * Collect documentation for every package in the map
+ * @param {string} basePath - Project root
+ * @param {Object} map - Repo map
+ * @param {Object} [options]
+ * @param {string[]} [options.include] - Only directories under these paths
+ * @returns {Map<string, Object>} Directory -> package
+ */
+function collectPackages(basePath, map, options = {}) {
+  const include = (options.include || []).map(prefix => prefix.replace(/\/+$/, ''));
+  const packages = new Map();
+  const cache = new Map();
+  const linesOf = file => {
+    if (!cache.has(file)) cache.set(file, readLines(basePath, file));
+    return cache.get(file);
+  };
+
+  for (const file of Object.keys(map.files || {}).sort()) {
+    if (isTestFile(file)) continue;
+    const dir = path.posix.dirname(file);
+    if (include.length && !include.some(prefix => dir === prefix || dir.startsWith(`${prefix}/`) || prefix === '.')) continue;
+    const fileData = map.files[file];
+    const symbols = exportedSymbols(fileData);
+    if (!symbols.length) continue;
+
+    if (!packages.has(dir)) packages.set(dir, { dir, page: pageName(dir), languages: new Set(), files: [], doc: null, symbols: [] });
+    const pkg = packages.get(dir);
+    const lines = linesOf(file);
+    pkg.languages.add(fileData.language);
+    pkg.files.push({ file, doc: readModuleDoc(lines, fileData.language) });
+
+    for (const { category, entry } of symbols) {
+      const kind = entry.kind || CATEGORY_KINDS[category];
+      const mapHeadline = `${kind} ${entry.name}${entry.signature || ''}`;
+      const methods = (entry.methods || [])
+        .filter(method => method.exported !== false && !(fileData.language === 'python' && method.name.startsWith('_')))
+        .map(method => {
+          const methodFile = method.file || file;
+          const methodLines = linesOf(methodFile);
+          return {
+            name: method.name,
+            file: methodFile,
+            line: method.line,
+            declaration: declarationAt(methodLines, method.line, method.name) || `${method.name}()`,
+            doc: readDocComment(methodLines, method.line, fileData.language)
+          };
+        });
+      pkg.symbols.push({
+        name: entry.name,
+        category,
+        kind,
+        file,
+        line: entry.line,
+        language: fileData.language,
+        declaration: declarationAt(lines, entry.line, entry.name) || mapHeadline,
+        doc: readDocComment(lines, entry.line, fileData.language),
+        methods
+      });
+    }
+  }
+
+  for (const pkg of packages.values()) {
+    const preferred = pkg.files.find(({ file }) => /(?:^|\/)(?:doc\.go|__init__\.py|index\.[cm]?[jt]sx?|mod\.rs|lib\.rs|package-info\.java)$/.test(file));
+    const documented = (preferred && preferred.doc) ? preferred : pkg.files.length === 1 ? pkg.files[0] : pkg.files.find(({ file, doc }) => doc && pkg.languages.has('go') && /^Package\s/.test(doc.text));
+    pkg.doc = documented && documented.doc ? documented.doc.text : null;
+    pkg.languages = [...pkg.languages].sort();
+  }
+  return packages;
+}
+
+/**
+ * Package-level import edges between documented packages
+ * @param {Object} map - Repo map
+ * @param {Map<string, Object>} packages - Result of collectPackages
+ * @returns {{imports: Map<string, string[]>, importedBy: Map<string, string[]>}}
+ */
+function packageGraph(map, packages) {
+  const imports = new Map([...packages.keys()].map(dir => [dir, new Set()]));
+  const importedBy = new Map([...packages.keys()].map(dir => [dir, new Set()]));
+  for (const [file, targets] of Object.entries(buildDependencyGraph(map))) {
+    if (isTestFile(file)) continue;
+    const from = path.posix.dirname(file);
+    for (const target of targets) {
+      const to = path.posix.dirname(target);
+      if (from === to || !packages.has(to)) continue;
+      if (imports.has(from)) imports.get(from).add(to);
+      if (packages.has(from)) importedBy.get(to).add(from);
+    }
+  }
+  const sorted = edges => new Map([...edges].map(([dir, set]) => [dir, [...set].sort()]));
+  return { imports: sorted(imports), importedBy: sorted(importedBy) };
+}
+
+/**
+ * Heading of a symbol section
+ * @param {Object} symbol - Collected symbol
+ * @returns {string}
+ */
+function symbolHeading(symbol) {
+  return `${symbol.kind} ${symbol.name}`;
+}
+
+/**
+ * Render one package page
+ * @param {Object} pkg - Collected package
+ * @param {Object} context - `{packages, imports, importedBy, outDir}`
+ * @returns {string}
+ */
+function renderPackage(pkg, context) {
+  const { packages, imports, importedBy, outDir } = context;
+  const sourceRoot = path.posix.relative(outDir, '.') || '.';
+  const source = (file, line) => `[${path.posix.basename(file)}:${line}](${sourceRoot}/${file}#L${line})`;
+  const link = dir => `[${dir}](${packages.get(dir).page})`;
+
+  // Types a signature can mention: this package's and those of the packages it imports
+  const linkable = new Map();
+  for (const dir of [...(imports.get(pkg.dir) || []), pkg.dir]) {
+    for (const symbol of packages.get(dir).symbols) {
+      if (symbol.category === 'classes' || symbol.category === 'types') {
+        linkable.set(symbol.name, `${dir === pkg.dir ? '' : packages.get(dir).page}#${anchorOf(symbolHeading(symbol))}`);
+      }
+    }
+  }
+  const uses = symbol => {
+    const names = new Set((symbol.declaration.match(/[A-Za-z_]\w*/g) || []).filter(word => word !== symbol.name && linkable.has(word)));
+    return names.size ? `**Uses**: ${[...names].map(name => `[${name}](${linkable.get(name)})`).join(', ')}` : null;
+  };
+  const fence = symbol => FENCE_LANGUAGES[symbol.language] || '';
+
+  const lines = [GENERATED_MARKER, '', `# ${pkg.dir === '.' ? '(root)' : pkg.dir}`, ''];
+  if (pkg.doc) lines.push(pkg.doc, '');
+  lines.push(`**Language**: ${pkg.languages.join(', ')} | **Files**: ${pkg.files.length} | **Symbols**: ${pkg.symbols.length}`);
+  const imported = imports.get(pkg.dir) || [];
+  const dependents = importedBy.get(pkg.dir) || [];
+  if (imported.length) lines.push(`**Imports**: ${imported.map(link).join(', ')}`);
+  if (dependents.length) lines.push(`**Imported by**: ${dependents.map(link).join(', ')}`);
+  lines.push('', '## Index', '');
+  for (const section of SECTIONS) {
+    for (const symbol of pkg.symbols.filter(item => section.categories.includes(item.category))) {
+      lines.push(`- [${symbolHeading(symbol)}](#${anchorOf(symbolHeading(symbol))})`);
+    }
+  }
+  lines.push('');
+
+  if (pkg.files.length > 1) {
+    lines.push('## Files', '');
+    for (const { file, doc } of pkg.files) {
+      lines.push(`- [${path.posix.basename(file)}](${sourceRoot}/${file})${doc && doc.text ? ` - ${firstSentence(doc.text)}` : ''}`);
+    }
+    lines.push('');
+  }
+
+  for (const section of SECTIONS) {
+    const symbols = pkg.symbols.filter(item => section.categories.includes(item.category));
+    if (!symbols.length) continue;
+    lines.push(`## ${section.title}`, '');
+    for (const symbol of symbols) {
+      lines.push(`### ${symbolHeading(symbol)}`, '', `\`\`\`${fence(symbol)}`, symbol.declaration, '```', '');
+      if (symbol.doc && symbol.doc.deprecated) lines.push('**Deprecated.**', '');
+      if (symbol.doc && symbol.doc.text) lines.push(symbol.doc.text, '');
+      const used = uses(symbol);
+      lines.push(`${used ? `${used} | ` : ''}Source: ${source(symbol.file, symbol.line)}`, '');
+      for (const method of symbol.methods) {
+        lines.push(`#### ${symbol.name}.${method.name}`, '', `\`\`\`${fence(symbol)}`, method.declaration, '```', '');
+        if (method.doc && method.doc.text) lines.push(method.doc.text, '');
+        if (method.line) lines.push(`Source: ${source(method.file, method.line)}`, '');
+      }
+    }
+  }
+  return lines.join('\n').replace(/\n+$/, '\n');
+}
+
+/**
+ * Render the index page
+ * @param {Object[]} packages - Collected packages
+ * @returns {string}
+ */
+function renderIndex(packages) {
+  const total = packages.reduce((sum, pkg) => sum + pkg.symbols.length, 0);
+  const lines = [GENERATED_MARKER, '', '# API Reference', ''];
+  lines.push(`${packages.length} packages, ${total} exported symbols.`, '');
+  lines.push('| Package | Language | Symbols | Summary |', '|---------|----------|---------|---------|');
+  for (const pkg of packages) {
+    const summary = pkg.doc ? firstSentence(pkg.doc).replace(/\|/g, '\\|') : '';
+    lines.push(`| [${pkg.dir === '.' ? '(root)' : pkg.dir}](${pkg.page}) | ${pkg.languages.join(', ')} | ${pkg.symbols.length} | ${summary} |`);
+  }
+  return lines.join('\n') + '\n';
+}
+
+/**
+ * Generate reference pages from the repo map
+ * @param {string} basePath - Project root
+ * @param {Object} [options]
+ * @param {Object} options.map - Repo map
+ * @param {string} [options.outDir='docs/api'] - Output directory, relative to the root
+ * @param {string[]} [options.include] - Only packages under these directories
+ * @returns {{success: boolean, outDir?: string, pages?: Array<{file: string, dir: string|null, symbols: number, content: string}>, error?: string}}
+ */
+function generateDocs(basePath, options = {}) {
+  if (!options.map) return { success: false, error: 'No repo-map found. Run /repo-map init first.' };
+  const outDir = (options.outDir || OUTPUT_DIR).replace(/\/+$/, '');
+  const packages = collectPackages(basePath, options.map, options);
+  if (!packages.size) return { success: false, error: 'The repo map has no exported symbols to document.' };
+
+  const { imports, importedBy } = packageGraph(options.map, packages);
+  const ordered = [...packages.values()].sort((a, b) => a.dir.localeCompare(b.dir));
+  const pages = ordered.map(pkg => ({
+    file: `${outDir}/${pkg.page}`,
+    dir: pkg.dir,
+    symbols: pkg.symbols.length,
+    content: renderPackage(pkg, { packages, imports, importedBy, outDir })
+  }));
+  pages.unshift({ file: `${outDir}/README.md`, dir: null, symbols: pages.reduce((sum, page) => sum + page.symbols, 0), content: renderIndex(ordered) });
+  return { success: true, outDir, pages };
+}
+
+/**
+ * Write generated pages, removing earlier generated pages that are gone
+ * Files in the output directory without the generated marker are never touched.
+ * @param {string} basePath - Project root
+ * @param {Object} result - Result of generateDocs
+ * @returns {{written: string[], removed: string[], skipped: string[]}} `skipped` are hand-written files a page would overwrite
+ */
+function writeDocs(basePath, result) {
+  const dir = path.join(basePath, result.outDir);
+  fs.mkdirSync(dir, { recursive: true });
+  const isGenerated = file => {
+    try {
+      return fs.readFileSync(file, 'utf8').startsWith(GENERATED_MARKER);
+    } catch {
+      return false;
+    }
+  };
+
+  const written = [];
+  const skipped = [];
+  for (const page of result.pages) {
+    const target = path.join(basePath, page.file);
+    if (fs.existsSync(target) && !isGenerated(target)) {
+      skipped.push(page.file);
+      continue;
+    }
+    fs.writeFileSync(target, page.content);
+    written.push(page.file);
+  }
+  const current = new Set(result.pages.map(page => path.posix.basename(page.file)));
+  const removed = [];
+  for (const entry of fs.readdirSync(dir)) {
+    const target = path.join(dir, entry);
+    if (entry.endsWith('.md') && !current.has(entry) && isGenerated(target)) {
+      fs.unlinkSync(target);
+      removed.push(`${result.outDir}/${entry}`);
+    }
+  }
+  return { written, removed, skipped };
+}
+
+if (require.main === module) {
+  const args = process.argv.slice(2);
+  const outDir = args.includes('--out') ? args[args.indexOf('--out') + 1] : undefined;
+  const map = require('../repo-map').load(process.cwd());
+  const result = generateDocs(process.cwd(), { map, outDir });
+  const indent = process.stdout.isTTY ? 2 : 0;
+  if (result.success && args.includes('--write')) {
+    console.log(JSON.stringify({ success: true, ...writeDocs(process.cwd(), result) }, null, indent));
+  } else {
+    const summary = result.success ? { ...result, pages: result.pages.map(({ content, ...page }) => page) } : result;
+    console.log(JSON.stringify(summary, null, indent));
+    if (!result.success) process.exitCode = 1;
+  }
+}
+
+module.exports = {
+  OUTPUT_DIR,
+  GENERATED_MARKER,
+  readDocComment,
+  readModuleDoc,
+  declarationAt,
+  anchorOf,
+  collectPackages,
+  packageGraph,
+  renderPackage,
+  renderIndex,
+  generateDocs,
+  writeDocs
+};
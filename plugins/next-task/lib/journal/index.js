@@ -0,0 +1,497 @@
+#!/usr/bin/env node
+/**
+ * Change Journal: Dry Runs and Rollback
+ *
+ * Mutating commands (release, slop auto-fix, issue creation) route their
+ * file writes and git/forge commands through a transaction from `begin`.
+ * In a dry run the transaction only records what would change: file writes
+ * as unified diffs, commands as argv. Otherwise it applies each change and
+ * remembers how to undo it: the previous content of every file, and the
+ * inverse of every command it knows (`git tag -d`, `gh release delete`,
+ * closing a created issue, ...). `commit` appends the run to
+ * `<state-dir>/journal.json` (the last 10 runs), and `rollback` undoes
+ * the latest one in reverse order.
+ *
+ * Rollback refuses to overwrite a file that changed after the run (unless
+ * forced) and to reset a commit that is no longer HEAD. Pushes and edits to
+ * existing issues have no inverse; they are listed for manual follow-up.
+ * A rollback that stops part way marks the changes it undid, so running it
+ * again only retries the rest.
+ *
+ * Usage: node lib/journal/index.js [--rollback] [--dry-run] [--force] [--command NAME]
+ * Output: the last run (default) or the rollback result as JSON
+ *
+ * @module lib/journal
+ */
+
+const crypto = require('crypto');
+const fs = require('fs');
+const path = require('path');
+
+const { getStateDirPath } = require('../platform/state-dir');
+const { t } = require('../messages');
+const exec = require('../utils/exec');
+
+/**
+ * Journal file name inside the state directory
+ */
+const JOURNAL_FILE = 'journal.json';
+
+/**
+ * Journal format version
+ */
+const JOURNAL_VERSION = 1;
+
+/**
+ * Runs kept in the journal
+ */
+const MAX_RUNS = 10;
+
+/**
+ * Subcommands that only read, run even in a dry run and never journaled
+ */
+const READ_ONLY = {
+  git: ['rev-parse', 'log', 'status', 'diff', 'show', 'describe', 'ls-files', 'remote', 'fetch', 'blame'],
+  gh: ['list', 'view', 'status'],
+  glab: ['list', 'view', 'status']
+};
+
+/**
+ * Run a command and return stdout, or null on failure
+ * @param {string} basePath - Repository root
+ * @param {string[]} argv - Command and arguments
+ * @returns {string|null}
+ */
+function run(basePath, argv) {
+  return exec.run(basePath, argv, { timeout: 120000 });
+}
+
+/**
+ * sha256 of file content
+ * @param {string|null} content - null for a missing file
+ * @returns {string|null}
+ */
+function hashContent(content) {
+  return content === null ? null : crypto.createHash('sha256').update(content).digest('hex');
+}
+
+/**
+ * Read a file, null when missing
+ * @param {string} filePath - Absolute path
+ * @returns {string|null}
+ */
+function readOrNull(filePath) {
+  try {
+    return fs.readFileSync(filePath, 'utf8');
+  } catch {
+    return null;
+  }
+}
+
+/**
+ * Journal path for a repository
+ * @param {string} basePath - Repository root
+ * @returns {string}
+ */
+function journalPath(basePath) {
+  return path.join(getStateDirPath(basePath), JOURNAL_FILE);
+}
+
+/**
+ * Read the journal
+ * @param {string} basePath - Repository root
+ * @returns {{version: number, runs: Object[]}} Empty when missing or unreadable
+ */
+function readJournal(basePath) {
+  try {
+    const journal = JSON.parse(fs.readFileSync(journalPath(basePath), 'utf8'));
+    if (journal && journal.version === JOURNAL_VERSION && Array.isArray(journal.runs)) return journal;
+  } catch {
+    // Missing or corrupt: start over
+  }
+  return { version: JOURNAL_VERSION, runs: [] };
+}
+
+/**
+ * Write the journal
+ * @param {string} basePath - Repository root
+ * @param {Object} journal - {version, runs}
+ */
+function writeJournal(basePath, journal) {
+  const file = journalPath(basePath);
+  fs.mkdirSync(path.dirname(file), { recursive: true });
+  fs.writeFileSync(file, JSON.stringify({ ...journal, runs: journal.runs.slice(-MAX_RUNS) }, null, 2) + '\n');
+}
+
+/**
+ * Whether a command only reads
+ * @param {string[]} argv - Command and arguments
+ * @returns {boolean}
+ */
+function isReadOnly(argv) {
+  const subcommands = READ_ONLY[argv[0]];
+  if (!subcommands) return false;
+  if (argv[0] === 'git') return subcommands.includes(argv[1]) && !(argv[1] === 'remote' && ['add', 'remove', 'set-url'].includes(argv[2]));
+  return subcommands.includes(argv[2]) || (argv[1] === 'api' && !argv.some(arg => /^(-X|--method|-f|-F|--field|--raw-field|--input)$/.test(arg)));
+}
+
+/**
+ * First positional argument after `start`, skipping flags and their values
+ * @param {string[]} argv - Command and arguments
+ * @param {number} start - Index to start at
+ * @param {string[]} valueFlags - Flags that take a value
+ * @returns {string|null}
+ */
+function positional(argv, start, valueFlags) {
+  for (let i = start; i < argv.length; i++) {
+    if (valueFlags.includes(argv[i])) i++;
+    else if (!argv[i].startsWith('-')) return argv[i];
+  }
+  return null;
+}
+
+/**
+ * How to undo a command that succeeded
+ * @param {string} basePath - Repository root
+ * @param {string[]} argv - The command
+ * @param {string} output - Its stdout
+ * @param {Function} runCommand - `(basePath, argv) => stdout|null`
+ * @returns {{undo: string[]|null, expectHead?: string, note?: string}}
+ */
+function inverseCommand(basePath, argv, output, runCommand) {
+  const [tool, group, action] = argv;
+  const url = (String(output || '').match(/https?:\/\/\S+/) || [null])[0];
+
+  if (tool === 'git') {
+    if (group === 'tag' && !argv.some(arg => ['-d', '--delete', '-l', '--list'].includes(arg))) {
+      const tag = positional(argv, 2, ['-m', '-F', '-u', '--message', '--file']);
+      return tag ? { undo: ['git', 'tag', '-d', tag] } : { undo: null, note: 'tag name not found' };
+    }
+    if (group === 'commit') {
+      const head = (runCommand(basePath, ['git', 'rev-parse', 'HEAD']) || '').trim();
+      return { undo: ['git', 'reset', '--soft', 'HEAD~1'], expectHead: head || undefined };
+    }
+    if (group === 'add') {
+      const files = argv.slice(2).filter(arg => !arg.startsWith('-'));
+      return files.length ? { undo: ['git', 'reset', '--quiet', '--', ...files] } : { undo: null, note: 'nothing to unstage' };
+    }
+    if (group === 'push') return { undo: null, note: 'pushed commits and tags stay on the remote; delete them there if needed' };
+  }
+
+  if ((tool === 'gh' || tool === 'glab') && group === 'release' && action === 'create' && argv[3]) {
+    return { undo: [tool, 'release', 'delete', argv[3], '--yes'] };
+  }
+  if (tool === 'gh' && group === 'label' && action === 'create' && argv[3]) {
+    return { undo: ['gh', 'label', 'delete', argv[3], '--yes'] };
+  }
+  if ((tool === 'gh' || tool === 'glab') && group === 'issue') {
+    if (action === 'create') {
+      const number = url && (url.match(/\/issues\/(\d+)/) || [])[1];
+      if (tool === 'gh' && url) return { undo: ['gh', 'issue', 'close', url, '--comment', 'Rolled back by awesome-slash'] };
+      if (tool === 'glab' && number) return { undo: ['glab', 'issue', 'close', number] };
+      return { undo: null, note: 'created issue URL not found in the output' };
+    }
+    if (action === 'close' && argv[3]) return { undo: [tool, 'issue', 'reopen', argv[3]] };
+    if (action === 'reopen' && argv[3]) return { undo: [tool, 'issue', 'close', argv[3]] };
+    return { undo: null, note: 'edits to existing issues are not undone' };
+  }
+  return { undo: null, note: 'no known undo' };
+}
+
+/**
+ * Unified diff between two versions of a file (one hunk around the change)
+ * @param {string} file - Repo-relative path
+ * @param {string|null} before - Old content, null when created
+ * @param {string|null} after - New content, null when deleted
+ * @param {number} [context=3] - Unchanged lines around the change
+ * @returns {string} Empty when the contents are equal
+ */
+function diffContent(file, before, after, context = 3) {
+  if (before === after) return '';
+  const oldLines = before === null ? [] : before.replace(/\n$/, '').split('\n');
+  const newLines = after === null ? [] : after.replace(/\n$/, '').split('\n');
+  let start = 0;
+  while (start < oldLines.length && start < newLines.length && oldLines[start] === newLines[start]) start++;
+  let oldEnd = oldLines.length;
+  let newEnd = newLines.length;
+  while (oldEnd > start && newEnd > start && oldLines[oldEnd - 1] === newLines[newEnd - 1]) {
+    oldEnd--;
+    newEnd--;
+  }
+
+  const from = Math.max(0, start - context);
+  const oldTo = Math.min(oldLines.length, oldEnd + context);
+  const newTo = Math.min(newLines.length, newEnd + context);
+  const range = (first, count) => `${count === 0 ? first : first + 1},${count}`;
+  return [
+    `--- ${before === null ? '/dev/null' : `a/${file}`}`,
+    `+++ ${after === null ? '/dev/null' : `b/${file}`}`,
+    `@@ -${range(from, oldTo - from)} +${range(from, newTo - from)} @@`,
+    ...oldLines.slice(from, start).map(line => ` ${line}`),
+    ...oldLines.slice(start, oldEnd).map(line => `-${line}`),
+    ...newLines.slice(start, newEnd).map(line => `+${line}`),
+    ...oldLines.slice(oldEnd, oldTo).map(line => ` ${line}`)
+  ].join('\n') + '\n';
+}
+
+/**
+ * Start a transaction for one command run
+ * @param {string} basePath - Repository root
+ * @param {string} command - Command name (release, deslop, issue, ...)
+ * @param {Object} [options]
+ * @param {boolean} [options.dryRun=false] - Record changes without applying them
+ * @param {Function} [options.run] - `(basePath, argv) => stdout|null`
+ * @returns {Object} Transaction: `writeFile`, `removeFile`, `track`, `run`, `runner`, `changes`, `commit`
+ */
+function begin(basePath, command, options = {}) {
+  const root = path.resolve(basePath);
+  const runCommand = options.run || run;
+  const dryRun = Boolean(options.dryRun);
+  const changes = [];
+  const tracked = new Map();
+  let committed = null;
+
+  const relative = file => (path.isAbsolute(file) ? path.relative(root, file) : file).replace(/\\/g, '/');
+
+  // First snapshot wins: rollback restores the content from before the run
+  function track(file) {
+    const rel = relative(file);
+    if (!tracked.has(rel)) {
+      const change = { type: 'file', file: rel, before: readOrNull(path.join(root, rel)) };
+      tracked.set(rel, change);
+      changes.push(change);
+    }
+    return tracked.get(rel);
+  }
+
+  const tx = {
+    command,
+    dryRun,
+    changes,
+    track,
+
+    /**
+     * Write (or, in a dry run, record) a file
+     * @param {string} file - Absolute or repo-relative path
+     * @param {string} content - New content
+     */
+    writeFile(file, content) {
+      const change = track(file);
+      if (dryRun) {
+        change.after = content;
+        return;
+      }
+      const target = path.join(root, change.file);
+      fs.mkdirSync(path.dirname(target), { recursive: true });
+      fs.writeFileSync(target, content);
+    },
+
+    /**
+     * Delete (or, in a dry run, record deleting) a file
+     * @param {string} file - Absolute or repo-relative path
+     */
+    removeFile(file) {
+      const change = track(file);
+      if (dryRun) {
+        change.after = null;
+        return;
+      }
+      fs.rmSync(path.join(root, change.file), { force: true });
+    },
+
+    /**
+     * Run (or, in a dry run, record) a command; read-only commands always run
+     * @param {string[]} argv - Command and arguments
+     * @returns {string|null} stdout ('' in a dry run), null on failure
+     */
+    run(argv) {
+      if (isReadOnly(argv)) return runCommand(root, argv);
+      if (dryRun) {
+        changes.push({ type: 'command', argv });
+        return '';
+      }
+      const output = runCommand(root, argv);
+      if (output !== null) changes.push({ type: 'command', argv, ...inverseCommand(root, argv, output, runCommand) });
+      return output;
+    },
+
+    /**
+     * Append the run to the journal; a dry run or a run without changes writes nothing
+     * @returns {{id: string|null, changes: number}}
+     */
+    commit() {
+      if (committed) return committed;
+      const contentAfter = change => (dryRun ? (change.after === undefined ? change.before : change.after) : readOrNull(path.join(root, change.file)));
+      const recorded = changes.map(change => (change.type === 'file'
+        ? { type: 'file', file: change.file, before: change.before, afterHash: hashContent(contentAfter(change)) }
+        : change));
+      const applied = recorded.filter(change => change.type === 'command' || hashContent(change.before) !== change.afterHash);
+      committed = { id: null, changes: applied.length };
+      if (dryRun || applied.length === 0) return committed;
+
+      const journal = readJournal(root);
+      committed.id = `${new Date().toISOString().replace(/[:.]/g, '-')}-${command}`;
+      journal.runs.push({ id: committed.id, command, finishedAt: new Date().toISOString(), changes: applied });
+      writeJournal(root, journal);
+      return committed;
+    }
+  };
+  tx.runner = (_basePath, argv) => tx.run(argv);
+  return tx;
+}
+
+/**
+ * Describe one change for humans
+ * @param {Object} change - Transaction or journal change
+ * @returns {string}
+ */
+function describeChange(change) {
+  if (change.type === 'command') return t('journal.change.run', { command: change.argv.join(' ') });
+  const removed = change.after === null || change.afterHash === null;
+  if (removed) return t('journal.change.delete', { file: change.file });
+  return t(change.before === null ? 'journal.change.create' : 'journal.change.write', { file: change.file });
+}
+
+/**
+ * Render a dry run: one line per change, file writes as diffs
+ * @param {Object[]} changes - tx.changes
+ * @returns {string}
+ */
+function renderChanges(changes) {
+  if (changes.length === 0) return t('journal.noChanges');
+  return changes.map(change => {
+    const line = describeChange(change);
+    const diff = change.type === 'file' && change.after !== undefined ? diffContent(change.file, change.before, change.after) : '';
+    return diff ? `${line}\n${diff.trimEnd()}` : line;
+  }).join('\n');
+}
+
+/**
+ * Undo the latest journaled run
+ * @param {string} basePath - Repository root
+ * @param {Object} [options]
+ * @param {string} [options.command] - Latest run of this command instead of any
+ * @param {boolean} [options.dryRun=false] - Report the steps without undoing anything
+ * @param {boolean} [options.force=false] - Restore files that changed after the run
+ * @param {Function} [options.run] - `(basePath, argv) => stdout|null`
+ * @returns {{success: boolean, run: Object|null, undone: string[], skipped: Array<{change: string, reason: string}>,
+ *   manual: Array<{change: string, note: string}>, error?: string}}
+ *   The run stays in the journal when a step is skipped or fails, so it can be retried
+ */
+function rollback(basePath, options = {}) {
+  const root = path.resolve(basePath);
+  const runCommand = options.run || run;
+  const journal = readJournal(root);
+  const index = options.command
+    ? journal.runs.map(entry => entry.command).lastIndexOf(options.command)
+    : journal.runs.length - 1;
+  if (index < 0) {
+    return { success: false, run: null, undone: [], skipped: [], manual: [], error: options.command ? t('journal.rollback.noCommandRun', { command: options.command }) : t('journal.rollback.noRun') };
+  }
+
+  const target = journal.runs[index];
+  const undone = [];
+  const skipped = [];
+  const manual = [];
+  let progressed = false;
+  for (const change of target.changes.slice().reverse()) {
+    if (change.undone) continue;
+    const label = describeChange(change);
+    if (change.type === 'file') {
+      const filePath = path.join(root, change.file);
+      if (!options.force && hashContent(readOrNull(filePath)) !== change.afterHash) {
+        skipped.push({ change: label, reason: t('journal.rollback.changedSince') });
+        continue;
+      }
+      if (!options.dryRun) {
+        if (change.before === null) fs.rmSync(filePath, { force: true });
+        else fs.writeFileSync(filePath, change.before);
+        change.undone = true;
+        progressed = true;
+      }
+      undone.push(t(change.before === null ? 'journal.change.delete' : 'journal.change.restore', { file: change.file }));
+      continue;
+    }
+
+    if (!change.undo) {
+      manual.push({ change: label, note: change.note || t('journal.rollback.noUndo') });
+      continue;
+    }
+    if (change.expectHead) {
+      const head = (runCommand(root, ['git', 'rev-parse', 'HEAD']) || '').trim();
+      if (head !== change.expectHead) {
+        skipped.push({ change: label, reason: t('journal.rollback.headMoved', { commit: change.expectHead.slice(0, 7) }) });
+        continue;
+      }
+    }
+    if (!options.dryRun) {
+      if (runCommand(root, change.undo) === null) {
+        skipped.push({ change: label, reason: t('journal.rollback.undoFailed', { command: change.undo.join(' ') }) });
+        continue;
+      }
+      change.undone = true;
+      progressed = true;
+    }
+    undone.push(t('journal.change.run', { command: change.undo.join(' ') }));
+  }
+
+  if (!options.dryRun && skipped.length === 0) {
+    journal.runs.splice(index, 1);
+    writeJournal(root, journal);
+  } else if (progressed) {
+    // Keep the run for a retry, without the changes already undone
+    writeJournal(root, journal);
+  }
+  return {
+    success: skipped.length === 0,
+    run: { id: target.id, command: target.command, finishedAt: target.finishedAt },
+    undone,
+    skipped,
+    manual
+  };
+}
+
+/**
+ * Render a rollback result as markdown
+ * @param {Object} result - Result of rollback
+ * @param {boolean} [dryRun=false] - Word it as a plan
+ * @returns {string}
+ */
+function renderRollback(result, dryRun = false) {
+  if (!result.run) return result.error;
+  const lines = [t(dryRun ? 'journal.rollback.titleDryRun' : 'journal.rollback.title'), '', t('journal.rollback.run', result.run), ''];
+  for (const step of result.undone) lines.push(t(dryRun ? 'journal.rollback.wouldUndo' : 'journal.rollback.undone', { step }));
+  for (const { change, reason } of result.skipped) lines.push(t('journal.rollback.skipped', { change, reason }));
+  for (const { change, note } of result.manual) lines.push(t('journal.rollback.manual', { change, note }));
+  if (result.undone.length + result.skipped.length + result.manual.length === 0) lines.push(t('journal.rollback.nothing'));
+  return lines.join('\n');
+}
+
+if (require.main === module) {
+  const args = process.argv.slice(2);
+  const value = flag => (args.includes(flag) ? args[args.indexOf(flag) + 1] : undefined);
+  const indent = process.stdout.isTTY ? 2 : 0;
+  if (args.includes('--rollback')) {
+    const result = rollback(process.cwd(), { command: value('--command'), dryRun: args.includes('--dry-run'), force: args.includes('--force') });
+    console.log(JSON.stringify(result, null, indent));
+    if (!result.success) process.exitCode = 1;
+  } else {
+    const runs = readJournal(process.cwd()).runs.filter(entry => !value('--command') || entry.command === value('--command'));
+    console.log(JSON.stringify(runs[runs.length - 1] || null, null, indent));
+  }
+}
+
+module.exports = {
+  JOURNAL_FILE,
+  MAX_RUNS,
+  journalPath,
+  readJournal,
+  isReadOnly,
+  inverseCommand,
+  diffContent,
+  begin,
+  describeChange,
+  renderChanges,
+  rollback,
+  renderRollback
+};
@@ -0,0 +1,406 @@
+#!/usr/bin/env node
+/**
+ * Project Onboarding Document
+ *
+ * Combines platform detection (project type, package manager, runtimes, CI,
+ * deployments, branch model) with the repo map (entry points, top-ranked
+ * files and exported symbols, directory layout) into an ONBOARDING.md that
+ * says how to build, test, and deploy the project and where its important
+ * code lives. `generateOnboarding` gathers the facts; `renderOnboarding` is
+ * pure so the command can show the document before writing it.
+ *
+ * Usage: node lib/onboard/index.js [--write]
+ * Output: ONBOARDING.md content (written to the repository root with --write)
+ *
+ * @module lib/onboard
+ */
+
+const fs = require('fs');
+const path = require('path');
+
+const { detectRuntimes } = require('../platform/detect-runtimes');
+const { detectTestFrameworks, parseMakeTargets } = require('../platform/detect-tests');
+const { detectLinters } = require('../platform/detect-linters');
+const { rankFiles, rankSymbols } = require('../repo-map/rank');
+const { readPackageEntryPoints } = require('../repo-map/dead');
+const { isTestFile } = require('../repo-map/testgen');
+
+const OUTPUT_FILE = 'ONBOARDING.md';
+
+/**
+ * Install commands per detected package manager, then per project type
+ */
+const INSTALL_COMMANDS = {
+  npm: 'npm ci',
+  pnpm: 'pnpm install --frozen-lockfile',
+  yarn: 'yarn install --frozen-lockfile',
+  bun: 'bun install',
+  poetry: 'poetry install',
+  pipenv: 'pipenv install --dev',
+  cargo: 'cargo fetch',
+  go: 'go mod download'
+};
+
+const DEFAULT_INSTALL = {
+  nodejs: 'npm install',
+  python: 'pip install -e .',
+  rust: 'cargo fetch',
+  go: 'go mod download'
+};
+
+/**
+ * Build commands for project types without a build script
+ */
+const DEFAULT_BUILD = {
+  rust: 'cargo build',
+  go: 'go build ./...'
+};
+
+// package.json scripts listed under Build and Run, in order
+const BUILD_SCRIPTS = ['build', 'compile'];
+const RUN_SCRIPTS = ['dev', 'start', 'serve'];
+const MAKE_TARGETS = ['build', 'run', 'dev', 'install'];
+
+// Entry points by convention, beyond those declared in package.json
+const ENTRY_POINT_PATTERNS = [
+  /^(?:src\/)?(?:index|main|cli|app|server)\.[^/]+$/,
+  /(?:^|\/)(?:__main__|manage|wsgi|asgi)\.py$/,
+  /^(?:src\/)?(?:main|lib)\.rs$/,
+  /^src\/bin\/[^/]+\.rs$/,
+  /^(?:cmd\/[^/]+\/)?main\.go$/,
+  /^bin\/[^/]+$/
+];
+
+// Singular names for repo-map symbol categories
+const CATEGORY_KINDS = {
+  functions: 'function',
+  classes: 'class',
+  types: 'type',
+  constants: 'constant'
+};
+
+const DEFAULT_LIMIT = 10;
+
+/**
+ * Read a file relative to the project, or null
+ * @param {string} basePath - Project root
+ * @param {string} file - Relative path
+ * @returns {string|null}
+ */
+function readText(basePath, file) {
+  try {
+    return fs.readFileSync(path.join(basePath, file), 'utf8');
+  } catch {
+    return null;
+  }
+}
+
+/**
+ * Project name and description from the manifest
+ * @param {Function} readFile - (file) => content or null
+ * @param {string} fallback - Name when no manifest has one
+ * @returns {{name: string, description: string|null, scripts: Object}}
+ */
+function readProjectInfo(readFile, fallback) {
+  let pkg = null;
+  try {
+    pkg = JSON.parse(readFile('package.json'));
+  } catch {
+    // Not a Node project, or invalid package.json
+  }
+  if (pkg && typeof pkg === 'object') {
+    return {
+      name: typeof pkg.name === 'string' ? pkg.name : fallback,
+      description: typeof pkg.description === 'string' ? pkg.description : null,
+      scripts: pkg.scripts && typeof pkg.scripts === 'object' ? pkg.scripts : {}
+    };
+  }
+
+  const toml = readFile('Cargo.toml') || readFile('pyproject.toml') || '';
+  const section = toml.match(/^\[(?:package|project|tool\.poetry)\]([^[]*)/m);
+  const field = key => section && (section[1].match(new RegExp(`^${key}\\s*=\\s*["']([^"']+)["']`, 'm')) || [])[1];
+  const goModule = ((readFile('go.mod') || '').match(/^module\s+(\S+)/m) || [])[1];
+  return {
+    name: field('name') || (goModule && goModule.split('/').pop()) || fallback,
+    description: field('description') || null,
+    scripts: {}
+  };
+}
+
+/**
+ * Install, build, and run commands
+ * @param {Object} detection - Platform detection result
+ * @param {Object} info - Result of `readProjectInfo`
+ * @param {Function} readFile - (file) => content or null
+ * @returns {{install: string|null, build: string[], run: string[]}}
+ */
+function buildCommands(detection, info, readFile) {
+  const manager = detection.packageManager;
+  const type = detection.projectType;
+  let install = INSTALL_COMMANDS[manager] || DEFAULT_INSTALL[type] || null;
+  if (!INSTALL_COMMANDS[manager] && type === 'python' && readFile('requirements.txt') !== null) {
+    install = 'pip install -r requirements.txt';
+  }
+
+  const runner = ['npm', 'pnpm', 'yarn', 'bun'].includes(manager) ? manager : 'npm';
+  const scripts = name => `${runner} run ${name}`;
+  const build = BUILD_SCRIPTS.filter(name => info.scripts[name]).map(scripts);
+  const run = RUN_SCRIPTS.filter(name => info.scripts[name]).map(scripts);
+
+  if (build.length === 0 && DEFAULT_BUILD[type]) build.push(DEFAULT_BUILD[type]);
+  if (build.length === 0 && type === 'java') {
+    build.push(readFile('pom.xml') !== null ? 'mvn package' : './gradlew build');
+  }
+
+  const makefile = readFile('Makefile');
+  if (makefile) {
+    const targets = parseMakeTargets(makefile);
+    for (const target of MAKE_TARGETS.filter(name => targets.includes(name))) {
+      (target === 'run' || target === 'dev' ? run : build).push(`make ${target}`);
+    }
+  }
+  return { install, build, run };
+}
+
+/**
+ * Entry points: declared in package.json, or named by convention, in rank order
+ * @param {Object} map - Repo map
+ * @param {Set<string>} declared - Declared entry points
+ * @param {Map<string, number>} ranks - Result of `rankFiles`
+ * @returns {string[]}
+ */
+function findEntryPoints(map, declared, ranks) {
+  return Object.keys(map.files || {})
+    .filter(file => !isTestFile(file))
+    .filter(file => declared.has(file) || ENTRY_POINT_PATTERNS.some(pattern => pattern.test(file)))
+    .sort((a, b) => Number(declared.has(b)) - Number(declared.has(a)) || (ranks.get(b) || 0) - (ranks.get(a) || 0) || a.localeCompare(b));
+}
+
+/**
+ * Top-level directories with their file count and main language
+ * @param {Object} map - Repo map
+ * @returns {Array<{path: string, files: number, language: string|null}>}
+ */
+function describeLayout(map) {
+  const dirs = new Map();
+  for (const [file, entry] of Object.entries(map.files || {})) {
+    const top = file.includes('/') ? `${file.split('/')[0]}/` : '.';
+    if (!dirs.has(top)) dirs.set(top, { path: top, files: 0, languages: new Map() });
+    const dir = dirs.get(top);
+    dir.files++;
+    if (entry.language) dir.languages.set(entry.language, (dir.languages.get(entry.language) || 0) + 1);
+  }
+  return Array.from(dirs.values())
+    .map(dir => ({
+      path: dir.path,
+      files: dir.files,
+      language: Array.from(dir.languages).sort((a, b) => b[1] - a[1])[0]?.[0] || null
+    }))
+    .sort((a, b) => b.files - a.files || a.path.localeCompare(b.path));
+}
+
+/**
+ * Repo-map facts: entry points, key files and symbols, layout
+ * @param {Object} map - Repo map
+ * @param {Object} [options]
+ * @param {Function} [options.readFile] - (file) => content, for package.json entry points
+ * @param {number} [options.limit=10] - Files and symbols listed
+ * @returns {{entryPoints: string[], files: Array, symbols: Array, layout: Array}}
+ */
+function summarizeMap(map, options = {}) {
+  const limit = options.limit || DEFAULT_LIMIT;
+  const readFile = options.readFile || (() => null);
+  const ranks = rankFiles(map);
+  const declared = readPackageEntryPoints(file => {
+    const content = readFile(file);
+    if (content === null) throw new Error(`${file} not found`);
+    return content;
+  });
+
+  const files = Array.from(ranks)
+    .filter(([file]) => !isTestFile(file))
+    .sort((a, b) => b[1] - a[1] || a[0].localeCompare(b[0]))
+    .slice(0, limit)
+    .map(([file, score]) => ({ file, score, language: map.files[file]?.language || null }));
+
+  const symbols = rankSymbols(map)
+    .filter(symbol => symbol.exported && !isTestFile(symbol.file))
+    .slice(0, limit);
+
+  return {
+    entryPoints: findEntryPoints(map, declared, ranks).slice(0, limit),
+    files,
+    symbols,
+    layout: describeLayout(map)
+  };
+}
+
+/**
+ * Render ONBOARDING.md
+ * @param {Object} facts - Result of `generateOnboarding` (without `content`)
+ * @returns {string}
+ */
+function renderOnboarding(facts) {
+  const { detection = {}, commands = {}, runtimes, tests, linters, code } = facts;
+  const lines = [`# Onboarding: ${facts.name}`, ''];
+  if (facts.description) lines.push(facts.description, '');
+
+  lines.push('## Stack', '');
+  lines.push(`- **Project type**: ${detection.projectType || 'unknown'}`);
+  if (detection.packageManager) lines.push(`- **Package manager**: ${detection.packageManager}`);
+  for (const runtime of runtimes?.runtimes || []) {
+    const version = runtime.version || (runtime.minimum ? `>=${runtime.minimum}` : 'any version');
+    const sources = (runtime.sources || []).map(source => source.file);
+    lines.push(`- **${runtime.name}**: ${version}${sources.length ? ` (${sources.join(', ')})` : ''}`);
+  }
+  if (detection.monorepo) {
+    lines.push(`- **Monorepo**: ${detection.monorepo.tools.join(', ')} with ${detection.monorepo.packages.length} packages`);
+    for (const pkg of detection.monorepo.packages) {
+      lines.push(`  - \`${pkg.path}\` - ${pkg.name} (${pkg.projectType})`);
+    }
+  }
+  lines.push('');
+
+  lines.push('## Build', '');
+  if (commands.install) lines.push(`- Install dependencies: \`${commands.install}\``);
+  for (const command of commands.build || []) lines.push(`- Build: \`${command}\``);
+  for (const command of commands.run || []) lines.push(`- Run locally: \`${command}\``);
+  if (!commands.install && !(commands.build || []).length && !(commands.run || []).length) {
+    lines.push('No build steps detected.');
+  }
+  lines.push('');
+
+  lines.push('## Test', '');
+  if (tests?.commands?.length) {
+    if (tests.frameworks.length) lines.push(`Frameworks: ${tests.frameworks.map(framework => framework.name).join(', ')}`, '');
+    for (const { command, source } of tests.commands) lines.push(`- \`${command}\` (${source})`);
+  } else {
+    lines.push('No test command detected.');
+  }
+  const checks = (linters?.linters || []).map(linter => `${linter.name} (${[linter.lints && 'lint', linter.formats && 'format'].filter(Boolean).join(', ')})`);
+  if (checks.length) lines.push('', `Lint and format: ${checks.join(', ')}`);
+  lines.push('');
+
+  lines.push('## Deploy', '');
+  const deploy = [];
+  if (detection.deployments?.length) deploy.push(`- **Deploys to**: ${detection.deployments.join(', ')}`);
+  for (const pipeline of detection.ciPipelines || []) {
+    deploy.push(`- **CI** (${pipeline.platform}): \`${pipeline.file}\`${pipeline.jobs.length ? ` - jobs: ${pipeline.jobs.join(', ')}` : ''}`);
+  }
+  const containers = detection.containerization;
+  if (containers) {
+    const files = [...containers.dockerfiles, ...containers.compose];
+    deploy.push(`- **Containers**: ${files.map(file => `\`${file}\``).join(', ') || 'none'}${containers.orchestrator ? ` (${containers.orchestrator})` : ''}`);
+  }
+  for (const project of detection.infrastructure?.projects || []) {
+    deploy.push(`- **Infrastructure** (${project.tool}): \`${project.path}\`${project.providers?.length ? ` - ${project.providers.join(', ')}` : ''}`);
+  }
+  for (const fn of detection.serverless?.functions || []) {
+    deploy.push(`- **Function** ${fn.name}: \`${fn.deployCommand}\``);
+  }
+  lines.push(...(deploy.length ? deploy : ['No deployment configuration detected.']), '');
+
+  const branching = detection.branching;
+  if (branching) {
+    lines.push('### Branches', '');
+    lines.push(`- **Model**: ${branching.model}`);
+    if (detection.mainBranch) lines.push(`- **Main branch**: \`${detection.mainBranch}\``);
+    if (branching.developBranch) lines.push(`- **Development branch**: \`${branching.developBranch}\``);
+    if (branching.productionBranch) lines.push(`- **Production branch**: \`${branching.productionBranch}\``);
+    if (branching.releaseBranches?.length) lines.push(`- **Release branches**: ${branching.releaseBranches.map(branch => `\`${branch}\``).join(', ')}`);
+    if (branching.latestTag) lines.push(`- **Latest tag**: \`${branching.latestTag}\``);
+    if (branching.environments?.length) lines.push(`- **Environments**: ${branching.environments.join(', ')}`);
+    lines.push('');
+  }
+
+  lines.push('## Where the Code Lives', '');
+  if (!code) {
+    lines.push('Run /repo-map init and regenerate for entry points and key code.', '');
+    return lines.join('\n');
+  }
+  if (code.entryPoints.length) {
+    lines.push('### Entry points', '');
+    for (const file of code.entryPoints) lines.push(`- \`${file}\``);
+    lines.push('');
+  }
+  if (code.layout.length) {
+    lines.push('### Layout', '', '| Directory | Files | Language |', '|-----------|-------|----------|');
+    for (const dir of code.layout) lines.push(`| \`${dir.path}\` | ${dir.files} | ${dir.language || '-'} |`);
+    lines.push('');
+  }
+  if (code.files.length) {
+    lines.push('### Most central files', '', 'Ranked by how much of the codebase depends on them.', '');
+    for (const { file } of code.files) lines.push(`- \`${file}\``);
+    lines.push('');
+  }
+  if (code.symbols.length) {
+    lines.push('### Key exported symbols', '', '| Symbol | Kind | Location | References |', '|--------|------|----------|------------|');
+    for (const symbol of code.symbols) {
+      lines.push(`| \`${symbol.name}\` | ${symbol.kind || CATEGORY_KINDS[symbol.category] || symbol.category} | \`${symbol.file}:${symbol.line}\` | ${symbol.references} |`);
+    }
+    lines.push('');
+  }
+  return lines.join('\n');
+}
+
+/**
+ * Gather the facts and render ONBOARDING.md
+ * @param {string} basePath - Project root
+ * @param {Object} [options]
+ * @param {Object} [options.detection] - Platform detection result (default: `detect()` for the working directory)
+ * @param {Object|null} [options.map] - Repo map; without it the code section only points to /repo-map init
+ * @param {number} [options.limit=10] - Files and symbols listed
+ * @returns {Promise<{success: boolean, file: string, content: string, name: string}>}
+ */
+async function generateOnboarding(basePath, options = {}) {
+  const detection = options.detection || await require('../platform/detect-platform').detect();
+  const readFile = file => readText(basePath, file);
+  const info = readProjectInfo(readFile, path.basename(path.resolve(basePath)));
+
+  const [tests, runtimes, linters] = await Promise.all([
+    detectTestFrameworks(basePath).catch(() => null),
+    Promise.resolve().then(() => detectRuntimes(basePath)).catch(() => null),
+    Promise.resolve().then(() => detectLinters(basePath)).catch(() => null)
+  ]);
+
+  const facts = {
+    name: info.name,
+    description: info.description,
+    detection,
+    commands: buildCommands(detection, info, readFile),
+    runtimes,
+    tests,
+    linters,
+    code: options.map ? summarizeMap(options.map, { readFile, limit: options.limit }) : null
+  };
+  return {
+    success: true,
+    file: OUTPUT_FILE,
+    name: info.name,
+    content: renderOnboarding(facts)
+  };
+}
+
+if (require.main === module) {
+  const repoMap = require('../repo-map');
+  generateOnboarding(process.cwd(), { map: repoMap.load(process.cwd()) }).then(result => {
+    if (process.argv.includes('--write')) {
+      fs.writeFileSync(path.join(process.cwd(), result.file), result.content);
+      console.log(`Wrote ${result.file}`);
+    } else {
+      console.log(result.content);
+    }
+  });
+}
+
+module.exports = {
+  OUTPUT_FILE,
+  INSTALL_COMMANDS,
+  readProjectInfo,
+  buildCommands,
+  findEntryPoints,
+  describeLayout,
+  summarizeMap,
+  renderOnboarding,
+  generateOnboarding
+};
@@ -12,10 +12,10 @@
  * @license MIT
  */
 
-const { execSync, execFileSync } = require('child_process');
+const exec = require('../utils/exec');
 const path = require('path');
 const fs = require('fs');
-// Note: escapeDoubleQuotes no longer needed - using execFileSync with arg arrays
+// Note: escapeDoubleQuotes no longer needed - using exec with arg arrays
 
 /**
  * Cache for tool availability (per-repo)
@@ -102,11 +102,7 @@ const CLI_TOOLS = {
  */
 function isToolAvailable(command) {
   try {
-    execSync(command, {
-      stdio: 'pipe',
-      timeout: 5000,
-      windowsHide: true
-    });
+    exec.execSync(process.cwd(), command.split(/\s+/), { timeout: 5000 });
     return true;
   } catch {
     return false;
@@ -342,7 +338,7 @@ function runDuplicateDetection(repoPath, options = {}) {
 
   try {
     // Run jscpd with JSON output
-    // Use execFileSync with arg array to prevent command injection (no shell interpretation)
+    // Arg array, no shell string: prevents command injection
     const outputPath = process.platform === 'win32' ? 'NUL' : '/dev/null';
     const args = [
       repoPath,
@@ -353,13 +349,7 @@ function runDuplicateDetection(repoPath, options = {}) {
       '--silent'
     ];
 
-    const result = execFileSync('jscpd', args, {
-      stdio: ['pipe', 'pipe', 'pipe'], // stdin, stdout, stderr all piped
-      timeout: 60000,
-      windowsHide: true,
-      cwd: repoPath,
-      encoding: 'utf8'
-    });
+    const result = exec.execSync(repoPath, ['jscpd', ...args], { timeout: 60000 });
 
     // Parse JSON output
     try {
@@ -431,16 +421,10 @@ function runDependencyAnalysis(repoPath, options = {}) {
 
   try {
     // Run madge with circular flag and JSON output
-    // Use execFileSync with arg array to prevent command injection (no shell interpretation)
+    // Arg array, no shell string: prevents command injection
     const args = ['--circular', '--json', entry];
 
-    const result = execFileSync('madge', args, {
-      stdio: ['pipe', 'pipe', 'pipe'],
-      timeout: 60000,
-      windowsHide: true,
-      cwd: repoPath,
-      encoding: 'utf8'
-    });
+    const result = exec.execSync(repoPath, ['madge', ...args], { timeout: 60000 });
 
     // Parse JSON output
     try {
@@ -479,16 +463,10 @@ function runComplexityAnalysis(repoPath, targetFiles, options = {}) {
     const filePath = path.isAbsolute(file) ? file : path.join(repoPath, file);
 
     try {
-      // Use execFileSync with arg array to prevent command injection (no shell interpretation)
+      // Arg array, no shell string: prevents command injection
       const args = [filePath, '--format', 'json'];
 
-      const result = execFileSync('escomplex', args, {
-        stdio: ['pipe', 'pipe', 'pipe'],
-        timeout: 30000,
-        windowsHide: true,
-        cwd: repoPath,
-        encoding: 'utf8'
-      });
+      const result = exec.execSync(repoPath, ['escomplex', ...args], { timeout: 30000 });
 
       try {
         const report = JSON.parse(result);
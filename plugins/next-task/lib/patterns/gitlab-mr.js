@@ -0,0 +1,427 @@
+/**
+ * GitLab Merge Request Discussions
+ *
+ * Posts slop and review findings as diff discussions on the merge request
+ * a GitLab CI pipeline runs for, anchored to the changed line. Each thread
+ * carries the finding's fingerprint (pattern and line content, as in the
+ * slop baseline), so later pipelines leave existing threads alone, reopen a
+ * thread whose finding is back, and resolve threads whose finding is gone.
+ * Only lines the merge request adds or shows as context can hold a thread;
+ * findings elsewhere are counted, not posted. Threads name the file's
+ * CODEOWNERS; with `requestReviewers` the owners of newly flagged files are
+ * added as reviewers (the `/assign_reviewer` quick action).
+ *
+ * Needs `GITLAB_TOKEN` (or `GLAB_TOKEN`): a project or personal access
+ * token with `api` scope. `CI_JOB_TOKEN` cannot write discussions.
+ *
+ * Usage:
+ *   node gitlab-mr.js review <review-queue.json> [--request-reviewers]
+ *
+ * @module patterns/gitlab-mr
+ * @author Avi Fenesh
+ * @license MIT
+ */
+
+const fs = require('fs');
+const http = require('http');
+const https = require('https');
+const path = require('path');
+const { keyFindings } = require('./baseline');
+const { SEVERITIES } = require('./pipeline');
+const { t } = require('../messages');
+const codeowners = require('../utils/codeowners');
+
+/**
+ * Variables holding an API token, in lookup order
+ */
+const TOKEN_VARIABLES = ['GITLAB_TOKEN', 'GLAB_TOKEN'];
+
+/**
+ * New threads per pipeline; the rest wait for the next run
+ */
+const MAX_NEW_THREADS = 50;
+
+const PAGE_SIZE = 100;
+
+// <!-- awesome-slash:finding fingerprint=0123456789abcdef source=slop -->
+const MARKER = /<!-- awesome-slash:finding fingerprint=([0-9a-f]{16}) source=(\w+) -->/;
+
+/**
+ * Merge request pipeline context from GitLab CI variables
+ * @param {Object} [env=process.env] - Environment
+ * @returns {{apiUrl: string, projectId: string, iid: string, token: string|null, workspace: string}|null}
+ *   null outside a merge request pipeline
+ */
+function mergeRequestContext(env = process.env) {
+  if (env.GITLAB_CI !== 'true' || !env.CI_MERGE_REQUEST_IID || !env.CI_PROJECT_ID) return null;
+  const tokenVariable = TOKEN_VARIABLES.find(name => env[name]);
+  return {
+    apiUrl: (env.CI_API_V4_URL || `${env.CI_SERVER_URL || 'https://gitlab.com'}/api/v4`).replace(/\/+$/, ''),
+    projectId: env.CI_PROJECT_ID,
+    iid: env.CI_MERGE_REQUEST_IID,
+    token: tokenVariable ? env[tokenVariable] : null,
+    workspace: env.CI_PROJECT_DIR || process.cwd()
+  };
+}
+
+/**
+ * JSON request function against the GitLab API
+ * @param {Object} context - Result of mergeRequestContext
+ * @returns {Function} `(method, pathname, body) => Promise<Object>`, pathname relative to the merge request
+ */
+function createRequest(context) {
+  const base = new URL(`${context.apiUrl}/projects/${encodeURIComponent(context.projectId)}/merge_requests/${context.iid}`);
+  const client = base.protocol === 'http:' ? http : https;
+  return (method, pathname, body) => new Promise((resolve, reject) => {
+    const url = new URL(`${base.pathname}${pathname}`, base);
+    const payload = body ? JSON.stringify(body) : null;
+    const req = client.request(url, {
+      method,
+      headers: {
+        'PRIVATE-TOKEN': context.token,
+        ...(payload ? { 'Content-Type': 'application/json', 'Content-Length': Buffer.byteLength(payload) } : {})
+      },
+      timeout: 30000
+    }, res => {
+      let data = '';
+      res.setEncoding('utf8');
+      res.on('data', chunk => { data += chunk; });
+      res.on('end', () => {
+        if (res.statusCode >= 400) {
+          reject(new Error(`GitLab ${method} ${url.pathname} returned ${res.statusCode}`));
+          return;
+        }
+        try {
+          resolve(data ? JSON.parse(data) : {});
+        } catch (error) {
+          reject(error);
+        }
+      });
+    });
+    req.on('timeout', () => req.destroy(new Error('GitLab request timed out')));
+    req.on('error', reject);
+    if (payload) req.write(payload);
+    req.end();
+  });
+}
+
+/**
+ * Lines of a unified diff that a discussion can be attached to
+ * @param {string} diff - Diff of one file (hunks only, as the API returns it)
+ * @returns {Map<number, number|null>} New line -> old line (null for added lines)
+ */
+function parseDiffLines(diff) {
+  const lines = new Map();
+  let oldLine = 0;
+  let newLine = 0;
+  for (const text of String(diff || '').split('\n')) {
+    const hunk = text.match(/^@@ -(\d+)(?:,\d+)? \+(\d+)(?:,\d+)? @@/);
+    if (hunk) {
+      oldLine = Number(hunk[1]);
+      newLine = Number(hunk[2]);
+    } else if (text.startsWith('+')) {
+      lines.set(newLine++, null);
+    } else if (text.startsWith('-')) {
+      oldLine++;
+    } else if (text.startsWith(' ')) {
+      lines.set(newLine++, oldLine++);
+    }
+  }
+  return lines;
+}
+
+/**
+ * Fetch every page of a list endpoint
+ * @param {Function} request - Result of createRequest
+ * @param {string} pathname - Endpoint relative to the merge request
+ * @returns {Promise<Object[]>}
+ */
+async function fetchAll(request, pathname) {
+  const items = [];
+  for (let page = 1; ; page++) {
+    const batch = await request('GET', `${pathname}?per_page=${PAGE_SIZE}&page=${page}`);
+    if (!Array.isArray(batch)) break;
+    items.push(...batch);
+    if (batch.length < PAGE_SIZE) break;
+  }
+  return items;
+}
+
+/**
+ * Diff versions, changed lines, and existing finding threads of the merge request
+ * @param {Function} request - Result of createRequest
+ * @returns {Promise<{refs: Object, files: Map<string, {oldPath: string, lines: Map}>, threads: Object[]}>}
+ */
+async function loadMergeRequest(request) {
+  const versions = await request('GET', '/versions');
+  const latest = Array.isArray(versions) ? versions[0] : null;
+  if (!latest) throw new Error('The merge request has no diff versions yet');
+
+  const files = new Map();
+  for (const change of await fetchAll(request, '/diffs')) {
+    if (change.deleted_file) continue;
+    files.set(change.new_path, { oldPath: change.old_path, lines: parseDiffLines(change.diff) });
+  }
+
+  const threads = [];
+  for (const discussion of await fetchAll(request, '/discussions')) {
+    const note = (discussion.notes || [])[0];
+    const marker = note && String(note.body || '').match(MARKER);
+    if (!marker) continue;
+    threads.push({ id: discussion.id, fingerprint: marker[1], source: marker[2], resolved: Boolean(note.resolved) });
+  }
+
+  return {
+    refs: { base_sha: latest.base_commit_sha, start_sha: latest.start_commit_sha, head_sha: latest.head_commit_sha },
+    files,
+    threads
+  };
+}
+
+/**
+ * Path relative to the project checkout
+ * @param {string} repoPath - Root the finding paths are relative to
+ * @param {string} file - Finding path
+ * @param {string} workspace - Checkout root
+ * @returns {string}
+ */
+function projectPath(repoPath, file, workspace) {
+  return path.relative(workspace, path.resolve(repoPath, file)).replace(/\\/g, '/');
+}
+
+/**
+ * Findings with their CODEOWNERS owners
+ * @param {string} repoPath - Root the finding paths are relative to
+ * @param {Object[]} findings
+ * @param {Object} options - `codeowners`: parsed CODEOWNERS, false for none (default: read from repoPath)
+ * @returns {Object[]}
+ */
+function withOwners(repoPath, findings, options) {
+  const owners = options.codeowners !== undefined ? options.codeowners || null : codeowners.readCodeowners(repoPath);
+  return codeowners.attachOwners(findings, owners);
+}
+
+/**
+ * Thread-ready slop findings
+ * The pattern description is posted, never the flagged content, which may hold a secret.
+ * @param {string} repoPath - Scanned root
+ * @param {Array} findings - Pipeline findings
+ * @param {Object} [options]
+ * @param {string} [options.workspace] - Checkout root (default: cwd)
+ * @param {Object|false} [options.codeowners] - Parsed CODEOWNERS for findings without `owners` (default: read from repoPath)
+ * @returns {Object[]} `{source, fingerprint, file, line, severity, title, message}`, plus `owners` with a CODEOWNERS
+ */
+function slopThreads(repoPath, findings, options = {}) {
+  const workspace = options.workspace || process.cwd();
+  return keyFindings(repoPath, withOwners(repoPath, findings.filter(finding => finding.file && finding.line), options)).map(({ file, fingerprint, finding }) => ({
+    source: 'slop',
+    fingerprint,
+    file: projectPath(repoPath, file, workspace),
+    line: finding.line,
+    severity: finding.severity || 'medium',
+    title: finding.patternName || 'unknown',
+    message: finding.description || finding.patternName,
+    ...(finding.owners ? { owners: finding.owners } : {})
+  }));
+}
+
+/**
+ * Thread-ready review findings (false positives dropped)
+ * @param {string} repoPath - Root the review paths are relative to
+ * @param {Array} findings - Review findings ({file, line, severity, category|pass, description, suggestion})
+ * @param {Object} [options]
+ * @param {string} [options.workspace] - Checkout root (default: cwd)
+ * @param {Object|false} [options.codeowners] - Parsed CODEOWNERS (default: read from repoPath)
+ * @returns {Object[]}
+ */
+function reviewThreads(repoPath, findings, options = {}) {
+  const workspace = options.workspace || process.cwd();
+  const kept = withOwners(repoPath, findings.filter(finding => finding && finding.file && finding.line && !finding.falsePositive), options)
+    .map(finding => ({ ...finding, patternName: `review/${finding.category || finding.pass || 'general'}`, content: finding.description }));
+  return keyFindings(repoPath, kept).map(({ file, patternName, fingerprint, finding }) => ({
+    source: 'review',
+    fingerprint,
+    file: projectPath(repoPath, file, workspace),
+    line: finding.line,
+    severity: finding.severity || 'medium',
+    title: patternName,
+    message: finding.suggestion ? `${finding.description}\n\n**Suggestion**: ${finding.suggestion}` : finding.description,
+    ...(finding.owners ? { owners: finding.owners } : {})
+  }));
+}
+
+/**
+ * First note of a finding thread
+ * @param {Object} finding - Thread-ready finding
+ * @returns {string}
+ */
+function threadBody(finding) {
+  return [
+    t(finding.source === 'slop' ? 'thread.slop' : 'thread.review', { title: finding.title, severity: finding.severity }),
+    '',
+    finding.message,
+    // Code spans, so listing the owners does not mention them on every thread
+    ...(finding.owners && finding.owners.length > 0 ? ['', t('thread.owners', { owners: finding.owners.map(owner => `\`${owner}\``).join(', ') })] : []),
+    '',
+    `<!-- awesome-slash:finding fingerprint=${finding.fingerprint} source=${finding.source} -->`
+  ].join('\n');
+}
+
+/**
+ * Decide which threads to open, reopen, and resolve
+ * Only threads from the same source are resolved, so a slop run never
+ * touches review threads.
+ * @param {Object[]} findings - Thread-ready findings
+ * @param {Object} mergeRequest - Result of loadMergeRequest
+ * @param {Object} [options]
+ * @param {string} options.source - `slop` or `review`
+ * @param {string} [options.minSeverity='medium'] - Least severe finding posted
+ * @param {number} [options.limit=MAX_NEW_THREADS] - New threads per run
+ * @returns {{create: Object[], reopen: Object[], resolve: Object[], unchanged: number, outsideDiff: number, deferred: number}}
+ */
+function planThreads(findings, mergeRequest, options = {}) {
+  const minRank = SEVERITIES.indexOf(options.minSeverity || 'medium');
+  const limit = options.limit || MAX_NEW_THREADS;
+  const threads = new Map(mergeRequest.threads.filter(thread => thread.source === options.source).map(thread => [thread.fingerprint, thread]));
+  const plan = { create: [], reopen: [], resolve: [], unchanged: 0, outsideDiff: 0, deferred: 0 };
+  const seen = new Set();
+
+  const ordered = findings.slice().sort((a, b) => SEVERITIES.indexOf(a.severity) - SEVERITIES.indexOf(b.severity));
+  for (const finding of ordered) {
+    if (seen.has(finding.fingerprint)) continue;
+    seen.add(finding.fingerprint);
+    const thread = threads.get(finding.fingerprint);
+    if (thread) {
+      if (thread.resolved) plan.reopen.push(thread);
+      else plan.unchanged++;
+      continue;
+    }
+    if (SEVERITIES.indexOf(finding.severity) > minRank) continue;
+    const file = mergeRequest.files.get(finding.file);
+    if (!file || !file.lines.has(finding.line)) {
+      plan.outsideDiff++;
+      continue;
+    }
+    if (plan.create.length >= limit) {
+      plan.deferred++;
+      continue;
+    }
+    const oldLine = file.lines.get(finding.line);
+    plan.create.push({
+      finding,
+      body: threadBody(finding),
+      position: {
+        position_type: 'text',
+        ...mergeRequest.refs,
+        old_path: file.oldPath,
+        new_path: finding.file,
+        new_line: finding.line,
+        ...(oldLine !== null ? { old_line: oldLine } : {})
+      }
+    });
+  }
+
+  for (const thread of threads.values()) {
+    if (!thread.resolved && !seen.has(thread.fingerprint)) plan.resolve.push(thread);
+  }
+  return plan;
+}
+
+/**
+ * Post findings to the merge request and resolve fixed ones
+ * @param {Object[]} findings - Thread-ready findings
+ * @param {Object} options
+ * @param {string} options.source - `slop` or `review`
+ * @param {Object} [options.context] - Result of mergeRequestContext (default: from the environment)
+ * @param {Function} [options.request] - `(method, pathname, body) => Promise<Object>` (default: GitLab API)
+ * @param {string} [options.minSeverity='medium'] - Least severe finding posted
+ * @param {number} [options.limit] - New threads per run
+ * @param {boolean} [options.requestReviewers=false] - Add the CODEOWNERS of newly posted findings as reviewers
+ * @returns {Promise<{success: boolean, created?: number, reopened?: number, resolved?: number, unchanged?: number, outsideDiff?: number,
+ *   deferred?: number, reviewers?: string[], error?: string}>}
+ */
+async function syncMergeRequest(findings, options) {
+  const context = options.context || mergeRequestContext();
+  if (!options.request) {
+    if (!context) return { success: false, error: 'Not running in a GitLab merge request pipeline' };
+    if (!context.token) return { success: false, error: `Set ${TOKEN_VARIABLES.join(' or ')} (api scope) to post merge request threads; CI_JOB_TOKEN cannot` };
+  }
+  const request = options.request || createRequest(context);
+
+  try {
+    const plan = planThreads(findings, await loadMergeRequest(request), options);
+    for (const { body, position } of plan.create) await request('POST', '/discussions', { body, position });
+    for (const thread of plan.reopen) await request('PUT', `/discussions/${thread.id}`, { resolved: false });
+    for (const thread of plan.resolve) {
+      await request('POST', `/discussions/${thread.id}/notes`, { body: t('thread.resolving') });
+      await request('PUT', `/discussions/${thread.id}`, { resolved: true });
+    }
+    // Only owners of new threads, so re-runs do not ask again; groups cannot be reviewers
+    const reviewers = options.requestReviewers ? codeowners.reviewersFor(plan.create.map(entry => entry.finding)).users : [];
+    if (reviewers.length > 0) await request('POST', '/notes', { body: `/assign_reviewer ${reviewers.map(user => `@${user}`).join(' ')}` });
+    return {
+      success: true,
+      created: plan.create.length,
+      reopened: plan.reopen.length,
+      resolved: plan.resolve.length,
+      unchanged: plan.unchanged,
+      outsideDiff: plan.outsideDiff,
+      deferred: plan.deferred,
+      ...(options.requestReviewers ? { reviewers } : {})
+    };
+  } catch (error) {
+    return { success: false, error: error.message };
+  }
+}
+
+/**
+ * One-line outcome for CI logs
+ * @param {Object} result - Result of syncMergeRequest
+ * @returns {string}
+ */
+function describeSync(result) {
+  if (!result.success) return t('thread.syncSkipped', { error: result.error });
+  const parts = ['created', 'reopened', 'resolved', 'unchanged'].map(key => t(`thread.${key}`, { count: result[key] }));
+  if (result.outsideDiff) parts.push(t('thread.outsideDiff', { count: result.outsideDiff }));
+  if (result.deferred) parts.push(t('thread.deferred', { count: result.deferred, limit: MAX_NEW_THREADS }));
+  if (result.reviewers && result.reviewers.length) parts.push(t('thread.reviewers', { reviewers: result.reviewers.map(user => `@${user}`).join(' ') }));
+  return t('thread.sync', { parts: parts.join(', ') });
+}
+
+module.exports = {
+  TOKEN_VARIABLES,
+  MAX_NEW_THREADS,
+  mergeRequestContext,
+  createRequest,
+  parseDiffLines,
+  loadMergeRequest,
+  slopThreads,
+  reviewThreads,
+  threadBody,
+  planThreads,
+  syncMergeRequest,
+  describeSync
+};
+
+// CLI usage
+if (require.main === module) {
+  const [kind, file, ...flags] = process.argv.slice(2);
+  if (kind !== 'review' || !file) {
+    console.error('Usage: node gitlab-mr.js review <review-queue.json> [--request-reviewers]');
+    process.exit(1);
+  }
+  let findings;
+  try {
+    const queue = JSON.parse(fs.readFileSync(file, 'utf8'));
+    findings = Array.isArray(queue) ? queue : (queue.items || []);
+  } catch (error) {
+    console.error(`Failed to read ${file}: ${error.message}`);
+    process.exit(1);
+  }
+  const context = mergeRequestContext();
+  syncMergeRequest(reviewThreads(process.cwd(), findings, { workspace: context ? context.workspace : undefined }), { source: 'review', context, requestReviewers: flags.includes('--request-reviewers') })
+    .then(result => {
+      console.error(describeSync(result));
+      if (!result.success) process.exitCode = 1;
+    });
+}
@@ -0,0 +1,194 @@
+/**
+ * Secret Detection Helpers
+ *
+ * Support for the `secrets` pattern category: Shannon entropy for patterns
+ * flagged `requiresEntropyCheck`, redaction of finding content so reports and
+ * CI logs never echo a credential, and detection of values from local `.env`
+ * files that were pasted into source. `.env` values are only held in memory;
+ * findings name the variable, never the value.
+ *
+ * @module patterns/secrets
+ * @author Avi Fenesh
+ * @license MIT
+ */
+
+const fs = require('fs');
+const path = require('path');
+
+/**
+ * Env files that hold real values (checked at the repository root)
+ */
+const ENV_FILE_PATTERN = /^\.env(?:\.[\w-]+)?$/;
+
+/**
+ * Env files that document variables rather than hold them
+ */
+const ENV_TEMPLATE_PATTERN = /\.(?:example|sample|template|dist|defaults?|schema)$/i;
+
+/**
+ * Values too short or too regular to identify a secret
+ */
+const MIN_ENV_VALUE_LENGTH = 8;
+const MIN_ENV_VALUE_ENTROPY = 3;
+
+/**
+ * Shannon entropy in bits per character
+ * @param {string} value
+ * @returns {number}
+ */
+function shannonEntropy(value) {
+  if (!value) return 0;
+  const counts = new Map();
+  for (const char of value) counts.set(char, (counts.get(char) || 0) + 1);
+  let entropy = 0;
+  for (const count of counts.values()) {
+    const p = count / value.length;
+    entropy -= p * Math.log2(p);
+  }
+  return entropy;
+}
+
+/**
+ * Value a secret pattern matched: its first capture group, or the match without quotes
+ * @param {RegExpMatchArray} match
+ * @returns {string}
+ */
+function matchedSecretValue(match) {
+  const group = match.slice(1).find(value => typeof value === 'string' && value.length > 0);
+  return (group !== undefined ? group : match[0]).replace(/^["'`]|["'`]$/g, '');
+}
+
+/**
+ * Mask a secret with a fixed-width mask (length is not revealed), keeping a
+ * short prefix for values long enough to stay unguessable
+ * @param {string} value
+ * @returns {string}
+ */
+function maskValue(value) {
+  return (value.length >= 16 ? value.slice(0, 4) : '') + '********';
+}
+
+/**
+ * Redact secret values in a line of source
+ *
+ * Masks string literal contents, unquoted `KEY=value` / `key: value`
+ * values, and long tokens mixing letters and digits (bare API keys).
+ *
+ * @param {string} text - Finding content
+ * @returns {string}
+ */
+function redactSecret(text) {
+  if (!text) return text;
+  return text
+    .replace(/(["'`])([^"'`]{4,})\1/g, (match, quote, value) => `${quote}${maskValue(value)}${quote}`)
+    .replace(/([:=]\s*)([^\s"'`,;*][^\s"'`,;]{5,})/g, (match, prefix, value) => `${prefix}${maskValue(value)}`)
+    .replace(/[A-Za-z0-9+/_-]{20,}={0,2}/g, token =>
+      (/\d/.test(token) && /[A-Za-z]/.test(token) ? maskValue(token) : token));
+}
+
+/**
+ * Parse `KEY=value` lines of an env file
+ * @param {string} content - File content
+ * @returns {Array<{key: string, value: string}>}
+ */
+function parseEnvFile(content) {
+  const entries = [];
+  for (const line of content.split('\n')) {
+    const match = line.match(/^\s*(?:export\s+)?([A-Za-z_][\w.]*)\s*=\s*(.*?)\s*$/);
+    if (!match) continue;
+    let value = match[2];
+    const quoted = value.match(/^(["'])(.*)\1$/);
+    if (quoted) {
+      value = quoted[2];
+    } else {
+      value = value.replace(/\s+#.*$/, '');
+    }
+    entries.push({ key: match[1], value });
+  }
+  return entries;
+}
+
+/**
+ * Read secret-looking values from env files at the repository root
+ * @param {string} repoPath - Repository root
+ * @param {Object} [options]
+ * @param {Object} [options.fs] - File system module (for testing)
+ * @returns {Array<{file: string, key: string, value: string}>}
+ */
+function readEnvValues(repoPath, options = {}) {
+  const fsModule = options.fs || fs;
+  let names;
+  try {
+    names = fsModule.readdirSync(repoPath);
+  } catch {
+    return [];
+  }
+
+  const values = [];
+  for (const name of names.sort()) {
+    if (!ENV_FILE_PATTERN.test(name) || ENV_TEMPLATE_PATTERN.test(name)) continue;
+    let content;
+    try {
+      content = fsModule.readFileSync(path.join(repoPath, name), 'utf8');
+    } catch {
+      continue;
+    }
+    for (const { key, value } of parseEnvFile(content)) {
+      if (value.length < MIN_ENV_VALUE_LENGTH || shannonEntropy(value) < MIN_ENV_VALUE_ENTROPY) continue;
+      if (/^(?:\$\{.*\}|<.*>|changeme|placeholder)$/i.test(value)) continue;
+      values.push({ file: name, key, value });
+    }
+  }
+  return values;
+}
+
+/**
+ * Find lines in source files that contain a value from a local env file
+ * @param {string} repoPath - Repository root
+ * @param {string[]} files - Files to check (relative to repoPath)
+ * @param {Object} [options]
+ * @param {Array} [options.envValues] - Pre-read values (defaults to readEnvValues)
+ * @param {Function} [options.readFile] - File reader (for testing)
+ * @returns {Array<{file: string, line: number, envFile: string, key: string, content: string}>} content is redacted
+ */
+function findEnvValueLeaks(repoPath, files, options = {}) {
+  const envValues = options.envValues || readEnvValues(repoPath);
+  if (envValues.length === 0) return [];
+  const readFile = options.readFile || (file => fs.readFileSync(path.isAbsolute(file) ? file : path.join(repoPath, file), 'utf8'));
+
+  const leaks = [];
+  for (const file of files) {
+    if (ENV_FILE_PATTERN.test(path.basename(file))) continue;
+    let content;
+    try {
+      content = readFile(file);
+    } catch {
+      continue;
+    }
+    if (!envValues.some(({ value }) => content.includes(value))) continue;
+
+    const lines = content.split('\n');
+    for (let i = 0; i < lines.length; i++) {
+      const leaked = envValues.find(({ value }) => lines[i].includes(value));
+      if (!leaked) continue;
+      leaks.push({
+        file,
+        line: i + 1,
+        envFile: leaked.file,
+        key: leaked.key,
+        content: lines[i].split(leaked.value).join(maskValue(leaked.value)).trim().substring(0, 100)
+      });
+    }
+  }
+  return leaks;
+}
+
+module.exports = {
+  ENV_FILE_PATTERN,
+  shannonEntropy,
+  matchedSecretValue,
+  redactSecret,
+  parseEnvFile,
+  readEnvValues,
+  findEnvValueLeaks
+};
@@ -3,8 +3,9 @@
  * Platform Detection Infrastructure
  * Auto-detects project configuration for zero-config slash commands
  *
- * Usage: node lib/platform/detect-platform.js
- * Output: JSON with detected platform information
+ * Usage: node lib/platform/detect-platform.js [--refresh] [--log-level debug]
+ * Output: JSON with detected platform information; `--log-level debug` traces
+ * cache decisions and failed detectors on stderr (namespace `platform:detect`)
  *
  * @author Avi Fenesh
  * @license MIT
@@ -12,19 +13,40 @@
 
 const fs = require('fs');
 const path = require('path');
-const { exec } = require('child_process');
-const { promisify } = require('util');
-
-const execAsync = promisify(exec);
 const fsPromises = fs.promises;
 
 // Import shared utilities
 const { CacheManager } = require('../utils/cache-manager');
+const { createCache } = require('../cache');
 const {
   CI_CONFIGS,
+  CI_PIPELINE_SOURCES,
   DEPLOYMENT_CONFIGS,
-  PACKAGE_MANAGER_CONFIGS
+  PACKAGE_MANAGER_CONFIGS,
+  WORKSPACE_TOOL_CONFIGS,
+  CONTAINER_CONFIGS,
+  IAC_CONFIGS,
+  SERVERLESS_CONFIGS,
+  CONFIDENCE_CONFIGS,
+  BRANCH_STRATEGIES
 } = require('./detection-configs');
+const { lowerBound, parseVersion } = require('./detect-runtimes');
+const { CONFIG_FILENAMES, parseConfig, readConfigFile } = require('../config');
+const { DEFAULT_EXCLUDE_DIRS } = require('../utils/ignore');
+const git = require('../utils/git');
+const exec = require('../utils/exec');
+const { getStateDirPath } = require('./state-dir');
+const { createLogger, applyLogArgs } = require('../utils/logger');
+const {
+  PROJECT_DETECTORS_DIR,
+  registerDetector,
+  unregisterDetector,
+  getDetectors,
+  loadProjectDetectors,
+  runDetectors
+} = require('./detector-registry');
+
+const log = createLogger('platform:detect');
 
 /**
  * Default timeout for async operations (5 seconds)
@@ -36,6 +58,36 @@ const DEFAULT_ASYNC_TIMEOUT_MS = 5000;
  */
 const MAX_JSON_SIZE_BYTES = 1024 * 1024;
 
+/**
+ * Workspace expansion limits - bounds directory walks in large monorepos
+ */
+const MAX_WORKSPACE_PACKAGES = 500;
+const MAX_WORKSPACE_DEPTH = 4;
+
+/**
+ * Persisted detection cache (`platform` in the shared `detection` cache namespace)
+ * Entries are reused across processes until a marker file or git ref changes
+ */
+const PERSISTED_CACHE_NAMESPACE = 'detection';
+const PERSISTED_CACHE_KEY = 'platform';
+const PERSISTED_CACHE_VERSION = 5; // Bump when the detection result shape changes
+const PERSISTED_CACHE_MAX_AGE_MS = 24 * 60 * 60 * 1000;
+const MAX_FINGERPRINT_DIRS = 500;
+
+/**
+ * Root files whose presence or content feeds detection
+ */
+const PLATFORM_MARKER_FILES = [
+  'package.json', 'requirements.txt', 'pyproject.toml', 'setup.py', 'Cargo.toml', 'go.mod',
+  'pom.xml', 'build.gradle', 'pnpm-workspace.yaml', 'lerna.json', 'PLAN.md', 'TECHNICAL_DEBT.md',
+  '.gitmodules', ...CONFIG_FILENAMES
+];
+
+/**
+ * Git refs (relative to the common git dir) that branch detection reads
+ */
+const GIT_FINGERPRINT_REFS = ['packed-refs', 'refs/heads', 'refs/remotes', 'refs/remotes/origin', 'refs/remotes/origin/HEAD', 'refs/tags'];
+
 /**
  * Safely parse JSON content with size limit
  * @param {string} content - JSON string to parse
@@ -77,14 +129,13 @@ function withTimeout(promise, timeoutMs = DEFAULT_ASYNC_TIMEOUT_MS, operation =
 }
 
 /**
- * Execute a command with timeout protection
- * @param {string} cmd - Command to execute
- * @param {Object} options - exec options
+ * Run git in the working directory with timeout protection
+ * @param {string[]} args - git arguments
  * @param {number} timeoutMs - Timeout in milliseconds
- * @returns {Promise<{stdout: string, stderr: string}>}
+ * @returns {Promise<string|null>} stdout, or null on failure
  */
-async function execWithTimeout(cmd, options = {}, timeoutMs = DEFAULT_ASYNC_TIMEOUT_MS) {
-  return withTimeout(execAsync(cmd, options), timeoutMs, `exec: ${cmd.substring(0, 50)}`);
+function gitWithTimeout(args, timeoutMs = DEFAULT_ASYNC_TIMEOUT_MS) {
+  return exec.runAsync(process.cwd(), ['git', ...args], { timeout: timeoutMs });
 }
 
 // Maximum cached file size constant
@@ -94,6 +145,7 @@ const MAX_CACHED_FILE_SIZE = 64 * 1024; // 64KB max per cached file
 const _detectionCache = new CacheManager({ maxSize: 1, ttl: 60000 });
 const _fileCache = new CacheManager({ maxSize: 100, ttl: 60000, maxValueSize: MAX_CACHED_FILE_SIZE });
 const _existsCache = new CacheManager({ maxSize: 100, ttl: 60000 });
+const _listingCache = new CacheManager({ maxSize: 4, ttl: 60000 });
 
 /**
  * Generic file-based detector
@@ -166,28 +218,205 @@ async function detectCI() {
   return detectFromFiles(CI_CONFIGS, existsCached);
 }
 
+/**
+ * Strip quotes and trailing comments from a YAML scalar
+ * @param {string} value - Raw value
+ * @returns {string}
+ */
+function cleanYamlValue(value) {
+  return value.replace(/\s+#.*$/, '').trim().replace(/^(['"])(.*)\1$/, '$2');
+}
+
+/**
+ * Read a top-level `key: value` from YAML
+ * @param {string} content - YAML content
+ * @param {string} key - Key name
+ * @returns {string|null}
+ */
+function yamlTopLevelValue(content, key) {
+  const match = content.match(new RegExp(`^${key}:[ \\t]*(\\S.*)$`, 'm'));
+  return match ? cleanYamlValue(match[1]) || null : null;
+}
+
+/**
+ * Read the keys directly under a top-level YAML mapping (`jobs:`, `steps:`)
+ * @param {string} content - YAML content
+ * @param {string} key - Top-level key
+ * @returns {string[]}
+ */
+function yamlMappingKeys(content, key) {
+  const keys = [];
+  let inside = false;
+  let indent = null;
+  for (const line of content.split(/\r?\n/)) {
+    if (!inside) {
+      inside = new RegExp(`^${key}:\\s*(#.*)?$`).test(line);
+      continue;
+    }
+    if (/^\S/.test(line)) break;
+    // A list under the key (`- name: x`) is not a mapping
+    if (indent === null && /^\s*-\s/.test(line)) return [];
+    const match = line.match(/^(\s+)(['"]?)([\w.-]+)\2:/);
+    if (!match) continue;
+    if (indent === null) indent = match[1].length;
+    if (match[1].length === indent) keys.push(match[3]);
+  }
+  return keys;
+}
+
+/**
+ * Read every `- key: value` list item from YAML
+ * @param {string} content - YAML content
+ * @param {string} key - Item key
+ * @returns {string[]}
+ */
+function yamlListValues(content, key) {
+  const values = [];
+  for (const match of content.matchAll(new RegExp(`^\\s*-\\s*${key}:[ \\t]*(\\S.*)$`, 'gm'))) {
+    const value = cleanYamlValue(match[1]);
+    if (value) values.push(value);
+  }
+  return values;
+}
+
+/**
+ * GitLab CI top-level keys that are not jobs
+ */
+const GITLAB_RESERVED_KEYS = new Set([
+  'stages', 'variables', 'default', 'include', 'workflow', 'image', 'services',
+  'before_script', 'after_script', 'cache', 'pages'
+]);
+
+/**
+ * Read pipeline and job names from a CI definition file
+ * @param {string} platform - CI platform identifier
+ * @param {string} file - Definition file path
+ * @param {string} content - File content
+ * @returns {Array<{platform: string, file: string, name: string, jobs: string[]}>}
+ */
+function parseCIPipelines(platform, file, content) {
+  if (!content || typeof content !== 'string') return [];
+  const fallbackName = path.posix.basename(file).replace(/\.ya?ml$/i, '').replace(/^\./, '');
+  const pipeline = (name, jobs) => ({ platform, file, name: name || fallbackName, jobs: Array.from(new Set(jobs)) });
+
+  switch (platform) {
+    case 'github-actions':
+      return [pipeline(yamlTopLevelValue(content, 'name'), yamlMappingKeys(content, 'jobs'))];
+    case 'gitlab-ci': {
+      const jobs = Array.from(content.matchAll(/^(['"]?)([\w][\w .:/-]*)\1:\s*(#.*)?$/gm))
+        .map(match => match[2])
+        .filter(name => !GITLAB_RESERVED_KEYS.has(name));
+      return [pipeline(null, jobs)];
+    }
+    case 'circleci':
+      return [pipeline(null, yamlMappingKeys(content, 'jobs'))];
+    case 'azure-pipelines': {
+      // `name:` is usually a run-number format such as $(Date:yyyyMMdd)
+      const name = yamlTopLevelValue(content, 'name');
+      const jobs = [...yamlListValues(content, 'stage'), ...yamlListValues(content, 'job')];
+      return [pipeline(name && !name.includes('$(') ? name : null, jobs)];
+    }
+    case 'buildkite':
+      return [pipeline(null, yamlListValues(content, 'label'))];
+    case 'drone':
+      // One YAML document per pipeline
+      return content.split(/^---\s*$/m)
+        .filter(doc => /^kind:\s*pipeline/m.test(doc))
+        .map(doc => pipeline(yamlTopLevelValue(doc, 'name'), yamlListValues(doc, 'name')));
+    case 'woodpecker': {
+      const steps = yamlMappingKeys(content, 'steps');
+      const legacy = steps.length > 0 ? steps : yamlMappingKeys(content, 'pipeline');
+      return [pipeline(null, legacy.length > 0 ? legacy : yamlListValues(content, 'name'))];
+    }
+    default:
+      return [pipeline(yamlTopLevelValue(content, 'name'), yamlListValues(content, 'name'))];
+  }
+}
+
+/**
+ * Detects CI pipelines for every CI platform present, with job names
+ * @returns {Promise<Array<{platform: string, file: string, name: string, jobs: string[]}>>}
+ */
+async function detectCIPipelines() {
+  const checks = await Promise.all(CI_CONFIGS.map(({ file }) => existsCached(file)));
+  const platforms = Array.from(new Set(CI_CONFIGS.filter((config, i) => checks[i]).map(config => config.platform)));
+
+  const pipelines = [];
+  for (const platform of platforms) {
+    const source = CI_PIPELINE_SOURCES[platform] || {};
+    const files = [...(source.files || [])];
+    if (source.dir) {
+      try {
+        const entries = await fsPromises.readdir(source.dir, { withFileTypes: true });
+        const names = entries.filter(entry => entry.isFile() && /\.ya?ml$/i.test(entry.name)).map(entry => entry.name);
+        files.push(...names.sort().map(name => `${source.dir}/${name}`));
+      } catch {}
+    }
+    for (const file of files) {
+      const content = await readFileCached(file);
+      pipelines.push(...parseCIPipelines(platform, file, content));
+    }
+  }
+  return pipelines;
+}
+
+/**
+ * Detects every deployment platform configured in the repo
+ * @returns {Promise<string[]>} Platform names in DEPLOYMENT_CONFIGS order
+ */
+async function detectDeployments() {
+  const platforms = [];
+  for (const { platform } of await matchDeploymentConfigs()) {
+    if (!platforms.includes(platform)) platforms.push(platform);
+  }
+  return platforms;
+}
+
+/**
+ * Deployment configs that match, at most one per file
+ * @returns {Promise<Array<Object>>} Matching `DEPLOYMENT_CONFIGS` entries in config order
+ */
+async function matchDeploymentConfigs() {
+  const checks = await Promise.all(DEPLOYMENT_CONFIGS.map(async config => {
+    if (!(await existsCached(config.file))) return false;
+    if (!config.contains) return true;
+    const content = await readFileCached(config.file);
+    return typeof content === 'string' && config.contains.test(content);
+  }));
+
+  const claimedFiles = new Set();
+  return DEPLOYMENT_CONFIGS.filter((config, i) => {
+    if (!checks[i] || claimedFiles.has(config.file)) return false;
+    claimedFiles.add(config.file);
+    return true;
+  });
+}
+
 /**
  * Detects deployment platform by scanning for platform-specific files
  * @returns {Promise<string|null>} Deployment platform name or null if not detected
  */
 async function detectDeployment() {
-  return detectFromFiles(DEPLOYMENT_CONFIGS, existsCached);
+  const platforms = await detectDeployments();
+  return platforms[0] || null;
 }
 
 /**
  * Detects project type by scanning for language-specific files
+ * @param {string} [dir] - Directory relative to the repo root (defaults to the root)
  * @returns {Promise<string>} Project type identifier
  */
-async function detectProjectType() {
+async function detectProjectType(dir) {
+  const inDir = file => (dir ? path.join(dir, file) : file);
   const checks = await Promise.all([
-    existsCached('package.json'),
-    existsCached('requirements.txt'),
-    existsCached('pyproject.toml'),
-    existsCached('setup.py'),
-    existsCached('Cargo.toml'),
-    existsCached('go.mod'),
-    existsCached('pom.xml'),
-    existsCached('build.gradle')
+    existsCached(inDir('package.json')),
+    existsCached(inDir('requirements.txt')),
+    existsCached(inDir('pyproject.toml')),
+    existsCached(inDir('setup.py')),
+    existsCached(inDir('Cargo.toml')),
+    existsCached(inDir('go.mod')),
+    existsCached(inDir('pom.xml')),
+    existsCached(inDir('build.gradle'))
   ]);
 
   if (checks[0]) return 'nodejs';
@@ -200,52 +429,830 @@ async function detectProjectType() {
 
 /**
  * Detects package manager by scanning for lockfiles
+ * @param {string} [dir] - Directory relative to the repo root (defaults to the root)
  * @returns {Promise<string|null>} Package manager name or null if not detected
  */
-async function detectPackageManager() {
+async function detectPackageManager(dir) {
   return detectFromFiles(
-    PACKAGE_MANAGER_CONFIGS.map(({ file, manager }) => ({ file, platform: manager })),
+    PACKAGE_MANAGER_CONFIGS.map(({ file, manager }) => ({ file: dir ? path.join(dir, file) : file, platform: manager })),
     existsCached
   );
 }
 
 /**
- * Detects branch strategy (single-branch vs multi-branch with dev+prod)
- * @returns {Promise<string>} 'single-branch' or 'multi-branch'
+ * Read and parse a JSON file (cached)
+ * @param {string} filepath - Path to read
+ * @returns {Promise<Object|null>}
  */
-async function detectBranchStrategy() {
-  try {
-    const [localResult, remoteResult] = await Promise.all([
-      execWithTimeout('git branch', { encoding: 'utf8' }).catch(() => ({ stdout: '' })),
-      execWithTimeout('git branch -r', { encoding: 'utf8' }).catch(() => ({ stdout: '' }))
-    ]);
+async function readJSONCached(filepath) {
+  const content = await readFileCached(filepath);
+  const parsed = safeJSONParse(content, filepath);
+  return parsed && typeof parsed === 'object' ? parsed : null;
+}
 
-    const allBranches = (localResult.stdout || '') + (remoteResult.stdout || '');
+/**
+ * Read the `packages:` list from pnpm-workspace.yaml
+ * @param {string|null} content - File content
+ * @returns {string[]}
+ */
+function parsePnpmWorkspace(content) {
+  if (!content || typeof content !== 'string') return [];
+  const patterns = [];
+  let inPackages = false;
+  for (const line of content.split(/\r?\n/)) {
+    if (/^packages\s*:/.test(line)) {
+      inPackages = true;
+      continue;
+    }
+    if (!inPackages) continue;
+    if (/^\S/.test(line)) break;
+    const item = line.match(/^\s*-\s*['"]?([^'"#]+?)['"]?\s*(?:#.*)?$/);
+    if (item) patterns.push(item[1]);
+  }
+  return patterns;
+}
 
-    const hasStable = allBranches.includes('stable');
-    const hasProduction = allBranches.includes('production') || allBranches.includes('prod');
+/**
+ * Expand one workspace glob (`packages/*`, `apps/**`, `tools/cli`) to directories
+ * @param {string} pattern - Workspace pattern relative to the repo root
+ * @returns {Promise<string[]>} Matching directories (posix, relative)
+ */
+async function expandWorkspacePattern(pattern) {
+  const segments = pattern.replace(/\\/g, '/').replace(/^\.\//, '').replace(/\/+$/, '').split('/').filter(Boolean);
+  let dirs = [''];
 
-    if (hasStable || hasProduction) {
-      return 'multi-branch';
+  for (const segment of segments) {
+    if (!/[*?]/.test(segment)) {
+      dirs = dirs.map(dir => (dir ? `${dir}/${segment}` : segment));
+      continue;
     }
 
-    if (await existsCached('railway.json')) {
+    const source = segment.replace(/[.+^${}()|[\]\\]/g, '\\$&').replace(/\*+/g, '[^/]*').replace(/\?/g, '[^/]');
+    const regex = new RegExp(`^${source}$`);
+    const next = [];
+    const queue = dirs.map(dir => ({ dir, depth: 0 }));
+    while (queue.length > 0 && next.length < MAX_WORKSPACE_PACKAGES) {
+      const { dir, depth } = queue.shift();
+      let entries = [];
       try {
-        const content = await readFileCached('railway.json');
-        if (content) {
-          const config = safeJSONParse(content, 'railway.json');
-          if (config &&
-              typeof config === 'object' &&
-              typeof config.environments === 'object' &&
-              config.environments !== null &&
-              Object.keys(config.environments).length > 1) {
-            return 'multi-branch';
-          }
+        entries = await fsPromises.readdir(dir || '.', { withFileTypes: true });
+      } catch {
+        continue;
+      }
+      for (const entry of entries) {
+        if (!entry.isDirectory() || entry.name.startsWith('.') || entry.name === 'node_modules') continue;
+        const child = dir ? `${dir}/${entry.name}` : entry.name;
+        if (segment === '**') {
+          next.push(child);
+          if (depth < MAX_WORKSPACE_DEPTH) queue.push({ dir: child, depth: depth + 1 });
+        } else if (regex.test(entry.name)) {
+          next.push(child);
         }
-      } catch {}
+      }
     }
+    dirs = next;
+  }
 
-    return 'single-branch';
+  return dirs.filter(Boolean);
+}
+
+/**
+ * Expand workspace patterns, honoring `!` exclusions
+ * @param {string[]} patterns - Workspace patterns
+ * @returns {Promise<string[]>} Sorted package directories
+ */
+async function resolveWorkspacePackages(patterns) {
+  const included = new Set();
+  const excluded = new Set();
+  for (const pattern of patterns) {
+    if (typeof pattern !== 'string' || !pattern.trim()) continue;
+    const negated = pattern.startsWith('!');
+    for (const dir of await expandWorkspacePattern(negated ? pattern.slice(1) : pattern)) {
+      (negated ? excluded : included).add(dir);
+    }
+  }
+  return Array.from(included).filter(dir => !excluded.has(dir)).sort().slice(0, MAX_WORKSPACE_PACKAGES);
+}
+
+/**
+ * Detects monorepo workspaces (pnpm, yarn, npm, Turborepo, Nx, Lerna)
+ * Each package gets its own project type and package manager; Node packages
+ * without a lockfile of their own inherit the workspace manager.
+ * @returns {Promise<Object|null>} `{tools, packageManager, packages}` or null for single-package repos
+ */
+async function detectWorkspaces() {
+  const [rootPackage, pnpmWorkspace, lerna, toolChecks, rootManager] = await Promise.all([
+    readJSONCached('package.json'),
+    existsCached('pnpm-workspace.yaml').then(found => (found ? readFileCached('pnpm-workspace.yaml') : null)),
+    existsCached('lerna.json').then(found => (found ? readJSONCached('lerna.json') : null)),
+    Promise.all(WORKSPACE_TOOL_CONFIGS.map(({ file }) => existsCached(file))),
+    detectPackageManager()
+  ]);
+
+  const tools = [];
+  const patterns = [];
+  const pnpmPatterns = parsePnpmWorkspace(pnpmWorkspace);
+  if (pnpmPatterns.length > 0) {
+    tools.push('pnpm');
+    patterns.push(...pnpmPatterns);
+  }
+
+  const workspaces = rootPackage && rootPackage.workspaces;
+  const packageWorkspaces = Array.isArray(workspaces) ? workspaces : (workspaces && Array.isArray(workspaces.packages) ? workspaces.packages : []);
+  if (packageWorkspaces.length > 0) {
+    tools.push(rootManager === 'yarn' ? 'yarn' : rootManager === 'bun' ? 'bun' : 'npm');
+    patterns.push(...packageWorkspaces);
+  }
+
+  if (lerna) {
+    tools.push('lerna');
+    patterns.push(...(Array.isArray(lerna.packages) ? lerna.packages : ['packages/*']));
+  }
+
+  WORKSPACE_TOOL_CONFIGS.forEach(({ tool, patterns: defaults }, i) => {
+    if (!toolChecks[i]) return;
+    tools.push(tool);
+    // Nx projects may live outside package-manager workspaces
+    if (defaults) patterns.push(...defaults);
+  });
+
+  if (tools.length === 0) return null;
+
+  const packageManager = rootManager || (pnpmPatterns.length > 0 ? 'pnpm' : (lerna && lerna.npmClient) || (packageWorkspaces.length > 0 ? 'npm' : null));
+  const dirs = await resolveWorkspacePackages(Array.from(new Set(patterns)));
+  const packages = (await Promise.all(dirs.map(async dir => {
+    const [projectType, ownManager, manifest] = await Promise.all([
+      detectProjectType(dir),
+      detectPackageManager(dir),
+      readJSONCached(path.join(dir, 'package.json'))
+    ]);
+    if (projectType === 'unknown' && !(await existsCached(path.join(dir, 'project.json')))) return null;
+    return {
+      name: (manifest && typeof manifest.name === 'string' && manifest.name) || path.posix.basename(dir),
+      path: dir,
+      projectType,
+      packageManager: ownManager || (projectType === 'nodejs' ? packageManager : null)
+    };
+  }))).filter(Boolean);
+
+  return { tools, packageManager, packages };
+}
+
+/**
+ * Linked worktrees and submodules under the working directory
+ * Their files belong to other checkouts, so shallow walks skip them.
+ * @returns {Set<string>} Relative posix paths
+ */
+function nestedCheckoutDirs() {
+  try {
+    const { worktrees, submodules } = git.nestedCheckouts(process.cwd());
+    return new Set([...worktrees, ...submodules]);
+  } catch {
+    return new Set();
+  }
+}
+
+/**
+ * List files near the repo root, skipping dot and excluded directories
+ * and other checkouts (nestedCheckoutDirs)
+ * @param {number} maxDepth - Directory levels to descend
+ * @returns {Promise<string[]>} Relative posix paths
+ */
+async function listShallowFiles(maxDepth) {
+  const cached = _listingCache.get(String(maxDepth));
+  if (cached !== undefined) {
+    return cached;
+  }
+  const files = [];
+  const nested = nestedCheckoutDirs();
+  const queue = [{ dir: '', depth: 0 }];
+  while (queue.length > 0) {
+    const { dir, depth } = queue.shift();
+    let entries = [];
+    try {
+      entries = await fsPromises.readdir(dir || '.', { withFileTypes: true });
+    } catch {
+      continue;
+    }
+    for (const entry of entries) {
+      const rel = dir ? `${dir}/${entry.name}` : entry.name;
+      if (entry.isDirectory()) {
+        if (depth < maxDepth && !entry.name.startsWith('.') && !DEFAULT_EXCLUDE_DIRS.includes(entry.name) && !nested.has(rel)) {
+          queue.push({ dir: rel, depth: depth + 1 });
+        }
+      } else if (entry.isFile()) {
+        files.push(rel);
+      }
+    }
+  }
+  files.sort();
+  _listingCache.set(String(maxDepth), files);
+  return files;
+}
+
+/**
+ * Detects Dockerfiles, Compose files, Helm charts, Kustomize overlays, and Kubernetes manifests
+ * @returns {Promise<Object|null>} `{dockerfiles, compose, helmCharts, kustomizations, manifests, orchestrator}` or null
+ */
+async function detectContainerization() {
+  const files = await listShallowFiles(CONTAINER_CONFIGS.maxDepth);
+  const dirOf = file => path.posix.dirname(file);
+  const nameOf = file => path.posix.basename(file);
+
+  const dockerfiles = files.filter(file => CONTAINER_CONFIGS.dockerfile.test(nameOf(file)));
+  const compose = files.filter(file => CONTAINER_CONFIGS.compose.test(nameOf(file)));
+  const helmCharts = files.filter(file => CONTAINER_CONFIGS.helmChart.test(nameOf(file))).map(dirOf);
+  const kustomizations = files.filter(file => CONTAINER_CONFIGS.kustomization.test(nameOf(file))).map(dirOf);
+
+  // Chart templates are Go templates, not plain manifests
+  const inChart = file => helmCharts.some(chart => chart === '.' || file.startsWith(`${chart}/`));
+  const candidates = files.filter(file => /\.ya?ml$/i.test(file) &&
+    file.split('/').slice(0, -1).some(part => CONTAINER_CONFIGS.manifestDirs.includes(part.toLowerCase())) &&
+    !inChart(file) &&
+    !CONTAINER_CONFIGS.kustomization.test(nameOf(file)) &&
+    !CONTAINER_CONFIGS.compose.test(nameOf(file)));
+  const contents = await Promise.all(candidates.slice(0, CONTAINER_CONFIGS.maxManifests).map(readFileCached));
+  const manifests = candidates.filter((file, i) =>
+    typeof contents[i] === 'string' && /^apiVersion:/m.test(contents[i]) && /^kind:/m.test(contents[i]));
+
+  if (dockerfiles.length + compose.length + helmCharts.length + kustomizations.length + manifests.length === 0) {
+    return null;
+  }
+
+  const usesKubernetes = helmCharts.length > 0 || kustomizations.length > 0 || manifests.length > 0;
+  return {
+    dockerfiles,
+    compose,
+    helmCharts,
+    kustomizations,
+    manifests,
+    orchestrator: usesKubernetes ? 'kubernetes' : compose.length > 0 ? 'compose' : null
+  };
+}
+
+/**
+ * Map provider names to clouds, dropping utility providers
+ * @param {Iterable<string>} names - Provider or package names
+ * @returns {string[]} Sorted unique clouds
+ */
+function cloudsFor(names) {
+  const clouds = new Set();
+  for (const name of names) {
+    const cloud = IAC_CONFIGS.providers[String(name).toLowerCase()];
+    if (cloud) clouds.add(cloud);
+  }
+  return Array.from(clouds).sort();
+}
+
+/**
+ * Terraform providers referenced by configuration
+ * Reads `provider` blocks, `required_providers` sources, and resource/data prefixes.
+ * @param {string} content - .tf file content
+ * @returns {string[]} Provider names
+ */
+function parseTerraformProviders(content) {
+  if (!content || typeof content !== 'string') return [];
+  const names = [];
+  for (const match of content.matchAll(/^\s*provider\s+"([\w-]+)"/gm)) names.push(match[1]);
+  for (const match of content.matchAll(/source\s*=\s*"(?:[\w.-]+\/)?[\w-]+\/([\w-]+)"/g)) names.push(match[1]);
+  for (const match of content.matchAll(/^\s*(?:resource|data)\s+"([a-z0-9]+)_/gm)) names.push(match[1]);
+  return names;
+}
+
+/**
+ * Pulumi/CDKTF provider packages declared next to a project file
+ * @param {string} dir - Project directory ('.' for the root)
+ * @returns {Promise<string[]>} Provider names (e.g. 'aws' for @pulumi/aws)
+ */
+async function iacPackageProviders(dir) {
+  const inDir = file => (dir === '.' ? file : `${dir}/${file}`);
+  const [pkg, requirements, goMod, cdktf] = await Promise.all([
+    readJSONCached(inDir('package.json')),
+    readFileCached(inDir('requirements.txt')),
+    readFileCached(inDir('go.mod')),
+    readJSONCached(inDir('cdktf.json'))
+  ]);
+  const names = [];
+  if (pkg) {
+    for (const dep of Object.keys({ ...pkg.dependencies, ...pkg.devDependencies })) {
+      const match = dep.match(/^@pulumi\/([\w-]+)$|^@cdktf\/provider-([\w-]+)$/);
+      if (match) names.push(match[1] || match[2]);
+    }
+  }
+  if (typeof requirements === 'string') {
+    for (const match of requirements.matchAll(/^pulumi[-_]([\w-]+)/gim)) names.push(match[1].replace(/_/g, '-'));
+  }
+  if (typeof goMod === 'string') {
+    for (const match of goMod.matchAll(/github\.com\/pulumi\/pulumi-([\w-]+)\/sdk/g)) names.push(match[1]);
+  }
+  if (cdktf && Array.isArray(cdktf.terraformProviders)) {
+    for (const provider of cdktf.terraformProviders) {
+      const source = typeof provider === 'string' ? provider : provider && provider.name;
+      if (typeof source === 'string') names.push(source.split('@')[0].split('/').pop());
+    }
+  }
+  return names;
+}
+
+/**
+ * Detects Terraform modules, Pulumi projects, CDK apps, and CloudFormation templates
+ * @returns {Promise<Object|null>} `{tools, providers, projects}` or null
+ */
+async function detectInfrastructure() {
+  const files = await listShallowFiles(IAC_CONFIGS.maxDepth);
+  const dirOf = file => path.posix.dirname(file);
+  const nameOf = file => path.posix.basename(file);
+  const projects = [];
+
+  const terraformDirs = new Map();
+  for (const file of files.filter(candidate => IAC_CONFIGS.terraform.test(candidate))) {
+    if (!terraformDirs.has(dirOf(file))) terraformDirs.set(dirOf(file), []);
+    terraformDirs.get(dirOf(file)).push(file);
+  }
+  for (const [dir, tfFiles] of terraformDirs) {
+    const contents = await Promise.all(tfFiles.map(readFileCached));
+    projects.push({ tool: 'terraform', path: dir, providers: cloudsFor(contents.flatMap(parseTerraformProviders)), files: tfFiles });
+  }
+
+  for (const file of files.filter(candidate => IAC_CONFIGS.pulumi.test(nameOf(candidate)))) {
+    const content = await readFileCached(file);
+    const runtime = typeof content === 'string' && content.match(/^runtime:\s*(?:\n\s+name:\s*)?['"]?([\w-]+)/m);
+    projects.push({
+      tool: 'pulumi',
+      path: dirOf(file),
+      runtime: runtime ? runtime[1] : null,
+      providers: cloudsFor(await iacPackageProviders(dirOf(file))),
+      files: [file]
+    });
+  }
+
+  for (const file of files.filter(candidate => IAC_CONFIGS.cdk.test(nameOf(candidate)))) {
+    projects.push({ tool: 'cdk', path: dirOf(file), providers: ['aws'], files: [file] });
+  }
+  for (const file of files.filter(candidate => IAC_CONFIGS.cdktf.test(nameOf(candidate)))) {
+    projects.push({ tool: 'cdktf', path: dirOf(file), providers: cloudsFor(await iacPackageProviders(dirOf(file))), files: [file] });
+  }
+
+  const candidates = files.filter(file => IAC_CONFIGS.cloudformationCandidate.test(file)).slice(0, IAC_CONFIGS.maxTemplates);
+  const templates = await Promise.all(candidates.map(readFileCached));
+  const cloudformationDirs = new Map();
+  candidates.forEach((file, i) => {
+    if (typeof templates[i] !== 'string' || !IAC_CONFIGS.cloudformationMarker.test(templates[i])) return;
+    if (!cloudformationDirs.has(dirOf(file))) cloudformationDirs.set(dirOf(file), []);
+    cloudformationDirs.get(dirOf(file)).push(file);
+  });
+  for (const [dir, templateFiles] of cloudformationDirs) {
+    projects.push({ tool: 'cloudformation', path: dir, providers: ['aws'], files: templateFiles });
+  }
+
+  if (projects.length === 0) return null;
+
+  return {
+    tools: Array.from(new Set(projects.map(project => project.tool))),
+    providers: Array.from(new Set(projects.flatMap(project => project.providers))).sort(),
+    projects
+  };
+}
+
+/**
+ * Parse a YAML document into nested mappings
+ * Enough for Serverless/SAM configs: block mappings and scalar values. List
+ * items, flow collections, and block scalars are not expanded.
+ * @param {string|null} content - YAML content
+ * @returns {Object} Nested mappings (scalars as strings)
+ */
+function parseYamlMap(content) {
+  const root = {};
+  if (typeof content !== 'string') return root;
+  const stack = [{ indent: -1, node: root }];
+  for (const raw of content.split('\n')) {
+    const line = raw.replace(/\s+#.*$/, '');
+    if (!line.trim() || line.trim().startsWith('#') || line.trim().startsWith('-')) continue;
+    const match = line.trim().match(/^(['"]?)([^'":]+)\1\s*:(?:\s+(.*))?$/);
+    if (!match) continue;
+    const indent = line.search(/\S/);
+    while (stack.length > 1 && stack[stack.length - 1].indent >= indent) stack.pop();
+    const parent = stack[stack.length - 1].node;
+    const value = match[3] === undefined ? '' : match[3].trim().replace(/^(['"])(.*)\1$/, '$2');
+    if (value === '') {
+      parent[match[2]] = {};
+      stack.push({ indent, node: parent[match[2]] });
+    } else {
+      parent[match[2]] = value;
+    }
+  }
+  return root;
+}
+
+/**
+ * Fill a `SERVERLESS_CONFIGS.deployCommands` template for a function
+ * @param {Object} fn - Function entry
+ * @returns {string|null} Command, or null when a placeholder has no value
+ */
+function serverlessDeployCommand(fn) {
+  const template = SERVERLESS_CONFIGS.deployCommands[fn.framework];
+  if (!template) return null;
+  let missing = false;
+  const command = template.replace(/\{(\w+)\}/g, (placeholder, key) => {
+    if (fn[key] === null || fn[key] === undefined) missing = true;
+    return fn[key];
+  });
+  if (missing) return null;
+  return fn.framework === 'gcp-functions' && fn.trigger === 'http' ? `${command} --trigger-http` : command;
+}
+
+/**
+ * Functions declared in a Serverless Framework config
+ * @param {string} file - serverless.yml/json path
+ * @returns {Promise<Object[]>} Function entries (JS/TS configs yield none)
+ */
+async function serverlessFrameworkFunctions(file) {
+  const content = await readFileCached(file);
+  const config = /\.json$/.test(file) ? safeJSONParse(content, file) : (/\.ya?ml$/.test(file) ? parseYamlMap(content) : null);
+  if (!config || typeof config !== 'object' || !config.functions || typeof config.functions !== 'object') return [];
+
+  const provider = typeof config.provider === 'object' && config.provider !== null ? config.provider : {};
+  return Object.entries(config.functions)
+    .filter(([, fn]) => fn && typeof fn === 'object')
+    .map(([name, fn]) => ({
+      name,
+      framework: 'serverless',
+      provider: typeof provider.name === 'string' ? provider.name : null,
+      runtime: typeof fn.runtime === 'string' ? fn.runtime : (typeof provider.runtime === 'string' ? provider.runtime : null),
+      handler: typeof fn.handler === 'string' ? fn.handler : null,
+      path: path.posix.dirname(file),
+      file
+    }));
+}
+
+/**
+ * Functions declared in a SAM template
+ * @param {string} file - Template path
+ * @param {string} content - Template content
+ * @returns {Object[]} Function entries keyed by logical ID
+ */
+function samFunctions(file, content) {
+  const template = /\.json$/.test(file) ? safeJSONParse(content, file) : parseYamlMap(content);
+  if (!template || typeof template.Resources !== 'object' || template.Resources === null) return [];
+
+  const globals = (template.Globals && template.Globals.Function) || {};
+  return Object.entries(template.Resources)
+    .filter(([, resource]) => resource && resource.Type === SERVERLESS_CONFIGS.samFunctionType)
+    .map(([name, resource]) => {
+      const props = resource.Properties || {};
+      const runtime = props.Runtime || globals.Runtime;
+      const handler = props.Handler || globals.Handler;
+      return {
+        name,
+        framework: 'sam',
+        provider: 'aws',
+        runtime: typeof runtime === 'string' ? runtime : null,
+        handler: typeof handler === 'string' ? handler : null,
+        path: path.posix.dirname(file),
+        file
+      };
+    });
+}
+
+/**
+ * Google Cloud Functions runtime id for a function directory
+ * @param {string} language - node, python, or go
+ * @param {string} dir - Function source directory
+ * @returns {Promise<string|null>} e.g. `nodejs20`, `python312`, `go122`
+ */
+async function gcpRuntime(language, dir) {
+  const inDir = file => (dir === '.' ? file : `${dir}/${file}`);
+  if (language === 'node') {
+    const pkg = await readJSONCached(inDir('package.json'));
+    const bound = pkg && pkg.engines && typeof pkg.engines.node === 'string' ? lowerBound(pkg.engines.node) : null;
+    return bound ? `nodejs${parseVersion(bound)[0]}` : null;
+  }
+  let version = null;
+  if (language === 'python') {
+    version = parseVersion((await readFileCached(inDir('.python-version'))) || (await readFileCached('.python-version')));
+  } else if (language === 'go') {
+    const directive = ((await readFileCached(inDir('go.mod'))) || '').match(/^go\s+(\d+\.\d+)/m);
+    version = directive ? parseVersion(directive[1]) : null;
+  }
+  return version && version.length > 1 ? `${language}${version[0]}${version[1]}` : null;
+}
+
+/**
+ * Google Cloud Functions registered with the Functions Framework
+ * @param {string[]} files - Repository files (shallow listing)
+ * @returns {Promise<Object[]>} Function entries
+ */
+async function gcpFunctions(files) {
+  const functions = [];
+  const manifests = { node: 'package.json', python: 'requirements.txt', go: 'go.mod' };
+  const triggers = { http: 'http', HTTP: 'http' };
+  for (const [language, manifest] of Object.entries(manifests)) {
+    for (const file of files.filter(candidate => path.posix.basename(candidate) === manifest)) {
+      const content = await readFileCached(file);
+      if (typeof content !== 'string' || !SERVERLESS_CONFIGS.gcpDependencies[language].test(content)) continue;
+
+      const dir = path.posix.dirname(file);
+      const sources = language === 'go'
+        ? files.filter(candidate => path.posix.dirname(candidate) === dir && candidate.endsWith('.go'))
+        : SERVERLESS_CONFIGS.gcpSources[language].map(source => (dir === '.' ? source : `${dir}/${source}`));
+      const runtime = await gcpRuntime(language, dir);
+      for (const source of sources) {
+        const code = await readFileCached(source);
+        if (typeof code !== 'string') continue;
+        for (const match of code.matchAll(SERVERLESS_CONFIGS.gcpEntryPoints[language])) {
+          functions.push({
+            name: match[2],
+            framework: 'gcp-functions',
+            provider: 'gcp',
+            runtime,
+            handler: null,
+            entryPoint: match[2],
+            trigger: triggers[match[1]] || 'event',
+            path: dir,
+            file: source
+          });
+        }
+      }
+    }
+  }
+  return functions;
+}
+
+/**
+ * Firebase Functions from firebase.json codebases
+ * @returns {Promise<Object[]|null>} Function entries, or null without a `functions` config
+ */
+async function firebaseFunctions() {
+  const config = await readJSONCached('firebase.json');
+  if (!config || !config.functions) return null;
+
+  const functions = [];
+  for (const codebase of [].concat(config.functions)) {
+    if (!codebase || typeof codebase !== 'object') continue;
+    const dir = typeof codebase.source === 'string' ? codebase.source.replace(/\/+$/, '') : 'functions';
+    let runtime = typeof codebase.runtime === 'string' ? codebase.runtime : null;
+    if (!runtime) runtime = await gcpRuntime('node', dir);
+    for (const source of SERVERLESS_CONFIGS.firebaseSources.map(name => `${dir}/${name}`)) {
+      const code = await readFileCached(source);
+      if (typeof code !== 'string') continue;
+      for (const pattern of SERVERLESS_CONFIGS.firebaseEntryPoints) {
+        for (const match of code.matchAll(pattern)) {
+          functions.push({
+            name: match[1] || match[2],
+            framework: 'firebase-functions',
+            provider: 'gcp',
+            runtime,
+            handler: null,
+            path: dir,
+            file: source
+          });
+        }
+      }
+    }
+  }
+  return functions;
+}
+
+/**
+ * Detects serverless functions: Serverless Framework, AWS SAM, Google Cloud
+ * Functions, and Firebase Functions, with each function's runtime and a
+ * command that deploys only that function
+ * @returns {Promise<Object|null>} `{frameworks, files, functions}` or null
+ */
+async function detectServerless() {
+  const files = await listShallowFiles(SERVERLESS_CONFIGS.maxDepth);
+  const configFiles = [];
+  const functions = [];
+  const frameworks = new Set();
+
+  for (const file of files.filter(candidate => SERVERLESS_CONFIGS.serverless.test(path.posix.basename(candidate)))) {
+    configFiles.push(file);
+    frameworks.add('serverless');
+    functions.push(...await serverlessFrameworkFunctions(file));
+  }
+
+  const candidates = files.filter(file => IAC_CONFIGS.cloudformationCandidate.test(file)).slice(0, IAC_CONFIGS.maxTemplates);
+  const templates = await Promise.all(candidates.map(readFileCached));
+  candidates.forEach((file, i) => {
+    if (typeof templates[i] !== 'string' || !SERVERLESS_CONFIGS.samTransform.test(templates[i])) return;
+    configFiles.push(file);
+    frameworks.add('sam');
+    functions.push(...samFunctions(file, templates[i]));
+  });
+
+  const gcp = await gcpFunctions(files);
+  configFiles.push(...gcp.map(fn => fn.file));
+  functions.push(...gcp);
+  if (gcp.length > 0) frameworks.add('gcp-functions');
+
+  const firebase = await firebaseFunctions();
+  if (firebase) {
+    configFiles.push('firebase.json');
+    functions.push(...firebase);
+    frameworks.add('firebase-functions');
+  }
+
+  if (configFiles.length === 0) return null;
+
+  for (const fn of functions) {
+    fn.deployCommand = serverlessDeployCommand(fn);
+  }
+
+  return { frameworks: Array.from(frameworks), files: Array.from(new Set(configFiles)), functions };
+}
+
+/**
+ * List branch names from `git branch` output, without remote prefixes
+ * @param {string} output - `git branch` and `git branch -r` output
+ * @returns {string[]} Unique branch names
+ */
+function parseBranchNames(output) {
+  const names = output.split('\n')
+    .map(line => line.replace(/^[*+]?\s+/, '').trim())
+    .filter(line => line && !line.includes(' -> ') && !line.startsWith('('))
+    .map(line => line.replace(/^(remotes\/)?origin\//, ''));
+  return Array.from(new Set(names));
+}
+
+/**
+ * Read the `branchStrategy` override from the project config
+ * Accepts a model name, `single-branch`/`multi-branch`, or an object with
+ * `model`, `strategy`, `mainBranch`, `productionBranch`, `releaseBranches`,
+ * `releaseTags`, and `environments`.
+ * @returns {Promise<{override: Object|null, source: string|null, error: string|null}>}
+ */
+async function loadBranchStrategyOverride() {
+  for (const file of CONFIG_FILENAMES) {
+    let loaded;
+    if (file.endsWith('.js')) {
+      loaded = readConfigFile(process.cwd(), file);
+    } else {
+      const content = await readFileCached(file);
+      loaded = content === null ? null : parseConfig(content, file);
+    }
+    if (!loaded) continue;
+
+    const { config, error } = loaded;
+    if (error) return { override: null, source: file, error };
+    let value = config.branchStrategy;
+    if (value === undefined) return { override: null, source: null, error: null };
+
+    if (typeof value === 'string') {
+      value = ['single-branch', 'multi-branch'].includes(value) ? { strategy: value } : { model: value };
+    }
+    if (!value || typeof value !== 'object' || Array.isArray(value)) {
+      return { override: null, source: file, error: `${file}: branchStrategy must be a string or an object` };
+    }
+    if (value.model !== undefined && !BRANCH_STRATEGIES.models.includes(value.model)) {
+      return {
+        override: null,
+        source: file,
+        error: `${file}: unknown branchStrategy.model "${value.model}" (expected ${BRANCH_STRATEGIES.models.join(', ')})`
+      };
+    }
+    if (value.strategy !== undefined && !['single-branch', 'multi-branch'].includes(value.strategy)) {
+      return { override: null, source: file, error: `${file}: branchStrategy.strategy must be single-branch or multi-branch` };
+    }
+    return { override: value, source: file, error: null };
+  }
+  return { override: null, source: null, error: null };
+}
+
+/**
+ * Tag-triggered CI pipelines and deployment environments (CI and railway.json)
+ * @param {string[]} ciFiles - CI pipeline files
+ * @returns {Promise<{environments: string[], tagTriggers: string[], railwayEnvironments: number}>}
+ */
+async function detectDeployTriggers(ciFiles) {
+  const environments = new Set();
+  const tagTriggers = [];
+  const contents = await Promise.all(ciFiles.map(readFileCached));
+  ciFiles.forEach((file, i) => {
+    if (typeof contents[i] !== 'string') return;
+    if (BRANCH_STRATEGIES.tagTrigger.test(contents[i])) tagTriggers.push(file);
+    for (const match of contents[i].matchAll(BRANCH_STRATEGIES.environment)) {
+      environments.add(match[1] || match[2]);
+    }
+  });
+
+  let railwayEnvironments = 0;
+  if (await existsCached('railway.json')) {
+    try {
+      const config = safeJSONParse(await readFileCached('railway.json'), 'railway.json');
+      if (config && typeof config.environments === 'object' && config.environments !== null) {
+        railwayEnvironments = Object.keys(config.environments).length;
+        Object.keys(config.environments).forEach(name => environments.add(name));
+      }
+    } catch {}
+  }
+
+  return { environments: Array.from(environments), tagTriggers, railwayEnvironments };
+}
+
+/**
+ * Detects the branching model from branches, tags, CI triggers, and config
+ * Models: github-flow (feature branches into main), gitflow (develop + main,
+ * release/hotfix branches), production-branch (main merged into stable/prod),
+ * release-branch (production ships from release/* branches), tag-based
+ * (production ships from version tags), trunk-based (main promoted through
+ * environments). `branchStrategy` in the project config overrides detection.
+ * @returns {Promise<Object>} `{model, strategy, source, mainBranch, releaseTags, developBranch,
+ *   productionBranch, releaseBranches, latestTag, tagTriggers, environments, configError}`;
+ *   `mainBranch` and `releaseTags` are only set by the config
+ */
+async function detectBranching() {
+  const [localResult, remoteResult, tagResult, ciPipelines, config] = await Promise.all([
+    gitWithTimeout(['branch']),
+    gitWithTimeout(['branch', '-r']),
+    gitWithTimeout(['tag', '--list', '--sort=-v:refname']),
+    detectCIPipelines().catch(() => []),
+    loadBranchStrategyOverride().catch(error => ({ override: null, source: null, error: error.message }))
+  ]);
+
+  const branches = parseBranchNames(`${localResult || ''}\n${remoteResult || ''}`);
+  const has = name => branches.includes(name);
+  const tags = String(tagResult || '').split('\n').map(tag => tag.trim()).filter(tag => BRANCH_STRATEGIES.releaseTag.test(tag));
+  const ciFiles = Array.from(new Set(ciPipelines.map(pipeline => pipeline.file)));
+  const { environments, tagTriggers, railwayEnvironments } = await detectDeployTriggers(ciFiles);
+
+  const trunkBranch = BRANCH_STRATEGIES.trunkBranches.find(has) || null;
+  const developBranch = BRANCH_STRATEGIES.developBranches.find(has) || null;
+  const releaseBranches = branches
+    .filter(name => BRANCH_STRATEGIES.releaseBranch.test(name))
+    .sort()
+    .slice(0, BRANCH_STRATEGIES.maxListed);
+  const hasHotfix = branches.some(name => BRANCH_STRATEGIES.hotfixBranch.test(name));
+  let productionBranch = BRANCH_STRATEGIES.productionBranches.find(has) || null;
+
+  let model;
+  if (developBranch && trunkBranch && (releaseBranches.length > 0 || hasHotfix || !productionBranch)) {
+    model = 'gitflow';
+    productionBranch = productionBranch || trunkBranch;
+  } else if (productionBranch) {
+    model = 'production-branch';
+  } else if (releaseBranches.length > 0) {
+    model = 'release-branch';
+  } else if (tagTriggers.length > 0) {
+    model = 'tag-based';
+  } else if (environments.length > 1) {
+    model = 'trunk-based';
+  } else {
+    model = 'github-flow';
+  }
+
+  const branching = {
+    model,
+    strategy: null,
+    source: 'detected',
+    mainBranch: null,
+    releaseTags: null,
+    developBranch: model === 'gitflow' ? developBranch : null,
+    productionBranch,
+    releaseBranches,
+    latestTag: tags[0] || null,
+    tagTriggers,
+    environments,
+    configError: config.error
+  };
+
+  const override = config.override;
+  if (override) {
+    branching.source = config.source;
+    if (override.model && override.model !== model) {
+      // Branches detected for another model do not carry over
+      branching.model = override.model;
+      branching.developBranch = override.model === 'gitflow' ? developBranch : null;
+      branching.productionBranch = override.model === 'gitflow'
+        ? (productionBranch || trunkBranch)
+        : (override.model === 'production-branch' ? productionBranch : null);
+    }
+    for (const key of ['mainBranch', 'developBranch', 'productionBranch', 'releaseTags']) {
+      if (typeof override[key] === 'string') branching[key] = override[key];
+    }
+    if (override.releaseBranches !== undefined) {
+      branching.releaseBranches = [].concat(override.releaseBranches).filter(branch => typeof branch === 'string');
+    }
+    if (Array.isArray(override.environments)) {
+      branching.environments = override.environments.filter(env => typeof env === 'string');
+    }
+  }
+
+  // Legacy two-value strategy consumed by /ship: multi-branch means main is promoted to a production branch
+  branching.strategy = (override && override.strategy) ||
+    (['gitflow', 'production-branch'].includes(branching.model) || (!override && railwayEnvironments > 1)
+      ? 'multi-branch'
+      : 'single-branch');
+
+  return branching;
+}
+
+/**
+ * Detects branch strategy (single-branch vs multi-branch with dev+prod)
+ * @returns {Promise<string>} 'single-branch' or 'multi-branch'
+ */
+async function detectBranchStrategy() {
+  try {
+    return (await detectBranching()).strategy;
   } catch {
     return 'single-branch';
   }
@@ -256,84 +1263,462 @@ async function detectBranchStrategy() {
  * @returns {Promise<string>} Main branch name ('main' or 'master')
  */
 async function detectMainBranch() {
+  const ref = await gitWithTimeout(['symbolic-ref', 'refs/remotes/origin/HEAD']);
+  if (ref !== null) return ref.trim().replace('refs/remotes/origin/', '');
+  return (await gitWithTimeout(['rev-parse', '--verify', 'main'])) !== null ? 'main' : 'master';
+}
+
+/**
+ * Rank candidates by confidence, breaking ties by built-in priority
+ * @param {Map<string, Array<{source: string, weight: number}>>} evidence - Evidence per candidate value
+ * @param {string[]} order - Built-in priority order
+ * @returns {Array<{value: string, confidence: number, evidence: Array<Object>}>}
+ */
+function rankCandidates(evidence, order) {
+  const rank = value => (order.includes(value) ? order.indexOf(value) : order.length);
+  return Array.from(evidence, ([value, items]) => ({
+    value,
+    // Capped below 1: file-based signals are never certain
+    confidence: Math.min(0.99, Math.round((1 - items.reduce((rest, { weight }) => rest * (1 - weight), 1)) * 100) / 100),
+    evidence: items
+  })).sort((a, b) => b.confidence - a.confidence || rank(a.value) - rank(b.value));
+}
+
+/**
+ * Detects ranked candidates for CI, deployment, project type, and package manager
+ * Unlike the single first-match fields, every signal counts, so conflicting
+ * configs (e.g. `vercel.json` and `netlify.toml`) surface as several candidates.
+ * @param {Array<Object>} [ciPipelines] - Result of `detectCIPipelines`
+ * @returns {Promise<{candidates: Object<string, Array<Object>>, ambiguous: string[]}>}
+ */
+async function detectCandidates(ciPipelines) {
+  const pipelines = ciPipelines || await detectCIPipelines().catch(() => []);
+  const evidence = { ci: new Map(), deployment: new Map(), projectType: new Map(), packageManager: new Map() };
+  const add = (category, value, source, weight) => {
+    const items = evidence[category].get(value) || [];
+    if (!items.some(item => item.source === source)) items.push({ source, weight });
+    evidence[category].set(value, items);
+  };
+
+  const ciChecks = await Promise.all(CI_CONFIGS.map(({ file }) => existsCached(file)));
+  CI_CONFIGS.forEach(({ file, platform }, i) => {
+    if (!ciChecks[i]) return;
+    const defined = pipelines.filter(pipeline => pipeline.platform === platform &&
+      (pipeline.file === file || pipeline.file.startsWith(`${file}/`)));
+    if (defined.length === 0) add('ci', platform, file, CONFIDENCE_CONFIGS.ci.marker);
+    defined.forEach(pipeline => add('ci', platform, pipeline.file, CONFIDENCE_CONFIGS.ci.pipeline));
+  });
+
+  for (const config of await matchDeploymentConfigs()) {
+    add('deployment', config.platform, config.file, config.weight || CONFIDENCE_CONFIGS.deployment.config);
+  }
+  const pipelineFiles = Array.from(new Set(pipelines.map(pipeline => pipeline.file)));
+  const contents = await Promise.all(pipelineFiles.map(readFileCached));
+  pipelineFiles.forEach((file, i) => {
+    if (typeof contents[i] !== 'string') return;
+    for (const { platform, pattern } of CONFIDENCE_CONFIGS.deployCommands) {
+      if (pattern.test(contents[i])) add('deployment', platform, `${file}#deploy-command`, CONFIDENCE_CONFIGS.deployment.ciCommand);
+    }
+  });
+
+  const typeChecks = await Promise.all(CONFIDENCE_CONFIGS.projectTypes.map(({ file }) => existsCached(file)));
+  CONFIDENCE_CONFIGS.projectTypes.forEach(({ file, type, weight }, i) => {
+    if (typeChecks[i]) add('projectType', type, file, weight);
+  });
+
+  const [lockChecks, rootPackage] = await Promise.all([
+    Promise.all(PACKAGE_MANAGER_CONFIGS.map(({ file }) => existsCached(file))),
+    readJSONCached('package.json')
+  ]);
+  PACKAGE_MANAGER_CONFIGS.forEach(({ file, manager }, i) => {
+    if (lockChecks[i]) add('packageManager', manager, file, CONFIDENCE_CONFIGS.packageManager.lockfile);
+  });
+  // Corepack `packageManager` field, e.g. "pnpm@9.1.0"
+  const declared = rootPackage && typeof rootPackage.packageManager === 'string' && rootPackage.packageManager.split('@')[0];
+  if (['npm', 'pnpm', 'yarn', 'bun'].includes(declared)) {
+    add('packageManager', declared, 'package.json#packageManager', CONFIDENCE_CONFIGS.packageManager.packageManagerField);
+  }
+
+  const order = {
+    ci: CI_CONFIGS.map(config => config.platform),
+    deployment: DEPLOYMENT_CONFIGS.map(config => config.platform),
+    projectType: CONFIDENCE_CONFIGS.projectTypes.map(config => config.type),
+    packageManager: PACKAGE_MANAGER_CONFIGS.map(config => config.manager)
+  };
+  const candidates = {};
+  const ambiguous = [];
+  for (const category of Object.keys(evidence)) {
+    candidates[category] = rankCandidates(evidence[category], order[category]);
+    const runnerUp = candidates[category][1];
+    if (runnerUp && runnerUp.confidence >= CONFIDENCE_CONFIGS.ambiguityThreshold) ambiguous.push(category);
+  }
+  return { candidates, ambiguous };
+}
+
+/**
+ * Shared cache namespace holding the persisted detection result
+ * @returns {Object} Result of createCache for the working directory
+ */
+function persistedCache() {
+  return createCache(process.cwd(), PERSISTED_CACHE_NAMESPACE);
+}
+
+/**
+ * Get the persisted detection cache path
+ * @returns {string}
+ */
+function getPersistedCachePath() {
+  return persistedCache().file(PERSISTED_CACHE_KEY);
+}
+
+/**
+ * Modification time of a path
+ * @param {string} filepath - File or directory path
+ * @returns {Promise<number|null>} mtime in ms, or null when missing
+ */
+async function mtimeOf(filepath) {
   try {
-    const { stdout } = await execWithTimeout('git symbolic-ref refs/remotes/origin/HEAD', { encoding: 'utf8' });
-    return stdout.trim().replace('refs/remotes/origin/', '');
+    const stats = await fsPromises.stat(filepath);
+    return stats.mtimeMs;
   } catch {
+    return null;
+  }
+}
+
+/**
+ * List directories walked by detection, root first
+ * Adding or removing a file changes its directory's mtime, so these catch new markers.
+ * @param {number} maxDepth - Directory levels to descend
+ * @returns {Promise<string[]>} Relative posix paths ('.' for the root)
+ */
+async function listShallowDirs(maxDepth) {
+  const dirs = [];
+  const nested = nestedCheckoutDirs();
+  const queue = [{ dir: '.', depth: 0 }];
+  while (queue.length > 0 && dirs.length < MAX_FINGERPRINT_DIRS) {
+    const { dir, depth } = queue.shift();
+    dirs.push(dir);
+    if (depth >= maxDepth) continue;
+    let entries = [];
     try {
-      await execWithTimeout('git rev-parse --verify main', { encoding: 'utf8' });
-      return 'main';
+      entries = await fsPromises.readdir(dir, { withFileTypes: true });
     } catch {
-      return 'master';
+      continue;
+    }
+    for (const entry of entries || []) {
+      const rel = dir === '.' ? entry.name : `${dir}/${entry.name}`;
+      if (entry.isDirectory() && !entry.name.startsWith('.') && !DEFAULT_EXCLUDE_DIRS.includes(entry.name) && !nested.has(rel)) {
+        queue.push({ dir: rel, depth: depth + 1 });
+      }
     }
   }
+  return dirs;
+}
+
+/**
+ * Collect the paths whose mtimes invalidate a detection result
+ * @param {Object} detection - Detection result
+ * @param {string[]} dirs - Result of `listShallowDirs`
+ * @returns {string[]} Sorted relative paths
+ */
+function collectMarkers(detection, dirs) {
+  const markers = new Set(dirs);
+  for (const configs of [CI_CONFIGS, DEPLOYMENT_CONFIGS, PACKAGE_MANAGER_CONFIGS, WORKSPACE_TOOL_CONFIGS]) {
+    configs.forEach(({ file }) => markers.add(file));
+  }
+  PLATFORM_MARKER_FILES.forEach(file => markers.add(file));
+
+  (detection.ciPipelines || []).forEach(({ file }) => markers.add(file));
+  if (detection.containerization) {
+    // Manifests are recognized by content; the rest only by name
+    detection.containerization.manifests.forEach(file => markers.add(file));
+    detection.containerization.compose.forEach(file => markers.add(file));
+  }
+  if (detection.infrastructure) {
+    detection.infrastructure.projects.forEach(project => project.files.forEach(file => markers.add(file)));
+  }
+  if (detection.serverless) {
+    detection.serverless.files.forEach(file => markers.add(file));
+  }
+  if (detection.monorepo) {
+    for (const pkg of detection.monorepo.packages) {
+      markers.add(pkg.path);
+      markers.add(`${pkg.path}/package.json`);
+    }
+  }
+
+  const stateDir = exec.relativePath(process.cwd(), getStateDirPath());
+  markers.add(`${stateDir}/${PROJECT_DETECTORS_DIR}`);
+  for (const detector of getDetectors()) {
+    detector.markers.forEach(marker => markers.add(marker));
+    if (detector.source) markers.add(detector.source);
+  }
+  return Array.from(markers).sort();
+}
+
+/**
+ * Fingerprint git HEAD and the refs branch detection depends on
+ * @returns {Promise<Object|null>} `{head, refs}` or null outside a git repo
+ */
+async function gitFingerprint() {
+  try {
+    const stdout = await gitWithTimeout(['rev-parse', 'HEAD', '--git-common-dir']);
+    const [head, reported] = String(stdout || '').trim().split(/\r?\n/);
+    if (!head || !reported) return null;
+    // Older git prints the common dir relative to the top level, not the cwd
+    let dirs = null;
+    try {
+      dirs = git.resolveGitDirs(process.cwd());
+    } catch {
+      dirs = null;
+    }
+    const commonDir = dirs ? dirs.commonDir : path.resolve(reported);
+    const mtimes = await Promise.all(GIT_FINGERPRINT_REFS.map(ref => mtimeOf(path.resolve(commonDir, ref))));
+    return { head, refs: Object.fromEntries(GIT_FINGERPRINT_REFS.map((ref, i) => [ref, mtimes[i]])) };
+  } catch {
+    return null;
+  }
+}
+
+/**
+ * Fingerprint marker mtimes and git state
+ * @param {string[]} markers - Relative paths from `collectMarkers`
+ * @returns {Promise<{git: Object|null, files: Object<string, number|null>}>}
+ */
+async function computeFingerprint(markers) {
+  const [git, mtimes] = await Promise.all([
+    gitFingerprint(),
+    Promise.all(markers.map(mtimeOf))
+  ]);
+  return { git, files: Object.fromEntries(markers.map((marker, i) => [marker, mtimes[i]])) };
+}
+
+/**
+ * Load a persisted detection result if its fingerprint still matches
+ * @param {string[]} detectorNames - Registered custom detectors; must match the record
+ * @returns {Promise<Object|null>} Detection result or null when missing or stale
+ */
+async function loadPersistedDetection(detectorNames) {
+  const skip = reason => {
+    log.debug('persisted cache skipped', { reason });
+    return null;
+  };
+  let record;
+  try {
+    record = persistedCache().get(PERSISTED_CACHE_KEY);
+  } catch {
+    record = undefined;
+  }
+  if (record === undefined) return skip('no cache entry');
+  if (!record ||
+      record.version !== PERSISTED_CACHE_VERSION ||
+      !record.detection || typeof record.detection !== 'object' ||
+      !Array.isArray(record.markers) ||
+      !record.fingerprint) {
+    return skip('invalid or old cache format');
+  }
+  if (JSON.stringify(record.detectors) !== JSON.stringify(detectorNames)) {
+    return skip('custom detectors changed');
+  }
+  const age = Date.now() - Date.parse(record.createdAt);
+  if (!(age >= 0 && age <= PERSISTED_CACHE_MAX_AGE_MS)) return skip('expired');
+
+  const fingerprint = await computeFingerprint(record.markers);
+  if (JSON.stringify(fingerprint) !== JSON.stringify(record.fingerprint)) {
+    const before = record.fingerprint.files || {};
+    const changed = record.markers.filter(marker => fingerprint.files[marker] !== before[marker]);
+    if (JSON.stringify(fingerprint.git) !== JSON.stringify(record.fingerprint.git)) changed.unshift('git HEAD/refs');
+    return skip(`changed since ${record.createdAt}: ${changed.slice(0, 5).join(', ') || 'fingerprint'}`);
+  }
+  return record.detection;
+}
+
+/**
+ * Persist a detection result with its fingerprint
+ * Failures are ignored; the in-memory cache still applies.
+ * @param {Object} detection - Detection result
+ * @param {string[]} detectorNames - Registered custom detectors
+ * @returns {Promise<void>}
+ */
+async function persistDetection(detection, detectorNames) {
+  try {
+    // Create the state dir first so it does not change the root mtime
+    const cache = persistedCache();
+    await fsPromises.mkdir(path.dirname(cache.file(PERSISTED_CACHE_KEY)), { recursive: true });
+    const markers = collectMarkers(detection, await listShallowDirs(CONTAINER_CONFIGS.maxDepth));
+    const record = {
+      version: PERSISTED_CACHE_VERSION,
+      createdAt: detection.timestamp,
+      detectors: detectorNames,
+      markers,
+      fingerprint: await computeFingerprint(markers),
+      detection
+    };
+    if (!cache.set(PERSISTED_CACHE_KEY, record)) log.debug('detection not persisted', { reason: 'cache disabled or not writable' });
+  } catch (error) {
+    // Read-only checkout or missing state dir: skip persistence
+    log.debug('detection not persisted', { error });
+  }
+}
+
+/**
+ * Run custom detectors registered in-process or loaded from `<state-dir>/detectors`
+ * @returns {Promise<{custom: Object|null, customErrors: Array<Object>}>}
+ */
+async function detectCustom() {
+  const { errors: loadErrors } = await loadProjectDetectors();
+  const context = {
+    cwd: process.cwd(),
+    exists: existsCached,
+    readFile: readFileCached,
+    readJSON: readJSONCached
+  };
+  const { results, errors } = await runDetectors(context, (promise, name) =>
+    withTimeout(promise, DEFAULT_ASYNC_TIMEOUT_MS, `detector ${name}`));
+  for (const { file, name, error } of [...loadErrors, ...errors]) {
+    log.warn('custom detector failed', { detector: name || file, error });
+  }
+  return {
+    custom: Object.keys(results).length > 0 ? results : null,
+    customErrors: [...loadErrors, ...errors]
+  };
 }
 
 /**
  * Main detection function - aggregates all platform information
- * Uses Promise.all for parallel execution and caching
+ * Uses Promise.all for parallel execution and caching. Results persist to
+ * `.awesome-slash/cache/detection/platform.json` and are reused until marker files or git refs change.
  * @param {boolean} forceRefresh - Force cache refresh
  * @returns {Promise<Object>} Platform configuration object
  */
 async function detect(forceRefresh = false) {
+  const started = Date.now();
+  await loadProjectDetectors().catch(() => null);
+  const detectorNames = getDetectors().map(detector => detector.name);
+  // Registering a detector changes the key, so cached results without it are skipped
+  const cacheKey = ['detection', ...detectorNames].join(':');
+
   if (!forceRefresh) {
-    const cached = _detectionCache.get('detection');
+    const cached = _detectionCache.get(cacheKey);
     if (cached !== undefined) {
+      log.trace('cache hit', { source: 'memory' });
       return cached;
     }
+    const persisted = await loadPersistedDetection(detectorNames);
+    if (persisted) {
+      log.debug('cache hit', { source: PERSISTED_CACHE_NAMESPACE, detectedAt: persisted.timestamp });
+      _detectionCache.set(cacheKey, persisted);
+      return persisted;
+    }
   }
-
+  // A failed step falls back to an empty result; the trace says which and why
+  const fallback = (step, value) => error => {
+    log.debug('detection step failed', { step, error });
+    return value;
+  };
   const [
     ci,
-    deployment,
+    deployments,
     projectType,
     packageManager,
-    branchStrategy,
-    mainBranch,
+    branching,
+    detectedMainBranch,
     hasPlanFile,
-    hasTechDebtFile
+    hasTechDebtFile,
+    monorepo,
+    containerization,
+    infrastructure,
+    serverless,
+    ciPipelines,
+    customDetection
   ] = await Promise.all([
     detectCI(),
-    detectDeployment(),
+    detectDeployments().catch(fallback('deployments', [])),
     detectProjectType(),
     detectPackageManager(),
-    detectBranchStrategy(),
+    detectBranching().catch(fallback('branching', { model: 'github-flow', strategy: 'single-branch', source: 'detected', mainBranch: null })),
     detectMainBranch(),
     existsCached('PLAN.md'),
-    existsCached('TECHNICAL_DEBT.md')
+    existsCached('TECHNICAL_DEBT.md'),
+    detectWorkspaces().catch(fallback('workspaces', null)),
+    detectContainerization().catch(fallback('containerization', null)),
+    detectInfrastructure().catch(fallback('infrastructure', null)),
+    detectServerless().catch(fallback('serverless', null)),
+    detectCIPipelines().catch(fallback('ciPipelines', [])),
+    detectCustom().catch(error => ({ custom: null, customErrors: [{ error: error.message }] }))
   ]);
 
+  const mainBranch = branching.mainBranch || detectedMainBranch;
+  branching.mainBranch = mainBranch;
+
+  const { candidates, ambiguous } = await detectCandidates(ciPipelines)
+    .catch(fallback('candidates', { candidates: {}, ambiguous: [] }));
+
   const detection = {
     ci,
-    deployment,
+    ciPipelines,
+    deployment: deployments[0] || null,
+    deployments,
     projectType,
     packageManager,
-    branchStrategy,
+    branchStrategy: branching.strategy,
+    branching,
     mainBranch,
     hasPlanFile,
     hasTechDebtFile,
+    monorepo,
+    containerization,
+    infrastructure,
+    serverless,
+    candidates,
+    ambiguous,
+    custom: customDetection.custom,
+    customErrors: customDetection.customErrors,
     timestamp: new Date().toISOString()
   };
 
-  _detectionCache.set('detection', detection);
+  log.debug('detected', {
+    ci,
+    deployment: detection.deployment,
+    projectType,
+    packageManager,
+    branchModel: branching.model,
+    branchSource: branching.source,
+    mainBranch,
+    ambiguous,
+    durationMs: Date.now() - started
+  });
+  _detectionCache.set(cacheKey, detection);
+  await persistDetection(detection, detectorNames);
   return detection;
 }
 
 /**
- * Invalidate all detection caches
+ * Invalidate all detection caches, including the persisted result
  * Call this after making changes that affect platform detection
  */
 function invalidateCache() {
   _detectionCache.clear();
   _fileCache.clear();
   _existsCache.clear();
+  _listingCache.clear();
+  try {
+    persistedCache().delete(PERSISTED_CACHE_KEY);
+  } catch {
+    // Nothing persisted
+  }
 }
 
 // When run directly, output JSON
 if (require.main === module) {
   (async () => {
+    const { argv, error: argsError } = applyLogArgs(process.argv.slice(2));
+    if (argsError) {
+      console.error(argsError);
+      process.exit(1);
+    }
     try {
-      const result = await detect();
+      const result = await detect(argv.includes('--refresh'));
       const indent = process.stdout.isTTY ? 2 : 0;
       console.log(JSON.stringify(result, null, indent));
     } catch (error) {
@@ -351,10 +1736,24 @@ if (require.main === module) {
 module.exports = {
   detect,
   invalidateCache,
+  getPersistedCachePath,
+  registerDetector,
+  unregisterDetector,
+  getDetectors,
   detectCI,
+  detectCIPipelines,
+  parseCIPipelines,
   detectDeployment,
+  detectDeployments,
+  detectCandidates,
   detectProjectType,
   detectPackageManager,
+  detectWorkspaces,
+  detectContainerization,
+  detectInfrastructure,
+  parseTerraformProviders,
+  detectServerless,
   detectBranchStrategy,
+  detectBranching,
   detectMainBranch
 };
@@ -0,0 +1,236 @@
+/**
+ * Call-site extraction and cross-file call graph
+ *
+ * Call sites are collected per file as `name(` occurrences outside comments
+ * and strings. They are resolved to symbols defined in other files through
+ * the caller's imports, Go package directories, or a repo-wide unique
+ * definition, producing `map.callGraph[file][symbol] = [{file, line}]`.
+ *
+ * @module lib/repo-map/calls
+ */
+
+'use strict';
+
+const path = require('path');
+
+const { maskComments, createLineLookup } = require('./details/utils');
+const { createFileIndex, resolveImport } = require('./imports');
+
+const CALL = /(?:\b([A-Za-z_$][\w$]*)\s*(\.|::|->)\s*)?\b([A-Za-z_$][\w$]*)\s*(?:!\s*)?\(/g;
+const HASH_COMMENT_LANGUAGES = new Set(['python', 'ruby']);
+const SELF_QUALIFIERS = new Set(['this', 'self', 'super', 'Self', 'base']);
+const KEYWORDS = new Set([
+  'if', 'for', 'while', 'switch', 'catch', 'return', 'function', 'typeof', 'sizeof',
+  'elif', 'and', 'or', 'not', 'in', 'match', 'with', 'assert', 'lambda', 'yield', 'await',
+  'defined', 'alignof', 'decltype', 'foreach', 'using', 'lock', 'nameof', 'fixed', 'when', 'unless'
+]);
+
+/**
+ * Blank string literal contents, preserving offsets and newlines
+ * @param {string} text - Comment-masked source
+ * @param {string} language - Language name
+ * @returns {string}
+ */
+function maskStrings(text, language) {
+  let out = '';
+  for (let i = 0; i < text.length; i++) {
+    const ch = text[i];
+    if (ch !== '"' && ch !== '\'' && ch !== '`') {
+      out += ch;
+      continue;
+    }
+    if (language === 'rust' && ch === '\'' && !/^'(?:\\.[^']*|[^\\'])'/.test(text.slice(i, i + 12))) {
+      out += ch;
+      continue;
+    }
+
+    const triple = language === 'python' && text.startsWith(ch.repeat(3), i);
+    const close = triple ? ch.repeat(3) : ch;
+    out += close;
+    i += close.length;
+    while (i < text.length && !text.startsWith(close, i)) {
+      if (!triple && ch !== '`' && text[i] === '\n') break;
+      if (text[i] === '\\') {
+        out += ' ';
+        i++;
+      }
+      out += text[i] === '\n' ? '\n' : ' ';
+      i++;
+    }
+    if (i < text.length) {
+      out += text.slice(i, i + close.length);
+      i += close.length - 1;
+    }
+  }
+  return out;
+}
+
+/**
+ * Extract call sites from file content
+ * @param {string} language - Language name
+ * @param {string} content - File content
+ * @returns {Array<{name: string, line: number, qualifier?: string}>}
+ */
+function extractCallSites(language, content) {
+  const masked = maskStrings(maskComments(content, { hashComments: HASH_COMMENT_LANGUAGES.has(language) }), language);
+  const lineAt = createLineLookup(masked);
+  const calls = [];
+  const seen = new Set();
+  let match;
+
+  CALL.lastIndex = 0;
+  while ((match = CALL.exec(masked)) !== null) {
+    const [, qualifier, , name] = match;
+    if (KEYWORDS.has(name) || SELF_QUALIFIERS.has(qualifier)) continue;
+    // Skip member calls on values (`obj.items.map(`); only the first link of a chain is kept
+    if (/(?:\.|->)\s*$/.test(masked.slice(Math.max(0, match.index - 4), match.index))) continue;
+
+    const line = lineAt(match.index);
+    const key = `${qualifier || ''}:${name}:${line}`;
+    if (seen.has(key)) continue;
+    seen.add(key);
+    calls.push(qualifier ? { name, line, qualifier } : { name, line });
+  }
+  return calls;
+}
+
+/**
+ * Language family used to keep calls within one toolchain
+ * @param {string} language - Language name
+ * @returns {string}
+ */
+function languageFamily(language) {
+  if (language === 'c' || language === 'cpp') return 'c';
+  if (language === 'javascript' || language === 'typescript') return 'js';
+  if (language === 'java' || language === 'kotlin') return 'jvm';
+  return language;
+}
+
+/**
+ * Index callable definitions (functions and classes) by name
+ * Header declarations point at their definition file when linked.
+ * @param {Object} map - Repo map
+ * @returns {Map<string, Array<{file: string, header: string|null, entry: Object}>>}
+ */
+function indexDefinitions(map) {
+  const definitions = new Map();
+  for (const [file, fileData] of Object.entries(map.files)) {
+    const symbols = fileData.symbols || {};
+    for (const entry of [...(symbols.functions || []), ...(symbols.classes || [])]) {
+      if (entry.declaredIn) continue;
+      const target = entry.definedIn || file;
+      if (!definitions.has(entry.name)) definitions.set(entry.name, []);
+      const list = definitions.get(entry.name);
+      if (!list.some(item => item.file === target)) {
+        list.push({ file: target, header: entry.definedIn ? file : null, entry });
+      }
+    }
+  }
+  return definitions;
+}
+
+/**
+ * Last segment of an import source, used to match qualifiers (`pkg.Func`)
+ * @param {string} source - Import source
+ * @returns {string}
+ */
+function importAlias(source) {
+  const segments = source.replace(/\.(?:[jt]sx?|mjs|cjs|py|rb|h|hpp)$/, '').split(/[/.:]+/).filter(Boolean);
+  return segments.length > 0 ? segments[segments.length - 1] : '';
+}
+
+/**
+ * Resolve one call site to defining files
+ * @param {Object} call - Call site
+ * @param {Object[]} candidates - Definitions sharing the call's name
+ * @param {Object} scope - Caller scope (imported files, aliases, package files)
+ * @returns {string[]}
+ */
+function resolveCall(call, candidates, scope) {
+  const inFiles = files => candidates.filter(item => files.has(item.file) || (item.header && files.has(item.header)));
+
+  if (call.qualifier) {
+    const aliased = scope.aliases.get(call.qualifier);
+    if (aliased) return inFiles(aliased).map(item => item.file);
+
+    const members = candidates.filter(item => item.entry.parent === call.qualifier);
+    const imported = inFiles(scope.imported).filter(item => members.includes(item));
+    if (imported.length > 0) return imported.map(item => item.file);
+    return members.length === 1 ? [members[0].file] : [];
+  }
+
+  const imported = inFiles(scope.imported);
+  if (imported.length > 0) return imported.map(item => item.file);
+
+  const samePackage = inFiles(scope.packageFiles);
+  if (samePackage.length > 0) return samePackage.map(item => item.file);
+
+  const exported = candidates.filter(item => item.entry.exported && !item.entry.parent);
+  return exported.length === 1 ? [exported[0].file] : [];
+}
+
+/**
+ * Build the cross-file call graph from recorded call sites
+ * @param {Object} map - Repo map with `files[*].calls`
+ * @returns {Object} - `{ [file]: { [symbol]: Array<{file: string, line: number}> } }`
+ */
+function buildCallGraph(map) {
+  const graph = {};
+  if (!map || !map.files) return graph;
+
+  const index = createFileIndex(Object.keys(map.files));
+  const definitions = indexDefinitions(map);
+
+  for (const [file, fileData] of Object.entries(map.files)) {
+    if (!fileData.calls || fileData.calls.length === 0) continue;
+
+    const language = fileData.language;
+    const family = languageFamily(language);
+    const symbols = fileData.symbols || {};
+    const localNames = new Set([...(symbols.functions || []), ...(symbols.classes || [])].map(entry => entry.name));
+
+    const scope = { imported: new Set(), aliases: new Map(), packageFiles: new Set() };
+    for (const imp of fileData.imports || []) {
+      const resolved = resolveImport(file, imp.source, language, index);
+      if (resolved.length === 0) continue;
+      for (const target of resolved) scope.imported.add(target);
+      const alias = importAlias(imp.source);
+      if (!alias) continue;
+      if (!scope.aliases.has(alias)) scope.aliases.set(alias, new Set());
+      for (const target of resolved) scope.aliases.get(alias).add(target);
+    }
+    if (language === 'go') {
+      for (const sibling of index.byDir.get(path.posix.dirname(file)) || []) {
+        if (sibling !== file && sibling.endsWith('.go')) scope.packageFiles.add(sibling);
+      }
+    }
+
+    for (const call of fileData.calls) {
+      if (!call.qualifier && localNames.has(call.name)) continue;
+      const name = call.qualifier && family === 'c' && definitions.has(`${call.qualifier}::${call.name}`)
+        ? `${call.qualifier}::${call.name}`
+        : call.name;
+      const candidates = (definitions.get(name) || []).filter(item => item.file !== file
+        && map.files[item.file] && languageFamily(map.files[item.file].language) === family);
+      if (candidates.length === 0) continue;
+
+      for (const target of resolveCall(call, candidates, scope)) {
+        if (!graph[target]) graph[target] = {};
+        if (!graph[target][name]) graph[target][name] = [];
+        graph[target][name].push({ file, line: call.line });
+      }
+    }
+  }
+
+  for (const symbolsByName of Object.values(graph)) {
+    for (const callers of Object.values(symbolsByName)) {
+      callers.sort((a, b) => a.file.localeCompare(b.file) || a.line - b.line);
+    }
+  }
+  return graph;
+}
+
+module.exports = {
+  extractCallSites,
+  buildCallGraph
+};
@@ -0,0 +1,319 @@
+/**
+ * Ruby symbol details (modules, nesting, method visibility, Rails conventions)
+ *
+ * Scopes are tracked by indentation: a `class`/`module`/`def` closes at the
+ * `end` sharing its indent, which holds for conventionally formatted code.
+ *
+ * @module lib/repo-map/details/ruby
+ */
+
+'use strict';
+
+const path = require('path');
+
+const CLASS_LINE = /^class\s+([A-Z][\w:]*)(?:\s*<\s*([A-Z][\w:.]*(?:\[[^\]]*\])?(?:\([^)]*\))?))?/;
+const MODULE_LINE = /^module\s+([A-Z][\w:]*)/;
+const DEF_LINE = /^(?:(private|protected|public)\s+)?def\s+(self\.)?([A-Za-z_]\w*[?!=]?|\[\]=?|[+\-*/%<>=!~^&|]+)/;
+const MIXIN_LINE = /^(include|extend|prepend)\s+([A-Z][\w:]*(?:\s*,\s*[A-Z][\w:]*)*)/;
+const ASSOCIATION_LINE = /^(has_many|has_one|belongs_to|has_and_belongs_to_many)\s+:(\w+)/;
+const CALLBACK_LINE = /^((?:before|after|around|skip_before|skip_after)_(?:action|save|create|update|destroy|validation|commit))\s+:(\w+)/;
+const SCOPE_LINE = /^scope\s+:(\w+)/;
+const CONSTANT_LINE = /^([A-Z][A-Z0-9_]*)\s*=(?![=~])/;
+const VISIBILITY_LINE = /^(private|protected|public)(?:\s+:([\w?!]+(?:\s*,\s*:[\w?!]+)*))?\s*$/;
+const HEREDOC = /<<[~-]?(['"]?)([A-Z_][A-Z0-9_]*)\1/;
+
+const RAILS_PATHS = [
+  ['app/models/concerns/', 'concern'],
+  ['app/controllers/concerns/', 'concern'],
+  ['app/models/', 'model'],
+  ['app/controllers/', 'controller'],
+  ['app/jobs/', 'job'],
+  ['app/mailers/', 'mailer'],
+  ['app/helpers/', 'helper'],
+  ['app/channels/', 'channel'],
+  ['app/serializers/', 'serializer'],
+  ['app/policies/', 'policy'],
+  ['app/services/', 'service'],
+  ['db/migrate/', 'migration']
+];
+
+const RAILS_SUPERCLASSES = [
+  [/^(?:ApplicationRecord|ActiveRecord::Base)$/, 'model'],
+  [/(?:^|::)(?:ApplicationController|ActionController::(?:Base|API))$|Controller$/, 'controller'],
+  [/^(?:ApplicationJob|ActiveJob::Base)$/, 'job'],
+  [/^(?:ApplicationMailer|ActionMailer::Base)$/, 'mailer'],
+  [/^ActiveRecord::Migration/, 'migration']
+];
+
+/**
+ * Strip a trailing `#` comment outside of strings
+ * @param {string} line - Source line
+ * @returns {string}
+ */
+function stripComment(line) {
+  let quote = null;
+  for (let i = 0; i < line.length; i++) {
+    const ch = line[i];
+    if (quote) {
+      if (ch === '\\') i++;
+      else if (ch === quote) quote = null;
+      continue;
+    }
+    if (ch === '"' || ch === '\'') quote = ch;
+    else if (ch === '#') return line.slice(0, i);
+  }
+  return line;
+}
+
+/**
+ * Check whether a `def` line is self-contained (one-liner or endless method)
+ * @param {string} text - Trimmed line
+ * @returns {boolean}
+ */
+function isOneLineDef(text) {
+  return /;\s*end\s*$/.test(text) || /\bend\s*$/.test(text.replace(/^.*?\bdef\b/, '')) || /^(?:\w+\s+)?def\s+[^(=\s]+(?:\([^)]*\))?\s*=(?!=)/.test(text);
+}
+
+/**
+ * Parse Ruby modules, classes, and methods
+ * @param {string} content - File content
+ * @returns {{classes: Object[], methods: Object[], constants: Object[]}}
+ */
+function parseRubyDefinitions(content) {
+  const result = { classes: [], methods: [], constants: [] };
+  const lines = content.split('\n');
+  const stack = [];
+  let heredoc = null;
+  let blockComment = false;
+
+  const currentType = () => {
+    for (let i = stack.length - 1; i >= 0; i--) {
+      if (stack[i].type === 'def') return null;
+      if (stack[i].decl) return stack[i];
+    }
+    return null;
+  };
+
+  for (let i = 0; i < lines.length; i++) {
+    const raw = lines[i];
+    const lineNo = i + 1;
+
+    if (blockComment) {
+      if (/^=end\b/.test(raw)) blockComment = false;
+      continue;
+    }
+    if (/^=begin\b/.test(raw)) {
+      blockComment = true;
+      continue;
+    }
+    if (heredoc) {
+      if (raw.trim() === heredoc) heredoc = null;
+      continue;
+    }
+
+    const text = stripComment(raw).trim();
+    if (!text) continue;
+    const indent = raw.length - raw.trimStart().length;
+
+    const heredocMatch = text.match(HEREDOC);
+    if (heredocMatch) heredoc = heredocMatch[2];
+
+    if (/^end\b/.test(text)) {
+      while (stack.length > 0 && stack[stack.length - 1].indent > indent) stack.pop();
+      if (stack.length > 0 && stack[stack.length - 1].indent === indent) stack.pop();
+      continue;
+    }
+
+    const scope = currentType();
+    const namespace = stack.filter(item => item.decl).map(item => item.decl.name).join('::') || null;
+
+    const classMatch = text.match(CLASS_LINE);
+    const moduleMatch = !classMatch && text.match(MODULE_LINE);
+    if (classMatch || moduleMatch) {
+      const decl = {
+        name: (classMatch || moduleMatch)[1],
+        line: lineNo,
+        kind: classMatch ? 'class' : 'module',
+        namespace,
+        superclass: classMatch && classMatch[2] ? classMatch[2] : null,
+        mixins: [],
+        methods: [],
+        associations: [],
+        callbacks: [],
+        scopes: []
+      };
+      result.classes.push(decl);
+      if (!/\bend\s*$/.test(text)) stack.push({ type: decl.kind, indent, decl, visibility: 'public' });
+      continue;
+    }
+
+    if (/^class\s*<<\s*self\b/.test(text) || /^class_methods\s+do\b/.test(text)) {
+      stack.push({ type: 'singleton', indent, decl: null });
+      continue;
+    }
+
+    const defMatch = text.match(DEF_LINE);
+    if (defMatch) {
+      const owner = scope ? scope.decl : null;
+      const singleton = stack.length > 0 && stack[stack.length - 1].type === 'singleton';
+      const method = {
+        name: defMatch[3],
+        line: lineNo,
+        scope: (defMatch[2] || singleton) ? 'class' : 'instance',
+        visibility: defMatch[1] || (scope && !singleton ? scope.visibility : 'public'),
+        parent: owner ? owner.name : null
+      };
+      result.methods.push(method);
+      if (owner) owner.methods.push(method);
+      if (!isOneLineDef(text)) stack.push({ type: 'def', indent, decl: null });
+      continue;
+    }
+
+    if (!scope) {
+      const constant = text.match(CONSTANT_LINE);
+      if (constant && stack.length === 0) result.constants.push({ name: constant[1], line: lineNo, namespace: null });
+      continue;
+    }
+
+    const decl = scope.decl;
+    const visibility = text.match(VISIBILITY_LINE);
+    if (visibility) {
+      if (visibility[2]) {
+        const names = visibility[2].split(',').map(name => name.trim().replace(/^:/, ''));
+        for (const method of decl.methods) {
+          if (names.includes(method.name)) method.visibility = visibility[1];
+        }
+      } else {
+        scope.visibility = visibility[1];
+      }
+      continue;
+    }
+
+    const mixin = text.match(MIXIN_LINE);
+    if (mixin) {
+      for (const name of mixin[2].split(',')) {
+        decl.mixins.push({ kind: mixin[1], name: name.trim() });
+      }
+      continue;
+    }
+
+    const association = text.match(ASSOCIATION_LINE);
+    if (association) {
+      decl.associations.push({ kind: association[1], name: association[2] });
+      continue;
+    }
+
+    const callback = text.match(CALLBACK_LINE);
+    if (callback) {
+      decl.callbacks.push({ kind: callback[1], name: callback[2] });
+      continue;
+    }
+
+    const railsScope = text.match(SCOPE_LINE);
+    if (railsScope) {
+      decl.scopes.push(railsScope[1]);
+      continue;
+    }
+
+    const constant = text.match(CONSTANT_LINE);
+    if (constant) result.constants.push({ name: constant[1], line: lineNo, namespace: decl.name });
+  }
+
+  return result;
+}
+
+/**
+ * Convert a snake_case file stem to a CamelCase constant name
+ * @param {string} stem - File stem
+ * @returns {string}
+ */
+function camelize(stem) {
+  return stem.split('_').map(part => part.charAt(0).toUpperCase() + part.slice(1)).join('');
+}
+
+/**
+ * Infer a class's Rails role from its superclass, mixins, and file location
+ * @param {Object} decl - Parsed class/module
+ * @param {string|null} file - Repository-relative file path
+ * @returns {string|null}
+ */
+function inferRailsRole(decl, file) {
+  if (decl.mixins.some(mixin => mixin.kind === 'extend' && mixin.name === 'ActiveSupport::Concern')) {
+    return 'concern';
+  }
+  if (decl.superclass) {
+    for (const [pattern, role] of RAILS_SUPERCLASSES) {
+      if (pattern.test(decl.superclass)) return role;
+    }
+  }
+  if (!file) return null;
+
+  const normalized = file.replace(/\\/g, '/');
+  const stem = path.posix.basename(normalized, '.rb').replace(/^\d+_/, '');
+  if (camelize(stem) !== decl.name) return null;
+  for (const [prefix, role] of RAILS_PATHS) {
+    if (normalized.startsWith(prefix) || normalized.includes(`/${prefix}`)) return role;
+  }
+  return null;
+}
+
+/**
+ * Attach nesting, visibility, mixins, and Rails roles to Ruby symbols
+ * @param {string} content - File content
+ * @param {Object} symbolMaps - Symbol maps
+ * @param {Object} [context] - Scan context
+ * @param {string} [context.file] - Repository-relative file path
+ */
+function applyRubyDetails(content, symbolMaps, context = {}) {
+  const parsed = parseRubyDefinitions(content);
+  const file = context.file || null;
+
+  if (symbolMaps.classes) {
+    for (const entry of symbolMaps.classes.values()) {
+      const decl = parsed.classes.find(item => item.name === entry.name && item.line === entry.line)
+        || parsed.classes.find(item => item.name === entry.name);
+      if (!decl) continue;
+      entry.kind = decl.kind;
+      if (decl.namespace) entry.namespace = decl.namespace;
+      if (decl.superclass) entry.superclass = decl.superclass;
+      if (decl.mixins.length > 0) entry.mixins = decl.mixins;
+      if (decl.associations.length > 0) entry.associations = decl.associations;
+      if (decl.callbacks.length > 0) entry.callbacks = decl.callbacks;
+      if (decl.scopes.length > 0) entry.scopes = decl.scopes;
+      const methods = decl.methods.filter(method => method.visibility === 'public');
+      if (methods.length > 0) {
+        entry.methods = methods.map(method => (method.scope === 'class'
+          ? { name: method.name, line: method.line, scope: 'class' }
+          : { name: method.name, line: method.line }));
+      }
+      const role = inferRailsRole(decl, file);
+      if (role) entry.rails = role;
+    }
+  }
+
+  if (symbolMaps.functions) {
+    for (const entry of symbolMaps.functions.values()) {
+      const decl = parsed.methods.find(item => item.name === entry.name && item.line === entry.line)
+        || parsed.methods.find(item => item.name === entry.name);
+      if (!decl) continue;
+      entry.visibility = decl.visibility;
+      if (decl.parent) entry.parent = decl.parent;
+      if (decl.scope === 'class') entry.scope = 'class';
+    }
+  }
+
+  if (symbolMaps.constants) {
+    for (const constant of parsed.constants) {
+      if (symbolMaps.constants.has(constant.name)) continue;
+      const entry = { name: constant.name, line: constant.line, kind: 'constant' };
+      if (constant.namespace) entry.namespace = constant.namespace;
+      symbolMaps.constants.set(constant.name, entry);
+    }
+  }
+}
+
+module.exports = {
+  applyRubyDetails,
+  parseRubyDefinitions,
+  inferRailsRole
+};
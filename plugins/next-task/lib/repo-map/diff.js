@@ -0,0 +1,304 @@
+/**
+ * Symbol-level diff between two git refs, or between HEAD and the index
+ *
+ * Only files changed between the refs are extracted: each side is read with
+ * `git show <ref>:<file>` into a scratch directory and run through the normal
+ * extractors, so the result reflects the refs rather than the working tree.
+ * Staged changes read the index side with `git show :<file>`.
+ *
+ * @module lib/repo-map/diff
+ */
+
+'use strict';
+
+const fs = require('fs');
+const os = require('os');
+const path = require('path');
+
+const runner = require('./runner');
+const { parseDiff } = require('./updater');
+const { git } = require('../utils/exec');
+
+const CATEGORIES = ['functions', 'classes', 'types', 'constants'];
+
+/**
+ * Resolve a ref to a commit hash
+ * @param {string} basePath - Repository root
+ * @param {string} ref - Branch, tag, or commit
+ * @returns {string|null}
+ */
+function resolveRef(basePath, ref) {
+  if (!ref || ref.startsWith('-')) return null;
+  const out = git(basePath, ['rev-parse', '--verify', '--quiet', `${ref}^{commit}`]);
+  return out ? out.trim() : null;
+}
+
+/**
+ * Find the repo-map language for a path
+ * @param {string} file - File path
+ * @returns {string|null}
+ */
+function getLanguage(file) {
+  const ext = path.extname(file).toLowerCase();
+  for (const [language, exts] of Object.entries(runner.LANGUAGE_EXTENSIONS)) {
+    if (exts.includes(ext)) return language;
+  }
+  return null;
+}
+
+/**
+ * Extract symbols for files as they exist at a commit
+ * @param {string} cmd - ast-grep command
+ * @param {string} basePath - Repository root
+ * @param {string} commit - Commit hash (`''` for the index)
+ * @param {string[]} files - Repository-relative paths
+ * @returns {Object<string, Object>} - File -> symbols (files missing at the commit are omitted)
+ */
+function extractAtRef(cmd, basePath, commit, files) {
+  const byLanguage = new Map();
+  const scratch = fs.mkdtempSync(path.join(os.tmpdir(), 'repo-map-diff-'));
+  try {
+    for (const file of files) {
+      const language = getLanguage(file);
+      if (!language) continue;
+      const content = git(basePath, ['show', `${commit}:${file}`]);
+      if (content === null) continue;
+
+      const fullPath = path.join(scratch, file);
+      fs.mkdirSync(path.dirname(fullPath), { recursive: true });
+      fs.writeFileSync(fullPath, content);
+      if (!byLanguage.has(language)) byLanguage.set(language, []);
+      byLanguage.get(language).push({ file: fullPath, relativePath: file, content });
+    }
+
+    const symbols = {};
+    for (const [language, entries] of byLanguage) {
+      const extracted = runner.extractFiles(cmd, scratch, language, entries);
+      for (const [file, result] of Object.entries(extracted)) symbols[file] = result.symbols;
+    }
+    return symbols;
+  } finally {
+    fs.rmSync(scratch, { recursive: true, force: true });
+  }
+}
+
+/**
+ * Stable identity for a symbol within a file
+ * @param {string} category - Symbol category
+ * @param {Object} entry - Symbol entry
+ * @returns {string}
+ */
+function symbolKey(category, entry) {
+  return `${category}:${entry.receiver ? `${entry.receiver}.` : ''}${entry.name}`;
+}
+
+/**
+ * Signature text used to detect changes (matches the rendered headline)
+ * @param {Object} entry - Symbol entry
+ * @returns {string}
+ */
+function signatureOf(entry) {
+  if (entry.signature) return entry.signature;
+  return `${entry.typeParams || ''}${entry.returnType ? `: ${entry.returnType}` : ''}`;
+}
+
+/**
+ * Summarize a symbol for diff output
+ * @param {string} file - File path
+ * @param {string} category - Symbol category
+ * @param {Object} entry - Symbol entry
+ * @returns {Object}
+ */
+function describe(file, category, entry) {
+  const item = {
+    file,
+    name: entry.receiver ? `${entry.receiver}.${entry.name}` : entry.name,
+    kind: entry.kind || category,
+    line: entry.line || null,
+    exported: entry.exported !== false
+  };
+  const signature = signatureOf(entry);
+  if (signature) item.signature = signature;
+  return item;
+}
+
+/**
+ * Index a file's symbols by identity
+ * @param {Object} symbols - File symbols
+ * @returns {Map<string, {category: string, entry: Object}>}
+ */
+function indexSymbols(symbols) {
+  const index = new Map();
+  for (const category of CATEGORIES) {
+    for (const entry of (symbols && symbols[category]) || []) {
+      if (entry.declaredIn) continue;
+      const key = symbolKey(category, entry);
+      if (!index.has(key)) index.set(key, { category, entry });
+    }
+  }
+  return index;
+}
+
+/**
+ * Compare one file's symbols before and after
+ * Removed/added pairs with the same kind and a non-empty identical signature
+ * are reported as renames.
+ * @param {string|null} beforeFile - Path at the base ref (null if added)
+ * @param {string|null} afterFile - Path at the head ref (null if deleted)
+ * @param {Object} before - Symbols at the base ref
+ * @param {Object} after - Symbols at the head ref
+ * @param {Object} result - Accumulator `{added, removed, renamed, changed}`
+ */
+function compareFile(beforeFile, afterFile, before, after, result) {
+  const oldIndex = indexSymbols(before);
+  const newIndex = indexSymbols(after);
+  const removed = [];
+  const added = [];
+
+  for (const [key, { category, entry }] of oldIndex) {
+    const match = newIndex.get(key);
+    if (!match) {
+      removed.push(describe(beforeFile, category, entry));
+      continue;
+    }
+    const was = describe(beforeFile, category, entry);
+    const now = describe(afterFile, category, match.entry);
+    if (was.signature !== now.signature || was.kind !== now.kind || was.exported !== now.exported) {
+      result.changed.push({ ...now, before: { kind: was.kind, signature: was.signature, exported: was.exported } });
+    }
+  }
+  for (const [key, { category, entry }] of newIndex) {
+    if (!oldIndex.has(key)) added.push(describe(afterFile, category, entry));
+  }
+
+  for (const item of removed) {
+    const candidates = added.filter(other => other.kind === item.kind && item.signature && other.signature === item.signature);
+    if (candidates.length === 1) {
+      const target = candidates[0];
+      added.splice(added.indexOf(target), 1);
+      result.renamed.push({ ...target, from: { file: item.file, name: item.name, line: item.line } });
+    } else {
+      result.removed.push(item);
+    }
+  }
+  result.added.push(...added);
+}
+
+/**
+ * Compare symbols across parsed changes
+ * @param {Object} changes - Result of updater.parseDiff
+ * @param {Function} extractBefore - `(files) => {file: symbols}` for the old side
+ * @param {Function} extractAfter - `(files) => {file: symbols}` for the new side
+ * @returns {{files: Object, symbols: Object}}
+ */
+function compareChanges(changes, extractBefore, extractAfter) {
+  const isSource = file => getLanguage(file) !== null;
+  const renamed = changes.renamed.filter(({ from, to }) => isSource(from) || isSource(to));
+  const oldFiles = [...changes.modified, ...changes.deleted, ...renamed.map(r => r.from)].filter(isSource);
+  const newFiles = [...changes.modified, ...changes.added, ...renamed.map(r => r.to)].filter(isSource);
+
+  const before = extractBefore(Array.from(new Set(oldFiles)));
+  const after = extractAfter(Array.from(new Set(newFiles)));
+
+  const symbols = { added: [], removed: [], renamed: [], changed: [] };
+  const renamedTargets = new Set(renamed.map(r => r.to));
+  for (const file of changes.added.filter(isSource)) compareFile(null, file, null, after[file], symbols);
+  for (const file of changes.deleted.filter(isSource)) compareFile(file, null, before[file], null, symbols);
+  for (const file of changes.modified.filter(file => isSource(file) && !renamedTargets.has(file))) {
+    compareFile(file, file, before[file], after[file], symbols);
+  }
+  for (const { from, to } of renamed) compareFile(from, to, before[from], after[to], symbols);
+
+  const byLocation = (a, b) => (a.file || '').localeCompare(b.file || '') || (a.line || 0) - (b.line || 0);
+  for (const list of Object.values(symbols)) list.sort(byLocation);
+
+  return {
+    files: {
+      added: changes.added.filter(isSource),
+      removed: changes.deleted.filter(isSource),
+      modified: changes.modified.filter(file => isSource(file) && !renamedTargets.has(file)),
+      renamed
+    },
+    symbols
+  };
+}
+
+/**
+ * Diff symbols between two refs
+ * @param {string} basePath - Repository root
+ * @param {string} base - Base ref
+ * @param {string} [head='HEAD'] - Head ref
+ * @param {Object} [options]
+ * @param {string} [options.cmd] - ast-grep command (required unless `extract` is given)
+ * @param {Function} [options.extract] - `(commit, files) => {file: symbols}` override
+ * @returns {{success: boolean, base?: string, head?: string, files?: Object, symbols?: Object, error?: string}}
+ */
+function diffRefs(basePath, base, head = 'HEAD', options = {}) {
+  const baseCommit = resolveRef(basePath, base);
+  if (!baseCommit) return { success: false, error: `Unknown ref: ${base}` };
+  const headCommit = resolveRef(basePath, head);
+  if (!headCommit) return { success: false, error: `Unknown ref: ${head}` };
+
+  const diff = git(basePath, ['diff', '--name-status', '-M', baseCommit, headCommit]);
+  if (diff === null) return { success: false, error: 'git diff failed' };
+
+  const extract = options.extract || ((commit, files) => extractAtRef(options.cmd, basePath, commit, files));
+  return {
+    success: true,
+    base: baseCommit,
+    head: headCommit,
+    ...compareChanges(parseDiff(diff.trim()), files => extract(baseCommit, files), files => extract(headCommit, files))
+  };
+}
+
+/**
+ * Diff symbols between HEAD and the index (what `git commit` would record)
+ * Before the first commit every staged file counts as added.
+ * @param {string} basePath - Repository root
+ * @param {Object} [options] - Same as diffRefs; `extract` receives `''` for the index
+ * @returns {{success: boolean, base?: string|null, head?: string, files?: Object, symbols?: Object, error?: string}}
+ *   `head` is `'index'`
+ */
+function diffStaged(basePath, options = {}) {
+  const baseCommit = resolveRef(basePath, 'HEAD');
+  const diff = git(basePath, ['diff', '--cached', '--name-status', '-M']);
+  if (diff === null) return { success: false, error: 'git diff --cached failed' };
+
+  const extract = options.extract || ((commit, files) => extractAtRef(options.cmd, basePath, commit, files));
+  return {
+    success: true,
+    base: baseCommit,
+    head: 'index',
+    ...compareChanges(parseDiff(diff.trim()), files => (baseCommit ? extract(baseCommit, files) : {}), files => extract('', files))
+  };
+}
+
+/**
+ * Render a diff as text, one symbol per line
+ * @param {Object} diff - Result of `diffRefs`
+ * @returns {string}
+ */
+function renderDiff(diff) {
+  const headline = item => `${item.kind} ${item.name}${item.signature || ''}`;
+  const location = item => `${item.file}${item.line ? `:${item.line}` : ''}`;
+  const lines = [];
+  for (const item of diff.symbols.added) lines.push(`+ ${headline(item)}  ${location(item)}`);
+  for (const item of diff.symbols.removed) lines.push(`- ${headline(item)}  ${location(item)}`);
+  for (const item of diff.symbols.renamed) {
+    lines.push(`~ ${item.from.name} -> ${headline(item)}  ${location(item)}`);
+  }
+  for (const item of diff.symbols.changed) {
+    const before = `${item.before.kind} ${item.name}${item.before.signature || ''}`;
+    const visibility = item.before.exported !== item.exported ? (item.exported ? ' (now exported)' : ' (no longer exported)') : '';
+    lines.push(`* ${before} -> ${headline(item)}${visibility}  ${location(item)}`);
+  }
+  return lines.length > 0 ? lines.join('\n') + '\n' : 'No symbol changes\n';
+}
+
+module.exports = {
+  diffRefs,
+  diffStaged,
+  renderDiff,
+  extractAtRef,
+  resolveRef
+};
@@ -10,6 +10,32 @@
 const fs = require('fs');
 const path = require('path');
 
+/**
+ * Messages `validate` reports without a property path
+ */
+const UNSCOPED_ERROR = /^(Missing required property|Unexpected property|Expected type|String too|Array )/;
+
+/**
+ * JSON type name of a value (`integer` for whole numbers)
+ * @param {*} value - Value
+ * @returns {string}
+ */
+function typeOf(value) {
+  if (value === null) return 'null';
+  if (Array.isArray(value)) return 'array';
+  return typeof value;
+}
+
+/**
+ * Whether a value has one of the schema's types (`type` may be a list)
+ * @param {*} value - Value
+ * @param {string|string[]} type - Schema type
+ * @returns {boolean}
+ */
+function matchesType(value, type) {
+  return [].concat(type).some(name => (name === 'integer' ? Number.isInteger(value) : typeOf(value) === name));
+}
+
 /**
  * Simple JSON Schema validator (minimal implementation)
  * For production use, consider using a library like ajv
@@ -33,22 +59,12 @@ class SchemaValidator {
    */
   static validate(data, schema) {
     const errors = [];
+    schema = this.resolveRef(schema);
 
     // Check type (handle arrays correctly and null separately)
-    if (schema.type) {
-      let actualType;
-      if (data === null) {
-        actualType = 'null';
-      } else if (Array.isArray(data)) {
-        actualType = 'array';
-      } else {
-        actualType = typeof data;
-      }
-
-      if (actualType !== schema.type) {
-        errors.push(`Expected type ${schema.type}, got ${actualType}`);
-        return { valid: false, errors };
-      }
+    if (schema.type && !matchesType(data, schema.type)) {
+      errors.push(`Expected type ${[].concat(schema.type).join(' or ')}, got ${typeOf(data)}`);
+      return { valid: false, errors };
     }
 
     // String validations (for primitive string values)
@@ -105,17 +121,21 @@ class SchemaValidator {
     }
 
     // Check additional properties (guard against null, honor patternProperties)
-    if (schema.additionalProperties === false && typeof data === 'object' && data !== null) {
+    if ((schema.additionalProperties !== undefined || schema.patternProperties) && typeof data === 'object' && data !== null && !Array.isArray(data)) {
       const allowedKeys = new Set(Object.keys(schema.properties || {}));
-
-      // Honor patternProperties - don't reject keys that match patterns
-      const patternProps = schema.patternProperties || {};
-      const patternRegexes = Object.keys(patternProps).map(p => new RegExp(p));
+      const patternProps = Object.entries(schema.patternProperties || {}).map(([p, propSchema]) => [new RegExp(p), propSchema]);
 
       for (const key of Object.keys(data)) {
-        const matchesPattern = patternRegexes.some(regex => regex.test(key));
-        if (!allowedKeys.has(key) && !matchesPattern) {
+        if (allowedKeys.has(key)) continue;
+        const matched = patternProps.filter(([regex]) => regex.test(key));
+        for (const [, propSchema] of matched) {
+          errors.push(...this.validateProperty(data[key], propSchema, key).errors);
+        }
+        if (matched.length > 0) continue;
+        if (schema.additionalProperties === false) {
           errors.push(`Unexpected property: ${key}`);
+        } else if (schema.additionalProperties && typeof schema.additionalProperties === 'object') {
+          errors.push(...this.validateProperty(data[key], schema.additionalProperties, key).errors);
         }
       }
     }
@@ -132,18 +152,32 @@ class SchemaValidator {
    */
   static validateProperty(value, schema, path) {
     const errors = [];
+    schema = this.resolveRef(schema);
 
     // Type check
-    if (schema.type) {
-      const actualType = Array.isArray(value) ? 'array' : typeof value;
-      if (actualType !== schema.type) {
-        errors.push(`${path}: expected type ${schema.type}, got ${actualType}`);
-        return { valid: false, errors };
+    if (schema.type && !matchesType(value, schema.type)) {
+      errors.push(`${path}: expected type ${[].concat(schema.type).join(' or ')}, got ${typeOf(value)}`);
+      return { valid: false, errors };
+    }
+
+    // Number validations
+    if (typeof value === 'number') {
+      if (schema.minimum !== undefined && value < schema.minimum) {
+        errors.push(`${path}: must be at least ${schema.minimum}`);
+      }
+      if (schema.exclusiveMinimum !== undefined && value <= schema.exclusiveMinimum) {
+        errors.push(`${path}: must be greater than ${schema.exclusiveMinimum}`);
+      }
+      if (schema.maximum !== undefined && value > schema.maximum) {
+        errors.push(`${path}: must be at most ${schema.maximum}`);
+      }
+      if (schema.exclusiveMaximum !== undefined && value >= schema.exclusiveMaximum) {
+        errors.push(`${path}: must be less than ${schema.exclusiveMaximum}`);
       }
     }
 
     // String validations
-    if (schema.type === 'string' && typeof value === 'string') {
+    if (typeof value === 'string') {
       if (schema.minLength && value.length < schema.minLength) {
         errors.push(`${path}: string too short (min ${schema.minLength})`);
       }
@@ -155,8 +189,13 @@ class SchemaValidator {
       }
     }
 
+    // Enum validation
+    if (Array.isArray(schema.enum) && !schema.enum.includes(value)) {
+      errors.push(`${path}: must be one of ${schema.enum.join(', ')}`);
+    }
+
     // Array validations
-    if (schema.type === 'array' && Array.isArray(value)) {
+    if (Array.isArray(value)) {
       if (schema.minItems && value.length < schema.minItems) {
         errors.push(`${path}: array too short (min ${schema.minItems})`);
       }
@@ -174,19 +213,56 @@ class SchemaValidator {
           seen.add(key);
         }
       }
+      if (schema.items) {
+        value.forEach((item, index) => {
+          errors.push(...this.validateProperty(item, schema.items, `${path}[${index}]`).errors);
+        });
+      }
     }
 
     // Object validations
-    if (schema.type === 'object' && typeof value === 'object' && value !== null) {
+    if (typeOf(value) === 'object' && [].concat(schema.type).includes('object')) {
       const result = this.validate(value, schema);
       for (const error of result.errors) {
-        errors.push(`${path}.${error}`);
+        errors.push(UNSCOPED_ERROR.test(error) ? `${path}: ${error}` : `${path}.${error}`);
       }
     }
 
     return { valid: errors.length === 0, errors };
   }
 
+  /**
+   * Resolve a `$ref` to another schema file in this directory
+   * @param {Object} schema - Schema, possibly `{"$ref": "name.schema.json"}`
+   * @returns {Object} Referenced schema, or the schema itself
+   */
+  static resolveRef(schema) {
+    if (!schema || typeof schema.$ref !== 'string') return schema;
+    return this.loadSchema(path.join(__dirname, path.basename(schema.$ref)));
+  }
+
+  /**
+   * Validate a project config (.awesome-slash.json or awesome-slash.config.js)
+   * @param {Object} config - Parsed project config
+   * @returns {{valid: boolean, errors: string[]}} Validation result
+   */
+  static validateProjectConfig(config) {
+    const schemaPath = path.join(__dirname, 'project-config.schema.json');
+    const schema = this.loadSchema(schemaPath);
+    return this.validate(config, schema);
+  }
+
+  /**
+   * Validate a custom slop pattern definition (slopPatterns.<name> in project config)
+   * @param {Object} definition - Pattern definition
+   * @returns {{valid: boolean, errors: string[]}} Validation result
+   */
+  static validateSlopPattern(definition) {
+    const schemaPath = path.join(__dirname, 'slop-pattern.schema.json');
+    const schema = this.loadSchema(schemaPath);
+    return this.validate(definition, schema);
+  }
+
   /**
    * Validate a plugin manifest
    * @param {Object} manifest - Plugin manifest to validate
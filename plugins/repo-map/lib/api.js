@@ -0,0 +1,215 @@
+/**
+ * Awesome-Slash Programmatic API
+ *
+ * The package entry point (`require('awesome-slash')`): the slash commands'
+ * building blocks as functions that return structured objects and never
+ * print, for bots and scripts.
+ *
+ *   const slash = require('awesome-slash');
+ *   const platform = await slash.detect();
+ *   const { findings, gate } = slash.scanSlop({ cwd: repo, failOn: 'high' });
+ *   const review = slash.review(diffText, { cwd: repo });
+ *
+ * These four signatures are stable across minor versions. The platform
+ * detection exports the package used to resolve to (detectCI,
+ * detectProjectType, ...) stay available; everything else lives in
+ * `awesome-slash/lib`.
+ *
+ * @module awesome-slash
+ * @author Avi Fenesh
+ * @license MIT
+ */
+
+const fs = require('fs');
+const path = require('path');
+const detectPlatform = require('./platform/detect-platform');
+const repoMap = require('./repo-map');
+const { runPipeline, buildSummary, evaluateSeverityGate, THOROUGHNESS } = require('./patterns/pipeline');
+const { loadScanSettings } = require('./patterns/custom-patterns');
+const diffScope = require('./patterns/diff-scope');
+const exec = require('./utils/exec');
+
+const DETECT_SCRIPT = path.join(__dirname, 'platform', 'detect-platform.js');
+
+/**
+ * Detect the project platform: CI, deployment, project type, branch strategy
+ * The current directory is detected in-process and cached for the process.
+ * Detectors read paths relative to `process.cwd()`, so another `cwd` is
+ * detected by the detection CLI run there; detectors added with
+ * `registerDetector` only apply in-process (project detector files load in both).
+ * @param {Object|boolean} [options] - Options, or `refresh` as a boolean
+ * @param {string} [options.cwd=process.cwd()] - Repository root
+ * @param {boolean} [options.refresh=false] - Skip the in-process and persisted cache
+ * @returns {Promise<Object>} Detection result (see lib/platform/detect-platform.js)
+ */
+async function detect(options = {}) {
+  const refresh = typeof options === 'boolean' ? options : Boolean(options && options.refresh);
+  const cwd = options && typeof options === 'object' && options.cwd ? path.resolve(options.cwd) : null;
+  if (!cwd || cwd === path.resolve(process.cwd())) return detectPlatform.detect(refresh);
+
+  const output = await exec.runAsync(cwd, [process.execPath, DETECT_SCRIPT, ...(refresh ? ['--refresh'] : [])]);
+  if (output === null) throw new Error(`Platform detection failed in ${cwd}`);
+  return JSON.parse(output);
+}
+
+/**
+ * Build or refresh the cached repo map
+ * An existing map is updated incrementally unless `full` is set; an update
+ * that needs a rebuild (e.g. the map's commit is gone) rebuilds.
+ * @param {Object} [options]
+ * @param {string} [options.cwd=process.cwd()] - Repository root
+ * @param {boolean} [options.full=false] - Rebuild from scratch
+ * @param {string[]} [options.languages] - Languages to scan on a rebuild (auto-detected when omitted)
+ * @param {boolean} [options.noCache=false] - Re-extract every file on a rebuild
+ * @param {number} [options.concurrency] - Maximum extraction worker threads
+ * @returns {Promise<{success: boolean, action: string, map?: Object, summary?: Object, changes?: Object, error?: string, installSuggestion?: string}>}
+ *   `action` is init or update
+ */
+async function buildRepoMap(options = {}) {
+  const basePath = path.resolve(options.cwd || process.cwd());
+  const scan = { noCache: options.noCache, concurrency: options.concurrency, languages: options.languages };
+  if (!options.full && repoMap.exists(basePath)) {
+    const updated = await repoMap.update(basePath, scan);
+    if (!updated.needsFullRebuild) return { ...updated, action: 'update' };
+  }
+  return { ...(await repoMap.init(basePath, { ...scan, force: true })), action: 'init' };
+}
+
+/**
+ * Gate thresholds: explicit options, then `severity` in the project config
+ * @param {Object} options - failOn / warnOn
+ * @param {Object} scanSettings - From loadScanSettings
+ * @returns {{failOn: string, warnOn: string}}
+ */
+function gatePolicy(options, scanSettings) {
+  const configured = (scanSettings && scanSettings.severity) || {};
+  return {
+    failOn: options.failOn || configured.failOn || 'critical',
+    warnOn: options.warnOn || configured.warnOn || 'medium'
+  };
+}
+
+/**
+ * Shape a pipeline result for callers: no LLM prompt, a gate verdict added
+ * @param {Object} result - runPipeline result
+ * @param {{failOn: string, warnOn: string}} policy - Gate thresholds
+ * @returns {Object}
+ */
+function scanResult(result, policy) {
+  return {
+    findings: result.findings,
+    summary: result.summary,
+    gate: { ...policy, ...evaluateSeverityGate(result.summary.bySeverity, policy) },
+    configErrors: result.configErrors,
+    customPatternErrors: result.customPatternErrors,
+    linterEnforced: result.linterEnforced,
+    missingTools: result.missingTools,
+    baseline: result.baseline,
+    diffScope: result.diffScope,
+    metadata: result.metadata
+  };
+}
+
+/**
+ * Scan for slop: debug leftovers, placeholders, secrets, dead code, ...
+ * Project settings (`ignore`, `patterns`, `severity`, `slopPatterns`, the
+ * baseline) apply as they do for /deslop.
+ * @param {Object} [options] - Also accepts any runPipeline option (e.g. `baseline: false`)
+ * @param {string} [options.cwd=process.cwd()] - Repository root
+ * @param {string[]} [options.files] - Repo-relative files to scan (defaults to all source files)
+ * @param {string} [options.thoroughness='normal'] - quick, normal, or deep
+ * @param {string} [options.diffBase] - Only findings on lines changed since this ref
+ * @param {string} [options.failOn] - Lowest failing severity (default: config, else critical)
+ * @param {string} [options.warnOn] - Lowest warning severity (default: config, else medium)
+ * @returns {{findings: Object[], summary: Object, gate: Object, configErrors: string[], metadata: Object}}
+ *   `gate.exitCode` is 0 when nothing fails, else the /deslop exit code of the most severe failing finding
+ * @throws {Error} When `diffBase` cannot be diffed
+ */
+function scanSlop(options = {}) {
+  const { cwd, files, failOn, warnOn, ...pipelineOptions } = options;
+  const basePath = path.resolve(cwd || process.cwd());
+  const scanSettings = pipelineOptions.scanSettings !== undefined
+    ? pipelineOptions.scanSettings
+    : loadScanSettings(basePath);
+  const result = runPipeline(basePath, {
+    thoroughness: THOROUGHNESS.NORMAL,
+    ...pipelineOptions,
+    scanSettings,
+    targetFiles: files,
+    mode: 'report'
+  });
+  return scanResult(result, gatePolicy({ failOn, warnOn }, scanSettings));
+}
+
+/**
+ * Review a unified diff: slop findings on the lines it adds
+ * The diff must describe the files as they are on disk at `cwd` (e.g. the
+ * output of `git diff main...HEAD` in that checkout); line numbers are the
+ * new-side numbers.
+ * @param {string} diff - Unified diff text
+ * @param {Object} [options] - scanSlop options other than `files` and `diffBase`
+ * @param {boolean} [options.includeTests=false] - Review changed test files too
+ * @returns {{files: Array<{file: string, addedLines: number, findings: Object[]}>, skipped: Array<{file: string, reason: string}>,
+ *   findings: Object[], summary: Object, gate: Object, configErrors: string[], metadata: Object}}
+ * @throws {Error} When diff is not a string
+ */
+function review(diff, options = {}) {
+  if (typeof diff !== 'string') {
+    throw new Error('review() expects unified diff text');
+  }
+  const { includeTests = false, ...scanOptions } = options;
+  const basePath = path.resolve(scanOptions.cwd || process.cwd());
+  const changed = diffScope.parseUnifiedDiff(diff);
+  const sources = new Set(diffScope.selectSourceFiles(basePath, changed, { includeTests }));
+
+  const targets = [];
+  const skipped = [];
+  for (const file of changed.keys()) {
+    if (!sources.has(file)) {
+      skipped.push({ file, reason: 'not a source file, test, or ignored' });
+    } else if (!fs.existsSync(path.join(basePath, file))) {
+      skipped.push({ file, reason: 'not found on disk' });
+    } else {
+      targets.push(file);
+    }
+  }
+
+  const scanSettings = scanOptions.scanSettings !== undefined ? scanOptions.scanSettings : loadScanSettings(basePath);
+  const policy = gatePolicy(scanOptions, scanSettings);
+  if (targets.length === 0) {
+    const empty = scanResult({
+      findings: [],
+      summary: buildSummary([]),
+      configErrors: scanSettings ? scanSettings.errors : [],
+      customPatternErrors: [],
+      linterEnforced: [],
+      missingTools: [],
+      baseline: null,
+      diffScope: null,
+      metadata: { repoPath: basePath, filesAnalyzed: 0, timestamp: new Date().toISOString() }
+    }, policy);
+    return { files: [], skipped, ...empty };
+  }
+
+  const result = scanSlop({
+    ...scanOptions,
+    scanSettings,
+    files: targets,
+    diffBase: 'diff',
+    changedLines: new Map(targets.map(file => [file, changed.get(file)]))
+  });
+  const byFile = targets.map(file => ({
+    file,
+    addedLines: changed.get(file).length,
+    findings: result.findings.filter(finding => String(finding.file).replace(/\\/g, '/').replace(/^\.\//, '') === file)
+  }));
+  return { files: byFile, skipped, ...result };
+}
+
+module.exports = {
+  ...detectPlatform,
+  detect,
+  buildRepoMap,
+  scanSlop,
+  review
+};
@@ -0,0 +1,597 @@
+#!/usr/bin/env node
+/**
+ * Conventional Commit Messages from the Staged Diff
+ *
+ * Proposes `type(scope): subject` plus a body for what `git commit` would
+ * record. The type comes from the kinds of files staged and the repo-map
+ * symbol diff (new symbols are a feature, only removals and renames a
+ * refactor), the scope from the workspace package or source directory all
+ * changes share, and the body lists the symbol-level changes. Removed,
+ * renamed, or unexported exports mark the commit as breaking.
+ *
+ * A commitlint config in the repository, when present, sets the allowed
+ * types and scopes, the header and body lengths, and the subject case. It
+ * is read statically; JavaScript and TypeScript configs are never executed,
+ * so only literal `extends` and `rules` values are understood.
+ *
+ * Usage: node lib/commit/index.js
+ * Output: JSON with the message, its parts, and any commitlint problems
+ *
+ * @module lib/commit
+ */
+
+const fs = require('fs');
+const path = require('path');
+
+const { groupFiles } = require('../pr-description');
+const { findBreakingChanges } = require('../patterns/api-design');
+const { git } = require('../utils/exec');
+
+/**
+ * commitlint config files, in the order commitlint searches them
+ * (`package.json` with a `commitlint` field comes first)
+ */
+const COMMITLINT_FILES = [
+  '.commitlintrc',
+  '.commitlintrc.json',
+  '.commitlintrc.yaml',
+  '.commitlintrc.yml',
+  '.commitlintrc.js',
+  '.commitlintrc.cjs',
+  '.commitlintrc.mjs',
+  '.commitlintrc.ts',
+  '.commitlintrc.cts',
+  'commitlint.config.js',
+  'commitlint.config.cjs',
+  'commitlint.config.mjs',
+  'commitlint.config.ts',
+  'commitlint.config.cts'
+];
+
+/**
+ * Rules of @commitlint/config-conventional that shape a message
+ * Used when a config extends it, and when the repository has no config.
+ */
+const CONVENTIONAL_RULES = {
+  'body-leading-blank': [1, 'always'],
+  'body-max-line-length': [2, 'always', 100],
+  'header-max-length': [2, 'always', 100],
+  'subject-case': [2, 'never', ['sentence-case', 'start-case', 'pascal-case', 'upper-case']],
+  'subject-empty': [2, 'never'],
+  'subject-full-stop': [2, 'never', '.'],
+  'type-empty': [2, 'never'],
+  'type-enum': [2, 'always', ['build', 'chore', 'ci', 'docs', 'feat', 'fix', 'perf', 'refactor', 'revert', 'style', 'test']]
+};
+
+/**
+ * Commit type for changes that touch only one kind of non-source file
+ */
+const GROUP_TYPES = { Tests: 'test', Docs: 'docs', CI: 'ci', Dependencies: 'build', Config: 'chore' };
+
+/**
+ * Types to fall back to, in order, when type-enum rejects the inferred one
+ */
+const FALLBACK_TYPES = ['chore', 'fix', 'feat', 'refactor'];
+
+/**
+ * Top-level directories that hold source rather than name a component
+ */
+const SOURCE_ROOTS = ['src', 'lib', 'pkg', 'internal', 'app', 'cmd', 'source'];
+
+const STATUS_NAMES = { A: 'added', M: 'modified', D: 'deleted', R: 'renamed', C: 'copied', T: 'modified' };
+const HEADER = /^(\w+)(?:\(([^)]*)\))?(!)?:\s*(.*)$/;
+const MAX_BODY_LINES = 10;
+
+/**
+ * Files staged for the next commit
+ * @param {string} basePath - Repository root
+ * @returns {Array<{file: string, status: string, from?: string}>|null} null outside a repository
+ */
+function getStagedFiles(basePath) {
+  const out = git(basePath, ['diff', '--cached', '--name-status', '-M']);
+  if (out === null) return null;
+  return out.split('\n').filter(Boolean).map(line => {
+    const [status, first, second] = line.split('\t');
+    const kind = STATUS_NAMES[status[0]] || 'modified';
+    return second ? { file: second, status: kind, from: first } : { file: first, status: kind };
+  });
+}
+
+/**
+ * Convert a JavaScript or YAML flow literal to a value
+ * Quotes bare words (`always`, `feat`, unquoted keys), maps
+ * `RuleConfigSeverity.*` to levels, and drops comments and trailing commas.
+ * @param {string} text - Literal source (`[2, 'always', 72]`, `{...}`)
+ * @returns {*} Parsed value
+ * @throws {SyntaxError} When the literal is not plain data
+ */
+function parseLiteral(text) {
+  const source = String(text)
+    .replace(/\/\*[\s\S]*?\*\//g, '')
+    .replace(/(^|[^:'"])\/\/.*$/gm, '$1')
+    .replace(/\bRuleConfigSeverity\.(Disabled|Warning|Error)\b/g, (match, level) => String(['Disabled', 'Warning', 'Error'].indexOf(level)));
+  const tokens = source.match(/'(?:[^'\\]|\\.)*'|"(?:[^"\\]|\\.)*"|`[^`$]*`|-?\d+(?:\.\d+)?|[[\]{}:,]|[^\s[\]{}:,'"`]+/g) || [];
+  const json = tokens.map(token => {
+    if (/^'/.test(token)) return JSON.stringify(token.slice(1, -1).replace(/\\'/g, '\''));
+    if (/^`/.test(token)) return JSON.stringify(token.slice(1, -1));
+    if (/^"|^-?\d|^[[\]{}:,]$|^(?:true|false|null)$/.test(token)) return token;
+    if (/^[\w@$./-]+$/.test(token)) return JSON.stringify(token);
+    throw new SyntaxError(`Not a literal: ${token}`);
+  }).join('').replace(/,([\]}])/g, '$1');
+  return JSON.parse(json);
+}
+
+/**
+ * The balanced `[...]`, `{...}`, or quoted value starting at an index
+ * @param {string} text - Source text
+ * @param {number} start - Index of the opening character
+ * @returns {string|null}
+ */
+function balancedAt(text, start) {
+  const open = text[start];
+  if (open === '\'' || open === '"') {
+    const end = text.indexOf(open, start + 1);
+    return end === -1 ? null : text.slice(start, end + 1);
+  }
+  const close = { '[': ']', '{': '}' }[open];
+  if (!close) return null;
+  let depth = 0;
+  let quote = null;
+  for (let i = start; i < text.length; i++) {
+    const char = text[i];
+    if (quote) {
+      if (char === '\\') i++;
+      else if (char === quote) quote = null;
+    } else if (char === '\'' || char === '"' || char === '`') {
+      quote = char;
+    } else if (char === open) {
+      depth++;
+    } else if (char === close && --depth === 0) {
+      return text.slice(start, i + 1);
+    }
+  }
+  return null;
+}
+
+/**
+ * `extends` and `rules` from a JavaScript or TypeScript config, read as text
+ * @param {string} content - Config source
+ * @returns {{extends: string[], rules: Object}}
+ * @throws {SyntaxError} When a value is not a literal
+ */
+function readScriptConfig(content) {
+  const config = { extends: [], rules: {} };
+  for (const key of ['extends', 'rules']) {
+    const match = new RegExp(`(?:^|[{,\\s])['"]?${key}['"]?\\s*:\\s*`).exec(content);
+    if (!match) continue;
+    const value = balancedAt(content, match.index + match[0].length);
+    if (!value) throw new SyntaxError(`${key} is not a literal`);
+    config[key] = parseLiteral(value);
+  }
+  return config;
+}
+
+/**
+ * `extends` and `rules` from a YAML config
+ * Rule values must be flow sequences on one line (`[2, always, 72]`).
+ * @param {string} content - Config source
+ * @returns {{extends: string[], rules: Object}}
+ */
+function readYamlConfig(content) {
+  const config = { extends: [], rules: {} };
+  let section = null;
+  for (const raw of content.split(/\r?\n/)) {
+    const line = raw.replace(/\s+#.*$/, '');
+    if (!line.trim() || line.trim().startsWith('#')) continue;
+    const top = /^([\w-]+)\s*:\s*(.*)$/.exec(line);
+    if (top) {
+      section = top[1];
+      if (section === 'extends' && top[2]) config.extends = parseLiteral(top[2]);
+      continue;
+    }
+    const item = /^\s+-\s+(.+)$/.exec(line);
+    if (item && section === 'extends') {
+      config.extends.push(parseLiteral(item[1]));
+      continue;
+    }
+    const rule = /^\s+(['"]?)([\w-]+)\1\s*:\s*(.+)$/.exec(line);
+    if (rule && section === 'rules') config.rules[rule[2]] = parseLiteral(rule[3]);
+  }
+  return config;
+}
+
+/**
+ * Find and read the repository's commitlint config
+ * @param {string} basePath - Repository root
+ * @returns {{file: string, extends: string[], rules: Object, error: string|null}|null}
+ *   Explicit settings only (see resolveRules); null when there is no config
+ */
+function findCommitlintConfig(basePath) {
+  const read = file => {
+    try {
+      return fs.readFileSync(path.join(basePath, file), 'utf8');
+    } catch {
+      return null;
+    }
+  };
+  const result = (file, config) => ({
+    file,
+    extends: [].concat(config.extends || []).filter(entry => typeof entry === 'string'),
+    rules: config.rules && typeof config.rules === 'object' ? config.rules : {},
+    error: null
+  });
+
+  try {
+    const pkg = JSON.parse(read('package.json') || '{}');
+    if (pkg.commitlint && typeof pkg.commitlint === 'object') return result('package.json', pkg.commitlint);
+  } catch {
+    // Invalid package.json: look for a config file instead
+  }
+
+  for (const file of COMMITLINT_FILES) {
+    const content = read(file);
+    if (content === null) continue;
+    try {
+      if (/\.[cm]?[jt]s$/.test(file)) return result(file, readScriptConfig(content));
+      if (/\.ya?ml$/.test(file)) return result(file, readYamlConfig(content));
+      try {
+        return result(file, JSON.parse(content));
+      } catch {
+        // `.commitlintrc` may hold YAML
+        return result(file, readYamlConfig(content));
+      }
+    } catch (err) {
+      return { file, extends: [], rules: {}, error: `${file}: rules are not plain literals (${err.message}); using defaults` };
+    }
+  }
+  return null;
+}
+
+/**
+ * Effective rules for a config
+ * The conventional preset applies when the config extends it or when there
+ * is no config; explicit rules override it.
+ * @param {Object|null} config - Result of findCommitlintConfig
+ * @returns {Object} Rule name to `[level, when, value]`
+ */
+function resolveRules(config) {
+  if (!config) return { ...CONVENTIONAL_RULES };
+  const conventional = config.extends.some(entry => /(?:^|\/)(?:commitlint-)?config-conventional$/.test(entry));
+  return { ...(conventional ? CONVENTIONAL_RULES : {}), ...config.rules };
+}
+
+/**
+ * An enabled rule
+ * @param {Object} rules - Effective rules
+ * @param {string} name - Rule name
+ * @returns {{level: number, when: string, value: *}|null} null when missing or disabled
+ */
+function getRule(rules, name) {
+  const entry = rules && rules[name];
+  if (!Array.isArray(entry) || !entry[0]) return null;
+  return { level: entry[0], when: entry[1] || 'always', value: entry[2] };
+}
+
+/**
+ * Whether a rule allows a value in a list (`type-enum`, `scope-enum`)
+ * @param {Object} rules - Effective rules
+ * @param {string} name - Rule name
+ * @param {string} value - Candidate
+ * @returns {boolean}
+ */
+function allowedBy(rules, name, value) {
+  const rule = getRule(rules, name);
+  if (!rule || !Array.isArray(rule.value) || rule.value.length === 0) return true;
+  return rule.when === 'never' ? !rule.value.includes(value) : rule.value.includes(value);
+}
+
+/**
+ * Source-like groups: code and migrations
+ * @param {Array} groups - Result of groupFiles
+ * @returns {Array<{file: string, status: string}>}
+ */
+function sourceFiles(groups) {
+  return groups.filter(group => group.name === 'Source' || group.name === 'Migrations').flatMap(group => group.files);
+}
+
+/**
+ * Commit type for the staged changes
+ * @param {Array<{file: string, status: string}>} files - Staged files
+ * @param {Object|null} [diff] - repo-map staged diff
+ * @param {Object} [rules] - Effective commitlint rules
+ * @returns {string}
+ */
+function inferType(files, diff = null, rules = CONVENTIONAL_RULES) {
+  const groups = groupFiles(files);
+  const source = sourceFiles(groups);
+  let type;
+  if (source.length === 0) {
+    type = groups.length === 1 ? GROUP_TYPES[groups[0].name] || 'chore' : 'chore';
+  } else if (diff && diff.symbols) {
+    const { added, removed, renamed, changed } = diff.symbols;
+    if (added.length > 0 || source.some(entry => entry.status === 'added')) type = 'feat';
+    else if ((removed.length > 0 || renamed.length > 0) && changed.length === 0) type = 'refactor';
+    else type = 'fix';
+  } else {
+    type = source.some(entry => entry.status === 'added') ? 'feat' : 'fix';
+  }
+
+  if (allowedBy(rules, 'type-enum', type)) return type;
+  const fallback = FALLBACK_TYPES.find(candidate => allowedBy(rules, 'type-enum', candidate));
+  const rule = getRule(rules, 'type-enum');
+  return fallback || (rule && rule.when !== 'never' ? rule.value[0] : type);
+}
+
+/**
+ * Commit scope shared by every staged file
+ * The workspace package all files belong to, else the first directory
+ * below a source root (`lib/commit/index.js` -> `commit`).
+ * @param {Array<{file: string, status: string}>} files - Staged files
+ * @param {Object} [options]
+ * @param {Array<{name: string, path: string}>} [options.packages] - Workspace packages (detectWorkspaces().packages)
+ * @param {Object} [options.rules] - Effective commitlint rules
+ * @param {string} [options.type] - Commit type; a scope repeating it is dropped
+ * @returns {string|null}
+ */
+function inferScope(files, options = {}) {
+  const rules = options.rules || CONVENTIONAL_RULES;
+  const emptyRule = getRule(rules, 'scope-empty');
+  if (files.length === 0 || (emptyRule && emptyRule.when === 'always')) return null;
+
+  let scope = null;
+  let spansPackages = false;
+  const packages = (options.packages || []).filter(pkg => pkg.path && pkg.path !== '.');
+  if (packages.length > 0) {
+    const owner = file => packages
+      .filter(pkg => file === pkg.path || file.startsWith(`${pkg.path}/`))
+      .sort((a, b) => b.path.length - a.path.length)[0];
+    const owners = new Set(files.map(entry => owner(entry.file)));
+    const [only] = owners;
+    if (owners.size === 1 && only) scope = only.name.replace(/^@[^/]+\//, '');
+    else spansPackages = Array.from(owners).some(Boolean);
+  }
+  // Files in several packages share no scope; a directory like `packages` is not one
+  if (!scope && !spansPackages) {
+    const source = sourceFiles(groupFiles(files));
+    const dirs = new Set((source.length > 0 ? source : files).map(entry => {
+      const parts = entry.file.split('/');
+      if (parts.length > 2 && SOURCE_ROOTS.includes(parts[0])) parts.shift();
+      return parts.length > 1 ? parts[0] : null;
+    }));
+    const [only] = dirs;
+    if (dirs.size === 1 && only && !SOURCE_ROOTS.includes(only)) scope = only;
+  }
+
+  if (!scope) return null;
+  scope = scope.toLowerCase();
+  if (scope === options.type || !allowedBy(rules, 'scope-enum', scope)) return null;
+  return scope;
+}
+
+/**
+ * Join names as `a`, `a and b`, or `a, b and 3 more`
+ * @param {string[]} names - Names
+ * @param {number} limit - Names to show
+ * @returns {string}
+ */
+function listNames(names, limit) {
+  if (names.length <= limit) {
+    return names.length <= 1 ? names.join('') : `${names.slice(0, -1).join(', ')} and ${names[names.length - 1]}`;
+  }
+  return `${names.slice(0, limit).join(', ')} and ${names.length - limit} more`;
+}
+
+/**
+ * Subject candidates, most specific first: `[verb, names]`
+ * @param {string} type - Commit type
+ * @param {Array<{file: string, status: string}>} files - Staged files
+ * @param {Object|null} symbols - Symbol diff
+ * @returns {{verb: string, names: string[]}}
+ */
+function describeChange(type, files, symbols) {
+  const unique = names => Array.from(new Set(names));
+  const base = file => path.posix.basename(file).replace(/\.[^.]+$/, '');
+  if (type === 'test') {
+    const tested = unique(files.map(entry => base(entry.file).replace(/[._-](?:test|spec)$|^test_|_test$/g, '')));
+    return { verb: files.every(entry => entry.status === 'added') ? 'add tests for' : 'update tests for', names: tested };
+  }
+  if (symbols && type !== 'docs') {
+    const exportedFirst = list => unique([...list.filter(item => item.exported), ...list.filter(item => !item.exported)].map(item => item.name));
+    if (symbols.added.length > 0) return { verb: 'add', names: exportedFirst(symbols.added) };
+    if (symbols.renamed.length === 1 && symbols.removed.length === 0 && symbols.changed.length === 0) {
+      const [item] = symbols.renamed;
+      return { verb: 'rename', names: [`${item.from.name} to ${item.name}`] };
+    }
+    if (symbols.removed.length > 0 && symbols.changed.length === 0) return { verb: 'remove', names: exportedFirst(symbols.removed) };
+    if (symbols.changed.length > 0) return { verb: 'update', names: exportedFirst(symbols.changed) };
+  }
+  // Without symbols, name the source files; `index.js` alone says nothing
+  const source = sourceFiles(groupFiles(files));
+  const relevant = source.length > 0 ? source : files;
+  const name = entry => (/^(?:index|__init__|mod|main)$/.test(base(entry.file)) && entry.file.includes('/')
+    ? path.posix.join(path.posix.basename(path.posix.dirname(entry.file)), path.posix.basename(entry.file))
+    : path.posix.basename(entry.file));
+  const added = relevant.filter(entry => entry.status === 'added');
+  const deleted = relevant.filter(entry => entry.status === 'deleted');
+  if (added.length > 0 && (type === 'feat' || added.length === relevant.length)) return { verb: 'add', names: unique(added.map(name)) };
+  if (deleted.length === relevant.length) return { verb: 'remove', names: unique(deleted.map(name)) };
+  return { verb: 'update', names: unique(relevant.map(name)) };
+}
+
+/**
+ * Body bullets for the symbol and file changes
+ * @param {Array<{file: string, status: string, from?: string}>} files - Staged files
+ * @param {Object|null} symbols - Symbol diff
+ * @returns {string[]}
+ */
+function bodyLines(files, symbols) {
+  const lines = [];
+  if (symbols) {
+    for (const item of symbols.added) lines.push(`Add \`${item.name}\` in ${item.file}`);
+    for (const item of symbols.renamed) lines.push(`Rename \`${item.from.name}\` to \`${item.name}\` in ${item.file}`);
+    for (const item of symbols.removed) lines.push(`Remove \`${item.name}\` from ${item.file}`);
+    for (const item of symbols.changed) {
+      const what = item.before.exported && !item.exported ? 'Stop exporting'
+        : (item.before.signature || '') !== (item.signature || '') ? 'Change the signature of' : 'Update';
+      lines.push(`${what} \`${item.name}\` in ${item.file}`);
+    }
+  }
+  const covered = new Set(lines.length > 0 ? [...symbols.added, ...symbols.renamed, ...symbols.removed, ...symbols.changed].map(item => item.file) : []);
+  for (const entry of files) {
+    if (covered.has(entry.file)) continue;
+    if (entry.status === 'renamed') lines.push(`Rename ${entry.from} to ${entry.file}`);
+    else lines.push(`${{ added: 'Add', deleted: 'Delete' }[entry.status] || 'Update'} ${entry.file}`);
+  }
+  if (lines.length > MAX_BODY_LINES) {
+    const rest = lines.length - MAX_BODY_LINES + 1;
+    lines.splice(MAX_BODY_LINES - 1, lines.length, `...and ${rest} more`);
+  }
+  return lines;
+}
+
+/**
+ * Wrap a bullet to a line length, continuation lines indented
+ * @param {string} text - Bullet text without the marker
+ * @param {number} width - Maximum line length
+ * @returns {string}
+ */
+function wrapBullet(text, width) {
+  const lines = [];
+  let current = '-';
+  for (const word of text.split(' ')) {
+    if (current.length + 1 + word.length > width && current.trim() !== '-') {
+      lines.push(current);
+      current = ' ';
+    }
+    current += ` ${word}`;
+  }
+  lines.push(current);
+  return lines.join('\n');
+}
+
+/**
+ * Build a commit message for staged changes
+ * @param {Object} analysis
+ * @param {Array<{file: string, status: string, from?: string}>} analysis.files - Result of getStagedFiles
+ * @param {Object} [analysis.diff] - repo-map staged diff (`repoMap.diff(cwd, {staged: true})`)
+ * @param {Array<{name: string, path: string}>} [analysis.packages] - Workspace packages
+ * @param {Object|null} [analysis.config] - Result of findCommitlintConfig
+ * @returns {{message: string, header: string, type: string, scope: string|null, subject: string, body: string, breaking: Object[], problems: Object[]}}
+ */
+function buildMessage(analysis) {
+  const files = analysis.files || [];
+  const rules = resolveRules(analysis.config || null);
+  const diff = analysis.diff && analysis.diff.symbols ? analysis.diff : null;
+  const symbols = diff ? diff.symbols : null;
+  const type = inferType(files, diff, rules);
+  const scope = inferScope(files, { packages: analysis.packages, rules, type });
+  const breaking = findBreakingChanges(diff).filter(change => change.change !== 'signature');
+
+  const headerRule = getRule(rules, 'header-max-length');
+  const maxHeader = headerRule ? headerRule.value : Infinity;
+  const prefix = `${type}${scope ? `(${scope})` : ''}${breaking.length > 0 ? '!' : ''}: `;
+  const { verb, names } = describeChange(type, files, symbols);
+  const caseRule = getRule(rules, 'subject-case');
+  const sentence = caseRule && caseRule.when === 'always' && [].concat(caseRule.value).includes('sentence-case');
+  const format = text => (sentence ? `${text[0].toUpperCase()}${text.slice(1)}` : text);
+
+  let subject = format(`${verb} ${listNames(names, 2)}`.trim());
+  for (let limit = 1; prefix.length + subject.length > maxHeader && limit >= 0; limit--) {
+    subject = format(limit > 0 ? `${verb} ${listNames(names, limit)}` : `${verb} ${files.length} file${files.length === 1 ? '' : 's'}`);
+  }
+  if (prefix.length + subject.length > maxHeader) subject = subject.slice(0, Math.max(0, maxHeader - prefix.length));
+  const header = `${prefix}${subject}`;
+
+  const bodyRule = getRule(rules, 'body-max-line-length');
+  const width = bodyRule ? bodyRule.value : 100;
+  const bullets = bodyLines(files, symbols);
+  const body = bullets.length > 1 || breaking.length > 0 ? bullets.map(line => wrapBullet(line, width)).join('\n') : '';
+  const footer = breaking.length > 0
+    ? `BREAKING CHANGE: ${breaking.map(change => (change.change === 'renamed'
+      ? `\`${change.before}\` renamed to \`${change.name}\``
+      : `\`${change.name}\` ${change.change === 'removed' ? 'removed' : 'no longer exported'}`)).join(', ')}`
+    : '';
+
+  const message = `${[header, body, footer].filter(Boolean).join('\n\n')}\n`;
+  return { message, header, type, scope, subject, body, breaking, problems: lintMessage(message, rules) };
+}
+
+/**
+ * Check a message against commitlint rules
+ * Covers the header, type, scope, subject, and body rules; other rules are
+ * left to commitlint itself.
+ * @param {string} message - Commit message
+ * @param {Object} [rules] - Effective rules (resolveRules)
+ * @returns {Array<{rule: string, level: string, message: string}>} level is `error` or `warning`
+ */
+function lintMessage(message, rules = CONVENTIONAL_RULES) {
+  const problems = [];
+  const lines = String(message).replace(/\n+$/, '').split('\n');
+  const header = lines[0] || '';
+  const parsed = HEADER.exec(header);
+  const type = parsed ? parsed[1] : '';
+  const scope = parsed ? parsed[2] || '' : '';
+  const subject = parsed ? parsed[4] : header;
+  const check = (name, failed, text) => {
+    const rule = getRule(rules, name);
+    if (rule && failed(rule)) problems.push({ rule: name, level: rule.level === 2 ? 'error' : 'warning', message: text(rule) });
+  };
+  const violated = (rule, condition) => (rule.when === 'never' ? condition : !condition);
+
+  check('header-max-length', rule => header.length > rule.value, rule => `Header is ${header.length} characters; the limit is ${rule.value}`);
+  check('type-empty', rule => violated(rule, !type), rule => (rule.when === 'never' ? 'Type is missing' : 'Type must be empty'));
+  check('type-enum', rule => type && !allowedBy(rules, 'type-enum', type), rule => `Type "${type}" is not one of ${[].concat(rule.value).join(', ')}`);
+  check('scope-empty', rule => violated(rule, !scope), rule => (rule.when === 'never' ? 'Scope is required' : 'Scope must be empty'));
+  check('scope-enum', rule => scope && !allowedBy(rules, 'scope-enum', scope), rule => `Scope "${scope}" is not one of ${[].concat(rule.value).join(', ')}`);
+  check('subject-empty', rule => violated(rule, !subject.trim()), rule => (rule.when === 'never' ? 'Subject is missing' : 'Subject must be empty'));
+  check('subject-full-stop', rule => violated(rule, subject.endsWith(rule.value || '.')), rule => `Subject ${rule.when === 'never' ? 'must not' : 'must'} end with "${rule.value || '.'}"`);
+  check('subject-case', rule => {
+    const cases = [].concat(rule.value || []);
+    const sentence = /^[A-Z]/.test(subject);
+    const matches = (cases.includes('sentence-case') && sentence) || (cases.includes('lower-case') && subject === subject.toLowerCase());
+    return subject && (cases.includes('sentence-case') || cases.includes('lower-case')) && violated(rule, matches);
+  }, rule => `Subject case must ${rule.when === 'never' ? 'not be' : 'be'} ${[].concat(rule.value).join(' or ')}`);
+  check('body-leading-blank', rule => lines.length > 1 && violated(rule, lines[1] === ''), () => 'Body must be separated from the header by a blank line');
+  const bodyLine = lines.slice(1).find(line => line.length > ((getRule(rules, 'body-max-line-length') || {}).value || Infinity) && !/^\S*:\/\//.test(line.trim()));
+  check('body-max-line-length', () => Boolean(bodyLine), rule => `Body line is ${bodyLine.length} characters; the limit is ${rule.value}`);
+
+  return problems;
+}
+
+// When run directly, output JSON
+if (require.main === module) {
+  const basePath = process.cwd();
+  const files = getStagedFiles(basePath);
+  if (!files || files.length === 0) {
+    console.log(JSON.stringify({ success: false, error: files ? 'Nothing staged' : 'Not a git repository' }));
+    process.exitCode = 1;
+  } else {
+    const diff = require('../repo-map').diff(basePath, { staged: true });
+    require('../platform/detect-platform').detectWorkspaces()
+      .catch(() => null)
+      .then(monorepo => {
+        const config = findCommitlintConfig(basePath);
+        const result = buildMessage({
+          files,
+          diff: diff.success ? diff : null,
+          packages: monorepo ? monorepo.packages : [],
+          config
+        });
+        const indent = process.stdout.isTTY ? 2 : 0;
+        console.log(JSON.stringify({ success: true, config: config && config.file, ...result }, null, indent));
+      });
+  }
+}
+
+module.exports = {
+  COMMITLINT_FILES,
+  CONVENTIONAL_RULES,
+  getStagedFiles,
+  parseLiteral,
+  findCommitlintConfig,
+  resolveRules,
+  inferType,
+  inferScope,
+  buildMessage,
+  lintMessage
+};
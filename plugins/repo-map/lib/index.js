@@ -12,13 +12,33 @@
 
 const detectPlatform = require('./platform/detect-platform');
 const verifyTools = require('./platform/verify-tools');
+const detectDatabase = require('./platform/detect-database');
+const detectTests = require('./platform/detect-tests');
+const detectLinters = require('./platform/detect-linters');
+const detectSecrets = require('./platform/detect-secrets');
+const detectRuntimes = require('./platform/detect-runtimes');
+const detectFrameworkVersions = require('./platform/detect-framework-versions');
 const reviewPatterns = require('./patterns/review-patterns');
+const patternPacks = require('./patterns/pattern-packs');
 const slopPatterns = require('./patterns/slop-patterns');
 const pipeline = require('./patterns/pipeline');
+const customPatterns = require('./patterns/custom-patterns');
+const baseline = require('./patterns/baseline');
+const fixer = require('./patterns/fixer');
+const diffScope = require('./patterns/diff-scope');
+const sarif = require('./patterns/sarif');
+const duplicates = require('./patterns/duplicates');
+const secrets = require('./patterns/secrets');
+const migrations = require('./patterns/migrations');
+const apiDesign = require('./patterns/api-design');
 const cliEnhancers = require('./patterns/cli-enhancers');
 const workflowState = require('./state/workflow-state');
 const contextOptimizer = require('./utils/context-optimizer');
 const shellEscape = require('./utils/shell-escape');
+const ignore = require('./utils/ignore');
+const git = require('./utils/git');
+const scanLimits = require('./utils/scan-limits');
+const codeowners = require('./utils/codeowners');
 const config = require('./config');
 const sourceCache = require('./sources/source-cache');
 const customHandler = require('./sources/custom-handler');
@@ -26,6 +46,32 @@ const policyQuestions = require('./sources/policy-questions');
 const crossPlatform = require('./cross-platform');
 const enhance = require('./enhance');
 const repoMap = require('./repo-map');
+const changelog = require('./changelog');
+const release = require('./release');
+const prDescription = require('./pr-description');
+const commit = require('./commit');
+const resolve = require('./resolve');
+const deps = require('./deps');
+const migrate = require('./migrate');
+const onboard = require('./onboard');
+const todos = require('./todos');
+const flaky = require('./flaky');
+const envCheck = require('./env-check');
+const licenseCheck = require('./license-check');
+const sbom = require('./sbom');
+const history = require('./history');
+const report = require('./report');
+const benchmark = require('./benchmark');
+const docsGen = require('./docs-gen');
+const issues = require('./issues');
+const notify = require('./notify');
+const gitHooks = require('./git-hooks');
+const plugins = require('./plugins');
+const journal = require('./journal');
+const telemetry = require('./telemetry');
+const cache = require('./cache');
+const taskRunner = require('./task-runner');
+const messages = require('./messages');
 
 /**
  * Platform detection and verification utilities
@@ -38,13 +84,65 @@ const platform = {
   detect: detectPlatform.detect,
   detectAsync: detectPlatform.detectAsync,
   detectCI: detectPlatform.detectCI,
+  detectCIPipelines: detectPlatform.detectCIPipelines,
   detectDeployment: detectPlatform.detectDeployment,
+  detectDeployments: detectPlatform.detectDeployments,
   detectProjectType: detectPlatform.detectProjectType,
   detectPackageManager: detectPlatform.detectPackageManager,
+  detectWorkspaces: detectPlatform.detectWorkspaces,
+  detectContainerization: detectPlatform.detectContainerization,
+  detectInfrastructure: detectPlatform.detectInfrastructure,
+  detectServerless: detectPlatform.detectServerless,
   detectBranchStrategy: detectPlatform.detectBranchStrategy,
+  detectBranching: detectPlatform.detectBranching,
   detectMainBranch: detectPlatform.detectMainBranch,
   invalidateCache: detectPlatform.invalidateCache,
 
+  /**
+   * Register custom detectors that run alongside the built-ins
+   * @see module:platform/detector-registry
+   */
+  registerDetector: detectPlatform.registerDetector,
+  unregisterDetector: detectPlatform.unregisterDetector,
+  getDetectors: detectPlatform.getDetectors,
+
+  /**
+   * Detect databases, ORMs, and migration directories
+   * @see module:platform/detect-database
+   */
+  detectDatabases: detectDatabase.detectDatabases,
+
+  /**
+   * Detect test frameworks and how tests are run
+   * @see module:platform/detect-tests
+   */
+  detectTestFrameworks: detectTests.detectTestFrameworks,
+
+  /**
+   * Detect linters/formatters and their enabled rules
+   * @see module:platform/detect-linters
+   */
+  detectLinters: detectLinters.detectLinters,
+  isLinterRuleEnabled: detectLinters.isRuleEnabled,
+
+  /**
+   * Detect env files, secrets tooling, and files that must never be committed
+   * @see module:platform/detect-secrets
+   */
+  detectSecrets: detectSecrets.detectSecrets,
+
+  /**
+   * Detect declared language runtime versions
+   * @see module:platform/detect-runtimes
+   */
+  detectRuntimes: detectRuntimes.detectRuntimes,
+
+  /**
+   * Detect installed framework versions from lockfiles
+   * @see module:platform/detect-framework-versions
+   */
+  detectFrameworkVersions: detectFrameworkVersions.detectFrameworkVersions,
+
   /**
    * Verify tool availability
    * @see module:platform/verify-tools
@@ -72,6 +170,109 @@ const patterns = {
    */
   slop: slopPatterns,
 
+  /**
+   * Project-defined slop patterns (slopPatterns in .awesome-slash.json)
+   * @see module:patterns/custom-patterns
+   */
+  customPatterns: {
+    loadCustomPatterns: customPatterns.loadCustomPatterns,
+    parseCustomPatterns: customPatterns.parseCustomPatterns,
+    compileCustomPattern: customPatterns.compileCustomPattern
+  },
+
+  /**
+   * Baseline of known slop findings (only new ones are reported)
+   * @see module:patterns/baseline
+   */
+  baseline: {
+    BASELINE_FILE: baseline.BASELINE_FILE,
+    createBaseline: baseline.createBaseline,
+    writeBaseline: baseline.writeBaseline,
+    loadBaseline: baseline.loadBaseline,
+    filterBaseline: baseline.filterBaseline
+  },
+
+  /**
+   * Auto-fixer for remove/replace/add_logging findings
+   * @see module:patterns/fixer
+   */
+  fixer: {
+    generateFixes: fixer.generateFixes,
+    writeFixes: fixer.writeFixes,
+    formatUnifiedDiff: fixer.formatUnifiedDiff
+  },
+
+  /**
+   * Diff-scoped scanning (lines changed since a base ref)
+   * @see module:patterns/diff-scope
+   */
+  diffScope: {
+    getChangedLines: diffScope.getChangedLines,
+    parseUnifiedDiff: diffScope.parseUnifiedDiff,
+    filterToChangedLines: diffScope.filterToChangedLines
+  },
+
+  /**
+   * SARIF 2.1.0 output for slop and review findings
+   * @see module:patterns/sarif
+   */
+  sarif: {
+    slopToSarif: sarif.slopToSarif,
+    reviewToSarif: sarif.reviewToSarif
+  },
+
+  /**
+   * Token-based duplicate code detection (winnowing)
+   * @see module:patterns/duplicates
+   */
+  duplicates: {
+    findDuplicates: duplicates.findDuplicates,
+    tokenize: duplicates.tokenize,
+    DEFAULTS: duplicates.DEFAULTS
+  },
+
+  /**
+   * Secret entropy, redaction, and .env value leak detection
+   * @see module:patterns/secrets
+   */
+  secrets: {
+    shannonEntropy: secrets.shannonEntropy,
+    redactSecret: secrets.redactSecret,
+    findEnvValueLeaks: secrets.findEnvValueLeaks
+  },
+
+  /**
+   * Project review pattern packs (.awesome-slash/patterns/*.js)
+   * @see module:patterns/pattern-packs
+   */
+  patternPacks: {
+    loadReviewPatterns: patternPacks.loadReviewPatterns,
+    validatePack: patternPacks.validatePack,
+    mergePatterns: patternPacks.mergePatterns
+  },
+
+  /**
+   * Migration file detection and dropped-column cross-checks
+   * @see module:patterns/migrations
+   */
+  migrations: {
+    isMigrationFile: migrations.isMigrationFile,
+    extractDroppedColumns: migrations.extractDroppedColumns,
+    findDroppedColumnReferences: migrations.findDroppedColumnReferences
+  },
+
+  /**
+   * REST route, GraphQL resolver and public API compatibility checks
+   * @see module:patterns/api-design
+   */
+  apiDesign: {
+    extractRoutes: apiDesign.extractRoutes,
+    checkRoutes: apiDesign.checkRoutes,
+    checkResolvers: apiDesign.checkResolvers,
+    checkApiDesign: apiDesign.checkApiDesign,
+    findBreakingChanges: apiDesign.findBreakingChanges
+  },
+
   /**
    * Slop detection pipeline orchestrator
    * @see module:patterns/pipeline
@@ -139,13 +340,21 @@ const state = {
 };
 
 /**
- * Git command optimization and string escaping utilities
+ * Git command optimization, string escaping, ignore-rule, git layout, scan limit, and CODEOWNERS utilities
  * @see module:utils/context-optimizer
  * @see module:utils/shell-escape
+ * @see module:utils/ignore
+ * @see module:utils/git
+ * @see module:utils/scan-limits
+ * @see module:utils/codeowners
  */
 const utils = {
   contextOptimizer,
-  shellEscape
+  shellEscape,
+  ignore,
+  git,
+  scanLimits,
+  codeowners
 };
 
 /**
@@ -228,6 +437,32 @@ module.exports = {
   xplat,
   enhance,
   repoMap,
+  changelog,
+  release,
+  prDescription,
+  commit,
+  resolve,
+  deps,
+  migrate,
+  onboard,
+  todos,
+  flaky,
+  envCheck,
+  licenseCheck,
+  sbom,
+  history,
+  report,
+  benchmark,
+  docsGen,
+  issues,
+  notify,
+  gitHooks,
+  plugins,
+  journal,
+  telemetry,
+  cache,
+  taskRunner,
+  messages,
 
   // Direct module access for backward compatibility
   detectPlatform,
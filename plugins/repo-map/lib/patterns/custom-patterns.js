@@ -0,0 +1,264 @@
+/**
+ * Custom Slop Patterns
+ *
+ * Loads project-defined patterns from `slopPatterns` in the project config
+ * (`.awesome-slash.json`) so teams can flag their own banned APIs and habits
+ * alongside the built-in library. Each entry is validated against
+ * lib/schemas/slop-pattern.schema.json and compiled to the same shape as the
+ * built-in patterns in slop-patterns.js:
+ *
+ *   "slopPatterns": {
+ *     "no_moment": {
+ *       "pattern": "from ['\"]moment['\"]",
+ *       "ast": { "matcher": "import", "sources": ["moment"] },
+ *       "severity": "high",
+ *       "language": "javascript",
+ *       "exclude": ["scripts/*"],
+ *       "description": "moment is deprecated here; use date-fns"
+ *     }
+ *   }
+ *
+ * Invalid entries are skipped and reported; they never stop a scan.
+ *
+ * The same config also tunes the built-in library: `ignore` globs skip files,
+ * `patterns.<name>` turns a pattern off or changes its severity and excludes,
+ * and `severity` sets the default `--fail-on`/`--warn-on` gate
+ * (`loadScanSettings`).
+ *
+ * @module patterns/custom-patterns
+ * @author Avi Fenesh
+ * @license MIT
+ */
+
+const { loadConfig, validateConfig } = require('../config');
+const { SchemaValidator } = require('../schemas/validator');
+const { slopPatterns } = require('./slop-patterns');
+
+/**
+ * Config key holding custom patterns
+ */
+const CONFIG_KEY = 'slopPatterns';
+
+/**
+ * Config keys read by loadScanSettings
+ */
+const SETTINGS_KEYS = ['ignore', 'patterns', 'severity'];
+
+/**
+ * Allowed pattern names (snake_case, like the built-in library)
+ */
+const NAME_PATTERN = /^[a-z][a-z0-9_]{0,63}$/;
+
+/**
+ * Required option fields per AST matcher
+ */
+const AST_REQUIRED_FIELDS = {
+  call: ['names'],
+  member_call: ['object', 'properties'],
+  import: ['sources'],
+  empty_catch: [],
+  empty_function: [],
+  placeholder_throw: ['message']
+};
+
+/**
+ * Compile a regex from config, reporting syntax errors
+ * @param {string} source - Regex source
+ * @param {string} flags - Regex flags
+ * @param {string} label - Field path for error messages
+ * @param {string[]} errors - Collected errors
+ * @returns {RegExp|null}
+ */
+function compileRegex(source, flags, label, errors) {
+  try {
+    return new RegExp(source, flags);
+  } catch (error) {
+    errors.push(`${label}: invalid regular expression (${error.message})`);
+    return null;
+  }
+}
+
+/**
+ * Validate and compile one custom pattern
+ * @param {string} name - Pattern name
+ * @param {Object} definition - Pattern definition from config
+ * @returns {{pattern: Object|null, errors: string[]}} Compiled pattern, or null with errors
+ */
+function compileCustomPattern(name, definition) {
+  const label = `${CONFIG_KEY}.${name}`;
+  const errors = [];
+
+  if (!NAME_PATTERN.test(name)) {
+    errors.push(`${label}: name must be snake_case (a-z, 0-9, _)`);
+  } else if (Object.prototype.hasOwnProperty.call(slopPatterns, name)) {
+    errors.push(`${label}: name collides with a built-in pattern`);
+  }
+
+  const validation = definition && typeof definition === 'object' && !Array.isArray(definition)
+    ? SchemaValidator.validateSlopPattern(definition)
+    : { valid: false, errors: ['expected an object'] };
+  errors.push(...validation.errors.map(error => `${label}: ${error}`));
+  if (errors.length > 0) return { pattern: null, errors };
+
+  if (!definition.pattern && !definition.ast) {
+    errors.push(`${label}: requires pattern or ast`);
+  }
+  if (definition.autoFix === 'replace' && typeof definition.replacement !== 'string') {
+    errors.push(`${label}: autoFix replace requires replacement`);
+  }
+
+  const regex = definition.pattern
+    ? compileRegex(definition.pattern, definition.flags || '', `${label}.pattern`, errors)
+    : null;
+
+  let ast;
+  if (definition.ast) {
+    const missing = AST_REQUIRED_FIELDS[definition.ast.matcher].filter(field => !(field in definition.ast));
+    if (missing.length > 0) {
+      errors.push(`${label}.ast: ${definition.ast.matcher} requires ${missing.join(', ')}`);
+    } else if (definition.language && definition.language !== 'javascript') {
+      errors.push(`${label}.ast: AST matchers only support javascript`);
+    }
+    ast = { ...definition.ast };
+    if (ast.message) ast.message = compileRegex(ast.message, 'i', `${label}.ast.message`, errors);
+  }
+
+  if (errors.length > 0) return { pattern: null, errors };
+
+  const pattern = {
+    pattern: regex,
+    exclude: definition.exclude || [],
+    severity: definition.severity || 'medium',
+    autoFix: definition.autoFix || 'flag',
+    language: definition.language || null,
+    description: definition.description || `Custom pattern ${name}`,
+    custom: true
+  };
+  if (ast) pattern.ast = ast;
+  if (definition.replacement !== undefined) pattern.replacement = definition.replacement;
+  return { pattern, errors };
+}
+
+/**
+ * Compile custom patterns from a parsed config
+ * @param {Object} config - Parsed project config
+ * @returns {{patterns: Object<string, Object>, errors: string[]}}
+ */
+function parseCustomPatterns(config) {
+  const definitions = config && config[CONFIG_KEY];
+  if (definitions === undefined) return { patterns: {}, errors: [] };
+  if (!definitions || typeof definitions !== 'object' || Array.isArray(definitions)) {
+    return { patterns: {}, errors: [`${CONFIG_KEY}: expected an object of named patterns`] };
+  }
+
+  const patterns = {};
+  const errors = [];
+  for (const [name, definition] of Object.entries(definitions)) {
+    const result = compileCustomPattern(name, definition);
+    errors.push(...result.errors);
+    if (result.pattern) patterns[name] = result.pattern;
+  }
+  return { patterns, errors };
+}
+
+/**
+ * Load custom patterns from the project config
+ * @param {string} repoPath - Repository root
+ * @returns {{patterns: Object<string, Object>, errors: string[], file: string|null}}
+ *   Errors are prefixed with the config filename
+ */
+function loadCustomPatterns(repoPath) {
+  const { config, file, error } = loadConfig(repoPath);
+  if (error) return { patterns: {}, errors: [error], file };
+
+  const result = parseCustomPatterns(config);
+  return {
+    patterns: result.patterns,
+    errors: result.errors.map(message => `${file}: ${message}`),
+    file
+  };
+}
+
+/**
+ * Custom patterns that apply under a pipeline language filter
+ * @param {Object<string, Object>} patterns - Compiled custom patterns
+ * @param {string|null} language - Language filter
+ * @returns {Object<string, Object>}
+ */
+function filterByLanguage(patterns, language) {
+  if (!language) return patterns;
+  const target = language === 'typescript' ? 'javascript' : language;
+  return Object.fromEntries(Object.entries(patterns)
+    .filter(([, pattern]) => !pattern.language || pattern.language === target));
+}
+
+/**
+ * Read scanner settings from a parsed config
+ * The whole section is dropped when any of it is invalid, so a typo never
+ * half-applies.
+ * @param {Object} config - Parsed project config
+ * @returns {{ignore: string[], overrides: Object<string, {enabled?: boolean, severity?: string, exclude?: string[]}>, severity: {failOn?: string, warnOn?: string}, errors: string[]}}
+ */
+function parseScanSettings(config) {
+  const settings = { ignore: [], overrides: {}, severity: {}, errors: [] };
+  const section = Object.fromEntries(SETTINGS_KEYS.filter(key => config && config[key] !== undefined).map(key => [key, config[key]]));
+  const { errors } = validateConfig(section);
+  for (const name of Object.keys(section.patterns || {})) {
+    if (!Object.prototype.hasOwnProperty.call(slopPatterns, name)) {
+      errors.push(`patterns.${name}: no built-in pattern named ${name}`);
+    }
+  }
+  if (errors.length > 0) return { ...settings, errors };
+
+  return {
+    ignore: section.ignore || [],
+    overrides: section.patterns || {},
+    severity: section.severity || {},
+    errors
+  };
+}
+
+/**
+ * Load scanner settings from the project config
+ * @param {string} repoPath - Repository root
+ * @returns {{ignore: string[], overrides: Object, severity: Object, errors: string[], file: string|null}}
+ *   Errors are prefixed with the config filename
+ */
+function loadScanSettings(repoPath) {
+  const { config, file, error } = loadConfig(repoPath);
+  // loadCustomPatterns reports unreadable files
+  if (error) return { ...parseScanSettings({}), file };
+
+  const settings = parseScanSettings(config);
+  return { ...settings, errors: settings.errors.map(message => `${file}: ${message}`), file };
+}
+
+/**
+ * Apply `ignore` and `patterns` overrides to findings
+ * @param {Object[]} findings - Pipeline findings
+ * @param {Object} settings - Result of loadScanSettings
+ * @param {Function} isExcluded - `(file, globs) => boolean`
+ * @returns {{findings: Object[], suppressed: number}}
+ */
+function applyScanSettings(findings, settings, isExcluded) {
+  const kept = [];
+  for (const finding of findings) {
+    const override = settings.overrides[finding.patternName] || {};
+    const file = finding.file || '';
+    if (override.enabled === false || isExcluded(file, settings.ignore) || isExcluded(file, override.exclude)) continue;
+    kept.push(override.severity ? { ...finding, severity: override.severity } : finding);
+  }
+  return { findings: kept, suppressed: findings.length - kept.length };
+}
+
+module.exports = {
+  CONFIG_KEY,
+  SETTINGS_KEYS,
+  compileCustomPattern,
+  parseCustomPatterns,
+  loadCustomPatterns,
+  filterByLanguage,
+  parseScanSettings,
+  loadScanSettings,
+  applyScanSettings
+};
@@ -0,0 +1,111 @@
+/**
+ * Runtime Feature Patterns
+ * Language and standard-library features with the runtime version that
+ * introduced them, used to flag code that needs a newer runtime than the
+ * project declares (see lib/platform/detect-runtimes.js)
+ *
+ * Patterns match single lines and are deliberately conservative: a feature
+ * is only listed when its syntax or API name is unlikely to appear otherwise.
+ *
+ * @module patterns/runtime-features
+ * @author Avi Fenesh
+ * @license MIT
+ */
+
+const { compareVersions } = require('../platform/detect-runtimes');
+
+/**
+ * Deep freeze an object for V8 optimization and immutability
+ * @param {Object} obj - Object to freeze
+ * @returns {Object} Frozen object
+ */
+function deepFreeze(obj) {
+  Object.keys(obj).forEach(key => {
+    if (typeof obj[key] === 'object' && obj[key] !== null && !(obj[key] instanceof RegExp)) {
+      deepFreeze(obj[key]);
+    }
+  });
+  return Object.freeze(obj);
+}
+
+/**
+ * Source language (as returned by detectLanguage) checked for each runtime
+ */
+const RUNTIME_LANGUAGES = deepFreeze({
+  node: 'js',
+  python: 'python',
+  go: 'go',
+  rust: 'rust'
+});
+
+/**
+ * Display names for runtimes in finding descriptions
+ */
+const RUNTIME_LABELS = deepFreeze({
+  node: 'Node',
+  python: 'Python',
+  go: 'Go',
+  rust: 'Rust'
+});
+
+const runtimeFeatures = deepFreeze({
+  node: [
+    { name: 'structuredClone', since: '17.0', pattern: /\bstructuredClone\s*\(/, description: 'structuredClone()' },
+    { name: 'array_at', since: '16.6', pattern: /\.at\(\s*-?\d+\s*\)/, description: 'Array/String .at()' },
+    { name: 'object_hasown', since: '16.9', pattern: /\bObject\.hasOwn\s*\(/, description: 'Object.hasOwn()' },
+    { name: 'find_last', since: '18.0', pattern: /\.findLast(Index)?\s*\(/, description: 'Array .findLast()/.findLastIndex()' },
+    { name: 'node_test', since: '18.0', pattern: /(require\s*\(\s*|from\s+)['"]node:test['"]/, description: 'node:test runner' },
+    { name: 'change_array_by_copy', since: '20.0', pattern: /\.(toSorted|toReversed|toSpliced)\s*\(/, description: 'Array .toSorted()/.toReversed()/.toSpliced()' },
+    { name: 'import_meta_dirname', since: '20.11', pattern: /\bimport\.meta\.(dirname|filename)\b/, description: 'import.meta.dirname/filename' },
+    { name: 'group_by', since: '21.0', pattern: /\b(Object|Map)\.groupBy\s*\(/, description: 'Object.groupBy()/Map.groupBy()' },
+    { name: 'array_from_async', since: '22.0', pattern: /\bArray\.fromAsync\s*\(/, description: 'Array.fromAsync()' },
+    { name: 'promise_with_resolvers', since: '22.0', pattern: /\bPromise\.withResolvers\s*\(/, description: 'Promise.withResolvers()' }
+  ],
+  python: [
+    { name: 'walrus', since: '3.8', pattern: /\w\s*:=\s*\S/, description: 'assignment expression (:=)' },
+    { name: 'fstring_debug', since: '3.8', pattern: /\bf["'][^"']*\{[^{}!:]+=\s*\}/, description: 'f-string {expr=} specifier' },
+    { name: 'remove_prefix', since: '3.9', pattern: /\.remove(prefix|suffix)\s*\(/, description: 'str.removeprefix()/removesuffix()' },
+    { name: 'zoneinfo', since: '3.9', pattern: /^\s*(from|import)\s+zoneinfo\b/, description: 'zoneinfo module' },
+    { name: 'match_statement', since: '3.10', pattern: /^\s*match\s+(?![=(,.])[^=]*:\s*$/, description: 'match statement' },
+    { name: 'except_group', since: '3.11', pattern: /^\s*except\s*\*/, description: 'except* (exception groups)' },
+    { name: 'tomllib', since: '3.11', pattern: /^\s*(from|import)\s+tomllib\b/, description: 'tomllib module' },
+    { name: 'typing_self', since: '3.11', pattern: /^\s*from\s+typing\s+import\s+.*\bSelf\b/, description: 'typing.Self' },
+    { name: 'task_group', since: '3.11', pattern: /\basyncio\.TaskGroup\b/, description: 'asyncio.TaskGroup' },
+    { name: 'type_statement', since: '3.12', pattern: /^\s*type\s+\w+(\[[^\]]*\])?\s*=/, description: 'type alias statement' },
+    { name: 'generic_syntax', since: '3.12', pattern: /^\s*(class|def)\s+\w+\[[^\]]+\]\s*\(/, description: 'PEP 695 type parameter syntax' },
+    { name: 'itertools_batched', since: '3.12', pattern: /\bitertools\.batched\b|^\s*from\s+itertools\s+import\s+.*\bbatched\b/, description: 'itertools.batched()' },
+    { name: 'typing_override', since: '3.12', pattern: /@typing\.override\b|^\s*from\s+typing\s+import\s+.*\boverride\b/, description: 'typing.override' }
+  ],
+  go: [
+    { name: 'generics', since: '1.18', pattern: /^\s*(func(\s+\([^)]*\))?\s+\w+|type\s+\w+)\[\w+(,\s*\w+)*\s+[\w.~|[\]*]+/, description: 'type parameters (generics)' },
+    { name: 'std_generic_packages', since: '1.21', pattern: /^\s*(import\s+)?(\w+\s+)?"(slices|maps|cmp|log\/slog)"/, description: 'slices/maps/cmp/log/slog packages' },
+    { name: 'range_over_int', since: '1.22', pattern: /\brange\s+(\d+|len\()/, description: 'range over an integer' },
+    { name: 'iter_package', since: '1.23', pattern: /^\s*(import\s+)?(\w+\s+)?"iter"/, description: 'iter package' }
+  ],
+  rust: [
+    { name: 'let_else', since: '1.65', pattern: /^\s*let\s+[\w:]+\s*[({][^=]*=\s*[^;{]*\belse\s*\{/, description: 'let-else' },
+    { name: 'once_lock', since: '1.70', pattern: /\bOnceLock\b/, description: 'std::sync::OnceLock' },
+    { name: 'is_some_and', since: '1.70', pattern: /\.is_(some|ok)_and\s*\(/, description: 'Option/Result is_some_and()/is_ok_and()' },
+    { name: 'inspect_err', since: '1.76', pattern: /\.inspect_err\s*\(/, description: 'Result::inspect_err()' },
+    { name: 'c_string_literal', since: '1.77', pattern: /(^|[^\w"])c"/, description: 'C string literals (c"...")' },
+    { name: 'diagnostic_attribute', since: '1.78', pattern: /#\[diagnostic::/, description: '#[diagnostic] attributes' },
+    { name: 'lazy_lock', since: '1.80', pattern: /\bLazyLock\b/, description: 'std::sync::LazyLock' }
+  ]
+});
+
+/**
+ * Features a runtime gained after the given version
+ * @param {string} runtime - Runtime name (node, python, go, rust)
+ * @param {string} minimum - Oldest version the project supports
+ * @returns {Array<Object>} Feature definitions
+ */
+function getFeaturesNewerThan(runtime, minimum) {
+  return (runtimeFeatures[runtime] || []).filter(feature => compareVersions(feature.since, minimum) > 0);
+}
+
+module.exports = {
+  runtimeFeatures,
+  RUNTIME_LANGUAGES,
+  RUNTIME_LABELS,
+  getFeaturesNewerThan
+};
@@ -0,0 +1,178 @@
+#!/usr/bin/env node
+/**
+ * Secrets and Env Tooling Detection
+ * Finds env files and secrets tooling (direnv, Doppler, Vault, SOPS, AWS
+ * Secrets Manager) so workflows know where configuration lives and which
+ * files must never be committed
+ *
+ * Usage: node lib/platform/detect-secrets.js [path]
+ * Output: JSON with env files, secrets tools, and never-commit files (or null)
+ *
+ * @module lib/platform/detect-secrets
+ */
+
+const path = require('path');
+
+const { SECRETS_CONFIGS } = require('./detection-configs');
+const { DEFAULT_EXCLUDE_DIRS } = require('../utils/ignore');
+const exec = require('../utils/exec');
+const { collectDependencies, dependencyMatches, readText, listDir } = require('./detect-database');
+
+/**
+ * Timeout for git queries (5 seconds)
+ */
+const GIT_TIMEOUT_MS = 5000;
+
+/**
+ * List files within `SECRETS_CONFIGS.scanDepth` directories of the root
+ * Dotfiles are included; dot directories and excluded directories are skipped.
+ * @param {string} basePath - Project root
+ * @returns {Promise<string[]>} Sorted relative posix paths
+ */
+async function listFiles(basePath) {
+  const files = [];
+  const queue = [{ dir: '.', depth: 0 }];
+  while (queue.length > 0) {
+    const { dir, depth } = queue.shift();
+    for (const entry of await listDir(basePath, dir)) {
+      const rel = dir === '.' ? entry.name : `${dir}/${entry.name}`;
+      if (entry.isFile()) {
+        files.push(rel);
+      } else if (entry.isDirectory() && depth < SECRETS_CONFIGS.scanDepth &&
+          !entry.name.startsWith('.') && !DEFAULT_EXCLUDE_DIRS.includes(entry.name)) {
+        queue.push({ dir: rel, depth: depth + 1 });
+      }
+    }
+  }
+  return files.sort();
+}
+
+/**
+ * Run a git command that prints one path per line (or NUL-separated)
+ * @param {string} basePath - Project root
+ * @param {string[]} args - git arguments
+ * @param {number[]} okCodes - Exit codes that still mean success
+ * @returns {Promise<Set<string>|null>} null when git fails
+ */
+async function gitPaths(basePath, args, okCodes = []) {
+  const stdout = await exec.runAsync(basePath, ['git', ...args], { timeout: GIT_TIMEOUT_MS, okCodes });
+  return stdout === null ? null : new Set(stdout.split(/\0|\r?\n/).filter(Boolean));
+}
+
+/**
+ * Which files git tracks and which it ignores
+ * @param {string} basePath - Project root
+ * @param {string[]} files - Relative paths
+ * @returns {Promise<{tracked: Set<string>, ignored: Set<string>}|null>} null outside a git repo
+ */
+async function gitFileStatus(basePath, files) {
+  if (files.length === 0) return { tracked: new Set(), ignored: new Set() };
+  // `git check-ignore` exits 1 when nothing is ignored
+  const [tracked, ignored] = await Promise.all([
+    gitPaths(basePath, ['ls-files', '-z', '--', ...files]),
+    gitPaths(basePath, ['check-ignore', '--', ...files], [1])
+  ]);
+  return tracked && ignored ? { tracked, ignored } : null;
+}
+
+/**
+ * Files that may contain tool invocations: scripts, Makefiles, CI pipelines
+ * @param {string} basePath - Project root
+ * @returns {Promise<string[]>}
+ */
+async function listCommandFiles(basePath) {
+  const files = SECRETS_CONFIGS.commandFiles.slice();
+  for (const dir of SECRETS_CONFIGS.commandDirs) {
+    for (const entry of await listDir(basePath, dir)) {
+      if (entry.isFile() && /\.ya?ml$/i.test(entry.name)) files.push(`${dir}/${entry.name}`);
+    }
+  }
+  return files;
+}
+
+/**
+ * Detect secrets tools from config files, dependencies, and commands
+ * @param {string} basePath - Project root
+ * @param {string[]} files - Result of `listFiles`
+ * @returns {Promise<Array<{name: string, sources: string[]}>>}
+ */
+async function detectSecretsTools(basePath, files) {
+  const fileSet = new Set(files);
+  const dependencies = await collectDependencies(basePath);
+  const commandFiles = await listCommandFiles(basePath);
+  const contents = await Promise.all(commandFiles.map(file => readText(basePath, file)));
+
+  // SOPS-encrypted files carry a `sops` metadata block
+  const sopsCandidates = files.filter(file => SECRETS_CONFIGS.sopsFile.test(path.posix.basename(file)));
+  const sopsContents = await Promise.all(sopsCandidates.map(file => readText(basePath, file)));
+  const encrypted = sopsCandidates.filter((file, i) => sopsContents[i] && SECRETS_CONFIGS.sopsMetadata.test(sopsContents[i]));
+
+  const tools = [];
+  for (const tool of SECRETS_CONFIGS.tools) {
+    const sources = (tool.files || []).filter(file => fileSet.has(file));
+    for (const [ecosystem, expected] of Object.entries(tool.dependencies || {})) {
+      dependencies
+        .filter(dep => dep.ecosystem === ecosystem && expected.some(name => dependencyMatches(ecosystem, dep.name, name)))
+        .forEach(dep => sources.push(`${dep.file}:${dep.name}`));
+    }
+    if (tool.commands) {
+      commandFiles.forEach((file, i) => {
+        if (contents[i] && tool.commands.test(contents[i])) sources.push(file);
+      });
+    }
+    if (tool.name === 'sops') sources.push(...encrypted);
+
+    if (sources.length > 0) tools.push({ name: tool.name, sources: Array.from(new Set(sources)) });
+  }
+  return tools;
+}
+
+/**
+ * Detect env files, secrets tooling, and files that must never be committed
+ * @param {string} [basePath=process.cwd()] - Project root
+ * @returns {Promise<Object|null>} `{envFiles, tools, neverCommit, exposed}` or null when nothing is found
+ */
+async function detectSecrets(basePath = process.cwd()) {
+  const files = await listFiles(basePath);
+  const envFiles = files
+    .filter(file => SECRETS_CONFIGS.envFile.test(path.posix.basename(file)))
+    .map(file => ({ file, template: SECRETS_CONFIGS.envTemplate.test(file) }));
+  const tools = await detectSecretsTools(basePath, files);
+
+  if (envFiles.length === 0 && tools.length === 0) {
+    return null;
+  }
+
+  const neverCommit = [
+    ...envFiles.filter(env => !env.template).map(env => env.file),
+    ...files.filter(file => SECRETS_CONFIGS.neverCommitFiles.includes(path.posix.basename(file)))
+  ].sort();
+
+  const status = await gitFileStatus(basePath, [...envFiles.map(env => env.file), ...neverCommit]);
+  for (const env of envFiles) {
+    env.tracked = status ? status.tracked.has(env.file) : null;
+    env.ignored = status ? status.ignored.has(env.file) : null;
+  }
+
+  return {
+    envFiles,
+    tools,
+    neverCommit,
+    // Secret files git would commit: already tracked, or not covered by .gitignore
+    exposed: status ? neverCommit.filter(file => status.tracked.has(file) || !status.ignored.has(file)) : null
+  };
+}
+
+// When run directly, output JSON
+if (require.main === module) {
+  detectSecrets(process.argv[2] || process.cwd()).then(result => {
+    const indent = process.stdout.isTTY ? 2 : 0;
+    console.log(JSON.stringify(result, null, indent));
+  });
+}
+
+module.exports = {
+  detectSecrets,
+  detectSecretsTools,
+  listFiles
+};
@@ -0,0 +1,199 @@
+/**
+ * Custom Detector Registry
+ * Lets projects contribute platform detectors that run alongside the
+ * built-ins; results appear under `custom` in the `detect()` result.
+ *
+ * Project-local detectors live in `<state-dir>/detectors/*.js`. Each module
+ * exports a detector, an array of detectors, or a function that receives
+ * `{ registerDetector }`:
+ *
+ *   module.exports = {
+ *     name: 'acme-paas',
+ *     markers: ['acme.yaml'],
+ *     async detect({ exists, readFile }) {
+ *       return (await exists('acme.yaml')) ? { manifest: 'acme.yaml' } : null;
+ *     }
+ *   };
+ *
+ * @module lib/platform/detector-registry
+ */
+
+const fs = require('fs');
+const path = require('path');
+const { getStateDirPath } = require('./state-dir');
+const { relativePath } = require('../utils/exec');
+
+const fsPromises = fs.promises;
+
+/**
+ * Directory (inside the state dir) holding project-local detectors
+ */
+const PROJECT_DETECTORS_DIR = 'detectors';
+
+/**
+ * Detector names: lowercase, digits, and dashes
+ */
+const DETECTOR_NAME_PATTERN = /^[a-z0-9][a-z0-9-]*$/;
+
+/**
+ * Registered detectors by name, in registration order
+ * @type {Map<string, Object>}
+ */
+const _detectors = new Map();
+
+/**
+ * Project detector load results, by resolved detectors dir
+ * @type {Map<string, {files: string[], errors: Array<Object>}>}
+ */
+const _loadedProjects = new Map();
+
+/**
+ * Register a custom detector
+ * @param {Object} detector - Detector definition
+ * @param {string} detector.name - Unique name; key in `detect().custom`
+ * @param {Function} detector.detect - `async (context) => result`; null/undefined means not detected
+ * @param {string[]} [detector.markers] - Files whose changes invalidate the persisted cache
+ * @param {string} [detector.source] - Plugin file (set for project-local detectors)
+ * @returns {Object} The normalized detector
+ * @throws {Error} When the definition is invalid or the name is taken
+ */
+function registerDetector(detector) {
+  if (!detector || typeof detector !== 'object') {
+    throw new Error('Detector must be an object with name and detect()');
+  }
+  const { name, detect, markers = [], source = null } = detector;
+  if (typeof name !== 'string' || !DETECTOR_NAME_PATTERN.test(name)) {
+    throw new Error(`Invalid detector name: ${name}`);
+  }
+  if (typeof detect !== 'function') {
+    throw new Error(`Detector "${name}" must provide a detect() function`);
+  }
+  if (!Array.isArray(markers) || markers.some(marker => typeof marker !== 'string')) {
+    throw new Error(`Detector "${name}" markers must be an array of paths`);
+  }
+  if (_detectors.has(name)) {
+    throw new Error(`Detector "${name}" is already registered`);
+  }
+
+  const normalized = { name, detect, markers: markers.slice(), source };
+  _detectors.set(name, normalized);
+  return normalized;
+}
+
+/**
+ * Remove a registered detector
+ * @param {string} name - Detector name
+ * @returns {boolean} True if it was registered
+ */
+function unregisterDetector(name) {
+  return _detectors.delete(name);
+}
+
+/**
+ * Registered detectors in registration order
+ * @returns {Object[]}
+ */
+function getDetectors() {
+  return Array.from(_detectors.values());
+}
+
+/**
+ * Register every detector a plugin module exports
+ * @param {*} exported - module.exports of the plugin
+ * @param {string} source - Plugin file path (relative)
+ * @returns {void}
+ */
+function registerExports(exported, source) {
+  if (typeof exported === 'function') {
+    exported({ registerDetector: detector => registerDetector({ ...detector, source }) });
+    return;
+  }
+  for (const detector of Array.isArray(exported) ? exported : [exported]) {
+    registerDetector({ ...detector, source });
+  }
+}
+
+/**
+ * Load project-local detectors from `<state-dir>/detectors/*.js`
+ * Each file loads once per process; broken plugins are reported, not thrown.
+ * @param {string} [basePath=process.cwd()] - Project root
+ * @returns {Promise<{files: string[], errors: Array<{file: string, error: string}>}>}
+ */
+async function loadProjectDetectors(basePath = process.cwd()) {
+  const dir = path.join(getStateDirPath(basePath), PROJECT_DETECTORS_DIR);
+  const key = path.resolve(dir);
+  if (_loadedProjects.has(key)) {
+    return _loadedProjects.get(key);
+  }
+
+  let entries = [];
+  try {
+    entries = (await fsPromises.readdir(dir, { withFileTypes: true })) || [];
+  } catch {
+    entries = [];
+  }
+
+  const files = [];
+  const errors = [];
+  const names = entries
+    .filter(entry => entry.isFile() && /\.c?js$/.test(entry.name))
+    .map(entry => entry.name)
+    .sort();
+  for (const name of names) {
+    const file = relativePath(basePath, path.join(dir, name));
+    files.push(file);
+    try {
+      registerExports(require(path.join(key, name)), file);
+    } catch (error) {
+      errors.push({ file, error: error.message });
+    }
+  }
+
+  _loadedProjects.set(key, { files, errors });
+  return { files, errors };
+}
+
+/**
+ * Run every registered detector
+ * A detector that throws, times out, or returns null/undefined is left out.
+ * @param {Object} context - Passed to each detector's detect()
+ * @param {Function} wrap - Wraps each detector promise (e.g. with a timeout)
+ * @returns {Promise<{results: Object<string, *>, errors: Array<{name: string, error: string}>}>}
+ */
+async function runDetectors(context, wrap = promise => promise) {
+  const detectors = getDetectors();
+  const settled = await Promise.all(detectors.map(detector =>
+    wrap(Promise.resolve().then(() => detector.detect(context)), detector.name)
+      .then(value => ({ value }), error => ({ error: error || new Error('rejected') }))
+  ));
+
+  const results = {};
+  const errors = [];
+  detectors.forEach(({ name }, i) => {
+    const { value, error } = settled[i];
+    if (error) {
+      errors.push({ name, error: error.message || String(error) });
+    } else if (value !== null && value !== undefined) {
+      results[name] = value;
+    }
+  });
+  return { results, errors };
+}
+
+/**
+ * Remove all registered detectors and forget loaded project plugins (for tests)
+ */
+function clearDetectors() {
+  _detectors.clear();
+  _loadedProjects.clear();
+}
+
+module.exports = {
+  PROJECT_DETECTORS_DIR,
+  registerDetector,
+  unregisterDetector,
+  getDetectors,
+  loadProjectDetectors,
+  runDetectors,
+  clearDetectors
+};
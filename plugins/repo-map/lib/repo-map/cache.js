@@ -9,9 +9,13 @@
 const fs = require('fs');
 const path = require('path');
 const { getStateDirPath } = require('../platform/state-dir');
+const { createCache } = require('../cache');
 
 const MAP_FILENAME = 'repo-map.json';
 const STALE_FILENAME = 'repo-map.stale';
+const FILE_CACHE_NAMESPACE = 'repo-map';
+const FILE_CACHE_KEY = 'files';
+const FILE_CACHE_VERSION = 1;
 
 /**
  * Get repo-map path
@@ -31,6 +35,15 @@ function getStalePath(basePath) {
   return path.join(getStateDirPath(basePath), STALE_FILENAME);
 }
 
+/**
+ * Get per-file symbol cache path
+ * @param {string} basePath - Repository root
+ * @returns {string}
+ */
+function getFileCachePath(basePath) {
+  return createCache(basePath, FILE_CACHE_NAMESPACE).file(FILE_CACHE_KEY);
+}
+
 /**
  * Ensure state directory exists
  * @param {string} basePath - Repository root
@@ -81,6 +94,48 @@ function save(basePath, map) {
   clearStale(basePath);
 }
 
+/**
+ * Load the per-file symbol cache
+ * @param {string} basePath - Repository root
+ * @returns {{extractors: Object, files: Object}|null}
+ */
+function loadFileCache(basePath) {
+  const parsed = createCache(basePath, FILE_CACHE_NAMESPACE).get(FILE_CACHE_KEY);
+  if (!parsed || parsed.version !== FILE_CACHE_VERSION || !parsed.files) return null;
+  return { extractors: parsed.extractors || {}, files: parsed.files };
+}
+
+/**
+ * Save per-file hashes and extracted symbols from a map
+ * @param {string} basePath - Repository root
+ * @param {Object} map - Map object
+ * @param {Object} extractors - Extractor fingerprint per language
+ */
+function saveFileCache(basePath, map, extractors) {
+  const files = {};
+  for (const [file, fileData] of Object.entries(map.files || {})) {
+    if (!fileData.hash || !extractors[fileData.language]) continue;
+    files[file] = {
+      hash: fileData.hash,
+      language: fileData.language,
+      symbols: fileData.symbols,
+      imports: fileData.imports,
+      calls: fileData.calls,
+      env: fileData.env
+    };
+  }
+
+  createCache(basePath, FILE_CACHE_NAMESPACE).set(FILE_CACHE_KEY, { version: FILE_CACHE_VERSION, extractors, files });
+}
+
+/**
+ * Remove the per-file symbol cache
+ * @param {string} basePath - Repository root
+ */
+function clearFileCache(basePath) {
+  createCache(basePath, FILE_CACHE_NAMESPACE).delete(FILE_CACHE_KEY);
+}
+
 /**
  * Check if repo-map exists
  * @param {string} basePath - Repository root
@@ -145,6 +200,10 @@ module.exports = {
   exists,
   getStatus,
   getMapPath,
+  getFileCachePath,
+  loadFileCache,
+  saveFileCache,
+  clearFileCache,
   markStale,
   clearStale,
   isMarkedStale
@@ -0,0 +1,184 @@
+/**
+ * Unreferenced files and exports
+ *
+ * A file is unreferenced when no other file imports it (resolved through the
+ * dependency graph) or calls into it (call graph), and it is not an entry
+ * point. An export is unused when its name appears in no other file at all;
+ * the call graph only records `name(` calls, so type, constant and re-export
+ * references are found with an identifier index over file contents instead.
+ * Results carry a safe-delete suggestion: delete the file or symbol, or only
+ * drop the export when the file still uses the symbol itself.
+ *
+ * @module lib/repo-map/dead
+ */
+
+'use strict';
+
+const { buildDependencyGraph } = require('./graph');
+
+// Languages whose files are only reachable through explicit imports; in Go,
+// Java, Rust, C# and friends, files in one package or crate see each other
+const FILE_IMPORT_LANGUAGES = new Set(['javascript', 'typescript', 'python']);
+
+const ENTRY_POINT_PATTERNS = [
+  /(?:^|\/)(?:index|main|cli|app|server|lib|mod|build)\.[^/]+$/,
+  /(?:^|\/)(?:__init__|__main__|setup|conftest|manage|wsgi|asgi)\.py$/,
+  /(?:^|\/)(?:bin|scripts|examples?|hooks|migrations|fixtures)\//,
+  /(?:^|\/)(?:__tests__|__mocks__|tests?|spec|benches|testdata)\//,
+  /[._-](?:test|spec)\.[^/]+$/,
+  /(?:^|\/)test_[^/]+\.py$/,
+  /_test\.go$/,
+  /\.config\.[^/]+$/,
+  /\.d\.ts$/
+];
+
+const IDENTIFIER = /[A-Za-z_$][\w$]*/g;
+
+// Export lists name a symbol without using it
+const EXPORT_LISTS = /\bmodule\.exports\s*=\s*\{[^}]*\}|\bexport\s*\{[^}]*\}|\bexports\.[\w$]+|\b__all__\s*=\s*[[(][^\])]*[\])]/g;
+
+/**
+ * Entry points declared in package.json (`main`, `bin`, `exports`)
+ * @param {Function} readFile - (file) => content
+ * @returns {Set<string>}
+ */
+function readPackageEntryPoints(readFile) {
+  const entries = new Set();
+  let pkg;
+  try {
+    pkg = JSON.parse(readFile('package.json'));
+  } catch {
+    return entries;
+  }
+
+  const add = value => {
+    if (typeof value === 'string') {
+      entries.add(value.replace(/^\.\//, ''));
+    } else if (value && typeof value === 'object') {
+      Object.values(value).forEach(add);
+    }
+  };
+  add(pkg.main);
+  add(pkg.module);
+  add(pkg.bin);
+  add(pkg.exports);
+  return entries;
+}
+
+/**
+ * Check whether a file is an entry point (never reported as unreferenced)
+ * @param {string} file - Repository-relative path
+ * @param {Set<string>} [declared] - Entry points declared by the project
+ * @returns {boolean}
+ */
+function isEntryPoint(file, declared) {
+  if (declared && declared.has(file)) return true;
+  return ENTRY_POINT_PATTERNS.some(pattern => pattern.test(file));
+}
+
+/**
+ * Index identifiers: the files mentioning each name, and per-file use counts
+ * (export lists excluded, so a definition plus its export counts once)
+ * @param {string[]} files - Map files
+ * @param {Function} readFile - (file) => content
+ * @returns {{filesByName: Map<string, Set<string>>, counts: Map<string, Map<string, number>>}}
+ */
+function indexIdentifiers(files, readFile) {
+  const filesByName = new Map();
+  const counts = new Map();
+  for (const file of files) {
+    let content;
+    try {
+      content = readFile(file);
+    } catch {
+      continue;
+    }
+    if (typeof content !== 'string') continue;
+
+    for (const [name] of content.matchAll(IDENTIFIER)) {
+      if (!filesByName.has(name)) filesByName.set(name, new Set());
+      filesByName.get(name).add(file);
+    }
+    const fileCounts = new Map();
+    for (const [name] of content.replace(EXPORT_LISTS, ' ').matchAll(IDENTIFIER)) {
+      fileCounts.set(name, (fileCounts.get(name) || 0) + 1);
+    }
+    counts.set(file, fileCounts);
+  }
+  return { filesByName, counts };
+}
+
+/**
+ * Find files and exports nothing else references
+ * @param {Object} map - Repo map
+ * @param {Object} [options]
+ * @param {Function} [options.readFile] - (file) => content; without it only the graphs are used and exports are not checked
+ * @param {string[]} [options.entryPoints] - Extra entry point files
+ * @returns {{files: Array<{file: string, language: string, suggestion: string}>, exports: Array<{file: string, name: string, kind: string, line: number, usedLocally: boolean, suggestion: string}>}}
+ */
+function findUnreferenced(map, options = {}) {
+  const files = Object.keys((map && map.files) || {}).sort();
+  const readFile = options.readFile;
+  const declared = readFile ? readPackageEntryPoints(readFile) : new Set();
+  for (const file of options.entryPoints || []) declared.add(file.replace(/^\.\//, ''));
+
+  // Incoming references per file: importers plus callers from other files
+  const referencedBy = new Map(files.map(file => [file, new Set()]));
+  const graph = buildDependencyGraph(map);
+  for (const [from, targets] of Object.entries(graph)) {
+    for (const to of targets) referencedBy.get(to).add(from);
+  }
+  for (const [target, symbols] of Object.entries(map.callGraph || {})) {
+    if (!referencedBy.has(target)) continue;
+    for (const callers of Object.values(symbols)) {
+      for (const caller of callers) {
+        if (caller.file !== target) referencedBy.get(target).add(caller.file);
+      }
+    }
+  }
+
+  const deadFiles = [];
+  const unreferenced = new Set();
+  for (const file of files) {
+    const language = map.files[file].language;
+    if (!FILE_IMPORT_LANGUAGES.has(language) || isEntryPoint(file, declared)) continue;
+    if (referencedBy.get(file).size > 0) continue;
+    unreferenced.add(file);
+    deadFiles.push({ file, language, suggestion: `Delete ${file}: no file imports it or calls into it` });
+  }
+
+  const deadExports = [];
+  if (readFile) {
+    const { filesByName, counts } = indexIdentifiers(files, readFile);
+    for (const file of files) {
+      // Whole-file findings cover their exports; entry point exports are public API
+      if (unreferenced.has(file) || isEntryPoint(file, declared) || !counts.has(file)) continue;
+
+      for (const entry of map.files[file].symbols?.exports || []) {
+        const users = filesByName.get(entry.name);
+        if (users && Array.from(users).some(user => user !== file)) continue;
+
+        const usedLocally = (counts.get(file).get(entry.name) || 0) > 1;
+        deadExports.push({
+          file,
+          name: entry.name,
+          kind: entry.kind,
+          line: entry.line,
+          usedLocally,
+          suggestion: usedLocally
+            ? `Stop exporting ${entry.name}: only ${file} uses it`
+            : `Delete ${entry.name}: nothing references it`
+        });
+      }
+    }
+  }
+
+  return { files: deadFiles, exports: deadExports };
+}
+
+module.exports = {
+  FILE_IMPORT_LANGUAGES,
+  readPackageEntryPoints,
+  isEntryPoint,
+  findUnreferenced
+};
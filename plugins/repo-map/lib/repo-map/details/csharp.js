@@ -0,0 +1,210 @@
+/**
+ * C# symbol details (namespaces, attributes, records, async methods)
+ *
+ * @module lib/repo-map/details/csharp
+ */
+
+'use strict';
+
+const { splitArgs, maskComments, findClosing, compact, logicalLines, braceDelta } = require('./utils');
+
+const MODIFIERS = 'public|private|protected|internal|static|abstract|sealed|partial|readonly|ref|unsafe|new|file|virtual|override|async|extern';
+const TYPE_DECLARATION = new RegExp(`^((?:(?:${MODIFIERS})\\s+)*)(class|interface|struct|enum|record(?:\\s+(?:class|struct))?)\\s+([A-Za-z_]\\w*)`);
+const METHOD_DECLARATION = new RegExp(`^((?:(?:${MODIFIERS})\\s+)*)([\\w.]+(?:<[^()]*>)?(?:\\[\\])*\\??)\\s+([A-Za-z_]\\w*)\\s*(?:<[^>]*>)?\\s*\\(`);
+const NAMESPACE = /^namespace\s+([\w.]+)\s*(;)?/;
+const NOT_RETURN_TYPES = new Set(['return', 'new', 'throw', 'else', 'case', 'await', 'using', 'namespace', 'yield', 'goto']);
+const VISIBILITY = ['public', 'private', 'protected', 'internal'];
+
+/**
+ * Strip leading attribute lists (`[HttpGet("{id}")]`) from a line
+ * Assembly/module-level attributes are dropped.
+ * @param {string} text - Logical line
+ * @returns {{attributes: string[], rest: string}}
+ */
+function takeAttributes(text) {
+  const attributes = [];
+  let rest = text;
+  while (rest.startsWith('[')) {
+    const close = findClosing(rest, 0);
+    if (close === -1) break;
+    const body = rest.slice(1, close).trim();
+    if (!/^(?:assembly|module):/.test(body)) {
+      for (const item of splitArgs(body.replace(/^(?:return|method|field|property|type|param):\s*/, ''))) {
+        attributes.push(compact(item).replace(/\(\s+/, '(').replace(/\s+\)$/, ')'));
+      }
+    }
+    rest = rest.slice(close + 1).trim();
+  }
+  return { attributes, rest };
+}
+
+/**
+ * Resolve visibility from modifiers (C# defaults: internal types, private members)
+ * @param {string[]} modifiers - Declaration modifiers
+ * @param {Object|null} parent - Enclosing type
+ * @returns {string}
+ */
+function resolveVisibility(modifiers, parent) {
+  const explicit = VISIBILITY.filter(mod => modifiers.includes(mod));
+  if (explicit.length > 1) return explicit.join(' ');
+  if (explicit.length === 1) return explicit[0];
+  if (parent && parent.kind === 'interface') return 'public';
+  return parent ? 'private' : 'internal';
+}
+
+/**
+ * Parse base types after a type name (`: Base, IFoo`)
+ * @param {string} rest - Text following the type name
+ * @returns {string[]}
+ */
+function parseBases(rest) {
+  let text = rest.trim();
+  if (text.startsWith('<')) {
+    const close = findClosing(text, 0);
+    if (close !== -1) text = text.slice(close + 1).trim();
+  }
+  if (text.startsWith('(')) {
+    const close = findClosing(text, 0);
+    if (close !== -1) text = text.slice(close + 1).trim();
+  }
+  const header = text.split(/[{;]/)[0].replace(/\bwhere\b[\s\S]*$/, '');
+  const colon = header.match(/^\s*:\s*([\s\S]+)$/);
+  if (!colon) return [];
+  return splitArgs(colon[1], { angles: true }).map(item => compact(item.replace(/\(.*\)$/, '')));
+}
+
+/**
+ * Parse C# declarations from content
+ * @param {string} content - File content
+ * @returns {{namespaces: string[], types: Object[], methods: Object[]}}
+ */
+function parseCSharpDeclarations(content) {
+  const result = { namespaces: [], types: [], methods: [] };
+  const lines = logicalLines(maskComments(content));
+  const stack = [];
+  let depth = 0;
+  let fileNamespace = null;
+  let pendingAttributes = [];
+  let pending = null;
+
+  for (let index = 0; index < lines.length; index++) {
+    const { text, line } = lines[index];
+    if (!text) continue;
+
+    const { attributes, rest } = takeAttributes(text);
+    pendingAttributes.push(...attributes);
+    if (!rest) continue;
+
+    const top = stack.length > 0 ? stack[stack.length - 1] : null;
+    const inBody = top ? top.bodyDepth === depth : depth === 0;
+    const parent = top && inBody && top.type === 'type' ? top.decl : null;
+    const namespace = [fileNamespace, ...stack.filter(item => item.type === 'namespace').map(item => item.name)]
+      .filter(Boolean)
+      .join('.') || null;
+    let opener = null;
+
+    const ns = inBody && !parent && rest.match(NAMESPACE);
+    const typeMatch = !ns && inBody && rest.match(TYPE_DECLARATION);
+    if (ns) {
+      result.namespaces.push(ns[1]);
+      if (ns[2]) fileNamespace = ns[1];
+      else opener = { type: 'namespace', name: ns[1] };
+    } else if (typeMatch) {
+      const modifiers = typeMatch[1].trim().split(/\s+/).filter(Boolean);
+      const keyword = typeMatch[2].replace(/\s+/g, ' ');
+      const decl = {
+        name: typeMatch[3],
+        line,
+        kind: keyword === 'record struct' ? 'record-struct' : keyword.split(' ')[0],
+        namespace,
+        modifiers,
+        visibility: resolveVisibility(modifiers, parent),
+        attributes: pendingAttributes,
+        bases: parseBases(rest.slice(typeMatch[0].length)),
+        parent: parent ? parent.name : null,
+        methods: []
+      };
+      result.types.push(decl);
+      opener = { type: 'type', decl };
+    } else if (parent) {
+      const match = rest.match(METHOD_DECLARATION);
+      if (match && !NOT_RETURN_TYPES.has(match[2]) && match[3] !== parent.name) {
+        const modifiers = match[1].trim().split(/\s+/).filter(Boolean);
+        const method = {
+          name: match[3],
+          line,
+          returnType: match[2],
+          modifiers,
+          visibility: resolveVisibility(modifiers, parent),
+          attributes: pendingAttributes,
+          parent: parent.name
+        };
+        if (modifiers.includes('async')) method.async = true;
+        result.methods.push(method);
+        parent.methods.push(method);
+      }
+    }
+    pendingAttributes = [];
+
+    const { delta, opens } = braceDelta(rest);
+    const scope = opener || pending;
+    if (scope && opens) {
+      stack.push({ ...scope, bodyDepth: depth + 1 });
+      pending = null;
+    } else if (opener) {
+      const next = lines.slice(index + 1).find(item => item.text);
+      pending = next && next.text.startsWith('{') ? opener : null;
+    } else {
+      pending = null;
+    }
+
+    depth = Math.max(0, depth + delta);
+    while (stack.length > 0 && stack[stack.length - 1].bodyDepth > depth) stack.pop();
+  }
+
+  return result;
+}
+
+/**
+ * Attach namespaces, attributes, bases, and async flags to C# symbols
+ * @param {string} content - File content
+ * @param {Object} symbolMaps - Symbol maps
+ */
+function applyCSharpDetails(content, symbolMaps) {
+  const parsed = parseCSharpDeclarations(content);
+  const find = (items, entry) => items.find(item => item.name === entry.name && item.line === entry.line)
+    || items.find(item => item.name === entry.name);
+
+  if (symbolMaps.classes) {
+    for (const entry of symbolMaps.classes.values()) {
+      const decl = find(parsed.types, entry);
+      if (!decl) continue;
+      if (decl.kind !== 'class') entry.kind = decl.kind;
+      entry.visibility = decl.visibility;
+      if (decl.namespace) entry.namespace = decl.namespace;
+      if (decl.parent) entry.parent = decl.parent;
+      if (decl.modifiers.includes('partial')) entry.partial = true;
+      if (decl.attributes.length > 0) entry.attributes = decl.attributes;
+      if (decl.bases.length > 0) entry.bases = decl.bases;
+      const methods = decl.methods.filter(method => method.visibility !== 'private');
+      if (methods.length > 0) entry.methods = methods.map(method => ({ name: method.name, line: method.line }));
+    }
+  }
+
+  if (symbolMaps.functions) {
+    for (const entry of symbolMaps.functions.values()) {
+      const decl = find(parsed.methods, entry);
+      if (!decl) continue;
+      entry.visibility = decl.visibility;
+      entry.parent = decl.parent;
+      entry.returnType = decl.returnType;
+      if (decl.async) entry.async = true;
+      if (decl.attributes.length > 0) entry.attributes = decl.attributes;
+    }
+  }
+}
+
+module.exports = {
+  applyCSharpDetails,
+  parseCSharpDeclarations
+};
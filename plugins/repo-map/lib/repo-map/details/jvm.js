@@ -0,0 +1,271 @@
+/**
+ * Java/Kotlin symbol details (packages, annotations, supertypes, methods)
+ *
+ * @module lib/repo-map/details/jvm
+ */
+
+'use strict';
+
+const { splitArgs, maskComments, findClosing, compact, logicalLines, braceDelta } = require('./utils');
+
+const TYPE_DECLARATION = /^((?:[a-z][\w-]*\s+)*)(class|interface|enum|record|@interface|object)\s+([A-Za-z_$][\w$]*)/;
+const JAVA_METHOD = /^((?:(?:public|protected|private|static|final|abstract|synchronized|native|default|strictfp)\s+)*)(?:<[^>]*>\s+)?([\w$.]+(?:<[^()]*>)?(?:\[\])*)\s+([A-Za-z_$][\w$]*)\s*\(/;
+const KOTLIN_FUNCTION = /^((?:[a-z]+\s+)*)fun\s+(?:<[^>]*>\s*)?(?:([\w.]+(?:<[^()]*>)?\??)\.)?([A-Za-z_][\w]*)\s*\(/;
+const NOT_RETURN_TYPES = new Set(['return', 'new', 'throw', 'else', 'case', 'yield', 'await', 'package', 'import']);
+const VISIBILITY = ['public', 'protected', 'private', 'internal'];
+
+/**
+ * Strip leading annotations from a line
+ * @param {string} text - Logical line
+ * @returns {{annotations: string[], rest: string}}
+ */
+function takeAnnotations(text) {
+  const annotations = [];
+  let rest = text;
+  while (rest.startsWith('@') && !rest.startsWith('@interface')) {
+    const match = rest.match(/^@(?:[a-z]+:)?([\w.]+)/);
+    if (!match) break;
+    let end = match[0].length;
+    if (rest[end] === '(') {
+      const close = findClosing(rest, end);
+      if (close === -1) break;
+      end = close + 1;
+    }
+    annotations.push(compact(rest.slice(1, end)).replace(/^[a-z]+:/, '').replace(/\(\s+/, '(').replace(/\s+\)$/, ')'));
+    rest = rest.slice(end).trim();
+  }
+  return { annotations, rest };
+}
+
+/**
+ * Parse supertypes following a type name
+ * @param {string} rest - Text after the type name
+ * @param {string} language - 'java' or 'kotlin'
+ * @returns {{extends: string[], implements: string[]}}
+ */
+function parseSupertypes(rest, language) {
+  let text = rest.trim();
+  if (text.startsWith('<')) {
+    const close = findClosing(text, 0);
+    if (close !== -1) text = text.slice(close + 1).trim();
+  }
+  if (text.startsWith('(')) {
+    const close = findClosing(text, 0);
+    if (close !== -1) text = text.slice(close + 1).trim();
+  }
+  const header = text.split('{')[0];
+
+  if (language === 'kotlin') {
+    const colon = header.match(/^[^:]*:\s*([\s\S]+)$/);
+    if (!colon) return { extends: [], implements: [] };
+    const supertypes = splitArgs(colon[1].replace(/\bwhere\b[\s\S]*$/, ''), { angles: true })
+      .map(item => compact(item.replace(/\(.*\)$/, '').replace(/\s+by\s+[\s\S]*$/, '')));
+    return { extends: supertypes, implements: [] };
+  }
+
+  const extendsMatch = header.match(/\bextends\s+([\s\S]+?)(?=\bimplements\b|\bpermits\b|$)/);
+  const implementsMatch = header.match(/\bimplements\s+([\s\S]+?)(?=\bpermits\b|$)/);
+  return {
+    extends: extendsMatch ? splitArgs(extendsMatch[1], { angles: true }).map(item => compact(item)) : [],
+    implements: implementsMatch ? splitArgs(implementsMatch[1], { angles: true }).map(item => compact(item)) : []
+  };
+}
+
+/**
+ * Resolve a declaration's visibility from its modifiers
+ * @param {string[]} modifiers - Declaration modifiers
+ * @param {string} language - 'java' or 'kotlin'
+ * @param {Object|null} parent - Enclosing type declaration
+ * @returns {string}
+ */
+function resolveVisibility(modifiers, language, parent) {
+  const explicit = modifiers.find(mod => VISIBILITY.includes(mod));
+  if (explicit) return explicit;
+  if (language === 'kotlin') return 'public';
+  if (parent && (parent.kind === 'interface' || parent.kind === 'annotation')) return 'public';
+  return 'package';
+}
+
+/**
+ * Map a declaration keyword and modifiers to a symbol kind
+ * @param {string} keyword - class/interface/enum/record/@interface/object
+ * @param {string[]} modifiers - Declaration modifiers
+ * @returns {string}
+ */
+function resolveTypeKind(keyword, modifiers) {
+  if (keyword === '@interface' || modifiers.includes('annotation')) return 'annotation';
+  if (keyword === 'enum' || modifiers.includes('enum')) return 'enum';
+  if (keyword === 'class' && modifiers.includes('data')) return 'dataclass';
+  return keyword;
+}
+
+/**
+ * Parse Java or Kotlin declarations from content
+ * @param {string} content - File content
+ * @param {string} [language='java'] - 'java' or 'kotlin'
+ * @returns {{package: string|null, types: Object[], methods: Object[]}}
+ */
+function parseJvmDeclarations(content, language = 'java') {
+  const result = { package: null, types: [], methods: [] };
+  const lines = logicalLines(maskComments(content));
+  const stack = [];
+  let depth = 0;
+  let pendingAnnotations = [];
+  let pendingType = null;
+
+  for (let index = 0; index < lines.length; index++) {
+    const { text, line } = lines[index];
+    if (!text) continue;
+
+    const { annotations, rest } = takeAnnotations(text);
+    pendingAnnotations.push(...annotations);
+
+    const parent = stack.length > 0 && stack[stack.length - 1].bodyDepth === depth
+      ? stack[stack.length - 1].decl
+      : null;
+    const atDeclarationLevel = stack.length === 0 ? depth === 0 : parent !== null;
+    let declared = null;
+
+    if (rest && !result.package && depth === 0) {
+      const pkg = rest.match(/^package\s+([\w.]+)/);
+      if (pkg) result.package = pkg[1];
+    }
+
+    const typeMatch = rest && atDeclarationLevel && rest.match(TYPE_DECLARATION);
+    if (typeMatch) {
+      const modifiers = typeMatch[1].trim().split(/\s+/).filter(Boolean);
+      const supertypes = parseSupertypes(rest.slice(typeMatch[0].length), language);
+      declared = {
+        name: typeMatch[3],
+        line,
+        kind: resolveTypeKind(typeMatch[2], modifiers),
+        modifiers,
+        visibility: resolveVisibility(modifiers, language, parent),
+        annotations: pendingAnnotations,
+        extends: supertypes.extends,
+        implements: supertypes.implements,
+        parent: parent ? parent.name : null,
+        methods: []
+      };
+      result.types.push(declared);
+    } else if (rest && atDeclarationLevel) {
+      const method = parseMethod(rest, language, parent);
+      if (method) {
+        method.line = line;
+        method.annotations = pendingAnnotations;
+        result.methods.push(method);
+        if (parent) parent.methods.push(method);
+      }
+    }
+
+    if (rest) pendingAnnotations = [];
+
+    const { delta, opens } = braceDelta(rest);
+    const opener = declared || pendingType;
+    if (opener && opens) {
+      stack.push({ decl: opener, bodyDepth: depth + 1 });
+      pendingType = null;
+    } else if (declared) {
+      const next = lines.slice(index + 1).find(item => item.text);
+      pendingType = next && next.text.startsWith('{') ? declared : null;
+    } else if (rest) {
+      pendingType = null;
+    }
+
+    depth = Math.max(0, depth + delta);
+    while (stack.length > 0 && stack[stack.length - 1].bodyDepth > depth) stack.pop();
+  }
+
+  return result;
+}
+
+/**
+ * Parse a method/function declaration line
+ * @param {string} text - Logical line without annotations
+ * @param {string} language - 'java' or 'kotlin'
+ * @param {Object|null} parent - Enclosing type declaration
+ * @returns {Object|null}
+ */
+function parseMethod(text, language, parent) {
+  if (language === 'kotlin') {
+    const match = text.match(KOTLIN_FUNCTION);
+    if (!match) return null;
+    const modifiers = match[1].trim().split(/\s+/).filter(Boolean);
+    const method = {
+      name: match[3],
+      modifiers,
+      visibility: resolveVisibility(modifiers, language, parent),
+      parent: parent ? parent.name : null
+    };
+    if (match[2]) method.receiver = match[2];
+    if (modifiers.includes('suspend')) method.suspend = true;
+    return method;
+  }
+
+  if (!parent) return null;
+  const match = text.match(JAVA_METHOD);
+  if (!match || NOT_RETURN_TYPES.has(match[2])) return null;
+  const modifiers = match[1].trim().split(/\s+/).filter(Boolean);
+  return {
+    name: match[3],
+    modifiers,
+    returnType: match[2],
+    visibility: resolveVisibility(modifiers, language, parent),
+    parent: parent.name
+  };
+}
+
+/**
+ * Find a declaration by name, preferring a matching line
+ * @param {Object[]} items - Parsed declarations
+ * @param {Object} entry - Symbol map entry
+ * @returns {Object|undefined}
+ */
+function findDeclaration(items, entry) {
+  return items.find(item => item.name === entry.name && item.line === entry.line)
+    || items.find(item => item.name === entry.name);
+}
+
+/**
+ * Attach package, annotations, supertypes, and methods to JVM symbols
+ * @param {string} content - File content
+ * @param {Object} symbolMaps - Symbol maps
+ * @param {string} language - 'java' or 'kotlin'
+ */
+function applyJvmDetails(content, symbolMaps, language) {
+  const parsed = parseJvmDeclarations(content, language);
+
+  if (symbolMaps.classes) {
+    for (const entry of symbolMaps.classes.values()) {
+      const decl = findDeclaration(parsed.types, entry);
+      if (!decl) continue;
+      if (decl.kind !== 'class') entry.kind = decl.kind;
+      entry.visibility = decl.visibility;
+      if (parsed.package) entry.package = parsed.package;
+      if (decl.parent) entry.parent = decl.parent;
+      if (decl.annotations.length > 0) entry.annotations = decl.annotations;
+      if (decl.extends.length > 0) entry.extends = decl.extends;
+      if (decl.implements.length > 0) entry.implements = decl.implements;
+      const methods = decl.methods.filter(method => method.visibility !== 'private');
+      if (methods.length > 0) entry.methods = methods.map(method => ({ name: method.name, line: method.line }));
+    }
+  }
+
+  if (symbolMaps.functions) {
+    for (const entry of symbolMaps.functions.values()) {
+      const decl = findDeclaration(parsed.methods, entry);
+      if (!decl) continue;
+      entry.visibility = decl.visibility;
+      if (decl.parent) entry.parent = decl.parent;
+      if (decl.annotations.length > 0) entry.annotations = decl.annotations;
+      if (decl.receiver) entry.receiver = decl.receiver;
+      if (decl.suspend) entry.suspend = true;
+    }
+  }
+}
+
+module.exports = {
+  applyJavaDetails: (content, symbolMaps) => applyJvmDetails(content, symbolMaps, 'java'),
+  applyKotlinDetails: (content, symbolMaps) => applyJvmDetails(content, symbolMaps, 'kotlin'),
+  parseJvmDeclarations
+};
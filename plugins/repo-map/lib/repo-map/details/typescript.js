@@ -0,0 +1,456 @@
+/**
+ * TypeScript/JavaScript symbol details (signatures, generics, type shapes, component props)
+ *
+ * @module lib/repo-map/details/typescript
+ */
+
+'use strict';
+
+const {
+  splitArgs,
+  maskComments,
+  findClosing,
+  createLineLookup,
+  compact,
+  skipWhitespace
+} = require('./utils');
+
+const MAX_MEMBERS = 50;
+
+const DECLARATION_PATTERNS = {
+  interface: /^[ \t]*(?:export\s+(?:default\s+)?)?(?:declare\s+)?interface\s+([A-Za-z_$][\w$]*)/gm,
+  type: /^[ \t]*(?:export\s+)?(?:declare\s+)?type\s+([A-Za-z_$][\w$]*)\s*(?=[<=])/gm,
+  enum: /^[ \t]*(?:export\s+)?(?:declare\s+)?(?:const\s+)?enum\s+([A-Za-z_$][\w$]*)/gm,
+  class: /^[ \t]*(?:export\s+(?:default\s+)?)?(?:declare\s+)?(?:abstract\s+)?class\s+([A-Za-z_$][\w$]*)/gm,
+  function: /^[ \t]*(?:export\s+(?:default\s+)?)?(?:declare\s+)?(async\s+)?function\s*\*?\s*([A-Za-z_$][\w$]*)/gm,
+  variable: /^[ \t]*(?:export\s+)?(?:declare\s+)?(?:const|let|var)\s+([A-Za-z_$][\w$]*)/gm
+};
+
+/**
+ * Read optional generic parameters at an index
+ * @param {string} text - Source text
+ * @param {number} index - Index to start at
+ * @returns {{typeParams: string|null, end: number}}
+ */
+function readTypeParams(text, index) {
+  const i = skipWhitespace(text, index);
+  if (text[i] !== '<') return { typeParams: null, end: index };
+  const close = findClosing(text, i);
+  if (close === -1) return { typeParams: null, end: index };
+  return { typeParams: compact(text.slice(i, close + 1)), end: close + 1 };
+}
+
+/**
+ * Read text up to (not including) the first stop character at bracket depth 0
+ * @param {string} text - Source text
+ * @param {number} index - Start index
+ * @param {string[]} stops - Stop tokens
+ * @returns {{value: string, end: number}}
+ */
+function readUntil(text, index, stops) {
+  let i = index;
+  while (i < text.length) {
+    const ch = text[i];
+    if (stops.some(stop => text.startsWith(stop, i))) break;
+    if (text.startsWith('=>', i)) {
+      i += 2;
+      continue;
+    }
+    if (ch === '(' || ch === '[' || ch === '{' || ch === '<') {
+      const close = findClosing(text, i);
+      if (close === -1) {
+        if (ch === '<') {
+          i++;
+          continue;
+        }
+        break;
+      }
+      i = close + 1;
+      continue;
+    }
+    if (ch === '"' || ch === "'" || ch === '`') {
+      let j = i + 1;
+      while (j < text.length && text[j] !== ch) {
+        if (text[j] === '\\') j++;
+        j++;
+      }
+      i = j + 1;
+      continue;
+    }
+    i++;
+  }
+  return { value: text.slice(index, i), end: i };
+}
+
+/**
+ * Parse a parameter list into structured parameters
+ * @param {string} paramsText - Text between the parentheses
+ * @returns {Object[]}
+ */
+function parseParams(paramsText) {
+  return splitArgs(paramsText, { angles: true }).map(raw => {
+    const param = { raw: compact(raw) };
+    const head = readUntil(raw, 0, [':', '=']);
+    const name = head.value.trim().replace(/^(?:public|private|protected|readonly)\s+/, '');
+    param.name = compact(name.replace(/\?$/, ''));
+    if (name.endsWith('?')) param.optional = true;
+
+    let rest = head.end;
+    if (raw[rest] === ':') {
+      const typed = readUntil(raw, rest + 1, ['=']);
+      param.type = compact(typed.value);
+      rest = typed.end;
+    }
+    if (raw[rest] === '=') param.default = compact(raw.slice(rest + 1), 60);
+    return param;
+  });
+}
+
+/**
+ * Parse members of an object type body (interface or type literal)
+ * @param {string} body - Text between the braces
+ * @returns {Object[]}
+ */
+function parseMembers(body) {
+  const members = [];
+  const entries = [];
+  let i = 0;
+  while (i < body.length) {
+    const { value, end } = readUntil(body, i, [';', ',', '\n']);
+    if (value.trim()) entries.push(value.trim());
+    i = end + 1;
+  }
+
+  for (const entry of entries) {
+    if (members.length >= MAX_MEMBERS) break;
+    const cleaned = entry.replace(/^readonly\s+/, '');
+    const method = cleaned.match(/^([A-Za-z_$][\w$]*)(\?)?\s*(?=[<(])/);
+    const signature = method && readSignature(cleaned, method[0].length);
+    if (signature) {
+      const member = {
+        name: method[1],
+        type: compact(`${signature.typeParams || ''}(${signature.params.map(p => p.raw).join(', ')}) => ${signature.returnType || 'void'}`)
+      };
+      if (method[2]) member.optional = true;
+      members.push(member);
+      continue;
+    }
+
+    const prop = cleaned.match(/^(\[[^\]]+\]|[A-Za-z_$][\w$]*|['"][^'"]+['"])(\?)?\s*:\s*([\s\S]+)$/);
+    if (prop) {
+      const member = { name: prop[1].replace(/^['"]|['"]$/g, ''), type: compact(prop[3]) };
+      if (prop[2]) member.optional = true;
+      if (entry.startsWith('readonly ')) member.readonly = true;
+      members.push(member);
+    }
+  }
+
+  return members;
+}
+
+/**
+ * Read a function signature: optional generics, parameter list, return type
+ * @param {string} text - Source text
+ * @param {number} index - Index after the function name (or at `<`/`(`)
+ * @returns {Object|null}
+ */
+function readSignature(text, index) {
+  const generics = readTypeParams(text, index);
+  const open = skipWhitespace(text, generics.end);
+  if (text[open] !== '(') return null;
+  const close = findClosing(text, open);
+  if (close === -1) return null;
+
+  const params = parseParams(text.slice(open + 1, close));
+  let returnType = null;
+  let end = close + 1;
+  const afterParams = skipWhitespace(text, end);
+  if (text[afterParams] === ':') {
+    const { value, end: typeEnd } = readUntil(text, afterParams + 1, ['{', '=>', ';', '\n']);
+    returnType = compact(value) || null;
+    end = typeEnd;
+  }
+
+  return {
+    typeParams: generics.typeParams,
+    params,
+    returnType,
+    signature: compact(`${generics.typeParams || ''}(${params.map(p => p.raw).join(', ')})${returnType ? `: ${returnType}` : ''}`),
+    end
+  };
+}
+
+/**
+ * Parse TypeScript/JavaScript declarations from content
+ * @param {string} content - File content
+ * @returns {{interfaces: Object[], typeAliases: Object[], enums: Object[], classes: Object[], functions: Object[]}}
+ */
+function parseTypeScriptDeclarations(content) {
+  const text = maskComments(content);
+  const lineAt = createLineLookup(text);
+  const result = { interfaces: [], typeAliases: [], enums: [], classes: [], functions: [] };
+
+  const each = (regex, handler) => {
+    regex.lastIndex = 0;
+    let match;
+    while ((match = regex.exec(text)) !== null) {
+      handler(match, match.index + match[0].length);
+    }
+  };
+
+  each(DECLARATION_PATTERNS.interface, (match, index) => {
+    const generics = readTypeParams(text, index);
+    const { value: heritage, end } = readUntil(text, generics.end, ['{']);
+    if (text[end] !== '{') return;
+    const close = findClosing(text, end);
+    const extendsMatch = heritage.match(/extends\s+([\s\S]+)/);
+    result.interfaces.push({
+      name: match[1],
+      line: lineAt(match.index + match[0].indexOf('interface')),
+      typeParams: generics.typeParams,
+      extends: extendsMatch ? splitArgs(extendsMatch[1], { angles: true }).map(item => compact(item)) : [],
+      members: close === -1 ? [] : parseMembers(text.slice(end + 1, close))
+    });
+  });
+
+  each(DECLARATION_PATTERNS.type, (match, index) => {
+    const generics = readTypeParams(text, index);
+    const eq = skipWhitespace(text, generics.end);
+    if (text[eq] !== '=') return;
+    const { value } = readUntil(text, eq + 1, [';', '\n\n', '\nexport ', '\nconst ', '\nfunction ', '\ntype ', '\ninterface ']);
+    const definition = value.trim();
+    const alias = {
+      name: match[1],
+      line: lineAt(match.index + match[0].indexOf('type')),
+      typeParams: generics.typeParams,
+      definition: compact(definition)
+    };
+    if (definition.startsWith('{')) {
+      const close = findClosing(definition, 0);
+      if (close === definition.length - 1) alias.members = parseMembers(definition.slice(1, close));
+    }
+    result.typeAliases.push(alias);
+  });
+
+  each(DECLARATION_PATTERNS.enum, (match, index) => {
+    const open = text.indexOf('{', index);
+    if (open === -1) return;
+    const close = findClosing(text, open);
+    if (close === -1) return;
+    result.enums.push({
+      name: match[1],
+      line: lineAt(match.index + match[0].indexOf('enum')),
+      members: splitArgs(text.slice(open + 1, close)).map(item => item.split('=')[0].trim()).filter(Boolean)
+    });
+  });
+
+  each(DECLARATION_PATTERNS.class, (match, index) => {
+    const generics = readTypeParams(text, index);
+    const { value: heritage } = readUntil(text, generics.end, ['{']);
+    const extendsMatch = heritage.match(/extends\s+([\s\S]+?)(?=\s+implements\b|$)/);
+    const implementsMatch = heritage.match(/implements\s+([\s\S]+)/);
+    result.classes.push({
+      name: match[1],
+      line: lineAt(match.index + match[0].indexOf('class')),
+      typeParams: generics.typeParams,
+      extends: extendsMatch ? compact(extendsMatch[1]) : null,
+      implements: implementsMatch ? splitArgs(implementsMatch[1], { angles: true }).map(item => compact(item)) : []
+    });
+  });
+
+  each(DECLARATION_PATTERNS.function, (match, index) => {
+    const signature = readSignature(text, index);
+    if (!signature) return;
+    result.functions.push({
+      name: match[2],
+      line: lineAt(match.index + match[0].indexOf('function')),
+      async: Boolean(match[1]),
+      ...withoutEnd(signature)
+    });
+  });
+
+  each(DECLARATION_PATTERNS.variable, (match, index) => {
+    let i = skipWhitespace(text, index);
+    let annotation = null;
+    if (text[i] === ':') {
+      const { value, end } = readUntil(text, i + 1, ['=']);
+      annotation = compact(value);
+      i = end;
+    }
+    if (text[i] !== '=' || text[i + 1] === '=') return;
+    i = skipWhitespace(text, i + 1);
+
+    let isAsync = false;
+    if (text.startsWith('async', i) && /\s|\(|</.test(text[i + 5] || '')) {
+      isAsync = true;
+      i = skipWhitespace(text, i + 5);
+    }
+    if (text.startsWith('function', i)) {
+      i = skipWhitespace(text, i + 'function'.length);
+      if (text[i] === '*') i++;
+      const name = text.slice(i).match(/^[A-Za-z_$][\w$]*/);
+      if (name) i += name[0].length;
+    }
+
+    let signature = null;
+    if (text[i] === '(' || text[i] === '<') {
+      signature = readSignature(text, i);
+      if (signature) {
+        const after = skipWhitespace(text, signature.end);
+        const isArrow = text.startsWith('=>', after);
+        const isFunctionBody = text[after] === '{';
+        if (!isArrow && !isFunctionBody) signature = null;
+      }
+    } else if (/^[A-Za-z_$][\w$]*\s*=>/.test(text.slice(i))) {
+      const param = text.slice(i).match(/^[A-Za-z_$][\w$]*/)[0];
+      signature = { typeParams: null, params: [{ raw: param, name: param }], returnType: null, signature: `(${param})` };
+    }
+
+    if (!signature && !annotation) return;
+    const fn = {
+      name: match[1],
+      line: lineAt(match.index + match[0].search(/const|let|var/)),
+      async: isAsync
+    };
+    if (signature) Object.assign(fn, withoutEnd(signature));
+    if (annotation) fn.annotation = annotation;
+    result.functions.push(fn);
+  });
+
+  return result;
+}
+
+/**
+ * Drop the parser offset from a signature
+ * @param {Object} signature - Result of readSignature
+ * @returns {Object}
+ */
+function withoutEnd(signature) {
+  const { end, ...rest } = signature;
+  return rest;
+}
+
+/**
+ * Find the first entry by name, preferring a matching line
+ * @param {Object[]} items - Parsed declarations
+ * @param {Object} entry - Symbol map entry
+ * @returns {Object|undefined}
+ */
+function findDeclaration(items, entry) {
+  return items.find(item => item.name === entry.name && item.line === entry.line)
+    || items.find(item => item.name === entry.name);
+}
+
+/**
+ * Resolve React component props from a function's parameters or annotation
+ * @param {Object} fn - Parsed function
+ * @param {Object} declarations - All parsed declarations
+ * @returns {{propsType: string|null, props: Object[]|null}|null}
+ */
+function resolveComponentProps(fn, declarations) {
+  const annotationMatch = fn.annotation && fn.annotation.match(/^(?:React\.)?(?:FC|FunctionComponent|VFC|ComponentType)\s*<([\s\S]+)>$/);
+  const first = fn.params && fn.params[0];
+  const propsType = annotationMatch ? annotationMatch[1].trim() : (first && first.type) || null;
+
+  if (!annotationMatch && !isComponentLike(fn)) return null;
+
+  let props = null;
+  if (propsType) {
+    if (propsType.startsWith('{')) {
+      const close = findClosing(propsType, 0);
+      if (close !== -1) props = parseMembers(propsType.slice(1, close));
+    } else {
+      const typeName = propsType.replace(/<[\s\S]*>$/, '').trim();
+      const shape = declarations.interfaces.find(item => item.name === typeName)
+        || declarations.typeAliases.find(item => item.name === typeName && item.members);
+      if (shape) props = shape.members;
+    }
+  } else if (first && first.name.startsWith('{')) {
+    props = splitArgs(first.name.slice(1, -1)).map(name => ({ name: name.split(/[=:]/)[0].trim() }));
+  }
+
+  return { propsType: propsType && !propsType.startsWith('{') ? propsType : null, props };
+}
+
+/**
+ * Heuristic: PascalCase function taking at most one (props) parameter
+ * @param {Object} fn - Parsed function
+ * @returns {boolean}
+ */
+function isComponentLike(fn) {
+  if (!/^[A-Z][A-Za-z0-9]*$/.test(fn.name)) return false;
+  if (!fn.params || fn.params.length > 2) return false;
+  if (fn.returnType && !/JSX|React|ReactNode|ReactElement|Element/.test(fn.returnType)) return false;
+  const first = fn.params[0];
+  if (!first) return Boolean(fn.returnType);
+  return /props/i.test(first.name) || first.name.startsWith('{') || /Props\b/.test(first.type || '');
+}
+
+/**
+ * Attach signatures, type shapes, and component props to TS/JS symbols
+ * @param {string} content - File content
+ * @param {Object} symbolMaps - Symbol maps
+ */
+function applyTypeScriptDetails(content, symbolMaps) {
+  const declarations = parseTypeScriptDeclarations(content);
+
+  if (symbolMaps.functions) {
+    for (const entry of symbolMaps.functions.values()) {
+      const fn = findDeclaration(declarations.functions, entry);
+      if (!fn || !fn.signature) continue;
+      entry.signature = fn.signature;
+      if (fn.typeParams) entry.typeParams = fn.typeParams;
+      if (fn.returnType) entry.returnType = fn.returnType;
+      if (fn.async) entry.async = true;
+
+      const component = resolveComponentProps(fn, declarations);
+      if (component) {
+        entry.kind = 'component';
+        if (component.propsType) entry.propsType = component.propsType;
+        if (component.props) entry.props = component.props;
+      }
+    }
+  }
+
+  if (symbolMaps.types) {
+    for (const entry of symbolMaps.types.values()) {
+      const iface = findDeclaration(declarations.interfaces, entry);
+      const alias = findDeclaration(declarations.typeAliases, entry);
+      const enumDecl = findDeclaration(declarations.enums, entry);
+      const decl = [iface, alias, enumDecl]
+        .filter(Boolean)
+        .sort((a, b) => Math.abs(a.line - entry.line) - Math.abs(b.line - entry.line))[0];
+      if (!decl) continue;
+
+      if (decl === iface) {
+        entry.kind = 'interface';
+        if (iface.typeParams) entry.typeParams = iface.typeParams;
+        if (iface.extends.length > 0) entry.extends = iface.extends;
+        entry.members = iface.members;
+      } else if (decl === alias) {
+        entry.kind = 'type-alias';
+        if (alias.typeParams) entry.typeParams = alias.typeParams;
+        entry.definition = alias.definition;
+        if (alias.members) entry.members = alias.members;
+      } else {
+        entry.kind = 'enum';
+        entry.members = enumDecl.members.map(name => ({ name }));
+      }
+    }
+  }
+
+  if (symbolMaps.classes) {
+    for (const entry of symbolMaps.classes.values()) {
+      const cls = findDeclaration(declarations.classes, entry);
+      if (!cls) continue;
+      if (cls.typeParams) entry.typeParams = cls.typeParams;
+      if (cls.extends) entry.extends = cls.extends;
+      if (cls.implements.length > 0) entry.implements = cls.implements;
+    }
+  }
+}
+
+module.exports = {
+  applyTypeScriptDetails,
+  parseTypeScriptDeclarations
+};
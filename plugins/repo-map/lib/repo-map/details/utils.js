@@ -0,0 +1,294 @@
+/**
+ * Shared helpers for language detail parsers
+ *
+ * @module lib/repo-map/details/utils
+ */
+
+'use strict';
+
+const OPENERS = { '(': ')', '[': ']', '{': '}' };
+const CLOSERS = new Set([')', ']', '}']);
+
+/**
+ * Split a comma-separated list, respecting nested brackets
+ * @param {string} text - List text
+ * @param {Object} [options]
+ * @param {boolean} [options.angles=false] - Treat `<`/`>` as brackets (generic arguments)
+ * @param {string} [options.separator=','] - Separator character
+ * @returns {string[]}
+ */
+function splitArgs(text, options = {}) {
+  const separator = options.separator || ',';
+  const parts = [];
+  let depth = 0;
+  let quote = null;
+  let current = '';
+  for (let i = 0; i < text.length; i++) {
+    const ch = text[i];
+    if (quote) {
+      current += ch;
+      if (ch === '\\') {
+        current += text[++i] || '';
+      } else if (ch === quote) {
+        quote = null;
+      }
+      continue;
+    }
+    if (ch === '"' || ch === "'" || ch === '`') {
+      quote = ch;
+      current += ch;
+      continue;
+    }
+    if (ch === '(' || ch === '[' || ch === '{' || (options.angles && ch === '<')) depth++;
+    if (ch === ')' || ch === ']' || ch === '}' || (options.angles && ch === '>' && text[i - 1] !== '=')) depth--;
+    if (ch === separator && depth === 0) {
+      if (current.trim()) parts.push(current.trim());
+      current = '';
+      continue;
+    }
+    current += ch;
+  }
+  if (current.trim()) parts.push(current.trim());
+  return parts;
+}
+
+/**
+ * Replace C-style comments with spaces, preserving offsets and newlines
+ * Strings are left intact so literal types survive.
+ * @param {string} content - Source content
+ * @param {Object} [options]
+ * @param {boolean} [options.hashComments=false] - Also mask `#` line comments
+ * @returns {string}
+ */
+function maskComments(content, options = {}) {
+  let out = '';
+  let quote = null;
+  for (let i = 0; i < content.length; i++) {
+    const ch = content[i];
+    const next = content[i + 1];
+    if (quote) {
+      out += ch;
+      if (ch === '\\') {
+        out += content[++i] || '';
+      } else if (ch === quote || (ch === '\n' && quote !== '`')) {
+        quote = null;
+      }
+      continue;
+    }
+    if (ch === '"' || ch === "'" || ch === '`') {
+      quote = ch;
+      out += ch;
+      continue;
+    }
+    if ((ch === '/' && next === '/') || (options.hashComments && ch === '#')) {
+      while (i < content.length && content[i] !== '\n') {
+        out += ' ';
+        i++;
+      }
+      if (i < content.length) out += '\n';
+      continue;
+    }
+    if (ch === '/' && next === '*') {
+      out += '  ';
+      i += 2;
+      while (i < content.length && !(content[i] === '*' && content[i + 1] === '/')) {
+        out += content[i] === '\n' ? '\n' : ' ';
+        i++;
+      }
+      out += '  ';
+      i++;
+      continue;
+    }
+    out += ch;
+  }
+  return out;
+}
+
+/**
+ * Find the index of the bracket closing the one at `openIndex`
+ * @param {string} text - Source text
+ * @param {number} openIndex - Index of the opening bracket
+ * @returns {number} - Index of the closing bracket, or -1
+ */
+function findClosing(text, openIndex) {
+  const open = text[openIndex];
+  if (open === '<') return findClosingAngle(text, openIndex);
+  if (!OPENERS[open]) return -1;
+
+  const stack = [];
+  let quote = null;
+  for (let i = openIndex; i < text.length; i++) {
+    const ch = text[i];
+    if (quote) {
+      if (ch === '\\') i++;
+      else if (ch === quote) quote = null;
+      continue;
+    }
+    if (ch === '"' || ch === "'" || ch === '`') {
+      quote = ch;
+      continue;
+    }
+    if (OPENERS[ch]) {
+      stack.push(OPENERS[ch]);
+    } else if (CLOSERS.has(ch)) {
+      if (stack.pop() !== ch) return -1;
+      if (stack.length === 0) return i;
+    }
+  }
+  return -1;
+}
+
+/**
+ * Find the `>` closing a generic parameter list (ignores `=>`)
+ * @param {string} text - Source text
+ * @param {number} openIndex - Index of `<`
+ * @returns {number}
+ */
+function findClosingAngle(text, openIndex) {
+  let depth = 0;
+  for (let i = openIndex; i < text.length; i++) {
+    const ch = text[i];
+    if (OPENERS[ch]) {
+      const close = findClosing(text, i);
+      if (close === -1) return -1;
+      i = close;
+      continue;
+    }
+    if (ch === '<') depth++;
+    if (ch === '>' && text[i - 1] !== '=') {
+      depth--;
+      if (depth === 0) return i;
+    }
+    if (ch === ';' || ch === '{') return -1;
+  }
+  return -1;
+}
+
+/**
+ * Build a function mapping string offsets to 1-based line numbers
+ * @param {string} content - Source content
+ * @returns {Function} - (index) => line
+ */
+function createLineLookup(content) {
+  const starts = [0];
+  for (let i = 0; i < content.length; i++) {
+    if (content[i] === '\n') starts.push(i + 1);
+  }
+  return (index) => {
+    let lo = 0;
+    let hi = starts.length - 1;
+    while (lo < hi) {
+      const mid = (lo + hi + 1) >> 1;
+      if (starts[mid] <= index) lo = mid;
+      else hi = mid - 1;
+    }
+    return lo + 1;
+  };
+}
+
+/**
+ * Collapse whitespace and cap length for compact map output
+ * @param {string} text - Text to compact
+ * @param {number} [maxLength=200] - Maximum length
+ * @returns {string}
+ */
+function compact(text, maxLength = 200) {
+  const collapsed = String(text || '').replace(/\s+/g, ' ').trim();
+  return collapsed.length > maxLength ? collapsed.slice(0, maxLength - 3) + '...' : collapsed;
+}
+
+/**
+ * Skip whitespace starting at an index
+ * @param {string} text - Source text
+ * @param {number} index - Start index
+ * @returns {number}
+ */
+function skipWhitespace(text, index) {
+  let i = index;
+  while (i < text.length && /\s/.test(text[i])) i++;
+  return i;
+}
+
+/**
+ * Join physical lines into logical lines with balanced parentheses
+ * @param {string} text - Comment-masked content
+ * @returns {Array<{text: string, line: number}>}
+ */
+function logicalLines(text) {
+  const lines = text.split('\n');
+  const result = [];
+  for (let i = 0; i < lines.length; i++) {
+    const startLine = i + 1;
+    let current = lines[i];
+    while (parenBalance(current) > 0 && i + 1 < lines.length) {
+      current += ' ' + lines[++i].trim();
+    }
+    result.push({ text: current.trim(), line: startLine });
+  }
+  return result;
+}
+
+/**
+ * Count unclosed parentheses, ignoring string contents
+ * @param {string} text - Line text
+ * @returns {number}
+ */
+function parenBalance(text) {
+  let depth = 0;
+  forEachCode(text, ch => {
+    if (ch === '(') depth++;
+    if (ch === ')') depth--;
+  });
+  return depth;
+}
+
+/**
+ * Count brace delta, ignoring string contents
+ * @param {string} text - Line text
+ * @returns {{delta: number, opens: boolean}}
+ */
+function braceDelta(text) {
+  let delta = 0;
+  let opens = false;
+  forEachCode(text, ch => {
+    if (ch === '{') {
+      delta++;
+      opens = true;
+    }
+    if (ch === '}') delta--;
+  });
+  return { delta, opens };
+}
+
+/**
+ * Invoke a callback for every character outside string/char literals
+ * @param {string} text - Line text
+ * @param {Function} callback - (char) => void
+ */
+function forEachCode(text, callback) {
+  let quote = null;
+  for (let i = 0; i < text.length; i++) {
+    const ch = text[i];
+    if (quote) {
+      if (ch === '\\') i++;
+      else if (ch === quote) quote = null;
+      continue;
+    }
+    if (ch === '"' || ch === '\'') {
+      quote = ch;
+      continue;
+    }
+    callback(ch);
+  }
+}
+
+module.exports = {
+  splitArgs,
+  maskComments,
+  findClosing,
+  createLineLookup,
+  compact,
+  skipWhitespace,
+  logicalLines,
+  braceDelta
+};
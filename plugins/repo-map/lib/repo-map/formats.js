@@ -0,0 +1,153 @@
+/**
+ * Repo map output formats
+ *
+ * - `text`: prompt-ready symbol listing (see `render.renderMap`)
+ * - `json`: files, symbols, resolved file dependencies, and cycles for other tools
+ * - `mermaid`: file dependency flowchart for Markdown/PR descriptions
+ * - `dot`: Graphviz digraph of file dependencies
+ *
+ * @module lib/repo-map/formats
+ */
+
+'use strict';
+
+const { renderMap } = require('./render');
+const { rankFiles } = require('./rank');
+const { buildDependencyGraph, findCycles } = require('./graph');
+
+const FORMATS = ['text', 'json', 'mermaid', 'dot'];
+
+/**
+ * Limit a dependency graph to the highest-ranked files
+ * @param {Object} map - Repo map
+ * @param {Object<string, string[]>} graph - Full dependency graph
+ * @param {Object} options
+ * @param {number} [options.maxFiles] - Files to keep (all if omitted)
+ * @param {string[]} [options.focus] - Files to rank first
+ * @returns {{graph: Object<string, string[]>, omittedFiles: number}}
+ */
+function limitGraph(map, graph, options) {
+  const files = Object.keys(graph);
+  if (!(options.maxFiles > 0) || files.length <= options.maxFiles) {
+    return { graph, omittedFiles: 0 };
+  }
+
+  const ranks = rankFiles(map, { focus: options.focus });
+  const kept = new Set(files
+    .sort((a, b) => (ranks.get(b) || 0) - (ranks.get(a) || 0) || a.localeCompare(b))
+    .slice(0, options.maxFiles));
+  const limited = {};
+  for (const file of Array.from(kept).sort()) {
+    limited[file] = graph[file].filter(target => kept.has(target));
+  }
+  return { graph: limited, omittedFiles: files.length - kept.size };
+}
+
+/**
+ * Count edges in a dependency graph
+ * @param {Object<string, string[]>} graph - Dependency graph
+ * @returns {number}
+ */
+function countEdges(graph) {
+  return Object.values(graph).reduce((sum, targets) => sum + targets.length, 0);
+}
+
+/**
+ * Serialize files, symbols, and dependencies as JSON
+ * @param {Object} map - Repo map
+ * @param {Object<string, string[]>} graph - Dependency graph
+ * @returns {string}
+ */
+function toJson(map, graph) {
+  const files = {};
+  for (const file of Object.keys(graph)) {
+    const fileData = map.files[file];
+    files[file] = {
+      language: fileData.language,
+      symbols: fileData.symbols,
+      imports: (fileData.imports || []).map(imp => imp.source)
+    };
+  }
+  return JSON.stringify({
+    version: map.version,
+    generated: map.generated,
+    updated: map.updated,
+    commit: map.git?.commit || null,
+    project: map.project,
+    files,
+    dependencies: graph,
+    cycles: findCycles(graph)
+  }, null, 2) + '\n';
+}
+
+/**
+ * Render a Mermaid flowchart
+ * @param {Object<string, string[]>} graph - Dependency graph
+ * @returns {string}
+ */
+function toMermaid(graph) {
+  const ids = new Map(Object.keys(graph).map((file, i) => [file, `n${i}`]));
+  const lines = ['graph LR'];
+  for (const [file, id] of ids) {
+    lines.push(`  ${id}["${file.replace(/"/g, '#quot;')}"]`);
+  }
+  for (const [file, targets] of Object.entries(graph)) {
+    for (const target of targets) lines.push(`  ${ids.get(file)} --> ${ids.get(target)}`);
+  }
+  return lines.join('\n') + '\n';
+}
+
+/**
+ * Render a Graphviz DOT digraph
+ * @param {Object<string, string[]>} graph - Dependency graph
+ * @returns {string}
+ */
+function toDot(graph) {
+  const quote = value => `"${value.replace(/\\/g, '\\\\').replace(/"/g, '\\"')}"`;
+  const lines = ['digraph repo_map {', '  rankdir=LR;', '  node [shape=box];'];
+  for (const file of Object.keys(graph)) {
+    lines.push(`  ${quote(file)};`);
+  }
+  for (const [file, targets] of Object.entries(graph)) {
+    for (const target of targets) lines.push(`  ${quote(file)} -> ${quote(target)};`);
+  }
+  lines.push('}');
+  return lines.join('\n') + '\n';
+}
+
+/**
+ * Format a repo map
+ * @param {Object} map - Repo map
+ * @param {string} [format='text'] - One of FORMATS
+ * @param {Object} [options]
+ * @param {number} [options.maxTokens] - Token budget (`text` only)
+ * @param {Function} [options.tokenizer] - Custom tokenizer (`text` only)
+ * @param {number} [options.maxFiles] - Keep only the highest-ranked files (graph formats)
+ * @param {string[]} [options.focus] - Files to rank first
+ * @returns {{format: string, text: string, files: number, omittedFiles: number, edges?: number}}
+ */
+function formatMap(map, format = 'text', options = {}) {
+  const name = String(format || 'text').toLowerCase();
+  if (!FORMATS.includes(name)) {
+    throw new Error(`Unknown format: ${format}. Use ${FORMATS.join(', ')}.`);
+  }
+
+  if (name === 'text') {
+    return { format: name, ...renderMap(map, options) };
+  }
+
+  const { graph, omittedFiles } = limitGraph(map, buildDependencyGraph(map), options);
+  const text = name === 'json' ? toJson(map, graph) : name === 'mermaid' ? toMermaid(graph) : toDot(graph);
+  return {
+    format: name,
+    text,
+    files: Object.keys(graph).length,
+    omittedFiles,
+    edges: countEdges(graph)
+  };
+}
+
+module.exports = {
+  FORMATS,
+  formatMap
+};
@@ -0,0 +1,155 @@
+/**
+ * C/C++ header/source pairing
+ *
+ * Links declarations in headers to their definitions in source files so a
+ * symbol declared in `foo.h` and defined in `foo.c` is counted once.
+ *
+ * @module lib/repo-map/headers
+ */
+
+'use strict';
+
+const path = require('path');
+
+const HEADER_EXTENSIONS = ['.h', '.hh', '.hpp', '.hxx'];
+const SOURCE_EXTENSIONS = ['.c', '.cc', '.cpp', '.cxx'];
+const HEADER_DIRS = ['include', 'inc', 'includes', 'headers'];
+const SOURCE_DIRS = ['src', 'source', 'lib'];
+
+/**
+ * Check whether a path is a C/C++ header
+ * @param {string} file - File path
+ * @returns {boolean}
+ */
+function isHeader(file) {
+  return HEADER_EXTENSIONS.includes(path.posix.extname(file).toLowerCase());
+}
+
+/**
+ * Check whether a path is a C/C++ source file
+ * @param {string} file - File path
+ * @returns {boolean}
+ */
+function isSource(file) {
+  return SOURCE_EXTENSIONS.includes(path.posix.extname(file).toLowerCase());
+}
+
+/**
+ * Directory with well-known header/source folder names normalized away
+ * @param {string} dir - Directory path (posix)
+ * @returns {string}
+ */
+function normalizeDir(dir) {
+  return dir
+    .split('/')
+    .filter(part => !HEADER_DIRS.includes(part) && !SOURCE_DIRS.includes(part))
+    .join('/');
+}
+
+/**
+ * Pair source files with their headers
+ * Prefers a header in the same directory, then one in a parallel
+ * include/src layout, then a unique header with the same stem anywhere.
+ * @param {string[]} files - Relative file paths
+ * @returns {Map<string, string>} - source path -> header path
+ */
+function pairHeaders(files) {
+  const headersByStem = new Map();
+  for (const file of files) {
+    if (!isHeader(file)) continue;
+    const stem = path.posix.basename(file, path.posix.extname(file));
+    if (!headersByStem.has(stem)) headersByStem.set(stem, []);
+    headersByStem.get(stem).push(file);
+  }
+
+  const pairs = new Map();
+  for (const file of files) {
+    if (!isSource(file)) continue;
+    const stem = path.posix.basename(file, path.posix.extname(file));
+    const candidates = headersByStem.get(stem);
+    if (!candidates) continue;
+
+    const dir = path.posix.dirname(file);
+    const header = candidates.find(candidate => path.posix.dirname(candidate) === dir)
+      || candidates.find(candidate => normalizeDir(path.posix.dirname(candidate)) === normalizeDir(dir))
+      || (candidates.length === 1 ? candidates[0] : null);
+    if (header) pairs.set(file, header);
+  }
+  return pairs;
+}
+
+/**
+ * Link header declarations with source definitions in place
+ * Source entries get `declaredIn`; header entries get `definedIn`.
+ * @param {Object} map - Repo map
+ * @returns {number} - Number of linked symbols
+ */
+function linkHeaderDeclarations(map) {
+  if (!map || !map.files) return 0;
+
+  const files = Object.keys(map.files).filter(file => {
+    const language = map.files[file].language;
+    return language === 'c' || language === 'cpp';
+  });
+
+  for (const file of files) {
+    for (const entry of getLinkableEntries(map.files[file])) {
+      delete entry.declaredIn;
+      delete entry.definedIn;
+    }
+  }
+
+  let linked = 0;
+  for (const [source, header] of pairHeaders(files)) {
+    const headerEntries = new Map();
+    for (const entry of getLinkableEntries(map.files[header])) {
+      headerEntries.set(entry.name, entry);
+    }
+
+    for (const entry of getLinkableEntries(map.files[source])) {
+      const declaration = headerEntries.get(entry.name)
+        || headerEntries.get(entry.name.split('::').pop());
+      if (!declaration) continue;
+      entry.declaredIn = header;
+      declaration.definedIn = source;
+      linked++;
+    }
+  }
+
+  return linked;
+}
+
+/**
+ * Entries that can be declared in a header and defined elsewhere
+ * @param {Object} fileData - File entry from the repo map
+ * @returns {Object[]}
+ */
+function getLinkableEntries(fileData) {
+  const symbols = fileData && fileData.symbols;
+  if (!symbols) return [];
+  return [...(symbols.functions || []), ...(symbols.classes || []), ...(symbols.types || [])];
+}
+
+/**
+ * Count a file's symbols, skipping definitions already counted via their header
+ * @param {Object} symbols - File symbols
+ * @returns {number}
+ */
+function countFileSymbols(symbols) {
+  if (!symbols) return 0;
+  let count = 0;
+  for (const category of ['functions', 'classes', 'types', 'constants']) {
+    for (const entry of symbols[category] || []) {
+      if (!entry.declaredIn) count++;
+    }
+  }
+  return count;
+}
+
+module.exports = {
+  pairHeaders,
+  linkHeaderDeclarations,
+  countFileSymbols,
+  isHeader,
+  isSource
+};
@@ -10,6 +10,9 @@ module.exports = {
     { pattern: 'public interface $NAME { $$$ }', kind: 'class', nameVar: 'NAME' },
     { pattern: 'public enum $NAME { $$$ }', kind: 'class', nameVar: 'NAME' },
     { pattern: 'public record $NAME($$$) { $$$ }', kind: 'class', nameVar: 'NAME' },
+    { pattern: 'public @interface $NAME { $$$ }', kind: 'class', nameVar: 'NAME' },
+    { pattern: 'public abstract class $NAME { $$$ }', kind: 'class', nameVar: 'NAME' },
+    { pattern: 'public final class $NAME { $$$ }', kind: 'class', nameVar: 'NAME' },
     { pattern: 'public $RET $NAME($$$) { $$$ }', kind: 'function', nameVar: 'NAME' },
     { pattern: 'public static $RET $NAME($$$) { $$$ }', kind: 'function', nameVar: 'NAME' },
     { pattern: 'public $RET $NAME($$$);', kind: 'function', nameVar: 'NAME' }
@@ -24,7 +27,8 @@ module.exports = {
     { pattern: 'class $NAME { $$$ }', nameVar: 'NAME' },
     { pattern: 'interface $NAME { $$$ }', nameVar: 'NAME' },
     { pattern: 'enum $NAME { $$$ }', nameVar: 'NAME' },
-    { pattern: 'record $NAME($$$) { $$$ }', nameVar: 'NAME' }
+    { pattern: 'record $NAME($$$) { $$$ }', nameVar: 'NAME' },
+    { pattern: '@interface $NAME { $$$ }', nameVar: 'NAME' }
   ],
   types: [],
   constants: [
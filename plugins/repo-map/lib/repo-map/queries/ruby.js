@@ -0,0 +1,26 @@
+/**
+ * Ruby query patterns for ast-grep
+ */
+
+'use strict';
+
+module.exports = {
+  exports: [],
+  functions: [
+    { pattern: 'def $NAME($$$)\n  $$$\nend', nameVar: 'NAME' },
+    { pattern: 'def $NAME\n  $$$\nend', nameVar: 'NAME' },
+    { pattern: 'def self.$NAME($$$)\n  $$$\nend', kind: 'class-method', nameVar: 'NAME' },
+    { pattern: 'def self.$NAME\n  $$$\nend', kind: 'class-method', nameVar: 'NAME' }
+  ],
+  classes: [
+    { pattern: 'class $NAME\n  $$$\nend', nameVar: 'NAME' },
+    { pattern: 'class $NAME < $BASE\n  $$$\nend', nameVar: 'NAME' },
+    { pattern: 'module $NAME\n  $$$\nend', kind: 'module', nameVar: 'NAME' }
+  ],
+  types: [],
+  constants: [],
+  imports: [
+    { pattern: 'require $SOURCE', sourceVar: 'SOURCE', kind: 'require' },
+    { pattern: 'require_relative $SOURCE', sourceVar: 'SOURCE', kind: 'require_relative' }
+  ]
+};
@@ -0,0 +1,213 @@
+/**
+ * Structured Logger
+ * Leveled diagnostics on stderr, as text or one JSON object per line, so
+ * stdout stays free for command output and JSON results.
+ *
+ * Each module logs under a namespace (`platform:detect`, `sources:cache`).
+ * Settings come from the environment, which child processes inherit, or
+ * from the `--log-*` flags through `applyLogArgs`:
+ * - `AWESOME_SLASH_LOG_LEVEL` / `--log-level`: silent, error, warn (default),
+ *   info, debug, trace
+ * - `AWESOME_SLASH_LOG_FORMAT` / `--log-format`: text (default) or json
+ * - `AWESOME_SLASH_LOG_NAMESPACES` / `--log-namespaces`: comma-separated
+ *   namespaces for info and below, `*` as a wildcard (e.g. `platform:*`);
+ *   errors and warnings always pass
+ *
+ *   const log = createLogger('platform:detect');
+ *   log.debug('persisted cache skipped', { reason: 'fingerprint changed' });
+ *
+ * @author Avi Fenesh
+ * @license MIT
+ */
+
+/**
+ * Levels from least to most verbose
+ */
+const LEVELS = ['silent', 'error', 'warn', 'info', 'debug', 'trace'];
+
+/**
+ * Output formats
+ */
+const FORMATS = ['text', 'json'];
+
+/**
+ * Environment variables holding the settings
+ */
+const ENV = {
+  level: 'AWESOME_SLASH_LOG_LEVEL',
+  format: 'AWESOME_SLASH_LOG_FORMAT',
+  namespaces: 'AWESOME_SLASH_LOG_NAMESPACES'
+};
+
+const DEFAULT_LEVEL = 'warn';
+
+/**
+ * Writes one formatted record; replaced in tests
+ * @type {Function|null}
+ */
+let _sink = null;
+
+/**
+ * Current settings, read from the environment on every call
+ * @returns {{level: string, format: string, namespaces: string[]}}
+ */
+function getSettings() {
+  const level = String(process.env[ENV.level] || '').toLowerCase();
+  const format = String(process.env[ENV.format] || '').toLowerCase();
+  return {
+    level: LEVELS.includes(level) ? level : DEFAULT_LEVEL,
+    format: FORMATS.includes(format) ? format : 'text',
+    namespaces: String(process.env[ENV.namespaces] || '').split(',').map(item => item.trim()).filter(Boolean)
+  };
+}
+
+/**
+ * Whether a namespace matches a filter (`*` matches any run of characters)
+ * @param {string} namespace - Logger namespace
+ * @param {string} filter - Namespace or wildcard pattern
+ * @returns {boolean}
+ */
+function matchesNamespace(namespace, filter) {
+  const pattern = filter.split('*').map(part => part.replace(/[.+?^${}()|[\]\\]/g, '\\$&')).join('.*');
+  return new RegExp(`^${pattern}$`).test(namespace);
+}
+
+/**
+ * Whether a record would be written
+ * @param {string} namespace - Logger namespace
+ * @param {string} level - Record level
+ * @param {Object} [settings] - Defaults to getSettings()
+ * @returns {boolean}
+ */
+function isEnabled(namespace, level, settings = getSettings()) {
+  const rank = LEVELS.indexOf(level);
+  if (rank <= 0 || rank > LEVELS.indexOf(settings.level)) return false;
+  if (rank <= LEVELS.indexOf('warn') || settings.namespaces.length === 0) return true;
+  return settings.namespaces.some(filter => matchesNamespace(namespace, filter));
+}
+
+/**
+ * JSON-safe copy of record fields (errors become their message)
+ * @param {Object} fields - Extra fields
+ * @returns {Object}
+ */
+function normalizeFields(fields) {
+  const result = {};
+  for (const [key, value] of Object.entries(fields || {})) {
+    if (value === undefined) continue;
+    result[key] = value instanceof Error ? value.message : value;
+  }
+  return result;
+}
+
+/**
+ * Format a record
+ * Text is `namespace: message (key=value, ...)`; JSON adds time and level.
+ * @param {Object} record - {level, namespace, message, fields}
+ * @param {string} format - text or json
+ * @returns {string}
+ */
+function formatRecord(record, format) {
+  const fields = normalizeFields(record.fields);
+  if (format === 'json') {
+    return JSON.stringify({
+      time: new Date().toISOString(),
+      level: record.level,
+      namespace: record.namespace,
+      message: record.message,
+      ...fields
+    });
+  }
+  const details = Object.entries(fields)
+    .map(([key, value]) => `${key}=${typeof value === 'string' ? value : JSON.stringify(value)}`)
+    .join(', ');
+  return `${record.namespace}: ${record.message}${details ? ` (${details})` : ''}`;
+}
+
+/**
+ * Write a record to stderr (warnings through console.warn, the rest console.error)
+ * @param {string} line - Formatted record
+ * @param {string} level - Record level
+ */
+function write(line, level) {
+  if (_sink) {
+    _sink(line, level);
+  } else if (level === 'warn') {
+    console.warn(line);
+  } else {
+    console.error(line);
+  }
+}
+
+/**
+ * Create a namespaced logger
+ * @param {string} namespace - e.g. `platform:detect`
+ * @returns {{error: Function, warn: Function, info: Function, debug: Function, trace: Function,
+ *   enabled: Function, child: Function, namespace: string}} Each level method takes (message, fields)
+ */
+function createLogger(namespace) {
+  const logger = { namespace };
+  for (const level of LEVELS.slice(1)) {
+    logger[level] = (message, fields) => {
+      const settings = getSettings();
+      if (!isEnabled(namespace, level, settings)) return;
+      write(formatRecord({ level, namespace, message, fields }, settings.format), level);
+    };
+  }
+  logger.enabled = level => isEnabled(namespace, level);
+  logger.child = name => createLogger(`${namespace}:${name}`);
+  return logger;
+}
+
+/**
+ * Apply `--log-level`, `--log-format`, and `--log-namespaces` (also as
+ * `--flag=value`) to the environment and remove them from argv
+ * @param {string[]} argv - Arguments (not modified)
+ * @returns {{argv: string[], error: string|null}} Remaining arguments
+ */
+function applyLogArgs(argv) {
+  const flags = { '--log-level': 'level', '--log-format': 'format', '--log-namespaces': 'namespaces' };
+  const rest = [];
+  const values = {};
+  for (let i = 0; i < argv.length; i++) {
+    const [flag, inline] = argv[i].split(/=(.*)/s);
+    if (!flags[flag]) {
+      rest.push(argv[i]);
+      continue;
+    }
+    const value = inline !== undefined ? inline : argv[++i];
+    if (value === undefined || value === '') {
+      return { argv: rest, error: `${flag} requires a value` };
+    }
+    values[flags[flag]] = value;
+  }
+  if (values.level && !LEVELS.includes(values.level.toLowerCase())) {
+    return { argv: rest, error: `--log-level must be one of ${LEVELS.join(', ')}` };
+  }
+  if (values.format && !FORMATS.includes(values.format.toLowerCase())) {
+    return { argv: rest, error: `--log-format must be one of ${FORMATS.join(', ')}` };
+  }
+  for (const [key, value] of Object.entries(values)) {
+    process.env[ENV[key]] = value;
+  }
+  return { argv: rest, error: null };
+}
+
+/**
+ * Replace the output (for tests); null restores stderr
+ * @param {Function|null} sink - `(line, level) => void`
+ */
+function setSink(sink) {
+  _sink = sink;
+}
+
+module.exports = {
+  LEVELS,
+  FORMATS,
+  ENV,
+  createLogger,
+  isEnabled,
+  formatRecord,
+  applyLogArgs,
+  setSink
+};
@@ -0,0 +1,308 @@
+/**
+ * Scan Limits
+ * Which files scanners leave out because their content is not worth
+ * reading: oversized fixtures, binaries, minified bundles, and generated
+ * code. The checks follow GitHub linguist's heuristics.
+ *
+ * - `too-large`: bigger than `scan.maxFileSizeKb` (default 1024; 0 turns the ceiling off)
+ * - `binary`: a NUL byte in the first 8000 bytes (git's test), or `binary` / `-diff` / `-text` in `.gitattributes`
+ * - `minified`: `*.min.js` / `*.min.css`, or JavaScript/CSS whose average line is longer than 110 characters
+ * - `generated`: `linguist-generated` in `.gitattributes`, generated paths
+ *   (`*.pb.go`, `*_pb2.py`, `__generated__/`, ...), or an `@generated` /
+ *   `DO NOT EDIT` header; `-linguist-generated` keeps a path in
+ *
+ * Files are skipped by default; `scan.generated` and `scan.minified` in the
+ * project config scan them anyway. Binary files are always skipped.
+ * Skipped files are recorded so reports can say what was left out.
+ *
+ * @module lib/utils/scan-limits
+ * @author Avi Fenesh
+ * @license MIT
+ */
+
+const { loadConfig } = require('../config');
+const { compilePattern } = require('./ignore');
+const { t } = require('../messages');
+
+const CONFIG_KEY = 'scan';
+
+const DEFAULTS = {
+  maxFileSizeKb: 1024,
+  generated: false,
+  minified: false
+};
+
+/**
+ * Skip reasons, in the order reports list them
+ */
+const REASONS = ['too-large', 'binary', 'minified', 'generated'];
+
+/**
+ * Paths of generated files (protobuf, Dart codegen, ...)
+ */
+const GENERATED_PATHS = /\.pb(?:\.gw)?\.go$|_pb2(?:_grpc)?\.pyi?$|_pb\.(?:js|d\.ts)$|\.g\.dart$|\.freezed\.dart$|[._]generated\.\w+$|(?:^|\/)(?:__generated__|generated)\//;
+
+/**
+ * Header comments generators write, checked in the first lines of the file
+ */
+const GENERATED_MARKER = /@generated|Code generated .* DO NOT EDIT|auto-?generated|DO NOT EDIT/i;
+
+/**
+ * Paths of minified bundles
+ */
+const MINIFIED_PATHS = /[.-]min\.(?:[cm]?js|css)$/;
+const MINIFIABLE_EXTENSIONS = ['.js', '.mjs', '.cjs', '.css'];
+
+/** Lines checked for a generated header */
+const MARKER_LINES = 5;
+/** Bytes read to check for NUL bytes and line lengths */
+const SAMPLE_BYTES = 64 * 1024;
+const BINARY_SAMPLE_BYTES = 8000;
+/** Average line length above which JavaScript and CSS count as minified */
+const MINIFIED_LINE_LENGTH = 110;
+/** Skipped files listed individually in a summary */
+const LISTED_FILES = 20;
+
+/**
+ * Scan limits from the project config
+ * @param {string} basePath - Repository root
+ * @returns {{maxFileSizeKb: number, generated: boolean, minified: boolean, error: string|null}}
+ *   `generated` / `minified`: scan those files instead of skipping them
+ */
+function readSettings(basePath) {
+  const { config, file, error } = loadConfig(basePath);
+  const settings = { ...DEFAULTS, error };
+  const value = config[CONFIG_KEY];
+  if (error || value === undefined) return settings;
+
+  const fail = message => ({ ...settings, error: `${file}: ${CONFIG_KEY}${message}` });
+  if (!value || typeof value !== 'object' || Array.isArray(value)) return fail(' must be an object');
+  if (value.maxFileSizeKb !== undefined && !(typeof value.maxFileSizeKb === 'number' && value.maxFileSizeKb >= 0)) {
+    return fail('.maxFileSizeKb must be a number of kilobytes (0 for no limit)');
+  }
+  for (const key of ['generated', 'minified']) {
+    if (value[key] !== undefined && typeof value[key] !== 'boolean') return fail(`.${key} must be true or false`);
+  }
+
+  return {
+    ...settings,
+    maxFileSizeKb: value.maxFileSizeKb !== undefined ? value.maxFileSizeKb : DEFAULTS.maxFileSizeKb,
+    generated: value.generated === true,
+    minified: value.minified === true
+  };
+}
+
+/**
+ * Parse `.gitattributes` into the rules scanners care about
+ * @param {string} content - File content
+ * @returns {Array<{regex: RegExp, dirOnly: boolean, generated?: boolean, binary?: boolean}>}
+ */
+function parseAttributes(content) {
+  const rules = [];
+  for (const raw of String(content || '').split('\n')) {
+    const line = raw.trim();
+    if (!line || line.startsWith('#')) continue;
+    const [pattern, ...attrs] = line.split(/\s+/);
+    // Negated patterns are not allowed in .gitattributes
+    if (pattern.startsWith('!')) continue;
+
+    const rule = {};
+    for (const attr of attrs) {
+      if (attr === 'linguist-generated' || attr === 'linguist-generated=true') rule.generated = true;
+      else if (attr === '-linguist-generated' || attr === 'linguist-generated=false') rule.generated = false;
+      else if (attr === 'binary' || attr === '-diff' || attr === '-text') rule.binary = true;
+      else if (attr === 'diff' || attr === 'text') rule.binary = false;
+    }
+    if (rule.generated === undefined && rule.binary === undefined) continue;
+    const { regex, dirOnly } = compilePattern(pattern);
+    rules.push({ regex, dirOnly, ...rule });
+  }
+  return rules;
+}
+
+/**
+ * Attributes of one path (last matching rule wins per attribute)
+ * @param {Array} rules - From parseAttributes
+ * @param {string} relativePath - Path relative to the repository root, posix
+ * @returns {{generated?: boolean, binary?: boolean}}
+ */
+function attributesFor(rules, relativePath) {
+  const attrs = {};
+  const parts = relativePath.split('/');
+  for (const rule of rules) {
+    // A directory pattern applies to every file below it
+    const matched = rule.dirOnly
+      ? parts.slice(0, -1).some((_, i) => rule.regex.test(parts.slice(0, i + 1).join('/')))
+      : rule.regex.test(relativePath);
+    if (!matched) continue;
+    if (rule.generated !== undefined) attrs.generated = rule.generated;
+    if (rule.binary !== undefined) attrs.binary = rule.binary;
+  }
+  return attrs;
+}
+
+/**
+ * Whether a path or header marks a file as generated
+ * @param {string} relativePath - Path relative to the repository root, posix
+ * @param {string} [content] - File content or its first lines
+ * @returns {boolean}
+ */
+function isGenerated(relativePath, content = '') {
+  return GENERATED_PATHS.test(relativePath) ||
+    GENERATED_MARKER.test(content.split('\n').slice(0, MARKER_LINES).join('\n'));
+}
+
+/**
+ * Whether JavaScript or CSS content is minified
+ * @param {string} relativePath - Path relative to the repository root
+ * @param {string} content - File content or a leading sample of it
+ * @returns {boolean}
+ */
+function isMinified(relativePath, content) {
+  if (MINIFIED_PATHS.test(relativePath)) return true;
+  const dot = relativePath.lastIndexOf('.');
+  if (dot === -1 || !MINIFIABLE_EXTENSIONS.includes(relativePath.slice(dot).toLowerCase())) return false;
+  const lines = content.split('\n');
+  return content.length > 0 && content.length / lines.length > MINIFIED_LINE_LENGTH;
+}
+
+/**
+ * Read the first bytes of a file
+ * @param {Object} fs - File system module
+ * @param {string} file - Absolute path
+ * @param {number} size - File size
+ * @returns {Buffer}
+ */
+function readSample(fs, file, size) {
+  const buffer = Buffer.alloc(Math.min(size, SAMPLE_BYTES));
+  const fd = fs.openSync(file, 'r');
+  try {
+    const read = fs.readSync(fd, buffer, 0, buffer.length, 0);
+    return buffer.subarray(0, read);
+  } finally {
+    fs.closeSync(fd);
+  }
+}
+
+/**
+ * Build the skip predicate scanners apply to each file before reading it
+ *
+ * The predicate takes a path relative to `basePath` (or absolute), returns
+ * the skip reason (see REASONS) or null to scan the file, and records every
+ * skipped file in `filter.skipped`.
+ *
+ * @param {string} basePath - Repository root
+ * @param {Object} [options]
+ * @param {Object} [options.settings] - Limits (default: readSettings)
+ * @param {Object} [options.fs] - File system module (for testing)
+ * @param {Object} [options.path] - Path module (for testing)
+ * @returns {Function} `(file) => string|null`, with `skipped`, `settings`, and `summary()`
+ */
+function createFileFilter(basePath, options = {}) {
+  const fs = options.fs || require('fs');
+  const path = options.path || require('path');
+  const settings = options.settings || readSettings(basePath);
+  const maxBytes = settings.maxFileSizeKb > 0 ? settings.maxFileSizeKb * 1024 : Infinity;
+
+  let rules = [];
+  try {
+    rules = parseAttributes(fs.readFileSync(path.join(basePath, '.gitattributes'), 'utf8'));
+  } catch {
+    // No .gitattributes
+  }
+
+  function classify(relativePath) {
+    const attrs = attributesFor(rules, relativePath);
+    let size;
+    try {
+      size = fs.statSync(path.join(basePath, relativePath)).size;
+    } catch {
+      return null; // Missing files are the scanner's to report
+    }
+
+    if (size > maxBytes) return { reason: 'too-large', size };
+    if (attrs.binary) return { reason: 'binary', size };
+    if (attrs.generated === true && !settings.generated) return { reason: 'generated', size };
+    if (size === 0) return null;
+
+    let sample;
+    try {
+      sample = readSample(fs, path.join(basePath, relativePath), size);
+    } catch {
+      return null;
+    }
+    if (sample.subarray(0, BINARY_SAMPLE_BYTES).includes(0)) return { reason: 'binary', size };
+
+    const text = sample.toString('utf8');
+    if (!settings.minified && isMinified(relativePath, text)) return { reason: 'minified', size };
+    if (!settings.generated && attrs.generated !== false && isGenerated(relativePath, text)) return { reason: 'generated', size };
+    return null;
+  }
+
+  // Each file is checked and recorded once, even when several walks reach it
+  const seen = new Map();
+  const skipped = [];
+  function skip(file) {
+    const relative = path.isAbsolute(file) ? path.relative(basePath, file) : file;
+    const normalized = relative.replace(/\\/g, '/');
+    if (seen.has(normalized)) return seen.get(normalized);
+    const result = classify(normalized);
+    seen.set(normalized, result ? result.reason : null);
+    if (!result) return null;
+    skipped.push({ file: normalized, reason: result.reason, size: result.size });
+    return result.reason;
+  }
+  skip.skipped = skipped;
+  skip.settings = settings;
+  skip.summary = () => summarize(skipped, settings);
+  return skip;
+}
+
+/**
+ * Summarize skipped files for reports
+ * @param {Array<{file: string, reason: string, size: number}>} skipped - From a file filter
+ * @param {Object} [settings] - Limits the filter used
+ * @returns {{total: number, bytes: number, byReason: Object<string, number>, files: Array, maxFileSizeKb: number}}
+ *   `files` lists the largest skipped files first, at most 20
+ */
+function summarize(skipped, settings = DEFAULTS) {
+  const byReason = {};
+  for (const entry of skipped) byReason[entry.reason] = (byReason[entry.reason] || 0) + 1;
+  return {
+    total: skipped.length,
+    bytes: skipped.reduce((total, entry) => total + entry.size, 0),
+    byReason,
+    files: skipped.slice().sort((a, b) => b.size - a.size || a.file.localeCompare(b.file)).slice(0, LISTED_FILES),
+    maxFileSizeKb: settings.maxFileSizeKb
+  };
+}
+
+/**
+ * One-line description of a summary, e.g. "3 files: 1 larger than 1024 KB, 2 minified"
+ * @param {Object} summary - From summarize
+ * @returns {string} Empty when nothing was skipped
+ */
+function renderSkipped(summary) {
+  if (!summary || summary.total === 0) return '';
+  const parts = REASONS
+    .filter(reason => summary.byReason[reason])
+    .map(reason => t(`scan.skipped.${reason}`, { count: summary.byReason[reason], kb: summary.maxFileSizeKb }));
+  return t('scan.skipped.files', { count: summary.total, reasons: parts.join(', ') });
+}
+
+module.exports = {
+  CONFIG_KEY,
+  DEFAULTS,
+  REASONS,
+  GENERATED_PATHS,
+  GENERATED_MARKER,
+  MINIFIED_PATHS,
+  readSettings,
+  parseAttributes,
+  attributesFor,
+  isGenerated,
+  isMinified,
+  createFileFilter,
+  summarize,
+  renderSkipped
+};
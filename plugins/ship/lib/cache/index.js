@@ -0,0 +1,348 @@
+#!/usr/bin/env node
+/**
+ * Shared Disk Cache
+ *
+ * One cache for every subsystem that keeps results between runs: platform
+ * detection, repo-map per-file symbols, OSV vulnerability lookups, and CI
+ * test results. Entries are JSON files under `.awesome-slash/cache/<namespace>/`,
+ * expire after a per-namespace TTL, and the oldest are evicted once the
+ * directory grows past `cache.maxSizeMb`. Callers that can tell whether an
+ * entry is still valid (fingerprints, content hashes) keep checking that;
+ * the TTL only bounds how long anything is trusted.
+ *
+ * Usage: node lib/cache/index.js [stats|clear] [--namespace NAME] [--expired]
+ * Output: JSON (stats: entries and bytes per namespace; clear: what was removed)
+ *
+ * @module lib/cache
+ */
+
+const crypto = require('crypto');
+const fs = require('fs');
+const path = require('path');
+
+const { loadConfig } = require('../config');
+
+/**
+ * Cache directory, relative to the repository root
+ */
+const CACHE_DIR = '.awesome-slash/cache';
+
+const CONFIG_KEY = 'cache';
+
+/**
+ * Size ceiling and TTLs (seconds) per namespace; `ttl.default` covers the rest
+ */
+const DEFAULTS = {
+  maxSizeMb: 100,
+  ttl: {
+    default: 24 * 60 * 60,
+    detection: 24 * 60 * 60,
+    'repo-map': 30 * 24 * 60 * 60,
+    osv: 6 * 60 * 60,
+    ci: 7 * 24 * 60 * 60
+  }
+};
+
+const NAMESPACE_PATTERN = /^[a-z0-9][a-z0-9-]*$/;
+
+/**
+ * Bytes in use per cache directory, counted once per process
+ * @type {Map<string, number>}
+ */
+const _usage = new Map();
+
+/**
+ * Cache settings from the project config
+ * @param {string} basePath - Repository root
+ * @returns {{enabled: boolean, maxSizeMb: number, ttl: Object<string, number>, error: string|null}}
+ */
+function readSettings(basePath) {
+  const { config, file, error } = loadConfig(basePath);
+  const settings = { enabled: true, maxSizeMb: DEFAULTS.maxSizeMb, ttl: { ...DEFAULTS.ttl }, error };
+  const value = config[CONFIG_KEY];
+  if (error || value === undefined) return settings;
+
+  const fail = message => ({ ...settings, error: `${file}: ${CONFIG_KEY}${message}` });
+  if (!value || typeof value !== 'object' || Array.isArray(value)) return fail(' must be an object');
+  if (value.enabled !== undefined && typeof value.enabled !== 'boolean') return fail('.enabled must be true or false');
+  if (value.maxSizeMb !== undefined && !(typeof value.maxSizeMb === 'number' && value.maxSizeMb > 0)) return fail('.maxSizeMb must be a positive number');
+  if (value.ttl !== undefined && (!value.ttl || typeof value.ttl !== 'object' || Array.isArray(value.ttl))) return fail('.ttl must be an object');
+  for (const [namespace, seconds] of Object.entries(value.ttl || {})) {
+    if (!(Number.isInteger(seconds) && seconds >= 0)) return fail(`.ttl.${namespace} must be a whole number of seconds`);
+  }
+
+  return {
+    ...settings,
+    enabled: value.enabled !== false,
+    maxSizeMb: value.maxSizeMb || DEFAULTS.maxSizeMb,
+    ttl: { ...settings.ttl, ...(value.ttl || {}) }
+  };
+}
+
+/**
+ * Cache directory for a repository
+ * @param {string} basePath - Repository root
+ * @param {string} [namespace] - Subdirectory of one subsystem
+ * @returns {string}
+ */
+function cacheDir(basePath, namespace) {
+  const dir = path.join(path.resolve(basePath), CACHE_DIR);
+  return namespace ? path.join(dir, namespace) : dir;
+}
+
+/**
+ * File name for a key; keys that are not plain names are hashed
+ * @param {string} key - Cache key
+ * @returns {string}
+ */
+function entryName(key) {
+  const text = String(key);
+  if (/^[A-Za-z0-9][A-Za-z0-9._-]{0,79}$/.test(text)) return `${text}.json`;
+  return `${crypto.createHash('sha1').update(text).digest('hex')}.json`;
+}
+
+/**
+ * Entry files of a cache directory
+ * @param {string} dir - Result of cacheDir
+ * @returns {Array<{namespace: string, file: string, bytes: number, mtimeMs: number}>}
+ */
+function listEntries(dir) {
+  const entries = [];
+  let namespaces = [];
+  try {
+    namespaces = fs.readdirSync(dir, { withFileTypes: true }).filter(entry => entry.isDirectory());
+  } catch {
+    return entries;
+  }
+  for (const { name: namespace } of namespaces) {
+    let files = [];
+    try {
+      files = fs.readdirSync(path.join(dir, namespace));
+    } catch {
+      continue;
+    }
+    for (const name of files) {
+      if (!name.endsWith('.json')) continue;
+      const file = path.join(dir, namespace, name);
+      try {
+        const stats = fs.statSync(file);
+        if (stats.isFile()) entries.push({ namespace, file, bytes: stats.size, mtimeMs: stats.mtimeMs });
+      } catch {
+        // Removed meanwhile
+      }
+    }
+  }
+  return entries;
+}
+
+/**
+ * Remove the oldest entries until the directory fits
+ * @param {string} dir - Result of cacheDir
+ * @param {number} maxBytes - Size ceiling
+ * @returns {{removed: number, bytes: number}} What was evicted
+ */
+function evict(dir, maxBytes) {
+  const entries = listEntries(dir).sort((a, b) => a.mtimeMs - b.mtimeMs);
+  let total = entries.reduce((sum, entry) => sum + entry.bytes, 0);
+  const evicted = { removed: 0, bytes: 0 };
+  for (const entry of entries) {
+    if (total <= maxBytes) break;
+    fs.rmSync(entry.file, { force: true });
+    total -= entry.bytes;
+    evicted.removed++;
+    evicted.bytes += entry.bytes;
+  }
+  _usage.set(dir, total);
+  return evicted;
+}
+
+/**
+ * Open one namespace of the cache
+ * @param {string} basePath - Repository root
+ * @param {string} namespace - Subsystem name (lowercase, dashes)
+ * @param {Object} [options]
+ * @param {number} [options.ttl] - Seconds an entry stays valid; overrides config and defaults
+ * @param {Object} [options.settings] - Result of readSettings (read from the project config otherwise)
+ * @returns {{namespace: string, enabled: boolean, ttlMs: number, file: Function, get: Function, set: Function, delete: Function, wrap: Function, clear: Function}}
+ */
+function createCache(basePath, namespace, options = {}) {
+  if (!NAMESPACE_PATTERN.test(String(namespace))) {
+    throw new Error(`Invalid cache namespace: ${namespace}`);
+  }
+  const settings = options.settings || readSettings(basePath);
+  const root = cacheDir(basePath);
+  const dir = cacheDir(basePath, namespace);
+  const seconds = options.ttl !== undefined ? options.ttl : (settings.ttl[namespace] ?? settings.ttl.default);
+  const ttlMs = seconds * 1000;
+  const maxBytes = settings.maxSizeMb * 1024 * 1024;
+
+  const file = key => path.join(dir, entryName(key));
+
+  /**
+   * Cached value, or undefined when missing, expired, or unreadable
+   */
+  function get(key) {
+    if (!settings.enabled) return undefined;
+    const entryFile = file(key);
+    try {
+      const stats = fs.statSync(entryFile);
+      if (Date.now() - stats.mtimeMs > ttlMs) {
+        remove(key);
+        return undefined;
+      }
+      return JSON.parse(fs.readFileSync(entryFile, 'utf8'));
+    } catch {
+      return undefined;
+    }
+  }
+
+  /**
+   * Store a JSON-serializable value; returns false when caching is off or the write failed
+   */
+  function set(key, value) {
+    if (!settings.enabled || value === undefined) return false;
+    const entryFile = file(key);
+    const content = JSON.stringify(value);
+    const tempFile = `${entryFile}.${process.pid}.tmp`;
+    try {
+      fs.mkdirSync(dir, { recursive: true });
+      if (!_usage.has(root)) _usage.set(root, listEntries(root).reduce((sum, entry) => sum + entry.bytes, 0));
+      let previous = 0;
+      try {
+        previous = fs.statSync(entryFile).size;
+      } catch {
+        // New entry
+      }
+      fs.writeFileSync(tempFile, content, 'utf8');
+      fs.renameSync(tempFile, entryFile);
+      _usage.set(root, _usage.get(root) - previous + Buffer.byteLength(content));
+    } catch {
+      // Read-only checkout: run without the cache
+      fs.rmSync(tempFile, { force: true });
+      return false;
+    }
+    if (_usage.get(root) > maxBytes) evict(root, maxBytes);
+    return true;
+  }
+
+  /**
+   * Remove one entry; returns true when it existed
+   */
+  function remove(key) {
+    const entryFile = file(key);
+    try {
+      const { size } = fs.statSync(entryFile);
+      fs.rmSync(entryFile, { force: true });
+      if (_usage.has(root)) _usage.set(root, _usage.get(root) - size);
+      return true;
+    } catch {
+      return false;
+    }
+  }
+
+  /**
+   * Cached value, computing and storing it on a miss
+   * `undefined` results are not stored.
+   */
+  async function wrap(key, compute) {
+    const cached = get(key);
+    if (cached !== undefined) return cached;
+    const value = await compute();
+    set(key, value);
+    return value;
+  }
+
+  return {
+    namespace,
+    enabled: settings.enabled,
+    ttlMs,
+    file,
+    get,
+    set,
+    delete: remove,
+    wrap,
+    clear: () => clear(basePath, { namespace })
+  };
+}
+
+/**
+ * Remove cache entries
+ * @param {string} basePath - Repository root
+ * @param {Object} [options]
+ * @param {string} [options.namespace] - Only this namespace
+ * @param {boolean} [options.expired] - Only entries past their namespace TTL
+ * @returns {{removed: number, bytes: number}}
+ */
+function clear(basePath, options = {}) {
+  const root = cacheDir(basePath);
+  const { ttl } = options.expired ? readSettings(basePath) : DEFAULTS;
+  const now = Date.now();
+  const result = { removed: 0, bytes: 0 };
+  for (const entry of listEntries(root)) {
+    if (options.namespace && entry.namespace !== options.namespace) continue;
+    if (options.expired && now - entry.mtimeMs <= (ttl[entry.namespace] ?? ttl.default) * 1000) continue;
+    fs.rmSync(entry.file, { force: true });
+    result.removed++;
+    result.bytes += entry.bytes;
+  }
+  if (!options.namespace && !options.expired) fs.rmSync(root, { recursive: true, force: true });
+  _usage.delete(root);
+  return result;
+}
+
+/**
+ * Entries and bytes per namespace, for `cache stats`
+ * @param {string} basePath - Repository root
+ * @returns {{dir: string, enabled: boolean, maxSizeMb: number, entries: number, bytes: number,
+ *   namespaces: Array<{namespace: string, entries: number, bytes: number, expired: number, ttl: number}>, error: string|null}}
+ */
+function stats(basePath) {
+  const settings = readSettings(basePath);
+  const now = Date.now();
+  const namespaces = new Map();
+  for (const entry of listEntries(cacheDir(basePath))) {
+    const ttl = settings.ttl[entry.namespace] ?? settings.ttl.default;
+    if (!namespaces.has(entry.namespace)) namespaces.set(entry.namespace, { namespace: entry.namespace, entries: 0, bytes: 0, expired: 0, ttl });
+    const summary = namespaces.get(entry.namespace);
+    summary.entries++;
+    summary.bytes += entry.bytes;
+    if (now - entry.mtimeMs > ttl * 1000) summary.expired++;
+  }
+  const list = Array.from(namespaces.values()).sort((a, b) => a.namespace.localeCompare(b.namespace));
+  return {
+    dir: CACHE_DIR,
+    enabled: settings.enabled,
+    maxSizeMb: settings.maxSizeMb,
+    entries: list.reduce((sum, item) => sum + item.entries, 0),
+    bytes: list.reduce((sum, item) => sum + item.bytes, 0),
+    namespaces: list,
+    error: settings.error
+  };
+}
+
+if (require.main === module) {
+  const args = process.argv.slice(2);
+  const action = args[0] && !args[0].startsWith('-') ? args[0] : 'stats';
+  const namespaceIndex = args.indexOf('--namespace');
+  const namespace = namespaceIndex === -1 ? undefined : args[namespaceIndex + 1];
+  const indent = process.stdout.isTTY ? 2 : 0;
+  if (action === 'stats') console.log(JSON.stringify(stats(process.cwd()), null, indent));
+  else if (action === 'clear') console.log(JSON.stringify(clear(process.cwd(), { namespace, expired: args.includes('--expired') }), null, indent));
+  else {
+    console.error('Usage: node lib/cache/index.js [stats|clear] [--namespace NAME] [--expired]');
+    process.exitCode = 1;
+  }
+}
+
+module.exports = {
+  CACHE_DIR,
+  CONFIG_KEY,
+  DEFAULTS,
+  readSettings,
+  cacheDir,
+  entryName,
+  createCache,
+  evict,
+  clear,
+  stats
+};
@@ -0,0 +1,932 @@
+#!/usr/bin/env node
+/**
+ * License Check
+ *
+ * Walks the lockfile of every package manager in the project (transitive
+ * dependencies included), resolves each package's license from the
+ * lockfile, the installed package, its license file, or the ecosystem's
+ * registry, and checks it against the `licenseCheck` policy in the project
+ * config. Without a policy, copyleft, proprietary, and unrecognized
+ * licenses fail and weak copyleft is a warning.
+ *
+ * Usage: node lib/license-check/index.js [--offline]
+ * Output: JSON report; exit code 1 when the check fails to run, 2 on violations
+ *
+ * @module lib/license-check
+ */
+
+const fs = require('fs');
+const https = require('https');
+const path = require('path');
+
+const { loadConfig } = require('../config');
+const { parseGoRequires } = require('../deps');
+const { normalizePythonName } = require('../platform/detect-database');
+const exec = require('../utils/exec');
+
+/**
+ * Project config key holding the policy
+ */
+const CONFIG_KEY = 'licenseCheck';
+
+/**
+ * Categories that fail when no allow list is configured
+ */
+const FAILING_CATEGORIES = ['copyleft', 'proprietary', 'unknown'];
+
+/**
+ * License families by category, matched against the start of an SPDX id
+ * Checked in order, so the non-commercial Creative Commons variants are
+ * caught before the permissive `CC-BY` ones.
+ */
+const CATEGORIES = [
+  ['proprietary', /^(?:UNLICENSED|BUSL|Elastic|Commons-Clause|CC-BY-NC|SSPL|LicenseRef-Proprietary|Proprietary)/i],
+  ['copyleft', /^(?:AGPL|GPL|OSL|EUPL|RPL|CPAL|Sleepycat|QPL|CC-BY-SA)/i],
+  ['weak-copyleft', /^(?:LGPL|MPL|EPL|CDDL|CPL|MS-RL|APSL|OFL|IPL|Artistic-1)/i],
+  ['permissive', /^(?:MIT|ISC|BSD|0BSD|Apache|Unlicense|CC0|CC-BY|Zlib|BSL-1\.0|BlueOak|Python|PSF|WTFPL|Artistic-2|X11|Unicode|PostgreSQL|NCSA|OpenSSL|curl|W3C|Ruby|UPL|HPND|MulanPSL|LicenseRef-Public-Domain)/i]
+];
+
+/**
+ * Exceptions that allow linking without the copyleft terms applying
+ */
+const LINKING_EXCEPTIONS = /^(?:Classpath-exception|GCC-exception|LLVM-exception|Autoconf-exception|Bison-exception|Font-exception|openvpn-openssl-exception)/i;
+
+/**
+ * Free-form license names (package metadata, Python classifiers) to SPDX ids
+ * Matched against the lowercased name with "the", "license", "version", and
+ * "software" removed.
+ */
+const LICENSE_ALIASES = [
+  [/^mit$/, 'MIT'],
+  [/^apache(?:[ -]?v?2(?:\.0)?)?$/, 'Apache-2.0'],
+  [/^isc$/, 'ISC'],
+  [/^(?:new |modified |revised )?bsd[ -]?3(?:[ -]clause)?$|^(?:new|modified|revised) bsd$/, 'BSD-3-Clause'],
+  [/^(?:simplified |freebsd )?bsd[ -]?2(?:[ -]clause)?$|^(?:simplified|freebsd) bsd$/, 'BSD-2-Clause'],
+  [/^bsd$/, 'BSD'],
+  [/^(?:gnu )?affero general public v?3|^agpl[ -]?v?3/, 'AGPL-3.0'],
+  [/^(?:gnu )?lesser general public v?3|^lgpl[ -]?v?3/, 'LGPL-3.0'],
+  [/^(?:gnu )?lesser general public v?2\.1|^lgpl[ -]?v?2\.1/, 'LGPL-2.1'],
+  [/^(?:gnu )?(?:library|lesser) general public v?2|^lgpl[ -]?v?2/, 'LGPL-2.0'],
+  [/^lgpl$|^(?:gnu )?lesser general public$/, 'LGPL'],
+  [/^(?:gnu )?general public v?3|^gpl[ -]?v?3/, 'GPL-3.0'],
+  [/^(?:gnu )?general public v?2|^gpl[ -]?v?2/, 'GPL-2.0'],
+  [/^gpl$|^gnu general public$/, 'GPL'],
+  [/^mozilla public 2(?:\.0)?|^mpl[ -]?2(?:\.0)?$/, 'MPL-2.0'],
+  [/^eclipse public 2(?:\.0)?|^epl[ -]?2(?:\.0)?$/, 'EPL-2.0'],
+  [/^eclipse public 1(?:\.0)?|^epl[ -]?1(?:\.0)?$/, 'EPL-1.0'],
+  [/^python foundation|^psf/, 'PSF-2.0'],
+  [/^unlicense$/, 'Unlicense'],
+  [/^public domain$/, 'LicenseRef-Public-Domain'],
+  [/^cc0(?:[ -]1\.0)?(?: universal)?$|^creative commons zero/, 'CC0-1.0'],
+  [/^zlib(?:\/libpng)?$/, 'Zlib'],
+  [/^boost(?: 1\.0)?$/, 'BSL-1.0']
+];
+
+/**
+ * License texts by their identifying sentence, on whitespace-collapsed text
+ * More specific licenses come first (AGPL and LGPL before GPL, BSD-3 before BSD-2).
+ */
+const LICENSE_TEXTS = [
+  ['AGPL-3.0', /GNU AFFERO GENERAL PUBLIC LICENSE/i],
+  ['LGPL-3.0', /GNU LESSER GENERAL PUBLIC LICENSE Version 3/i],
+  ['LGPL-2.1', /GNU LESSER GENERAL PUBLIC LICENSE Version 2\.1/i],
+  ['LGPL-2.0', /GNU LIBRARY GENERAL PUBLIC LICENSE/i],
+  ['GPL-3.0', /GNU GENERAL PUBLIC LICENSE Version 3/i],
+  ['GPL-2.0', /GNU GENERAL PUBLIC LICENSE Version 2/i],
+  ['MPL-2.0', /Mozilla Public License,? (?:Version|v\.?) ?2\.0/i],
+  ['EPL-2.0', /Eclipse Public License - v 2\.0/i],
+  ['Apache-2.0', /Apache License,? Version 2\.0/i],
+  ['Unlicense', /This is free and unencumbered software released into the public domain/i],
+  ['CC0-1.0', /CC0 1\.0 Universal/i],
+  ['BSL-1.0', /Boost Software License - Version 1\.0/i],
+  ['ISC', /Permission to use, copy, modify, and(?:\/or)? distribute this software for any purpose with or without fee/i],
+  ['MIT', /Permission is hereby granted, free of charge, to any person obtaining a copy/i],
+  ['BSD-3-Clause', /Redistribution and use in source and binary forms.*Neither the name/i],
+  ['BSD-2-Clause', /Redistribution and use in source and binary forms/i],
+  ['Zlib', /This software is provided 'as-is', without any express or implied warranty/i]
+];
+
+/**
+ * License file names in a package directory
+ */
+const LICENSE_FILE = /^(?:LICEN[CS]E|COPYING)(?:[-.][\w.-]+)?$/i;
+
+/**
+ * Lockfiles by ecosystem; the first one found per ecosystem wins
+ * `go.mod` lists every module in the build since Go 1.17, and pinned
+ * requirements stand in for a Python lockfile.
+ */
+const LOCKFILES = [
+  { file: 'package-lock.json', manager: 'npm', ecosystem: 'npm' },
+  { file: 'npm-shrinkwrap.json', manager: 'npm', ecosystem: 'npm' },
+  { file: 'pnpm-lock.yaml', manager: 'pnpm', ecosystem: 'npm' },
+  { file: 'yarn.lock', manager: 'yarn', ecosystem: 'npm' },
+  { file: 'poetry.lock', manager: 'poetry', ecosystem: 'python' },
+  { file: 'uv.lock', manager: 'uv', ecosystem: 'python' },
+  { file: 'Pipfile.lock', manager: 'pipenv', ecosystem: 'python' },
+  { file: 'requirements.txt', manager: 'pip', ecosystem: 'python' },
+  { file: 'Cargo.lock', manager: 'cargo', ecosystem: 'rust' },
+  { file: 'go.mod', manager: 'go', ecosystem: 'go' },
+  { file: 'Gemfile.lock', manager: 'bundler', ecosystem: 'ruby' }
+];
+
+/**
+ * Registry endpoints for a package version, and where the license sits in the response
+ * Go modules have no registry license field; they rely on the module cache.
+ */
+const REGISTRIES = {
+  npm: {
+    url: dep => `https://registry.npmjs.org/${dep.name.replace('/', '%2F')}/${encodeURIComponent(dep.version)}`,
+    license: body => body.license || body.licenses
+  },
+  python: {
+    url: dep => `https://pypi.org/pypi/${encodeURIComponent(dep.name)}/${encodeURIComponent(dep.version)}/json`,
+    license: body => pythonLicense(body.info && {
+      expression: body.info.license_expression,
+      license: body.info.license,
+      classifiers: body.info.classifiers
+    })
+  },
+  rust: {
+    url: dep => `https://crates.io/api/v1/crates/${encodeURIComponent(dep.name)}/${encodeURIComponent(dep.version)}`,
+    license: body => body.version && body.version.license
+  },
+  ruby: {
+    url: dep => `https://rubygems.org/api/v2/rubygems/${encodeURIComponent(dep.name)}/versions/${encodeURIComponent(dep.version)}.json`,
+    license: body => body.licenses
+  }
+};
+
+/**
+ * Lists installed Python distributions with their license metadata
+ */
+const PYTHON_METADATA_SCRIPT = [
+  'import json, importlib.metadata as m',
+  'out = {}',
+  'for d in m.distributions():',
+  '    md = d.metadata',
+  "    if md['Name']: out[md['Name']] = {'version': d.version, 'expression': md.get('License-Expression'), 'license': md.get('License'), 'classifiers': md.get_all('Classifier') or []}",
+  'print(json.dumps(out))'
+].join('\n');
+
+/**
+ * Run a command and return stdout
+ * @param {string} basePath - Project root
+ * @param {string[]} argv - Command and arguments
+ * @returns {string|null} null when the command fails
+ */
+function run(basePath, argv) {
+  return exec.run(basePath, argv, { timeout: 120000 });
+}
+
+/**
+ * Read a file
+ * @param {string} file - Absolute path
+ * @returns {string|null}
+ */
+function readFile(file) {
+  try {
+    return fs.readFileSync(file, 'utf8');
+  } catch {
+    return null;
+  }
+}
+
+/**
+ * GET JSON from a package registry
+ * @param {string} url - Request URL
+ * @returns {Promise<Object|null>} null when the version is not found
+ */
+function registryRequest(url) {
+  return new Promise((resolve, reject) => {
+    const req = https.get(url, {
+      headers: { 'Accept': 'application/json', 'User-Agent': 'awesome-slash-license-check' },
+      timeout: 30000
+    }, res => {
+      let data = '';
+      res.setEncoding('utf8');
+      res.on('data', chunk => { data += chunk; });
+      res.on('end', () => {
+        if (res.statusCode === 404) {
+          resolve(null);
+          return;
+        }
+        if (res.statusCode >= 400) {
+          reject(new Error(`${url} returned ${res.statusCode}`));
+          return;
+        }
+        try {
+          resolve(JSON.parse(data));
+        } catch (error) {
+          reject(error);
+        }
+      });
+    });
+    req.on('timeout', () => req.destroy(new Error(`${url} timed out`)));
+    req.on('error', reject);
+  });
+}
+
+/**
+ * Packages in package-lock.json or npm-shrinkwrap.json
+ * Lockfile v2/v3 `packages` entries carry the license; v1 nests `dependencies`.
+ * @param {string} content - Lockfile content
+ * @returns {Array<{name: string, version: string, dev: boolean, license?: string, path?: string}>}
+ */
+function parsePackageLock(content) {
+  let lock;
+  try {
+    lock = JSON.parse(content);
+  } catch {
+    return [];
+  }
+  const packages = [];
+  if (lock.packages) {
+    for (const [key, entry] of Object.entries(lock.packages)) {
+      if (!key || entry.link || !entry.version) continue;
+      const name = entry.name || key.slice(key.lastIndexOf('node_modules/') + 'node_modules/'.length);
+      packages.push({ name, version: entry.version, dev: Boolean(entry.dev), license: entry.license, path: key });
+    }
+    return packages;
+  }
+  const walk = (dependencies, prefix) => {
+    for (const [name, entry] of Object.entries(dependencies || {})) {
+      if (!entry.version) continue;
+      const key = `${prefix}node_modules/${name}`;
+      packages.push({ name, version: entry.version, dev: Boolean(entry.dev), path: key });
+      walk(entry.dependencies, `${key}/`);
+    }
+  };
+  walk(lock.dependencies, '');
+  return packages;
+}
+
+/**
+ * Packages in yarn.lock (classic and berry)
+ * @param {string} content - Lockfile content
+ * @returns {Array<{name: string, version: string, dev: boolean}>}
+ */
+function parseYarnLock(content) {
+  const packages = [];
+  for (const block of String(content || '').split(/\n\s*\n/)) {
+    const header = block.split('\n').find(line => line && !/^[\s#]/.test(line));
+    if (!header || header.startsWith('__metadata')) continue;
+    const spec = header.split(',')[0].trim().replace(/^"|"?:?$/g, '').replace(/"$/, '');
+    if (/@(?:workspace|link|portal|file):/.test(spec)) continue;
+    const name = spec.slice(0, spec.indexOf('@', 1));
+    const version = block.match(/^\s+version:?\s+"?([^"\s]+)"?/m);
+    if (name && version) packages.push({ name, version: version[1], dev: false });
+  }
+  return packages;
+}
+
+/**
+ * Packages in pnpm-lock.yaml
+ * Keys look like `/react@18.2.0:` (v6), `react@18.2.0(peer):` (v9), or `/react/18.2.0:` (v5).
+ * @param {string} content - Lockfile content
+ * @returns {Array<{name: string, version: string, dev: boolean}>}
+ */
+function parsePnpmLock(content) {
+  const packages = [];
+  let inPackages = false;
+  let current = null;
+  for (const line of String(content || '').split('\n')) {
+    if (/^\S/.test(line)) {
+      inPackages = /^packages:\s*$/.test(line);
+      current = null;
+      continue;
+    }
+    if (!inPackages) continue;
+    const key = line.match(/^ {2}['"]?\/?([^\s'"]+?)['"]?:\s*$/);
+    if (key) {
+      const id = key[1].replace(/\(.*$/, '');
+      const match = id.match(/^(@?[^@]+)@(\d[^_]*)/) || id.match(/^((?:@[^/]+\/)?[^/@]+)\/(\d[^_/]*)/);
+      current = match ? { name: match[1], version: match[2], dev: false } : null;
+      if (current) packages.push(current);
+      continue;
+    }
+    if (current && /^ {4}dev:\s*true\s*$/.test(line)) current.dev = true;
+  }
+  return packages;
+}
+
+/**
+ * `[[package]]` entries in a TOML lockfile (poetry.lock, uv.lock, Cargo.lock)
+ * @param {string} content - Lockfile content
+ * @returns {Array<{name: string, version: string, dev: boolean, source: string|null}>}
+ */
+function parseTomlPackages(content) {
+  const packages = [];
+  for (const block of String(content || '').split(/^\[\[package\]\]\s*$/m).slice(1)) {
+    const body = block.split(/^\[/m)[0];
+    const name = body.match(/^name\s*=\s*"([^"]+)"/m);
+    const version = body.match(/^version\s*=\s*"([^"]+)"/m);
+    if (!name || !version) continue;
+    const source = body.match(/^source\s*=\s*(.+)$/m);
+    const category = body.match(/^category\s*=\s*"([^"]+)"/m);
+    const groups = body.match(/^groups\s*=\s*\[([^\]]*)\]/m);
+    const dev = Boolean((category && category[1] === 'dev') || (groups && !/"main"/.test(groups[1])));
+    packages.push({ name: name[1], version: version[1], dev, source: source ? source[1].trim() : null });
+  }
+  return packages;
+}
+
+/**
+ * Packages in Pipfile.lock
+ * @param {string} content - Lockfile content
+ * @returns {Array<{name: string, version: string, dev: boolean}>}
+ */
+function parsePipfileLock(content) {
+  let lock;
+  try {
+    lock = JSON.parse(content);
+  } catch {
+    return [];
+  }
+  const packages = [];
+  for (const [section, dev] of [['default', false], ['develop', true]]) {
+    for (const [name, entry] of Object.entries(lock[section] || {})) {
+      if (entry && typeof entry.version === 'string') packages.push({ name, version: entry.version.replace(/^==/, ''), dev });
+    }
+  }
+  return packages;
+}
+
+/**
+ * Pinned (`==`) requirements
+ * @param {string} content - requirements.txt content
+ * @returns {Array<{name: string, version: string, dev: boolean}>}
+ */
+function parseRequirements(content) {
+  return String(content || '').split('\n')
+    .map(line => line.replace(/#.*/, '').match(/^\s*([A-Za-z0-9][\w.-]*)(?:\[[^\]]*\])?\s*==\s*([\w.+!-]+)/))
+    .filter(Boolean)
+    .map(match => ({ name: match[1], version: match[2], dev: false }));
+}
+
+/**
+ * Gems in the GEM section of Gemfile.lock
+ * @param {string} content - Lockfile content
+ * @returns {Array<{name: string, version: string, dev: boolean}>}
+ */
+function parseGemfileLock(content) {
+  const packages = [];
+  let inGem = false;
+  for (const line of String(content || '').split('\n')) {
+    if (/^\S/.test(line)) inGem = line.trim() === 'GEM';
+    const match = inGem && line.match(/^ {4}([^\s(]+) \(([^)]+)\)\s*$/);
+    if (match) packages.push({ name: match[1], version: match[2].replace(/-(?:x86|arm|aarch|universal|java|x64)[\w-]*$/, ''), dev: false });
+  }
+  return packages;
+}
+
+/**
+ * Packages from one lockfile
+ * Cargo and uv workspace members (no registry source) are the project itself and are skipped.
+ * @param {string} file - Lockfile name
+ * @param {string} content - Lockfile content
+ * @returns {Array<{name: string, version: string, dev: boolean}>}
+ */
+function parseLockfile(file, content) {
+  switch (file) {
+    case 'package-lock.json':
+    case 'npm-shrinkwrap.json':
+      return parsePackageLock(content);
+    case 'pnpm-lock.yaml':
+      return parsePnpmLock(content);
+    case 'yarn.lock':
+      return parseYarnLock(content);
+    case 'poetry.lock':
+      return parseTomlPackages(content).map(({ source, ...dep }) => dep);
+    case 'uv.lock':
+    case 'Cargo.lock':
+      return parseTomlPackages(content)
+        .filter(dep => dep.source && !/\b(?:editable|virtual|path)\s*=/.test(dep.source))
+        .map(({ source, ...dep }) => dep);
+    case 'Pipfile.lock':
+      return parsePipfileLock(content);
+    case 'requirements.txt':
+      return parseRequirements(content);
+    case 'go.mod':
+      return [...parseGoRequires(content)].map(([name, { version }]) => ({ name, version, dev: false }));
+    case 'Gemfile.lock':
+      return parseGemfileLock(content);
+    default:
+      return [];
+  }
+}
+
+/**
+ * Lockfiles in the project root, one per ecosystem
+ * @param {string} basePath - Project root
+ * @returns {Array<{file: string, manager: string, ecosystem: string}>}
+ */
+function detectLockfiles(basePath) {
+  const found = [];
+  for (const entry of LOCKFILES) {
+    if (found.some(existing => existing.ecosystem === entry.ecosystem)) continue;
+    if (fs.existsSync(path.join(basePath, entry.file))) found.push({ ...entry });
+  }
+  return found;
+}
+
+/**
+ * Normalize a license value to an SPDX expression
+ * Accepts SPDX expressions, free-form names ("Apache Software License",
+ * "GPLv3"), Python classifiers, `{type}` objects, and arrays (alternatives,
+ * joined with OR).
+ * @param {string|Object|Array} value - License value from package metadata
+ * @returns {string|null} null when there is no usable license
+ */
+function normalizeLicense(value) {
+  if (Array.isArray(value)) {
+    const licenses = [...new Set(value.map(normalizeLicense).filter(Boolean))];
+    if (!licenses.length) return null;
+    return licenses.length === 1 ? licenses[0] : licenses.map(license => (/\s/.test(license) ? `(${license})` : license)).join(' OR ');
+  }
+  if (value && typeof value === 'object') return normalizeLicense(value.type || value.name);
+  if (typeof value !== 'string') return null;
+
+  let text = value.trim();
+  if (text.startsWith('License ::')) text = text.split('::').pop().trim();
+  if (!text || /^(?:UNKNOWN|NOASSERTION|NONE|OSI Approved)$/i.test(text) || /^SEE LICEN[CS]E IN/i.test(text)) return null;
+  if (/^UNLICENSED$/i.test(text)) return 'UNLICENSED';
+
+  // Older Cargo manifests write alternatives with a slash: "MIT/Apache-2.0"
+  if (/^[\w.+-]+(?:\s*\/\s*[\w.+-]+)+$/.test(text) && !aliasOf(text)) {
+    return normalizeLicense(text.split('/').map(part => part.trim()));
+  }
+  if (isExpression(text)) {
+    return text.match(/\(|\)|[^\s()]+/g)
+      .map(token => (/^(?:OR|AND|WITH)$/i.test(token) ? token.toUpperCase() : (token.includes('-') ? token : aliasOf(token) || token)))
+      .join(' ')
+      .replace(/\( /g, '(')
+      .replace(/ \)/g, ')');
+  }
+  if (text.length > 200) return classifyLicenseText(text) || text.slice(0, 40).trim();
+
+  // Classifiers put the short form in parentheses: "GNU General Public License v3 (GPLv3)"
+  const short = text.match(/\(([^()]+)\)\s*$/);
+  return (short && aliasOf(short[1])) || aliasOf(text) || text;
+}
+
+/**
+ * SPDX id for a free-form license name
+ * @param {string} name - License name
+ * @returns {string|null}
+ */
+function aliasOf(name) {
+  const cleaned = name.toLowerCase()
+    .replace(/\b(?:the|license|licence|version|software)\b/g, ' ')
+    .replace(/,/g, ' ')
+    .replace(/\s+/g, ' ')
+    .trim();
+  const found = LICENSE_ALIASES.find(([pattern]) => pattern.test(cleaned));
+  return found ? found[1] : null;
+}
+
+/**
+ * Whether text is an SPDX expression: ids joined by OR/AND/WITH, or a single id
+ * @param {string} text - License text
+ * @returns {boolean}
+ */
+function isExpression(text) {
+  const words = (String(text).match(/[^\s()]+/g) || []);
+  return words.length > 0 && words.every((word, i) => (i % 2 === 1
+    ? /^(?:OR|AND|WITH)$/i.test(word)
+    : /^[A-Za-z0-9][\w.+-]*$/.test(word) && !/^(?:OR|AND|WITH)$/i.test(word)));
+}
+
+/**
+ * License from Python package metadata
+ * `License-Expression` wins, then specific classifiers, then the `License` field.
+ * @param {{expression?: string, license?: string, classifiers?: string[]}} metadata
+ * @returns {string|null}
+ */
+function pythonLicense(metadata) {
+  if (!metadata) return null;
+  const classifiers = (metadata.classifiers || []).filter(classifier => classifier.startsWith('License ::'));
+  return normalizeLicense(metadata.expression) ||
+    normalizeLicense(classifiers) ||
+    normalizeLicense(metadata.license);
+}
+
+/**
+ * Identify a license from its text
+ * @param {string} text - License file content
+ * @returns {string|null} SPDX id
+ */
+function classifyLicenseText(text) {
+  const collapsed = String(text || '').replace(/\s+/g, ' ');
+  const match = LICENSE_TEXTS.find(([, pattern]) => pattern.test(collapsed));
+  return match ? match[0] : null;
+}
+
+/**
+ * License of a package directory from its license files
+ * Several license files (`LICENSE-MIT`, `LICENSE-APACHE`) are combined with AND,
+ * so every one of them has to pass.
+ * @param {string} dir - Package directory
+ * @returns {string|null}
+ */
+function licenseFromDirectory(dir) {
+  let entries;
+  try {
+    entries = fs.readdirSync(dir);
+  } catch {
+    return null;
+  }
+  const licenses = [...new Set(entries
+    .filter(entry => LICENSE_FILE.test(entry))
+    .sort()
+    .map(entry => classifyLicenseText(readFile(path.join(dir, entry))))
+    .filter(Boolean))];
+  return licenses.length ? licenses.join(' AND ') : null;
+}
+
+/**
+ * Parse an SPDX expression
+ * @param {string} expression - SPDX expression
+ * @returns {Object} Tree of `{op: 'OR'|'AND', args}` and `{id, exception}` nodes
+ */
+function parseExpression(expression) {
+  const tokens = String(expression).match(/\(|\)|[^\s()]+/g) || [];
+  let position = 0;
+  const peek = () => tokens[position];
+  const isOperator = (token, operator) => token && token.toUpperCase() === operator;
+
+  const parseAtom = () => {
+    if (peek() === '(') {
+      position++;
+      const node = parseOr();
+      if (peek() === ')') position++;
+      return node;
+    }
+    const node = { id: tokens[position++] || '', exception: null };
+    if (isOperator(peek(), 'WITH')) {
+      position++;
+      node.exception = tokens[position++] || null;
+    }
+    return node;
+  };
+  const parseBinary = (operator, parseOperand) => () => {
+    const args = [parseOperand()];
+    while (isOperator(peek(), operator)) {
+      position++;
+      args.push(parseOperand());
+    }
+    return args.length === 1 ? args[0] : { op: operator, args };
+  };
+  const parseAnd = parseBinary('AND', parseAtom);
+  const parseOr = parseBinary('OR', parseAnd);
+  return parseOr();
+}
+
+/**
+ * Category of an SPDX id
+ * @param {string} id - SPDX id
+ * @param {string|null} [exception] - WITH exception
+ * @returns {string} permissive, weak-copyleft, copyleft, proprietary, or unknown
+ */
+function categoryOf(id, exception = null) {
+  const match = CATEGORIES.find(([, pattern]) => pattern.test(id || ''));
+  const category = match ? match[0] : 'unknown';
+  return category === 'copyleft' && exception && LINKING_EXCEPTIONS.test(exception) ? 'weak-copyleft' : category;
+}
+
+/**
+ * Whether an SPDX id matches a policy entry
+ * Matching ignores case and the `-only`/`-or-later`/`+` suffixes; a trailing
+ * `*` matches any id with that prefix (`GPL-*`).
+ * @param {string} pattern - Policy entry
+ * @param {string} id - SPDX id
+ * @returns {boolean}
+ */
+function matchesLicense(pattern, id) {
+  const base = value => String(value).toLowerCase().replace(/(?:-only|-or-later|\+)$/, '');
+  if (pattern.endsWith('*')) return String(id).toLowerCase().startsWith(pattern.slice(0, -1).toLowerCase());
+  return base(pattern) === base(id);
+}
+
+/**
+ * Read the license policy from the project config
+ * `licenseCheck: {allow, deny, ignore, dev}`; `ignore` takes package names
+ * or `name@version`, and `dev: true` checks development-only packages too.
+ * @param {string} basePath - Project root
+ * @returns {{file: string|null, allow: string[], deny: string[], ignore: string[], dev: boolean, error: string|null}}
+ */
+function readPolicy(basePath) {
+  const { config, file, error } = loadConfig(basePath);
+  const policy = { file: null, allow: [], deny: [], ignore: [], dev: false, error };
+  const value = config[CONFIG_KEY];
+  if (error || value === undefined) return policy;
+
+  if (!value || typeof value !== 'object' || Array.isArray(value)) {
+    return { ...policy, error: `${file}: ${CONFIG_KEY} must be an object` };
+  }
+  for (const key of ['allow', 'deny', 'ignore']) {
+    if (value[key] === undefined) continue;
+    if (!Array.isArray(value[key]) || value[key].some(entry => typeof entry !== 'string')) {
+      return { ...policy, error: `${file}: ${CONFIG_KEY}.${key} must be an array of strings` };
+    }
+    policy[key] = value[key];
+  }
+  if (value.dev !== undefined && typeof value.dev !== 'boolean') {
+    return { ...policy, error: `${file}: ${CONFIG_KEY}.dev must be a boolean` };
+  }
+  return { ...policy, file, dev: Boolean(value.dev) };
+}
+
+/**
+ * Check a license expression against a policy
+ * OR takes the best alternative, AND the worst part.
+ * @param {string|null} expression - SPDX expression
+ * @param {Object} policy - Result of readPolicy
+ * @returns {{status: 'allowed'|'warning'|'denied', category: string, reason: string|null}}
+ */
+function evaluateLicense(expression, policy) {
+  if (!expression) return { status: 'denied', category: 'unknown', reason: 'no license found' };
+  if (!isExpression(expression)) {
+    const listed = policy.allow.some(pattern => matchesLicense(pattern, expression));
+    return listed ? { status: 'allowed', category: 'unknown', reason: null } : { status: 'denied', category: 'unknown', reason: `unrecognized license "${expression}"` };
+  }
+  const rank = { allowed: 0, warning: 1, denied: 2 };
+
+  const evaluate = node => {
+    if (node.op) {
+      const results = node.args.map(evaluate).sort((a, b) => rank[a.status] - rank[b.status]);
+      return node.op === 'OR' ? results[0] : results[results.length - 1];
+    }
+    const category = categoryOf(node.id, node.exception);
+    const names = node.exception ? [node.id, `${node.id} WITH ${node.exception}`] : [node.id];
+    const matches = list => list.some(pattern => names.some(name => matchesLicense(pattern, name)));
+    if (matches(policy.deny)) return { status: 'denied', category, reason: `${node.id} is denied by policy` };
+    if (matches(policy.allow)) return { status: 'allowed', category, reason: null };
+    if (policy.allow.length) return { status: 'denied', category, reason: `${node.id} is not in the allow list` };
+    if (category === 'unknown') return { status: 'denied', category, reason: `unrecognized license ${node.id}` };
+    if (FAILING_CATEGORIES.includes(category)) return { status: 'denied', category, reason: `${category} license` };
+    if (category === 'weak-copyleft') return { status: 'warning', category, reason: 'weak copyleft license' };
+    return { status: 'allowed', category, reason: null };
+  };
+  return evaluate(parseExpression(expression));
+}
+
+/**
+ * Module cache directory of a Go module version
+ * Upper-case letters are escaped as `!` plus the lower-case letter.
+ * @param {string} modCache - GOMODCACHE
+ * @param {string} module - Module path
+ * @param {string} version - Module version
+ * @returns {string}
+ */
+function goModuleDir(modCache, module, version) {
+  const escape = value => value.replace(/[A-Z]/g, letter => `!${letter.toLowerCase()}`);
+  return path.join(modCache, `${escape(module)}@${escape(version)}`);
+}
+
+/**
+ * Fill in licenses from installed packages and license files
+ * @param {string} basePath - Project root
+ * @param {Object[]} packages - Packages with `ecosystem`, `name`, `version`
+ * @param {Function} [runCommand] - `(basePath, argv) => stdout|null`
+ */
+function resolveLocalLicenses(basePath, packages, runCommand = run) {
+  const set = (dep, value, source) => {
+    const license = normalizeLicense(value);
+    if (license) Object.assign(dep, { license, source });
+    return Boolean(license);
+  };
+  const missing = ecosystem => packages.filter(dep => dep.ecosystem === ecosystem && !dep.license);
+
+  for (const dep of missing('npm')) {
+    const dir = path.join(basePath, dep.path || `node_modules/${dep.name}`);
+    let pkg = {};
+    try {
+      pkg = JSON.parse(readFile(path.join(dir, 'package.json')) || '{}');
+    } catch {
+      pkg = {};
+    }
+    if (!set(dep, pkg.license || pkg.licenses, 'installed')) set(dep, licenseFromDirectory(dir), 'license-file');
+  }
+
+  if (missing('python').length) {
+    const python = ['.venv/bin/python', 'venv/bin/python'].find(file => fs.existsSync(path.join(basePath, file)));
+    let installed = {};
+    try {
+      installed = JSON.parse(runCommand(basePath, [python ? path.join(basePath, python) : 'python3', '-c', PYTHON_METADATA_SCRIPT]) || '{}');
+    } catch {
+      installed = {};
+    }
+    const byName = new Map(Object.entries(installed).map(([name, metadata]) => [normalizePythonName(name), metadata]));
+    for (const dep of missing('python')) set(dep, pythonLicense(byName.get(normalizePythonName(dep.name))), 'installed');
+  }
+
+  if (missing('rust').length) {
+    let metadata = {};
+    try {
+      metadata = JSON.parse(runCommand(basePath, ['cargo', 'metadata', '--format-version', '1', '--locked']) || '{}');
+    } catch {
+      metadata = {};
+    }
+    const byId = new Map((metadata.packages || []).map(pkg => [`${pkg.name}@${pkg.version}`, pkg]));
+    for (const dep of missing('rust')) {
+      const pkg = byId.get(`${dep.name}@${dep.version}`);
+      if (pkg && !set(dep, pkg.license, 'installed') && pkg.manifest_path) {
+        set(dep, licenseFromDirectory(path.dirname(pkg.manifest_path)), 'license-file');
+      }
+    }
+  }
+
+  if (missing('go').length) {
+    const modCache = (runCommand(basePath, ['go', 'env', 'GOMODCACHE']) || '').trim();
+    if (modCache) {
+      for (const dep of missing('go')) set(dep, licenseFromDirectory(goModuleDir(modCache, dep.name, dep.version)), 'license-file');
+    }
+  }
+
+  if (missing('ruby').length) {
+    const gemDir = (runCommand(basePath, ['gem', 'env', 'gemdir']) || '').trim();
+    if (gemDir) {
+      for (const dep of missing('ruby')) {
+        const spec = readFile(path.join(gemDir, 'specifications', `${dep.name}-${dep.version}.gemspec`)) || '';
+        const licenses = spec.match(/\.licenses?\s*=\s*(.+)/);
+        if (licenses) set(dep, licenses[1].match(/"([^"]+)"/g) ? licenses[1].match(/"([^"]+)"/g).map(item => item.slice(1, -1)) : null, 'installed');
+      }
+    }
+  }
+}
+
+/**
+ * Fill in remaining licenses from package registries
+ * @param {Object[]} packages - Packages with `ecosystem`, `name`, `version`
+ * @param {Function} [request] - `(url) => Promise<Object|null>`
+ * @param {number} [concurrency=8] - Parallel requests
+ * @returns {Promise<string[]>} Lookup errors
+ */
+async function resolveRegistryLicenses(packages, request = registryRequest, concurrency = 8) {
+  const queue = packages.filter(dep => !dep.license && REGISTRIES[dep.ecosystem]);
+  const errors = [];
+  const worker = async () => {
+    while (queue.length) {
+      const dep = queue.shift();
+      const registry = REGISTRIES[dep.ecosystem];
+      try {
+        const body = await request(registry.url(dep));
+        const license = body ? normalizeLicense(registry.license(body)) : null;
+        if (license) Object.assign(dep, { license, source: 'registry' });
+      } catch (error) {
+        errors.push(`${dep.name}@${dep.version}: ${error.message}`);
+      }
+    }
+  };
+  await Promise.all(Array.from({ length: concurrency }, worker));
+  return errors;
+}
+
+/**
+ * Check every locked package against the license policy
+ * @param {string} basePath - Project root
+ * @param {Object} [options]
+ * @param {boolean} [options.offline] - Skip registry lookups
+ * @param {Function} [options.run] - Command runner, for tests
+ * @param {Function} [options.request] - Registry request, for tests
+ * @returns {Promise<Object>} `{success, lockfiles, policy, packages, violations, warnings, ignored, licenses, errors}`
+ */
+async function checkLicenses(basePath, options = {}) {
+  const policy = readPolicy(basePath);
+  if (policy.error) return { success: false, error: policy.error };
+
+  const lockfiles = detectLockfiles(basePath);
+  if (!lockfiles.length) {
+    return { success: false, error: `No lockfile found. Looked for ${LOCKFILES.map(entry => entry.file).join(', ')}.` };
+  }
+
+  const seen = new Set();
+  const packages = [];
+  let ignored = 0;
+  for (const lockfile of lockfiles) {
+    const locked = parseLockfile(lockfile.file, readFile(path.join(basePath, lockfile.file)) || '');
+    lockfile.count = 0;
+    for (const dep of locked) {
+      const key = `${lockfile.ecosystem}:${dep.name}@${dep.version}`;
+      if (seen.has(key) || (dep.dev && !policy.dev)) continue;
+      seen.add(key);
+      if (policy.ignore.includes(dep.name) || policy.ignore.includes(`${dep.name}@${dep.version}`)) {
+        ignored++;
+        continue;
+      }
+      lockfile.count++;
+      const license = normalizeLicense(dep.license);
+      packages.push({
+        ecosystem: lockfile.ecosystem,
+        manager: lockfile.manager,
+        name: dep.name,
+        version: dep.version,
+        dev: dep.dev,
+        path: dep.path,
+        license,
+        source: license ? 'lockfile' : null
+      });
+    }
+  }
+
+  resolveLocalLicenses(basePath, packages, options.run);
+  const errors = options.offline ? [] : await resolveRegistryLicenses(packages, options.request);
+
+  const checked = packages.map(({ path: lockPath, ...dep }) => ({ ...dep, ...evaluateLicense(dep.license, policy) }));
+  const licenses = {};
+  for (const dep of checked) {
+    const license = dep.license || 'unknown';
+    licenses[license] = (licenses[license] || 0) + 1;
+  }
+  const order = (a, b) => a.ecosystem.localeCompare(b.ecosystem) || a.name.localeCompare(b.name);
+
+  return {
+    success: true,
+    lockfiles,
+    policy: { file: policy.file, allow: policy.allow, deny: policy.deny, ignore: policy.ignore, dev: policy.dev },
+    offline: Boolean(options.offline),
+    packages: checked,
+    violations: checked.filter(dep => dep.status === 'denied').sort(order),
+    warnings: checked.filter(dep => dep.status === 'warning').sort(order),
+    ignored,
+    licenses,
+    errors
+  };
+}
+
+/**
+ * Render the report as markdown
+ * @param {Object} report - Result of checkLicenses
+ * @returns {string}
+ */
+function renderReport(report) {
+  const lines = ['## License Check', ''];
+  lines.push(`**Lockfiles**: ${report.lockfiles.map(lockfile => `${lockfile.file} (${lockfile.count})`).join(', ')}`);
+  lines.push(`**Packages**: ${report.packages.length} checked${report.ignored ? `, ${report.ignored} ignored` : ''} | **Violations**: ${report.violations.length} | **Warnings**: ${report.warnings.length}`);
+  const policy = report.policy.file
+    ? `${report.policy.file} (allow ${report.policy.allow.length || 'any'}, deny ${report.policy.deny.length})`
+    : 'default (copyleft, proprietary, and unknown licenses fail; weak copyleft warns)';
+  lines.push(`**Policy**: ${policy}${report.policy.dev ? '; dev dependencies included' : ''}`, '');
+
+  const table = (title, deps) => {
+    if (!deps.length) return;
+    lines.push(`### ${title}`, '', '| Package | Version | Ecosystem | License | Reason |', '|---------|---------|-----------|---------|--------|');
+    for (const dep of deps) lines.push(`| \`${dep.name}\` | ${dep.version} | ${dep.ecosystem} | ${dep.license || 'unknown'} | ${dep.reason} |`);
+    lines.push('');
+  };
+  table('Violations', report.violations);
+  table('Warnings', report.warnings);
+
+  const counts = Object.entries(report.licenses).sort((a, b) => b[1] - a[1] || a[0].localeCompare(b[0]));
+  if (counts.length) {
+    lines.push('### Licenses', '', '| License | Packages |', '|---------|----------|');
+    for (const [license, count] of counts) lines.push(`| ${license} | ${count} |`);
+    lines.push('');
+  }
+  if (report.offline && report.violations.some(dep => !dep.license)) {
+    lines.push('Registry lookups were skipped (`--offline`); packages that are not installed show as unknown.', '');
+  }
+  if (report.errors.length) {
+    lines.push(`**Registry errors**: ${report.errors.length} (${report.errors.slice(0, 3).join('; ')})`, '');
+  }
+  if (!report.violations.length && !report.warnings.length) {
+    lines.push(`All ${report.packages.length} packages pass the license policy.`);
+  }
+  return lines.join('\n');
+}
+
+if (require.main === module) {
+  checkLicenses(process.cwd(), { offline: process.argv.includes('--offline') }).then(report => {
+    const indent = process.stdout.isTTY ? 2 : 0;
+    console.log(JSON.stringify(report, null, indent));
+    if (!report.success) process.exitCode = 1;
+    else if (report.violations.length) process.exitCode = 2;
+  });
+}
+
+module.exports = {
+  CONFIG_KEY,
+  FAILING_CATEGORIES,
+  LOCKFILES,
+  parsePackageLock,
+  parseYarnLock,
+  parsePnpmLock,
+  parseTomlPackages,
+  parsePipfileLock,
+  parseGemfileLock,
+  parseLockfile,
+  detectLockfiles,
+  normalizeLicense,
+  isExpression,
+  classifyLicenseText,
+  parseExpression,
+  categoryOf,
+  matchesLicense,
+  readPolicy,
+  evaluateLicense,
+  resolveLocalLicenses,
+  resolveRegistryLicenses,
+  checkLicenses,
+  renderReport
+};
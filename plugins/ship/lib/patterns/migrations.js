@@ -0,0 +1,177 @@
+/**
+ * Migration Safety Helpers
+ *
+ * Support for the `migrations` review pattern set: recognizing migration
+ * files in a diff, and cross-checking columns a migration drops against the
+ * rest of the codebase. Files to search come from the repo map when one is
+ * cached, so the check covers the same files the map indexes. A dropped
+ * column that code still reads breaks the deploy window between migrating
+ * and shipping the code that stops using it.
+ *
+ * Usage:
+ *   node migrations.js <migration-file>... > dropped-columns.json
+ *
+ * @module patterns/migrations
+ * @author Avi Fenesh
+ * @license MIT
+ */
+
+const fs = require('fs');
+const path = require('path');
+
+/**
+ * Migration locations: Rails, Django, Alembic, Prisma, Flyway, Knex/Sequelize, Supabase, Goose
+ */
+const MIGRATION_PATH_PATTERNS = [
+  /(?:^|\/)db\/migrate\/[^/]+\.rb$/,
+  /(?:^|\/)migrations\/(?!__init__\.py$)[^/]+\.(?:py|sql|js|ts|cjs|mjs|go)$/,
+  /(?:^|\/)alembic\/versions\/[^/]+\.py$/,
+  /(?:^|\/)prisma\/migrations\/[^/]+\/migration\.sql$/,
+  /(?:^|\/)[VU]\d+(?:[._]\d+)*__[^/]+\.sql$/,
+  /(?:^|\/)db\/migrations?\/[^/]+$/
+];
+
+/**
+ * Schema snapshots that list every column and are regenerated by migrations
+ */
+const SCHEMA_DUMPS = /(?:^|\/)(?:schema\.rb|structure\.sql|schema\.prisma|[^/]*\.snap)$/;
+
+/**
+ * Statements that drop a column, per migration dialect
+ * Each yields the table (when known) and the column
+ */
+const DROP_COLUMN_PATTERNS = [
+  // Rails: remove_column :users, :email
+  { regex: /\bremove_column\s*\(?\s*:?["']?(\w+)["']?\s*,\s*:?["']?(\w+)/g, table: 1, column: 2 },
+  // Rails: t.remove :email inside change_table
+  { regex: /\bt\.remove\s+:(\w+)/g, column: 1 },
+  // Alembic: op.drop_column('users', 'email')
+  { regex: /\bop\.drop_column\(\s*["'](\w+)["']\s*,\s*["'](\w+)["']/g, table: 1, column: 2 },
+  // Django: migrations.RemoveField(model_name='user', name='email')
+  { regex: /\bRemoveField\(\s*model_name\s*=\s*["'](\w+)["']\s*,\s*name\s*=\s*["'](\w+)["']/g, table: 1, column: 2 },
+  // SQL: ALTER TABLE users DROP COLUMN email
+  { regex: /\bALTER\s+TABLE\s+(?:IF\s+EXISTS\s+)?(?:ONLY\s+)?["`]?([\w.]+)["`]?\s+DROP\s+(?:COLUMN\s+)?(?:IF\s+EXISTS\s+)?["`]?(\w+)["`]?/gi, table: 1, column: 2 },
+  // Knex/Laravel: table.dropColumn('email'), queryInterface.removeColumn('users', 'email')
+  { regex: /\.dropColumns?\(\s*["'](\w+)["']/g, column: 1 },
+  { regex: /\.removeColumn\(\s*["'](\w+)["']\s*,\s*["'](\w+)["']/g, table: 1, column: 2 }
+];
+
+/**
+ * Words SQL parsing can mistake for a column name
+ */
+const NOT_COLUMNS = new Set(['constraint', 'index', 'primary', 'foreign', 'default', 'not', 'column']);
+
+/**
+ * Check whether a path is a database migration
+ * @param {string} file - Repository-relative path
+ * @returns {boolean}
+ */
+function isMigrationFile(file) {
+  const normalized = String(file || '').replace(/\\/g, '/');
+  return MIGRATION_PATH_PATTERNS.some(pattern => pattern.test(normalized));
+}
+
+/**
+ * Columns a migration drops
+ * @param {string} content - Migration source
+ * @returns {Array<{table: string|null, column: string, line: number}>}
+ */
+function extractDroppedColumns(content) {
+  const dropped = [];
+  const seen = new Set();
+  for (const { regex, table, column } of DROP_COLUMN_PATTERNS) {
+    for (const match of content.matchAll(new RegExp(regex))) {
+      const name = match[column];
+      if (NOT_COLUMNS.has(name.toLowerCase())) continue;
+      const tableName = table ? match[table] : null;
+      const line = content.slice(0, match.index).split('\n').length;
+      const key = `${line}:${name}`;
+      if (seen.has(key)) continue;
+      seen.add(key);
+      dropped.push({ table: tableName, column: name, line });
+    }
+  }
+  return dropped.sort((a, b) => a.line - b.line);
+}
+
+/**
+ * Find code that still references columns dropped by migrations
+ *
+ * Matches the column name as a whole word outside migrations and schema
+ * dumps. Short or generic names (`id`, `name`) produce noise, so names under
+ * four characters are skipped.
+ *
+ * @param {string} repoPath - Repository root
+ * @param {string[]} migrationFiles - Migration files to check (relative to repoPath)
+ * @param {Object} [options]
+ * @param {Object} [options.map] - Repo map; its files are searched
+ * @param {string[]} [options.files] - Files to search when no map is available
+ * @param {Function} [options.readFile] - File reader (for testing)
+ * @returns {Array<{migration: string, line: number, table: string|null, column: string, references: Array<{file: string, line: number}>}>} Dropped columns with remaining references
+ */
+function findDroppedColumnReferences(repoPath, migrationFiles, options = {}) {
+  const readFile = options.readFile || (file => fs.readFileSync(path.isAbsolute(file) ? file : path.join(repoPath, file), 'utf8'));
+  const candidates = (options.map && options.map.files ? Object.keys(options.map.files) : options.files || [])
+    .filter(file => !isMigrationFile(file) && !SCHEMA_DUMPS.test(file));
+
+  const dropped = [];
+  for (const migration of migrationFiles) {
+    let content;
+    try {
+      content = readFile(migration);
+    } catch {
+      continue;
+    }
+    for (const entry of extractDroppedColumns(content)) {
+      if (entry.column.length >= 4) dropped.push({ migration, ...entry });
+    }
+  }
+  if (dropped.length === 0) return [];
+
+  const contents = new Map();
+  for (const file of candidates) {
+    try {
+      contents.set(file, readFile(file));
+    } catch {
+      // Deleted since the map was built
+    }
+  }
+
+  const results = [];
+  for (const entry of dropped) {
+    const word = new RegExp(`\\b${entry.column}\\b`);
+    const references = [];
+    for (const [file, content] of contents) {
+      if (!word.test(content)) continue;
+      content.split('\n').forEach((text, index) => {
+        if (word.test(text)) references.push({ file, line: index + 1 });
+      });
+    }
+    if (references.length > 0) results.push({ ...entry, references });
+  }
+  return results;
+}
+
+module.exports = {
+  MIGRATION_PATH_PATTERNS,
+  isMigrationFile,
+  extractDroppedColumns,
+  findDroppedColumnReferences
+};
+
+// CLI usage
+if (require.main === module) {
+  const files = process.argv.slice(2).filter(isMigrationFile);
+  const repoPath = process.cwd();
+  const map = require('../repo-map/cache').load(repoPath);
+  let searchFiles;
+  if (!map) {
+    const tracked = require('../utils/exec').git(repoPath, ['ls-files']);
+    if (tracked === null) {
+      console.error('Error: git ls-files failed; run inside a git repository or build a repo map first');
+      process.exit(1);
+    }
+    searchFiles = tracked.split('\n').filter(Boolean);
+  }
+  console.log(JSON.stringify(findDroppedColumnReferences(repoPath, files, { map, files: searchFiles }), null, 2));
+}
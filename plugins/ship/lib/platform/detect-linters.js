@@ -0,0 +1,547 @@
+#!/usr/bin/env node
+/**
+ * Linter and Formatter Detection
+ * Identifies the project's linters and formatters and reads their configs
+ * far enough to know which rules are enabled, so the slop pipeline can skip
+ * findings the project's own tooling already enforces
+ *
+ * Synchronous so `runPipeline` can call it directly.
+ *
+ * Usage: node lib/platform/detect-linters.js [path]
+ * Output: JSON with detected linters (or null)
+ *
+ * @module lib/platform/detect-linters
+ */
+
+const fs = require('fs');
+const path = require('path');
+
+const { LINTER_CONFIGS, PRE_COMMIT_HOOKS } = require('./detection-configs');
+
+/**
+ * Maximum config size to read (256KB)
+ */
+const MAX_CONFIG_SIZE_BYTES = 256 * 1024;
+
+/**
+ * Read a small text file relative to the project root
+ * @param {string} basePath - Project root
+ * @param {string} file - Relative path
+ * @returns {string|null} Content, or null if missing or too large
+ */
+function readText(basePath, file) {
+  try {
+    const filePath = path.join(basePath, file);
+    const stats = fs.statSync(filePath);
+    if (!stats.isFile() || stats.size > MAX_CONFIG_SIZE_BYTES) return null;
+    return fs.readFileSync(filePath, 'utf8');
+  } catch {
+    return null;
+  }
+}
+
+/**
+ * Parse JSON with comments and trailing commas (biome.jsonc, .eslintrc)
+ * @param {string} content - JSONC text
+ * @returns {Object|null}
+ */
+function parseJsonc(content) {
+  let out = '';
+  let inString = false;
+  for (let i = 0; i < content.length; i++) {
+    const ch = content[i];
+    if (inString) {
+      out += ch;
+      if (ch === '\\') out += content[++i] || '';
+      else if (ch === '"') inString = false;
+    } else if (ch === '"') {
+      inString = true;
+      out += ch;
+    } else if (ch === '/' && content[i + 1] === '/') {
+      while (i < content.length && content[i] !== '\n') i++;
+      out += '\n';
+    } else if (ch === '/' && content[i + 1] === '*') {
+      i = content.indexOf('*/', i + 2);
+      if (i === -1) break;
+      i++;
+    } else {
+      out += ch;
+    }
+  }
+  try {
+    return JSON.parse(out.replace(/,(\s*[}\]])/g, '$1'));
+  } catch {
+    return null;
+  }
+}
+
+/**
+ * Normalize a rule severity to off | warn | error
+ * @param {*} value - ESLint/Biome/Clippy level (string, number, array, or `{level}`)
+ * @returns {string}
+ */
+function normalizeLevel(value) {
+  const level = Array.isArray(value) ? value[0] : value && typeof value === 'object' ? value.level : value;
+  if (level === 0 || level === '0' || level === 'off' || level === 'allow') return 'off';
+  if (level === 2 || level === '2' || level === 'error' || level === 'deny' || level === 'forbid') return 'error';
+  return 'warn';
+}
+
+/**
+ * Read a string array from a TOML table
+ * @param {string} content - TOML text
+ * @param {string} table - Table name ('' for the root table)
+ * @param {string} key - Array key
+ * @returns {string[]|null} Values, or null when the key is absent
+ */
+function readTomlArray(content, table, key) {
+  let current = '';
+  let collecting = null;
+  for (const line of content.split('\n')) {
+    const text = line.replace(/#.*/, '').trim();
+    if (collecting) {
+      collecting.push(...Array.from(text.matchAll(/["']([^"']+)["']/g), match => match[1]));
+      if (text.includes(']')) return collecting;
+      continue;
+    }
+    const header = text.match(/^\[([^\]]+)\]$/);
+    if (header) {
+      current = header[1].trim();
+      continue;
+    }
+    const assignment = current === table && text.match(new RegExp(`^${key.replace(/[-]/g, '\\-')}\\s*=\\s*\\[(.*)$`));
+    if (assignment) {
+      collecting = Array.from(assignment[1].matchAll(/["']([^"']+)["']/g), match => match[1]);
+      if (assignment[1].includes(']')) return collecting;
+    }
+  }
+  return collecting;
+}
+
+/**
+ * Check whether a TOML document has a table (or a subtable of it)
+ * @param {string} content - TOML text
+ * @param {string} table - Table name
+ * @returns {boolean}
+ */
+function hasTomlTable(content, table) {
+  const escaped = table.replace(/\./g, '\\.');
+  return new RegExp(`^\\s*\\[${escaped}(?:\\.[^\\]]+)?\\]`, 'm').test(content);
+}
+
+/**
+ * Read a comma/newline separated list from an INI section (flake8)
+ * @param {string} content - INI text
+ * @param {string} section - Section name
+ * @param {string} key - Option name
+ * @returns {string[]|null} Values, or null when the option is absent
+ */
+function readIniList(content, section, key) {
+  let current = '';
+  let collecting = null;
+  for (const line of content.split('\n')) {
+    const text = line.replace(/[#;].*/, '');
+    const header = text.trim().match(/^\[([^\]]+)\]$/);
+    if (header) {
+      if (collecting) return collecting;
+      current = header[1].trim();
+      continue;
+    }
+    if (current !== section) continue;
+    if (collecting && /^\s+\S/.test(text)) {
+      collecting.push(...text.split(',').map(value => value.trim()).filter(Boolean));
+      continue;
+    }
+    if (collecting) return collecting;
+    const option = text.match(/^\s*([\w-]+)\s*[=:]\s*(.*)$/);
+    if (option && option[1].replace(/_/g, '-') === key) {
+      collecting = option[2].split(',').map(value => value.trim()).filter(Boolean);
+    }
+  }
+  return collecting;
+}
+
+/**
+ * Read ESLint rules and presets from a config file
+ * JSON configs are parsed; JS/YAML configs are scanned for `rule: level` pairs.
+ * @param {string} file - Config file name
+ * @param {string|Object} config - File content, or the package.json `eslintConfig` object
+ * @returns {{presets: string[], rules: Object<string, string>}}
+ */
+function parseEslintConfig(file, config) {
+  const rules = {};
+  const presets = [];
+  const json = typeof config === 'object' ? config
+    : (file.endsWith('.json') || file === '.eslintrc') ? parseJsonc(config) : null;
+
+  if (json) {
+    const extendsList = [].concat(json.extends || []);
+    if (extendsList.includes('eslint:recommended')) presets.push('recommended');
+    for (const [rule, value] of Object.entries(json.rules || {})) rules[rule] = normalizeLevel(value);
+    return { presets, rules };
+  }
+
+  if (/eslint:recommended|js\.configs\.recommended/.test(config)) presets.push('recommended');
+  const rulePattern = /["']?((?:@[\w-]+\/)?[\w-]+(?:\/[\w-]+)*)["']?\s*:\s*\[?\s*(?:["'](off|warn|error)["']|([012])\b)/g;
+  for (const match of config.matchAll(rulePattern)) {
+    rules[match[1]] = normalizeLevel(match[2] || Number(match[3]));
+  }
+  return { presets, rules };
+}
+
+/**
+ * Read Biome linter rules and formatter state
+ * @param {string} content - biome.json(c) content
+ * @returns {{lints: boolean, formats: boolean, presets: string[], rules: Object<string, string>}}
+ */
+function parseBiomeConfig(content) {
+  const json = parseJsonc(content) || {};
+  const linter = json.linter || {};
+  const ruleGroups = linter.rules || {};
+  const presets = [];
+  if (ruleGroups.recommended !== false) presets.push('recommended');
+  if (ruleGroups.all === true) presets.push('all');
+
+  const rules = {};
+  for (const [group, groupRules] of Object.entries(ruleGroups)) {
+    if (!groupRules || typeof groupRules !== 'object') continue;
+    for (const [rule, value] of Object.entries(groupRules)) {
+      if (rule === 'recommended' || rule === 'all') continue;
+      rules[`${group}/${rule}`] = normalizeLevel(value === 'on' || value === 'info' ? 'warn' : value);
+    }
+  }
+  return {
+    lints: linter.enabled !== false,
+    formats: (json.formatter || {}).enabled !== false,
+    presets,
+    rules
+  };
+}
+
+/**
+ * Read ruff rule selection from ruff.toml or pyproject.toml
+ * @param {string} content - TOML text
+ * @param {string} prefix - Root table ('' for ruff.toml, 'tool.ruff' for pyproject)
+ * @returns {{formats: boolean, select: string[], ignore: string[]}}
+ */
+function parseRuffConfig(content, prefix) {
+  const tables = [prefix ? `${prefix}.lint` : 'lint', prefix];
+  const read = key => {
+    for (const table of tables) {
+      const values = readTomlArray(content, table, key);
+      if (values) return values;
+    }
+    return null;
+  };
+  const defaults = LINTER_CONFIGS.ruff;
+  return {
+    formats: hasTomlTable(content, prefix ? `${prefix}.format` : 'format'),
+    select: [...(read('select') || defaults.select), ...(read('extend-select') || [])],
+    ignore: [...(read('ignore') || defaults.ignore), ...(read('extend-ignore') || [])]
+  };
+}
+
+/**
+ * Read flake8 rule selection from an INI section
+ * @param {string} content - INI text
+ * @returns {{select: string[], ignore: string[]}}
+ */
+function parseFlake8Config(content) {
+  const defaults = LINTER_CONFIGS.flake8;
+  return {
+    select: [...(readIniList(content, 'flake8', 'select') || defaults.select), ...(readIniList(content, 'flake8', 'extend-select') || [])],
+    ignore: [...(readIniList(content, 'flake8', 'ignore') || defaults.ignore), ...(readIniList(content, 'flake8', 'extend-ignore') || [])]
+  };
+}
+
+/**
+ * Read enabled and disabled linters from a golangci-lint config
+ * Handles v1 (`disable-all`/`enable-all`) and v2 (`default: none|all`) YAML or JSON.
+ * @param {string} file - Config file name
+ * @param {string} content - Config content
+ * @returns {{presets: string[], rules: Object<string, string>}}
+ */
+function parseGolangciConfig(file, content) {
+  let settings = {};
+  if (file.endsWith('.json')) {
+    settings = ((parseJsonc(content) || {}).linters) || {};
+  } else if (/\.ya?ml$/.test(file)) {
+    let inLinters = false;
+    let listKey = null;
+    let keyIndent = null;
+    for (const line of content.split('\n')) {
+      if (/^\s*(#|$)/.test(line)) continue;
+      if (/^\S/.test(line)) {
+        inLinters = /^linters:\s*$/.test(line);
+        listKey = null;
+        keyIndent = null;
+        continue;
+      }
+      if (!inLinters) continue;
+      const indent = line.match(/^\s*/)[0].length;
+      const item = line.match(/^\s*-\s*["']?([\w.-]+)/);
+      if (item && listKey && indent >= keyIndent) {
+        settings[listKey].push(item[1]);
+        continue;
+      }
+      const entry = line.match(/^\s*([\w-]+):\s*(.*)$/);
+      if (!entry || (keyIndent !== null && indent > keyIndent)) continue;
+      keyIndent = indent;
+      listKey = null;
+      const value = entry[2].replace(/#.*/, '').trim();
+      if (value === '') {
+        listKey = entry[1];
+        settings[listKey] = [];
+      } else if (value.startsWith('[')) {
+        settings[entry[1]] = value.replace(/[[\]"']/g, '').split(',').map(name => name.trim()).filter(Boolean);
+      } else {
+        settings[entry[1]] = value.replace(/["']/g, '');
+      }
+    }
+  }
+
+  const base = settings.default || (settings['disable-all'] === true || settings['disable-all'] === 'true' ? 'none'
+    : settings['enable-all'] === true || settings['enable-all'] === 'true' ? 'all' : 'standard');
+  const presets = base === 'all' ? ['all'] : base === 'none' ? [] : ['default'];
+  const rules = {};
+  for (const name of [].concat(settings.enable || [])) rules[name] = 'error';
+  for (const name of [].concat(settings.disable || [])) rules[name] = 'off';
+  return { presets, rules };
+}
+
+/**
+ * Read clippy lint levels from Cargo.toml lint tables and crate attributes
+ * @param {string|null} cargo - Cargo.toml content
+ * @param {string[]} crateRoots - Contents of src/lib.rs / src/main.rs
+ * @returns {Object<string, string>|null} Lint -> level, or null when none are configured
+ */
+function parseClippyLints(cargo, crateRoots) {
+  const rules = {};
+  let found = false;
+  if (cargo) {
+    let inTable = false;
+    for (const line of cargo.split('\n')) {
+      const text = line.replace(/#.*/, '').trim();
+      const header = text.match(/^\[([^\]]+)\]$/);
+      if (header) {
+        inTable = /^(?:workspace\.)?lints\.clippy$/.test(header[1].trim());
+        found = found || inTable;
+        continue;
+      }
+      const entry = inTable && text.match(/^([\w-]+)\s*=\s*(?:"(\w+)"|\{[^}]*level\s*=\s*"(\w+)")/);
+      if (entry) rules[entry[1].replace(/-/g, '_')] = normalizeLevel(entry[2] || entry[3]);
+    }
+  }
+  for (const content of crateRoots) {
+    for (const match of content.matchAll(/#!\[(allow|warn|deny|forbid)\(([^)]*)\)\]/g)) {
+      for (const lint of match[2].matchAll(/clippy::(\w+)/g)) {
+        rules[lint[1]] = normalizeLevel(match[1]);
+        found = true;
+      }
+    }
+  }
+  return found ? rules : null;
+}
+
+/**
+ * Tools run from .pre-commit-config.yaml hooks
+ * @param {string|null} content - pre-commit config
+ * @returns {{tools: Set<string>, hooks: Set<string>}}
+ */
+function parsePreCommitHooks(content) {
+  const hooks = new Set(content ? Array.from(content.matchAll(/^\s*-\s*id:\s*["']?([\w.-]+)/gm), match => match[1]) : []);
+  const tools = new Set();
+  for (const hook of hooks) {
+    if (PRE_COMMIT_HOOKS[hook]) tools.add(PRE_COMMIT_HOOKS[hook]);
+  }
+  return { tools, hooks };
+}
+
+/**
+ * Detect linters and formatters with their enabled rules
+ * @param {string} [basePath=process.cwd()] - Project root
+ * @returns {{linters: Object[]}|null} Each linter is `{name, language, lints, formats, sources, presets, rules}`
+ *   (ruff/flake8 use `select`/`ignore` code prefixes instead of `rules`); null when none are found
+ */
+function detectLinters(basePath = process.cwd()) {
+  const pkgContent = readText(basePath, 'package.json');
+  let pkg = null;
+  if (pkgContent) {
+    try {
+      pkg = JSON.parse(pkgContent);
+    } catch {
+      pkg = null;
+    }
+  }
+  const pyproject = readText(basePath, 'pyproject.toml');
+  const preCommit = parsePreCommitHooks(readText(basePath, '.pre-commit-config.yaml'));
+
+  const linters = [];
+  for (const [name, config] of Object.entries(LINTER_CONFIGS)) {
+    const sources = [];
+    let content = null;
+    let configFile = null;
+
+    for (const file of config.files) {
+      const text = readText(basePath, file);
+      if (text === null) continue;
+      sources.push(file);
+      if (content === null) {
+        content = text;
+        configFile = file;
+      }
+    }
+    if (config.packageKey && pkg && pkg[config.packageKey]) {
+      sources.push(`package.json#${config.packageKey}`);
+      if (content === null) {
+        content = pkg[config.packageKey];
+        configFile = 'package.json';
+      }
+    }
+    if (config.pyprojectTable && pyproject && hasTomlTable(pyproject, config.pyprojectTable)) {
+      sources.push('pyproject.toml');
+      if (content === null) {
+        content = pyproject;
+        configFile = 'pyproject.toml';
+      }
+    }
+    for (const [file, section] of Object.entries(config.iniSections || {})) {
+      const text = readText(basePath, file);
+      if (text && new RegExp(`^\\[${section}\\]`, 'm').test(text)) {
+        sources.push(file);
+        if (content === null) {
+          content = text;
+          configFile = file;
+        }
+      }
+    }
+
+    let clippyRules = null;
+    if (name === 'clippy') {
+      const roots = ['src/lib.rs', 'src/main.rs'].map(file => readText(basePath, file)).filter(Boolean);
+      clippyRules = parseClippyLints(readText(basePath, 'Cargo.toml'), roots);
+      if (clippyRules) sources.push('Cargo.toml');
+    }
+    if (preCommit.tools.has(name)) sources.push('.pre-commit-config.yaml');
+    if (sources.length === 0) continue;
+
+    const linter = {
+      name,
+      language: config.language,
+      lints: Boolean(config.lints),
+      formats: Boolean(config.formats),
+      sources,
+      presets: [],
+      rules: {}
+    };
+
+    if (name === 'eslint') {
+      Object.assign(linter, content !== null ? parseEslintConfig(configFile, content) : {});
+    } else if (name === 'biome') {
+      Object.assign(linter, content !== null ? parseBiomeConfig(content) : { presets: ['recommended'] });
+    } else if (name === 'ruff') {
+      const parsed = content !== null
+        ? parseRuffConfig(content, configFile === 'pyproject.toml' ? 'tool.ruff' : '')
+        : { formats: false, select: config.select, ignore: config.ignore };
+      linter.formats = parsed.formats || preCommit.hooks.has('ruff-format');
+      linter.select = parsed.select;
+      linter.ignore = parsed.ignore;
+      delete linter.rules;
+    } else if (name === 'flake8') {
+      const parsed = content !== null ? parseFlake8Config(content) : { select: config.select, ignore: config.ignore };
+      linter.select = parsed.select;
+      linter.ignore = parsed.ignore;
+      delete linter.rules;
+    } else if (name === 'golangci-lint') {
+      Object.assign(linter, content !== null ? parseGolangciConfig(configFile, content) : { presets: ['default'] });
+    } else if (name === 'clippy') {
+      linter.presets = ['default'];
+      linter.rules = clippyRules || {};
+    }
+
+    linters.push(linter);
+  }
+
+  return linters.length > 0 ? { linters } : null;
+}
+
+/**
+ * Check whether a linter enables a rule
+ * @param {Object} linter - Entry from `detectLinters`
+ * @param {string} rule - Rule id (ESLint/Biome name, ruff/flake8 code, golangci linter, clippy lint)
+ * @returns {boolean}
+ */
+function isRuleEnabled(linter, rule) {
+  if (!linter.lints) return false;
+
+  if (linter.select) {
+    // Most specific prefix wins, as in ruff and flake8. The letter part must
+    // name the same plugin (`E` is not a prefix of `ERA001`); `PL` covers Pylint's PLC/PLE/PLR/PLW.
+    const letters = code => code.match(/^[A-Z]*/)[0];
+    const matches = prefix => {
+      if (prefix === 'ALL') return true;
+      if (!rule.startsWith(prefix)) return false;
+      const family = letters(prefix);
+      return family === letters(rule) || (family === 'PL' && prefix === 'PL' && /^PL[CERW]$/.test(letters(rule)));
+    };
+    const longest = list => list
+      .filter(matches)
+      .reduce((max, prefix) => Math.max(max, prefix === 'ALL' ? 0 : prefix.length), -1);
+    const selected = longest(linter.select);
+    return selected >= 0 && selected > longest(linter.ignore);
+  }
+
+  const config = LINTER_CONFIGS[linter.name] || {};
+  const group = config.groups && config.groups[rule];
+  const level = linter.rules[rule] !== undefined ? linter.rules[rule] : group ? linter.rules[group] : undefined;
+  if (level !== undefined) return level !== 'off';
+
+  if (linter.presets.includes('all')) return true;
+  if (linter.presets.includes('recommended') && (config.recommended || []).includes(rule)) return true;
+  if (linter.presets.includes('default') && (config.defaults || []).includes(rule)) return true;
+  return false;
+}
+
+/**
+ * Find which detected tool already enforces a slop pattern for a language
+ * Entries are `tool` (the tool formats the file) or `tool:rule`; a nested
+ * array means every entry in it must be enforced.
+ * @param {Object[]} linters - `detectLinters(...).linters`
+ * @param {Array<string|string[]>} enforcedBy - Pattern `enforcedBy` list
+ * @param {string} language - slop-analyzer language key of the file
+ * @returns {string|null} The enforcing entry (joined with `+` for combinations), or null
+ */
+function findEnforcingRule(linters, enforcedBy, language) {
+  const enforced = token => {
+    const separator = token.indexOf(':');
+    const tool = separator === -1 ? token : token.slice(0, separator);
+    const linter = linters.find(candidate => candidate.name === tool && candidate.language === language);
+    if (!linter) return false;
+    return separator === -1 ? linter.formats : isRuleEnabled(linter, token.slice(separator + 1));
+  };
+
+  for (const entry of enforcedBy || []) {
+    const tokens = Array.isArray(entry) ? entry : [entry];
+    if (tokens.every(enforced)) return tokens.join('+');
+  }
+  return null;
+}
+
+// When run directly, output JSON
+if (require.main === module) {
+  const indent = process.stdout.isTTY ? 2 : 0;
+  console.log(JSON.stringify(detectLinters(process.argv[2] || process.cwd()), null, indent));
+}
+
+module.exports = {
+  detectLinters,
+  isRuleEnabled,
+  findEnforcingRule,
+  parseEslintConfig,
+  parseBiomeConfig,
+  parseRuffConfig,
+  parseFlake8Config,
+  parseGolangciConfig,
+  parseJsonc,
+  readText
+};
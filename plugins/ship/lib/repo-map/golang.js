@@ -0,0 +1,197 @@
+/**
+ * Go package-level method grouping and interface satisfaction
+ *
+ * Go methods may be declared in any file of the receiver's package, so
+ * grouping happens after extraction: methods are attached to their receiver
+ * types and each type lists the interfaces its method set satisfies.
+ *
+ * @module lib/repo-map/golang
+ */
+
+'use strict';
+
+const path = require('path');
+
+// Common standard library interfaces (name -> [params, results] per method)
+const WELL_KNOWN_INTERFACES = {
+  error: { Error: [0, 1] },
+  'fmt.Stringer': { String: [0, 1] },
+  'io.Reader': { Read: [1, 2] },
+  'io.Writer': { Write: [1, 2] },
+  'io.Closer': { Close: [0, 1] },
+  'sort.Interface': { Len: [0, 1], Less: [2, 1], Swap: [2, 0] },
+  'http.Handler': { ServeHTTP: [2, 0] },
+  'json.Marshaler': { MarshalJSON: [0, 2] },
+  'json.Unmarshaler': { UnmarshalJSON: [1, 1] }
+};
+
+/**
+ * Group Go files by package directory
+ * @param {Object} map - Repo map
+ * @returns {Map<string, string[]>}
+ */
+function groupPackages(map) {
+  const packages = new Map();
+  for (const [file, fileData] of Object.entries(map.files)) {
+    if (fileData.language !== 'go') continue;
+    const dir = path.posix.dirname(file);
+    if (!packages.has(dir)) packages.set(dir, []);
+    packages.get(dir).push(file);
+  }
+  return packages;
+}
+
+/**
+ * Check whether a method set contains every method of an interface
+ * @param {Map<string, number[]|undefined>} methodSet - Method name -> arity
+ * @param {Map<string, number[]|undefined>} required - Interface method name -> arity
+ * @returns {boolean}
+ */
+function satisfies(methodSet, required) {
+  if (required.size === 0) return false;
+  for (const [name, arity] of required) {
+    if (!methodSet.has(name)) return false;
+    const actual = methodSet.get(name);
+    if (arity && actual && (arity[0] !== actual[0] || arity[1] !== actual[1])) return false;
+  }
+  return true;
+}
+
+/**
+ * Resolve an interface's full method set, including embedded interfaces
+ * @param {Object} entry - Interface type entry
+ * @param {string} dir - Package directory of the interface
+ * @param {Object} index - `{byDir, qualified}` interface lookup
+ * @param {Set<Object>} [seen] - Guard against embedding cycles
+ * @returns {Map<string, number[]|undefined>}
+ */
+function resolveInterface(entry, dir, index, seen = new Set()) {
+  const methods = new Map();
+  if (seen.has(entry)) return methods;
+  seen.add(entry);
+
+  for (const method of entry.methods || []) methods.set(method.name, method.arity);
+  for (const embedded of entry.embeds || []) {
+    if (WELL_KNOWN_INTERFACES[embedded]) {
+      for (const [name, arity] of Object.entries(WELL_KNOWN_INTERFACES[embedded])) methods.set(name, arity);
+      continue;
+    }
+    const target = embedded.includes('.')
+      ? index.qualified.get(embedded)
+      : { entry: index.byDir.get(dir)?.get(embedded), dir };
+    if (!target || !target.entry) continue;
+    for (const [name, arity] of resolveInterface(target.entry, target.dir, index, seen)) methods.set(name, arity);
+  }
+  return methods;
+}
+
+/**
+ * Index interfaces by package and reset link state from a previous run
+ * @param {Object} map - Repo map
+ * @param {Map<string, string[]>} packages - Package directory -> files
+ * @returns {{byDir: Map<string, Map<string, Object>>, qualified: Map<string, {entry: Object, dir: string}>}}
+ */
+function indexInterfaces(map, packages) {
+  const byDir = new Map();
+  const qualified = new Map();
+  for (const [dir, files] of packages) {
+    const local = new Map();
+    for (const file of files) {
+      const symbols = map.files[file].symbols || {};
+      for (const entry of symbols.functions || []) delete entry.memberOf;
+      for (const entry of symbols.types || []) {
+        if (entry.kind !== 'interface') {
+          delete entry.methods;
+          delete entry.implements;
+        } else if (!entry.constraint) {
+          local.set(entry.name, entry);
+          if (entry.exported !== false) qualified.set(`${path.posix.basename(dir)}.${entry.name}`, { entry, dir });
+        }
+      }
+    }
+    byDir.set(dir, local);
+  }
+  return { byDir, qualified };
+}
+
+/**
+ * Interfaces visible from a package: its own (plain names), exported ones
+ * from other packages (`pkg.Name`), then well-known standard interfaces
+ * @param {string} dir - Package directory
+ * @param {Object} index - `{byDir, qualified}` interface lookup
+ * @returns {Array<[string, Map<string, number[]|undefined>]>}
+ */
+function visibleInterfaces(dir, index) {
+  const result = [];
+  for (const entry of index.byDir.get(dir).values()) {
+    result.push([entry.name, resolveInterface(entry, dir, index)]);
+  }
+  for (const [name, target] of index.qualified) {
+    if (target.dir !== dir) result.push([name, resolveInterface(target.entry, target.dir, index)]);
+  }
+  for (const [name, methods] of Object.entries(WELL_KNOWN_INTERFACES)) {
+    result.push([name, new Map(Object.entries(methods))]);
+  }
+  return result;
+}
+
+/**
+ * Attach Go methods to their receiver types and compute `implements` in place
+ * Methods get `memberOf`; non-interface types get `methods` and `implements`.
+ * A type satisfies an interface when `T` or `*T` has every method by name
+ * and parameter/result count.
+ * @param {Object} map - Repo map
+ * @returns {number} - Number of methods grouped under a type
+ */
+function linkGoMethods(map) {
+  if (!map || !map.files) return 0;
+
+  const packages = groupPackages(map);
+  if (packages.size === 0) return 0;
+  const index = indexInterfaces(map, packages);
+
+  let linked = 0;
+  for (const [dir, files] of packages) {
+    const types = new Map();
+    for (const file of files) {
+      for (const entry of map.files[file].symbols?.types || []) {
+        if (entry.kind !== 'interface' && !types.has(entry.name)) types.set(entry.name, { entry, file });
+      }
+    }
+
+    const methodSets = new Map();
+    for (const file of files) {
+      for (const entry of map.files[file].symbols?.functions || []) {
+        const owner = entry.receiver && types.get(entry.receiver);
+        if (!owner) continue;
+        const method = { name: entry.name, line: entry.line, exported: entry.exported !== false };
+        if (entry.pointerReceiver) method.pointer = true;
+        if (file !== owner.file) method.file = file;
+        (owner.entry.methods = owner.entry.methods || []).push(method);
+        entry.memberOf = entry.receiver;
+
+        if (!methodSets.has(entry.receiver)) methodSets.set(entry.receiver, new Map());
+        methodSets.get(entry.receiver).set(entry.name, entry.arity);
+        linked++;
+      }
+    }
+    if (methodSets.size === 0) continue;
+
+    const interfaces = visibleInterfaces(dir, index);
+    for (const [typeName, methodSet] of methodSets) {
+      const owner = types.get(typeName).entry;
+      owner.methods.sort((a, b) => a.name.localeCompare(b.name));
+      const implemented = interfaces
+        .filter(([, required]) => satisfies(methodSet, required))
+        .map(([name]) => name);
+      if (implemented.length > 0) owner.implements = implemented;
+    }
+  }
+
+  return linked;
+}
+
+module.exports = {
+  WELL_KNOWN_INTERFACES,
+  linkGoMethods
+};
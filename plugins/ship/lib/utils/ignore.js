@@ -0,0 +1,261 @@
+/**
+ * Ignore Rules
+ * Shared .gitignore-style matching for every file walker (repo-map, slop
+ * analyzers, drift-detect collectors)
+ *
+ * Sources, applied in order (later negations can re-include earlier matches):
+ * 1. Built-in excluded directories (node_modules, dist, vendor, ...)
+ * 2. `.gitignore` at the repository root and in subdirectories; a nested
+ *    file's patterns are relative to its own directory and win over those
+ *    of its parents
+ * 3. `.awesome-slashignore` at the repository root (`.awsome-slashignore` is also read)
+ * 4. Globs passed by the caller (e.g. from configuration)
+ *
+ * Linked worktrees and submodules checked out under the root are excluded
+ * too (see lib/utils/git); callers scan submodules separately.
+ *
+ * @module lib/utils/ignore
+ * @author Avi Fenesh
+ * @license MIT
+ */
+
+const git = require('./git');
+
+/**
+ * Directories excluded from every walk regardless of ignore files
+ * (including this tool's own cache, bench, and history data)
+ */
+const DEFAULT_EXCLUDE_DIRS = [
+  'node_modules', 'vendor', 'dist', 'build', 'out', 'target',
+  '.git', '.svn', '.hg', '__pycache__', '.pytest_cache',
+  'coverage', '.nyc_output', '.next', '.nuxt', '.cache',
+  '.awesome-slash', '.awsome-slash'
+];
+
+/**
+ * Project-specific ignore files read after .gitignore
+ */
+const IGNORE_FILENAMES = ['.awesome-slashignore', '.awsome-slashignore'];
+
+/**
+ * Compile one gitignore-style glob
+ * @param {string} rawPattern - Trimmed, non-comment pattern line
+ * @returns {{regex: RegExp, negated: boolean, dirOnly: boolean}}
+ */
+function compilePattern(rawPattern) {
+  let pattern = rawPattern;
+
+  // Track if pattern is negated
+  const negated = pattern.startsWith('!');
+  if (negated) pattern = pattern.slice(1);
+
+  // Track if pattern is directory-only
+  const dirOnly = pattern.endsWith('/');
+  if (dirOnly) pattern = pattern.slice(0, -1);
+
+  // Track if pattern is anchored (explicit / at start)
+  const anchored = pattern.startsWith('/');
+  if (anchored) pattern = pattern.slice(1);
+
+  // Check if pattern starts with ** (matches any leading path)
+  const matchesAnywhere = pattern.startsWith('**/');
+
+  // Convert gitignore pattern to regex
+  // Step 1: Replace bracket classes (`*.py[co]`, `[!._]*`) and globstar
+  // patterns with placeholders (before escaping)
+  const classes = [];
+  let regexStr = pattern
+    .replace(/\[([!^]?)([^\]/]+)\]/g, (_, negate, body) => {
+      const escaped = body.replace(/\\/g, '\\\\');
+      classes.push(negate ? `[^/${escaped}]` : `[${escaped}]`);
+      return `\x00CLASS${classes.length - 1}\x00`;
+    })
+    .replace(/^\*\*\//, '\x00LEADING\x00')
+    .replace(/\/\*\*$/, '\x00TRAILING\x00')
+    .replace(/\*\*\//g, '\x00ANYPATH\x00')
+    .replace(/\/\*\*/g, '\x00ANYPATH2\x00')
+    .replace(/\*\*/g, '\x00STAR2\x00');
+
+  // Step 2: Escape special regex chars (except * and ?) and handle remaining globs
+  regexStr = regexStr
+    .replace(/[.+^${}()|[\]\\]/g, '\\$&')
+    .replace(/\*/g, '[^/]*')
+    .replace(/\?/g, '[^/]');
+
+  // Step 3: Restore globstar patterns with proper regex
+  regexStr = regexStr
+    .replace(/\x00LEADING\x00/g, '(?:.*/)?')
+    .replace(/\x00TRAILING\x00/g, '/.*')
+    .replace(/\x00ANYPATH\x00/g, '(?:.*/)?')
+    .replace(/\x00ANYPATH2\x00/g, '(?:/.*)?')
+    .replace(/\x00STAR2\x00/g, '.*')
+    .replace(/\x00CLASS(\d+)\x00/g, (_, index) => classes[index]);
+
+  // Pattern matching rules:
+  // - Anchored (starts with /), leading **, or containing /: match from root
+  //   (a leading ** already allows any prefix via (?:.*/)?)
+  // - Simple name: match anywhere in path
+  if (anchored || matchesAnywhere || pattern.includes('/')) {
+    regexStr = '^' + regexStr;
+  } else {
+    regexStr = '(?:^|/)' + regexStr;
+  }
+
+  return {
+    regex: new RegExp(regexStr + '(?:$|/)'),
+    negated,
+    dirOnly
+  };
+}
+
+/**
+ * Parse ignore file content (or a list of globs) into compiled patterns
+ * @param {string|string[]} content - File content or glob list
+ * @returns {Array<{regex: RegExp, negated: boolean, dirOnly: boolean}>}
+ */
+function parseIgnoreContent(content) {
+  const lines = Array.isArray(content) ? content : String(content || '').split('\n');
+  return lines
+    .map(line => String(line).trim())
+    .filter(line => line && !line.startsWith('#'))
+    .map(compilePattern);
+}
+
+/**
+ * Test a path against compiled patterns (last match wins)
+ * @param {Array} patterns - Compiled patterns
+ * @param {string} relativePath - Path relative to the repository root
+ * @param {boolean} [isDirectory=false] - Whether the path is a directory
+ * @returns {boolean}
+ */
+function matchPatterns(patterns, relativePath, isDirectory = false) {
+  return lastMatch(patterns, relativePath, isDirectory) === true;
+}
+
+/**
+ * Verdict of the last matching pattern
+ * @param {Array} patterns - Compiled patterns
+ * @param {string} relativePath - Path relative to the patterns' directory
+ * @param {boolean} isDirectory - Whether the path is a directory
+ * @returns {boolean|null} null when no pattern matches
+ */
+function lastMatch(patterns, relativePath, isDirectory) {
+  // Normalize path separators
+  const normalized = relativePath.replace(/\\/g, '/');
+  let ignored = null;
+
+  for (const { regex, negated, dirOnly } of patterns) {
+    // Skip directory-only patterns for files
+    if (dirOnly && !isDirectory) continue;
+
+    if (regex.test(normalized)) {
+      ignored = !negated;
+    }
+  }
+
+  return ignored;
+}
+
+/**
+ * Read and compile an ignore file
+ * @param {string} filePath - Absolute file path
+ * @param {Object} fs - File system module
+ * @returns {Array|null} Compiled patterns, or null if the file is missing
+ */
+function readIgnoreFile(filePath, fs) {
+  try {
+    return parseIgnoreContent(fs.readFileSync(filePath, 'utf8'));
+  } catch {
+    return null;
+  }
+}
+
+/**
+ * Build the ignore predicate used by file walkers
+ *
+ * Files are also checked against their parent directories, so callers that
+ * test a single path (e.g. file watchers) get the same answer as a walk that
+ * prunes ignored directories.
+ *
+ * @param {string} repoPath - Repository root path
+ * @param {Object} [options]
+ * @param {string[]} [options.patterns] - Extra gitignore-style globs (e.g. from configuration)
+ * @param {string[]} [options.excludeDirs] - Directory names always excluded (default DEFAULT_EXCLUDE_DIRS)
+ * @param {boolean} [options.respectGitignore=true] - Read .gitignore
+ * @param {boolean} [options.nestedCheckouts=true] - Exclude nested worktrees and submodules
+ * @param {Object} [options.fs] - File system module (for testing)
+ * @param {Object} [options.path] - Path module (for testing)
+ * @returns {Function} `(relativePath, isDirectory) => boolean`
+ */
+function createIgnoreFilter(repoPath, options = {}) {
+  const fs = options.fs || require('fs');
+  const path = options.path || require('path');
+  const excludeDirs = options.excludeDirs || DEFAULT_EXCLUDE_DIRS;
+
+  const respectGitignore = options.respectGitignore !== false;
+  const patterns = [];
+  for (const filename of IGNORE_FILENAMES) {
+    const compiled = readIgnoreFile(path.join(repoPath, filename), fs);
+    if (compiled) patterns.push(...compiled);
+  }
+  if (options.patterns && options.patterns.length > 0) {
+    patterns.push(...parseIgnoreContent(options.patterns));
+  }
+
+  // .gitignore per directory ('' is the root), read the first time a path below it is checked
+  const gitignores = new Map();
+  const gitignoreIn = dir => {
+    if (!gitignores.has(dir)) gitignores.set(dir, readIgnoreFile(dir ? path.join(repoPath, dir, '.gitignore') : path.join(repoPath, '.gitignore'), fs));
+    return gitignores.get(dir);
+  };
+  if (respectGitignore) gitignoreIn('');
+
+  // Deeper .gitignore files override shallower ones; the project ignore
+  // files and caller globs apply last
+  const isMatch = (parts, isDirectory) => {
+    let ignored = null;
+    if (respectGitignore) {
+      for (let depth = 0; depth < parts.length; depth++) {
+        const scoped = gitignoreIn(parts.slice(0, depth).join('/'));
+        if (!scoped) continue;
+        const verdict = lastMatch(scoped, parts.slice(depth).join('/'), isDirectory);
+        if (verdict !== null) ignored = verdict;
+      }
+    }
+    const verdict = lastMatch(patterns, parts.join('/'), isDirectory);
+    return (verdict === null ? ignored : verdict) === true;
+  };
+  let checkouts = [];
+  if (options.nestedCheckouts !== false) {
+    try {
+      const nested = git.nestedCheckouts(repoPath, { fs, path });
+      checkouts = [...nested.worktrees, ...nested.submodules];
+    } catch {
+      // Stub fs/path modules without stat or resolve: nothing to exclude
+    }
+  }
+
+  return function isIgnored(relativePath, isDirectory = false) {
+    const parts = relativePath.replace(/\\/g, '/').split('/').filter(part => part && part !== '.');
+    if (parts.some(part => excludeDirs.includes(part))) return true;
+    if (checkouts.length > 0) {
+      const normalized = parts.join('/');
+      if (checkouts.some(dir => normalized === dir || normalized.startsWith(`${dir}/`))) return true;
+    }
+
+    // An ignored parent directory cannot be re-included by a later negation
+    for (let i = 1; i < parts.length; i++) {
+      if (isMatch(parts.slice(0, i), true)) return true;
+    }
+    return isMatch(parts, isDirectory);
+  };
+}
+
+module.exports = {
+  DEFAULT_EXCLUDE_DIRS,
+  IGNORE_FILENAMES,
+  compilePattern,
+  parseIgnoreContent,
+  matchPatterns,
+  createIgnoreFilter
+};
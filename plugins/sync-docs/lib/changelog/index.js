@@ -0,0 +1,455 @@
+#!/usr/bin/env node
+/**
+ * Release Notes from Conventional Commits
+ *
+ * Parses `type(scope)!: description` commits since the last tag, groups them
+ * by type and scope, and renders a release section in Keep a Changelog or
+ * conventional-changelog style. PR, issue and commit links follow the git
+ * host: the remote URL names it, and the detected CI platform settles
+ * self-hosted remotes (GitLab CI means GitLab merge request and issue URLs).
+ * Commits that do not follow the convention are listed under "Other" in
+ * conventional style and skipped in Keep a Changelog.
+ *
+ * Usage: node lib/changelog/index.js [--from <ref>] [--to <ref>] [--version <v>]
+ *          [--style keep-a-changelog|conventional] [--write]
+ * Output: JSON with the rendered section (and the updated file with --write)
+ *
+ * @module lib/changelog
+ */
+
+const fs = require('fs');
+const path = require('path');
+
+const { git } = require('../utils/exec');
+
+const STYLES = ['keep-a-changelog', 'conventional'];
+
+const HEADER = /^(\w+)(?:\(([^)]*)\))?(!)?:\s*(.+)$/;
+
+/**
+ * Keep a Changelog sections per commit type, in render order
+ */
+const KEEP_A_CHANGELOG_SECTIONS = [
+  { title: 'Added', types: ['feat'] },
+  { title: 'Changed', types: ['perf', 'refactor'] },
+  { title: 'Deprecated', types: ['deprecate'] },
+  { title: 'Removed', types: ['remove', 'revert'] },
+  { title: 'Fixed', types: ['fix'] },
+  { title: 'Security', types: ['security'] }
+];
+
+/**
+ * conventional-changelog sections per commit type, in render order
+ */
+const CONVENTIONAL_SECTIONS = [
+  { title: 'Features', types: ['feat'] },
+  { title: 'Bug Fixes', types: ['fix'] },
+  { title: 'Performance Improvements', types: ['perf'] },
+  { title: 'Reverts', types: ['revert'] },
+  { title: 'Code Refactoring', types: ['refactor'] },
+  { title: 'Documentation', types: ['docs'] }
+];
+
+/**
+ * Link templates per git host
+ */
+const HOSTS = {
+  github: { pr: '/pull/', issue: '/issues/', commit: '/commit/', compare: '/compare/' },
+  gitlab: { pr: '/-/merge_requests/', issue: '/-/issues/', commit: '/-/commit/', compare: '/-/compare/' },
+  bitbucket: { pr: '/pull-requests/', issue: '/issues/', commit: '/commits/', compare: '/branches/compare/' }
+};
+
+/**
+ * Git host implied by a CI platform, for remotes on custom domains
+ */
+const CI_HOSTS = {
+  'github-actions': 'github',
+  'gitlab-ci': 'gitlab'
+};
+
+/**
+ * Parse a commit into conventional parts
+ * @param {{hash: string, subject: string, body?: string}} commit - Raw commit
+ * @param {string} [host] - Git host (`gitlab` references merge requests as `!N`)
+ * @returns {{hash: string, type: string|null, scope: string|null, breaking: boolean, breakingNote: string|null,
+ *   description: string, prs: number[], issues: number[]}}
+ */
+function parseCommit(commit, host) {
+  const subject = String(commit.subject || '').trim();
+  const body = String(commit.body || '');
+  const match = subject.match(HEADER);
+  const note = body.match(/^BREAKING[ -]CHANGE:\s*([\s\S]+?)(?:\n\s*\n|$)/m);
+
+  let description = match ? match[4] : subject;
+  const prs = [];
+  const issues = [];
+
+  // Squash merges append `(#123)`; GitLab uses `!123` for merge requests
+  const trailing = host === 'gitlab' ? /\s*\((?:!|#)(\d+)\)\s*$/ : /\s*\(#(\d+)\)\s*$/;
+  let pr;
+  while ((pr = description.match(trailing))) {
+    prs.unshift(Number(pr[1]));
+    description = description.slice(0, pr.index);
+  }
+  const merge = subject.match(/^Merge pull request #(\d+)/) || body.match(/^See merge request \S*!(\d+)/m);
+  if (merge && !prs.includes(Number(merge[1]))) prs.push(Number(merge[1]));
+  for (const ref of body.matchAll(/\b(?:close[sd]?|fix(?:e[sd])?|resolve[sd]?|refs?)\s+#(\d+)/gi)) {
+    const number = Number(ref[1]);
+    if (!issues.includes(number) && !prs.includes(number)) issues.push(number);
+  }
+
+  return {
+    hash: commit.hash,
+    type: match ? match[1].toLowerCase() : null,
+    scope: match && match[2] ? match[2].trim() : null,
+    breaking: Boolean(match && match[3]) || Boolean(note),
+    breakingNote: note ? note[1].replace(/\s+/g, ' ').trim() : null,
+    description: description.trim(),
+    prs,
+    issues
+  };
+}
+
+/**
+ * Most recent tag reachable from a ref
+ * @param {string} basePath - Repository root
+ * @param {string} [ref='HEAD'] - Ref to start from
+ * @returns {string|null}
+ */
+function getLastTag(basePath, ref = 'HEAD') {
+  const out = git(basePath, ['describe', '--tags', '--abbrev=0', ref]);
+  return out ? out.trim() : null;
+}
+
+/**
+ * Read commits in a range, oldest first
+ * @param {string} basePath - Repository root
+ * @param {Object} [options]
+ * @param {string} [options.from] - Exclusive start (defaults to the last tag; all history when there is none)
+ * @param {string} [options.to='HEAD'] - Inclusive end
+ * @returns {{from: string|null, to: string, commits: Array<{hash: string, subject: string, body: string}>}|null} null when git fails
+ */
+function getCommits(basePath, options = {}) {
+  const to = options.to || 'HEAD';
+  const from = options.from || getLastTag(basePath, to);
+  if ([from, to].some(ref => ref && ref.startsWith('-'))) return null;
+
+  // Unit and record separators cannot appear in commit messages
+  const out = git(basePath, ['log', '--reverse', '--format=%H%x1f%s%x1f%b%x1e', from ? `${from}..${to}` : to]);
+  if (out === null) return null;
+  const commits = out.split('\x1e')
+    .map(record => record.replace(/^\n/, ''))
+    .filter(Boolean)
+    .map(record => {
+      const [hash, subject, body] = record.split('\x1f');
+      return { hash, subject, body: (body || '').trim() };
+    });
+  return { from: from || null, to, commits };
+}
+
+/**
+ * Repository web URL and host from a git remote
+ * @param {string} remote - Remote URL (`git@github.com:o/r.git`, `https://gitlab.example.com/g/r`)
+ * @param {string|null} [ciPlatform] - Detected CI platform, for custom domains
+ * @returns {{host: string, url: string}|null}
+ */
+function resolveRepository(remote, ciPlatform) {
+  const text = String(remote || '').trim();
+  const match = text.match(/^(?:[\w+]+:\/\/)?(?:[^@/]+@)?([^/:]+)(?::\d+)?[:/](.+?)(?:\.git)?\/?$/);
+  if (!match) return null;
+  const [, domain, repoPath] = match;
+  const host = /github/i.test(domain) ? 'github'
+    : /gitlab/i.test(domain) ? 'gitlab'
+      : /bitbucket/i.test(domain) ? 'bitbucket'
+        : CI_HOSTS[ciPlatform] || null;
+  if (!host) return null;
+  return { host, url: `https://${domain}/${repoPath.replace(/^\/+/, '')}` };
+}
+
+/**
+ * Repository of the `origin` remote
+ * @param {string} basePath - Repository root
+ * @param {string|null} [ciPlatform] - Detected CI platform, for custom domains
+ * @returns {{host: string, url: string}|null}
+ */
+function getRepository(basePath, ciPlatform) {
+  return resolveRepository(git(basePath, ['remote', 'get-url', 'origin']), ciPlatform);
+}
+
+/**
+ * Group parsed commits into sections for a style
+ * @param {Object[]} commits - Results of parseCommit
+ * @param {string} [style='keep-a-changelog'] - Output style
+ * @returns {{breaking: Object[], sections: Array<{title: string, scopes: Array<{scope: string|null, commits: Object[]}>}>}}
+ */
+function groupCommits(commits, style = 'keep-a-changelog') {
+  const definitions = style === 'conventional' ? CONVENTIONAL_SECTIONS : KEEP_A_CHANGELOG_SECTIONS;
+  const sections = definitions.map(definition => ({ title: definition.title, types: definition.types, commits: [] }));
+  const other = { title: 'Other', types: [], commits: [] };
+
+  for (const commit of commits) {
+    if (/^Merge /.test(commit.description) && !commit.type) continue;
+    const section = sections.find(candidate => candidate.types.includes(commit.type));
+    if (section) section.commits.push(commit);
+    else if (style === 'conventional' && !commit.type) other.commits.push(commit);
+  }
+  if (other.commits.length > 0) sections.push(other);
+
+  const byScope = list => {
+    const scopes = new Map();
+    for (const commit of list) {
+      if (!scopes.has(commit.scope)) scopes.set(commit.scope, []);
+      scopes.get(commit.scope).push(commit);
+    }
+    // Unscoped entries first, then scopes alphabetically
+    return Array.from(scopes.entries())
+      .sort(([a], [b]) => (a === null ? -1 : b === null ? 1 : a.localeCompare(b)))
+      .map(([scope, grouped]) => ({ scope, commits: grouped }));
+  };
+
+  return {
+    breaking: commits.filter(commit => commit.breaking),
+    sections: sections
+      .filter(section => section.commits.length > 0)
+      .map(section => ({ title: section.title, scopes: byScope(section.commits) }))
+  };
+}
+
+/**
+ * Markdown links for a commit's PRs, issues and hash
+ * @param {Object} commit - Parsed commit
+ * @param {{host: string, url: string}|null} repository - Result of resolveRepository
+ * @returns {string} e.g. ` ([#12](…/pull/12)) ([abc1234](…/commit/abc…))`
+ */
+function formatRefs(commit, repository) {
+  const links = HOSTS[repository && repository.host];
+  const prPrefix = repository && repository.host === 'gitlab' ? '!' : '#';
+  const ref = (prefix, number, kind) => links ? `[${prefix}${number}](${repository.url}${links[kind]}${number})` : `${prefix}${number}`;
+  const parts = [
+    ...commit.prs.map(number => ref(prPrefix, number, 'pr')),
+    ...commit.issues.map(number => ref('#', number, 'issue'))
+  ];
+  if (commit.hash) {
+    const short = commit.hash.slice(0, 7);
+    parts.push(links ? `[${short}](${repository.url}${links.commit}${commit.hash})` : short);
+  }
+  return parts.map(part => ` (${part})`).join('');
+}
+
+/**
+ * Compare URL between the previous tag and a release
+ * @param {{host: string, url: string}|null} repository - Result of resolveRepository
+ * @param {string|null} from - Previous tag
+ * @param {string} version - Release version, or Unreleased
+ * @param {string} [to='HEAD'] - Release ref
+ * @returns {string|null}
+ */
+function compareUrl(repository, from, version, to = 'HEAD') {
+  const links = HOSTS[repository && repository.host];
+  if (!links || !from) return null;
+  let head = to;
+  // Released from HEAD: the tag about to be created, prefixed like the previous one
+  if (version !== 'Unreleased' && to === 'HEAD') head = `${from.startsWith('v') ? 'v' : ''}${version.replace(/^v/, '')}`;
+  return `${repository.url}${links.compare}${from}...${head}`;
+}
+
+/**
+ * Render a release section
+ * @param {Object[]} commits - Results of parseCommit
+ * @param {Object} [options]
+ * @param {string} [options.style='keep-a-changelog'] - Output style
+ * @param {string} [options.version='Unreleased'] - Version heading
+ * @param {string} [options.date] - Release date (YYYY-MM-DD); defaults to today for versioned releases
+ * @param {{host: string, url: string}|null} [options.repository] - For links
+ * @param {string|null} [options.compare] - Compare URL; conventional style links the heading to it
+ * @returns {string} Markdown section, empty when nothing is notable
+ */
+function renderRelease(commits, options = {}) {
+  const style = options.style || 'keep-a-changelog';
+  const version = options.version || 'Unreleased';
+  const repository = options.repository || null;
+  const date = options.date || (version === 'Unreleased' ? null : new Date().toISOString().slice(0, 10));
+  const { breaking, sections } = groupCommits(commits, style);
+  if (sections.length === 0 && breaking.length === 0) return '';
+
+  const compare = options.compare || null;
+  const lines = [];
+
+  if (style === 'conventional') {
+    const title = compare ? `[${version}](${compare})` : version;
+    lines.push(`## ${title}${date ? ` (${date})` : ''}`, '');
+    if (breaking.length > 0) {
+      lines.push('### ⚠ BREAKING CHANGES', '');
+      breaking.forEach(commit => lines.push(`* ${commit.scope ? `**${commit.scope}:** ` : ''}${commit.breakingNote || commit.description}`));
+      lines.push('');
+    }
+    for (const section of sections) {
+      lines.push(`### ${section.title}`, '');
+      for (const { scope, commits: grouped } of section.scopes) {
+        grouped.forEach(commit => lines.push(`* ${scope ? `**${scope}:** ` : ''}${commit.description}${formatRefs(commit, repository)}`));
+      }
+      lines.push('');
+    }
+  } else {
+    lines.push(`## [${version}]${date ? ` - ${date}` : ''}`, '');
+    for (const section of sections) {
+      lines.push(`### ${section.title}`);
+      for (const { scope, commits: grouped } of section.scopes) {
+        grouped.forEach(commit => {
+          const marker = commit.breaking ? '**BREAKING** ' : '';
+          lines.push(`- ${marker}${scope ? `**${scope}:** ` : ''}${commit.breakingNote && commit.breaking ? `${commit.description}. ${commit.breakingNote}` : commit.description}${formatRefs(commit, repository)}`);
+        });
+      }
+      lines.push('');
+    }
+  }
+
+  return lines.join('\n').replace(/\n+$/, '\n');
+}
+
+/**
+ * Insert or replace a release section in changelog content
+ * An existing section for the same version is replaced; releasing a version
+ * also clears `## [Unreleased]`, which is kept as an empty heading in Keep a
+ * Changelog files. New sections go above the newest release. In Keep a
+ * Changelog style the compare URL becomes a `[version]: url` reference with
+ * the others at the end of the file.
+ * @param {string|null} existing - Current CHANGELOG.md content
+ * @param {string} section - Result of renderRelease
+ * @param {Object} [options]
+ * @param {string} [options.version='Unreleased'] - Version of the section
+ * @param {string} [options.style='keep-a-changelog'] - Output style, for the header of new files
+ * @param {string|null} [options.compare] - Compare URL for the version's link reference
+ * @returns {string}
+ */
+function updateChangelog(existing, section, options = {}) {
+  const version = options.version || 'Unreleased';
+  const style = options.style || 'keep-a-changelog';
+  const header = style === 'conventional'
+    ? '# Changelog\n\nAll notable changes to this project will be documented in this file.\n'
+    : '# Changelog\n\nAll notable changes to this project will be documented in this file.\n\n' +
+      'The format is based on [Keep a Changelog](https://keepachangelog.com/en/1.1.0/),\n' +
+      'and this project adheres to [Semantic Versioning](https://semver.org/spec/v2.0.0.html).\n';
+  const content = existing && existing.trim() ? existing.replace(/\r\n/g, '\n') : header;
+  const body = section.replace(/\n+$/, '');
+
+  const lines = content.split('\n');
+  const headingVersion = line => {
+    const match = line.match(/^##\s+\[?v?([^\]\s()]+)\]?/);
+    return match ? match[1] : null;
+  };
+  const reference = /^\[[^\]]+\]:\s*\S+/;
+  // A version's section runs to the next `## ` heading or the trailing link references
+  const sectionRange = name => {
+    const start = lines.findIndex(line => headingVersion(line) === name.replace(/^v/, ''));
+    if (start === -1) return null;
+    let end = lines.findIndex((line, i) => i > start && (/^##\s/.test(line) || reference.test(line)));
+    if (end === -1) end = lines.length;
+    return { start, end };
+  };
+
+  const target = sectionRange(version);
+  const unreleased = version === 'Unreleased' ? null : sectionRange('Unreleased');
+
+  if (target) {
+    lines.splice(target.start, target.end - target.start, body, '');
+  } else if (unreleased) {
+    const keep = style === 'conventional' ? [] : ['## [Unreleased]', ''];
+    lines.splice(unreleased.start, unreleased.end - unreleased.start, ...keep, body, '');
+  } else {
+    const firstRelease = lines.findIndex(line => /^##\s/.test(line));
+    if (firstRelease === -1) {
+      while (lines.length > 0 && lines[lines.length - 1] === '') lines.pop();
+      lines.push('', body, '');
+    } else {
+      lines.splice(firstRelease, 0, body, '');
+    }
+  }
+
+  if (options.compare && style !== 'conventional') {
+    const label = `[${version}]:`;
+    const existingRef = lines.findIndex(line => line.startsWith(label));
+    if (existingRef !== -1) lines.splice(existingRef, 1);
+    const firstRef = lines.findIndex(line => reference.test(line));
+    if (firstRef === -1) {
+      while (lines.length > 0 && lines[lines.length - 1] === '') lines.pop();
+      lines.push('', `${label} ${options.compare}`);
+    } else {
+      lines.splice(firstRef, 0, `${label} ${options.compare}`);
+    }
+  }
+
+  return lines.join('\n').replace(/\n{3,}/g, '\n\n').replace(/\n*$/, '\n');
+}
+
+/**
+ * Build release notes for a repository
+ * @param {string} basePath - Repository root
+ * @param {Object} [options]
+ * @param {string} [options.from] - Start ref (defaults to the last tag)
+ * @param {string} [options.to='HEAD'] - End ref
+ * @param {string} [options.version] - Version heading (defaults to Unreleased)
+ * @param {string} [options.style='keep-a-changelog'] - `keep-a-changelog` or `conventional`
+ * @param {string|null} [options.ciPlatform] - Detected CI platform (from detect-platform)
+ * @param {string} [options.date] - Release date
+ * @returns {{success: boolean, from?: string|null, to?: string, repository?: Object|null, commits?: number, compare?: string|null, section?: string, error?: string}}
+ */
+function generateChangelog(basePath, options = {}) {
+  const style = options.style || 'keep-a-changelog';
+  if (!STYLES.includes(style)) {
+    return { success: false, error: `Unknown style: ${style}. Use ${STYLES.join(' or ')}.` };
+  }
+  const range = getCommits(basePath, options);
+  if (!range) return { success: false, error: 'Could not read git history for the requested range' };
+
+  const repository = getRepository(basePath, options.ciPlatform);
+  const host = repository ? repository.host : CI_HOSTS[options.ciPlatform];
+  const commits = range.commits.map(commit => parseCommit(commit, host));
+  const compare = compareUrl(repository, range.from, options.version || 'Unreleased', range.to);
+
+  return {
+    success: true,
+    from: range.from,
+    to: range.to,
+    repository,
+    commits: commits.length,
+    compare,
+    section: renderRelease(commits, { style, version: options.version, date: options.date, repository, compare })
+  };
+}
+
+// When run directly, output JSON
+if (require.main === module) {
+  const args = process.argv.slice(2);
+  const value = flag => (args.includes(flag) ? args[args.indexOf(flag) + 1] : undefined);
+  const basePath = process.cwd();
+
+  require('../platform/detect-platform').detectCI().catch(() => null).then(ciPlatform => {
+    const options = { from: value('--from'), to: value('--to'), version: value('--version'), style: value('--style'), ciPlatform };
+    const result = generateChangelog(basePath, options);
+    if (result.success && args.includes('--write') && result.section) {
+      const file = path.join(basePath, 'CHANGELOG.md');
+      const existing = fs.existsSync(file) ? fs.readFileSync(file, 'utf8') : null;
+      fs.writeFileSync(file, updateChangelog(existing, result.section, { ...options, compare: result.compare }));
+      result.written = 'CHANGELOG.md';
+    }
+    const indent = process.stdout.isTTY ? 2 : 0;
+    console.log(JSON.stringify(result, null, indent));
+    if (!result.success) process.exitCode = 1;
+  });
+}
+
+module.exports = {
+  STYLES,
+  parseCommit,
+  getLastTag,
+  getCommits,
+  resolveRepository,
+  getRepository,
+  groupCommits,
+  formatRefs,
+  compareUrl,
+  renderRelease,
+  updateChangelog,
+  generateChangelog
+};
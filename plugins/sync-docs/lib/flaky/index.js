@@ -0,0 +1,549 @@
+#!/usr/bin/env node
+/**
+ * Flaky Test Detection from CI History
+ *
+ * Lists recent runs on the detected CI platform, loads each run's test
+ * results (GitHub Actions artifacts holding JUnit XML, `go test -json`, or
+ * Jest/Vitest JSON; GitLab's pipeline test report), and finds tests that
+ * both passed and failed at the same commit. Same-commit disagreement is
+ * the signal: a test that fails on one commit and passes on the next may
+ * just have been fixed.
+ *
+ * Usage: node lib/flaky/index.js [--limit N] [--workflow <name>] [--branch <name>] [--no-cache]
+ * Output: JSON flaky-test report
+ *
+ * @module lib/flaky
+ */
+
+const fs = require('fs');
+const os = require('os');
+const path = require('path');
+
+const exec = require('../utils/exec');
+
+const SUPPORTED_PLATFORMS = ['github-actions', 'gitlab-ci'];
+
+// Runs still producing results; finished runs never change and are cached
+const UNFINISHED_STATUSES = ['running', 'pending', 'in_progress', 'queued', 'waiting', 'created', 'preparing', 'scheduled'];
+
+const DEFAULT_LIMIT = 30;
+const MESSAGE_LENGTH = 300;
+const MAX_REPORT_BYTES = 50 * 1024 * 1024;
+
+// CI checkout prefixes stripped from absolute test file paths
+const CHECKOUT_PREFIX = /^.*?\/(?:work\/[^/]+\/[^/]+|builds\/[^/]+\/[^/]+)\//;
+
+/**
+ * Run a command and return stdout, or null on failure
+ * @param {string} basePath - Project root
+ * @param {string[]} argv - Command and arguments
+ * @returns {string|null}
+ */
+function run(basePath, argv) {
+  return exec.run(basePath, argv, { timeout: 300000 });
+}
+
+/**
+ * Decode XML entities in attribute values and text
+ * @param {string} value
+ * @returns {string}
+ */
+function decodeXml(value) {
+  return String(value || '')
+    .replace(/<!\[CDATA\[([\s\S]*?)\]\]>/g, '$1')
+    .replace(/&lt;/g, '<')
+    .replace(/&gt;/g, '>')
+    .replace(/&quot;/g, '"')
+    .replace(/&apos;/g, "'")
+    .replace(/&#(\d+);/g, (match, code) => String.fromCharCode(Number(code)))
+    .replace(/&amp;/g, '&');
+}
+
+/**
+ * Read XML attributes into an object
+ * @param {string} source - Attribute text of a tag
+ * @returns {Object<string, string>}
+ */
+function attributes(source) {
+  const attrs = {};
+  for (const [, name, , value] of source.matchAll(/([\w:-]+)\s*=\s*(["'])([\s\S]*?)\2/g)) attrs[name] = decodeXml(value);
+  return attrs;
+}
+
+/**
+ * Shorten a failure message to its first lines
+ * @param {string} message
+ * @returns {string|null}
+ */
+function shortMessage(message) {
+  const text = String(message || '').trim();
+  if (!text) return null;
+  return text.length > MESSAGE_LENGTH ? `${text.slice(0, MESSAGE_LENGTH - 3)}...` : text;
+}
+
+/**
+ * Repository-relative test file path
+ * @param {string|null} file - Path from the report
+ * @returns {string|null}
+ */
+function normalizeFile(file) {
+  if (!file) return null;
+  return String(file).replace(/\\/g, '/').replace(CHECKOUT_PREFIX, '').replace(/^[A-Za-z]:\/a\/[^/]+\/[^/]+\//, '');
+}
+
+/**
+ * Parse JUnit XML (Jest/Vitest junit reporters, pytest, Maven Surefire, gotestsum)
+ * @param {string} content - XML
+ * @returns {Array<{id: string, name: string, suite: string|null, file: string|null, status: string, message: string|null}>}
+ */
+function parseJUnit(content) {
+  const results = [];
+  const suites = [];
+  const tags = /<testsuite\b([^>]*?)(\/?)>|<\/testsuite>|<testcase\b([^>]*?)(?:\/>|>([\s\S]*?)<\/testcase>)/g;
+  for (const match of String(content).matchAll(tags)) {
+    if (match[0].startsWith('</testsuite')) {
+      suites.pop();
+      continue;
+    }
+    if (match[0].startsWith('<testsuite')) {
+      if (!match[2]) suites.push(attributes(match[1]));
+      continue;
+    }
+
+    const attrs = attributes(match[3]);
+    const body = match[4] || '';
+    const suite = suites[suites.length - 1] || {};
+    const failure = body.match(/<(failure|error)\b([^>]*?)(?:\/>|>([\s\S]*?)<\/\1>)/);
+    const status = failure ? 'failed' : /<skipped\b/.test(body) ? 'skipped' : 'passed';
+    const group = attrs.classname || suite.name || null;
+    results.push({
+      id: group ? `${group}::${attrs.name}` : attrs.name,
+      name: attrs.name,
+      suite: group,
+      file: normalizeFile(attrs.file || suite.file || null),
+      status,
+      message: failure ? shortMessage(attributes(failure[2]).message || decodeXml(failure[3])) : null
+    });
+  }
+  return results;
+}
+
+/**
+ * Parse `go test -json` output
+ * @param {string} content - Newline-delimited test events
+ * @returns {Array<{id: string, name: string, suite: string, file: null, status: string, message: string|null}>}
+ */
+function parseGoTestJson(content) {
+  const output = new Map();
+  const results = [];
+  for (const line of String(content).split('\n')) {
+    let event;
+    try {
+      event = JSON.parse(line);
+    } catch {
+      continue;
+    }
+    if (!event || !event.Test) continue;
+    const id = `${event.Package}::${event.Test}`;
+    if (event.Action === 'output') {
+      output.set(id, (output.get(id) || '') + event.Output);
+    } else if (['pass', 'fail', 'skip'].includes(event.Action)) {
+      const status = event.Action === 'pass' ? 'passed' : event.Action === 'fail' ? 'failed' : 'skipped';
+      const message = status === 'failed'
+        ? (output.get(id) || '').split('\n').filter(text => text.trim() && !/^\s*(?:=== RUN|--- FAIL)/.test(text)).join('\n')
+        : null;
+      results.push({ id, name: event.Test, suite: event.Package, file: null, status, message: shortMessage(message) });
+    }
+  }
+  return results;
+}
+
+/**
+ * Parse Jest or Vitest JSON results (`--json --outputFile`, `--reporter=json`)
+ * @param {string|Object} content - JSON
+ * @returns {Array<{id: string, name: string, suite: string|null, file: string|null, status: string, message: string|null}>}
+ */
+function parseJestJson(content) {
+  const data = typeof content === 'string' ? JSON.parse(content) : content;
+  const results = [];
+  for (const file of data.testResults || []) {
+    const name = normalizeFile(file.name || file.testFilePath);
+    for (const test of file.assertionResults || []) {
+      const title = test.fullName || [...(test.ancestorTitles || []), test.title].join(' ');
+      const status = test.status === 'passed' ? 'passed' : test.status === 'failed' ? 'failed' : 'skipped';
+      results.push({
+        id: `${name}::${title}`,
+        name: title,
+        suite: (test.ancestorTitles || [])[0] || null,
+        file: name,
+        status,
+        message: status === 'failed' ? shortMessage((test.failureMessages || []).join('\n')) : null
+      });
+    }
+  }
+  return results;
+}
+
+/**
+ * Parse a GitLab pipeline test report (`/pipelines/:id/test_report`)
+ * @param {string|Object} content - JSON
+ * @returns {Array<{id: string, name: string, suite: string|null, file: string|null, status: string, message: string|null}>}
+ */
+function parseGitlabTestReport(content) {
+  const data = typeof content === 'string' ? JSON.parse(content) : content;
+  const results = [];
+  for (const suite of data.test_suites || []) {
+    for (const test of suite.test_cases || []) {
+      const group = test.classname || suite.name || null;
+      const status = test.status === 'success' ? 'passed' : ['failed', 'error'].includes(test.status) ? 'failed' : 'skipped';
+      results.push({
+        id: group ? `${group}::${test.name}` : test.name,
+        name: test.name,
+        suite: group,
+        file: normalizeFile(test.file || null),
+        status,
+        message: status === 'failed' ? shortMessage(test.system_output) : null
+      });
+    }
+  }
+  return results;
+}
+
+const PARSERS = {
+  junit: parseJUnit,
+  go: parseGoTestJson,
+  jest: parseJestJson,
+  gitlab: parseGitlabTestReport
+};
+
+/**
+ * Detect a report format from its name and content
+ * @param {string} file - Report path
+ * @param {string} content - Report content
+ * @returns {string|null} Key of PARSERS
+ */
+function detectFormat(file, content) {
+  const head = String(content).slice(0, 4096).trimStart();
+  if (/\.xml$/i.test(file) && /<testsuites?\b/.test(head)) return 'junit';
+  if (/^\{"Time":[^\n]*"Action":/.test(head) || /^\{"Action":/.test(head)) return 'go';
+  if (/\.json$/i.test(file) && head.startsWith('{')) {
+    if (/"testResults"\s*:/.test(content)) return 'jest';
+    if (/"test_suites"\s*:/.test(content)) return 'gitlab';
+  }
+  return null;
+}
+
+/**
+ * Parse a report, detecting its format
+ * @param {string} file - Report path
+ * @param {string} content - Report content
+ * @param {string} [format] - Force a format
+ * @returns {Object[]|null} Results, or null when the file is not a test report
+ */
+function parseReport(file, content, format) {
+  const key = format || detectFormat(file, content);
+  if (!PARSERS[key]) return null;
+  try {
+    return PARSERS[key](content);
+  } catch {
+    return null;
+  }
+}
+
+/**
+ * Parse every test report below a directory (a downloaded artifact)
+ * @param {string} dir - Directory
+ * @returns {Object[]} Results from all reports
+ */
+function collectReports(dir) {
+  const results = [];
+  const walk = current => {
+    let entries;
+    try {
+      entries = fs.readdirSync(current, { withFileTypes: true });
+    } catch {
+      return;
+    }
+    for (const entry of entries) {
+      const full = path.join(current, entry.name);
+      if (entry.isDirectory()) {
+        walk(full);
+      } else if (/\.(?:xml|json|jsonl|log|txt)$/i.test(entry.name) && fs.statSync(full).size <= MAX_REPORT_BYTES) {
+        results.push(...(parseReport(entry.name, fs.readFileSync(full, 'utf8')) || []));
+      }
+    }
+  };
+  walk(dir);
+  return results;
+}
+
+/**
+ * CLI argv listing recent runs
+ * @param {string} platform - CI platform from detectCI
+ * @param {Object} [options]
+ * @param {number} [options.limit=30] - Runs to list
+ * @param {string} [options.workflow] - GitHub workflow name or file
+ * @param {string} [options.branch] - Branch filter
+ * @returns {string[]|null}
+ */
+function listRunsCommand(platform, options = {}) {
+  const limit = String(options.limit || DEFAULT_LIMIT);
+  if (platform === 'github-actions') {
+    return [
+      'gh', 'run', 'list', '--limit', limit,
+      '--json', 'databaseId,headSha,headBranch,conclusion,createdAt,url,attempt,workflowName',
+      ...(options.workflow ? ['--workflow', options.workflow] : []),
+      ...(options.branch ? ['--branch', options.branch] : [])
+    ];
+  }
+  if (platform === 'gitlab-ci') {
+    const query = `per_page=${limit}${options.branch ? `&ref=${encodeURIComponent(options.branch)}` : ''}`;
+    return ['glab', 'api', `projects/:id/pipelines?${query}`];
+  }
+  return null;
+}
+
+/**
+ * Normalize run lists from `listRunsCommand`
+ * @param {string} platform - CI platform
+ * @param {string} output - JSON output
+ * @returns {Array<{id: string, commit: string, branch: string|null, status: string|null, createdAt: string|null, url: string|null, attempt: number, workflow: string|null}>}
+ */
+function parseRuns(platform, output) {
+  let list;
+  try {
+    list = JSON.parse(output);
+  } catch {
+    return [];
+  }
+  if (!Array.isArray(list)) return [];
+  if (platform === 'github-actions') {
+    return list.map(item => ({
+      id: String(item.databaseId),
+      commit: item.headSha,
+      branch: item.headBranch || null,
+      status: item.conclusion || null,
+      createdAt: item.createdAt || null,
+      url: item.url || null,
+      attempt: item.attempt || 1,
+      workflow: item.workflowName || null
+    }));
+  }
+  return list.map(item => ({
+    id: String(item.id),
+    commit: item.sha,
+    branch: item.ref || null,
+    status: item.status || null,
+    createdAt: item.created_at || null,
+    url: item.web_url || null,
+    attempt: 1,
+    workflow: null
+  }));
+}
+
+/**
+ * Load the test results of one run
+ * Results of finished runs are kept in `options.cache`; runs without
+ * readable reports are not, since artifacts may still be uploading.
+ * @param {string} basePath - Project root
+ * @param {string} platform - CI platform
+ * @param {Object} runInfo - Entry from `parseRuns`
+ * @param {Object} [options]
+ * @param {Function} [options.run] - `(basePath, argv) => stdout|null`
+ * @param {string} [options.artifact] - GitHub artifact name pattern
+ * @param {Object} [options.cache] - Result of `cache.createCache(basePath, 'ci')`
+ * @returns {Object[]|null} Results, or null when the run has no readable reports
+ */
+function loadRunResults(basePath, platform, runInfo, options = {}) {
+  const cacheable = options.cache && runInfo.status && !UNFINISHED_STATUSES.includes(runInfo.status);
+  const key = `results:${platform}:${runInfo.id}:${runInfo.attempt}:${options.artifact || ''}`;
+  const cached = cacheable ? options.cache.get(key) : undefined;
+  if (Array.isArray(cached)) return cached;
+
+  const results = downloadRunResults(basePath, platform, runInfo, options);
+  if (cacheable && results) options.cache.set(key, results);
+  return results;
+}
+
+/**
+ * Fetch the test results of one run from the CI platform
+ * @param {string} basePath - Project root
+ * @param {string} platform - CI platform
+ * @param {Object} runInfo - Entry from `parseRuns`
+ * @param {Object} options - See loadRunResults
+ * @returns {Object[]|null}
+ */
+function downloadRunResults(basePath, platform, runInfo, options) {
+  const runCommand = options.run || run;
+  if (platform === 'gitlab-ci') {
+    const output = runCommand(basePath, ['glab', 'api', `projects/:id/pipelines/${runInfo.id}/test_report`]);
+    const results = output === null ? null : parseReport('test_report.json', output, 'gitlab');
+    return results && results.length > 0 ? results : null;
+  }
+
+  const dir = fs.mkdtempSync(path.join(os.tmpdir(), 'flaky-'));
+  try {
+    const argv = ['gh', 'run', 'download', runInfo.id, '--dir', dir, ...(options.artifact ? ['--pattern', options.artifact] : [])];
+    if (runCommand(basePath, argv) === null) return null;
+    const results = collectReports(dir);
+    return results.length > 0 ? results : null;
+  } finally {
+    fs.rmSync(dir, { recursive: true, force: true });
+  }
+}
+
+/**
+ * Find tests that passed and failed at the same commit
+ * @param {Array<{run: Object, results: Object[]}>} history - Runs with their results
+ * @returns {Array<{id: string, name: string, suite: string|null, file: string|null, executions: number,
+ *   failures: number, flakeRate: number, flakyCommits: number, lastFailure: Object|null}>} Most flaky first
+ */
+function findFlakyTests(history) {
+  const tests = new Map();
+  for (const { run: runInfo, results } of history) {
+    for (const result of results) {
+      if (result.status === 'skipped') continue;
+      if (!tests.has(result.id)) {
+        tests.set(result.id, { id: result.id, name: result.name, suite: result.suite, file: result.file, commits: new Map(), executions: 0, failures: 0, lastFailure: null });
+      }
+      const test = tests.get(result.id);
+      if (!test.commits.has(runInfo.commit)) test.commits.set(runInfo.commit, { passed: 0, failed: 0 });
+      test.commits.get(runInfo.commit)[result.status]++;
+      test.executions++;
+      if (result.status !== 'failed') continue;
+      test.failures++;
+      if (!test.lastFailure || String(runInfo.createdAt) > String(test.lastFailure.createdAt)) {
+        test.lastFailure = { run: runInfo.id, commit: runInfo.commit, branch: runInfo.branch, createdAt: runInfo.createdAt, url: runInfo.url, message: result.message };
+      }
+    }
+  }
+
+  return Array.from(tests.values())
+    .map(({ commits, ...test }) => ({
+      ...test,
+      flakeRate: Math.round((test.failures / test.executions) * 1000) / 1000,
+      flakyCommits: Array.from(commits.values()).filter(counts => counts.passed > 0 && counts.failed > 0).length
+    }))
+    .filter(test => test.flakyCommits > 0)
+    .sort((a, b) => b.flakyCommits - a.flakyCommits || b.flakeRate - a.flakeRate || a.id.localeCompare(b.id));
+}
+
+/**
+ * Load recent CI runs and report flaky tests
+ * @param {string} basePath - Project root
+ * @param {Object} options
+ * @param {string} options.platform - CI platform from detectCI
+ * @param {number} [options.limit=30] - Runs to inspect
+ * @param {string} [options.workflow] - GitHub workflow name or file
+ * @param {string} [options.branch] - Branch filter
+ * @param {string} [options.artifact] - GitHub artifact name pattern
+ * @param {Function} [options.run] - `(basePath, argv) => stdout|null`
+ * @param {Object} [options.cache] - Shared `ci` cache namespace for finished runs' results
+ * @returns {Object} `{success, platform, runs, analyzed, skipped, commits, tests, error}`
+ */
+function detectFlakyTests(basePath, options = {}) {
+  const { platform } = options;
+  if (!SUPPORTED_PLATFORMS.includes(platform)) {
+    return { success: false, error: `CI history is only read from ${SUPPORTED_PLATFORMS.join(' and ')} (detected: ${platform || 'none'})` };
+  }
+  const output = (options.run || run)(basePath, listRunsCommand(platform, options));
+  if (output === null) {
+    return { success: false, error: `Could not list runs; check that ${platform === 'gitlab-ci' ? 'glab' : 'gh'} is installed and authenticated` };
+  }
+
+  const runs = parseRuns(platform, output).filter(item => item.commit && !['cancelled', 'canceled', 'skipped'].includes(item.status));
+  const history = [];
+  const skipped = [];
+  for (const runInfo of runs) {
+    const results = loadRunResults(basePath, platform, runInfo, options);
+    if (results) history.push({ run: runInfo, results });
+    else skipped.push(runInfo.id);
+  }
+
+  return {
+    success: true,
+    platform,
+    runs: runs.length,
+    analyzed: history.length,
+    skipped,
+    commits: new Set(history.map(entry => entry.run.commit)).size,
+    tests: findFlakyTests(history)
+  };
+}
+
+/**
+ * Render the flaky-test report as markdown
+ * @param {Object} report - Result of `detectFlakyTests`
+ * @param {Object} [options]
+ * @param {number} [options.limit=20] - Rows listed
+ * @returns {string}
+ */
+function renderReport(report, options = {}) {
+  const limit = options.limit || 20;
+  const lines = ['## Flaky Tests', ''];
+  lines.push(`**Platform**: ${report.platform}`);
+  lines.push(`**Runs**: ${report.analyzed} analyzed of ${report.runs} (${report.commits} commits)${report.skipped.length ? `; ${report.skipped.length} without test reports` : ''}`);
+  lines.push(`**Flaky**: ${report.tests.length}`, '');
+
+  if (report.analyzed === 0) {
+    lines.push('No run had a readable test report. Upload JUnit XML, `go test -json` output, or Jest/Vitest JSON as an artifact (GitHub), or declare `artifacts:reports:junit` (GitLab).');
+    return lines.join('\n');
+  }
+  if (report.tests.length === 0) {
+    lines.push('No test both passed and failed at the same commit.');
+    return lines.join('\n');
+  }
+
+  lines.push('| Test | Flake rate | Failures | Flaky commits | Last failure |', '|------|------------|----------|---------------|--------------|');
+  for (const test of report.tests.slice(0, limit)) {
+    const last = test.lastFailure;
+    const when = last ? `${last.createdAt ? last.createdAt.slice(0, 10) : last.run}${last.url ? ` ([run](${last.url}))` : ''}` : '-';
+    lines.push(`| \`${test.id.replace(/\|/g, '\\|')}\` | ${Math.round(test.flakeRate * 100)}% | ${test.failures}/${test.executions} | ${test.flakyCommits} | ${when} |`);
+  }
+  if (report.tests.length > limit) lines.push('', `...and ${report.tests.length - limit} more`);
+
+  const messages = report.tests.slice(0, limit).filter(test => test.lastFailure?.message);
+  if (messages.length) {
+    lines.push('', '### Most recent failures', '');
+    for (const test of messages) {
+      lines.push(`**${test.name}**`, '', '```', test.lastFailure.message, '```', '');
+    }
+  }
+  return lines.join('\n');
+}
+
+if (require.main === module) {
+  const args = process.argv.slice(2);
+  const value = flag => (args.includes(flag) ? args[args.indexOf(flag) + 1] : undefined);
+  const { detectCI } = require('../platform/detect-platform');
+  const { createCache } = require('../cache');
+
+  detectCI().then(platform => {
+    const report = detectFlakyTests(process.cwd(), {
+      platform,
+      limit: Number(value('--limit')) || undefined,
+      workflow: value('--workflow'),
+      branch: value('--branch'),
+      cache: args.includes('--no-cache') ? null : createCache(process.cwd(), 'ci')
+    });
+    const indent = process.stdout.isTTY ? 2 : 0;
+    console.log(JSON.stringify(report, null, indent));
+  });
+}
+
+module.exports = {
+  SUPPORTED_PLATFORMS,
+  parseJUnit,
+  parseGoTestJson,
+  parseJestJson,
+  parseGitlabTestReport,
+  detectFormat,
+  parseReport,
+  collectReports,
+  listRunsCommand,
+  parseRuns,
+  loadRunResults,
+  findFlakyTests,
+  detectFlakyTests,
+  renderReport
+};
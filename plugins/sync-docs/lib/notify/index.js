@@ -0,0 +1,344 @@
+#!/usr/bin/env node
+/**
+ * Slack and Discord Notifications
+ *
+ * Sends a short message to incoming webhooks when something worth telling
+ * the team happens: a release is published, the slop gate fails in CI, or
+ * flaky tests show up. Channels (webhooks) and which command posts to which
+ * channel come from the `notify` key in the project config; nothing is sent
+ * for a command that is not configured. Webhook URLs are credentials, so
+ * channels usually name an environment variable (`urlEnv`) instead of
+ * holding the URL, and errors never include it.
+ *
+ * Usage: node lib/notify/index.js <event> [variables.json] [--dry-run]
+ * Output: JSON delivery result (the message and channels only with --dry-run)
+ *
+ * @module lib/notify
+ */
+
+const fs = require('fs');
+const https = require('https');
+const path = require('path');
+
+const { loadConfig } = require('../config');
+
+const CONFIG_KEY = 'notify';
+
+const CHANNEL_TYPES = ['slack', 'discord'];
+
+/**
+ * Events, the command whose config section routes them, their template
+ * variables, and the default message
+ */
+const EVENTS = {
+  'release-published': {
+    command: 'release',
+    variables: ['project', 'version', 'tag', 'previous', 'commits', 'url', 'compare'],
+    template: 'Released {{project}} {{tag}} ({{commits}} commits)\n{{url}}'
+  },
+  'slop-gate-failed': {
+    command: 'deslop',
+    variables: ['project', 'failOn', 'failing', 'count', 'ref', 'url'],
+    template: 'Slop gate failed on {{project}} {{ref}}: {{failing}} at or above {{failOn}}\n{{url}}'
+  },
+  'flaky-tests-detected': {
+    command: 'flaky',
+    variables: ['project', 'count', 'runs', 'platform', 'tests'],
+    template: '{{count}} flaky tests in {{project}} across {{runs}} {{platform}} runs:\n{{tests}}'
+  }
+};
+
+// Message length limits: Discord rejects content over 2000 characters; Slack truncates long text
+const MESSAGE_LIMITS = { slack: 4000, discord: 2000 };
+
+const VARIABLE = /\{\{\s*(\w+)\s*\}\}/g;
+
+/**
+ * Read and validate the `notify` config
+ * @param {string} basePath - Repository root
+ * @returns {{channels: Object<string, {type: string, url?: string, urlEnv?: string}>,
+ *   commands: Object<string, {channels: string[], template: string|null}>, error: string|null}}
+ */
+function readSettings(basePath) {
+  const { config, file, error } = loadConfig(basePath);
+  const settings = { channels: {}, commands: {}, error };
+  const value = config[CONFIG_KEY];
+  if (error || value === undefined) return settings;
+
+  const fail = message => ({ ...settings, channels: {}, commands: {}, error: `${file}: ${CONFIG_KEY}.${message}` });
+  const isObject = item => item && typeof item === 'object' && !Array.isArray(item);
+  if (!isObject(value)) return { ...settings, error: `${file}: ${CONFIG_KEY} must be an object` };
+
+  for (const [name, channel] of Object.entries(value.channels || {})) {
+    if (!isObject(channel)) return fail(`channels.${name} must be an object`);
+    if (!CHANNEL_TYPES.includes(channel.type)) return fail(`channels.${name}.type must be one of ${CHANNEL_TYPES.join(', ')}`);
+    if ((channel.url === undefined) === (channel.urlEnv === undefined)) return fail(`channels.${name} needs exactly one of url, urlEnv`);
+    if (channel.url !== undefined && !/^https:\/\//.test(String(channel.url))) return fail(`channels.${name}.url must be an https URL`);
+    if (channel.urlEnv !== undefined && !/^[A-Za-z_]\w*$/.test(String(channel.urlEnv))) return fail(`channels.${name}.urlEnv must be an environment variable name`);
+    settings.channels[name] = { type: channel.type, ...(channel.url ? { url: channel.url } : { urlEnv: channel.urlEnv }) };
+  }
+
+  const commands = Object.values(EVENTS).map(event => event.command);
+  for (const [command, route] of Object.entries(value.commands || {})) {
+    if (!commands.includes(command)) return fail(`commands.${command} is not a notifying command (${commands.join(', ')})`);
+    if (route === false) continue;
+    if (!isObject(route)) return fail(`commands.${command} must be an object or false`);
+    const names = [].concat(route.channels || []);
+    if (names.length === 0) return fail(`commands.${command}.channels must name at least one channel`);
+    const unknown = names.find(name => !settings.channels[name]);
+    if (unknown !== undefined) return fail(`commands.${command}.channels: no channel named ${unknown}`);
+    if (route.template !== undefined) {
+      if (typeof route.template !== 'string' || !route.template.trim()) return fail(`commands.${command}.template must be a non-empty string`);
+      const { variables } = Object.values(EVENTS).find(event => event.command === command);
+      const bad = Array.from(route.template.matchAll(VARIABLE), match => match[1]).find(name => !variables.includes(name));
+      if (bad) return fail(`commands.${command}.template uses unknown variable {{${bad}}} (${variables.join(', ')})`);
+    }
+    settings.commands[command] = { channels: names, template: route.template || null };
+  }
+  return settings;
+}
+
+/**
+ * Fill a template
+ * Lines left empty by missing variables are dropped and the gaps they leave
+ * inside a line are closed. Slack control characters in values are escaped
+ * so values cannot inject links or mentions.
+ * @param {string} template - Text with `{{variable}}` placeholders
+ * @param {Object} variables - Values
+ * @param {string} type - Channel type
+ * @returns {string}
+ */
+function renderMessage(template, variables, type) {
+  const escape = type === 'slack'
+    ? text => text.replace(/&/g, '&amp;').replace(/</g, '&lt;').replace(/>/g, '&gt;')
+    : text => text;
+  const lines = template.split('\n').map(line => {
+    let filled = false;
+    const text = line.replace(VARIABLE, (match, name) => {
+      const value = variables[name];
+      if (value === undefined || value === null || value === '') return '';
+      filled = true;
+      return escape(String(value));
+    });
+    if (text === line) return { text, placeholders: false, filled };
+    return { text: text.replace(/ {2,}/g, ' ').replace(/ ([:,.)])/g, '$1').replace(/\s+$/, ''), placeholders: true, filled };
+  });
+  const text = lines.filter(line => !line.placeholders || line.filled).map(line => line.text).join('\n').trim();
+  const limit = MESSAGE_LIMITS[type];
+  return text.length > limit ? `${text.slice(0, limit - 3)}...` : text;
+}
+
+/**
+ * Webhook request body for a channel type
+ * Discord mentions are disabled so a test name like `@everyone` cannot ping.
+ * @param {string} type - Channel type
+ * @param {string} text - Message
+ * @returns {Object}
+ */
+function webhookPayload(type, text) {
+  return type === 'discord' ? { content: text, allowed_mentions: { parse: [] } } : { text };
+}
+
+/**
+ * POST a JSON payload to a webhook
+ * @param {string} url - Webhook URL
+ * @param {Object} payload - Request body
+ * @returns {Promise<void>} Rejects with the status code only; the URL is a credential
+ */
+function postWebhook(url, payload) {
+  return new Promise((resolve, reject) => {
+    const body = JSON.stringify(payload);
+    const req = https.request(url, {
+      method: 'POST',
+      headers: { 'Content-Type': 'application/json', 'Content-Length': Buffer.byteLength(body) },
+      timeout: 15000
+    }, res => {
+      res.resume();
+      res.on('end', () => {
+        if (res.statusCode >= 400) reject(new Error(`webhook returned ${res.statusCode}`));
+        else resolve();
+      });
+    });
+    req.on('timeout', () => req.destroy(new Error('webhook request timed out')));
+    req.on('error', error => reject(new Error(`webhook request failed (${error.code || 'network error'})`)));
+    req.write(body);
+    req.end();
+  });
+}
+
+/**
+ * Resolve the message and webhooks for an event without sending anything
+ * @param {string} basePath - Repository root
+ * @param {string} event - Key of EVENTS
+ * @param {Object} variables - Template values
+ * @param {Object} [options]
+ * @param {Object} [options.env=process.env] - Environment for `urlEnv` channels
+ * @returns {{success: boolean, event: string, command?: string, configured?: boolean,
+ *   deliveries?: Array<{channel: string, type: string, url: string, payload: Object}>,
+ *   skipped?: Array<{channel: string, reason: string}>, error?: string}}
+ */
+function planNotification(basePath, event, variables, options = {}) {
+  const definition = EVENTS[event];
+  if (!definition) return { success: false, event, error: `Unknown event ${event} (${Object.keys(EVENTS).join(', ')})` };
+  const settings = readSettings(basePath);
+  if (settings.error) return { success: false, event, error: settings.error };
+
+  const env = options.env || process.env;
+  const route = settings.commands[definition.command];
+  const plan = { success: true, event, command: definition.command, configured: Boolean(route), deliveries: [], skipped: [] };
+  if (!route) return plan;
+
+  const values = { project: path.basename(path.resolve(basePath)), ...variables };
+  for (const name of route.channels) {
+    const channel = settings.channels[name];
+    const url = channel.url || env[channel.urlEnv];
+    if (!url) {
+      plan.skipped.push({ channel: name, reason: `${channel.urlEnv} is not set` });
+      continue;
+    }
+    const text = renderMessage(route.template || definition.template, values, channel.type);
+    plan.deliveries.push({ channel: name, type: channel.type, url, payload: webhookPayload(channel.type, text) });
+  }
+  return plan;
+}
+
+/**
+ * Send an event to the channels its command is routed to
+ * @param {string} basePath - Repository root
+ * @param {string} event - Key of EVENTS
+ * @param {Object} variables - Template values
+ * @param {Object} [options]
+ * @param {Object} [options.env=process.env] - Environment for `urlEnv` channels
+ * @param {Function} [options.request] - `(url, payload) => Promise` (default: HTTPS POST)
+ * @returns {Promise<{success: boolean, event: string, configured?: boolean, sent?: string[],
+ *   failed?: Array<{channel: string, error: string}>, skipped?: Array<{channel: string, reason: string}>, error?: string}>}
+ */
+async function notify(basePath, event, variables, options = {}) {
+  const plan = planNotification(basePath, event, variables, options);
+  if (!plan.success) return plan;
+
+  const request = options.request || postWebhook;
+  const sent = [];
+  const failed = [];
+  for (const delivery of plan.deliveries) {
+    try {
+      await request(delivery.url, delivery.payload);
+      sent.push(delivery.channel);
+    } catch (error) {
+      failed.push({ channel: delivery.channel, error: error.message });
+    }
+  }
+  return { success: failed.length === 0, event, configured: plan.configured, sent, failed, skipped: plan.skipped };
+}
+
+/**
+ * CI run link and ref name from GitHub Actions or GitLab CI variables
+ * @param {Object} [env=process.env] - Environment
+ * @returns {{url: string|null, ref: string|null}}
+ */
+function ciRun(env = process.env) {
+  if (env.GITHUB_ACTIONS === 'true') {
+    const url = env.GITHUB_REPOSITORY && env.GITHUB_RUN_ID
+      ? `${env.GITHUB_SERVER_URL || 'https://github.com'}/${env.GITHUB_REPOSITORY}/actions/runs/${env.GITHUB_RUN_ID}`
+      : null;
+    return { url, ref: env.GITHUB_HEAD_REF || env.GITHUB_REF_NAME || null };
+  }
+  if (env.GITLAB_CI === 'true') {
+    return { url: env.CI_PIPELINE_URL || null, ref: env.CI_MERGE_REQUEST_SOURCE_BRANCH_NAME || env.CI_COMMIT_REF_NAME || null };
+  }
+  return { url: null, ref: null };
+}
+
+/**
+ * Template values for `release-published`
+ * @param {Object} plan - Result of planRelease
+ * @param {string} [url] - Forge release URL
+ * @returns {Object}
+ */
+function releaseVariables(plan, url) {
+  return {
+    version: plan.version,
+    tag: plan.tag,
+    previous: plan.from || null,
+    commits: plan.commits,
+    url: url || plan.compare || null,
+    compare: plan.compare || null
+  };
+}
+
+/**
+ * Template values for `slop-gate-failed`
+ * @param {Object} gate - Result of evaluateSeverityGate
+ * @param {string} failOn - Failing threshold
+ * @param {Object} [env=process.env] - Environment for the CI run link
+ * @returns {Object}
+ */
+function slopGateVariables(gate, failOn, env = process.env) {
+  const entries = Object.entries(gate.failing);
+  return {
+    failOn,
+    failing: entries.map(([severity, count]) => `${count} ${severity}`).join(', '),
+    count: entries.reduce((total, [, count]) => total + count, 0),
+    ...ciRun(env)
+  };
+}
+
+/**
+ * Template values for `flaky-tests-detected`
+ * @param {Object} report - Result of detectFlakyTests
+ * @param {number} [limit=5] - Tests listed
+ * @returns {Object}
+ */
+function flakyVariables(report, limit = 5) {
+  const listed = report.tests.slice(0, limit).map(test => `- ${test.id} (${Math.round(test.flakeRate * 100)}%, ${test.failures}/${test.executions} failed)`);
+  if (report.tests.length > limit) listed.push(`...and ${report.tests.length - limit} more`);
+  return { count: report.tests.length, runs: report.analyzed, platform: report.platform, tests: listed.join('\n') };
+}
+
+// When run directly, send (or with --dry-run, preview) one event
+if (require.main === module) {
+  const args = process.argv.slice(2);
+  const [event, file] = args.filter(arg => !arg.startsWith('--'));
+  const indent = process.stdout.isTTY ? 2 : 0;
+  if (!event) {
+    console.error(`Usage: node lib/notify/index.js <${Object.keys(EVENTS).join('|')}> [variables.json] [--dry-run]`);
+    process.exit(1);
+  }
+  let variables = {};
+  if (file) {
+    try {
+      variables = JSON.parse(fs.readFileSync(file, 'utf8'));
+    } catch (error) {
+      console.error(`Failed to read ${file}: ${error.message}`);
+      process.exit(1);
+    }
+  }
+
+  if (args.includes('--dry-run')) {
+    const plan = planNotification(process.cwd(), event, variables);
+    const deliveries = (plan.deliveries || []).map(({ url, ...delivery }) => delivery);
+    console.log(JSON.stringify({ ...plan, ...(plan.deliveries ? { deliveries } : {}) }, null, indent));
+    if (!plan.success) process.exitCode = 1;
+  } else {
+    notify(process.cwd(), event, variables).then(result => {
+      console.log(JSON.stringify(result, null, indent));
+      if (!result.success) process.exitCode = 1;
+    });
+  }
+}
+
+module.exports = {
+  CONFIG_KEY,
+  CHANNEL_TYPES,
+  EVENTS,
+  readSettings,
+  renderMessage,
+  webhookPayload,
+  postWebhook,
+  planNotification,
+  notify,
+  ciRun,
+  releaseVariables,
+  slopGateVariables,
+  flakyVariables
+};
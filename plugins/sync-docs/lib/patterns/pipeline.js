@@ -17,6 +17,23 @@ const path = require('path');
 const fs = require('fs');
 const slopPatterns = require('./slop-patterns');
 const analyzers = require('./slop-analyzers');
+const slopAst = require('./slop-ast');
+const { loadCustomPatterns, filterByLanguage, loadScanSettings, applyScanSettings } = require('./custom-patterns');
+const baselines = require('./baseline');
+const diffScope = require('./diff-scope');
+const duplicates = require('./duplicates');
+const secrets = require('./secrets');
+const repoMapCache = require('../repo-map/cache');
+const deadSymbols = require('../repo-map/dead');
+const { detectLinters, findEnforcingRule } = require('../platform/detect-linters');
+const { detectRuntimes } = require('../platform/detect-runtimes');
+const runtimeFeatures = require('./runtime-features');
+const { createLogger } = require('../utils/logger');
+const scanLimits = require('../utils/scan-limits');
+const codeowners = require('../utils/codeowners');
+const { t } = require('../messages');
+
+const log = createLogger('patterns:pipeline');
 
 /**
  * Certainty levels for findings
@@ -42,6 +59,23 @@ const THOROUGHNESS = {
   DEEP: 'deep'
 };
 
+/**
+ * Severities from most to least severe; `explain` marks findings that are
+ * worth a note in review but never a defect on their own
+ */
+const SEVERITIES = ['critical', 'high', 'medium', 'low', 'explain'];
+
+/**
+ * CLI exit code per most severe failing finding (1 is reserved for errors)
+ */
+const SEVERITY_EXIT_CODES = {
+  critical: 2,
+  high: 3,
+  medium: 4,
+  low: 5,
+  explain: 6
+};
+
 /**
  * Run the slop detection pipeline
  *
@@ -52,35 +86,124 @@ const THOROUGHNESS = {
  * @param {string} [options.language] - Filter to specific language
  * @param {string} [options.mode='report'] - report | apply
  * @param {Object} [options.cliTools] - Pre-detected CLI tools (from detectAvailableTools)
- * @returns {Object} Pipeline results: { findings, summary, phase3Prompt, missingTools }
+ * @param {Object[]|null} [options.linters] - Pre-detected linters (from detectLinters); detected when omitted
+ * @param {boolean} [options.includeLinterEnforced=false] - Keep findings the project's linters already enforce
+ * @param {Object[]|null} [options.runtimes] - Pre-detected runtimes (from detectRuntimes); detected when omitted
+ * @param {boolean|Object} [options.ast=true] - AST matching for JS/TS; false forces regex, an object is passed to slop-ast loadParser
+ * @param {Object|false} [options.customPatterns] - Compiled custom patterns by name; loaded from the project
+ *   config (`slopPatterns`) when omitted, false disables them
+ * @param {Object|false} [options.scanSettings] - `ignore` globs and built-in pattern overrides (from
+ *   loadScanSettings); loaded from the project config when omitted, false disables them
+ * @param {Object|false} [options.scanLimits] - Size ceiling and generated/minified opt-ins (from scan-limits
+ *   readSettings); loaded from the project config (`scan`) when omitted, false scans every file
+ * @param {string|Object|false} [options.baseline] - Baseline path (relative to repoPath) or loaded baseline whose
+ *   findings are dropped; `.slop-baseline.json` is used when it exists, false reports everything
+ * @param {string} [options.diffBase] - Only report findings on lines changed since this ref (`git diff <base>...HEAD`
+ *   plus uncommitted edits); targetFiles defaults to the changed source files
+ * @param {Object} [options.changedLines] - Pre-computed changed lines (Map of file to line numbers) for diffBase
+ * @param {Object|false} [options.duplicates] - Duplicate block thresholds ({minLines, minTokens}) overriding the
+ *   duplicate_code pattern; false disables duplicate detection
+ * @param {Object|null} [options.repoMap] - Repo map for dead_code_* checks (defaults to the cached /repo-map map; null skips)
+ * @param {boolean} [options.redactSecrets=false] - Mask secret values in the content of secrets-category findings
+ * @param {Object|false} [options.codeowners] - Parsed CODEOWNERS (from codeowners readCodeowners) whose owners are
+ *   attached to each finding as `owners`; read from the repository when omitted, false attaches none
+ * @returns {Object} Pipeline results: { findings, summary, phase3Prompt, missingTools, linterEnforced, customPatternErrors, configErrors, baseline, diffScope, skipped, codeowners }
  */
 function runPipeline(repoPath, options = {}) {
   const thoroughness = options.thoroughness || THOROUGHNESS.NORMAL;
   const mode = options.mode || 'report';
   const language = options.language || null;
+  const started = Date.now();
 
   const findings = [];
   const missingTools = [];
   let cliTools = options.cliTools || null;
 
+  // Diff scope: changed lines since the base ref
+  let changed = null;
+  if (options.diffBase) {
+    changed = options.changedLines
+      ? { base: options.diffBase, mergeBase: null, files: options.changedLines, error: null }
+      : diffScope.getChangedLines(repoPath, options.diffBase);
+    if (changed.error) throw new Error(changed.error);
+  }
+
+  // Binary, minified, generated, and oversized files are left out and reported
+  const skipFile = options.scanLimits === false
+    ? null
+    : scanLimits.createFileFilter(repoPath, options.scanLimits ? { settings: options.scanLimits } : {});
+
   // Get target files
   let targetFiles = options.targetFiles;
-  if (!targetFiles || targetFiles.length === 0) {
+  const explicitTargets = Boolean(targetFiles && targetFiles.length > 0);
+  if (changed && !explicitTargets) {
+    targetFiles = diffScope.selectSourceFiles(repoPath, changed.files);
+  } else if (!explicitTargets) {
     const result = analyzers.countSourceFiles(repoPath, {
       maxFiles: 1000,
-      includeTests: false
+      includeTests: false,
+      skip: skipFile || undefined
     });
     targetFiles = result.files;
   }
+  if (skipFile && (changed || explicitTargets)) {
+    targetFiles = targetFiles.filter(file => !skipFile(file));
+  }
 
-  // Phase 1: Built-in regex patterns (always runs)
-  const phase1Results = runPhase1(repoPath, targetFiles, language);
+  // Project ignore globs and pattern overrides
+  const scanSettings = options.scanSettings === false
+    ? null
+    : (options.scanSettings || loadScanSettings(repoPath));
+  if (scanSettings && scanSettings.ignore.length > 0) {
+    targetFiles = targetFiles.filter(file => !slopPatterns.isFileExcluded(file, scanSettings.ignore));
+  }
+
+  log.debug('targets selected', {
+    files: targetFiles.length,
+    skipped: skipFile ? skipFile.skipped.length : undefined,
+    explicit: explicitTargets,
+    diffBase: options.diffBase,
+    ignored: scanSettings ? scanSettings.ignore : undefined
+  });
+
+  // Project-defined patterns from .awesome-slash.json
+  let customPatterns = {};
+  let customPatternErrors = [];
+  if (options.customPatterns !== undefined) {
+    customPatterns = options.customPatterns || {};
+  } else {
+    const loaded = loadCustomPatterns(repoPath);
+    customPatterns = loaded.patterns;
+    customPatternErrors = loaded.errors;
+  }
+
+  // Phase 1: Built-in and custom regex patterns (always runs)
+  const astOptions = options.ast === false ? false : (typeof options.ast === 'object' ? options.ast : {});
+  const phase1Results = runPhase1(repoPath, targetFiles, language, astOptions, customPatterns);
   findings.push(...phase1Results);
 
+  // Test files are skipped above; patterns scoped to them (`include`) still run
+  if (!explicitTargets) {
+    const testFiles = selectTestScopedFiles(repoPath, changed, language, customPatterns)
+      .filter(file => !skipFile || !skipFile(file));
+    if (testFiles.length > 0) {
+      findings.push(...runPhase1(repoPath, testFiles, language, astOptions, customPatterns, { testScoped: true }));
+    }
+  }
+
   // Phase 1b: Multi-pass analyzers (if normal or deep)
   if (thoroughness !== THOROUGHNESS.QUICK) {
-    const multiPassResults = runMultiPassAnalyzers(repoPath, targetFiles);
+    const multiPassResults = runMultiPassAnalyzers(repoPath, targetFiles, {
+      duplicates: options.duplicates,
+      repoMap: options.repoMap
+    });
     findings.push(...multiPassResults);
+
+    // Features newer than the runtime versions the project declares
+    const runtimes = options.runtimes !== undefined
+      ? options.runtimes
+      : (detectRuntimes(repoPath) || { runtimes: [] }).runtimes;
+    findings.push(...runRuntimeChecks(repoPath, targetFiles, runtimes || [], language));
   }
 
   // Phase 2: CLI tools (only if deep and tools available)
@@ -110,8 +233,75 @@ function runPipeline(repoPath, options = {}) {
     findings.push(...phase2Results);
   }
 
+  if (scanSettings) {
+    const configured = applyScanSettings(findings, scanSettings, slopPatterns.isFileExcluded);
+    findings.splice(0, findings.length, ...configured.findings);
+  }
+
+  // Keep only findings on lines the diff adds or modifies
+  let diffScopeResult = null;
+  if (changed) {
+    const scoped = diffScope.filterToChangedLines(findings, changed.files);
+    findings.splice(0, findings.length, ...scoped.findings);
+    diffScopeResult = {
+      base: changed.base,
+      mergeBase: changed.mergeBase,
+      changedFiles: changed.files.size,
+      changedLines: Array.from(changed.files.values()).reduce((total, lines) => total + lines.length, 0),
+      outOfScope: scoped.outOfScope
+    };
+  }
+
+  // Drop findings the project's own linters/formatters already enforce
+  let linterEnforced = [];
+  if (!options.includeLinterEnforced) {
+    const linters = options.linters !== undefined
+      ? options.linters
+      : (detectLinters(repoPath) || { linters: [] }).linters;
+    const split = filterLinterEnforced(findings, linters || []);
+    findings.splice(0, findings.length, ...split.findings);
+    linterEnforced = split.skipped;
+  }
+
+  // Drop findings recorded in the baseline (only new or changed ones are reported)
+  let baseline = null;
+  if (options.baseline !== false) {
+    const preloaded = typeof options.baseline === 'object' && options.baseline !== null;
+    const baselineFile = preloaded ? null : (options.baseline || baselines.BASELINE_FILE);
+    const loaded = preloaded
+      ? { baseline: options.baseline, error: null }
+      : baselines.loadBaseline(repoPath, baselineFile);
+    if (loaded.baseline) {
+      const split = baselines.filterBaseline(repoPath, findings, loaded.baseline);
+      findings.splice(0, findings.length, ...split.findings);
+      baseline = { file: baselineFile, suppressed: split.suppressed, error: null };
+    } else if (loaded.error) {
+      baseline = { file: baselineFile, suppressed: 0, error: loaded.error };
+    }
+  }
+
+  // Mask credential values so reports and CI logs don't echo them
+  if (options.redactSecrets) {
+    for (const finding of findings) {
+      const pattern = slopPatterns.slopPatterns[finding.patternName];
+      if (pattern && pattern.category === 'secrets') finding.content = secrets.redactSecret(finding.content);
+    }
+  }
+
+  // Route each finding to its file's CODEOWNERS
+  const owners = options.codeowners !== undefined ? options.codeowners || null : codeowners.readCodeowners(repoPath);
+  if (owners) findings.splice(0, findings.length, ...codeowners.attachOwners(findings, owners));
+
   // Build summary
   const summary = buildSummary(findings);
+  log.debug('scan complete', {
+    thoroughness,
+    files: targetFiles.length,
+    findings: findings.length,
+    linterEnforced: linterEnforced.length,
+    baselineSuppressed: baseline ? baseline.suppressed : undefined,
+    durationMs: Date.now() - started
+  });
 
   // Generate Phase 3 handoff prompt
   const phase3Prompt = formatHandoffPrompt(findings, mode);
@@ -122,31 +312,124 @@ function runPipeline(repoPath, options = {}) {
     phase3Prompt,
     missingTools,
     detectedLanguages,
+    linterEnforced,
+    customPatternErrors,
+    configErrors: scanSettings ? scanSettings.errors : [],
+    baseline,
+    diffScope: diffScopeResult,
+    skipped: skipFile ? { ...skipFile.summary(), error: skipFile.settings.error } : null,
+    codeowners: owners ? owners.file || null : null,
     metadata: {
       repoPath,
       thoroughness,
       mode,
       filesAnalyzed: targetFiles.length,
+      astEngine: astOptions !== false && slopAst.isAvailable(astOptions),
+      customPatterns: Object.keys(customPatterns).length,
       timestamp: new Date().toISOString()
     }
   };
 }
 
 /**
- * Phase 1: Run built-in regex patterns against target files
+ * Separate findings already enforced by the project's linters
+ *
+ * @param {Array} findings - Pipeline findings
+ * @param {Object[]} linters - Detected linters
+ * @returns {{findings: Array, skipped: Array<{patternName: string, enforcedBy: string, count: number}>}}
+ */
+function filterLinterEnforced(findings, linters) {
+  if (linters.length === 0) return { findings: findings.slice(), skipped: [] };
+
+  const kept = [];
+  const skipped = new Map();
+  for (const finding of findings) {
+    const pattern = slopPatterns.slopPatterns[finding.patternName];
+    const rule = pattern && pattern.enforcedBy
+      ? findEnforcingRule(linters, pattern.enforcedBy, analyzers.detectLanguage(finding.file || ''))
+      : null;
+    if (!rule) {
+      kept.push(finding);
+      continue;
+    }
+    const key = `${finding.patternName}\0${rule}`;
+    if (!skipped.has(key)) skipped.set(key, { patternName: finding.patternName, enforcedBy: rule, count: 0 });
+    skipped.get(key).count++;
+  }
+  return { findings: kept, skipped: Array.from(skipped.values()) };
+}
+
+/**
+ * Built-in and custom patterns for a language filter
+ * @param {string|null} language - Optional language filter
+ * @param {Object} customPatterns - Compiled custom patterns
+ * @returns {Object} Patterns by name
+ */
+function getPhase1Patterns(language, customPatterns) {
+  return {
+    ...(language ? slopPatterns.getPatternsForLanguage(language) : slopPatterns.slopPatterns),
+    ...filterByLanguage(customPatterns, language)
+  };
+}
+
+/**
+ * Test files matched by a pattern's `include` globs
+ *
+ * Walks the repository again (or the diff's changed files) with tests included,
+ * and only when some active pattern targets test files.
+ *
+ * @param {string} repoPath - Repository root
+ * @param {Object|null} changed - Diff scope from getChangedLines, or null
+ * @param {string|null} language - Optional language filter
+ * @param {Object} customPatterns - Compiled custom patterns
+ * @returns {string[]}
+ */
+function selectTestScopedFiles(repoPath, changed, language, customPatterns) {
+  const includes = Object.values(getPhase1Patterns(language, customPatterns))
+    .filter(pattern => pattern.include && pattern.pattern)
+    .map(pattern => pattern.include);
+  if (includes.length === 0) return [];
+
+  const candidates = changed
+    ? diffScope.selectSourceFiles(repoPath, changed.files, { includeTests: true })
+    : analyzers.countSourceFiles(repoPath, { maxFiles: 1000, includeTests: true }).files;
+  return candidates.filter(file =>
+    analyzers.isTestFile(file) && includes.some(include => slopPatterns.isFileExcluded(file, include)));
+}
+
+/**
+ * Finding description from the message catalog
+ * `pattern.<name>` translates the pattern library's description; `key`
+ * wraps it with the finding's details.
+ *
+ * @param {string} patternName - Reported pattern name
+ * @param {string} description - Description from the pattern library
+ * @param {string} [key] - Message id with a `{description}` placeholder
+ * @param {Object} [params] - Details for `key`
+ * @returns {string}
+ */
+function describeFinding(patternName, description, key, params = {}) {
+  const text = t(`pattern.${patternName}`, {}, description);
+  return key ? t(key, { ...params, description: text }) : text;
+}
+
+/**
+ * Phase 1: Run built-in and custom regex patterns against target files
  *
  * @param {string} repoPath - Repository root
  * @param {string[]} targetFiles - Files to analyze
  * @param {string|null} language - Optional language filter
+ * @param {Object|false} [astOptions={}] - slop-ast loader options; false disables AST matching
+ * @param {Object} [customPatterns={}] - Compiled custom patterns (see custom-patterns.js)
+ * @param {Object} [options]
+ * @param {boolean} [options.testScoped=false] - Only run patterns with `include` globs (for test files)
  * @returns {Array} Findings with HIGH certainty
  */
-function runPhase1(repoPath, targetFiles, language) {
+function runPhase1(repoPath, targetFiles, language, astOptions = {}, customPatterns = {}, options = {}) {
   const findings = [];
 
   // Get patterns (filtered by language if specified)
-  const patterns = language
-    ? slopPatterns.getPatternsForLanguage(language)
-    : slopPatterns.slopPatterns;
+  const patterns = getPhase1Patterns(language, customPatterns);
 
   for (const file of targetFiles) {
     // Detect file language once per file
@@ -170,15 +453,27 @@ function runPhase1(repoPath, targetFiles, language) {
 
     const lines = content.split('\n');
 
+    // Inline unit tests (`#[cfg(test)] mod tests`) for skipInlineTests patterns
+    const testRanges = fileLanguage === 'rust' ? analyzers.findRustTestRanges(content) : [];
+    const inInlineTests = lineNumber => testRanges.some(([start, end]) => lineNumber >= start && lineNumber <= end);
+    // One secrets finding per line, from the first (most specific) matching pattern
+    const secretLines = new Set();
+
+    // Syntax-tree matches replace the regex for patterns with an `ast` matcher
+    const astMatches = fileLanguage === 'js' && astOptions !== false
+      ? slopAst.findAstMatches(content, file, patterns, astOptions)
+      : null;
+
     for (const [patternName, pattern] of Object.entries(patterns)) {
       // Skip multi-pass patterns (handled separately)
       if (pattern.requiresMultiPass) continue;
 
-      // Skip if no regex pattern
-      if (!pattern.pattern) continue;
+      // Skip if no regex pattern (AST-only custom patterns need the parser)
+      if (!pattern.pattern && !(astMatches && astMatches.has(patternName))) continue;
 
-      // Skip if file matches exclude patterns
+      // Skip if file matches exclude patterns, or misses the include globs
       if (slopPatterns.isFileExcluded(file, pattern.exclude)) continue;
+      if (pattern.include ? !slopPatterns.isFileExcluded(file, pattern.include) : options.testScoped) continue;
 
       // Skip language-specific patterns that don't match file's language
       if (pattern.language) {
@@ -194,6 +489,24 @@ function runPhase1(repoPath, targetFiles, language) {
         if (!langMatch) continue;
       }
 
+      if (astMatches && astMatches.has(patternName)) {
+        for (const lineNumber of astMatches.get(patternName)) {
+          findings.push({
+            file,
+            line: lineNumber,
+            patternName,
+            severity: pattern.severity,
+            certainty: CERTAINTY.HIGH,
+            description: describeFinding(patternName, pattern.description),
+            autoFix: pattern.autoFix,
+            content: (lines[lineNumber - 1] || '').trim().substring(0, 100),
+            phase: 1,
+            details: { engine: 'ast' }
+          });
+        }
+        continue;
+      }
+
       // Handle patterns requiring consecutive line blocks
       if (pattern.minConsecutiveLines) {
         const minLines = pattern.minConsecutiveLines;
@@ -218,7 +531,7 @@ function runPhase1(repoPath, targetFiles, language) {
                 patternName,
                 severity: pattern.severity,
                 certainty: CERTAINTY.HIGH,
-                description: `${pattern.description} (${consecutiveCount} consecutive lines)`,
+                description: describeFinding(patternName, pattern.description, 'finding.consecutiveLines', { count: consecutiveCount }),
                 autoFix: pattern.autoFix,
                 content: `Lines ${consecutiveStart + 1}-${consecutiveStart + consecutiveCount}`,
                 phase: 1,
@@ -229,18 +542,74 @@ function runPhase1(repoPath, targetFiles, language) {
             consecutiveCount = 0;
           }
         }
+      } else if (pattern.multiline) {
+        // Whole-file matching for constructs spanning lines
+        const regex = new RegExp(pattern.pattern.source, pattern.pattern.flags.replace('g', '') + 'g');
+        let lineNumber = 1;
+        let counted = 0;
+        let match;
+        while ((match = regex.exec(content)) !== null) {
+          if (match[0] === '') {
+            regex.lastIndex++;
+            continue;
+          }
+          for (; counted < match.index; counted++) {
+            if (content.charCodeAt(counted) === 10) lineNumber++;
+          }
+          if (pattern.skipInlineTests && inInlineTests(lineNumber)) continue;
+          findings.push({
+            file,
+            line: lineNumber,
+            patternName,
+            severity: pattern.severity,
+            certainty: CERTAINTY.HIGH,
+            description: describeFinding(patternName, pattern.description),
+            autoFix: pattern.autoFix,
+            content: (lines[lineNumber - 1] || '').trim().substring(0, 100),
+            phase: 1
+          });
+        }
+      } else if (pattern.minOccurrences) {
+        // File-level count, reported once at the first match
+        const matched = [];
+        for (let i = 0; i < lines.length; i++) {
+          if (pattern.pattern.test(lines[i]) && !(pattern.skipInlineTests && inInlineTests(i + 1))) matched.push(i + 1);
+        }
+        if (matched.length >= pattern.minOccurrences) {
+          findings.push({
+            file,
+            line: matched[0],
+            patternName,
+            severity: pattern.severity,
+            certainty: CERTAINTY.HIGH,
+            description: describeFinding(patternName, pattern.description, 'finding.occurrences', { count: matched.length }),
+            autoFix: pattern.autoFix,
+            content: `Lines ${matched.join(', ')}`.substring(0, 100),
+            phase: 1,
+            details: { lines: matched, count: matched.length }
+          });
+        }
       } else {
         // Standard per-line matching
         for (let i = 0; i < lines.length; i++) {
           const line = lines[i];
+          if (pattern.skipInlineTests && inInlineTests(i + 1)) continue;
           if (pattern.pattern.test(line)) {
+            if (pattern.requiresEntropyCheck) {
+              const value = secrets.matchedSecretValue(line.match(pattern.pattern));
+              if (secrets.shannonEntropy(value) < (pattern.entropyThreshold || 4.5)) continue;
+            }
+            if (pattern.category === 'secrets') {
+              if (secretLines.has(i)) continue;
+              secretLines.add(i);
+            }
             findings.push({
               file,
               line: i + 1,
               patternName,
               severity: pattern.severity,
               certainty: CERTAINTY.HIGH,
-              description: pattern.description,
+              description: describeFinding(patternName, pattern.description),
               autoFix: pattern.autoFix,
               content: line.trim().substring(0, 100),
               phase: 1
@@ -259,9 +628,12 @@ function runPhase1(repoPath, targetFiles, language) {
  *
  * @param {string} repoPath - Repository root
  * @param {string[]} targetFiles - Files to analyze
- * @returns {Array} Findings with MEDIUM certainty
+ * @param {Object} [options]
+ * @param {Object|false} [options.duplicates] - Duplicate thresholds ({minLines, minTokens}); false disables
+ * @param {Object|null} [options.repoMap] - Repo map for unreferenced file/export checks (defaults to the cached map; null skips)
+ * @returns {Array} Findings with MEDIUM certainty (LOW for unreferenced files, HIGH for .env values)
  */
-function runMultiPassAnalyzers(repoPath, targetFiles) {
+function runMultiPassAnalyzers(repoPath, targetFiles, options = {}) {
   const findings = [];
 
   // Get multi-pass pattern definitions for thresholds
@@ -299,7 +671,7 @@ function runMultiPassAnalyzers(repoPath, targetFiles) {
           patternName: 'doc_code_ratio',
           severity: docCodePattern.severity,
           certainty: CERTAINTY.MEDIUM,
-          description: `${docCodePattern.description} (${v.docLines} doc lines / ${v.codeLines} code lines = ${v.ratio}x)`,
+          description: describeFinding('doc_code_ratio', docCodePattern.description, 'finding.docCodeRatio', v),
           autoFix: docCodePattern.autoFix,
           content: v.functionName ? `${v.functionName}()` : `Function at line ${v.line}`,
           phase: 1,
@@ -324,7 +696,7 @@ function runMultiPassAnalyzers(repoPath, targetFiles) {
           patternName: 'verbosity_ratio',
           severity: verbosityPattern.severity,
           certainty: CERTAINTY.MEDIUM,
-          description: `${verbosityPattern.description} (${v.commentLines} comment lines / ${v.codeLines} code lines = ${v.ratio}x)`,
+          description: describeFinding('verbosity_ratio', verbosityPattern.description, 'finding.commentRatio', v),
           autoFix: verbosityPattern.autoFix,
           content: `Function at line ${v.line}`,
           phase: 1,
@@ -332,6 +704,30 @@ function runMultiPassAnalyzers(repoPath, targetFiles) {
         });
       }
     }
+
+    // Comments that restate the next line (AI narration)
+    const restatingPattern = multiPassPatterns.ai_artifact_restating_comment;
+    if (restatingPattern && !slopPatterns.isFileExcluded(file, restatingPattern.exclude)) {
+      const restatingViolations = analyzers.analyzeRestatingComments(content, {
+        minWords: restatingPattern.minWords || 2,
+        filePath: file
+      });
+
+      for (const v of restatingViolations) {
+        findings.push({
+          file,
+          line: v.line,
+          patternName: 'ai_artifact_restating_comment',
+          severity: restatingPattern.severity,
+          certainty: CERTAINTY.MEDIUM,
+          description: describeFinding('ai_artifact_restating_comment', restatingPattern.description),
+          autoFix: restatingPattern.autoFix,
+          content: v.comment,
+          phase: 1,
+          details: { comment: v.comment, code: v.code }
+        });
+      }
+    }
   }
 
   // Project-level analyzers (run once, not per-file)
@@ -350,7 +746,7 @@ function runMultiPassAnalyzers(repoPath, targetFiles) {
         patternName: 'over_engineering_metrics',
         severity: v.severity,
         certainty: CERTAINTY.MEDIUM,
-        description: `Over-engineering: ${v.type} - ${v.value} (threshold: ${v.threshold})`,
+        description: t('finding.overEngineering', v),
         autoFix: 'flag',
         content: v.value,
         phase: 1,
@@ -403,6 +799,26 @@ function runMultiPassAnalyzers(repoPath, targetFiles) {
     }
   }
 
+  // Local .env values pasted into source
+  const envPattern = multiPassPatterns.env_value_in_source;
+  if (envPattern) {
+    const envFiles = targetFiles.filter(file => !slopPatterns.isFileExcluded(file, envPattern.exclude));
+    for (const v of secrets.findEnvValueLeaks(repoPath, envFiles)) {
+      findings.push({
+        file: v.file,
+        line: v.line,
+        patternName: 'env_value_in_source',
+        severity: envPattern.severity,
+        certainty: CERTAINTY.HIGH,
+        description: describeFinding('env_value_in_source', envPattern.description, 'finding.envValue', v),
+        autoFix: envPattern.autoFix,
+        content: v.content,
+        phase: 1,
+        details: { key: v.key, envFile: v.envFile }
+      });
+    }
+  }
+
   // Dead code analysis (per-file)
   const deadCodePattern = multiPassPatterns.dead_code;
   if (deadCodePattern) {
@@ -427,7 +843,7 @@ function runMultiPassAnalyzers(repoPath, targetFiles) {
           patternName: 'dead_code',
           severity: deadCodePattern.severity,
           certainty: CERTAINTY.MEDIUM,
-          description: `${deadCodePattern.description}: ${v.terminationType} at line ${v.terminationLine}`,
+          description: describeFinding('dead_code', deadCodePattern.description, 'finding.deadCode', v),
           autoFix: deadCodePattern.autoFix,
           content: v.content,
           phase: 1,
@@ -437,6 +853,54 @@ function runMultiPassAnalyzers(repoPath, targetFiles) {
     }
   }
 
+  // Unreferenced files and exports (needs a repo map from /repo-map init)
+  const unreferencedFilePattern = multiPassPatterns.dead_code_unreferenced_file;
+  const unusedExportPattern = multiPassPatterns.dead_code_unused_export;
+  const repoMap = options.repoMap !== undefined ? options.repoMap : repoMapCache.load(repoPath);
+  if (repoMap && (unreferencedFilePattern || unusedExportPattern)) {
+    const targets = new Set(targetFiles.map(file =>
+      (path.isAbsolute(file) ? path.relative(repoPath, file) : file).replace(/\\/g, '/')));
+    const unreferenced = deadSymbols.findUnreferenced(repoMap, {
+      readFile: file => fs.readFileSync(path.join(repoPath, file), 'utf8')
+    });
+
+    if (unreferencedFilePattern) {
+      for (const v of unreferenced.files) {
+        if (!targets.has(v.file) || slopPatterns.isFileExcluded(v.file, unreferencedFilePattern.exclude)) continue;
+        findings.push({
+          file: v.file,
+          line: 1,
+          patternName: 'dead_code_unreferenced_file',
+          severity: unreferencedFilePattern.severity,
+          certainty: CERTAINTY.LOW,
+          description: describeFinding('dead_code_unreferenced_file', unreferencedFilePattern.description),
+          autoFix: unreferencedFilePattern.autoFix,
+          content: v.file,
+          phase: 1,
+          details: { suggestion: v.suggestion }
+        });
+      }
+    }
+
+    if (unusedExportPattern) {
+      for (const v of unreferenced.exports) {
+        if (!targets.has(v.file) || slopPatterns.isFileExcluded(v.file, unusedExportPattern.exclude)) continue;
+        findings.push({
+          file: v.file,
+          line: v.line || 1,
+          patternName: 'dead_code_unused_export',
+          severity: unusedExportPattern.severity,
+          certainty: CERTAINTY.MEDIUM,
+          description: describeFinding('dead_code_unused_export', unusedExportPattern.description, 'finding.unusedExport', v),
+          autoFix: unusedExportPattern.autoFix,
+          content: v.name,
+          phase: 1,
+          details: { name: v.name, kind: v.kind, usedLocally: v.usedLocally, suggestion: v.suggestion }
+        });
+      }
+    }
+  }
+
   // Stub function analysis (per-file, multi-language)
   const stubPattern = multiPassPatterns.placeholder_stub_returns_js;
   if (stubPattern) {
@@ -466,7 +930,7 @@ function runMultiPassAnalyzers(repoPath, targetFiles) {
           patternName: 'placeholder_stub_returns',
           severity: v.hasTodo ? 'high' : stubPattern.severity,
           certainty: v.certainty,
-          description: `${stubPattern.description}: ${v.functionName}() returns ${v.returnValue}`,
+          description: describeFinding('placeholder_stub_returns', stubPattern.description, 'finding.stubReturn', v),
           autoFix: stubPattern.autoFix,
           content: v.content,
           phase: 1,
@@ -476,6 +940,39 @@ function runMultiPassAnalyzers(repoPath, targetFiles) {
     }
   }
 
+  // Duplicate code blocks (cross-file)
+  const duplicatePattern = multiPassPatterns.duplicate_code;
+  if (duplicatePattern && options.duplicates !== false) {
+    const overrides = options.duplicates || {};
+    const candidates = targetFiles.filter(file =>
+      !analyzers.isTestFile(file) && !slopPatterns.isFileExcluded(file, duplicatePattern.exclude));
+    const clones = duplicates.findDuplicates(repoPath, candidates, {
+      minLines: overrides.minLines || duplicatePattern.minLines,
+      minTokens: overrides.minTokens || duplicatePattern.minTokens
+    });
+
+    for (const clone of clones) {
+      findings.push({
+        file: clone.b.file,
+        line: clone.b.startLine,
+        patternName: 'duplicate_code',
+        severity: duplicatePattern.severity,
+        certainty: CERTAINTY.MEDIUM,
+        description: describeFinding('duplicate_code', duplicatePattern.description, 'finding.duplicateCode', { lines: clone.lines, ...clone.a }),
+        autoFix: duplicatePattern.autoFix,
+        content: `Lines ${clone.b.startLine}-${clone.b.endLine}`,
+        phase: 1,
+        details: {
+          startLine: clone.b.startLine,
+          endLine: clone.b.endLine,
+          lines: clone.lines,
+          tokens: clone.tokens,
+          duplicateOf: clone.a
+        }
+      });
+    }
+  }
+
   // Shotgun surgery analysis (git history)
   const shotgunPattern = multiPassPatterns.shotgun_surgery;
   if (shotgunPattern) {
@@ -492,7 +989,7 @@ function runMultiPassAnalyzers(repoPath, targetFiles) {
           patternName: 'shotgun_surgery',
           severity: shotgunPattern.severity,
           certainty: CERTAINTY.MEDIUM,
-          description: `${shotgunPattern.description}: ${v.files.length} files change together ${v.count} times`,
+          description: describeFinding('shotgun_surgery', shotgunPattern.description, 'finding.shotgunSurgery', { files: v.files.length, count: v.count }),
           autoFix: shotgunPattern.autoFix,
           content: v.files.join(', ').substring(0, 100),
           phase: 1,
@@ -507,6 +1004,73 @@ function runMultiPassAnalyzers(repoPath, targetFiles) {
   return findings;
 }
 
+/**
+ * Flag language features newer than the project's declared runtime minimum
+ *
+ * @param {string} repoPath - Repository root
+ * @param {string[]} targetFiles - Files to analyze
+ * @param {Object[]} runtimes - Detected runtimes ({name, minimum, minimumSource})
+ * @param {string|null} [language] - Optional language filter
+ * @returns {Array} Findings with MEDIUM certainty
+ */
+function runRuntimeChecks(repoPath, targetFiles, runtimes, language = null) {
+  const findings = [];
+  const checks = runtimes
+    .filter(runtime => runtime.minimum && runtimeFeatures.RUNTIME_LANGUAGES[runtime.name])
+    .map(runtime => ({
+      runtime,
+      fileLanguage: runtimeFeatures.RUNTIME_LANGUAGES[runtime.name],
+      features: runtimeFeatures.getFeaturesNewerThan(runtime.name, runtime.minimum)
+    }))
+    .filter(check => check.features.length > 0);
+  if (checks.length === 0) return findings;
+
+  for (const file of targetFiles) {
+    const fileLanguage = analyzers.detectLanguage(file);
+    if (language && fileLanguage !== language &&
+        !((language === 'javascript' || language === 'typescript') && fileLanguage === 'js')) continue;
+    const check = checks.find(c => c.fileLanguage === fileLanguage);
+    if (!check) continue;
+
+    const filePath = path.isAbsolute(file) ? file : path.join(repoPath, file);
+    let content;
+    try {
+      content = fs.readFileSync(filePath, 'utf8');
+    } catch {
+      continue;
+    }
+
+    const { runtime, features } = check;
+    const label = runtimeFeatures.RUNTIME_LABELS[runtime.name];
+    const source = runtime.minimumSource || null;
+    const lines = content.split('\n');
+    for (let i = 0; i < lines.length; i++) {
+      const line = lines[i];
+      // Skip comment-only lines
+      if (/^\s*(\/\/|#(?!\[)|\*|\/\*)/.test(line)) continue;
+      for (const feature of features) {
+        if (!feature.pattern.test(line)) continue;
+        findings.push({
+          file,
+          line: i + 1,
+          patternName: 'runtime_feature',
+          severity: 'medium',
+          certainty: CERTAINTY.MEDIUM,
+          description: t(source ? 'finding.runtimeFeatureSource' : 'finding.runtimeFeature', {
+            feature: feature.description, runtime: label, since: feature.since, declared: runtime.minimum, source
+          }),
+          autoFix: 'flag',
+          content: line.trim().substring(0, 100),
+          phase: 1,
+          details: { runtime: runtime.name, feature: feature.name, since: feature.since, declared: runtime.minimum, source }
+        });
+      }
+    }
+  }
+
+  return findings;
+}
+
 /**
  * Phase 2: Run CLI tools (if available)
  *
@@ -530,7 +1094,7 @@ function runPhase2(repoPath, cliTools, targetFiles) {
           patternName: 'code_duplication',
           severity: 'medium',
           certainty: CERTAINTY.LOW,
-          description: `Code duplication: ${dup.lines} lines duplicated in ${dup.secondFile}:${dup.secondLine}`,
+          description: t('finding.codeDuplication', { lines: dup.lines, file: dup.secondFile, line: dup.secondLine }),
           autoFix: 'flag',
           content: `${dup.lines} lines duplicated`,
           phase: 2,
@@ -551,7 +1115,7 @@ function runPhase2(repoPath, cliTools, targetFiles) {
           patternName: 'circular_dependency',
           severity: 'high',
           certainty: CERTAINTY.LOW,
-          description: `Circular dependency: ${cycle.join(' -> ')}`,
+          description: t('finding.circularDependency', { cycle: cycle.join(' -> ') }),
           autoFix: 'flag',
           content: cycle.join(' -> '),
           phase: 2,
@@ -573,7 +1137,7 @@ function runPhase2(repoPath, cliTools, targetFiles) {
             patternName: 'high_complexity',
             severity: result.complexity > 20 ? 'high' : 'medium',
             certainty: CERTAINTY.LOW,
-            description: `High cyclomatic complexity: ${result.complexity} in ${result.name}`,
+            description: t('finding.highComplexity', result),
             autoFix: 'flag',
             content: `${result.name}: complexity ${result.complexity}`,
             phase: 2,
@@ -596,7 +1160,7 @@ function runPhase2(repoPath, cliTools, targetFiles) {
 function buildSummary(findings) {
   const summary = {
     total: findings.length,
-    bySeverity: { critical: 0, high: 0, medium: 0, low: 0 },
+    bySeverity: { critical: 0, high: 0, medium: 0, low: 0, explain: 0 },
     byCertainty: { HIGH: 0, MEDIUM: 0, LOW: 0 },
     byPhase: { 1: 0, 2: 0 },
     byAutoFix: { remove: 0, replace: 0, add_logging: 0, flag: 0, none: 0 },
@@ -614,6 +1178,40 @@ function buildSummary(findings) {
   return summary;
 }
 
+/**
+ * Apply a CI severity policy to findings
+ *
+ * Findings at or above `failOn` fail the run with the exit code of the most
+ * severe one; findings at or above `warnOn` (but below `failOn`) are counted
+ * as warnings; anything less severe is ignored. `none` disables a threshold.
+ *
+ * @param {Object} bySeverity - Finding counts by severity (summary.bySeverity)
+ * @param {Object} [policy]
+ * @param {string} [policy.failOn='critical'] - Lowest severity that fails
+ * @param {string} [policy.warnOn='medium'] - Lowest severity that warns
+ * @returns {{exitCode: number, failing: Object, warnings: Object}} Counts by severity for each bucket
+ */
+function evaluateSeverityGate(bySeverity, policy = {}) {
+  const rank = level => (level === 'none' ? -1 : SEVERITIES.indexOf(level));
+  const failRank = rank(policy.failOn || 'critical');
+  const warnRank = rank(policy.warnOn || 'medium');
+
+  const failing = {};
+  const warnings = {};
+  let exitCode = 0;
+  SEVERITIES.forEach((severity, index) => {
+    const count = bySeverity[severity] || 0;
+    if (count === 0) return;
+    if (index <= failRank) {
+      failing[severity] = count;
+      if (exitCode === 0) exitCode = SEVERITY_EXIT_CODES[severity];
+    } else if (index <= warnRank) {
+      warnings[severity] = count;
+    }
+  });
+  return { exitCode, failing, warnings };
+}
+
 /**
  * Format handoff prompt for LLM (Phase 3)
  *
@@ -763,6 +1361,7 @@ function formatFindingsList(findings) {
         ? ` [${f.autoFix}]`
         : '';
       output += `- L${f.line}: ${f.description}${fixTag}\n`;
+      if (f.details && f.details.suggestion) output += `  - Suggestion: ${f.details.suggestion}\n`;
     }
     output += '\n';
   }
@@ -775,11 +1374,16 @@ module.exports = {
   // Exported for testing
   runPhase1,
   runMultiPassAnalyzers,
+  runRuntimeChecks,
   runPhase2,
+  filterLinterEnforced,
   buildSummary,
+  evaluateSeverityGate,
   formatHandoffPrompt,
   formatCompactPrompt,
   // Constants
   CERTAINTY,
-  THOROUGHNESS
+  THOROUGHNESS,
+  SEVERITIES,
+  SEVERITY_EXIT_CODES
 };
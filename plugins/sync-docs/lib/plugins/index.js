@@ -0,0 +1,460 @@
+/**
+ * Third-party Command Plugins
+ * Discovers plugins and lets them register commands that run with the same
+ * context the built-in commands use: the platform detection result, a
+ * repo-map handle, and the project config.
+ *
+ * Plugins are found, in this order:
+ * - npm packages named `awesome-slash-plugin-*` or `awsome-slash-plugin-*`
+ *   (scoped names too) in the project's node_modules
+ * - `<state-dir>/plugins/`: a `.js` file, or a directory with an index.js
+ *   or package.json `main`
+ * - `plugins.paths` in the project config
+ *
+ * `plugins.disable` lists plugin names to skip. A plugin exports a function
+ * that receives `{ registerCommand, registerDetector }`, or an object with
+ * `commands` (and optionally `detectors`) arrays:
+ *
+ *   module.exports = ({ registerCommand }) => registerCommand({
+ *     name: 'licenses-summary',
+ *     description: 'Summarize licenses per workspace',
+ *     argumentHint: '[--json]',
+ *     async run({ detection, repoMap, config }, args) {
+ *       return { projectType: detection.projectType, args };
+ *     }
+ *   });
+ *
+ * @module lib/plugins
+ */
+
+const fs = require('fs');
+const path = require('path');
+const { getStateDirPath } = require('../platform/state-dir');
+const { registerDetector, unregisterDetector } = require('../platform/detector-registry');
+const { loadConfig } = require('../config');
+const repoMap = require('../repo-map');
+const { createLogger } = require('../utils/logger');
+const { relativePath } = require('../utils/exec');
+
+const log = createLogger('plugins');
+
+/**
+ * npm package name prefixes that mark a plugin
+ */
+const PACKAGE_PREFIXES = ['awesome-slash-plugin-', 'awsome-slash-plugin-'];
+
+/**
+ * Directory (inside the state dir) holding project-local plugins
+ */
+const PROJECT_PLUGINS_DIR = 'plugins';
+
+/**
+ * Command names: lowercase, digits, and dashes, starting with a letter
+ */
+const COMMAND_NAME_PATTERN = /^[a-z][a-z0-9-]*$/;
+
+/**
+ * Built-in command names; plugins cannot take them
+ */
+const BUILTIN_COMMANDS = [
+  'audit-project', 'benchmark', 'changelog', 'commit', 'coverage', 'delivery-approval',
+  'deps-audit', 'deslop', 'docs-gen', 'drift-detect', 'enhance', 'env-check',
+  'flaky', 'install-hooks', 'issue', 'license-check', 'migrate', 'next-task',
+  'onboard', 'pr-description', 'release', 'report', 'repo-map', 'resolve', 'sbom', 'ship', 'sync-docs',
+  'test-gen', 'todo-triage', 'trends', 'update-docs-around'
+];
+
+/**
+ * Claude Code project slash commands, where `plugins sync` writes wrappers
+ * (a fixed location, unlike the platform-aware state dir)
+ */
+const COMMANDS_DIR = path.join('.claude', 'commands');
+
+/**
+ * First line generated command wrappers start with (after the frontmatter)
+ */
+const WRAPPER_MARKER = '<!-- generated by awesome-slash plugins sync -->';
+
+/**
+ * Registered commands by name, in registration order
+ * @type {Map<string, Object>}
+ */
+const _commands = new Map();
+
+/**
+ * Plugin load results, by resolved project root
+ * @type {Map<string, {plugins: Object[], errors: Array<Object>}>}
+ */
+const _loadedProjects = new Map();
+
+/**
+ * Register a plugin command
+ * @param {Object} command - Command definition
+ * @param {string} command.name - Unique name, used as `/<name>`
+ * @param {Function} command.run - `async (context, args) => result`
+ * @param {string} [command.description] - One-line description
+ * @param {string} [command.argumentHint] - Argument summary (e.g. `[--json]`)
+ * @param {string} [command.plugin] - Plugin that registered it
+ * @param {string} [command.source] - Plugin path (relative to the project root)
+ * @returns {Object} The normalized command
+ * @throws {Error} When the definition is invalid or the name is taken
+ */
+function registerCommand(command) {
+  if (!command || typeof command !== 'object') {
+    throw new Error('Command must be an object with name and run()');
+  }
+  const { name, run, description = '', argumentHint = '', plugin = null, source = null } = command;
+  if (typeof name !== 'string' || !COMMAND_NAME_PATTERN.test(name)) {
+    throw new Error(`Invalid command name: ${name}`);
+  }
+  if (BUILTIN_COMMANDS.includes(name)) {
+    throw new Error(`Command "${name}" is a built-in command`);
+  }
+  if (typeof run !== 'function') {
+    throw new Error(`Command "${name}" must provide a run() function`);
+  }
+  if (typeof description !== 'string' || typeof argumentHint !== 'string') {
+    throw new Error(`Command "${name}" description and argumentHint must be strings`);
+  }
+  if (_commands.has(name)) {
+    const existing = _commands.get(name);
+    throw new Error(`Command "${name}" is already registered${existing.plugin ? ` by ${existing.plugin}` : ''}`);
+  }
+
+  const normalized = { name, description, argumentHint, run, plugin, source };
+  _commands.set(name, normalized);
+  return normalized;
+}
+
+/**
+ * Remove a registered command
+ * @param {string} name - Command name
+ * @returns {boolean} True if it was registered
+ */
+function unregisterCommand(name) {
+  return _commands.delete(name);
+}
+
+/**
+ * Registered commands in registration order
+ * @returns {Object[]}
+ */
+function getCommands() {
+  return Array.from(_commands.values());
+}
+
+/**
+ * A registered command
+ * @param {string} name - Command name
+ * @returns {Object|null}
+ */
+function getCommand(name) {
+  return _commands.get(name) || null;
+}
+
+/**
+ * Whether a package name marks a plugin
+ * @param {string} name - Package name, scoped or not
+ * @returns {boolean}
+ */
+function isPluginPackage(name) {
+  const base = name.startsWith('@') ? name.split('/')[1] || '' : name;
+  return PACKAGE_PREFIXES.some(prefix => base.startsWith(prefix) && base.length > prefix.length);
+}
+
+/**
+ * Directory entries, or none when the directory cannot be read
+ * @param {string} dir - Directory
+ * @returns {fs.Dirent[]}
+ */
+function readEntries(dir) {
+  try {
+    return fs.readdirSync(dir, { withFileTypes: true }).sort((a, b) => a.name.localeCompare(b.name));
+  } catch {
+    return [];
+  }
+}
+
+/**
+ * Read the `plugins` settings from the project config
+ * @param {Object} config - Project config
+ * @param {string} file - Config filename for error messages
+ * @returns {{paths: string[], disable: string[], errors: string[]}}
+ */
+function readSettings(config, file) {
+  const settings = config.plugins;
+  const result = { paths: [], disable: [], errors: [] };
+  if (settings === undefined) return result;
+  if (!settings || typeof settings !== 'object' || Array.isArray(settings)) {
+    result.errors.push(`${file}: plugins must be an object`);
+    return result;
+  }
+  for (const key of ['paths', 'disable']) {
+    const value = settings[key];
+    if (value === undefined) continue;
+    if (!Array.isArray(value) || value.some(item => typeof item !== 'string' || !item)) {
+      result.errors.push(`${file}: plugins.${key} must be an array of strings`);
+      continue;
+    }
+    result[key] = value;
+  }
+  return result;
+}
+
+/**
+ * Find plugins for a project
+ * @param {string} [basePath=process.cwd()] - Project root
+ * @param {Object} [config] - Project config (read from basePath when omitted)
+ * @returns {Array<{name: string, path: string, source: string, kind: string}>} `kind` is package, project, or config
+ */
+function discoverPlugins(basePath = process.cwd(), config = loadConfig(basePath).config) {
+  const root = path.resolve(basePath);
+  const relative = file => relativePath(root, file);
+  const found = [];
+  const add = (name, file, kind) => {
+    if (!found.some(plugin => plugin.path === file)) {
+      found.push({ name, path: file, source: relative(file), kind });
+    }
+  };
+
+  const modules = path.join(root, 'node_modules');
+  for (const entry of readEntries(modules)) {
+    if (entry.name.startsWith('@') && entry.isDirectory()) {
+      for (const scoped of readEntries(path.join(modules, entry.name))) {
+        const name = `${entry.name}/${scoped.name}`;
+        if (isPluginPackage(name)) add(name, path.join(modules, entry.name, scoped.name), 'package');
+      }
+    } else if (isPluginPackage(entry.name)) {
+      add(entry.name, path.join(modules, entry.name), 'package');
+    }
+  }
+
+  const projectDir = path.join(getStateDirPath(root), PROJECT_PLUGINS_DIR);
+  for (const entry of readEntries(projectDir)) {
+    if (entry.isFile() && /\.c?js$/.test(entry.name)) {
+      add(entry.name.replace(/\.c?js$/, ''), path.join(projectDir, entry.name), 'project');
+    } else if (entry.isDirectory()) {
+      add(entry.name, path.join(projectDir, entry.name), 'project');
+    }
+  }
+
+  const { paths, disable } = readSettings(config, 'config');
+  for (const configured of paths) {
+    const file = path.resolve(root, configured);
+    add(path.basename(file).replace(/\.c?js$/, ''), file, 'config');
+  }
+
+  return found.filter(plugin => !disable.includes(plugin.name));
+}
+
+/**
+ * Register everything a plugin module exports
+ * @param {*} exported - module.exports of the plugin
+ * @param {Object} plugin - Discovered plugin
+ * @param {{commands: string[], detectors: string[]}} registered - Collects the names registered
+ * @returns {void}
+ */
+function registerExports(exported, plugin, registered) {
+  if (exported && exported.__esModule && exported.default) exported = exported.default;
+  const api = {
+    registerCommand: command => {
+      const normalized = registerCommand({ ...command, plugin: plugin.name, source: plugin.source });
+      registered.commands.push(normalized.name);
+      return normalized;
+    },
+    registerDetector: detector => {
+      const normalized = registerDetector({ ...detector, source: plugin.source });
+      registered.detectors.push(normalized.name);
+      return normalized;
+    }
+  };
+
+  if (typeof exported === 'function') {
+    exported(api);
+  } else if (exported && typeof exported === 'object' && (exported.commands || exported.detectors)) {
+    for (const command of exported.commands || []) api.registerCommand(command);
+    for (const detector of exported.detectors || []) api.registerDetector(detector);
+  } else {
+    throw new Error('Plugin must export a function or an object with commands');
+  }
+}
+
+/**
+ * Discover and load a project's plugins
+ * Each project loads once per process; broken plugins are reported, not
+ * thrown. Whatever a plugin registered before failing is removed again.
+ * @param {string} [basePath=process.cwd()] - Project root
+ * @returns {{plugins: Array<Object>, errors: Array<{plugin: string, source: string, error: string}>}}
+ */
+function loadPlugins(basePath = process.cwd()) {
+  const key = path.resolve(basePath);
+  if (_loadedProjects.has(key)) {
+    return _loadedProjects.get(key);
+  }
+
+  const { config, file, error } = loadConfig(key);
+  const settings = readSettings(config, file);
+  const errors = [error, ...settings.errors]
+    .filter(Boolean)
+    .map(message => ({ plugin: null, source: file, error: message }));
+  const plugins = [];
+
+  for (const plugin of discoverPlugins(key, config)) {
+    const registered = { commands: [], detectors: [] };
+    try {
+      registerExports(require(plugin.path), plugin, registered);
+      plugins.push({ name: plugin.name, source: plugin.source, kind: plugin.kind, ...registered });
+      log.debug('plugin loaded', { plugin: plugin.name, source: plugin.source, commands: registered.commands });
+    } catch (err) {
+      registered.commands.forEach(unregisterCommand);
+      registered.detectors.forEach(unregisterDetector);
+      errors.push({ plugin: plugin.name, source: plugin.source, error: err.message.split('\n')[0] });
+    }
+  }
+
+  const result = { plugins, errors };
+  _loadedProjects.set(key, result);
+  return result;
+}
+
+/**
+ * Build the context a command runs with
+ * Detection reads the current directory, so basePath should be cwd.
+ * @param {string} [basePath=process.cwd()] - Project root
+ * @param {Object} [options] - Options
+ * @param {Function} [options.detect] - Replaces platform detection (for tests)
+ * @param {boolean} [options.refresh] - Skip the detection cache
+ * @returns {Promise<Object>} `{basePath, detection, repoMap, config, configFile, configError}`
+ */
+async function createContext(basePath = process.cwd(), options = {}) {
+  const root = path.resolve(basePath);
+  const detect = options.detect || (refresh => require('../platform/detect-platform').detect(refresh));
+  const { config, file, error } = loadConfig(root);
+  return {
+    basePath: root,
+    detection: await detect(Boolean(options.refresh)),
+    repoMap: {
+      exists: () => repoMap.exists(root),
+      load: () => repoMap.load(root),
+      status: () => repoMap.status(root),
+      findSymbols: (query, queryOptions) => repoMap.query.findSymbols(repoMap.load(root), query, queryOptions),
+      findCallers: (name, queryOptions) => repoMap.query.findCallers(repoMap.load(root), name, queryOptions)
+    },
+    config,
+    configFile: file,
+    configError: error
+  };
+}
+
+/**
+ * Run a plugin command
+ * @param {string} name - Command name
+ * @param {string[]} [args] - Arguments
+ * @param {Object} [options] - createContext options, plus basePath
+ * @returns {Promise<{success: boolean, command: string, result?: *, error?: string}>}
+ */
+async function runCommand(name, args = [], options = {}) {
+  const basePath = options.basePath || process.cwd();
+  loadPlugins(basePath);
+  const command = getCommand(name);
+  if (!command) {
+    const builtin = BUILTIN_COMMANDS.includes(name) ? ` (/${name} is built in; run it from your assistant)` : '';
+    return { success: false, command: name, error: `Unknown plugin command: ${name}${builtin}` };
+  }
+  try {
+    const context = await createContext(basePath, options);
+    return { success: true, command: name, result: await command.run(context, args) };
+  } catch (error) {
+    return { success: false, command: name, error: error.message };
+  }
+}
+
+/**
+ * Render the slash command file that forwards a plugin command to the CLI
+ * @param {Object} command - Registered command
+ * @returns {string} Markdown for `<COMMANDS_DIR>/<name>.md`
+ */
+function renderCommandFile(command) {
+  const description = (command.description || `Run the ${command.name} plugin command`).replace(/\.$/, '');
+  const lines = [
+    '---',
+    `description: ${JSON.stringify(description)}`,
+    ...(command.argumentHint ? [`argument-hint: ${JSON.stringify(command.argumentHint)}`] : []),
+    'allowed-tools: Bash(npx:*)',
+    '---',
+    '',
+    WRAPPER_MARKER,
+    '',
+    `# /${command.name}`,
+    '',
+    `${description}. Provided by the \`${command.plugin}\` plugin (\`${command.source}\`).`,
+    '',
+    '```bash',
+    `npx --no-install awesome-slash run ${command.name} $ARGUMENTS`,
+    '```',
+    '',
+    'Show the JSON result to the user. On `"success": false`, report the error.',
+    ''
+  ];
+  return lines.join('\n');
+}
+
+/**
+ * Write `<COMMANDS_DIR>/<name>.md` for each registered command
+ * Files without the generated marker belong to the user and are left alone.
+ * @param {string} [basePath=process.cwd()] - Project root
+ * @returns {{written: string[], skipped: string[]}} Relative paths
+ */
+function syncCommandFiles(basePath = process.cwd()) {
+  const dir = path.join(path.resolve(basePath), COMMANDS_DIR);
+  const written = [];
+  const skipped = [];
+  for (const command of getCommands()) {
+    const file = path.join(dir, `${command.name}.md`);
+    const relative = relativePath(basePath, file);
+    const content = renderCommandFile(command);
+    let current = null;
+    try {
+      current = fs.readFileSync(file, 'utf8');
+    } catch {
+      current = null;
+    }
+    if (current !== null && !current.includes(WRAPPER_MARKER)) {
+      skipped.push(relative);
+      continue;
+    }
+    if (current !== content) {
+      fs.mkdirSync(dir, { recursive: true });
+      fs.writeFileSync(file, content);
+      written.push(relative);
+    }
+  }
+  return { written, skipped };
+}
+
+/**
+ * Remove all registered commands and forget loaded projects (for tests)
+ */
+function clearCommands() {
+  _commands.clear();
+  _loadedProjects.clear();
+}
+
+module.exports = {
+  PACKAGE_PREFIXES,
+  PROJECT_PLUGINS_DIR,
+  COMMANDS_DIR,
+  BUILTIN_COMMANDS,
+  registerCommand,
+  unregisterCommand,
+  getCommands,
+  getCommand,
+  isPluginPackage,
+  discoverPlugins,
+  loadPlugins,
+  createContext,
+  runCommand,
+  renderCommandFile,
+  syncCommandFiles,
+  clearCommands
+};
@@ -0,0 +1,56 @@
+/**
+ * Language-specific symbol details
+ *
+ * ast-grep patterns locate symbols; these parsers enrich the resulting
+ * entries with structure that patterns alone cannot express.
+ *
+ * @module lib/repo-map/details
+ */
+
+'use strict';
+
+const { applyPythonDetails, parsePythonDefinitions } = require('./python');
+const { applyTypeScriptDetails, parseTypeScriptDeclarations } = require('./typescript');
+const { applyJavaDetails, applyKotlinDetails, parseJvmDeclarations } = require('./jvm');
+const { applyCDetails, parseMacros } = require('./c');
+const { applyRubyDetails, parseRubyDefinitions } = require('./ruby');
+const { applyCSharpDetails, parseCSharpDeclarations } = require('./csharp');
+const { applyGoDetails, parseGoDeclarations } = require('./go');
+
+const DETAIL_HANDLERS = {
+  python: applyPythonDetails,
+  typescript: applyTypeScriptDetails,
+  javascript: applyTypeScriptDetails,
+  java: applyJavaDetails,
+  kotlin: applyKotlinDetails,
+  c: applyCDetails,
+  cpp: applyCDetails,
+  ruby: applyRubyDetails,
+  csharp: applyCSharpDetails,
+  go: applyGoDetails
+};
+
+/**
+ * Apply language-specific details to symbol maps in place
+ * @param {string} language - Language name
+ * @param {string} content - File content
+ * @param {Object} symbolMaps - Maps of exports/functions/classes/types/constants
+ * @param {Object} [context] - Scan context
+ * @param {string} [context.file] - Repository-relative file path
+ */
+function applySymbolDetails(language, content, symbolMaps, context = {}) {
+  const handler = DETAIL_HANDLERS[language];
+  if (!handler || !content || !symbolMaps) return;
+  handler(content, symbolMaps, context);
+}
+
+module.exports = {
+  applySymbolDetails,
+  parsePythonDefinitions,
+  parseTypeScriptDeclarations,
+  parseJvmDeclarations,
+  parseMacros,
+  parseRubyDefinitions,
+  parseCSharpDeclarations,
+  parseGoDeclarations
+};
@@ -0,0 +1,171 @@
+/**
+ * .NET solution/project grouping for repo-map
+ *
+ * SDK-style projects include every `.cs` file under their directory, so a
+ * source file belongs to the nearest `.csproj` above it.
+ *
+ * @module lib/repo-map/dotnet
+ */
+
+'use strict';
+
+const fs = require('fs');
+const path = require('path');
+
+const SLN_PROJECT = /^Project\("\{[^}]+\}"\)\s*=\s*"([^"]+)",\s*"([^"]+\.csproj)"/gm;
+
+/**
+ * Normalize a path to forward slashes without leading `./`
+ * @param {string} value - Path
+ * @returns {string}
+ */
+function toPosix(value) {
+  return path.posix.normalize(value.replace(/\\/g, '/')).replace(/^\.\//, '');
+}
+
+/**
+ * Read all values of an MSBuild item's Include attribute
+ * @param {string} xml - Project file content
+ * @param {string} item - Item name (e.g. ProjectReference)
+ * @returns {string[]}
+ */
+function readIncludes(xml, item) {
+  const regex = new RegExp(`<${item}\\s+[^>]*Include="([^"]+)"`, 'g');
+  const values = [];
+  let match;
+  while ((match = regex.exec(xml)) !== null) values.push(match[1]);
+  return values;
+}
+
+/**
+ * Parse a `.csproj` file
+ * @param {string} basePath - Repository root
+ * @param {string} relativePath - Project path relative to root
+ * @returns {Object}
+ */
+function parseProject(basePath, relativePath) {
+  const project = {
+    name: path.posix.basename(relativePath, '.csproj'),
+    path: relativePath,
+    sdk: null,
+    targetFrameworks: [],
+    references: [],
+    packages: [],
+    solutions: [],
+    files: 0
+  };
+
+  let xml = '';
+  try {
+    xml = fs.readFileSync(path.join(basePath, relativePath), 'utf8');
+  } catch {
+    return project;
+  }
+
+  const sdk = xml.match(/<Project\s+[^>]*Sdk="([^"]+)"/);
+  if (sdk) project.sdk = sdk[1];
+
+  const frameworks = xml.match(/<TargetFrameworks?>([^<]+)<\/TargetFrameworks?>/);
+  if (frameworks) project.targetFrameworks = frameworks[1].split(';').map(item => item.trim()).filter(Boolean);
+
+  const assemblyName = xml.match(/<AssemblyName>([^<]+)<\/AssemblyName>/);
+  if (assemblyName) project.assemblyName = assemblyName[1].trim();
+
+  const dir = path.posix.dirname(relativePath);
+  project.references = readIncludes(xml, 'ProjectReference').map(ref => toPosix(path.posix.join(dir, ref.replace(/\\/g, '/'))));
+  project.packages = readIncludes(xml, 'PackageReference');
+
+  return project;
+}
+
+/**
+ * Parse a `.sln` file into project paths
+ * @param {string} basePath - Repository root
+ * @param {string} relativePath - Solution path relative to root
+ * @returns {string[]} - Project paths relative to root
+ */
+function parseSolution(basePath, relativePath) {
+  let content = '';
+  try {
+    content = fs.readFileSync(path.join(basePath, relativePath), 'utf8');
+  } catch {
+    return [];
+  }
+
+  const dir = path.posix.dirname(relativePath);
+  const projects = [];
+  SLN_PROJECT.lastIndex = 0;
+  let match;
+  while ((match = SLN_PROJECT.exec(content)) !== null) {
+    projects.push(toPosix(path.posix.join(dir, match[2].replace(/\\/g, '/'))));
+  }
+  return projects;
+}
+
+/**
+ * Find the project owning a source file (nearest project directory)
+ * @param {string} file - Source path relative to root
+ * @param {Object[]} projects - Parsed projects
+ * @returns {Object|null}
+ */
+function findOwningProject(file, projects) {
+  let owner = null;
+  let ownerDepth = -1;
+  for (const project of projects) {
+    const dir = path.posix.dirname(project.path);
+    const prefix = dir === '.' ? '' : `${dir}/`;
+    if (!file.startsWith(prefix)) continue;
+    const depth = prefix.split('/').length;
+    if (depth > ownerDepth) {
+      owner = project;
+      ownerDepth = depth;
+    }
+  }
+  return owner;
+}
+
+/**
+ * Group C# files by project and attach solution membership
+ * Sets `map.projects` (keyed by `.csproj` path) and `project` on each C# file.
+ * @param {Object} map - Repo map
+ * @param {string} basePath - Repository root
+ * @param {{projects: string[], solutions: string[]}} manifests - Relative `.csproj`/`.sln` paths
+ */
+function groupDotnetProjects(map, basePath, manifests) {
+  if (!map || !map.files) return;
+  const projectPaths = (manifests.projects || []).map(toPosix);
+  if (projectPaths.length === 0) {
+    delete map.projects;
+    return;
+  }
+
+  const projects = projectPaths.sort().map(projectPath => parseProject(basePath, projectPath));
+  const byPath = new Map(projects.map(project => [project.path, project]));
+
+  for (const solution of (manifests.solutions || []).map(toPosix).sort()) {
+    for (const projectPath of parseSolution(basePath, solution)) {
+      const project = byPath.get(projectPath);
+      if (project && !project.solutions.includes(solution)) project.solutions.push(solution);
+    }
+  }
+
+  for (const [file, fileData] of Object.entries(map.files)) {
+    if (fileData.language !== 'csharp') continue;
+    const owner = findOwningProject(file, projects);
+    if (owner) {
+      fileData.project = owner.path;
+      owner.files++;
+    } else {
+      delete fileData.project;
+    }
+  }
+
+  map.projects = Object.fromEntries(projects.map(project => [project.path, project]));
+}
+
+module.exports = {
+  groupDotnetProjects,
+  parseProject,
+  parseSolution,
+  findOwningProject
+};
@@ -0,0 +1,117 @@
+/**
+ * Worker pool for repo-map extraction
+ *
+ * Full scans are dominated by ast-grep runs and per-file post-processing.
+ * Files are sharded across worker threads (one per CPU, capped) so each
+ * worker drives its own ast-grep processes; small scans stay in-process.
+ *
+ * @module lib/repo-map/pool
+ */
+
+'use strict';
+
+const os = require('os');
+const path = require('path');
+const { Worker } = require('worker_threads');
+
+const WORKER_SCRIPT = path.join(__dirname, 'extract-worker.js');
+// Default cap; past this, extra ast-grep processes mostly contend for disk
+const MAX_DEFAULT_CONCURRENCY = 8;
+// Below this many files per worker, thread startup costs more than it saves
+const MIN_FILES_PER_WORKER = 50;
+
+/**
+ * Resolve how many workers to use for a batch of files
+ * @param {number} fileCount - Files to extract
+ * @param {Object} [options]
+ * @param {number} [options.concurrency] - Maximum workers (default: CPU count, capped at 8)
+ * @param {number} [options.minFilesPerWorker=50] - Minimum shard size
+ * @returns {number} - Worker count (1 means run in-process)
+ */
+function getWorkerCount(fileCount, options = {}) {
+  const cpus = Math.max(1, (os.cpus() || []).length);
+  const requested = Number.isInteger(options.concurrency) && options.concurrency > 0
+    ? options.concurrency
+    : Math.min(cpus, MAX_DEFAULT_CONCURRENCY);
+  const minFiles = options.minFilesPerWorker || MIN_FILES_PER_WORKER;
+  return Math.max(1, Math.min(requested, Math.floor(fileCount / minFiles)));
+}
+
+/**
+ * Split entries into balanced shards (largest files first, each to the lightest shard)
+ * @param {Array<{content: string}>} entries - File entries
+ * @param {number} count - Number of shards
+ * @returns {Array<Array>} - Non-empty shards
+ */
+function shardEntries(entries, count) {
+  const shards = Array.from({ length: count }, () => ({ size: 0, entries: [] }));
+  const bySize = entries.slice().sort((a, b) => (b.content || '').length - (a.content || '').length);
+  for (const entry of bySize) {
+    let lightest = shards[0];
+    for (const shard of shards) {
+      if (shard.size < lightest.size) lightest = shard;
+    }
+    lightest.entries.push(entry);
+    lightest.size += (entry.content || '').length || 1;
+  }
+  return shards.map(shard => shard.entries).filter(shard => shard.length > 0);
+}
+
+/**
+ * Extract one shard in a worker thread
+ * @param {Object} data - Worker payload
+ * @returns {Promise<Object>} - Results keyed by relative path
+ */
+function runWorker(data) {
+  return new Promise((resolve, reject) => {
+    const worker = new Worker(WORKER_SCRIPT, { workerData: data });
+    let settled = false;
+    worker.once('message', (message) => {
+      settled = true;
+      if (message && message.error) reject(new Error(message.error));
+      else resolve(message.results);
+    });
+    worker.once('error', (err) => {
+      settled = true;
+      reject(err);
+    });
+    worker.once('exit', (code) => {
+      if (!settled) reject(new Error(`Extraction worker exited with code ${code}`));
+    });
+  });
+}
+
+/**
+ * Extract files for one language, in parallel when the batch is large enough
+ * A shard whose worker fails is extracted in-process instead.
+ * @param {string} cmd - ast-grep command
+ * @param {string} basePath - Repository root
+ * @param {string} language - Language name
+ * @param {Array<{file: string, relativePath: string, content: string}>} entries - Files to extract
+ * @param {Object} options
+ * @param {Function} options.extract - In-process extractor `(cmd, basePath, language, entries) => results`
+ * @param {number} [options.concurrency] - Maximum workers
+ * @param {number} [options.minFilesPerWorker] - Minimum shard size
+ * @returns {Promise<Object>} - Results keyed by relative path
+ */
+async function extractParallel(cmd, basePath, language, entries, options) {
+  const workerCount = getWorkerCount(entries.length, options);
+  if (workerCount <= 1) {
+    return options.extract(cmd, basePath, language, entries);
+  }
+
+  const shards = shardEntries(entries, workerCount);
+  const results = await Promise.all(shards.map(shard =>
+    runWorker({ cmd, basePath, language, entries: shard })
+      .catch(() => options.extract(cmd, basePath, language, shard))
+  ));
+  return Object.assign({}, ...results);
+}
+
+module.exports = {
+  extractParallel,
+  getWorkerCount,
+  shardEntries,
+  MAX_DEFAULT_CONCURRENCY,
+  MIN_FILES_PER_WORKER
+};
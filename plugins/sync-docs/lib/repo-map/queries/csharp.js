@@ -0,0 +1,52 @@
+/**
+ * C# query patterns for ast-grep
+ */
+
+'use strict';
+
+module.exports = {
+  exports: [
+    { pattern: 'public class $NAME { $$$ }', kind: 'class', nameVar: 'NAME' },
+    { pattern: 'public interface $NAME { $$$ }', kind: 'class', nameVar: 'NAME' },
+    { pattern: 'public struct $NAME { $$$ }', kind: 'class', nameVar: 'NAME' },
+    { pattern: 'public enum $NAME { $$$ }', kind: 'class', nameVar: 'NAME' },
+    { pattern: 'public record $NAME($$$);', kind: 'class', nameVar: 'NAME' },
+    { pattern: 'public record $NAME { $$$ }', kind: 'class', nameVar: 'NAME' },
+    { pattern: 'public $RET $NAME($$$) { $$$ }', kind: 'function', nameVar: 'NAME' },
+    { pattern: 'public async $RET $NAME($$$) { $$$ }', kind: 'function', nameVar: 'NAME' },
+    { pattern: 'public static $RET $NAME($$$) { $$$ }', kind: 'function', nameVar: 'NAME' }
+  ],
+  functions: [
+    { pattern: 'public $RET $NAME($$$) { $$$ }', nameVar: 'NAME' },
+    { pattern: 'public async $RET $NAME($$$) { $$$ }', nameVar: 'NAME' },
+    { pattern: 'public static $RET $NAME($$$) { $$$ }', nameVar: 'NAME' },
+    { pattern: 'public $RET $NAME($$$) => $$$;', nameVar: 'NAME' },
+    { pattern: 'protected $RET $NAME($$$) { $$$ }', nameVar: 'NAME' },
+    { pattern: 'internal $RET $NAME($$$) { $$$ }', nameVar: 'NAME' },
+    { pattern: 'private $RET $NAME($$$) { $$$ }', nameVar: 'NAME' },
+    { pattern: 'private async $RET $NAME($$$) { $$$ }', nameVar: 'NAME' },
+    { pattern: '$RET $NAME($$$);', kind: 'declaration', nameVar: 'NAME' }
+  ],
+  classes: [
+    { pattern: 'class $NAME { $$$ }', nameVar: 'NAME' },
+    { pattern: 'class $NAME : $$$ { $$$ }', nameVar: 'NAME' },
+    { pattern: 'interface $NAME { $$$ }', nameVar: 'NAME' },
+    { pattern: 'struct $NAME { $$$ }', nameVar: 'NAME' },
+    { pattern: 'enum $NAME { $$$ }', nameVar: 'NAME' },
+    { pattern: 'record $NAME($$$);', nameVar: 'NAME' },
+    { pattern: 'record $NAME { $$$ }', nameVar: 'NAME' }
+  ],
+  types: [
+    { pattern: 'namespace $NAME { $$$ }', kind: 'namespace', nameVar: 'NAME' },
+    { pattern: 'namespace $NAME;', kind: 'namespace', nameVar: 'NAME' }
+  ],
+  constants: [
+    { pattern: 'public const $TYPE $NAME = $$$;', nameVar: 'NAME' },
+    { pattern: 'const $TYPE $NAME = $$$;', nameVar: 'NAME' }
+  ],
+  imports: [
+    { pattern: 'using $SOURCE;', sourceVar: 'SOURCE', kind: 'using' },
+    { pattern: 'using static $SOURCE;', sourceVar: 'SOURCE', kind: 'using' },
+    { pattern: 'global using $SOURCE;', sourceVar: 'SOURCE', kind: 'global-using' }
+  ]
+};
@@ -6,14 +6,27 @@
 
 'use strict';
 
-const { execSync, spawnSync } = require('child_process');
+// ast-grep is spawned directly: getCommand resolves the native binary, and a
+// cmd.exe command line would cap the file list at 8191 characters
+const { spawnSync } = require('child_process');
 const path = require('path');
 const fs = require('fs');
 const crypto = require('crypto');
 
 const installer = require('./installer');
 const queries = require('./queries');
-const slopAnalyzers = require('../patterns/slop-analyzers');
+const { applySymbolDetails } = require('./details');
+const { linkHeaderDeclarations, countFileSymbols } = require('./headers');
+const { groupDotnetProjects } = require('./dotnet');
+const { linkGoMethods } = require('./golang');
+const { extractCallSites, buildCallGraph } = require('./calls');
+const { extractEnvReads } = require('./env');
+const pool = require('./pool');
+const treesitter = require('./treesitter');
+const ignore = require('../utils/ignore');
+const git = require('../utils/git');
+const exec = require('../utils/exec');
+const scanLimits = require('../utils/scan-limits');
 const { analyzeDocumentation } = require('../drift-detect/collectors');
 
 // Language file extensions mapping
@@ -23,16 +36,23 @@ const LANGUAGE_EXTENSIONS = {
   python: ['.py', '.pyw'],
   rust: ['.rs'],
   go: ['.go'],
-  java: ['.java']
+  java: ['.java'],
+  kotlin: ['.kt'],
+  c: ['.c', '.h'],
+  cpp: ['.cpp', '.cc', '.cxx', '.hpp', '.hh', '.hxx'],
+  ruby: ['.rb', '.rake'],
+  csharp: ['.cs']
 };
 
 // Directories to exclude from scanning (extend base list)
 const EXCLUDE_DIRS = Array.from(new Set([
-  ...slopAnalyzers.EXCLUDE_DIRS,
+  ...ignore.DEFAULT_EXCLUDE_DIRS,
   '.claude', '.opencode', '.codex', '.venv', 'venv', 'env'
 ]));
 
 const AST_GREP_BATCH_SIZE = 100;
+// Bump when symbol post-processing changes so cached file symbols are re-extracted
+const EXTRACTOR_VERSION = 3;
 const LANGUAGE_EXTENSION_SCAN_LIMIT = 500;
 
 /**
@@ -50,7 +70,9 @@ async function detectLanguages(basePath) {
     python: ['pyproject.toml', 'setup.py', 'requirements.txt', 'Pipfile'],
     rust: ['Cargo.toml'],
     go: ['go.mod', 'go.sum'],
-    java: ['pom.xml', 'build.gradle', 'build.gradle.kts']
+    java: ['pom.xml', 'build.gradle', 'build.gradle.kts'],
+    ruby: ['Gemfile', '.ruby-version'],
+    csharp: ['global.json', 'Directory.Build.props']
   };
   
   for (const [lang, files] of Object.entries(configIndicators)) {
@@ -82,7 +104,7 @@ async function detectLanguages(basePath) {
 function scanForExtensions(basePath, maxFiles = 100) {
   const extensions = new Set();
   let count = 0;
-  const isIgnored = slopAnalyzers.parseGitignore(basePath, fs, path);
+  const isIgnored = ignore.createIgnoreFilter(basePath, { excludeDirs: EXCLUDE_DIRS });
   
   function scan(dir) {
     if (count >= maxFiles) return;
@@ -94,15 +116,13 @@ function scanForExtensions(basePath, maxFiles = 100) {
         
         if (entry.isDirectory()) {
           const relativePath = path.relative(basePath, path.join(dir, entry.name));
-          if (slopAnalyzers.shouldExclude(relativePath, EXCLUDE_DIRS)) continue;
-          if (isIgnored && isIgnored(relativePath, true)) continue;
+          if (isIgnored(relativePath, true)) continue;
           if (!entry.name.startsWith('.')) {
             scan(path.join(dir, entry.name));
           }
         } else if (entry.isFile()) {
           const relativePath = path.relative(basePath, path.join(dir, entry.name));
-          if (slopAnalyzers.shouldExclude(relativePath, EXCLUDE_DIRS)) continue;
-          if (isIgnored && isIgnored(relativePath, false)) continue;
+          if (isIgnored(relativePath, false)) continue;
           const ext = path.extname(entry.name).toLowerCase();
           if (ext) {
             extensions.add(ext);
@@ -119,10 +139,47 @@ function scanForExtensions(basePath, maxFiles = 100) {
   return extensions;
 }
 
+/**
+ * Fingerprint a language's extraction rules
+ * Cached file symbols are only reused while the fingerprint matches.
+ * @param {string} language - Language name
+ * @returns {string|null}
+ */
+function getExtractorFingerprint(language) {
+  const langQueries = queries.getQueriesForLanguage(language);
+  if (!langQueries) return null;
+  // Installing a grammar changes extraction, so it must invalidate cached symbols
+  const parser = treesitter.isAvailable(language) ? 'tree-sitter' : 'patterns';
+  return crypto.createHash('sha256')
+    .update(`${EXTRACTOR_VERSION}:${language}:${parser}:${JSON.stringify(langQueries)}`)
+    .digest('hex')
+    .slice(0, 16);
+}
+
+/**
+ * Fingerprint every language present in a map
+ * @param {Object} map - Repo map
+ * @returns {Object} - language -> fingerprint
+ */
+function getExtractorFingerprints(map) {
+  const extractors = {};
+  for (const fileData of Object.values((map && map.files) || {})) {
+    const language = fileData.language;
+    if (language && !(language in extractors)) {
+      extractors[language] = getExtractorFingerprint(language);
+    }
+  }
+  return extractors;
+}
+
 /**
  * Run a full scan of the repository
  * @param {string} basePath - Repository root
  * @param {string[]} languages - Languages to scan
+ * @param {Object} [options] - Options
+ * @param {Object} [options.fileCache] - Per-file cache from `cache.loadFileCache`; unchanged files reuse its symbols
+ * @param {number} [options.concurrency] - Maximum extraction worker threads (see `pool.getWorkerCount`)
+ * @param {boolean} [options.submodules] - Scan checked-out submodules too; recorded in `map.submodules`
  * @returns {Promise<Object>} - The generated map
  */
 async function fullScan(basePath, languages, options = {}) {
@@ -139,31 +196,38 @@ async function fullScan(basePath, languages, options = {}) {
     project: {
       type: detectProjectType(languages),
       languages,
-      frameworks: [] // Could be enhanced later
+      frameworks: detectFrameworks(basePath)
     },
     stats: {
       totalFiles: 0,
       totalSymbols: 0,
+      cachedFiles: 0,
       scanDurationMs: 0,
+      skipped: null,
       errors: []
     },
     files: {},
     dependencies: {},
+    callGraph: {},
     docs: null
   };
   
+  // Binary, minified, generated, and oversized files are left out of the map
+  const skip = scanLimits.createFileFilter(basePath);
+
   // Run queries for each language
   for (const lang of languages) {
     const langQueries = queries.getQueriesForLanguage(lang);
     if (!langQueries) continue;
 
-    const files = findFilesForLanguage(basePath, lang);
+    const files = findFilesForLanguage(basePath, lang, { submodules: options.submodules, skip });
     if (files.length === 0) continue;
 
+    const fileCache = options.fileCache;
+    const cachedFiles = fileCache && fileCache.extractors && fileCache.extractors[lang] === getExtractorFingerprint(lang)
+      ? fileCache.files || {}
+      : {};
     const fileEntries = [];
-    const symbolMapsByFile = new Map();
-    const importStateByFile = new Map();
-    const contentByFile = new Map();
 
     for (const file of files) {
       const relativePath = path.relative(basePath, file).replace(/\\/g, '/');
@@ -186,14 +250,27 @@ async function fullScan(basePath, languages, options = {}) {
             types: [],
             constants: []
           },
-          imports: []
+          imports: [],
+          calls: [],
+          env: []
         };
 
         map.stats.totalFiles++;
-        fileEntries.push({ file, relativePath });
-        symbolMapsByFile.set(relativePath, createSymbolMaps());
-        importStateByFile.set(relativePath, { items: [], seen: new Set() });
-        contentByFile.set(relativePath, content);
+
+        const cached = cachedFiles[relativePath];
+        if (cached && cached.hash === hash && cached.language === lang) {
+          map.files[relativePath].symbols = cached.symbols;
+          map.files[relativePath].imports = cached.imports || [];
+          map.files[relativePath].calls = cached.calls || [];
+          map.files[relativePath].env = cached.env || [];
+          if (map.files[relativePath].imports.length > 0) {
+            map.dependencies[relativePath] = Array.from(new Set(map.files[relativePath].imports.map(imp => imp.source)));
+          }
+          map.stats.cachedFiles++;
+          continue;
+        }
+
+        fileEntries.push({ file, relativePath, content });
       } catch (err) {
         map.stats.errors.push({
           file: relativePath,
@@ -204,110 +281,49 @@ async function fullScan(basePath, languages, options = {}) {
 
     if (fileEntries.length === 0) continue;
 
-    const filesBySgLang = new Map();
+    const extracted = await pool.extractParallel(cmd, basePath, lang, fileEntries, {
+      concurrency: options.concurrency,
+      extract: extractFiles
+    });
     for (const entry of fileEntries) {
-      const sgLang = queries.getSgLanguageForFile(entry.file, lang);
-      if (!filesBySgLang.has(sgLang)) {
-        filesBySgLang.set(sgLang, []);
-      }
-      filesBySgLang.get(sgLang).push(entry);
-    }
-
-    for (const [sgLang, entries] of filesBySgLang) {
-      const filePaths = entries.map(entry => entry.file);
-      const chunks = chunkArray(filePaths, AST_GREP_BATCH_SIZE);
-
-      const patternGroups = [
-        { category: 'exports', patterns: langQueries.exports, defaultKind: 'export' },
-        { category: 'functions', patterns: langQueries.functions, defaultKind: 'function' },
-        { category: 'classes', patterns: langQueries.classes, defaultKind: 'class' },
-        { category: 'types', patterns: langQueries.types, defaultKind: 'type' },
-        { category: 'constants', patterns: langQueries.constants, defaultKind: 'constant' },
-        { category: 'imports', patterns: langQueries.imports, defaultKind: 'import' }
-      ];
-
-      for (const group of patternGroups) {
-        if (!group.patterns || group.patterns.length === 0) continue;
-
-        for (const patternDef of group.patterns) {
-          const pattern = typeof patternDef === 'string' ? patternDef : patternDef.pattern;
-          if (!pattern) continue;
-
-          for (const chunk of chunks) {
-            const matches = runAstGrepPattern(cmd, pattern, sgLang, basePath, chunk);
-            for (const match of matches) {
-              const matchedPath = normalizeMatchPath(match.file, basePath);
-              if (!matchedPath) continue;
-
-              const symbolMaps = symbolMapsByFile.get(matchedPath);
-              const importState = importStateByFile.get(matchedPath);
-              if (!symbolMaps || !importState) continue;
-
-              if (group.category === 'imports') {
-                const sourceResult = extractSourceFromMatch(match, patternDef);
-                const sources = Array.isArray(sourceResult) ? sourceResult : [sourceResult];
-                for (const source of sources) {
-                  if (!source) continue;
-                  const kind = patternDef.kind || 'import';
-                  const key = `${source}:${kind}`;
-                  if (importState.seen.has(key)) continue;
-                  importState.seen.add(key);
-                  importState.items.push({
-                    source,
-                    kind,
-                    line: getLine(match)
-                  });
-                }
-                continue;
-              }
+      const result = extracted[entry.relativePath];
+      if (!result) continue;
 
-              const names = extractNamesFromMatch(match, patternDef);
-              const targetMap = symbolMaps[group.category];
-              if (!targetMap) continue;
-              for (const name of names) {
-                const kind = patternDef.kind || group.defaultKind;
-                addSymbolToMap(targetMap, name, match, kind, patternDef.extra);
-              }
-            }
-          }
-        }
+      map.files[entry.relativePath].symbols = result.symbols;
+      map.files[entry.relativePath].imports = result.imports;
+      map.files[entry.relativePath].calls = result.calls;
+      map.files[entry.relativePath].env = result.env;
+
+      if (result.imports.length > 0) {
+        map.dependencies[entry.relativePath] = Array.from(new Set(result.imports.map(imp => imp.source)));
       }
     }
+  }
 
-    for (const entry of fileEntries) {
-      const relativePath = entry.relativePath;
-      const symbolMaps = symbolMapsByFile.get(relativePath);
-      const importState = importStateByFile.get(relativePath);
-      if (!symbolMaps || !importState) continue;
-
-      const exportNames = new Set(symbolMaps.exports.keys());
-      const content = contentByFile.get(relativePath) || '';
-      applyLanguageExportRules(lang, content, exportNames, symbolMaps.functions, symbolMaps.classes, symbolMaps.types, symbolMaps.constants);
-      ensureExportEntries(symbolMaps.exports, exportNames, symbolMaps.functions, symbolMaps.classes, symbolMaps.types, symbolMaps.constants);
-
-      const symbols = {
-        exports: mapToSortedArray(symbolMaps.exports),
-        functions: mapToSortedArray(symbolMaps.functions, exportNames),
-        classes: mapToSortedArray(symbolMaps.classes, exportNames),
-        types: mapToSortedArray(symbolMaps.types, exportNames),
-        constants: mapToSortedArray(symbolMaps.constants, exportNames)
-      };
-
-      map.files[relativePath].symbols = symbols;
-      map.files[relativePath].imports = importState.items;
-
-      if (importState.items.length > 0) {
-        map.dependencies[relativePath] = Array.from(new Set(importState.items.map(imp => imp.source)));
-      }
+  map.stats.skipped = skip.summary();
 
-      map.stats.totalSymbols +=
-        (symbols.functions?.length || 0) +
-        (symbols.classes?.length || 0) +
-        (symbols.types?.length || 0) +
-        (symbols.constants?.length || 0);
-    }
+  // Record submodule commits so updates can tell when to re-scan them
+  if (options.submodules) {
+    map.submodules = getSubmoduleCommits(basePath);
+  }
+
+  // Group C# files into .csproj projects and solutions
+  if (languages.includes('csharp')) {
+    groupDotnetProjects(map, basePath, findDotnetManifests(basePath));
   }
 
+  // Group Go methods under their receiver types
+  linkGoMethods(map);
+
+  // Count header-declared C/C++ symbols once
+  linkHeaderDeclarations(map);
+  for (const fileData of Object.values(map.files)) {
+    map.stats.totalSymbols += countFileSymbols(fileData.symbols);
+  }
+
+  // Resolve call sites to definitions in other files
+  map.callGraph = buildCallGraph(map);
+
   // Optionally include documentation analysis
   if (options.includeDocs !== false) {
     map.docs = analyzeDocumentation({
@@ -319,16 +335,189 @@ async function fullScan(basePath, languages, options = {}) {
   return map;
 }
 
+/**
+ * Extract symbols, imports, and call sites for files of one language
+ * Results are plain data so they can cross worker thread boundaries.
+ * @param {string} cmd - ast-grep command
+ * @param {string} basePath - Repository root
+ * @param {string} lang - Language name
+ * @param {Array<{file: string, relativePath: string, content: string}>} fileEntries - Files to extract
+ * @returns {Object<string, {symbols: Object, imports: Array, calls: Array, env: Array}>} - Results keyed by relative path
+ */
+function extractFiles(cmd, basePath, lang, fileEntries) {
+  const langQueries = queries.getQueriesForLanguage(lang);
+  const results = {};
+  if (!langQueries) return results;
+
+  const symbolMapsByFile = new Map();
+  const importStateByFile = new Map();
+  for (const entry of fileEntries) {
+    symbolMapsByFile.set(entry.relativePath, createSymbolMaps());
+    importStateByFile.set(entry.relativePath, { items: [], seen: new Set() });
+  }
+
+  const filesBySgLang = new Map();
+  for (const entry of fileEntries) {
+    const sgLang = queries.getSgLanguageForFile(entry.file, lang);
+    if (!filesBySgLang.has(sgLang)) {
+      filesBySgLang.set(sgLang, []);
+    }
+    filesBySgLang.get(sgLang).push(entry);
+  }
+
+  for (const [sgLang, entries] of filesBySgLang) {
+    const filePaths = entries.map(entry => entry.file);
+    const chunks = chunkArray(filePaths, AST_GREP_BATCH_SIZE);
+
+    const patternGroups = [
+      { category: 'exports', patterns: langQueries.exports, defaultKind: 'export' },
+      { category: 'functions', patterns: langQueries.functions, defaultKind: 'function' },
+      { category: 'classes', patterns: langQueries.classes, defaultKind: 'class' },
+      { category: 'types', patterns: langQueries.types, defaultKind: 'type' },
+      { category: 'constants', patterns: langQueries.constants, defaultKind: 'constant' },
+      { category: 'imports', patterns: langQueries.imports, defaultKind: 'import' }
+    ];
+
+    for (const group of patternGroups) {
+      if (!group.patterns || group.patterns.length === 0) continue;
+
+      for (const patternDef of group.patterns) {
+        const pattern = typeof patternDef === 'string' ? patternDef : patternDef.pattern;
+        if (!pattern) continue;
+
+        for (const chunk of chunks) {
+          const matches = runAstGrepPattern(cmd, pattern, sgLang, basePath, chunk);
+          for (const match of matches) {
+            const matchedPath = normalizeMatchPath(match.file, basePath);
+            if (!matchedPath) continue;
+
+            const symbolMaps = symbolMapsByFile.get(matchedPath);
+            const importState = importStateByFile.get(matchedPath);
+            if (!symbolMaps || !importState) continue;
+
+            if (group.category === 'imports') {
+              const sourceResult = extractSourceFromMatch(match, patternDef);
+              const sources = Array.isArray(sourceResult) ? sourceResult : [sourceResult];
+              for (const source of sources) {
+                if (!source) continue;
+                const kind = patternDef.kind || 'import';
+                const key = `${source}:${kind}`;
+                if (importState.seen.has(key)) continue;
+                importState.seen.add(key);
+                importState.items.push({
+                  source,
+                  kind,
+                  line: getLine(match)
+                });
+              }
+              continue;
+            }
+
+            const names = extractNamesFromMatch(match, patternDef);
+            const targetMap = symbolMaps[group.category];
+            if (!targetMap) continue;
+            for (const name of names) {
+              const kind = patternDef.kind || group.defaultKind;
+              addSymbolToMap(targetMap, name, match, kind, patternDef.extra);
+            }
+          }
+        }
+      }
+    }
+  }
+
+  for (const entry of fileEntries) {
+    const relativePath = entry.relativePath;
+    const symbolMaps = symbolMapsByFile.get(relativePath);
+    const importState = importStateByFile.get(relativePath);
+    if (!symbolMaps || !importState) continue;
+
+    const exportNames = new Set(symbolMaps.exports.keys());
+    const content = entry.content || '';
+    treesitter.mergeDeclarations(lang, content, symbolMaps);
+    applySymbolDetails(lang, content, symbolMaps, { file: relativePath });
+    applyLanguageExportRules(lang, content, exportNames, symbolMaps.functions, symbolMaps.classes, symbolMaps.types, symbolMaps.constants);
+    ensureExportEntries(symbolMaps.exports, exportNames, symbolMaps.functions, symbolMaps.classes, symbolMaps.types, symbolMaps.constants);
+
+    const symbols = {
+      exports: mapToSortedArray(symbolMaps.exports),
+      functions: mapToSortedArray(symbolMaps.functions, exportNames),
+      classes: mapToSortedArray(symbolMaps.classes, exportNames),
+      types: mapToSortedArray(symbolMaps.types, exportNames),
+      constants: mapToSortedArray(symbolMaps.constants, exportNames)
+    };
+
+    results[relativePath] = {
+      symbols,
+      imports: importState.items,
+      calls: extractCallSites(lang, content),
+      env: extractEnvReads(lang, content)
+    };
+  }
+
+  return results;
+}
+
 /**
  * Find all files for a language
  * @param {string} basePath - Repository root
  * @param {string} language - Language name
+ * @param {Object} [options] - See findFilesByExtension
+ * @returns {string[]} - Array of file paths
+ */
+function findFilesForLanguage(basePath, language, options = {}) {
+  return findFilesByExtension(basePath, LANGUAGE_EXTENSIONS[language] || [], options);
+}
+
+/**
+ * Checked-out submodules under a directory
+ * @param {string} basePath - Repository root
+ * @returns {string[]} Relative submodule paths
+ */
+function findSubmodules(basePath) {
+  return git.nestedCheckouts(basePath).submodules
+    .filter(dir => fs.existsSync(path.join(basePath, dir, '.git')));
+}
+
+/**
+ * Current commit of each checked-out submodule
+ * @param {string} basePath - Repository root
+ * @returns {Array<{path: string, commit: string|null}>}
+ */
+function getSubmoduleCommits(basePath) {
+  return findSubmodules(basePath).map(dir => ({
+    path: dir,
+    commit: getGitInfo(path.join(basePath, dir))?.commit || null
+  }));
+}
+
+/**
+ * Find .NET project and solution files
+ * @param {string} basePath - Repository root
+ * @returns {{projects: string[], solutions: string[]}} - Paths relative to root
+ */
+function findDotnetManifests(basePath) {
+  const toRelative = file => path.relative(basePath, file).replace(/\\/g, '/');
+  return {
+    projects: findFilesByExtension(basePath, ['.csproj']).map(toRelative),
+    solutions: findFilesByExtension(basePath, ['.sln']).map(toRelative)
+  };
+}
+
+/**
+ * Find all files with the given extensions, honoring excludes and ignore files
+ * Nested worktrees and submodules are skipped; with `submodules`, each
+ * checked-out submodule is walked on its own, with its own ignore files.
+ * @param {string} basePath - Repository root
+ * @param {string[]} extensions - Lowercase extensions including the dot
+ * @param {Object} [options]
+ * @param {boolean} [options.submodules] - Include files in submodules
+ * @param {Function} [options.skip] - Content filter from scan-limits `createFileFilter`
  * @returns {string[]} - Array of file paths
  */
-function findFilesForLanguage(basePath, language) {
-  const extensions = LANGUAGE_EXTENSIONS[language] || [];
+function findFilesByExtension(basePath, extensions, options = {}) {
   const files = [];
-  const isIgnored = slopAnalyzers.parseGitignore(basePath, fs, path);
+  const isIgnored = ignore.createIgnoreFilter(basePath, { excludeDirs: EXCLUDE_DIRS });
   
   function scan(dir) {
     try {
@@ -338,17 +527,15 @@ function findFilesForLanguage(basePath, language) {
         
         if (entry.isDirectory()) {
           const relativePath = path.relative(basePath, fullPath);
-          if (slopAnalyzers.shouldExclude(relativePath, EXCLUDE_DIRS)) continue;
-          if (isIgnored && isIgnored(relativePath, true)) continue;
+          if (isIgnored(relativePath, true)) continue;
           if (!entry.name.startsWith('.')) {
             scan(fullPath);
           }
         } else if (entry.isFile()) {
           const relativePath = path.relative(basePath, fullPath);
-          if (slopAnalyzers.shouldExclude(relativePath, EXCLUDE_DIRS)) continue;
-          if (isIgnored && isIgnored(relativePath, false)) continue;
+          if (isIgnored(relativePath, false)) continue;
           const ext = path.extname(entry.name).toLowerCase();
-          if (extensions.includes(ext)) {
+          if (extensions.includes(ext) && !(options.skip && options.skip(fullPath))) {
             files.push(fullPath);
           }
         }
@@ -359,6 +546,11 @@ function findFilesForLanguage(basePath, language) {
   }
   
   scan(basePath);
+  if (options.submodules) {
+    for (const dir of findSubmodules(basePath)) {
+      files.push(...findFilesByExtension(path.join(basePath, dir), extensions, options));
+    }
+  }
   return files;
 }
 
@@ -500,6 +692,20 @@ function extractSymbols(cmd, file, language, langQueries, basePath, content) {
   // Extract constants
   runPatternSet(langQueries.constants, constMap, 'constant');
 
+  const symbolMaps = {
+    exports: exportMap,
+    functions: functionMap,
+    classes: classMap,
+    types: typeMap,
+    constants: constMap
+  };
+
+  // Add multi-line/generic declarations the patterns missed (tree-sitter grammars, when installed)
+  treesitter.mergeDeclarations(language, content, symbolMaps);
+
+  // Attach language-specific structure (decorators, members, bases)
+  applySymbolDetails(language, content, symbolMaps, { file: path.relative(basePath, file).replace(/\\/g, '/') });
+
   // Infer exports for languages with implicit public rules
   const exportNames = new Set(exportMap.keys());
   applyLanguageExportRules(language, content, exportNames, functionMap, classMap, typeMap, constMap);
@@ -627,12 +833,36 @@ function extractNamesFromMatch(match, patternDef) {
     return extractNamesFromObjectLiteral(match.text || '');
   }
 
+  if (def.nameTemplate) {
+    const templated = applyNameTemplate(match, def.nameTemplate);
+    if (templated) return [templated];
+  }
+
   const name = extractNameFromMatch(match, def.nameVar);
   if (name) return [name];
   if (def.fallbackName) return [def.fallbackName];
   return [];
 }
 
+/**
+ * Build a symbol name from a template of meta variables (e.g. `$TRAIT for $NAME`)
+ * @param {Object} match - ast-grep match result
+ * @param {string} template - Template containing `$VAR` placeholders
+ * @returns {string|null} - Name, or null if any placeholder is unresolved
+ */
+function applyNameTemplate(match, template) {
+  let resolved = true;
+  const name = template.replace(/\$([A-Z_][A-Z0-9_]*)/g, (_, key) => {
+    const variable = getMetaVariable(match, key);
+    if (!variable || !variable.text) {
+      resolved = false;
+      return '';
+    }
+    return variable.text.replace(/\s+/g, ' ');
+  });
+  return resolved ? name : null;
+}
+
 function getMetaVariable(match, key) {
   if (!match || !match.metaVariables) return null;
   if (match.metaVariables[key]) return match.metaVariables[key];
@@ -687,7 +917,7 @@ function extractSourceFromMatch(match, patternDef) {
 
   const variable = getMetaVariable(match, sourceVar);
   if (variable && variable.text) {
-    const raw = variable.text.replace(/^['"]|['"]$/g, '');
+    const raw = variable.text.replace(/^['"]|['"]$/g, '').replace(/^<([^<>]+)>$/, '$1');
     if (def.multiSource) {
       return splitMultiSource(raw);
     }
@@ -808,10 +1038,63 @@ function applyLanguageExportRules(language, content, exportNames, functionMap, c
   }
 
   if (language === 'go') {
-    addPublicNames(exportNames, functionMap, classMap, typeMap, constMap, name => isExportedGoName(name));
+    // Methods are keyed `Receiver.Method` and exported only with their receiver type
+    addPublicNames(exportNames, functionMap, classMap, typeMap, constMap, (name, entry) =>
+      entry.receiver
+        ? isExportedGoName(entry.name) && isExportedGoName(entry.receiver)
+        : isExportedGoName(name));
+    return;
+  }
+
+  if (language === 'kotlin') {
+    // Kotlin declarations are public unless marked otherwise
+    addPublicNames(exportNames, functionMap, classMap, typeMap, constMap, (name, entry) =>
+      entry.visibility ? entry.visibility === 'public' : true);
+    return;
+  }
+
+  if (language === 'c' || language === 'cpp') {
+    // Everything without internal linkage is visible to other translation units
+    addPublicNames(exportNames, functionMap, classMap, typeMap, constMap, (name, entry) =>
+      entry.storage !== 'static');
+    return;
+  }
+
+  if (language === 'csharp') {
+    addPublicNames(exportNames, functionMap, classMap, typeMap, constMap, (name, entry) =>
+      entry.visibility === 'public');
+    return;
+  }
+
+  if (language === 'ruby') {
+    addPublicNames(exportNames, functionMap, classMap, typeMap, constMap, (name, entry) =>
+      !entry.visibility || entry.visibility === 'public');
+    return;
+  }
+
+  if (language === 'rust') {
+    for (const name of extractRustExportedMacros(content)) {
+      if (functionMap.has(name)) exportNames.add(name);
+    }
   }
 }
 
+/**
+ * Extract `#[macro_export]` macro names from Rust content
+ * @param {string} content - File content
+ * @returns {string[]}
+ */
+function extractRustExportedMacros(content) {
+  if (!content) return [];
+  const names = [];
+  const regex = /#\[macro_export(?:\([^)]*\))?\]\s*(?:#\[[^\]]*\]\s*)*macro_rules!\s*([A-Za-z_][A-Za-z0-9_]*)/g;
+  let m;
+  while ((m = regex.exec(content)) !== null) {
+    names.push(m[1]);
+  }
+  return names;
+}
+
 /**
  * Extract __all__ exports from Python content
  * @param {string} content - File content
@@ -836,14 +1119,14 @@ function extractPythonAll(content) {
  * Add public names from symbol maps based on predicate
  * @param {Set<string>} exportNames - Export name set
  * @param {...Map} maps - Symbol maps
- * @param {Function} predicate - Function(name) => boolean
+ * @param {Function} predicate - Function(name, entry) => boolean
  */
 function addPublicNames(exportNames, ...args) {
   const predicate = args.pop();
   const maps = args;
   for (const map of maps) {
-    for (const name of map.keys()) {
-      if (predicate(name)) exportNames.add(name);
+    for (const [name, entry] of map) {
+      if (predicate(name, entry)) exportNames.add(name);
     }
   }
 }
@@ -873,6 +1156,8 @@ function ensureExportEntries(exportMap, exportNames, functionMap, classMap, type
 
   for (const name of exportNames) {
     if (exportMap.has(name)) continue;
+    // Go methods are reachable through their receiver type, not exported by name
+    if (functionMap.get(name)?.receiver) continue;
 
     let entry = null;
     for (const map of sources) {
@@ -900,11 +1185,11 @@ function ensureExportEntries(exportMap, exportNames, functionMap, classMap, type
 function mapToSortedArray(map, exportNames) {
   const list = Array.from(map.values());
   if (exportNames) {
-    for (const item of list) {
-      item.exported = exportNames.has(item.name);
+    for (const [key, item] of map) {
+      item.exported = exportNames.has(key);
     }
   }
-  list.sort((a, b) => a.name.localeCompare(b.name));
+  list.sort((a, b) => a.name.localeCompare(b.name) || (a.receiver || '').localeCompare(b.receiver || ''));
   return list;
 }
 
@@ -914,23 +1199,10 @@ function mapToSortedArray(map, exportNames) {
  * @returns {Object|null}
  */
 function getGitInfo(basePath) {
-  try {
-    const commit = execSync('git rev-parse HEAD', {
-      cwd: basePath,
-      encoding: 'utf8',
-      stdio: ['pipe', 'pipe', 'pipe']
-    }).trim();
-    
-    const branch = execSync('git rev-parse --abbrev-ref HEAD', {
-      cwd: basePath,
-      encoding: 'utf8',
-      stdio: ['pipe', 'pipe', 'pipe']
-    }).trim();
-    
-    return { commit, branch };
-  } catch {
-    return null;
-  }
+  const commit = exec.git(basePath, ['rev-parse', 'HEAD']);
+  const branch = exec.git(basePath, ['rev-parse', '--abbrev-ref', 'HEAD']);
+  if (commit === null || branch === null) return null;
+  return { commit: commit.trim(), branch: branch.trim() };
 }
 
 /**
@@ -940,7 +1212,7 @@ function getGitInfo(basePath) {
  */
 function detectProjectType(languages) {
   // Priority order
-  const priority = ['typescript', 'javascript', 'python', 'rust', 'go', 'java'];
+  const priority = ['typescript', 'javascript', 'python', 'rust', 'go', 'java', 'kotlin', 'cpp', 'c', 'ruby', 'csharp'];
   for (const lang of priority) {
     if (languages.includes(lang)) {
       return lang === 'typescript' ? 'node' : lang;
@@ -949,6 +1221,33 @@ function detectProjectType(languages) {
   return languages[0] || 'unknown';
 }
 
+/**
+ * Detect frameworks with repo-map specific handling
+ * @param {string} basePath - Repository root
+ * @returns {string[]}
+ */
+function detectFrameworks(basePath) {
+  const frameworks = [];
+  try {
+    const gemfile = path.join(basePath, 'Gemfile');
+    const hasRailsGem = fs.existsSync(gemfile) &&
+      /^\s*gem\s+['"]rails['"]/m.test(fs.readFileSync(gemfile, 'utf8'));
+    if (hasRailsGem || fs.existsSync(path.join(basePath, 'config', 'application.rb'))) {
+      frameworks.push('rails');
+    }
+    const hasSpringBoot = ['pom.xml', 'build.gradle', 'build.gradle.kts'].some(name => {
+      const manifest = path.join(basePath, name);
+      return fs.existsSync(manifest) && /org\.springframework\.boot/.test(fs.readFileSync(manifest, 'utf8'));
+    });
+    if (hasSpringBoot) {
+      frameworks.push('spring');
+    }
+  } catch {
+    // Unreadable manifests: no framework hints
+  }
+  return frameworks;
+}
+
 /**
  * Scan a single file (for incremental updates)
  * @param {string} cmd - ast-grep command
@@ -985,7 +1284,9 @@ function scanSingleFile(cmd, file, basePath) {
       language,
       size: content.length,
       symbols,
-      imports
+      imports,
+      calls: extractCallSites(language, content),
+      env: extractEnvReads(language, content)
     };
   } catch {
     return null;
@@ -994,10 +1295,19 @@ function scanSingleFile(cmd, file, basePath) {
 
 module.exports = {
   detectLanguages,
+  detectFrameworks,
   fullScan,
+  extractFiles,
+  getExtractorFingerprint,
+  getExtractorFingerprints,
   findFilesForLanguage,
+  findDotnetManifests,
+  findSubmodules,
+  getSubmoduleCommits,
   scanSingleFile,
   runAstGrep,
+  applyNameTemplate,
+  extractRustExportedMacros,
   getGitInfo,
   LANGUAGE_EXTENSIONS,
   EXCLUDE_DIRS
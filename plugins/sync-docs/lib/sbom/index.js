@@ -0,0 +1,939 @@
+#!/usr/bin/env node
+/**
+ * SBOM
+ *
+ * Builds a software bill of materials from the lockfiles license-check
+ * reads (one per ecosystem): every locked package, transitive dependencies
+ * included, with its purl, the hashes the lockfile pins, its license, and
+ * the dependency graph. Written as CycloneDX 1.5 or SPDX 2.3 JSON.
+ *
+ * What each lockfile records:
+ *
+ * | Lockfile | Hashes | Dependency graph |
+ * |----------|--------|------------------|
+ * | package-lock.json, npm-shrinkwrap.json | `integrity` | yes |
+ * | yarn.lock | `integrity` (classic), `#sha1` of the tarball URL | yes |
+ * | pnpm-lock.yaml | `resolution.integrity` | yes |
+ * | poetry.lock, uv.lock | distribution file hashes | yes |
+ * | Cargo.lock | `checksum` | yes |
+ * | Pipfile.lock, requirements.txt | `hashes`, `--hash` | no |
+ * | Gemfile.lock | `CHECKSUMS` (Bundler 2.6+) | yes |
+ * | go.mod | none (go.sum `h1:` hashes are not file hashes) | direct requires only |
+ *
+ * Development-only packages are left out unless `dev` is set; then they are
+ * listed with CycloneDX scope `excluded` and SPDX `DEV_DEPENDENCY_OF`.
+ * `SOURCE_DATE_EPOCH` fixes the timestamp for reproducible documents.
+ *
+ * Usage: node lib/sbom/index.js [--format cyclonedx|spdx] [--output FILE] [--dev] [--offline]
+ * Output: the document on stdout, or a summary when written with --output;
+ * exit code 1 when no SBOM can be generated
+ *
+ * @module lib/sbom
+ */
+
+const crypto = require('crypto');
+const fs = require('fs');
+const path = require('path');
+
+const { parseGoRequires } = require('../deps');
+const licenseCheck = require('../license-check');
+const { normalizePythonName } = require('../platform/detect-database');
+
+const FORMATS = ['cyclonedx', 'spdx'];
+
+/**
+ * Default file name per format
+ */
+const DEFAULT_OUTPUT = {
+  cyclonedx: 'sbom.cdx.json',
+  spdx: 'sbom.spdx.json'
+};
+
+const TOOL_NAME = 'awesome-slash';
+
+/**
+ * Package URL type per ecosystem
+ */
+const PURL_TYPES = {
+  npm: 'npm',
+  python: 'pypi',
+  rust: 'cargo',
+  go: 'golang',
+  ruby: 'gem'
+};
+
+/**
+ * CycloneDX hash algorithm names by lockfile prefix
+ */
+const HASH_ALGORITHMS = {
+  md5: 'MD5',
+  sha1: 'SHA-1',
+  sha256: 'SHA-256',
+  sha384: 'SHA-384',
+  sha512: 'SHA-512'
+};
+
+/**
+ * Bundler platform suffix on a gem version (`1.15.4-x86_64-linux`)
+ */
+const GEM_PLATFORM = /-(?:x86|arm|aarch|universal|java|x64)[\w-]*$/;
+
+/**
+ * Version of this tool, when its package.json ships alongside
+ * @returns {string|null}
+ */
+function toolVersion() {
+  try {
+    return require('../../package.json').version;
+  } catch {
+    return null;
+  }
+}
+
+/**
+ * Parse a JSON file's content
+ * @param {string|null} content - File content
+ * @returns {Object|null}
+ */
+function parseJson(content) {
+  try {
+    return JSON.parse(content);
+  } catch {
+    return null;
+  }
+}
+
+/**
+ * A hash from an algorithm name and hex digest
+ * @param {string} algorithm - `sha256`, `sha512`, ...
+ * @param {string} hex - Digest
+ * @returns {{alg: string, content: string}|null}
+ */
+function hexHash(algorithm, hex) {
+  const alg = HASH_ALGORITHMS[String(algorithm).toLowerCase()];
+  return alg && /^[0-9a-f]+$/i.test(hex) ? { alg, content: hex.toLowerCase() } : null;
+}
+
+/**
+ * Hashes from a Subresource Integrity value (`sha512-<base64> sha1-<base64>`)
+ * @param {string} integrity - npm, yarn, or pnpm integrity
+ * @returns {Array<{alg: string, content: string}>}
+ */
+function integrityHashes(integrity) {
+  return String(integrity || '').split(/\s+/)
+    .map(value => value.match(/^(\w+)-([A-Za-z0-9+/=]+)$/))
+    .filter(Boolean)
+    .map(([, algorithm, digest]) => hexHash(algorithm, Buffer.from(digest, 'base64').toString('hex')))
+    .filter(Boolean);
+}
+
+/**
+ * Hashes written as `sha256:<hex>` or `sha256=<hex>`
+ * @param {string} text - Text holding the hashes
+ * @returns {Array<{alg: string, content: string}>}
+ */
+function prefixedHashes(text) {
+  return [...String(text || '').matchAll(/\b(md5|sha1|sha256|sha384|sha512)[:=]([0-9a-f]{32,128})\b/gi)]
+    .map(([, algorithm, hex]) => hexHash(algorithm, hex))
+    .filter(Boolean);
+}
+
+/**
+ * Package URL of a package
+ * @param {string} ecosystem - License-check ecosystem
+ * @param {string} name - Package name (npm scope and Go module path included)
+ * @param {string} version - Locked version
+ * @returns {string}
+ */
+function purlOf(ecosystem, name, version) {
+  const type = PURL_TYPES[ecosystem] || ecosystem;
+  const normalized = ecosystem === 'python' ? normalizePythonName(name) : name;
+  const encoded = normalized.split('/').map(encodeURIComponent).join('/');
+  return `pkg:${type}/${encoded}@${encodeURIComponent(version)}`;
+}
+
+/**
+ * Nodes of a lockfile's dependency graph, keyed by `name@version`
+ * @typedef {Object} LockGraph
+ * @property {Map<string, {hashes: Object[], dependsOn: string[], resolved?: string}>} nodes
+ * @property {string[]|null} direct - Ids the project depends on; null when the lockfile does not say
+ */
+
+/**
+ * Add a node, merging with an earlier one for the same id
+ * @param {Map} nodes - Graph nodes
+ * @param {string} id - `name@version`
+ * @param {Object} node - `{hashes, dependsOn, resolved}`
+ */
+function addNode(nodes, id, node) {
+  const existing = nodes.get(id);
+  if (!existing) {
+    nodes.set(id, { hashes: node.hashes || [], dependsOn: [...new Set(node.dependsOn || [])], resolved: node.resolved });
+    return;
+  }
+  for (const hash of node.hashes || []) {
+    if (!existing.hashes.some(known => known.alg === hash.alg && known.content === hash.content)) existing.hashes.push(hash);
+  }
+  existing.dependsOn = [...new Set([...existing.dependsOn, ...(node.dependsOn || [])])];
+  if (!existing.resolved) existing.resolved = node.resolved;
+}
+
+/**
+ * Dependency names a package.json declares
+ * @param {Object|null} manifest - Parsed package.json
+ * @returns {string[]}
+ */
+function manifestDependencies(manifest) {
+  if (!manifest) return [];
+  return Object.keys({ ...manifest.dependencies, ...manifest.devDependencies, ...manifest.optionalDependencies });
+}
+
+/**
+ * Graph of package-lock.json / npm-shrinkwrap.json
+ * Dependencies resolve the way Node does: the nearest `node_modules` up the tree.
+ * @param {string} content - Lockfile content
+ * @param {Object|null} manifest - Parsed package.json
+ * @returns {LockGraph}
+ */
+function npmGraph(content, manifest) {
+  const lock = parseJson(content) || {};
+  let entries = lock.packages;
+  if (!entries) {
+    // v1: nested `dependencies` with `requires`
+    entries = {};
+    const walk = (dependencies, prefix) => {
+      for (const [name, entry] of Object.entries(dependencies || {})) {
+        const key = `${prefix}node_modules/${name}`;
+        entries[key] = { ...entry, dependencies: entry.requires };
+        walk(entry.dependencies, `${key}/`);
+      }
+    };
+    walk(lock.dependencies, '');
+  }
+
+  const idOf = key => {
+    const entry = entries[key];
+    if (!entry || entry.link || !entry.version) return null;
+    const name = entry.name || key.slice(key.lastIndexOf('node_modules/') + 'node_modules/'.length);
+    return `${name}@${entry.version}`;
+  };
+  const resolve = (from, name) => {
+    let base = from;
+    for (;;) {
+      const candidate = base ? `${base}/node_modules/${name}` : `node_modules/${name}`;
+      if (entries[candidate]) return idOf(candidate);
+      if (!base) return null;
+      const parent = base.lastIndexOf('/node_modules/');
+      base = parent === -1 ? '' : base.slice(0, parent);
+    }
+  };
+
+  const nodes = new Map();
+  for (const [key, entry] of Object.entries(entries)) {
+    const id = key ? idOf(key) : null;
+    if (!id) continue;
+    addNode(nodes, id, {
+      hashes: integrityHashes(entry.integrity),
+      dependsOn: Object.keys({ ...entry.dependencies, ...entry.optionalDependencies }).map(name => resolve(key, name)).filter(Boolean),
+      resolved: entry.resolved
+    });
+  }
+  const root = manifest || entries[''] || null;
+  const direct = root ? manifestDependencies(root).map(name => resolve('', name)).filter(Boolean) : null;
+  return { nodes, direct };
+}
+
+/**
+ * Graph of yarn.lock (classic and berry)
+ * Dependencies resolve through the `name@range` specs each entry lists in its header.
+ * @param {string} content - Lockfile content
+ * @param {Object|null} manifest - Parsed package.json
+ * @returns {LockGraph}
+ */
+function yarnGraph(content, manifest) {
+  const specs = new Map();
+  const entries = [];
+  for (const block of String(content || '').split(/\n\s*\n/)) {
+    const lines = block.split('\n');
+    const header = lines.find(line => line && !/^[\s#]/.test(line));
+    if (!header || header.startsWith('__metadata')) continue;
+    const headerSpecs = header.replace(/:\s*$/, '').split(',').map(spec => spec.trim().replace(/^"|"$/g, ''));
+    if (headerSpecs.some(spec => /@(?:workspace|link|portal|file):/.test(spec))) continue;
+    const name = headerSpecs[0].slice(0, headerSpecs[0].indexOf('@', 1));
+    const version = block.match(/^\s+version:?\s+"?([^"\s]+)"?/m);
+    if (!name || !version) continue;
+    const id = `${name}@${version[1]}`;
+    for (const spec of headerSpecs) specs.set(spec, id);
+
+    const dependencies = [];
+    let inDependencies = false;
+    for (const line of lines) {
+      if (/^ {2}\S/.test(line)) inDependencies = /^ {2}(?:dependencies|optionalDependencies):\s*$/.test(line);
+      const dependency = inDependencies && line.match(/^ {4}"?([^\s":]+)"?:?\s+"?([^"]+?)"?\s*$/);
+      if (dependency) dependencies.push([dependency[1], dependency[2]]);
+    }
+    const integrity = block.match(/^\s+integrity:?\s+"?([^"\s]+)"?/m);
+    const resolved = block.match(/^\s+resolved:?\s+"?([^"\s]+)"?/m);
+    const sha1 = resolved && resolved[1].match(/#([0-9a-f]{40})$/);
+    entries.push({ id, dependencies, hashes: integrity ? integrityHashes(integrity[1]) : (sha1 ? [hexHash('sha1', sha1[1])] : []), resolved: resolved ? resolved[1].replace(/#.*$/, '') : undefined });
+  }
+
+  const resolve = (name, range) => specs.get(`${name}@${range}`) || specs.get(`${name}@npm:${range}`) ||
+    [...specs.values()].find(id => id.slice(0, id.lastIndexOf('@')) === name) || null;
+  const nodes = new Map();
+  for (const entry of entries) {
+    addNode(nodes, entry.id, { ...entry, dependsOn: entry.dependencies.map(([name, range]) => resolve(name, range)).filter(Boolean) });
+  }
+  const declared = manifest ? { ...manifest.dependencies, ...manifest.devDependencies, ...manifest.optionalDependencies } : null;
+  const direct = declared ? Object.entries(declared).map(([name, range]) => resolve(name, range)).filter(Boolean) : null;
+  return { nodes, direct };
+}
+
+/**
+ * Parse the block mappings of a YAML document (enough for pnpm-lock.yaml)
+ * Flow mappings (`{integrity: sha512-...}`) become objects; sequences are skipped.
+ * @param {string} content - YAML content
+ * @returns {Object}
+ */
+function parseYamlMappings(content) {
+  const unquote = value => value.trim().replace(/^(['"])(.*)\1$/, '$2');
+  const scalar = value => {
+    const flow = value.trim().match(/^\{(.*)\}$/);
+    if (!flow) return unquote(value);
+    const object = {};
+    for (const pair of flow[1].split(/,\s*/)) {
+      const separator = pair.indexOf(':');
+      if (separator > 0) object[unquote(pair.slice(0, separator))] = unquote(pair.slice(separator + 1));
+    }
+    return object;
+  };
+
+  const root = {};
+  const stack = [{ indent: -1, value: root }];
+  for (const line of String(content || '').split('\n')) {
+    const text = line.trim();
+    if (!text || text.startsWith('#') || text.startsWith('- ')) continue;
+    const match = text.match(/^('[^']*'|"[^"]*"|[^:]+?):(?:\s+(.*))?$/);
+    if (!match) continue;
+    const indent = line.search(/\S/);
+    while (stack[stack.length - 1].indent >= indent) stack.pop();
+    const parent = stack[stack.length - 1].value;
+    const key = unquote(match[1]);
+    if (match[2] === undefined || match[2] === '') {
+      parent[key] = {};
+      stack.push({ indent, value: parent[key] });
+    } else {
+      parent[key] = scalar(match[2]);
+    }
+  }
+  return root;
+}
+
+/**
+ * `name@version` of a pnpm package key or alias
+ * Keys look like `/react@18.2.0` (v6), `react@18.2.0(peer)` (v9), or `/react/18.2.0_peer` (v5).
+ * @param {string} key - Package key
+ * @returns {string|null}
+ */
+function pnpmId(key) {
+  const id = key.replace(/^\//, '').replace(/\(.*$/, '');
+  const match = id.match(/^(@?[^@]+)@(\d[^_]*)/) || id.match(/^((?:@[^/]+\/)?[^/@]+)\/(\d[^_/]*)/);
+  return match ? `${match[1]}@${match[2]}` : null;
+}
+
+/**
+ * Graph of pnpm-lock.yaml (v5, v6, and v9, whose edges sit under `snapshots`)
+ * @param {string} content - Lockfile content
+ * @returns {LockGraph}
+ */
+function pnpmGraph(content) {
+  const lock = parseYamlMappings(content);
+  const target = (name, value) => {
+    const version = typeof value === 'object' && value ? value.version : value;
+    if (typeof version !== 'string' || /^(?:link|file|workspace):/.test(version)) return null;
+    // Aliases point at another package: `/string-width@4.2.3` or `string-width@4.2.3`
+    return (/^\/|^(?:@|[^\d@(])[^@(]*@\d/.test(version) && pnpmId(version)) || `${name}@${version.replace(/[(_].*$/, '')}`;
+  };
+  const edges = entry => Object.entries({ ...(entry && entry.dependencies), ...(entry && entry.optionalDependencies) })
+    .map(([name, value]) => target(name, value))
+    .filter(Boolean);
+
+  const nodes = new Map();
+  for (const [key, entry] of Object.entries(lock.packages || {})) {
+    const id = pnpmId(key);
+    if (!id) continue;
+    const resolution = entry.resolution || {};
+    addNode(nodes, id, { hashes: integrityHashes(resolution.integrity), dependsOn: edges(entry), resolved: resolution.tarball });
+  }
+  for (const [key, entry] of Object.entries(lock.snapshots || {})) {
+    const id = pnpmId(key);
+    if (id && nodes.has(id)) addNode(nodes, id, { dependsOn: edges(entry) });
+  }
+
+  const importer = (lock.importers && lock.importers['.']) || lock;
+  const direct = ['dependencies', 'devDependencies', 'optionalDependencies']
+    .flatMap(section => Object.entries(importer[section] || {}).map(([name, value]) => target(name, value)))
+    .filter(Boolean);
+  return { nodes, direct: lock.importers || lock.dependencies || lock.devDependencies ? direct : null };
+}
+
+/**
+ * Graph of a TOML lockfile (poetry.lock, uv.lock, Cargo.lock)
+ * Entries name their dependencies without versions (Cargo adds one only
+ * when several are locked); uv and Cargo workspace members are the project
+ * itself, so their dependencies are the direct ones.
+ * @param {string} file - Lockfile name
+ * @param {string} content - Lockfile content
+ * @returns {LockGraph}
+ */
+function tomlGraph(file, content) {
+  const text = String(content || '');
+  const python = file !== 'Cargo.lock';
+  const key = name => (python ? normalizePythonName(name) : name);
+
+  // poetry < 1.2 keeps file hashes in `[metadata.files]`
+  const metadataFiles = new Map();
+  const metadata = text.match(/^\[metadata\.files\]\s*$([\s\S]*?)(?=^\[|(?![\s\S]))/m);
+  if (metadata) {
+    for (const [, name, files] of metadata[1].matchAll(/^"?([\w.-]+)"?\s*=\s*\[([\s\S]*?)^\]/gm)) metadataFiles.set(key(name), prefixedHashes(files));
+  }
+
+  const entries = [];
+  for (const block of text.split(/^\[\[package\]\]\s*$/m).slice(1)) {
+    const [body, ...tables] = block.split(/^(?=\[)/m);
+    const name = body.match(/^name\s*=\s*"([^"]+)"/m);
+    const version = body.match(/^version\s*=\s*"([^"]+)"/m);
+    if (!name || !version) continue;
+    const source = body.match(/^source\s*=\s*(.+)$/m);
+    const member = file !== 'poetry.lock' && (!source || /\b(?:editable|virtual|path)\s*=/.test(source[1]));
+
+    const dependencies = [];
+    if (file === 'Cargo.lock') {
+      const list = body.match(/^dependencies\s*=\s*\[([\s\S]*?)\]/m);
+      for (const [, spec] of (list ? list[1] : '').matchAll(/"([^"]+)"/g)) {
+        const [depName, depVersion] = spec.split(' ');
+        dependencies.push({ name: depName, version: depVersion });
+      }
+    } else if (file === 'uv.lock') {
+      const lists = [body.match(/^dependencies\s*=\s*\[(.*)\]\s*$|^dependencies\s*=\s*\[([\s\S]*?)^\]/m), ...tables
+        .filter(table => /^\[package\.(?:optional|dev)-dependencies\]/.test(table))
+        .map(table => [null, table])];
+      for (const list of lists.filter(Boolean)) {
+        for (const [, depName] of (list[1] || list[2]).matchAll(/\{\s*name\s*=\s*"([^"]+)"/g)) dependencies.push({ name: depName });
+      }
+    } else {
+      const table = tables.find(section => /^\[package\.dependencies\]/.test(section));
+      for (const [, depName] of (table || '').matchAll(/^"?([A-Za-z0-9][\w.-]*)"?\s*=/gm)) dependencies.push({ name: depName });
+    }
+
+    const checksum = body.match(/^checksum\s*=\s*"([0-9a-f]+)"/m);
+    const hashes = file === 'Cargo.lock'
+      ? (checksum ? [hexHash('sha256', checksum[1])] : [])
+      : [...prefixedHashes(body), ...(metadataFiles.get(key(name[1])) || [])];
+    entries.push({ id: `${name[1]}@${version[1]}`, name: name[1], member, dependencies, hashes });
+  }
+
+  const byName = new Map();
+  for (const entry of entries.filter(locked => !locked.member)) {
+    byName.set(key(entry.name), [...(byName.get(key(entry.name)) || []), entry.id]);
+  }
+  const resolve = dep => {
+    const ids = byName.get(key(dep.name)) || [];
+    return (dep.version && ids.find(id => id === `${dep.name}@${dep.version}`)) || ids[0] || null;
+  };
+
+  const nodes = new Map();
+  let direct = null;
+  for (const entry of entries) {
+    const dependsOn = entry.dependencies.map(resolve).filter(Boolean);
+    if (entry.member) direct = [...new Set([...(direct || []), ...dependsOn])];
+    else addNode(nodes, entry.id, { hashes: entry.hashes, dependsOn });
+  }
+  return { nodes, direct };
+}
+
+/**
+ * Graph of Pipfile.lock or requirements.txt: hashes, no edges
+ * @param {string} file - Lockfile name
+ * @param {string} content - Lockfile content
+ * @returns {LockGraph}
+ */
+function pythonHashGraph(file, content) {
+  const nodes = new Map();
+  if (file === 'Pipfile.lock') {
+    const lock = parseJson(content) || {};
+    for (const section of ['default', 'develop']) {
+      for (const [name, entry] of Object.entries(lock[section] || {})) {
+        if (entry && typeof entry.version === 'string') {
+          addNode(nodes, `${name}@${entry.version.replace(/^==/, '')}`, { hashes: prefixedHashes((entry.hashes || []).join(' ')) });
+        }
+      }
+    }
+    return { nodes, direct: null };
+  }
+  // Hashes often sit on continuation lines: `pkg==1.0 \` + `    --hash=sha256:...`
+  for (const line of String(content || '').replace(/\\\r?\n/g, ' ').split('\n')) {
+    const match = line.replace(/#.*/, '').match(/^\s*([A-Za-z0-9][\w.-]*)(?:\[[^\]]*\])?\s*==\s*([\w.+!-]+)/);
+    if (match) addNode(nodes, `${match[1]}@${match[2]}`, { hashes: prefixedHashes(line.replace(/#.*/, '')) });
+  }
+  return { nodes, direct: null };
+}
+
+/**
+ * Graph of go.mod: the modules the project requires directly
+ * @param {string} content - go.mod content
+ * @returns {LockGraph}
+ */
+function goGraph(content) {
+  const nodes = new Map();
+  const direct = [];
+  for (const [name, { version, indirect }] of parseGoRequires(content)) {
+    addNode(nodes, `${name}@${version}`, {});
+    if (!indirect) direct.push(`${name}@${version}`);
+  }
+  return { nodes, direct };
+}
+
+/**
+ * Graph of Gemfile.lock: GEM specs, DEPENDENCIES, and CHECKSUMS
+ * @param {string} content - Lockfile content
+ * @returns {LockGraph}
+ */
+function gemGraph(content) {
+  const specs = [];
+  const declared = [];
+  const checksums = new Map();
+  let section = null;
+  let current = null;
+  for (const line of String(content || '').split('\n')) {
+    if (/^\S/.test(line)) {
+      section = line.trim();
+      current = null;
+      continue;
+    }
+    if (section === 'GEM') {
+      const spec = line.match(/^ {4}([^\s(]+) \(([^)]+)\)\s*$/);
+      const dependency = line.match(/^ {6}([^\s(]+)/);
+      if (spec) {
+        current = { id: `${spec[1]}@${spec[2].replace(GEM_PLATFORM, '')}`, name: spec[1], dependencies: [] };
+        specs.push(current);
+      } else if (dependency && current) {
+        current.dependencies.push(dependency[1]);
+      }
+    } else if (section === 'DEPENDENCIES') {
+      const dependency = line.match(/^ {2}([^\s(!]+)/);
+      if (dependency) declared.push(dependency[1]);
+    } else if (section === 'CHECKSUMS') {
+      const checksum = line.match(/^ {2}([^\s(]+) \(([^)]+)\)\s+(.+)$/);
+      if (checksum) checksums.set(`${checksum[1]}@${checksum[2].replace(GEM_PLATFORM, '')}`, prefixedHashes(checksum[3]));
+    }
+  }
+
+  const byName = new Map(specs.map(spec => [spec.name, spec.id]));
+  const nodes = new Map();
+  for (const spec of specs) {
+    addNode(nodes, spec.id, {
+      hashes: checksums.get(spec.id) || [],
+      dependsOn: spec.dependencies.map(name => byName.get(name)).filter(Boolean)
+    });
+  }
+  return { nodes, direct: declared.length ? declared.map(name => byName.get(name)).filter(Boolean) : null };
+}
+
+/**
+ * Dependency graph of one lockfile
+ * @param {string} file - Lockfile name
+ * @param {string} content - Lockfile content
+ * @param {Object|null} [manifest] - Parsed package.json, for npm lockfiles
+ * @returns {LockGraph}
+ */
+function readLockGraph(file, content, manifest = null) {
+  switch (file) {
+    case 'package-lock.json':
+    case 'npm-shrinkwrap.json':
+      return npmGraph(content, manifest);
+    case 'yarn.lock':
+      return yarnGraph(content, manifest);
+    case 'pnpm-lock.yaml':
+      return pnpmGraph(content);
+    case 'poetry.lock':
+    case 'uv.lock':
+    case 'Cargo.lock':
+      return tomlGraph(file, content);
+    case 'Pipfile.lock':
+    case 'requirements.txt':
+      return pythonHashGraph(file, content);
+    case 'go.mod':
+      return goGraph(content);
+    case 'Gemfile.lock':
+      return gemGraph(content);
+    default:
+      return { nodes: new Map(), direct: null };
+  }
+}
+
+/**
+ * Name and version of the project the SBOM describes
+ * @param {string} basePath - Project root
+ * @returns {{name: string, version: string|null}}
+ */
+function readProject(basePath) {
+  const read = file => {
+    try {
+      return fs.readFileSync(path.join(basePath, file), 'utf8');
+    } catch {
+      return null;
+    }
+  };
+  const manifest = parseJson(read('package.json'));
+  if (manifest && manifest.name) return { name: manifest.name, version: manifest.version || null };
+  for (const file of ['pyproject.toml', 'Cargo.toml']) {
+    const content = read(file);
+    const table = content && content.match(/^\[(?:project|tool\.poetry|package)\]\s*$([\s\S]*?)(?=^\[|(?![\s\S]))/m);
+    const name = table && table[1].match(/^name\s*=\s*"([^"]+)"/m);
+    const version = table && table[1].match(/^version\s*=\s*"([^"]+)"/m);
+    if (name) return { name: name[1], version: version ? version[1] : null };
+  }
+  const goModule = (read('go.mod') || '').match(/^module\s+(\S+)/m);
+  if (goModule) return { name: goModule[1], version: null };
+  return { name: path.basename(path.resolve(basePath)), version: null };
+}
+
+/**
+ * Every locked package with its hashes, license, and dependencies
+ * @param {string} basePath - Project root
+ * @param {Object} [options]
+ * @param {boolean} [options.dev] - Include development-only packages
+ * @param {boolean} [options.offline] - Skip registry license lookups
+ * @param {Function} [options.run] - Command runner, for tests
+ * @param {Function} [options.request] - Registry request, for tests
+ * @returns {Promise<Object>} `{success, project, lockfiles, components, direct, errors}`; components carry `ref` (their purl) and `dependsOn` refs
+ */
+async function collectComponents(basePath, options = {}) {
+  const lockfiles = licenseCheck.detectLockfiles(basePath);
+  if (!lockfiles.length) {
+    return { success: false, error: `No lockfile found. Looked for ${licenseCheck.LOCKFILES.map(entry => entry.file).join(', ')}.` };
+  }
+
+  const read = file => {
+    try {
+      return fs.readFileSync(path.join(basePath, file), 'utf8');
+    } catch {
+      return '';
+    }
+  };
+  const manifest = parseJson(read('package.json'));
+
+  const components = [];
+  const byRef = new Map();
+  const direct = new Set();
+  for (const lockfile of lockfiles) {
+    const content = read(lockfile.file);
+    const graph = readLockGraph(lockfile.file, content, lockfile.ecosystem === 'npm' ? manifest : null);
+    const refOf = id => purlOf(lockfile.ecosystem, id.slice(0, id.lastIndexOf('@')), id.slice(id.lastIndexOf('@') + 1));
+    const locked = licenseCheck.parseLockfile(lockfile.file, content);
+    lockfile.count = 0;
+    for (const dep of locked) {
+      const ref = purlOf(lockfile.ecosystem, dep.name, dep.version);
+      if (dep.dev && !options.dev) continue;
+      if (byRef.has(ref)) {
+        byRef.get(ref).dev = byRef.get(ref).dev && dep.dev;
+        continue;
+      }
+      const node = graph.nodes.get(`${dep.name}@${dep.version}`) || { hashes: [], dependsOn: [] };
+      const component = {
+        ecosystem: lockfile.ecosystem,
+        manager: lockfile.manager,
+        lockfile: lockfile.file,
+        name: dep.name,
+        version: dep.version,
+        dev: dep.dev,
+        path: dep.path,
+        ref,
+        purl: ref,
+        hashes: node.hashes,
+        dependsOn: node.dependsOn.map(refOf),
+        resolved: /^https?:\/\//.test(node.resolved || '') ? node.resolved : null,
+        license: licenseCheck.normalizeLicense(dep.license)
+      };
+      byRef.set(ref, component);
+      components.push(component);
+      lockfile.count++;
+    }
+
+    // Without a direct list, the packages nothing else depends on are the top level
+    const dependedOn = new Set([...graph.nodes.values()].flatMap(node => node.dependsOn));
+    const top = graph.direct || [...graph.nodes.keys()].filter(id => !dependedOn.has(id));
+    for (const id of top) direct.add(refOf(id));
+  }
+
+  // Edges and direct dependencies only point at packages kept in the SBOM
+  for (const component of components) component.dependsOn = [...new Set(component.dependsOn)].filter(ref => byRef.has(ref) && ref !== component.ref);
+
+  licenseCheck.resolveLocalLicenses(basePath, components, options.run);
+  const errors = options.offline ? [] : await licenseCheck.resolveRegistryLicenses(components, options.request);
+
+  components.sort((a, b) => a.ecosystem.localeCompare(b.ecosystem) || a.name.localeCompare(b.name) || a.version.localeCompare(b.version));
+  return {
+    success: true,
+    project: readProject(basePath),
+    lockfiles,
+    components: components.map(({ path: lockPath, source, ...component }) => component),
+    direct: [...direct].filter(ref => byRef.has(ref)),
+    errors
+  };
+}
+
+/**
+ * Timestamp for the document: `SOURCE_DATE_EPOCH` when set, else now
+ * @param {Object} [env=process.env]
+ * @returns {string} ISO 8601 without milliseconds
+ */
+function timestamp(env = process.env) {
+  const epoch = Number(env.SOURCE_DATE_EPOCH);
+  const date = env.SOURCE_DATE_EPOCH && Number.isFinite(epoch) ? new Date(epoch * 1000) : new Date();
+  return date.toISOString().replace(/\.\d{3}Z$/, 'Z');
+}
+
+/**
+ * Whether a license is an SPDX expression documents can carry as is
+ * `UNLICENSED` (npm's "all rights reserved") is not an SPDX id.
+ * @param {string|null} license - Normalized license
+ * @returns {boolean}
+ */
+function isSpdxLicense(license) {
+  return Boolean(license) && license !== 'UNLICENSED' && licenseCheck.isExpression(license);
+}
+
+/**
+ * CycloneDX 1.5 document
+ * @param {Object} inventory - Result of collectComponents
+ * @param {Object} [options]
+ * @param {string} [options.timestamp] - Creation time (default: now, or SOURCE_DATE_EPOCH)
+ * @param {string} [options.uuid] - Serial number (default: random)
+ * @returns {Object}
+ */
+function toCycloneDx(inventory, options = {}) {
+  const version = toolVersion();
+  const rootRef = `${inventory.project.name}@${inventory.project.version || 'unversioned'}`;
+  const licenses = license => {
+    if (!license) return undefined;
+    if (/\s/.test(license)) return [{ expression: license }];
+    return [isSpdxLicense(license) && !license.startsWith('LicenseRef-') ? { license: { id: license } } : { license: { name: license } }];
+  };
+
+  return {
+    bomFormat: 'CycloneDX',
+    specVersion: '1.5',
+    serialNumber: `urn:uuid:${options.uuid || crypto.randomUUID()}`,
+    version: 1,
+    metadata: {
+      timestamp: options.timestamp || timestamp(),
+      tools: { components: [{ type: 'application', name: TOOL_NAME, ...(version && { version }) }] },
+      component: {
+        type: 'application',
+        'bom-ref': rootRef,
+        name: inventory.project.name,
+        ...(inventory.project.version && { version: inventory.project.version })
+      }
+    },
+    components: inventory.components.map(component => ({
+      type: 'library',
+      'bom-ref': component.ref,
+      name: component.name,
+      version: component.version,
+      scope: component.dev ? 'excluded' : 'required',
+      ...(component.hashes.length && { hashes: component.hashes }),
+      ...(component.license && { licenses: licenses(component.license) }),
+      purl: component.purl,
+      ...(component.resolved && { externalReferences: [{ type: 'distribution', url: component.resolved }] }),
+      properties: [{ name: `${TOOL_NAME}:lockfile`, value: component.lockfile }]
+    })),
+    dependencies: [
+      { ref: rootRef, dependsOn: inventory.direct },
+      ...inventory.components.map(component => ({ ref: component.ref, dependsOn: component.dependsOn }))
+    ]
+  };
+}
+
+/**
+ * SPDX 2.3 document
+ * @param {Object} inventory - Result of collectComponents
+ * @param {Object} [options]
+ * @param {string} [options.timestamp] - Creation time (default: now, or SOURCE_DATE_EPOCH)
+ * @param {string} [options.uuid] - Namespace id (default: random)
+ * @returns {Object}
+ */
+function toSpdx(inventory, options = {}) {
+  const version = toolVersion();
+  const used = new Set();
+  const spdxId = (...parts) => {
+    const base = `SPDXRef-${parts.join('-').replace(/[^A-Za-z0-9.-]+/g, '-').replace(/-+$/, '')}`;
+    let id = base;
+    for (let n = 2; used.has(id); n++) id = `${base}-${n}`;
+    used.add(id);
+    return id;
+  };
+
+  const rootId = spdxId('Package', inventory.project.name);
+  const ids = new Map(inventory.components.map(component => [component.ref, spdxId('Package', component.ecosystem, component.name, component.version)]));
+  const byRef = new Map(inventory.components.map(component => [component.ref, component]));
+
+  const packages = [{
+    name: inventory.project.name,
+    SPDXID: rootId,
+    ...(inventory.project.version && { versionInfo: inventory.project.version }),
+    downloadLocation: 'NOASSERTION',
+    filesAnalyzed: false,
+    licenseConcluded: 'NOASSERTION',
+    licenseDeclared: 'NOASSERTION',
+    copyrightText: 'NOASSERTION',
+    primaryPackagePurpose: 'APPLICATION'
+  }];
+  for (const component of inventory.components) {
+    packages.push({
+      name: component.name,
+      SPDXID: ids.get(component.ref),
+      versionInfo: component.version,
+      downloadLocation: component.resolved || 'NOASSERTION',
+      filesAnalyzed: false,
+      ...(component.hashes.length && {
+        checksums: component.hashes.map(hash => ({ algorithm: hash.alg.replace('-', ''), checksumValue: hash.content }))
+      }),
+      licenseConcluded: 'NOASSERTION',
+      licenseDeclared: isSpdxLicense(component.license) ? component.license : 'NOASSERTION',
+      ...(component.license && !isSpdxLicense(component.license) && { licenseComments: `Declared as "${component.license}"` }),
+      copyrightText: 'NOASSERTION',
+      externalRefs: [{ referenceCategory: 'PACKAGE-MANAGER', referenceType: 'purl', referenceLocator: component.purl }],
+      primaryPackagePurpose: 'LIBRARY'
+    });
+  }
+
+  const relationships = [{ spdxElementId: 'SPDXRef-DOCUMENT', relationshipType: 'DESCRIBES', relatedSpdxElement: rootId }];
+  for (const ref of inventory.direct) {
+    relationships.push(byRef.get(ref).dev
+      ? { spdxElementId: ids.get(ref), relationshipType: 'DEV_DEPENDENCY_OF', relatedSpdxElement: rootId }
+      : { spdxElementId: rootId, relationshipType: 'DEPENDS_ON', relatedSpdxElement: ids.get(ref) });
+  }
+  for (const component of inventory.components) {
+    for (const ref of component.dependsOn) {
+      relationships.push({ spdxElementId: ids.get(component.ref), relationshipType: 'DEPENDS_ON', relatedSpdxElement: ids.get(ref) });
+    }
+  }
+
+  const uuid = options.uuid || crypto.randomUUID();
+  return {
+    spdxVersion: 'SPDX-2.3',
+    dataLicense: 'CC0-1.0',
+    SPDXID: 'SPDXRef-DOCUMENT',
+    name: inventory.project.name,
+    documentNamespace: `https://spdx.org/spdxdocs/${encodeURIComponent(inventory.project.name)}-${uuid}`,
+    creationInfo: {
+      created: options.timestamp || timestamp(),
+      creators: [`Tool: ${TOOL_NAME}${version ? `-${version}` : ''}`]
+    },
+    packages,
+    relationships
+  };
+}
+
+/**
+ * Generate an SBOM for the project
+ * @param {string} basePath - Project root
+ * @param {Object} [options]
+ * @param {string} [options.format='cyclonedx'] - `cyclonedx` or `spdx`
+ * @param {boolean} [options.dev] - Include development-only packages
+ * @param {boolean} [options.offline] - Skip registry license lookups
+ * @param {string} [options.timestamp] - Creation time, for tests
+ * @param {string} [options.uuid] - Serial number / namespace id, for tests
+ * @param {Function} [options.run] - Command runner, for tests
+ * @param {Function} [options.request] - Registry request, for tests
+ * @returns {Promise<Object>} `{success, format, document, lockfiles, components, hashed, licensed, errors}`
+ */
+async function generateSbom(basePath, options = {}) {
+  const format = options.format || 'cyclonedx';
+  if (!FORMATS.includes(format)) return { success: false, error: `Unknown SBOM format "${format}". Use ${FORMATS.join(' or ')}.` };
+
+  const inventory = await collectComponents(basePath, options);
+  if (!inventory.success) return inventory;
+  const document = format === 'spdx' ? toSpdx(inventory, options) : toCycloneDx(inventory, options);
+  return {
+    success: true,
+    format,
+    document,
+    project: inventory.project,
+    lockfiles: inventory.lockfiles,
+    components: inventory.components.length,
+    hashed: inventory.components.filter(component => component.hashes.length).length,
+    licensed: inventory.components.filter(component => component.license).length,
+    dev: Boolean(options.dev),
+    offline: Boolean(options.offline),
+    errors: inventory.errors
+  };
+}
+
+/**
+ * Render what was generated as markdown
+ * @param {Object} result - Result of generateSbom
+ * @param {string} [output] - File the document was written to
+ * @returns {string}
+ */
+function renderSummary(result, output) {
+  const lines = ['## SBOM', ''];
+  lines.push(`**Format**: ${result.format === 'spdx' ? 'SPDX 2.3' : 'CycloneDX 1.5'} JSON${output ? ` (\`${output}\`)` : ''}`);
+  lines.push(`**Lockfiles**: ${result.lockfiles.map(lockfile => `${lockfile.file} (${lockfile.count})`).join(', ')}`);
+  lines.push(`**Components**: ${result.components}${result.dev ? ' (dev dependencies included)' : ''} | **With hashes**: ${result.hashed} | **With licenses**: ${result.licensed}`);
+  const unhashed = result.lockfiles.filter(lockfile => lockfile.file === 'go.mod');
+  if (unhashed.length) lines.push('', 'Go modules have no file hashes in go.mod or go.sum; their components have none.');
+  if (result.offline && result.licensed < result.components) {
+    lines.push('', 'Registry lookups were skipped (`--offline`); packages that are not installed have no license.');
+  }
+  if (result.errors.length) {
+    lines.push('', `**Registry errors**: ${result.errors.length} (${result.errors.slice(0, 3).join('; ')})`);
+  }
+  return lines.join('\n');
+}
+
+if (require.main === module) {
+  const argv = process.argv.slice(2);
+  const valueOf = flag => {
+    const index = argv.indexOf(flag);
+    return index === -1 ? undefined : argv[index + 1];
+  };
+  const output = valueOf('--output');
+  generateSbom(process.cwd(), {
+    format: valueOf('--format'),
+    dev: argv.includes('--dev'),
+    offline: argv.includes('--offline')
+  }).then(result => {
+    if (!result.success) {
+      console.error(result.error);
+      process.exitCode = 1;
+      return;
+    }
+    const json = JSON.stringify(result.document, null, 2);
+    if (!output) {
+      console.log(json);
+      return;
+    }
+    fs.writeFileSync(path.resolve(output), `${json}\n`);
+    console.log(renderSummary(result, output));
+  });
+}
+
+module.exports = {
+  FORMATS,
+  DEFAULT_OUTPUT,
+  PURL_TYPES,
+  integrityHashes,
+  prefixedHashes,
+  purlOf,
+  parseYamlMappings,
+  readLockGraph,
+  readProject,
+  collectComponents,
+  toCycloneDx,
+  toSpdx,
+  generateSbom,
+  renderSummary
+};